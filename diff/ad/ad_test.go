@@ -0,0 +1,185 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// The extended Rosenbrock function written for each engine.
+
+func rosenbrockDual(x []dual.Number) dual.Number {
+	var f dual.Number
+	for i := 0; i < len(x)-1; i++ {
+		a := dual.Sub(dual.Number{Real: 1}, x[i])
+		b := dual.Sub(x[i+1], dual.Mul(x[i], x[i]))
+		f = dual.Add(f, dual.Add(dual.Mul(a, a), dual.Scale(100, dual.Mul(b, b))))
+	}
+	return f
+}
+
+func rosenbrockHyperdual(x []hyperdual.Number) hyperdual.Number {
+	var f hyperdual.Number
+	for i := 0; i < len(x)-1; i++ {
+		a := hyperdual.Sub(hyperdual.Number{Real: 1}, x[i])
+		b := hyperdual.Sub(x[i+1], hyperdual.Mul(x[i], x[i]))
+		f = hyperdual.Add(f, hyperdual.Add(hyperdual.Mul(a, a), hyperdual.Scale(100, hyperdual.Mul(b, b))))
+	}
+	return f
+}
+
+func rosenbrockReverse(_ *Tape, x []Var) Var {
+	f := Const(0)
+	for i := 0; i < len(x)-1; i++ {
+		a := Sub(Const(1), x[i])
+		b := Sub(x[i+1], Mul(x[i], x[i]))
+		f = Add(f, Add(Mul(a, a), Scale(100, Mul(b, b))))
+	}
+	return f
+}
+
+func TestRosenbrock(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 5, 20} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+		}
+		wantF := functions.ExtendedRosenbrock{}.Func(x)
+		wantGrad := make([]float64, n)
+		functions.ExtendedRosenbrock{}.Grad(wantGrad, x)
+		wantHess := mat.NewSymDense(n, nil)
+		for i := 0; i < n-1; i++ {
+			wantHess.SetSym(i, i, wantHess.At(i, i)+2+1200*x[i]*x[i]-400*x[i+1])
+			wantHess.SetSym(i, i+1, -400*x[i])
+			wantHess.SetSym(i+1, i+1, 200)
+		}
+
+		for _, test := range []struct {
+			name string
+			f    func([]float64) float64
+			grad func(grad, x []float64)
+		}{
+			{name: "dual", f: DualFunc(rosenbrockDual).Func, grad: DualFunc(rosenbrockDual).Grad},
+			{name: "hyperdual", f: HyperdualFunc(rosenbrockHyperdual).Func, grad: HyperdualFunc(rosenbrockHyperdual).Grad},
+			{name: "reverse", f: ReverseFunc(rosenbrockReverse).Func, grad: ReverseFunc(rosenbrockReverse).Grad},
+		} {
+			if got := test.f(x); !scalar.EqualWithinAbsOrRel(got, wantF, 1e-12, 1e-12) {
+				t.Errorf("%s n=%d: unexpected value: got:%v want:%v", test.name, n, got, wantF)
+			}
+			grad := make([]float64, n)
+			test.grad(grad, x)
+			if !floats.EqualApprox(grad, wantGrad, 1e-12) {
+				t.Errorf("%s n=%d: unexpected gradient: got:%v want:%v", test.name, n, grad, wantGrad)
+			}
+		}
+
+		hess := mat.NewSymDense(n, nil)
+		HyperdualFunc(rosenbrockHyperdual).Hess(hess, x)
+		if !mat.EqualApprox(hess, wantHess, 1e-12) {
+			t.Errorf("n=%d: unexpected Hessian:\ngot: %v\nwant:%v", n, mat.Formatted(hess), mat.Formatted(wantHess))
+		}
+	}
+}
+
+func TestReverseOperations(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		f    func(x, y Var) Var
+		x, y float64
+	}{
+		{name: "add", f: Add, x: 1.5, y: -2},
+		{name: "sub", f: Sub, x: 1.5, y: -2},
+		{name: "mul", f: Mul, x: 1.5, y: -2},
+		{name: "div", f: Div, x: 1.5, y: -2},
+		{name: "pow", f: Pow, x: 1.5, y: 2.3},
+		{name: "inv", f: func(x, y Var) Var { return Mul(Inv(x), y) }, x: 1.5, y: -2},
+		{name: "scale", f: func(x, y Var) Var { return Add(Scale(3, x), y) }, x: 1.5, y: -2},
+		{name: "abs", f: func(x, y Var) Var { return Mul(Abs(x), Abs(y)) }, x: 1.5, y: -2},
+		{name: "sqrt", f: func(x, y Var) Var { return Sqrt(Mul(x, x)) }, x: 1.5, y: -2},
+		{name: "powreal", f: func(x, y Var) Var { return Mul(PowReal(x, 2.5), y) }, x: 1.5, y: -2},
+		{name: "exp log", f: func(x, y Var) Var { return Log(Add(Exp(x), Exp(y))) }, x: 1.5, y: -2},
+		{name: "trig", f: func(x, y Var) Var { return Add(Mul(Sin(x), Cos(y)), Tan(Sub(x, y))) }, x: 0.3, y: -0.4},
+		{name: "atan tanh", f: func(x, y Var) Var { return Mul(Atan(x), Tanh(y)) }, x: 1.5, y: -2},
+		{name: "reuse", f: func(x, y Var) Var { s := Mul(x, y); return Sum([]Var{s, s, x}) }, x: 1.5, y: -2},
+		{name: "constant", f: func(x, y Var) Var { return Mul(Const(2), Add(Const(3), Const(4))) }, x: 1.5, y: -2},
+	} {
+		f := ReverseFunc(func(_ *Tape, v []Var) Var { return test.f(v[0], v[1]) })
+		x := []float64{test.x, test.y}
+		grad := make([]float64, 2)
+		f.Grad(grad, x)
+		want := fd.Gradient(nil, f.Func, x, &fd.Settings{Formula: fd.Central})
+		if !floats.EqualApprox(grad, want, 1e-7) {
+			t.Errorf("%s: unexpected gradient: got:%v want:%v", test.name, grad, want)
+		}
+	}
+}
+
+func TestTape(t *testing.T) {
+	t.Parallel()
+	var tape Tape
+	x := tape.Vars([]float64{2, 3})
+	y := Mul(x[0], Exp(x[1]))
+	grad := make([]float64, 1)
+	tape.Gradient(grad, y, x[1:])
+	if want := 2 * math.Exp(3); !scalar.EqualWithinAbsOrRel(grad[0], want, 1e-14, 1e-14) {
+		t.Errorf("unexpected partial derivative: got:%v want:%v", grad[0], want)
+	}
+
+	// Intermediate values can be differentiated.
+	z := Add(y, x[0])
+	tape.Gradient(grad, Exp(x[1]), x[1:])
+	if want := math.Exp(3); grad[0] != want {
+		t.Errorf("unexpected intermediate derivative: got:%v want:%v", grad[0], want)
+	}
+	if z.Value() != 2*math.Exp(3)+2 {
+		t.Errorf("unexpected value: got:%v", z.Value())
+	}
+
+	tape.Reset()
+	x = tape.Vars([]float64{1})
+	tape.Gradient(grad, Scale(4, x[0]), x)
+	if grad[0] != 4 {
+		t.Errorf("unexpected derivative after reset: got:%v want:4", grad[0])
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+	f := ReverseFunc(rosenbrockReverse)
+	h := HyperdualFunc(rosenbrockHyperdual)
+	x0 := []float64{-1.2, 1, -1.2, 1}
+	for _, test := range []struct {
+		name    string
+		problem optimize.Problem
+		method  optimize.Method
+	}{
+		{name: "reverse", problem: optimize.Problem{Func: f.Func, Grad: f.Grad}, method: &optimize.BFGS{}},
+		{name: "hyperdual", problem: optimize.Problem{Func: h.Func, Grad: h.Grad, Hess: h.Hess}, method: &optimize.Newton{}},
+	} {
+		res, err := optimize.Minimize(test.problem, x0, nil, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !floats.EqualApprox(res.X, []float64{1, 1, 1, 1}, 1e-6) {
+			t.Errorf("%s: minimum not found: %v", test.name, res.X)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ad provides automatic differentiation of scalar functions of
+// several variables, giving exact derivatives without hand-coding them and
+// without the error and cost of finite differences.
+//
+// Two engines are provided. Forward mode, using the dual and hyperdual numbers
+// of the num/dual and num/hyperdual packages, evaluates the function once per
+// variable and is suited to functions of few variables. Reverse mode records
+// the operations of a single evaluation on a Tape and propagates derivatives
+// backward through them, so its cost does not grow with the number of
+// variables.
+//
+// The Func, Grad and Hess methods of the function types have the signatures of
+// the fields of optimize.Problem, so they can be used directly:
+//  f := ad.ReverseFunc(func(t *ad.Tape, x []ad.Var) ad.Var { ... })
+//  p := optimize.Problem{Func: f.Func, Grad: f.Grad}
+package ad // import "gonum.org/v1/gonum/diff/ad"
@@ -0,0 +1,38 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/diff/ad"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleReverseFunc() {
+	// Minimize the Beale function using a gradient computed
+	// in reverse mode.
+	beale := ad.ReverseFunc(func(_ *ad.Tape, x []ad.Var) ad.Var {
+		var f ad.Var
+		for i, c := range []float64{1.5, 2.25, 2.625} {
+			t := ad.Sub(ad.Const(c), ad.Mul(x[0], ad.Sub(ad.Const(1), ad.PowReal(x[1], float64(i+1)))))
+			f = ad.Add(f, ad.Mul(t, t))
+		}
+		return f
+	})
+
+	p := optimize.Problem{
+		Func: beale.Func,
+		Grad: beale.Grad,
+	}
+	res, err := optimize.Minimize(p, []float64{1, 1}, nil, &optimize.BFGS{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = %.4f\n", res.X)
+	// Output:
+	// x = [3.0000 0.5000]
+}
@@ -0,0 +1,92 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+)
+
+// DualFunc is a scalar function of several variables written in terms of dual
+// numbers. Its gradient is computed in forward mode with one evaluation per
+// variable.
+type DualFunc func(x []dual.Number) dual.Number
+
+// Func returns the value of the function at x.
+func (f DualFunc) Func(x []float64) float64 {
+	d := make([]dual.Number, len(x))
+	for i, v := range x {
+		d[i].Real = v
+	}
+	return f(d).Real
+}
+
+// Grad stores the gradient of the function at x into grad. Grad panics if the
+// lengths of grad and x do not match.
+func (f DualFunc) Grad(grad, x []float64) {
+	if len(grad) != len(x) {
+		panic(badLength)
+	}
+	d := make([]dual.Number, len(x))
+	for i, v := range x {
+		d[i].Real = v
+	}
+	for i := range d {
+		d[i].Emag = 1
+		grad[i] = f(d).Emag
+		d[i].Emag = 0
+	}
+}
+
+// HyperdualFunc is a scalar function of several variables written in terms of
+// hyperdual numbers. Its gradient and Hessian are computed in forward mode with
+// one evaluation per variable and per pair of variables, respectively.
+type HyperdualFunc func(x []hyperdual.Number) hyperdual.Number
+
+// Func returns the value of the function at x.
+func (f HyperdualFunc) Func(x []float64) float64 {
+	return f(f.hyper(x)).Real
+}
+
+// Grad stores the gradient of the function at x into grad. Grad panics if the
+// lengths of grad and x do not match.
+func (f HyperdualFunc) Grad(grad, x []float64) {
+	if len(grad) != len(x) {
+		panic(badLength)
+	}
+	d := f.hyper(x)
+	for i := range d {
+		d[i].E1mag = 1
+		grad[i] = f(d).E1mag
+		d[i].E1mag = 0
+	}
+}
+
+// Hess stores the Hessian of the function at x into dst. Hess panics if the
+// dimension of dst does not match the length of x.
+func (f HyperdualFunc) Hess(dst *mat.SymDense, x []float64) {
+	if dst.SymmetricDim() != len(x) {
+		panic(badLength)
+	}
+	d := f.hyper(x)
+	for i := range d {
+		d[i].E1mag = 1
+		for j := i; j < len(d); j++ {
+			d[j].E2mag = 1
+			dst.SetSym(i, j, f(d).E1E2mag)
+			d[j].E2mag = 0
+		}
+		d[i].E1mag = 0
+	}
+}
+
+func (HyperdualFunc) hyper(x []float64) []hyperdual.Number {
+	d := make([]hyperdual.Number, len(x))
+	for i, v := range x {
+		d[i].Real = v
+	}
+	return d
+}
@@ -0,0 +1,272 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ad
+
+import "math"
+
+const badLength = "ad: length mismatch"
+
+// ReverseFunc is a scalar function of several variables written in terms of
+// Vars recorded on a Tape. Its gradient is computed in reverse mode with a
+// single evaluation of the function.
+type ReverseFunc func(t *Tape, x []Var) Var
+
+// Func returns the value of the function at x.
+func (f ReverseFunc) Func(x []float64) float64 {
+	var t Tape
+	return f(&t, t.Vars(x)).Value()
+}
+
+// Grad stores the gradient of the function at x into grad. Grad panics if the
+// lengths of grad and x do not match.
+func (f ReverseFunc) Grad(grad, x []float64) {
+	if len(grad) != len(x) {
+		panic(badLength)
+	}
+	var t Tape
+	vars := t.Vars(x)
+	t.Gradient(grad, f(&t, vars), vars)
+}
+
+// Tape records the operations on Vars for reverse mode differentiation. The
+// zero value is an empty Tape ready to use. A Tape is not safe for concurrent
+// use.
+type Tape struct {
+	nodes []node
+}
+
+// node is a recorded operation with up to two arguments, holding the indices of
+// the arguments on the tape and the partial derivatives of the result with
+// respect to them. Unused arguments have index -1.
+type node struct {
+	args    [2]int
+	partial [2]float64
+}
+
+// Var is a variable of a function differentiated in reverse mode. The zero
+// value is the constant 0.
+type Var struct {
+	tape *Tape
+	// idx is the index of the node recording the variable, or
+	// -1 for constants.
+	idx   int
+	value float64
+}
+
+// Const returns a constant Var with the value v. Constants are not recorded on
+// a Tape.
+func Const(v float64) Var {
+	return Var{idx: -1, value: v}
+}
+
+// Value returns the value of v.
+func (v Var) Value() float64 {
+	return v.value
+}
+
+// Vars returns independent variables recorded on t with the values in x.
+func (t *Tape) Vars(x []float64) []Var {
+	vars := make([]Var, len(x))
+	for i, v := range x {
+		vars[i] = t.record(v, node{args: [2]int{-1, -1}})
+	}
+	return vars
+}
+
+// Reset removes all of the recorded operations from t, invalidating the Vars
+// recorded on it.
+func (t *Tape) Reset() {
+	t.nodes = t.nodes[:0]
+}
+
+// Gradient stores into grad the derivatives of y with respect to the
+// variables in wrt, which must be recorded on t. If y is a constant, the
+// derivatives are zero. Gradient panics if the lengths of grad and wrt do not
+// match or if y is recorded on a different Tape.
+func (t *Tape) Gradient(grad []float64, y Var, wrt []Var) {
+	if len(grad) != len(wrt) {
+		panic(badLength)
+	}
+	if y.tape == nil {
+		for i := range grad {
+			grad[i] = 0
+		}
+		return
+	}
+	if y.tape != t {
+		panic("ad: variable not recorded on tape")
+	}
+	adj := make([]float64, len(t.nodes))
+	adj[y.idx] = 1
+	for i := y.idx; i >= 0; i-- {
+		a := adj[i]
+		if a == 0 {
+			continue
+		}
+		n := t.nodes[i]
+		for k, arg := range n.args {
+			if arg >= 0 {
+				adj[arg] += a * n.partial[k]
+			}
+		}
+	}
+	for i, v := range wrt {
+		if v.tape != t {
+			panic("ad: variable not recorded on tape")
+		}
+		grad[i] = adj[v.idx]
+	}
+}
+
+func (t *Tape) record(value float64, n node) Var {
+	t.nodes = append(t.nodes, n)
+	return Var{tape: t, idx: len(t.nodes) - 1, value: value}
+}
+
+// unary returns the result of an operation on x with the given value and
+// derivative.
+func unary(x Var, value, deriv float64) Var {
+	if x.tape == nil {
+		return Const(value)
+	}
+	return x.tape.record(value, node{args: [2]int{x.idx, -1}, partial: [2]float64{deriv, 0}})
+}
+
+// binary returns the result of an operation on x and y with the given value
+// and partial derivatives.
+func binary(x, y Var, value, dx, dy float64) Var {
+	switch {
+	case x.tape == nil && y.tape == nil:
+		return Const(value)
+	case x.tape == nil:
+		return unary(y, value, dy)
+	case y.tape == nil:
+		return unary(x, value, dx)
+	case x.tape != y.tape:
+		panic("ad: variables recorded on different tapes")
+	}
+	return x.tape.record(value, node{args: [2]int{x.idx, y.idx}, partial: [2]float64{dx, dy}})
+}
+
+// Add returns the sum of x and y.
+func Add(x, y Var) Var {
+	return binary(x, y, x.value+y.value, 1, 1)
+}
+
+// Sub returns the difference of x and y, x-y.
+func Sub(x, y Var) Var {
+	return binary(x, y, x.value-y.value, 1, -1)
+}
+
+// Mul returns the product of x and y.
+func Mul(x, y Var) Var {
+	return binary(x, y, x.value*y.value, y.value, x.value)
+}
+
+// Div returns the quotient of x and y, x/y.
+func Div(x, y Var) Var {
+	q := x.value / y.value
+	return binary(x, y, q, 1/y.value, -q/y.value)
+}
+
+// Inv returns the reciprocal of x.
+func Inv(x Var) Var {
+	r := 1 / x.value
+	return unary(x, r, -r*r)
+}
+
+// Scale returns x scaled by f.
+func Scale(f float64, x Var) Var {
+	return unary(x, f*x.value, f)
+}
+
+// Abs returns the absolute value of x. The derivative at zero is taken to be
+// zero.
+func Abs(x Var) Var {
+	var d float64
+	switch {
+	case x.value > 0:
+		d = 1
+	case x.value < 0:
+		d = -1
+	}
+	return unary(x, math.Abs(x.value), d)
+}
+
+// Sqrt returns the square root of x.
+func Sqrt(x Var) Var {
+	s := math.Sqrt(x.value)
+	return unary(x, s, 0.5/s)
+}
+
+// PowReal returns x**p, the base-x exponential of p.
+func PowReal(x Var, p float64) Var {
+	v := math.Pow(x.value, p)
+	if p == 0 {
+		return unary(x, v, 0)
+	}
+	return unary(x, v, p*math.Pow(x.value, p-1))
+}
+
+// Pow returns x**p, the base-x exponential of p. The derivative with respect
+// to p is only defined for positive x.
+func Pow(x, p Var) Var {
+	v := math.Pow(x.value, p.value)
+	var dx, dp float64
+	if p.value != 0 {
+		dx = p.value * math.Pow(x.value, p.value-1)
+	}
+	if p.tape != nil {
+		dp = v * math.Log(x.value)
+	}
+	return binary(x, p, v, dx, dp)
+}
+
+// Exp returns e**x, the base-e exponential of x.
+func Exp(x Var) Var {
+	e := math.Exp(x.value)
+	return unary(x, e, e)
+}
+
+// Log returns the natural logarithm of x.
+func Log(x Var) Var {
+	return unary(x, math.Log(x.value), 1/x.value)
+}
+
+// Sin returns the sine of x.
+func Sin(x Var) Var {
+	return unary(x, math.Sin(x.value), math.Cos(x.value))
+}
+
+// Cos returns the cosine of x.
+func Cos(x Var) Var {
+	return unary(x, math.Cos(x.value), -math.Sin(x.value))
+}
+
+// Tan returns the tangent of x.
+func Tan(x Var) Var {
+	t := math.Tan(x.value)
+	return unary(x, t, 1+t*t)
+}
+
+// Atan returns the inverse tangent of x.
+func Atan(x Var) Var {
+	return unary(x, math.Atan(x.value), 1/(1+x.value*x.value))
+}
+
+// Tanh returns the hyperbolic tangent of x.
+func Tanh(x Var) Var {
+	t := math.Tanh(x.value)
+	return unary(x, t, 1-t*t)
+}
+
+// Sum returns the sum of the elements of x.
+func Sum(x []Var) Var {
+	s := Const(0)
+	for _, v := range x {
+		s = Add(s, v)
+	}
+	return s
+}
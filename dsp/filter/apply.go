@@ -0,0 +1,217 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import "gonum.org/v1/gonum/mat"
+
+// Filter applies the filter with numerator b and denominator a to the
+// sequence x, placing the result in dst and returning it. The filter is
+// implemented in transposed direct form II. If a is nil, the filter is
+// treated as an FIR filter.
+//
+// The state slice holds the initial conditions of the filter's delays and
+// on return holds their final conditions, allowing a long sequence to be
+// filtered in blocks. Its length must be max(len(a), len(b))-1. If state is
+// nil, the initial conditions are zero. InitialState returns the state for
+// a step response in steady state.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil,
+// it must have the same length as x. It is safe to use the same slice for
+// dst and x. Filter will panic if b is empty, if a[0] is zero, or if the
+// lengths of dst or state are not valid.
+func Filter(dst, b, a, x, state []float64) []float64 {
+	b, a = normalize(b, a)
+	n := len(b)
+	if dst == nil {
+		dst = make([]float64, len(x))
+	} else if len(dst) != len(x) {
+		panic("filter: destination length mismatch")
+	}
+	if state == nil {
+		state = make([]float64, n-1)
+	} else if len(state) != n-1 {
+		panic("filter: state length mismatch")
+	}
+	for i, v := range x {
+		if n == 1 {
+			dst[i] = b[0] * v
+			continue
+		}
+		y := b[0]*v + state[0]
+		for j := 1; j < n-1; j++ {
+			state[j-1] = b[j]*v + state[j] - a[j]*y
+		}
+		state[n-2] = b[n-1]*v - a[n-1]*y
+		dst[i] = y
+	}
+	return dst
+}
+
+// normalize returns copies of b and a padded to the same length and scaled
+// so that a[0] is one.
+func normalize(b, a []float64) (nb, na []float64) {
+	if len(b) == 0 {
+		panic("filter: empty numerator")
+	}
+	if a == nil {
+		a = []float64{1}
+	}
+	if len(a) == 0 || a[0] == 0 {
+		panic("filter: zero leading denominator coefficient")
+	}
+	n := len(b)
+	if len(a) > n {
+		n = len(a)
+	}
+	nb = make([]float64, n)
+	na = make([]float64, n)
+	for i, v := range b {
+		nb[i] = v / a[0]
+	}
+	for i, v := range a {
+		na[i] = v / a[0]
+	}
+	return nb, na
+}
+
+// InitialState returns the initial state of the filter with numerator b and
+// denominator a for which the response to a constant unit input is in steady
+// state. Scaling the returned state by the first element of a sequence and
+// passing it to Filter reduces the start-up transient. If a is nil, the
+// filter is treated as an FIR filter.
+//
+// InitialState will panic if b is empty, if a[0] is zero, or if the filter
+// has a pole at z = 1, so that it has no steady state response.
+func InitialState(b, a []float64) []float64 {
+	b, a = normalize(b, a)
+	n := len(b) - 1
+	if n == 0 {
+		return []float64{}
+	}
+
+	// The steady state satisfies (I - Aᵀ) zi = b[1:] - a[1:]*b[0]
+	// where A is the companion matrix of a.
+	m := mat.NewDense(n, n, nil)
+	rhs := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, 0, a[i+1])
+		m.Set(i, i, m.At(i, i)+1)
+		if i+1 < n {
+			m.Set(i, i+1, -1)
+		}
+		rhs.SetVec(i, b[i+1]-a[i+1]*b[0])
+	}
+	var zi mat.VecDense
+	err := zi.SolveVec(m, rhs)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic("filter: no steady state response")
+		}
+	}
+	return zi.RawVector().Data
+}
+
+// FiltFilt applies the filter with numerator b and denominator a to the
+// sequence x forwards and then backwards, placing the result in dst and
+// returning it. The result has zero phase distortion and a magnitude
+// response that is the square of the filter's. If a is nil, the filter is
+// treated as an FIR filter.
+//
+// To reduce transients at the ends, the sequence is extended at each end by
+// its odd reflection about the end element, by 3*max(len(a), len(b))
+// elements or len(x)-1 elements if that is fewer, and the filter state is
+// initialized with the steady state response to the first element of each
+// pass.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil,
+// it must have the same length as x. It is safe to use the same slice for
+// dst and x. FiltFilt will panic under the same conditions as Filter and
+// InitialState.
+func FiltFilt(dst, b, a, x []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(x))
+	} else if len(dst) != len(x) {
+		panic("filter: destination length mismatch")
+	}
+	if len(x) == 0 {
+		return dst
+	}
+	zi := InitialState(b, a)
+
+	pad := 3 * (len(zi) + 1)
+	if pad > len(x)-1 {
+		pad = len(x) - 1
+	}
+	ext := make([]float64, len(x)+2*pad)
+	copy(ext[pad:], x)
+	first, last := x[0], x[len(x)-1]
+	for i := 1; i <= pad; i++ {
+		ext[pad-i] = 2*first - x[i]
+		ext[pad+len(x)-1+i] = 2*last - x[len(x)-1-i]
+	}
+
+	state := make([]float64, len(zi))
+	for i, v := range zi {
+		state[i] = v * ext[0]
+	}
+	Filter(ext, b, a, ext, state)
+	reverse(ext)
+	for i, v := range zi {
+		state[i] = v * ext[0]
+	}
+	Filter(ext, b, a, ext, state)
+	reverse(ext)
+	copy(dst, ext[pad:pad+len(x)])
+	return dst
+}
+
+func reverse(s []float64) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// FilterColumns applies the filter with numerator b and denominator a to
+// each column of m using Filter with zero initial conditions, placing the
+// result in dst. Each column of m is treated as a sequence.
+//
+// If dst is empty, it is resized to the dimensions of m. Otherwise dst must
+// have the same dimensions as m. FilterColumns will panic under the same
+// conditions as Filter.
+func FilterColumns(dst *mat.Dense, b, a []float64, m mat.Matrix) {
+	applyColumns(dst, m, func(col []float64) {
+		Filter(col, b, a, col, nil)
+	})
+}
+
+// FiltFiltColumns applies the filter with numerator b and denominator a to
+// each column of m using FiltFilt, placing the result in dst. Each column of
+// m is treated as a sequence.
+//
+// If dst is empty, it is resized to the dimensions of m. Otherwise dst must
+// have the same dimensions as m. FiltFiltColumns will panic under the same
+// conditions as FiltFilt.
+func FiltFiltColumns(dst *mat.Dense, b, a []float64, m mat.Matrix) {
+	applyColumns(dst, m, func(col []float64) {
+		FiltFilt(col, b, a, col)
+	})
+}
+
+// applyColumns applies fn in place to a copy of each column of m and
+// stores the result in the corresponding column of dst.
+func applyColumns(dst *mat.Dense, m mat.Matrix, fn func([]float64)) {
+	r, c := m.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic(mat.ErrShape)
+	}
+	col := make([]float64, r)
+	for j := 0; j < c; j++ {
+		mat.Col(col, j, m)
+		fn(col)
+		dst.SetCol(j, col)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		b, a []float64
+	}{
+		{name: "gain", b: []float64{2}},
+		{name: "fir", b: []float64{0.5, 0.25, -0.125}},
+		{name: "iir", b: []float64{0.2, 0.1}, a: []float64{1, -0.5, 0.25}},
+		{name: "unnormalized", b: []float64{0.4, 0.2, 0.1, 0.3}, a: []float64{2, -1}},
+	} {
+		x := make([]float64, 50)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+		}
+		a := test.a
+		if a == nil {
+			a = []float64{1}
+		}
+
+		// Evaluate the difference equation directly.
+		want := make([]float64, len(x))
+		for n := range want {
+			var v float64
+			for k, c := range test.b {
+				if n-k >= 0 {
+					v += c * x[n-k]
+				}
+			}
+			for k := 1; k < len(a); k++ {
+				if n-k >= 0 {
+					v -= a[k] * want[n-k]
+				}
+			}
+			want[n] = v / a[0]
+		}
+
+		got := Filter(nil, test.b, test.a, x, nil)
+		if !floats.EqualApprox(got, want, tol) {
+			t.Errorf("unexpected result for %s:\ngot: %v\nwant:%v", test.name, got, want)
+		}
+
+		// Filtering in blocks with state must match.
+		n := len(test.b)
+		if len(a) > n {
+			n = len(a)
+		}
+		state := make([]float64, n-1)
+		blocks := make([]float64, len(x))
+		copy(blocks, x)
+		for _, r := range [][2]int{{0, 7}, {7, 8}, {8, 30}, {30, 50}} {
+			Filter(blocks[r[0]:r[1]], test.b, test.a, blocks[r[0]:r[1]], state)
+		}
+		if !floats.EqualApprox(blocks, want, tol) {
+			t.Errorf("unexpected block result for %s:\ngot: %v\nwant:%v", test.name, blocks, want)
+		}
+	}
+}
+
+func TestInitialState(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	for _, test := range []struct {
+		name string
+		b, a []float64
+	}{
+		{name: "fir", b: FIRWindow(11, Lowpass, nil, 0.2)},
+		{name: "butterworth", b: nil},
+		{name: "elliptic"},
+	} {
+		b, a := test.b, test.a
+		switch test.name {
+		case "butterworth":
+			b, a = Butterworth(4, Bandpass, 0.1, 0.2)
+		case "elliptic":
+			b, a = Elliptic(5, 0.5, 40, Highpass, 0.3)
+		}
+		zi := InitialState(b, a)
+
+		// The response to a unit step from the steady
+		// state is constant at the DC gain.
+		dc := real(Response(b, a, 0))
+		x := make([]float64, 30)
+		for i := range x {
+			x[i] = 1
+		}
+		y := Filter(nil, b, a, x, zi)
+		for i, v := range y {
+			if math.Abs(v-dc) > tol {
+				t.Errorf("unexpected step response for %s at %d: got:%v want:%v", test.name, i, v, dc)
+				break
+			}
+		}
+	}
+}
+
+func TestFiltFilt(t *testing.T) {
+	t.Parallel()
+	b, a := Butterworth(4, Lowpass, 0.1)
+	const n = 400
+	x := make([]float64, n)
+	for i := range x {
+		// A pass band tone and a stop band tone.
+		x[i] = math.Sin(2*math.Pi*0.02*float64(i)) + 0.5*math.Sin(2*math.Pi*0.3*float64(i))
+	}
+	y := FiltFilt(nil, b, a, x)
+
+	// The result is the pass band tone without phase shift,
+	// scaled by the squared magnitude of the response.
+	h := Response(b, a, 0.02)
+	g := real(h)*real(h) + imag(h)*imag(h)
+	for i := 50; i < n-50; i++ {
+		want := g * math.Sin(2*math.Pi*0.02*float64(i))
+		if math.Abs(y[i]-want) > 1e-3 {
+			t.Errorf("unexpected value at %d: got:%v want:%v", i, y[i], want)
+			break
+		}
+	}
+
+	// Away from the ends, filtering the reversed sequence
+	// gives the reversed result.
+	rx := append([]float64(nil), x...)
+	reverse(rx)
+	ry := FiltFilt(nil, b, a, rx)
+	reverse(ry)
+	if !floats.EqualApprox(ry[100:n-100], y[100:n-100], 1e-6) {
+		t.Errorf("result not symmetric under reversal")
+	}
+
+	// Short sequences are handled.
+	for _, m := range []int{0, 1, 2, 5} {
+		got := FiltFilt(nil, b, a, x[:m])
+		if len(got) != m {
+			t.Errorf("unexpected length for short sequence: got:%d want:%d", len(got), m)
+		}
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const r, c = 60, 3
+	m := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	b, a := Chebyshev1(3, 1, Lowpass, 0.2)
+
+	for _, test := range []struct {
+		name string
+		mat  func(dst *mat.Dense, b, a []float64, m mat.Matrix)
+		seq  func(x []float64) []float64
+	}{
+		{
+			name: "FilterColumns", mat: FilterColumns,
+			seq: func(x []float64) []float64 { return Filter(nil, b, a, x, nil) },
+		},
+		{
+			name: "FiltFiltColumns", mat: FiltFiltColumns,
+			seq: func(x []float64) []float64 { return FiltFilt(nil, b, a, x) },
+		},
+	} {
+		var dst mat.Dense
+		test.mat(&dst, b, a, m)
+		for j := 0; j < c; j++ {
+			want := test.seq(mat.Col(nil, j, m))
+			got := mat.Col(nil, j, &dst)
+			if !floats.Equal(got, want) {
+				t.Errorf("unexpected column %d for %s", j, test.name)
+			}
+		}
+
+		// The result may be placed in the input.
+		in := mat.DenseCopyOf(m)
+		test.mat(in, b, a, in)
+		if !mat.Equal(in, &dst) {
+			t.Errorf("unexpected in place result for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package filter provides digital filter design and filtering of sequences.
+//
+// Filters are described by the coefficients of the numerator, b, and
+// denominator, a, of their transfer function,
+//  H(z) = (b[0] + b[1]*z⁻¹ + ... + b[M]*z⁻ᴹ) / (a[0] + a[1]*z⁻¹ + ... + a[N]*z⁻ᴺ).
+// Finite impulse response (FIR) filters have a denominator of one.
+//
+// Frequencies are relative frequencies in cycles per sample, so the Nyquist
+// frequency is 0.5. This is the same convention used by the Freq methods of
+// the transforms in the gonum.org/v1/gonum/dsp/fourier package. A frequency
+// f in Hz for a sequence sampled at fs Hz corresponds to the relative
+// frequency f/fs.
+package filter // import "gonum.org/v1/gonum/dsp/filter"
@@ -0,0 +1,132 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// ellipticK returns the complete elliptic integral of the first kind
+// with parameter m, K(m), computed by the arithmetic-geometric mean.
+func ellipticK(m float64) float64 {
+	return math.Pi / (2 * agm(1, math.Sqrt(1-m)))
+}
+
+// ellipticKm1 returns K(1-p), computed accurately for small p.
+func ellipticKm1(p float64) float64 {
+	return math.Pi / (2 * agm(1, math.Sqrt(p)))
+}
+
+// agm returns the arithmetic-geometric mean of a and b.
+func agm(a, b float64) float64 {
+	for i := 0; i < 64 && math.Abs(a-b) > 1e-16*a; i++ {
+		a, b = (a+b)/2, math.Sqrt(a*b)
+	}
+	return a
+}
+
+// ellipticDegree returns the parameter m of the elliptic functions of an
+// elliptic filter of order n and discrimination parameter m1 satisfying
+// the degree equation n*K(1-m)/K(m) = K(1-m1)/K(m1), computed using the
+// nome.
+func ellipticDegree(n int, m1 float64) float64 {
+	const terms = 7
+	q1 := math.Exp(-math.Pi * ellipticKm1(m1) / ellipticK(m1))
+	q := math.Pow(q1, 1/float64(n))
+	var num float64
+	for i := 0; i <= terms; i++ {
+		num += math.Pow(q, float64(i*(i+1)))
+	}
+	den := 1.0
+	for i := 1; i <= terms+1; i++ {
+		den += 2 * math.Pow(q, float64(i*i))
+	}
+	r := num / den
+	return 16 * q * r * r * r * r
+}
+
+// jacobi returns the Jacobi elliptic functions sn, cn and dn of u with
+// parameter m, computed by the descending Landen transformation.
+func jacobi(u, m float64) (sn, cn, dn float64) {
+	switch {
+	case m < 1e-9:
+		t, b := math.Sincos(u)
+		ai := 0.25 * m * (u - t*b)
+		return t - ai*b, b + ai*t, 1 - 0.5*m*t*t
+	case m >= 0.9999999999:
+		ai := 0.25 * (1 - m)
+		b := math.Cosh(u)
+		t := math.Tanh(u)
+		phi := 1 / b
+		twon := b * math.Sinh(u)
+		sn = t + ai*(twon-u)/(b*b)
+		ai *= t * phi
+		return sn, phi - ai*(twon-u), phi + ai*(twon+u)
+	}
+
+	const maxIter = 16
+	var a, c [maxIter + 1]float64
+	a[0] = 1
+	b := math.Sqrt(1 - m)
+	c[0] = math.Sqrt(m)
+	twon := 1.0
+	i := 0
+	for i < maxIter && math.Abs(c[i]/a[i]) > 1e-16 {
+		ai := a[i]
+		i++
+		c[i] = (ai - b) / 2
+		t := math.Sqrt(ai * b)
+		a[i] = (ai + b) / 2
+		b = t
+		twon *= 2
+	}
+	phi := twon * a[i] * u
+	var prev float64
+	for ; i > 0; i-- {
+		t := c[i] * math.Sin(phi) / a[i]
+		prev = phi
+		phi = (math.Asin(t) + phi) / 2
+	}
+	sn, cn = math.Sincos(phi)
+	return sn, cn, cn / math.Cos(prev-phi)
+}
+
+// arcJacobiSC1 returns the real z satisfying sc(z, 1-m) = w, where
+// sc = sn/cn, using the identity sc(z, 1-m) = -i*sn(iz, m).
+func arcJacobiSC1(w, m float64) float64 {
+	return imag(arcJacobiSN(complex(0, w), m))
+}
+
+// arcJacobiSN returns the inverse of the Jacobi elliptic function sn of
+// the complex argument w with parameter m, computed by the descending
+// Landen transformation.
+func arcJacobiSN(w complex128, m float64) complex128 {
+	k := math.Sqrt(m)
+	if k == 1 {
+		return cmplx.Atanh(w)
+	}
+	complement := func(x complex128) complex128 {
+		return cmplx.Sqrt((1 - x) * (1 + x))
+	}
+	ks := []float64{k}
+	for ks[len(ks)-1] != 0 {
+		kn := ks[len(ks)-1]
+		kp := math.Sqrt((1 - kn) * (1 + kn))
+		ks = append(ks, (1-kp)/(1+kp))
+		if len(ks) > 11 {
+			break
+		}
+	}
+	capk := math.Pi / 2
+	for _, kn := range ks[1:] {
+		capk *= 1 + kn
+	}
+	for i, kn := range ks[:len(ks)-1] {
+		next := ks[i+1]
+		w = 2 * w / (complex(1+next, 0) * (1 + complement(complex(kn, 0)*w)))
+	}
+	return complex(capk*2/math.Pi, 0) * cmplx.Asin(w)
+}
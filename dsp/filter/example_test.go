@@ -0,0 +1,42 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter_test
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/filter"
+)
+
+func Example() {
+	// Design a fourth order lowpass Butterworth filter with
+	// a cutoff of 50 Hz for a sequence sampled at 1 kHz.
+	const fs = 1000.0
+	b, a := filter.Butterworth(4, filter.Lowpass, 50/fs)
+
+	for _, f := range []float64{10, 50, 100, 200} {
+		gain := 20 * math.Log10(cmplx.Abs(filter.Response(b, a, f/fs)))
+		fmt.Printf("gain at %3v Hz: %6.2f dB\n", f, gain)
+	}
+
+	// Remove the 200 Hz component of a signal
+	// without shifting the 10 Hz component.
+	x := make([]float64, 1000)
+	for i := range x {
+		t := float64(i) / fs
+		x[i] = math.Sin(2*math.Pi*10*t) + math.Sin(2*math.Pi*200*t)
+	}
+	y := filter.FiltFilt(nil, b, a, x)
+	fmt.Printf("y[525] = %.3f, sin(2π*10*0.525) = %.3f\n", y[525], math.Sin(2*math.Pi*10*0.525))
+
+	// Output:
+	// gain at  10 Hz:  -0.00 dB
+	// gain at  50 Hz:  -3.01 dB
+	// gain at 100 Hz: -24.98 dB
+	// gain at 200 Hz: -52.92 dB
+	// y[525] = 1.000, sin(2π*10*0.525) = 1.000
+}
@@ -0,0 +1,158 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import "math"
+
+// BandType specifies the frequency band passed by a filter.
+type BandType int
+
+const (
+	// Lowpass passes frequencies below a cutoff.
+	Lowpass BandType = iota
+	// Highpass passes frequencies above a cutoff.
+	Highpass
+	// Bandpass passes frequencies between two cutoffs.
+	Bandpass
+	// Bandstop passes frequencies outside two cutoffs.
+	Bandstop
+)
+
+// checkCutoff panics if cutoff does not hold valid cutoff frequencies for
+// the band type.
+func checkCutoff(band BandType, cutoff []float64) {
+	switch band {
+	case Lowpass, Highpass:
+		if len(cutoff) != 1 {
+			panic("filter: lowpass and highpass filters require one cutoff")
+		}
+	case Bandpass, Bandstop:
+		if len(cutoff) != 2 {
+			panic("filter: bandpass and bandstop filters require two cutoffs")
+		}
+		if !(cutoff[0] < cutoff[1]) {
+			panic("filter: cutoffs not increasing")
+		}
+	default:
+		panic("filter: unknown band type")
+	}
+	for _, f := range cutoff {
+		if !(0 < f && f < 0.5) {
+			panic("filter: cutoff out of range")
+		}
+	}
+}
+
+// FIRWindow returns the n coefficients of a linear phase FIR filter designed
+// by the window method. The ideal impulse response of the band given by the
+// band type and cutoff frequencies is truncated to n coefficients and
+// multiplied by the window. Lowpass and Highpass filters take a single
+// cutoff and Bandpass and Bandstop filters take a lower and upper cutoff.
+// The coefficients are scaled to give unit gain at zero frequency for
+// Lowpass and Bandstop filters, at the Nyquist frequency for Highpass
+// filters and at the center of the pass band for Bandpass filters.
+//
+// The window function is called once with a slice of n ones and must return
+// the window weights; the functions in gonum.org/v1/gonum/dsp/window may be
+// used. If window is nil, a rectangular window is used. KaiserDesign in that
+// package gives the length and Kaiser window parameter for a required
+// attenuation and transition width.
+//
+// FIRWindow will panic if n is not positive, if the cutoffs are not in
+// (0, 0.5) or do not match the band type, or if n is even and the band
+// type is Highpass or Bandstop, since those filters require a non-zero
+// response at the Nyquist frequency.
+func FIRWindow(n int, band BandType, window func([]float64) []float64, cutoff ...float64) []float64 {
+	if n <= 0 {
+		panic("filter: non-positive filter length")
+	}
+	checkCutoff(band, cutoff)
+	if n%2 == 0 && (band == Highpass || band == Bandstop) {
+		panic("filter: even length filter has zero response at Nyquist")
+	}
+
+	// edges holds the pass bands as pairs of frequencies.
+	var edges []float64
+	switch band {
+	case Lowpass:
+		edges = []float64{0, cutoff[0]}
+	case Highpass:
+		edges = []float64{cutoff[0], 0.5}
+	case Bandpass:
+		edges = []float64{cutoff[0], cutoff[1]}
+	case Bandstop:
+		edges = []float64{0, cutoff[0], cutoff[1], 0.5}
+	}
+
+	h := make([]float64, n)
+	mid := float64(n-1) / 2
+	for i := range h {
+		m := float64(i) - mid
+		for j := 0; j < len(edges); j += 2 {
+			h[i] += 2*edges[j+1]*sinc(2*edges[j+1]*m) - 2*edges[j]*sinc(2*edges[j]*m)
+		}
+	}
+	if window != nil {
+		w := make([]float64, n)
+		for i := range w {
+			w[i] = 1
+		}
+		w = window(w)
+		for i := range h {
+			h[i] *= w[i]
+		}
+	}
+
+	// Scale to unit gain at the reference frequency
+	// of the first pass band.
+	var f float64
+	switch {
+	case edges[0] == 0:
+		f = 0
+	case edges[1] == 0.5:
+		f = 0.5
+	default:
+		f = (edges[0] + edges[1]) / 2
+	}
+	var gain float64
+	for i, v := range h {
+		gain += v * math.Cos(2*math.Pi*f*(float64(i)-mid))
+	}
+	for i := range h {
+		h[i] /= gain
+	}
+	return h
+}
+
+// sinc returns the normalized sinc function, sin(πx)/(πx).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+// Response returns the complex frequency response of the filter with
+// numerator b and denominator a at the relative frequency f. If a is nil,
+// the filter is treated as an FIR filter.
+func Response(b, a []float64, f float64) complex128 {
+	return evalz(b, f) / evalz(a, f)
+}
+
+// evalz returns the value of the polynomial in z⁻¹ with coefficients c
+// at z = exp(2πif). If c is empty, evalz returns one.
+func evalz(c []float64, f float64) complex128 {
+	if len(c) == 0 {
+		return 1
+	}
+	var re, im float64
+	for k, v := range c {
+		s, co := math.Sincos(-2 * math.Pi * f * float64(k))
+		re += v * co
+		im += v * s
+	}
+	return complex(re, im)
+}
@@ -0,0 +1,106 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestFIRWindow(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		n      int
+		band   BandType
+		window func([]float64) []float64
+		cutoff []float64
+
+		// pass and stop are frequencies at which the
+		// response must be within tol of one and zero.
+		pass, stop []float64
+		ref        float64
+		tol        float64
+	}{
+		{
+			name: "lowpass", n: 61, band: Lowpass, window: window.Hamming, cutoff: []float64{0.1},
+			pass: []float64{0, 0.05, 0.07}, stop: []float64{0.14, 0.25, 0.5}, ref: 0, tol: 0.005,
+		},
+		{
+			name: "lowpass even", n: 60, band: Lowpass, window: window.Hamming, cutoff: []float64{0.2},
+			pass: []float64{0, 0.1, 0.16}, stop: []float64{0.24, 0.35, 0.5}, ref: 0, tol: 0.005,
+		},
+		{
+			name: "highpass", n: 61, band: Highpass, window: window.Blackman, cutoff: []float64{0.3},
+			pass: []float64{0.36, 0.45, 0.5}, stop: []float64{0, 0.1, 0.24}, ref: 0.5, tol: 0.005,
+		},
+		{
+			name: "bandpass", n: 81, band: Bandpass, window: window.Hamming, cutoff: []float64{0.1, 0.3},
+			pass: []float64{0.14, 0.2, 0.26}, stop: []float64{0, 0.05, 0.35, 0.5}, ref: 0.2, tol: 0.005,
+		},
+		{
+			name: "bandstop", n: 81, band: Bandstop, window: window.Hamming, cutoff: []float64{0.1, 0.3},
+			pass: []float64{0, 0.05, 0.35, 0.5}, stop: []float64{0.14, 0.2, 0.26}, ref: 0, tol: 0.005,
+		},
+		{
+			name: "rectangular", n: 201, band: Lowpass, cutoff: []float64{0.25},
+			pass: []float64{0, 0.1}, stop: []float64{0.4, 0.5}, ref: 0, tol: 0.05,
+		},
+	} {
+		h := FIRWindow(test.n, test.band, test.window, test.cutoff...)
+		if len(h) != test.n {
+			t.Errorf("unexpected length for %s: got:%d want:%d", test.name, len(h), test.n)
+			continue
+		}
+		for i := range h {
+			if math.Abs(h[i]-h[len(h)-1-i]) > 1e-15 {
+				t.Errorf("coefficients not symmetric for %s", test.name)
+				break
+			}
+		}
+		if g := cmplx.Abs(Response(h, nil, test.ref)); !scalar.EqualWithinAbs(g, 1, 1e-12) {
+			t.Errorf("unexpected gain at reference frequency for %s: got:%v want:1", test.name, g)
+		}
+		for _, f := range test.pass {
+			if g := cmplx.Abs(Response(h, nil, f)); math.Abs(g-1) > test.tol {
+				t.Errorf("unexpected pass band gain for %s at %v: got:%v", test.name, f, g)
+			}
+		}
+		for _, f := range test.stop {
+			if g := cmplx.Abs(Response(h, nil, f)); g > test.tol {
+				t.Errorf("unexpected stop band gain for %s at %v: got:%v", test.name, f, g)
+			}
+		}
+	}
+}
+
+func TestFIRWindowPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero length", fn: func() { FIRWindow(0, Lowpass, nil, 0.1) }},
+		{name: "two cutoffs", fn: func() { FIRWindow(11, Lowpass, nil, 0.1, 0.2) }},
+		{name: "one cutoff", fn: func() { FIRWindow(11, Bandpass, nil, 0.1) }},
+		{name: "decreasing cutoffs", fn: func() { FIRWindow(11, Bandpass, nil, 0.2, 0.1) }},
+		{name: "nyquist cutoff", fn: func() { FIRWindow(11, Lowpass, nil, 0.5) }},
+		{name: "even highpass", fn: func() { FIRWindow(10, Highpass, nil, 0.2) }},
+		{name: "even bandstop", fn: func() { FIRWindow(10, Bandstop, nil, 0.1, 0.2) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
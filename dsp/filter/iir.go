@@ -0,0 +1,374 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Butterworth returns the numerator and denominator coefficients of a
+// digital Butterworth filter of the given order. Lowpass and Highpass
+// filters take a single cutoff and Bandpass and Bandstop filters take a
+// lower and upper cutoff. The response at the cutoff frequencies is -3 dB.
+// Bandpass and Bandstop filters have twice the given order.
+//
+// The filter is designed from the analog prototype using the bilinear
+// transform with frequency prewarping. High order filters, particularly
+// with cutoffs close to zero or the Nyquist frequency, may be numerically
+// inaccurate in transfer function form.
+//
+// Butterworth will panic if order is not positive or if the cutoffs are not
+// in (0, 0.5) or do not match the band type.
+func Butterworth(order int, band BandType, cutoff ...float64) (b, a []float64) {
+	checkOrder(order)
+	z, p, k := butterworthPrototype(order)
+	return design(z, p, k, band, cutoff)
+}
+
+// Chebyshev1 returns the numerator and denominator coefficients of a
+// digital Chebyshev type I filter of the given order with the given pass
+// band ripple in decibels. The cutoff frequencies are the edges of the pass
+// band, where the response falls to -ripple dB. Lowpass and Highpass filters
+// take a single cutoff and Bandpass and Bandstop filters take a lower and
+// upper cutoff. Bandpass and Bandstop filters have twice the given order.
+//
+// See Butterworth for details of the design method. Chebyshev1 will panic
+// if order or ripple is not positive or if the cutoffs are not in (0, 0.5)
+// or do not match the band type.
+func Chebyshev1(order int, ripple float64, band BandType, cutoff ...float64) (b, a []float64) {
+	checkOrder(order)
+	if !(ripple > 0) {
+		panic("filter: non-positive ripple")
+	}
+	z, p, k := chebyshev1Prototype(order, ripple)
+	return design(z, p, k, band, cutoff)
+}
+
+// Chebyshev2 returns the numerator and denominator coefficients of a
+// digital Chebyshev type II filter of the given order with the given stop
+// band attenuation in decibels. The cutoff frequencies are the edges of the
+// stop band, where the response first reaches -attenuation dB. Lowpass and
+// Highpass filters take a single cutoff and Bandpass and Bandstop filters
+// take a lower and upper cutoff. Bandpass and Bandstop filters have twice
+// the given order.
+//
+// See Butterworth for details of the design method. Chebyshev2 will panic
+// if order or attenuation is not positive or if the cutoffs are not in
+// (0, 0.5) or do not match the band type.
+func Chebyshev2(order int, attenuation float64, band BandType, cutoff ...float64) (b, a []float64) {
+	checkOrder(order)
+	if !(attenuation > 0) {
+		panic("filter: non-positive attenuation")
+	}
+	z, p, k := chebyshev2Prototype(order, attenuation)
+	return design(z, p, k, band, cutoff)
+}
+
+// Elliptic returns the numerator and denominator coefficients of a digital
+// elliptic (Cauer) filter of the given order with the given pass band
+// ripple and stop band attenuation in decibels. The cutoff frequencies are
+// the edges of the pass band, where the response falls to -ripple dB.
+// Lowpass and Highpass filters take a single cutoff and Bandpass and
+// Bandstop filters take a lower and upper cutoff. Bandpass and Bandstop
+// filters have twice the given order.
+//
+// See Butterworth for details of the design method. Elliptic will panic if
+// order is not positive, if ripple is not positive, if attenuation is not
+// greater than ripple or if the cutoffs are not in (0, 0.5) or do not match
+// the band type.
+func Elliptic(order int, ripple, attenuation float64, band BandType, cutoff ...float64) (b, a []float64) {
+	checkOrder(order)
+	if !(ripple > 0) {
+		panic("filter: non-positive ripple")
+	}
+	if !(attenuation > ripple) {
+		panic("filter: attenuation not greater than ripple")
+	}
+	z, p, k := ellipticPrototype(order, ripple, attenuation)
+	return design(z, p, k, band, cutoff)
+}
+
+func checkOrder(order int) {
+	if order <= 0 {
+		panic("filter: non-positive filter order")
+	}
+}
+
+// design returns the transfer function coefficients of the digital filter
+// obtained from the normalized analog lowpass prototype with the given
+// zeros, poles and gain by transformation to the band type and cutoffs and
+// the bilinear transform.
+func design(z, p []complex128, k float64, band BandType, cutoff []float64) (b, a []float64) {
+	checkCutoff(band, cutoff)
+
+	// Prewarp the cutoffs for the bilinear transform
+	// with a sample rate of one.
+	warped := make([]float64, len(cutoff))
+	for i, f := range cutoff {
+		warped[i] = 2 * math.Tan(math.Pi*f)
+	}
+	switch band {
+	case Lowpass:
+		z, p, k = lowpassToLowpass(z, p, k, warped[0])
+	case Highpass:
+		z, p, k = lowpassToHighpass(z, p, k, warped[0])
+	case Bandpass:
+		bw := warped[1] - warped[0]
+		wo := math.Sqrt(warped[0] * warped[1])
+		z, p, k = lowpassToBandpass(z, p, k, wo, bw)
+	case Bandstop:
+		bw := warped[1] - warped[0]
+		wo := math.Sqrt(warped[0] * warped[1])
+		z, p, k = lowpassToBandstop(z, p, k, wo, bw)
+	}
+	z, p, k = bilinear(z, p, k)
+
+	b = realPoly(z)
+	for i := range b {
+		b[i] *= k
+	}
+	return b, realPoly(p)
+}
+
+// butterworthPrototype returns the zeros, poles and gain of the analog
+// Butterworth lowpass filter with a cutoff of 1 rad/s.
+func butterworthPrototype(n int) (z, p []complex128, k float64) {
+	p = make([]complex128, n)
+	for i := range p {
+		m := float64(2*i - n + 1)
+		p[i] = -cmplx.Exp(complex(0, math.Pi*m/float64(2*n)))
+	}
+	return nil, p, 1
+}
+
+// chebyshev1Prototype returns the zeros, poles and gain of the analog
+// Chebyshev type I lowpass filter with a pass band edge of 1 rad/s and the
+// given pass band ripple in decibels.
+func chebyshev1Prototype(n int, ripple float64) (z, p []complex128, k float64) {
+	eps := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	mu := math.Asinh(1/eps) / float64(n)
+	p = make([]complex128, n)
+	for i := range p {
+		theta := math.Pi * float64(2*i-n+1) / float64(2*n)
+		p[i] = -cmplx.Sinh(complex(mu, theta))
+	}
+	k = real(prodNeg(p))
+	if n%2 == 0 {
+		k /= math.Sqrt(1 + eps*eps)
+	}
+	return nil, p, k
+}
+
+// chebyshev2Prototype returns the zeros, poles and gain of the analog
+// Chebyshev type II lowpass filter with a stop band edge of 1 rad/s and the
+// given stop band attenuation in decibels.
+func chebyshev2Prototype(n int, attenuation float64) (z, p []complex128, k float64) {
+	de := 1 / math.Sqrt(math.Pow(10, attenuation/10)-1)
+	mu := math.Asinh(1/de) / float64(n)
+	for i := 0; i < n; i++ {
+		m := 2*i - n + 1
+		if m != 0 {
+			z = append(z, cmplx.Conj(-complex(0, 1)/complex(math.Sin(float64(m)*math.Pi/float64(2*n)), 0)))
+		}
+		q := -cmplx.Exp(complex(0, math.Pi*float64(m)/float64(2*n)))
+		q = complex(math.Sinh(mu)*real(q), math.Cosh(mu)*imag(q))
+		p = append(p, 1/q)
+	}
+	k = real(prodNeg(p) / prodNeg(z))
+	return z, p, k
+}
+
+// ellipticPrototype returns the zeros, poles and gain of the analog
+// elliptic lowpass filter with a pass band edge of 1 rad/s and the given
+// pass band ripple and stop band attenuation in decibels.
+func ellipticPrototype(n int, ripple, attenuation float64) (z, p []complex128, k float64) {
+	if n == 1 {
+		pole := -math.Sqrt(1 / (math.Pow(10, ripple/10) - 1))
+		return nil, []complex128{complex(pole, 0)}, -pole
+	}
+
+	eps := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	ck1 := eps / math.Sqrt(math.Pow(10, attenuation/10)-1)
+	ck1sq := ck1 * ck1
+	m := ellipticDegree(n, ck1sq)
+	capk := ellipticK(m)
+
+	var s, c, d []float64
+	for j := 1 - n%2; j < n; j += 2 {
+		sj, cj, dj := jacobi(float64(j)*capk/float64(n), m)
+		s = append(s, sj)
+		c = append(c, cj)
+		d = append(d, dj)
+	}
+	const tiny = 1e-14
+	for _, sj := range s {
+		if math.Abs(sj) > tiny {
+			z = append(z, complex(0, 1/(math.Sqrt(m)*sj)))
+		}
+	}
+	for i := range z {
+		z = append(z, cmplx.Conj(z[i]))
+	}
+
+	r := arcJacobiSC1(1/eps, ck1sq)
+	v0 := capk * r / (float64(n) * ellipticK(ck1sq))
+	sv, cv, dv := jacobi(v0, 1-m)
+	for i := range s {
+		den := 1 - (d[i]*sv)*(d[i]*sv)
+		p = append(p, -complex(c[i]*d[i]*sv*cv, s[i]*dv)/complex(den, 0))
+	}
+	var norm float64
+	for _, q := range p {
+		norm += real(q)*real(q) + imag(q)*imag(q)
+	}
+	norm = math.Sqrt(norm)
+	for _, q := range p[:len(s)] {
+		if n%2 == 0 || math.Abs(imag(q)) > tiny*norm {
+			p = append(p, cmplx.Conj(q))
+		}
+	}
+
+	k = real(prodNeg(p) / prodNeg(z))
+	if n%2 == 0 {
+		k /= math.Sqrt(1 + eps*eps)
+	}
+	return z, p, k
+}
+
+// lowpassToLowpass transforms an analog lowpass filter with a cutoff of
+// 1 rad/s to one with a cutoff of wo rad/s.
+func lowpassToLowpass(z, p []complex128, k, wo float64) ([]complex128, []complex128, float64) {
+	degree := len(p) - len(z)
+	z = scale(z, wo)
+	p = scale(p, wo)
+	return z, p, k * math.Pow(wo, float64(degree))
+}
+
+// lowpassToHighpass transforms an analog lowpass filter with a cutoff of
+// 1 rad/s to a highpass filter with a cutoff of wo rad/s.
+func lowpassToHighpass(z, p []complex128, k, wo float64) ([]complex128, []complex128, float64) {
+	degree := len(p) - len(z)
+	k *= real(prodNeg(z) / prodNeg(p))
+	zh := make([]complex128, 0, len(p))
+	for _, v := range z {
+		zh = append(zh, complex(wo, 0)/v)
+	}
+	for i := 0; i < degree; i++ {
+		zh = append(zh, 0)
+	}
+	ph := make([]complex128, len(p))
+	for i, v := range p {
+		ph[i] = complex(wo, 0) / v
+	}
+	return zh, ph, k
+}
+
+// lowpassToBandpass transforms an analog lowpass filter with a cutoff of
+// 1 rad/s to a bandpass filter with center frequency wo and bandwidth bw
+// in rad/s.
+func lowpassToBandpass(z, p []complex128, k, wo, bw float64) ([]complex128, []complex128, float64) {
+	degree := len(p) - len(z)
+	zb := splitRoots(scale(z, bw/2), wo)
+	for i := 0; i < degree; i++ {
+		zb = append(zb, 0)
+	}
+	pb := splitRoots(scale(p, bw/2), wo)
+	return zb, pb, k * math.Pow(bw, float64(degree))
+}
+
+// lowpassToBandstop transforms an analog lowpass filter with a cutoff of
+// 1 rad/s to a bandstop filter with center frequency wo and bandwidth bw
+// in rad/s.
+func lowpassToBandstop(z, p []complex128, k, wo, bw float64) ([]complex128, []complex128, float64) {
+	degree := len(p) - len(z)
+	k *= real(prodNeg(z) / prodNeg(p))
+	invert := func(r []complex128) []complex128 {
+		dst := make([]complex128, len(r))
+		for i, v := range r {
+			dst[i] = complex(bw/2, 0) / v
+		}
+		return dst
+	}
+	zb := splitRoots(invert(z), wo)
+	for i := 0; i < degree; i++ {
+		zb = append(zb, complex(0, wo))
+	}
+	for i := 0; i < degree; i++ {
+		zb = append(zb, complex(0, -wo))
+	}
+	pb := splitRoots(invert(p), wo)
+	return zb, pb, k
+}
+
+// splitRoots returns the roots r ± sqrt(r² - wo²) for each root r.
+func splitRoots(r []complex128, wo float64) []complex128 {
+	dst := make([]complex128, 2*len(r))
+	w2 := complex(wo*wo, 0)
+	for i, v := range r {
+		s := cmplx.Sqrt(v*v - w2)
+		dst[i] = v + s
+		dst[i+len(r)] = v - s
+	}
+	return dst
+}
+
+// bilinear transforms an analog filter to a digital filter using the
+// bilinear transform with a sample rate of one.
+func bilinear(z, p []complex128, k float64) ([]complex128, []complex128, float64) {
+	const fs2 = 2
+	degree := len(p) - len(z)
+	num, den := complex(1, 0), complex(1, 0)
+	zd := make([]complex128, 0, len(p))
+	for _, v := range z {
+		zd = append(zd, (fs2+v)/(fs2-v))
+		num *= fs2 - v
+	}
+	for i := 0; i < degree; i++ {
+		zd = append(zd, -1)
+	}
+	pd := make([]complex128, len(p))
+	for i, v := range p {
+		pd[i] = (fs2 + v) / (fs2 - v)
+		den *= fs2 - v
+	}
+	return zd, pd, k * real(num/den)
+}
+
+// scale returns a new slice holding the roots in r multiplied by f.
+func scale(r []complex128, f float64) []complex128 {
+	dst := make([]complex128, len(r))
+	for i, v := range r {
+		dst[i] = v * complex(f, 0)
+	}
+	return dst
+}
+
+// prodNeg returns the product of the negated roots in r.
+func prodNeg(r []complex128) complex128 {
+	v := complex(1, 0)
+	for _, x := range r {
+		v *= -x
+	}
+	return v
+}
+
+// realPoly returns the real parts of the coefficients of the monic
+// polynomial with the given roots, in order of decreasing degree.
+// The roots must occur in conjugate pairs for the result to be exact.
+func realPoly(roots []complex128) []float64 {
+	c := make([]complex128, len(roots)+1)
+	c[0] = 1
+	for i, r := range roots {
+		for j := i + 1; j > 0; j-- {
+			c[j] -= r * c[j-1]
+		}
+	}
+	dst := make([]float64, len(c))
+	for i, v := range c {
+		dst[i] = real(v)
+	}
+	return dst
+}
@@ -0,0 +1,262 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestButterworthCoefficients(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-12
+	b, a := Butterworth(4, Lowpass, 0.1)
+	wantB := []float64{0.004824343357716228, 0.019297373430864913, 0.02894606014629737, 0.019297373430864913, 0.004824343357716228}
+	wantA := []float64{1, -2.3695130071820376, 2.31398841441588, -1.0546654058785676, 0.1873794923681849}
+	if !floats.EqualApprox(b, wantB, tol) {
+		t.Errorf("unexpected numerator:\ngot: %v\nwant:%v", b, wantB)
+	}
+	if !floats.EqualApprox(a, wantA, tol) {
+		t.Errorf("unexpected denominator:\ngot: %v\nwant:%v", a, wantA)
+	}
+}
+
+// iirDesign is a filter design function with the
+// type and parameters of the design bound.
+type iirDesign struct {
+	name string
+	fn   func(order int, band BandType, cutoff ...float64) (b, a []float64)
+
+	// edge is the gain in dB at the cutoff frequencies.
+	edge float64
+	// ripple and attenuation are the pass band ripple
+	// and minimum stop band attenuation in dB.
+	ripple, attenuation float64
+}
+
+var iirDesigns = []iirDesign{
+	{
+		name: "Butterworth", fn: Butterworth,
+		edge: -10 * math.Log10(2),
+	},
+	{
+		name: "Chebyshev1",
+		fn: func(order int, band BandType, cutoff ...float64) (b, a []float64) {
+			return Chebyshev1(order, 0.5, band, cutoff...)
+		},
+		edge: -0.5, ripple: 0.5,
+	},
+	{
+		name: "Chebyshev2",
+		fn: func(order int, band BandType, cutoff ...float64) (b, a []float64) {
+			return Chebyshev2(order, 40, band, cutoff...)
+		},
+		edge: -40, attenuation: 40,
+	},
+	{
+		name: "Elliptic",
+		fn: func(order int, band BandType, cutoff ...float64) (b, a []float64) {
+			return Elliptic(order, 1, 50, band, cutoff...)
+		},
+		edge: -1, ripple: 1, attenuation: 50,
+	},
+}
+
+func TestIIRDesign(t *testing.T) {
+	t.Parallel()
+	for _, design := range iirDesigns {
+		for _, test := range []struct {
+			band   BandType
+			cutoff []float64
+		}{
+			{band: Lowpass, cutoff: []float64{0.1}},
+			{band: Lowpass, cutoff: []float64{0.35}},
+			{band: Highpass, cutoff: []float64{0.2}},
+			{band: Bandpass, cutoff: []float64{0.1, 0.25}},
+			{band: Bandstop, cutoff: []float64{0.15, 0.3}},
+		} {
+			for _, order := range []int{1, 2, 3, 4, 5} {
+				name := fmt.Sprintf("%s order=%d band=%d cutoff=%v", design.name, order, test.band, test.cutoff)
+				b, a := design.fn(order, test.band, test.cutoff...)
+				wantLen := order + 1
+				if test.band == Bandpass || test.band == Bandstop {
+					wantLen = 2*order + 1
+				}
+				if len(b) != wantLen || len(a) != wantLen {
+					t.Errorf("unexpected coefficient lengths for %s: got:%d,%d want:%d", name, len(b), len(a), wantLen)
+					continue
+				}
+
+				gain := func(f float64) float64 {
+					return 20 * math.Log10(cmplx.Abs(Response(b, a, f)))
+				}
+				for _, f := range test.cutoff {
+					if got := gain(f); math.Abs(got-design.edge) > 1e-6 {
+						t.Errorf("unexpected gain at cutoff %v for %s: got:%v dB want:%v dB", f, name, got, design.edge)
+					}
+				}
+
+				// Check the response across the bands.
+				const samples = 1000
+				freq := make([]float64, samples+1)
+				gains := make([]float64, samples+1)
+				for i := range freq {
+					freq[i] = 0.5 * float64(i) / samples
+					gains[i] = gain(freq[i])
+				}
+				passMin, passMax := math.Inf(1), math.Inf(-1)
+				for i, f := range freq {
+					if inPassBand(test.band, test.cutoff, f) {
+						passMin = math.Min(passMin, gains[i])
+						passMax = math.Max(passMax, gains[i])
+					}
+				}
+				const tol = 1e-6
+				if passMax > tol {
+					t.Errorf("pass band gain above unity for %s: %v dB", name, passMax)
+				}
+				if design.ripple != 0 && passMin < -design.ripple-tol {
+					t.Errorf("pass band ripple too large for %s: %v dB", name, passMin)
+				}
+				if design.attenuation != 0 {
+					stopMax := stopBandMax(test.band, test.cutoff, freq, gains, -design.attenuation+tol)
+					if stopMax > -design.attenuation+tol {
+						t.Errorf("stop band attenuation too small for %s: %v dB", name, stopMax)
+					}
+				}
+
+				// The filter must be stable, so its impulse
+				// response decays.
+				x := make([]float64, 20000)
+				x[0] = 1
+				y := Filter(nil, b, a, x, nil)
+				if tail := math.Abs(y[len(y)-1]); tail > 1e-8 {
+					t.Errorf("impulse response does not decay for %s: %v", name, tail)
+				}
+			}
+		}
+	}
+}
+
+// stopBandMax returns the maximum gain in the stop band of a filter with the
+// given type and cutoffs sampled at the frequencies in freq. The stop band
+// starts where the gain first falls to the threshold on moving away from a
+// cutoff.
+func stopBandMax(band BandType, cutoff, freq, gains []float64, threshold float64) float64 {
+	max := math.Inf(-1)
+	for lo := 0; lo < len(freq); {
+		if inPassBand(band, cutoff, freq[lo]) {
+			lo++
+			continue
+		}
+		hi := lo
+		for hi < len(freq) && !inPassBand(band, cutoff, freq[hi]) {
+			hi++
+		}
+
+		// The segment [lo, hi) is bounded by a cutoff on
+		// each side that is not zero or the Nyquist frequency.
+		fromLeft := make([]bool, hi-lo)
+		reached := lo == 0
+		for i := lo; i < hi; i++ {
+			reached = reached || gains[i] <= threshold
+			fromLeft[i-lo] = reached
+		}
+		reached = hi == len(freq)
+		for i := hi - 1; i >= lo; i-- {
+			reached = reached || gains[i] <= threshold
+			if reached && fromLeft[i-lo] {
+				max = math.Max(max, gains[i])
+			}
+		}
+		lo = hi
+	}
+	return max
+}
+
+// inPassBand returns whether f is strictly inside the band passed by
+// a filter with the given type and cutoffs.
+func inPassBand(band BandType, cutoff []float64, f float64) bool {
+	switch band {
+	case Lowpass:
+		return f < cutoff[0]
+	case Highpass:
+		return f > cutoff[0]
+	case Bandpass:
+		return cutoff[0] < f && f < cutoff[1]
+	default:
+		return f < cutoff[0] || cutoff[1] < f
+	}
+}
+
+func TestEllipticFunctions(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-12
+	for _, m := range []float64{0, 1e-12, 0.1, 0.5, 0.9, 0.999, 1 - 1e-12} {
+		for _, u := range []float64{-1.3, 0, 0.2, 0.7, 1.5, 3} {
+			sn, cn, dn := jacobi(u, m)
+			if math.Abs(sn*sn+cn*cn-1) > tol {
+				t.Errorf("sn²+cn² != 1 for u=%v m=%v: %v", u, m, sn*sn+cn*cn)
+			}
+			if math.Abs(dn*dn+m*sn*sn-1) > tol {
+				t.Errorf("dn²+m*sn² != 1 for u=%v m=%v: %v", u, m, dn*dn+m*sn*sn)
+			}
+		}
+
+		// sn(K(m), m) = 1.
+		if m < 1-1e-6 {
+			if sn, _, _ := jacobi(ellipticK(m), m); math.Abs(sn-1) > 1e-10 {
+				t.Errorf("sn(K) != 1 for m=%v: %v", m, sn)
+			}
+		}
+	}
+
+	// Known values.
+	if got, want := ellipticK(0.5), 1.8540746773013719; math.Abs(got-want) > tol {
+		t.Errorf("unexpected K(0.5): got:%v want:%v", got, want)
+	}
+	if got, want := ellipticK(0), math.Pi/2; math.Abs(got-want) > tol {
+		t.Errorf("unexpected K(0): got:%v want:%v", got, want)
+	}
+
+	// The inverse of sc(z, 1-m).
+	for _, m := range []float64{0.01, 0.3, 0.8} {
+		for _, z := range []float64{0.1, 0.5, 1} {
+			sn, cn, _ := jacobi(z, 1-m)
+			got := arcJacobiSC1(sn/cn, m)
+			if math.Abs(got-z) > 1e-10 {
+				t.Errorf("unexpected inverse sc for z=%v m=%v: got:%v", z, m, got)
+			}
+		}
+	}
+}
+
+func TestIIRPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero order", fn: func() { Butterworth(0, Lowpass, 0.1) }},
+		{name: "bad cutoff", fn: func() { Butterworth(2, Lowpass, 0.6) }},
+		{name: "missing cutoff", fn: func() { Butterworth(2, Bandpass, 0.1) }},
+		{name: "zero ripple", fn: func() { Chebyshev1(2, 0, Lowpass, 0.1) }},
+		{name: "zero attenuation", fn: func() { Chebyshev2(2, 0, Lowpass, 0.1) }},
+		{name: "attenuation below ripple", fn: func() { Elliptic(2, 3, 2, Lowpass, 0.1) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoConvergence is returned by Remez when the exchange iterations do not
+// converge.
+var ErrNoConvergence = errors.New("filter: remez exchange did not converge")
+
+const (
+	// remezGridDensity is the number of grid points
+	// per extremal frequency of the approximation.
+	remezGridDensity = 16
+
+	// remezMaxIterations is the maximum number of
+	// exchange iterations.
+	remezMaxIterations = 40
+)
+
+// Remez returns the n coefficients of the linear phase FIR filter that
+// minimizes the maximum weighted error between its amplitude response and
+// a piecewise constant desired response, using the Parks-McClellan
+// algorithm. The bands are given as pairs of relative frequencies in
+// [0, 0.5] in increasing order, and the desired amplitude and error weight
+// in each band are given by desired and weight. If weight is nil, all bands
+// are weighted equally. The frequencies between bands are transition bands
+// where the response is not constrained.
+//
+// Filters with an even number of coefficients have a zero response at the
+// Nyquist frequency, so their final band should not require a non-zero
+// response there.
+//
+// Remez returns ErrNoConvergence if the exchange algorithm fails to
+// converge; the returned coefficients are then the last approximation. Remez
+// will panic if n is less than 3, if bands are not valid or if the lengths
+// of desired and weight do not match the number of bands.
+func Remez(n int, bands, desired, weight []float64) ([]float64, error) {
+	if n < 3 {
+		panic("filter: remez filter length too short")
+	}
+	if len(bands) == 0 || len(bands)%2 != 0 {
+		panic("filter: bands must be given as pairs of frequencies")
+	}
+	for i, f := range bands {
+		if f < 0 || 0.5 < f || (i > 0 && f < bands[i-1]) {
+			panic("filter: invalid band frequencies")
+		}
+	}
+	nbands := len(bands) / 2
+	if len(desired) != nbands {
+		panic("filter: desired response length mismatch")
+	}
+	if weight == nil {
+		weight = make([]float64, nbands)
+		for i := range weight {
+			weight[i] = 1
+		}
+	} else if len(weight) != nbands {
+		panic("filter: weight length mismatch")
+	}
+
+	r := n / 2
+	if n%2 != 0 {
+		r++
+	}
+
+	// Construct the dense grid over the bands.
+	delf := 0.5 / (remezGridDensity * float64(r))
+	var grid, des, wt []float64
+	for b := 0; b < nbands; b++ {
+		lo, hi := bands[2*b], bands[2*b+1]
+		k := int(math.Round((hi - lo) / delf))
+		if k < 1 {
+			k = 1
+		}
+		for i := 0; i < k; i++ {
+			grid = append(grid, lo+float64(i)*delf)
+			des = append(des, desired[b])
+			wt = append(wt, weight[b])
+		}
+		grid[len(grid)-1] = hi
+	}
+	even := n%2 == 0
+	if even && grid[len(grid)-1] > 0.5-delf {
+		grid[len(grid)-1] = 0.5 - delf
+	}
+	if len(grid) < r+1 {
+		panic("filter: bands too narrow for filter length")
+	}
+
+	// Even length filters have an amplitude response
+	// of the form cos(πf) times a cosine polynomial.
+	if even {
+		for i, f := range grid {
+			c := math.Cos(math.Pi * f)
+			des[i] /= c
+			wt[i] *= c
+		}
+	}
+
+	ext := make([]int, r+1)
+	for i := range ext {
+		ext[i] = i * (len(grid) - 1) / r
+	}
+	p := remezApprox{
+		ad: make([]float64, r+1),
+		x:  make([]float64, r+1),
+		y:  make([]float64, r+1),
+	}
+	e := make([]float64, len(grid))
+	var err error = ErrNoConvergence
+	for iter := 0; iter < remezMaxIterations; iter++ {
+		p.fit(ext, grid, des, wt)
+		for i, f := range grid {
+			e[i] = wt[i] * (des[i] - p.at(f))
+		}
+		var ok bool
+		ext, ok = remezSearch(ext, e)
+		if !ok {
+			break
+		}
+		if remezDone(ext, e) {
+			err = nil
+			break
+		}
+	}
+	p.fit(ext, grid, des, wt)
+
+	// Sample the amplitude response and compute
+	// the coefficients by frequency sampling.
+	amp := make([]float64, n/2+1)
+	for i := range amp {
+		f := float64(i) / float64(n)
+		amp[i] = p.at(f)
+		if even {
+			amp[i] *= math.Cos(math.Pi * f)
+		}
+	}
+	h := make([]float64, n)
+	mid := float64(n-1) / 2
+	m := (n - 1) / 2
+	if even {
+		m = n/2 - 1
+	}
+	for i := range h {
+		x := 2 * math.Pi * (float64(i) - mid) / float64(n)
+		v := amp[0]
+		for k := 1; k <= m; k++ {
+			v += 2 * amp[k] * math.Cos(x*float64(k))
+		}
+		h[i] = v / float64(n)
+	}
+	return h, err
+}
+
+// remezApprox holds the barycentric Lagrange interpolation of the current
+// approximation at the extremal frequencies.
+type remezApprox struct {
+	ad, x, y []float64
+}
+
+// fit computes the interpolation for the extremal grid indices in ext.
+func (p *remezApprox) fit(ext []int, grid, des, wt []float64) {
+	r := len(ext) - 1
+	for i, k := range ext {
+		p.x[i] = math.Cos(2 * math.Pi * grid[k])
+	}
+
+	// Compute the barycentric weights, interleaving
+	// the products to avoid overflow and underflow.
+	ld := (r-1)/15 + 1
+	for i := range p.ad {
+		denom := 1.0
+		xi := p.x[i]
+		for j := 0; j < ld; j++ {
+			for k := j; k <= r; k += ld {
+				if k != i {
+					denom *= 2 * (xi - p.x[k])
+				}
+			}
+		}
+		if math.Abs(denom) < 1e-5 {
+			denom = 1e-5
+		}
+		p.ad[i] = 1 / denom
+	}
+
+	var numer, denom float64
+	sign := 1.0
+	for i, k := range ext {
+		numer += p.ad[i] * des[k]
+		denom += sign * p.ad[i] / wt[k]
+		sign = -sign
+	}
+	delta := numer / denom
+	sign = 1
+	for i, k := range ext {
+		p.y[i] = des[k] - sign*delta/wt[k]
+		sign = -sign
+	}
+}
+
+// at returns the value of the approximation at the relative frequency f.
+func (p *remezApprox) at(f float64) float64 {
+	xc := math.Cos(2 * math.Pi * f)
+	var numer, denom float64
+	for i, x := range p.x {
+		c := xc - x
+		if math.Abs(c) < 1e-7 {
+			return p.y[i]
+		}
+		c = p.ad[i] / c
+		denom += c
+		numer += c * p.y[i]
+	}
+	return numer / denom
+}
+
+// remezSearch returns the grid indices of the len(ext) alternating
+// extrema of the error e with the largest magnitudes, reusing ext. It
+// returns false if there are too few extrema.
+func remezSearch(ext []int, e []float64) ([]int, bool) {
+	var found []int
+	last := len(e) - 1
+	if (e[0] > 0 && e[0] > e[1]) || (e[0] < 0 && e[0] < e[1]) {
+		found = append(found, 0)
+	}
+	for i := 1; i < last; i++ {
+		if (e[i] >= e[i-1] && e[i] > e[i+1] && e[i] > 0) || (e[i] <= e[i-1] && e[i] < e[i+1] && e[i] < 0) {
+			found = append(found, i)
+		}
+	}
+	if (e[last] > 0 && e[last] > e[last-1]) || (e[last] < 0 && e[last] < e[last-1]) {
+		found = append(found, last)
+	}
+
+	// Keep the larger of adjacent extrema with the same sign.
+	alt := found[:0]
+	for _, k := range found {
+		if len(alt) != 0 {
+			prev := alt[len(alt)-1]
+			if (e[k] > 0) == (e[prev] > 0) {
+				if math.Abs(e[k]) > math.Abs(e[prev]) {
+					alt[len(alt)-1] = k
+				}
+				continue
+			}
+		}
+		alt = append(alt, k)
+	}
+
+	// Remove the smaller end extremum until there are
+	// the required number.
+	for len(alt) > len(ext) {
+		if math.Abs(e[alt[0]]) < math.Abs(e[alt[len(alt)-1]]) {
+			alt = alt[1:]
+		} else {
+			alt = alt[:len(alt)-1]
+		}
+	}
+	if len(alt) < len(ext) {
+		return ext, false
+	}
+	copy(ext, alt)
+	return ext, true
+}
+
+// remezDone returns whether the magnitudes of the error at the extremal
+// frequencies are equal to within a small relative tolerance.
+func remezDone(ext []int, e []float64) bool {
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, k := range ext {
+		v := math.Abs(e[k])
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return (max-min)/max < 1e-4
+}
@@ -0,0 +1,111 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestRemez(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name    string
+		n       int
+		bands   []float64
+		desired []float64
+		weight  []float64
+	}{
+		{name: "lowpass", n: 31, bands: []float64{0, 0.1, 0.15, 0.5}, desired: []float64{1, 0}},
+		{name: "lowpass even", n: 32, bands: []float64{0, 0.1, 0.15, 0.5}, desired: []float64{1, 0}},
+		{name: "weighted lowpass", n: 45, bands: []float64{0, 0.2, 0.25, 0.5}, desired: []float64{1, 0}, weight: []float64{1, 10}},
+		{name: "highpass", n: 41, bands: []float64{0, 0.2, 0.25, 0.5}, desired: []float64{0, 1}},
+		{
+			name: "bandpass", n: 63,
+			bands: []float64{0, 0.1, 0.15, 0.3, 0.35, 0.5}, desired: []float64{0, 1, 0}, weight: []float64{2, 1, 2},
+		},
+		{
+			name: "multiband", n: 45,
+			bands: []float64{0, 0.05, 0.1, 0.2, 0.25, 0.35, 0.4, 0.5}, desired: []float64{1, 0, 0.5, 0},
+		},
+	} {
+		h, err := Remez(test.n, test.bands, test.desired, test.weight)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		if len(h) != test.n {
+			t.Errorf("unexpected length for %s: got:%d want:%d", test.name, len(h), test.n)
+			continue
+		}
+		for i := range h {
+			if math.Abs(h[i]-h[len(h)-1-i]) > 1e-12 {
+				t.Errorf("coefficients not symmetric for %s", test.name)
+				break
+			}
+		}
+
+		// The weighted error of an optimal filter has the
+		// same maximum magnitude in each band, and is small.
+		weight := test.weight
+		if weight == nil {
+			weight = make([]float64, len(test.desired))
+			for i := range weight {
+				weight[i] = 1
+			}
+		}
+		var errs []float64
+		for b := range test.desired {
+			lo, hi := test.bands[2*b], test.bands[2*b+1]
+			if test.n%2 == 0 && hi == 0.5 {
+				// The response of even length
+				// filters is zero at Nyquist.
+				hi -= 0.01
+			}
+			var max float64
+			const samples = 500
+			for i := 0; i <= samples; i++ {
+				f := lo + (hi-lo)*float64(i)/samples
+				e := weight[b] * math.Abs(cmplx.Abs(Response(h, nil, f))-test.desired[b])
+				max = math.Max(max, e)
+			}
+			errs = append(errs, max)
+		}
+		for _, e := range errs[1:] {
+			if math.Abs(e-errs[0]) > 0.05*errs[0] {
+				t.Errorf("weighted errors not equiripple for %s: %v", test.name, errs)
+				break
+			}
+		}
+		if errs[0] > 0.1 {
+			t.Errorf("unexpectedly large error for %s: %v", test.name, errs[0])
+		}
+	}
+}
+
+func TestRemezPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "short", fn: func() { Remez(2, []float64{0, 0.1, 0.2, 0.5}, []float64{1, 0}, nil) }},
+		{name: "odd bands", fn: func() { Remez(11, []float64{0, 0.1, 0.2}, []float64{1, 0}, nil) }},
+		{name: "decreasing bands", fn: func() { Remez(11, []float64{0, 0.2, 0.1, 0.5}, []float64{1, 0}, nil) }},
+		{name: "out of range", fn: func() { Remez(11, []float64{0, 0.1, 0.2, 0.6}, []float64{1, 0}, nil) }},
+		{name: "desired", fn: func() { Remez(11, []float64{0, 0.1, 0.2, 0.5}, []float64{1}, nil) }},
+		{name: "weight", fn: func() { Remez(11, []float64{0, 0.1, 0.2, 0.5}, []float64{1, 0}, []float64{1}) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConvolutionMode specifies the part of a convolution or correlation that
+// is returned.
+type ConvolutionMode int
+
+const (
+	// FullConvolution returns the complete result, with
+	// length len(a)+len(b)-1 along each dimension.
+	FullConvolution ConvolutionMode = iota
+
+	// SameConvolution returns the central part of the
+	// complete result with the same length as a along
+	// each dimension.
+	SameConvolution
+
+	// ValidConvolution returns the part of the complete
+	// result that does not depend on zero padding, with
+	// length len(a)-len(b)+1 along each dimension.
+	ValidConvolution
+)
+
+// Convolve returns the linear convolution of the sequences a and b,
+//  dst[k] = Σ_i a[i] * b[k-i],
+// restricted according to mode, placing the result in dst and returning it.
+// The convolution is computed directly or using the FFT, whichever is
+// estimated to be faster.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// its length does not match the length of the result, Convolve will panic.
+// Convolve will panic if a or b is empty, or if mode is ValidConvolution and
+// a is shorter than b.
+func Convolve(dst, a, b []float64, mode ConvolutionMode) []float64 {
+	dst, _, _ = convolve2D(dst, a, 1, len(a), b, 1, len(b), mode, false)
+	return dst
+}
+
+// Correlate returns the cross-correlation of the sequences a and b,
+//  dst[k] = Σ_i a[i+k] * b[i],
+// for lags k from -(len(b)-1) to len(a)-1, restricted according to mode,
+// placing the result in dst and returning it. This is the convolution of a
+// with the reverse of b. The correlation is computed directly or using the
+// FFT, whichever is estimated to be faster.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// its length does not match the length of the result, Correlate will panic.
+// Correlate will panic if a or b is empty, or if mode is ValidConvolution and
+// a is shorter than b.
+func Correlate(dst, a, b []float64, mode ConvolutionMode) []float64 {
+	dst, _, _ = convolve2D(dst, a, 1, len(a), b, 1, len(b), mode, true)
+	return dst
+}
+
+// Convolve2D returns the two-dimensional linear convolution of the matrices
+// a and b,
+//  dst[i, j] = Σ_{k,l} a[i-k, j-l] * b[k, l],
+// restricted according to mode, placing the result in dst and returning it.
+// The convolution is computed directly or using the FFT, whichever is
+// estimated to be faster.
+//
+// If dst is nil, a new matrix is allocated and returned. If dst is empty, it
+// is resized to the dimensions of the result. Otherwise Convolve2D will panic
+// if the dimensions of dst do not match the result. Convolve2D will panic if
+// mode is ValidConvolution and a is smaller than b in either dimension.
+func Convolve2D(dst *mat.Dense, a, b mat.Matrix, mode ConvolutionMode) *mat.Dense {
+	return convolveMatrix(dst, a, b, mode, false)
+}
+
+// Correlate2D returns the two-dimensional cross-correlation of the matrices
+// a and b, restricted according to mode, placing the result in dst and
+// returning it. This is the convolution of a with b reversed along both
+// dimensions. The correlation is computed directly or using the FFT,
+// whichever is estimated to be faster.
+//
+// If dst is nil, a new matrix is allocated and returned. If dst is empty, it
+// is resized to the dimensions of the result. Otherwise Correlate2D will
+// panic if the dimensions of dst do not match the result. Correlate2D will
+// panic if mode is ValidConvolution and a is smaller than b in either
+// dimension.
+func Correlate2D(dst *mat.Dense, a, b mat.Matrix, mode ConvolutionMode) *mat.Dense {
+	return convolveMatrix(dst, a, b, mode, true)
+}
+
+func convolveMatrix(dst *mat.Dense, a, b mat.Matrix, mode ConvolutionMode, correlate bool) *mat.Dense {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	_, r := convWindow(ar, br, mode)
+	_, c := convWindow(ac, bc, mode)
+	if dst == nil {
+		dst = mat.NewDense(r, c, nil)
+	} else if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic("fourier: destination dimension mismatch")
+	}
+	res, _, _ := convolve2D(nil, matrixData(a), ar, ac, matrixData(b), br, bc, mode, correlate)
+	for i := 0; i < r; i++ {
+		copy(dst.RawRowView(i), res[i*c:(i+1)*c])
+	}
+	return dst
+}
+
+// convWindow returns the offset into the full convolution and the length of
+// the part returned for a dimension of lengths na and nb.
+func convWindow(na, nb int, mode ConvolutionMode) (off, n int) {
+	switch mode {
+	case FullConvolution:
+		return 0, na + nb - 1
+	case SameConvolution:
+		return (nb - 1) / 2, na
+	case ValidConvolution:
+		if na < nb {
+			panic("fourier: valid convolution with larger kernel")
+		}
+		return nb - 1, na - nb + 1
+	default:
+		panic("fourier: unknown convolution mode")
+	}
+}
+
+func convolve2D(dst, a []float64, ar, ac int, b []float64, br, bc int, mode ConvolutionMode, correlate bool) (res []float64, r, c int) {
+	if ar <= 0 || ac <= 0 || br <= 0 || bc <= 0 {
+		panic("fourier: empty input")
+	}
+	if len(a) != ar*ac || len(b) != br*bc {
+		panic("fourier: input length mismatch")
+	}
+	roff, r := convWindow(ar, br, mode)
+	coff, c := convWindow(ac, bc, mode)
+	if dst == nil {
+		dst = make([]float64, r*c)
+	} else if len(dst) != r*c {
+		panic("fourier: destination length mismatch")
+	}
+
+	if correlate {
+		rev := make([]float64, len(b))
+		for i, v := range b {
+			rev[len(b)-1-i] = v
+		}
+		b = rev
+	}
+
+	fr, fc := ar+br-1, ac+bc-1
+	if useFFT(r*c, br*bc, fr, fc) {
+		convolveFFT(dst, a, ar, ac, b, br, bc, roff, coff, r, c)
+	} else {
+		convolveDirect(dst, a, ar, ac, b, br, bc, roff, coff, r, c)
+	}
+	return dst, r, c
+}
+
+// useFFT returns whether an FFT convolution of size fr×fc is estimated to
+// be faster than direct computation of n outputs with a kernel of size k.
+func useFFT(n, k, fr, fc int) bool {
+	// The constants are rough estimates of the relative
+	// cost of the FFT including its setup.
+	const (
+		fftCost   = 6
+		fftMinLen = 64
+	)
+	direct := float64(n) * float64(k)
+	size := float64(nextFastLen(fr) * nextFastLen(fc))
+	if size < fftMinLen {
+		return false
+	}
+	return direct > fftCost*size*math.Log2(size)
+}
+
+// convolveDirect computes the r×c part of the full convolution of a and b
+// starting at row roff and column coff directly, placing it in dst.
+func convolveDirect(dst, a []float64, ar, ac int, b []float64, br, bc int, roff, coff, r, c int) {
+	for i := 0; i < r; i++ {
+		fi := i + roff
+		// Rows of b that overlap a for output row fi.
+		klo, khi := max(0, fi-ar+1), min(br-1, fi)
+		for j := 0; j < c; j++ {
+			fj := j + coff
+			llo, lhi := max(0, fj-ac+1), min(bc-1, fj)
+			var sum float64
+			for k := klo; k <= khi; k++ {
+				arow := a[(fi-k)*ac:]
+				brow := b[k*bc:]
+				for l := llo; l <= lhi; l++ {
+					sum += arow[fj-l] * brow[l]
+				}
+			}
+			dst[i*c+j] = sum
+		}
+	}
+}
+
+// convolveFFT computes the r×c part of the full convolution of a and b
+// starting at row roff and column coff using the FFT, placing it in dst.
+func convolveFFT(dst, a []float64, ar, ac int, b []float64, br, bc int, roff, coff, r, c int) {
+	nr := nextFastLen(ar + br - 1)
+	nc := nextFastLen(ac + bc - 1)
+	fft := NewFFTN(nr, nc)
+
+	pad := make([]float64, nr*nc)
+	for i := 0; i < ar; i++ {
+		copy(pad[i*nc:], a[i*ac:(i+1)*ac])
+	}
+	ca := fft.Coefficients(nil, pad)
+	for i := range pad {
+		pad[i] = 0
+	}
+	for i := 0; i < br; i++ {
+		copy(pad[i*nc:], b[i*bc:(i+1)*bc])
+	}
+	cb := fft.Coefficients(nil, pad)
+	for i, v := range cb {
+		ca[i] *= v
+	}
+	fft.Sequence(pad, ca)
+
+	scale := 1 / float64(nr*nc)
+	for i := 0; i < r; i++ {
+		row := pad[(i+roff)*nc+coff:]
+		for j := 0; j < c; j++ {
+			dst[i*c+j] = row[j] * scale
+		}
+	}
+}
+
+// nextFastLen returns the smallest integer not less than n with no prime
+// factors other than 2, 3 and 5.
+func nextFastLen(n int) int {
+	if n <= 6 {
+		return n
+	}
+	for m := n; ; m++ {
+		k := m
+		for _, p := range []int{2, 3, 5} {
+			for k%p == 0 {
+				k /= p
+			}
+		}
+		if k == 1 {
+			return m
+		}
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,201 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConvolve(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	for _, test := range []struct{ na, nb int }{
+		{1, 1}, {5, 1}, {1, 5}, {5, 3}, {3, 5}, {10, 4}, {64, 7}, {200, 150}, {1000, 300},
+	} {
+		a := randFloats(test.na, src)
+		b := randFloats(test.nb, src)
+		full := naiveConvolve(a, b)
+		for _, mode := range []ConvolutionMode{FullConvolution, SameConvolution, ValidConvolution} {
+			if mode == ValidConvolution && test.na < test.nb {
+				continue
+			}
+			off, n := convWindow(test.na, test.nb, mode)
+			want := full[off : off+n]
+			got := Convolve(nil, a, b, mode)
+			if !floats.EqualApprox(got, want, tol) {
+				t.Errorf("unexpected convolution for na=%d nb=%d mode=%d:\ngot: %v\nwant:%v", test.na, test.nb, mode, got, want)
+			}
+
+			rb := make([]float64, len(b))
+			for i, v := range b {
+				rb[len(b)-1-i] = v
+			}
+			want = naiveConvolve(a, rb)[off : off+n]
+			got = Correlate(make([]float64, n), a, b, mode)
+			if !floats.EqualApprox(got, want, tol) {
+				t.Errorf("unexpected correlation for na=%d nb=%d mode=%d:\ngot: %v\nwant:%v", test.na, test.nb, mode, got, want)
+			}
+		}
+	}
+}
+
+func TestCorrelateLags(t *testing.T) {
+	t.Parallel()
+	a := []float64{1, 2, 3, 4}
+	b := []float64{1, 0, -1}
+
+	// dst[k] = Σ_i a[i+k] * b[i] for k = -2, ..., 3.
+	want := []float64{-1, -2, -2, -2, 3, 4}
+	got := Correlate(nil, a, b, FullConvolution)
+	if !floats.Equal(got, want) {
+		t.Errorf("unexpected correlation: got:%v want:%v", got, want)
+	}
+}
+
+func TestConvolve2D(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	for _, test := range []struct{ ar, ac, br, bc int }{
+		{1, 1, 1, 1}, {4, 5, 2, 3}, {3, 7, 3, 1}, {6, 6, 5, 5}, {40, 30, 9, 11}, {64, 64, 16, 16},
+	} {
+		a := randFloats(test.ar*test.ac, src)
+		b := randFloats(test.br*test.bc, src)
+		full := naiveConvolve2D(a, test.ar, test.ac, b, test.br, test.bc)
+		fc := test.ac + test.bc - 1
+		for _, mode := range []ConvolutionMode{FullConvolution, SameConvolution, ValidConvolution} {
+			roff, r := convWindow(test.ar, test.br, mode)
+			coff, c := convWindow(test.ac, test.bc, mode)
+			want := make([]float64, 0, r*c)
+			for i := 0; i < r; i++ {
+				want = append(want, full[(i+roff)*fc+coff:(i+roff)*fc+coff+c]...)
+			}
+			am := mat.NewDense(test.ar, test.ac, a)
+			bm := mat.NewDense(test.br, test.bc, b)
+			got := Convolve2D(nil, am, bm, mode)
+			if gr, gc := got.Dims(); gr != r || gc != c {
+				t.Errorf("unexpected dimensions for %v mode=%d: got:%d×%d want:%d×%d", test, mode, gr, gc, r, c)
+				continue
+			}
+			if !mat.EqualApprox(got, mat.NewDense(r, c, want), tol) {
+				t.Errorf("unexpected convolution for %v mode=%d:\ngot: %v\nwant:%v", test, mode, got.RawMatrix().Data, want)
+			}
+
+			rb := make([]float64, len(b))
+			for i, v := range b {
+				rb[len(b)-1-i] = v
+			}
+			var wantCorr, gotCorr mat.Dense
+			Convolve2D(&wantCorr, am, mat.NewDense(test.br, test.bc, rb), mode)
+			Correlate2D(&gotCorr, am, bm, mode)
+			if !mat.EqualApprox(&gotCorr, &wantCorr, tol) {
+				t.Errorf("unexpected correlation for %v mode=%d", test, mode)
+			}
+		}
+	}
+}
+
+func TestConvolveMethods(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	for _, test := range []struct{ ar, ac, br, bc int }{
+		{1, 50, 1, 20}, {7, 9, 3, 4}, {12, 5, 12, 5},
+	} {
+		a := randFloats(test.ar*test.ac, src)
+		b := randFloats(test.br*test.bc, src)
+		for _, mode := range []ConvolutionMode{FullConvolution, SameConvolution, ValidConvolution} {
+			roff, r := convWindow(test.ar, test.br, mode)
+			coff, c := convWindow(test.ac, test.bc, mode)
+			direct := make([]float64, r*c)
+			convolveDirect(direct, a, test.ar, test.ac, b, test.br, test.bc, roff, coff, r, c)
+			fft := make([]float64, r*c)
+			convolveFFT(fft, a, test.ar, test.ac, b, test.br, test.bc, roff, coff, r, c)
+			if !floats.EqualApprox(direct, fft, tol) {
+				t.Errorf("direct and FFT results differ for %v mode=%d:\ndirect:%v\nfft:   %v", test, mode, direct, fft)
+			}
+		}
+	}
+}
+
+func TestNextFastLen(t *testing.T) {
+	t.Parallel()
+	for n, want := range map[int]int{1: 1, 5: 5, 7: 8, 11: 12, 13: 15, 17: 18, 31: 32, 97: 100, 121: 125} {
+		if got := nextFastLen(n); got != want {
+			t.Errorf("unexpected fast length for %d: got:%d want:%d", n, got, want)
+		}
+	}
+}
+
+func TestConvolvePanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "empty", fn: func() { Convolve(nil, nil, []float64{1}, FullConvolution) }},
+		{name: "valid", fn: func() { Convolve(nil, []float64{1, 2}, []float64{1, 2, 3}, ValidConvolution) }},
+		{name: "dst", fn: func() { Convolve(make([]float64, 4), []float64{1, 2}, []float64{1, 2}, FullConvolution) }},
+		{name: "mode", fn: func() { Convolve(nil, []float64{1, 2}, []float64{1, 2}, -1) }},
+		{name: "dst 2D", fn: func() {
+			Convolve2D(mat.NewDense(2, 2, nil), mat.NewDense(2, 3, nil), mat.NewDense(1, 1, nil), FullConvolution)
+		}},
+		{name: "valid 2D", fn: func() { Convolve2D(nil, mat.NewDense(2, 3, nil), mat.NewDense(3, 1, nil), ValidConvolution) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+func naiveConvolve(a, b []float64) []float64 {
+	dst := make([]float64, len(a)+len(b)-1)
+	for i, u := range a {
+		for j, v := range b {
+			dst[i+j] += u * v
+		}
+	}
+	return dst
+}
+
+func naiveConvolve2D(a []float64, ar, ac int, b []float64, br, bc int) []float64 {
+	fc := ac + bc - 1
+	dst := make([]float64, (ar+br-1)*fc)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			for k := 0; k < br; k++ {
+				for l := 0; l < bc; l++ {
+					dst[(i+k)*fc+j+l] += a[i*ac+j] * b[k*bc+l]
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func BenchmarkConvolve(b *testing.B) {
+	for _, test := range []struct{ na, nb int }{{1000, 10}, {1000, 100}, {10000, 1000}, {100000, 10000}} {
+		x := randFloats(test.na, rand.NewSource(1))
+		y := randFloats(test.nb, rand.NewSource(2))
+		dst := make([]float64, test.na+test.nb-1)
+		b.Run(fmt.Sprintf("%d×%d", test.na, test.nb), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Convolve(dst, x, y, FullConvolution)
+			}
+		})
+	}
+}
@@ -0,0 +1,454 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import "gonum.org/v1/gonum/mat"
+
+// FFTN implements the multi-dimensional Fast Fourier Transform and its
+// inverse for real arrays. Arrays are held in row-major order, so the last
+// dimension varies fastest. Two-dimensional arrays may also be transformed
+// as matrices using MatrixCoefficients and MatrixSequence.
+//
+// The Fourier coefficients of a real array with dimensions d₀, d₁, …, dₖ
+// are held in a complex array with dimensions d₀, d₁, …, dₖ/2+1, the
+// remaining coefficients being given by conjugate symmetry.
+type FFTN struct {
+	dims  []int
+	cdims []int
+
+	// last transforms along the last dimension and
+	// cmplx holds the transforms for the remaining
+	// dimensions. Dimensions of equal length share
+	// the same transform.
+	last  *FFT
+	cmplx []*CmplxFFT
+
+	work []complex128
+	line []complex128
+}
+
+// NewFFTN returns an FFTN initialized for work on arrays with the given
+// dimensions. NewFFTN will panic if no dimensions are given or if any
+// dimension is not positive.
+func NewFFTN(dims ...int) *FFTN {
+	var t FFTN
+	t.Reset(dims...)
+	return &t
+}
+
+// Dims returns the dimensions of the acceptable input.
+func (t *FFTN) Dims() []int { return append([]int(nil), t.dims...) }
+
+// CoefficientDims returns the dimensions of the Fourier coefficients.
+func (t *FFTN) CoefficientDims() []int { return append([]int(nil), t.cdims...) }
+
+// Len returns the number of elements of the acceptable input.
+func (t *FFTN) Len() int { return product(t.dims) }
+
+// Reset reinitializes the FFTN for work on arrays with the given dimensions.
+// Reset will panic if no dimensions are given or if any dimension is not
+// positive.
+func (t *FFTN) Reset(dims ...int) {
+	checkDims(dims)
+	t.dims = append(t.dims[:0], dims...)
+	t.cdims = append(t.cdims[:0], dims...)
+	k := len(dims) - 1
+	t.cdims[k] = dims[k]/2 + 1
+
+	if t.last == nil {
+		t.last = NewFFT(dims[k])
+	} else if t.last.Len() != dims[k] {
+		t.last.Reset(dims[k])
+	}
+	t.cmplx = plans(t.cmplx, dims[:k])
+
+	n := product(t.cdims)
+	if n <= cap(t.work) {
+		t.work = t.work[:n]
+	} else {
+		t.work = make([]complex128, n)
+	}
+	t.line = reuseLine(t.line, dims[:k])
+}
+
+// Coefficients computes the Fourier coefficients of the real array seq,
+// converting the spatial or time domain data into the frequency domain,
+// placing the result in dst and returning it. This transform is
+// unnormalized; a call to Coefficients followed by a call of Sequence will
+// multiply the input array by t.Len().
+//
+// If the length of seq is not t.Len(), Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the product of t.CoefficientDims(),
+// Coefficients will panic.
+func (t *FFTN) Coefficients(dst []complex128, seq []float64) []complex128 {
+	if len(seq) != t.Len() {
+		panic("fourier: sequence length mismatch")
+	}
+	n := product(t.cdims)
+	if dst == nil {
+		dst = make([]complex128, n)
+	} else if len(dst) != n {
+		panic("fourier: destination length mismatch")
+	}
+	k := len(t.dims) - 1
+	m, h := t.dims[k], t.cdims[k]
+	for r := 0; r < len(seq)/m; r++ {
+		t.last.Coefficients(dst[r*h:(r+1)*h], seq[r*m:(r+1)*m])
+	}
+	for axis := 0; axis < k; axis++ {
+		transformAxis(dst, t.cdims, axis, t.line, t.cmplx[axis].Coefficients)
+	}
+	return dst
+}
+
+// Sequence computes the real array from its Fourier coefficients,
+// converting the frequency domain data into the spatial or time domain,
+// placing the result in dst and returning it. This transform is
+// unnormalized; a call to Coefficients followed by a call of Sequence will
+// multiply the input array by t.Len(). The coefficients in coeff are not
+// modified.
+//
+// If the length of coeff is not the product of t.CoefficientDims(),
+// Sequence will panic. If dst is nil, a new slice is allocated and
+// returned. If dst is not nil and the length of dst does not equal
+// t.Len(), Sequence will panic.
+func (t *FFTN) Sequence(dst []float64, coeff []complex128) []float64 {
+	if len(coeff) != product(t.cdims) {
+		panic("fourier: coefficients length mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, t.Len())
+	} else if len(dst) != t.Len() {
+		panic("fourier: destination length mismatch")
+	}
+	copy(t.work, coeff)
+	k := len(t.dims) - 1
+	for axis := 0; axis < k; axis++ {
+		transformAxis(t.work, t.cdims, axis, t.line, t.cmplx[axis].Sequence)
+	}
+	m, h := t.dims[k], t.cdims[k]
+	for r := 0; r < len(dst)/m; r++ {
+		t.last.Sequence(dst[r*m:(r+1)*m], t.work[r*h:(r+1)*h])
+	}
+	return dst
+}
+
+// MatrixCoefficients computes the Fourier coefficients of the real matrix m,
+// placing the result in dst and returning it. The coefficients have the
+// dimensions of m with the number of columns c replaced by c/2+1. This
+// transform is unnormalized; a call to MatrixCoefficients followed by a call
+// of MatrixSequence will multiply the input matrix by t.Len().
+//
+// If t is not two-dimensional or the dimensions of m are not t.Dims(),
+// MatrixCoefficients will panic. If dst is nil, a new matrix is allocated and
+// returned. If dst is empty, it is resized to the dimensions of the
+// coefficients. Otherwise MatrixCoefficients will panic if the dimensions of
+// dst do not equal t.CoefficientDims().
+func (t *FFTN) MatrixCoefficients(dst *mat.CDense, m mat.Matrix) *mat.CDense {
+	checkMatrixDims(t.dims, m)
+	return setCDense(dst, t.cdims, t.Coefficients(nil, matrixData(m)))
+}
+
+// MatrixSequence computes the real matrix with the Fourier coefficients in
+// coeff, placing the result in dst and returning it. This transform is
+// unnormalized; a call to MatrixCoefficients followed by a call of
+// MatrixSequence will multiply the input matrix by t.Len().
+//
+// If t is not two-dimensional or the dimensions of coeff are not
+// t.CoefficientDims(), MatrixSequence will panic. If dst is nil, a new matrix
+// is allocated and returned. If dst is empty, it is resized to t.Dims().
+// Otherwise MatrixSequence will panic if the dimensions of dst do not equal
+// t.Dims().
+func (t *FFTN) MatrixSequence(dst *mat.Dense, coeff mat.CMatrix) *mat.Dense {
+	checkCMatrixDims(t.cdims, coeff)
+	seq := t.Sequence(nil, cmatrixData(coeff))
+	r, c := t.dims[0], t.dims[1]
+	if dst == nil {
+		return mat.NewDense(r, c, seq)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic("fourier: destination dimension mismatch")
+	}
+	for i := 0; i < r; i++ {
+		copy(dst.RawRowView(i), seq[i*c:(i+1)*c])
+	}
+	return dst
+}
+
+// CmplxFFTN implements the multi-dimensional Fast Fourier Transform and its
+// inverse for complex arrays. Arrays are held in row-major order, so the
+// last dimension varies fastest. Two-dimensional arrays may also be
+// transformed as matrices using MatrixCoefficients and MatrixSequence.
+type CmplxFFTN struct {
+	dims []int
+
+	// cmplx holds the transforms for each dimension.
+	// Dimensions of equal length share the same
+	// transform.
+	cmplx []*CmplxFFT
+
+	line []complex128
+}
+
+// NewCmplxFFTN returns a CmplxFFTN initialized for work on arrays with the
+// given dimensions. NewCmplxFFTN will panic if no dimensions are given or if
+// any dimension is not positive.
+func NewCmplxFFTN(dims ...int) *CmplxFFTN {
+	var t CmplxFFTN
+	t.Reset(dims...)
+	return &t
+}
+
+// Dims returns the dimensions of the acceptable input.
+func (t *CmplxFFTN) Dims() []int { return append([]int(nil), t.dims...) }
+
+// Len returns the number of elements of the acceptable input.
+func (t *CmplxFFTN) Len() int { return product(t.dims) }
+
+// Reset reinitializes the CmplxFFTN for work on arrays with the given
+// dimensions. Reset will panic if no dimensions are given or if any
+// dimension is not positive.
+func (t *CmplxFFTN) Reset(dims ...int) {
+	checkDims(dims)
+	t.dims = append(t.dims[:0], dims...)
+	t.cmplx = plans(t.cmplx, dims)
+	t.line = reuseLine(t.line, dims)
+}
+
+// Coefficients computes the Fourier coefficients of the complex array seq,
+// converting the spatial or time domain data into the frequency domain,
+// placing the result in dst and returning it. This transform is
+// unnormalized; a call to Coefficients followed by a call of Sequence will
+// multiply the input array by t.Len().
+//
+// If the length of seq is not t.Len(), Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of seq, Coefficients will panic.
+// It is safe to use the same slice for dst and seq.
+func (t *CmplxFFTN) Coefficients(dst, seq []complex128) []complex128 {
+	if len(seq) != t.Len() {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, len(seq))
+	} else if len(dst) != len(seq) {
+		panic("fourier: destination length mismatch")
+	}
+	copy(dst, seq)
+	for axis := range t.dims {
+		transformAxis(dst, t.dims, axis, t.line, t.cmplx[axis].Coefficients)
+	}
+	return dst
+}
+
+// Sequence computes the complex array from its Fourier coefficients,
+// converting the frequency domain data into the spatial or time domain,
+// placing the result in dst and returning it. This transform is
+// unnormalized; a call to Coefficients followed by a call of Sequence will
+// multiply the input array by t.Len().
+//
+// If the length of coeff is not t.Len(), Sequence will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of coeff, Sequence will panic.
+// It is safe to use the same slice for dst and coeff.
+func (t *CmplxFFTN) Sequence(dst, coeff []complex128) []complex128 {
+	if len(coeff) != t.Len() {
+		panic("fourier: coefficients length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, len(coeff))
+	} else if len(dst) != len(coeff) {
+		panic("fourier: destination length mismatch")
+	}
+	copy(dst, coeff)
+	for axis := range t.dims {
+		transformAxis(dst, t.dims, axis, t.line, t.cmplx[axis].Sequence)
+	}
+	return dst
+}
+
+// MatrixCoefficients computes the Fourier coefficients of the complex matrix
+// m, placing the result in dst and returning it. This transform is
+// unnormalized; a call to MatrixCoefficients followed by a call of
+// MatrixSequence will multiply the input matrix by t.Len().
+//
+// If t is not two-dimensional or the dimensions of m are not t.Dims(),
+// MatrixCoefficients will panic. If dst is nil, a new matrix is allocated and
+// returned. If dst is empty, it is resized to t.Dims(). Otherwise
+// MatrixCoefficients will panic if the dimensions of dst do not equal
+// t.Dims(). It is safe to use the same matrix for dst and m.
+func (t *CmplxFFTN) MatrixCoefficients(dst *mat.CDense, m mat.CMatrix) *mat.CDense {
+	checkCMatrixDims(t.dims, m)
+	data := cmatrixData(m)
+	return setCDense(dst, t.dims, t.Coefficients(data, data))
+}
+
+// MatrixSequence computes the complex matrix with the Fourier coefficients
+// in coeff, placing the result in dst and returning it. This transform is
+// unnormalized; a call to MatrixCoefficients followed by a call of
+// MatrixSequence will multiply the input matrix by t.Len().
+//
+// If t is not two-dimensional or the dimensions of coeff are not t.Dims(),
+// MatrixSequence will panic. If dst is nil, a new matrix is allocated and
+// returned. If dst is empty, it is resized to t.Dims(). Otherwise
+// MatrixSequence will panic if the dimensions of dst do not equal t.Dims().
+// It is safe to use the same matrix for dst and coeff.
+func (t *CmplxFFTN) MatrixSequence(dst *mat.CDense, coeff mat.CMatrix) *mat.CDense {
+	checkCMatrixDims(t.dims, coeff)
+	data := cmatrixData(coeff)
+	return setCDense(dst, t.dims, t.Sequence(data, data))
+}
+
+// checkMatrixDims panics if dims are not the dimensions of m.
+func checkMatrixDims(dims []int, m mat.Matrix) {
+	if len(dims) != 2 {
+		panic("fourier: transform is not two-dimensional")
+	}
+	if r, c := m.Dims(); r != dims[0] || c != dims[1] {
+		panic("fourier: matrix dimension mismatch")
+	}
+}
+
+// checkCMatrixDims panics if dims are not the dimensions of m.
+func checkCMatrixDims(dims []int, m mat.CMatrix) {
+	if len(dims) != 2 {
+		panic("fourier: transform is not two-dimensional")
+	}
+	if r, c := m.Dims(); r != dims[0] || c != dims[1] {
+		panic("fourier: matrix dimension mismatch")
+	}
+}
+
+// matrixData returns the elements of m in a newly allocated
+// row-major slice.
+func matrixData(m mat.Matrix) []float64 {
+	r, c := m.Dims()
+	data := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data[i*c+j] = m.At(i, j)
+		}
+	}
+	return data
+}
+
+// cmatrixData returns the elements of m in a newly allocated
+// row-major slice.
+func cmatrixData(m mat.CMatrix) []complex128 {
+	r, c := m.Dims()
+	data := make([]complex128, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data[i*c+j] = m.At(i, j)
+		}
+	}
+	return data
+}
+
+// setCDense stores the row-major array data with the given
+// two-dimensional dims into dst, allocating a new matrix
+// backed by data if dst is nil, and returns dst.
+func setCDense(dst *mat.CDense, dims []int, data []complex128) *mat.CDense {
+	r, c := dims[0], dims[1]
+	if dst == nil {
+		return mat.NewCDense(r, c, data)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic("fourier: destination dimension mismatch")
+	}
+	raw := dst.RawCMatrix()
+	for i := 0; i < r; i++ {
+		copy(raw.Data[i*raw.Stride:i*raw.Stride+c], data[i*c:(i+1)*c])
+	}
+	return dst
+}
+
+// transformAxis applies the one-dimensional transform fn in place to each
+// line of the row-major array data with the given dimensions along axis,
+// using line as working space.
+func transformAxis(data []complex128, dims []int, axis int, line []complex128, fn func(dst, seq []complex128) []complex128) {
+	n := dims[axis]
+	stride := product(dims[axis+1:])
+	if stride == 1 {
+		for off := 0; off < len(data); off += n {
+			fn(data[off:off+n], data[off:off+n])
+		}
+		return
+	}
+	line = line[:n]
+	block := n * stride
+	for base := 0; base < len(data); base += block {
+		for j := 0; j < stride; j++ {
+			for i := range line {
+				line[i] = data[base+i*stride+j]
+			}
+			fn(line, line)
+			for i, v := range line {
+				data[base+i*stride+j] = v
+			}
+		}
+	}
+}
+
+// plans returns the complex transforms for the given dimensions, reusing
+// the transforms in dst where possible. Dimensions of equal length share
+// a transform.
+func plans(dst []*CmplxFFT, dims []int) []*CmplxFFT {
+	old := make(map[int]*CmplxFFT)
+	for _, p := range dst {
+		old[p.Len()] = p
+	}
+	dst = dst[:0]
+	for _, n := range dims {
+		p, ok := old[n]
+		if !ok {
+			p = NewCmplxFFT(n)
+			old[n] = p
+		}
+		dst = append(dst, p)
+	}
+	return dst
+}
+
+// reuseLine returns a slice long enough to hold a line of the longest of
+// the given dimensions, reusing line if it has sufficient capacity.
+func reuseLine(line []complex128, dims []int) []complex128 {
+	var n int
+	for _, d := range dims {
+		if d > n {
+			n = d
+		}
+	}
+	if n <= cap(line) {
+		return line[:n]
+	}
+	return make([]complex128, n)
+}
+
+// checkDims panics if dims is empty or holds a non-positive dimension.
+func checkDims(dims []int) {
+	if len(dims) == 0 {
+		panic("fourier: no dimensions")
+	}
+	for _, d := range dims {
+		if d <= 0 {
+			panic("fourier: non-positive dimension")
+		}
+	}
+}
+
+// product returns the product of the values in dims.
+func product(dims []int) int {
+	p := 1
+	for _, d := range dims {
+		p *= d
+	}
+	return p
+}
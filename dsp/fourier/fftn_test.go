@@ -0,0 +1,233 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var fftnDims = [][]int{
+	{1},
+	{7},
+	{1, 1},
+	{4, 6},
+	{5, 3},
+	{9, 1},
+	{1, 8},
+	{3, 4, 5},
+	{2, 3, 2, 3},
+}
+
+func TestCmplxFFTN(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	var fft CmplxFFTN
+	for _, dims := range fftnDims {
+		fft.Reset(dims...)
+		n := fft.Len()
+		seq := randComplexes(n, src)
+
+		want := naiveDFTN(seq, dims, -1)
+		got := fft.Coefficients(nil, seq)
+		if !equalApprox(got, want, tol) {
+			t.Errorf("unexpected coefficients for dims %v:\ngot: %v\nwant:%v", dims, got, want)
+		}
+
+		// Check the inverse transform in place.
+		inv := append([]complex128(nil), got...)
+		fft.Sequence(inv, inv)
+		for i := range inv {
+			inv[i] /= complex(float64(n), 0)
+		}
+		if !equalApprox(inv, seq, tol) {
+			t.Errorf("unexpected round trip for dims %v:\ngot: %v\nwant:%v", dims, inv, seq)
+		}
+	}
+}
+
+func TestFFTN(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	var fft FFTN
+	for _, dims := range fftnDims {
+		fft.Reset(dims...)
+		n := fft.Len()
+		seq := randFloats(n, src)
+
+		cseq := make([]complex128, n)
+		for i, v := range seq {
+			cseq[i] = complex(v, 0)
+		}
+		full := naiveDFTN(cseq, dims, -1)
+
+		// The real transform holds the first d/2+1
+		// coefficients along the last dimension.
+		cdims := fft.CoefficientDims()
+		k := len(dims) - 1
+		if cdims[k] != dims[k]/2+1 {
+			t.Errorf("unexpected coefficient dims for %v: %v", dims, cdims)
+		}
+		var want []complex128
+		for r := 0; r < n/dims[k]; r++ {
+			want = append(want, full[r*dims[k]:r*dims[k]+cdims[k]]...)
+		}
+		coeff := fft.Coefficients(nil, seq)
+		if !equalApprox(coeff, want, tol) {
+			t.Errorf("unexpected coefficients for dims %v:\ngot: %v\nwant:%v", dims, coeff, want)
+		}
+
+		saved := append([]complex128(nil), coeff...)
+		got := fft.Sequence(nil, coeff)
+		floats.Scale(1/float64(n), got)
+		if !floats.EqualApprox(got, seq, tol) {
+			t.Errorf("unexpected round trip for dims %v:\ngot: %v\nwant:%v", dims, got, seq)
+		}
+		if !equalApprox(coeff, saved, 0) {
+			t.Errorf("coefficients modified by Sequence for dims %v", dims)
+		}
+	}
+}
+
+func TestFFTNMatrix(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	for _, dims := range [][2]int{{1, 1}, {4, 6}, {5, 3}, {9, 1}, {1, 8}} {
+		r, c := dims[0], dims[1]
+		fft := NewFFTN(r, c)
+		cfft := NewCmplxFFTN(r, c)
+
+		// Transform a view with a stride greater than its
+		// number of columns.
+		a := mat.NewDense(r, c+2, randFloats(r*(c+2), src)).Slice(0, r, 1, c+1)
+		seq := make([]float64, r*c)
+		cseq := make([]complex128, r*c)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				seq[i*c+j] = a.At(i, j)
+				cseq[i*c+j] = complex(a.At(i, j), 0)
+			}
+		}
+
+		want := fft.Coefficients(nil, seq)
+		coeff := fft.MatrixCoefficients(nil, a)
+		if !equalApprox(cdenseData(coeff), want, tol) {
+			t.Errorf("unexpected real matrix coefficients for dims %v", dims)
+		}
+		var got mat.Dense
+		fft.MatrixSequence(&got, coeff)
+		got.Scale(1/float64(r*c), &got)
+		if !mat.EqualApprox(&got, a, tol) {
+			t.Errorf("unexpected real matrix round trip for dims %v", dims)
+		}
+
+		ca := mat.NewCDense(r, c, cseq)
+		cwant := cfft.Coefficients(nil, cseq)
+		ccoeff := cfft.MatrixCoefficients(nil, ca)
+		if !equalApprox(cdenseData(ccoeff), cwant, tol) {
+			t.Errorf("unexpected complex matrix coefficients for dims %v", dims)
+		}
+		cfft.MatrixSequence(ccoeff, ccoeff)
+		inv := cdenseData(ccoeff)
+		for i := range inv {
+			inv[i] /= complex(float64(r*c), 0)
+		}
+		if !equalApprox(inv, cseq, tol) {
+			t.Errorf("unexpected complex matrix round trip for dims %v", dims)
+		}
+	}
+}
+
+// cdenseData returns the elements of m in row-major order.
+func cdenseData(m *mat.CDense) []complex128 {
+	r, c := m.Dims()
+	data := make([]complex128, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data = append(data, m.At(i, j))
+		}
+	}
+	return data
+}
+
+func TestFFTNPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "no dims", fn: func() { NewFFTN() }},
+		{name: "zero dim", fn: func() { NewCmplxFFTN(3, 0) }},
+		{name: "short seq", fn: func() { NewFFTN(2, 3).Coefficients(nil, make([]float64, 5)) }},
+		{name: "short dst", fn: func() { NewFFTN(2, 3).Coefficients(make([]complex128, 6), make([]float64, 6)) }},
+		{name: "short coeff", fn: func() { NewCmplxFFTN(2, 3).Sequence(nil, make([]complex128, 5)) }},
+		{name: "matrix not 2-D", fn: func() { NewFFTN(2, 3, 4).MatrixCoefficients(nil, mat.NewDense(2, 3, nil)) }},
+		{name: "matrix dims", fn: func() { NewFFTN(2, 3).MatrixCoefficients(nil, mat.NewDense(3, 2, nil)) }},
+		{name: "matrix coeff dims", fn: func() { NewFFTN(2, 3).MatrixSequence(nil, mat.NewCDense(2, 3, nil)) }},
+		{name: "matrix dst dims", fn: func() { NewCmplxFFTN(2, 3).MatrixCoefficients(mat.NewCDense(3, 2, nil), mat.NewCDense(2, 3, nil)) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+// naiveDFTN returns the multi-dimensional discrete Fourier transform of the
+// row-major array seq with the given dimensions computed directly from the
+// definition. The sign of the exponent is given by sign.
+func naiveDFTN(seq []complex128, dims []int, sign float64) []complex128 {
+	n := len(seq)
+	index := func(flat int) []int {
+		idx := make([]int, len(dims))
+		for k := len(dims) - 1; k >= 0; k-- {
+			idx[k] = flat % dims[k]
+			flat /= dims[k]
+		}
+		return idx
+	}
+	dst := make([]complex128, n)
+	for f := range dst {
+		fi := index(f)
+		var sum complex128
+		for x, v := range seq {
+			xi := index(x)
+			var phase float64
+			for k, d := range dims {
+				phase += float64(fi[k]*xi[k]) / float64(d)
+			}
+			sum += v * cmplx.Exp(complex(0, sign*2*math.Pi*phase))
+		}
+		dst[f] = sum
+	}
+	return dst
+}
+
+func BenchmarkCmplxFFTN(b *testing.B) {
+	for _, dims := range [][]int{{64, 64}, {256, 256}, {32, 32, 32}} {
+		fft := NewCmplxFFTN(dims...)
+		seq := randComplexes(fft.Len(), rand.NewSource(1))
+		dst := make([]complex128, len(seq))
+		b.Run(fmt.Sprint(dims), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fft.Coefficients(dst, seq)
+			}
+		})
+	}
+}
@@ -223,3 +223,48 @@ func Example_cmplxFFT2() {
 	// ⎣ 1.1   3.9   2.6   1.4   1.1   1.1   1.2   1.7   3.8   6.8   1.6⎦
 
 }
+
+func ExampleFFTN() {
+	// This example repeats the 2D fourier transform of
+	// Example_fFT2 using the multi-dimensional FFT type.
+
+	// Image is a set of diagonal lines.
+	image := mat.NewDense(11, 11, []float64{
+		0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0,
+		0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1,
+		1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0,
+		0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0,
+		0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1,
+		1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0,
+		0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0,
+		0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1,
+		1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0,
+		0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0,
+		0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1,
+	})
+
+	r, c := image.Dims()
+	fft := fourier.NewFFTN(r, c)
+	coeff := fft.MatrixCoefficients(nil, image)
+
+	// Only c/2+1 coefficients are returned for
+	// each row of the real FFT.
+	c = fft.CoefficientDims()[1]
+	freqs := mat.NewDense(c, c, nil)
+	for i := 0; i < c; i++ {
+		for j := 0; j < c; j++ {
+			freqs.Set(i, j, scalar.Round(cmplx.Abs(coeff.At(i, j)), 1))
+		}
+	}
+
+	fmt.Printf("%v\n", mat.Formatted(freqs))
+
+	// Output:
+	//
+	// ⎡  40   0.4   0.5   1.4   3.2   1.1⎤
+	// ⎢ 0.4   0.5   0.7   1.8     4   1.2⎥
+	// ⎢ 0.5   0.7   1.1   2.8   5.9   1.7⎥
+	// ⎢ 1.4   1.8   2.8   6.8  14.1   3.8⎥
+	// ⎢ 3.2     4   5.9  14.1  27.5   6.8⎥
+	// ⎣ 1.1   1.2   1.7   3.8   6.8   1.6⎦
+}
@@ -0,0 +1,227 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/internal/bessel"
+)
+
+// NUFFT implements the one-dimensional non-uniform Fast Fourier Transform
+// of types 1 and 2 for complex data. The non-uniform points are real values
+// t_j with the transforms being periodic in t_j with period one, so that
+// uniformly spaced points t_j = j/n give the discrete Fourier transform
+// computed by CmplxFFT.
+//
+// The n Fourier modes k are held in the same order as the coefficients of a
+// CmplxFFT of length n, with index i holding mode k = i for i < (n-1)/2+1
+// and mode k = i-n otherwise. The Mode method returns the mode of an index.
+//
+// The transforms are computed by spreading onto an oversampled uniform grid
+// using a Kaiser-Bessel kernel, followed by an FFT and correction for the
+// kernel's Fourier transform, as described in Beatty et al., "Rapid gridding
+// reconstruction with a minimal oversampling ratio" (2005). The cost of each
+// transform is O(n log n + w m) for m points and a kernel of width w.
+type NUFFT struct {
+	n    int
+	w    int
+	beta float64
+
+	fft  *CmplxFFT
+	grid []complex128
+
+	// deapod holds the reciprocal of the kernel's
+	// Fourier transform for each mode.
+	deapod []float64
+}
+
+const (
+	// nufftOversample is the ratio of the
+	// grid length to the number of modes.
+	nufftOversample = 2
+
+	nufftMinWidth = 2
+	nufftMaxWidth = 16
+)
+
+// NewNUFFT returns a NUFFT initialized for work with n Fourier modes to
+// the given relative tolerance. The tolerance determines the width of the
+// spreading kernel; tolerances below about 1e-14 are not achievable in
+// float64 arithmetic. NewNUFFT will panic if n is not positive or if tol is
+// not in (0, 1).
+func NewNUFFT(n int, tol float64) *NUFFT {
+	if n <= 0 {
+		panic("fourier: non-positive number of modes")
+	}
+	if !(0 < tol && tol < 1) {
+		panic("fourier: tolerance out of range")
+	}
+	w := int(math.Ceil(-math.Log10(tol))) + 1
+	if w < nufftMinWidth {
+		w = nufftMinWidth
+	}
+	if w > nufftMaxWidth {
+		w = nufftMaxWidth
+	}
+	m := nextFastLen(nufftOversample * n)
+	if m < 2*w {
+		m = 2 * w
+	}
+
+	const sigma = nufftOversample
+	fw := float64(w)
+	beta := math.Pi * math.Sqrt(fw*fw/(sigma*sigma)*(sigma-0.5)*(sigma-0.5)-0.8)
+
+	t := &NUFFT{
+		n:      n,
+		w:      w,
+		beta:   beta,
+		fft:    NewCmplxFFT(m),
+		grid:   make([]complex128, m),
+		deapod: make([]float64, n),
+	}
+	for i := range t.deapod {
+		t.deapod[i] = 1 / t.kernelTransform(float64(t.Mode(i))/float64(m))
+	}
+	return t
+}
+
+// Len returns the number of Fourier modes.
+func (t *NUFFT) Len() int { return t.n }
+
+// Mode returns the Fourier mode held at index i.
+// Mode will panic if i is negative or greater than or equal to t.Len().
+func (t *NUFFT) Mode(i int) int {
+	if i < 0 || t.n <= i {
+		panic("fourier: index out of range")
+	}
+	if i < (t.n-1)/2+1 {
+		return i
+	}
+	return i - t.n
+}
+
+// Coefficients computes the type 1 non-uniform transform of the values in
+// seq at the points in pts,
+//  dst[i] = Σ_j seq[j] * exp(-2πi * k_i * pts[j]),
+// for each mode k_i, placing the result in dst and returning it. This
+// transform is unnormalized and is the adjoint of Sequence.
+//
+// If the lengths of pts and seq differ, Coefficients will panic. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil and its
+// length is not t.Len(), Coefficients will panic.
+func (t *NUFFT) Coefficients(dst []complex128, pts []float64, seq []complex128) []complex128 {
+	if len(pts) != len(seq) {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, t.n)
+	} else if len(dst) != t.n {
+		panic("fourier: destination length mismatch")
+	}
+
+	for i := range t.grid {
+		t.grid[i] = 0
+	}
+	weights := make([]float64, t.w)
+	for j, p := range pts {
+		l0 := t.kernel(weights, p)
+		v := seq[j]
+		for k, w := range weights {
+			t.grid[t.wrap(l0+k)] += complex(w, 0) * v
+		}
+	}
+	t.fft.Coefficients(t.grid, t.grid)
+	for i := range dst {
+		dst[i] = t.grid[t.wrap(t.Mode(i))] * complex(t.deapod[i], 0)
+	}
+	return dst
+}
+
+// Sequence computes the type 2 non-uniform transform of the Fourier
+// coefficients in coeff at the points in pts,
+//  dst[j] = Σ_i coeff[i] * exp(2πi * k_i * pts[j]),
+// for each mode k_i, placing the result in dst and returning it. This
+// transform is unnormalized and is the adjoint of Coefficients.
+//
+// If the length of coeff is not t.Len(), Sequence will panic. If dst is nil,
+// a new slice with the length of pts is allocated and returned. If dst is not
+// nil and its length does not equal the length of pts, Sequence will panic.
+func (t *NUFFT) Sequence(dst []complex128, pts []float64, coeff []complex128) []complex128 {
+	if len(coeff) != t.n {
+		panic("fourier: coefficients length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, len(pts))
+	} else if len(dst) != len(pts) {
+		panic("fourier: destination length mismatch")
+	}
+
+	for i := range t.grid {
+		t.grid[i] = 0
+	}
+	for i, v := range coeff {
+		t.grid[t.wrap(t.Mode(i))] = v * complex(t.deapod[i], 0)
+	}
+	t.fft.Sequence(t.grid, t.grid)
+	weights := make([]float64, t.w)
+	for j, p := range pts {
+		l0 := t.kernel(weights, p)
+		var sum complex128
+		for k, w := range weights {
+			sum += complex(w, 0) * t.grid[t.wrap(l0+k)]
+		}
+		dst[j] = sum
+	}
+	return dst
+}
+
+// kernel fills weights with the values of the spreading kernel at the grid
+// points covered by the kernel centered at the point p and returns the
+// unwrapped index of the first of those grid points.
+func (t *NUFFT) kernel(weights []float64, p float64) int {
+	m := float64(len(t.grid))
+	u := (p - math.Floor(p)) * m
+	half := float64(t.w) / 2
+	l0 := int(math.Ceil(u - half))
+	for k := range weights {
+		z := 2 * (u - float64(l0+k)) / float64(t.w)
+		if s := 1 - z*z; s > 0 {
+			weights[k] = bessel.I0(t.beta * math.Sqrt(s))
+		} else {
+			weights[k] = 0
+		}
+	}
+	return l0
+}
+
+// kernelTransform returns the continuous Fourier transform of the spreading
+// kernel at the frequency nu in cycles per grid spacing.
+func (t *NUFFT) kernelTransform(nu float64) float64 {
+	w := float64(t.w)
+	x := math.Pi * w * nu
+	s := t.beta*t.beta - x*x
+	switch {
+	case s > 0:
+		s = math.Sqrt(s)
+		return w * math.Sinh(s) / s
+	case s < 0:
+		s = math.Sqrt(-s)
+		return w * math.Sin(s) / s
+	default:
+		return w
+	}
+}
+
+// wrap returns the grid index for the unwrapped index l.
+func (t *NUFFT) wrap(l int) int {
+	m := len(t.grid)
+	l %= m
+	if l < 0 {
+		l += m
+	}
+	return l
+}
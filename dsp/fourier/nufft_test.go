@@ -0,0 +1,121 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNUFFT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 7, 16, 33, 100} {
+		for _, tol := range []float64{1e-3, 1e-6, 1e-9, 1e-12} {
+			nufft := NewNUFFT(n, tol)
+			m := 3*n + 5
+			pts := make([]float64, m)
+			for j := range pts {
+				// Include points outside [0, 1).
+				pts[j] = 3*rnd.Float64() - 1
+			}
+			seq := randComplexes(m, rnd)
+			coeff := randComplexes(n, rnd)
+
+			got := nufft.Coefficients(nil, pts, seq)
+			want := make([]complex128, n)
+			for i := range want {
+				k := float64(nufft.Mode(i))
+				for j, p := range pts {
+					want[i] += seq[j] * cmplx.Exp(complex(0, -2*math.Pi*k*p))
+				}
+			}
+			if e := relErr(got, want); e > tol {
+				t.Errorf("unexpected type 1 error for n=%d tol=%v: %v", n, tol, e)
+			}
+
+			got = nufft.Sequence(nil, pts, coeff)
+			want = make([]complex128, m)
+			for j, p := range pts {
+				for i, c := range coeff {
+					k := float64(nufft.Mode(i))
+					want[j] += c * cmplx.Exp(complex(0, 2*math.Pi*k*p))
+				}
+			}
+			if e := relErr(got, want); e > tol {
+				t.Errorf("unexpected type 2 error for n=%d tol=%v: %v", n, tol, e)
+			}
+		}
+	}
+}
+
+func TestNUFFTUniform(t *testing.T) {
+	t.Parallel()
+	const n = 24
+	rnd := rand.New(rand.NewSource(1))
+	seq := randComplexes(n, rnd)
+	pts := make([]float64, n)
+	for j := range pts {
+		pts[j] = float64(j) / n
+	}
+
+	// Uniform points give the discrete Fourier transform
+	// with modes in the same order.
+	want := NewCmplxFFT(n).Coefficients(nil, seq)
+	got := NewNUFFT(n, 1e-12).Coefficients(nil, pts, seq)
+	if e := relErr(got, want); e > 1e-12 {
+		t.Errorf("unexpected error for uniform points: %v", e)
+	}
+
+	fft := NewCmplxFFT(n)
+	nufft := NewNUFFT(n, 1e-3)
+	for i := 0; i < n; i++ {
+		if got, want := float64(nufft.Mode(i))/n, fft.Freq(i); math.Abs(got-want) > 1e-15 {
+			t.Errorf("mode mismatch at %d: got:%v want:%v", i, got, want)
+		}
+	}
+}
+
+func TestNUFFTPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero modes", fn: func() { NewNUFFT(0, 1e-6) }},
+		{name: "zero tol", fn: func() { NewNUFFT(4, 0) }},
+		{name: "unit tol", fn: func() { NewNUFFT(4, 1) }},
+		{name: "seq", fn: func() { NewNUFFT(4, 1e-6).Coefficients(nil, make([]float64, 3), make([]complex128, 2)) }},
+		{name: "dst", fn: func() {
+			NewNUFFT(4, 1e-6).Coefficients(make([]complex128, 3), make([]float64, 3), make([]complex128, 3))
+		}},
+		{name: "coeff", fn: func() { NewNUFFT(4, 1e-6).Sequence(nil, make([]float64, 3), make([]complex128, 3)) }},
+		{name: "mode", fn: func() { NewNUFFT(4, 1e-6).Mode(4) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+// relErr returns the relative error of got with respect to want in the
+// Euclidean norm.
+func relErr(got, want []complex128) float64 {
+	var num, den float64
+	for i := range got {
+		d := got[i] - want[i]
+		num += real(d)*real(d) + imag(d)*imag(d)
+		den += real(want[i])*real(want[i]) + imag(want[i])*imag(want[i])
+	}
+	return math.Sqrt(num / den)
+}
@@ -0,0 +1,260 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Padding specifies how a sequence is extended at its ends before it is
+// divided into frames by a short-time Fourier transform.
+type Padding int
+
+const (
+	// NoPadding divides the sequence into frames starting
+	// at the first element. Trailing elements that do not
+	// fill a frame are not transformed.
+	NoPadding Padding = iota
+
+	// ZeroPadding extends the sequence by half a frame of
+	// zeros at each end so that each frame is centered on
+	// a multiple of the hop length.
+	ZeroPadding
+
+	// ReflectPadding extends the sequence by half a frame
+	// at each end with the reflection of the sequence about
+	// its end elements so that each frame is centered on a
+	// multiple of the hop length.
+	ReflectPadding
+)
+
+// STFT implements the short-time Fourier transform and its inverse for real
+// sequences. The sequence is divided into overlapping frames of a fixed
+// length starting at multiples of the hop length, and each frame is
+// multiplied by a window and transformed by a real FFT.
+type STFT struct {
+	window  []float64
+	hop     int
+	padding Padding
+
+	fft   *FFT
+	frame []float64
+}
+
+// NewSTFT returns an STFT initialized for work on frames of length n
+// separated by hop elements with the given padding. The window function is
+// called once with a slice of n ones and must return the window weights; the
+// functions in gonum.org/v1/gonum/dsp/window may be used. If window is nil,
+// a rectangular window is used. NewSTFT will panic if n or hop is not
+// positive.
+func NewSTFT(n, hop int, window func([]float64) []float64, padding Padding) *STFT {
+	if n <= 0 {
+		panic("fourier: non-positive frame length")
+	}
+	if hop <= 0 {
+		panic("fourier: non-positive hop length")
+	}
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1
+	}
+	if window != nil {
+		w = window(w)
+	}
+	return &STFT{
+		window:  w,
+		hop:     hop,
+		padding: padding,
+		fft:     NewFFT(n),
+		frame:   make([]float64, n),
+	}
+}
+
+// Len returns the length of the frames.
+func (t *STFT) Len() int { return len(t.window) }
+
+// Hop returns the number of elements between the starts of adjacent frames.
+func (t *STFT) Hop() int { return t.hop }
+
+// Freq returns the relative frequency center for coefficient i of a frame.
+// Freq will panic if i is negative or greater than or equal to t.Len().
+func (t *STFT) Freq(i int) float64 { return t.fft.Freq(i) }
+
+// pad returns the number of padding elements at each end of a sequence.
+func (t *STFT) pad() int {
+	if t.padding == NoPadding {
+		return 0
+	}
+	return t.Len() / 2
+}
+
+// Frames returns the number of frames in the transform of a sequence of
+// length n.
+func (t *STFT) Frames(n int) int {
+	padded := n + 2*t.pad()
+	if padded < t.Len() {
+		return 0
+	}
+	return 1 + (padded-t.Len())/t.hop
+}
+
+// Coefficients computes the short-time Fourier coefficients of the real
+// sequence seq, placing the t.Len()/2+1 coefficients of each frame in the
+// corresponding row of dst and returning it. Frame i starts at element
+// i*t.Hop() of the padded sequence. The transform of each frame is
+// unnormalized.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and its length is not t.Frames(len(seq)) or the length of any of its rows
+// is not t.Len()/2+1, Coefficients will panic. Coefficients will panic if
+// the padding is ReflectPadding and seq is not longer than t.Len()/2.
+func (t *STFT) Coefficients(dst [][]complex128, seq []float64) [][]complex128 {
+	frames := t.Frames(len(seq))
+	bins := t.Len()/2 + 1
+	if dst == nil {
+		dst = make([][]complex128, frames)
+		for i := range dst {
+			dst[i] = make([]complex128, bins)
+		}
+	} else if len(dst) != frames {
+		panic("fourier: destination length mismatch")
+	}
+	if t.padding == ReflectPadding && len(seq) <= t.pad() {
+		panic("fourier: sequence too short for reflect padding")
+	}
+	p := t.pad()
+	for i, row := range dst {
+		if len(row) != bins {
+			panic("fourier: destination length mismatch")
+		}
+		start := i*t.hop - p
+		for j, w := range t.window {
+			t.frame[j] = w * t.at(seq, start+j)
+		}
+		t.fft.Coefficients(row, t.frame)
+	}
+	return dst
+}
+
+// at returns the element k of seq extended according to the padding.
+func (t *STFT) at(seq []float64, k int) float64 {
+	if 0 <= k && k < len(seq) {
+		return seq[k]
+	}
+	if t.padding != ReflectPadding {
+		return 0
+	}
+	if k < 0 {
+		return seq[-k]
+	}
+	return seq[2*(len(seq)-1)-k]
+}
+
+// Sequence computes the real sequence from its short-time Fourier
+// coefficients using the weighted overlap-add method, placing the result
+// in dst and returning it. Each frame is inverse transformed, multiplied by
+// the window and added to the result, which is then divided by the sum of
+// the squared window weights at each element. This inverts Coefficients for
+// the elements covered by a frame with a non-zero window weight. Elements
+// that are not covered are set to zero.
+//
+// If dst is nil, a slice long enough to hold all the elements covered by the
+// frames is allocated and returned. Sequence will panic if the length of
+// any row of coeff is not t.Len()/2+1.
+func (t *STFT) Sequence(dst []float64, coeff [][]complex128) []float64 {
+	n := t.Len()
+	p := t.pad()
+	if dst == nil {
+		length := 0
+		if len(coeff) != 0 {
+			length = (len(coeff)-1)*t.hop + n - 2*p
+		}
+		if length < 0 {
+			length = 0
+		}
+		dst = make([]float64, length)
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	norm := make([]float64, len(dst))
+	scale := 1 / float64(n)
+	for i, row := range coeff {
+		if len(row) != n/2+1 {
+			panic("fourier: coefficients length mismatch")
+		}
+		t.fft.Sequence(t.frame, row)
+		start := i*t.hop - p
+		for j, w := range t.window {
+			k := start + j
+			if k < 0 || len(dst) <= k {
+				continue
+			}
+			dst[k] += w * t.frame[j] * scale
+			norm[k] += w * w
+		}
+	}
+	for k, s := range norm {
+		if s > 1e-12 {
+			dst[k] /= s
+		} else {
+			dst[k] = 0
+		}
+	}
+	return dst
+}
+
+// MagnitudeSpectrogram returns the magnitudes of the short-time Fourier
+// coefficients in coeff as a matrix with one row per frame and one column
+// per frequency bin, placing the result in dst and returning it.
+//
+// If dst is nil, a new matrix is allocated and returned. If dst is empty,
+// it is resized to hold the spectrogram. Otherwise MagnitudeSpectrogram
+// will panic if the dimensions of dst do not match coeff.
+// MagnitudeSpectrogram will panic if coeff is empty or if its rows do not
+// all have the same length.
+func MagnitudeSpectrogram(dst *mat.Dense, coeff [][]complex128) *mat.Dense {
+	return spectrogram(dst, coeff, cmplx.Abs)
+}
+
+// PowerSpectrogram returns the squared magnitudes of the short-time Fourier
+// coefficients in coeff as a matrix with one row per frame and one column
+// per frequency bin, placing the result in dst and returning it.
+//
+// If dst is nil, a new matrix is allocated and returned. If dst is empty,
+// it is resized to hold the spectrogram. Otherwise PowerSpectrogram will
+// panic if the dimensions of dst do not match coeff. PowerSpectrogram will
+// panic if coeff is empty or if its rows do not all have the same length.
+func PowerSpectrogram(dst *mat.Dense, coeff [][]complex128) *mat.Dense {
+	return spectrogram(dst, coeff, func(c complex128) float64 {
+		return real(c)*real(c) + imag(c)*imag(c)
+	})
+}
+
+func spectrogram(dst *mat.Dense, coeff [][]complex128, fn func(complex128) float64) *mat.Dense {
+	if len(coeff) == 0 || len(coeff[0]) == 0 {
+		panic("fourier: empty coefficients")
+	}
+	frames, bins := len(coeff), len(coeff[0])
+	if dst == nil {
+		dst = mat.NewDense(frames, bins, nil)
+	} else if dst.IsEmpty() {
+		dst.ReuseAs(frames, bins)
+	} else if r, c := dst.Dims(); r != frames || c != bins {
+		panic("fourier: destination dimension mismatch")
+	}
+	for i, row := range coeff {
+		if len(row) != bins {
+			panic("fourier: ragged coefficients")
+		}
+		dstRow := dst.RawRowView(i)
+		for j, c := range row {
+			dstRow[j] = fn(c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,170 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSTFTRoundTrip(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		n, hop  int
+		window  func([]float64) []float64
+		padding Padding
+		length  int
+	}{
+		{n: 8, hop: 8, padding: NoPadding, length: 64},
+		{n: 8, hop: 3, padding: NoPadding, length: 50},
+		{n: 16, hop: 4, window: window.Hann, padding: ZeroPadding, length: 100},
+		{n: 16, hop: 4, window: window.Hann, padding: ReflectPadding, length: 100},
+		{n: 15, hop: 5, window: window.Hamming, padding: ReflectPadding, length: 61},
+		{n: 32, hop: 8, window: window.Blackman, padding: ZeroPadding, length: 256},
+	} {
+		stft := NewSTFT(test.n, test.hop, test.window, test.padding)
+		seq := randFloats(test.length, src)
+		coeff := stft.Coefficients(nil, seq)
+		if len(coeff) != stft.Frames(len(seq)) {
+			t.Errorf("unexpected number of frames for n=%d hop=%d: got:%d want:%d",
+				test.n, test.hop, len(coeff), stft.Frames(len(seq)))
+		}
+
+		got := stft.Sequence(make([]float64, len(seq)), coeff)
+
+		// Only the elements covered by a frame with a
+		// non-zero window weight are recovered.
+		covered := len(seq)
+		if test.padding == NoPadding {
+			covered = (len(coeff)-1)*test.hop + test.n
+		}
+		if !floats.EqualApprox(got[:covered], seq[:covered], tol) {
+			t.Errorf("unexpected round trip for n=%d hop=%d padding=%d:\ngot: %v\nwant:%v",
+				test.n, test.hop, test.padding, got[:covered], seq[:covered])
+		}
+	}
+}
+
+func TestSTFTFrames(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-12
+	src := rand.NewSource(1)
+	const n, hop = 10, 4
+	seq := randFloats(37, src)
+	for _, padding := range []Padding{NoPadding, ZeroPadding, ReflectPadding} {
+		stft := NewSTFT(n, hop, window.Hann, padding)
+		coeff := stft.Coefficients(nil, seq)
+
+		// Construct the padded sequence explicitly.
+		p := 0
+		if padding != NoPadding {
+			p = n / 2
+		}
+		padded := make([]float64, len(seq)+2*p)
+		copy(padded[p:], seq)
+		if padding == ReflectPadding {
+			for i := 1; i <= p; i++ {
+				padded[p-i] = seq[i]
+				padded[p+len(seq)-1+i] = seq[len(seq)-1-i]
+			}
+		}
+
+		fft := NewFFT(n)
+		frame := make([]float64, n)
+		for i, row := range coeff {
+			for j := range frame {
+				frame[j] = 1
+			}
+			window.Hann(frame)
+			for j := range frame {
+				frame[j] *= padded[i*hop+j]
+			}
+			want := fft.Coefficients(nil, frame)
+			if !equalApprox(row, want, tol) {
+				t.Errorf("unexpected coefficients for frame %d with padding %d:\ngot: %v\nwant:%v",
+					i, padding, row, want)
+			}
+		}
+	}
+}
+
+func TestSTFTTone(t *testing.T) {
+	t.Parallel()
+	const (
+		n   = 64
+		hop = 16
+		bin = 5
+	)
+	seq := make([]float64, 512)
+	for i := range seq {
+		seq[i] = math.Sin(2 * math.Pi * bin * float64(i) / n)
+	}
+	stft := NewSTFT(n, hop, window.Hann, ReflectPadding)
+	coeff := stft.Coefficients(nil, seq)
+	if stft.Freq(bin) != float64(bin)/n {
+		t.Errorf("unexpected frequency for bin %d: got:%v want:%v", bin, stft.Freq(bin), float64(bin)/n)
+	}
+
+	bins := n/2 + 1
+	mag := MagnitudeSpectrogram(nil, coeff)
+	if r, c := mag.Dims(); r != len(coeff) || c != bins {
+		t.Fatalf("unexpected spectrogram dimensions: got:%d×%d want:%d×%d", r, c, len(coeff), bins)
+	}
+	// The end frames include the reflected padding,
+	// so only check the interior frames.
+	for i := 1; i < len(coeff)-1; i++ {
+		if peak := floats.MaxIdx(mag.RawRowView(i)); peak != bin {
+			t.Errorf("unexpected peak bin in frame %d: got:%d want:%d", i, peak, bin)
+		}
+	}
+
+	var pow mat.Dense
+	PowerSpectrogram(&pow, coeff)
+	for i := 0; i < len(coeff); i++ {
+		for j := 0; j < bins; j++ {
+			m := mag.At(i, j)
+			if p := pow.At(i, j); math.Abs(p-m*m) > 1e-9*math.Max(1, p) {
+				t.Errorf("unexpected power at (%d, %d): got:%v want:%v", i, j, p, m*m)
+			}
+		}
+	}
+}
+
+func TestSTFTPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero length", fn: func() { NewSTFT(0, 1, nil, NoPadding) }},
+		{name: "zero hop", fn: func() { NewSTFT(4, 0, nil, NoPadding) }},
+		{name: "short dst", fn: func() { NewSTFT(4, 2, nil, NoPadding).Coefficients(make([][]complex128, 1), make([]float64, 8)) }},
+		{name: "short row", fn: func() {
+			NewSTFT(4, 4, nil, NoPadding).Coefficients([][]complex128{make([]complex128, 2)}, make([]float64, 4))
+		}},
+		{name: "short reflect", fn: func() { NewSTFT(8, 2, nil, ReflectPadding).Coefficients(nil, make([]float64, 4)) }},
+		{name: "short coeff", fn: func() { NewSTFT(4, 2, nil, NoPadding).Sequence(nil, [][]complex128{make([]complex128, 2)}) }},
+		{name: "ragged", fn: func() { PowerSpectrogram(nil, [][]complex128{make([]complex128, 2), make([]complex128, 3)}) }},
+		{name: "empty spectrogram", fn: func() { MagnitudeSpectrogram(nil, nil) }},
+		{name: "short spectrogram", fn: func() { MagnitudeSpectrogram(mat.NewDense(1, 3, nil), [][]complex128{make([]complex128, 2)}) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bessel provides the Bessel functions used by the Kaiser–Bessel
+// windows and kernels of the dsp packages.
+package bessel // import "gonum.org/v1/gonum/dsp/internal/bessel"
+
+// I0 returns the modified Bessel function of the first kind of order zero
+// evaluated at x, computed from its power series.
+func I0(x float64) float64 {
+	q := x * x / 4
+	sum, term := 1.0, 1.0
+	for k := 1.0; term > sum*1e-17; k++ {
+		term *= q / (k * k)
+		sum += term
+	}
+	return sum
+}
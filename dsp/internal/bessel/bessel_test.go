@@ -0,0 +1,31 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bessel
+
+import (
+	"math"
+	"testing"
+)
+
+func TestI0(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	for _, test := range []struct {
+		x, want float64
+	}{
+		{x: 0, want: 1},
+		{x: 0.5, want: 1.0634833707413236},
+		{x: 1, want: 1.2660658777520082},
+		{x: -1, want: 1.2660658777520082},
+		{x: 2, want: 2.2795853023360673},
+		{x: 10, want: 2815.716628466254},
+		{x: 30, want: 7.816722978239774e11},
+	} {
+		got := I0(test.x)
+		if math.Abs(got-test.want) > tol*test.want {
+			t.Errorf("unexpected value for I0(%v): got:%v want:%v", test.x, got, test.want)
+		}
+	}
+}
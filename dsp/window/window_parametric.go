@@ -4,7 +4,11 @@
 
 package window
 
-import "math"
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/internal/bessel"
+)
 
 // Gaussian can modify a sequence using the Gaussian window and return the
 // result.
@@ -120,6 +124,210 @@ func (t Tukey) TransformComplex(seq []complex128) []complex128 {
 	}
 }
 
+// Kaiser can modify a sequence using the Kaiser window and return the result.
+// See https://en.wikipedia.org/wiki/Kaiser_window for details.
+//
+// The Kaiser window is an adjustable window.
+//
+// The sequence weights are
+//  w[k] = I_0(β * sqrt(1 - (2*k/(N-1) - 1)²)) / I_0(β),
+// for k=0,1,...,N-1 where N is the length of the window and I_0 is the
+// modified Bessel function of the first kind of order zero.
+//
+// The value of β (Beta) trades the width of the main lobe against the level
+// of the side lobes. A β of zero gives the rectangular window and a β of
+// about 5 approximates the Hamming window. KaiserBeta returns the β giving
+// a required side lobe attenuation.
+type Kaiser struct {
+	Beta float64
+}
+
+// Transform applies the Kaiser transformation to seq in place, using the
+// value of the receiver as the β parameter, and returning the result.
+func (k Kaiser) Transform(seq []float64) []float64 {
+	if len(seq) < 2 {
+		return seq
+	}
+	a := float64(len(seq)-1) / 2
+	norm := bessel.I0(k.Beta)
+	for i := range seq {
+		x := (float64(i) - a) / a
+		seq[i] *= bessel.I0(k.Beta*math.Sqrt(math.Max(0, 1-x*x))) / norm
+	}
+	return seq
+}
+
+// TransformComplex applies the Kaiser transformation to seq in place, using
+// the value of the receiver as the β parameter, and returning the result.
+func (k Kaiser) TransformComplex(seq []complex128) []complex128 {
+	if len(seq) < 2 {
+		return seq
+	}
+	a := float64(len(seq)-1) / 2
+	norm := bessel.I0(k.Beta)
+	for i, v := range seq {
+		x := (float64(i) - a) / a
+		w := bessel.I0(k.Beta*math.Sqrt(math.Max(0, 1-x*x))) / norm
+		seq[i] = complex(w*real(v), w*imag(v))
+	}
+	return seq
+}
+
+// KaiserBeta returns the Kaiser window β parameter giving a side lobe
+// attenuation in a filter designed by the window method of at least
+// attenuation decibels, using the empirical formula of Kaiser,
+//  β = 0.1102*(A - 8.7),                          A > 50,
+//    = 0.5842*(A - 21)^0.4 + 0.07886*(A - 21),   21 ≤ A ≤ 50,
+//    = 0,                                         A < 21.
+// The attenuation is given as a positive value.
+func KaiserBeta(attenuation float64) float64 {
+	a := attenuation
+	switch {
+	case a > 50:
+		return 0.1102 * (a - 8.7)
+	case a >= 21:
+		return 0.5842*math.Pow(a-21, 0.4) + 0.07886*(a-21)
+	default:
+		return 0
+	}
+}
+
+// KaiserDesign returns the length and β parameter of a Kaiser window giving
+// a filter designed by the window method with a side lobe attenuation of at
+// least attenuation decibels and a transition band of the given width. The
+// transition width is a relative frequency in cycles per sample, in the
+// same units as returned by the Freq method of the FFT types in the
+// gonum.org/v1/gonum/dsp/fourier package. The length is estimated using
+// Kaiser's formula,
+//  N = (A - 7.95) / (2.285 * 2π * Δf) + 1.
+// KaiserDesign will panic if the transition width is not in (0, 0.5].
+func KaiserDesign(attenuation, transition float64) (n int, beta float64) {
+	if !(0 < transition && transition <= 0.5) {
+		panic("window: transition width out of range")
+	}
+	n = int(math.Ceil((attenuation-7.95)/(2.285*2*math.Pi*transition))) + 1
+	if n < 1 {
+		n = 1
+	}
+	return n, KaiserBeta(attenuation)
+}
+
+// DolphChebyshev can modify a sequence using the Dolph-Chebyshev window and
+// return the result.
+// See https://en.wikipedia.org/wiki/Window_function#Dolph%E2%80%93Chebyshev_window
+// for details.
+//
+// The Dolph-Chebyshev window is an adjustable window.
+//
+// The window is defined by its discrete Fourier transform,
+//  W[k] = T_{N-1}(x_0 * cos(π*k/N)), x_0 = cosh(acosh(10^(A/20)) / (N-1)),
+// for k=0,1,...,N-1 where N is the length of the window, T_{N-1} is the
+// Chebyshev polynomial of the first kind of degree N-1, and A is the side
+// lobe attenuation in decibels. The weights are normalized to have a maximum
+// of one.
+//
+// For a given length the Dolph-Chebyshev window has the narrowest main lobe
+// for a given side lobe level, and all its side lobes have equal height.
+// DolphChebyshevLength returns the length giving a required main lobe width.
+type DolphChebyshev struct {
+	// Attenuation is the side lobe attenuation
+	// in decibels, given as a positive value.
+	Attenuation float64
+}
+
+// Transform applies the Dolph-Chebyshev transformation to seq in place,
+// using the value of the receiver as the side lobe attenuation, and
+// returning the result.
+func (d DolphChebyshev) Transform(seq []float64) []float64 {
+	for i, w := range d.weights(len(seq)) {
+		seq[i] *= w
+	}
+	return seq
+}
+
+// TransformComplex applies the Dolph-Chebyshev transformation to seq in
+// place, using the value of the receiver as the side lobe attenuation, and
+// returning the result.
+func (d DolphChebyshev) TransformComplex(seq []complex128) []complex128 {
+	for i, w := range d.weights(len(seq)) {
+		v := seq[i]
+		seq[i] = complex(w*real(v), w*imag(v))
+	}
+	return seq
+}
+
+// weights returns the Dolph-Chebyshev window weights for a window of
+// length n, computed by a direct inverse discrete Fourier transform of
+// the window's spectrum.
+func (d DolphChebyshev) weights(n int) []float64 {
+	w := make([]float64, n)
+	if n < 2 {
+		for i := range w {
+			w[i] = 1
+		}
+		return w
+	}
+	m := float64(n - 1)
+	x0 := math.Cosh(math.Acosh(math.Pow(10, d.Attenuation/20)) / m)
+	spec := make([]float64, n)
+	for k := range spec {
+		spec[k] = chebyshev(m, x0*math.Cos(math.Pi*float64(k)/float64(n)))
+	}
+
+	// The window is symmetric about (N-1)/2, so its transform is
+	// spec[k]*exp(-iπk(N-1)/N) and the inverse transform is real.
+	var max float64
+	for j := range w {
+		var sum float64
+		for k, s := range spec {
+			sum += s * math.Cos(math.Pi*float64(k)*(2*float64(j)-m)/float64(n))
+		}
+		w[j] = sum
+		if sum > max {
+			max = sum
+		}
+	}
+	for j := range w {
+		w[j] /= max
+	}
+	return w
+}
+
+// chebyshev returns the Chebyshev polynomial of the first kind of degree m
+// evaluated at x.
+func chebyshev(m, x float64) float64 {
+	switch {
+	case x > 1:
+		return math.Cosh(m * math.Acosh(x))
+	case x < -1:
+		s := 1.0
+		if math.Mod(m, 2) != 0 {
+			s = -1
+		}
+		return s * math.Cosh(m*math.Acosh(-x))
+	default:
+		return math.Cos(m * math.Acos(x))
+	}
+}
+
+// DolphChebyshevLength returns the shortest length of a Dolph-Chebyshev
+// window with a side lobe attenuation of attenuation decibels and a main
+// lobe, measured between its first nulls, no wider than width. The width
+// is a relative frequency in cycles per sample, in the same units as
+// returned by the Freq method of the FFT types in the
+// gonum.org/v1/gonum/dsp/fourier package. DolphChebyshevLength will panic
+// if the width is not in (0, 1).
+func DolphChebyshevLength(attenuation, width float64) int {
+	if !(0 < width && width < 1) {
+		panic("window: main lobe width out of range")
+	}
+	// The first null of the main lobe is at the frequency f
+	// where x_0 * cos(π*f) = 1.
+	x0 := 1 / math.Cos(math.Pi*width/2)
+	m := math.Acosh(math.Pow(10, attenuation/20)) / math.Acosh(x0)
+	return int(math.Ceil(m)) + 1
+}
+
 // Values is an arbitrary real window function.
 type Values []float64
 
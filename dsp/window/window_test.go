@@ -5,6 +5,7 @@
 package window
 
 import (
+	"math"
 	"testing"
 
 	"gonum.org/v1/gonum/floats"
@@ -152,6 +153,43 @@ var windowTests = []struct {
 			1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 0.939737, 0.700847, 0.377257, 0.105429, 0.000000,
 		},
 	},
+	{
+		name: "Kaiser{0}.Transform", fn: Kaiser{0}.Transform, fnCmplx: Kaiser{0}.TransformComplex,
+		want: []float64{ // Rectangular window case.
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		},
+	},
+	{
+		name: "Kaiser{5}.Transform", fn: Kaiser{5}.Transform, fnCmplx: Kaiser{5}.TransformComplex,
+		want: []float64{
+			0.036711, 0.098870, 0.189498, 0.306526, 0.443411, 0.589551, 0.731474, 0.854639, 0.945551, 0.993829,
+			0.993829, 0.945551, 0.854639, 0.731474, 0.589551, 0.443411, 0.306526, 0.189498, 0.098870, 0.036711,
+		},
+	},
+	{
+		name: "Kaiser{8.6}.TransformOdd", fn: Kaiser{8.6}.Transform, fnCmplx: Kaiser{8.6}.TransformComplex,
+		want: []float64{
+			0.001333, 0.012137, 0.041887, 0.101964, 0.201055, 0.340394, 0.510250, 0.689535, 0.849416, 0.960298,
+			1.000000,
+			0.960298, 0.849416, 0.689535, 0.510250, 0.340394, 0.201055, 0.101964, 0.041887, 0.012137, 0.001333,
+		},
+	},
+	{
+		name: "DolphChebyshev{50}.Transform", fn: DolphChebyshev{50}.Transform, fnCmplx: DolphChebyshev{50}.TransformComplex,
+		want: []float64{
+			0.047443, 0.096382, 0.178824, 0.289526, 0.423784, 0.571706, 0.719161, 0.849767, 0.947593, 1.000000,
+			1.000000, 0.947593, 0.849767, 0.719161, 0.571706, 0.423784, 0.289526, 0.178824, 0.096382, 0.047443,
+		},
+	},
+	{
+		name: "DolphChebyshev{80}.TransformOdd", fn: DolphChebyshev{80}.Transform, fnCmplx: DolphChebyshev{80}.TransformComplex,
+		want: []float64{
+			0.004554, 0.019132, 0.053291, 0.117196, 0.218355, 0.357284, 0.524298, 0.699130, 0.854291, 0.961616,
+			1.000000,
+			0.961616, 0.854291, 0.699130, 0.524298, 0.357284, 0.218355, 0.117196, 0.053291, 0.019132, 0.004554,
+		},
+	},
 }
 
 func TestWindows(t *testing.T) {
@@ -246,3 +284,111 @@ func equalApprox(seq1 []complex128, seq2 []float64, tol float64) bool {
 	}
 	return true
 }
+
+func TestKaiserBeta(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		attenuation float64
+		want        float64
+	}{
+		{attenuation: 10, want: 0},
+		{attenuation: 21, want: 0},
+		{attenuation: 30, want: 0.5842*math.Pow(9, 0.4) + 0.07886*9},
+		{attenuation: 50, want: 0.5842*math.Pow(29, 0.4) + 0.07886*29},
+		{attenuation: 60, want: 5.65326},
+	} {
+		got := KaiserBeta(test.attenuation)
+		if !scalar.EqualWithinAbsOrRel(got, test.want, 1e-12, 1e-12) {
+			t.Errorf("unexpected beta for attenuation %v: got:%v want:%v", test.attenuation, got, test.want)
+		}
+	}
+}
+
+func TestKaiserDesign(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		attenuation, transition float64
+		wantN                   int
+	}{
+		// (60-7.95)/(2.285*2π*0.05)+1 = 73.5
+		{attenuation: 60, transition: 0.05, wantN: 74},
+		{attenuation: 40, transition: 0.1, wantN: 24},
+		{attenuation: 5, transition: 0.5, wantN: 1},
+	} {
+		n, beta := KaiserDesign(test.attenuation, test.transition)
+		if n != test.wantN {
+			t.Errorf("unexpected length for attenuation %v transition %v: got:%d want:%d",
+				test.attenuation, test.transition, n, test.wantN)
+		}
+		if beta != KaiserBeta(test.attenuation) {
+			t.Errorf("unexpected beta for attenuation %v: got:%v want:%v",
+				test.attenuation, beta, KaiserBeta(test.attenuation))
+		}
+	}
+}
+
+func TestDolphChebyshevSideLobes(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		n           int
+		attenuation float64
+	}{
+		{n: 20, attenuation: 50},
+		{n: 31, attenuation: 80},
+		{n: 64, attenuation: 100},
+	} {
+		w := NewValues(DolphChebyshev{test.attenuation}.Transform, test.n)
+
+		// The first null of the main lobe.
+		m := float64(test.n - 1)
+		x0 := math.Cosh(math.Acosh(math.Pow(10, test.attenuation/20)) / m)
+		null := math.Acos(1/x0) / math.Pi
+
+		peak := response(w, 0)
+		var worst float64
+		const samples = 2000
+		for i := 0; i <= samples; i++ {
+			f := null + (0.5-null)*float64(i)/samples
+			worst = math.Max(worst, response(w, f))
+		}
+		got := 20 * math.Log10(worst/peak)
+		if math.Abs(got+test.attenuation) > 0.1 {
+			t.Errorf("unexpected side lobe level for n=%d: got:%.2f dB want:%.2f dB", test.n, got, -test.attenuation)
+		}
+	}
+}
+
+func TestDolphChebyshevLength(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		attenuation, width float64
+	}{
+		{attenuation: 50, width: 0.1},
+		{attenuation: 80, width: 0.05},
+		{attenuation: 100, width: 0.2},
+	} {
+		n := DolphChebyshevLength(test.attenuation, test.width)
+		width := func(n int) float64 {
+			x0 := math.Cosh(math.Acosh(math.Pow(10, test.attenuation/20)) / float64(n-1))
+			return 2 * math.Acos(1/x0) / math.Pi
+		}
+		if width(n) > test.width {
+			t.Errorf("main lobe too wide for attenuation %v: got:%v want at most:%v", test.attenuation, width(n), test.width)
+		}
+		if width(n-1) <= test.width {
+			t.Errorf("length not minimal for attenuation %v width %v: n=%d", test.attenuation, test.width, n)
+		}
+	}
+}
+
+// response returns the magnitude of the discrete-time Fourier transform of
+// w at the relative frequency f.
+func response(w []float64, f float64) float64 {
+	var re, im float64
+	for k, v := range w {
+		s, c := math.Sincos(2 * math.Pi * f * float64(k))
+		re += v * c
+		im -= v * s
+	}
+	return math.Hypot(re, im)
+}
@@ -0,0 +1,227 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Leiden returns the hierarchical modularization of g at the given resolution
+// using the Leiden algorithm. If src is nil, rand.Intn is used as the random
+// generator. Leiden will panic if g has any edge with negative edge weight.
+//
+// The graph is modularised to minimise
+//  Q = 1/2m \sum_{ij} [ A_{ij} - (\gamma k_i k_j)/2m ] \delta(c_i,c_j),
+// as is done by Modularize for undirected graphs.
+//
+// The Leiden algorithm extends the Louvain algorithm with a refinement phase
+// between the local moving of nodes and the aggregation of communities. Each
+// community is split into well-connected sub-communities, which become the
+// nodes of the aggregate graph, while the community structure found by local
+// moving is retained as the initial partition of the aggregate graph. This
+// guarantees that the communities found are connected, which is not the case
+// for the Louvain algorithm. Nodes are merged into the sub-community giving
+// the greatest increase in modularity.
+//  Traag, V. A., Waltman, L. and van Eck, N. J. "From Louvain to Leiden:
+//  guaranteeing well-connected communities." Scientific Reports 9 (2019): 5233.
+//
+// The concrete type of the returned ReducedGraph is a *ReducedUndirected. The
+// levels of the hierarchy below the returned graph are the aggregations of
+// the refined sub-communities.
+//
+// graph.Undirect may be used as a shim to allow modularization of
+// directed graphs with the undirected modularity function.
+func Leiden(g graph.Undirected, resolution float64, src rand.Source) ReducedGraph {
+	c := reduceUndirected(g, nil)
+	rnd := rand.Intn
+	if src != nil {
+		rnd = rand.New(src).Intn
+	}
+	for {
+		l := newUndirectedLocalMover(c, c.communities, resolution)
+		if l == nil {
+			return c
+		}
+		if done := l.localMovingHeuristic(rnd); done {
+			c.communities = splitDisconnected(c)
+			return c
+		}
+
+		// Aggregate the refined communities and use the
+		// communities found by local moving as the initial
+		// partition of the aggregate graph.
+		r := reduceUndirected(c, l.refine(rnd))
+		index := make(map[int]int)
+		r.communities = r.communities[:0]
+		for i, n := range r.nodes {
+			p := l.memberships[n.nodes[0].ID()]
+			k, ok := index[p]
+			if !ok {
+				k = len(r.communities)
+				index[p] = k
+				r.communities = append(r.communities, nil)
+			}
+			r.communities[k] = append(r.communities[k], node(i))
+		}
+		c = r
+	}
+}
+
+// refine returns the Leiden refinement of the communities of the
+// undirectedLocalMover. Each returned community is a subset of one of the
+// local mover's communities and is well-connected within it.
+func (l *undirectedLocalMover) refine(rnd func(int) int) [][]graph.Node {
+	n := len(l.nodes)
+	gamma := l.resolution
+	m2 := l.m2
+
+	// refined holds the refined community of each node, and
+	// weightOf and external hold the total degree weight and the
+	// weight of edges to the rest of the enclosing community
+	// of each refined community.
+	refined := make([]int, n)
+	size := make([]int, n)
+	weightOf := make([]float64, n)
+	external := make([]float64, n)
+	for i := range refined {
+		refined[i] = i
+		size[i] = 1
+		weightOf[i] = l.edgeWeightOf[i]
+	}
+
+	connected := make(map[int]float64)
+	var candidates []int
+	for _, comm := range l.communities {
+		if len(comm) < 2 {
+			continue
+		}
+		var sigma float64
+		for _, u := range comm {
+			uid := u.ID()
+			sigma += l.edgeWeightOf[uid]
+			for _, vid := range l.g.edges[uid] {
+				// The weight of a self loop is internal to
+				// the node, not to the rest of the community.
+				if int64(vid) != uid && l.memberships[vid] == l.memberships[uid] {
+					external[uid] += l.weight(uid, int64(vid))
+				}
+			}
+		}
+
+		order := make([]graph.Node, len(comm))
+		copy(order, comm)
+		for i := range order[:len(order)-1] {
+			j := i + rnd(len(order)-i)
+			order[i], order[j] = order[j], order[i]
+		}
+		for _, u := range order {
+			uid := u.ID()
+			k := l.edgeWeightOf[uid]
+			// Only singleton nodes that are well-connected
+			// within the community may be merged.
+			if size[refined[uid]] != 1 || external[uid] < gamma*k*(sigma-k)/m2 {
+				continue
+			}
+
+			for _, s := range candidates {
+				delete(connected, s)
+			}
+			candidates = candidates[:0]
+			for _, vid := range l.g.edges[uid] {
+				if l.memberships[vid] != l.memberships[uid] {
+					continue
+				}
+				s := refined[vid]
+				if _, ok := connected[s]; !ok {
+					candidates = append(candidates, s)
+				}
+				connected[s] += l.weight(uid, int64(vid))
+			}
+
+			// Find the well-connected refined community
+			// with the greatest gain in modularity.
+			dst := -1
+			var best float64
+			for _, s := range candidates {
+				if s == refined[uid] || external[s] < gamma*weightOf[s]*(sigma-weightOf[s])/m2 {
+					continue
+				}
+				dQ := connected[s] - gamma*k*weightOf[s]/m2
+				if dQ > best+deltaQtol {
+					dst = s
+					best = dQ
+				}
+			}
+			if dst == -1 {
+				continue
+			}
+
+			src := refined[uid]
+			external[dst] += external[src] - 2*connected[dst]
+			weightOf[dst] += weightOf[src]
+			size[dst]++
+			size[src] = 0
+			refined[uid] = dst
+		}
+	}
+
+	index := make([]int, n)
+	for i := range index {
+		index[i] = -1
+	}
+	var communities [][]graph.Node
+	for i, s := range refined {
+		if index[s] == -1 {
+			index[s] = len(communities)
+			communities = append(communities, nil)
+		}
+		communities[index[s]] = append(communities[index[s]], node(i))
+	}
+	return communities
+}
+
+// splitDisconnected returns the communities of g with each community
+// split into its connected components. Moving nodes out of a community
+// during local moving may leave it disconnected, and splitting it does
+// not decrease modularity.
+func splitDisconnected(g *ReducedUndirected) [][]graph.Node {
+	membership := make([]int, len(g.nodes))
+	for i, comm := range g.communities {
+		for _, n := range comm {
+			membership[n.ID()] = i
+		}
+	}
+	seen := make([]bool, len(g.nodes))
+	var (
+		communities [][]graph.Node
+		stack       []int
+	)
+	for _, comm := range g.communities {
+		for _, n := range comm {
+			uid := int(n.ID())
+			if seen[uid] {
+				continue
+			}
+			seen[uid] = true
+			var component []graph.Node
+			stack = append(stack[:0], uid)
+			for len(stack) != 0 {
+				u := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				component = append(component, node(u))
+				for _, v := range g.edges[u] {
+					if !seen[v] && membership[v] == membership[u] {
+						seen[v] = true
+						stack = append(stack, v)
+					}
+				}
+			}
+			communities = append(communities, component)
+		}
+	}
+	return communities
+}
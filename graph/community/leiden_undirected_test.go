@@ -0,0 +1,117 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestLeiden(t *testing.T) {
+	for _, test := range communityUndirectedQTests {
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+
+		const leidenIterations = 20
+		src := rand.New(rand.NewSource(1))
+		bestQ := math.Inf(-1)
+		for i := 0; i < leidenIterations; i++ {
+			r := Leiden(g, 1, src)
+			communities := r.Communities()
+			testLeidenCommunities(t, test.name, g, communities)
+			q := Q(g, communities, 1)
+			if math.IsNaN(q) {
+				// The graph has no edges.
+				break
+			}
+			bestQ = math.Max(bestQ, q)
+		}
+		if math.IsInf(bestQ, -1) {
+			continue
+		}
+		want := test.structures[0].want
+		if bestQ < want-test.structures[0].tol {
+			t.Errorf("unexpected best Q for %s: got %.4v, want at least %.4v", test.name, bestQ, want)
+		}
+	}
+}
+
+func TestLeidenDuplication(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, resolution := range []float64{0.5, 1, 2} {
+		r := Leiden(dupGraph, resolution, src)
+		communities := r.Communities()
+		testLeidenCommunities(t, "duplication", dupGraph, communities)
+
+		var levels int
+		for p := r.(*ReducedUndirected); p != nil; p = p.Expanded().(*ReducedUndirected) {
+			levels++
+			if p.parent == nil {
+				if got := len(graph.NodesOf(p.Nodes())); got != dupGraph.Nodes().Len() {
+					t.Errorf("unexpected number of nodes at lowest level: got %d, want %d", got, dupGraph.Nodes().Len())
+				}
+			}
+		}
+		if levels < 2 {
+			t.Errorf("expected hierarchical result for resolution %v", resolution)
+		}
+
+		q := Q(dupGraph, communities, resolution)
+		qLouvain := Q(dupGraph, Modularize(dupGraph, resolution, src).Communities(), resolution)
+		if q < qLouvain-0.02 {
+			t.Errorf("unexpectedly poor modularity for resolution %v: got %.4v, Louvain %.4v", resolution, q, qLouvain)
+		}
+	}
+}
+
+// testLeidenCommunities checks that communities is a partition of the nodes
+// of g into communities that are connected in g.
+func testLeidenCommunities(t *testing.T, name string, g graph.Undirected, communities [][]graph.Node) {
+	t.Helper()
+	seen := make(map[int64]bool)
+	for _, c := range communities {
+		in := make(map[int64]bool, len(c))
+		for _, n := range c {
+			if seen[n.ID()] {
+				t.Errorf("node %d in more than one community for %s", n.ID(), name)
+			}
+			seen[n.ID()] = true
+			in[n.ID()] = true
+		}
+		sub := simple.NewUndirectedGraph()
+		for _, n := range c {
+			sub.AddNode(n)
+		}
+		for _, u := range c {
+			to := g.From(u.ID())
+			for to.Next() {
+				v := to.Node()
+				if in[v.ID()] && u.ID() != v.ID() {
+					sub.SetEdge(simple.Edge{F: u, T: v})
+				}
+			}
+		}
+		if cc := topo.ConnectedComponents(sub); len(cc) != 1 {
+			t.Errorf("community not connected for %s: %v", name, cc)
+		}
+	}
+	if len(seen) != g.Nodes().Len() {
+		t.Errorf("communities do not cover the graph for %s: got %d nodes, want %d", name, len(seen), g.Nodes().Len())
+	}
+}
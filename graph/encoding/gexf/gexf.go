@@ -0,0 +1,287 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gexf implements GEXF 1.2 marshaling and unmarshaling of graphs.
+//
+// GEXF is the native graph format of Gephi and is read by sigma.js. The
+// GEXF document types are provided by the gonum/graph/formats/gexf12
+// package.
+//
+// Attributes
+//
+// Node and edge attributes are mapped to GEXF attribute values using the
+// encoding.Attributer and encoding.AttributeSetter interfaces. Attribute keys
+// are declared as GEXF string attributes with the attribute name as the
+// title. The "label" attribute of nodes and edges is mapped to the GEXF label
+// of the element. During unmarshaling, default values declared for an
+// attribute are set on nodes and edges that do not hold a value for it.
+//
+// Edge weights of graph.WeightedEdge values are marshaled as GEXF edge
+// weights and are unmarshaled into edges created by a graph.WeightedBuilder.
+// Since gexf12 does not distinguish a zero weight from an absent weight,
+// edges with zero weight are unmarshaled as unweighted edges.
+//
+// Graph attributes, hierarchical graphs, dynamics and visualization data
+// are not supported.
+package gexf // import "gonum.org/v1/gonum/graph/encoding/gexf"
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// labelKey is the attribute name mapped to GEXF labels.
+const labelKey = "label"
+
+// Node is a GEXF node with a GEXF ID.
+type Node interface {
+	// GEXFID returns the GEXF ID of the node.
+	GEXFID() string
+}
+
+// IDSetter is implemented by types that can set a GEXF ID.
+type IDSetter interface {
+	SetGEXFID(id string)
+}
+
+// Marshal returns the GEXF encoding for the graph g, applying the prefix and
+// indent to the encoding. Nodes are identified by their GEXFID if they
+// implement Node, and by their integer ID otherwise.
+//
+// Node and edge attributes are marshaled if the nodes or edges of g implement
+// encoding.Attributer. Edges that implement graph.WeightedEdge have their
+// weight marshaled as the GEXF edge weight.
+func Marshal(g graph.Graph, prefix, indent string) ([]byte, error) {
+	_, directed := g.(graph.Directed)
+	dst := gexf12.Graph{DefaultEdgeType: "undirected"}
+	if directed {
+		dst.DefaultEdgeType = "directed"
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	ids := make(map[int64]string, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	nodeAttrs := newAttributes("node")
+	for _, n := range nodes {
+		id := nodeID(n)
+		if seen[id] {
+			return nil, fmt.Errorf("gexf: duplicate node ID %q", id)
+		}
+		seen[id] = true
+		ids[n.ID()] = id
+		dn := gexf12.Node{ID: id}
+		if a, ok := n.(encoding.Attributer); ok {
+			dn.Label, dn.AttValues = nodeAttrs.values(a.Attributes())
+		}
+		dst.Nodes.Nodes = append(dst.Nodes.Nodes, dn)
+	}
+	dst.Nodes.Count = len(dst.Nodes.Nodes)
+
+	edgeAttrs := newAttributes("edge")
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		ordered.ByID(to)
+		for _, t := range to {
+			vid := t.ID()
+			if !directed && vid < uid {
+				// Undirected edges are only
+				// marshaled once.
+				continue
+			}
+			e := g.Edge(uid, vid)
+			de := gexf12.Edge{
+				ID:     strconv.Itoa(len(dst.Edges.Edges)),
+				Source: ids[uid],
+				Target: ids[vid],
+			}
+			if w, ok := e.(graph.WeightedEdge); ok {
+				de.Weight = w.Weight()
+			}
+			if a, ok := e.(encoding.Attributer); ok {
+				de.Label, de.AttValues = edgeAttrs.values(a.Attributes())
+			}
+			dst.Edges.Edges = append(dst.Edges.Edges, de)
+		}
+	}
+	dst.Edges.Count = len(dst.Edges.Edges)
+
+	for _, a := range []*attributes{nodeAttrs, edgeAttrs} {
+		if len(a.decl.Attributes) != 0 {
+			dst.Attributes = append(dst.Attributes, a.decl)
+		}
+	}
+
+	b, err := xml.MarshalIndent(gexf12.Content{Graph: dst, Version: "1.2"}, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// nodeID returns the GEXF ID of n.
+func nodeID(n graph.Node) string {
+	if n, ok := n.(Node); ok {
+		return n.GEXFID()
+	}
+	return fmt.Sprint(n.ID())
+}
+
+// attributes holds the GEXF attribute declarations for a class of element.
+type attributes struct {
+	decl gexf12.Attributes
+	ids  map[string]string
+}
+
+func newAttributes(class string) *attributes {
+	return &attributes{
+		decl: gexf12.Attributes{Class: class},
+		ids:  make(map[string]string),
+	}
+}
+
+// values returns the GEXF label and attribute values for attrs, declaring
+// attributes for names that have not been seen before.
+func (a *attributes) values(attrs []encoding.Attribute) (label string, values *gexf12.AttValues) {
+	for _, attr := range attrs {
+		if attr.Key == labelKey {
+			label = attr.Value
+			continue
+		}
+		id, ok := a.ids[attr.Key]
+		if !ok {
+			id = strconv.Itoa(len(a.ids))
+			a.ids[attr.Key] = id
+			a.decl.Attributes = append(a.decl.Attributes, gexf12.Attribute{ID: id, Title: attr.Key, Type: "string"})
+		}
+		if values == nil {
+			values = &gexf12.AttValues{}
+		}
+		values.AttValues = append(values.AttValues, gexf12.AttValue{For: id, Value: attr.Value})
+	}
+	return label, values
+}
+
+// Unmarshal parses the GEXF-encoded data and stores the result in dst.
+//
+// Nodes are created with dst.NewNode and have their GEXF ID set if they
+// implement IDSetter. Node and edge labels and attributes are set on nodes
+// and edges that implement encoding.AttributeSetter. If dst implements
+// graph.WeightedBuilder, edges with a non-zero weight are created with that
+// weight.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	var content gexf12.Content
+	err := xml.Unmarshal(data, &content)
+	if err != nil {
+		return err
+	}
+	src := content.Graph
+
+	titles := make(map[string]map[string]string)
+	defaults := make(map[string][]encoding.Attribute)
+	for _, decl := range src.Attributes {
+		if titles[decl.Class] == nil {
+			titles[decl.Class] = make(map[string]string)
+		}
+		for _, a := range decl.Attributes {
+			titles[decl.Class][a.ID] = a.Title
+			if a.Default != "" {
+				defaults[decl.Class] = append(defaults[decl.Class], encoding.Attribute{Key: a.Title, Value: a.Default})
+			}
+		}
+	}
+
+	nodes := make(map[string]graph.Node, len(src.Nodes.Nodes))
+	for _, sn := range src.Nodes.Nodes {
+		if sn.Nodes != nil || sn.Edges != nil || sn.ParentID != "" || sn.Parents != nil {
+			return errors.New("gexf: hierarchical graphs not supported")
+		}
+		if _, ok := nodes[sn.ID]; ok {
+			return fmt.Errorf("gexf: duplicate node ID %q", sn.ID)
+		}
+		n := dst.NewNode()
+		if s, ok := n.(IDSetter); ok {
+			s.SetGEXFID(sn.ID)
+		}
+		dst.AddNode(n)
+		nodes[sn.ID] = n
+		if s, ok := n.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, sn.Label, sn.AttValues, titles["node"], defaults["node"])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	wb, weighted := dst.(graph.WeightedBuilder)
+	for _, se := range src.Edges.Edges {
+		u, ok := nodes[se.Source]
+		if !ok {
+			return fmt.Errorf("gexf: edge source %q not declared", se.Source)
+		}
+		v, ok := nodes[se.Target]
+		if !ok {
+			return fmt.Errorf("gexf: edge target %q not declared", se.Target)
+		}
+		var e graph.Edge
+		if weighted && se.Weight != 0 {
+			we := wb.NewWeightedEdge(u, v, se.Weight)
+			wb.SetWeightedEdge(we)
+			e = we
+		} else {
+			e = dst.NewEdge(u, v)
+			dst.SetEdge(e)
+		}
+		if s, ok := e.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, se.Label, se.AttValues, titles["edge"], defaults["edge"])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setAttributes sets the label and attribute values on dst using the
+// attribute titles in titles. Default attribute values that are not held
+// in values are also set.
+func setAttributes(dst encoding.AttributeSetter, label string, values *gexf12.AttValues, titles map[string]string, defaults []encoding.Attribute) error {
+	if label != "" {
+		err := dst.SetAttribute(encoding.Attribute{Key: labelKey, Value: label})
+		if err != nil {
+			return err
+		}
+	}
+	set := make(map[string]bool)
+	if values != nil {
+		for _, v := range values.AttValues {
+			title, ok := titles[v.For]
+			if !ok {
+				return fmt.Errorf("gexf: undeclared attribute %q", v.For)
+			}
+			set[title] = true
+			err := dst.SetAttribute(encoding.Attribute{Key: title, Value: v.Value})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, a := range defaults {
+		if !set[a.Key] {
+			err := dst.SetAttribute(a)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
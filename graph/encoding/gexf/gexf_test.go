@@ -0,0 +1,181 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gexf
+
+import (
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+	g.AddNode(simple.Node(3))
+
+	got, err := Marshal(g, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+	<graph defaultedgetype="undirected">
+		<nodes count="4">
+			<node id="0"></node>
+			<node id="1"></node>
+			<node id="2"></node>
+			<node id="3"></node>
+		</nodes>
+		<edges count="2">
+			<edge id="0" source="0" target="1"></edge>
+			<edge id="1" source="1" target="2"></edge>
+		</edges>
+	</graph>
+</gexf>`
+	if string(got) != want {
+		t.Errorf("unexpected marshaled graph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := attrgraph.NewDirected()
+	var nodes []*attrgraph.Node
+	for i, name := range []string{"a", "b", "c"} {
+		n := g.NewNode().(*attrgraph.Node)
+		n.Name = name
+		n.Attrs = encoding.Attributes{{Key: "label", Value: strings.ToUpper(name)}}
+		if i == 1 {
+			n.Attrs = append(n.Attrs, encoding.Attribute{Key: "color", Value: "red"})
+		}
+		g.AddNode(n)
+		nodes = append(nodes, n)
+	}
+	e := g.NewWeightedEdge(nodes[0], nodes[1], 0.5).(*attrgraph.Edge)
+	e.Attrs = encoding.Attributes{{Key: "kind", Value: "<friend> & co"}, {Key: "label", Value: "knows"}}
+	g.SetWeightedEdge(e)
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[1], nodes[2], 2))
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[2], nodes[0], 3))
+
+	want, err := Marshal(g, "", " ")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	for _, s := range []string{`label="B"`, `title="color"`, `label="knows"`, `weight="0.5"`, "&lt;friend&gt; &amp; co"} {
+		if !strings.Contains(string(want), s) {
+			t.Errorf("missing %s in marshaled graph:\n%s", s, want)
+		}
+	}
+	dst := attrgraph.NewDirected()
+	err = Unmarshal(want, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling graph: %v", err)
+	}
+	got, err := Marshal(dst, "", " ")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("unexpected round trip result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// gephi is an example of GEXF in the style written by Gephi.
+const gephi = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" xmlns:viz="http://www.gexf.net/1.2draft/viz" version="1.2">
+  <meta lastmodifieddate="2022-01-02">
+    <creator>Gephi 0.9</creator>
+  </meta>
+  <graph mode="static" defaultedgetype="undirected">
+    <attributes class="node">
+      <attribute id="0" title="color" type="string">
+        <default>blue</default>
+      </attribute>
+    </attributes>
+    <nodes>
+      <node id="x" label="X">
+        <attvalues>
+          <attvalue for="0" value="green"></attvalue>
+        </attvalues>
+        <viz:size value="10.0"></viz:size>
+      </node>
+      <node id="y" label="Y"></node>
+      <node id="z"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="x" target="y" weight="1.5"></edge>
+      <edge id="1" source="y" target="z"></edge>
+    </edges>
+  </graph>
+</gexf>`
+
+func TestUnmarshalGephi(t *testing.T) {
+	dst := attrgraph.NewUndirected()
+	err := Unmarshal([]byte(gephi), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantNode := map[string]encoding.Attributes{
+		"x": {{Key: "label", Value: "X"}, {Key: "color", Value: "green"}},
+		"y": {{Key: "label", Value: "Y"}, {Key: "color", Value: "blue"}},
+		"z": {{Key: "color", Value: "blue"}},
+	}
+	ids := make(map[string]int64)
+	for _, n := range graph.NodesOf(dst.Nodes()) {
+		an := n.(*attrgraph.Node)
+		ids[an.Name] = an.ID()
+		if !attrgraph.EqualAttributes(an.Attrs, wantNode[an.Name]) {
+			t.Errorf("unexpected attributes for node %q: got %v, want %v", an.Name, an.Attrs, wantNode[an.Name])
+		}
+	}
+	if len(ids) != 3 {
+		t.Errorf("unexpected number of nodes: got %d, want 3", len(ids))
+	}
+	if w, ok := dst.Weight(ids["y"], ids["x"]); !ok || w != 1.5 {
+		t.Errorf("unexpected weight for edge x-y: got %v, want 1.5", w)
+	}
+	if !dst.HasEdgeBetween(ids["y"], ids["z"]) {
+		t.Error("missing edge y-z")
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{
+		name: "wrong namespace",
+		data: `<gexf xmlns="http://graphml.graphdrawing.org/xmlns" version="1.2"><graph/></gexf>`,
+	},
+	{
+		name: "undeclared attribute",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"><attvalues><attvalue for="0" value="v"/></attvalues></node></nodes></graph></gexf>`,
+	},
+	{
+		name: "undeclared node",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"/></nodes><edges><edge source="a" target="b"/></edges></graph></gexf>`,
+	},
+	{
+		name: "duplicate node",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"/><node id="a"/></nodes></graph></gexf>`,
+	},
+	{
+		name: "hierarchy",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"><nodes><node id="b"/></nodes></node></nodes></graph></gexf>`,
+	},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := Unmarshal([]byte(test.data), attrgraph.NewDirected())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
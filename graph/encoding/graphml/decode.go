@@ -0,0 +1,196 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// Unmarshal parses the GraphML-encoded data and stores the result in dst.
+// If the number of graphs encoded in data is not one, an error is returned and
+// dst will hold the first graph in data.
+//
+// Nodes are created with dst.NewNode and have their GraphML ID set if they
+// implement IDSetter. Graph, node and edge attributes are set on dst, nodes
+// and edges that implement encoding.AttributeSetter. If dst implements
+// graph.WeightedBuilder, edges with a "weight" attribute are created with
+// that weight, and the attribute is not otherwise set on the edge.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	var doc document
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	if len(doc.Graphs) == 0 {
+		return errors.New("graphml: no graph")
+	}
+	err = copyGraph(dst, doc.Keys, doc.Graphs[0])
+	if err == nil && len(doc.Graphs) != 1 {
+		err = fmt.Errorf("graphml: invalid number of graphs; expected 1, got %d", len(doc.Graphs))
+	}
+	return err
+}
+
+// copyGraph copies the nodes and edges from the GraphML source graph to the
+// destination graph.
+func copyGraph(dst encoding.Builder, keys []keyElement, src graphElement) error {
+	if len(src.Hyperedges) != 0 {
+		return errors.New("graphml: hyperedges not supported")
+	}
+
+	names := make(map[string]string, len(keys))
+	for _, k := range keys {
+		name := k.Name
+		if name == "" {
+			name = k.ID
+		}
+		names[k.ID] = name
+	}
+	// defaults returns the attributes with default
+	// values that apply to the given domain.
+	defaults := func(domain string) []encoding.Attribute {
+		var attrs []encoding.Attribute
+		for _, k := range keys {
+			if k.Default != nil && (k.For == domain || k.For == "all") {
+				attrs = append(attrs, encoding.Attribute{Key: names[k.ID], Value: *k.Default})
+			}
+		}
+		return attrs
+	}
+	nodeDefaults := defaults("node")
+	edgeDefaults := defaults("edge")
+
+	if s, ok := dst.(encoding.AttributeSetter); ok {
+		attrs, err := attributes(names, nil, src.Data)
+		if err != nil {
+			return err
+		}
+		err = setAttributes(s, attrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	nodes := make(map[string]graph.Node, len(src.Nodes))
+	for _, sn := range src.Nodes {
+		if len(sn.Graph) != 0 {
+			return errors.New("graphml: nested graphs not supported")
+		}
+		if _, ok := nodes[sn.ID]; ok {
+			return fmt.Errorf("graphml: duplicate node ID %q", sn.ID)
+		}
+		n := dst.NewNode()
+		if s, ok := n.(IDSetter); ok {
+			s.SetGraphMLID(sn.ID)
+		}
+		dst.AddNode(n)
+		nodes[sn.ID] = n
+		if s, ok := n.(encoding.AttributeSetter); ok {
+			attrs, err := attributes(names, nodeDefaults, sn.Data)
+			if err != nil {
+				return err
+			}
+			err = setAttributes(s, attrs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	wb, weighted := dst.(graph.WeightedBuilder)
+	for _, se := range src.Edges {
+		u, ok := nodes[se.Source]
+		if !ok {
+			return fmt.Errorf("graphml: edge source %q not declared", se.Source)
+		}
+		v, ok := nodes[se.Target]
+		if !ok {
+			return fmt.Errorf("graphml: edge target %q not declared", se.Target)
+		}
+		attrs, err := attributes(names, edgeDefaults, se.Data)
+		if err != nil {
+			return err
+		}
+
+		var e graph.Edge
+		if weighted {
+			w, i, err := weight(attrs)
+			if err != nil {
+				return err
+			}
+			if i >= 0 {
+				attrs = append(attrs[:i:i], attrs[i+1:]...)
+				we := wb.NewWeightedEdge(u, v, w)
+				wb.SetWeightedEdge(we)
+				e = we
+			}
+		}
+		if e == nil {
+			e = dst.NewEdge(u, v)
+			dst.SetEdge(e)
+		}
+		if s, ok := e.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, attrs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// attributes returns the attributes described by the GraphML data elements
+// in d using the attribute names in names. Default values that are not
+// held in d are included.
+func attributes(names map[string]string, defaults []encoding.Attribute, d []dataElement) ([]encoding.Attribute, error) {
+	attrs := make([]encoding.Attribute, 0, len(defaults)+len(d))
+	set := make(map[string]bool, len(d))
+	for _, v := range d {
+		name, ok := names[v.Key]
+		if !ok {
+			return nil, fmt.Errorf("graphml: undeclared key %q", v.Key)
+		}
+		set[name] = true
+		attrs = append(attrs, encoding.Attribute{Key: name, Value: v.Value})
+	}
+	for _, a := range defaults {
+		if !set[a.Key] {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs, nil
+}
+
+// setAttributes sets the attributes in attrs on dst.
+func setAttributes(dst encoding.AttributeSetter, attrs []encoding.Attribute) error {
+	for _, a := range attrs {
+		err := dst.SetAttribute(a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// weight returns the value of the weight attribute in attrs and its index.
+// If there is no weight attribute, the returned index is -1.
+func weight(attrs []encoding.Attribute) (w float64, index int, err error) {
+	for i, a := range attrs {
+		if a.Key == weightKey {
+			w, err = strconv.ParseFloat(a.Value, 64)
+			if err != nil {
+				return 0, -1, fmt.Errorf("graphml: invalid edge weight: %w", err)
+			}
+			return w, i, nil
+		}
+	}
+	return 0, -1, nil
+}
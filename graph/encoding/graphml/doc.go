@@ -0,0 +1,27 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package graphml implements GraphML marshaling and unmarshaling of graphs.
+//
+// GraphML is an XML based format for graphs that is supported by many graph
+// tools including Gephi, NetworkX and yEd. See the GraphML primer for more
+// information on the format:
+//
+// GraphML primer: http://graphml.graphdrawing.org/primer/graphml-primer.html
+//
+// # Attributes
+//
+// Graph, node and edge attributes are mapped to GraphML data elements using
+// the encoding.Attributer and encoding.AttributeSetter interfaces. Attribute
+// keys are declared with the attribute name as the GraphML attr.name, and
+// attribute values are held as strings. During unmarshaling, keys without an
+// attr.name are identified by their key ID and default values declared for
+// a key are set on nodes and edges that do not hold a value for the key.
+//
+// Edge weights of graph.WeightedEdge values are marshaled as the "weight"
+// edge attribute unless the edge also has an attribute named "weight", and
+// are unmarshaled into edges created by a graph.WeightedBuilder.
+//
+// Nested graphs, hyperedges and ports are not supported.
+package graphml // import "gonum.org/v1/gonum/graph/encoding/graphml"
@@ -0,0 +1,162 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Marshal returns the GraphML encoding for the graph g, applying the prefix
+// and indent to the encoding. Name is used to specify the graph ID. Nodes
+// are identified by their GraphMLID if they implement Node, and by their
+// integer ID otherwise.
+//
+// Graph, node and edge attributes are marshaled if g, its nodes or its edges
+// implement encoding.Attributer. Edges that implement graph.WeightedEdge have
+// their weight marshaled as the "weight" attribute.
+func Marshal(g graph.Graph, name, prefix, indent string) ([]byte, error) {
+	doc := document{NS: namespace}
+	dst := graphElement{ID: name, EdgeDefault: "undirected"}
+	_, directed := g.(graph.Directed)
+	if directed {
+		dst.EdgeDefault = "directed"
+	}
+
+	graphKeys := newKeys("g")
+	if a, ok := g.(encoding.Attributer); ok {
+		dst.Data = graphKeys.data(a.Attributes())
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	ids := make(map[int64]string, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	nodeKeys := newKeys("n")
+	for _, n := range nodes {
+		id := nodeID(n)
+		if seen[id] {
+			return nil, fmt.Errorf("graphml: duplicate node ID %q", id)
+		}
+		seen[id] = true
+		ids[n.ID()] = id
+		dn := nodeElement{ID: id}
+		if a, ok := n.(encoding.Attributer); ok {
+			dn.Data = nodeKeys.data(a.Attributes())
+		}
+		dst.Nodes = append(dst.Nodes, dn)
+	}
+
+	edgeKeys := newKeys("e")
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		ordered.ByID(to)
+		for _, t := range to {
+			vid := t.ID()
+			if !directed && vid < uid {
+				// Undirected edges are only
+				// marshaled once.
+				continue
+			}
+			e := g.Edge(uid, vid)
+			de := edgeElement{Source: ids[uid], Target: ids[vid]}
+			var attrs []encoding.Attribute
+			if a, ok := e.(encoding.Attributer); ok {
+				attrs = a.Attributes()
+			}
+			if w, ok := e.(graph.WeightedEdge); ok && !hasAttribute(attrs, weightKey) {
+				de.Data = append(de.Data, edgeKeys.typed(weightKey, "double", strconv.FormatFloat(w.Weight(), 'g', -1, 64)))
+			}
+			de.Data = append(de.Data, edgeKeys.data(attrs)...)
+			dst.Edges = append(dst.Edges, de)
+		}
+	}
+
+	doc.Keys = append(doc.Keys, graphKeys.declarations("graph")...)
+	doc.Keys = append(doc.Keys, nodeKeys.declarations("node")...)
+	doc.Keys = append(doc.Keys, edgeKeys.declarations("edge")...)
+	doc.Graphs = []graphElement{dst}
+
+	b, err := xml.MarshalIndent(doc, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// nodeID returns the GraphML ID of n.
+func nodeID(n graph.Node) string {
+	if n, ok := n.(Node); ok {
+		return n.GraphMLID()
+	}
+	return fmt.Sprint(n.ID())
+}
+
+// hasAttribute returns whether attrs holds an attribute with the given key.
+func hasAttribute(attrs []encoding.Attribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// keys holds the GraphML key declarations for one kind of element.
+type keys struct {
+	prefix string
+	ids    map[string]string
+	types  map[string]string
+}
+
+func newKeys(prefix string) *keys {
+	return &keys{prefix: prefix, ids: make(map[string]string), types: make(map[string]string)}
+}
+
+// data returns the GraphML data elements for attrs, declaring keys for
+// attribute names that have not been seen before.
+func (k *keys) data(attrs []encoding.Attribute) []dataElement {
+	if len(attrs) == 0 {
+		return nil
+	}
+	d := make([]dataElement, len(attrs))
+	for i, a := range attrs {
+		d[i] = k.typed(a.Key, "string", a.Value)
+	}
+	return d
+}
+
+// typed returns a GraphML data element for the named attribute with the
+// given value, declaring a key of the given type for the name if it has
+// not been seen before.
+func (k *keys) typed(name, typ, value string) dataElement {
+	id, ok := k.ids[name]
+	if !ok {
+		id = k.prefix + strconv.Itoa(len(k.ids))
+		k.ids[name] = id
+		k.types[name] = typ
+	}
+	return dataElement{Key: id, Value: value}
+}
+
+// declarations returns the key declarations for the receiver, ordered by
+// key ID.
+func (k *keys) declarations(domain string) []keyElement {
+	decl := make([]keyElement, 0, len(k.ids))
+	for name, id := range k.ids {
+		decl = append(decl, keyElement{ID: id, For: domain, Name: name, Type: k.types[name]})
+	}
+	sort.Slice(decl, func(i, j int) bool {
+		return len(decl[i].ID) < len(decl[j].ID) || (len(decl[i].ID) == len(decl[j].ID) && decl[i].ID < decl[j].ID)
+	})
+	return decl
+}
@@ -0,0 +1,73 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import "encoding/xml"
+
+// namespace is the GraphML XML namespace.
+const namespace = "http://graphml.graphdrawing.org/xmlns"
+
+// weightKey is the attribute name used for edge weights.
+const weightKey = "weight"
+
+// Node is a GraphML node with a GraphML ID.
+type Node interface {
+	// GraphMLID returns the GraphML ID of the node.
+	GraphMLID() string
+}
+
+// IDSetter is implemented by types that can set a GraphML ID.
+type IDSetter interface {
+	SetGraphMLID(id string)
+}
+
+// document is a GraphML document.
+type document struct {
+	XMLName xml.Name       `xml:"graphml"`
+	NS      string         `xml:"xmlns,attr,omitempty"`
+	Keys    []keyElement   `xml:"key"`
+	Graphs  []graphElement `xml:"graph"`
+}
+
+// keyElement is a GraphML attribute declaration.
+type keyElement struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr,omitempty"`
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Default *string `xml:"default"`
+}
+
+// graphElement is a GraphML graph element.
+type graphElement struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []dataElement `xml:"data"`
+	Nodes       []nodeElement `xml:"node"`
+	Edges       []edgeElement `xml:"edge"`
+	Hyperedges  []struct{}    `xml:"hyperedge"`
+}
+
+// nodeElement is a GraphML node element.
+type nodeElement struct {
+	ID    string        `xml:"id,attr"`
+	Data  []dataElement `xml:"data"`
+	Graph []struct{}    `xml:"graph"`
+}
+
+// edgeElement is a GraphML edge element.
+type edgeElement struct {
+	ID       string        `xml:"id,attr,omitempty"`
+	Directed string        `xml:"directed,attr,omitempty"`
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Data     []dataElement `xml:"data"`
+}
+
+// dataElement is a GraphML attribute value.
+type dataElement struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
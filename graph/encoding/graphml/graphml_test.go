@@ -0,0 +1,192 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+	g.AddNode(simple.Node(3))
+
+	got, err := Marshal(g, "G", "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<graph id="G" edgedefault="undirected">
+		<node id="0"></node>
+		<node id="1"></node>
+		<node id="2"></node>
+		<node id="3"></node>
+		<edge source="0" target="1"></edge>
+		<edge source="1" target="2"></edge>
+	</graph>
+</graphml>`
+	if string(got) != want {
+		t.Errorf("unexpected marshaled graph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := attrgraph.NewDirected()
+	g.Attrs = encoding.Attributes{{Key: "name", Value: "example"}}
+	var nodes []*attrgraph.Node
+	for i, name := range []string{"a", "b", "c"} {
+		n := g.NewNode().(*attrgraph.Node)
+		n.Name = name
+		n.Attrs = encoding.Attributes{{Key: "label", Value: strings.ToUpper(name)}}
+		if i == 1 {
+			n.Attrs = append(n.Attrs, encoding.Attribute{Key: "color", Value: "red"})
+		}
+		g.AddNode(n)
+		nodes = append(nodes, n)
+	}
+	e := g.NewWeightedEdge(nodes[0], nodes[1], 0.5).(*attrgraph.Edge)
+	e.Attrs = encoding.Attributes{{Key: "kind", Value: "<friend> & co"}}
+	g.SetWeightedEdge(e)
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[1], nodes[2], 2))
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[2], nodes[0], math.Inf(1)))
+
+	want, err := Marshal(g, "rt", "", " ")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	for _, attr := range []string{`attr.name="label"`, `attr.name="color"`, `attr.name="kind"`, `attr.name="weight"`, "&lt;friend&gt; &amp; co"} {
+		if !strings.Contains(string(want), attr) {
+			t.Errorf("missing %s in marshaled graph:\n%s", attr, want)
+		}
+	}
+	dst := attrgraph.NewDirected()
+	err = Unmarshal(want, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling graph: %v", err)
+	}
+	got, err := Marshal(dst, "rt", "", " ")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("unexpected round trip result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if w, ok := dst.Weight(2, 0); !ok || !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight for edge c->a: got %v, want +Inf", w)
+	}
+}
+
+// networkx is an example of GraphML written by NetworkX with
+// the addition of a key default value.
+const networkx = `<?xml version='1.0' encoding='utf-8'?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="d2" for="edge" attr.name="weight" attr.type="double" />
+  <key id="d1" for="node" attr.name="color" attr.type="string">
+    <default>blue</default>
+  </key>
+  <key id="d0" for="graph" attr.name="name" attr.type="string" />
+  <graph edgedefault="undirected">
+    <data key="d0">triangle</data>
+    <node id="x">
+      <data key="d1">green</data>
+    </node>
+    <node id="y" />
+    <node id="z" />
+    <edge source="x" target="y">
+      <data key="d2">1.5</data>
+    </edge>
+    <edge source="y" target="z" />
+  </graph>
+</graphml>`
+
+func TestUnmarshalNetworkX(t *testing.T) {
+	dst := attrgraph.NewUndirected()
+	err := Unmarshal([]byte(networkx), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst.Attrs) != 1 || dst.Attrs[0] != (encoding.Attribute{Key: "name", Value: "triangle"}) {
+		t.Errorf("unexpected graph attributes: %v", dst.Attrs)
+	}
+	if n := dst.Nodes().Len(); n != 3 {
+		t.Errorf("unexpected number of nodes: got %d, want 3", n)
+	}
+	wantColor := map[string]string{"x": "green", "y": "blue", "z": "blue"}
+	ids := make(map[string]int64)
+	for _, n := range graph.NodesOf(dst.Nodes()) {
+		an := n.(*attrgraph.Node)
+		ids[an.Name] = an.ID()
+		if len(an.Attrs) != 1 || an.Attrs[0] != (encoding.Attribute{Key: "color", Value: wantColor[an.Name]}) {
+			t.Errorf("unexpected attributes for node %q: %v", an.Name, an.Attrs)
+		}
+	}
+	if w, ok := dst.Weight(ids["y"], ids["x"]); !ok || w != 1.5 {
+		t.Errorf("unexpected weight for edge x-y: got %v, want 1.5", w)
+	}
+	// Edges without a weight are created by NewEdge
+	// and so have the default edge weight.
+	if w, ok := dst.Weight(ids["y"], ids["z"]); !ok || w != 1 {
+		t.Errorf("unexpected weight for edge y-z: got %v, want 1", w)
+	}
+	if dst.HasEdgeBetween(ids["x"], ids["z"]) {
+		t.Error("unexpected edge x-z")
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{
+		name: "no graph",
+		data: `<graphml></graphml>`,
+	},
+	{
+		name: "undeclared key",
+		data: `<graphml><graph edgedefault="directed"><node id="a"><data key="k">v</data></node></graph></graphml>`,
+	},
+	{
+		name: "undeclared node",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="b"/></graph></graphml>`,
+	},
+	{
+		name: "duplicate node",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><node id="a"/></graph></graphml>`,
+	},
+	{
+		name: "hyperedge",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><hyperedge><endpoint node="a"/></hyperedge></graph></graphml>`,
+	},
+	{
+		name: "nested graph",
+		data: `<graphml><graph edgedefault="directed"><node id="a"><graph edgedefault="directed"/></node></graph></graphml>`,
+	},
+	{
+		name: "invalid weight",
+		data: `<graphml><key id="w" for="edge" attr.name="weight"/><graph edgedefault="directed"><node id="a"/><node id="b"/><edge source="a" target="b"><data key="w">heavy</data></edge></graph></graphml>`,
+	},
+	{
+		name: "two graphs",
+		data: `<graphml><graph edgedefault="directed"/><graph edgedefault="directed"/></graphml>`,
+	},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := Unmarshal([]byte(test.data), attrgraph.NewDirected())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nodelink implements marshaling and unmarshaling of graphs in the
+// node-link JSON format used by NetworkX and by web visualization tools
+// such as d3 and sigma.js.
+//
+// A node-link document is a JSON object holding a "directed" flag, graph
+// attributes in "graph", a list of node objects in "nodes" and a list of
+// link objects in "links". Each node object holds its ID in "id" and each
+// link object holds the IDs of its end nodes in "source" and "target". All
+// other fields of the graph, node and link objects are attributes.
+//
+// See https://networkx.org/documentation/stable/reference/readwrite/json_graph.html
+// for details of the format.
+//
+// Attributes
+//
+// Graph, node and edge attributes are mapped to the fields of the JSON
+// objects using the encoding.Attributer and encoding.AttributeSetter
+// interfaces. Attribute values are marshaled as JSON strings. During
+// unmarshaling, JSON string values are unquoted and other JSON values are
+// held as their JSON text, so the number 1.5 is held as "1.5".
+//
+// Edge weights of graph.WeightedEdge values are marshaled as the "weight"
+// link field unless the edge also has an attribute named "weight", and are
+// unmarshaled into edges created by a graph.WeightedBuilder.
+package nodelink // import "gonum.org/v1/gonum/graph/encoding/nodelink"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// weightKey is the link field used for edge weights.
+const weightKey = "weight"
+
+// Node is a node-link node with a node-link ID.
+type Node interface {
+	// NodeLinkID returns the node-link ID of the node.
+	NodeLinkID() string
+}
+
+// IDSetter is implemented by types that can set a node-link ID.
+type IDSetter interface {
+	SetNodeLinkID(id string)
+}
+
+// document is a node-link JSON document.
+type document struct {
+	Directed   bool                         `json:"directed"`
+	Multigraph bool                         `json:"multigraph"`
+	Graph      map[string]json.RawMessage   `json:"graph"`
+	Nodes      []map[string]json.RawMessage `json:"nodes"`
+	Links      []map[string]json.RawMessage `json:"links"`
+
+	// Edges is the name of the links
+	// field used by recent versions
+	// of NetworkX.
+	Edges []map[string]json.RawMessage `json:"edges,omitempty"`
+}
+
+// Marshal returns the node-link JSON encoding for the graph g, applying the
+// prefix and indent to the encoding if either is not empty. Nodes are
+// identified by their NodeLinkID if they implement Node, and by their integer
+// ID otherwise.
+//
+// Graph, node and edge attributes are marshaled if g, its nodes or its edges
+// implement encoding.Attributer. Edges that implement graph.WeightedEdge have
+// their weight marshaled as the "weight" field.
+func Marshal(g graph.Graph, prefix, indent string) ([]byte, error) {
+	_, directed := g.(graph.Directed)
+	doc := document{
+		Directed: directed,
+		Graph:    make(map[string]json.RawMessage),
+		Nodes:    []map[string]json.RawMessage{},
+		Links:    []map[string]json.RawMessage{},
+	}
+	if a, ok := g.(encoding.Attributer); ok {
+		err := addAttributes(doc.Graph, a.Attributes())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	ids := make(map[int64]json.RawMessage, len(nodes))
+	for _, n := range nodes {
+		id, err := nodeID(n)
+		if err != nil {
+			return nil, err
+		}
+		ids[n.ID()] = id
+		dn := map[string]json.RawMessage{"id": id}
+		if a, ok := n.(encoding.Attributer); ok {
+			err = addAttributes(dn, a.Attributes())
+			if err != nil {
+				return nil, err
+			}
+		}
+		doc.Nodes = append(doc.Nodes, dn)
+	}
+
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		ordered.ByID(to)
+		for _, t := range to {
+			vid := t.ID()
+			if !directed && vid < uid {
+				// Undirected edges are only
+				// marshaled once.
+				continue
+			}
+			e := g.Edge(uid, vid)
+			dl := map[string]json.RawMessage{"source": ids[uid], "target": ids[vid]}
+			if w, ok := e.(graph.WeightedEdge); ok {
+				b, err := json.Marshal(w.Weight())
+				if err != nil {
+					return nil, fmt.Errorf("nodelink: invalid weight for edge %d->%d: %w", uid, vid, err)
+				}
+				dl[weightKey] = b
+			}
+			if a, ok := e.(encoding.Attributer); ok {
+				attrs := a.Attributes()
+				for _, attr := range attrs {
+					if attr.Key == weightKey {
+						// Attribute weights take
+						// precedence.
+						delete(dl, weightKey)
+					}
+				}
+				err := addAttributes(dl, attrs)
+				if err != nil {
+					return nil, err
+				}
+			}
+			doc.Links = append(doc.Links, dl)
+		}
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" && indent == "" {
+		return b, nil
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, b, prefix, indent)
+	return buf.Bytes(), err
+}
+
+// nodeID returns the JSON encoding of the node-link ID of n.
+func nodeID(n graph.Node) (json.RawMessage, error) {
+	if n, ok := n.(Node); ok {
+		return json.Marshal(n.NodeLinkID())
+	}
+	return json.RawMessage(strconv.FormatInt(n.ID(), 10)), nil
+}
+
+// addAttributes adds the attributes in attrs to the JSON object dst. It
+// returns an error if an attribute key is already held by dst.
+func addAttributes(dst map[string]json.RawMessage, attrs []encoding.Attribute) error {
+	for _, a := range attrs {
+		if _, ok := dst[a.Key]; ok {
+			return fmt.Errorf("nodelink: duplicate attribute key %q", a.Key)
+		}
+		b, err := json.Marshal(a.Value)
+		if err != nil {
+			return err
+		}
+		dst[a.Key] = b
+	}
+	return nil
+}
+
+// Unmarshal parses the node-link JSON data and stores the result in dst.
+//
+// Nodes are created with dst.NewNode and have their node-link ID set if they
+// implement IDSetter. Graph, node and edge attributes are set on dst, nodes
+// and edges that implement encoding.AttributeSetter in lexical order of their
+// keys. If dst implements graph.WeightedBuilder, links with a numeric
+// "weight" field are created with that weight, and the field is not otherwise
+// set on the edge. Parallel links in multigraph documents are merged.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	var doc document
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	if doc.Links == nil {
+		doc.Links = doc.Edges
+	}
+
+	if s, ok := dst.(encoding.AttributeSetter); ok {
+		err = setAttributes(s, doc.Graph)
+		if err != nil {
+			return err
+		}
+	}
+
+	nodes := make(map[string]graph.Node, len(doc.Nodes))
+	for _, dn := range doc.Nodes {
+		id, err := value(dn["id"])
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return errors.New("nodelink: missing node ID")
+		}
+		if _, ok := nodes[id]; ok {
+			return fmt.Errorf("nodelink: duplicate node ID %s", id)
+		}
+		delete(dn, "id")
+		n := dst.NewNode()
+		if s, ok := n.(IDSetter); ok {
+			s.SetNodeLinkID(id)
+		}
+		dst.AddNode(n)
+		nodes[id] = n
+		if s, ok := n.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, dn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	wb, weighted := dst.(graph.WeightedBuilder)
+	for _, dl := range doc.Links {
+		var end [2]graph.Node
+		for i, field := range [2]string{"source", "target"} {
+			id, err := value(dl[field])
+			if err != nil {
+				return err
+			}
+			n, ok := nodes[id]
+			if !ok {
+				return fmt.Errorf("nodelink: link %s %s not declared", field, id)
+			}
+			end[i] = n
+			delete(dl, field)
+		}
+
+		var e graph.Edge
+		if weighted {
+			if b, ok := dl[weightKey]; ok {
+				var w float64
+				if json.Unmarshal(b, &w) == nil {
+					delete(dl, weightKey)
+					we := wb.NewWeightedEdge(end[0], end[1], w)
+					wb.SetWeightedEdge(we)
+					e = we
+				}
+			}
+		}
+		if e == nil {
+			e = dst.NewEdge(end[0], end[1])
+			dst.SetEdge(e)
+		}
+		if s, ok := e.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, dl)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setAttributes sets the fields of the JSON object src as attributes on dst
+// in lexical order of their keys.
+func setAttributes(dst encoding.AttributeSetter, src map[string]json.RawMessage) error {
+	keys := make([]string, 0, len(src))
+	for k := range src {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := value(src[k])
+		if err != nil {
+			return err
+		}
+		err = dst.SetAttribute(encoding.Attribute{Key: k, Value: v})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value returns the attribute value of the JSON value b. JSON strings are
+// unquoted and other values are returned as compact JSON text.
+func value(b json.RawMessage) (string, error) {
+	if len(b) == 0 {
+		return "", nil
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	err := json.Compact(&buf, b)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,147 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nodelink
+
+import (
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+	g.AddNode(simple.Node(3))
+
+	got, err := Marshal(g, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `{"directed":false,"multigraph":false,"graph":{},"nodes":[{"id":0},{"id":1},{"id":2},{"id":3}],"links":[{"source":0,"target":1},{"source":1,"target":2}]}`
+	if string(got) != want {
+		t.Errorf("unexpected marshaled graph:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := attrgraph.NewDirected()
+	g.Attrs = encoding.Attributes{{Key: "name", Value: "example"}}
+	var nodes []*attrgraph.Node
+	for i, name := range []string{"a", "b", "c"} {
+		n := g.NewNode().(*attrgraph.Node)
+		n.Name = name
+		n.Attrs = encoding.Attributes{{Key: "label", Value: strings.ToUpper(name)}}
+		if i == 1 {
+			n.Attrs = append(n.Attrs, encoding.Attribute{Key: "color", Value: "red"})
+		}
+		g.AddNode(n)
+		nodes = append(nodes, n)
+	}
+	e := g.NewWeightedEdge(nodes[0], nodes[1], 0.5).(*attrgraph.Edge)
+	e.Attrs = encoding.Attributes{{Key: "kind", Value: "\"friend\""}}
+	g.SetWeightedEdge(e)
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[1], nodes[2], 2))
+	g.SetWeightedEdge(g.NewWeightedEdge(nodes[2], nodes[0], 0))
+
+	want, err := Marshal(g, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	dst := attrgraph.NewDirected()
+	err = Unmarshal(want, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling graph: %v", err)
+	}
+	got, err := Marshal(dst, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling graph: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("unexpected round trip result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if w, ok := dst.Weight(2, 0); !ok || w != 0 {
+		t.Errorf("unexpected weight for edge c->a: got %v, want 0", w)
+	}
+}
+
+// networkx is an example of node-link JSON written by NetworkX.
+const networkx = `{
+  "directed": false,
+  "multigraph": false,
+  "graph": {"name": "triangle", "year": 2022},
+  "nodes": [
+    {"color": "green", "id": "x"},
+    {"id": "y", "size": 2.5},
+    {"id": 3}
+  ],
+  "links": [
+    {"weight": 1.5, "source": "x", "target": "y"},
+    {"source": "y", "target": 3, "tags": ["a", "b"]}
+  ]
+}`
+
+func TestUnmarshalNetworkX(t *testing.T) {
+	dst := attrgraph.NewUndirected()
+	err := Unmarshal([]byte(networkx), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantGraph := encoding.Attributes{{Key: "name", Value: "triangle"}, {Key: "year", Value: "2022"}}
+	if !attrgraph.EqualAttributes(dst.Attrs, wantGraph) {
+		t.Errorf("unexpected graph attributes: got %v, want %v", dst.Attrs, wantGraph)
+	}
+	wantNode := map[string]encoding.Attributes{
+		"x": {{Key: "color", Value: "green"}},
+		"y": {{Key: "size", Value: "2.5"}},
+		"3": nil,
+	}
+	ids := make(map[string]int64)
+	for _, n := range graph.NodesOf(dst.Nodes()) {
+		an := n.(*attrgraph.Node)
+		ids[an.Name] = an.ID()
+		want, ok := wantNode[an.Name]
+		if !ok {
+			t.Errorf("unexpected node %q", an.Name)
+		}
+		if !attrgraph.EqualAttributes(an.Attrs, want) {
+			t.Errorf("unexpected attributes for node %q: got %v, want %v", an.Name, an.Attrs, want)
+		}
+	}
+	if len(ids) != 3 {
+		t.Errorf("unexpected number of nodes: got %d, want 3", len(ids))
+	}
+	if w, ok := dst.Weight(ids["y"], ids["x"]); !ok || w != 1.5 {
+		t.Errorf("unexpected weight for edge x-y: got %v, want 1.5", w)
+	}
+	e := dst.Edge(ids["y"], ids["3"]).(*attrgraph.Edge)
+	wantEdge := encoding.Attributes{{Key: "tags", Value: `["a","b"]`}}
+	if !attrgraph.EqualAttributes(e.Attrs, wantEdge) {
+		t.Errorf("unexpected attributes for edge y-3: got %v, want %v", e.Attrs, wantEdge)
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{name: "invalid JSON", data: `{"nodes": [}`},
+	{name: "missing ID", data: `{"nodes": [{"color": "red"}]}`},
+	{name: "duplicate node", data: `{"nodes": [{"id": "a"}, {"id": "a"}]}`},
+	{name: "undeclared node", data: `{"nodes": [{"id": "a"}], "links": [{"source": "a", "target": "b"}]}`},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := Unmarshal([]byte(test.data), attrgraph.NewDirected())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
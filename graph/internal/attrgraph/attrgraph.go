@@ -0,0 +1,128 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package attrgraph
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// DirectedGraph is a weighted directed graph with attributes that creates
+// nodes and edges that can hold an encoding ID and attributes.
+type DirectedGraph struct {
+	*simple.WeightedDirectedGraph
+	Attrs encoding.Attributes
+}
+
+// NewDirected returns a new DirectedGraph with no edge weight for absent
+// edges, so that edges with zero and infinite weight can be held.
+func NewDirected() *DirectedGraph {
+	return &DirectedGraph{WeightedDirectedGraph: simple.NewWeightedDirectedGraph(0, math.Inf(1))}
+}
+
+func (g *DirectedGraph) Attributes() []encoding.Attribute { return g.Attrs }
+func (g *DirectedGraph) SetAttribute(attr encoding.Attribute) error {
+	return g.Attrs.SetAttribute(attr)
+}
+
+func (g *DirectedGraph) NewNode() graph.Node {
+	return &Node{Node: g.WeightedDirectedGraph.NewNode()}
+}
+
+func (g *DirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return g.NewWeightedEdge(from, to, 1)
+}
+
+func (g *DirectedGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(graph.WeightedEdge))
+}
+
+func (g *DirectedGraph) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &Edge{WeightedEdge: g.WeightedDirectedGraph.NewWeightedEdge(from, to, weight)}
+}
+
+// UndirectedGraph is a weighted undirected graph with attributes that
+// creates nodes and edges that can hold an encoding ID and attributes.
+type UndirectedGraph struct {
+	*simple.WeightedUndirectedGraph
+	Attrs encoding.Attributes
+}
+
+// NewUndirected returns a new UndirectedGraph with no edge weight for absent
+// edges, so that edges with zero and infinite weight can be held.
+func NewUndirected() *UndirectedGraph {
+	return &UndirectedGraph{WeightedUndirectedGraph: simple.NewWeightedUndirectedGraph(0, math.Inf(1))}
+}
+
+func (g *UndirectedGraph) Attributes() []encoding.Attribute { return g.Attrs }
+func (g *UndirectedGraph) SetAttribute(attr encoding.Attribute) error {
+	return g.Attrs.SetAttribute(attr)
+}
+
+func (g *UndirectedGraph) NewNode() graph.Node {
+	return &Node{Node: g.WeightedUndirectedGraph.NewNode()}
+}
+
+func (g *UndirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return g.NewWeightedEdge(from, to, 1)
+}
+
+func (g *UndirectedGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(graph.WeightedEdge))
+}
+
+func (g *UndirectedGraph) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &Edge{WeightedEdge: g.WeightedUndirectedGraph.NewWeightedEdge(from, to, weight)}
+}
+
+// Node is a graph node with an encoding ID and attributes. The ID is
+// exposed through the ID interfaces of each of the encoding packages.
+type Node struct {
+	graph.Node
+	Name  string
+	Attrs encoding.Attributes
+}
+
+func (n *Node) GraphMLID() string      { return n.Name }
+func (n *Node) SetGraphMLID(id string) { n.Name = id }
+
+func (n *Node) GEXFID() string      { return n.Name }
+func (n *Node) SetGEXFID(id string) { n.Name = id }
+
+func (n *Node) NodeLinkID() string      { return n.Name }
+func (n *Node) SetNodeLinkID(id string) { n.Name = id }
+
+func (n *Node) Attributes() []encoding.Attribute { return n.Attrs }
+func (n *Node) SetAttribute(attr encoding.Attribute) error {
+	return n.Attrs.SetAttribute(attr)
+}
+
+// Edge is a weighted graph edge with attributes.
+type Edge struct {
+	graph.WeightedEdge
+	Attrs encoding.Attributes
+}
+
+func (e *Edge) Attributes() []encoding.Attribute { return e.Attrs }
+func (e *Edge) SetAttribute(attr encoding.Attribute) error {
+	return e.Attrs.SetAttribute(attr)
+}
+
+// EqualAttributes returns whether a and b hold the same attributes in the
+// same order.
+func EqualAttributes(a, b encoding.Attributes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,7 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package attrgraph provides weighted graphs with attributed nodes and edges
+// for testing the graph encoding packages.
+package attrgraph // import "gonum.org/v1/gonum/graph/internal/attrgraph"
@@ -0,0 +1,6 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package isomorphism provides graph and subgraph isomorphism matching.
+package isomorphism // import "gonum.org/v1/gonum/graph/isomorphism"
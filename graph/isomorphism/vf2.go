@@ -0,0 +1,466 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package isomorphism
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Mode specifies the kind of mapping found by a Matcher.
+type Mode int
+
+const (
+	// Subgraph matches the pattern to a subgraph of the
+	// target graph. Each pattern edge must correspond to
+	// an edge of the target graph, but the target graph
+	// may have additional edges between matched nodes.
+	// This is also known as graph monomorphism.
+	Subgraph Mode = iota
+
+	// Induced matches the pattern to an induced subgraph
+	// of the target graph. Two matched nodes are adjacent
+	// in the target graph if and only if the corresponding
+	// pattern nodes are adjacent.
+	Induced
+
+	// Isomorphism matches the pattern to the whole target
+	// graph.
+	Isomorphism
+)
+
+// NodeMatch is a predicate that returns whether the node n in the target
+// graph may be matched to the node p in the pattern graph.
+type NodeMatch func(n, p graph.Node) bool
+
+// EdgeMatch is a predicate that returns whether the edge e in the target
+// graph may be matched to the edge p in the pattern graph. For directed
+// graphs, e and p have the same orientation with respect to the mapping.
+type EdgeMatch func(e, p graph.Edge) bool
+
+// Matcher is an iterator over the mappings of the nodes of a pattern graph
+// to the nodes of a target graph.
+//
+// Matcher uses the VF2 state space search of Cordella et al. with the
+// breadth first node matching order of the VF2++ algorithm described in
+// Jüttner and Madarasi, "VF2++—An improved subgraph isomorphism algorithm"
+// (2018). Partial mappings are pruned by node degree and by comparing the
+// numbers of unmatched neighbors of the candidate node pair. Mappings are
+// found lazily, so the first match is available without enumerating the
+// others.
+type Matcher struct {
+	g, pattern *indexed
+	mode       Mode
+
+	nodeMatch NodeMatch
+	edgeMatch EdgeMatch
+
+	// order is the order in which pattern nodes are
+	// matched and parent[i] is a matched neighbor of
+	// order[i] used to generate candidates, or -1.
+	order  []int
+	parent []int
+
+	// core holds the target node matched to each
+	// pattern node and coreG holds the pattern node
+	// matched to each target node, or -1.
+	core, coreG []int
+
+	// candidates and pos hold the candidate target
+	// nodes and the next candidate for each depth.
+	candidates [][]int
+	pos        []int
+	depth      int
+
+	started, done bool
+}
+
+// NewMatcher returns a Matcher that iterates over the mappings of the nodes
+// of pattern to nodes of g according to mode. If nodeMatch or edgeMatch is
+// not nil, matched nodes and edges must also satisfy the predicate. g and
+// pattern must both be graph.Directed or both undirected, otherwise
+// NewMatcher will panic.
+func NewMatcher(g, pattern graph.Graph, mode Mode, nodeMatch NodeMatch, edgeMatch EdgeMatch) *Matcher {
+	_, gDirected := g.(graph.Directed)
+	_, pDirected := pattern.(graph.Directed)
+	if gDirected != pDirected {
+		panic("isomorphism: mismatched graph directedness")
+	}
+	m := &Matcher{
+		g:         newIndexed(g, gDirected),
+		pattern:   newIndexed(pattern, pDirected),
+		mode:      mode,
+		nodeMatch: nodeMatch,
+		edgeMatch: edgeMatch,
+	}
+
+	np, ng := len(m.pattern.nodes), len(m.g.nodes)
+	switch {
+	case np > ng:
+		m.done = true
+	case mode == Isomorphism && (np != ng || len(m.pattern.edges) != len(m.g.edges)):
+		m.done = true
+	}
+
+	m.core = make([]int, np)
+	for i := range m.core {
+		m.core[i] = -1
+	}
+	m.coreG = make([]int, ng)
+	for i := range m.coreG {
+		m.coreG[i] = -1
+	}
+	m.candidates = make([][]int, np)
+	m.pos = make([]int, np)
+	for i := range m.pos {
+		m.pos[i] = -1
+	}
+	m.orderPattern()
+	return m
+}
+
+// Next advances the iterator to the next mapping and returns whether
+// there is one.
+func (m *Matcher) Next() bool {
+	if m.done {
+		return false
+	}
+	np := len(m.order)
+	if !m.started {
+		m.started = true
+		m.depth = 0
+	} else {
+		// Resume the search from the last mapping.
+		m.depth = np - 1
+		if m.depth < 0 {
+			m.done = true
+			return false
+		}
+		m.unassign(m.depth)
+	}
+
+	for {
+		d := m.depth
+		if d == np {
+			return true
+		}
+		if m.pos[d] < 0 {
+			m.candidates[d] = m.candidatesFor(d)
+			m.pos[d] = 0
+		}
+		u := m.order[d]
+		var assigned bool
+		for m.pos[d] < len(m.candidates[d]) {
+			v := m.candidates[d][m.pos[d]]
+			m.pos[d]++
+			if m.coreG[v] < 0 && m.feasible(u, v) {
+				m.core[u] = v
+				m.coreG[v] = u
+				m.depth++
+				assigned = true
+				break
+			}
+		}
+		if assigned {
+			continue
+		}
+
+		// Backtrack.
+		m.pos[d] = -1
+		m.depth--
+		if m.depth < 0 {
+			m.done = true
+			return false
+		}
+		m.unassign(m.depth)
+	}
+}
+
+// Mapping returns the current mapping from pattern node IDs to the IDs of
+// the matched nodes in the target graph.
+func (m *Matcher) Mapping() map[int64]int64 {
+	if !m.started || m.done {
+		return nil
+	}
+	mapping := make(map[int64]int64, len(m.core))
+	for u, v := range m.core {
+		mapping[m.pattern.nodes[u].ID()] = m.g.nodes[v].ID()
+	}
+	return mapping
+}
+
+// Isomorphic returns whether a and b are isomorphic with node and edge
+// correspondence constrained by nodeMatch and edgeMatch if they are not
+// nil. Nodes and edges of a are passed as the first parameter of the
+// predicates.
+func Isomorphic(a, b graph.Graph, nodeMatch NodeMatch, edgeMatch EdgeMatch) bool {
+	return NewMatcher(a, b, Isomorphism, nodeMatch, edgeMatch).Next()
+}
+
+// SubgraphIsomorphic returns whether pattern is isomorphic to a subgraph of
+// g, or to an induced subgraph of g if induced is true, with node and edge
+// correspondence constrained by nodeMatch and edgeMatch if they are not nil.
+func SubgraphIsomorphic(g, pattern graph.Graph, induced bool, nodeMatch NodeMatch, edgeMatch EdgeMatch) bool {
+	mode := Subgraph
+	if induced {
+		mode = Induced
+	}
+	return NewMatcher(g, pattern, mode, nodeMatch, edgeMatch).Next()
+}
+
+func (m *Matcher) unassign(d int) {
+	u := m.order[d]
+	m.coreG[m.core[u]] = -1
+	m.core[u] = -1
+}
+
+// orderPattern determines the matching order of the pattern nodes. Each
+// connected component is traversed breadth first from a node of largest
+// degree. Within each level, nodes with the most matched neighbors are
+// placed first, breaking ties by largest degree.
+func (m *Matcher) orderPattern() {
+	p := m.pattern
+	n := len(p.nodes)
+	m.order = make([]int, 0, n)
+	m.parent = make([]int, 0, n)
+	inOrder := make([]bool, n)
+	seen := make([]bool, n)
+	conn := make([]int, n)
+	for len(m.order) < n {
+		root := -1
+		for u := range p.nodes {
+			if !seen[u] && (root < 0 || p.degree(u) > p.degree(root)) {
+				root = u
+			}
+		}
+		seen[root] = true
+		level := []int{root}
+		for len(level) != 0 {
+			start := len(m.order)
+			for len(level) != 0 {
+				best := 0
+				for i, u := range level[1:] {
+					b := level[best]
+					if conn[u] > conn[b] || (conn[u] == conn[b] && p.degree(u) > p.degree(b)) {
+						best = i + 1
+					}
+				}
+				u := level[best]
+				level[best] = level[len(level)-1]
+				level = level[:len(level)-1]
+
+				parent := -1
+				for _, w := range p.neighbors(u) {
+					if inOrder[w] && (parent < 0 || p.degree(w) < p.degree(parent)) {
+						parent = w
+					}
+					conn[w]++
+				}
+				m.order = append(m.order, u)
+				m.parent = append(m.parent, parent)
+				inOrder[u] = true
+			}
+			var next []int
+			for _, u := range m.order[start:] {
+				for _, w := range p.neighbors(u) {
+					if !seen[w] {
+						seen[w] = true
+						next = append(next, w)
+					}
+				}
+			}
+			level = next
+		}
+	}
+}
+
+// candidatesFor returns the target nodes that may be matched to the
+// pattern node at depth d.
+func (m *Matcher) candidatesFor(d int) []int {
+	u, parent := m.order[d], m.parent[d]
+	if parent < 0 {
+		all := make([]int, len(m.g.nodes))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	v := m.core[parent]
+	if !m.g.directed {
+		return m.g.out[v]
+	}
+	if m.pattern.hasEdge(parent, u) {
+		return m.g.out[v]
+	}
+	return m.g.in[v]
+}
+
+// feasible returns whether the pattern node u may be matched to the target
+// node v given the current partial mapping.
+func (m *Matcher) feasible(u, v int) bool {
+	p, g := m.pattern, m.g
+	if m.mode == Isomorphism {
+		if len(p.out[u]) != len(g.out[v]) || len(p.in[u]) != len(g.in[v]) || p.loop[u] != g.loop[v] {
+			return false
+		}
+	} else {
+		if len(p.out[u]) > len(g.out[v]) || len(p.in[u]) > len(g.in[v]) || (p.loop[u] && !g.loop[v]) {
+			return false
+		}
+		if m.mode == Induced && g.loop[v] && !p.loop[u] {
+			return false
+		}
+	}
+	if m.nodeMatch != nil && !m.nodeMatch(g.nodes[v], p.nodes[u]) {
+		return false
+	}
+	if p.loop[u] && !m.matchEdge(v, v, u, u) {
+		return false
+	}
+
+	// Check the consistency of the mapping over edges to matched
+	// nodes and count unmatched neighbors for the lookahead rule.
+	var pFree, gFree int
+	for _, w := range p.out[u] {
+		x := m.core[w]
+		if x < 0 {
+			pFree++
+			continue
+		}
+		if !g.hasEdge(v, x) || !m.matchEdge(v, x, u, w) {
+			return false
+		}
+	}
+	if p.directed {
+		for _, w := range p.in[u] {
+			x := m.core[w]
+			if x < 0 {
+				pFree++
+				continue
+			}
+			if !g.hasEdge(x, v) || !m.matchEdge(x, v, w, u) {
+				return false
+			}
+		}
+	}
+	for _, x := range g.out[v] {
+		w := m.coreG[x]
+		if w < 0 {
+			gFree++
+			continue
+		}
+		if m.mode != Subgraph && !p.hasEdge(u, w) {
+			return false
+		}
+	}
+	if g.directed {
+		for _, x := range g.in[v] {
+			w := m.coreG[x]
+			if w < 0 {
+				gFree++
+				continue
+			}
+			if m.mode != Subgraph && !p.hasEdge(w, u) {
+				return false
+			}
+		}
+	}
+	if m.mode == Isomorphism {
+		return pFree == gFree
+	}
+	return pFree <= gFree
+}
+
+// matchEdge returns whether the target edge from x to y may be matched to
+// the pattern edge from a to b.
+func (m *Matcher) matchEdge(x, y, a, b int) bool {
+	if m.edgeMatch == nil {
+		return true
+	}
+	g, p := m.g, m.pattern
+	return m.edgeMatch(
+		g.g.Edge(g.nodes[x].ID(), g.nodes[y].ID()),
+		p.g.Edge(p.nodes[a].ID(), p.nodes[b].ID()),
+	)
+}
+
+// indexed is a graph with nodes ordered by ID and adjacency held by index.
+// Self loops are recorded in loop and excluded from the adjacency lists.
+// For undirected graphs in is the same as out.
+type indexed struct {
+	g        graph.Graph
+	directed bool
+	nodes    []graph.Node
+	out, in  [][]int
+	loop     []bool
+	edges    map[[2]int]struct{}
+}
+
+func newIndexed(g graph.Graph, directed bool) *indexed {
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	ig := &indexed{
+		g:        g,
+		directed: directed,
+		nodes:    nodes,
+		out:      make([][]int, len(nodes)),
+		loop:     make([]bool, len(nodes)),
+		edges:    make(map[[2]int]struct{}),
+	}
+	if directed {
+		ig.in = make([][]int, len(nodes))
+	} else {
+		ig.in = ig.out
+	}
+	for u, n := range nodes {
+		uid := n.ID()
+		to := g.From(uid)
+		for to.Next() {
+			v := indexOf[to.Node().ID()]
+			if v == u {
+				ig.loop[u] = true
+				continue
+			}
+			ig.out[u] = append(ig.out[u], v)
+			if directed {
+				ig.in[v] = append(ig.in[v], u)
+			}
+			if directed || u < v {
+				ig.edges[[2]int{u, v}] = struct{}{}
+			}
+		}
+	}
+	return ig
+}
+
+// hasEdge returns whether there is an edge from u to v.
+func (g *indexed) hasEdge(u, v int) bool {
+	if !g.directed && u > v {
+		u, v = v, u
+	}
+	_, ok := g.edges[[2]int{u, v}]
+	return ok
+}
+
+// degree returns the number of edges incident to u, excluding self loops.
+func (g *indexed) degree(u int) int {
+	if !g.directed {
+		return len(g.out[u])
+	}
+	return len(g.out[u]) + len(g.in[u])
+}
+
+// neighbors returns the nodes adjacent to u in either direction. The
+// returned slice may contain duplicates for directed graphs.
+func (g *indexed) neighbors(u int) []int {
+	if !g.directed {
+		return g.out[u]
+	}
+	return append(g.out[u][:len(g.out[u]):len(g.out[u])], g.in[u]...)
+}
@@ -0,0 +1,382 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package isomorphism
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// petersen is the Petersen graph.
+var petersen = [][2]int64{
+	{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0},
+	{0, 5}, {1, 6}, {2, 7}, {3, 8}, {4, 9},
+	{5, 7}, {7, 9}, {9, 6}, {6, 8}, {8, 5},
+}
+
+func undirected(edges [][2]int64, nodes ...int64) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for _, n := range nodes {
+		g.AddNode(simple.Node(n))
+	}
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+func directed(edges [][2]int64, nodes ...int64) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for _, n := range nodes {
+		g.AddNode(simple.Node(n))
+	}
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+var matcherTests = []struct {
+	name       string
+	g, pattern graph.Graph
+	mode       Mode
+	want       int
+}{
+	{
+		name:    "triangle in K4",
+		g:       undirected([][2]int64{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}, {2, 0}}),
+		mode:    Subgraph,
+		want:    24,
+	},
+	{
+		name:    "path in K4",
+		g:       undirected([][2]int64{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}}),
+		mode:    Subgraph,
+		want:    24,
+	},
+	{
+		name:    "induced path in K4",
+		g:       undirected([][2]int64{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}}),
+		mode:    Induced,
+		want:    0,
+	},
+	{
+		name:    "induced path in square",
+		g:       undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 0}}),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}}),
+		mode:    Induced,
+		want:    8,
+	},
+	{
+		name:    "isolated nodes",
+		g:       undirected([][2]int64{{0, 1}}, 2),
+		pattern: undirected(nil, 0, 1),
+		mode:    Induced,
+		// Either end of the edge with the isolated node, in both orders.
+		want: 4,
+	},
+	{
+		name:    "Petersen automorphisms",
+		g:       undirected(petersen),
+		pattern: undirected(petersen),
+		mode:    Isomorphism,
+		want:    120,
+	},
+	{
+		name:    "pentagon in Petersen",
+		g:       undirected(petersen),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}}),
+		mode:    Induced,
+		// The Petersen graph has 12 pentagons, each with 10 automorphisms.
+		want: 120,
+	},
+	{
+		name:    "square in Petersen",
+		g:       undirected(petersen),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 0}}),
+		mode:    Subgraph,
+		want:    0,
+	},
+	{
+		name:    "directed cycle",
+		g:       directed([][2]int64{{0, 1}, {1, 2}, {2, 0}, {0, 2}}),
+		pattern: directed([][2]int64{{0, 1}, {1, 2}, {2, 0}}),
+		mode:    Subgraph,
+		want:    3,
+	},
+	{
+		name:    "induced directed cycle",
+		g:       directed([][2]int64{{0, 1}, {1, 2}, {2, 0}, {0, 2}}),
+		pattern: directed([][2]int64{{0, 1}, {1, 2}, {2, 0}}),
+		mode:    Induced,
+		want:    0,
+	},
+	{
+		name:    "reversed directed path",
+		g:       directed([][2]int64{{2, 1}, {1, 0}}),
+		pattern: directed([][2]int64{{0, 1}, {1, 2}}),
+		mode:    Isomorphism,
+		want:    1,
+	},
+	{
+		name:    "empty pattern",
+		g:       undirected([][2]int64{{0, 1}}),
+		pattern: undirected(nil),
+		mode:    Subgraph,
+		want:    1,
+	},
+	{
+		name:    "pattern larger than graph",
+		g:       undirected([][2]int64{{0, 1}}),
+		pattern: undirected([][2]int64{{0, 1}, {1, 2}}),
+		mode:    Subgraph,
+		want:    0,
+	},
+}
+
+func TestMatcher(t *testing.T) {
+	for _, test := range matcherTests {
+		m := NewMatcher(test.g, test.pattern, test.mode, nil, nil)
+		seen := make(map[string]bool)
+		for m.Next() {
+			mapping := m.Mapping()
+			checkMapping(t, test.name, test.g, test.pattern, test.mode, mapping)
+			key := fmt.Sprint(mapping)
+			if seen[key] {
+				t.Errorf("repeated mapping for %s: %v", test.name, mapping)
+			}
+			seen[key] = true
+		}
+		if len(seen) != test.want {
+			t.Errorf("unexpected number of mappings for %s: got %d, want %d", test.name, len(seen), test.want)
+		}
+		if m.Next() {
+			t.Errorf("unexpected mapping after exhaustion for %s", test.name)
+		}
+	}
+}
+
+func TestIsomorphic(t *testing.T) {
+	// Relabel the Petersen graph with a random permutation.
+	rnd := rand.New(rand.NewSource(1))
+	perm := rnd.Perm(10)
+	relabeled := make([][2]int64, len(petersen))
+	for i, e := range petersen {
+		relabeled[i] = [2]int64{int64(perm[e[0]]) + 100, int64(perm[e[1]]) + 100}
+	}
+	if !Isomorphic(undirected(petersen), undirected(relabeled), nil, nil) {
+		t.Error("relabeled Petersen graph not isomorphic")
+	}
+
+	// The 5-prism has the same degree sequence as the
+	// Petersen graph but is not isomorphic to it.
+	prism := [][2]int64{
+		{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0},
+		{5, 6}, {6, 7}, {7, 8}, {8, 9}, {9, 5},
+		{0, 5}, {1, 6}, {2, 7}, {3, 8}, {4, 9},
+	}
+	if Isomorphic(undirected(petersen), undirected(prism), nil, nil) {
+		t.Error("Petersen graph isomorphic to 5-prism")
+	}
+
+	if !SubgraphIsomorphic(undirected(petersen), undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}}), true, nil, nil) {
+		t.Error("no induced hexagon found in Petersen graph")
+	}
+}
+
+func TestMatcherPredicates(t *testing.T) {
+	// A labeled molecule-like graph: carbon ring with
+	// an attached oxygen, matched against a C-O pattern
+	// with a double bond weight.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	label := map[int64]string{0: "C", 1: "C", 2: "C", 3: "C", 4: "C", 5: "C", 6: "O", 7: "O"}
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(2), T: simple.Node(3), W: 1},
+		{F: simple.Node(3), T: simple.Node(4), W: 2},
+		{F: simple.Node(4), T: simple.Node(5), W: 1},
+		{F: simple.Node(5), T: simple.Node(0), W: 2},
+		{F: simple.Node(0), T: simple.Node(6), W: 2},
+		{F: simple.Node(3), T: simple.Node(7), W: 1},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	pattern := simple.NewWeightedUndirectedGraph(0, 0)
+	pattern.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(10), T: simple.Node(11), W: 2})
+	patternLabel := map[int64]string{10: "C", 11: "O"}
+
+	nodeMatch := func(n, p graph.Node) bool { return label[n.ID()] == patternLabel[p.ID()] }
+	edgeMatch := func(e, p graph.Edge) bool {
+		return e.(graph.WeightedEdge).Weight() == p.(graph.WeightedEdge).Weight()
+	}
+
+	var got []map[int64]int64
+	m := NewMatcher(g, pattern, Subgraph, nodeMatch, edgeMatch)
+	for m.Next() {
+		got = append(got, m.Mapping())
+	}
+	if len(got) != 1 || got[0][10] != 0 || got[0][11] != 6 {
+		t.Errorf("unexpected matches: got %v, want [map[10:0 11:6]]", got)
+	}
+
+	// Without the edge predicate both oxygens match.
+	var n int
+	m = NewMatcher(g, pattern, Subgraph, nodeMatch, nil)
+	for m.Next() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("unexpected number of matches without edge predicate: got %d, want 2", n)
+	}
+}
+
+func TestMatcherRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		isDirected := i%2 == 0
+		gn := 1 + rnd.Intn(6)
+		pn := 1 + rnd.Intn(gn)
+		if i%3 == 0 {
+			pn = gn
+		}
+		g := randomGraph(rnd, gn, 0.5, isDirected)
+		pattern := randomGraph(rnd, pn, 0.5, isDirected)
+		for _, mode := range []Mode{Subgraph, Induced, Isomorphism} {
+			want := bruteForceCount(g, pattern, mode)
+			var got int
+			m := NewMatcher(g, pattern, mode, nil, nil)
+			for m.Next() {
+				checkMapping(t, "random", g, pattern, mode, m.Mapping())
+				got++
+			}
+			if got != want {
+				t.Errorf("unexpected number of mappings for test %d mode %d: got %d, want %d", i, mode, got, want)
+			}
+		}
+	}
+}
+
+func randomGraph(rnd *rand.Rand, n int, p float64, isDirected bool) graph.Graph {
+	var g interface {
+		graph.Graph
+		AddNode(graph.Node)
+		SetEdge(graph.Edge)
+	}
+	if isDirected {
+		g = simple.NewDirectedGraph()
+	} else {
+		g = simple.NewUndirectedGraph()
+	}
+	for u := 0; u < n; u++ {
+		g.AddNode(simple.Node(u))
+	}
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u == v || (!isDirected && v < u) || rnd.Float64() >= p {
+				continue
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	return g
+}
+
+// hasEdgeFromTo returns whether g has an edge from u to v.
+func hasEdgeFromTo(g graph.Graph, u, v int64) bool {
+	if d, ok := g.(graph.Directed); ok {
+		return d.HasEdgeFromTo(u, v)
+	}
+	return g.HasEdgeBetween(u, v)
+}
+
+// checkMapping checks that mapping is an injective mapping of the nodes of
+// pattern into g that is valid for mode.
+func checkMapping(t *testing.T, name string, g, pattern graph.Graph, mode Mode, mapping map[int64]int64) {
+	t.Helper()
+	pnodes := graph.NodesOf(pattern.Nodes())
+	if len(mapping) != len(pnodes) {
+		t.Errorf("mapping does not cover pattern for %s: %v", name, mapping)
+		return
+	}
+	used := make(map[int64]bool)
+	for _, v := range mapping {
+		if used[v] {
+			t.Errorf("mapping not injective for %s: %v", name, mapping)
+			return
+		}
+		used[v] = true
+	}
+	if mode == Isomorphism && len(used) != g.Nodes().Len() {
+		t.Errorf("isomorphism does not cover graph for %s: %v", name, mapping)
+	}
+	for _, u := range pnodes {
+		for _, w := range pnodes {
+			if u.ID() == w.ID() {
+				continue
+			}
+			pe := hasEdgeFromTo(pattern, u.ID(), w.ID())
+			ge := hasEdgeFromTo(g, mapping[u.ID()], mapping[w.ID()])
+			if pe && !ge {
+				t.Errorf("pattern edge %d->%d not mapped for %s: %v", u.ID(), w.ID(), name, mapping)
+				return
+			}
+			if mode != Subgraph && ge && !pe {
+				t.Errorf("extra edge between mapped nodes of %d and %d for %s: %v", u.ID(), w.ID(), name, mapping)
+				return
+			}
+		}
+	}
+}
+
+// bruteForceCount returns the number of valid mappings of pattern into g
+// for mode by enumerating all injective mappings.
+func bruteForceCount(g, pattern graph.Graph, mode Mode) int {
+	gnodes := graph.NodesOf(g.Nodes())
+	pnodes := graph.NodesOf(pattern.Nodes())
+	if mode == Isomorphism && len(gnodes) != len(pnodes) {
+		return 0
+	}
+	mapping := make([]int64, len(pnodes))
+	used := make([]bool, len(gnodes))
+	var count func(i int) int
+	count = func(i int) int {
+		if i == len(pnodes) {
+			for a, u := range pnodes {
+				for b, w := range pnodes {
+					if a == b {
+						continue
+					}
+					pe := hasEdgeFromTo(pattern, u.ID(), w.ID())
+					ge := hasEdgeFromTo(g, mapping[a], mapping[b])
+					if (pe && !ge) || (mode != Subgraph && ge && !pe) {
+						return 0
+					}
+				}
+			}
+			return 1
+		}
+		var n int
+		for j, v := range gnodes {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			mapping[i] = v.ID()
+			n += count(i + 1)
+			used[j] = false
+		}
+		return n
+	}
+	return count(0)
+}
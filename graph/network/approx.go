@@ -0,0 +1,351 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// ApproxBetweenness returns an approximation of the non-zero betweenness
+// centrality for nodes in the unweighted graph g, as computed exactly by
+// Betweenness, by sampling shortest paths between uniformly chosen pairs
+// of nodes.
+//
+// With probability at least 1-delta, every returned value is within
+// eps*n*(n-1) of the exact betweenness, where n is the number of nodes in g.
+// The number of sampled paths is
+//
+//  r = (0.5 / eps^2) * (floor(log2(VD-2)) + 1 + ln(1/delta))
+//
+// where VD is an upper bound on the number of nodes in a shortest path of g,
+// and is independent of the size of g. ApproxBetweenness will panic if eps
+// or delta is not in (0, 1).
+//
+// The sampling is performed by the given number of concurrent workers, or
+// by GOMAXPROCS workers if workers is not positive. If src is nil the
+// global random source is used to seed the workers, otherwise src is used.
+// For a given src and number of workers the result is deterministic.
+//
+// See Riondato and Kornaropoulos, "Fast approximation of betweenness
+// centrality through sampling", WSDM 2014, doi:10.1145/2556195.2556224.
+func ApproxBetweenness(g graph.Graph, eps, delta float64, workers int, src rand.Source) map[int64]float64 {
+	checkApproxParams(eps, delta)
+	idx := newIndexed(g)
+	n := len(idx.nodes)
+	cb := make(map[int64]float64)
+	if n < 3 {
+		return cb
+	}
+
+	vd := idx.vertexDiameterBound(g)
+	var logVD float64
+	if vd > 2 {
+		logVD = math.Floor(math.Log2(float64(vd - 2)))
+	}
+	r := int(math.Ceil(0.5 / (eps * eps) * (logVD + 1 + math.Log(1/delta))))
+	scale := float64(n) * float64(n-1) / float64(r)
+
+	counts := parallelSamples(r, workers, src, func(rnd *rand.Rand, samples int) []float64 {
+		count := make([]float64, n)
+		bfs := newPathSampler(n)
+		for i := 0; i < samples; i++ {
+			s := rnd.Intn(n)
+			t := rnd.Intn(n - 1)
+			if t >= s {
+				t++
+			}
+			bfs.sample(g, idx, s, t, rnd, count)
+		}
+		return count
+	})
+	for i, c := range counts {
+		if c != 0 {
+			cb[idx.nodes[i].ID()] = c * scale
+		}
+	}
+	return cb
+}
+
+// ApproxCloseness returns an approximation of the closeness centrality for
+// nodes in the graph g, as computed exactly by Closeness, by estimating the
+// sum of distances to each node from the distances from a sample of
+// uniformly chosen pivot nodes. If g is a graph.Weighted, the edge weights
+// are used as distances, otherwise each edge has unit length.
+//
+// With probability at least 1-delta, the estimated sum of distances to every
+// node, the reciprocal of the returned value, is within eps*n*D of the exact
+// sum, where n is the number of nodes in g and D is the largest finite
+// distance in g. The number of pivots is
+//
+//  k = ln(2n/delta) / (2 eps^2).
+//
+// ApproxCloseness will panic if eps or delta is not in (0, 1).
+//
+// The shortest paths from the pivots are computed by the given number of
+// concurrent workers, or by GOMAXPROCS workers if workers is not positive.
+// If src is nil the global random source is used to choose the pivots,
+// otherwise src is used.
+//
+// See Eppstein and Wang, "Fast approximation of centrality", Journal of
+// Graph Algorithms and Applications 8(1):39-45, 2004, and Brandes and Pich,
+// "Centrality estimation in large networks", International Journal of
+// Bifurcation and Chaos 17(7):2303-2318, 2007.
+func ApproxCloseness(g graph.Graph, eps, delta float64, workers int, src rand.Source) map[int64]float64 {
+	checkApproxParams(eps, delta)
+	idx := newIndexed(g)
+	n := len(idx.nodes)
+	c := make(map[int64]float64, n)
+	if n == 0 {
+		return c
+	}
+
+	k := int(math.Ceil(math.Log(2*float64(n)/delta) / (2 * eps * eps)))
+	intn := rand.Intn
+	if src != nil {
+		intn = rand.New(src).Intn
+	}
+	pivots := make([]int, k)
+	for i := range pivots {
+		pivots[i] = intn(n)
+	}
+
+	sums := parallel(k, workers, func(_, lo, hi int) []float64 {
+		sum := make([]float64, n)
+		for _, p := range pivots[lo:hi] {
+			sp := path.DijkstraFrom(idx.nodes[p], g)
+			for i, v := range idx.nodes {
+				d := sp.WeightTo(v.ID())
+				if math.IsInf(d, 0) {
+					continue
+				}
+				sum[i] += d
+			}
+		}
+		return sum
+	})
+	scale := float64(n) / float64(k)
+	for i, v := range idx.nodes {
+		c[v.ID()] = 1 / (sums[i] * scale)
+	}
+	return c
+}
+
+// checkApproxParams panics if eps or delta is not in (0, 1).
+func checkApproxParams(eps, delta float64) {
+	if !(0 < eps && eps < 1) {
+		panic("network: approximation error bound not in (0, 1)")
+	}
+	if !(0 < delta && delta < 1) {
+		panic("network: approximation failure probability not in (0, 1)")
+	}
+}
+
+// parallelSamples divides r samples between workers, each with its own
+// random source seeded from src, and returns the element-wise sum of the
+// vectors returned by each worker's call to fn.
+func parallelSamples(r, workers int, src rand.Source, fn func(rnd *rand.Rand, samples int) []float64) []float64 {
+	uint64n := rand.Uint64
+	if src != nil {
+		uint64n = rand.New(src).Uint64
+	}
+	w := numWorkers(r, workers)
+	seeds := make([]uint64, w)
+	for i := range seeds {
+		seeds[i] = uint64n()
+	}
+	return parallel(r, w, func(i, lo, hi int) []float64 {
+		return fn(rand.New(rand.NewSource(seeds[i])), hi-lo)
+	})
+}
+
+// parallel divides the range [0, n) into contiguous blocks that are passed
+// to fn with the index of the block by concurrent workers, and returns the
+// element-wise sum of the vectors returned by fn in block order.
+func parallel(n, workers int, fn func(i, lo, hi int) []float64) []float64 {
+	w := numWorkers(n, workers)
+	results := make([][]float64, w)
+	var wg sync.WaitGroup
+	wg.Add(w)
+	for i := 0; i < w; i++ {
+		go func(i int) {
+			defer wg.Done()
+			results[i] = fn(i, i*n/w, (i+1)*n/w)
+		}(i)
+	}
+	wg.Wait()
+	sum := results[0]
+	for _, r := range results[1:] {
+		for j, v := range r {
+			sum[j] += v
+		}
+	}
+	return sum
+}
+
+// numWorkers returns the number of workers to use for n tasks.
+func numWorkers(n, workers int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// indexed holds the nodes of a graph ordered by ID and their indices.
+type indexed struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+}
+
+func newIndexed(g graph.Graph) indexed {
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	return indexed{nodes: nodes, indexOf: indexOf}
+}
+
+// vertexDiameterBound returns an upper bound on the number of nodes in a
+// shortest path of g. For undirected graphs the bound is obtained from a
+// breadth-first search in each connected component, and is at most twice
+// the vertex diameter. For directed graphs the number of nodes is returned.
+func (idx indexed) vertexDiameterBound(g graph.Graph) int {
+	n := len(idx.nodes)
+	if _, ok := g.(graph.Undirected); !ok {
+		return n
+	}
+	dist := make([]int, n)
+	for i := range dist {
+		dist[i] = -1
+	}
+	var bound int
+	queue := make([]int, 0, n)
+	for root := range idx.nodes {
+		if dist[root] >= 0 {
+			continue
+		}
+		dist[root] = 0
+		var ecc int
+		queue = append(queue[:0], root)
+		for len(queue) != 0 {
+			u := queue[0]
+			queue = queue[1:]
+			ecc = dist[u]
+			to := g.From(idx.nodes[u].ID())
+			for to.Next() {
+				v := idx.indexOf[to.Node().ID()]
+				if dist[v] < 0 {
+					dist[v] = dist[u] + 1
+					queue = append(queue, v)
+				}
+			}
+		}
+		// Any shortest path in the component has
+		// at most 2*ecc edges.
+		if b := 2*ecc + 1; b > bound {
+			bound = b
+		}
+	}
+	return bound
+}
+
+// pathSampler samples uniformly chosen shortest paths between pairs of
+// nodes using breadth-first search.
+type pathSampler struct {
+	dist    []int
+	sigma   []float64
+	pred    [][]int
+	touched []int
+	queue   []int
+}
+
+func newPathSampler(n int) *pathSampler {
+	s := &pathSampler{
+		dist:  make([]int, n),
+		sigma: make([]float64, n),
+		pred:  make([][]int, n),
+	}
+	for i := range s.dist {
+		s.dist[i] = -1
+	}
+	return s
+}
+
+// sample chooses a shortest path from s to t uniformly at random and adds
+// one to count for each of its internal nodes. If there is no path from s
+// to t, count is not altered.
+func (p *pathSampler) sample(g graph.Graph, idx indexed, s, t int, rnd *rand.Rand, count []float64) {
+	p.dist[s] = 0
+	p.sigma[s] = 1
+	p.touched = append(p.touched[:0], s)
+	p.queue = append(p.queue[:0], s)
+	for len(p.queue) != 0 {
+		u := p.queue[0]
+		p.queue = p.queue[1:]
+		if p.dist[t] >= 0 && p.dist[u] >= p.dist[t] {
+			// All shortest paths to t are known.
+			break
+		}
+		to := g.From(idx.nodes[u].ID())
+		for to.Next() {
+			v := idx.indexOf[to.Node().ID()]
+			if p.dist[v] < 0 {
+				p.dist[v] = p.dist[u] + 1
+				p.touched = append(p.touched, v)
+				p.queue = append(p.queue, v)
+			}
+			if p.dist[v] == p.dist[u]+1 {
+				p.sigma[v] += p.sigma[u]
+				p.pred[v] = append(p.pred[v], u)
+			}
+		}
+	}
+
+	if p.dist[t] >= 0 {
+		// Walk back from t choosing each predecessor
+		// with probability proportional to the number
+		// of shortest paths through it. Predecessors
+		// are sorted so that the choice does not depend
+		// on the iteration order of g.
+		for w := t; ; {
+			sort.Ints(p.pred[w])
+			x := rnd.Float64() * p.sigma[w]
+			var v int
+			for _, v = range p.pred[w] {
+				x -= p.sigma[v]
+				if x < 0 {
+					break
+				}
+			}
+			if v == s {
+				break
+			}
+			count[v]++
+			w = v
+		}
+	}
+
+	for _, v := range p.touched {
+		p.dist[v] = -1
+		p.sigma[v] = 0
+		p.pred[v] = p.pred[v][:0]
+	}
+}
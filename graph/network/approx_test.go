@@ -0,0 +1,177 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestApproxBetweenness(t *testing.T) {
+	t.Parallel()
+	const (
+		eps   = 0.05
+		delta = 0.01
+	)
+	var graphs []graph.Graph
+	for _, test := range betweennessTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	ug := simple.NewUndirectedGraph()
+	err := gen.Gnp(ug, 100, 0.05, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error generating graph: %v", err)
+	}
+	dg := simple.NewDirectedGraph()
+	err = gen.Gnp(dg, 100, 0.03, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error generating graph: %v", err)
+	}
+	graphs = append(graphs, ug, dg)
+
+	for i, g := range graphs {
+		want := Betweenness(g)
+		n := float64(g.Nodes().Len())
+		tol := eps * n * (n - 1)
+		for _, workers := range []int{1, 3, 0} {
+			got := ApproxBetweenness(g, eps, delta, workers, rand.NewSource(1))
+			for _, u := range graph.NodesOf(g.Nodes()) {
+				id := u.ID()
+				if math.Abs(got[id]-want[id]) > tol {
+					t.Errorf("unexpected betweenness for test %d node %d with %d workers: got %v, want %v±%v",
+						i, id, workers, got[id], want[id], tol)
+				}
+			}
+
+			again := ApproxBetweenness(g, eps, delta, workers, rand.NewSource(1))
+			if len(again) != len(got) {
+				t.Errorf("non-deterministic result for test %d with %d workers", i, workers)
+			}
+			for id, v := range got {
+				if again[id] != v {
+					t.Errorf("non-deterministic result for test %d node %d with %d workers: %v != %v",
+						i, id, workers, again[id], v)
+				}
+			}
+		}
+	}
+}
+
+func TestApproxCloseness(t *testing.T) {
+	t.Parallel()
+	const (
+		eps   = 0.05
+		delta = 0.01
+	)
+	var graphs []graph.Graph
+	for _, test := range undirectedCentralityTests {
+		g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	for _, test := range directedCentralityTests {
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	wg := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for u := 0; u < 60; u++ {
+		wg.AddNode(simple.Node(u))
+		if u != 0 {
+			// Connect to an earlier node so the
+			// graph is connected.
+			v := rnd.Intn(u)
+			wg.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1 + rnd.Float64()})
+		}
+	}
+	graphs = append(graphs, wg)
+
+	for i, g := range graphs {
+		p := path.DijkstraAllPaths(g)
+		want := Closeness(g, p)
+		var diam float64
+		for _, u := range graph.NodesOf(g.Nodes()) {
+			for _, v := range graph.NodesOf(g.Nodes()) {
+				if d := p.Weight(u.ID(), v.ID()); !math.IsInf(d, 1) {
+					diam = math.Max(diam, d)
+				}
+			}
+		}
+		n := float64(g.Nodes().Len())
+		tol := eps * n * diam
+		for _, workers := range []int{1, 4} {
+			got := ApproxCloseness(g, eps, delta, workers, rand.NewSource(1))
+			for _, u := range graph.NodesOf(g.Nodes()) {
+				id := u.ID()
+				gotFar := 1 / got[id]
+				wantFar := 1 / want[id]
+				if math.Abs(gotFar-wantFar) > tol {
+					t.Errorf("unexpected farness estimate for test %d node %d with %d workers: got %v, want %v±%v",
+						i, id, workers, gotFar, wantFar, tol)
+				}
+			}
+		}
+	}
+}
+
+func TestApproxPanics(t *testing.T) {
+	t.Parallel()
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	for _, test := range []struct{ eps, delta float64 }{
+		{eps: 0, delta: 0.1},
+		{eps: 1, delta: 0.1},
+		{eps: 0.1, delta: 0},
+		{eps: 0.1, delta: math.NaN()},
+	} {
+		for _, fn := range []func(graph.Graph, float64, float64, int, rand.Source) map[int64]float64{
+			ApproxBetweenness,
+			ApproxCloseness,
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected panic for eps=%v delta=%v", test.eps, test.delta)
+					}
+				}()
+				fn(g, test.eps, test.delta, 1, nil)
+			}()
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Flow is a flow between a source and a sink node of a directed graph.
+type Flow struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+	s, t    int
+
+	// The arcs of the residual network are
+	// stored in pairs, so the reverse of arc
+	// a is arc a^1. The arcs leaving node u
+	// are the indices held in from[u] and the
+	// node an arc a enters is head[a].
+	head []int
+	cap  []float64
+	res  []float64
+	from [][]int
+
+	// cost holds the cost per unit flow of
+	// each arc for minimum cost flows.
+	cost []float64
+
+	value     float64
+	totalCost float64
+}
+
+// Value returns the value of the flow.
+func (f *Flow) Value() float64 {
+	return f.value
+}
+
+// EdgeFlow returns the flow along the edge from u to v. If there is no
+// such edge in the graph, EdgeFlow returns zero.
+func (f *Flow) EdgeFlow(uid, vid int64) float64 {
+	u, ok := f.indexOf[uid]
+	if !ok {
+		return 0
+	}
+	v, ok := f.indexOf[vid]
+	if !ok {
+		return 0
+	}
+	for _, a := range f.from[u] {
+		if a&1 == 0 && f.head[a] == v {
+			return f.res[a^1]
+		}
+	}
+	return 0
+}
+
+// MinCut returns a minimum cut separating the source from the sink. The
+// nodes on the source side of the cut are returned in source, and the
+// edges crossing from the source side to the sink side, whose capacities
+// sum to the value of the flow, are returned in cut. The returned cut is
+// only a minimum cut if the receiver is a maximum flow.
+func (f *Flow) MinCut() (source []graph.Node, cut []graph.Edge) {
+	reached := make([]bool, len(f.nodes))
+	reached[f.s] = true
+	queue := []int{f.s}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		source = append(source, f.nodes[u])
+		for _, a := range f.from[u] {
+			v := f.head[a]
+			if !reached[v] && f.res[a] > 0 {
+				reached[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	for _, u := range source {
+		ui := f.indexOf[u.ID()]
+		for _, a := range f.from[ui] {
+			if a&1 == 0 && !reached[f.head[a]] {
+				cut = append(cut, edge{from: u, to: f.nodes[f.head[a]], weight: f.cap[a]})
+			}
+		}
+	}
+	return source, cut
+}
+
+// edge is a graph edge with a capacity.
+type edge struct {
+	from, to graph.Node
+	weight   float64
+}
+
+func (e edge) From() graph.Node         { return e.from }
+func (e edge) To() graph.Node           { return e.to }
+func (e edge) ReversedEdge() graph.Edge { e.from, e.to = e.to, e.from; return e }
+func (e edge) Weight() float64          { return e.weight }
+
+// newFlow returns a zero flow from s to t in g with edge capacities given by
+// capacity. If capacity is nil, the capacity of each edge is its weight if g
+// is a graph.Weighted, and one otherwise. newFlow panics if s or t is not in
+// g, if s and t are the same node or if any edge has a negative or NaN
+// capacity.
+func newFlow(g graph.Directed, s, t graph.Node, capacity func(uid, vid int64) float64) *Flow {
+	if g.Node(s.ID()) == nil {
+		panic("network: source not in graph")
+	}
+	if g.Node(t.ID()) == nil {
+		panic("network: sink not in graph")
+	}
+	if s.ID() == t.ID() {
+		panic("network: source and sink are the same node")
+	}
+
+	if capacity == nil {
+		capacity = func(uid, vid int64) float64 { return 1 }
+		if wg, ok := g.(graph.Weighted); ok {
+			capacity = func(uid, vid int64) float64 {
+				w, _ := wg.Weight(uid, vid)
+				return w
+			}
+		}
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	f := &Flow{
+		nodes:   nodes,
+		indexOf: make(map[int64]int, len(nodes)),
+		from:    make([][]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		f.indexOf[n.ID()] = i
+	}
+	f.s = f.indexOf[s.ID()]
+	f.t = f.indexOf[t.ID()]
+	for u, n := range nodes {
+		uid := n.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			c := capacity(uid, vid)
+			if !(c >= 0) {
+				panic("network: negative or NaN edge capacity")
+			}
+			v := f.indexOf[vid]
+			a := len(f.head)
+			f.head = append(f.head, v, u)
+			f.cap = append(f.cap, c, 0)
+			f.res = append(f.res, c, 0)
+			f.from[u] = append(f.from[u], a)
+			f.from[v] = append(f.from[v], a+1)
+		}
+	}
+	return f
+}
+
+// push moves x units of flow along arc a of the residual network.
+func (f *Flow) push(a int, x float64) {
+	f.res[a] -= x
+	f.res[a^1] += x
+}
+
+// MaxFlowDinic returns a maximum flow from s to t in g computed using
+// Dinic's algorithm. The capacity of each edge is its weight if g is a
+// graph.Weighted, and one otherwise. MaxFlowDinic will panic if s or t is
+// not in g, if s and t are the same node or if g has an edge with a negative
+// capacity.
+//
+// The time complexity of MaxFlowDinic is O(|V|^2 |E|).
+func MaxFlowDinic(g graph.Directed, s, t graph.Node) *Flow {
+	f := newFlow(g, s, t, nil)
+	n := len(f.nodes)
+	level := make([]int, n)
+	next := make([]int, n)
+	queue := make([]int, 0, n)
+	for {
+		// Build the level graph of the residual network
+		// with a breadth-first search from the source.
+		for i := range level {
+			level[i] = -1
+		}
+		level[f.s] = 0
+		queue = append(queue[:0], f.s)
+		for len(queue) != 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, a := range f.from[u] {
+				v := f.head[a]
+				if level[v] < 0 && f.res[a] > 0 {
+					level[v] = level[u] + 1
+					queue = append(queue, v)
+				}
+			}
+		}
+		if level[f.t] < 0 {
+			break
+		}
+
+		// Find a blocking flow in the level graph.
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			x := f.augment(f.s, math.Inf(1), level, next)
+			if x == 0 {
+				break
+			}
+			f.value += x
+		}
+	}
+	return f
+}
+
+// augment pushes flow of at most limit from u to the sink along paths of
+// increasing level, returning the amount of flow pushed. The index of the
+// next arc to try for each node is held in next.
+func (f *Flow) augment(u int, limit float64, level, next []int) float64 {
+	if u == f.t {
+		return limit
+	}
+	for ; next[u] < len(f.from[u]); next[u]++ {
+		a := f.from[u][next[u]]
+		v := f.head[a]
+		if f.res[a] <= 0 || level[v] != level[u]+1 {
+			continue
+		}
+		x := f.augment(v, math.Min(limit, f.res[a]), level, next)
+		if x > 0 {
+			f.push(a, x)
+			return x
+		}
+	}
+	return 0
+}
+
+// MaxFlowPushRelabel returns a maximum flow from s to t in g computed using
+// the push-relabel algorithm of Goldberg and Tarjan with first-in first-out
+// selection of active nodes and the gap relabelling heuristic. The capacity
+// of each edge is its weight if g is a graph.Weighted, and one otherwise.
+// MaxFlowPushRelabel will panic if s or t is not in g, if s and t are the
+// same node or if g has an edge with a negative capacity.
+//
+// The time complexity of MaxFlowPushRelabel is O(|V|^3).
+func MaxFlowPushRelabel(g graph.Directed, s, t graph.Node) *Flow {
+	f := newFlow(g, s, t, nil)
+	n := len(f.nodes)
+	height := make([]int, n)
+	excess := make([]float64, n)
+	// count holds the number of nodes at each height
+	// for the gap heuristic.
+	count := make([]int, 2*n+1)
+	active := make([]bool, n)
+	// current holds the index into f.from of the next
+	// arc to examine when discharging each node.
+	current := make([]int, n)
+	var queue []int
+	enqueue := func(v int) {
+		if !active[v] && v != f.s && v != f.t && excess[v] > 0 {
+			active[v] = true
+			queue = append(queue, v)
+		}
+	}
+
+	height[f.s] = n
+	count[0] = n - 1
+	count[n] = 1
+	for _, a := range f.from[f.s] {
+		if x := f.res[a]; x > 0 {
+			v := f.head[a]
+			f.push(a, x)
+			excess[v] += x
+			excess[f.s] -= x
+			enqueue(v)
+		}
+	}
+
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+
+		// Discharge u, resuming the scan of its arcs at
+		// its current arc.
+		for excess[u] > 0 {
+			if current[u] < len(f.from[u]) {
+				a := f.from[u][current[u]]
+				v := f.head[a]
+				if f.res[a] > 0 && height[u] == height[v]+1 {
+					x := math.Min(excess[u], f.res[a])
+					f.push(a, x)
+					excess[u] -= x
+					excess[v] += x
+					enqueue(v)
+				} else {
+					current[u]++
+				}
+				continue
+			}
+
+			minHeight := 2 * n
+			for _, a := range f.from[u] {
+				if f.res[a] > 0 && height[f.head[a]] < minHeight {
+					minHeight = height[f.head[a]]
+				}
+			}
+			if minHeight == 2*n {
+				// There is no residual arc leaving u, so
+				// the excess is due to rounding error.
+				excess[u] = 0
+				break
+			}
+
+			// Relabel u. If u was the only node at its
+			// height, no node above the gap can reach
+			// the sink, so lift them above the source.
+			old := height[u]
+			count[old]--
+			height[u] = minHeight + 1
+			count[height[u]]++
+			current[u] = 0
+			if count[old] == 0 && old < n {
+				for v, h := range height {
+					if h > old && h < n {
+						count[h]--
+						height[v] = n + 1
+						count[n+1]++
+						current[v] = 0
+					}
+				}
+			}
+		}
+	}
+	f.value = excess[f.t]
+	return f
+}
@@ -0,0 +1,236 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var maxFlowTests = []struct {
+	name  string
+	g     []simple.WeightedEdge
+	s, t  int64
+	want  float64
+	nodes int // Number of nodes on the source side of the minimum cut.
+}{
+	{
+		// Example from Cormen et al. Introduction to Algorithms figure 26.6.
+		name: "clrs",
+		g: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 16},
+			{F: simple.Node(0), T: simple.Node(2), W: 13},
+			{F: simple.Node(2), T: simple.Node(1), W: 4},
+			{F: simple.Node(1), T: simple.Node(3), W: 12},
+			{F: simple.Node(3), T: simple.Node(2), W: 9},
+			{F: simple.Node(2), T: simple.Node(4), W: 14},
+			{F: simple.Node(4), T: simple.Node(3), W: 7},
+			{F: simple.Node(3), T: simple.Node(5), W: 20},
+			{F: simple.Node(4), T: simple.Node(5), W: 4},
+		},
+		s: 0, t: 5,
+		want:  23,
+		nodes: 4,
+	},
+	{
+		name: "disconnected",
+		g: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(2), T: simple.Node(3), W: 3},
+		},
+		s: 0, t: 3,
+		want:  0,
+		nodes: 2,
+	},
+	{
+		name: "antiparallel",
+		g: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(0), W: 5},
+			{F: simple.Node(1), T: simple.Node(2), W: 1.5},
+			{F: simple.Node(0), T: simple.Node(2), W: 0.25},
+			{F: simple.Node(2), T: simple.Node(1), W: 1},
+		},
+		s: 0, t: 2,
+		want:  1.75,
+		nodes: 2,
+	},
+	{
+		name: "bottleneck",
+		g: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 10},
+			{F: simple.Node(0), T: simple.Node(2), W: 10},
+			{F: simple.Node(1), T: simple.Node(3), W: 10},
+			{F: simple.Node(2), T: simple.Node(3), W: 10},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+			{F: simple.Node(4), T: simple.Node(5), W: 10},
+		},
+		s: 0, t: 5,
+		want:  1,
+		nodes: 4,
+	},
+}
+
+var maxFlowFuncs = []struct {
+	name string
+	fn   func(graph.Directed, graph.Node, graph.Node) *Flow
+}{
+	{name: "Dinic", fn: MaxFlowDinic},
+	{name: "PushRelabel", fn: MaxFlowPushRelabel},
+}
+
+func TestMaxFlow(t *testing.T) {
+	t.Parallel()
+	for _, test := range maxFlowTests {
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for _, e := range test.g {
+			g.SetWeightedEdge(e)
+		}
+		for _, alg := range maxFlowFuncs {
+			f := alg.fn(g, simple.Node(test.s), simple.Node(test.t))
+			if !scalar.EqualWithinAbsOrRel(f.Value(), test.want, 1e-12, 1e-12) {
+				t.Errorf("unexpected flow value for %s %s: got %v, want %v", alg.name, test.name, f.Value(), test.want)
+			}
+			source, _ := f.MinCut()
+			if len(source) != test.nodes {
+				t.Errorf("unexpected number of source side nodes for %s %s: got %d, want %d", alg.name, test.name, len(source), test.nodes)
+			}
+			checkFlow(t, alg.name+" "+test.name, g, f, simple.Node(test.s), simple.Node(test.t))
+		}
+	}
+}
+
+func TestMaxFlowUnweighted(t *testing.T) {
+	t.Parallel()
+	// Bipartite matching between {1, 2, 3} and {4, 5, 6} with
+	// source 0 and sink 7. The maximum matching has size 2.
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{
+		{0, 1}, {0, 2}, {0, 3},
+		{1, 4}, {2, 4}, {3, 4}, {3, 5}, {1, 6}, {2, 6},
+		{4, 7}, {5, 7},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	for _, alg := range maxFlowFuncs {
+		f := alg.fn(g, simple.Node(0), simple.Node(7))
+		if f.Value() != 2 {
+			t.Errorf("unexpected matching size for %s: got %v, want 2", alg.name, f.Value())
+		}
+		checkFlow(t, alg.name, g, f, simple.Node(0), simple.Node(7))
+	}
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := 2 + rnd.Intn(30)
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for j := 0; j < n; j++ {
+			g.AddNode(simple.Node(j))
+		}
+		p := rnd.Float64()
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if u == v || rnd.Float64() > p {
+					continue
+				}
+				w := 10 * rnd.Float64()
+				if i%2 == 0 {
+					// Use integer capacities for half of the tests.
+					w = math.Floor(w)
+				}
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w})
+			}
+		}
+		s := simple.Node(rnd.Intn(n))
+		t0 := simple.Node(rnd.Intn(n - 1))
+		if t0 >= s {
+			t0++
+		}
+
+		var want float64
+		for k, alg := range maxFlowFuncs {
+			f := alg.fn(g, s, t0)
+			checkFlow(t, alg.name, g, f, s, t0)
+			if k == 0 {
+				want = f.Value()
+				continue
+			}
+			if !scalar.EqualWithinAbsOrRel(f.Value(), want, 1e-10, 1e-10) {
+				t.Errorf("mismatched flow values for test %d: got %v, want %v", i, f.Value(), want)
+			}
+		}
+	}
+}
+
+// checkFlow checks that f satisfies the capacity and conservation
+// constraints of g, and that its minimum cut matches its value.
+func checkFlow(t *testing.T, name string, g graph.Directed, f *Flow, s, sink graph.Node) {
+	t.Helper()
+	const tol = 1e-10
+	capacity := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		capacity = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	net := make(map[int64]float64)
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			x := f.EdgeFlow(uid, vid)
+			if x < -tol || x > capacity(uid, vid)+tol {
+				t.Errorf("flow out of capacity bounds for %s edge %d->%d: %v", name, uid, vid, x)
+			}
+			net[uid] -= x
+			net[vid] += x
+		}
+	}
+	for id, x := range net {
+		switch id {
+		case s.ID():
+			x = -x
+			fallthrough
+		case sink.ID():
+			if !scalar.EqualWithinAbsOrRel(x, f.Value(), tol, tol) {
+				t.Errorf("unexpected net flow at terminal %d for %s: got %v, want %v", id, name, x, f.Value())
+			}
+		default:
+			if math.Abs(x) > tol {
+				t.Errorf("flow not conserved for %s at node %d: %v", name, id, x)
+			}
+		}
+	}
+
+	source, cut := f.MinCut()
+	onSource := make(map[int64]bool)
+	for _, n := range source {
+		onSource[n.ID()] = true
+	}
+	if !onSource[s.ID()] || onSource[sink.ID()] {
+		t.Errorf("cut does not separate source and sink for %s", name)
+	}
+	var cutCap float64
+	for _, e := range cut {
+		if !onSource[e.From().ID()] || onSource[e.To().ID()] {
+			t.Errorf("cut edge %d->%d does not cross the cut for %s", e.From().ID(), e.To().ID(), name)
+		}
+		cutCap += e.(graph.WeightedEdge).Weight()
+	}
+	if !scalar.EqualWithinAbsOrRel(cutCap, f.Value(), tol, tol) {
+		t.Errorf("cut capacity does not match flow value for %s: got %v, want %v", name, cutCap, f.Value())
+	}
+}
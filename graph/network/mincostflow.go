@@ -0,0 +1,173 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Cost returns the total cost of a minimum cost flow. It returns zero for
+// flows that were not computed by MinCostFlow.
+func (f *Flow) Cost() float64 {
+	return f.totalCost
+}
+
+// MinCostFlow returns a flow of value at most limit from s to t in g that has
+// the minimum total cost among all flows of its value. If limit is +Inf, the
+// returned flow is a minimum cost maximum flow.
+//
+// The capacity and cost per unit flow of the edge from u to v are given by
+// capacity and cost. If capacity is nil, the capacity of each edge is its
+// weight if g is a graph.Weighted, and one otherwise. If cost is nil, the
+// cost of each edge is one. Costs may be negative, but g must not have a
+// cycle of edges with negative total cost and positive capacity.
+// MinCostFlow will panic if s or t is not in g, if s and t are the same node,
+// if g has an edge with a negative capacity, if limit is negative, if g has a
+// negative cost cycle or if limit is +Inf and g has a path from s to t with
+// infinite capacity, making the flow unbounded.
+//
+// MinCostFlow uses the successive shortest path algorithm, augmenting flow
+// along the cheapest path in the residual network found by Dijkstra's
+// algorithm with node potentials. The number of augmentations is bounded by
+// the value of the flow when capacities are integers.
+func MinCostFlow(g graph.Directed, s, t graph.Node, limit float64, capacity, cost func(uid, vid int64) float64) *Flow {
+	if !(limit >= 0) {
+		panic("network: negative flow limit")
+	}
+	f := newFlow(g, s, t, capacity)
+	if cost == nil {
+		cost = func(uid, vid int64) float64 { return 1 }
+	}
+	n := len(f.nodes)
+	f.cost = make([]float64, len(f.head))
+	for a := 0; a < len(f.head); a += 2 {
+		c := cost(f.nodes[f.head[a+1]].ID(), f.nodes[f.head[a]].ID())
+		f.cost[a] = c
+		f.cost[a+1] = -c
+	}
+
+	// Initialise the node potentials with the distances
+	// from the source using the Bellman-Ford algorithm so
+	// that negative costs are allowed.
+	potential := make([]float64, n)
+	for i := range potential {
+		potential[i] = math.Inf(1)
+	}
+	potential[f.s] = 0
+	for i := 0; ; i++ {
+		var changed bool
+		for u, arcs := range f.from {
+			if math.IsInf(potential[u], 1) {
+				continue
+			}
+			for _, a := range arcs {
+				if f.res[a] <= 0 {
+					continue
+				}
+				if d := potential[u] + f.cost[a]; d < potential[f.head[a]] {
+					potential[f.head[a]] = d
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+		if i == n {
+			panic("network: negative cost cycle")
+		}
+	}
+
+	dist := make([]float64, n)
+	via := make([]int, n)
+	done := make([]bool, n)
+	var queue distanceQueue
+	for f.value < limit {
+		// Find the cheapest augmenting path using the
+		// reduced costs, which are non-negative.
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			via[i] = -1
+			done[i] = false
+		}
+		dist[f.s] = 0
+		queue = append(queue[:0], distance{node: f.s})
+		for queue.Len() != 0 {
+			u := heap.Pop(&queue).(distance).node
+			if done[u] {
+				continue
+			}
+			done[u] = true
+			for _, a := range f.from[u] {
+				if f.res[a] <= 0 {
+					continue
+				}
+				v := f.head[a]
+				reduced := f.cost[a] + potential[u] - potential[v]
+				if reduced < 0 {
+					// Correct for rounding error.
+					reduced = 0
+				}
+				if d := dist[u] + reduced; d < dist[v] {
+					dist[v] = d
+					via[v] = a
+					heap.Push(&queue, distance{node: v, dist: d})
+				}
+			}
+		}
+		if !done[f.t] {
+			break
+		}
+		for i, d := range dist {
+			if done[i] {
+				potential[i] += d
+			}
+		}
+
+		// Augment along the path by its bottleneck
+		// capacity, or up to the limit.
+		x := limit - f.value
+		for v := f.t; v != f.s; v = f.head[via[v]^1] {
+			x = math.Min(x, f.res[via[v]])
+		}
+		if math.IsInf(x, 1) {
+			panic("network: unbounded flow")
+		}
+		for v := f.t; v != f.s; v = f.head[via[v]^1] {
+			f.push(via[v], x)
+		}
+		f.value += x
+	}
+
+	for a := 0; a < len(f.head); a += 2 {
+		if x := f.res[a^1]; x != 0 {
+			f.totalCost += x * f.cost[a]
+		}
+	}
+	return f
+}
+
+// distance is a node and its distance from the source.
+type distance struct {
+	node int
+	dist float64
+}
+
+// distanceQueue is a priority queue of distances.
+type distanceQueue []distance
+
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(n interface{}) { *q = append(*q, n.(distance)) }
+func (q *distanceQueue) Pop() interface{} {
+	t := *q
+	var n distance
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
@@ -0,0 +1,255 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestMinCostFlowAssignment(t *testing.T) {
+	t.Parallel()
+	// Assign workers 1-3 to jobs 4-6 with source 0 and sink 7.
+	costs := [3][3]float64{
+		{9, 2, 7},
+		{6, 4, 3},
+		{5, 8, 1},
+	}
+	g := simple.NewDirectedGraph()
+	cost := make(map[[2]int64]float64)
+	for i := int64(1); i <= 3; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(i)})
+		g.SetEdge(simple.Edge{F: simple.Node(i + 3), T: simple.Node(7)})
+		for j := int64(4); j <= 6; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+			cost[[2]int64{i, j}] = costs[i-1][j-4]
+		}
+	}
+	costFn := func(uid, vid int64) float64 { return cost[[2]int64{uid, vid}] }
+
+	f := MinCostFlow(g, simple.Node(0), simple.Node(7), math.Inf(1), nil, costFn)
+	if f.Value() != 3 {
+		t.Errorf("unexpected flow value: got %v, want 3", f.Value())
+	}
+	// The optimal assignment is 1->5, 2->4 and 3->6.
+	if f.Cost() != 9 {
+		t.Errorf("unexpected assignment cost: got %v, want 9", f.Cost())
+	}
+	for _, e := range [][2]int64{{1, 5}, {2, 4}, {3, 6}} {
+		if f.EdgeFlow(e[0], e[1]) != 1 {
+			t.Errorf("expected assignment %d->%d", e[0], e[1])
+		}
+	}
+	checkMinCostFlow(t, "assignment", g, f, nil, costFn)
+
+	// With a limit of 2 only the two cheapest compatible
+	// assignments are made.
+	f = MinCostFlow(g, simple.Node(0), simple.Node(7), 2, nil, costFn)
+	if f.Value() != 2 || f.Cost() != 3 {
+		t.Errorf("unexpected limited flow: got value %v cost %v, want value 2 cost 3", f.Value(), f.Cost())
+	}
+}
+
+func TestMinCostFlowTransportation(t *testing.T) {
+	t.Parallel()
+	// Ship from two suppliers, 1 and 2, with supplies 20 and 30
+	// to three consumers, 3, 4 and 5, with demands 10, 25 and 15.
+	// Supplies and demands are the capacities of the edges from
+	// the source 0 and to the sink 6.
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	supply := []float64{20, 30}
+	demand := []float64{10, 25, 15}
+	unitCost := [2][3]float64{
+		{8, 6, 10},
+		{9, 12, 13},
+	}
+	cost := make(map[[2]int64]float64)
+	for i, s := range supply {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(i + 1), W: s})
+		for j := range demand {
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i + 1), T: simple.Node(j + 3), W: math.Inf(1)})
+			cost[[2]int64{int64(i + 1), int64(j + 3)}] = unitCost[i][j]
+		}
+	}
+	for j, d := range demand {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(j + 3), T: simple.Node(6), W: d})
+	}
+	costFn := func(uid, vid int64) float64 { return cost[[2]int64{uid, vid}] }
+
+	f := MinCostFlow(g, simple.Node(0), simple.Node(6), math.Inf(1), nil, costFn)
+	if f.Value() != 50 {
+		t.Errorf("unexpected flow value: got %v, want 50", f.Value())
+	}
+	// Supplier 1 sends 20 to consumer 4, and supplier 2 sends
+	// 10 to consumer 3, 5 to consumer 4 and 15 to consumer 5.
+	const want = 20*6 + 10*9 + 5*12 + 15*13
+	if f.Cost() != want {
+		t.Errorf("unexpected transportation cost: got %v, want %v", f.Cost(), want)
+	}
+	checkMinCostFlow(t, "transportation", g, f, nil, costFn)
+}
+
+func TestMinCostFlowRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := 2 + rnd.Intn(20)
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for j := 0; j < n; j++ {
+			g.AddNode(simple.Node(j))
+		}
+		cost := make(map[[2]int64]float64)
+		p := rnd.Float64()
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if u == v || rnd.Float64() > p {
+					continue
+				}
+				w := math.Floor(10 * rnd.Float64())
+				c := math.Floor(10*rnd.Float64()) - 3
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w})
+				cost[[2]int64{int64(u), int64(v)}] = c
+			}
+		}
+		costFn := func(uid, vid int64) float64 { return cost[[2]int64{uid, vid}] }
+		if hasNegativeCycle(g, nil, costFn, nil) {
+			// Minimum cost flows are not defined.
+			continue
+		}
+		s := simple.Node(rnd.Intn(n))
+		t0 := simple.Node(rnd.Intn(n - 1))
+		if t0 >= s {
+			t0++
+		}
+
+		f := MinCostFlow(g, s, t0, math.Inf(1), nil, costFn)
+		want := MaxFlowDinic(g, s, t0).Value()
+		if f.Value() != want {
+			t.Errorf("unexpected flow value for test %d: got %v, want %v", i, f.Value(), want)
+		}
+		checkFlow(t, "random", g, f, s, t0)
+		checkMinCostFlow(t, "random", g, f, nil, costFn)
+	}
+}
+
+func TestMinCostFlowUnitCost(t *testing.T) {
+	t.Parallel()
+	// With unit costs the cheapest unit of flow follows the
+	// shortest path 0->3 rather than 0->1->2->3.
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(2), T: simple.Node(3), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(3), W: 1})
+
+	f := MinCostFlow(g, simple.Node(0), simple.Node(3), 1, nil, nil)
+	if f.Value() != 1 || f.Cost() != 1 {
+		t.Errorf("unexpected flow: got value %v cost %v, want value 1 cost 1", f.Value(), f.Cost())
+	}
+	if f.EdgeFlow(0, 3) != 1 {
+		t.Error("expected flow along shortest path")
+	}
+}
+
+func TestMinCostFlowUnbounded(t *testing.T) {
+	t.Parallel()
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: math.Inf(1)})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: math.Inf(1)})
+
+	// A finite limit bounds the flow.
+	f := MinCostFlow(g, simple.Node(0), simple.Node(2), 5, nil, nil)
+	if f.Value() != 5 || f.Cost() != 10 {
+		t.Errorf("unexpected limited flow: got value %v cost %v, want value 5 cost 10", f.Value(), f.Cost())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unbounded flow")
+		}
+	}()
+	MinCostFlow(g, simple.Node(0), simple.Node(2), math.Inf(1), nil, nil)
+}
+
+// checkMinCostFlow checks that the total cost of f is consistent with its
+// edge flows, and that f has no negative cost cycle in its residual network,
+// which is the optimality condition for minimum cost flows.
+func checkMinCostFlow(t *testing.T, name string, g graph.Directed, f *Flow, capacity, cost func(uid, vid int64) float64) {
+	t.Helper()
+	var total float64
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		to := g.From(u.ID())
+		for to.Next() {
+			v := to.Node()
+			total += f.EdgeFlow(u.ID(), v.ID()) * cost(u.ID(), v.ID())
+		}
+	}
+	if !scalar.EqualWithinAbsOrRel(total, f.Cost(), 1e-10, 1e-10) {
+		t.Errorf("unexpected total cost for %s: got %v, want %v", name, f.Cost(), total)
+	}
+	if hasNegativeCycle(g, capacity, cost, f) {
+		t.Errorf("residual network has a negative cost cycle for %s", name)
+	}
+}
+
+// hasNegativeCycle returns whether the residual network of g with the flow f
+// has a cycle of negative cost. If f is nil, the network g is used.
+func hasNegativeCycle(g graph.Directed, capacity, cost func(uid, vid int64) float64, f *Flow) bool {
+	if capacity == nil {
+		capacity = func(uid, vid int64) float64 { return 1 }
+		if wg, ok := g.(graph.Weighted); ok {
+			capacity = func(uid, vid int64) float64 {
+				w, _ := wg.Weight(uid, vid)
+				return w
+			}
+		}
+	}
+	type arc struct {
+		u, v int64
+		c    float64
+	}
+	var arcs []arc
+	nodes := graph.NodesOf(g.Nodes())
+	for _, u := range nodes {
+		to := g.From(u.ID())
+		for to.Next() {
+			uid, vid := u.ID(), to.Node().ID()
+			var x float64
+			if f != nil {
+				x = f.EdgeFlow(uid, vid)
+			}
+			if x < capacity(uid, vid) {
+				arcs = append(arcs, arc{u: uid, v: vid, c: cost(uid, vid)})
+			}
+			if x > 0 {
+				arcs = append(arcs, arc{u: vid, v: uid, c: -cost(uid, vid)})
+			}
+		}
+	}
+	// Run Bellman-Ford from a virtual node joined to all nodes.
+	dist := make(map[int64]float64)
+	for _, n := range nodes {
+		dist[n.ID()] = 0
+	}
+	for i := 0; i <= len(nodes); i++ {
+		var changed bool
+		for _, a := range arcs {
+			if d := dist[a.u] + a.c; d < dist[a.v]-1e-9 {
+				dist[a.v] = d
+				changed = true
+			}
+		}
+		if !changed {
+			return false
+		}
+	}
+	return true
+}
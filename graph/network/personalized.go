@@ -0,0 +1,192 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/mat"
+)
+
+// PersonalizedPageRank returns the personalized PageRank weights for nodes
+// of the directed graph g using the given damping factor and terminating
+// when the 2-norm of the vector difference between iterations is below tol.
+// The returned map is keyed on the graph node IDs.
+//
+// The random surfer restarts with probability 1-damp at a node drawn from
+// the personalization distribution, which is given by the non-negative
+// weights in personalization keyed on node IDs and normalized to sum to one.
+// A restart set of nodes is specified by giving each node in the set equal
+// weight. The surfer also restarts from the personalization distribution
+// when it reaches a node without out edges.
+// If g is a graph.WeightedDirected, an edge-weighted PageRank is calculated.
+//
+// PersonalizedPageRank will panic if personalization holds a node that is
+// not in g, a negative weight, or has no positive weight.
+func PersonalizedPageRank(g graph.Directed, damp, tol float64, personalization map[int64]float64) map[int64]float64 {
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	restart := make([]float64, len(nodes))
+	var sum float64
+	for id, w := range personalization {
+		i, ok := indexOf[id]
+		if !ok {
+			panic("network: personalization node not in graph")
+		}
+		if !(w >= 0) {
+			panic("network: negative personalization weight")
+		}
+		restart[i] = w
+		sum += w
+	}
+	if !(sum > 0) {
+		panic("network: no positive personalization weight")
+	}
+	floats.Scale(1/sum, restart)
+
+	weight := transitionWeight(g)
+	m := make(rowCompressedMatrix, len(nodes))
+	var dangling compressedRow
+	for j, u := range nodes {
+		to := graph.NodesOf(g.From(u.ID()))
+		var z float64
+		for _, v := range to {
+			z += weight(u.ID(), v.ID())
+		}
+		if z == 0 {
+			dangling.addTo(j, damp)
+			continue
+		}
+		for _, v := range to {
+			if w := weight(u.ID(), v.ID()); w != 0 {
+				m.addTo(indexOf[v.ID()], j, (w*damp)/z)
+			}
+		}
+	}
+
+	last := make([]float64, len(nodes))
+	lastV := mat.NewVecDense(len(nodes), last)
+	vec := make([]float64, len(nodes))
+	copy(vec, restart)
+	v := mat.NewVecDense(len(nodes), vec)
+
+	for {
+		lastV, v = v, lastV
+
+		m.mulVecUnitary(v, lastV)
+		with := dangling.dotUnitary(lastV)
+		away := onesDotUnitary(1-damp, lastV)
+
+		floats.AddScaled(v.RawVector().Data, with+away, restart)
+		if normDiff(vec, last) < tol {
+			break
+		}
+	}
+
+	ranks := make(map[int64]float64, len(nodes))
+	for i, r := range v.RawVector().Data {
+		ranks[nodes[i].ID()] = r
+	}
+
+	return ranks
+}
+
+// ApproxPersonalizedPageRank returns an approximation of the personalized
+// PageRank weights for nodes of the directed graph g with restarts at the
+// source node, using the given damping factor. The returned map is keyed on
+// the graph node IDs and only holds nodes with non-zero weight.
+// If g is a graph.WeightedDirected, an edge-weighted PageRank is calculated.
+//
+// ApproxPersonalizedPageRank uses the local push algorithm of Andersen, Chung
+// and Lang, "Local Graph Partitioning using PageRank Vectors" (2006), which
+// only visits nodes close to the source. Pushing stops when the residual
+// probability at each node is less than eps times its out degree. The
+// remaining residual probability bounds the total error, and each returned
+// weight underestimates the exact weight. The time taken is
+// O(1/(eps(1-damp))), independent of the size of g.
+//
+// ApproxPersonalizedPageRank will panic if source is not in g or eps is not
+// positive.
+func ApproxPersonalizedPageRank(g graph.Directed, source graph.Node, damp, eps float64) map[int64]float64 {
+	if g.Node(source.ID()) == nil {
+		panic("network: source not in graph")
+	}
+	if !(eps > 0) {
+		panic("network: non-positive push tolerance")
+	}
+	weight := transitionWeight(g)
+
+	sid := source.ID()
+	rank := make(map[int64]float64)
+	residual := map[int64]float64{sid: 1}
+	queue := []int64{sid}
+	queued := map[int64]bool{sid: true}
+	for len(queue) != 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		queued[uid] = false
+
+		to := graph.NodesOf(g.From(uid))
+		var z float64
+		for _, v := range to {
+			z += weight(uid, v.ID())
+		}
+		deg := float64(len(to))
+		if z == 0 {
+			deg = 1
+		}
+		r := residual[uid]
+		if r < eps*deg {
+			continue
+		}
+
+		rank[uid] += (1 - damp) * r
+		residual[uid] = 0
+		push := func(vid int64, x float64) {
+			residual[vid] += x
+			if queued[vid] {
+				return
+			}
+			vdeg := float64(g.From(vid).Len())
+			if vdeg == 0 {
+				vdeg = 1
+			}
+			if residual[vid] >= eps*vdeg {
+				queued[vid] = true
+				queue = append(queue, vid)
+			}
+		}
+		if z == 0 {
+			// Restart at the source from dangling nodes.
+			push(sid, damp*r)
+			continue
+		}
+		for _, v := range to {
+			if w := weight(uid, v.ID()); w != 0 {
+				push(v.ID(), damp*r*w/z)
+			}
+		}
+	}
+
+	return rank
+}
+
+// transitionWeight returns a function returning the weight of the edge
+// from u to v used to determine random walk transition probabilities. The
+// weight is the edge weight if g is a graph.WeightedDirected, and one
+// otherwise.
+func transitionWeight(g graph.Directed) func(uid, vid int64) float64 {
+	if wg, ok := g.(graph.WeightedDirected); ok {
+		return func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	return func(uid, vid int64) float64 { return 1 }
+}
@@ -0,0 +1,201 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPersonalizedPageRankUniform(t *testing.T) {
+	// With a uniform personalization vector, personalized
+	// PageRank is the standard PageRank.
+	for i, test := range pageRankTests {
+		g := simple.NewDirectedGraph()
+		personalization := make(map[int64]float64)
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+			personalization[int64(u)] = 1
+		}
+		got := PersonalizedPageRank(g, test.damp, test.tol, personalization)
+		for n := range test.g {
+			if !scalar.EqualWithinAbsOrRel(got[int64(n)], test.want[int64(n)], test.wantTol, test.wantTol) {
+				t.Errorf("unexpected PageRank result for test %d node %c: got %v, want %v",
+					i, n+'A', got[int64(n)], test.want[int64(n)])
+			}
+		}
+	}
+}
+
+func TestPersonalizedPageRankRestartSet(t *testing.T) {
+	// Nodes 0-2 form a cycle that links to the
+	// cycle 3-5, which does not link back.
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 5}, {5, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+
+	got := PersonalizedPageRank(g, 0.85, 1e-12, map[int64]float64{3: 1, 4: 1})
+	checkPersonalizedPageRank(t, "restart set", g, 0.85, map[int64]float64{3: 0.5, 4: 0.5}, got)
+	for _, id := range []int64{0, 1, 2} {
+		if got[id] != 0 {
+			t.Errorf("unexpected weight for node %d not reachable from restart set: %v", id, got[id])
+		}
+	}
+
+	got = PersonalizedPageRank(g, 0.85, 1e-12, map[int64]float64{0: 3, 5: 1})
+	checkPersonalizedPageRank(t, "weighted restart", g, 0.85, map[int64]float64{0: 0.75, 5: 0.25}, got)
+}
+
+func TestPersonalizedPageRankRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 2 + rnd.Intn(30)
+		g := simple.NewWeightedDirectedGraph(0, 0)
+		for u := 0; u < n; u++ {
+			g.AddNode(simple.Node(u))
+		}
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if u != v && rnd.Float64() < 0.15 {
+					g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1 + rnd.Float64()})
+				}
+			}
+		}
+		personalization := map[int64]float64{
+			int64(rnd.Intn(n)): rnd.Float64(),
+			int64(rnd.Intn(n)): 1,
+		}
+		var sum float64
+		for _, w := range personalization {
+			sum += w
+		}
+		restart := make(map[int64]float64)
+		for id, w := range personalization {
+			restart[id] = w / sum
+		}
+		got := PersonalizedPageRank(g, 0.85, 1e-12, personalization)
+		checkPersonalizedPageRank(t, "random", g, 0.85, restart, got)
+	}
+}
+
+func TestApproxPersonalizedPageRank(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 2 + rnd.Intn(50)
+		var g graph.Directed
+		if i%2 == 0 {
+			wg := simple.NewWeightedDirectedGraph(0, 0)
+			for u := 0; u < n; u++ {
+				wg.AddNode(simple.Node(u))
+			}
+			for u := 0; u < n; u++ {
+				for v := 0; v < n; v++ {
+					if u != v && rnd.Float64() < 0.1 {
+						wg.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1 + rnd.Float64()})
+					}
+				}
+			}
+			g = wg
+		} else {
+			dg := simple.NewDirectedGraph()
+			for u := 0; u < n; u++ {
+				dg.AddNode(simple.Node(u))
+			}
+			for u := 0; u < n; u++ {
+				for v := 0; v < n; v++ {
+					if u != v && rnd.Float64() < 0.1 {
+						dg.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+					}
+				}
+			}
+			g = dg
+		}
+		source := simple.Node(rnd.Intn(n))
+		want := PersonalizedPageRank(g, 0.85, 1e-14, map[int64]float64{source.ID(): 1})
+
+		const eps = 1e-6
+		got := ApproxPersonalizedPageRank(g, source, 0.85, eps)
+		var total float64
+		for id, w := range want {
+			if got[id] > w+1e-12 {
+				t.Errorf("approximate weight exceeds exact weight for test %d node %d: got %v, want at most %v", i, id, got[id], w)
+			}
+			total += math.Abs(got[id] - w)
+		}
+		var m int
+		for _, u := range graph.NodesOf(g.Nodes()) {
+			d := g.From(u.ID()).Len()
+			if d == 0 {
+				d = 1
+			}
+			m += d
+		}
+		if total > eps*float64(m) {
+			t.Errorf("unexpected total error for test %d: got %v, want at most %v", i, total, eps*float64(m))
+		}
+	}
+}
+
+// checkPersonalizedPageRank checks got against the personalized PageRank
+// for g with the given restart distribution computed by solving the linear
+// system (I - damp T) x = (1-damp) restart, where T is the transition matrix
+// with dangling nodes restarting from the restart distribution.
+func checkPersonalizedPageRank(t *testing.T, name string, g graph.Directed, damp float64, restart, got map[int64]float64) {
+	t.Helper()
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	n := len(nodes)
+	p := mat.NewVecDense(n, nil)
+	for id, w := range restart {
+		p.SetVec(indexOf[id], w)
+	}
+	weight := transitionWeight(g)
+	a := mat.NewDense(n, n, nil)
+	for j, u := range nodes {
+		to := graph.NodesOf(g.From(u.ID()))
+		var z float64
+		for _, v := range to {
+			z += weight(u.ID(), v.ID())
+		}
+		if z == 0 {
+			for i := 0; i < n; i++ {
+				a.Set(i, j, -damp*p.AtVec(i))
+			}
+		} else {
+			for _, v := range to {
+				a.Set(indexOf[v.ID()], j, -damp*weight(u.ID(), v.ID())/z)
+			}
+		}
+		a.Set(j, j, a.At(j, j)+1)
+	}
+	var b, x mat.VecDense
+	b.ScaleVec(1-damp, p)
+	err := x.SolveVec(a, &b)
+	if err != nil {
+		t.Fatalf("unexpected error solving for %s: %v", name, err)
+	}
+	for i, u := range nodes {
+		if !scalar.EqualWithinAbsOrRel(got[u.ID()], x.AtVec(i), 1e-9, 1e-9) {
+			t.Errorf("unexpected personalized PageRank for %s node %d: got %v, want %v", name, u.ID(), got[u.ID()], x.AtVec(i))
+		}
+	}
+}
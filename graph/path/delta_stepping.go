@@ -0,0 +1,221 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// DeltaSteppingFrom returns a shortest-path tree for a shortest path from u to
+// all nodes in the graph g. If the graph does not implement Weighted,
+// UniformCost is used. DeltaSteppingFrom will panic if delta is not positive
+// or if g has a u-reachable negative edge weight.
+//
+// DeltaSteppingFrom uses the delta-stepping algorithm of Meyer and Sanders,
+// "Δ-stepping: a parallelizable shortest path algorithm" (2003). Nodes are
+// held in buckets of tentative distance of width delta, and the edges leaving
+// the nodes of each bucket are relaxed concurrently using the given number of
+// workers. If workers is not positive, runtime.GOMAXPROCS(0) workers are used.
+// The From and Weight methods of g must be safe for concurrent use. A delta
+// close to the typical edge weight divided by the typical node degree usually
+// performs well. Small values of delta give many buckets with little work in
+// each, and large values of delta cause nodes to be relaxed repeatedly.
+//
+// If g is a graph.Graph, all nodes of the graph will be stored in the shortest-path
+// tree, otherwise only nodes reachable from u will be stored.
+func DeltaSteppingFrom(u graph.Node, g traverse.Graph, delta float64, workers int) Shortest {
+	if !(delta > 0) {
+		panic("delta-stepping: non-positive delta")
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var path Shortest
+	if h, ok := g.(graph.Graph); ok {
+		if h.Node(u.ID()) == nil {
+			return Shortest{from: u}
+		}
+		path = newShortestFrom(u, graph.NodesOf(h.Nodes()))
+	} else {
+		if g.From(u.ID()) == graph.Empty {
+			return Shortest{from: u}
+		}
+		path = newShortestFrom(u, []graph.Node{u})
+	}
+
+	var weight Weighting
+	if wg, ok := g.(Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	ds := deltaStepping{
+		path:    &path,
+		g:       g,
+		weight:  weight,
+		delta:   delta,
+		workers: workers,
+		bucket:  make(map[int][]int),
+	}
+	ds.relax(request{to: u, dist: 0, from: -1})
+
+	for len(ds.bucket) != 0 {
+		// Find the lowest non-empty bucket.
+		i := math.MaxInt64
+		for b := range ds.bucket {
+			if b < i {
+				i = b
+			}
+		}
+
+		// Repeatedly relax the light edges of the nodes
+		// in the bucket, which may return nodes to it.
+		var settled []int
+		for {
+			current := ds.take(i)
+			if len(current) == 0 {
+				break
+			}
+			settled = append(settled, current...)
+			for _, r := range ds.requests(current, true) {
+				ds.relax(r)
+			}
+		}
+
+		// Relax the heavy edges of the settled nodes,
+		// which cannot return nodes to the bucket.
+		for _, r := range ds.requests(settled, false) {
+			ds.relax(r)
+		}
+	}
+
+	return path
+}
+
+// deltaStepping holds the state of a delta-stepping search.
+type deltaStepping struct {
+	path    *Shortest
+	g       traverse.Graph
+	weight  Weighting
+	delta   float64
+	workers int
+
+	// bucket holds the node indices in each bucket. Nodes
+	// are removed lazily, so an entry is only valid if the
+	// node's distance places it in the bucket.
+	bucket map[int][]int
+}
+
+// request is a request to relax the distance to a node.
+type request struct {
+	to   graph.Node
+	dist float64
+	from int
+}
+
+// take removes and returns the valid nodes of bucket i.
+func (ds *deltaStepping) take(i int) []int {
+	b := ds.bucket[i]
+	delete(ds.bucket, i)
+	nodes := b[:0]
+	for _, k := range b {
+		if ds.index(ds.path.dist[k]) == i {
+			nodes = append(nodes, k)
+		}
+	}
+	return nodes
+}
+
+// index returns the bucket index for the distance d.
+func (ds *deltaStepping) index(d float64) int {
+	return int(d / ds.delta)
+}
+
+// relax updates the distance to the node in r if r improves it.
+func (ds *deltaStepping) relax(r request) {
+	p := ds.path
+	j, ok := p.indexOf[r.to.ID()]
+	if !ok {
+		j = p.add(r.to)
+	}
+	if r.from >= 0 && !(r.dist < p.dist[j]) {
+		return
+	}
+	if r.from >= 0 {
+		p.set(j, r.dist, r.from)
+	}
+	b := ds.index(r.dist)
+	ds.bucket[b] = append(ds.bucket[b], j)
+}
+
+// requests returns the relaxation requests for the light edges, if light is
+// true, or heavy edges, otherwise, leaving the nodes with the given indices.
+// The requests are generated concurrently.
+func (ds *deltaStepping) requests(nodes []int, light bool) []request {
+	n := ds.workers
+	if n > len(nodes) {
+		n = len(nodes)
+	}
+	if n == 0 {
+		return nil
+	}
+	p := ds.path
+	parts := make([][]request, n)
+	negative := make([]bool, n)
+	invalid := make([]bool, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for w := 0; w < n; w++ {
+		lo := w * len(nodes) / n
+		hi := (w + 1) * len(nodes) / n
+		go func(w int, part []int) {
+			defer wg.Done()
+			var reqs []request
+			for _, k := range part {
+				uid := p.nodes[k].ID()
+				to := ds.g.From(uid)
+				for to.Next() {
+					v := to.Node()
+					c, ok := ds.weight(uid, v.ID())
+					if !ok {
+						invalid[w] = true
+						return
+					}
+					if c < 0 {
+						negative[w] = true
+						return
+					}
+					if math.IsInf(c, 1) {
+						continue
+					}
+					if (c <= ds.delta) == light {
+						reqs = append(reqs, request{to: v, dist: p.dist[k] + c, from: k})
+					}
+				}
+			}
+			parts[w] = reqs
+		}(w, nodes[lo:hi])
+	}
+	wg.Wait()
+
+	var reqs []request
+	for w, part := range parts {
+		if invalid[w] {
+			panic("delta-stepping: unexpected invalid weight")
+		}
+		if negative[w] {
+			panic("delta-stepping: negative edge weight")
+		}
+		reqs = append(reqs, part...)
+	}
+	return reqs
+}
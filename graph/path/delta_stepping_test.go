@@ -0,0 +1,150 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path/internal/testgraphs"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+func TestDeltaSteppingFrom(t *testing.T) {
+	t.Parallel()
+	for _, test := range testgraphs.ShortestPathTests {
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetWeightedEdge(e)
+		}
+
+		for _, tg := range []struct {
+			typ string
+			g   traverse.Graph
+		}{
+			{"complete", g.(graph.Graph)},
+			{"incremental", incremental{g.(graph.Weighted)}},
+		} {
+			for _, delta := range []float64{0.5, 1, 3, 100} {
+				var (
+					pt Shortest
+
+					panicked bool
+				)
+				func() {
+					defer func() {
+						panicked = recover() != nil
+					}()
+					pt = DeltaSteppingFrom(test.Query.From(), tg.g, delta, 2)
+				}()
+				if panicked || test.HasNegativeWeight {
+					if !test.HasNegativeWeight {
+						t.Errorf("%q %s delta=%v: unexpected panic", test.Name, tg.typ, delta)
+					}
+					if !panicked {
+						t.Errorf("%q %s delta=%v: expected panic for negative edge weight", test.Name, tg.typ, delta)
+					}
+					continue
+				}
+
+				if pt.From().ID() != test.Query.From().ID() {
+					t.Fatalf("%q %s delta=%v: unexpected from node ID: got:%d want:%d", test.Name, tg.typ, delta, pt.From().ID(), test.Query.From().ID())
+				}
+
+				p, weight := pt.To(test.Query.To().ID())
+				if weight != test.Weight {
+					t.Errorf("%q %s delta=%v: unexpected weight from To: got:%f want:%f",
+						test.Name, tg.typ, delta, weight, test.Weight)
+				}
+
+				var got []int64
+				for _, n := range p {
+					got = append(got, n.ID())
+				}
+				ok := len(got) == 0 && len(test.WantPaths) == 0
+				for _, sp := range test.WantPaths {
+					if reflect.DeepEqual(got, sp) {
+						ok = true
+						break
+					}
+				}
+				if !ok {
+					t.Errorf("%q %s delta=%v: unexpected shortest path:\ngot: %v\nwant from:%v",
+						test.Name, tg.typ, delta, p, test.WantPaths)
+				}
+
+				np, weight := pt.To(test.NoPathFor.To().ID())
+				if pt.From().ID() == test.NoPathFor.From().ID() && (np != nil || !math.IsInf(weight, 1)) {
+					t.Errorf("%q %s delta=%v: unexpected path:\ngot: path=%v weight=%f\nwant:path=<nil> weight=+Inf",
+						test.Name, tg.typ, delta, np, weight)
+				}
+			}
+		}
+	}
+}
+
+func TestDeltaSteppingFromRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 10 + rnd.Intn(500)
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for u := 0; u < n; u++ {
+			g.AddNode(simple.Node(u))
+		}
+		for e := 0; e < 4*n; e++ {
+			u, v := rnd.Intn(n), rnd.Intn(n)
+			if u == v {
+				continue
+			}
+			w := 10 * rnd.Float64()
+			if i%2 == 0 {
+				// Include zero and repeated weights.
+				w = math.Floor(w)
+			}
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w})
+		}
+		from := simple.Node(rnd.Intn(n))
+		want := DijkstraFrom(from, g)
+		for _, delta := range []float64{0.1, 2.5, 50} {
+			for _, workers := range []int{0, 1, 7} {
+				got := DeltaSteppingFrom(from, g, delta, workers)
+				for _, v := range graph.NodesOf(g.Nodes()) {
+					vid := v.ID()
+					gw, ww := got.WeightTo(vid), want.WeightTo(vid)
+					if !scalar.EqualWithinAbsOrRel(gw, ww, 1e-12, 1e-12) && !(math.IsInf(gw, 1) && math.IsInf(ww, 1)) {
+						t.Errorf("unexpected distance to %d for test %d delta=%v workers=%d: got %v, want %v",
+							vid, i, delta, workers, gw, ww)
+						continue
+					}
+					path, pw := got.To(vid)
+					if math.IsInf(ww, 1) {
+						if path != nil {
+							t.Errorf("unexpected path to unreachable node %d for test %d", vid, i)
+						}
+						continue
+					}
+					var sum float64
+					for k := 1; k < len(path); k++ {
+						w, ok := g.Weight(path[k-1].ID(), path[k].ID())
+						if !ok {
+							t.Errorf("path to %d uses non-edge for test %d", vid, i)
+						}
+						sum += w
+					}
+					if !scalar.EqualWithinAbsOrRel(sum, pw, 1e-12, 1e-12) {
+						t.Errorf("path weight mismatch for %d in test %d: got %v, want %v", vid, i, sum, pw)
+					}
+				}
+			}
+		}
+	}
+}
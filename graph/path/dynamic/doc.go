@@ -2,5 +2,5 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package dynamic provides incremental heuristic graph path finding functions.
+// Package dynamic provides incremental graph path finding functions.
 package dynamic // import "gonum.org/v1/gonum/graph/path/dynamic"
@@ -0,0 +1,352 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// Shortest is a single-source shortest path tree that is maintained
+// incrementally as edges are added, removed or reweighted. Changes are
+// recorded by SetWeightedEdge, RemoveEdge and RemoveNode, and the shortest
+// path tree is brought up to date by the next query, so that a batch of
+// changes is processed together.
+//
+// When the weight of an edge in the tree increases or the edge is removed,
+// only the subtree below the edge is invalidated and its nodes are
+// reconnected from the remaining tree. When the weight of an edge decreases
+// or an edge is added, shortest paths are relaxed from the head of the edge.
+// In both cases the work done is proportional to the part of the tree that
+// is affected by the change, rather than to the size of the graph.
+//
+// See Narváez, Siu and Tzeng, "New dynamic algorithms for shortest path tree
+// computation", IEEE/ACM Transactions on Networking 8(6):734-746, 2000,
+// doi:10.1109/90.893870.
+//
+// Shortest is not safe for concurrent use, since queries may update the
+// receiver.
+type Shortest struct {
+	from *shortestNode
+
+	nodes map[int64]*shortestNode
+
+	// out and in hold the weights of the
+	// edges leaving and entering each node.
+	out map[int64]map[int64]float64
+	in  map[int64]map[int64]float64
+
+	undirected bool
+
+	// invalid holds the roots of subtrees
+	// invalidated by weight increases and
+	// relax holds the heads of edges with
+	// weight decreases since the last
+	// update.
+	invalid []int64
+	relax   []int64
+}
+
+// shortestNode is a node in a dynamic shortest path tree.
+type shortestNode struct {
+	graph.Node
+	dist   float64
+	parent *shortestNode
+}
+
+// NewShortest returns a dynamic shortest path tree for paths from s in g.
+// The nodes and edges of g are copied so that later changes to g are not
+// reflected in the returned Shortest. If g is a graph.Weighted, its edge
+// weights are used, otherwise each edge has unit weight. If g is a
+// graph.Undirected, edge changes made to the returned Shortest apply to
+// both directions of the edge.
+//
+// NewShortest will panic if s is not in g or if g has a negative edge
+// weight.
+func NewShortest(s graph.Node, g graph.Graph) *Shortest {
+	if g.Node(s.ID()) == nil {
+		panic("dynamic: source not in graph")
+	}
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+	_, undirected := g.(graph.Undirected)
+
+	p := &Shortest{
+		nodes:      make(map[int64]*shortestNode),
+		out:        make(map[int64]map[int64]float64),
+		in:         make(map[int64]map[int64]float64),
+		undirected: undirected,
+	}
+	nodes := g.Nodes()
+	for nodes.Next() {
+		p.node(nodes.Node())
+	}
+	for uid := range p.nodes {
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			w := edgeWeight(weight, uid, vid)
+			if w < 0 {
+				panic("dynamic: negative edge weight")
+			}
+			p.out[uid][vid] = w
+			p.in[vid][uid] = w
+		}
+	}
+	p.from = p.nodes[s.ID()]
+	p.from.dist = 0
+	p.relax = append(p.relax, p.from.ID())
+	return p
+}
+
+// node returns the shortestNode for n, adding it to the receiver if it
+// does not exist.
+func (p *Shortest) node(n graph.Node) *shortestNode {
+	id := n.ID()
+	u, ok := p.nodes[id]
+	if !ok {
+		u = &shortestNode{Node: n, dist: math.Inf(1)}
+		p.nodes[id] = u
+		p.out[id] = make(map[int64]float64)
+		p.in[id] = make(map[int64]float64)
+	}
+	return u
+}
+
+// From returns the source node of the shortest path tree.
+func (p *Shortest) From() graph.Node { return p.from.Node }
+
+// SetWeightedEdge adds the edge e to the graph, or changes its weight if it
+// already exists. The nodes of e are added if they are not in the graph. If
+// the graph is undirected, the reverse of e is also set.
+//
+// SetWeightedEdge will panic if e has a negative weight or is a self edge.
+func (p *Shortest) SetWeightedEdge(e graph.WeightedEdge) {
+	w := e.Weight()
+	if w < 0 {
+		panic("dynamic: negative edge weight")
+	}
+	u := p.node(e.From())
+	v := p.node(e.To())
+	if u == v {
+		panic("dynamic: adding self edge")
+	}
+	p.change(u, v, w)
+	if p.undirected {
+		p.change(v, u, w)
+	}
+}
+
+// RemoveEdge removes the edge from fid to tid from the graph. If the graph
+// is undirected, the reverse edge is also removed. If the edge does not
+// exist, RemoveEdge is a no-op.
+func (p *Shortest) RemoveEdge(fid, tid int64) {
+	u, ok := p.nodes[fid]
+	if !ok {
+		return
+	}
+	v, ok := p.nodes[tid]
+	if !ok {
+		return
+	}
+	p.change(u, v, math.Inf(1))
+	if p.undirected {
+		p.change(v, u, math.Inf(1))
+	}
+}
+
+// RemoveNode removes the node with the given ID and its edges from the
+// graph. If the node is not in the graph, RemoveNode is a no-op.
+// RemoveNode will panic if id is the source of the shortest path tree.
+func (p *Shortest) RemoveNode(id int64) {
+	u, ok := p.nodes[id]
+	if !ok {
+		return
+	}
+	if u == p.from {
+		panic("dynamic: removing source node")
+	}
+	for vid := range p.out[id] {
+		p.change(u, p.nodes[vid], math.Inf(1))
+	}
+	for vid := range p.in[id] {
+		p.change(p.nodes[vid], u, math.Inf(1))
+	}
+	delete(p.nodes, id)
+	delete(p.out, id)
+	delete(p.in, id)
+}
+
+// change sets the weight of the edge from u to v to w, removing the edge
+// if w is infinite, and records the change for the next update.
+func (p *Shortest) change(u, v *shortestNode, w float64) {
+	uid := u.ID()
+	vid := v.ID()
+	old, ok := p.out[uid][vid]
+	if !ok {
+		old = math.Inf(1)
+	}
+	if math.IsInf(w, 1) {
+		delete(p.out[uid], vid)
+		delete(p.in[vid], uid)
+	} else {
+		p.out[uid][vid] = w
+		p.in[vid][uid] = w
+	}
+	switch {
+	case w < old:
+		p.relax = append(p.relax, vid)
+	case w > old && v.parent == u:
+		p.invalid = append(p.invalid, vid)
+	}
+}
+
+// update brings the shortest path tree up to date with the recorded
+// changes.
+func (p *Shortest) update() {
+	if len(p.invalid) == 0 && len(p.relax) == 0 {
+		return
+	}
+	var queue shortestQueue
+
+	// Invalidate the subtrees below edges with
+	// increased weights. The tree still holds the
+	// paths from before the changes, so the nodes
+	// of a subtree are found by following edges
+	// to nodes whose parent is the edge's tail.
+	var affected []*shortestNode
+	for _, id := range p.invalid {
+		v, ok := p.nodes[id]
+		if !ok || math.IsInf(v.dist, 1) {
+			continue
+		}
+		v.dist = math.Inf(1)
+		v.parent = nil
+		affected = append(affected, v)
+		for i := len(affected) - 1; i < len(affected); i++ {
+			u := affected[i]
+			for cid := range p.out[u.ID()] {
+				c := p.nodes[cid]
+				if c.parent == u {
+					c.dist = math.Inf(1)
+					c.parent = nil
+					affected = append(affected, c)
+				}
+			}
+		}
+	}
+	// Reconnect invalidated nodes through their
+	// best predecessor outside the invalid set.
+	for _, v := range affected {
+		for uid, w := range p.in[v.ID()] {
+			u := p.nodes[uid]
+			if d := u.dist + w; d < v.dist {
+				v.dist = d
+				v.parent = u
+			}
+		}
+		if !math.IsInf(v.dist, 1) {
+			heap.Push(&queue, shortestItem{node: v, dist: v.dist})
+		}
+	}
+	// Relax edges with decreased weights.
+	for _, id := range p.relax {
+		v, ok := p.nodes[id]
+		if !ok {
+			continue
+		}
+		if v == p.from {
+			heap.Push(&queue, shortestItem{node: v, dist: v.dist})
+			continue
+		}
+		for uid, w := range p.in[id] {
+			u := p.nodes[uid]
+			if d := u.dist + w; d < v.dist {
+				v.dist = d
+				v.parent = u
+			}
+		}
+		if !math.IsInf(v.dist, 1) {
+			heap.Push(&queue, shortestItem{node: v, dist: v.dist})
+		}
+	}
+	p.invalid = p.invalid[:0]
+	p.relax = p.relax[:0]
+
+	// Propagate the new distances with Dijkstra's
+	// algorithm restricted to the nodes whose
+	// distances change.
+	for queue.Len() != 0 {
+		it := heap.Pop(&queue).(shortestItem)
+		u := it.node
+		if it.dist > u.dist {
+			continue
+		}
+		for vid, w := range p.out[u.ID()] {
+			v := p.nodes[vid]
+			if d := u.dist + w; d < v.dist {
+				v.dist = d
+				v.parent = u
+				heap.Push(&queue, shortestItem{node: v, dist: d})
+			}
+		}
+	}
+}
+
+// WeightTo returns the weight of the minimum path to v. If the node is not
+// in the graph or is not reachable from the source, WeightTo returns +Inf.
+func (p *Shortest) WeightTo(vid int64) float64 {
+	p.update()
+	v, ok := p.nodes[vid]
+	if !ok {
+		return math.Inf(1)
+	}
+	return v.dist
+}
+
+// To returns a shortest path to v and the weight of the path. If the node
+// is not in the graph or is not reachable from the source, To returns a nil
+// path and a weight of +Inf.
+func (p *Shortest) To(vid int64) (path []graph.Node, weight float64) {
+	p.update()
+	v, ok := p.nodes[vid]
+	if !ok || math.IsInf(v.dist, 1) {
+		return nil, math.Inf(1)
+	}
+	for u := v; u != nil; u = u.parent {
+		path = append(path, u.Node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, v.dist
+}
+
+// shortestItem is a node and its distance at the time it was queued.
+type shortestItem struct {
+	node *shortestNode
+	dist float64
+}
+
+// shortestQueue is a priority queue of nodes ordered by distance.
+type shortestQueue []shortestItem
+
+func (q shortestQueue) Len() int            { return len(q) }
+func (q shortestQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q shortestQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *shortestQueue) Push(x interface{}) { *q = append(*q, x.(shortestItem)) }
+func (q *shortestQueue) Pop() interface{} {
+	t := *q
+	var n shortestItem
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
@@ -0,0 +1,178 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestShortestDynamic(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, undirected := range []bool{false, true} {
+		for test := 0; test < 20; test++ {
+			const n = 30
+			var g interface {
+				graph.WeightedBuilder
+				graph.Weighted
+				RemoveEdge(fid, tid int64)
+				RemoveNode(id int64)
+			}
+			if undirected {
+				g = simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+			} else {
+				g = simple.NewWeightedDirectedGraph(0, math.Inf(1))
+			}
+			for u := 0; u < n; u++ {
+				g.AddNode(simple.Node(u))
+			}
+			randomEdge := func() simple.WeightedEdge {
+				u := rnd.Intn(n)
+				v := rnd.Intn(n - 1)
+				if v >= u {
+					v++
+				}
+				// Use integer weights including zero
+				// for some tests to exercise ties.
+				w := 10 * rnd.Float64()
+				if test%2 == 0 {
+					w = math.Floor(w / 2)
+				}
+				return simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w}
+			}
+			for i := 0; i < 3*n; i++ {
+				g.SetWeightedEdge(randomEdge())
+			}
+
+			s := simple.Node(rnd.Intn(n))
+			p := NewShortest(s, g)
+			checkShortest(t, g, p, s, "initial")
+
+			for step := 0; step < 30; step++ {
+				// Apply a batch of changes before
+				// querying the shortest paths.
+				for k := 1 + rnd.Intn(4); k > 0; k-- {
+					switch rnd.Intn(4) {
+					case 0, 1:
+						e := randomEdge()
+						g.SetWeightedEdge(e)
+						p.SetWeightedEdge(e)
+					case 2:
+						e := randomEdge()
+						g.RemoveEdge(e.F.ID(), e.T.ID())
+						p.RemoveEdge(e.F.ID(), e.T.ID())
+					case 3:
+						// Change the weight of an existing edge.
+						nodes := graph.NodesOf(g.Nodes())
+						u := nodes[rnd.Intn(len(nodes))]
+						to := graph.NodesOf(g.From(u.ID()))
+						if len(to) == 0 {
+							continue
+						}
+						v := to[rnd.Intn(len(to))]
+						w, _ := g.Weight(u.ID(), v.ID())
+						e := simple.WeightedEdge{F: u, T: v, W: math.Max(0, w+4*rnd.Float64()-2)}
+						g.SetWeightedEdge(e)
+						p.SetWeightedEdge(e)
+					}
+				}
+				if step == 20 {
+					id := int64(rnd.Intn(n))
+					if id != s.ID() {
+						g.RemoveNode(id)
+						p.RemoveNode(id)
+					}
+				}
+				checkShortest(t, g, p, s, fmt.Sprintf("updated u=%v test=%d step=%d", undirected, test, step))
+			}
+		}
+	}
+}
+
+// checkShortest checks that the dynamic shortest paths p from s agree with
+// the shortest paths in g computed by Dijkstra's algorithm.
+func checkShortest(t *testing.T, g graph.Weighted, p *Shortest, s graph.Node, name string) {
+	t.Helper()
+	want := path.DijkstraFrom(s, g)
+	for _, v := range graph.NodesOf(g.Nodes()) {
+		vid := v.ID()
+		wantW := want.WeightTo(vid)
+		gotW := p.WeightTo(vid)
+		if !scalar.EqualWithinAbsOrRel(gotW, wantW, 1e-12, 1e-12) && gotW != wantW {
+			t.Errorf("unexpected %s weight to %d: got %v, want %v", name, vid, gotW, wantW)
+			continue
+		}
+		path, weight := p.To(vid)
+		if math.IsInf(wantW, 1) {
+			if path != nil {
+				t.Errorf("unexpected %s path to unreachable node %d: %v", name, vid, path)
+			}
+			continue
+		}
+		if path[0].ID() != s.ID() || path[len(path)-1].ID() != vid {
+			t.Errorf("unexpected %s path ends to %d: %v", name, vid, path)
+			continue
+		}
+		var sum float64
+		for i := 1; i < len(path); i++ {
+			w, ok := g.Weight(path[i-1].ID(), path[i].ID())
+			if !ok {
+				t.Errorf("%s path to %d uses missing edge %d->%d", name, vid, path[i-1].ID(), path[i].ID())
+			}
+			sum += w
+		}
+		if !scalar.EqualWithinAbsOrRel(sum, weight, 1e-12, 1e-12) {
+			t.Errorf("unexpected %s path weight to %d: got %v, want %v", name, vid, sum, weight)
+		}
+	}
+	if w := p.WeightTo(-1); !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight to missing node: %v", w)
+	}
+}
+
+func TestShortestUnweighted(t *testing.T) {
+	t.Parallel()
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 3}, {0, 4}, {4, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	p := NewShortest(simple.Node(0), g)
+	if w := p.WeightTo(3); w != 2 {
+		t.Errorf("unexpected weight to 3: got %v, want 2", w)
+	}
+
+	// Closing the short route lengthens the path.
+	p.RemoveEdge(4, 3)
+	path, w := p.To(3)
+	if w != 3 || len(path) != 4 {
+		t.Errorf("unexpected path to 3 after removal: got %v with weight %v", path, w)
+	}
+
+	// A new shortcut shortens it again.
+	p.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(3), W: 0.5})
+	if w := p.WeightTo(3); w != 0.5 {
+		t.Errorf("unexpected weight to 3 after shortcut: got %v, want 0.5", w)
+	}
+
+	// New nodes are added by new edges.
+	p.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(3), T: simple.Node(5), W: 1})
+	if w := p.WeightTo(5); w != 1.5 {
+		t.Errorf("unexpected weight to new node: got %v, want 1.5", w)
+	}
+
+	p.RemoveNode(3)
+	if w := p.WeightTo(5); !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight to node 5 after removal of 3: got %v, want +Inf", w)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// errNotConverged is returned when the Lanczos iteration
+// does not converge to the requested eigenvectors.
+var errNotConverged = errors.New("spectral: eigenvectors did not converge")
+
+// NewSparseSymNormLaplacian returns a symmetric normalized Laplacian matrix
+// for the simple undirected graph g held as a *mat.CSR. The normalized
+// Laplacian is defined as I-D^(-1/2)AD^(-1/2) where D is a diagonal matrix
+// holding the degree of each node and A is the graph adjacency matrix of the
+// input graph. If g is a graph.Weighted, A holds the edge weights and the
+// degree of a node is the sum of the weights of its edges. The diagonal
+// elements for nodes without edges are zero. The nodes of the Laplacian are
+// ordered by ID.
+// If g contains self edges or negative edge weights, NewSparseSymNormLaplacian
+// will panic.
+func NewSparseSymNormLaplacian(g graph.Undirected) Laplacian {
+	a := newNormAdjacency(g)
+	n := len(a.nodes)
+	l := mat.NewCOO(n, n, nil, nil, nil)
+	a.m.DoNonZero(func(i, j int, v float64) {
+		l.Append(i, j, -v)
+	})
+	for i, d := range a.degree {
+		if d != 0 {
+			l.Append(i, i, 1)
+		}
+	}
+	return Laplacian{Matrix: l.ToCSR(), Nodes: a.nodes, Index: a.index}
+}
+
+// normAdjacency is the normalized adjacency matrix D^(-1/2)AD^(-1/2) of a
+// graph.
+type normAdjacency struct {
+	m      *mat.CSR
+	degree []float64
+	nodes  []graph.Node
+	index  map[int64]int
+}
+
+func newNormAdjacency(g graph.Undirected) normAdjacency {
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	n := len(nodes)
+	a := mat.NewCOO(n, n, nil, nil, nil)
+	degree := make([]float64, n)
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if uid == vid {
+				panic("spectral: self edge in graph")
+			}
+			w := weight(uid, vid)
+			if w < 0 {
+				panic("spectral: negative edge weight")
+			}
+			degree[i] += w
+			a.Append(i, indexOf[vid], w)
+		}
+	}
+	scale := make([]float64, n)
+	for i, d := range degree {
+		if d != 0 {
+			scale[i] = 1 / math.Sqrt(d)
+		}
+	}
+	m := mat.NewCOO(n, n, nil, nil, nil)
+	a.DoNonZero(func(i, j int, v float64) {
+		m.Append(i, j, scale[i]*v*scale[j])
+	})
+	return normAdjacency{m: m.ToCSR(), degree: degree, nodes: nodes, index: indexOf}
+}
+
+// shifted is the operator I+A for a symmetric matrix A.
+type shifted struct {
+	a *mat.CSR
+}
+
+func (op shifted) MulVecTo(dst *mat.VecDense, _ bool, x mat.Vector) {
+	op.a.MulVecTo(dst, false, x)
+	dst.AddVec(dst, x)
+}
+
+// smallest returns the k smallest eigenvalues of the symmetric normalized
+// Laplacian of the graph described by a and their eigenvectors in the
+// columns of the returned matrix. The eigenvalues of the Laplacian are in
+// [0, 2], so they are obtained from the largest eigenvalues of I+A, which
+// converge quickly under Lanczos iteration.
+func (a normAdjacency) smallest(k int, settings *mat.KrylovSettings) ([]float64, *mat.Dense, error) {
+	var eig mat.EigenSymPartial
+	ok := eig.Factorize(shifted{a.m}, len(a.nodes), k, mat.EigenLargest, settings)
+	if !ok {
+		return nil, nil, errNotConverged
+	}
+	values := eig.Values(nil)
+	for i, v := range values {
+		values[i] = 2 - v
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	return values, &vectors, nil
+}
+
+// Embedding is a spectral embedding of the nodes of a graph.
+type Embedding struct {
+	// Coords holds the coordinates of the
+	// embedded nodes in its rows.
+	Coords *mat.Dense
+
+	// Values holds the Laplacian eigenvalues
+	// corresponding to the columns of Coords.
+	Values []float64
+
+	// Nodes holds the input graph nodes.
+	Nodes []graph.Node
+
+	// Index is a mapping from the graph
+	// node IDs to row indices of Coords.
+	Index map[int64]int
+}
+
+// LaplacianEigenmap returns the dim-dimensional Laplacian eigenmap embedding
+// of the simple undirected graph g. The coordinates of the nodes are given by
+// the solutions of the generalized eigenproblem Lx = λDx for the dim smallest
+// eigenvalues following the trivial smallest eigenvalue, where L is the
+// Laplacian of g and D is the diagonal matrix of node degrees. If g is a
+// graph.Weighted, the edge weights are used. Nodes without edges are placed
+// at the origin. The nodes of the embedding are ordered by ID.
+//
+// The eigenvectors are computed by the thick-restart Lanczos method of
+// mat.EigenSymPartial using the provided settings, which may be nil. If the
+// Lanczos iteration does not converge, an error is returned.
+//
+// LaplacianEigenmap will panic if dim is not positive or is not less than
+// the number of nodes in g, or if g contains self edges or negative edge
+// weights.
+//
+// See Belkin and Niyogi, "Laplacian eigenmaps for dimensionality reduction
+// and data representation", Neural Computation 15(6):1373-1396, 2003.
+func LaplacianEigenmap(g graph.Undirected, dim int, settings *mat.KrylovSettings) (Embedding, error) {
+	a := newNormAdjacency(g)
+	n := len(a.nodes)
+	if dim < 1 || dim >= n {
+		panic("spectral: invalid embedding dimension")
+	}
+	values, vectors, err := a.smallest(dim+1, settings)
+	if err != nil {
+		return Embedding{}, err
+	}
+	coords := mat.NewDense(n, dim, nil)
+	for i, d := range a.degree {
+		if d == 0 {
+			continue
+		}
+		s := 1 / math.Sqrt(d)
+		for j := 0; j < dim; j++ {
+			coords.Set(i, j, s*vectors.At(i, j+1))
+		}
+	}
+	return Embedding{Coords: coords, Values: values[1:], Nodes: a.nodes, Index: a.index}, nil
+}
+
+// Clustering returns a partition of the nodes of the simple undirected graph
+// g into k clusters computed by the normalized spectral clustering algorithm
+// of Ng, Jordan and Weiss. The nodes are embedded using the eigenvectors of
+// the k smallest eigenvalues of the symmetric normalized Laplacian of g, the
+// embedded points are scaled to unit length and then partitioned by k-means
+// clustering. If g is a graph.Weighted, the edge weights are used. The nodes
+// of each cluster are ordered by ID and the clusters are ordered by the ID of
+// their first node.
+//
+// The eigenvectors are computed by the thick-restart Lanczos method of
+// mat.EigenSymPartial using the provided settings, which may be nil. The
+// source of randomness of settings is also used for the k-means clustering.
+// If the Lanczos iteration does not converge, an error is returned.
+//
+// Clustering will panic if k is not positive or is greater than the number
+// of nodes in g, or if g contains self edges or negative edge weights.
+//
+// See Ng, Jordan and Weiss, "On spectral clustering: Analysis and an
+// algorithm", Advances in Neural Information Processing Systems 14, 2001.
+func Clustering(g graph.Undirected, k int, settings *mat.KrylovSettings) ([][]graph.Node, error) {
+	a := newNormAdjacency(g)
+	n := len(a.nodes)
+	if k < 1 || k > n {
+		panic("spectral: invalid number of clusters")
+	}
+	if k == 1 {
+		return [][]graph.Node{a.nodes}, nil
+	}
+	_, vectors, err := a.smallest(k, settings)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		row := vectors.RawRowView(i)
+		if norm := floats.Norm(row, 2); norm != 0 {
+			floats.Scale(1/norm, row)
+		}
+	}
+
+	km := stat.KMeans{}
+	if settings != nil {
+		km.Src = settings.Src
+	}
+	km.Cluster(vectors, k, nil)
+	assign := km.Assignments(nil)
+
+	clusters := make([][]graph.Node, k)
+	for i, c := range assign {
+		clusters[c] = append(clusters[c], a.nodes[i])
+	}
+	var nonEmpty [][]graph.Node
+	for _, c := range clusters {
+		if len(c) != 0 {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+	ordered.BySliceIDs(nonEmpty)
+	return nonEmpty, nil
+}
@@ -0,0 +1,230 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSparseSymNormLaplacian(t *testing.T) {
+	for i, test := range []struct{ g []set }{
+		{g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+			C: nil,
+			D: nil,
+		}},
+		{g: []set{
+			A: linksTo(B, C, D, E),
+			B: linksTo(C),
+			D: linksTo(E, F),
+			F: linksTo(G),
+			G: nil,
+		}},
+	} {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		want := NewSymNormLaplacian(g)
+		got := NewSparseSymNormLaplacian(g)
+		if _, ok := got.Matrix.(*mat.CSR); !ok {
+			t.Errorf("unexpected matrix type for test %d: %T", i, got.Matrix)
+		}
+		for j, u := range got.Nodes {
+			if got.Index[u.ID()] != j {
+				t.Errorf("inconsistent node index for test %d node %d", i, u.ID())
+			}
+			if j != 0 && got.Nodes[j-1].ID() >= u.ID() {
+				t.Errorf("nodes not ordered by ID for test %d", i)
+			}
+			for _, v := range got.Nodes {
+				gotL := got.At(got.Index[u.ID()], got.Index[v.ID()])
+				wantL := want.At(want.Index[u.ID()], want.Index[v.ID()])
+				if !scalar.EqualWithinAbs(gotL, wantL, 1e-14) {
+					t.Errorf("unexpected normalized Laplacian element for test %d at (%d, %d): got %v, want %v",
+						i, u.ID(), v.ID(), gotL, wantL)
+				}
+			}
+		}
+	}
+}
+
+func TestLaplacianEigenmap(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name     string
+		n        int
+		p        float64
+		weighted bool
+		dim      int
+	}{
+		{name: "path", n: 30, p: 0, dim: 2},
+		{name: "random", n: 60, p: 0.1, dim: 3},
+		{name: "weighted", n: 60, p: 0.1, weighted: true, dim: 3},
+	} {
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for u := 0; u < test.n; u++ {
+			g.AddNode(simple.Node(u))
+		}
+		weight := func() float64 {
+			if test.weighted {
+				return 0.5 + rnd.Float64()
+			}
+			return 1
+		}
+		for u := 1; u < test.n; u++ {
+			// Ensure the graph is connected.
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u - 1), T: simple.Node(u), W: weight()})
+			for v := u + 1; v < test.n; v++ {
+				if rnd.Float64() < test.p {
+					g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: weight()})
+				}
+			}
+		}
+
+		emb, err := LaplacianEigenmap(g, test.dim, &mat.KrylovSettings{Src: rand.NewSource(1)})
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+
+		// Compare against a dense eigendecomposition
+		// of the normalized Laplacian.
+		l := NewSparseSymNormLaplacian(g)
+		n := test.n
+		sym := mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				sym.SetSym(i, j, l.At(i, j))
+			}
+		}
+		var eig mat.EigenSym
+		if !eig.Factorize(sym, false) {
+			t.Fatalf("dense eigendecomposition failed for %s", test.name)
+		}
+		want := eig.Values(nil)
+		sort.Float64s(want)
+		if !floats.EqualApprox(emb.Values, want[1:test.dim+1], 1e-8) {
+			t.Errorf("unexpected eigenvalues for %s: got %v, want %v", test.name, emb.Values, want[1:test.dim+1])
+		}
+
+		// Check that the coordinates solve the generalized
+		// eigenproblem L x = λ D x for the unnormalized
+		// Laplacian L = D - A.
+		for j, lambda := range emb.Values {
+			x := emb.Coords.ColView(j)
+			for i, u := range emb.Nodes {
+				var deg, ax float64
+				to := g.From(u.ID())
+				for to.Next() {
+					v := to.Node()
+					w, _ := g.Weight(u.ID(), v.ID())
+					deg += w
+					ax += w * x.AtVec(emb.Index[v.ID()])
+				}
+				lx := deg*x.AtVec(i) - ax
+				if !scalar.EqualWithinAbs(lx, lambda*deg*x.AtVec(i), 1e-8) {
+					t.Errorf("coordinate %d of node %d does not solve generalized eigenproblem for %s: %v != %v",
+						j, u.ID(), test.name, lx, lambda*deg*x.AtVec(i))
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestClustering(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		sizes   []int
+		bridges [][2]int64
+	}{
+		{name: "disconnected", sizes: []int{5, 6, 7}},
+		{name: "bridged", sizes: []int{8, 8}, bridges: [][2]int64{{0, 8}}},
+		{name: "ring", sizes: []int{6, 6, 6, 6}, bridges: [][2]int64{{0, 6}, {7, 12}, {13, 18}, {19, 1}}},
+	} {
+		g := simple.NewUndirectedGraph()
+		var want [][]int64
+		var offset int64
+		for _, size := range test.sizes {
+			var c []int64
+			for u := offset; u < offset+int64(size); u++ {
+				c = append(c, u)
+				g.AddNode(simple.Node(u))
+				for v := offset; v < u; v++ {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+			want = append(want, c)
+			offset += int64(size)
+		}
+		for _, b := range test.bridges {
+			g.SetEdge(simple.Edge{F: simple.Node(b[0]), T: simple.Node(b[1])})
+		}
+
+		got, err := Clustering(g, len(test.sizes), &mat.KrylovSettings{Src: rand.NewSource(1)})
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		if !equalClusters(got, want) {
+			t.Errorf("unexpected clusters for %s: got %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func equalClusters(got [][]graph.Node, want [][]int64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i, c := range got {
+		if len(c) != len(want[i]) {
+			return false
+		}
+		for j, n := range c {
+			if n.ID() != want[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEmbeddingPanics(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	for _, fn := range []func(){
+		func() { LaplacianEigenmap(g, 0, nil) },
+		func() { LaplacianEigenmap(g, 3, nil) },
+		func() { Clustering(g, 0, nil) },
+		func() { Clustering(g, 4, nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
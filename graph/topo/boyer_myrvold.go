@@ -0,0 +1,975 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+// bmPlanarity holds the state of the edge addition planarity test of
+// Boyer and Myrvold, "On the Cutting Edge: Simplified O(n) Planarity by
+// Edge Addition" (2004), which is used to construct a planar embedding of
+// a planar graph or to isolate a Kuratowski subgraph of a non-planar graph
+// in linear time.
+//
+// Nodes are numbered by their depth first index. The root copy of the
+// parent of the node c, which roots the biconnected component containing
+// the tree edge to c until it is merged into its parent, is numbered n+c.
+type bmPlanarity struct {
+	n int
+
+	// DFS.
+	order     []int
+	adj       [][]int
+	parent    []int
+	size      []int
+	least     []int
+	lowpt     []int
+	lowVertex []int
+
+	// sepHead, sepNext and sepPrev hold for each node the list of
+	// its DFS children, ordered by lowpoint, whose biconnected
+	// components have not been merged into the node's component.
+	sepHead, sepNext, sepPrev []int
+
+	// Embedding.
+	arcs []bmArc
+	head [][2]int
+	ext  [][2]int
+
+	// inverted holds whether a node whose external face links both
+	// lead to the root of its component is entered from the root's
+	// side 0 by its own side 0, rather than by side 1.
+	inverted []bool
+
+	// Per step state.
+	backFlag []int
+	visited  []int
+	pertInt  [][]int
+	pertExt  [][]int
+	roots    []int
+	stack    []int
+}
+
+// bmArc is a half edge in the adjacency list of a node. The link fields
+// hold the neighboring arcs in the list towards each end, or -1.
+type bmArc struct {
+	to   int
+	twin int
+	link [2]int
+
+	// child and sign mark a tree edge from the parent side and
+	// whether the orientation of the child's subtree is reversed
+	// relative to the parent's.
+	child bool
+	sign  int
+}
+
+// newBMPlanarity returns the planarity test state for the graph with
+// the given adjacency lists.
+func newBMPlanarity(adj [][]int) *bmPlanarity {
+	n := len(adj)
+	p := &bmPlanarity{
+		n:      n,
+		order:  make([]int, 0, n),
+		adj:    make([][]int, n),
+		parent: make([]int, n),
+		size:   make([]int, n),
+		least:  make([]int, n),
+		lowpt:  make([]int, n),
+
+		lowVertex: make([]int, n),
+
+		sepHead: make([]int, n),
+		sepNext: make([]int, n),
+		sepPrev: make([]int, n),
+
+		head: make([][2]int, 2*n),
+		ext:  make([][2]int, 2*n),
+
+		inverted: make([]bool, n),
+
+		backFlag: make([]int, n),
+		visited:  make([]int, 2*n),
+		pertInt:  make([][]int, n),
+		pertExt:  make([][]int, n),
+	}
+
+	// Number the nodes in depth first order.
+	dfi := make([]int, n)
+	for i := range dfi {
+		dfi[i] = -1
+	}
+	type frame struct{ u, next int }
+	var stack []frame
+	for s := range adj {
+		if dfi[s] >= 0 {
+			continue
+		}
+		dfi[s] = len(p.order)
+		p.order = append(p.order, s)
+		p.parent[dfi[s]] = -1
+		stack = append(stack[:0], frame{u: s})
+		for len(stack) != 0 {
+			f := &stack[len(stack)-1]
+			if f.next == len(adj[f.u]) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			w := adj[f.u][f.next]
+			f.next++
+			if dfi[w] >= 0 {
+				continue
+			}
+			dfi[w] = len(p.order)
+			p.order = append(p.order, w)
+			p.parent[dfi[w]] = dfi[f.u]
+			stack = append(stack, frame{u: w})
+		}
+	}
+	m := 0
+	for u, a := range adj {
+		m += len(a)
+		for _, w := range a {
+			p.adj[dfi[u]] = append(p.adj[dfi[u]], dfi[w])
+		}
+	}
+	p.arcs = make([]bmArc, 0, m+2*n)
+
+	// Find the least ancestors and lowpoints. The children of
+	// a node follow it in depth first order.
+	for u := range p.adj {
+		p.least[u] = u
+		for _, w := range p.adj[u] {
+			if w < p.least[u] && w != p.parent[u] {
+				p.least[u] = w
+			}
+		}
+		p.lowpt[u] = p.least[u]
+		p.lowVertex[u] = u
+		p.size[u] = 1
+	}
+	for u := n - 1; u >= 0; u-- {
+		if q := p.parent[u]; q >= 0 {
+			p.size[q] += p.size[u]
+			if p.lowpt[u] < p.lowpt[q] {
+				p.lowpt[q] = p.lowpt[u]
+				p.lowVertex[q] = p.lowVertex[u]
+			}
+		}
+	}
+
+	// Bucket sort the children of each node by lowpoint.
+	buckets := make([][]int, n)
+	for u := range p.adj {
+		p.sepHead[u] = -1
+		if p.parent[u] >= 0 {
+			buckets[p.lowpt[u]] = append(buckets[p.lowpt[u]], u)
+		}
+	}
+	tail := make([]int, n)
+	for u := range tail {
+		tail[u] = -1
+	}
+	for _, b := range buckets {
+		for _, c := range b {
+			q := p.parent[c]
+			p.sepPrev[c] = tail[q]
+			p.sepNext[c] = -1
+			if tail[q] < 0 {
+				p.sepHead[q] = c
+			} else {
+				p.sepNext[tail[q]] = c
+			}
+			tail[q] = c
+		}
+	}
+
+	// Embed each tree edge as a biconnected component
+	// rooted at the root copy of the parent.
+	for v := range p.head {
+		p.head[v] = [2]int{-1, -1}
+		p.ext[v] = [2]int{-1, -1}
+		p.visited[v] = -1
+	}
+	for c := range p.adj {
+		p.backFlag[c] = -1
+		if p.parent[c] < 0 {
+			continue
+		}
+		r := n + c
+		a := p.newArcs(r, c)
+		p.arcs[a].child = true
+		p.insert(r, a, 0)
+		p.insert(c, p.arcs[a].twin, 0)
+		p.ext[r] = [2]int{c, c}
+		p.ext[c] = [2]int{r, r}
+	}
+	return p
+}
+
+// newArcs adds a pair of arcs for an edge between u and v, returning
+// the arc in the list of u.
+func (p *bmPlanarity) newArcs(u, v int) int {
+	a := len(p.arcs)
+	p.arcs = append(p.arcs,
+		bmArc{to: v, twin: a + 1, link: [2]int{-1, -1}, sign: 1},
+		bmArc{to: u, twin: a, link: [2]int{-1, -1}, sign: 1},
+	)
+	return a
+}
+
+// insert adds the arc a at the given end of the adjacency list of v.
+func (p *bmPlanarity) insert(v, a, end int) {
+	p.arcs[a].link[end] = -1
+	p.arcs[a].link[1^end] = p.head[v][end]
+	if h := p.head[v][end]; h >= 0 {
+		p.arcs[h].link[end] = a
+	} else {
+		p.head[v][1^end] = a
+	}
+	p.head[v][end] = a
+}
+
+// reverse reverses the adjacency list of v.
+func (p *bmPlanarity) reverse(v int) {
+	for a := p.head[v][0]; a >= 0; {
+		next := p.arcs[a].link[1]
+		p.arcs[a].link[0], p.arcs[a].link[1] = p.arcs[a].link[1], p.arcs[a].link[0]
+		a = next
+	}
+	p.head[v][0], p.head[v][1] = p.head[v][1], p.head[v][0]
+}
+
+// real returns the node represented by v, which may be a root copy.
+func (p *bmPlanarity) real(v int) int {
+	if v >= p.n {
+		return p.parent[v-p.n]
+	}
+	return v
+}
+
+// extNext returns the node following x on the external face of its
+// biconnected component, leaving x away from the side xin by which it
+// was entered, and the side by which the returned node is entered.
+func (p *bmPlanarity) extNext(x, xin int) (int, int) {
+	z := p.ext[x][1^xin]
+	switch {
+	case p.ext[z][0] == x && p.ext[z][1] == x:
+		return z, xin
+	case p.ext[z][0] == x:
+		return z, 0
+	default:
+		return z, 1
+	}
+}
+
+// extFirst returns the node following the root r on the external face of
+// its component leaving r by side d, and the side by which it is entered.
+func (p *bmPlanarity) extFirst(r, d int) (int, int) {
+	x := p.ext[r][d]
+	switch {
+	case p.ext[x][0] == p.ext[x][1]:
+		return x, 1 ^ d ^ p.inversion(x)
+	case p.ext[x][0] == r:
+		return x, 0
+	default:
+		return x, 1
+	}
+}
+
+// inversion returns 1 if x is marked as inverted and 0 otherwise.
+func (p *bmPlanarity) inversion(x int) int {
+	if p.inverted[x] {
+		return 1
+	}
+	return 0
+}
+
+// pertinent returns whether w has an unembedded connection to v,
+// the node being processed.
+func (p *bmPlanarity) pertinent(w, v int) bool {
+	return p.backFlag[w] == v || len(p.pertInt[w]) != 0 || len(p.pertExt[w]) != 0
+}
+
+// externallyActive returns whether w has a connection to an ancestor
+// of v, the node being processed.
+func (p *bmPlanarity) externallyActive(w, v int) bool {
+	if p.least[w] < v {
+		return true
+	}
+	c := p.sepHead[w]
+	return c >= 0 && p.lowpt[c] < v
+}
+
+func (p *bmPlanarity) inactive(w, v int) bool {
+	return !p.pertinent(w, v) && !p.externallyActive(w, v)
+}
+
+// pertinentRoot returns a root copy of w rooting a pertinent child
+// component, preferring components that are not externally active.
+func (p *bmPlanarity) pertinentRoot(w int) int {
+	if r := p.pertInt[w]; len(r) != 0 {
+		return r[len(r)-1]
+	}
+	r := p.pertExt[w]
+	return r[len(r)-1]
+}
+
+// activeFrom returns the first node that is not inactive on the
+// external face of the component rooted at r leaving r by side d,
+// and the side by which it is entered.
+func (p *bmPlanarity) activeFrom(r, d, v int) (int, int) {
+	x, xin := p.extFirst(r, d)
+	for x != r && p.inactive(x, v) {
+		x, xin = p.extNext(x, xin)
+	}
+	return x, xin
+}
+
+// walkup records the back edge from v to its descendant w and marks
+// the child components through which w is reached from v as pertinent.
+func (p *bmPlanarity) walkup(v, w int) {
+	p.backFlag[w] = v
+	x, xin := w, 1
+	y, yin := w, 0
+	for p.visited[x] != v && p.visited[y] != v {
+		p.visited[x] = v
+		p.visited[y] = v
+
+		r := -1
+		if x >= p.n {
+			r = x
+		} else if y >= p.n {
+			r = y
+		}
+		if r < 0 {
+			x, xin = p.extNext(x, xin)
+			y, yin = p.extNext(y, yin)
+			continue
+		}
+
+		c := r - p.n
+		u := p.parent[c]
+		if u == v {
+			p.roots = append(p.roots, r)
+			return
+		}
+		if p.lowpt[c] < v {
+			p.pertExt[u] = append(p.pertExt[u], r)
+		} else {
+			p.pertInt[u] = append(p.pertInt[u], r)
+		}
+		x, xin = u, 1
+		y, yin = u, 0
+	}
+}
+
+// walkdown embeds the back edges from v to the descendants reachable
+// along the external face of the component rooted at r, merging the
+// pertinent child components on the way. If the walk is blocked in a
+// child component, walkdown returns the root of that component and
+// -1 otherwise.
+func (p *bmPlanarity) walkdown(v, r int) int {
+	for d := 0; d < 2; d++ {
+		w, win := p.extFirst(r, d)
+		for w != r {
+			if p.backFlag[w] == v {
+				for len(p.stack) != 0 {
+					p.merge()
+				}
+				a := p.newArcs(r, w)
+				p.insert(r, a, d)
+				p.insert(w, p.arcs[a].twin, win)
+				p.ext[r][d] = w
+				p.ext[w][win] = r
+				p.backFlag[w] = -1
+			}
+
+			if len(p.pertInt[w]) != 0 || len(p.pertExt[w]) != 0 {
+				// Descend into a pertinent child component,
+				// preferring a direction that does not lead
+				// to a stopping node.
+				p.stack = append(p.stack, w, win)
+				root := p.pertinentRoot(w)
+				x, xin := p.activeFrom(root, 0, v)
+				y, yin := p.activeFrom(root, 1, v)
+				var out int
+				switch {
+				case p.pertinent(x, v) && !p.externallyActive(x, v):
+					w, win, out = x, xin, 0
+				case p.pertinent(y, v) && !p.externallyActive(y, v):
+					w, win, out = y, yin, 1
+				case p.pertinent(x, v):
+					w, win, out = x, xin, 0
+				default:
+					w, win, out = y, yin, 1
+				}
+				p.stack = append(p.stack, root, out)
+				continue
+			}
+
+			if !p.externallyActive(w, v) {
+				w, win = p.extNext(w, win)
+				continue
+			}
+
+			// w is a stopping node.
+			if len(p.stack) != 0 {
+				return p.stack[len(p.stack)-2]
+			}
+			p.ext[r][d] = w
+			p.ext[w][win] = r
+			p.inverted[w] = p.ext[w][0] == p.ext[w][1] && win == d
+			break
+		}
+	}
+	return -1
+}
+
+// merge merges the child component at the top of the merge stack into
+// the component of its parent, flipping it if necessary to keep the
+// external face path of the walkdown on one side.
+func (p *bmPlanarity) merge() {
+	n := len(p.stack)
+	w, win, root, out := p.stack[n-4], p.stack[n-3], p.stack[n-2], p.stack[n-1]
+	p.stack = p.stack[:n-4]
+
+	// The external face of the child component on the side away
+	// from the walk becomes the external face of w on the side by
+	// which it was entered.
+	z := p.ext[root][1^out]
+	p.ext[w][win] = z
+	if p.ext[z][0] == p.ext[z][1] {
+		p.ext[z][out^p.inversion(z)] = w
+	} else if p.ext[z][0] == root {
+		p.ext[z][0] = w
+	} else {
+		p.ext[z][1] = w
+	}
+
+	if out == win {
+		p.reverse(root)
+		p.ext[root][0], p.ext[root][1] = p.ext[root][1], p.ext[root][0]
+		for a := p.head[root][0]; a >= 0; a = p.arcs[a].link[1] {
+			if p.arcs[a].child {
+				p.arcs[a].sign = -p.arcs[a].sign
+			}
+		}
+	}
+
+	for a := p.head[root][0]; a >= 0; a = p.arcs[a].link[1] {
+		p.arcs[p.arcs[a].twin].to = w
+	}
+	hs, ho, g := p.head[root][win], p.head[root][1^win], p.head[w][win]
+	p.arcs[g].link[win] = ho
+	p.arcs[ho].link[1^win] = g
+	p.head[w][win] = hs
+	p.head[root] = [2]int{-1, -1}
+
+	c := root - p.n
+	if p.sepPrev[c] < 0 {
+		p.sepHead[w] = p.sepNext[c]
+	} else {
+		p.sepNext[p.sepPrev[c]] = p.sepNext[c]
+	}
+	if p.sepNext[c] >= 0 {
+		p.sepPrev[p.sepNext[c]] = p.sepPrev[c]
+	}
+	if r := p.pertInt[w]; len(r) != 0 {
+		p.pertInt[w] = r[:len(r)-1]
+	} else {
+		r := p.pertExt[w]
+		p.pertExt[w] = r[:len(r)-1]
+	}
+}
+
+// test runs the planarity test. If the graph is not planar, test returns
+// the node being processed and the root of the component in which the
+// embedding was blocked. Otherwise it returns -1 and -1.
+func (p *bmPlanarity) test() (v, blocked int) {
+	for v := p.n - 1; v >= 0; v-- {
+		p.roots = p.roots[:0]
+		for _, w := range p.adj[v] {
+			if w > v && p.parent[w] != v {
+				p.walkup(v, w)
+			}
+		}
+		for _, r := range p.roots {
+			if blocked := p.walkdown(v, r); blocked >= 0 {
+				return v, blocked
+			}
+		}
+		for _, w := range p.adj[v] {
+			if w > v && p.parent[w] != v && p.backFlag[w] == v {
+				c := w
+				for p.parent[c] != v {
+					c = p.parent[c]
+				}
+				return v, p.n + c
+			}
+		}
+	}
+	return -1, -1
+}
+
+// embedding returns the rotation of each node of a graph that has been
+// shown to be planar by the test method, in terms of the indices of the
+// adjacency lists passed to newBMPlanarity.
+func (p *bmPlanarity) embedding() [][]int {
+	// Merge the components that remain separate into the lists
+	// of their cut nodes. A biconnected component can be placed
+	// in any face incident to its cut node, so no flip is needed.
+	for c := 0; c < p.n; c++ {
+		r := p.n + c
+		if p.head[r][0] < 0 {
+			continue
+		}
+		u := p.parent[c]
+		for a := p.head[r][0]; a >= 0; a = p.arcs[a].link[1] {
+			p.arcs[p.arcs[a].twin].to = u
+		}
+		if t := p.head[u][1]; t < 0 {
+			p.head[u] = p.head[r]
+		} else {
+			h := p.head[r][0]
+			p.arcs[t].link[1] = h
+			p.arcs[h].link[0] = t
+			p.head[u][1] = p.head[r][1]
+		}
+		p.head[r] = [2]int{-1, -1}
+	}
+
+	// Orient each node consistently with its DFS tree root
+	// by reversing the nodes whose subtree was flipped an
+	// odd number of times.
+	sign := make([]int, p.n)
+	var stack []int
+	for u := range sign {
+		if p.parent[u] >= 0 {
+			continue
+		}
+		sign[u] = 1
+		stack = append(stack[:0], u)
+		for len(stack) != 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for a := p.head[u][0]; a >= 0; a = p.arcs[a].link[1] {
+				if p.arcs[a].child {
+					c := p.arcs[a].to
+					sign[c] = sign[u] * p.arcs[a].sign
+					stack = append(stack, c)
+				}
+			}
+			if sign[u] < 0 {
+				p.reverse(u)
+			}
+		}
+	}
+
+	rotation := make([][]int, p.n)
+	for u := range p.adj {
+		var around []int
+		for a := p.head[u][0]; a >= 0; a = p.arcs[a].link[1] {
+			around = append(around, p.order[p.arcs[a].to])
+		}
+		rotation[p.order[u]] = around
+	}
+	return rotation
+}
+
+// bmEdges is a set of edges of a subgraph.
+type bmEdges map[[2]int]bool
+
+func (s bmEdges) add(u, v int) {
+	if u > v {
+		u, v = v, u
+	}
+	s[[2]int{u, v}] = true
+}
+
+// treePath adds the tree path from u up to its ancestor a to s.
+func (p *bmPlanarity) treePath(s bmEdges, u, a int) {
+	for ; u != a; u = p.parent[u] {
+		s.add(u, p.parent[u])
+	}
+}
+
+// pertinentPath adds a path from the pertinent node w to v to s. If
+// c is not negative, the path passes through the DFS child c of w.
+func (p *bmPlanarity) pertinentPath(s bmEdges, v, w, c int) {
+	if c < 0 {
+		if p.backFlag[w] == v {
+			s.add(w, v)
+			return
+		}
+		c = p.pertinentRoot(w) - p.n
+	}
+	for _, u := range p.adj[v] {
+		if c <= u && u < c+p.size[c] && p.backFlag[u] == v {
+			p.treePath(s, u, w)
+			s.add(u, v)
+			return
+		}
+	}
+	panic("topo: no pertinent path")
+}
+
+// externalPath adds a path from the externally active node w to an
+// ancestor of v to s, returning the ancestor. If c is not negative,
+// the path passes through the DFS child c of w.
+func (p *bmPlanarity) externalPath(s bmEdges, v, w, c int) int {
+	if c < 0 {
+		if p.least[w] < v {
+			s.add(w, p.least[w])
+			return p.least[w]
+		}
+		c = p.sepHead[w]
+	}
+	d := p.lowVertex[c]
+	p.treePath(s, d, w)
+	s.add(d, p.least[d])
+	return p.least[d]
+}
+
+// isolate returns a Kuratowski subgraph for the failure of the walkdown
+// for v, blocked in the component rooted at root.
+func (p *bmPlanarity) isolate(v, root int) [][2]int {
+	r := p.real(root)
+	x, xin := p.activeFrom(root, 0, v)
+	y, _ := p.activeFrom(root, 1, v)
+	w, win := p.extNext(x, xin)
+	for w != y && !p.pertinent(w, v) {
+		w, win = p.extNext(w, win)
+	}
+	if w == y {
+		panic("topo: no pertinent node")
+	}
+
+	// Find the external face of the blocked component.
+	s := make(bmEdges)
+	face := []int{root}
+	pos := map[int]int{root: 0}
+	for a := p.head[root][0]; ; {
+		u := p.arcs[a].to
+		s.add(p.real(p.arcs[p.arcs[a].twin].to), p.real(u))
+		if u == root {
+			break
+		}
+		pos[u] = len(face)
+		face = append(face, u)
+		t := p.arcs[a].twin
+		if p.head[u][0] == t {
+			a = p.head[u][1]
+		} else {
+			a = p.head[u][0]
+		}
+	}
+
+	u := v
+	ancestor := func(a int) {
+		if a < u {
+			u = a
+		}
+	}
+	ancestor(p.externalPath(s, v, x, -1))
+	ancestor(p.externalPath(s, v, y, -1))
+	switch {
+	case r != v:
+		// Minor A: the blocked component is not rooted at v.
+		p.treePath(s, r, v)
+		p.pertinentPath(s, v, w, -1)
+	case len(p.pertExt[w]) != 0:
+		// Minor B: w has a child component that is both
+		// pertinent and externally active.
+		c := p.pertExt[w][len(p.pertExt[w])-1] - p.n
+		p.pertinentPath(s, v, w, c)
+		ancestor(p.externalPath(s, v, w, c))
+	default:
+		p.pertinentPath(s, v, w, -1)
+		px, py, ok := p.xyPath(s, root, pos, pos[w])
+		if !ok {
+			panic("topo: no x-y path")
+		}
+		if px < pos[x] || py > pos[y] {
+			// Minor C: the x-y path attaches above x or y.
+			break
+		}
+		if p.externallyActive(w, v) {
+			ancestor(p.externalPath(s, v, w, -1))
+			break
+		}
+		for _, z := range face[px+1 : py] {
+			if z != w && p.externallyActive(z, v) {
+				ancestor(p.externalPath(s, v, z, -1))
+				break
+			}
+		}
+	}
+	p.treePath(s, v, u)
+
+	edges := make([][2]int, 0, len(s))
+	for e := range s {
+		edges = append(edges, [2]int{p.order[e[0]], p.order[e[1]]})
+	}
+	return minimalNonPlanar(edges)
+}
+
+// xyPath adds to s the highest path through the blocked component rooted
+// at root between the two external face paths from the root to the node
+// at position pw of the external face, and, if there is one, a path from
+// the interior of the x-y path to the root. It returns the positions of
+// the ends of the x-y path on the external face and whether it exists.
+func (p *bmPlanarity) xyPath(s bmEdges, root int, pos map[int]int, pw int) (px, py int, ok bool) {
+	// Orient the component consistently so that its faces
+	// can be traced.
+	sign := map[int]int{root: 1}
+	stack := []int{root}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for a := p.head[u][0]; a >= 0; a = p.arcs[a].link[1] {
+			if p.arcs[a].child {
+				c := p.arcs[a].to
+				sign[c] = sign[u] * p.arcs[a].sign
+				stack = append(stack, c)
+			}
+		}
+		if sign[u] < 0 {
+			p.reverse(u)
+		}
+	}
+
+	// Trace the boundary of the faces incident to the root,
+	// excluding the external face, and find the last visit to
+	// the x side of the external face before the first visit
+	// to the y side.
+	const (
+		sideX = iota
+		pertinent
+		sideY
+		interior
+	)
+	class := func(u int) int {
+		i, ok := pos[u]
+		switch {
+		case !ok:
+			return interior
+		case i < pw:
+			return sideX
+		case i == pw:
+			return pertinent
+		default:
+			return sideY
+		}
+	}
+	var (
+		path    []int
+		index   = make(map[int]int)
+		started bool
+		found   bool
+		last    = -1
+	)
+	visit := func(u int) {
+		if u == last {
+			return
+		}
+		last = u
+		switch class(u) {
+		case sideX:
+			for _, z := range path {
+				delete(index, z)
+			}
+			path = append(path[:0], u)
+			started = true
+		case pertinent:
+			started = false
+		case sideY:
+			if started {
+				path = append(path, u)
+				found = true
+			}
+		default:
+			if !started {
+				return
+			}
+			if i, ok := index[u]; ok {
+				for _, z := range path[i+1:] {
+					delete(index, z)
+				}
+				path = path[:i+1]
+				return
+			}
+			index[u] = len(path)
+			path = append(path, u)
+		}
+	}
+	for a := p.head[root][0]; !found && p.arcs[a].link[1] >= 0; a = p.arcs[a].link[1] {
+		for e := a; ; {
+			u := p.arcs[e].to
+			if u == root {
+				break
+			}
+			visit(u)
+			if found {
+				break
+			}
+			t := p.arcs[e].twin
+			e = p.arcs[t].link[0]
+			if e < 0 {
+				e = p.head[u][1]
+			}
+		}
+	}
+	if !found {
+		return 0, 0, false
+	}
+	px, py = pos[path[0]], pos[path[len(path)-1]]
+	for i := 1; i < len(path); i++ {
+		s.add(path[i-1], path[i])
+	}
+
+	// Find a path from the interior of the x-y path to the root
+	// that avoids the external face.
+	inner := make(map[int]bool)
+	for _, u := range path[1 : len(path)-1] {
+		inner[u] = true
+	}
+	from := map[int]int{root: root}
+	queue := []int{root}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for a := p.head[u][0]; a >= 0; a = p.arcs[a].link[1] {
+			z := p.arcs[a].to
+			if _, ok := from[z]; ok {
+				continue
+			}
+			if _, ok := pos[z]; ok {
+				continue
+			}
+			from[z] = u
+			if inner[z] {
+				for ; z != root; z = from[z] {
+					s.add(p.real(z), p.real(from[z]))
+				}
+				return px, py, true
+			}
+			queue = append(queue, z)
+		}
+	}
+	return px, py, true
+}
+
+// minimalNonPlanar returns a minimal non-planar subgraph of the non-planar
+// graph with the given edges. The paths through nodes of degree two in the
+// graph are removed or retained as a whole, so the cost of finding the
+// subgraph depends on the number of nodes of higher degree, which is
+// small for the subgraphs constructed by isolate.
+func minimalNonPlanar(edges [][2]int) [][2]int {
+	adj := make(map[int][]int)
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], e[1])
+		adj[e[1]] = append(adj[e[1]], e[0])
+	}
+
+	// Remove trees hanging from the graph.
+	removed := make(map[[2]int]bool)
+	degree := make(map[int]int, len(adj))
+	var leaves []int
+	for u, a := range adj {
+		degree[u] = len(a)
+		if len(a) == 1 {
+			leaves = append(leaves, u)
+		}
+	}
+	for len(leaves) != 0 {
+		u := leaves[len(leaves)-1]
+		leaves = leaves[:len(leaves)-1]
+		for _, v := range adj[u] {
+			if removed[[2]int{u, v}] {
+				continue
+			}
+			removed[[2]int{u, v}] = true
+			removed[[2]int{v, u}] = true
+			degree[u]--
+			degree[v]--
+			if degree[v] == 1 {
+				leaves = append(leaves, v)
+			}
+		}
+	}
+
+	// Find the paths between the branch nodes.
+	type path struct {
+		ends  [2]int
+		edges [][2]int
+	}
+	var paths []path
+	index := make(map[int]int)
+	for u, a := range adj {
+		if degree[u] < 3 {
+			continue
+		}
+		index[u] = len(index)
+		for _, v := range a {
+			if removed[[2]int{u, v}] {
+				continue
+			}
+			pth := path{edges: [][2]int{{u, v}}}
+			prev, cur := u, v
+			removed[[2]int{u, v}] = true
+			for degree[cur] == 2 {
+				for _, w := range adj[cur] {
+					if !removed[[2]int{cur, w}] && w != prev {
+						prev, cur = cur, w
+						break
+					}
+				}
+				removed[[2]int{prev, cur}] = true
+				pth.edges = append(pth.edges, [2]int{prev, cur})
+			}
+			removed[[2]int{cur, prev}] = true
+			pth.ends = [2]int{u, cur}
+			paths = append(paths, pth)
+		}
+	}
+
+	// Remove each path in turn, restoring it if its removal
+	// makes the graph planar.
+	keep := make([]bool, len(paths))
+	for i := range keep {
+		keep[i] = true
+	}
+	nonPlanar := func() bool {
+		adj := make([][]int, len(index))
+		seen := make(map[[2]int]bool)
+		for i, pth := range paths {
+			u, v := index[pth.ends[0]], index[pth.ends[1]]
+			if !keep[i] || u == v || seen[[2]int{u, v}] {
+				continue
+			}
+			seen[[2]int{u, v}] = true
+			seen[[2]int{v, u}] = true
+			adj[u] = append(adj[u], v)
+			adj[v] = append(adj[v], u)
+		}
+		v, _ := newBMPlanarity(adj).test()
+		return v >= 0
+	}
+	if !nonPlanar() {
+		panic("topo: planar Kuratowski subgraph candidate")
+	}
+	for i := range paths {
+		keep[i] = false
+		if !nonPlanar() {
+			keep[i] = true
+		}
+	}
+
+	var kuratowski [][2]int
+	for i, pth := range paths {
+		if keep[i] {
+			kuratowski = append(kuratowski, pth.edges...)
+		}
+	}
+	return kuratowski
+}
@@ -0,0 +1,83 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Embedding is a combinatorial embedding of a graph in the plane. It holds
+// for each node ID the neighbors of the node in clockwise order around the
+// node. The faces of the embedding are the cycles obtained by leaving each
+// node along the edge that follows, in the node's clockwise order, the edge
+// by which the node was entered.
+type Embedding map[int64][]graph.Node
+
+// IsPlanar returns whether g is planar, that is whether g can be drawn in
+// the plane without edge crossings.
+func IsPlanar(g graph.Undirected) bool {
+	_, adj := planarGraphOf(g)
+	v, _ := newBMPlanarity(adj).test()
+	return v < 0
+}
+
+// Planarity tests whether g is planar. If g is planar, Planarity returns a
+// combinatorial planar embedding of g and true. Otherwise it returns the
+// edges of a Kuratowski subgraph of g, a subdivision of K₅ or K₃,₃, that
+// witnesses the non-planarity of g and false. Self loops in g are ignored.
+//
+// Planarity uses the edge addition planarity test of Boyer and Myrvold,
+// "On the Cutting Edge: Simplified O(n) Planarity by Edge Addition" (2004),
+// which constructs the embedding or isolates the Kuratowski subgraph in
+// linear time.
+func Planarity(g graph.Undirected) (embedding Embedding, kuratowski []graph.Edge, planar bool) {
+	nodes, adj := planarGraphOf(g)
+	bm := newBMPlanarity(adj)
+	v, blocked := bm.test()
+	if v < 0 {
+		embedding = make(Embedding, len(nodes))
+		for u, around := range bm.embedding() {
+			var ns []graph.Node
+			for _, w := range around {
+				ns = append(ns, nodes[w])
+			}
+			embedding[nodes[u].ID()] = ns
+		}
+		return embedding, nil, true
+	}
+
+	for _, e := range bm.isolate(v, blocked) {
+		kuratowski = append(kuratowski, g.Edge(nodes[e[0]].ID(), nodes[e[1]].ID()))
+	}
+	return nil, kuratowski, false
+}
+
+// planarGraphOf returns the nodes of g ordered by ID and the adjacency
+// lists of g in terms of node indices, excluding self loops.
+func planarGraphOf(g graph.Undirected) (nodes []graph.Node, adj [][]int) {
+	nodes = graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	adj = make([][]int, len(nodes))
+	for u, n := range nodes {
+		uid := n.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			adj[u] = append(adj[u], indexOf[vid])
+		}
+		sort.Ints(adj[u])
+	}
+	return nodes, adj
+}
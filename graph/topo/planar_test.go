@@ -0,0 +1,408 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var planarityTests = []struct {
+	name   string
+	g      []intset
+	planar bool
+}{
+	{name: "empty", g: nil, planar: true},
+	{name: "single", g: []intset{0: nil}, planar: true},
+	{
+		name:   "K4",
+		g:      []intset{0: linksTo(1, 2, 3), 1: linksTo(2, 3), 2: linksTo(3)},
+		planar: true,
+	},
+	{
+		name:   "K5",
+		g:      []intset{0: linksTo(1, 2, 3, 4), 1: linksTo(2, 3, 4), 2: linksTo(3, 4), 3: linksTo(4)},
+		planar: false,
+	},
+	{
+		name:   "K5 less one edge",
+		g:      []intset{0: linksTo(1, 2, 3, 4), 1: linksTo(2, 3, 4), 2: linksTo(3, 4), 3: nil},
+		planar: true,
+	},
+	{
+		name:   "K3,3",
+		g:      []intset{0: linksTo(3, 4, 5), 1: linksTo(3, 4, 5), 2: linksTo(3, 4, 5)},
+		planar: false,
+	},
+	{
+		name:   "K3,3 less one edge",
+		g:      []intset{0: linksTo(3, 4, 5), 1: linksTo(3, 4, 5), 2: linksTo(3, 4)},
+		planar: true,
+	},
+	{
+		// The Petersen graph contains a subdivision of K₃,₃ but not of K₅.
+		name: "Petersen",
+		g: []intset{
+			0: linksTo(1, 4, 5),
+			1: linksTo(2, 6),
+			2: linksTo(3, 7),
+			3: linksTo(4, 8),
+			4: linksTo(9),
+			5: linksTo(7, 8),
+			6: linksTo(8, 9),
+			7: linksTo(9),
+		},
+		planar: false,
+	},
+	{
+		// The Wagner graph is a non-planar Möbius ladder.
+		name: "Wagner",
+		g: []intset{
+			0: linksTo(1, 4, 7),
+			1: linksTo(2, 5),
+			2: linksTo(3, 6),
+			3: linksTo(4, 7),
+			4: linksTo(5),
+			5: linksTo(6),
+			6: linksTo(7),
+		},
+		planar: false,
+	},
+	{
+		name: "disconnected with isolated node",
+		g: []intset{
+			0: linksTo(1, 2, 3), 1: linksTo(2, 3), 2: linksTo(3),
+			4: nil,
+			5: linksTo(6, 7), 6: linksTo(7),
+		},
+		planar: true,
+	},
+	{
+		name: "disconnected non-planar component",
+		g: []intset{
+			0: linksTo(1, 2),
+			3: linksTo(6, 7, 8), 4: linksTo(6, 7, 8), 5: linksTo(6, 7, 8),
+		},
+		planar: false,
+	},
+}
+
+func TestPlanarity(t *testing.T) {
+	for _, test := range planarityTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		checkPlanarity(t, test.name, g, test.planar)
+	}
+}
+
+func TestPlanarityGrid(t *testing.T) {
+	for _, test := range []struct{ rows, cols int64 }{
+		{rows: 10, cols: 15},
+
+		// The Kuratowski subgraph of a large graph
+		// is found in linear time.
+		{rows: 80, cols: 100},
+	} {
+		rows, cols := test.rows, test.cols
+		g := simple.NewUndirectedGraph()
+		for r := int64(0); r < rows; r++ {
+			for c := int64(0); c < cols; c++ {
+				id := r*cols + c
+				g.AddNode(simple.Node(id))
+				if c > 0 {
+					g.SetEdge(simple.Edge{F: simple.Node(id - 1), T: simple.Node(id)})
+				}
+				if r > 0 {
+					g.SetEdge(simple.Edge{F: simple.Node(id - cols), T: simple.Node(id)})
+				}
+			}
+		}
+		checkPlanarity(t, "grid", g, true)
+
+		// A grid with a crossing diagonal pair of edges between
+		// distant cells is no longer planar.
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(rows*cols - 1)})
+		g.SetEdge(simple.Edge{F: simple.Node(cols - 1), T: simple.Node((rows - 1) * cols)})
+		checkPlanarity(t, "grid with crossing", g, false)
+	}
+}
+
+func TestPlanarityRandomTriangulation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		// Construct a maximal planar graph by repeatedly
+		// placing a new node in a random triangular face.
+		n := 3 + rnd.Intn(100)
+		g := simple.NewUndirectedGraph()
+		faces := [][3]int64{{0, 1, 2}, {0, 2, 1}}
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+		g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+		g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+		for v := int64(3); v < int64(n); v++ {
+			k := rnd.Intn(len(faces))
+			f := faces[k]
+			for _, u := range f {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+			faces[k] = [3]int64{f[0], f[1], v}
+			faces = append(faces, [3]int64{f[1], f[2], v}, [3]int64{f[2], f[0], v})
+		}
+		checkPlanarity(t, "triangulation", g, true)
+
+		// Adding any edge between non-adjacent
+		// nodes makes the graph non-planar.
+		if n < 5 {
+			continue
+		}
+		for {
+			u := rnd.Int63n(int64(n))
+			v := rnd.Int63n(int64(n))
+			if u != v && !g.HasEdgeBetween(u, v) {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				break
+			}
+		}
+		checkPlanarity(t, "triangulation with extra edge", g, false)
+	}
+}
+
+func TestPlanarityRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var planar, nonPlanar int
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(20)
+		p := 3 * rnd.Float64() / float64(n)
+		g := simple.NewUndirectedGraph()
+		for u := 0; u < n; u++ {
+			g.AddNode(simple.Node(u))
+			for v := 0; v < u; v++ {
+				if rnd.Float64() < p {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+		}
+		embedding, kuratowski, ok := Planarity(g)
+		if ok != IsPlanar(g) {
+			t.Errorf("mismatched planarity results for test %d", i)
+		}
+		if ok {
+			planar++
+			checkEmbedding(t, "random", g, embedding)
+		} else {
+			nonPlanar++
+			checkKuratowski(t, "random", g, kuratowski)
+		}
+	}
+	if planar == 0 || nonPlanar == 0 {
+		t.Errorf("random tests do not cover both cases: %d planar, %d non-planar", planar, nonPlanar)
+	}
+}
+
+// checkPlanarity checks the result of Planarity and IsPlanar for g against
+// want, and checks the validity of the returned embedding or Kuratowski
+// subgraph.
+func checkPlanarity(t *testing.T, name string, g graph.Undirected, want bool) {
+	t.Helper()
+	if got := IsPlanar(g); got != want {
+		t.Errorf("unexpected IsPlanar result for %s: got %t, want %t", name, got, want)
+	}
+	embedding, kuratowski, ok := Planarity(g)
+	if ok != want {
+		t.Errorf("unexpected Planarity result for %s: got %t, want %t", name, ok, want)
+		return
+	}
+	if ok {
+		if kuratowski != nil {
+			t.Errorf("unexpected Kuratowski subgraph for planar graph %s", name)
+		}
+		checkEmbedding(t, name, g, embedding)
+	} else {
+		if embedding != nil {
+			t.Errorf("unexpected embedding for non-planar graph %s", name)
+		}
+		checkKuratowski(t, name, g, kuratowski)
+	}
+}
+
+// checkEmbedding checks that embedding is a rotation system of g and that
+// it satisfies Euler's formula, V - E + F = 2, for each connected component
+// of g with at least one edge.
+func checkEmbedding(t *testing.T, name string, g graph.Undirected, embedding Embedding) {
+	t.Helper()
+	nodes := graph.NodesOf(g.Nodes())
+	if len(embedding) != len(nodes) {
+		t.Errorf("unexpected number of nodes in embedding for %s: got %d, want %d", name, len(embedding), len(nodes))
+		return
+	}
+
+	// next[u][v] is the neighbor following v clockwise around u.
+	next := make(map[int64]map[int64]int64)
+	var edges, nonIsolated int
+	for _, u := range nodes {
+		uid := u.ID()
+		around := embedding[uid]
+		if len(around) != g.From(uid).Len() {
+			t.Errorf("unexpected number of neighbors of %d in embedding for %s: got %d, want %d", uid, name, len(around), g.From(uid).Len())
+			return
+		}
+		next[uid] = make(map[int64]int64)
+		for i, v := range around {
+			if !g.HasEdgeBetween(uid, v.ID()) {
+				t.Errorf("embedding has non-edge %d--%d for %s", uid, v.ID(), name)
+				return
+			}
+			next[uid][v.ID()] = around[(i+1)%len(around)].ID()
+		}
+		if len(next[uid]) != len(around) {
+			t.Errorf("embedding has repeated neighbors of %d for %s", uid, name)
+			return
+		}
+		edges += len(around)
+		if len(around) != 0 {
+			nonIsolated++
+		}
+	}
+	edges /= 2
+
+	// Trace the faces of the embedding.
+	var faces int
+	visited := make(map[[2]int64]bool)
+	for _, u := range nodes {
+		for v := range next[u.ID()] {
+			if visited[[2]int64{u.ID(), v}] {
+				continue
+			}
+			faces++
+			for a, b := u.ID(), v; !visited[[2]int64{a, b}]; {
+				visited[[2]int64{a, b}] = true
+				a, b = b, next[b][a]
+			}
+		}
+	}
+
+	var components int
+	for _, c := range ConnectedComponents(g) {
+		if len(c) > 1 {
+			components++
+		}
+	}
+	if nonIsolated-edges+faces != 2*components {
+		t.Errorf("embedding does not satisfy Euler's formula for %s: V=%d E=%d F=%d with %d components",
+			name, nonIsolated, edges, faces, components)
+	}
+}
+
+// checkKuratowski checks that the edges in kuratowski are edges of g
+// that form a subdivision of K₅ or K₃,₃.
+func checkKuratowski(t *testing.T, name string, g graph.Undirected, kuratowski []graph.Edge) {
+	t.Helper()
+	adj := make(map[int64]map[int64]bool)
+	for _, e := range kuratowski {
+		u, v := e.From().ID(), e.To().ID()
+		if !g.HasEdgeBetween(u, v) {
+			t.Errorf("Kuratowski subgraph has non-edge %d--%d for %s", u, v, name)
+			return
+		}
+		if adj[u] == nil {
+			adj[u] = make(map[int64]bool)
+		}
+		if adj[v] == nil {
+			adj[v] = make(map[int64]bool)
+		}
+		adj[u][v] = true
+		adj[v][u] = true
+	}
+
+	// Suppress the nodes of degree two, finding the
+	// paths between the branch nodes.
+	branch := make(map[int64]bool)
+	for u, a := range adj {
+		switch len(a) {
+		case 2:
+		case 3, 4:
+			branch[u] = true
+		default:
+			t.Errorf("unexpected node degree %d in Kuratowski subgraph for %s", len(a), name)
+			return
+		}
+	}
+	linked := make(map[[2]int64]bool)
+	for u := range branch {
+		for v := range adj[u] {
+			prev, cur := u, v
+			for !branch[cur] {
+				for w := range adj[cur] {
+					if w != prev {
+						prev, cur = cur, w
+						break
+					}
+				}
+				if cur == u && !branch[cur] {
+					break
+				}
+			}
+			if cur == u || linked[[2]int64{u, cur}] {
+				t.Errorf("Kuratowski subgraph is not a subdivision of a simple graph for %s", name)
+				return
+			}
+			linked[[2]int64{u, cur}] = true
+		}
+	}
+
+	switch len(branch) {
+	case 5:
+		// K₅: all branch nodes are linked.
+		for u := range branch {
+			if len(adj[u]) != 4 {
+				t.Errorf("Kuratowski subgraph is not a subdivision of K₅ for %s", name)
+				return
+			}
+		}
+	case 6:
+		// K₃,₃: the branch nodes form a complete
+		// bipartite graph with parts of size three.
+		var first int64
+		for u := range branch {
+			first = u
+			break
+		}
+		part := map[int64]bool{first: true}
+		for v := range branch {
+			if v != first && !linked[[2]int64{first, v}] {
+				part[v] = true
+			}
+		}
+		if len(part) != 3 {
+			t.Errorf("Kuratowski subgraph is not a subdivision of K₃,₃ for %s", name)
+			return
+		}
+		for u := range branch {
+			if len(adj[u]) != 3 {
+				t.Errorf("Kuratowski subgraph is not a subdivision of K₃,₃ for %s", name)
+				return
+			}
+			for v := range branch {
+				if part[u] != part[v] && !linked[[2]int64{u, v}] {
+					t.Errorf("Kuratowski subgraph is not a subdivision of K₃,₃ for %s", name)
+					return
+				}
+			}
+		}
+	default:
+		t.Errorf("unexpected number of branch nodes in Kuratowski subgraph for %s: %d", name, len(branch))
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// ParallelBreadthFirst performs a level-synchronous breadth-first traversal
+// of g starting from the given node, expanding each level of the traversal
+// frontier concurrently using the given number of workers. If workers is
+// not positive, runtime.GOMAXPROCS(0) workers are used. The From method of
+// g must be safe for concurrent use.
+//
+// The returned depth map holds the number of edges on a shortest path from
+// from to each reachable node and the parent map holds the ID of the node
+// preceding each reachable node other than from on such a path, both keyed
+// on node IDs. The parent of a node is the first node in the preceding level
+// of the traversal with an edge to it, so the results are independent of the
+// number of workers when the order of nodes returned by g.From is fixed.
+func ParallelBreadthFirst(g Graph, from graph.Node, workers int) (depth map[int64]int, parent map[int64]int64) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	fid := from.ID()
+	depth = map[int64]int{fid: 0}
+	parent = make(map[int64]int64)
+
+	type discovery struct {
+		node   graph.Node
+		parent int64
+	}
+	frontier := []graph.Node{from}
+	found := make([][]discovery, workers)
+	for d := 1; len(frontier) != 0; d++ {
+		// Expand the frontier concurrently. The depth
+		// map is only read during expansion, so nodes
+		// found more than once are resolved when the
+		// results are merged below.
+		n := workers
+		if n > len(frontier) {
+			n = len(frontier)
+		}
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for w := 0; w < n; w++ {
+			lo := w * len(frontier) / n
+			hi := (w + 1) * len(frontier) / n
+			go func(w int, part []graph.Node) {
+				defer wg.Done()
+				local := found[w][:0]
+				for _, u := range part {
+					uid := u.ID()
+					to := g.From(uid)
+					for to.Next() {
+						v := to.Node()
+						if _, seen := depth[v.ID()]; !seen {
+							local = append(local, discovery{node: v, parent: uid})
+						}
+					}
+				}
+				found[w] = local
+			}(w, frontier[lo:hi])
+		}
+		wg.Wait()
+
+		// Merge the discoveries in frontier order.
+		frontier = frontier[:0:0]
+		for _, local := range found[:n] {
+			for _, f := range local {
+				vid := f.node.ID()
+				if _, seen := depth[vid]; seen {
+					continue
+				}
+				depth[vid] = d
+				parent[vid] = f.parent
+				frontier = append(frontier, f.node)
+			}
+		}
+	}
+	return depth, parent
+}
@@ -0,0 +1,90 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestParallelBreadthFirst(t *testing.T) {
+	for i, test := range breadthFirstTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for _, workers := range []int{0, 1, 3} {
+			checkParallelBreadthFirst(t, fmt.Sprintf("test %d with %d workers", i, workers), g, test.from, workers)
+		}
+	}
+}
+
+func TestParallelBreadthFirstGnp(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		var g graph.Graph
+		if directed {
+			dg := simple.NewDirectedGraph()
+			err := gen.Gnp(dg, 2000, 0.002, nil)
+			if err != nil {
+				t.Fatalf("unexpected error generating graph: %v", err)
+			}
+			g = dg
+		} else {
+			g = gnpUndirected(2000, 0.002)
+		}
+		var want map[int64]int
+		for _, workers := range []int{1, 4, 16} {
+			name := fmt.Sprintf("Gnp directed=%t with %d workers", directed, workers)
+			depth := checkParallelBreadthFirst(t, name, g, simple.Node(0), workers)
+			if want == nil {
+				want = depth
+			} else if !reflect.DeepEqual(depth, want) {
+				t.Errorf("depths depend on number of workers for %s", name)
+			}
+		}
+	}
+}
+
+// checkParallelBreadthFirst checks the result of ParallelBreadthFirst
+// against the depths found by BreadthFirst and checks that the parents
+// form a breadth-first tree.
+func checkParallelBreadthFirst(t *testing.T, name string, g graph.Graph, from graph.Node, workers int) map[int64]int {
+	t.Helper()
+	depth, parent := ParallelBreadthFirst(g, from, workers)
+
+	want := make(map[int64]int)
+	var bft BreadthFirst
+	bft.Walk(g, from, func(n graph.Node, d int) bool {
+		want[n.ID()] = d
+		return false
+	})
+	if !reflect.DeepEqual(depth, want) {
+		t.Errorf("unexpected depths for %s:\ngot: %v\nwant:%v", name, depth, want)
+	}
+
+	if len(parent) != len(depth)-1 {
+		t.Errorf("unexpected number of parents for %s: got %d, want %d", name, len(parent), len(depth)-1)
+	}
+	for vid, uid := range parent {
+		if depth[uid] != depth[vid]-1 {
+			t.Errorf("parent of %d not in preceding level for %s", vid, name)
+		}
+		if g.Edge(uid, vid) == nil {
+			t.Errorf("no edge from parent %d to %d for %s", uid, vid, name)
+		}
+	}
+	return depth
+}
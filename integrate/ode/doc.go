@@ -0,0 +1,8 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ode provides numerical solution of initial value problems for
+// systems of ordinary differential equations,
+//  dy/dt = f(t, y), y(t0) = y0.
+package ode // import "gonum.org/v1/gonum/integrate/ode"
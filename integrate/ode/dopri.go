@@ -0,0 +1,135 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+// dpStages is the number of stages of the Dormand–Prince method,
+// including the derivative at the end of the step.
+const dpStages = 7
+
+// Coefficients of the Dormand–Prince 5(4) method.
+var (
+	dpC = [dpStages]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1}
+	dpA = [dpStages][dpStages - 1]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+
+	// dpE holds the difference between the fifth order
+	// weights and the embedded fourth order weights.
+	dpE = [dpStages]float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+
+	// dpP holds the coefficients of the polynomials in θ of the
+	// continuous extension, with dpP[i][j] the coefficient of
+	// θ^(j+1) in the weight of stage i.
+	dpP = [dpStages][4]float64{
+		{1, -8048581381.0 / 2820520608, 8663915743.0 / 2820520608, -12715105075.0 / 11282082432},
+		{0, 0, 0, 0},
+		{0, 131558114200.0 / 32700410799, -68118460800.0 / 10900136933, 87487479700.0 / 32700410799},
+		{0, -1754552775.0 / 470086768, 14199869525.0 / 1410260304, -10690763975.0 / 1880347072},
+		{0, 127303824393.0 / 49829197408, -318862633887.0 / 49829197408, 701980252875.0 / 199316789632},
+		{0, -282668133.0 / 205662961, 2019193451.0 / 616988883, -1453857185.0 / 822651844},
+		{0, 40617522.0 / 29380423, -110615467.0 / 29380423, 69997945.0 / 29380423},
+	}
+)
+
+// dormandPrince implements the Dormand–Prince 5(4) method.
+type dormandPrince struct {
+	f Func
+
+	// k holds the derivatives at each stage of the last step,
+	// with k[0] being the derivative at the start of the step.
+	k   [dpStages][]float64
+	tmp []float64
+
+	t, h float64
+	y    []float64
+}
+
+func newDormandPrince(f Func, f0 []float64) *dormandPrince {
+	n := len(f0)
+	m := &dormandPrince{f: f, tmp: make([]float64, n)}
+	for i := range m.k {
+		m.k[i] = make([]float64, n)
+	}
+	copy(m.k[0], f0)
+	return m
+}
+
+func (m *dormandPrince) errorOrder() int { return 4 }
+
+func (m *dormandPrince) step(yNew, yErr []float64, t, h float64, y []float64) {
+	m.t, m.h, m.y = t, h, y
+	k := &m.k
+	for s := 1; s < dpStages; s++ {
+		dst := m.tmp
+		if s == dpStages-1 {
+			dst = yNew
+		}
+		a := dpA[s]
+		for i := range dst {
+			var sum float64
+			for j := 0; j < s; j++ {
+				sum += a[j] * k[j][i]
+			}
+			dst[i] = y[i] + h*sum
+		}
+		m.f(k[s], t+dpC[s]*h, dst)
+	}
+	for i := range yErr {
+		var sum float64
+		for s := 0; s < dpStages; s++ {
+			sum += dpE[s] * k[s][i]
+		}
+		yErr[i] = h * sum
+	}
+}
+
+func (m *dormandPrince) accept() interpolant {
+	d := newDenseStep(m.t, m.h, m.y, &m.k)
+	// The last stage is the derivative at the new
+	// state, so it is reused as the first stage.
+	m.k[0], m.k[dpStages-1] = m.k[dpStages-1], m.k[0]
+	return d
+}
+
+// denseStep holds the continuous extension of an accepted step.
+type denseStep struct {
+	t, h float64
+	y    []float64
+
+	// q holds the coefficients of θ^(j+1) for each
+	// component i at q[4*i+j], scaled by h.
+	q []float64
+}
+
+func newDenseStep(t, h float64, y []float64, k *[dpStages][]float64) denseStep {
+	n := len(y)
+	q := make([]float64, 4*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 4; j++ {
+			var sum float64
+			for s := 0; s < dpStages; s++ {
+				sum += k[s][i] * dpP[s][j]
+			}
+			q[4*i+j] = h * sum
+		}
+	}
+	return denseStep{t: t, h: h, y: append([]float64(nil), y...), q: q}
+}
+
+func (d denseStep) span() (t, h float64) { return d.t, d.h }
+
+func (d denseStep) at(dst []float64, t float64) {
+	theta := (t - d.t) / d.h
+	for i, v := range d.y {
+		q := d.q[4*i : 4*i+4]
+		dst[i] = v + theta*(q[0]+theta*(q[1]+theta*(q[2]+theta*q[3])))
+	}
+}
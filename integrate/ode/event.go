@@ -0,0 +1,105 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"sort"
+)
+
+// findEvents locates the events occurring in the accepted step ending at
+// tNew with state yNew and appends them to sol.Events in order of
+// occurrence. gPrev holds the values of the event functions at the start
+// of the step and is updated to their values at the end of the step. If a
+// terminal event occurs, findEvents returns the time of the first terminal
+// event and true, and events after it are discarded. Otherwise it returns
+// tNew and false.
+func findEvents(sol *Solution, events []Event, gPrev []float64, step interpolant, tNew float64, yNew []float64) (float64, bool) {
+	t0, h := step.span()
+	var found []EventOccurrence
+	y := make([]float64, len(yNew))
+	for i, e := range events {
+		g0 := gPrev[i]
+		g1 := e.Func(tNew, yNew)
+		gPrev[i] = g1
+		up := g0 < 0 && g1 >= 0
+		down := g0 > 0 && g1 <= 0
+		if !(up && e.Direction >= 0 || down && e.Direction <= 0) {
+			continue
+		}
+		g := func(t float64) float64 {
+			step.at(y, t)
+			return e.Func(t, y)
+		}
+		t := eventRoot(g, t0, tNew, g0, g1)
+		if t == tNew {
+			copy(y, yNew)
+		} else {
+			step.at(y, t)
+		}
+		found = append(found, EventOccurrence{Index: i, T: t, Y: append([]float64(nil), y...)})
+	}
+
+	forward := h > 0
+	sort.SliceStable(found, func(i, j int) bool {
+		if forward {
+			return found[i].T < found[j].T
+		}
+		return found[i].T > found[j].T
+	})
+	for i, occ := range found {
+		if events[occ.Index].Terminal {
+			sol.Events = append(sol.Events, found[:i+1]...)
+			return occ.T, true
+		}
+	}
+	sol.Events = append(sol.Events, found...)
+	return tNew, false
+}
+
+// eventRoot returns the time in (a, b] at which the continuous function g
+// changes sign, given the values ga and gb of g at a and b with ga non-zero.
+// The root is bracketed using the Illinois variant of the regula falsi
+// method and the end of the final bracket nearest b is returned, so that
+// the sign of g at the returned time matches the sign of gb.
+func eventRoot(g func(float64) float64, a, b, ga, gb float64) float64 {
+	if gb == 0 {
+		return b
+	}
+	const maxIter = 100
+	var side int
+	for i := 0; i < maxIter; i++ {
+		tol := 4 * eps * math.Max(math.Abs(a), math.Abs(b))
+		if math.Abs(b-a) <= tol {
+			break
+		}
+		t := (a*gb - b*ga) / (gb - ga)
+		// Keep the estimate strictly inside the bracket
+		// so that the bracket shrinks.
+		if !(math.Min(a, b) < t && t < math.Max(a, b)) {
+			t = (a + b) / 2
+		}
+		gt := g(t)
+		if gt == 0 {
+			return t
+		}
+		if math.Signbit(gt) == math.Signbit(gb) {
+			b, gb = t, gt
+			if side == -1 {
+				ga /= 2
+			}
+			side = -1
+		} else {
+			a, ga = t, gt
+			if side == 1 {
+				gb /= 2
+			}
+			side = 1
+		}
+	}
+	return b
+}
+
+const eps = 0x1p-52
@@ -0,0 +1,50 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/integrate/ode"
+)
+
+func ExampleSolve() {
+	// A projectile launched upwards at 20 m/s with
+	// quadratic air drag, stopping when it lands.
+	const (
+		g    = 9.81
+		drag = 0.01
+	)
+	f := func(dy []float64, t float64, y []float64) {
+		v := y[1]
+		dy[0] = v
+		dy[1] = -g - drag*v*math.Abs(v)
+	}
+	settings := &ode.Settings{
+		AbsTol: 1e-9,
+		RelTol: 1e-9,
+		Events: []ode.Event{
+			// Apex: the velocity crosses zero.
+			{Func: func(t float64, y []float64) float64 { return y[1] }, Direction: -1},
+			// Landing: the height crosses zero while falling.
+			{Func: func(t float64, y []float64) float64 { return y[0] }, Direction: -1, Terminal: true},
+		},
+	}
+	sol, err := ode.Solve(f, 0, 100, []float64{0, 20}, settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	apex, landing := sol.Events[0], sol.Events[1]
+	fmt.Printf("apex at t=%.4f s, height %.4f m\n", apex.T, apex.Y[0])
+	fmt.Printf("landed at t=%.4f s, speed %.4f m/s\n", landing.T, -landing.Y[1])
+	fmt.Printf("height at t=1 s: %.4f m\n", sol.At(nil, 1)[0])
+
+	// Output:
+	// apex at t=1.8144 s, height 17.0995 m
+	// landed at t=3.7352 s, speed 16.8565 m/s
+	// height at t=1 s: 13.8105 m
+}
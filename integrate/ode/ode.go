@@ -0,0 +1,441 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrMaxSteps is returned by Solve when the maximum number of
+	// steps is taken before the end of the integration interval.
+	ErrMaxSteps = errors.New("ode: maximum number of steps reached")
+
+	// ErrStepTooSmall is returned by Solve when the step size required
+	// to achieve the requested tolerance is too small to be represented
+	// relative to the current time, usually because of a singularity, or
+	// because of stiffness when using an explicit method.
+	ErrStepTooSmall = errors.New("ode: step size too small")
+
+	// ErrNonFinite is returned by Solve when either end of the
+	// integration interval is infinite or NaN.
+	ErrNonFinite = errors.New("ode: non-finite integration interval")
+)
+
+const (
+	defaultAbsTol = 1e-6
+	defaultRelTol = 1e-3
+)
+
+// Func evaluates the derivative dy/dt = f(t, y) of the system at time t
+// and state y, storing the result in dy. Func must not modify y.
+type Func func(dy []float64, t float64, y []float64)
+
+// Event describes an event to be located during integration. An event
+// occurs when the value of its function crosses zero.
+type Event struct {
+	// Func returns the value whose zero crossings are events.
+	// It must be a continuous function of t and y.
+	Func func(t float64, y []float64) float64
+
+	// Direction restricts the events to crossings in one direction.
+	// If Direction is positive, only crossings from negative to
+	// positive values are events, and if Direction is negative, only
+	// crossings from positive to negative values are events. If
+	// Direction is zero, crossings in either direction are events.
+	Direction int
+
+	// Terminal specifies that integration stops at the
+	// first occurrence of the event.
+	Terminal bool
+}
+
+// Method is an integration method for initial value problems.
+type Method int
+
+const (
+	// DormandPrince45 is the explicit Runge–Kutta method of order 5(4)
+	// of Dormand and Prince, suitable for non-stiff problems. The
+	// continuous output is of order 4.
+	//  Dormand, J. R. and Prince, P. J. "A family of embedded Runge-Kutta
+	//  formulae." Journal of Computational and Applied Mathematics 6.1 (1980).
+	DormandPrince45 Method = iota
+
+	// Rosenbrock23 is the linearly implicit Rosenbrock method of order
+	// 2(3) of Shampine and Reichelt, suitable for stiff problems at
+	// moderate tolerances. The method is L-stable and requires the
+	// Jacobian of the system and the solution of linear systems with
+	// the matrix I - h*d*J at each step. The continuous output is of
+	// order 2.
+	//  Shampine, L. F. and Reichelt, M. W. "The MATLAB ODE Suite." SIAM
+	//  Journal on Scientific Computing 18.1 (1997).
+	Rosenbrock23
+)
+
+// Settings holds the settings for solving an initial value problem.
+type Settings struct {
+	// Method is the integration method. The zero value
+	// is DormandPrince45.
+	Method Method
+
+	// Jacobian evaluates the Jacobian matrix ∂f_i/∂y_j of the system
+	// at time t and state y, storing the result in dst, which has
+	// dimensions n×n for a state of length n. Jacobian is only used by
+	// implicit methods. If Jacobian is nil, the Jacobian is estimated
+	// by forward finite differences using n evaluations of the
+	// derivative.
+	Jacobian func(dst *mat.Dense, t float64, y []float64)
+
+	// AbsTol and RelTol are the absolute and relative tolerances
+	// of the local error of each step. The error in each component
+	// y_i is controlled to be at most about AbsTol + RelTol*|y_i|.
+	// If both are zero, defaults of 1e-6 and 1e-3 are used.
+	AbsTol float64
+	RelTol float64
+
+	// InitialStep is the magnitude of the first step. If InitialStep
+	// is zero, it is chosen automatically.
+	InitialStep float64
+
+	// MaxStep is the maximum magnitude of a step. If MaxStep is zero,
+	// the step size is not bounded.
+	MaxStep float64
+
+	// MaxSteps is the maximum number of accepted steps. If MaxSteps
+	// is zero, the number of steps is not bounded.
+	MaxSteps int
+
+	// Events holds the events to locate during integration.
+	Events []Event
+}
+
+// EventOccurrence is the location of an event found during integration.
+type EventOccurrence struct {
+	// Index is the index of the event in Settings.Events.
+	Index int
+
+	// T and Y are the time and state at which the event occurred.
+	T float64
+	Y []float64
+}
+
+// Stats holds statistics of an integration.
+type Stats struct {
+	Steps           int // Number of accepted steps
+	RejectedSteps   int // Number of rejected steps
+	FuncEvaluations int // Number of evaluations of Func, including for finite differences
+	JacEvaluations  int // Number of evaluations of the Jacobian
+	Decompositions  int // Number of LU decompositions
+}
+
+// Solution is the solution of an initial value problem.
+type Solution struct {
+	// T holds the times of the accepted steps, starting with the
+	// initial time, and Y holds the corresponding states.
+	T []float64
+	Y [][]float64
+
+	// Events holds the events found in the order of their occurrence.
+	Events []EventOccurrence
+
+	// Terminated indicates that the integration was stopped
+	// by a terminal event before the end of the interval.
+	Terminated bool
+
+	Stats Stats
+
+	// steps holds the dense output of each accepted step.
+	steps []interpolant
+}
+
+// At returns the solution at time t, computed by the continuous extension
+// of the integration method, placing the result in dst and returning it.
+// The order of accuracy of the continuous output depends on the method.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil,
+// it must have the same length as the state, otherwise At will panic. At
+// will panic if t is outside the interval spanned by s.T.
+func (s *Solution) At(dst []float64, t float64) []float64 {
+	n := len(s.Y[0])
+	if dst == nil {
+		dst = make([]float64, n)
+	} else if len(dst) != n {
+		panic("ode: destination length mismatch")
+	}
+	first, last := s.T[0], s.T[len(s.T)-1]
+	if t < math.Min(first, last) || math.Max(first, last) < t {
+		panic("ode: time out of range")
+	}
+	if len(s.steps) == 0 {
+		copy(dst, s.Y[0])
+		return dst
+	}
+	// Find the first step ending at or after t in
+	// the direction of integration.
+	forward := last >= first
+	i := sort.Search(len(s.steps), func(i int) bool {
+		if forward {
+			return s.T[i+1] >= t
+		}
+		return s.T[i+1] <= t
+	})
+	if i == len(s.steps) {
+		i--
+	}
+	s.steps[i].at(dst, t)
+	return dst
+}
+
+// Solve integrates the initial value problem
+//  dy/dt = f(t, y), y(t0) = y0
+// from t0 to t1 and returns the solution. The integration proceeds
+// backwards in time if t1 is less than t0.
+//
+// The problem is solved using the method given in settings with adaptive
+// step size control. The local error of each step is estimated from an
+// embedded solution of a different order and the step is accepted if the
+// root mean square of the error scaled by AbsTol + RelTol*|y_i| is at most
+// one. The returned Solution provides continuous output over the
+// integration interval.
+//
+// Events given in settings are located within each step using the
+// continuous output. If a terminal event occurs, the integration stops at
+// the event and the Terminated field of the Solution is set.
+//
+// If settings is nil, the default settings are used. If an error is
+// returned, the Solution holds the integration up to the point of failure.
+// Solve will panic if y0 is empty or if the settings are not valid.
+func Solve(f Func, t0, t1 float64, y0 []float64, settings *Settings) (*Solution, error) {
+	n := len(y0)
+	if n == 0 {
+		panic("ode: empty initial state")
+	}
+	var s Settings
+	if settings != nil {
+		s = *settings
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 {
+		panic("ode: negative tolerance")
+	}
+	if s.AbsTol == 0 && s.RelTol == 0 {
+		s.AbsTol = defaultAbsTol
+		s.RelTol = defaultRelTol
+	}
+	if s.Method != DormandPrince45 && s.Method != Rosenbrock23 {
+		panic("ode: unknown method")
+	}
+	if s.InitialStep < 0 || s.MaxStep < 0 || s.MaxSteps < 0 {
+		panic("ode: negative step setting")
+	}
+	for _, e := range s.Events {
+		if e.Func == nil {
+			panic("ode: nil event function")
+		}
+	}
+
+	sol := &Solution{
+		T: []float64{t0},
+		Y: [][]float64{append([]float64(nil), y0...)},
+	}
+	if math.IsInf(t0, 0) || math.IsNaN(t0) || math.IsInf(t1, 0) || math.IsNaN(t1) {
+		return sol, ErrNonFinite
+	}
+	if t0 == t1 {
+		return sol, nil
+	}
+	eval := func(dy []float64, t float64, y []float64) {
+		sol.Stats.FuncEvaluations++
+		f(dy, t, y)
+	}
+
+	dir := 1.0
+	if t1 < t0 {
+		dir = -1
+	}
+	maxStep := math.Inf(1)
+	if s.MaxStep > 0 {
+		maxStep = s.MaxStep
+	}
+
+	t := t0
+	y := append([]float64(nil), y0...)
+	f0 := make([]float64, n)
+	eval(f0, t, y)
+
+	var m stepper
+	switch s.Method {
+	case DormandPrince45:
+		m = newDormandPrince(eval, f0)
+	case Rosenbrock23:
+		m = newRosenbrock(eval, s.Jacobian, f0, &sol.Stats)
+	}
+	exponent := -1 / float64(m.errorOrder()+1)
+
+	hAbs := s.InitialStep
+	if hAbs == 0 {
+		hAbs = initialStep(eval, t0, y, f0, dir, m.errorOrder(), s.AbsTol, s.RelTol)
+	}
+	hAbs = math.Min(hAbs, math.Min(maxStep, math.Abs(t1-t0)))
+
+	gPrev := make([]float64, len(s.Events))
+	for i, e := range s.Events {
+		gPrev[i] = e.Func(t, y)
+	}
+
+	yNew := make([]float64, n)
+	yErr := make([]float64, n)
+	for t != t1 {
+		if s.MaxSteps > 0 && sol.Stats.Steps >= s.MaxSteps {
+			return sol, ErrMaxSteps
+		}
+
+		rejected := false
+		var tNew float64
+		for {
+			minStep := 10 * math.Abs(math.Nextafter(t, dir*math.Inf(1))-t)
+			if hAbs < minStep {
+				return sol, ErrStepTooSmall
+			}
+			hAbs = math.Min(hAbs, maxStep)
+			tNew = t + dir*hAbs
+			if dir*(tNew-t1) > 0 {
+				tNew = t1
+			}
+			h := tNew - t
+			hAbs = math.Abs(h)
+
+			m.step(yNew, yErr, t, h, y)
+			errNorm := errorNorm(yErr, y, yNew, s.AbsTol, s.RelTol)
+			if errNorm < 1 {
+				factor := maxFactor
+				if errNorm != 0 {
+					factor = math.Min(maxFactor, safety*math.Pow(errNorm, exponent))
+				}
+				if rejected {
+					factor = math.Min(1, factor)
+				}
+				hAbs *= factor
+				break
+			}
+			if math.IsNaN(errNorm) || math.IsInf(errNorm, 1) {
+				hAbs *= minFactor
+			} else {
+				hAbs *= math.Max(minFactor, safety*math.Pow(errNorm, exponent))
+			}
+			rejected = true
+			sol.Stats.RejectedSteps++
+		}
+		sol.Stats.Steps++
+
+		step := m.accept()
+		sol.steps = append(sol.steps, step)
+		if len(s.Events) != 0 {
+			tEvent, terminal := findEvents(sol, s.Events, gPrev, step, tNew, yNew)
+			if terminal {
+				if tEvent != tNew {
+					step.at(yNew, tEvent)
+				}
+				sol.T = append(sol.T, tEvent)
+				sol.Y = append(sol.Y, append([]float64(nil), yNew...))
+				sol.Terminated = true
+				return sol, nil
+			}
+		}
+		sol.T = append(sol.T, tNew)
+		sol.Y = append(sol.Y, append([]float64(nil), yNew...))
+
+		t = tNew
+		copy(y, yNew)
+	}
+	return sol, nil
+}
+
+// Step size control parameters.
+const (
+	safety    = 0.9
+	minFactor = 0.2
+	maxFactor = 10.0
+)
+
+// stepper is an integration method used by Solve.
+type stepper interface {
+	// errorOrder returns the order of the method
+	// used to estimate the local error.
+	errorOrder() int
+
+	// step takes a step of size h from the state y at time t,
+	// placing the solution in yNew and an estimate of its local
+	// error in yErr.
+	step(yNew, yErr []float64, t, h float64, y []float64)
+
+	// accept is called when the last step is accepted and
+	// returns the continuous extension of the step.
+	accept() interpolant
+}
+
+// interpolant is the continuous extension of an accepted step.
+type interpolant interface {
+	// at places the solution at time t into dst.
+	at(dst []float64, t float64)
+
+	// span returns the start time and size of the step.
+	span() (t, h float64)
+}
+
+// errorNorm returns the root mean square of the error estimate scaled by
+// the tolerances.
+func errorNorm(yErr, y, yNew []float64, absTol, relTol float64) float64 {
+	var sum float64
+	for i, e := range yErr {
+		sc := absTol + relTol*math.Max(math.Abs(y[i]), math.Abs(yNew[i]))
+		v := e / sc
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(yErr)))
+}
+
+// initialStep returns an initial step size based on the derivative at the
+// initial state and a finite difference estimate of the second derivative,
+// as described in
+//  Hairer, E., Nørsett, S. P. and Wanner, G. "Solving Ordinary Differential
+//  Equations I: Nonstiff Problems." Springer (1993), Section II.4.
+// The order is the order of the method used to estimate the local error.
+func initialStep(f Func, t0 float64, y0, f0 []float64, dir float64, order int, absTol, relTol float64) float64 {
+	n := len(y0)
+	rms := func(fn func(i int) float64) float64 {
+		var sum float64
+		for i := 0; i < n; i++ {
+			v := fn(i) / (absTol + relTol*math.Abs(y0[i]))
+			sum += v * v
+		}
+		return math.Sqrt(sum / float64(n))
+	}
+	d0 := rms(func(i int) float64 { return y0[i] })
+	d1 := rms(func(i int) float64 { return f0[i] })
+	h0 := 1e-6
+	if d0 >= 1e-5 && d1 >= 1e-5 {
+		h0 = 0.01 * d0 / d1
+	}
+
+	y1 := make([]float64, n)
+	for i := range y1 {
+		y1[i] = y0[i] + dir*h0*f0[i]
+	}
+	f1 := make([]float64, n)
+	f(f1, t0+dir*h0, y1)
+	d2 := rms(func(i int) float64 { return f1[i] - f0[i] }) / h0
+
+	var h1 float64
+	if d1 <= 1e-15 && d2 <= 1e-15 {
+		h1 = math.Max(1e-6, h0*1e-3)
+	} else {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1/float64(order+1))
+	}
+	return math.Min(100*h0, h1)
+}
@@ -0,0 +1,407 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+)
+
+type odeTest struct {
+	name   string
+	f      Func
+	t0, t1 float64
+	y0     []float64
+	exact  func(t float64) []float64
+}
+
+var odeTests = []odeTest{
+	{
+		name:  "decay",
+		f:     func(dy []float64, t float64, y []float64) { dy[0] = -y[0] },
+		t0:    0,
+		t1:    5,
+		y0:    []float64{1},
+		exact: func(t float64) []float64 { return []float64{math.Exp(-t)} },
+	},
+	{
+		name:  "decay backwards",
+		f:     func(dy []float64, t float64, y []float64) { dy[0] = -y[0] },
+		t0:    2,
+		t1:    -1,
+		y0:    []float64{math.Exp(-2)},
+		exact: func(t float64) []float64 { return []float64{math.Exp(-t)} },
+	},
+	{
+		name: "oscillator",
+		f: func(dy []float64, t float64, y []float64) {
+			dy[0] = y[1]
+			dy[1] = -y[0]
+		},
+		t0: 0,
+		t1: 20,
+		y0: []float64{0, 1},
+		exact: func(t float64) []float64 {
+			s, c := math.Sincos(t)
+			return []float64{s, c}
+		},
+	},
+	{
+		name: "time dependent",
+		f:    func(dy []float64, t float64, y []float64) { dy[0] = math.Cos(t) * y[0] },
+		t0:   0,
+		t1:   10,
+		y0:   []float64{1},
+		exact: func(t float64) []float64 {
+			return []float64{math.Exp(math.Sin(t))}
+		},
+	},
+	{
+		name: "logistic",
+		f: func(dy []float64, t float64, y []float64) {
+			dy[0] = y[0] * (1 - y[0])
+		},
+		t0: 0,
+		t1: 10,
+		y0: []float64{0.01},
+		exact: func(t float64) []float64 {
+			e := math.Exp(t)
+			return []float64{0.01 * e / (1 - 0.01 + 0.01*e)}
+		},
+	},
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+	for _, test := range odeTests {
+		for _, tol := range []float64{1e-4, 1e-8, 1e-11} {
+			settings := &Settings{AbsTol: tol, RelTol: tol}
+			var evals int
+			f := func(dy []float64, t float64, y []float64) {
+				evals++
+				test.f(dy, t, y)
+			}
+			sol, err := Solve(f, test.t0, test.t1, test.y0, settings)
+			if err != nil {
+				t.Errorf("%s: unexpected error for tol=%v: %v", test.name, tol, err)
+				continue
+			}
+			if sol.Terminated {
+				t.Errorf("%s: unexpected termination", test.name)
+			}
+			if sol.T[0] != test.t0 || sol.T[len(sol.T)-1] != test.t1 {
+				t.Errorf("%s: unexpected interval: got:[%v,%v] want:[%v,%v]", test.name, sol.T[0], sol.T[len(sol.T)-1], test.t0, test.t1)
+			}
+			if len(sol.T) != len(sol.Y) || len(sol.T) != sol.Stats.Steps+1 {
+				t.Errorf("%s: mismatched solution lengths: T:%d Y:%d steps:%d", test.name, len(sol.T), len(sol.Y), sol.Stats.Steps)
+			}
+			if evals != sol.Stats.FuncEvaluations {
+				t.Errorf("%s: unexpected number of evaluations: got:%d want:%d", test.name, sol.Stats.FuncEvaluations, evals)
+			}
+			// The first stage is reused from the previous step
+			// and the initial step selection uses one evaluation.
+			if want := 2 + 6*(sol.Stats.Steps+sol.Stats.RejectedSteps); evals != want {
+				t.Errorf("%s: unexpected number of evaluations: got:%d want:%d", test.name, evals, want)
+			}
+
+			// The global error is expected to be within a modest
+			// multiple of the tolerance for these problems.
+			bound := 100 * tol
+			for i, ti := range sol.T {
+				if i > 0 && (ti-sol.T[i-1])*(test.t1-test.t0) <= 0 {
+					t.Errorf("%s: times not monotonic at %d", test.name, i)
+				}
+				want := test.exact(ti)
+				for j, v := range sol.Y[i] {
+					if math.Abs(v-want[j]) > bound*math.Max(1, math.Abs(want[j])) {
+						t.Errorf("%s: unexpected solution for tol=%v at t=%v: got:%v want:%v", test.name, tol, ti, sol.Y[i], want)
+						break
+					}
+				}
+			}
+
+			// Check the dense output at the step times
+			// and between them.
+			for i, ti := range sol.T {
+				got := sol.At(nil, ti)
+				for j, v := range got {
+					if math.Abs(v-sol.Y[i][j]) > 1e-12*math.Max(1, math.Abs(v)) {
+						t.Errorf("%s: dense output mismatch at step time %v: got:%v want:%v", test.name, ti, got, sol.Y[i])
+						break
+					}
+				}
+			}
+			dst := make([]float64, len(test.y0))
+			for i := 1; i < len(sol.T); i++ {
+				for _, theta := range []float64{0.1, 0.5, 0.77} {
+					ti := sol.T[i-1] + theta*(sol.T[i]-sol.T[i-1])
+					sol.At(dst, ti)
+					want := test.exact(ti)
+					for j, v := range dst {
+						if math.Abs(v-want[j]) > bound*math.Max(1, math.Abs(want[j])) {
+							t.Errorf("%s: unexpected dense output for tol=%v at t=%v: got:%v want:%v", test.name, tol, ti, dst, want)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSolveTolerance(t *testing.T) {
+	t.Parallel()
+	// Tighter tolerances must take more steps and give smaller errors.
+	test := odeTests[2]
+	prevErr := math.Inf(1)
+	prevSteps := 0
+	for _, tol := range []float64{1e-3, 1e-6, 1e-9, 1e-12} {
+		sol, err := Solve(test.f, test.t0, test.t1, test.y0, &Settings{AbsTol: tol, RelTol: tol})
+		if err != nil {
+			t.Fatalf("unexpected error for tol=%v: %v", tol, err)
+		}
+		last := sol.Y[len(sol.Y)-1]
+		want := test.exact(test.t1)
+		e := math.Hypot(last[0]-want[0], last[1]-want[1])
+		if e >= prevErr {
+			t.Errorf("error did not decrease for tol=%v: got:%v previous:%v", tol, e, prevErr)
+		}
+		if sol.Stats.Steps <= prevSteps {
+			t.Errorf("steps did not increase for tol=%v: got:%d previous:%d", tol, sol.Stats.Steps, prevSteps)
+		}
+		prevErr = e
+		prevSteps = sol.Stats.Steps
+	}
+}
+
+func TestSolveMaxStep(t *testing.T) {
+	t.Parallel()
+	test := odeTests[0]
+	sol, err := Solve(test.f, test.t0, test.t1, test.y0, &Settings{MaxStep: 0.1, InitialStep: 0.01})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sol.T[1]-sol.T[0] != 0.01 {
+		t.Errorf("unexpected initial step: got:%v want:0.01", sol.T[1]-sol.T[0])
+	}
+	for i := 1; i < len(sol.T); i++ {
+		if h := sol.T[i] - sol.T[i-1]; h > 0.1+1e-15 {
+			t.Errorf("step exceeds maximum: %v", h)
+		}
+	}
+	if sol.Stats.Steps < 50 {
+		t.Errorf("too few steps for maximum step size: %d", sol.Stats.Steps)
+	}
+}
+
+func TestSolveEvents(t *testing.T) {
+	t.Parallel()
+	const g = 9.81
+	ball := func(dy []float64, t float64, y []float64) {
+		dy[0] = y[1]
+		dy[1] = -g
+	}
+	height := func(t float64, y []float64) float64 { return y[0] }
+
+	// A ball dropped from a height of 10 hits the ground
+	// at t = sqrt(2*10/g).
+	sol, err := Solve(ball, 0, 10, []float64{10, 0}, &Settings{
+		AbsTol: 1e-10,
+		RelTol: 1e-10,
+		Events: []Event{{Func: height, Direction: -1, Terminal: true}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := math.Sqrt(2 * 10 / g)
+	if !sol.Terminated {
+		t.Errorf("expected termination")
+	}
+	if len(sol.Events) != 1 {
+		t.Fatalf("unexpected number of events: got:%d want:1", len(sol.Events))
+	}
+	ev := sol.Events[0]
+	if math.Abs(ev.T-want) > 1e-9 {
+		t.Errorf("unexpected event time: got:%v want:%v", ev.T, want)
+	}
+	if math.Abs(ev.Y[0]) > 1e-9 || math.Abs(ev.Y[1]+g*want) > 1e-8 {
+		t.Errorf("unexpected event state: got:%v want:[0 %v]", ev.Y, -g*want)
+	}
+	last := len(sol.T) - 1
+	if sol.T[last] != ev.T {
+		t.Errorf("integration did not stop at event: got:%v want:%v", sol.T[last], ev.T)
+	}
+	for j, v := range sol.Y[last] {
+		if v != ev.Y[j] {
+			t.Errorf("final state does not match event state: got:%v want:%v", sol.Y[last], ev.Y)
+			break
+		}
+	}
+	sol.At(nil, ev.T)
+
+	// Non-terminal events in the oscillator at the zeros of sin and cos.
+	osc := odeTests[2]
+	for _, test := range []struct {
+		direction int
+		want      []float64
+	}{
+		{direction: 0, want: []float64{math.Pi, 2 * math.Pi, 3 * math.Pi, 4 * math.Pi, 5 * math.Pi, 6 * math.Pi}},
+		{direction: 1, want: []float64{2 * math.Pi, 4 * math.Pi, 6 * math.Pi}},
+		{direction: -1, want: []float64{math.Pi, 3 * math.Pi, 5 * math.Pi}},
+	} {
+		events := []Event{
+			{Func: func(t float64, y []float64) float64 { return y[0] }, Direction: test.direction},
+			{Func: func(t float64, y []float64) float64 { return y[1] }, Direction: test.direction},
+		}
+		sol, err := Solve(osc.f, osc.t0, osc.t1, osc.y0, &Settings{AbsTol: 1e-10, RelTol: 1e-10, Events: events})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sol.Terminated {
+			t.Errorf("unexpected termination")
+		}
+		var got []float64
+		for i, ev := range sol.Events {
+			if i > 0 && ev.T < sol.Events[i-1].T {
+				t.Errorf("events out of order for direction %d", test.direction)
+			}
+			if ev.Index == 0 {
+				got = append(got, ev.T)
+			} else {
+				// Zeros of cos are offset by π/2 from those of sin.
+				if r := math.Mod(ev.T-math.Pi/2, math.Pi); math.Min(r, math.Pi-r) > 1e-8 {
+					t.Errorf("unexpected event time for cos: %v", ev.T)
+				}
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of events for direction %d: got:%v want:%v", test.direction, got, test.want)
+			continue
+		}
+		for i, v := range got {
+			if math.Abs(v-test.want[i]) > 1e-8 {
+				t.Errorf("unexpected event time for direction %d: got:%v want:%v", test.direction, v, test.want[i])
+			}
+		}
+	}
+}
+
+func TestEventRoot(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		g    func(float64) float64
+		a, b float64
+		want float64
+	}{
+		{g: func(x float64) float64 { return x*x - 2 }, a: 0, b: 2, want: math.Sqrt2},
+		{g: func(x float64) float64 { return x*x - 2 }, a: 3, b: 1, want: math.Sqrt2},
+		{g: func(x float64) float64 { return math.Pow(x, 9) - 1e-9 }, a: -1, b: 1, want: 0.1},
+		{g: func(x float64) float64 { return math.Cos(x) }, a: 0, b: 3, want: math.Pi / 2},
+	} {
+		got := eventRoot(test.g, test.a, test.b, test.g(test.a), test.g(test.b))
+		if math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected root: got:%v want:%v", got, test.want)
+		}
+		if math.Signbit(test.g(got)) != math.Signbit(test.g(test.b)) && test.g(got) != 0 {
+			t.Errorf("root not on the side of b: g(%v)=%v", got, test.g(got))
+		}
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	t.Parallel()
+	// y' = y² with y(0) = 1 has the solution
+	// 1/(1-t) which blows up at t = 1.
+	blowup := func(dy []float64, t float64, y []float64) { dy[0] = y[0] * y[0] }
+	sol, err := Solve(blowup, 0, 2, []float64{1}, nil)
+	if err != ErrStepTooSmall {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrStepTooSmall)
+	}
+	if last := sol.T[len(sol.T)-1]; last >= 1 || last < 0.99 {
+		t.Errorf("unexpected final time: %v", last)
+	}
+
+	test := odeTests[2]
+	sol, err = Solve(test.f, test.t0, test.t1, test.y0, &Settings{MaxSteps: 5})
+	if err != ErrMaxSteps {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrMaxSteps)
+	}
+	if sol.Stats.Steps != 5 || len(sol.T) != 6 {
+		t.Errorf("unexpected number of steps: got:%d", sol.Stats.Steps)
+	}
+
+	for _, t1 := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		sol, err = Solve(test.f, test.t0, t1, test.y0, nil)
+		if err != ErrNonFinite {
+			t.Errorf("unexpected error for t1=%v: got:%v want:%v", t1, err, ErrNonFinite)
+		}
+		if len(sol.T) != 1 || sol.Stats.FuncEvaluations != 0 {
+			t.Errorf("unexpected solution for t1=%v: %+v", t1, sol)
+		}
+	}
+	sol, err = Solve(test.f, math.NaN(), test.t1, test.y0, nil)
+	if err != ErrNonFinite {
+		t.Errorf("unexpected error for NaN t0: got:%v want:%v", err, ErrNonFinite)
+	}
+}
+
+func TestSolveEmptyInterval(t *testing.T) {
+	t.Parallel()
+	test := odeTests[0]
+	sol, err := Solve(test.f, 1, 1, test.y0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sol.T) != 1 || sol.Stats.FuncEvaluations != 0 {
+		t.Errorf("unexpected solution for empty interval: %+v", sol)
+	}
+	if got := sol.At(nil, 1); got[0] != test.y0[0] {
+		t.Errorf("unexpected dense output: got:%v want:%v", got, test.y0)
+	}
+}
+
+func TestSolvePanics(t *testing.T) {
+	t.Parallel()
+	f := odeTests[0].f
+	sol, _ := Solve(f, 0, 1, []float64{1}, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{
+			name: "empty state",
+			fn:   func() { Solve(f, 0, 1, nil, nil) },
+		},
+		{
+			name: "negative tolerance",
+			fn:   func() { Solve(f, 0, 1, []float64{1}, &Settings{AbsTol: -1}) },
+		},
+		{
+			name: "negative max step",
+			fn:   func() { Solve(f, 0, 1, []float64{1}, &Settings{MaxStep: -1}) },
+		},
+		{
+			name: "nil event",
+			fn:   func() { Solve(f, 0, 1, []float64{1}, &Settings{Events: []Event{{}}}) },
+		},
+		{
+			name: "time out of range",
+			fn:   func() { sol.At(nil, 1.5) },
+		},
+		{
+			name: "destination length",
+			fn:   func() { sol.At(make([]float64, 2), 0.5) },
+		},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected panic", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
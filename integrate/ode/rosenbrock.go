@@ -0,0 +1,196 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Coefficients of the Rosenbrock 2(3) method of Shampine and Reichelt.
+var (
+	rbD   = 1 / (2 + math.Sqrt2)
+	rbE32 = 6 + math.Sqrt2
+)
+
+// rosenbrock implements the Rosenbrock 2(3) method. The stages of a step
+// of size h from y at time t with derivative f0 are
+//  W = I - h*d*J
+//  W k1 = f0 + h*d*T
+//  f1 = f(t + h/2, y + h/2*k1)
+//  W (k2 - k1) = f1 - k1
+//  yNew = y + h*k2
+//  f2 = f(t + h, yNew)
+//  W k3 = f2 - e32*(k2 - f1) - 2*(k1 - f0) + h*d*T
+// where J = ∂f/∂y and T = ∂f/∂t at (t, y), and the error of yNew is
+// estimated by h/6*(k1 - 2*k2 + k3).
+type rosenbrock struct {
+	f     Func
+	jac   func(dst *mat.Dense, t float64, y []float64)
+	stats *Stats
+
+	// f0 holds the derivative at the start of the step
+	// and f2 the derivative at the end of the last step.
+	f0, f1, f2 []float64
+	k1, k2, k3 []float64
+	tmp        []float64
+
+	// j and dfdt hold the Jacobian and the time derivative
+	// at the start of the step. They are evaluated on the
+	// first attempt at each step and reused on rejection.
+	j       *mat.Dense
+	dfdt    []float64
+	current bool
+
+	w  *mat.Dense
+	lu mat.LU
+
+	t, h float64
+	y    []float64
+}
+
+func newRosenbrock(f Func, jac func(dst *mat.Dense, t float64, y []float64), f0 []float64, stats *Stats) *rosenbrock {
+	n := len(f0)
+	return &rosenbrock{
+		f:     f,
+		jac:   jac,
+		stats: stats,
+		f0:    append([]float64(nil), f0...),
+		f1:    make([]float64, n),
+		f2:    make([]float64, n),
+		k1:    make([]float64, n),
+		k2:    make([]float64, n),
+		k3:    make([]float64, n),
+		tmp:   make([]float64, n),
+		j:     mat.NewDense(n, n, nil),
+		dfdt:  make([]float64, n),
+		w:     mat.NewDense(n, n, nil),
+	}
+}
+
+func (m *rosenbrock) errorOrder() int { return 2 }
+
+// jacobian evaluates the Jacobian and the time derivative
+// of the system at (t, y).
+func (m *rosenbrock) jacobian(t float64, y []float64, dir float64) {
+	m.stats.JacEvaluations++
+	if m.jac != nil {
+		m.jac(m.j, t, y)
+	} else {
+		fd.Jacobian(m.j, func(dy, x []float64) {
+			m.f(dy, t, x)
+		}, y, &fd.JacobianSettings{
+			Formula:     fd.Forward,
+			OriginValue: m.f0,
+		})
+	}
+
+	// Estimate ∂f/∂t by a forward difference in the
+	// direction of integration.
+	dt := dir * math.Sqrt(eps) * math.Max(1, math.Abs(t))
+	m.f(m.tmp, t+dt, y)
+	for i, v := range m.tmp {
+		m.dfdt[i] = (v - m.f0[i]) / dt
+	}
+}
+
+func (m *rosenbrock) step(yNew, yErr []float64, t, h float64, y []float64) {
+	m.t, m.h, m.y = t, h, y
+	if !m.current {
+		m.jacobian(t, y, math.Copysign(1, h))
+		m.current = true
+	}
+
+	n := len(y)
+	hd := h * rbD
+	for i := 0; i < n; i++ {
+		for k := 0; k < n; k++ {
+			m.w.Set(i, k, -hd*m.j.At(i, k))
+		}
+		m.w.Set(i, i, 1+m.w.At(i, i))
+	}
+	m.stats.Decompositions++
+	m.lu.Factorize(m.w)
+	if m.lu.Det() == 0 {
+		// The step cannot be taken with a singular
+		// iteration matrix so reject it.
+		for i := range yErr {
+			yErr[i] = math.Inf(1)
+		}
+		return
+	}
+	solve := func(x []float64) {
+		v := mat.NewVecDense(n, x)
+		// Near-singularity is reflected in the error estimate.
+		_ = m.lu.SolveVecTo(v, false, v)
+	}
+
+	for i := range m.k1 {
+		m.k1[i] = m.f0[i] + hd*m.dfdt[i]
+	}
+	solve(m.k1)
+
+	for i := range m.tmp {
+		m.tmp[i] = y[i] + 0.5*h*m.k1[i]
+	}
+	m.f(m.f1, t+0.5*h, m.tmp)
+	for i := range m.k2 {
+		m.k2[i] = m.f1[i] - m.k1[i]
+	}
+	solve(m.k2)
+	for i := range m.k2 {
+		m.k2[i] += m.k1[i]
+	}
+
+	for i := range yNew {
+		yNew[i] = y[i] + h*m.k2[i]
+	}
+	m.f(m.f2, t+h, yNew)
+	for i := range m.k3 {
+		m.k3[i] = m.f2[i] - rbE32*(m.k2[i]-m.f1[i]) - 2*(m.k1[i]-m.f0[i]) + hd*m.dfdt[i]
+	}
+	solve(m.k3)
+
+	for i := range yErr {
+		yErr[i] = h / 6 * (m.k1[i] - 2*m.k2[i] + m.k3[i])
+	}
+}
+
+func (m *rosenbrock) accept() interpolant {
+	r := rosenbrockStep{
+		t:  m.t,
+		h:  m.h,
+		y:  append([]float64(nil), m.y...),
+		k1: append([]float64(nil), m.k1...),
+		k2: append([]float64(nil), m.k2...),
+	}
+	// The derivative at the end of the step is the
+	// derivative at the start of the next step.
+	m.f0, m.f2 = m.f2, m.f0
+	m.current = false
+	return r
+}
+
+// rosenbrockStep holds the continuous extension of an accepted step
+// of the Rosenbrock 2(3) method,
+//  y(t + s*h) = y + h*(s*(1-s)*k1 + s*(s-2*d)*k2)/(1-2*d).
+type rosenbrockStep struct {
+	t, h   float64
+	y      []float64
+	k1, k2 []float64
+}
+
+func (r rosenbrockStep) span() (t, h float64) { return r.t, r.h }
+
+func (r rosenbrockStep) at(dst []float64, t float64) {
+	s := (t - r.t) / r.h
+	c1 := r.h * s * (1 - s) / (1 - 2*rbD)
+	c2 := r.h * s * (s - 2*rbD) / (1 - 2*rbD)
+	for i, v := range r.y {
+		dst[i] = v + c1*r.k1[i] + c2*r.k2[i]
+	}
+}
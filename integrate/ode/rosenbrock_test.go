@@ -0,0 +1,181 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRosenbrock(t *testing.T) {
+	t.Parallel()
+	for _, test := range odeTests {
+		for _, tol := range []float64{1e-4, 1e-7} {
+			var evals int
+			f := func(dy []float64, t float64, y []float64) {
+				evals++
+				test.f(dy, t, y)
+			}
+			sol, err := Solve(f, test.t0, test.t1, test.y0, &Settings{
+				Method: Rosenbrock23,
+				AbsTol: tol,
+				RelTol: tol,
+			})
+			if err != nil {
+				t.Errorf("%s: unexpected error for tol=%v: %v", test.name, tol, err)
+				continue
+			}
+			if sol.T[len(sol.T)-1] != test.t1 {
+				t.Errorf("%s: integration did not reach end: %v", test.name, sol.T[len(sol.T)-1])
+			}
+			st := sol.Stats
+			if st.JacEvaluations != st.Steps {
+				t.Errorf("%s: unexpected number of Jacobian evaluations: got:%d want:%d", test.name, st.JacEvaluations, st.Steps)
+			}
+			if st.Decompositions != st.Steps+st.RejectedSteps {
+				t.Errorf("%s: unexpected number of decompositions: got:%d want:%d", test.name, st.Decompositions, st.Steps+st.RejectedSteps)
+			}
+			// Each Jacobian uses n evaluations for the finite difference
+			// Jacobian and one for the time derivative, and each attempted
+			// step uses two evaluations.
+			n := len(test.y0)
+			want := 2 + (n+1)*st.Steps + 2*(st.Steps+st.RejectedSteps)
+			if evals != want || st.FuncEvaluations != want {
+				t.Errorf("%s: unexpected number of evaluations: got:%d stats:%d want:%d", test.name, evals, st.FuncEvaluations, want)
+			}
+
+			// The method is of low order so the local errors
+			// accumulate to a global error that is allowed to
+			// grow with the number of steps.
+			bound := 4 * tol * float64(st.Steps)
+			dst := make([]float64, n)
+			for i := 1; i < len(sol.T); i++ {
+				for _, theta := range []float64{0, 0.3, 0.5, 1} {
+					ti := sol.T[i-1] + theta*(sol.T[i]-sol.T[i-1])
+					sol.At(dst, ti)
+					want := test.exact(ti)
+					for j, v := range dst {
+						if math.Abs(v-want[j]) > bound*math.Max(1, math.Abs(want[j])) {
+							t.Errorf("%s: unexpected solution for tol=%v at t=%v: got:%v want:%v", test.name, tol, ti, dst, want)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRosenbrockStiff(t *testing.T) {
+	t.Parallel()
+	// y' = -λ(y - cos(t)) - sin(t) has the solution y = cos(t) for
+	// y(0) = 1 with a rapidly decaying transient for any other initial
+	// value. An explicit method requires step sizes of order 1/λ.
+	const lambda = 1e4
+	f := func(dy []float64, t float64, y []float64) {
+		dy[0] = -lambda*(y[0]-math.Cos(t)) - math.Sin(t)
+	}
+	jac := func(dst *mat.Dense, t float64, y []float64) {
+		dst.Set(0, 0, -lambda)
+	}
+	for _, jacobian := range []func(*mat.Dense, float64, []float64){nil, jac} {
+		sol, err := Solve(f, 0, 10, []float64{2}, &Settings{
+			Method:   Rosenbrock23,
+			AbsTol:   1e-4,
+			RelTol:   1e-4,
+			Jacobian: jacobian,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sol.Stats.Steps > 1000 {
+			t.Errorf("too many steps for stiff problem: %d", sol.Stats.Steps)
+		}
+		last := sol.Y[len(sol.Y)-1][0]
+		if math.Abs(last-math.Cos(10)) > 1e-3 {
+			t.Errorf("unexpected solution: got:%v want:%v", last, math.Cos(10))
+		}
+	}
+
+	sol, err := Solve(f, 0, 10, []float64{2}, &Settings{AbsTol: 1e-4, RelTol: 1e-4, MaxSteps: 5000})
+	if err != ErrMaxSteps {
+		t.Errorf("expected explicit method to exceed maximum steps: got err=%v steps=%d", err, sol.Stats.Steps)
+	}
+}
+
+func TestRosenbrockRobertson(t *testing.T) {
+	t.Parallel()
+	// Robertson's chemical kinetics problem with reference
+	// values at t = 40 from Hairer and Wanner, "Solving Ordinary
+	// Differential Equations II", Section IV.10.
+	f := func(dy []float64, t float64, y []float64) {
+		dy[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+		dy[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+		dy[2] = 3e7 * y[1] * y[1]
+	}
+	jac := func(dst *mat.Dense, t float64, y []float64) {
+		dst.Set(0, 0, -0.04)
+		dst.Set(0, 1, 1e4*y[2])
+		dst.Set(0, 2, 1e4*y[1])
+		dst.Set(1, 0, 0.04)
+		dst.Set(1, 1, -1e4*y[2]-6e7*y[1])
+		dst.Set(1, 2, -1e4*y[1])
+		dst.Set(2, 0, 0)
+		dst.Set(2, 1, 6e7*y[1])
+		dst.Set(2, 2, 0)
+	}
+	want := []float64{0.7158270687, 9.185534764e-6, 0.2841637457}
+	for _, jacobian := range []func(*mat.Dense, float64, []float64){nil, jac} {
+		sol, err := Solve(f, 0, 40, []float64{1, 0, 0}, &Settings{
+			Method:   Rosenbrock23,
+			AbsTol:   1e-10,
+			RelTol:   1e-6,
+			Jacobian: jacobian,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := sol.Y[len(sol.Y)-1]
+		for i, v := range got {
+			if math.Abs(v-want[i]) > 1e-4*math.Abs(want[i]) {
+				t.Errorf("unexpected solution: got:%v want:%v", got, want)
+				break
+			}
+		}
+		if sum := got[0] + got[1] + got[2]; math.Abs(sum-1) > 1e-9 {
+			t.Errorf("mass not conserved: %v", sum)
+		}
+		if sol.Stats.Steps > 1000 {
+			t.Errorf("too many steps: %d", sol.Stats.Steps)
+		}
+	}
+}
+
+func TestRosenbrockEvents(t *testing.T) {
+	t.Parallel()
+	// Stop the stiff problem when the solution
+	// cos(t) first crosses zero at π/2.
+	const lambda = 1e4
+	f := func(dy []float64, t float64, y []float64) {
+		dy[0] = -lambda*(y[0]-math.Cos(t)) - math.Sin(t)
+	}
+	sol, err := Solve(f, 0, 10, []float64{1}, &Settings{
+		Method: Rosenbrock23,
+		AbsTol: 1e-8,
+		RelTol: 1e-8,
+		Events: []Event{{Func: func(t float64, y []float64) float64 { return y[0] }, Terminal: true}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sol.Terminated || len(sol.Events) != 1 {
+		t.Fatalf("expected a terminal event: %+v", sol.Events)
+	}
+	if got := sol.Events[0].T; math.Abs(got-math.Pi/2) > 1e-6 {
+		t.Errorf("unexpected event time: got:%v want:%v", got, math.Pi/2)
+	}
+}
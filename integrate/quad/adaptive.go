@@ -0,0 +1,754 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrMaxIntervals is returned by Adaptive when the maximum number of
+	// subintervals is reached before the requested tolerance is achieved.
+	ErrMaxIntervals = errors.New("quad: maximum number of subintervals reached")
+
+	// ErrRoundoff is returned by Adaptive when roundoff error prevents
+	// the requested tolerance from being achieved.
+	ErrRoundoff = errors.New("quad: roundoff error prevents reaching tolerance")
+
+	// ErrBadIntegrand is returned by Adaptive when the integrand behaves
+	// so badly at some point of the integration interval that further
+	// subdivision is not possible.
+	ErrBadIntegrand = errors.New("quad: bad integrand behavior")
+
+	// ErrExtrapolationRoundoff is returned by Adaptive when roundoff
+	// error in the extrapolation table prevents the requested tolerance
+	// from being achieved.
+	ErrExtrapolationRoundoff = errors.New("quad: roundoff error in extrapolation table")
+
+	// ErrDivergent is returned by Adaptive when the integral is likely
+	// to be divergent or to converge too slowly to be estimated.
+	ErrDivergent = errors.New("quad: integral divergent or slowly convergent")
+)
+
+const (
+	defaultAbsTol       = 1.49e-8
+	defaultRelTol       = 1.49e-8
+	defaultMaxIntervals = 1000
+)
+
+// AdaptiveSettings holds the settings for adaptive integration.
+type AdaptiveSettings struct {
+	// AbsTol and RelTol are the absolute and relative tolerances of the
+	// integral. The integration terminates when the estimated absolute
+	// error is at most max(AbsTol, RelTol*|integral|). Both tolerances
+	// must be non-negative, and either AbsTol must be positive or RelTol
+	// must be at least 50 times machine epsilon. If both AbsTol and RelTol
+	// are zero, defaults of 1.49e-8 are used.
+	AbsTol float64
+	RelTol float64
+
+	// MaxIntervals is the maximum number of subintervals used. If
+	// MaxIntervals is zero, a default of 1000 is used.
+	MaxIntervals int
+
+	// Breakpoints holds points strictly inside the integration interval
+	// where the integrand is singular or discontinuous, or is otherwise
+	// difficult to integrate. The integration interval is initially
+	// divided at the breakpoints, so that difficulties at the breakpoints
+	// are handled as difficulties at the ends of subintervals.
+	Breakpoints []float64
+}
+
+// AdaptiveResult holds the result of an adaptive integration.
+type AdaptiveResult struct {
+	// Value is the estimate of the integral.
+	Value float64
+
+	// Error is the estimate of the absolute error of Value.
+	Error float64
+
+	// Intervals is the number of subintervals used.
+	Intervals int
+
+	// Evaluations is the number of evaluations of the integrand.
+	Evaluations int
+}
+
+// Adaptive approximates the integral of the function f from min to max
+//  int_min^max f(x) dx
+// using globally adaptive Gauss–Kronrod quadrature. The subinterval with the
+// largest error estimate is repeatedly bisected, with each subinterval
+// integrated using a 21-point Kronrod rule whose error is estimated from the
+// embedded 10-point Gauss rule. The sequence of estimates is accelerated
+// using the epsilon algorithm, so that integrable singularities at the ends
+// of the interval or at breakpoints are handled efficiently. The algorithm
+// follows QAGS and QAGP from QUADPACK,
+//  Piessens, R., de Doncker-Kapenga, E., Überhuber, C. W. and Kahaner, D. K.
+//  "QUADPACK: A subroutine package for automatic integration." Springer (1983).
+//
+// Either or both of min and max may be infinite, in which case the integral
+// is transformed to one over a subinterval of (0, 1] before integration.
+// The integrand is never evaluated at the ends of the interval or at
+// breakpoints.
+//
+// If settings is nil, absolute and relative tolerances of 1.49e-8 are used.
+// The integration fails with ErrBadIntegrand if the integrand produces
+// a non-finite estimate of the integral or its error.
+//
+// Adaptive returns the result and a nil error if the requested tolerance
+// was achieved. Otherwise the result holds the best estimate found and the
+// error is one of ErrMaxIntervals, ErrRoundoff, ErrBadIntegrand,
+// ErrExtrapolationRoundoff or ErrDivergent.
+//
+// min must be less than or equal to max, the breakpoints must lie strictly
+// between min and max, and the settings must be valid, otherwise Adaptive
+// will panic.
+func Adaptive(f func(float64) float64, min, max float64, settings *AdaptiveSettings) (AdaptiveResult, error) {
+	if min > max {
+		panic("quad: min > max")
+	}
+	s := AdaptiveSettings{
+		AbsTol: defaultAbsTol,
+		RelTol: defaultRelTol,
+	}
+	if settings != nil {
+		s = *settings
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 {
+		panic("quad: negative tolerance")
+	}
+	if s.AbsTol == 0 && s.RelTol == 0 {
+		s.AbsTol = defaultAbsTol
+		s.RelTol = defaultRelTol
+	}
+	if s.AbsTol == 0 && s.RelTol < 50*eps {
+		panic("quad: tolerance too small")
+	}
+	if s.MaxIntervals < 0 {
+		panic("quad: negative maximum number of subintervals")
+	}
+	if s.MaxIntervals == 0 {
+		s.MaxIntervals = defaultMaxIntervals
+	}
+	for _, p := range s.Breakpoints {
+		if !(min < p && p < max) {
+			panic("quad: breakpoint outside integration interval")
+		}
+	}
+	if min == max {
+		return AdaptiveResult{}, nil
+	}
+
+	var evals int
+	g := func(x float64) float64 {
+		evals++
+		return f(x)
+	}
+	// int_a^b f(x)dx = int_u^-1(a)^u^-1(b) f(u(t))u'(t)dt
+	// where toT is u^-1.
+	lo, hi := min, max
+	toT := func(x float64) float64 { return x }
+	switch {
+	case math.IsInf(min, -1) && math.IsInf(max, 1):
+		// u(t) = ±(1-t)/t folding the negative half
+		// onto the positive half.
+		lo, hi = 0, 1
+		toT = func(x float64) float64 { return 1 / (1 + math.Abs(x)) }
+		g = func(t float64) float64 {
+			evals += 2
+			x := (1 - t) / t
+			return (f(x) + f(-x)) / (t * t)
+		}
+	case math.IsInf(max, 1):
+		// u(t) = a + (1-t)/t
+		a := min
+		lo, hi = 0, 1
+		toT = func(x float64) float64 { return 1 / (1 + x - a) }
+		g = func(t float64) float64 {
+			evals++
+			return f(a+(1-t)/t) / (t * t)
+		}
+	case math.IsInf(min, -1):
+		// u(t) = b - (1-t)/t
+		b := max
+		lo, hi = 0, 1
+		toT = func(x float64) float64 { return 1 / (1 + b - x) }
+		g = func(t float64) float64 {
+			evals++
+			return f(b-(1-t)/t) / (t * t)
+		}
+	}
+
+	points := make([]float64, 0, len(s.Breakpoints)+2)
+	points = append(points, lo, hi)
+	for _, p := range s.Breakpoints {
+		points = append(points, toT(p))
+	}
+	sort.Float64s(points)
+	// Remove repeated points.
+	n := 1
+	for _, p := range points[1:] {
+		if p != points[n-1] {
+			points[n] = p
+			n++
+		}
+	}
+	points = points[:n]
+	if len(points)-1 > s.MaxIntervals {
+		panic("quad: too many breakpoints for maximum number of subintervals")
+	}
+
+	res, err := qags(g, points, s.AbsTol, s.RelTol, s.MaxIntervals)
+	res.Evaluations = evals
+	return res, err
+}
+
+const (
+	eps       = 0x1p-52
+	minNormal = 0x1p-1022
+)
+
+// qags integrates f over the intervals between consecutive points using
+// globally adaptive bisection with extrapolation.
+func qags(f func(float64) float64, points []float64, absTol, relTol float64, limit int) (AdaptiveResult, error) {
+	w := &adaptiveWork{intervals: make([]interval, 0, limit)}
+
+	var result0, abserr0, resabs0, resasc0 float64
+	for k := 0; k < len(points)-1; k++ {
+		r, e, rabs, rasc := gk21(f, points[k], points[k+1])
+		result0 += r
+		abserr0 += e
+		resabs0 += rabs
+		resasc0 += rasc
+		w.intervals = append(w.intervals, interval{a: points[k], b: points[k+1], value: r, err: e})
+	}
+	w.sort(0)
+
+	// Test on accuracy of the first approximation.
+	tolerance := math.Max(absTol, relTol*math.Abs(result0))
+	first := AdaptiveResult{Value: result0, Error: abserr0, Intervals: len(w.intervals)}
+	switch {
+	case !isFinite(result0) || !isFinite(abserr0):
+		return first, ErrBadIntegrand
+	case abserr0 <= 100*eps*resabs0 && abserr0 > tolerance:
+		return first, ErrRoundoff
+	case (abserr0 <= tolerance && abserr0 != resasc0) || abserr0 == 0:
+		return first, nil
+	case len(w.intervals) >= limit:
+		return first, ErrMaxIntervals
+	}
+
+	var table epsilonTable
+	table.append(result0)
+	area := result0
+	errsum := abserr0
+	resExt := result0
+	errExt := math.MaxFloat64
+	positive := math.Abs(result0) >= (1-50*eps)*resabs0
+
+	var (
+		ertest, errLarge    float64
+		correc              float64
+		ktmin               int
+		roundoff1           int
+		roundoff2           int
+		roundoff3           int
+		errType             int
+		errType2            bool
+		extrapolate         bool
+		disallowExtrapolate bool
+	)
+	initial := len(w.intervals)
+	iteration := initial
+	sumResult := false
+	for iteration < limit {
+		// Bisect the subinterval with the largest error estimate.
+		cur := w.intervals[w.i]
+		level := cur.level + 1
+		a1, b1 := cur.a, 0.5*(cur.a+cur.b)
+		a2, b2 := b1, cur.b
+		if !hasInteriorNodes(a1, b1) || !hasInteriorNodes(a2, b2) {
+			// The interval is too small to be bisected
+			// without evaluating f at an end point.
+			errType = 4
+			break
+		}
+		iteration++
+
+		area1, error1, _, resasc1 := gk21(f, a1, b1)
+		area2, error2, _, resasc2 := gk21(f, a2, b2)
+		area12 := area1 + area2
+		error12 := error1 + error2
+
+		// Improve previous approximations to the integral
+		// and test for accuracy.
+		errsum += error12 - cur.err
+		area += area12 - cur.value
+		tolerance = math.Max(absTol, relTol*math.Abs(area))
+		if resasc1 != error1 && resasc2 != error2 {
+			delta := cur.value - area12
+			if math.Abs(delta) <= 1e-5*math.Abs(area12) && error12 >= 0.99*cur.err {
+				if !extrapolate {
+					roundoff1++
+				} else {
+					roundoff2++
+				}
+			}
+			if iteration > 10 && error12 > cur.err {
+				roundoff3++
+			}
+		}
+
+		// Test for roundoff and set the error flag.
+		if roundoff1+roundoff2 >= 10 || roundoff3 >= 20 {
+			errType = 2
+		}
+		if roundoff2 >= 5 {
+			errType2 = true
+		}
+		// Set the error flag in the case of bad integrand
+		// behavior at a point of the integration range.
+		if tooSmall(a1, a2, b2) {
+			errType = 4
+		}
+
+		w.update(interval{a: a1, b: b1, value: area1, err: error1, level: level},
+			interval{a: a2, b: b2, value: area2, err: error2, level: level})
+
+		if !isFinite(area) || !isFinite(errsum) {
+			errType = 4
+			break
+		}
+		if errsum <= tolerance {
+			sumResult = true
+			break
+		}
+		if errType != 0 {
+			break
+		}
+		if iteration >= limit {
+			errType = 1
+			break
+		}
+		if iteration == initial+1 {
+			errLarge = errsum
+			ertest = tolerance
+			table.append(area)
+			continue
+		}
+		if disallowExtrapolate {
+			continue
+		}
+
+		errLarge -= cur.err
+		if level < w.maxLevel {
+			errLarge += error12
+		}
+		if !extrapolate {
+			// Test whether the interval to be bisected
+			// next is the smallest interval.
+			if w.intervals[w.i].level < w.maxLevel {
+				continue
+			}
+			extrapolate = true
+			w.nrmax = 1
+		}
+		if !errType2 && errLarge > ertest {
+			// The smallest interval has the largest error. Before
+			// bisecting decrease the sum of the errors over the
+			// larger intervals and perform extrapolation.
+			if w.increaseNrmax() {
+				continue
+			}
+		}
+
+		// Perform extrapolation.
+		table.append(area)
+		reseps, abseps := table.extrapolate()
+		ktmin++
+		if ktmin > 5 && errExt < 1e-3*errsum {
+			errType = 5
+		}
+		if abseps < errExt {
+			ktmin = 0
+			errExt = abseps
+			resExt = reseps
+			correc = errLarge
+			ertest = math.Max(absTol, relTol*math.Abs(reseps))
+			if errExt <= ertest {
+				break
+			}
+		}
+
+		// Prepare bisection of the smallest interval.
+		if table.n == 1 {
+			disallowExtrapolate = true
+		}
+		if errType == 5 {
+			break
+		}
+		w.resetNrmax()
+		extrapolate = false
+		errLarge = errsum
+	}
+
+	result, abserr := resExt, errExt
+	switch {
+	case sumResult:
+	case errExt == math.MaxFloat64:
+		sumResult = true
+	default:
+		if errType != 0 || errType2 {
+			if errType2 {
+				abserr += correc
+			}
+			if errType == 0 {
+				errType = 3
+			}
+			if resExt != 0 && area != 0 {
+				if abserr/math.Abs(resExt) > errsum/math.Abs(area) {
+					sumResult = true
+					break
+				}
+			} else if abserr > errsum {
+				sumResult = true
+				break
+			} else if area == 0 {
+				break
+			}
+		}
+		// Test on divergence.
+		if !positive && math.Max(math.Abs(resExt), math.Abs(area)) <= 0.01*resabs0 {
+			break
+		}
+		ratio := resExt / area
+		if ratio < 0.01 || ratio > 100 || errsum > math.Abs(area) {
+			errType = 6
+		}
+	}
+	if sumResult {
+		result = 0
+		for _, iv := range w.intervals {
+			result += iv.value
+		}
+		abserr = errsum
+	}
+
+	res := AdaptiveResult{Value: result, Error: abserr, Intervals: len(w.intervals)}
+	switch errType {
+	case 0:
+		return res, nil
+	case 1:
+		return res, ErrMaxIntervals
+	case 2, 3:
+		return res, ErrRoundoff
+	case 4:
+		return res, ErrBadIntegrand
+	case 5:
+		return res, ErrExtrapolationRoundoff
+	default:
+		return res, ErrDivergent
+	}
+}
+
+// tooSmall returns whether the interval [a1, b2] bisected at a2 is too
+// small to be bisected further.
+func tooSmall(a1, a2, b2 float64) bool {
+	tmp := (1 + 100*eps) * (math.Abs(a2) + 1000*minNormal)
+	return math.Abs(a1) <= tmp && math.Abs(b2) <= tmp
+}
+
+// hasInteriorNodes returns whether all the nodes of gk21
+// over [a, b] lie strictly between a and b.
+func hasInteriorNodes(a, b float64) bool {
+	center := 0.5 * (a + b)
+	x := 0.5 * (b - a) * gk21X[0]
+	return a < center-x && center+x < b
+}
+
+func isFinite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
+
+// interval is a subinterval of an adaptive integration.
+type interval struct {
+	a, b  float64
+	value float64
+	err   float64
+
+	// level is the number of bisections
+	// that produced the interval.
+	level int
+}
+
+// adaptiveWork holds the subintervals of an adaptive integration.
+type adaptiveWork struct {
+	intervals []interval
+
+	// order holds the indices of intervals
+	// sorted by decreasing error estimate.
+	order []int
+
+	// nrmax is the position in order of the interval
+	// to be bisected next, and i is its index.
+	nrmax int
+	i     int
+
+	maxLevel int
+}
+
+// update replaces the interval that was bisected with the
+// two halves l and r.
+func (w *adaptiveWork) update(l, r interval) {
+	bisected := w.i
+	if r.err > l.err {
+		l, r = r, l
+	}
+	w.intervals[bisected] = l
+	w.intervals = append(w.intervals, r)
+	if l.level > w.maxLevel {
+		w.maxLevel = l.level
+	}
+	w.sort(bisected)
+}
+
+// sort orders the intervals by decreasing error estimate and sets the
+// interval to be bisected next. If the last bisected interval moves ahead
+// of the current position, the position is moved back to it.
+func (w *adaptiveWork) sort(bisected int) {
+	w.order = w.order[:0]
+	for i := range w.intervals {
+		w.order = append(w.order, i)
+	}
+	sort.SliceStable(w.order, func(i, j int) bool {
+		return w.intervals[w.order[i]].err > w.intervals[w.order[j]].err
+	})
+	for pos, i := range w.order[:w.nrmax] {
+		if i == bisected {
+			w.nrmax = pos
+			break
+		}
+	}
+	if w.nrmax >= len(w.order) {
+		w.nrmax = len(w.order) - 1
+	}
+	w.i = w.order[w.nrmax]
+}
+
+// increaseNrmax advances the interval to be bisected to the interval with
+// the largest error estimate that has not been bisected to the maximum
+// level, returning whether one was found.
+func (w *adaptiveWork) increaseNrmax() bool {
+	for ; w.nrmax < len(w.order); w.nrmax++ {
+		w.i = w.order[w.nrmax]
+		if w.intervals[w.i].level < w.maxLevel {
+			return true
+		}
+	}
+	w.nrmax = len(w.order) - 1
+	return false
+}
+
+// resetNrmax sets the interval to be bisected to the interval
+// with the largest error estimate.
+func (w *adaptiveWork) resetNrmax() {
+	w.nrmax = 0
+	w.i = w.order[0]
+}
+
+// epsilonTableSize is the maximum number of elements
+// held by an epsilonTable.
+const epsilonTableSize = 50
+
+// epsilonTable holds the state of Wynn's epsilon algorithm for
+// extrapolating the limit of a sequence.
+type epsilonTable struct {
+	n     int
+	list  [epsilonTableSize + 2]float64
+	nres  int
+	last3 [3]float64
+}
+
+func (t *epsilonTable) append(v float64) {
+	t.list[t.n] = v
+	t.n++
+}
+
+// extrapolate returns the extrapolated limit of the sequence in the table
+// and an estimate of its absolute error, and updates the table.
+func (t *epsilonTable) extrapolate() (result, abserr float64) {
+	e := &t.list
+	n := t.n - 1
+	current := e[n]
+	if n < 2 {
+		return current, math.MaxFloat64
+	}
+
+	result = current
+	abserr = math.MaxFloat64
+	newelm := n / 2
+	nOrig := n
+	nFinal := n
+	e[n+2] = e[n]
+	e[n] = math.MaxFloat64
+	for i := 0; i < newelm; i++ {
+		res := e[n-2*i+2]
+		e0 := e[n-2*i-2]
+		e1 := e[n-2*i-1]
+		e2 := res
+
+		delta2 := e2 - e1
+		err2 := math.Abs(delta2)
+		tol2 := math.Max(math.Abs(e2), math.Abs(e1)) * eps
+		delta3 := e1 - e0
+		err3 := math.Abs(delta3)
+		tol3 := math.Max(math.Abs(e1), math.Abs(e0)) * eps
+		if err2 <= tol2 && err3 <= tol3 {
+			// e0, e1 and e2 are equal to within machine
+			// accuracy so convergence is assumed.
+			return res, math.Max(err2+err3, 5*eps*math.Abs(res))
+		}
+
+		e3 := e[n-2*i]
+		e[n-2*i] = e1
+		delta1 := e1 - e3
+		err1 := math.Abs(delta1)
+		tol1 := math.Max(math.Abs(e1), math.Abs(e3)) * eps
+		// If two elements are very close to each other,
+		// omit a part of the table.
+		if err1 <= tol1 || err2 <= tol2 || err3 <= tol3 {
+			nFinal = 2 * i
+			break
+		}
+		ss := (1/delta1 + 1/delta2) - 1/delta3
+		// Detect irregular behavior in the table
+		// and omit a part of the table.
+		if math.Abs(ss*e1) <= 1e-4 {
+			nFinal = 2 * i
+			break
+		}
+		res = e1 + 1/ss
+		e[n-2*i] = res
+		if err := err2 + math.Abs(res-e2) + err3; err <= abserr {
+			abserr = err
+			result = res
+		}
+	}
+
+	// Shift the table.
+	const limexp = epsilonTableSize - 1
+	if nFinal == limexp {
+		nFinal = 2 * (limexp / 2)
+	}
+	if nOrig%2 == 1 {
+		for i := 0; i <= newelm; i++ {
+			e[1+2*i] = e[2*i+3]
+		}
+	} else {
+		for i := 0; i <= newelm; i++ {
+			e[2*i] = e[2*i+2]
+		}
+	}
+	if nOrig != nFinal {
+		for i := 0; i <= nFinal; i++ {
+			e[i] = e[nOrig-nFinal+i]
+		}
+	}
+	t.n = nFinal + 1
+
+	if t.nres < 3 {
+		t.last3[t.nres] = result
+		abserr = math.MaxFloat64
+	} else {
+		abserr = math.Abs(result-t.last3[2]) + math.Abs(result-t.last3[1]) + math.Abs(result-t.last3[0])
+		t.last3[0], t.last3[1], t.last3[2] = t.last3[1], t.last3[2], result
+	}
+	t.nres++
+	return result, math.Max(abserr, 5*eps*math.Abs(result))
+}
+
+// Abscissae and weights of the 21-point Kronrod rule and the embedded
+// 10-point Gauss rule on [-1, 1]. The Gauss abscissae are the odd
+// elements of gk21X. Only the non-negative abscissae are held, with the
+// last element being the center of the interval.
+var (
+	gk21X = [11]float64{
+		0.995657163025808080735527280689003,
+		0.973906528517171720077964012084452,
+		0.930157491355708226001207180059508,
+		0.865063366688984510732096688423493,
+		0.780817726586416897063717578345042,
+		0.679409568299024406234327365114874,
+		0.562757134668604683339000099272694,
+		0.433395394129247190799265943165784,
+		0.294392862701460198131126603103866,
+		0.148874338981631210884826001129720,
+		0,
+	}
+	gk21WK = [11]float64{
+		0.011694638867371874278064396062192,
+		0.032558162307964727478818972459390,
+		0.054755896574351996031381300244580,
+		0.075039674810919952767043140916190,
+		0.093125454583697605535065465083366,
+		0.109387158802297641899210590325805,
+		0.123491976262065851077208745778849,
+		0.134709217311473325928054001771707,
+		0.142775938577060080797094273138717,
+		0.147739104901338491374841515972068,
+		0.149445554002916905664936468389821,
+	}
+	gk21WG = [5]float64{
+		0.066671344308688137593568809893332,
+		0.149451349150580593145776339657697,
+		0.219086362515982043995534934228163,
+		0.269266719309996355091226921569469,
+		0.295524224714752870173892994651338,
+	}
+)
+
+// gk21 returns the 21-point Kronrod estimate of the integral of f over
+// [a, b] and an estimate of its absolute error, along with the integrals
+// of |f| and of |f - mean(f)| over the interval.
+func gk21(f func(float64) float64, a, b float64) (result, abserr, resabs, resasc float64) {
+	center := 0.5 * (a + b)
+	half := 0.5 * (b - a)
+
+	var fv1, fv2 [10]float64
+	fc := f(center)
+	var resg float64
+	resk := fc * gk21WK[10]
+	resabs = math.Abs(resk)
+	for j := 0; j < 10; j++ {
+		x := half * gk21X[j]
+		f1 := f(center - x)
+		f2 := f(center + x)
+		fv1[j], fv2[j] = f1, f2
+		if j%2 == 1 {
+			resg += gk21WG[j/2] * (f1 + f2)
+		}
+		resk += gk21WK[j] * (f1 + f2)
+		resabs += gk21WK[j] * (math.Abs(f1) + math.Abs(f2))
+	}
+	mean := 0.5 * resk
+	resasc = gk21WK[10] * math.Abs(fc-mean)
+	for j := 0; j < 10; j++ {
+		resasc += gk21WK[j] * (math.Abs(fv1[j]-mean) + math.Abs(fv2[j]-mean))
+	}
+
+	result = resk * half
+	resabs *= half
+	resasc *= half
+	abserr = math.Abs((resk - resg) * half)
+	if resasc != 0 && abserr != 0 {
+		abserr = resasc * math.Min(1, math.Pow(200*abserr/resasc, 1.5))
+	}
+	if resabs > minNormal/(50*eps) {
+		abserr = math.Max(abserr, 50*eps*resabs)
+	}
+	return result, abserr, resabs, resasc
+}
@@ -0,0 +1,398 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/integrate/testquad"
+)
+
+func TestGK21(t *testing.T) {
+	t.Parallel()
+	// The Kronrod rule is exact for polynomials of degree 31
+	// and the Gauss rule for polynomials of degree 19.
+	var sumK, sumG float64
+	for i := range gk21X {
+		if i == len(gk21X)-1 {
+			sumK += gk21WK[i]
+		} else {
+			sumK += 2 * gk21WK[i]
+		}
+	}
+	for _, w := range gk21WG {
+		sumG += 2 * w
+	}
+	if math.Abs(sumK-2) > 1e-15 || math.Abs(sumG-2) > 1e-15 {
+		t.Errorf("unexpected weight sums: Kronrod %v, Gauss %v", sumK, sumG)
+	}
+	for _, deg := range []int{0, 1, 2, 7, 19, 30, 31} {
+		f := func(x float64) float64 { return math.Pow(x, float64(deg)) }
+		want := (math.Pow(2, float64(deg+1)) - math.Pow(-1, float64(deg+1))) / float64(deg+1)
+		got, _, _, _ := gk21(f, -1, 2)
+		if math.Abs(got-want) > 1e-13*math.Max(1, math.Abs(want)) {
+			t.Errorf("unexpected result for degree %d: got:%v want:%v", deg, got, want)
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name        string
+		f           func(float64) float64
+		min, max    float64
+		breakpoints []float64
+		want        float64
+	}{
+		{
+			name: testquad.Sin().Name,
+			f:    testquad.Sin().F,
+			min:  testquad.Sin().A,
+			max:  testquad.Sin().B,
+			want: testquad.Sin().Value,
+		},
+		{
+			name: testquad.XExpMinusX().Name,
+			f:    testquad.XExpMinusX().F,
+			min:  testquad.XExpMinusX().A,
+			max:  testquad.XExpMinusX().B,
+			want: testquad.XExpMinusX().Value,
+		},
+		{
+			name: testquad.Sqrt().Name,
+			f:    testquad.Sqrt().F,
+			min:  testquad.Sqrt().A,
+			max:  testquad.Sqrt().B,
+			want: testquad.Sqrt().Value,
+		},
+		{
+			name: testquad.ExpOverX2Plus1().Name,
+			f:    testquad.ExpOverX2Plus1().F,
+			min:  testquad.ExpOverX2Plus1().A,
+			max:  testquad.ExpOverX2Plus1().B,
+			want: testquad.ExpOverX2Plus1().Value,
+		},
+		{
+			name: testquad.Poly(12).Name,
+			f:    testquad.Poly(12).F,
+			min:  testquad.Poly(12).A,
+			max:  testquad.Poly(12).B,
+			want: testquad.Poly(12).Value,
+		},
+		{
+			name: "∫_0^1 1/sqrt(x)dx",
+			f:    func(x float64) float64 { return 1 / math.Sqrt(x) },
+			min:  0,
+			max:  1,
+			want: 2,
+		},
+		{
+			name: "∫_0^1 log(x)/sqrt(x)dx",
+			f:    func(x float64) float64 { return math.Log(x) / math.Sqrt(x) },
+			min:  0,
+			max:  1,
+			want: -4,
+		},
+		{
+			name: "∫_0^1 x^-0.9dx",
+			f:    func(x float64) float64 { return math.Pow(x, -0.9) },
+			min:  0,
+			max:  1,
+			want: 10,
+		},
+		{
+			name: "∫_-1^1 1/sqrt(1-x²)dx",
+			f:    func(x float64) float64 { return 1 / math.Sqrt(1-x*x) },
+			min:  -1,
+			max:  1,
+			want: math.Pi,
+		},
+		{
+			name:        "∫_0^3 x³log|(x²-1)(x²-2)|dx",
+			f:           func(x float64) float64 { return x * x * x * math.Log(math.Abs((x*x-1)*(x*x-2))) },
+			min:         0,
+			max:         3,
+			breakpoints: []float64{1, math.Sqrt2},
+			want:        61*math.Log(2) + 77*math.Log(7)/4 - 27,
+		},
+		{
+			name:        "∫_-1^2 |x|^-0.5dx",
+			f:           func(x float64) float64 { return 1 / math.Sqrt(math.Abs(x)) },
+			min:         -1,
+			max:         2,
+			breakpoints: []float64{0},
+			want:        2 + 2*math.Sqrt2,
+		},
+		{
+			name:        "∫_0^1 log|x-0.5|dx",
+			f:           func(x float64) float64 { return math.Log(math.Abs(x - 0.5)) },
+			min:         0,
+			max:         1,
+			breakpoints: []float64{0.5},
+			want:        -math.Ln2 - 1,
+		},
+		{
+			name: "∫_-∞^∞ exp(-x²)dx",
+			f:    func(x float64) float64 { return math.Exp(-x * x) },
+			min:  math.Inf(-1),
+			max:  math.Inf(1),
+			want: math.Sqrt(math.Pi),
+		},
+		{
+			name: "∫_0^∞ log(x)/(1+100x²)dx",
+			f:    func(x float64) float64 { return math.Log(x) / (1 + 100*x*x) },
+			min:  0,
+			max:  math.Inf(1),
+			want: -math.Pi * math.Log(10) / 20,
+		},
+		{
+			name: "∫_1^∞ 1/x²dx",
+			f:    func(x float64) float64 { return 1 / (x * x) },
+			min:  1,
+			max:  math.Inf(1),
+			want: 1,
+		},
+		{
+			name: "∫_-∞^0 exp(x)dx",
+			f:    math.Exp,
+			min:  math.Inf(-1),
+			max:  0,
+			want: 1,
+		},
+		{
+			name:        "∫_-∞^∞ exp(-|x-1|)dx",
+			f:           func(x float64) float64 { return math.Exp(-math.Abs(x - 1)) },
+			min:         math.Inf(-1),
+			max:         math.Inf(1),
+			breakpoints: []float64{1},
+			want:        2,
+		},
+		{
+			name: "∫_2^2 xdx",
+			f:    func(x float64) float64 { return x },
+			min:  2,
+			max:  2,
+			want: 0,
+		},
+	} {
+		for _, tol := range []float64{1e-6, 1e-10} {
+			settings := &AdaptiveSettings{
+				AbsTol:      tol,
+				RelTol:      tol,
+				Breakpoints: test.breakpoints,
+			}
+			var evals int
+			f := func(x float64) float64 {
+				if x == test.min || x == test.max {
+					t.Errorf("%s: integrand evaluated at end point", test.name)
+				}
+				for _, p := range test.breakpoints {
+					if x == p {
+						t.Errorf("%s: integrand evaluated at breakpoint", test.name)
+					}
+				}
+				evals++
+				return test.f(x)
+			}
+			got, err := Adaptive(f, test.min, test.max, settings)
+			if err != nil {
+				t.Errorf("%s: unexpected error for tol=%v: %v", test.name, tol, err)
+				continue
+			}
+			bound := math.Max(tol, tol*math.Abs(test.want))
+			if math.Abs(got.Value-test.want) > bound {
+				t.Errorf("%s: unexpected value for tol=%v: got:%v want:%v", test.name, tol, got.Value, test.want)
+			}
+			if got.Error > bound {
+				t.Errorf("%s: error estimate exceeds tolerance for tol=%v: %v", test.name, tol, got.Error)
+			}
+			if math.Abs(got.Value-test.want) > 10*got.Error+1e-15 {
+				t.Errorf("%s: error estimate too small for tol=%v: got:%v actual:%v", test.name, tol, got.Error, math.Abs(got.Value-test.want))
+			}
+			if got.Evaluations != evals {
+				t.Errorf("%s: unexpected number of evaluations: got:%d want:%d", test.name, got.Evaluations, evals)
+			}
+		}
+	}
+}
+
+func TestAdaptiveInteriorSingularity(t *testing.T) {
+	t.Parallel()
+	// The spacing of floating point numbers away from zero limits
+	// how closely a singularity at a breakpoint can be approached,
+	// so tight tolerances may not be achievable. In that case the
+	// integration must fail with a finite estimate rather than
+	// evaluate the integrand at the breakpoint.
+	for _, p := range []struct{ min, bp, max float64 }{
+		{min: 0, bp: 1, max: 2},
+		{min: 0, bp: 1.5, max: 3},
+		{min: 0, bp: 0.5, max: 1},
+	} {
+		want := 2*math.Sqrt(p.bp-p.min) + 2*math.Sqrt(p.max-p.bp)
+		for _, tol := range []float64{1e-6, 1e-8, 1e-10} {
+			f := func(x float64) float64 {
+				if x == p.min || x == p.bp || x == p.max {
+					t.Errorf("integrand evaluated at %v for breakpoint %v", x, p.bp)
+				}
+				return 1 / math.Sqrt(math.Abs(x-p.bp))
+			}
+			settings := &AdaptiveSettings{AbsTol: tol, RelTol: tol, Breakpoints: []float64{p.bp}}
+			got, err := Adaptive(f, p.min, p.max, settings)
+			switch {
+			case err == nil:
+				bound := math.Max(tol, tol*want)
+				if math.Abs(got.Value-want) > bound {
+					t.Errorf("unexpected value for breakpoint %v and tol=%v: got:%v want:%v", p.bp, tol, got.Value, want)
+				}
+			case err == ErrBadIntegrand && tol < 1e-6:
+				if math.IsInf(got.Value, 0) || math.IsNaN(got.Value) {
+					t.Errorf("unexpected non-finite value for breakpoint %v and tol=%v", p.bp, tol)
+				}
+				if math.Abs(got.Value-want) > 1e-6 {
+					t.Errorf("unexpected value for breakpoint %v and tol=%v: got:%v want:%v", p.bp, tol, got.Value, want)
+				}
+			default:
+				t.Errorf("unexpected error for breakpoint %v and tol=%v: %v", p.bp, tol, err)
+			}
+		}
+	}
+}
+
+func TestAdaptiveDefaultSettings(t *testing.T) {
+	t.Parallel()
+	got, err := Adaptive(math.Cos, 0, math.Pi/2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got.Value-1) > defaultRelTol {
+		t.Errorf("unexpected value: got:%v want:1", got.Value)
+	}
+	if got.Intervals != 1 || got.Evaluations != 21 {
+		t.Errorf("unexpected work for smooth integrand: intervals=%d evaluations=%d", got.Intervals, got.Evaluations)
+	}
+
+	got, err = Adaptive(math.Cos, 0, math.Pi, &AdaptiveSettings{Breakpoints: []float64{math.Pi / 2}})
+	if err != nil {
+		t.Fatalf("unexpected error with zero tolerances: %v", err)
+	}
+	if math.Abs(got.Value) > defaultAbsTol {
+		t.Errorf("unexpected value with zero tolerances: got:%v want:0", got.Value)
+	}
+}
+
+func TestAdaptiveErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		f        func(float64) float64
+		min, max float64
+		settings *AdaptiveSettings
+		want     []error
+	}{
+		{
+			name:     "max intervals",
+			f:        func(x float64) float64 { return math.Sin(1 / x) },
+			min:      1e-3,
+			max:      1,
+			settings: &AdaptiveSettings{AbsTol: 1e-12, MaxIntervals: 3},
+			want:     []error{ErrMaxIntervals},
+		},
+		{
+			name:     "non-integrable",
+			f:        func(x float64) float64 { return 1 / x },
+			min:      0,
+			max:      1,
+			settings: &AdaptiveSettings{RelTol: 1e-10},
+			want:     []error{ErrDivergent, ErrRoundoff, ErrBadIntegrand, ErrExtrapolationRoundoff, ErrMaxIntervals},
+		},
+		{
+			name:     "non-integrable at breakpoint",
+			f:        func(x float64) float64 { return 1 / math.Abs(x-1) },
+			min:      0,
+			max:      2,
+			settings: &AdaptiveSettings{AbsTol: 1e-8, RelTol: 1e-8, Breakpoints: []float64{1}},
+			want:     []error{ErrDivergent, ErrRoundoff, ErrBadIntegrand, ErrExtrapolationRoundoff, ErrMaxIntervals},
+		},
+		{
+			name:     "infinite integrand",
+			f:        func(x float64) float64 { return math.Inf(1) },
+			min:      0,
+			max:      1,
+			settings: &AdaptiveSettings{AbsTol: 1e-8},
+			want:     []error{ErrBadIntegrand},
+		},
+	} {
+		got, err := Adaptive(test.f, test.min, test.max, test.settings)
+		var ok bool
+		for _, want := range test.want {
+			if err == want {
+				ok = true
+			}
+		}
+		if !ok {
+			t.Errorf("%s: unexpected error: got:%v want one of:%v", test.name, err, test.want)
+		}
+		if math.IsNaN(got.Value) && err != ErrBadIntegrand {
+			t.Errorf("%s: unexpected NaN result", test.name)
+		}
+		limit := test.settings.MaxIntervals
+		if limit == 0 {
+			limit = defaultMaxIntervals
+		}
+		if got.Intervals > limit {
+			t.Errorf("%s: too many intervals: got:%d max:%d", test.name, got.Intervals, limit)
+		}
+		if err == ErrMaxIntervals && got.Intervals != limit {
+			t.Errorf("%s: interval limit not used: got:%d want:%d", test.name, got.Intervals, limit)
+		}
+	}
+}
+
+func TestAdaptivePanics(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return x }
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{
+			name: "min > max",
+			fn:   func() { Adaptive(f, 1, 0, nil) },
+		},
+		{
+			name: "negative tolerance",
+			fn:   func() { Adaptive(f, 0, 1, &AdaptiveSettings{AbsTol: -1, RelTol: 1e-6}) },
+		},
+		{
+			name: "tolerance too small",
+			fn:   func() { Adaptive(f, 0, 1, &AdaptiveSettings{RelTol: eps}) },
+		},
+		{
+			name: "negative max intervals",
+			fn:   func() { Adaptive(f, 0, 1, &AdaptiveSettings{AbsTol: 1e-6, MaxIntervals: -1}) },
+		},
+		{
+			name: "breakpoint at end",
+			fn:   func() { Adaptive(f, 0, 1, &AdaptiveSettings{AbsTol: 1e-6, Breakpoints: []float64{1}}) },
+		},
+		{
+			name: "too many breakpoints",
+			fn: func() {
+				Adaptive(f, 0, 1, &AdaptiveSettings{AbsTol: 1e-6, MaxIntervals: 2, Breakpoints: []float64{0.25, 0.5}})
+			},
+		},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected panic", test.name)
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
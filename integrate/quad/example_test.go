@@ -45,3 +45,28 @@ func Example() {
 	// Estimate using parallel evaluations of f.
 	// EV = 4.19064
 }
+
+func ExampleAdaptive() {
+	fmt.Println("Integrate log(x)/sqrt(x) over [0, 1], which is singular at 0")
+	f := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+	res, err := quad.Adaptive(f, 0, 1, &quad.AdaptiveSettings{AbsTol: 1e-10, RelTol: 1e-10})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("integral = %.10f\n", res.Value)
+
+	fmt.Println("Integrate exp(-x²) over the real line")
+	res, err = quad.Adaptive(func(x float64) float64 { return math.Exp(-x * x) }, math.Inf(-1), math.Inf(1), nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("integral = %.8f, sqrt(π) = %.8f\n", res.Value, math.Sqrt(math.Pi))
+
+	// Output:
+	// Integrate log(x)/sqrt(x) over [0, 1], which is singular at 0
+	// integral = -4.0000000000
+	// Integrate exp(-x²) over the real line
+	// integral = 1.77245385, sqrt(π) = 1.77245385
+}
@@ -236,6 +236,30 @@ func Geqrf(a blas64.General, tau, work []float64, lwork int) {
 	lapack64.Dgeqrf(a.Rows, a.Cols, a.Data, max(1, a.Stride), tau, work, lwork)
 }
 
+// Geqp3 computes a QR factorization with column pivoting of the m×n matrix A,
+//  A*P = Q*R.
+// On return, the upper triangle of a contains R, and the elements below the
+// diagonal together with tau represent Q as a product of elementary reflectors
+// as described for Geqrf. The diagonal elements of R are non-increasing in
+// magnitude.
+//
+// jpvt specifies columns of A to be moved to the front of A*P before the
+// remaining columns are pivoted. If jpvt[j] is at least zero, the jth column
+// of A is a leading column, and if jpvt[j] is -1 it is a free column. On
+// return, jpvt holds the permutation that was applied; the jth column of A*P
+// was the jpvt[j] column of A. jpvt must have length n, and tau must have
+// length min(m,n).
+//
+// work must have length at least max(1,lwork), and lwork must be at least
+// 3*n+1. If lwork == -1, instead of performing Geqp3, only the optimal value
+// of lwork will be stored in work[0].
+//
+// Dgeqp3 is not part of the lapack.Float64 interface and so calls to Geqp3 are
+// always executed by the Gonum implementation.
+func Geqp3(a blas64.General, jpvt []int, tau, work []float64, lwork int) {
+	gonum.Implementation{}.Dgeqp3(a.Rows, a.Cols, a.Data, max(1, a.Stride), jpvt, tau, work, lwork)
+}
+
 // Gelqf computes the LQ factorization of the m×n matrix A using a blocked
 // algorithm. A is modified to contain the information to construct L and Q. The
 // lower triangle of a contains the matrix L. The elements above the diagonal
@@ -476,6 +500,25 @@ func Gtsv(trans blas.Transpose, a Tridiagonal, b blas64.General) (ok bool) {
 	return gonum.Implementation{}.Dgtsv(a.N, b.Cols, a.DL, a.D, a.DU, b.Data, max(1, b.Stride))
 }
 
+// Lacn2 estimates the 1-norm of an n×n matrix A using reverse communication,
+// where n is the length of x. The products with A are provided by the caller.
+//
+// On the first call kase must be 0. On each return with kase != 0, x must be
+// overwritten by
+//  A * x   if kase == 1,
+//  Aᵀ * x  if kase == 2,
+// and Lacn2 called again with the returned est and kase and all other
+// arguments unchanged. When kase is returned as 0, est is a lower bound on
+// the 1-norm of A.
+//
+// v and isgn must have the same length as x.
+//
+// Dlacn2 is not part of the lapack.Float64 interface and so calls to Lacn2 are
+// always executed by the Gonum implementation.
+func Lacn2(v, x []float64, isgn []int, est float64, kase int, isave *[3]int) (float64, int) {
+	return gonum.Implementation{}.Dlacn2(len(x), v, x, isgn, est, kase, isave)
+}
+
 // Lagtm performs one of the matrix-matrix operations
 //  C = alpha * A * B + beta * C   if trans == blas.NoTrans
 //  C = alpha * Aᵀ * B + beta * C  if trans == blas.Trans or blas.ConjTrans
@@ -646,6 +689,29 @@ func Pocon(a blas64.Symmetric, anorm float64, work []float64, iwork []int) float
 	return lapack64.Dpocon(a.Uplo, a.N, a.Data, max(1, a.Stride), anorm, work, iwork)
 }
 
+// Steqr computes the eigenvalues and optionally the eigenvectors of an n×n
+// symmetric tridiagonal matrix using the implicit QL or QR method.
+//
+// d contains the n diagonal elements of the tridiagonal matrix on entry and
+// the eigenvalues in ascending order on return. e contains the n-1
+// off-diagonal elements and is overwritten.
+//
+// If compz == lapack.EVTridiag, z is set to the orthonormal eigenvectors of
+// the tridiagonal matrix. If compz == lapack.EVOrig, z must contain on entry
+// the orthogonal matrix used to reduce the original matrix to tridiagonal
+// form and on return holds the eigenvectors of the original matrix. z is not
+// referenced if compz == lapack.EVCompNone.
+//
+// work must have length at least max(1, 2*n-2) if eigenvectors are computed.
+//
+// Steqr returns whether all eigenvalues were found.
+//
+// Dsteqr is not part of the lapack.Float64 interface and so calls to Steqr are
+// always executed by the Gonum implementation.
+func Steqr(compz lapack.EVComp, d, e []float64, z blas64.General, work []float64) (ok bool) {
+	return gonum.Implementation{}.Dsteqr(compz, len(d), d, e, z.Data, max(1, z.Stride), work)
+}
+
 // Syev computes all eigenvalues and, optionally, the eigenvectors of a real
 // symmetric matrix A.
 //
@@ -762,3 +828,91 @@ func Geev(jobvl lapack.LeftEVJob, jobvr lapack.RightEVJob, a blas64.General, wr,
 	}
 	return lapack64.Dgeev(jobvl, jobvr, n, a.Data, max(1, a.Stride), wr, wi, vl.Data, max(1, vl.Stride), vr.Data, max(1, vr.Stride), work, lwork)
 }
+
+// Gehrd reduces a block of a real n×n general matrix A to upper Hessenberg form
+// H by an orthogonal similarity transformation Qᵀ * A * Q = H.
+//
+// The matrix Q is represented as a product of (ihi-ilo) elementary reflectors
+// stored below the first subdiagonal of a on return, with their scalar factors
+// stored in tau. tau must have length n-1.
+//
+// ilo and ihi determine the block of A that will be reduced to upper Hessenberg
+// form. They are typically set to 0 and n-1, respectively.
+//
+// work must have length at least lwork and lwork must be at least max(1,n). If
+// lwork == -1, instead of performing Gehrd, only the optimal value of lwork
+// will be stored in work[0].
+//
+// Dgehrd is not part of the lapack.Float64 interface and so calls to Gehrd are
+// always executed by the Gonum implementation.
+func Gehrd(a blas64.General, ilo, ihi int, tau, work []float64, lwork int) {
+	if a.Rows != a.Cols {
+		panic("lapack64: matrix not square")
+	}
+	gonum.Implementation{}.Dgehrd(a.Rows, ilo, ihi, a.Data, max(1, a.Stride), tau, work, lwork)
+}
+
+// Orghr generates the n×n orthogonal matrix Q defined by the elementary
+// reflectors returned by Gehrd, overwriting a with Q. ilo, ihi and tau must
+// have the same values as in the previous call of Gehrd.
+//
+// work must have length at least lwork and lwork must be at least ihi-ilo. If
+// lwork == -1, instead of performing Orghr, only the optimal value of lwork
+// will be stored in work[0].
+//
+// Dorghr is not part of the lapack.Float64 interface and so calls to Orghr are
+// always executed by the Gonum implementation.
+func Orghr(a blas64.General, ilo, ihi int, tau, work []float64, lwork int) {
+	if a.Rows != a.Cols {
+		panic("lapack64: matrix not square")
+	}
+	gonum.Implementation{}.Dorghr(a.Rows, ilo, ihi, a.Data, max(1, a.Stride), tau, work, lwork)
+}
+
+// Hseqr computes the eigenvalues of an n×n Hessenberg matrix H and,
+// optionally, the matrices T and Z from the Schur decomposition
+//  H = Z T Zᵀ,
+// where T is an n×n upper quasi-triangular matrix (the Schur form), and Z is
+// the n×n orthogonal matrix of Schur vectors. If compz is lapack.SchurOrig,
+// z must hold on entry the orthogonal matrix Q that reduced a matrix A to
+// H, and will hold Q*Z on return.
+//
+// wr and wi must have length n and hold on return the real and imaginary
+// parts of the eigenvalues.
+//
+// work must have length at least lwork and lwork must be at least max(1,n). If
+// lwork == -1, instead of performing Hseqr, only the optimal value of lwork
+// will be stored in work[0].
+//
+// unconverged is zero if all the eigenvalues have been computed, otherwise
+// Hseqr failed and only some of the eigenvalues have converged.
+//
+// Dhseqr is not part of the lapack.Float64 interface and so calls to Hseqr are
+// always executed by the Gonum implementation.
+func Hseqr(job lapack.SchurJob, compz lapack.SchurComp, h blas64.General, ilo, ihi int, wr, wi []float64, z blas64.General, work []float64, lwork int) (unconverged int) {
+	if h.Rows != h.Cols {
+		panic("lapack64: matrix not square")
+	}
+	return gonum.Implementation{}.Dhseqr(job, compz, h.Rows, ilo, ihi, h.Data, max(1, h.Stride), wr, wi, z.Data, max(1, z.Stride), work, lwork)
+}
+
+// Trexc reorders the real Schur factorization of an n×n real matrix
+//  A = Q*T*Qᵀ
+// so that the diagonal block of T with row index ifst is moved to row ilst.
+// T must be in Schur canonical form. If compq is lapack.UpdateSchur, the
+// matrix Q of Schur vectors is updated.
+//
+// ifstOut points to the first row of the moved block before the move and
+// ilstOut to its first row in its final position. If ok is false, two adjacent
+// blocks were too close to swap and T may have been partially reordered.
+//
+// work must have length at least n.
+//
+// Dtrexc is not part of the lapack.Float64 interface and so calls to Trexc are
+// always executed by the Gonum implementation.
+func Trexc(compq lapack.UpdateSchurComp, t, q blas64.General, ifst, ilst int, work []float64) (ifstOut, ilstOut int, ok bool) {
+	if t.Rows != t.Cols {
+		panic("lapack64: matrix not square")
+	}
+	return gonum.Implementation{}.Dtrexc(compq, t.Rows, t.Data, max(1, t.Stride), q.Data, max(1, q.Stride), ifst, ilst, work)
+}
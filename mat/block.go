@@ -0,0 +1,331 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+var (
+	blockDense *BlockDense
+
+	_ Matrix  = blockDense
+	_ Reseter = blockDense
+)
+
+// BlockDense represents a matrix partitioned into a grid of sub-matrix
+// blocks. Block (i, j) has rowSizes[i] rows and colSizes[j] columns.
+// A nil block represents a block of zeros.
+type BlockDense struct {
+	// rowOff and colOff hold the cumulative offsets of the
+	// block rows and columns, starting with zero.
+	rowOff, colOff []int
+
+	// blocks holds the blocks in row-major order.
+	blocks []Matrix
+}
+
+// NewBlockDense creates a new BlockDense with the block partition specified
+// by rowSizes and colSizes. All blocks are initially nil, representing zero
+// blocks. NewBlockDense will panic if either partition is empty or has a
+// non-positive block size.
+func NewBlockDense(rowSizes, colSizes []int) *BlockDense {
+	var m BlockDense
+	m.reuseAs(rowSizes, colSizes)
+	return &m
+}
+
+// BlockDenseOf returns a BlockDense partitioning a into blocks with the
+// specified row and column sizes. If a is a *Dense, the blocks are views
+// sharing a's backing data, otherwise the blocks are copies. BlockDenseOf
+// will panic if the partition sizes do not sum to the dimensions of a.
+func BlockDenseOf(a Matrix, rowSizes, colSizes []int) *BlockDense {
+	m := NewBlockDense(rowSizes, colSizes)
+	r, c := a.Dims()
+	if r != m.rowOff[len(m.rowOff)-1] || c != m.colOff[len(m.colOff)-1] {
+		panic(ErrShape)
+	}
+	ad, isDense := a.(*Dense)
+	nc := len(colSizes)
+	for i := range rowSizes {
+		for j := range colSizes {
+			i0, i1 := m.rowOff[i], m.rowOff[i+1]
+			j0, j1 := m.colOff[j], m.colOff[j+1]
+			if isDense {
+				m.blocks[i*nc+j] = ad.Slice(i0, i1, j0, j1)
+				continue
+			}
+			b := NewDense(i1-i0, j1-j0, nil)
+			for bi := 0; bi < i1-i0; bi++ {
+				for bj := 0; bj < j1-j0; bj++ {
+					b.set(bi, bj, a.At(i0+bi, j0+bj))
+				}
+			}
+			m.blocks[i*nc+j] = b
+		}
+	}
+	return m
+}
+
+// reuseAs sets the partition of an empty receiver, or checks that the
+// partition of a non-empty receiver matches the given sizes.
+func (m *BlockDense) reuseAs(rowSizes, colSizes []int) {
+	if len(rowSizes) == 0 || len(colSizes) == 0 {
+		panic(ErrZeroLength)
+	}
+	if !m.IsEmpty() {
+		if !sameSizes(m.rowOff, rowSizes) || !sameSizes(m.colOff, colSizes) {
+			panic(ErrShape)
+		}
+		return
+	}
+	m.rowOff = offsetsOf(rowSizes)
+	m.colOff = offsetsOf(colSizes)
+	m.blocks = make([]Matrix, len(rowSizes)*len(colSizes))
+}
+
+// offsetsOf returns the cumulative offsets of the given block sizes.
+func offsetsOf(sizes []int) []int {
+	off := make([]int, len(sizes)+1)
+	for i, s := range sizes {
+		if s <= 0 {
+			if s == 0 {
+				panic(ErrZeroLength)
+			}
+			panic(ErrNegativeDimension)
+		}
+		off[i+1] = off[i] + s
+	}
+	return off
+}
+
+// sameSizes returns whether the block sizes described by the offsets off
+// are equal to sizes.
+func sameSizes(off, sizes []int) bool {
+	if len(off) != len(sizes)+1 {
+		return false
+	}
+	for i, s := range sizes {
+		if off[i+1]-off[i] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// sizesOf returns the block sizes described by the offsets off.
+func sizesOf(off []int) []int {
+	sizes := make([]int, len(off)-1)
+	for i := range sizes {
+		sizes[i] = off[i+1] - off[i]
+	}
+	return sizes
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *BlockDense) Dims() (r, c int) {
+	if m.IsEmpty() {
+		return 0, 0
+	}
+	return m.rowOff[len(m.rowOff)-1], m.colOff[len(m.colOff)-1]
+}
+
+// BlockDims returns the number of block rows and block columns in the
+// partition of the matrix.
+func (m *BlockDense) BlockDims() (r, c int) {
+	if m.IsEmpty() {
+		return 0, 0
+	}
+	return len(m.rowOff) - 1, len(m.colOff) - 1
+}
+
+// BlockSizes returns the row sizes and column sizes of the partition of
+// the matrix.
+func (m *BlockDense) BlockSizes() (rowSizes, colSizes []int) {
+	if m.IsEmpty() {
+		return nil, nil
+	}
+	return sizesOf(m.rowOff), sizesOf(m.colOff)
+}
+
+// At returns the element at row i, column j.
+func (m *BlockDense) At(i, j int) float64 {
+	r, c := m.Dims()
+	if uint(i) >= uint(r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(c) {
+		panic(ErrColAccess)
+	}
+	bi := sort.SearchInts(m.rowOff, i+1) - 1
+	bj := sort.SearchInts(m.colOff, j+1) - 1
+	b := m.blocks[bi*(len(m.colOff)-1)+bj]
+	if b == nil {
+		return 0
+	}
+	return b.At(i-m.rowOff[bi], j-m.colOff[bj])
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *BlockDense) T() Matrix {
+	return Transpose{m}
+}
+
+// Block returns the block at block row i and block column j. A nil
+// return value represents a block of zeros.
+func (m *BlockDense) Block(i, j int) Matrix {
+	br, bc := m.BlockDims()
+	if uint(i) >= uint(br) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(bc) {
+		panic(ErrColAccess)
+	}
+	return m.blocks[i*bc+j]
+}
+
+// SetBlock sets the block at block row i and block column j to a. The
+// block is held by reference. A nil a sets the block to zero. SetBlock
+// will panic if the dimensions of a do not match the partition.
+func (m *BlockDense) SetBlock(i, j int, a Matrix) {
+	br, bc := m.BlockDims()
+	if uint(i) >= uint(br) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(bc) {
+		panic(ErrColAccess)
+	}
+	if a != nil {
+		r, c := a.Dims()
+		if r != m.rowOff[i+1]-m.rowOff[i] || c != m.colOff[j+1]-m.colOff[j] {
+			panic(ErrShape)
+		}
+	}
+	m.blocks[i*bc+j] = a
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *BlockDense) IsEmpty() bool {
+	return len(m.blocks) == 0
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (m *BlockDense) Reset() {
+	m.rowOff = m.rowOff[:0]
+	m.colOff = m.colOff[:0]
+	m.blocks = m.blocks[:0]
+}
+
+// Assemble copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise Assemble will
+// panic if the dimensions do not match.
+func (m *BlockDense) Assemble(dst *Dense) {
+	r, c := m.Dims()
+	if r == 0 || c == 0 {
+		panic(ErrZeroLength)
+	}
+	dst.reuseAsNonZeroed(r, c)
+	br, bc := m.BlockDims()
+	for i := 0; i < br; i++ {
+		for j := 0; j < bc; j++ {
+			v := dst.Slice(m.rowOff[i], m.rowOff[i+1], m.colOff[j], m.colOff[j+1]).(*Dense)
+			b := m.blocks[i*bc+j]
+			if b == nil {
+				v.Zero()
+				continue
+			}
+			v.Copy(b)
+		}
+	}
+}
+
+// Mul takes the block matrix product of a and b, placing the result in the
+// receiver. The block column partition of a must match the block row
+// partition of b, otherwise Mul will panic. The result has the block rows
+// of a and the block columns of b, and each non-zero block of the result
+// is a newly allocated *Dense.
+//
+// Zero blocks in a and b are skipped, so that products of block-sparse
+// matrices avoid unnecessary work.
+func (m *BlockDense) Mul(a, b *BlockDense) {
+	if a.IsEmpty() || b.IsEmpty() {
+		panic(ErrZeroLength)
+	}
+	if len(a.colOff) != len(b.rowOff) {
+		panic(ErrShape)
+	}
+	for i, v := range a.colOff {
+		if b.rowOff[i] != v {
+			panic(ErrShape)
+		}
+	}
+
+	ar, ac := a.BlockDims()
+	_, bc := b.BlockDims()
+	blocks := make([]Matrix, ar*bc)
+	var tmp Dense
+	for i := 0; i < ar; i++ {
+		for j := 0; j < bc; j++ {
+			var acc *Dense
+			for k := 0; k < ac; k++ {
+				ab := a.blocks[i*ac+k]
+				bb := b.blocks[k*bc+j]
+				if ab == nil || bb == nil {
+					continue
+				}
+				if acc == nil {
+					acc = &Dense{}
+					acc.Mul(ab, bb)
+					continue
+				}
+				tmp.Reset()
+				tmp.Mul(ab, bb)
+				acc.Add(acc, &tmp)
+			}
+			if acc != nil {
+				blocks[i*bc+j] = acc
+			}
+		}
+	}
+
+	rowSizes, colSizes := sizesOf(a.rowOff), sizesOf(b.colOff)
+	m.reuseAs(rowSizes, colSizes)
+	copy(m.blocks, blocks)
+}
+
+// Add adds a and b block-wise, placing the result in the receiver. The
+// partitions of a and b must match, otherwise Add will panic. Each non-zero
+// block of the result is a newly allocated *Dense.
+func (m *BlockDense) Add(a, b *BlockDense) {
+	if a.IsEmpty() || b.IsEmpty() {
+		panic(ErrZeroLength)
+	}
+	rowSizes, colSizes := a.BlockSizes()
+	if !sameSizes(b.rowOff, rowSizes) || !sameSizes(b.colOff, colSizes) {
+		panic(ErrShape)
+	}
+
+	blocks := make([]Matrix, len(a.blocks))
+	for i, ab := range a.blocks {
+		bb := b.blocks[i]
+		switch {
+		case ab == nil && bb == nil:
+		case ab == nil:
+			blocks[i] = DenseCopyOf(bb)
+		case bb == nil:
+			blocks[i] = DenseCopyOf(ab)
+		default:
+			var d Dense
+			d.Add(ab, bb)
+			blocks[i] = &d
+		}
+	}
+
+	m.reuseAs(rowSizes, colSizes)
+	copy(m.blocks, blocks)
+}
@@ -0,0 +1,104 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestBlockDenseOf(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := randomDense(7, 5, rnd)
+	rowSizes, colSizes := []int{2, 4, 1}, []int{3, 2}
+
+	for _, src := range []Matrix{a, (*basicMatrix)(a)} {
+		b := BlockDenseOf(src, rowSizes, colSizes)
+		if !Equal(b, a) {
+			t.Errorf("unexpected BlockDense for %T:\ngot:\n%v\nwant:\n%v", src, Formatted(b), Formatted(a))
+		}
+		var got Dense
+		b.Assemble(&got)
+		if !Equal(&got, a) {
+			t.Errorf("unexpected assembled matrix for %T:\ngot:\n%v\nwant:\n%v", src, Formatted(&got), Formatted(a))
+		}
+		if !Equal(b.T(), a.T()) {
+			t.Errorf("unexpected transpose for %T", src)
+		}
+	}
+
+	b := BlockDenseOf(a, rowSizes, colSizes)
+	b.Block(1, 1).(*Dense).Set(0, 0, 100)
+	if a.At(2, 3) != 100 {
+		t.Errorf("block of Dense does not share backing data")
+	}
+}
+
+func TestBlockDenseMulAdd(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// Build a saddle-point style system with a zero block.
+	//  [ A  Bᵀ ]
+	//  [ B  0  ]
+	a := randomDense(4, 4, rnd)
+	bb := randomDense(2, 4, rnd)
+	k := NewBlockDense([]int{4, 2}, []int{4, 2})
+	k.SetBlock(0, 0, a)
+	k.SetBlock(0, 1, bb.T())
+	k.SetBlock(1, 0, bb)
+	if k.Block(1, 1) != nil {
+		t.Errorf("unexpected non-nil zero block")
+	}
+
+	x := BlockDenseOf(randomDense(6, 3, rnd), []int{4, 2}, []int{1, 2})
+
+	var got BlockDense
+	got.Mul(k, x)
+	var want Dense
+	want.Mul(k, x)
+	if !EqualApprox(&got, &want, 1e-14) {
+		t.Errorf("unexpected Mul result:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+	}
+	if r, c := got.BlockDims(); r != 2 || c != 2 {
+		t.Errorf("unexpected block dims: got:%d×%d want:2×2", r, c)
+	}
+
+	var sum BlockDense
+	sum.Add(k, k)
+	want.Reset()
+	want.Add(k, k)
+	if !EqualApprox(&sum, &want, 1e-14) {
+		t.Errorf("unexpected Add result:\ngot:\n%v\nwant:\n%v", Formatted(&sum), Formatted(&want))
+	}
+	if sum.Block(1, 1) != nil {
+		t.Errorf("unexpected non-nil zero block after Add")
+	}
+
+	// Check aliased receiver.
+	want.Reset()
+	want.Mul(k, k)
+	k.Mul(k, k)
+	if !EqualApprox(k, &want, 1e-14) {
+		t.Errorf("unexpected aliased Mul result:\ngot:\n%v\nwant:\n%v", Formatted(k), Formatted(&want))
+	}
+}
+
+func TestBlockDensePanics(t *testing.T) {
+	t.Parallel()
+	a := NewBlockDense([]int{2, 2}, []int{3})
+	b := NewBlockDense([]int{2, 1}, []int{2})
+	if p, _ := panics(func() { a.SetBlock(0, 0, NewDense(3, 3, nil)) }); !p {
+		t.Errorf("expected panic for mismatched block shape")
+	}
+	if p, _ := panics(func() { var m BlockDense; m.Mul(a, b) }); !p {
+		t.Errorf("expected panic for mismatched partition")
+	}
+	if p, _ := panics(func() { NewBlockDense([]int{2, 0}, []int{1}) }); !p {
+		t.Errorf("expected panic for zero block size")
+	}
+}
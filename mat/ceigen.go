@@ -0,0 +1,163 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/blas/cblas128"
+	"gonum.org/v1/gonum/lapack"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+// EigenHerm is a type for creating and manipulating the Eigen decomposition of
+// complex Hermitian matrices.
+type EigenHerm struct {
+	vectorsComputed bool
+
+	values  []float64
+	vectors *CDense
+}
+
+// Factorize computes the eigenvalue decomposition of the n×n Hermitian matrix
+// a. The Eigen decomposition is defined as
+//  A = P * D * Pᴴ
+// where D is a real diagonal matrix containing the eigenvalues of the matrix,
+// and P is a unitary matrix of the eigenvectors of A. Factorize computes the
+// eigenvalues in ascending order. If the vectors input argument is false, the
+// eigenvectors are not computed.
+//
+// Only the diagonal and upper triangle of a are referenced and the imaginary
+// parts of the diagonal elements are assumed to be zero. Factorize will panic
+// if a is not square.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (e *EigenHerm) Factorize(a CMatrix, vectors bool) (ok bool) {
+	// kill previous decomposition
+	e.vectorsComputed = false
+	e.values = e.values[:0]
+
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+
+	// Reduce A to real symmetric tridiagonal form, Qᴴ * A * Q = T, where
+	// Q = H_0 * H_1 * ... * H_{n-2}. The Householder vectors of the
+	// reflectors are kept below the subdiagonal of h.
+	h := NewCDense(n, n, nil)
+	hm := h.mat
+	for i := 0; i < n; i++ {
+		hm.Data[i*hm.Stride+i] = complex(real(a.At(i, i)), 0)
+		for j := i + 1; j < n; j++ {
+			v := a.At(i, j)
+			hm.Data[i*hm.Stride+j] = v
+			hm.Data[j*hm.Stride+i] = cmplx.Conj(v)
+		}
+	}
+	d := make([]float64, n)
+	off := make([]float64, max(0, n-1))
+	tau := make([]complex128, max(0, n-1))
+	v := make([]complex128, n)
+	work := make([]complex128, n)
+	for i := 0; i < n-1; i++ {
+		x := subColumn(hm, i+2, i)
+		beta, t := clarfg(hm.Data[(i+1)*hm.Stride+i], x)
+		off[i] = beta
+		tau[i] = t
+		vi := householderVector(v[:n-i-1], x)
+		sub := subCGeneral(hm, i+1, i+1)
+		clarfLeft(cmplx.Conj(t), vi, sub, work)
+		clarfRight(t, vi, sub, work)
+		d[i] = real(hm.Data[i*hm.Stride+i])
+	}
+	d[n-1] = real(hm.Data[(n-1)*hm.Stride+n-1])
+
+	compz := lapack.EVCompNone
+	var z blas64.General
+	if vectors {
+		compz = lapack.EVTridiag
+		z = blas64.General{Rows: n, Cols: n, Stride: n, Data: make([]float64, n*n)}
+	}
+	fwork := getFloat64s(max(1, 2*n-2), false)
+	ok = lapack64.Steqr(compz, d, off, z, fwork)
+	putFloat64s(fwork)
+	if !ok {
+		e.values = nil
+		e.vectors = nil
+		return false
+	}
+	e.values = d
+	if !vectors {
+		e.vectors = nil
+		return true
+	}
+
+	// Form Q explicitly and back-transform the eigenvectors of T.
+	q := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		q.mat.Data[i*q.mat.Stride+i] = 1
+	}
+	for i := n - 2; i >= 0; i-- {
+		vi := householderVector(v[:n-i-1], subColumn(hm, i+2, i))
+		clarfLeft(tau[i], vi, subCGeneral(q.mat, i+1, i+1), work)
+	}
+	zc := NewCDense(n, n, nil)
+	for i, f := range z.Data {
+		zc.mat.Data[i] = complex(f, 0)
+	}
+	e.vectors = NewCDense(n, n, nil)
+	cblas128.Gemm(blas.NoTrans, blas.NoTrans, 1, q.mat, zc.mat, 0, e.vectors.mat)
+	e.vectorsComputed = true
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *EigenHerm) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the real eigenvalues of the factorized matrix in ascending
+// order. If dst is non-nil, the values are stored in-place into dst. In this
+// case dst must have length n, otherwise Values will panic. If dst is nil,
+// then a new slice will be allocated of the proper length and filled with the
+// eigenvalues.
+//
+// Values panics if the Eigen decomposition was not successful.
+func (e *EigenHerm) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the orthonormal eigenvectors of the decomposition into the
+// columns of dst.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *EigenHerm) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if !e.vectorsComputed {
+		panic(noVectors)
+	}
+	n := len(e.values)
+	dst.reuseAsNonZeroed(n, n)
+	dst.Copy(e.vectors)
+}
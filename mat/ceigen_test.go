@@ -0,0 +1,76 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestEigenHerm(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		// Construct A = Q * D * Qᴴ with known eigenvalues.
+		var qr CQR
+		qr.Factorize(randomCDense(n, n, rnd))
+		var q CDense
+		qr.QTo(&q)
+		want := make([]float64, n)
+		for i := range want {
+			want[i] = 10 * rnd.NormFloat64()
+		}
+		d := NewCDense(n, n, nil)
+		for i, v := range want {
+			d.Set(i, i, complex(v, 0))
+		}
+		a := cmul(cmul(&q, d), q.H())
+		sort.Float64s(want)
+
+		var eig EigenHerm
+		if !eig.Factorize(a, true) {
+			t.Fatalf("n=%d: unexpected factorization failure", n)
+		}
+		got := eig.Values(nil)
+		if !floats.EqualApprox(got, want, 1e-10) {
+			t.Errorf("n=%d: unexpected eigenvalues:\ngot: %v\nwant:%v", n, got, want)
+		}
+
+		var vecs CDense
+		eig.VectorsTo(&vecs)
+		if !isUnitary(&vecs, 1e-12) {
+			t.Errorf("n=%d: eigenvectors are not orthonormal", n)
+		}
+		av := cmul(a, &vecs)
+		for j, v := range got {
+			for i := 0; i < n; i++ {
+				if cmplx.Abs(av.At(i, j)-complex(v, 0)*vecs.At(i, j)) > 1e-10 {
+					t.Fatalf("n=%d: A * v != λ * v for eigenvalue %d", n, j)
+				}
+			}
+		}
+
+		// Only the upper triangle is referenced.
+		for i := 1; i < n; i++ {
+			for j := 0; j < i; j++ {
+				a.Set(i, j, 0)
+			}
+		}
+		var vals EigenHerm
+		if !vals.Factorize(a, false) {
+			t.Fatalf("n=%d: unexpected factorization failure", n)
+		}
+		if got := vals.Values(nil); !floats.EqualApprox(got, want, 1e-10) {
+			t.Errorf("n=%d: unexpected eigenvalues from upper triangle:\ngot: %v\nwant:%v", n, got, want)
+		}
+		if p, _ := panics(func() { vals.VectorsTo(&CDense{}) }); !p {
+			t.Errorf("n=%d: expected panic for uncomputed vectors", n)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+const badCLU = "mat: invalid complex LU factorization"
+
+// CLU is a type for creating and using the LU factorization of a complex
+// matrix.
+type CLU struct {
+	lu    *CDense
+	pivot []int
+}
+
+// Factorize computes the LU factorization of the square complex matrix a and
+// stores the result. The LU decomposition will complete regardless of the
+// singularity of a.
+//
+// The LU factorization is computed with partial pivoting, so that
+//  A = P * L * U
+// where P is a permutation matrix, L is unit lower triangular and U is upper
+// triangular. The permutation can be obtained with the Pivot method and the
+// triangular factors with the LTo and UTo methods.
+func (lu *CLU) Factorize(a CMatrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	if lu.lu == nil {
+		lu.lu = NewCDense(r, r, nil)
+	} else {
+		lu.lu.Reset()
+		lu.lu.reuseAsNonZeroed(r, r)
+	}
+	lu.lu.Copy(a)
+	if cap(lu.pivot) < r {
+		lu.pivot = make([]int, r)
+	}
+	lu.pivot = lu.pivot[:r]
+	cgetf2(lu.lu.mat, lu.pivot)
+}
+
+// cgetf2 computes the LU factorization of the n×n matrix a in place using
+// partial pivoting with row interchanges. On return, ipiv[i] holds the row
+// that was interchanged with row i.
+func cgetf2(a cblas128.General, ipiv []int) {
+	n := a.Rows
+	lda := a.Stride
+	for j := 0; j < n; j++ {
+		col := cblas128.Vector{N: n - j, Inc: lda, Data: a.Data[j*lda+j:]}
+		p := j + cblas128.Iamax(col)
+		ipiv[j] = p
+		if p != j {
+			cblas128.Swap(
+				cblas128.Vector{N: n, Inc: 1, Data: a.Data[j*lda:]},
+				cblas128.Vector{N: n, Inc: 1, Data: a.Data[p*lda:]},
+			)
+		}
+		ajj := a.Data[j*lda+j]
+		if ajj == 0 || j == n-1 {
+			continue
+		}
+		below := cblas128.Vector{N: n - j - 1, Inc: lda, Data: a.Data[(j+1)*lda+j:]}
+		cblas128.Scal(1/ajj, below)
+		cblas128.Geru(-1,
+			below,
+			cblas128.Vector{N: n - j - 1, Inc: 1, Data: a.Data[j*lda+j+1:]},
+			cblas128.General{Rows: n - j - 1, Cols: n - j - 1, Stride: lda, Data: a.Data[(j+1)*lda+j+1:]},
+		)
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lu *CLU) isValid() bool {
+	return lu.lu != nil && !lu.lu.IsEmpty()
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (lu *CLU) Reset() {
+	if lu.lu != nil {
+		lu.lu.Reset()
+	}
+	lu.pivot = lu.pivot[:0]
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a factorization.
+func (lu *CLU) Det() complex128 {
+	det, phase := lu.LogDet()
+	return complex(math.Exp(det), 0) * phase
+}
+
+// LogDet returns the log of the absolute value of the determinant and the
+// phase of the determinant for the matrix that has been factorized, so that
+// the determinant is exp(det)*phase with |phase| = 1. Numerical stability in
+// product and division expressions is generally improved by working in log
+// space.
+// LogDet will panic if the receiver does not contain a factorization.
+func (lu *CLU) LogDet() (det float64, phase complex128) {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+
+	n := lu.lu.mat.Rows
+	phase = 1
+	for i := 0; i < n; i++ {
+		v := lu.lu.mat.Data[i*lu.lu.mat.Stride+i]
+		if lu.pivot[i] != i {
+			phase = -phase
+		}
+		abs := cmplx.Abs(v)
+		if abs != 0 {
+			phase *= v / complex(abs, 0)
+		}
+		det += math.Log(abs)
+	}
+	return det, phase
+}
+
+// Pivot returns pivot indices that enable the construction of the permutation
+// matrix P (see Dense.Permutation). If swaps == nil, then new memory will be
+// allocated, otherwise the length of the input must be equal to the size of the
+// factorized matrix.
+// Pivot will panic if the receiver does not contain a factorization.
+func (lu *CLU) Pivot(swaps []int) []int {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+
+	n := lu.lu.mat.Rows
+	if swaps == nil {
+		swaps = make([]int, n)
+	}
+	if len(swaps) != n {
+		panic(badSliceLength)
+	}
+	// Perform the inverse of the row swaps in order to find the final
+	// row swap position.
+	for i := range swaps {
+		swaps[i] = i
+	}
+	for i := n - 1; i >= 0; i-- {
+		v := lu.pivot[i]
+		swaps[i], swaps[v] = swaps[v], swaps[i]
+	}
+	return swaps
+}
+
+// LTo extracts the unit lower triangular matrix from an LU factorization.
+//
+// If dst is empty, LTo will resize dst to be n×n. When dst is non-empty, LTo
+// will panic if dst is not n×n. LTo will also panic if the receiver does not
+// contain a factorization.
+func (lu *CLU) LTo(dst *CDense) {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+
+	n := lu.lu.mat.Rows
+	dst.reuseAsNonZeroed(n, n)
+	for i := 0; i < n; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		copy(row[:i], lu.lu.mat.Data[i*lu.lu.mat.Stride:])
+		row[i] = 1
+		zeroC(row[i+1:])
+	}
+}
+
+// UTo extracts the upper triangular matrix from an LU factorization.
+//
+// If dst is empty, UTo will resize dst to be n×n. When dst is non-empty, UTo
+// will panic if dst is not n×n. UTo will also panic if the receiver does not
+// contain a factorization.
+func (lu *CLU) UTo(dst *CDense) {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+
+	n := lu.lu.mat.Rows
+	dst.reuseAsNonZeroed(n, n)
+	for i := 0; i < n; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		zeroC(row[:i])
+		copy(row[i:], lu.lu.mat.Data[i*lu.lu.mat.Stride+i:i*lu.lu.mat.Stride+n])
+	}
+}
+
+// SolveTo solves a system of linear equations using the LU decomposition of a
+// complex matrix. It computes
+//  A * X = B if trans == false
+//  Aᴴ * X = B if trans == true
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is exactly singular a Condition error is returned and dst is not
+// modified beyond being resized.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lu *CLU) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+
+	n := lu.lu.mat.Rows
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(n, bc)
+	for i := 0; i < n; i++ {
+		if lu.lu.mat.Data[i*lu.lu.mat.Stride+i] == 0 {
+			return Condition(math.Inf(1))
+		}
+	}
+
+	bU, _, _ := untransposeCmplx(b)
+	if dst == bU {
+		var restore func()
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawCMatrixer); ok {
+		dst.checkOverlap(rm.RawCMatrix())
+	}
+	dst.Copy(b)
+
+	x := dst.mat
+	l := cblas128.Triangular{N: n, Stride: lu.lu.mat.Stride, Data: lu.lu.mat.Data, Uplo: blas.Lower, Diag: blas.Unit}
+	u := cblas128.Triangular{N: n, Stride: lu.lu.mat.Stride, Data: lu.lu.mat.Data, Uplo: blas.Upper, Diag: blas.NonUnit}
+	if !trans {
+		for i, p := range lu.pivot {
+			if p != i {
+				swapCRows(x, i, p)
+			}
+		}
+		cblas128.Trsm(blas.Left, blas.NoTrans, 1, l, x)
+		cblas128.Trsm(blas.Left, blas.NoTrans, 1, u, x)
+		return nil
+	}
+	cblas128.Trsm(blas.Left, blas.ConjTrans, 1, u, x)
+	cblas128.Trsm(blas.Left, blas.ConjTrans, 1, l, x)
+	for i := n - 1; i >= 0; i-- {
+		if p := lu.pivot[i]; p != i {
+			swapCRows(x, i, p)
+		}
+	}
+	return nil
+}
+
+// swapCRows swaps rows i and j of a.
+func swapCRows(a cblas128.General, i, j int) {
+	cblas128.Swap(
+		cblas128.Vector{N: a.Cols, Inc: 1, Data: a.Data[i*a.Stride:]},
+		cblas128.Vector{N: a.Cols, Inc: 1, Data: a.Data[j*a.Stride:]},
+	)
+}
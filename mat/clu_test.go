@@ -0,0 +1,147 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 31} {
+		a := randomCDense(n, n, rnd)
+
+		var lu CLU
+		lu.Factorize(a)
+
+		var l, u CDense
+		lu.LTo(&l)
+		lu.UTo(&u)
+		for i := 0; i < n; i++ {
+			if l.At(i, i) != 1 {
+				t.Errorf("n=%d: L not unit diagonal", n)
+			}
+			for j := i + 1; j < n; j++ {
+				if l.At(i, j) != 0 || u.At(j, i) != 0 {
+					t.Fatalf("n=%d: factors not triangular", n)
+				}
+			}
+		}
+		// Check P * L * U = A.
+		piv := lu.Pivot(nil)
+		lu1 := cmul(&l, &u)
+		got := NewCDense(n, n, nil)
+		for i, p := range piv {
+			for j := 0; j < n; j++ {
+				got.Set(i, j, lu1.At(p, j))
+			}
+		}
+		if !CEqualApprox(got, a, 1e-12) {
+			t.Errorf("n=%d: P * L * U != A", n)
+		}
+
+		// Compare the determinant with the product of the
+		// diagonal of U and the sign of the permutation.
+		want := complex(1, 0)
+		for i := 0; i < n; i++ {
+			want *= u.At(i, i)
+			if lu.pivot[i] != i {
+				want = -want
+			}
+		}
+		if det := lu.Det(); cmplx.Abs(det-want) > 1e-10*cmplx.Abs(want) {
+			t.Errorf("n=%d: unexpected determinant: got %v, want %v", n, det, want)
+		}
+
+		for _, trans := range []bool{false, true} {
+			x := randomCDense(n, 3, rnd)
+			op := CMatrix(a)
+			if trans {
+				op = a.H()
+			}
+			b := cmul(op, x)
+			var got CDense
+			err := lu.SolveTo(&got, trans, b)
+			if err != nil {
+				t.Fatalf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			if !CEqualApprox(&got, x, 1e-10) {
+				t.Errorf("n=%d trans=%t: unexpected solution", n, trans)
+			}
+
+			// Solve in place.
+			err = lu.SolveTo(b, trans, b)
+			if err != nil {
+				t.Fatalf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			if !CEqualApprox(b, x, 1e-10) {
+				t.Errorf("n=%d trans=%t: unexpected in-place solution", n, trans)
+			}
+		}
+	}
+
+	var lu CLU
+	lu.Factorize(NewCDense(2, 2, []complex128{1, 1i, 1i, -1}))
+	if det := lu.Det(); det != 0 {
+		t.Errorf("unexpected determinant of singular matrix: %v", det)
+	}
+	var x CDense
+	err := lu.SolveTo(&x, false, NewCDense(2, 1, []complex128{1, 2}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix, got: %v", err)
+	}
+}
+
+// randomCDense returns an r×c complex matrix with elements drawn from the
+// standard complex normal distribution.
+func randomCDense(r, c int, rnd *rand.Rand) *CDense {
+	m := NewCDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+	}
+	return m
+}
+
+// cmul returns the product of a and b.
+func cmul(a, b CMatrix) *CDense {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	m := NewCDense(ar, bc, nil)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < bc; j++ {
+			var v complex128
+			for k := 0; k < ac; k++ {
+				v += a.At(i, k) * b.At(k, j)
+			}
+			m.Set(i, j, v)
+		}
+	}
+	return m
+}
+
+// isUnitary returns whether the columns of q are orthonormal.
+func isUnitary(q CMatrix, tol float64) bool {
+	_, c := q.Dims()
+	qhq := cmul(ConjTranspose{q}, q)
+	for i := 0; i < c; i++ {
+		for j := 0; j < c; j++ {
+			want := complex(0, 0)
+			if i == j {
+				want = 1
+			}
+			if cmplx.Abs(qhq.At(i, j)-want) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
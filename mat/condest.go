@@ -0,0 +1,106 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+var (
+	_ MulVecToer = InverseLU{}
+	_ MulVecToer = InverseCholesky{}
+)
+
+// InverseLU adapts the LU factorization of a matrix A to the MulVecToer
+// interface as the operator A⁻¹, computing products with the inverse by
+// solving systems with the factorization.
+type InverseLU struct {
+	LU *LU
+}
+
+// MulVecTo computes A⁻¹⋅x or A⁻ᵀ⋅x storing the result into dst. If A is
+// exactly singular, the elements of dst are set to +Inf.
+func (op InverseLU) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	err := op.LU.SolveVecTo(dst, trans, x)
+	if c, ok := err.(Condition); ok && math.IsInf(float64(c), 1) {
+		dst.reuseAsNonZeroed(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			dst.setVec(i, math.Inf(1))
+		}
+	}
+}
+
+// InverseCholesky adapts the Cholesky factorization of a symmetric positive
+// definite matrix A to the MulVecToer interface as the operator A⁻¹,
+// computing products with the inverse by solving systems with the
+// factorization.
+type InverseCholesky struct {
+	Cholesky *Cholesky
+}
+
+// MulVecTo computes A⁻¹⋅x storing the result into dst. Since A is symmetric,
+// trans is ignored.
+func (op InverseCholesky) MulVecTo(dst *VecDense, _ bool, x Vector) {
+	op.Cholesky.SolveVecTo(dst, x)
+}
+
+// NormEst1 returns an estimate of the 1-norm of the n×n operator a, computed
+// with the Hager-Higham algorithm implemented by Dlacn2 from at most a few
+// products of a and aᵀ with vectors. The estimate is a lower bound of ‖A‖₁
+// that is usually within a factor of 3 of the true norm, and is frequently
+// exact.
+//
+// NormEst1 will panic if n is not positive.
+func NormEst1(a MulVecToer, n int) float64 {
+	if n <= 0 {
+		panic(ErrZeroLength)
+	}
+	return normEst1(n, a.MulVecTo)
+}
+
+// CondEst1 returns an estimate of the 1-norm condition number
+//  κ₁(A) = ‖A‖₁ ‖A⁻¹‖₁
+// of the n×n operator a, where inv computes products with the inverse of
+// A, for example an InverseLU or InverseCholesky holding a factorization of
+// A. Both norms are estimated by NormEst1, so the inverse of A is never
+// formed. If the norm of A is already known, for example as the maximum
+// absolute column sum of an explicit matrix, it is cheaper to multiply it
+// by NormEst1(inv, n).
+//
+// CondEst1 will panic if n is not positive.
+func CondEst1(a, inv MulVecToer, n int) float64 {
+	if n <= 0 {
+		panic(ErrZeroLength)
+	}
+	return normEst1(n, a.MulVecTo) * normEst1(n, inv.MulVecTo)
+}
+
+// normEst1 returns an estimate of the 1-norm of the n×n matrix B, given a
+// function computing B * v, or Bᵀ * v if trans is true, into dst. mul must
+// not modify v. The estimate is a lower bound on ‖B‖₁ computed by Dlacn2.
+func normEst1(n int, mul func(dst *VecDense, trans bool, v Vector)) float64 {
+	v := getFloat64s(n, false)
+	defer putFloat64s(v)
+	isgn := getInts(n, false)
+	defer putInts(isgn)
+	x := NewVecDense(n, nil)
+	y := NewVecDense(n, nil)
+
+	var (
+		est   float64
+		kase  int
+		isave [3]int
+	)
+	for {
+		est, kase = lapack64.Lacn2(v, x.mat.Data, isgn, est, kase, &isave)
+		if kase == 0 {
+			return est
+		}
+		mul(y, kase == 2, x)
+		x.CopyVec(y)
+	}
+}
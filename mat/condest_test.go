@@ -0,0 +1,88 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/lapack"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+func TestNormEst1(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 20, 100} {
+		for trial := 0; trial < 10; trial++ {
+			a := randomDense(n, n, rnd)
+			want := Norm(a, 1)
+			got := NormEst1(MatrixOperator{a}, n)
+			if got > want*(1+1e-14) || got < want/3 {
+				t.Errorf("n=%d: unexpected 1-norm estimate: got %v, want %v", n, got, want)
+			}
+		}
+	}
+
+	a := poisson2D(10)
+	n, _ := a.Dims()
+	want := Norm(DenseCopyOf(a), 1)
+	if got := NormEst1(a, n); got > want*(1+1e-14) || got < want/3 {
+		t.Errorf("unexpected 1-norm estimate for sparse matrix: got %v, want %v", got, want)
+	}
+}
+
+func TestCondEst1(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 10, 50} {
+		a := randomDense(n, n, rnd)
+		var inv Dense
+		if err := inv.Inverse(a); err != nil {
+			t.Fatalf("n=%d: unexpected inverse error: %v", n, err)
+		}
+		want := Norm(a, 1) * Norm(&inv, 1)
+
+		var lu LU
+		lu.Factorize(a)
+		got := CondEst1(MatrixOperator{a}, InverseLU{&lu}, n)
+		if got > want*(1+1e-10) || got < want/10 {
+			t.Errorf("n=%d: unexpected LU condition estimate: got %v, want %v", n, got, want)
+		}
+
+		// The estimate of ‖A⁻¹‖₁ follows the same steps as Dgecon.
+		gecon := 1 / lapack64.Gecon(lapack.MaxColumnSum, lu.lu.mat, 1, make([]float64, 4*n), make([]int, n))
+		if got := NormEst1(InverseLU{&lu}, n); math.Abs(got-gecon) > 1e-8*gecon {
+			t.Errorf("n=%d: inverse norm estimate does not match Dgecon: got %v, want %v", n, got, gecon)
+		}
+
+		var spd SymDense
+		spd.SymOuterK(1, a)
+		for i := 0; i < n; i++ {
+			spd.SetSym(i, i, spd.At(i, i)+1)
+		}
+		var chol Cholesky
+		if !chol.Factorize(&spd) {
+			t.Fatalf("n=%d: unexpected Cholesky failure", n)
+		}
+		var sinv Dense
+		if err := sinv.Inverse(&spd); err != nil {
+			t.Fatalf("n=%d: unexpected inverse error: %v", n, err)
+		}
+		want = Norm(&spd, 1) * Norm(&sinv, 1)
+		got = CondEst1(MatrixOperator{&spd}, InverseCholesky{&chol}, n)
+		if got > want*(1+1e-10) || got < want/10 {
+			t.Errorf("n=%d: unexpected Cholesky condition estimate: got %v, want %v", n, got, want)
+		}
+	}
+
+	var lu LU
+	lu.Factorize(NewDense(2, 2, []float64{1, 2, 2, 4}))
+	if got := CondEst1(MatrixOperator{lu.lu}, InverseLU{&lu}, 2); !math.IsInf(got, 1) {
+		t.Errorf("unexpected condition estimate for singular matrix: got %v, want +Inf", got)
+	}
+}
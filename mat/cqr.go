@@ -0,0 +1,263 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+const badCQR = "mat: invalid complex QR factorization"
+
+// CQR is a type for creating and using the QR factorization of a complex
+// matrix.
+type CQR struct {
+	qr  *CDense
+	tau []complex128
+}
+
+// Factorize computes the QR factorization of an m×n complex matrix a where
+// m >= n. The QR factorization always exists even if A is singular.
+//
+// The QR decomposition is a factorization of the matrix A such that A = Q * R.
+// The matrix Q is a unitary m×m matrix, and R is an m×n upper triangular matrix
+// with a real diagonal. Q and R can be extracted using the QTo and RTo methods.
+func (qr *CQR) Factorize(a CMatrix) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	if qr.qr == nil {
+		qr.qr = NewCDense(m, n, nil)
+	} else {
+		qr.qr.Reset()
+		qr.qr.reuseAsNonZeroed(m, n)
+	}
+	qr.qr.Copy(a)
+	if cap(qr.tau) < n {
+		qr.tau = make([]complex128, n)
+	}
+	qr.tau = qr.tau[:n]
+
+	q := qr.qr.mat
+	v := make([]complex128, m)
+	work := make([]complex128, n)
+	for j := 0; j < n; j++ {
+		x := subColumn(q, j+1, j)
+		beta, tau := clarfg(q.Data[j*q.Stride+j], x)
+		q.Data[j*q.Stride+j] = complex(beta, 0)
+		qr.tau[j] = tau
+		if j < n-1 {
+			vj := householderVector(v[:m-j], x)
+			clarfLeft(cmplx.Conj(tau), vj, subCGeneral(q, j, j+1), work)
+		}
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *CQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsEmpty()
+}
+
+// RTo extracts the m×n upper trapezoidal matrix from a QR decomposition.
+//
+// If dst is empty, RTo will resize dst to be m×n. When dst is non-empty, RTo
+// will panic if dst is not m×n. RTo will also panic if the receiver does not
+// contain a successful factorization.
+func (qr *CQR) RTo(dst *CDense) {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+
+	m, n := qr.qr.Dims()
+	dst.reuseAsNonZeroed(m, n)
+	for i := 0; i < m; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		if i >= n {
+			zeroC(row)
+			continue
+		}
+		zeroC(row[:i])
+		copy(row[i:], qr.qr.mat.Data[i*qr.qr.mat.Stride+i:i*qr.qr.mat.Stride+n])
+	}
+}
+
+// QTo extracts the m×m unitary matrix Q from a QR decomposition.
+//
+// If dst is empty, QTo will resize dst to be m×m. When dst is non-empty, QTo
+// will panic if dst is not m×m. QTo will also panic if the receiver does not
+// contain a successful factorization.
+func (qr *CQR) QTo(dst *CDense) {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+
+	m, _ := qr.qr.Dims()
+	dst.reuseAsZeroed(m, m)
+	for i := 0; i < m; i++ {
+		dst.mat.Data[i*dst.mat.Stride+i] = 1
+	}
+	qr.applyQ(false, dst.mat)
+}
+
+// applyQ computes Q * b or Qᴴ * b, storing the result in place into b.
+func (qr *CQR) applyQ(conj bool, b cblas128.General) {
+	q := qr.qr.mat
+	v := make([]complex128, q.Rows)
+	work := make([]complex128, b.Cols)
+	apply := func(j int) {
+		vj := householderVector(v[:q.Rows-j], subColumn(q, j+1, j))
+		tau := qr.tau[j]
+		if conj {
+			tau = cmplx.Conj(tau)
+		}
+		clarfLeft(tau, vj, subCGeneral(b, j, 0), work)
+	}
+	if conj {
+		for j := range qr.tau {
+			apply(j)
+		}
+		return
+	}
+	for j := len(qr.tau) - 1; j >= 0; j-- {
+		apply(j)
+	}
+}
+
+// SolveTo finds a minimum-norm solution to a system of linear equations
+// defined by the matrices A and B, where A is an m×n complex matrix
+// represented in its QR factorized form.
+//
+// The minimization problem solved depends on the input parameters.
+//  If trans == false, find X such that ||A*X - B||_2 is minimized.
+//  If trans == true, find the minimum norm solution of Aᴴ * X = B.
+// The solution matrix, X, is stored in place into dst.
+//
+// If R has a zero on its diagonal a Condition error is returned.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (qr *CQR) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+
+	m, n := qr.qr.Dims()
+	br, bc := b.Dims()
+	if trans {
+		if br != n {
+			panic(ErrShape)
+		}
+		dst.reuseAsNonZeroed(m, bc)
+	} else {
+		if br != m {
+			panic(ErrShape)
+		}
+		dst.reuseAsNonZeroed(n, bc)
+	}
+	q := qr.qr.mat
+	for i := 0; i < n; i++ {
+		if q.Data[i*q.Stride+i] == 0 {
+			return Condition(math.Inf(1))
+		}
+	}
+
+	// The right hand side and the solution have different sizes, so
+	// both are held in an m×bc workspace.
+	x := getCDenseWorkspace(m, bc, true)
+	defer putCDenseWorkspace(x)
+	r := cblas128.Triangular{N: n, Stride: q.Stride, Data: q.Data, Uplo: blas.Upper, Diag: blas.NonUnit}
+	top := x.slice(0, n, 0, bc)
+	if trans {
+		top.Copy(b)
+		cblas128.Trsm(blas.Left, blas.ConjTrans, 1, r, top.mat)
+		qr.applyQ(false, x.mat)
+		dst.Copy(x)
+		return nil
+	}
+	x.Copy(b)
+	qr.applyQ(true, x.mat)
+	cblas128.Trsm(blas.Left, blas.NoTrans, 1, r, top.mat)
+	dst.Copy(top)
+	return nil
+}
+
+// clarfg generates an elementary reflector H of order n such that
+//  Hᴴ * [alpha] = [beta]
+//       [  x  ]   [  0 ]
+// where beta is real and H = I - tau * v * vᴴ with v = [1; x'].
+// On return, x is overwritten with x'.
+func clarfg(alpha complex128, x cblas128.Vector) (beta float64, tau complex128) {
+	var xnorm float64
+	if x.N > 0 {
+		xnorm = cblas128.Nrm2(x)
+	}
+	ar, ai := real(alpha), imag(alpha)
+	if xnorm == 0 && ai == 0 {
+		return ar, 0
+	}
+	beta = -math.Copysign(math.Hypot(cmplx.Abs(alpha), xnorm), ar)
+	tau = complex((beta-ar)/beta, -ai/beta)
+	if x.N > 0 {
+		cblas128.Scal(1/(alpha-complex(beta, 0)), x)
+	}
+	return beta, tau
+}
+
+// clarfLeft applies the elementary reflector H = I - tau * v * vᴴ to c
+// from the left. work must have length at least c.Cols.
+func clarfLeft(tau complex128, v []complex128, c cblas128.General, work []complex128) {
+	if tau == 0 || c.Cols == 0 {
+		return
+	}
+	vv := cblas128.Vector{N: len(v), Inc: 1, Data: v}
+	w := cblas128.Vector{N: c.Cols, Inc: 1, Data: work}
+	cblas128.Gemv(blas.ConjTrans, 1, c, vv, 0, w)
+	cblas128.Gerc(-tau, vv, w, c)
+}
+
+// clarfRight applies the elementary reflector H = I - tau * v * vᴴ to c
+// from the right. work must have length at least c.Rows.
+func clarfRight(tau complex128, v []complex128, c cblas128.General, work []complex128) {
+	if tau == 0 || c.Rows == 0 {
+		return
+	}
+	vv := cblas128.Vector{N: len(v), Inc: 1, Data: v}
+	w := cblas128.Vector{N: c.Rows, Inc: 1, Data: work}
+	cblas128.Gemv(blas.NoTrans, 1, c, vv, 0, w)
+	cblas128.Gerc(-tau, w, vv, c)
+}
+
+// householderVector fills dst with the Householder vector [1; x] and returns
+// it. The length of dst must be x.N+1.
+func householderVector(dst []complex128, x cblas128.Vector) []complex128 {
+	dst[0] = 1
+	for i := 0; i < x.N; i++ {
+		dst[i+1] = x.Data[i*x.Inc]
+	}
+	return dst
+}
+
+// subColumn returns the elements of column j of a from row i to the last row.
+func subColumn(a cblas128.General, i, j int) cblas128.Vector {
+	if i >= a.Rows {
+		return cblas128.Vector{Inc: 1}
+	}
+	return cblas128.Vector{N: a.Rows - i, Inc: a.Stride, Data: a.Data[i*a.Stride+j:]}
+}
+
+// subCGeneral returns the trailing submatrix of a starting at (i, j).
+func subCGeneral(a cblas128.General, i, j int) cblas128.General {
+	if i >= a.Rows || j >= a.Cols {
+		return cblas128.General{Stride: max(1, a.Stride)}
+	}
+	return cblas128.General{
+		Rows:   a.Rows - i,
+		Cols:   a.Cols - j,
+		Stride: a.Stride,
+		Data:   a.Data[i*a.Stride+j:],
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCQR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {3, 3}, {5, 3}, {10, 10}, {20, 7}, {7, 1},
+	} {
+		m, n := test.m, test.n
+		a := randomCDense(m, n, rnd)
+
+		var qr CQR
+		qr.Factorize(a)
+		var q, r CDense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		if !isUnitary(&q, 1e-12) {
+			t.Errorf("m=%d n=%d: Q is not unitary", m, n)
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j < min(i, n); j++ {
+				if r.At(i, j) != 0 {
+					t.Fatalf("m=%d n=%d: R is not upper triangular", m, n)
+				}
+			}
+			if i < n && imag(r.At(i, i)) != 0 {
+				t.Errorf("m=%d n=%d: R has complex diagonal", m, n)
+			}
+		}
+		if !CEqualApprox(cmul(&q, &r), a, 1e-12) {
+			t.Errorf("m=%d n=%d: Q * R != A", m, n)
+		}
+
+		// Least squares solution: the residual is orthogonal to the
+		// range of A.
+		b := randomCDense(m, 2, rnd)
+		var x CDense
+		if err := qr.SolveTo(&x, false, b); err != nil {
+			t.Fatalf("m=%d n=%d: unexpected error: %v", m, n, err)
+		}
+		res := cmul(a, &x)
+		for i := 0; i < m; i++ {
+			for j := 0; j < 2; j++ {
+				res.Set(i, j, b.At(i, j)-res.At(i, j))
+			}
+		}
+		if !CEqualApprox(cmul(a.H(), res), NewCDense(n, 2, nil), 1e-10) {
+			t.Errorf("m=%d n=%d: least squares residual not orthogonal to range of A", m, n)
+		}
+
+		// Minimum norm solution of Aᴴ * X = B: the solution lies in
+		// the range of A.
+		b = randomCDense(n, 2, rnd)
+		var xh CDense
+		if err := qr.SolveTo(&xh, true, b); err != nil {
+			t.Fatalf("m=%d n=%d: unexpected error: %v", m, n, err)
+		}
+		if !CEqualApprox(cmul(a.H(), &xh), b, 1e-10) {
+			t.Errorf("m=%d n=%d: Aᴴ * X != B", m, n)
+		}
+		qhx := cmul(q.H(), &xh)
+		for i := n; i < m; i++ {
+			for j := 0; j < 2; j++ {
+				if cmplx.Abs(qhx.At(i, j)) > 1e-10 {
+					t.Errorf("m=%d n=%d: minimum norm solution not in range of A", m, n)
+				}
+			}
+		}
+	}
+}
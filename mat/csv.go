@@ -0,0 +1,205 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	errCSVEmpty  = errors.New("mat: no data in CSV input")
+	errCSVHeader = errors.New("mat: header length mismatch")
+)
+
+// MissingPolicy specifies the handling of missing values when reading CSV data.
+type MissingPolicy int
+
+const (
+	// MissingError specifies that a missing value is an error.
+	MissingError MissingPolicy = iota
+
+	// MissingNaN specifies that a missing value is read as NaN.
+	MissingNaN
+
+	// MissingFill specifies that a missing value is read as the
+	// fill value of the CSVOptions.
+	MissingFill
+)
+
+// CSVOptions holds the options for reading and writing CSV data. The zero
+// value specifies comma separated values without a header, with empty fields
+// treated as an error.
+type CSVOptions struct {
+	// Comma is the field delimiter. If Comma is zero, ',' is used.
+	Comma rune
+
+	// Header specifies whether the first record is a header
+	// holding the column names.
+	Header bool
+
+	// Missing is the policy for fields holding a missing value.
+	Missing MissingPolicy
+
+	// MissingValues holds the field values, after trimming white
+	// space, that denote a missing value. If MissingValues is nil,
+	// only the empty field denotes a missing value.
+	MissingValues []string
+
+	// Fill is the value used for missing values when Missing
+	// is MissingFill.
+	Fill float64
+
+	// Format and Prec specify the formatting of values written
+	// by WriteCSV as described for strconv.FormatFloat. If Format
+	// is zero, the shortest representation that reads back exactly
+	// is written.
+	Format byte
+	Prec   int
+}
+
+// isMissing returns whether the trimmed field s denotes a missing value.
+func (o *CSVOptions) isMissing(s string) bool {
+	if o.MissingValues == nil {
+		return s == ""
+	}
+	for _, m := range o.MissingValues {
+		if s == m {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadCSV reads delimiter separated numeric values from r and returns them
+// as a newly allocated Dense with one row per record. If opts is nil, the
+// zero value of CSVOptions is used. If opts.Header is true, the first record
+// is returned as the header. All records must have the same number of fields.
+func ReadCSV(r io.Reader, opts *CSVOptions) (m *Dense, header []string, err error) {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	// Leading white space is trimmed from each field below, since
+	// trimming by the csv.Reader would consume white space delimiters.
+	cr.ReuseRecord = true
+
+	if opts.Header {
+		rec, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = errCSVEmpty
+			}
+			return nil, nil, err
+		}
+		header = append([]string(nil), rec...)
+	}
+
+	var (
+		data []float64
+		rows int
+		cols = -1
+	)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if cols < 0 {
+			cols = len(rec)
+		}
+		for j, f := range rec {
+			f = strings.TrimSpace(f)
+			if opts.isMissing(f) {
+				switch opts.Missing {
+				case MissingNaN:
+					data = append(data, math.NaN())
+					continue
+				case MissingFill:
+					data = append(data, opts.Fill)
+					continue
+				default:
+					line, _ := cr.FieldPos(j)
+					return nil, nil, fmt.Errorf("mat: missing value at line %d, column %d", line, j+1)
+				}
+			}
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				line, _ := cr.FieldPos(j)
+				return nil, nil, fmt.Errorf("mat: invalid value at line %d, column %d: %w", line, j+1, err)
+			}
+			data = append(data, v)
+		}
+		rows++
+	}
+	if rows == 0 || cols == 0 {
+		return nil, nil, errCSVEmpty
+	}
+	if header != nil && len(header) != cols {
+		return nil, nil, errCSVHeader
+	}
+	return NewDense(rows, cols, data), header, nil
+}
+
+// WriteCSV writes the elements of m to w as delimiter separated values with
+// one record per row. If opts is nil, the zero value of CSVOptions is used.
+// If opts.Header is true, header is written as the first record and must
+// have one element per column of m. NaN values are written as the first of
+// opts.MissingValues, or as the empty field if MissingValues is nil, unless
+// opts.Missing is MissingError.
+func WriteCSV(w io.Writer, m Matrix, header []string, opts *CSVOptions) error {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	r, c := m.Dims()
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	if opts.Header {
+		if len(header) != c {
+			return errCSVHeader
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	format, prec := opts.Format, opts.Prec
+	if format == 0 {
+		format, prec = 'g', -1
+	}
+	var missing string
+	if len(opts.MissingValues) != 0 {
+		missing = opts.MissingValues[0]
+	}
+	rec := make([]string, c)
+	for i := 0; i < r; i++ {
+		for j := range rec {
+			v := m.At(i, j)
+			if math.IsNaN(v) && opts.Missing != MissingError {
+				rec[j] = missing
+				continue
+			}
+			rec[j] = strconv.FormatFloat(v, format, prec, 64)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,111 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		in     string
+		opts   *CSVOptions
+		want   *Dense
+		header []string
+		err    bool
+	}{
+		{
+			in:   "1,2,3\n4,5,6\n",
+			want: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		},
+		{
+			in:     "a;b\n1.5; -2\n3e2;4\n",
+			opts:   &CSVOptions{Comma: ';', Header: true},
+			want:   NewDense(2, 2, []float64{1.5, -2, 300, 4}),
+			header: []string{"a", "b"},
+		},
+		{
+			in:   "1,,3\n4,NA,6\n",
+			opts: &CSVOptions{Missing: MissingFill, Fill: -1, MissingValues: []string{"", "NA"}},
+			want: NewDense(2, 3, []float64{1, -1, 3, 4, -1, 6}),
+		},
+		{
+			in:   "1,,3\n",
+			opts: &CSVOptions{Missing: MissingNaN},
+			want: NewDense(1, 3, []float64{1, math.NaN(), 3}),
+		},
+		{in: "1,,3\n", err: true},
+		{in: "1,x\n", err: true},
+		{in: "1,2\n3\n", err: true},
+		{in: "", err: true},
+		{in: "a,b,c\n1,2\n", opts: &CSVOptions{Header: true}, err: true},
+	} {
+		got, header, err := ReadCSV(strings.NewReader(test.in), test.opts)
+		if (err != nil) != test.err {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if test.err {
+			continue
+		}
+		if !Equal(got, test.want) && !sameNaNs(got, test.want) {
+			t.Errorf("test %d: unexpected result:\ngot:  %v\nwant: %v", i, Formatted(got), Formatted(test.want))
+		}
+		if !reflect.DeepEqual(header, test.header) {
+			t.Errorf("test %d: unexpected header: got %q, want %q", i, header, test.header)
+		}
+	}
+}
+
+// sameNaNs returns whether a and b are equal, treating NaNs as equal.
+func sameNaNs(a, b Matrix) bool {
+	r, c := a.Dims()
+	if br, bc := b.Dims(); r != br || c != bc {
+		return false
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			av, bv := a.At(i, j), b.At(i, j)
+			if av != bv && !(math.IsNaN(av) && math.IsNaN(bv)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	t.Parallel()
+	m := NewDense(2, 3, []float64{1, math.Pi, -1e-300, math.NaN(), 0.1, 7})
+	opts := &CSVOptions{Comma: '\t', Header: true, Missing: MissingNaN}
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, m, []string{"x", "y", "z"}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "x\ty\tz\n1\t3.141592653589793\t-1e-300\n\t0.1\t7\n"
+	if buf.String() != want {
+		t.Errorf("unexpected output:\ngot:  %q\nwant: %q", buf.String(), want)
+	}
+	got, header, err := ReadCSV(&buf, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sameNaNs(got, m) {
+		t.Errorf("round trip mismatch:\ngot:  %v\nwant: %v", Formatted(got), Formatted(m))
+	}
+	if !reflect.DeepEqual(header, []string{"x", "y", "z"}) {
+		t.Errorf("unexpected header: %q", header)
+	}
+
+	if err := WriteCSV(&buf, m, []string{"x"}, opts); err == nil {
+		t.Errorf("expected error for header length mismatch")
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+// maxJacobiSweeps is the maximum number of sweeps performed by the one-sided
+// Jacobi SVD before it reports failure.
+const maxJacobiSweeps = 100
+
+// CSVD is a type for creating and using the Singular Value Decomposition
+// of a complex matrix.
+type CSVD struct {
+	kind SVDKind
+
+	s []float64
+	u *CDense
+	v *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (svd *CSVD) succFact() bool {
+	return len(svd.s) != 0
+}
+
+// Factorize computes the singular value decomposition (SVD) of the complex
+// input matrix A. The singular values of A are computed in all cases, while
+// the singular vectors are optionally computed depending on the input kind.
+//
+// The full singular value decomposition (kind == SVDFull) is a factorization
+// of an m×n matrix A of the form
+//  A = U * Σ * Vᴴ
+// where Σ is an m×n real diagonal matrix, U is an m×m unitary matrix, and V is
+// an n×n unitary matrix. The diagonal elements of Σ are the singular values of
+// A. The first min(m,n) columns of U and V are, respectively, the left and
+// right singular vectors of A. The thin decomposition (kind == SVDThin) finds
+// U of size m×min(m,n) and V of size n×min(m,n).
+//
+// The decomposition is computed with the one-sided Jacobi method, which
+// computes small singular values to high relative accuracy.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+func (svd *CSVD) Factorize(a CMatrix, kind SVDKind) (ok bool) {
+	// kill previous factorization
+	svd.s = svd.s[:0]
+	svd.kind = kind
+	svd.u = nil
+	svd.v = nil
+
+	m, n := a.Dims()
+	if m == 0 || n == 0 {
+		panic(ErrZeroLength)
+	}
+	// The Jacobi iteration orthogonalizes the columns of a tall matrix, so
+	// a wide A is handled through Aᴴ = V * Σ * Uᴴ.
+	wide := m < n
+	var w *CDense
+	if wide {
+		w = NewCDense(n, m, nil)
+		w.Copy(a.H())
+		m, n = n, m
+	} else {
+		w = NewCDense(m, n, nil)
+		w.Copy(a)
+	}
+	v := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		v.mat.Data[i*v.mat.Stride+i] = 1
+	}
+	if !jacobiSVD(w.mat, v.mat) {
+		svd.kind = 0
+		return false
+	}
+
+	// Singular values are the column norms of W. Sort them in descending
+	// order and permute the columns of W and V accordingly.
+	s := make([]float64, n)
+	for j := range s {
+		s[j] = cblas128.Nrm2(cblas128.Vector{N: m, Inc: w.mat.Stride, Data: w.mat.Data[j:]})
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool { return s[perm[i]] > s[perm[j]] })
+	sorted := make([]float64, n)
+	for i, p := range perm {
+		sorted[i] = s[p]
+	}
+	svd.s = sorted
+
+	left := kind&(SVDThinU|SVDFullU) != 0
+	right := kind&(SVDThinV|SVDFullV) != 0
+	if wide {
+		left, right = right, left
+	}
+	var u, vs *CDense
+	if left {
+		// The left singular vectors are the normalized columns of W.
+		// Columns corresponding to negligible singular values are
+		// completed to an orthonormal set.
+		u = NewCDense(m, n, nil)
+		tol := float64(m) * eps * sorted[0]
+		k := n
+		for j, p := range perm {
+			if sorted[j] <= tol {
+				k = j
+				break
+			}
+			for i := 0; i < m; i++ {
+				u.mat.Data[i*u.mat.Stride+j] = w.mat.Data[i*w.mat.Stride+p] / complex(sorted[j], 0)
+			}
+		}
+		completeBasis(u, k)
+	}
+	if right {
+		vs = NewCDense(n, n, nil)
+		for j, p := range perm {
+			for i := 0; i < n; i++ {
+				vs.mat.Data[i*vs.mat.Stride+j] = v.mat.Data[i*v.mat.Stride+p]
+			}
+		}
+	}
+	if wide {
+		u, vs = vs, u
+		m, n = n, m
+	}
+
+	k := min(m, n)
+	switch {
+	case kind&SVDFullU != 0:
+		svd.u = extendBasis(u, m)
+	case kind&SVDThinU != 0:
+		svd.u = u.slice(0, m, 0, k)
+	}
+	switch {
+	case kind&SVDFullV != 0:
+		svd.v = extendBasis(vs, n)
+	case kind&SVDThinV != 0:
+		svd.v = vs.slice(0, n, 0, k)
+	}
+	return true
+}
+
+// jacobiSVD orthogonalizes the columns of the m×n matrix w, m >= n, with
+// one-sided Jacobi rotations, accumulating the rotations into the n×n matrix
+// v. jacobiSVD returns whether the iteration converged.
+func jacobiSVD(w, v cblas128.General) (ok bool) {
+	m, n := w.Rows, w.Cols
+	tol := float64(m) * eps
+	col := func(a cblas128.General, j int) cblas128.Vector {
+		return cblas128.Vector{N: a.Rows, Inc: a.Stride, Data: a.Data[j:]}
+	}
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				wp, wq := col(w, p), col(w, q)
+				alpha := real(cblas128.Dotc(wp, wp))
+				beta := real(cblas128.Dotc(wq, wq))
+				g := cblas128.Dotc(wp, wq)
+				absg := cmplx.Abs(g)
+				if absg == 0 || absg <= tol*math.Sqrt(alpha)*math.Sqrt(beta) {
+					continue
+				}
+				rotated = true
+
+				// Scale column q by a unit phase so that its inner
+				// product with column p is real and the problem is
+				// reduced to a real rotation.
+				phase := cmplx.Conj(g) / complex(absg, 0)
+				zeta := (beta - alpha) / (2 * absg)
+				t := 1 / (math.Abs(zeta) + math.Hypot(1, zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Hypot(1, t)
+				s := c * t
+				rotateColumns(w, p, q, c, s, phase)
+				rotateColumns(v, p, q, c, s, phase)
+			}
+		}
+		if !rotated {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateColumns applies the rotation
+//  [a_p a_q] = [a_p a_q*phase] * [c  s]
+//                                [-s c]
+// to columns p and q of a, where phase has unit modulus.
+func rotateColumns(a cblas128.General, p, q int, c, s float64, phase complex128) {
+	for i := 0; i < a.Rows; i++ {
+		ap := a.Data[i*a.Stride+p]
+		aq := a.Data[i*a.Stride+q] * phase
+		a.Data[i*a.Stride+p] = complex(c, 0)*ap - complex(s, 0)*aq
+		a.Data[i*a.Stride+q] = complex(s, 0)*ap + complex(c, 0)*aq
+	}
+}
+
+// completeBasis replaces the columns of the m×n matrix q from column k onward
+// with orthonormal vectors that are orthogonal to the first k columns, which
+// must already be orthonormal.
+func completeBasis(q *CDense, k int) {
+	m, n := q.Dims()
+	col := func(j int) cblas128.Vector {
+		return cblas128.Vector{N: m, Inc: q.mat.Stride, Data: q.mat.Data[j:]}
+	}
+	cand := make([]complex128, m)
+	cv := cblas128.Vector{N: m, Inc: 1, Data: cand}
+	for j := k; j < n; j++ {
+		// Orthogonalize the standard basis vectors against the current
+		// columns and keep the one with the largest remaining norm.
+		best := -1.0
+		for e := 0; e < m; e++ {
+			zeroC(cand)
+			cand[e] = 1
+			for pass := 0; pass < 2; pass++ {
+				for i := 0; i < j; i++ {
+					cblas128.Axpy(-cblas128.Dotc(col(i), cv), col(i), cv)
+				}
+			}
+			nrm := cblas128.Nrm2(cv)
+			if nrm > best {
+				best = nrm
+				cblas128.Copy(cv, col(j))
+			}
+			if nrm > 0.5 {
+				break
+			}
+		}
+		cblas128.Dscal(1/best, col(j))
+	}
+}
+
+// extendBasis returns an r×r unitary matrix whose leading columns are the
+// orthonormal columns of q.
+func extendBasis(q *CDense, r int) *CDense {
+	m, n := q.Dims()
+	if n == r {
+		return q
+	}
+	ext := NewCDense(m, r, nil)
+	ext.slice(0, m, 0, n).Copy(q)
+	completeBasis(ext, n)
+	return ext
+}
+
+// Kind returns the SVDKind of the decomposition. If no decomposition has been
+// computed, Kind returns -1.
+func (svd *CSVD) Kind() SVDKind {
+	if !svd.succFact() {
+		return -1
+	}
+	return svd.kind
+}
+
+// Rank returns the rank of A based on the count of singular values greater than
+// rcond scaled by the largest singular value.
+// Rank will panic if the receiver does not contain a successful factorization or
+// rcond is negative.
+func (svd *CSVD) Rank(rcond float64) int {
+	if rcond < 0 {
+		panic(badRcond)
+	}
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	s0 := svd.s[0]
+	for i, v := range svd.s {
+		if v <= rcond*s0 {
+			return i
+		}
+	}
+	return len(svd.s)
+}
+
+// Cond returns the 2-norm condition number for the factorized matrix. Cond will
+// panic if the receiver does not contain a successful factorization.
+func (svd *CSVD) Cond() float64 {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	return svd.s[0] / svd.s[len(svd.s)-1]
+}
+
+// Values returns the singular values of the factorized matrix in descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length min(m,n), and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (svd *CSVD) Values(s []float64) []float64 {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if s == nil {
+		s = make([]float64, len(svd.s))
+	}
+	if len(s) != len(svd.s) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(s, svd.s)
+	return s
+}
+
+// UTo extracts the matrix U from the singular value decomposition. The first
+// min(m,n) columns are the left singular vectors and correspond to the singular
+// values as returned from CSVD.Values.
+//
+// If dst is empty, UTo will resize dst to be m×m if the full U was computed
+// and size m×min(m,n) if the thin U was computed. When dst is non-empty, then
+// UTo will panic if dst is not the appropriate size. UTo will also panic if
+// the receiver does not contain a successful factorization, or if U was
+// not computed during factorization.
+func (svd *CSVD) UTo(dst *CDense) {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if svd.u == nil {
+		panic("svd: u not computed during factorization")
+	}
+	dst.reuseAsNonZeroed(svd.u.Dims())
+	dst.Copy(svd.u)
+}
+
+// VTo extracts the matrix V from the singular value decomposition. The first
+// min(m,n) columns are the right singular vectors and correspond to the singular
+// values as returned from CSVD.Values.
+//
+// If dst is empty, VTo will resize dst to be n×n if the full V was computed
+// and size n×min(m,n) if the thin V was computed. When dst is non-empty, then
+// VTo will panic if dst is not the appropriate size. VTo will also panic if
+// the receiver does not contain a successful factorization, or if V was
+// not computed during factorization.
+func (svd *CSVD) VTo(dst *CDense) {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if svd.v == nil {
+		panic("svd: v not computed during factorization")
+	}
+	dst.reuseAsNonZeroed(svd.v.Dims())
+	dst.Copy(svd.v)
+}
@@ -0,0 +1,75 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n, rank int }{
+		{1, 1, 1}, {3, 3, 3}, {5, 3, 3}, {3, 5, 3}, {10, 10, 10},
+		{12, 7, 4}, {7, 12, 2}, {6, 6, 0},
+	} {
+		m, n := test.m, test.n
+		a := NewCDense(m, n, nil)
+		if test.rank > 0 {
+			a = cmul(randomCDense(m, test.rank, rnd), randomCDense(test.rank, n, rnd))
+		}
+		for _, kind := range []SVDKind{SVDThin, SVDFull, SVDNone} {
+			var svd CSVD
+			if !svd.Factorize(a, kind) {
+				t.Fatalf("m=%d n=%d kind=%d: unexpected factorization failure", m, n, kind)
+			}
+			s := svd.Values(nil)
+			if len(s) != min(m, n) {
+				t.Fatalf("m=%d n=%d kind=%d: unexpected number of singular values", m, n, kind)
+			}
+			for i := 1; i < len(s); i++ {
+				if s[i] > s[i-1] {
+					t.Errorf("m=%d n=%d kind=%d: singular values not descending", m, n, kind)
+				}
+			}
+			if s[0] > 0 {
+				if got := svd.Rank(1e-12); got != test.rank {
+					t.Errorf("m=%d n=%d kind=%d: unexpected rank: got %d, want %d", m, n, kind, got, test.rank)
+				}
+			}
+			if kind == SVDNone {
+				if p, _ := panics(func() { svd.UTo(&CDense{}) }); !p {
+					t.Errorf("m=%d n=%d: expected panic for uncomputed U", m, n)
+				}
+				continue
+			}
+
+			var u, v CDense
+			svd.UTo(&u)
+			svd.VTo(&v)
+			ur, uc := u.Dims()
+			vr, vc := v.Dims()
+			wantU, wantV := min(m, n), min(m, n)
+			if kind == SVDFull {
+				wantU, wantV = m, n
+			}
+			if ur != m || uc != wantU || vr != n || vc != wantV {
+				t.Fatalf("m=%d n=%d kind=%d: unexpected dimensions U %d×%d, V %d×%d", m, n, kind, ur, uc, vr, vc)
+			}
+			if !isUnitary(&u, 1e-12) || !isUnitary(&v, 1e-12) {
+				t.Errorf("m=%d n=%d kind=%d: singular vectors not orthonormal", m, n, kind)
+			}
+			sigma := NewCDense(uc, vc, nil)
+			for i, sv := range s {
+				sigma.Set(i, i, complex(sv, 0))
+			}
+			if !CEqualApprox(cmul(cmul(&u, sigma), v.H()), a, 1e-10) {
+				t.Errorf("m=%d n=%d kind=%d: U * Σ * Vᴴ != A", m, n, kind)
+			}
+		}
+	}
+}
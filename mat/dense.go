@@ -573,6 +573,132 @@ func (m *Dense) Augment(a, b Matrix) {
 	w.Copy(b)
 }
 
+// VStack places the rows of the matrices in ms one after another into the
+// receiver, with the rows of ms[0] in the lowest indexed rows. VStack will
+// panic if ms is empty, if the matrices do not all have the same number of
+// columns, if the receiver is one of the inputs or shares backing data with
+// one of them, or if the stacked matrix is not the same shape as a non-empty
+// receiver.
+func (m *Dense) VStack(ms ...Matrix) {
+	if len(ms) == 0 {
+		panic(ErrZeroLength)
+	}
+	var r int
+	_, c := ms[0].Dims()
+	for _, a := range ms {
+		ar, ac := a.Dims()
+		if ac != c || m == a {
+			panic(ErrShape)
+		}
+		r += ar
+	}
+
+	m.reuseAsNonZeroed(r, c)
+	for _, a := range ms {
+		aU, _ := untransposeExtract(a)
+		m.checkOverlapMatrix(aU)
+	}
+
+	var i int
+	for _, a := range ms {
+		ar, _ := a.Dims()
+		m.slice(i, i+ar, 0, c).Copy(a)
+		i += ar
+	}
+}
+
+// HStack places the columns of the matrices in ms one after another into the
+// receiver, with the columns of ms[0] in the lowest indexed columns. HStack
+// will panic if ms is empty, if the matrices do not all have the same number
+// of rows, if the receiver is one of the inputs or shares backing data with
+// one of them, or if the stacked matrix is not the same shape as a non-empty
+// receiver.
+func (m *Dense) HStack(ms ...Matrix) {
+	if len(ms) == 0 {
+		panic(ErrZeroLength)
+	}
+	r, _ := ms[0].Dims()
+	var c int
+	for _, a := range ms {
+		ar, ac := a.Dims()
+		if ar != r || m == a {
+			panic(ErrShape)
+		}
+		c += ac
+	}
+
+	m.reuseAsNonZeroed(r, c)
+	for _, a := range ms {
+		aU, _ := untransposeExtract(a)
+		m.checkOverlapMatrix(aU)
+	}
+
+	var j int
+	for _, a := range ms {
+		_, ac := a.Dims()
+		m.slice(0, r, j, j+ac).Copy(a)
+		j += ac
+	}
+}
+
+// BlockDiag places the matrices in ms along the diagonal of the receiver and
+// sets all other elements to zero. The blocks need not be square. BlockDiag
+// will panic if ms is empty, if the receiver is one of the inputs or shares
+// backing data with one of them, or if the block diagonal matrix is not the
+// same shape as a non-empty receiver.
+func (m *Dense) BlockDiag(ms ...Matrix) {
+	if len(ms) == 0 {
+		panic(ErrZeroLength)
+	}
+	var r, c int
+	for _, a := range ms {
+		if m == a {
+			panic(ErrShape)
+		}
+		ar, ac := a.Dims()
+		r += ar
+		c += ac
+	}
+
+	m.reuseAsZeroed(r, c)
+	for _, a := range ms {
+		aU, _ := untransposeExtract(a)
+		m.checkOverlapMatrix(aU)
+	}
+
+	var i, j int
+	for _, a := range ms {
+		ar, ac := a.Dims()
+		m.slice(i, i+ar, j, j+ac).Copy(a)
+		i += ar
+		j += ac
+	}
+}
+
+// VStack returns a new matrix holding the rows of the matrices in ms placed
+// one after another. See Dense.VStack for details.
+func VStack(ms ...Matrix) *Dense {
+	var m Dense
+	m.VStack(ms...)
+	return &m
+}
+
+// HStack returns a new matrix holding the columns of the matrices in ms placed
+// one after another. See Dense.HStack for details.
+func HStack(ms ...Matrix) *Dense {
+	var m Dense
+	m.HStack(ms...)
+	return &m
+}
+
+// BlockDiag returns a new block diagonal matrix with the matrices in ms along
+// its diagonal. See Dense.BlockDiag for details.
+func BlockDiag(ms ...Matrix) *Dense {
+	var m Dense
+	m.BlockDiag(ms...)
+	return &m
+}
+
 // Trace returns the trace of the matrix.
 //
 // Trace will panic with ErrSquare if the matrix is not square and with
@@ -0,0 +1,385 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+var (
+	dense32 *Dense32
+
+	_ Matrix  = dense32
+	_ Mutable = dense32
+	_ Reseter = dense32
+)
+
+// Dense32 is a dense matrix representation with single precision storage.
+//
+// Dense32 implements the Matrix interface so that it can be used with
+// the float64 API of the package. Values returned by At are exactly
+// representable float64 values, and values passed to Set are rounded
+// to the nearest float32.
+type Dense32 struct {
+	mat blas32.General
+
+	capRows, capCols int
+}
+
+// NewDense32 creates a new Dense32 matrix with r rows and c columns. If
+// data == nil, a new slice is allocated for the backing slice. If
+// len(data) == r*c, data is used as the backing slice, and changes to the
+// elements of the returned Dense32 will be reflected in data. If neither of
+// these is true, NewDense32 will panic. NewDense32 will panic if either r or
+// c is zero.
+//
+// The data must be arranged in row-major order, i.e. the (i*c + j)-th
+// element in the data slice is the {i, j}-th element in the matrix.
+func NewDense32(r, c int, data []float32) *Dense32 {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if data != nil && r*c != len(data) {
+		panic(ErrShape)
+	}
+	if data == nil {
+		data = make([]float32, r*c)
+	}
+	return &Dense32{
+		mat: blas32.General{
+			Rows:   r,
+			Cols:   c,
+			Stride: c,
+			Data:   data,
+		},
+		capRows: r,
+		capCols: c,
+	}
+}
+
+// reuseAsNonZeroed resizes an empty matrix to a r×c matrix,
+// or checks that a non-empty matrix is r×c. It does not zero
+// the data in the receiver.
+func (m *Dense32) reuseAsNonZeroed(r, c int) {
+	if r == 0 || c == 0 {
+		panic(ErrZeroLength)
+	}
+	if m.IsEmpty() {
+		m.mat = blas32.General{
+			Rows:   r,
+			Cols:   c,
+			Stride: c,
+			Data:   use32(m.mat.Data, r*c),
+		}
+		m.capRows = r
+		m.capCols = c
+		return
+	}
+	if r != m.mat.Rows || c != m.mat.Cols {
+		panic(ErrShape)
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *Dense32) Dims() (r, c int) { return m.mat.Rows, m.mat.Cols }
+
+// Caps returns the number of rows and columns in the backing matrix.
+func (m *Dense32) Caps() (r, c int) { return m.capRows, m.capCols }
+
+// At returns the element at row i, column j.
+func (m *Dense32) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.Rows) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.Cols) {
+		panic(ErrColAccess)
+	}
+	return float64(m.mat.Data[i*m.mat.Stride+j])
+}
+
+// Set sets the element at row i, column j to the value v rounded to
+// the nearest float32.
+func (m *Dense32) Set(i, j int, v float64) {
+	if uint(i) >= uint(m.mat.Rows) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.Cols) {
+		panic(ErrColAccess)
+	}
+	m.mat.Data[i*m.mat.Stride+j] = float32(v)
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *Dense32) T() Matrix {
+	return Transpose{m}
+}
+
+// RawMatrix returns the underlying blas32.General used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in returned blas32.General.
+func (m *Dense32) RawMatrix() blas32.General { return m.mat }
+
+// SetRawMatrix sets the underlying blas32.General used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in b.
+func (m *Dense32) SetRawMatrix(b blas32.General) {
+	m.capRows, m.capCols = b.Rows, b.Cols
+	m.mat = b
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *Dense32) IsEmpty() bool {
+	// It must be the case that m.Dims() returns
+	// zeros in this case. See comment in Reset().
+	return m.mat.Stride == 0
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// Reset should not be used when the matrix shares backing data.
+// See the Reseter interface for more information.
+func (m *Dense32) Reset() {
+	// Row, Cols and Stride must be zeroed in unison.
+	m.mat.Rows, m.mat.Cols, m.mat.Stride = 0, 0, 0
+	m.capRows, m.capCols = 0, 0
+	m.mat.Data = m.mat.Data[:0]
+}
+
+// Zero sets all of the matrix elements to zero.
+func (m *Dense32) Zero() {
+	r := m.mat.Rows
+	c := m.mat.Cols
+	for i := 0; i < r; i++ {
+		zero32(m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c])
+	}
+}
+
+// Copy makes a copy of elements of a into the receiver. The values are
+// rounded to the nearest float32. Copy is equivalent to the Copy method
+// of Dense.
+//
+// See the Copier interface for more information.
+func (m *Dense32) Copy(a Matrix) (r, c int) {
+	r, c = a.Dims()
+	if a == m {
+		return r, c
+	}
+	r = min(r, m.mat.Rows)
+	c = min(c, m.mat.Cols)
+	if r == 0 || c == 0 {
+		return 0, 0
+	}
+
+	aU, trans := untranspose(a)
+	if am, ok := aU.(*Dense32); ok {
+		amat := am.mat
+		if trans {
+			if am == m {
+				// Transposing in place would overwrite
+				// elements before they are read.
+				w := NewDense32(r, c, nil)
+				w.Copy(a)
+				return m.Copy(w)
+			}
+			if amat.Stride != 1 {
+				m.checkOverlap(amat)
+			}
+			for i := 0; i < r; i++ {
+				for j := 0; j < c; j++ {
+					m.mat.Data[i*m.mat.Stride+j] = amat.Data[j*amat.Stride+i]
+				}
+			}
+			return r, c
+		}
+		switch o := offset32(m.mat.Data, amat.Data); {
+		case o < 0:
+			for i := r - 1; i >= 0; i-- {
+				copy(m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c], amat.Data[i*amat.Stride:i*amat.Stride+c])
+			}
+		case o > 0:
+			for i := 0; i < r; i++ {
+				copy(m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c], amat.Data[i*amat.Stride:i*amat.Stride+c])
+			}
+		default:
+			// Nothing to do.
+		}
+		return r, c
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.mat.Data[i*m.mat.Stride+j] = float32(a.At(i, j))
+		}
+	}
+	return r, c
+}
+
+// asDense32 returns a as a *Dense32 and whether it is transposed. If a is
+// not backed by a *Dense32, a rounded copy of its untransposed matrix is
+// returned.
+func asDense32(a Matrix) (*Dense32, bool) {
+	aU, trans := untranspose(a)
+	if am, ok := aU.(*Dense32); ok {
+		return am, trans
+	}
+	if av, ok := aU.(*VecDense32); ok {
+		return av.asDense32(), trans
+	}
+	r, c := aU.Dims()
+	am := NewDense32(r, c, nil)
+	am.Copy(aU)
+	return am, trans
+}
+
+// Mul takes the matrix product of a and b, placing the result in the receiver.
+// If the number of columns in a does not equal the number of rows in b, Mul
+// will panic.
+//
+// The product is computed using single precision BLAS routines. Inputs that
+// are not backed by a Dense32 are first rounded to single precision.
+func (m *Dense32) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+
+	aU, aTrans := asDense32(a)
+	bU, bTrans := asDense32(b)
+	m.reuseAsNonZeroed(ar, bc)
+
+	aT := blas.NoTrans
+	if aTrans {
+		aT = blas.Trans
+	}
+	bT := blas.NoTrans
+	if bTrans {
+		bT = blas.Trans
+	}
+
+	dst := m
+	if m == aU || m == bU {
+		dst = NewDense32(ar, bc, nil)
+	} else {
+		m.checkOverlap(aU.mat)
+		m.checkOverlap(bU.mat)
+	}
+	blas32.Gemm(aT, bT, 1, aU.mat, bU.mat, 0, dst.mat)
+	if dst != m {
+		m.Copy(dst)
+	}
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add
+// will panic if the two matrices do not have the same shape.
+func (m *Dense32) Add(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	m.reuseAsNonZeroed(ar, ac)
+
+	am, aok := a.(*Dense32)
+	bm, bok := b.(*Dense32)
+	if aok && bok {
+		if am != m {
+			m.checkOverlap(am.mat)
+		}
+		if bm != m {
+			m.checkOverlap(bm.mat)
+		}
+		for i := 0; i < ar; i++ {
+			ad := am.mat.Data[i*am.mat.Stride : i*am.mat.Stride+ac]
+			bd := bm.mat.Data[i*bm.mat.Stride : i*bm.mat.Stride+ac]
+			md := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+ac]
+			for j, v := range ad {
+				md[j] = v + bd[j]
+			}
+		}
+		return
+	}
+
+	var restore func()
+	if m.aliasedBy(a) || m.aliasedBy(b) {
+		m, restore = m.isolatedWorkspace()
+		defer restore()
+	}
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			m.mat.Data[i*m.mat.Stride+j] = float32(a.At(i, j) + b.At(i, j))
+		}
+	}
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+//
+// See the Scaler interface for more information.
+func (m *Dense32) Scale(f float32, a Matrix) {
+	ar, ac := a.Dims()
+	m.reuseAsNonZeroed(ar, ac)
+
+	if am, ok := a.(*Dense32); ok {
+		if am != m {
+			m.checkOverlap(am.mat)
+		}
+		for i := 0; i < ar; i++ {
+			ad := am.mat.Data[i*am.mat.Stride : i*am.mat.Stride+ac]
+			md := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+ac]
+			for j, v := range ad {
+				md[j] = f * v
+			}
+		}
+		return
+	}
+
+	var restore func()
+	if m.aliasedBy(a) {
+		m, restore = m.isolatedWorkspace()
+		defer restore()
+	}
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			m.mat.Data[i*m.mat.Stride+j] = f * float32(a.At(i, j))
+		}
+	}
+}
+
+// aliasedBy returns whether a is a transpose of the receiver.
+func (m *Dense32) aliasedBy(a Matrix) bool {
+	aU, trans := untranspose(a)
+	return trans && aU == m
+}
+
+// isolatedWorkspace returns a new matrix w with the size of the receiver
+// and a restore function that copies w back into the receiver.
+func (m *Dense32) isolatedWorkspace() (w *Dense32, restore func()) {
+	w = NewDense32(m.mat.Rows, m.mat.Cols, nil)
+	return w, func() {
+		m.Copy(w)
+	}
+}
+
+// use32 returns a float32 slice with l elements, using f if it
+// has the necessary capacity, otherwise creating a new slice.
+func use32(f []float32, l int) []float32 {
+	if l <= cap(f) {
+		return f[:l]
+	}
+	return make([]float32, l)
+}
+
+// zero32 zeros the given slice's elements.
+func zero32(f []float32) {
+	for i := range f {
+		f[i] = 0
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+func randDense32(rnd *rand.Rand, r, c int) (*Dense32, *Dense) {
+	m32 := NewDense32(r, c, nil)
+	m64 := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := float32(rnd.NormFloat64())
+			m32.Set(i, j, float64(v))
+			m64.Set(i, j, float64(v))
+		}
+	}
+	return m32, m64
+}
+
+func TestDense32Mul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, k, n int
+	}{
+		{1, 1, 1},
+		{3, 4, 5},
+		{10, 7, 3},
+		{20, 20, 20},
+	} {
+		for _, aTrans := range []bool{false, true} {
+			for _, bTrans := range []bool{false, true} {
+				a32, a64 := randDense32(rnd, test.m, test.k)
+				b32, b64 := randDense32(rnd, test.k, test.n)
+				var a, b Matrix = a32, b32
+				var wa, wb Matrix = a64, b64
+				if aTrans {
+					a32, a64 = randDense32(rnd, test.k, test.m)
+					a, wa = a32.T(), a64.T()
+				}
+				if bTrans {
+					b32, b64 = randDense32(rnd, test.n, test.k)
+					b, wb = b32.T(), b64.T()
+				}
+				var got Dense32
+				got.Mul(a, b)
+				var want Dense
+				want.Mul(wa, wb)
+				if !EqualApprox(&got, &want, 1e-4) {
+					t.Errorf("unexpected result for %d×%d×%d aTrans=%t bTrans=%t:\ngot:\n%v\nwant:\n%v",
+						test.m, test.k, test.n, aTrans, bTrans, Formatted(&got), Formatted(&want))
+				}
+
+				// Mixed precision input.
+				got.Mul(wa, b)
+				if !EqualApprox(&got, &want, 1e-4) {
+					t.Errorf("unexpected result for mixed input %d×%d×%d aTrans=%t bTrans=%t",
+						test.m, test.k, test.n, aTrans, bTrans)
+				}
+			}
+		}
+	}
+}
+
+func TestDense32MulAliased(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a32, a64 := randDense32(rnd, 5, 5)
+	var want Dense
+	want.Mul(a64, a64.T())
+	a32.Mul(a32, a32.T())
+	if !EqualApprox(a32, &want, 1e-4) {
+		t.Errorf("unexpected result for aliased Mul:\ngot:\n%v\nwant:\n%v", Formatted(a32), Formatted(&want))
+	}
+}
+
+func TestDense32Overlap(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a32, _ := randDense32(rnd, 6, 6)
+	raw := a32.RawMatrix()
+	view := func(i int) *Dense32 {
+		var m Dense32
+		m.SetRawMatrix(blas32.General{Rows: 3, Cols: 3, Stride: raw.Stride, Data: raw.Data[i*(raw.Stride+1):]})
+		return &m
+	}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "Mul", fn: func() { view(1).Mul(view(0), view(0)) }},
+		{name: "Add", fn: func() { view(1).Add(view(0), view(2)) }},
+		{name: "Scale", fn: func() { view(1).Scale(2, view(0)) }},
+	} {
+		_, msg := panics(test.fn)
+		if msg != regionOverlap {
+			t.Errorf("unexpected panic for overlapping %s: got:%v want:%v", test.name, msg, regionOverlap)
+		}
+	}
+}
+
+func TestDense32AddScale(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a32, a64 := randDense32(rnd, 4, 6)
+	b32, b64 := randDense32(rnd, 4, 6)
+
+	var got Dense32
+	got.Add(a32, b32)
+	var want Dense
+	want.Add(a64, b64)
+	if !EqualApprox(&got, &want, 1e-6) {
+		t.Errorf("unexpected result for Add:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+	}
+	got.Add(&got, b64)
+	want.Add(&want, b64)
+	if !EqualApprox(&got, &want, 1e-6) {
+		t.Errorf("unexpected result for mixed Add:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+	}
+
+	got.Scale(2, &got)
+	want.Scale(2, &want)
+	if !EqualApprox(&got, &want, 1e-6) {
+		t.Errorf("unexpected result for Scale:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+	}
+
+	sq32, sq64 := randDense32(rnd, 5, 5)
+	sq32.Add(sq32, sq32.T())
+	want.Reset()
+	want.Add(sq64, sq64.T())
+	if !EqualApprox(sq32, &want, 1e-6) {
+		t.Errorf("unexpected result for transpose aliased Add:\ngot:\n%v\nwant:\n%v", Formatted(sq32), Formatted(&want))
+	}
+}
+
+func TestDense32Copy(t *testing.T) {
+	t.Parallel()
+	a := NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	var m Dense32
+	m.reuseAsNonZeroed(3, 2)
+	m.Copy(a.T())
+	want := []float32{1, 4, 2, 5, 3, 6}
+	for i, v := range m.RawMatrix().Data {
+		if v != want[i] {
+			t.Errorf("unexpected value at %d: got:%v want:%v", i, v, want[i])
+		}
+	}
+
+	sq32, sq64 := randDense32(rand.New(rand.NewSource(1)), 4, 4)
+	sq32.Copy(sq32.T())
+	if !EqualApprox(sq32, sq64.T(), 0) {
+		t.Errorf("unexpected result for self-transpose Copy:\ngot:\n%v\nwant:\n%v", Formatted(sq32), Formatted(sq64.T()))
+	}
+
+	var back Dense
+	back.CloneFrom(m.T())
+	if !Equal(&back, a) {
+		t.Errorf("unexpected round trip result:\ngot:\n%v\nwant:\n%v", Formatted(&back), Formatted(a))
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/internal/asm/f64"
 	"gonum.org/v1/gonum/lapack/lapack64"
 )
 
@@ -273,8 +274,37 @@ func (m *Dense) Inverse(a Matrix) error {
 	return nil
 }
 
+// PseudoInverse computes the Moore–Penrose pseudo-inverse of the m×n matrix a
+// using its singular value decomposition, placing the n×m result in the
+// receiver. Singular values less than or equal to rcond scaled by the largest
+// singular value are treated as zero. A common choice for rcond is max(m,n)
+// times machine epsilon. PseudoInverse will panic if rcond is negative.
+//
+// If the singular value decomposition of a fails, PseudoInverse returns a
+// non-nil error and the receiver is not modified.
+func (m *Dense) PseudoInverse(a Matrix, rcond float64) error {
+	if rcond < 0 {
+		panic(badRcond)
+	}
+	var svd SVD
+	ok := svd.Factorize(a, SVDThin)
+	if !ok {
+		return ErrFailedConvergence
+	}
+	svd.PseudoInverseTo(m, rcond)
+	return nil
+}
+
 // Mul takes the matrix product of a and b, placing the result in the receiver.
 // If the number of columns in a does not equal the number of rows in b, Mul will panic.
+//
+// When a and b are both general dense matrices the product is computed by
+// the blas64 Gemm routine. The default Gonum BLAS implementation of Gemm
+// partitions large products into blocks that are computed concurrently
+// by up to runtime.GOMAXPROCS(0) goroutines, so the degree of parallelism
+// can be limited by setting GOMAXPROCS. The partitioning does not change the
+// order of summation for any element, so the result is reproducible. The
+// package-wide Parallelism settings do not apply to Mul.
 func (m *Dense) Mul(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -680,6 +710,325 @@ func (m *Dense) Pow(a Matrix, n int) {
 	putDenseWorkspace(x)
 }
 
+// Sqrt calculates the principal square root of the matrix a, placing the
+// result in the receiver. The principal square root is the unique square root
+// whose eigenvalues have positive real part. It exists when a has no
+// eigenvalues on the closed negative real axis. Sqrt will panic with ErrShape
+// if a is not square.
+//
+// If the iteration used to compute the square root fails to converge,
+// for example because a has eigenvalues on the negative real axis or is
+// singular, Sqrt returns a non-nil error and the contents of the receiver
+// are undefined.
+func (m *Dense) Sqrt(a Matrix) error {
+	// The implementation used here is the scaled Denman–Beavers iteration
+	// from Functions of Matrices: Theory and Computation, Chapter 6,
+	// Equation 6.28. https://doi.org/10.1137/1.9780898717778.ch6
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+
+	if r == 1 {
+		v := a.At(0, 0)
+		if v < 0 {
+			return ErrFailedConvergence
+		}
+		m.reuseAsNonZeroed(1, 1)
+		m.mat.Data[0] = math.Sqrt(v)
+		return nil
+	}
+
+	y := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(y)
+	y.Copy(a)
+	z := getDenseWorkspace(r, r, true)
+	defer putDenseWorkspace(z)
+	for i := 0; i < r; i++ {
+		z.mat.Data[i*z.mat.Stride+i] = 1
+	}
+	eye := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(eye)
+	eye.Copy(z)
+	yInv := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(yInv)
+	zInv := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(zInv)
+	prev := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(prev)
+
+	const (
+		maxIter = 100
+		tol     = 1e-14
+	)
+	var lu LU
+	scale := true
+	for k := 0; k < maxIter; k++ {
+		lu.Factorize(y)
+		if lu.isZero() || lu.Det() == 0 {
+			return ErrFailedConvergence
+		}
+		ldy, _ := lu.LogDet()
+		// A Condition error only warns of ill-conditioning,
+		// which the iteration tolerates as Sign does.
+		err := lu.SolveTo(yInv, false, eye)
+		if err != nil {
+			if _, ok := err.(Condition); !ok {
+				return err
+			}
+		}
+		lu.Factorize(z)
+		if lu.Det() == 0 {
+			return ErrFailedConvergence
+		}
+		ldz, _ := lu.LogDet()
+		err = lu.SolveTo(zInv, false, eye)
+		if err != nil {
+			if _, ok := err.(Condition); !ok {
+				return err
+			}
+		}
+
+		mu := 1.0
+		if scale {
+			mu = math.Exp(-(ldy + ldz) / float64(2*r))
+		}
+
+		prev.Copy(y)
+		y.Scale(mu, y)
+		y.addScaled(y, 1/mu, zInv)
+		y.Scale(0.5, y)
+		z.Scale(mu, z)
+		z.addScaled(z, 1/mu, yInv)
+		z.Scale(0.5, z)
+
+		prev.Sub(y, prev)
+		diff := prev.Norm(1)
+		norm := y.Norm(1)
+		if math.IsNaN(diff) || math.IsInf(diff, 0) {
+			return ErrFailedConvergence
+		}
+		if diff <= tol*norm {
+			m.reuseAsNonZeroed(r, r)
+			m.Copy(y)
+			return nil
+		}
+		if diff <= 1e-2*norm {
+			// Scaling is unnecessary close to convergence
+			// and may slow the final quadratic convergence.
+			scale = false
+		}
+	}
+	return ErrFailedConvergence
+}
+
+// Log calculates the principal logarithm of the matrix a, placing the result
+// in the receiver. The principal logarithm is the unique logarithm whose
+// eigenvalues have imaginary parts in (-π, π). It exists when a has no
+// eigenvalues on the closed negative real axis. Log will panic with ErrShape
+// if a is not square.
+//
+// If the computation of the logarithm fails, for example because a has
+// eigenvalues on the negative real axis or is singular, Log returns a
+// non-nil error and the contents of the receiver are undefined.
+func (m *Dense) Log(a Matrix) error {
+	// The implementation used here is the inverse scaling and squaring
+	// method from Functions of Matrices: Theory and Computation, Chapter 11,
+	// Section 11.5, using an 8 point Gauss–Legendre quadrature evaluation
+	// of the [8/8] Padé approximant to log(I+X) (Equation 11.18).
+	// https://doi.org/10.1137/1.9780898717778.ch11
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+
+	if r == 1 {
+		v := a.At(0, 0)
+		if v <= 0 {
+			return ErrFailedConvergence
+		}
+		m.reuseAsNonZeroed(1, 1)
+		m.mat.Data[0] = math.Log(v)
+		return nil
+	}
+
+	x := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(x)
+	x.Copy(a)
+
+	// Take square roots until X is close to the identity.
+	const (
+		theta   = 0.25
+		maxRoot = 64
+	)
+	var s int
+	for {
+		for i := 0; i < r; i++ {
+			x.mat.Data[i*x.mat.Stride+i]--
+		}
+		if x.Norm(1) <= theta {
+			break
+		}
+		if s == maxRoot {
+			return ErrFailedConvergence
+		}
+		for i := 0; i < r; i++ {
+			x.mat.Data[i*x.mat.Stride+i]++
+		}
+		err := x.Sqrt(x)
+		if err != nil {
+			return err
+		}
+		s++
+	}
+
+	// Gauss–Legendre nodes and weights on [0, 1].
+	nodes := [...]float64{
+		0.0198550717512319, 0.1016667612931866, 0.2372337950418355, 0.4082826787521751,
+		0.5917173212478249, 0.7627662049581645, 0.8983332387068134, 0.9801449282487681,
+	}
+	weights := [...]float64{
+		0.0506142681451881, 0.1111905172266872, 0.1568533229389436, 0.1813418916891810,
+		0.1813418916891810, 0.1568533229389436, 0.1111905172266872, 0.0506142681451881,
+	}
+
+	// log(I+X) ≈ Σ w_j X (I + t_j X)⁻¹
+	d := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(d)
+	q := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(q)
+	sum := getDenseWorkspace(r, r, true)
+	defer putDenseWorkspace(sum)
+	for j, t := range nodes {
+		d.Scale(t, x)
+		for i := 0; i < r; i++ {
+			d.mat.Data[i*d.mat.Stride+i]++
+		}
+		// Solve (I + t X)ᵀ Qᵀ = Xᵀ so that Q = X (I + t X)⁻¹.
+		err := q.Solve(d.T(), x.T())
+		if err != nil {
+			if _, ok := err.(Condition); !ok {
+				return err
+			}
+		}
+		sum.addScaled(sum, weights[j], q.T())
+	}
+
+	m.reuseAsNonZeroed(r, r)
+	m.Scale(math.Ldexp(1, s), sum)
+	return nil
+}
+
+// Sign calculates the matrix sign function of a, placing the result in the
+// receiver. If a = V * J * V⁻¹ is the Jordan decomposition of a, then
+//  sign(a) = V * sign(J) * V⁻¹,
+// where the eigenvalues of a with positive real part are mapped to 1 and
+// those with negative real part to -1. The matrices (I ± sign(a))/2 are the
+// spectral projectors onto the invariant subspaces of a associated with the
+// eigenvalues in the right and left half planes. The sign function exists
+// when a has no eigenvalues on the imaginary axis. Sign will panic with
+// ErrShape if a is not square.
+//
+// If the iteration used to compute the sign function fails to converge,
+// for example because a has eigenvalues on or close to the imaginary axis,
+// Sign returns a non-nil error and the contents of the receiver are
+// undefined.
+func (m *Dense) Sign(a Matrix) error {
+	// The implementation used here is the Newton iteration with
+	// determinantal scaling from Functions of Matrices: Theory and
+	// Computation, Chapter 5, Equations 5.34 and 5.35.
+	// https://doi.org/10.1137/1.9780898717778.ch5
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+
+	if r == 1 {
+		v := a.At(0, 0)
+		if v == 0 || math.IsNaN(v) {
+			return ErrFailedConvergence
+		}
+		m.reuseAsNonZeroed(1, 1)
+		m.mat.Data[0] = math.Copysign(1, v)
+		return nil
+	}
+
+	x := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(x)
+	x.Copy(a)
+	xInv := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(xInv)
+	prev := getDenseWorkspace(r, r, false)
+	defer putDenseWorkspace(prev)
+	eye := getDenseWorkspace(r, r, true)
+	defer putDenseWorkspace(eye)
+	for i := 0; i < r; i++ {
+		eye.mat.Data[i*eye.mat.Stride+i] = 1
+	}
+
+	const (
+		maxIter = 100
+		tol     = 1e-14
+	)
+	var lu LU
+	scale := true
+	for k := 0; k < maxIter; k++ {
+		lu.Factorize(x)
+		if lu.isZero() || lu.Det() == 0 {
+			return ErrFailedConvergence
+		}
+		ld, _ := lu.LogDet()
+		err := lu.SolveTo(xInv, false, eye)
+		if err != nil {
+			if _, ok := err.(Condition); !ok {
+				return err
+			}
+		}
+
+		mu := 1.0
+		if scale {
+			mu = math.Exp(-ld / float64(r))
+		}
+
+		// X ← (μ X + μ⁻¹ X⁻¹) / 2
+		prev.Copy(x)
+		x.Scale(mu, x)
+		x.addScaled(x, 1/mu, xInv)
+		x.Scale(0.5, x)
+
+		prev.Sub(x, prev)
+		diff := prev.Norm(1)
+		norm := x.Norm(1)
+		if math.IsNaN(diff) || math.IsInf(diff, 0) {
+			return ErrFailedConvergence
+		}
+		if diff <= tol*norm {
+			m.reuseAsNonZeroed(r, r)
+			m.Copy(x)
+			return nil
+		}
+		if diff <= 1e-2*norm {
+			// Scaling is unnecessary close to convergence
+			// and may slow the final quadratic convergence.
+			scale = false
+		}
+	}
+	return ErrFailedConvergence
+}
+
+// addScaled computes m = a + alpha*b.
+func (m *Dense) addScaled(a Matrix, alpha float64, b Matrix) {
+	r, c := a.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.set(i, j, a.At(i, j)+alpha*b.At(i, j))
+		}
+	}
+}
+
 // Kronecker calculates the Kronecker product of a and b, placing the result in
 // the receiver.
 func (m *Dense) Kronecker(a, b Matrix) {
@@ -774,6 +1123,70 @@ func (m *Dense) Apply(fn func(i, j int, v float64) float64, a Matrix) {
 	}
 }
 
+// minParallelApply is the number of elements below which ApplyParallel
+// applies fn serially.
+const minParallelApply = 1 << 14
+
+// ApplyParallel applies the function fn to each of the elements of a, placing
+// the resulting matrix in the receiver, as Apply does, but partitions the rows
+// of the result across up to workers goroutines. If workers is not positive,
+// the Workers value of the package-wide Parallelism settings is used. Matrices
+// with fewer than the MinSize elements given by the Parallelism settings, or
+// 16384 elements if no MinSize is set, are processed serially.
+//
+// fn is called concurrently and must be safe for concurrent use, as must the
+// At method of a if a is not a *Dense or the transpose of a *Dense. The order
+// in which fn is called for the elements of a is unspecified.
+func (m *Dense) ApplyParallel(fn func(i, j int, v float64) float64, a Matrix, workers int) {
+	ar, ac := a.Dims()
+	workers = parallelWorkers(CurrentParallelism(), workers, ar, ar*ac, minParallelApply)
+	if workers <= 1 {
+		m.Apply(fn, a)
+		return
+	}
+
+	m.reuseAsNonZeroed(ar, ac)
+
+	aU, aTrans := untransposeExtract(a)
+	rm, isDense := aU.(*Dense)
+	if isDense {
+		if m == aU || m.checkOverlap(rm.mat) {
+			var restore func()
+			m, restore = m.isolatedWorkspace(a)
+			defer restore()
+		}
+	} else {
+		m.checkOverlapMatrix(a)
+	}
+
+	parallelRanges(ar, workers, workers, func(_, start, end int) {
+		switch {
+		case isDense && !aTrans:
+			amat := rm.mat
+			for r := start; r < end; r++ {
+				row := m.mat.Data[r*m.mat.Stride : r*m.mat.Stride+ac]
+				for c, v := range amat.Data[r*amat.Stride : r*amat.Stride+ac] {
+					row[c] = fn(r, c, v)
+				}
+			}
+		case isDense:
+			amat := rm.mat
+			for r := start; r < end; r++ {
+				row := m.mat.Data[r*m.mat.Stride : r*m.mat.Stride+ac]
+				for c := range row {
+					row[c] = fn(r, c, amat.Data[c*amat.Stride+r])
+				}
+			}
+		default:
+			for r := start; r < end; r++ {
+				for c := 0; c < ac; c++ {
+					m.set(r, c, fn(r, c, a.At(r, c)))
+				}
+			}
+		}
+	})
+}
+
 // RankOne performs a rank-one update to the matrix a with the vectors x and
 // y, where x and y are treated as column vectors. The result is stored in the
 // receiver. The Outer method can be used instead of RankOne if a is not needed.
@@ -872,3 +1285,140 @@ func (m *Dense) Outer(alpha float64, x, y Vector) {
 		}
 	}
 }
+
+// AddRowVec adds alpha times the vector v to each row of a, placing the
+// result in the receiver. AddRowVec will panic if the length of v does not
+// equal the number of columns in a.
+//  m[i, j] = a[i, j] + alpha * v[j]
+// Setting alpha to -1 and v to the column means of a centers the columns of a.
+func (m *Dense) AddRowVec(a Matrix, alpha float64, v Vector) {
+	_, ac := a.Dims()
+	if v.Len() != ac {
+		panic(ErrShape)
+	}
+	m, restore := m.broadcastWorkspace(a, v)
+	if restore != nil {
+		defer restore()
+	}
+
+	r, c := m.Dims()
+	if rv, ok := v.(*VecDense); ok && rv.mat.Inc == 1 {
+		for i := 0; i < r; i++ {
+			f64.AxpyUnitary(alpha, rv.mat.Data[:c], m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c])
+		}
+		return
+	}
+	for j := 0; j < c; j++ {
+		av := alpha * v.AtVec(j)
+		for i := 0; i < r; i++ {
+			m.mat.Data[i*m.mat.Stride+j] += av
+		}
+	}
+}
+
+// AddColVec adds alpha times the vector v to each column of a, placing the
+// result in the receiver. AddColVec will panic if the length of v does not
+// equal the number of rows in a.
+//  m[i, j] = a[i, j] + alpha * v[i]
+func (m *Dense) AddColVec(a Matrix, alpha float64, v Vector) {
+	ar, _ := a.Dims()
+	if v.Len() != ar {
+		panic(ErrShape)
+	}
+	m, restore := m.broadcastWorkspace(a, v)
+	if restore != nil {
+		defer restore()
+	}
+
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		av := alpha * v.AtVec(i)
+		for j, e := range m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c] {
+			m.mat.Data[i*m.mat.Stride+j] = e + av
+		}
+	}
+}
+
+// ScaleRows multiplies each row of a by the corresponding element of v,
+// placing the result in the receiver. ScaleRows will panic if the length
+// of v does not equal the number of rows in a.
+//  m[i, j] = v[i] * a[i, j]
+// ScaleRows is equivalent to multiplying a on the left by a diagonal
+// matrix with v on the diagonal.
+func (m *Dense) ScaleRows(v Vector, a Matrix) {
+	ar, _ := a.Dims()
+	if v.Len() != ar {
+		panic(ErrShape)
+	}
+	m, restore := m.broadcastWorkspace(a, v)
+	if restore != nil {
+		defer restore()
+	}
+
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		f64.ScalUnitary(v.AtVec(i), m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c])
+	}
+}
+
+// ScaleCols multiplies each column of a by the corresponding element of v,
+// placing the result in the receiver. ScaleCols will panic if the length
+// of v does not equal the number of columns in a.
+//  m[i, j] = v[j] * a[i, j]
+// ScaleCols is equivalent to multiplying a on the right by a diagonal
+// matrix with v on the diagonal.
+func (m *Dense) ScaleCols(v Vector, a Matrix) {
+	_, ac := a.Dims()
+	if v.Len() != ac {
+		panic(ErrShape)
+	}
+	m, restore := m.broadcastWorkspace(a, v)
+	if restore != nil {
+		defer restore()
+	}
+
+	r, c := m.Dims()
+	if rv, ok := v.(*VecDense); ok && rv.mat.Inc == 1 {
+		for i := 0; i < r; i++ {
+			row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+			for j, s := range rv.mat.Data[:c] {
+				row[j] *= s
+			}
+		}
+		return
+	}
+	for j := 0; j < c; j++ {
+		s := v.AtVec(j)
+		for i := 0; i < r; i++ {
+			m.mat.Data[i*m.mat.Stride+j] *= s
+		}
+	}
+}
+
+// broadcastWorkspace prepares the receiver to hold the result of a
+// broadcast operation of v over a. It copies a into the returned matrix,
+// which is either the receiver or, when a is a transpose of the receiver,
+// an isolated workspace. The restore function must be called when it is
+// not nil.
+func (m *Dense) broadcastWorkspace(a Matrix, v Vector) (w *Dense, restore func()) {
+	ar, ac := a.Dims()
+	m.reuseAsNonZeroed(ar, ac)
+
+	vU, _ := untransposeExtract(v)
+	if rv, ok := vU.(*VecDense); ok {
+		r, c := vU.Dims()
+		m.checkOverlap(generalFromVector(rv.mat, r, c))
+	}
+
+	aU, trans := untransposeExtract(a)
+	if trans && m == aU {
+		w, restore = m.isolatedWorkspace(a)
+		w.Copy(a)
+		return w, restore
+	}
+	if m != a {
+		m.checkOverlapMatrix(aU)
+		m.Copy(a)
+	}
+	return m, nil
+}
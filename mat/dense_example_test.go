@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"log"
 
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/blas/gonum"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -267,3 +270,39 @@ func ExampleDense_Scale() {
 	// m = ⎡1.000  1.000⎤
 	//     ⎣1.000  1.000⎦
 }
+
+// offloadBLAS is a BLAS implementation that counts the matrix products it
+// computes. An accelerator backend would dispatch Dgemm to the device here.
+type offloadBLAS struct {
+	gonum.Implementation
+	products int
+}
+
+func (b *offloadBLAS) Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a []float64, lda int, bm []float64, ldb int, beta float64, c []float64, ldc int) {
+	b.products++
+	b.Implementation.Dgemm(tA, tB, m, n, k, alpha, a, lda, bm, ldb, beta, c, ldc)
+}
+
+func Example_blasBackend() {
+	// Register a custom BLAS implementation for all subsequent
+	// operations, restoring the original when done.
+	impl := &offloadBLAS{}
+	orig := blas64.Implementation()
+	blas64.Use(impl)
+	defer blas64.Use(orig)
+
+	a := mat.NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	var c mat.Dense
+	c.Mul(a, a.T())
+
+	fmt.Printf("c = %v\n", mat.Formatted(&c, mat.Prefix("    ")))
+	fmt.Println("products offloaded:", impl.products)
+
+	// Output:
+	// c = ⎡14  32⎤
+	//     ⎣32  77⎦
+	// products offloaded: 1
+}
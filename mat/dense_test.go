@@ -1006,6 +1006,213 @@ func TestDenseExp(t *testing.T) {
 	}
 }
 
+func TestDenseSqrtLog(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a        *Dense
+		wantSqrt *Dense
+		wantLog  *Dense
+	}{
+		{
+			a:        NewDense(1, 1, []float64{4}),
+			wantSqrt: NewDense(1, 1, []float64{2}),
+			wantLog:  NewDense(1, 1, []float64{math.Log(4)}),
+		},
+		{
+			a:        NewDense(2, 2, []float64{4, 0, 0, 9}),
+			wantSqrt: NewDense(2, 2, []float64{2, 0, 0, 3}),
+			wantLog:  NewDense(2, 2, []float64{math.Log(4), 0, 0, math.Log(9)}),
+		},
+		{
+			// Upper triangular with closed form square root.
+			a:        NewDense(2, 2, []float64{1, 4, 0, 9}),
+			wantSqrt: NewDense(2, 2, []float64{1, 1, 0, 3}),
+		},
+		{
+			// Rotation by π/2 has logarithm [0 -π/2; π/2 0].
+			a:       NewDense(2, 2, []float64{0, -1, 1, 0}),
+			wantLog: NewDense(2, 2, []float64{0, -math.Pi / 2, math.Pi / 2, 0}),
+		},
+		{a: randomPositiveDense(5, rnd)},
+		{a: randomPositiveDense(10, rnd)},
+		{a: randomPositiveDense(20, rnd)},
+	} {
+		var sqrt Dense
+		err := sqrt.Sqrt(test.a)
+		if err != nil {
+			t.Errorf("unexpected error for Sqrt test %d: %v", i, err)
+			continue
+		}
+		if test.wantSqrt != nil && !EqualApprox(&sqrt, test.wantSqrt, 1e-12) {
+			t.Errorf("unexpected result for Sqrt test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&sqrt), Formatted(test.wantSqrt))
+		}
+		var sq Dense
+		sq.Mul(&sqrt, &sqrt)
+		if !EqualApprox(&sq, test.a, 1e-10) {
+			t.Errorf("unexpected square of Sqrt for test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&sq), Formatted(test.a))
+		}
+
+		var log Dense
+		err = log.Log(test.a)
+		if err != nil {
+			t.Errorf("unexpected error for Log test %d: %v", i, err)
+			continue
+		}
+		if test.wantLog != nil && !EqualApprox(&log, test.wantLog, 1e-12) {
+			t.Errorf("unexpected result for Log test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&log), Formatted(test.wantLog))
+		}
+		var exp Dense
+		exp.Exp(&log)
+		if !EqualApprox(&exp, test.a, 1e-10) {
+			t.Errorf("unexpected exponential of Log for test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&exp), Formatted(test.a))
+		}
+	}
+
+	// Ill-conditioning of the iterates only warns and does
+	// not prevent convergence.
+	var sqrt Dense
+	if err := sqrt.Sqrt(NewDense(2, 2, []float64{4, 1, 0, 1e-18})); err != nil {
+		t.Errorf("unexpected error for Sqrt of near-singular matrix: %v", err)
+	} else if want := NewDense(2, 2, []float64{2, 1 / (2 + 1e-9), 0, 1e-9}); !EqualApprox(&sqrt, want, 1e-12) {
+		t.Errorf("unexpected result for Sqrt of near-singular matrix\ngot:\n%v\nwant:\n%v",
+			Formatted(&sqrt), Formatted(want))
+	}
+
+	// Matrices with eigenvalues on the negative real axis
+	// have no real principal square root or logarithm.
+	for i, a := range []*Dense{
+		NewDense(1, 1, []float64{-1}),
+		NewDense(2, 2, []float64{-1, 0, 0, -4}),
+		NewDense(2, 2, []float64{0, 0, 0, 0}),
+	} {
+		var m Dense
+		if err := m.Sqrt(a); err == nil {
+			t.Errorf("expected error for Sqrt of invalid matrix %d", i)
+		}
+		m.Reset()
+		if err := m.Log(a); err == nil {
+			t.Errorf("expected error for Log of invalid matrix %d", i)
+		}
+	}
+}
+
+func TestDenseSign(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a    *Dense
+		want *Dense
+	}{
+		{
+			a:    NewDense(1, 1, []float64{-3}),
+			want: NewDense(1, 1, []float64{-1}),
+		},
+		{
+			a:    NewDense(2, 2, []float64{4, 0, 0, -9}),
+			want: NewDense(2, 2, []float64{1, 0, 0, -1}),
+		},
+		{
+			// Eigenvalues 1±2i.
+			a:    NewDense(2, 2, []float64{1, -2, 2, 1}),
+			want: NewDense(2, 2, []float64{1, 0, 0, 1}),
+		},
+		{a: randomPositiveDense(5, rnd), want: eye(5)},
+		{a: randomSignDense(6, 2, rnd)},
+		{a: randomSignDense(20, 11, rnd)},
+	} {
+		var sign Dense
+		err := sign.Sign(test.a)
+		if err != nil {
+			t.Errorf("unexpected error for Sign test %d: %v", i, err)
+			continue
+		}
+		if test.want != nil && !EqualApprox(&sign, test.want, 1e-12) {
+			t.Errorf("unexpected result for Sign test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&sign), Formatted(test.want))
+		}
+		n, _ := test.a.Dims()
+		var sq Dense
+		sq.Mul(&sign, &sign)
+		if !EqualApprox(&sq, eye(n), 1e-10) {
+			t.Errorf("square of Sign is not the identity for test %d", i)
+		}
+		var as, sa Dense
+		as.Mul(test.a, &sign)
+		sa.Mul(&sign, test.a)
+		if !EqualApprox(&as, &sa, 1e-10) {
+			t.Errorf("Sign does not commute with matrix for test %d", i)
+		}
+		// The trace of the sign function is the difference between
+		// the numbers of eigenvalues in the right and left half planes.
+		var ed Eigen
+		ed.Factorize(test.a, EigenNone)
+		var want float64
+		for _, v := range ed.Values(nil) {
+			want += math.Copysign(1, real(v))
+		}
+		if got := Trace(&sign); math.Abs(got-want) > 1e-10 {
+			t.Errorf("unexpected trace of Sign for test %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// Ill-conditioning of the iterates only warns and does
+	// not prevent convergence.
+	var sign Dense
+	if err := sign.Sign(NewDense(2, 2, []float64{4, 1, 0, -1e-18})); err != nil {
+		t.Errorf("unexpected error for Sign of near-singular matrix: %v", err)
+	} else if want := NewDense(2, 2, []float64{1, 0.5, 0, -1}); !EqualApprox(&sign, want, 1e-12) {
+		t.Errorf("unexpected result for Sign of near-singular matrix\ngot:\n%v\nwant:\n%v",
+			Formatted(&sign), Formatted(want))
+	}
+
+	for i, a := range []*Dense{
+		NewDense(1, 1, []float64{0}),
+		NewDense(2, 2, []float64{0, -1, 1, 0}),
+	} {
+		var m Dense
+		if err := m.Sign(a); err == nil {
+			t.Errorf("expected error for Sign of matrix %d with imaginary eigenvalues", i)
+		}
+	}
+}
+
+// randomSignDense returns a random n×n matrix with neg eigenvalues in the
+// left half plane and the remaining eigenvalues in the right half plane.
+func randomSignDense(n, neg int, rnd *rand.Rand) *Dense {
+	a := randomDense(n, n, rnd)
+	a.Scale(1/math.Sqrt(float64(n)), a)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			a.Set(i, j, 0)
+		}
+		if i < neg {
+			a.Set(i, i, -1-rnd.Float64())
+		} else {
+			a.Set(i, i, 1+rnd.Float64())
+		}
+	}
+	// Hide the triangular structure by an orthogonal similarity.
+	q := RandomOrthogonal(n, rand.NewSource(rnd.Uint64()))
+	a.Product(q, a, q.T())
+	return a
+}
+
+// randomPositiveDense returns a random n×n matrix with eigenvalues
+// in the right half plane.
+func randomPositiveDense(n int, rnd *rand.Rand) *Dense {
+	a := randomDense(n, n, rnd)
+	a.Scale(1/math.Sqrt(float64(n)), a)
+	for i := 0; i < n; i++ {
+		a.Set(i, i, a.At(i, i)+3)
+	}
+	return a
+}
+
 func TestDensePow(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -1534,6 +1741,38 @@ func TestDenseApply(t *testing.T) {
 	}
 }
 
+func TestDenseApplyParallel(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	fn := func(r, c int, v float64) float64 { return float64(r) - 2*float64(c) + v*v }
+	for _, size := range []struct{ r, c int }{{3, 4}, {200, 150}, {1000, 17}} {
+		a := randomDense(size.r, size.c, rnd)
+		b := randomDense(size.c, size.r, rnd)
+		var sym SymDense
+		sym.SymOuterK(1, a.Slice(0, min(size.r, size.c), 0, size.c))
+		for _, workers := range []int{0, 1, 3, 8} {
+			for _, src := range []Matrix{a, b.T(), &sym} {
+				var want, got Dense
+				want.Apply(fn, src)
+				got.ApplyParallel(fn, src, workers)
+				if !Equal(&got, &want) {
+					r, c := src.Dims()
+					t.Errorf("unexpected result for %d×%d %T with %d workers", r, c, src, workers)
+				}
+			}
+
+			// Check in-place application.
+			var want Dense
+			want.Apply(fn, a)
+			got := DenseCopyOf(a)
+			got.ApplyParallel(fn, got, workers)
+			if !Equal(got, &want) {
+				t.Errorf("unexpected in-place result for %d×%d with %d workers", size.r, size.c, workers)
+			}
+		}
+	}
+}
+
 func TestDenseClone(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -1677,6 +1916,93 @@ func TestDenseAugment(t *testing.T) {
 	testTwoInput(t, "Augment", &Dense{}, method, denseComparison, legalTypesAll, legalSizeSameHeight, 0)
 }
 
+func TestDenseStacking(t *testing.T) {
+	t.Parallel()
+	a := NewDense(2, 2, []float64{1, 2, 3, 4})
+	b := NewDense(1, 2, []float64{5, 6})
+	c := NewDense(2, 1, []float64{7, 8})
+
+	for _, test := range []struct {
+		name string
+		fn   func(ms ...Matrix) *Dense
+		ms   []Matrix
+		want *Dense
+	}{
+		{
+			name: "VStack",
+			fn:   VStack,
+			ms:   []Matrix{a, b, a.T()},
+			want: NewDense(5, 2, []float64{1, 2, 3, 4, 5, 6, 1, 3, 2, 4}),
+		},
+		{
+			name: "VStack single",
+			fn:   VStack,
+			ms:   []Matrix{b},
+			want: NewDense(1, 2, []float64{5, 6}),
+		},
+		{
+			name: "HStack",
+			fn:   HStack,
+			ms:   []Matrix{a, c, b.T()},
+			want: NewDense(2, 4, []float64{1, 2, 7, 5, 3, 4, 8, 6}),
+		},
+		{
+			name: "BlockDiag",
+			fn:   BlockDiag,
+			ms:   []Matrix{a, b, c},
+			want: NewDense(5, 5, []float64{
+				1, 2, 0, 0, 0,
+				3, 4, 0, 0, 0,
+				0, 0, 5, 6, 0,
+				0, 0, 0, 0, 7,
+				0, 0, 0, 0, 8,
+			}),
+		},
+	} {
+		got := test.fn(test.ms...)
+		if !Equal(got, test.want) {
+			t.Errorf("unexpected result for %s:\ngot:\n%v\nwant:\n%v", test.name, Formatted(got), Formatted(test.want))
+		}
+	}
+
+	// A non-empty receiver is reused and zeroed outside the blocks.
+	m := NewDense(5, 5, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = -1
+	}
+	m.BlockDiag(a, b, c)
+	if !Equal(m, BlockDiag(a, b, c)) {
+		t.Errorf("unexpected BlockDiag result with non-empty receiver:\n%v", Formatted(m))
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{"VStack mismatched columns", func() { VStack(a, c) }},
+		{"HStack mismatched rows", func() { HStack(a, b) }},
+		{"VStack empty", func() { VStack() }},
+		{"BlockDiag aliased receiver", func() { a.BlockDiag(a, b) }},
+		{"HStack wrong receiver shape", func() { NewDense(2, 2, nil).HStack(a, c) }},
+		{"VStack slice of receiver", func() {
+			m := NewDense(4, 2, nil)
+			m.VStack(m.Slice(2, 4, 0, 2), a)
+		}},
+		{"HStack slice of receiver", func() {
+			m := NewDense(2, 4, nil)
+			m.HStack(a, m.Slice(0, 2, 0, 2).T())
+		}},
+		{"BlockDiag slice of receiver", func() {
+			m := NewDense(3, 3, nil)
+			m.BlockDiag(m.Slice(1, 3, 1, 3), NewDense(1, 1, nil))
+		}},
+	} {
+		if p, _ := panics(test.fn); !p {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
 func TestDenseRankOne(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -1823,6 +2149,101 @@ func TestDenseOuter(t *testing.T) {
 	}
 }
 
+func TestDenseBroadcast(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		vlen func(r, c int) int
+		fn   func(dst *Dense, a Matrix, v Vector)
+		want func(i, j int, a Matrix, v Vector) float64
+	}{
+		{
+			name: "AddRowVec",
+			vlen: func(r, c int) int { return c },
+			fn:   func(dst *Dense, a Matrix, v Vector) { dst.AddRowVec(a, -2, v) },
+			want: func(i, j int, a Matrix, v Vector) float64 { return a.At(i, j) - 2*v.AtVec(j) },
+		},
+		{
+			name: "AddColVec",
+			vlen: func(r, c int) int { return r },
+			fn:   func(dst *Dense, a Matrix, v Vector) { dst.AddColVec(a, 3, v) },
+			want: func(i, j int, a Matrix, v Vector) float64 { return a.At(i, j) + 3*v.AtVec(i) },
+		},
+		{
+			name: "ScaleRows",
+			vlen: func(r, c int) int { return r },
+			fn:   func(dst *Dense, a Matrix, v Vector) { dst.ScaleRows(v, a) },
+			want: func(i, j int, a Matrix, v Vector) float64 { return v.AtVec(i) * a.At(i, j) },
+		},
+		{
+			name: "ScaleCols",
+			vlen: func(r, c int) int { return c },
+			fn:   func(dst *Dense, a Matrix, v Vector) { dst.ScaleCols(v, a) },
+			want: func(i, j int, a Matrix, v Vector) float64 { return v.AtVec(j) * a.At(i, j) },
+		},
+	} {
+		for _, size := range []struct{ r, c int }{{1, 1}, {3, 4}, {5, 2}, {4, 4}} {
+			for _, inc := range []int{1, 3} {
+				n := test.vlen(size.r, size.c)
+				vdata := make([]float64, (n-1)*inc+1)
+				for i := range vdata {
+					vdata[i] = rnd.NormFloat64()
+				}
+				v := NewVecDense(n, nil)
+				v.SetRawVector(blas64.Vector{N: n, Inc: inc, Data: vdata})
+
+				a := NewDense(size.r, size.c, nil)
+				for i := 0; i < size.r; i++ {
+					for j := 0; j < size.c; j++ {
+						a.Set(i, j, rnd.NormFloat64())
+					}
+				}
+				want := NewDense(size.r, size.c, nil)
+				for i := 0; i < size.r; i++ {
+					for j := 0; j < size.c; j++ {
+						want.Set(i, j, test.want(i, j, a, v))
+					}
+				}
+
+				var got Dense
+				test.fn(&got, a, v)
+				if !EqualApprox(&got, want, 1e-14) {
+					t.Errorf("unexpected result for %s %d×%d inc=%d:\ngot:\n%v\nwant:\n%v",
+						test.name, size.r, size.c, inc, Formatted(&got), Formatted(want))
+				}
+
+				// Check with a non-VecDense vector.
+				got.Reset()
+				test.fn(&got, a, (*basicVector)(v))
+				if !EqualApprox(&got, want, 1e-14) {
+					t.Errorf("unexpected result for %s %d×%d with basicVector inc=%d:\ngot:\n%v\nwant:\n%v",
+						test.name, size.r, size.c, inc, Formatted(&got), Formatted(want))
+				}
+
+				// Check with the receiver aliasing a.
+				ac := DenseCopyOf(a)
+				test.fn(ac, ac, v)
+				if !EqualApprox(ac, want, 1e-14) {
+					t.Errorf("unexpected result for %s %d×%d with aliased receiver:\ngot:\n%v\nwant:\n%v",
+						test.name, size.r, size.c, Formatted(ac), Formatted(want))
+				}
+
+				// Check with the receiver aliasing a transposed a.
+				if size.r != size.c {
+					continue
+				}
+				at := DenseCopyOf(a.T())
+				test.fn(at, at.T(), v)
+				if !EqualApprox(at, want, 1e-14) {
+					t.Errorf("unexpected result for %s %d×%d with transposed aliased receiver:\ngot:\n%v\nwant:\n%v",
+						test.name, size.r, size.c, Formatted(at), Formatted(want))
+				}
+			}
+		}
+	}
+}
+
 func TestDenseInverse(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -2139,6 +2560,73 @@ var (
 	wd *Dense
 )
 
+func TestDensePseudoInverse(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{1, 1, 1},
+		{3, 3, 3},
+		{5, 3, 3},
+		{3, 5, 3},
+		{6, 6, 4},
+		{8, 5, 2},
+		{5, 8, 1},
+	} {
+		// Construct a matrix with the specified rank.
+		a := &Dense{}
+		a.Mul(randomDense(test.m, test.rank, rnd), randomDense(test.rank, test.n, rnd))
+
+		var x Dense
+		err := x.PseudoInverse(a, 1e-12)
+		if err != nil {
+			t.Fatalf("unexpected error for %d×%d rank %d: %v", test.m, test.n, test.rank, err)
+		}
+		if r, c := x.Dims(); r != test.n || c != test.m {
+			t.Errorf("unexpected dimensions for %d×%d rank %d: got %d×%d", test.m, test.n, test.rank, r, c)
+		}
+
+		// Check the Penrose conditions.
+		const tol = 1e-10
+		var ax, xa, tmp Dense
+		ax.Mul(a, &x)
+		xa.Mul(&x, a)
+		tmp.Mul(&ax, a)
+		if !EqualApprox(&tmp, a, tol) {
+			t.Errorf("A X A != A for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+		tmp.Reset()
+		tmp.Mul(&xa, &x)
+		if !EqualApprox(&tmp, &x, tol) {
+			t.Errorf("X A X != X for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+		if !EqualApprox(&ax, ax.T(), tol) {
+			t.Errorf("A X not symmetric for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+		if !EqualApprox(&xa, xa.T(), tol) {
+			t.Errorf("X A not symmetric for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+
+		if test.m == test.n && test.n == test.rank {
+			var inv Dense
+			err := inv.Inverse(a)
+			if err != nil {
+				t.Fatalf("unexpected error from Inverse: %v", err)
+			}
+			if !EqualApprox(&inv, &x, tol) {
+				t.Errorf("pseudo-inverse of invertible matrix does not match inverse")
+			}
+		}
+	}
+
+	var x Dense
+	x.PseudoInverse(NewDense(2, 3, nil), 0)
+	if !Equal(&x, NewDense(3, 2, nil)) {
+		t.Errorf("unexpected pseudo-inverse of zero matrix:\n%v", Formatted(&x))
+	}
+}
+
 func BenchmarkMulDense100Half(b *testing.B)        { denseMulBench(b, 100, 0.5) }
 func BenchmarkMulDense100Tenth(b *testing.B)       { denseMulBench(b, 100, 0.1) }
 func BenchmarkMulDense1000Half(b *testing.B)       { denseMulBench(b, 1000, 0.5) }
@@ -115,6 +115,13 @@
 // a cgo BLAS implementation is registered, the lapack64 calls will be partially
 // executed in Go and partially executed in C.
 //
+// The blas.Float64 interface registered with blas64.Use is the backend for the
+// core kernels of mat: Dense.Mul calls Dgemm, matrix-vector products call
+// Dgemv, and vector updates and reductions call routines such as Daxpy, Ddot
+// and Dnrm2. An implementation backed by an accelerator may embed
+// gonum.org/v1/gonum/blas/gonum.Implementation and override only the routines
+// it offloads, for example Dgemm, so that all other calls fall back to Go.
+//
 // Type Switching
 //
 // The Matrix abstraction enables efficiency as well as interoperability. Go's
@@ -73,6 +73,26 @@ func FormatPython() FormatOption {
 	return func(f *formatter) { f.format = formatPython }
 }
 
+// FormatLaTeX sets the printing behavior to output a LaTeX bmatrix environment. If LaTeX
+// output is specified, the ' ' verb flag and Excerpt option are ignored.
+func FormatLaTeX() FormatOption {
+	return func(f *formatter) { f.format = formatLaTeX }
+}
+
+// FormatMarkdown sets the printing behavior to output a Markdown table with a header row
+// holding the column indices. If Markdown output is specified, the ' ' verb flag and
+// Excerpt option are ignored.
+func FormatMarkdown() FormatOption {
+	return func(f *formatter) { f.format = formatMarkdown }
+}
+
+// FormatCSV sets the printing behavior to output comma separated values with one line
+// per row. If CSV output is specified, the ' ' and '-' verb flags and the Excerpt,
+// Prefix and Squeeze options are ignored.
+func FormatCSV() FormatOption {
+	return func(f *formatter) { f.format = formatCSV }
+}
+
 // Format satisfies the fmt.Formatter interface.
 func (f formatter) Format(fs fmt.State, c rune) {
 	if c == 'v' && fs.Flag('#') && f.format == nil {
@@ -449,6 +469,159 @@ func formatPython(m Matrix, prefix string, _ int, _ byte, squeeze bool, fs fmt.S
 	}
 }
 
+// formatTable prints the elements of m to the fs io.Writer as a table with each row
+// surrounded by open and close and elements separated by sep. Each line after the first
+// is preceded by prefix. The format character c specifies the numerical representation
+// of elements; valid values are those for float64 specified in the fmt package, with
+// their associated flags. If squeeze is true, column widths are determined on a
+// per-column basis. If header is not nil, it is called with the column widths to print
+// lines preceding the table. formatTable returns false if c is not a valid verb.
+func formatTable(m Matrix, prefix, open, sep, close string, squeeze bool, fs fmt.State, c rune, header func(widths widther)) bool {
+	rows, cols := m.Dims()
+
+	prec, pOk := fs.Precision()
+	if !pOk {
+		prec = -1
+	}
+	width, _ := fs.Width()
+
+	printed := max(rows, cols)
+	var (
+		maxWidth int
+		widths   widther
+		buf, pad []byte
+	)
+	if squeeze {
+		widths = make(columnWidth, cols)
+	} else {
+		widths = new(uniformWidth)
+	}
+	switch c {
+	case 'v', 'e', 'E', 'f', 'F', 'g', 'G':
+		if c == 'v' {
+			buf, maxWidth = maxCellWidth(m, 'g', printed, prec, widths)
+		} else {
+			buf, maxWidth = maxCellWidth(m, c, printed, prec, widths)
+		}
+	default:
+		fmt.Fprintf(fs, "%%!%c(%T=Dims(%d, %d))", c, m, rows, cols)
+		return false
+	}
+	if !squeeze {
+		widths.setWidth(0, max(width, maxWidth))
+	}
+	if header != nil {
+		header(widths)
+	}
+	for j := 0; j < cols; j++ {
+		maxWidth = max(maxWidth, widths.width(j))
+	}
+	pad = make([]byte, max(width, maxWidth))
+	for i := range pad {
+		pad[i] = ' '
+	}
+
+	for i := 0; i < rows; i++ {
+		if i != 0 || header != nil {
+			fmt.Fprint(fs, prefix)
+		}
+		fmt.Fprint(fs, open)
+		for j := 0; j < cols; j++ {
+			if j != 0 {
+				fmt.Fprint(fs, sep)
+			}
+			v := m.At(i, j)
+			if c == 'v' {
+				buf = strconv.AppendFloat(buf[:0], v, 'g', prec, 64)
+			} else {
+				buf = strconv.AppendFloat(buf[:0], v, byte(c), prec, 64)
+			}
+			if fs.Flag('-') {
+				fs.Write(buf)
+				fs.Write(pad[:widths.width(j)-len(buf)])
+			} else {
+				fs.Write(pad[:widths.width(j)-len(buf)])
+				fs.Write(buf)
+			}
+		}
+		fmt.Fprint(fs, close)
+		if i < rows-1 {
+			fmt.Fprint(fs, "\n")
+		}
+	}
+	return true
+}
+
+// formatLaTeX prints a LaTeX bmatrix representation of m to the fs io.Writer. The format
+// character c specifies the numerical representation of elements; valid values are those
+// for float64 specified in the fmt package, with their associated flags.
+// If squeeze is true, column widths are determined on a per-column basis.
+func formatLaTeX(m Matrix, prefix string, _ int, _ byte, squeeze bool, fs fmt.State, c rune) {
+	header := func(widther) {
+		fmt.Fprint(fs, "\\begin{bmatrix}\n")
+	}
+	if formatTable(m, prefix, "  ", " & ", ` \\`, squeeze, fs, c, header) {
+		fmt.Fprint(fs, "\n"+prefix+"\\end{bmatrix}")
+	}
+}
+
+// formatMarkdown prints a Markdown table representation of m to the fs io.Writer. The
+// table has a header row holding the column indices. The format character c specifies
+// the numerical representation of elements; valid values are those for float64
+// specified in the fmt package, with their associated flags.
+// If squeeze is true, column widths are determined on a per-column basis.
+func formatMarkdown(m Matrix, prefix string, _ int, _ byte, squeeze bool, fs fmt.State, c rune) {
+	_, cols := m.Dims()
+	header := func(widths widther) {
+		for j := 0; j < cols; j++ {
+			widths.setWidth(j, max(max(widths.width(j), len(strconv.Itoa(j))), 3))
+		}
+		fmt.Fprint(fs, "|")
+		for j := 0; j < cols; j++ {
+			fmt.Fprintf(fs, " %*d |", widths.width(j), j)
+		}
+		fmt.Fprint(fs, "\n"+prefix+"|")
+		for j := 0; j < cols; j++ {
+			fmt.Fprint(fs, " "+strings.Repeat("-", widths.width(j)-1)+": |")
+		}
+		fmt.Fprint(fs, "\n")
+	}
+	formatTable(m, prefix, "| ", " | ", " |", squeeze, fs, c, header)
+}
+
+// formatCSV prints a comma separated values representation of m to the fs io.Writer.
+// The format character c specifies the numerical representation of elements; valid
+// values are those for float64 specified in the fmt package, with their associated flags.
+func formatCSV(m Matrix, _ string, _ int, _ byte, _ bool, fs fmt.State, c rune) {
+	rows, cols := m.Dims()
+	switch c {
+	case 'v', 'e', 'E', 'f', 'F', 'g', 'G':
+	default:
+		fmt.Fprintf(fs, "%%!%c(%T=Dims(%d, %d))", c, m, rows, cols)
+		return
+	}
+	prec, pOk := fs.Precision()
+	if !pOk {
+		prec = -1
+	}
+	if c == 'v' {
+		c = 'g'
+	}
+	var buf []byte
+	for i := 0; i < rows; i++ {
+		if i != 0 {
+			fs.Write([]byte{'\n'})
+		}
+		for j := 0; j < cols; j++ {
+			if j != 0 {
+				fs.Write([]byte{','})
+			}
+			buf = strconv.AppendFloat(buf[:0], m.At(i, j), byte(c), prec, 64)
+			fs.Write(buf)
+		}
+	}
+}
+
 // This is horrible, but it's what we have.
 func fmtString(fs fmt.State, c rune, prec, width int) string {
 	var b strings.Builder
@@ -229,6 +229,44 @@ func TestFormat(t *testing.T) {
 				{"%#v", "[[ 1, -2,  3],\n [ 4,  5,  6],\n [ 7,  8,  9]]"},
 			},
 		},
+
+		// LaTeX, Markdown and CSV representations
+		{
+			m: Formatted(NewDense(2, 3, []float64{1, -2.5, 3, 400, 5, 0.125}), FormatLaTeX()),
+			rep: []rp{
+				{"%v", "\\begin{bmatrix}\n      1 &  -2.5 &     3 \\\\\n    400 &     5 & 0.125 \\\\\n\\end{bmatrix}"},
+				{"%.2f", "\\begin{bmatrix}\n    1.00 &  -2.50 &   3.00 \\\\\n  400.00 &   5.00 &   0.12 \\\\\n\\end{bmatrix}"},
+				{"%d", "%!d(*mat.Dense=Dims(2, 3))"},
+			},
+		},
+		{
+			m: Formatted(NewDense(2, 3, []float64{1, -2.5, 3, 400, 5, 0.125}), FormatLaTeX(), Squeeze(), Prefix(" ")),
+			rep: []rp{
+				{"%.2f", "\\begin{bmatrix}\n     1.00 & -2.50 & 3.00 \\\\\n   400.00 &  5.00 & 0.12 \\\\\n \\end{bmatrix}"},
+			},
+		},
+		{
+			m: Formatted(NewDense(2, 3, []float64{1, -2.5, 3, 400, 5, 0.125}), FormatMarkdown()),
+			rep: []rp{
+				{"%v", "|     0 |     1 |     2 |\n| ----: | ----: | ----: |\n|     1 |  -2.5 |     3 |\n|   400 |     5 | 0.125 |"},
+				{"%-v", "|     0 |     1 |     2 |\n| ----: | ----: | ----: |\n| 1     | -2.5  | 3     |\n| 400   | 5     | 0.125 |"},
+			},
+		},
+		{
+			m: Formatted(NewDense(2, 3, []float64{1, -2.5, 3, 400, 5, 0.125}), FormatMarkdown(), Squeeze()),
+			rep: []rp{
+				{"%.1e", "|       0 |        1 |       2 |\n| ------: | -------: | ------: |\n| 1.0e+00 | -2.5e+00 | 3.0e+00 |\n| 4.0e+02 |  5.0e+00 | 1.2e-01 |"},
+				{"%.0f", "|   0 |   1 |   2 |\n| --: | --: | --: |\n|   1 |  -2 |   3 |\n| 400 |   5 |   0 |"},
+			},
+		},
+		{
+			m: Formatted(NewDense(2, 3, []float64{1, -2.5, 3, 400, 5, 0.125}), FormatCSV()),
+			rep: []rp{
+				{"%v", "1,-2.5,3\n400,5,0.125"},
+				{"%.3e", "1.000e+00,-2.500e+00,3.000e+00\n4.000e+02,5.000e+00,1.250e-01"},
+				{"%s", "%!s(*mat.Dense=Dims(2, 3))"},
+			},
+		},
 	} {
 		for j, rp := range test.rep {
 			got := fmt.Sprintf(rp.format, test.m)
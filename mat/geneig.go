@@ -0,0 +1,540 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// GeneralizedEigenSym is a type for creating and using the eigenvalue
+// decomposition of a symmetric-definite matrix pencil, the solutions of
+//  A * x = λ * B * x,
+// where A is symmetric and B is symmetric positive definite.
+type GeneralizedEigenSym struct {
+	vectorsComputed bool
+
+	values  []float64
+	vectors *Dense
+}
+
+// Factorize computes the eigenvalues and, if vectors is true, the eigenvectors
+// of the symmetric-definite pencil (A, B). The problem is reduced to a standard
+// symmetric eigenvalue problem using the Cholesky factorization B = Uᵀ * U,
+//  U⁻ᵀ * A * U⁻¹ * y = λ * y,   x = U⁻¹ * y.
+// The eigenvalues are real and computed in ascending order, and the
+// eigenvectors are normalized to be B-orthonormal, Xᵀ * B * X = I.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if B is not positive definite. If the decomposition failed, methods
+// that require a successful factorization will panic. Factorize will panic if
+// a and b do not have the same dimensions.
+func (e *GeneralizedEigenSym) Factorize(a, b Symmetric, vectors bool) (ok bool) {
+	// kill previous decomposition
+	e.vectorsComputed = false
+	e.values = nil
+	e.vectors = nil
+
+	n := a.SymmetricDim()
+	if b.SymmetricDim() != n {
+		panic(ErrShape)
+	}
+	var chol Cholesky
+	if !chol.Factorize(b) {
+		return false
+	}
+	u := chol.chol.mat
+
+	// Form C = U⁻ᵀ * A * U⁻¹.
+	c := NewDense(n, n, nil)
+	c.Copy(a)
+	blas64.Trsm(blas.Left, blas.Trans, 1, u, c.mat)
+	blas64.Trsm(blas.Right, blas.NoTrans, 1, u, c.mat)
+	sc := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			// Average the rounding errors of the two triangles.
+			sc.SetSym(i, j, (c.at(i, j)+c.at(j, i))/2)
+		}
+	}
+
+	var ed EigenSym
+	if !ed.Factorize(sc, vectors) {
+		return false
+	}
+	e.values = ed.values
+	if vectors {
+		e.vectors = ed.vectors
+		blas64.Trsm(blas.Left, blas.NoTrans, 1, u, e.vectors.mat)
+		e.vectorsComputed = true
+	}
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *GeneralizedEigenSym) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the eigenvalues of the factorized pencil in ascending order.
+// If dst is non-nil, the values are stored in-place into dst. In this case dst
+// must have length n, otherwise Values will panic. If dst is nil, then a new
+// slice will be allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *GeneralizedEigenSym) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the B-orthonormal eigenvectors of the decomposition into
+// the columns of dst.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *GeneralizedEigenSym) VectorsTo(dst *Dense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if !e.vectorsComputed {
+		panic(noVectors)
+	}
+	r, c := e.vectors.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else {
+		r2, c2 := dst.Dims()
+		if r != r2 || c != c2 {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.vectors)
+}
+
+// GeneralizedEigen is a type for creating and using the eigenvalue
+// decomposition of a general square matrix pencil, the solutions of
+//  A * x = λ * B * x.
+// B may be singular, in which case the pencil may have infinite eigenvalues.
+type GeneralizedEigen struct {
+	n int
+
+	vectorsComputed bool
+
+	values  []complex128
+	vectors *CDense
+}
+
+// Factorize computes the eigenvalues and, if vectors is true, the right
+// eigenvectors of the pencil (A, B) using the QZ algorithm. The pencil is
+// reduced by unitary transformations to the generalized Schur form
+//  Qᴴ * A * Z = S,   Qᴴ * B * Z = T,
+// with S and T upper triangular, and the eigenvalues are the ratios of the
+// diagonal elements of S and T. The reduction is computed in complex
+// arithmetic, so the eigenvalues of a real pencil with real eigenvalues may
+// have imaginary parts of the order of the rounding error.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+// Factorize will panic if a is not square or if b does not have the same
+// dimensions as a.
+func (e *GeneralizedEigen) Factorize(a, b Matrix, vectors bool) (ok bool) {
+	// kill previous decomposition
+	e.n = 0
+	e.vectorsComputed = false
+	e.values = nil
+	e.vectors = nil
+
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if br, bc := b.Dims(); br != n || bc != n {
+		panic(ErrShape)
+	}
+
+	qz := newComplexQZ(a, b, vectors)
+	qz.hessenbergTriangular()
+	if !qz.iterate() {
+		return false
+	}
+	e.n = n
+	e.values = qz.values()
+	if vectors {
+		e.vectors = qz.vectors()
+		e.vectorsComputed = true
+	}
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *GeneralizedEigen) succFact() bool {
+	return e.n != 0
+}
+
+// Values extracts the eigenvalues of the factorized pencil. Infinite
+// eigenvalues, which arise when B is singular, are returned as cmplx.Inf(),
+// and the eigenvalues of a singular pencil, for which det(A - λB) is zero for
+// all λ, are returned as cmplx.NaN(). If dst is non-nil, the values are stored
+// in-place into dst. In this case dst must have length n, otherwise Values
+// will panic. If dst is nil, then a new slice will be allocated of the proper
+// length and filled with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *GeneralizedEigen) Values(dst []complex128) []complex128 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, e.n)
+	}
+	if len(dst) != e.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the right eigenvectors of the decomposition into the columns
+// of dst. The computed eigenvectors are normalized to have Euclidean norm equal
+// to 1 and largest component real.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *GeneralizedEigen) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if !e.vectorsComputed {
+		panic(noVectors)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(e.n, e.n)
+	} else {
+		r, c := dst.Dims()
+		if r != e.n || c != e.n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.vectors)
+}
+
+// complexQZ holds the state of the complex single-shift QZ algorithm
+// reducing the pencil (S, T) to generalized Schur form. The matrices are
+// stored in row-major order with stride n.
+type complexQZ struct {
+	n    int
+	s, t []complex128
+
+	// z accumulates the right transformations
+	// if eigenvectors are required.
+	z []complex128
+
+	// atol and btol are the thresholds below which
+	// elements of S and T are considered zero.
+	atol, btol float64
+}
+
+func newComplexQZ(a, b Matrix, vectors bool) *complexQZ {
+	n, _ := a.Dims()
+	qz := &complexQZ{
+		n: n,
+		s: make([]complex128, n*n),
+		t: make([]complex128, n*n),
+	}
+	var anorm, bnorm float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			av, bv := a.At(i, j), b.At(i, j)
+			qz.s[i*n+j] = complex(av, 0)
+			qz.t[i*n+j] = complex(bv, 0)
+			anorm = math.Hypot(anorm, av)
+			bnorm = math.Hypot(bnorm, bv)
+		}
+	}
+	const (
+		eps    = 0x1p-53
+		safmin = 0x1p-1022
+	)
+	qz.atol = math.Max(safmin, eps*anorm)
+	qz.btol = math.Max(safmin, eps*bnorm)
+	if vectors {
+		qz.z = make([]complex128, n*n)
+		for i := 0; i < n; i++ {
+			qz.z[i*n+i] = 1
+		}
+	}
+	return qz
+}
+
+// givens returns the complex plane rotation
+//  [  c    s ] [ f ]   [ r ]
+//  [ -s̄    c ] [ g ] = [ 0 ]
+// with real c.
+func givens(f, g complex128) (c float64, s complex128) {
+	if g == 0 {
+		return 1, 0
+	}
+	if f == 0 {
+		return 0, cmplx.Conj(g) / complex(cmplx.Abs(g), 0)
+	}
+	af := cmplx.Abs(f)
+	norm := math.Hypot(af, cmplx.Abs(g))
+	return af / norm, f / complex(af, 0) * cmplx.Conj(g) / complex(norm, 0)
+}
+
+// rowRot applies the rotation (c, s) from the left to rows i and i+1 of S
+// and T, chosen to zero the element of m at row i+1, column j.
+func (qz *complexQZ) rowRot(m []complex128, i, j int) {
+	n := qz.n
+	c, s := givens(m[i*n+j], m[(i+1)*n+j])
+	cc := complex(c, 0)
+	for _, a := range [][]complex128{qz.s, qz.t} {
+		for k := 0; k < n; k++ {
+			x, y := a[i*n+k], a[(i+1)*n+k]
+			a[i*n+k] = cc*x + s*y
+			a[(i+1)*n+k] = -cmplx.Conj(s)*x + cc*y
+		}
+	}
+	m[(i+1)*n+j] = 0
+}
+
+// colRot applies a rotation from the right to columns j and j+1 of S, T
+// and Z, chosen to zero the element of m at row i, column j.
+func (qz *complexQZ) colRot(m []complex128, i, j int) {
+	n := qz.n
+	c, s := givens(m[i*n+j+1], m[i*n+j])
+	cc := complex(c, 0)
+	for _, a := range [][]complex128{qz.s, qz.t, qz.z} {
+		if a == nil {
+			continue
+		}
+		for k := 0; k < n; k++ {
+			x, y := a[k*n+j], a[k*n+j+1]
+			a[k*n+j+1] = cc*y + s*x
+			a[k*n+j] = -cmplx.Conj(s)*y + cc*x
+		}
+	}
+	m[i*n+j] = 0
+}
+
+// hessenbergTriangular reduces the pencil to upper Hessenberg S and upper
+// triangular T.
+func (qz *complexQZ) hessenbergTriangular() {
+	n := qz.n
+	for j := 0; j < n-1; j++ {
+		for i := n - 1; i > j; i-- {
+			qz.rowRot(qz.t, i-1, j)
+		}
+	}
+	for j := 0; j < n-2; j++ {
+		for i := n - 1; i > j+1; i-- {
+			qz.rowRot(qz.s, i-1, j)
+			qz.colRot(qz.t, i, i-1)
+		}
+	}
+}
+
+// iterate performs QZ iterations until the pencil is in generalized Schur
+// form, returning whether the iterations converged.
+func (qz *complexQZ) iterate() bool {
+	n := qz.n
+	s, t := qz.s, qz.t
+	maxIter := 30 * n
+	var iter, sinceDeflation int
+	for ihi := n - 1; ihi > 0; {
+		// Find the start of the active unreduced block.
+		ilo := ihi
+		for ; ilo > 0; ilo-- {
+			if cmplx.Abs(s[ilo*n+ilo-1]) <= qz.atol {
+				s[ilo*n+ilo-1] = 0
+				break
+			}
+		}
+		if ilo == ihi {
+			ihi--
+			sinceDeflation = 0
+			continue
+		}
+
+		// Deflate infinite eigenvalues by chasing zero diagonal
+		// elements of T to the bottom of the block.
+		zeroT := -1
+		for j := ilo; j <= ihi; j++ {
+			if cmplx.Abs(t[j*n+j]) <= qz.btol {
+				t[j*n+j] = 0
+				zeroT = j
+				break
+			}
+		}
+		if zeroT >= 0 {
+			for k := zeroT; k < ihi; k++ {
+				qz.rowRot(t, k, k+1)
+				if k > ilo {
+					qz.colRot(s, k+1, k-1)
+				}
+			}
+			qz.colRot(s, ihi, ihi-1)
+			continue
+		}
+
+		iter++
+		sinceDeflation++
+		if iter > maxIter {
+			return false
+		}
+		qz.step(ilo, ihi, sinceDeflation%10 == 0)
+	}
+	return true
+}
+
+// step performs a single-shift QZ step on the unreduced block ilo..ihi.
+func (qz *complexQZ) step(ilo, ihi int, exceptional bool) {
+	n := qz.n
+	s, t := qz.s, qz.t
+
+	// Use the eigenvalue of the trailing 2×2 pencil closer to
+	// the last diagonal ratio as the shift,
+	//  det([a11-λb11 a12-λb12; a21 a22-λb22]) = 0.
+	a11, a12 := s[(ihi-1)*n+ihi-1], s[(ihi-1)*n+ihi]
+	a21, a22 := s[ihi*n+ihi-1], s[ihi*n+ihi]
+	b11, b12, b22 := t[(ihi-1)*n+ihi-1], t[(ihi-1)*n+ihi], t[ihi*n+ihi]
+	target := a22 / b22
+	var shift complex128
+	if exceptional {
+		shift = target + complex(cmplx.Abs(a21)/cmplx.Abs(b11), 0)
+	} else {
+		qa := b11 * b22
+		qb := -(a11*b22 + a22*b11 - a21*b12)
+		qc := a11*a22 - a21*a12
+		disc := cmplx.Sqrt(qb*qb - 4*qa*qc)
+		if real(cmplx.Conj(qb)*disc) < 0 {
+			disc = -disc
+		}
+		q := -(qb + disc) / 2
+		shift = target
+		if q != 0 {
+			l1, l2 := q/qa, qc/q
+			shift = l1
+			if cmplx.Abs(l2-target) < cmplx.Abs(l1-target) {
+				shift = l2
+			}
+		}
+	}
+
+	// Introduce the bulge with the first column of S*T⁻¹ - σI
+	// and chase it down the block.
+	tii := t[ilo*n+ilo]
+	x := s[ilo*n+ilo]/tii - shift
+	y := s[(ilo+1)*n+ilo] / tii
+	c, sn := givens(x, y)
+	cc := complex(c, 0)
+	for _, a := range [][]complex128{s, t} {
+		for k := 0; k < n; k++ {
+			u, v := a[ilo*n+k], a[(ilo+1)*n+k]
+			a[ilo*n+k] = cc*u + sn*v
+			a[(ilo+1)*n+k] = -cmplx.Conj(sn)*u + cc*v
+		}
+	}
+	for k := ilo; k < ihi; k++ {
+		qz.colRot(t, k+1, k)
+		if k+2 <= ihi {
+			qz.rowRot(s, k+1, k)
+		}
+	}
+}
+
+// values returns the generalized eigenvalues of the Schur form.
+func (qz *complexQZ) values() []complex128 {
+	n := qz.n
+	vals := make([]complex128, n)
+	for k := range vals {
+		alpha, beta := qz.s[k*n+k], qz.t[k*n+k]
+		switch {
+		case beta != 0:
+			vals[k] = alpha / beta
+		case alpha != 0:
+			vals[k] = cmplx.Inf()
+		default:
+			vals[k] = cmplx.NaN()
+		}
+	}
+	return vals
+}
+
+// vectors returns the right eigenvectors of the pencil, computed by back
+// substitution in the Schur form and transformation by Z.
+func (qz *complexQZ) vectors() *CDense {
+	n := qz.n
+	s, t := qz.s, qz.t
+	dst := NewCDense(n, n, nil)
+	y := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		alpha, beta := s[k*n+k], t[k*n+k]
+		scale := math.Max(cmplx.Abs(alpha), cmplx.Abs(beta))
+		if scale == 0 {
+			scale = 1
+		}
+		alpha /= complex(scale, 0)
+		beta /= complex(scale, 0)
+
+		// Solve (β S - α T)[:k, :k] y = -(β S - α T)[:k, k] with y[k] = 1.
+		// Perturb small pivots arising from repeated eigenvalues.
+		small := cmplx.Abs(beta)*qz.atol + cmplx.Abs(alpha)*qz.btol
+		for i := range y {
+			y[i] = 0
+		}
+		y[k] = 1
+		for i := k - 1; i >= 0; i-- {
+			var sum complex128
+			for j := i + 1; j <= k; j++ {
+				sum += (beta*s[i*n+j] - alpha*t[i*n+j]) * y[j]
+			}
+			d := beta*s[i*n+i] - alpha*t[i*n+i]
+			if cmplx.Abs(d) < small {
+				d = complex(small, 0)
+			}
+			y[i] = -sum / d
+		}
+
+		// x = Z y, normalized to unit norm with the largest
+		// component real.
+		var norm, maxAbs float64
+		var maxElem complex128
+		for i := 0; i < n; i++ {
+			var v complex128
+			for j := 0; j <= k; j++ {
+				v += qz.z[i*n+j] * y[j]
+			}
+			dst.mat.Data[i*dst.mat.Stride+k] = v
+			a := cmplx.Abs(v)
+			norm = math.Hypot(norm, a)
+			if a > maxAbs {
+				maxAbs = a
+				maxElem = v
+			}
+		}
+		f := complex(maxAbs/norm, 0) / maxElem
+		for i := 0; i < n; i++ {
+			dst.mat.Data[i*dst.mat.Stride+k] *= f
+		}
+	}
+	return dst
+}
@@ -0,0 +1,198 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestGeneralizedEigenSym(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	rnd := rand.New(src)
+	for _, n := range []int{1, 2, 5, 10, 30} {
+		a := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				a.SetSym(i, j, rnd.NormFloat64())
+			}
+		}
+		b := RandomSPD(n, 100, src)
+
+		var ge GeneralizedEigenSym
+		if !ge.Factorize(a, b, true) {
+			t.Fatalf("n=%d: unexpected factorization failure", n)
+		}
+		vals := ge.Values(nil)
+		for i := 1; i < n; i++ {
+			if vals[i] < vals[i-1] {
+				t.Errorf("n=%d: eigenvalues not ascending", n)
+			}
+		}
+		var x Dense
+		ge.VectorsTo(&x)
+
+		// Check Xᵀ B X = I and A X = B X Λ.
+		var xbx Dense
+		xbx.Product(x.T(), b, &x)
+		if !EqualApprox(&xbx, eye(n), 1e-10) {
+			t.Errorf("n=%d: eigenvectors not B-orthonormal", n)
+		}
+		var ax, bx Dense
+		ax.Mul(a, &x)
+		bx.Mul(b, &x)
+		bx.Mul(&bx, NewDiagDense(n, vals))
+		if !EqualApprox(&ax, &bx, 1e-10) {
+			t.Errorf("n=%d: A X != B X Λ", n)
+		}
+	}
+
+	var ge GeneralizedEigenSym
+	b := NewSymDense(2, []float64{1, 2, 2, 1})
+	if ge.Factorize(NewSymDense(2, nil), b, false) {
+		t.Error("expected factorization failure for indefinite B")
+	}
+}
+
+func TestGeneralizedEigen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 6, 10, 25} {
+		a := randomDense(n, n, rnd)
+		b := randomDense(n, n, rnd)
+		var ge GeneralizedEigen
+		if !ge.Factorize(a, b, true) {
+			t.Fatalf("n=%d: unexpected factorization failure", n)
+		}
+		vals := ge.Values(nil)
+
+		// The eigenvalues agree with those of B⁻¹A.
+		var lu LU
+		lu.Factorize(b)
+		var bia Dense
+		if err := lu.SolveTo(&bia, false, a); err != nil {
+			t.Fatalf("n=%d: unexpected solve error: %v", n, err)
+		}
+		var ed Eigen
+		if !ed.Factorize(&bia, EigenNone) {
+			t.Fatalf("n=%d: unexpected Eigen failure", n)
+		}
+		want := ed.Values(nil)
+		checkMatchedValues(t, n, vals, want, 1e-8)
+
+		var x CDense
+		ge.VectorsTo(&x)
+		checkGeneralizedVectors(t, n, a, b, vals, &x)
+	}
+}
+
+func TestGeneralizedEigenSingularB(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 5
+	a := randomDense(n, n, rnd)
+	b := randomDense(n, n, rnd)
+	// Make B singular with rank n-2.
+	for j := 0; j < n; j++ {
+		b.Set(n-1, j, b.At(0, j)+b.At(1, j))
+		b.Set(n-2, j, b.At(2, j))
+	}
+
+	var ge GeneralizedEigen
+	if !ge.Factorize(a, b, true) {
+		t.Fatal("unexpected factorization failure")
+	}
+	vals := ge.Values(nil)
+	var inf int
+	var finite []complex128
+	for _, v := range vals {
+		if cmplx.IsInf(v) {
+			inf++
+			continue
+		}
+		finite = append(finite, v)
+	}
+	if inf != 2 {
+		t.Fatalf("unexpected number of infinite eigenvalues: got %d, want 2: %v", inf, vals)
+	}
+	for _, v := range finite {
+		if imag(v) != 0 {
+			// Complex eigenvalues are checked by their eigenvectors below.
+			continue
+		}
+		// det(A - λB) = 0 for finite real eigenvalues.
+		var m Dense
+		m.Scale(real(v), b)
+		m.Sub(a, &m)
+		var svd SVD
+		svd.Factorize(&m, SVDNone)
+		s := svd.Values(nil)
+		if s[n-1] > 1e-10*s[0] {
+			t.Errorf("A - λB not singular for λ=%v: smallest singular value %v", v, s[n-1])
+		}
+	}
+	var x CDense
+	ge.VectorsTo(&x)
+	checkGeneralizedVectors(t, n, a, b, vals, &x)
+}
+
+// checkMatchedValues checks that each element of got is close to a distinct
+// element of want.
+func checkMatchedValues(t *testing.T, n int, got, want []complex128, tol float64) {
+	t.Helper()
+	used := make([]bool, len(want))
+	for _, g := range got {
+		best := -1
+		for j, w := range want {
+			if used[j] {
+				continue
+			}
+			if best < 0 || cmplx.Abs(g-w) < cmplx.Abs(g-want[best]) {
+				best = j
+			}
+		}
+		used[best] = true
+		if cmplx.Abs(g-want[best]) > tol*math.Max(1, cmplx.Abs(g)) {
+			t.Errorf("n=%d: eigenvalue %v not matched: closest %v", n, g, want[best])
+		}
+	}
+}
+
+// checkGeneralizedVectors checks that the columns of x are unit eigenvectors
+// of the pencil (a, b), β A x = α B x, with infinite eigenvalues satisfying
+// B x = 0.
+func checkGeneralizedVectors(t *testing.T, n int, a, b Matrix, vals []complex128, x *CDense) {
+	t.Helper()
+	for k, v := range vals {
+		var norm float64
+		for i := 0; i < n; i++ {
+			norm = math.Hypot(norm, cmplx.Abs(x.At(i, k)))
+		}
+		if math.Abs(norm-1) > 1e-12 {
+			t.Errorf("n=%d: eigenvector %d not normalized: %v", n, k, norm)
+		}
+		alpha, beta := v, complex(1, 0)
+		if cmplx.IsInf(v) {
+			alpha, beta = 1, 0
+		}
+		var resid float64
+		for i := 0; i < n; i++ {
+			var ax, bx complex128
+			for j := 0; j < n; j++ {
+				ax += complex(a.At(i, j), 0) * x.At(j, k)
+				bx += complex(b.At(i, j), 0) * x.At(j, k)
+			}
+			resid = math.Hypot(resid, cmplx.Abs(beta*ax-alpha*bx))
+		}
+		scale := cmplx.Abs(beta)*Norm(a, 2) + cmplx.Abs(alpha)*Norm(b, 2)
+		if resid > 1e-10*scale {
+			t.Errorf("n=%d: large residual for eigenpair %d (λ=%v): %v", n, k, v, resid)
+		}
+	}
+}
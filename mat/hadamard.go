@@ -0,0 +1,142 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/bits"
+)
+
+var (
+	_ Matrix     = (*Hadamard)(nil)
+	_ Symmetric  = (*Hadamard)(nil)
+	_ MulVecToer = (*Hadamard)(nil)
+)
+
+const badHadamardSize = "mat: Hadamard order not a power of two"
+
+// Hadamard represents the n×n Sylvester–Hadamard matrix, where n is a power
+// of two, with elements
+//  H[i, j] = s * (-1)^popcount(i&j),
+// where popcount is the number of set bits and the scale s is 1, or 1/√n if
+// the matrix is normalized so that it is orthogonal. The matrix is symmetric
+// and its elements are computed implicitly, and products with it are
+// computed by the fast Walsh–Hadamard transform in O(n log n) time.
+type Hadamard struct {
+	n     int
+	scale float64
+}
+
+// NewHadamard returns a new n×n Hadamard matrix, normalized by 1/√n if
+// normalized is true. NewHadamard will panic if n is not a power of two.
+func NewHadamard(n int, normalized bool) *Hadamard {
+	if n <= 0 || n&(n-1) != 0 {
+		panic(badHadamardSize)
+	}
+	scale := 1.0
+	if normalized {
+		scale = 1 / math.Sqrt(float64(n))
+	}
+	return &Hadamard{n: n, scale: scale}
+}
+
+// Dims returns the dimensions of the matrix.
+func (h *Hadamard) Dims() (r, c int) {
+	return h.n, h.n
+}
+
+// SymmetricDim returns the number of rows and columns in the matrix.
+func (h *Hadamard) SymmetricDim() int {
+	return h.n
+}
+
+// At returns the element at row i, column j.
+func (h *Hadamard) At(i, j int) float64 {
+	if uint(i) >= uint(h.n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(h.n) {
+		panic(ErrColAccess)
+	}
+	if bits.OnesCount(uint(i&j))%2 == 1 {
+		return -h.scale
+	}
+	return h.scale
+}
+
+// T returns the receiver, the transpose of a symmetric matrix.
+func (h *Hadamard) T() Matrix {
+	return h
+}
+
+// MulVecTo computes H⋅x storing the result into dst. Since H is symmetric,
+// trans is ignored.
+func (h *Hadamard) MulVecTo(dst *VecDense, _ bool, x Vector) {
+	if x.Len() != h.n {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(h.n)
+	if dst != x {
+		dst.CopyVec(x)
+	}
+	FWHT(dst)
+	if h.scale != 1 {
+		dst.ScaleVec(h.scale, dst)
+	}
+}
+
+// FWHT computes in place the unnormalized fast Walsh–Hadamard transform of v,
+// the product of v with the Sylvester–Hadamard matrix of order v.Len(), in
+// natural (Hadamard) order. Applying FWHT twice scales v by v.Len(). FWHT will
+// panic if the length of v is not a power of two.
+func FWHT(v *VecDense) {
+	n := v.mat.N
+	if n <= 0 || n&(n-1) != 0 {
+		panic(badHadamardSize)
+	}
+	fwht(v.mat.Data, v.mat.Inc, n)
+}
+
+// FWHTCols computes in place the unnormalized fast Walsh–Hadamard transform
+// of each column of m, computing H * m where H is the Sylvester–Hadamard
+// matrix of order equal to the number of rows of m. FWHTCols will panic if the
+// number of rows of m is not a power of two.
+func FWHTCols(m *Dense) {
+	r, c := m.Dims()
+	if r <= 0 || r&(r-1) != 0 {
+		panic(badHadamardSize)
+	}
+	// Transform whole rows at a time so that the innermost
+	// loops run over contiguous memory.
+	stride := m.mat.Stride
+	data := m.mat.Data
+	for h := 1; h < r; h *= 2 {
+		for i := 0; i < r; i += 2 * h {
+			for j := i; j < i+h; j++ {
+				u := data[j*stride : j*stride+c]
+				w := data[(j+h)*stride : (j+h)*stride+c]
+				for k, x := range u {
+					y := w[k]
+					u[k] = x + y
+					w[k] = x - y
+				}
+			}
+		}
+	}
+}
+
+// fwht computes the in-place Walsh–Hadamard transform of the n elements of
+// x with increment inc.
+func fwht(x []float64, inc, n int) {
+	for h := 1; h < n; h *= 2 {
+		for i := 0; i < n; i += 2 * h {
+			for j := i; j < i+h; j++ {
+				a, b := x[j*inc], x[(j+h)*inc]
+				x[j*inc] = a + b
+				x[(j+h)*inc] = a - b
+			}
+		}
+	}
+}
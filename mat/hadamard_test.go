@@ -0,0 +1,102 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestHadamard(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 4, 8, 64} {
+		for _, normalized := range []bool{false, true} {
+			h := NewHadamard(n, normalized)
+
+			// Check the Sylvester construction H_2n = [H H; H -H].
+			if n > 1 {
+				half := NewHadamard(n/2, false)
+				scale := h.At(0, 0)
+				for i := 0; i < n; i++ {
+					for j := 0; j < n; j++ {
+						want := scale * half.At(i%(n/2), j%(n/2))
+						if i >= n/2 && j >= n/2 {
+							want = -want
+						}
+						if h.At(i, j) != want {
+							t.Fatalf("n=%d normalized=%t: unexpected element at (%d,%d)", n, normalized, i, j)
+						}
+					}
+				}
+			}
+			if normalized {
+				hd := DenseCopyOf(h)
+				if !isOrthonormal(hd, 1e-14) {
+					t.Errorf("n=%d: normalized Hadamard matrix not orthogonal", n)
+				}
+			}
+
+			x := NewVecDense(n, nil)
+			for i := 0; i < n; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			var got, want VecDense
+			h.MulVecTo(&got, false, x)
+			want.MulVec(DenseCopyOf(h), x)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("n=%d normalized=%t: unexpected product", n, normalized)
+			}
+
+			// In-place product.
+			h.MulVecTo(x, false, x)
+			if !EqualApprox(x, &want, 1e-12) {
+				t.Errorf("n=%d normalized=%t: unexpected in-place product", n, normalized)
+			}
+		}
+	}
+
+	if p, _ := panics(func() { NewHadamard(6, false) }); !p {
+		t.Error("expected panic for order not a power of two")
+	}
+}
+
+func TestFWHT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 16
+
+	// Transform a strided vector view.
+	m := randomDense(n, 3, rnd)
+	col := m.ColView(1).(*VecDense)
+	var want VecDense
+	want.MulVec(DenseCopyOf(NewHadamard(n, false)), col)
+	FWHT(col)
+	if !EqualApprox(col, &want, 1e-12) {
+		t.Errorf("unexpected transform of strided vector:\ngot: %v\nwant:%v", col, &want)
+	}
+
+	// FWHT applied twice scales by n.
+	x := NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x.SetVec(i, rnd.NormFloat64())
+	}
+	orig := VecDenseCopyOf(x)
+	FWHT(x)
+	FWHT(x)
+	x.ScaleVec(1.0/n, x)
+	if !EqualApprox(x, orig, 1e-14) {
+		t.Error("inverse transform does not recover the input")
+	}
+
+	a := randomDense(n, 5, rnd)
+	var wantCols Dense
+	wantCols.Mul(NewHadamard(n, false), a)
+	FWHTCols(a)
+	if !EqualApprox(a, &wantCols, 1e-12) {
+		t.Errorf("unexpected column transform:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(&wantCols))
+	}
+}
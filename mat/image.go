@@ -0,0 +1,149 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// ImageLayout specifies the arrangement of the color channels of an image
+// in a matrix.
+type ImageLayout int
+
+const (
+	// LayoutHWC interleaves the channels of each pixel along the rows of
+	// the matrix, so that an h×w image with c channels is held in an
+	// h×(w*c) matrix with the channel k of the pixel at (x, y) in row y,
+	// column x*c+k.
+	LayoutHWC ImageLayout = iota
+
+	// LayoutCHW stacks the channel planes of the image vertically, so that
+	// an h×w image with c channels is held in an (c*h)×w matrix with the
+	// channel k of the pixel at (x, y) in row k*h+y, column x.
+	LayoutCHW
+)
+
+// imageChannels is the number of channels used for color images.
+const imageChannels = 4
+
+// DenseFromGray returns a newly allocated matrix holding the gray level of
+// the pixels of img, with the pixel at (x, y) relative to the minimum point
+// of the image bounds in row y, column x. Gray levels are scaled so that
+// full intensity corresponds to scale. For example, a scale of 1 gives
+// values in [0, 1] and a scale of 255 gives values in [0, 255].
+func DenseFromGray(img image.Image, scale float64) *Dense {
+	b := img.Bounds()
+	m := NewDense(b.Dy(), b.Dx(), nil)
+	f := scale / math.MaxUint16
+	for y := 0; y < b.Dy(); y++ {
+		row := m.RawRowView(y)
+		for x := range row {
+			g := color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray16)
+			row[x] = f * float64(g.Y)
+		}
+	}
+	return m
+}
+
+// DenseFromRGBA returns a newly allocated matrix holding the non-alpha-
+// premultiplied red, green, blue and alpha channels of the pixels of img,
+// arranged according to layout. Channel values are scaled so that full
+// intensity corresponds to scale.
+func DenseFromRGBA(img image.Image, layout ImageLayout, scale float64) *Dense {
+	b := img.Bounds()
+	h, w := b.Dy(), b.Dx()
+	var m *Dense
+	switch layout {
+	default:
+		panic("mat: bad image layout")
+	case LayoutHWC:
+		m = NewDense(h, imageChannels*w, nil)
+	case LayoutCHW:
+		m = NewDense(imageChannels*h, w, nil)
+	}
+	f := scale / math.MaxUint16
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA64Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA64)
+			for k, v := range [imageChannels]uint16{c.R, c.G, c.B, c.A} {
+				if layout == LayoutHWC {
+					m.set(y, imageChannels*x+k, f*float64(v))
+				} else {
+					m.set(k*h+y, x, f*float64(v))
+				}
+			}
+		}
+	}
+	return m
+}
+
+// GrayFromDense returns a newly allocated gray image with the pixel at
+// (x, y) holding the value of m at row y, column x. Values are scaled so
+// that scale corresponds to full intensity and are clamped to the range
+// of the image.
+func GrayFromDense(m Matrix, scale float64) *image.Gray16 {
+	r, c := m.Dims()
+	img := image.NewGray16(image.Rect(0, 0, c, r))
+	for y := 0; y < r; y++ {
+		for x := 0; x < c; x++ {
+			img.SetGray16(x, y, color.Gray16{Y: toPixel(m.At(y, x), scale)})
+		}
+	}
+	return img
+}
+
+// RGBAFromDense returns a newly allocated image from the red, green, blue
+// and alpha channels held in m arranged according to layout. Values are
+// scaled so that scale corresponds to full intensity and are clamped to the
+// range of the image. RGBAFromDense will panic if the dimensions of m are
+// not consistent with four channels in the given layout.
+func RGBAFromDense(m Matrix, layout ImageLayout, scale float64) *image.NRGBA64 {
+	r, c := m.Dims()
+	var h, w int
+	switch layout {
+	default:
+		panic("mat: bad image layout")
+	case LayoutHWC:
+		if c%imageChannels != 0 {
+			panic(ErrShape)
+		}
+		h, w = r, c/imageChannels
+	case LayoutCHW:
+		if r%imageChannels != 0 {
+			panic(ErrShape)
+		}
+		h, w = r/imageChannels, c
+	}
+	img := image.NewNRGBA64(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v [imageChannels]uint16
+			for k := range v {
+				if layout == LayoutHWC {
+					v[k] = toPixel(m.At(y, imageChannels*x+k), scale)
+				} else {
+					v[k] = toPixel(m.At(k*h+y, x), scale)
+				}
+			}
+			img.SetNRGBA64(x, y, color.NRGBA64{R: v[0], G: v[1], B: v[2], A: v[3]})
+		}
+	}
+	return img
+}
+
+// toPixel returns the 16-bit channel value corresponding to v for
+// the given full intensity scale.
+func toPixel(v, scale float64) uint16 {
+	p := math.Round(v / scale * math.MaxUint16)
+	switch {
+	case p <= 0 || math.IsNaN(p):
+		return 0
+	case p >= math.MaxUint16:
+		return math.MaxUint16
+	}
+	return uint16(p)
+}
@@ -0,0 +1,90 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageGray(t *testing.T) {
+	t.Parallel()
+	img := image.NewGray(image.Rect(2, 3, 5, 5))
+	img.SetGray(2, 3, color.Gray{Y: 0})
+	img.SetGray(3, 3, color.Gray{Y: 255})
+	img.SetGray(4, 3, color.Gray{Y: 51})
+	img.SetGray(2, 4, color.Gray{Y: 102})
+	img.SetGray(3, 4, color.Gray{Y: 204})
+	img.SetGray(4, 4, color.Gray{Y: 153})
+
+	got := DenseFromGray(img, 255)
+	want := NewDense(2, 3, []float64{
+		0, 255, 51,
+		102, 204, 153,
+	})
+	if !EqualApprox(got, want, 1e-12) {
+		t.Errorf("unexpected gray matrix:\ngot:  %v\nwant: %v", Formatted(got), Formatted(want))
+	}
+
+	back := GrayFromDense(got, 255)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			g := color.GrayModel.Convert(back.At(x, y)).(color.Gray)
+			if w := img.GrayAt(x+2, y+3); g != w {
+				t.Errorf("unexpected pixel at (%d, %d): got %v, want %v", x, y, g, w)
+			}
+		}
+	}
+
+	clamp := GrayFromDense(NewDense(1, 2, []float64{-1, 2}), 1)
+	if clamp.Gray16At(0, 0).Y != 0 || clamp.Gray16At(1, 0).Y != 0xffff {
+		t.Errorf("values not clamped")
+	}
+}
+
+func TestImageRGBA(t *testing.T) {
+	t.Parallel()
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, G: 0, B: 51, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{R: 102, G: 204, B: 0, A: 51})
+
+	for _, test := range []struct {
+		layout ImageLayout
+		want   *Dense
+	}{
+		{
+			layout: LayoutHWC,
+			want:   NewDense(1, 8, []float64{1, 0, 0.2, 1, 0.4, 0.8, 0, 0.2}),
+		},
+		{
+			layout: LayoutCHW,
+			want: NewDense(4, 2, []float64{
+				1, 0.4,
+				0, 0.8,
+				0.2, 0,
+				1, 0.2,
+			}),
+		},
+	} {
+		// Non-opaque pixels lose precision through alpha
+		// premultiplication in the image/color package.
+		got := DenseFromRGBA(img, test.layout, 1)
+		if !EqualApprox(got, test.want, 1e-3) {
+			t.Errorf("layout %d: unexpected matrix:\ngot:  %v\nwant: %v", test.layout, Formatted(got), Formatted(test.want))
+		}
+		back := RGBAFromDense(got, test.layout, 1)
+		for x := 0; x < 2; x++ {
+			c := color.NRGBAModel.Convert(back.At(x, 0)).(color.NRGBA)
+			if w := img.NRGBAAt(x, 0); c != w {
+				t.Errorf("layout %d: unexpected pixel at x=%d: got %v, want %v", test.layout, x, c, w)
+			}
+		}
+	}
+
+	if p, _ := panics(func() { RGBAFromDense(NewDense(2, 3, nil), LayoutHWC, 1) }); !p {
+		t.Errorf("expected panic for bad HWC shape")
+	}
+}
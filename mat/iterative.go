@@ -0,0 +1,543 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+)
+
+var (
+	_ MulVecToer = (*BandDense)(nil)
+	_ MulVecToer = (*SymBandDense)(nil)
+	_ MulVecToer = (*Tridiag)(nil)
+	_ MulVecToer = (*CSR)(nil)
+	_ MulVecToer = (*CSC)(nil)
+	_ MulVecToer = MatrixOperator{}
+
+	_ Preconditioner = (*Jacobi)(nil)
+	_ Preconditioner = (*ILU0)(nil)
+)
+
+// MulVecToer is a linear operator that can compute A * x or Aᵀ * x
+// without exposing its elements. It allows iterative solvers to work
+// with sparse and matrix-free representations of a system.
+type MulVecToer interface {
+	// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+	MulVecTo(dst *VecDense, trans bool, x Vector)
+}
+
+// MatrixOperator adapts a Matrix to the MulVecToer interface.
+type MatrixOperator struct {
+	Matrix Matrix
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (op MatrixOperator) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	if trans {
+		dst.MulVec(op.Matrix.T(), x)
+		return
+	}
+	dst.MulVec(op.Matrix, x)
+}
+
+// Preconditioner is an approximation M of a system matrix A for which
+// systems M z = r are cheap to solve.
+type Preconditioner interface {
+	// PrecondVecTo solves M z = r, storing z into dst.
+	PrecondVecTo(dst *VecDense, r Vector)
+}
+
+// IterativeSettings holds the settings for the iterative solvers
+// SolveCG, SolveGMRES and SolveBiCGSTAB. The zero value of each
+// field selects its default.
+type IterativeSettings struct {
+	// Tolerance is the relative residual tolerance at which the
+	// iteration is considered to have converged, that is
+	//  ‖b - A x‖₂ ≤ Tolerance ‖b‖₂.
+	// The default is 1e-8.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of iterations. The
+	// default is 10 times the dimension of the system.
+	MaxIterations int
+
+	// Restart is the number of iterations between restarts of
+	// GMRES. It is ignored by the other solvers. The default is
+	// the smaller of 30 and the dimension of the system.
+	Restart int
+
+	// Preconditioner is the preconditioner used by the solver.
+	// If it is nil, no preconditioning is performed.
+	Preconditioner Preconditioner
+
+	// Monitor, if not nil, is called after each iteration with
+	// the iteration number and the current residual norm.
+	Monitor func(iter int, residual float64)
+}
+
+// IterativeStats holds the statistics of a completed iterative solve.
+type IterativeStats struct {
+	// Iterations is the number of iterations performed.
+	Iterations int
+
+	// Residual is the norm of the final residual, ‖b - A x‖₂.
+	Residual float64
+}
+
+// iterativeState holds the resolved settings of an iterative solve.
+type iterativeState struct {
+	a      MulVecToer
+	b      Vector
+	n      int
+	tol    float64
+	bnorm  float64
+	maxIt  int
+	pre    Preconditioner
+	report func(iter int, residual float64)
+}
+
+// newIterativeState prepares dst and the solver settings for solving
+// a x = b. If dst is empty it is resized and zeroed, otherwise its
+// contents are used as the initial guess.
+func newIterativeState(dst *VecDense, a MulVecToer, b Vector, settings *IterativeSettings) *iterativeState {
+	n := b.Len()
+	if dst.IsEmpty() {
+		dst.reuseAsZeroed(n)
+	} else if dst.Len() != n {
+		panic(ErrShape)
+	}
+	if settings == nil {
+		settings = &IterativeSettings{}
+	}
+	s := &iterativeState{
+		a:      a,
+		b:      b,
+		n:      n,
+		tol:    settings.Tolerance,
+		maxIt:  settings.MaxIterations,
+		pre:    settings.Preconditioner,
+		report: settings.Monitor,
+	}
+	if s.tol <= 0 {
+		s.tol = 1e-8
+	}
+	if s.maxIt <= 0 {
+		s.maxIt = 10 * n
+	}
+	s.bnorm = Norm(b, 2)
+	if s.bnorm == 0 {
+		s.bnorm = 1
+	}
+	return s
+}
+
+// residual computes r = b - A x.
+func (s *iterativeState) residual(r, x *VecDense) {
+	s.a.MulVecTo(r, false, x)
+	r.SubVec(s.b, r)
+}
+
+// precond computes z = M⁻¹ r.
+func (s *iterativeState) precond(z *VecDense, r Vector) {
+	if s.pre == nil {
+		z.CopyVec(r)
+		return
+	}
+	s.pre.PrecondVecTo(z, r)
+}
+
+// converged reports the residual norm of iteration iter and returns
+// whether it satisfies the tolerance.
+func (s *iterativeState) converged(iter int, rnorm float64) bool {
+	if s.report != nil {
+		s.report(iter, rnorm)
+	}
+	return rnorm <= s.tol*s.bnorm
+}
+
+// SolveCG solves the symmetric positive definite system a x = b using the
+// preconditioned conjugate gradient method, storing the solution into dst.
+// If dst is empty it is resized to the length of b and the initial guess
+// is zero, otherwise the contents of dst are used as the initial guess.
+// The preconditioner, if any, must also be symmetric positive definite.
+//
+// SolveCG returns the number of iterations performed and the final residual
+// norm. If the iteration fails to converge within the iteration limit, an
+// error is returned along with the most recent iterate in dst.
+func SolveCG(dst *VecDense, a MulVecToer, b Vector, settings *IterativeSettings) (IterativeStats, error) {
+	s := newIterativeState(dst, a, b, settings)
+	x := dst
+	n := s.n
+
+	r := NewVecDense(n, nil)
+	z := NewVecDense(n, nil)
+	p := NewVecDense(n, nil)
+	ap := NewVecDense(n, nil)
+
+	s.residual(r, x)
+	rnorm := Norm(r, 2)
+	if rnorm <= s.tol*s.bnorm {
+		return IterativeStats{Residual: rnorm}, nil
+	}
+	s.precond(z, r)
+	p.CopyVec(z)
+	rz := Dot(r, z)
+	for iter := 1; iter <= s.maxIt; iter++ {
+		a.MulVecTo(ap, false, p)
+		pap := Dot(p, ap)
+		if pap == 0 {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+		}
+		alpha := rz / pap
+		x.AddScaledVec(x, alpha, p)
+		r.AddScaledVec(r, -alpha, ap)
+		rnorm = Norm(r, 2)
+		if s.converged(iter, rnorm) {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, nil
+		}
+		s.precond(z, r)
+		rzNew := Dot(r, z)
+		beta := rzNew / rz
+		rz = rzNew
+		p.AddScaledVec(z, beta, p)
+	}
+	return IterativeStats{Iterations: s.maxIt, Residual: rnorm}, ErrFailedConvergence
+}
+
+// SolveBiCGSTAB solves the general square system a x = b using the
+// right-preconditioned biconjugate gradient stabilized method, storing the
+// solution into dst. If dst is empty it is resized to the length of b and
+// the initial guess is zero, otherwise the contents of dst are used as the
+// initial guess.
+//
+// SolveBiCGSTAB returns the number of iterations performed and the final
+// residual norm. If the iteration breaks down or fails to converge within
+// the iteration limit, an error is returned along with the most recent
+// iterate in dst.
+func SolveBiCGSTAB(dst *VecDense, a MulVecToer, b Vector, settings *IterativeSettings) (IterativeStats, error) {
+	s := newIterativeState(dst, a, b, settings)
+	x := dst
+	n := s.n
+
+	r := NewVecDense(n, nil)
+	rhat := NewVecDense(n, nil)
+	p := NewVecDense(n, nil)
+	v := NewVecDense(n, nil)
+	phat := NewVecDense(n, nil)
+	shat := NewVecDense(n, nil)
+	t := NewVecDense(n, nil)
+
+	s.residual(r, x)
+	rnorm := Norm(r, 2)
+	if rnorm <= s.tol*s.bnorm {
+		return IterativeStats{Residual: rnorm}, nil
+	}
+	rhat.CopyVec(r)
+	rho, alpha, omega := 1.0, 1.0, 1.0
+	for iter := 1; iter <= s.maxIt; iter++ {
+		rhoNew := Dot(rhat, r)
+		if rhoNew == 0 {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+		}
+		if iter == 1 {
+			p.CopyVec(r)
+		} else {
+			beta := (rhoNew / rho) * (alpha / omega)
+			// p = r + β(p - ωv)
+			p.AddScaledVec(p, -omega, v)
+			p.AddScaledVec(r, beta, p)
+		}
+		rho = rhoNew
+
+		s.precond(phat, p)
+		a.MulVecTo(v, false, phat)
+		rv := Dot(rhat, v)
+		if rv == 0 {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+		}
+		alpha = rho / rv
+
+		// Use r to hold s = r - αv.
+		r.AddScaledVec(r, -alpha, v)
+		x.AddScaledVec(x, alpha, phat)
+		rnorm = Norm(r, 2)
+		if rnorm <= s.tol*s.bnorm {
+			s.converged(iter, rnorm)
+			return IterativeStats{Iterations: iter, Residual: rnorm}, nil
+		}
+
+		s.precond(shat, r)
+		a.MulVecTo(t, false, shat)
+		tt := Dot(t, t)
+		if tt == 0 {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+		}
+		omega = Dot(t, r) / tt
+		x.AddScaledVec(x, omega, shat)
+		r.AddScaledVec(r, -omega, t)
+		rnorm = Norm(r, 2)
+		if s.converged(iter, rnorm) {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, nil
+		}
+		if omega == 0 {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+		}
+	}
+	return IterativeStats{Iterations: s.maxIt, Residual: rnorm}, ErrFailedConvergence
+}
+
+// SolveGMRES solves the general square system a x = b using the restarted
+// generalized minimal residual method with right preconditioning, storing
+// the solution into dst. If dst is empty it is resized to the length of b
+// and the initial guess is zero, otherwise the contents of dst are used as
+// the initial guess. The restart length is taken from settings.Restart.
+//
+// SolveGMRES returns the number of iterations performed and the final
+// residual norm. If the iteration fails to converge within the iteration
+// limit, an error is returned along with the most recent iterate in dst.
+func SolveGMRES(dst *VecDense, a MulVecToer, b Vector, settings *IterativeSettings) (IterativeStats, error) {
+	s := newIterativeState(dst, a, b, settings)
+	x := dst
+	n := s.n
+
+	m := 30
+	if settings != nil && settings.Restart > 0 {
+		m = settings.Restart
+	}
+	m = min(m, n)
+
+	// v holds the Krylov basis in its columns and h the
+	// Hessenberg matrix reduced to triangular form by the
+	// Givens rotations held in cs and sn.
+	v := NewDense(n, m+1, nil)
+	h := NewDense(m+1, m, nil)
+	cs := make([]float64, m)
+	sn := make([]float64, m)
+	g := make([]float64, m+1)
+	r := NewVecDense(n, nil)
+	w := NewVecDense(n, nil)
+	z := NewVecDense(n, nil)
+	y := NewVecDense(m, nil)
+
+	s.residual(r, x)
+	rnorm := Norm(r, 2)
+	if rnorm <= s.tol*s.bnorm {
+		return IterativeStats{Residual: rnorm}, nil
+	}
+	var iter int
+	for iter < s.maxIt {
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = rnorm
+		vj := v.ColView(0).(*VecDense)
+		vj.ScaleVec(1/rnorm, r)
+
+		var k int
+		for k < m && iter < s.maxIt {
+			iter++
+			s.precond(z, v.ColView(k))
+			a.MulVecTo(w, false, z)
+
+			// Modified Gram-Schmidt orthogonalization.
+			for i := 0; i <= k; i++ {
+				vi := v.ColView(i)
+				hik := Dot(w, vi)
+				h.set(i, k, hik)
+				w.AddScaledVec(w, -hik, vi)
+			}
+			hk1 := Norm(w, 2)
+			h.set(k+1, k, hk1)
+			if hk1 != 0 {
+				v.ColView(k+1).(*VecDense).ScaleVec(1/hk1, w)
+			}
+
+			// Apply the previous rotations to the new column
+			// and compute the rotation eliminating h[k+1, k].
+			for i := 0; i < k; i++ {
+				hi, hi1 := h.at(i, k), h.at(i+1, k)
+				h.set(i, k, cs[i]*hi+sn[i]*hi1)
+				h.set(i+1, k, -sn[i]*hi+cs[i]*hi1)
+			}
+			hkk := h.at(k, k)
+			d := math.Hypot(hkk, hk1)
+			if d == 0 {
+				cs[k], sn[k] = 1, 0
+			} else {
+				cs[k], sn[k] = hkk/d, hk1/d
+			}
+			h.set(k, k, d)
+			h.set(k+1, k, 0)
+			g[k+1] = -sn[k] * g[k]
+			g[k] *= cs[k]
+			k++
+
+			rnorm = math.Abs(g[k])
+			if s.converged(iter, rnorm) || hk1 == 0 {
+				break
+			}
+		}
+
+		// Solve the triangular system H y = g and update
+		// x += M⁻¹ V y.
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for j := i + 1; j < k; j++ {
+				sum -= h.at(i, j) * y.at(j)
+			}
+			hii := h.at(i, i)
+			if hii == 0 {
+				return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+			}
+			y.setVec(i, sum/hii)
+		}
+		w.MulVec(v.Slice(0, n, 0, k), y.SliceVec(0, k))
+		s.precond(z, w)
+		x.AddVec(x, z)
+
+		s.residual(r, x)
+		rnorm = Norm(r, 2)
+		if rnorm <= s.tol*s.bnorm {
+			return IterativeStats{Iterations: iter, Residual: rnorm}, nil
+		}
+	}
+	return IterativeStats{Iterations: iter, Residual: rnorm}, ErrFailedConvergence
+}
+
+// Jacobi is a diagonal preconditioner, M = diag(A).
+type Jacobi struct {
+	inv []float64
+}
+
+// NewJacobi returns a Jacobi preconditioner for the square matrix a.
+// NewJacobi will panic if a is not square or has a zero diagonal element.
+func NewJacobi(a Matrix) *Jacobi {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	inv := make([]float64, r)
+	for i := range inv {
+		d := a.At(i, i)
+		if d == 0 {
+			panic(ErrSingular)
+		}
+		inv[i] = 1 / d
+	}
+	return &Jacobi{inv: inv}
+}
+
+// PrecondVecTo solves M z = r, storing z into dst.
+func (j *Jacobi) PrecondVecTo(dst *VecDense, r Vector) {
+	n := len(j.inv)
+	if r.Len() != n {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(n)
+	for i, v := range j.inv {
+		dst.setVec(i, v*r.AtVec(i))
+	}
+}
+
+// ILU0 is an incomplete LU preconditioner with no fill-in. The factors
+// L and U have the same sparsity pattern as the lower and upper
+// triangles of A.
+type ILU0 struct {
+	// lu holds the strictly lower triangle of L, with an implicit
+	// unit diagonal, and the upper triangle of U in the pattern of A.
+	lu CSR
+	// diag holds the index into the compressed storage of lu of
+	// the diagonal element of each row.
+	diag []int
+}
+
+// NewILU0 returns the zero fill-in incomplete LU factorization of the square
+// sparse matrix a. NewILU0 will panic if a is not square. It returns
+// ErrSingular if a diagonal element is structurally or numerically zero
+// during the factorization.
+func NewILU0(a *CSR) (*ILU0, error) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	var f ILU0
+	f.lu.setCompressed(r, c,
+		append([]int(nil), a.indptr...),
+		append([]int(nil), a.ind...),
+		append([]float64(nil), a.data...),
+	)
+	ind, ptr, val := f.lu.ind, f.lu.indptr, f.lu.data
+
+	f.diag = make([]int, r)
+	for i := 0; i < r; i++ {
+		cols := ind[ptr[i]:ptr[i+1]]
+		k := sort.SearchInts(cols, i)
+		if k == len(cols) || cols[k] != i {
+			return nil, ErrSingular
+		}
+		f.diag[i] = ptr[i] + k
+	}
+
+	// pos maps a column index to its position in the current row.
+	pos := make([]int, r)
+	for i := range pos {
+		pos[i] = -1
+	}
+	for i := 0; i < r; i++ {
+		for p := ptr[i]; p < ptr[i+1]; p++ {
+			pos[ind[p]] = p
+		}
+		for p := ptr[i]; p < f.diag[i]; p++ {
+			k := ind[p]
+			ukk := val[f.diag[k]]
+			if ukk == 0 {
+				return nil, ErrSingular
+			}
+			val[p] /= ukk
+			lik := val[p]
+			for q := f.diag[k] + 1; q < ptr[k+1]; q++ {
+				if j := pos[ind[q]]; j >= 0 {
+					val[j] -= lik * val[q]
+				}
+			}
+		}
+		for p := ptr[i]; p < ptr[i+1]; p++ {
+			pos[ind[p]] = -1
+		}
+		if val[f.diag[i]] == 0 {
+			return nil, ErrSingular
+		}
+	}
+	return &f, nil
+}
+
+// PrecondVecTo solves L U z = r, storing z into dst.
+func (f *ILU0) PrecondVecTo(dst *VecDense, r Vector) {
+	n := len(f.diag)
+	if r.Len() != n {
+		panic(ErrShape)
+	}
+	ind, ptr, val := f.lu.ind, f.lu.indptr, f.lu.data
+	z := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := r.AtVec(i)
+		for p := ptr[i]; p < f.diag[i]; p++ {
+			sum -= val[p] * z[ind[p]]
+		}
+		z[i] = sum
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := z[i]
+		for p := f.diag[i] + 1; p < ptr[i+1]; p++ {
+			sum -= val[p] * z[ind[p]]
+		}
+		z[i] = sum / val[f.diag[i]]
+	}
+	dst.reuseAsNonZeroed(n)
+	for i, v := range z {
+		dst.setVec(i, v)
+	}
+}
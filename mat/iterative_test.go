@@ -0,0 +1,169 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// poisson2D returns the n²×n² matrix of the five-point finite difference
+// discretization of the Laplacian on an n×n grid.
+func poisson2D(n int) *CSR {
+	var m COO
+	m.r, m.c = n*n, n*n
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			m.Append(k, k, 4)
+			if i > 0 {
+				m.Append(k, k-n, -1)
+			}
+			if i < n-1 {
+				m.Append(k, k+n, -1)
+			}
+			if j > 0 {
+				m.Append(k, k-1, -1)
+			}
+			if j < n-1 {
+				m.Append(k, k+1, -1)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+func TestIterativeSolvers(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	type solver func(*VecDense, MulVecToer, Vector, *IterativeSettings) (IterativeStats, error)
+	solvers := []struct {
+		name    string
+		solve   solver
+		symOnly bool
+	}{
+		{name: "CG", solve: SolveCG, symOnly: true},
+		{name: "BiCGSTAB", solve: SolveBiCGSTAB},
+		{name: "GMRES", solve: SolveGMRES},
+	}
+
+	spd := poisson2D(8)
+	n, _ := spd.Dims()
+
+	// Make a non-symmetric diagonally dominant system
+	// with the sparsity pattern of spd.
+	nsCOO := &COO{r: n, c: n}
+	spd.DoNonZero(func(i, j int, v float64) {
+		if i != j {
+			v += 0.5 * rnd.NormFloat64()
+		}
+		nsCOO.Append(i, j, v)
+	})
+	nonsym := nsCOO.ToCSR()
+
+	const tol = 1e-10
+	for _, sv := range solvers {
+		for _, sys := range []struct {
+			name string
+			a    *CSR
+			sym  bool
+		}{
+			{name: "spd", a: spd, sym: true},
+			{name: "nonsym", a: nonsym},
+		} {
+			if sv.symOnly && !sys.sym {
+				continue
+			}
+			ilu, err := NewILU0(sys.a)
+			if err != nil {
+				t.Fatalf("unexpected error from NewILU0: %v", err)
+			}
+			for _, pc := range []struct {
+				name string
+				p    Preconditioner
+			}{
+				{name: "none"},
+				{name: "jacobi", p: NewJacobi(sys.a)},
+				{name: "ilu0", p: ilu},
+			} {
+				if sv.symOnly && pc.name == "ilu0" {
+					// ILU(0) of a symmetric matrix is not symmetric.
+					continue
+				}
+				for _, op := range []struct {
+					name string
+					a    MulVecToer
+				}{
+					{name: "CSR", a: sys.a},
+					{name: "Dense", a: MatrixOperator{DenseCopyOf(sys.a)}},
+				} {
+					name := fmt.Sprintf("%s/%s/%s/%s", sv.name, sys.name, pc.name, op.name)
+					want := NewVecDense(n, nil)
+					for i := 0; i < n; i++ {
+						want.SetVec(i, rnd.NormFloat64())
+					}
+					var b VecDense
+					b.MulVec(sys.a, want)
+
+					var calls int
+					settings := &IterativeSettings{
+						Tolerance:      tol,
+						Restart:        20,
+						Preconditioner: pc.p,
+						Monitor:        func(int, float64) { calls++ },
+					}
+					var x VecDense
+					stats, err := sv.solve(&x, op.a, &b, settings)
+					if err != nil {
+						t.Errorf("%s: unexpected error: %v", name, err)
+						continue
+					}
+					if calls != stats.Iterations {
+						t.Errorf("%s: unexpected number of monitor calls: got %d, want %d", name, calls, stats.Iterations)
+					}
+					var r VecDense
+					r.MulVec(sys.a, &x)
+					r.SubVec(&b, &r)
+					if res := Norm(&r, 2); res > 10*tol*Norm(&b, 2) {
+						t.Errorf("%s: residual too large: %v", name, res)
+					}
+					if !EqualApprox(&x, want, 1e-6) {
+						t.Errorf("%s: unexpected solution", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIterativeInitialGuess(t *testing.T) {
+	t.Parallel()
+	a := poisson2D(4)
+	n, _ := a.Dims()
+	want := NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		want.SetVec(i, float64(i))
+	}
+	var b VecDense
+	b.MulVec(a, want)
+
+	x := VecDenseCopyOf(want)
+	stats, err := SolveCG(x, a, &b, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Iterations != 0 {
+		t.Errorf("unexpected iterations for exact initial guess: got %d, want 0", stats.Iterations)
+	}
+
+	var y VecDense
+	_, err = SolveGMRES(&y, a, &b, &IterativeSettings{MaxIterations: 2})
+	if err != ErrFailedConvergence {
+		t.Errorf("unexpected error for limited iterations: got %v, want %v", err, ErrFailedConvergence)
+	}
+}
@@ -0,0 +1,577 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+const badKrylov = "mat: invalid number of eigenvalues requested"
+
+// EigenWhich specifies the part of the spectrum computed by a partial
+// eigensolver.
+type EigenWhich int
+
+const (
+	// EigenLargest specifies the eigenvalues with the largest
+	// real part.
+	EigenLargest EigenWhich = iota
+
+	// EigenSmallest specifies the eigenvalues with the smallest
+	// real part.
+	EigenSmallest
+
+	// EigenLargestMagnitude specifies the eigenvalues with the
+	// largest absolute value.
+	EigenLargestMagnitude
+)
+
+// KrylovSettings holds the settings for the partial eigensolvers. The zero
+// value of each field selects its default.
+type KrylovSettings struct {
+	// Tolerance is the relative residual tolerance at which a Ritz pair
+	// (θ, x) is considered to have converged, that is
+	//  ‖A x - θ x‖₂ ≤ Tolerance * max |θ|
+	// where the maximum is over the Ritz values of the current
+	// subspace. The default is 1e-10.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of restarts. The default
+	// is 300.
+	MaxIterations int
+
+	// SubspaceDim is the dimension of the Krylov subspace built
+	// between restarts. It is increased to be at least two greater than
+	// the number of requested eigenvalues and reduced to be at most the
+	// dimension of the operator. The default is max(2k+1, 20) for k requested
+	// eigenvalues.
+	SubspaceDim int
+
+	// Src is the source of randomness used to generate the starting
+	// vector. If Src is nil, the global random source is used.
+	Src rand.Source
+}
+
+// krylovState holds the resolved settings of a partial eigensolve.
+type krylovState struct {
+	a      MulVecToer
+	n      int
+	k      int
+	m      int
+	tol    float64
+	maxIt  int
+	normal func() float64
+}
+
+// newKrylovState resolves the settings for computing k eigenvalues of
+// the n×n operator a. newKrylovState will panic if k is not positive or
+// is greater than n.
+func newKrylovState(a MulVecToer, n, k int, settings *KrylovSettings) *krylovState {
+	if k < 1 || n < k {
+		panic(badKrylov)
+	}
+	if settings == nil {
+		settings = &KrylovSettings{}
+	}
+	s := &krylovState{
+		a:      a,
+		n:      n,
+		k:      k,
+		m:      settings.SubspaceDim,
+		tol:    settings.Tolerance,
+		maxIt:  settings.MaxIterations,
+		normal: rand.NormFloat64,
+	}
+	if settings.Src != nil {
+		s.normal = rand.New(settings.Src).NormFloat64
+	}
+	if s.m <= 0 {
+		s.m = max(2*k+1, 20)
+	}
+	s.m = min(max(s.m, k+2), n)
+	if s.tol <= 0 {
+		s.tol = 1e-10
+	}
+	if s.maxIt <= 0 {
+		s.maxIt = 300
+	}
+	return s
+}
+
+// randomOrthogonal sets column j of v to a random unit vector orthogonal
+// to the preceding columns. If no such vector exists, the column is set
+// to zero.
+func (s *krylovState) randomOrthogonal(v *Dense, j int) {
+	vj := v.ColView(j).(*VecDense)
+	if j >= s.n {
+		vj.Zero()
+		return
+	}
+	for {
+		for i := 0; i < s.n; i++ {
+			vj.setVec(i, s.normal())
+		}
+		vj.ScaleVec(1/Norm(vj, 2), vj)
+		if j > 0 {
+			s.orthogonalize(vj, v.Slice(0, s.n, 0, j).(*Dense), nil)
+		}
+		nrm := Norm(vj, 2)
+		if nrm > 0.5 {
+			vj.ScaleVec(1/nrm, vj)
+			return
+		}
+	}
+}
+
+// orthogonalize orthogonalizes w against the columns of v using classical
+// Gram-Schmidt with one step of reorthogonalization. If h is not nil, the
+// projection coefficients vᵀw are accumulated into h.
+func (s *krylovState) orthogonalize(w *VecDense, v *Dense, h *VecDense) {
+	var c VecDense
+	for pass := 0; pass < 2; pass++ {
+		c.Reset()
+		c.MulVec(v.T(), w)
+		var vc VecDense
+		vc.MulVec(v, &c)
+		w.SubVec(w, &vc)
+		if h != nil {
+			h.AddVec(h, &c)
+		}
+	}
+}
+
+// extend extends the Krylov decomposition
+//  A V[:, :from] = V[:, :from+1] H[:from+1, :from]
+// to a decomposition of size to, using full reorthogonalization. The
+// columns of V must be orthonormal and H is an (m+1)×m matrix.
+func (s *krylovState) extend(v, h *Dense, from, to int) {
+	w := NewVecDense(s.n, nil)
+	for j := from; j < to; j++ {
+		s.a.MulVecTo(w, false, v.ColView(j))
+		wnorm := Norm(w, 2)
+		hj := h.ColView(j).(*VecDense).SliceVec(0, j+1).(*VecDense)
+		hj.Zero()
+		s.orthogonalize(w, v.Slice(0, s.n, 0, j+1).(*Dense), hj)
+		beta := Norm(w, 2)
+		if beta <= 1e-12*wnorm || beta == 0 {
+			// The Krylov subspace is invariant, so continue
+			// with a new direction.
+			h.set(j+1, j, 0)
+			s.randomOrthogonal(v, j+1)
+			continue
+		}
+		h.set(j+1, j, beta)
+		v.ColView(j+1).(*VecDense).ScaleVec(1/beta, w)
+	}
+}
+
+// sortRitz returns the indices of the Ritz values with real parts re and
+// imaginary parts im ordered from most to least wanted. im may be nil.
+func sortRitz(re, im []float64, which EigenWhich) []int {
+	idx := make([]int, len(re))
+	for i := range idx {
+		idx[i] = i
+	}
+	imag := func(i int) float64 {
+		if im == nil {
+			return 0
+		}
+		return im[i]
+	}
+	var less func(i, j int) bool
+	switch which {
+	default:
+		panic("mat: bad eigenvalue selection")
+	case EigenLargest:
+		less = func(i, j int) bool { return re[idx[i]] > re[idx[j]] }
+	case EigenSmallest:
+		less = func(i, j int) bool { return re[idx[i]] < re[idx[j]] }
+	case EigenLargestMagnitude:
+		less = func(i, j int) bool {
+			return math.Hypot(re[idx[i]], imag(idx[i])) > math.Hypot(re[idx[j]], imag(idx[j]))
+		}
+	}
+	sort.SliceStable(idx, less)
+	return idx
+}
+
+// EigenSymPartial is a type for computing a few eigenvalues and eigenvectors
+// of a large symmetric linear operator.
+type EigenSymPartial struct {
+	values  []float64
+	vectors *Dense
+
+	iterations int
+}
+
+// Factorize computes k eigenvalues and eigenvectors of the n×n symmetric
+// operator a from the part of the spectrum specified by which, using the
+// thick-restart Lanczos method with full reorthogonalization. Only products
+// of a with vectors are computed, so a may be sparse or matrix-free. If
+// settings is nil, default settings are used.
+//
+// Factorize will panic if k is not positive or is greater than n.
+//
+// Factorize returns whether all k eigenpairs converged within the iteration
+// limit. If the factorization failed, methods that require a successful
+// factorization will panic.
+func (e *EigenSymPartial) Factorize(a MulVecToer, n, k int, which EigenWhich, settings *KrylovSettings) (ok bool) {
+	e.values = nil
+	e.vectors = nil
+	e.iterations = 0
+
+	s := newKrylovState(a, n, k, settings)
+	m := s.m
+
+	// v holds the Krylov basis in its columns and h the projection
+	// of the operator onto the basis. After a restart the leading
+	// block of h is diagonal with its final row holding the
+	// coupling to the residual vector.
+	v := NewDense(n, m+1, nil)
+	h := NewDense(m+1, m, nil)
+	s.randomOrthogonal(v, 0)
+
+	var (
+		t     = NewSymDense(m, nil)
+		eig   EigenSym
+		y     Dense
+		theta []float64
+		res   = make([]float64, m)
+	)
+	var start int
+	for iter := 1; iter <= s.maxIt; iter++ {
+		s.extend(v, h, start, m)
+
+		for i := 0; i < m; i++ {
+			for j := i; j < m; j++ {
+				t.SetSym(i, j, 0.5*(h.at(i, j)+h.at(j, i)))
+			}
+		}
+		if !eig.Factorize(t, true) {
+			return false
+		}
+		theta = eig.Values(theta)
+		eig.VectorsTo(&y)
+
+		// Compute the residual norms of the Ritz pairs from the
+		// coupling to the final basis vector.
+		var scale float64
+		for i, th := range theta {
+			var r float64
+			for j := 0; j < m; j++ {
+				r += h.at(m, j) * y.at(j, i)
+			}
+			res[i] = math.Abs(r)
+			scale = math.Max(scale, math.Abs(th))
+		}
+		if scale == 0 {
+			scale = 1
+		}
+		idx := sortRitz(theta, nil, which)
+		var nconv int
+		for _, i := range idx[:k] {
+			if res[i] <= s.tol*scale {
+				nconv++
+			}
+		}
+
+		// Reorder the Ritz vectors from most to least wanted.
+		ys := NewDense(m, m, nil)
+		vals := make([]float64, m)
+		coupling := make([]float64, m)
+		for p, i := range idx {
+			vals[p] = theta[i]
+			for j := 0; j < m; j++ {
+				ys.set(j, p, y.at(j, i))
+				coupling[p] += h.at(m, j) * y.at(j, i)
+			}
+		}
+
+		if nconv == k {
+			e.values = vals[:k]
+			e.vectors = NewDense(n, k, nil)
+			e.vectors.Mul(v.Slice(0, n, 0, m), ys.Slice(0, m, 0, k))
+			e.iterations = iter
+			return true
+		}
+
+		// Restart keeping the most wanted Ritz vectors.
+		keep := min(k+min(nconv, (m-k)/2), m-1)
+		var vk Dense
+		vk.Mul(v.Slice(0, n, 0, m), ys.Slice(0, m, 0, keep))
+		v.Slice(0, n, 0, keep).(*Dense).Copy(&vk)
+		v.ColView(keep).(*VecDense).CopyVec(v.ColView(m))
+		h.Zero()
+		for p := 0; p < keep; p++ {
+			h.set(p, p, vals[p])
+			h.set(keep, p, coupling[p])
+		}
+		start = keep
+	}
+	return false
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *EigenSymPartial) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Iterations returns the number of restarts performed during the
+// factorization. Iterations will panic if the receiver does not contain a
+// successful factorization.
+func (e *EigenSymPartial) Iterations() int {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	return e.iterations
+}
+
+// Values extracts the computed eigenvalues ordered from most to least wanted.
+// If dst is non-nil, the values are stored in-place into dst. In this case dst
+// must have length k, otherwise Values will panic. If dst is nil, then a new
+// slice will be allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the receiver does not contain a successful factorization.
+func (e *EigenSymPartial) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the eigenvectors of the decomposition into the columns of
+// dst, in the order of the values returned by Values.
+//
+// If dst is empty, VectorsTo will resize dst to be n×k. When dst is
+// non-empty, VectorsTo will panic if dst is not n×k. VectorsTo will also
+// panic if the receiver does not contain a successful factorization.
+func (e *EigenSymPartial) VectorsTo(dst *Dense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	r, c := e.vectors.Dims()
+	dst.reuseAsNonZeroed(r, c)
+	dst.Copy(e.vectors)
+}
+
+// EigenPartial is a type for computing a few eigenvalues and eigenvectors of
+// a large general linear operator.
+type EigenPartial struct {
+	values  []complex128
+	vectors *CDense
+
+	iterations int
+}
+
+// Factorize computes k eigenvalues and right eigenvectors of the n×n
+// operator a from the part of the spectrum specified by which, using the
+// Krylov-Schur method, a form of the implicitly restarted Arnoldi method. Only
+// products of a with vectors are computed, so a may be sparse or matrix-free.
+// If settings is nil, default settings are used.
+//
+// Complex eigenvalues of a real operator occur in conjugate pairs. Only one
+// member of a pair is returned if the other is not among the k most wanted
+// eigenvalues.
+//
+// Factorize will panic if k is not positive or is greater than n.
+//
+// Factorize returns whether all k eigenpairs converged within the iteration
+// limit. If the factorization failed, methods that require a successful
+// factorization will panic.
+func (e *EigenPartial) Factorize(a MulVecToer, n, k int, which EigenWhich, settings *KrylovSettings) (ok bool) {
+	e.values = nil
+	e.vectors = nil
+	e.iterations = 0
+
+	s := newKrylovState(a, n, k, settings)
+	m := s.m
+
+	// v holds the Krylov basis in its columns and h the projection
+	// of the operator onto the basis. After a restart the leading
+	// block of h is in real Schur form with its final row holding
+	// the coupling to the residual vector.
+	v := NewDense(n, m+1, nil)
+	h := NewDense(m+1, m, nil)
+	s.randomOrthogonal(v, 0)
+
+	var (
+		proj   = NewDense(m, m, nil)
+		eig    Eigen
+		y      CDense
+		schur  Schur
+		z, t   Dense
+		re, im = make([]float64, m), make([]float64, m)
+	)
+	var start int
+	for iter := 1; iter <= s.maxIt; iter++ {
+		s.extend(v, h, start, m)
+		proj.Copy(h.Slice(0, m, 0, m))
+
+		if !eig.Factorize(proj, EigenRight) {
+			return false
+		}
+		vals := eig.Values(nil)
+		y.Reset()
+		eig.VectorsTo(&y)
+
+		// Compute the residual norms of the Ritz pairs from the
+		// coupling to the final basis vector.
+		var scale float64
+		for i, l := range vals {
+			re[i], im[i] = real(l), imag(l)
+			scale = math.Max(scale, math.Hypot(re[i], im[i]))
+		}
+		if scale == 0 {
+			scale = 1
+		}
+		idx := sortRitz(re, im, which)
+		var nconv int
+		for _, i := range idx[:k] {
+			var r complex128
+			for j := 0; j < m; j++ {
+				r += complex(h.at(m, j), 0) * y.At(j, i)
+			}
+			if math.Hypot(real(r), imag(r)) <= s.tol*scale {
+				nconv++
+			}
+		}
+
+		if nconv == k {
+			yr := NewDense(m, k, nil)
+			yi := NewDense(m, k, nil)
+			e.values = make([]complex128, k)
+			for p, i := range idx[:k] {
+				e.values[p] = vals[i]
+				for j := 0; j < m; j++ {
+					c := y.At(j, i)
+					yr.set(j, p, real(c))
+					yi.set(j, p, imag(c))
+				}
+			}
+			var xr, xi Dense
+			xr.Mul(v.Slice(0, n, 0, m), yr)
+			xi.Mul(v.Slice(0, n, 0, m), yi)
+			e.vectors = NewCDense(n, k, nil)
+			for i := 0; i < n; i++ {
+				for p := 0; p < k; p++ {
+					e.vectors.set(i, p, complex(xr.at(i, p), xi.at(i, p)))
+				}
+			}
+			e.iterations = iter
+			return true
+		}
+
+		// Restart keeping the Schur vectors of the most wanted
+		// Ritz values, without splitting complex conjugate pairs.
+		if !schur.Factorize(proj) {
+			return false
+		}
+		svals := schur.Values(nil)
+		for i, l := range svals {
+			re[i], im[i] = real(l), imag(l)
+		}
+		sidx := sortRitz(re, im, which)
+		keep := min(k+min(nconv, (m-k)/2), m-1)
+		if last := svals[sidx[keep-1]]; imag(last) != 0 && svals[sidx[keep]] == complex(real(last), -imag(last)) {
+			if keep+1 < m {
+				keep++
+			} else {
+				keep--
+			}
+		}
+		sel := make(map[complex128]bool, keep)
+		for _, i := range sidx[:keep] {
+			sel[svals[i]] = true
+		}
+		kk, ok := schur.Reorder(func(l complex128) bool { return sel[l] })
+		if !ok || kk == 0 {
+			return false
+		}
+		schur.ZTo(&z)
+		schur.TTo(&t)
+
+		var vk Dense
+		vk.Mul(v.Slice(0, n, 0, m), z.Slice(0, m, 0, kk))
+		v.Slice(0, n, 0, kk).(*Dense).Copy(&vk)
+		v.ColView(kk).(*VecDense).CopyVec(v.ColView(m))
+		var b VecDense
+		b.MulVec(z.Slice(0, m, 0, kk).T(), h.RowView(m))
+		h.Zero()
+		h.Slice(0, kk, 0, kk).(*Dense).Copy(t.Slice(0, kk, 0, kk))
+		for p := 0; p < kk; p++ {
+			h.set(kk, p, b.AtVec(p))
+		}
+		start = kk
+	}
+	return false
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *EigenPartial) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Iterations returns the number of restarts performed during the
+// factorization. Iterations will panic if the receiver does not contain a
+// successful factorization.
+func (e *EigenPartial) Iterations() int {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	return e.iterations
+}
+
+// Values extracts the computed eigenvalues ordered from most to least wanted.
+// If dst is non-nil, the values are stored in-place into dst. In this case dst
+// must have length k, otherwise Values will panic. If dst is nil, then a new
+// slice will be allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the receiver does not contain a successful factorization.
+func (e *EigenPartial) Values(dst []complex128) []complex128 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the right eigenvectors of the decomposition into the
+// columns of dst, in the order of the values returned by Values. The
+// eigenvectors are normalized to have Euclidean norm equal to 1.
+//
+// If dst is empty, VectorsTo will resize dst to be n×k. When dst is
+// non-empty, VectorsTo will panic if dst is not n×k. VectorsTo will also
+// panic if the receiver does not contain a successful factorization.
+func (e *EigenPartial) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	r, c := e.vectors.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else {
+		r2, c2 := dst.Dims()
+		if r != r2 || c != c2 {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.vectors)
+}
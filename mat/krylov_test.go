@@ -0,0 +1,265 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestEigenSymPartial(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, k int
+		dim  int
+	}{
+		{n: 1, k: 1},
+		{n: 5, k: 5},
+		{n: 10, k: 3},
+		{n: 50, k: 1},
+		{n: 50, k: 4},
+		{n: 100, k: 6, dim: 15},
+	} {
+		n, k := test.n, test.k
+		a := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				a.SetSym(i, j, rnd.NormFloat64())
+			}
+		}
+		var eig EigenSym
+		if !eig.Factorize(a, false) {
+			t.Fatalf("unexpected EigenSym failure for n=%d", n)
+		}
+		all := eig.Values(nil)
+
+		for _, which := range []EigenWhich{EigenLargest, EigenSmallest, EigenLargestMagnitude} {
+			want := make([]float64, n)
+			copy(want, all)
+			switch which {
+			case EigenLargest:
+				sort.Sort(sort.Reverse(sort.Float64Slice(want)))
+			case EigenLargestMagnitude:
+				sort.Slice(want, func(i, j int) bool { return math.Abs(want[i]) > math.Abs(want[j]) })
+			}
+			want = want[:k]
+
+			var e EigenSymPartial
+			settings := &KrylovSettings{SubspaceDim: test.dim, Src: rand.NewSource(1)}
+			if !e.Factorize(MatrixOperator{a}, n, k, which, settings) {
+				t.Errorf("n=%d k=%d which=%d: unexpected failure", n, k, which)
+				continue
+			}
+			got := e.Values(nil)
+			for i := range got {
+				if math.Abs(got[i]-want[i]) > 1e-8 {
+					t.Errorf("n=%d k=%d which=%d: unexpected eigenvalue %d: got %v, want %v", n, k, which, i, got[i], want[i])
+				}
+			}
+
+			var vecs Dense
+			e.VectorsTo(&vecs)
+			var vtv Dense
+			vtv.Mul(vecs.T(), &vecs)
+			if !EqualApprox(&vtv, eye(k), 1e-10) {
+				t.Errorf("n=%d k=%d which=%d: eigenvectors not orthonormal", n, k, which)
+			}
+			var av, lv Dense
+			av.Mul(a, &vecs)
+			lv.Mul(&vecs, NewDiagDense(k, got))
+			if !EqualApprox(&av, &lv, 1e-7) {
+				t.Errorf("n=%d k=%d which=%d: A*V != V*D", n, k, which)
+			}
+		}
+	}
+}
+
+func TestEigenSymPartialSparse(t *testing.T) {
+	t.Parallel()
+	const m = 20
+	a := poisson2D(m)
+	n, _ := a.Dims()
+
+	// The eigenvalues of the 2D Poisson matrix are
+	//  4 - 2cos(iπ/(m+1)) - 2cos(jπ/(m+1)) for i, j in [1, m].
+	var want []float64
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= m; j++ {
+			want = append(want, 4-2*math.Cos(float64(i)*math.Pi/(m+1))-2*math.Cos(float64(j)*math.Pi/(m+1)))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(want)))
+
+	const k = 5
+	var e EigenSymPartial
+	if !e.Factorize(a, n, k, EigenLargest, &KrylovSettings{Src: rand.NewSource(1)}) {
+		t.Fatal("unexpected failure")
+	}
+	got := e.Values(nil)
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-8 {
+			t.Errorf("unexpected eigenvalue %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if e.Iterations() < 1 {
+		t.Errorf("unexpected number of iterations: %d", e.Iterations())
+	}
+
+	if p, _ := panics(func() { e.Factorize(a, n, 0, EigenLargest, nil) }); !p {
+		t.Error("expected panic for zero k")
+	}
+	if p, _ := panics(func() { e.Values(nil) }); !p {
+		t.Error("expected panic after invalid factorization")
+	}
+}
+
+func TestEigenPartial(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, k int
+		dim  int
+	}{
+		{n: 1, k: 1},
+		{n: 6, k: 6},
+		{n: 10, k: 3},
+		{n: 60, k: 1},
+		{n: 60, k: 4},
+		{n: 100, k: 6, dim: 20},
+	} {
+		n, k := test.n, test.k
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+			// Separate a few eigenvalues from the bulk of the
+			// spectrum to make the test problems well posed.
+			if i < 5 {
+				a.Set(i, i, a.At(i, i)+float64(3*(i+3)))
+			}
+		}
+		var eig Eigen
+		if !eig.Factorize(a, EigenNone) {
+			t.Fatalf("unexpected Eigen failure for n=%d", n)
+		}
+		all := eig.Values(nil)
+
+		for _, which := range []EigenWhich{EigenLargest, EigenSmallest, EigenLargestMagnitude} {
+			var e EigenPartial
+			settings := &KrylovSettings{SubspaceDim: test.dim, Src: rand.NewSource(1)}
+			if !e.Factorize(MatrixOperator{a}, n, k, which, settings) {
+				t.Errorf("n=%d k=%d which=%d: unexpected failure", n, k, which)
+				continue
+			}
+			got := e.Values(nil)
+
+			// Check the ordering key of each value against the
+			// full spectrum, since the order of ties is arbitrary.
+			key := func(l complex128) float64 {
+				switch which {
+				case EigenLargest:
+					return real(l)
+				case EigenSmallest:
+					return -real(l)
+				default:
+					return math.Hypot(real(l), imag(l))
+				}
+			}
+			want := make([]float64, n)
+			for i, l := range all {
+				want[i] = key(l)
+			}
+			sort.Sort(sort.Reverse(sort.Float64Slice(want)))
+			for i, l := range got {
+				if math.Abs(key(l)-want[i]) > 1e-8 {
+					t.Errorf("n=%d k=%d which=%d: unexpected eigenvalue %d: got %v", n, k, which, i, l)
+				}
+			}
+
+			var vecs CDense
+			e.VectorsTo(&vecs)
+			for p, l := range got {
+				var resid float64
+				for i := 0; i < n; i++ {
+					var ax complex128
+					for j := 0; j < n; j++ {
+						ax += complex(a.At(i, j), 0) * vecs.At(j, p)
+					}
+					d := ax - l*vecs.At(i, p)
+					resid += real(d)*real(d) + imag(d)*imag(d)
+				}
+				if math.Sqrt(resid) > 1e-7 {
+					t.Errorf("n=%d k=%d which=%d: unexpected residual for eigenpair %d: %v", n, k, which, p, math.Sqrt(resid))
+				}
+			}
+		}
+	}
+}
+
+func TestEigenPartialPageRank(t *testing.T) {
+	t.Parallel()
+	const (
+		n       = 200
+		damping = 0.85
+	)
+	rnd := rand.New(rand.NewSource(1))
+
+	// Construct a column-stochastic Google matrix for a random graph.
+	links := NewDense(n, n, nil)
+	for j := 0; j < n; j++ {
+		var out float64
+		for i := 0; i < n; i++ {
+			if i != j && rnd.Float64() < 0.05 {
+				links.Set(i, j, 1)
+				out++
+			}
+		}
+		for i := 0; i < n; i++ {
+			if out == 0 {
+				links.Set(i, j, 1/float64(n))
+			} else {
+				links.Set(i, j, links.At(i, j)/out)
+			}
+		}
+	}
+	g := NewDense(n, n, nil)
+	g.Apply(func(i, j int, v float64) float64 {
+		return damping*links.At(i, j) + (1-damping)/n
+	}, g)
+
+	var e EigenPartial
+	if !e.Factorize(MatrixOperator{g}, n, 1, EigenLargestMagnitude, &KrylovSettings{Src: rand.NewSource(1)}) {
+		t.Fatal("unexpected failure")
+	}
+	l := e.Values(nil)[0]
+	if math.Abs(real(l)-1) > 1e-10 || math.Abs(imag(l)) > 1e-10 {
+		t.Errorf("unexpected dominant eigenvalue: got %v, want 1", l)
+	}
+
+	var vecs CDense
+	e.VectorsTo(&vecs)
+	rank := NewVecDense(n, nil)
+	var sum float64
+	for i := 0; i < n; i++ {
+		rank.SetVec(i, real(vecs.At(i, 0)))
+		sum += rank.AtVec(i)
+	}
+	rank.ScaleVec(1/sum, rank)
+	var power VecDense
+	power.MulVec(g, rank)
+	if !EqualApprox(&power, rank, 1e-10) {
+		t.Error("PageRank vector is not stationary")
+	}
+	for i := 0; i < n; i++ {
+		if rank.AtVec(i) < 0 {
+			t.Errorf("negative PageRank for node %d: %v", i, rank.AtVec(i))
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"os"
+
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+var (
+	_ Matrix      = (*MappedDense)(nil)
+	_ Mutable     = (*MappedDense)(nil)
+	_ RawMatrixer = (*MappedDense)(nil)
+)
+
+var (
+	errMapUnsupported = errors.New("mat: memory mapping not supported on this platform")
+	errMapShort       = errors.New("mat: file too short for mapped matrix")
+	errMapClosed      = errors.New("mat: mapped matrix already closed")
+)
+
+const (
+	badMapReadOnly = "mat: write to read-only mapped matrix"
+	badMapClosed   = "mat: use of closed mapped matrix"
+)
+
+// MappedDense is a dense matrix whose elements are held in a memory-mapped
+// file, allowing matrices that are larger than the available memory to be
+// worked on. The elements are stored in row-major order as float64 values in
+// the native byte order of the machine, starting at the beginning of the file.
+//
+// Slices of a MappedDense are *Dense values sharing the mapped storage, so
+// blocks of a mapped matrix can be used with the Dense arithmetic methods.
+// The methods of a MappedDense panic after it has been closed, and the
+// mapped storage obtained from Slice or RawMatrix must not be used after
+// the MappedDense has been closed.
+type MappedDense struct {
+	mat      *Dense
+	data     []byte
+	file     *os.File
+	writable bool
+}
+
+// MapDense maps the r×c matrix held at the start of f into memory. If writable
+// is true, the mapping is shared with the file so that modifications of the
+// matrix are written to f, which must have been opened for reading and
+// writing. Otherwise the matrix is read-only and f need only be open for
+// reading. Set panics for a read-only matrix, and modifications made through
+// Slice or RawMatrix are private to the process and are not written to f.
+// The file may be closed after MapDense returns.
+//
+// MapDense will panic if r or c is not positive.
+func MapDense(f *os.File, r, c int, writable bool) (*MappedDense, error) {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	size := int64(r) * int64(c) * 8
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < size {
+		return nil, errMapShort
+	}
+	data, err := mmap(f, int(size), writable)
+	if err != nil {
+		return nil, err
+	}
+	return &MappedDense{
+		mat:      NewDense(r, c, float64s(data)),
+		data:     data,
+		writable: writable,
+	}, nil
+}
+
+// CreateMappedDense creates or truncates the named file to hold an r×c zero
+// matrix and maps it into memory for reading and writing. The file is closed
+// when the returned matrix is closed.
+//
+// CreateMappedDense will panic if r or c is not positive.
+func CreateMappedDense(name string, r, c int) (*MappedDense, error) {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	err = f.Truncate(int64(r) * int64(c) * 8)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	m, err := MapDense(f, r, c, true)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	m.file = f
+	return m, nil
+}
+
+// Close unmaps the matrix. Modifications of a writable matrix are visible
+// to readers of the file once they have been made, but are only guaranteed
+// to have reached the storage device after the operating system has written
+// them back.
+func (m *MappedDense) Close() error {
+	if m.data == nil {
+		return errMapClosed
+	}
+	err := munmap(m.data)
+	m.data = nil
+	m.mat = nil
+	if m.file != nil {
+		cerr := m.file.Close()
+		if err == nil {
+			err = cerr
+		}
+		m.file = nil
+	}
+	return err
+}
+
+// Writable returns whether modifications of the matrix are written to
+// the mapped file.
+func (m *MappedDense) Writable() bool {
+	return m.writable
+}
+
+// checkOpen panics if the matrix has been closed.
+func (m *MappedDense) checkOpen() {
+	if m.mat == nil {
+		panic(badMapClosed)
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *MappedDense) Dims() (r, c int) {
+	m.checkOpen()
+	return m.mat.Dims()
+}
+
+// At returns the element at row i, column j.
+func (m *MappedDense) At(i, j int) float64 {
+	m.checkOpen()
+	return m.mat.At(i, j)
+}
+
+// Set sets the element at row i, column j to the value v. Set will panic
+// if the matrix is read-only.
+func (m *MappedDense) Set(i, j int, v float64) {
+	m.checkOpen()
+	if !m.writable {
+		panic(badMapReadOnly)
+	}
+	m.mat.Set(i, j, v)
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (m *MappedDense) T() Matrix {
+	return Transpose{m}
+}
+
+// RawMatrix returns the underlying blas64.General used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in returned blas64.General. Modifications of the data of a read-only
+// matrix are not written to the mapped file.
+func (m *MappedDense) RawMatrix() blas64.General {
+	m.checkOpen()
+	return m.mat.RawMatrix()
+}
+
+// Slice returns a new *Dense that shares backing data with the receiver.
+// The returned matrix starts at {i,j} of the receiver and extends k-i rows
+// and l-j columns. The final row in the resulting matrix is k-1 and the
+// final column is l-1. Slice panics with ErrIndexOutOfRange if the slice
+// is outside the capacity of the receiver. Modifications of the returned
+// matrix are not written to the mapped file if the receiver is read-only.
+func (m *MappedDense) Slice(i, k, j, l int) Matrix {
+	m.checkOpen()
+	return m.mat.slice(i, k, j, l)
+}
@@ -0,0 +1,25 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris) || safe
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris safe
+
+package mat
+
+import "os"
+
+// mmap returns an error since memory mapping is not available.
+func mmap(f *os.File, size int, writable bool) ([]byte, error) {
+	return nil, errMapUnsupported
+}
+
+// munmap returns an error since memory mapping is not available.
+func munmap(b []byte) error {
+	return errMapUnsupported
+}
+
+// float64s is not called since memory mapping is not available.
+func float64s(b []byte) []float64 {
+	panic("mat: memory mapping not supported")
+}
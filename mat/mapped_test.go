@@ -0,0 +1,126 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestMappedDense(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const r, c = 37, 53
+	name := filepath.Join(t.TempDir(), "matrix")
+
+	m, err := CreateMappedDense(name, r, c)
+	if err == errMapUnsupported {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error creating mapped matrix: %v", err)
+	}
+	if !m.Writable() {
+		t.Error("created matrix is not writable")
+	}
+	if !Equal(m, NewDense(r, c, nil)) {
+		t.Error("created matrix is not zero")
+	}
+	want := randomDense(r, c, rnd)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, want.At(i, j))
+		}
+	}
+
+	// Multiply blockwise using views of the mapping.
+	b := randomDense(c, 5, rnd)
+	var got, block Dense
+	got.ReuseAs(r, 5)
+	for i := 0; i < r; i += 10 {
+		k := min(i+10, r)
+		block.Reset()
+		block.Mul(m.Slice(i, k, 0, c), b)
+		got.Slice(i, k, 0, 5).(*Dense).Copy(&block)
+	}
+	var wantMul Dense
+	wantMul.Mul(want, b)
+	if !EqualApprox(&got, &wantMul, 1e-12) {
+		t.Error("unexpected blockwise product of mapped matrix")
+	}
+
+	// Write through a view.
+	m.Slice(1, 2, 2, 3).(*Dense).Set(0, 0, 42)
+	want.Set(1, 2, 42)
+	if err := m.Close(); err != nil {
+		t.Fatalf("unexpected error closing mapped matrix: %v", err)
+	}
+	if err := m.Close(); err != errMapClosed {
+		t.Errorf("unexpected error closing closed matrix: got %v, want %v", err, errMapClosed)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("unexpected error opening file: %v", err)
+	}
+	defer f.Close()
+	ro, err := MapDense(f, r, c, false)
+	if err != nil {
+		t.Fatalf("unexpected error mapping file: %v", err)
+	}
+	defer ro.Close()
+	if ro.Writable() {
+		t.Error("read-only matrix is writable")
+	}
+	if !Equal(ro, want) {
+		t.Error("unexpected mapped matrix after reopening")
+	}
+	if !Equal(ro.T(), want.T()) {
+		t.Error("unexpected transpose of mapped matrix")
+	}
+	if p, _ := panics(func() { ro.Set(0, 0, 1) }); !p {
+		t.Error("expected panic writing to read-only matrix")
+	}
+
+	// Writes through a view of a read-only matrix are
+	// private to the mapping and do not reach the file.
+	ro.Slice(0, 1, 0, 1).(*Dense).Set(0, 0, 7)
+	ro.RawMatrix().Data[1] = 8
+	if ro.At(0, 0) != 7 || ro.At(0, 1) != 8 {
+		t.Error("write through view of read-only matrix not visible")
+	}
+	again, err := MapDense(f, r, c, false)
+	if err != nil {
+		t.Fatalf("unexpected error mapping file: %v", err)
+	}
+	if !Equal(again, want) {
+		t.Error("write through view of read-only matrix reached the file")
+	}
+	if err := again.Close(); err != nil {
+		t.Fatalf("unexpected error closing mapped matrix: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "Dims", fn: func() { again.Dims() }},
+		{name: "At", fn: func() { again.At(0, 0) }},
+		{name: "Set", fn: func() { again.Set(0, 0, 1) }},
+		{name: "RawMatrix", fn: func() { again.RawMatrix() }},
+		{name: "Slice", fn: func() { again.Slice(0, 1, 0, 1) }},
+	} {
+		panicked, msg := panics(test.fn)
+		if !panicked || msg != badMapClosed {
+			t.Errorf("unexpected panic for %s after Close: got %q, want %q", test.name, msg, badMapClosed)
+		}
+	}
+
+	if _, err := MapDense(f, r+1, c, false); err != errMapShort {
+		t.Errorf("unexpected error mapping short file: got %v, want %v", err, errMapShort)
+	}
+}
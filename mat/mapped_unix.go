@@ -0,0 +1,35 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris) && !safe
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+// +build !safe
+
+package mat
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mmap maps the first size bytes of f into memory. If writable is false the
+// mapping is private, so that modifications are not written to f.
+func mmap(f *os.File, size int, writable bool) ([]byte, error) {
+	flags := syscall.MAP_PRIVATE
+	if writable {
+		flags = syscall.MAP_SHARED
+	}
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, flags)
+}
+
+// munmap unmaps memory mapped by mmap.
+func munmap(b []byte) error {
+	return syscall.Munmap(b)
+}
+
+// float64s returns the page-aligned mapped memory b as a slice of float64.
+func float64s(b []byte) []float64 {
+	return unsafe.Slice((*float64)(unsafe.Pointer(&b[0])), len(b)/8)
+}
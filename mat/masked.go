@@ -0,0 +1,305 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+var (
+	_ Matrix  = (*MaskedDense)(nil)
+	_ Mutable = (*MaskedDense)(nil)
+	_ Normer  = (*MaskedDense)(nil)
+)
+
+// MaskedDense is a dense matrix in which elements may be masked to mark them
+// as missing or invalid. The masked elements are excluded from the summary
+// statistics and norms computed by the methods of MaskedDense, and read as
+// NaN through the At method, so that functions operating on a general Matrix
+// propagate their absence.
+type MaskedDense struct {
+	mat  *Dense
+	mask []bool
+}
+
+// NewMaskedDense returns a MaskedDense holding the elements of m, with the
+// element at row i, column j masked if mask[i*c+j] is true, where c is the
+// number of columns of m. The returned matrix shares the backing data of m.
+// If mask is nil, no element is masked. The mask is copied.
+//
+// NewMaskedDense will panic if mask is not nil and its length is not the
+// number of elements of m.
+func NewMaskedDense(m *Dense, mask []bool) *MaskedDense {
+	r, c := m.Dims()
+	md := &MaskedDense{mat: m, mask: make([]bool, r*c)}
+	if mask != nil {
+		if len(mask) != r*c {
+			panic(ErrShape)
+		}
+		copy(md.mask, mask)
+	}
+	return md
+}
+
+// MaskNaN returns a MaskedDense holding the elements of m with its NaN
+// elements masked. The returned matrix shares the backing data of m.
+func MaskNaN(m *Dense) *MaskedDense {
+	r, c := m.Dims()
+	md := &MaskedDense{mat: m, mask: make([]bool, r*c)}
+	for i := 0; i < r; i++ {
+		for j, v := range m.RawRowView(i) {
+			md.mask[i*c+j] = math.IsNaN(v)
+		}
+	}
+	return md
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *MaskedDense) Dims() (r, c int) {
+	return m.mat.Dims()
+}
+
+// At returns the element at row i, column j, or NaN if the element is
+// masked.
+func (m *MaskedDense) At(i, j int) float64 {
+	v := m.mat.At(i, j)
+	if m.mask[m.index(i, j)] {
+		return math.NaN()
+	}
+	return v
+}
+
+// Set sets the element at row i, column j to the value v and unmasks it.
+func (m *MaskedDense) Set(i, j int, v float64) {
+	m.mat.Set(i, j, v)
+	m.mask[m.index(i, j)] = false
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (m *MaskedDense) T() Matrix {
+	return Transpose{m}
+}
+
+// index returns the index into the mask of the element at row i, column j.
+func (m *MaskedDense) index(i, j int) int {
+	_, c := m.mat.Dims()
+	return i*c + j
+}
+
+// Dense returns the underlying matrix, including the stored values of masked
+// elements.
+func (m *MaskedDense) Dense() *Dense {
+	return m.mat
+}
+
+// IsMasked returns whether the element at row i, column j is masked.
+func (m *MaskedDense) IsMasked(i, j int) bool {
+	r, c := m.mat.Dims()
+	if uint(i) >= uint(r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(c) {
+		panic(ErrColAccess)
+	}
+	return m.mask[i*c+j]
+}
+
+// SetMask sets whether the element at row i, column j is masked.
+func (m *MaskedDense) SetMask(i, j int, masked bool) {
+	r, c := m.mat.Dims()
+	if uint(i) >= uint(r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(c) {
+		panic(ErrColAccess)
+	}
+	m.mask[i*c+j] = masked
+}
+
+// Count returns the number of unmasked elements.
+func (m *MaskedDense) Count() int {
+	var n int
+	for _, masked := range m.mask {
+		if !masked {
+			n++
+		}
+	}
+	return n
+}
+
+// do calls fn for each unmasked element of the matrix.
+func (m *MaskedDense) do(fn func(i, j int, v float64)) {
+	r, c := m.mat.Dims()
+	for i := 0; i < r; i++ {
+		for j, v := range m.mat.RawRowView(i) {
+			if !m.mask[i*c+j] {
+				fn(i, j, v)
+			}
+		}
+	}
+}
+
+// Sum returns the sum of the unmasked elements.
+func (m *MaskedDense) Sum() float64 {
+	var sum float64
+	m.do(func(_, _ int, v float64) { sum += v })
+	return sum
+}
+
+// Mean returns the mean of the unmasked elements. Mean returns NaN if all
+// the elements are masked.
+func (m *MaskedDense) Mean() float64 {
+	n := m.Count()
+	if n == 0 {
+		return math.NaN()
+	}
+	return m.Sum() / float64(n)
+}
+
+// RowSums returns the sums of the unmasked elements of each row of the matrix.
+// If dst is not nil, the sums are stored in-place into dst. In this case dst
+// must have length r, otherwise RowSums will panic. If dst is nil, then a new
+// slice will be allocated.
+func (m *MaskedDense) RowSums(dst []float64) []float64 {
+	r, _ := m.mat.Dims()
+	dst = zeroedSlice(dst, r)
+	m.do(func(i, _ int, v float64) { dst[i] += v })
+	return dst
+}
+
+// ColSums returns the sums of the unmasked elements of each column of the
+// matrix. If dst is not nil, the sums are stored in-place into dst. In this
+// case dst must have length c, otherwise ColSums will panic. If dst is nil,
+// then a new slice will be allocated.
+func (m *MaskedDense) ColSums(dst []float64) []float64 {
+	_, c := m.mat.Dims()
+	dst = zeroedSlice(dst, c)
+	m.do(func(_, j int, v float64) { dst[j] += v })
+	return dst
+}
+
+// RowMeans returns the means of the unmasked elements of each row of the
+// matrix, with NaN for rows in which all elements are masked. The dst
+// parameter is treated as for RowSums.
+func (m *MaskedDense) RowMeans(dst []float64) []float64 {
+	r, _ := m.mat.Dims()
+	dst = m.RowSums(dst)
+	n := make([]int, r)
+	m.do(func(i, _ int, _ float64) { n[i]++ })
+	for i := range dst {
+		dst[i] /= float64(n[i])
+	}
+	return dst
+}
+
+// ColMeans returns the means of the unmasked elements of each column of the
+// matrix, with NaN for columns in which all elements are masked. The dst
+// parameter is treated as for ColSums.
+func (m *MaskedDense) ColMeans(dst []float64) []float64 {
+	_, c := m.mat.Dims()
+	dst = m.ColSums(dst)
+	n := make([]int, c)
+	m.do(func(_, j int, _ float64) { n[j]++ })
+	for j := range dst {
+		dst[j] /= float64(n[j])
+	}
+	return dst
+}
+
+// Norm returns the specified norm of the matrix with masked elements treated
+// as zero. Valid norms are:
+//  1 - The maximum absolute column sum
+//  2 - The Frobenius norm, the square root of the sum of the squares of the elements
+//  Inf - The maximum absolute row sum
+// Norm will panic with ErrNormOrder if an illegal norm is specified.
+func (m *MaskedDense) Norm(norm float64) float64 {
+	r, c := m.mat.Dims()
+	switch norm {
+	default:
+		panic(ErrNormOrder)
+	case 1:
+		sums := make([]float64, c)
+		m.do(func(_, j int, v float64) { sums[j] += math.Abs(v) })
+		return maxOf(sums)
+	case 2:
+		var scale, ssq float64 = 0, 1
+		m.do(func(_, _ int, v float64) {
+			if v == 0 {
+				return
+			}
+			absv := math.Abs(v)
+			if scale < absv {
+				ssq = 1 + ssq*(scale/absv)*(scale/absv)
+				scale = absv
+			} else {
+				ssq += (absv / scale) * (absv / scale)
+			}
+		})
+		return scale * math.Sqrt(ssq)
+	case math.Inf(1):
+		sums := make([]float64, r)
+		m.do(func(i, _ int, v float64) { sums[i] += math.Abs(v) })
+		return maxOf(sums)
+	}
+}
+
+// zeroedSlice returns dst with its elements set to zero, or a new slice of
+// length n if dst is nil. zeroedSlice will panic if dst is not nil and does
+// not have length n.
+func zeroedSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	return dst
+}
+
+// maxOf returns the largest element of s, which must not be empty.
+func maxOf(s []float64) float64 {
+	max := s[0]
+	for _, v := range s[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// FillMasked sets the value of each masked element to v. The elements remain
+// masked.
+func (m *MaskedDense) FillMasked(v float64) {
+	r, c := m.mat.Dims()
+	for i := 0; i < r; i++ {
+		row := m.mat.RawRowView(i)
+		for j := range row {
+			if m.mask[i*c+j] {
+				row[j] = v
+			}
+		}
+	}
+}
+
+// FillMaskedFrom sets the value of each masked element to the corresponding
+// element of a, for example to impute missing values from a model. The
+// elements remain masked. FillMaskedFrom will panic if a does not have the
+// same dimensions as the receiver.
+func (m *MaskedDense) FillMaskedFrom(a Matrix) {
+	r, c := m.mat.Dims()
+	if ar, ac := a.Dims(); ar != r || ac != c {
+		panic(ErrShape)
+	}
+	for i := 0; i < r; i++ {
+		row := m.mat.RawRowView(i)
+		for j := range row {
+			if m.mask[i*c+j] {
+				row[j] = a.At(i, j)
+			}
+		}
+	}
+}
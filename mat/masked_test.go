@@ -0,0 +1,132 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestMaskedDense(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	d := NewDense(3, 4, []float64{
+		1, nan, 3, -4,
+		nan, nan, nan, nan,
+		-2, 6, nan, 8,
+	})
+	m := MaskNaN(d)
+
+	if got, want := m.Count(), 6; got != want {
+		t.Errorf("unexpected count: got %d, want %d", got, want)
+	}
+	if got, want := m.Sum(), 12.0; got != want {
+		t.Errorf("unexpected sum: got %v, want %v", got, want)
+	}
+	if got, want := m.Mean(), 2.0; got != want {
+		t.Errorf("unexpected mean: got %v, want %v", got, want)
+	}
+	if got, want := m.RowSums(nil), []float64{0, 0, 12}; !sameFloats(got, want) {
+		t.Errorf("unexpected row sums: got %v, want %v", got, want)
+	}
+	if got, want := m.ColSums(make([]float64, 4)), []float64{-1, 6, 3, 4}; !sameFloats(got, want) {
+		t.Errorf("unexpected column sums: got %v, want %v", got, want)
+	}
+	if got, want := m.RowMeans(nil), []float64{0, nan, 4}; !sameFloats(got, want) {
+		t.Errorf("unexpected row means: got %v, want %v", got, want)
+	}
+	if got, want := m.ColMeans(nil), []float64{-0.5, 6, 3, 2}; !sameFloats(got, want) {
+		t.Errorf("unexpected column means: got %v, want %v", got, want)
+	}
+
+	// Norms treat masked elements as zero.
+	zeroed := NewDense(3, 4, []float64{
+		1, 0, 3, -4,
+		0, 0, 0, 0,
+		-2, 6, 0, 8,
+	})
+	for _, norm := range []float64{1, 2, math.Inf(1)} {
+		if got, want := m.Norm(norm), Norm(zeroed, norm); math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected %v-norm: got %v, want %v", norm, got, want)
+		}
+		if got, want := Norm(m.T(), norm), Norm(zeroed.T(), norm); math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected %v-norm of transpose: got %v, want %v", norm, got, want)
+		}
+	}
+
+	if !m.IsMasked(1, 2) || m.IsMasked(0, 0) {
+		t.Error("unexpected mask")
+	}
+	if !math.IsNaN(m.At(0, 1)) || m.At(0, 0) != 1 {
+		t.Error("unexpected element values")
+	}
+	m.Set(1, 1, 5)
+	if m.IsMasked(1, 1) || m.At(1, 1) != 5 {
+		t.Error("Set did not unmask element")
+	}
+	m.SetMask(0, 0, true)
+	if got, want := m.Sum(), 16.0; got != want {
+		t.Errorf("unexpected sum after masking: got %v, want %v", got, want)
+	}
+
+	m.FillMasked(-1)
+	if m.Dense().At(0, 0) != -1 || m.Dense().At(2, 2) != -1 || !m.IsMasked(2, 2) {
+		t.Error("unexpected result of FillMasked")
+	}
+	m.FillMaskedFrom(zeroed)
+	if m.Dense().At(0, 0) != 1 || m.Dense().At(2, 2) != 0 || m.Dense().At(1, 1) != 5 {
+		t.Error("unexpected result of FillMaskedFrom")
+	}
+
+	if p, _ := panics(func() { NewMaskedDense(d, make([]bool, 3)) }); !p {
+		t.Error("expected panic for mask length mismatch")
+	}
+	if p, _ := panics(func() { m.RowSums(make([]float64, 2)) }); !p {
+		t.Error("expected panic for destination length mismatch")
+	}
+	if p, _ := panics(func() { m.IsMasked(3, 0) }); !p {
+		t.Error("expected panic for out of range row")
+	}
+}
+
+func TestMaskedDenseAllMasked(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	d := randomDense(4, 5, rnd)
+	mask := make([]bool, 20)
+	for i := range mask {
+		mask[i] = true
+	}
+	m := NewMaskedDense(d, mask)
+	if m.Count() != 0 || m.Sum() != 0 || !math.IsNaN(m.Mean()) {
+		t.Errorf("unexpected statistics for fully masked matrix: count=%d sum=%v mean=%v", m.Count(), m.Sum(), m.Mean())
+	}
+	if m.Norm(2) != 0 {
+		t.Errorf("unexpected norm for fully masked matrix: %v", m.Norm(2))
+	}
+
+	m = NewMaskedDense(d, nil)
+	if !Equal(m, d) {
+		t.Error("unexpected elements of unmasked matrix")
+	}
+	if got, want := m.Sum(), Sum(d); math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected sum of unmasked matrix: got %v, want %v", got, want)
+	}
+}
+
+// sameFloats returns whether a and b are equal, treating NaN values as equal.
+func sameFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] && !(math.IsNaN(v) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
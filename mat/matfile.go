@@ -0,0 +1,358 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+)
+
+var (
+	errMATHeader  = errors.New("mat: invalid MAT-file header")
+	errMATElement = errors.New("mat: invalid MAT-file data element")
+	errMATName    = errors.New("mat: invalid MAT-file variable name")
+)
+
+// MAT-file data element types.
+const (
+	miINT8       = 1
+	miUINT8      = 2
+	miINT16      = 3
+	miUINT16     = 4
+	miINT32      = 5
+	miUINT32     = 6
+	miSINGLE     = 7
+	miDOUBLE     = 9
+	miINT64      = 12
+	miUINT64     = 13
+	miMATRIX     = 14
+	miCOMPRESSED = 15
+)
+
+// MAT-file array classes and flags.
+const (
+	mxDoubleClass = 6
+	mxUint64Class = 15
+
+	mxComplexFlag = 0x0800
+)
+
+const (
+	matHeaderLen  = 128
+	matHeaderText = 116
+)
+
+// ReadMAT reads the variables of a MATLAB Level 5 MAT-file from r. Real
+// numeric two-dimensional arrays of any numeric class are returned as
+// Dense matrices keyed by their variable name. Compressed variables are
+// supported. Variables of other types, such as character, cell, structure,
+// sparse and complex arrays, arrays with more than two dimensions and empty
+// arrays are skipped.
+func ReadMAT(r io.Reader) (map[string]*Dense, error) {
+	var header [matHeaderLen]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return nil, errMATHeader
+	}
+	var order binary.ByteOrder
+	switch string(header[126:128]) {
+	case "IM":
+		order = binary.LittleEndian
+	case "MI":
+		order = binary.BigEndian
+	default:
+		return nil, errMATHeader
+	}
+	if order.Uint16(header[124:126]) != 0x0100 {
+		return nil, errMATHeader
+	}
+
+	vars := make(map[string]*Dense)
+	for {
+		typ, data, err := readMATElement(r, order)
+		if err == io.EOF {
+			return vars, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if typ == miCOMPRESSED {
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			typ, data, err = readMATElement(zr, order)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if typ != miMATRIX {
+			continue
+		}
+		name, m, err := readMATMatrix(data, order)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			vars[name] = m
+		}
+	}
+}
+
+// readMATElement reads a data element from r, returning its type and data.
+// It returns io.EOF if r is at the end of its data.
+func readMATElement(r io.Reader, order binary.ByteOrder) (typ uint32, data []byte, err error) {
+	var tag [8]byte
+	_, err = io.ReadFull(r, tag[:])
+	if err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errMATElement
+		}
+		return 0, nil, err
+	}
+	typ = order.Uint32(tag[:4])
+	if typ>>16 != 0 {
+		// Small data element format.
+		n := typ >> 16
+		if n > 4 {
+			return 0, nil, errMATElement
+		}
+		return typ & 0xffff, tag[4 : 4+n], nil
+	}
+	n := order.Uint32(tag[4:])
+	// The data are read through a limited reader rather than into
+	// a buffer of length n so that a corrupt tag cannot force a
+	// large allocation.
+	data, err = ioutil.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return 0, nil, err
+	}
+	if uint32(len(data)) != n {
+		return 0, nil, errMATElement
+	}
+	if typ == miCOMPRESSED {
+		// Compressed elements are not padded.
+		return typ, data, nil
+	}
+	if pad := (8 - n%8) % 8; pad != 0 {
+		// The padding of the final element of a compressed
+		// stream may be omitted.
+		var buf [8]byte
+		_, err = io.ReadFull(r, buf[:pad])
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, nil, err
+		}
+	}
+	return typ, data, nil
+}
+
+// readMATMatrix decodes the contents of a miMATRIX data element, returning
+// the variable name and its value. The returned matrix is nil if the
+// variable is not a real numeric two-dimensional non-empty array.
+func readMATMatrix(data []byte, order binary.ByteOrder) (name string, m *Dense, err error) {
+	r := bytes.NewReader(data)
+	next := func() (uint32, []byte, error) {
+		typ, data, err := readMATElement(r, order)
+		if err == io.EOF {
+			err = errMATElement
+		}
+		return typ, data, err
+	}
+
+	typ, flags, err := next()
+	if err != nil || typ != miUINT32 || len(flags) < 4 {
+		return "", nil, errMATElement
+	}
+	f := order.Uint32(flags)
+	class := f & 0xff
+	if class < mxDoubleClass || mxUint64Class < class {
+		// Skip non-numeric classes.
+		return "", nil, nil
+	}
+
+	typ, dimData, err := next()
+	if err != nil || typ != miINT32 || len(dimData)%4 != 0 {
+		return "", nil, errMATElement
+	}
+	dims := make([]int, len(dimData)/4)
+	for i := range dims {
+		dims[i] = int(int32(order.Uint32(dimData[4*i:])))
+	}
+
+	typ, nameData, err := next()
+	if err != nil || typ != miINT8 {
+		return "", nil, errMATElement
+	}
+	name = string(nameData)
+
+	if f&mxComplexFlag != 0 || len(dims) != 2 || dims[0] <= 0 || dims[1] <= 0 {
+		return name, nil, nil
+	}
+	rows, cols := dims[0], dims[1]
+
+	typ, realData, err := next()
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := decodeMATNumeric(typ, realData, order)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(values) != rows*cols {
+		return "", nil, errMATElement
+	}
+	m = NewDense(rows, cols, nil)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			m.set(i, j, values[j*rows+i])
+		}
+	}
+	return name, m, nil
+}
+
+// decodeMATNumeric decodes the numeric data of type typ to float64.
+func decodeMATNumeric(typ uint32, data []byte, order binary.ByteOrder) ([]float64, error) {
+	var size int
+	switch typ {
+	case miINT8, miUINT8:
+		size = 1
+	case miINT16, miUINT16:
+		size = 2
+	case miINT32, miUINT32, miSINGLE:
+		size = 4
+	case miDOUBLE, miINT64, miUINT64:
+		size = 8
+	default:
+		return nil, fmt.Errorf("mat: unsupported MAT-file data type %d", typ)
+	}
+	if len(data)%size != 0 {
+		return nil, errMATElement
+	}
+	v := make([]float64, len(data)/size)
+	for i := range v {
+		b := data[i*size:]
+		switch typ {
+		case miINT8:
+			v[i] = float64(int8(b[0]))
+		case miUINT8:
+			v[i] = float64(b[0])
+		case miINT16:
+			v[i] = float64(int16(order.Uint16(b)))
+		case miUINT16:
+			v[i] = float64(order.Uint16(b))
+		case miINT32:
+			v[i] = float64(int32(order.Uint32(b)))
+		case miUINT32:
+			v[i] = float64(order.Uint32(b))
+		case miSINGLE:
+			v[i] = float64(math.Float32frombits(order.Uint32(b)))
+		case miDOUBLE:
+			v[i] = math.Float64frombits(order.Uint64(b))
+		case miINT64:
+			v[i] = float64(int64(order.Uint64(b)))
+		case miUINT64:
+			v[i] = float64(order.Uint64(b))
+		}
+	}
+	return v, nil
+}
+
+// WriteMAT writes the matrices in vars to w as a MATLAB Level 5 MAT-file,
+// storing each as an uncompressed double precision array named by its key.
+// The variables are written in lexical order of their names. Variable names
+// must start with a letter followed by at most 62 letters, digits or
+// underscores.
+func WriteMAT(w io.Writer, vars map[string]Matrix) error {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if !validMATName(name) {
+			return errMATName
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := binary.LittleEndian
+	var header [matHeaderLen]byte
+	text := "MATLAB 5.0 MAT-file, Created by: gonum.org/v1/gonum/mat"
+	copy(header[:], text)
+	for i := len(text); i < matHeaderText; i++ {
+		header[i] = ' '
+	}
+	order.PutUint16(header[124:], 0x0100)
+	copy(header[126:], "IM")
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		m := vars[name]
+		r, c := m.Dims()
+
+		buf.Reset()
+		var flags [8]byte
+		order.PutUint32(flags[:], mxDoubleClass)
+		writeMATElement(&buf, order, miUINT32, flags[:])
+		var dims [8]byte
+		order.PutUint32(dims[:], uint32(r))
+		order.PutUint32(dims[4:], uint32(c))
+		writeMATElement(&buf, order, miINT32, dims[:])
+		writeMATElement(&buf, order, miINT8, []byte(name))
+		data := make([]byte, 8*r*c)
+		for j := 0; j < c; j++ {
+			for i := 0; i < r; i++ {
+				order.PutUint64(data[8*(j*r+i):], math.Float64bits(m.At(i, j)))
+			}
+		}
+		writeMATElement(&buf, order, miDOUBLE, data)
+
+		var tag [8]byte
+		order.PutUint32(tag[:], miMATRIX)
+		order.PutUint32(tag[4:], uint32(buf.Len()))
+		if _, err := w.Write(tag[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMATElement writes a data element of type typ holding data to buf,
+// padded to a multiple of 8 bytes.
+func writeMATElement(buf *bytes.Buffer, order binary.ByteOrder, typ uint32, data []byte) {
+	var tag [8]byte
+	order.PutUint32(tag[:], typ)
+	order.PutUint32(tag[4:], uint32(len(data)))
+	buf.Write(tag[:])
+	buf.Write(data)
+	var pad [8]byte
+	buf.Write(pad[:(8-len(data)%8)%8])
+}
+
+// validMATName returns whether name is a valid MATLAB variable name.
+func validMATName(name string) bool {
+	if len(name) == 0 || len(name) > 63 {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
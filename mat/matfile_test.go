@@ -0,0 +1,140 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"testing"
+)
+
+func TestMATRoundTrip(t *testing.T) {
+	t.Parallel()
+	vars := map[string]Matrix{
+		"a":          NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		"longerName": NewDense(1, 1, []float64{-1.5}),
+		"v_1":        NewVecDense(3, []float64{7, 8, 9}),
+	}
+	var buf bytes.Buffer
+	err := WriteMAT(&buf, vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ReadMAT(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(vars) {
+		t.Errorf("unexpected number of variables: got %d, want %d", len(got), len(vars))
+	}
+	for name, want := range vars {
+		if !Equal(got[name], want) {
+			t.Errorf("mismatch for %q:\ngot:  %v\nwant: %v", name, Formatted(got[name]), Formatted(want))
+		}
+	}
+
+	for _, name := range []string{"", "1a", "_a", "a-b"} {
+		err := WriteMAT(&buf, map[string]Matrix{name: NewDense(1, 1, nil)})
+		if err != errMATName {
+			t.Errorf("expected error for variable name %q", name)
+		}
+	}
+}
+
+// matMatrixElement returns a miMATRIX element for a variable with the
+// given class, dimensions, name and stored real data.
+func matMatrixElement(order binary.ByteOrder, class uint32, dims []int32, name string, typ uint32, data []byte) []byte {
+	var buf bytes.Buffer
+	flags := make([]byte, 8)
+	order.PutUint32(flags, class)
+	writeMATElement(&buf, order, miUINT32, flags)
+	d := make([]byte, 4*len(dims))
+	for i, v := range dims {
+		order.PutUint32(d[4*i:], uint32(v))
+	}
+	writeMATElement(&buf, order, miINT32, d)
+	if len(name) <= 4 {
+		// Use the small data element format.
+		var tag [8]byte
+		order.PutUint32(tag[:], uint32(len(name))<<16|miINT8)
+		copy(tag[4:], name)
+		buf.Write(tag[:])
+	} else {
+		writeMATElement(&buf, order, miINT8, []byte(name))
+	}
+	writeMATElement(&buf, order, typ, data)
+
+	var elem bytes.Buffer
+	writeMATElement(&elem, order, miMATRIX, buf.Bytes())
+	return elem.Bytes()
+}
+
+func TestReadMAT(t *testing.T) {
+	t.Parallel()
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		var file bytes.Buffer
+		header := make([]byte, matHeaderLen)
+		copy(header, "MATLAB 5.0 MAT-file")
+		order.PutUint16(header[124:], 0x0100)
+		order.PutUint16(header[126:], 'M'<<8|'I')
+		file.Write(header)
+
+		// A double array stored as uint8.
+		file.Write(matMatrixElement(order, mxDoubleClass, []int32{2, 2}, "u8", miUINT8, []byte{1, 2, 3, 4}))
+
+		// A compressed int16 array.
+		i16 := make([]byte, 6)
+		for i, v := range []int16{-1, 2, -3} {
+			order.PutUint16(i16[2*i:], uint16(v))
+		}
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(matMatrixElement(order, 10, []int32{1, 3}, "compressed", miINT16, i16))
+		zw.Close()
+		var tag [8]byte
+		order.PutUint32(tag[:], miCOMPRESSED)
+		order.PutUint32(tag[4:], uint32(z.Len()))
+		file.Write(tag[:])
+		file.Write(z.Bytes())
+
+		// A character array and a 3-D array, which are skipped.
+		file.Write(matMatrixElement(order, 4, []int32{1, 2}, "s", miUINT16, []byte{0, 'a', 0, 'b'}))
+		file.Write(matMatrixElement(order, mxDoubleClass, []int32{1, 1, 2}, "nd", miUINT8, []byte{1, 2}))
+
+		got, err := ReadMAT(&file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]*Dense{
+			"u8":         NewDense(2, 2, []float64{1, 3, 2, 4}),
+			"compressed": NewDense(1, 3, []float64{-1, 2, -3}),
+		}
+		if len(got) != len(want) {
+			t.Errorf("unexpected variables for %v: got %d, want %d", order, len(got), len(want))
+		}
+		for name, w := range want {
+			if g, ok := got[name]; !ok || !Equal(g, w) {
+				t.Errorf("mismatch for %q with %v", name, order)
+			}
+		}
+	}
+
+	// A truncated element claiming nearly 4GiB of data.
+	header := make([]byte, matHeaderLen)
+	copy(header, "MATLAB 5.0 MAT-file")
+	binary.LittleEndian.PutUint16(header[124:], 0x0100)
+	binary.LittleEndian.PutUint16(header[126:], 'M'<<8|'I')
+	var tag [8]byte
+	binary.LittleEndian.PutUint32(tag[:], miMATRIX)
+	binary.LittleEndian.PutUint32(tag[4:], 0xfffffff8)
+	if _, err := ReadMAT(bytes.NewReader(append(append(header, tag[:]...), 1, 2, 3))); err != errMATElement {
+		t.Errorf("unexpected error for truncated element: got:%v want:%v", err, errMATElement)
+	}
+
+	if _, err := ReadMAT(bytes.NewReader(make([]byte, 10))); err != errMATHeader {
+		t.Errorf("expected header error for short input")
+	}
+}
@@ -851,8 +851,18 @@ func normLapack(norm float64, aTrans bool) lapack.MatrixNorm {
 	}
 }
 
+// minParallelSum is the number of elements below which Sum of a RawMatrixer
+// is computed serially when parallel reductions are enabled.
+const minParallelSum = 1 << 16
+
 // Sum returns the sum of the elements of the matrix.
 //
+// Sum is computed serially unless ParallelReductions is set in the
+// package-wide Parallelism settings. In that case the sum of a RawMatrixer
+// with at least 65536 elements, or the MinSize given by the settings, is
+// computed concurrently over blocks of rows. See Parallelism for control over
+// the reproducibility of the result.
+//
 // Sum will panic with ErrZeroLength if the matrix has zero size.
 func Sum(a Matrix) float64 {
 	r, c := a.Dims()
@@ -896,12 +906,21 @@ func Sum(a Matrix) float64 {
 		return sum
 	case RawMatrixer:
 		rm := rma.RawMatrix()
-		for i := 0; i < rm.Rows; i++ {
-			for _, v := range rm.Data[i*rm.Stride : i*rm.Stride+rm.Cols] {
-				sum += v
+		rowsSum := func(start, end int) float64 {
+			var sum float64
+			for i := start; i < end; i++ {
+				for _, v := range rm.Data[i*rm.Stride : i*rm.Stride+rm.Cols] {
+					sum += v
+				}
 			}
+			return sum
 		}
-		return sum
+		p := CurrentParallelism()
+		workers := 1
+		if p.ParallelReductions {
+			workers = parallelWorkers(p, 0, rm.Rows, rm.Rows*rm.Cols, minParallelSum)
+		}
+		return parallelRowSum(p, rm.Rows, rm.Cols, workers, rowsSum)
 	case *VecDense:
 		rm := rma.RawVector()
 		for i := 0; i < rm.N; i++ {
@@ -0,0 +1,272 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMMHeader      = errors.New("mat: invalid Matrix Market header")
+	errMMUnsupported = errors.New("mat: unsupported Matrix Market type")
+	errMMSize        = errors.New("mat: invalid Matrix Market size line")
+	errMMShort       = errors.New("mat: Matrix Market data too short")
+	errMMLong        = errors.New("mat: Matrix Market data too long")
+)
+
+// mmBanner is the first token of a Matrix Market header.
+const mmBanner = "%%MatrixMarket"
+
+// ReadMatrixMarket reads a real or integer matrix in the Matrix Market
+// exchange format from r. Both the coordinate and the array formats are
+// supported, with general, symmetric and skew-symmetric storage, as are
+// coordinate pattern matrices, for which every stored entry has value 1.
+//
+// A matrix in coordinate format is returned as a *COO with the entries of
+// symmetric and skew-symmetric matrices expanded to both triangles. A matrix
+// in array format is returned as a *SymDense if it is symmetric, and as a
+// *Dense otherwise.
+func ReadMatrixMarket(r io.Reader) (Matrix, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errMMHeader
+	}
+	header := strings.Fields(strings.ToLower(sc.Text()))
+	if len(header) != 5 || header[0] != strings.ToLower(mmBanner) || header[1] != "matrix" {
+		return nil, errMMHeader
+	}
+	format, field, symmetry := header[2], header[3], header[4]
+	switch {
+	case format != "coordinate" && format != "array",
+		field != "real" && field != "integer" && field != "pattern",
+		field == "pattern" && format == "array",
+		symmetry != "general" && symmetry != "symmetric" && symmetry != "skew-symmetric":
+		return nil, errMMUnsupported
+	}
+
+	// next returns the fields of the next line that is
+	// neither blank nor a comment.
+	next := func() ([]string, error) {
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || line[0] == '%' {
+				continue
+			}
+			return strings.Fields(line), nil
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	size, err := next()
+	if err != nil {
+		return nil, errMMSize
+	}
+	want := 3
+	if format == "array" {
+		want = 2
+	}
+	if len(size) != want {
+		return nil, errMMSize
+	}
+	dims := make([]int, want)
+	for i, s := range size {
+		dims[i], err = strconv.Atoi(s)
+		if err != nil || dims[i] < 0 {
+			return nil, errMMSize
+		}
+	}
+	rows, cols := dims[0], dims[1]
+	if rows == 0 || cols == 0 || (symmetry != "general" && rows != cols) {
+		return nil, errMMSize
+	}
+
+	if format == "coordinate" {
+		nnz := dims[2]
+		if int64(rows) <= maxLen/int64(cols) && nnz > rows*cols {
+			return nil, errMMSize
+		}
+		m := &COO{r: rows, c: cols}
+		for k := 0; k < nnz; k++ {
+			f, err := next()
+			if err != nil {
+				if err == io.EOF {
+					return nil, errMMShort
+				}
+				return nil, err
+			}
+			if (field == "pattern" && len(f) != 2) || (field != "pattern" && len(f) != 3) {
+				return nil, fmt.Errorf("mat: invalid Matrix Market entry %d", k+1)
+			}
+			i, erri := strconv.Atoi(f[0])
+			j, errj := strconv.Atoi(f[1])
+			if erri != nil || errj != nil || i < 1 || i > rows || j < 1 || j > cols {
+				return nil, fmt.Errorf("mat: invalid Matrix Market index in entry %d", k+1)
+			}
+			if i == j && symmetry == "skew-symmetric" {
+				return nil, fmt.Errorf("mat: diagonal Matrix Market entry %d in skew-symmetric matrix", k+1)
+			}
+			i--
+			j--
+			v := 1.0
+			if field != "pattern" {
+				v, err = strconv.ParseFloat(f[2], 64)
+				if err != nil {
+					return nil, fmt.Errorf("mat: invalid Matrix Market value in entry %d: %w", k+1, err)
+				}
+			}
+			m.Append(i, j, v)
+			if i != j {
+				switch symmetry {
+				case "symmetric":
+					m.Append(j, i, v)
+				case "skew-symmetric":
+					m.Append(j, i, -v)
+				}
+			}
+		}
+		if _, err := next(); err != io.EOF {
+			if err != nil {
+				return nil, err
+			}
+			return nil, errMMLong
+		}
+		return m, nil
+	}
+
+	// The array format stores values in column-major order, holding
+	// only the lower triangle of symmetric matrices and the strictly
+	// lower triangle of skew-symmetric matrices. The values are read
+	// before the matrix is allocated so that the size line alone
+	// cannot cause a large allocation.
+	if int64(rows) > maxLen/int64(cols)/int64(sizeFloat64) {
+		return nil, errTooBig
+	}
+	n := rows * cols
+	switch symmetry {
+	case "symmetric":
+		n = rows * (rows + 1) / 2
+	case "skew-symmetric":
+		n = rows * (rows - 1) / 2
+	}
+	var vals []float64
+	for k := 0; k < n; k++ {
+		f, err := next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errMMShort
+			}
+			return nil, err
+		}
+		if len(f) != 1 {
+			return nil, errors.New("mat: invalid Matrix Market array entry")
+		}
+		v, err := strconv.ParseFloat(f[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+	var m Matrix
+	switch symmetry {
+	case "general":
+		d := NewDense(rows, cols, nil)
+		for j := 0; j < cols; j++ {
+			for i := 0; i < rows; i++ {
+				d.set(i, j, vals[0])
+				vals = vals[1:]
+			}
+		}
+		m = d
+	case "symmetric":
+		s := NewSymDense(rows, nil)
+		for j := 0; j < cols; j++ {
+			for i := j; i < rows; i++ {
+				s.SetSym(i, j, vals[0])
+				vals = vals[1:]
+			}
+		}
+		m = s
+	case "skew-symmetric":
+		d := NewDense(rows, cols, nil)
+		for j := 0; j < cols; j++ {
+			for i := j + 1; i < rows; i++ {
+				d.set(i, j, vals[0])
+				d.set(j, i, -vals[0])
+				vals = vals[1:]
+			}
+		}
+		m = d
+	}
+	if _, err := next(); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errMMLong
+	}
+	return m, nil
+}
+
+// WriteMatrixMarket writes m to w in the Matrix Market exchange format.
+// Sparse matrices of type *COO, *CSR and *CSC are written in real general
+// coordinate format, with the duplicate entries of a *COO summed. Other
+// matrices are written in real array format, using symmetric storage if m
+// implements Symmetric and general storage otherwise.
+func WriteMatrixMarket(w io.Writer, m Matrix) error {
+	bw := bufio.NewWriter(w)
+	r, c := m.Dims()
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	var sparse NonZeroDoer
+	switch t := m.(type) {
+	case *COO:
+		sparse = t.ToCSR()
+	case *CSR:
+		sparse = t
+	case *CSC:
+		sparse = t
+	}
+	if sparse != nil {
+		var nnz int
+		sparse.DoNonZero(func(_, _ int, _ float64) { nnz++ })
+		fmt.Fprintf(bw, "%s matrix coordinate real general\n%d %d %d\n", mmBanner, r, c, nnz)
+		sparse.DoNonZero(func(i, j int, v float64) {
+			fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, format(v))
+		})
+		return bw.Flush()
+	}
+
+	if s, ok := m.(Symmetric); ok {
+		n := s.SymmetricDim()
+		fmt.Fprintf(bw, "%s matrix array real symmetric\n%d %d\n", mmBanner, n, n)
+		for j := 0; j < n; j++ {
+			for i := j; i < n; i++ {
+				fmt.Fprintln(bw, format(s.At(i, j)))
+			}
+		}
+		return bw.Flush()
+	}
+
+	fmt.Fprintf(bw, "%s matrix array real general\n%d %d\n", mmBanner, r, c)
+	for j := 0; j < c; j++ {
+		for i := 0; i < r; i++ {
+			fmt.Fprintln(bw, format(m.At(i, j)))
+		}
+	}
+	return bw.Flush()
+}
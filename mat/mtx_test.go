@@ -0,0 +1,147 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		in   string
+		want Matrix
+		err  bool
+	}{
+		{
+			in: `%%MatrixMarket matrix coordinate real general
+% A comment.
+3 4 4
+1 1 1.5
+2 3 -2
+3 4 3e1
+1 1 0.5
+`,
+			want: NewDense(3, 4, []float64{
+				2, 0, 0, 0,
+				0, 0, -2, 0,
+				0, 0, 0, 30,
+			}),
+		},
+		{
+			in: `%%MatrixMarket matrix coordinate integer symmetric
+3 3 3
+1 1 4
+3 1 -1
+2 2 5
+`,
+			want: NewDense(3, 3, []float64{
+				4, 0, -1,
+				0, 5, 0,
+				-1, 0, 0,
+			}),
+		},
+		{
+			in: `%%MatrixMarket matrix coordinate pattern skew-symmetric
+2 2 1
+2 1
+`,
+			want: NewDense(2, 2, []float64{
+				0, -1,
+				1, 0,
+			}),
+		},
+		{
+			in: `%%MatrixMarket matrix array real general
+2 3
+1
+4
+2
+5
+3
+6
+`,
+			want: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}),
+		},
+		{
+			in: `%%MatrixMarket matrix array real symmetric
+2 2
+1
+2
+3
+`,
+			want: NewSymDense(2, []float64{
+				1, 2,
+				2, 3,
+			}),
+		},
+		{in: "%%MatrixMarket matrix coordinate complex general\n1 1 0\n", err: true},
+		{in: "%%MatrixMarket matrix array pattern general\n1 1\n", err: true},
+		{in: "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", err: true},
+		{in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 1\n2 2 2\n", err: true},
+		{in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n", err: true},
+		{in: "%%MatrixMarket matrix array real symmetric\n2 3\n", err: true},
+		{in: "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n1 1 1\n", err: true},
+		{in: "%%MatrixMarket matrix coordinate real general\n2 2 5\n", err: true},
+		{in: "%%MatrixMarket matrix array real general\n4000000000 4000000000\n1\n", err: true},
+		{in: "%%MatrixMarket matrix array real general\n4000000 4000000\n1\n", err: true},
+		{in: "not a header\n", err: true},
+	} {
+		got, err := ReadMatrixMarket(strings.NewReader(test.in))
+		if (err != nil) != test.err {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if test.err {
+			continue
+		}
+		if _, ok := test.want.(*SymDense); ok {
+			if _, ok := got.(*SymDense); !ok {
+				t.Errorf("test %d: unexpected type %T, want *SymDense", i, got)
+			}
+		}
+		if !Equal(got, test.want) {
+			t.Errorf("test %d: unexpected result:\ngot:  %v\nwant: %v", i, Formatted(got), Formatted(test.want))
+		}
+	}
+}
+
+func TestMatrixMarketRoundTrip(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	d := randomSparse(7, 5, 0.3, rnd)
+	s := NewSymDense(4, nil)
+	for i := 0; i < 4; i++ {
+		for j := i; j < 4; j++ {
+			s.SetSym(i, j, rnd.NormFloat64())
+		}
+	}
+	for _, m := range []Matrix{
+		d,
+		s,
+		CSRCopyOf(d),
+		CSRCopyOf(d).TCSC(),
+		NewCOO(2, 2, []int{0, 0, 1}, []int{1, 1, 0}, []float64{1, 2, 3}),
+	} {
+		var buf bytes.Buffer
+		if err := WriteMatrixMarket(&buf, m); err != nil {
+			t.Fatalf("unexpected error writing %T: %v", m, err)
+		}
+		got, err := ReadMatrixMarket(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error reading %T: %v", m, err)
+		}
+		if !Equal(got, m) {
+			t.Errorf("round trip mismatch for %T:\ngot:  %v\nwant: %v", m, Formatted(got), Formatted(m))
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+const (
+	badNMF         = "mat: invalid NMF factorization"
+	badNMFNegative = "mat: negative element in NMF input"
+)
+
+// NMFKind specifies the algorithm used to compute a non-negative matrix
+// factorization.
+type NMFKind int
+
+const (
+	// NMFHALS specifies the hierarchical alternating least squares
+	// algorithm, which updates one row of H or column of W at a time.
+	// It usually converges in far fewer iterations than the
+	// multiplicative update algorithm.
+	NMFHALS NMFKind = iota
+
+	// NMFMultiplicative specifies the multiplicative update algorithm
+	// of Lee and Seung.
+	NMFMultiplicative
+)
+
+// NMFSettings holds the settings for a non-negative matrix factorization.
+// The zero value of each field selects its default.
+type NMFSettings struct {
+	// Kind is the algorithm used for the factorization.
+	Kind NMFKind
+
+	// MaxIterations is the maximum number of iterations. The
+	// default is 200.
+	MaxIterations int
+
+	// Tolerance is the relative decrease of the residual norm
+	// between iterations below which the factorization is
+	// considered to have converged. The default is 1e-4.
+	Tolerance float64
+
+	// Src is the source of randomness used to initialize the
+	// factors. If Src is nil, the global random source is used.
+	Src rand.Source
+
+	// Monitor, if not nil, is called after each iteration with
+	// the iteration number and the residual norm ‖A - W*H‖_F.
+	Monitor func(iter int, residual float64)
+}
+
+// NMF is a type for creating and using the non-negative matrix factorization
+// of a matrix.
+type NMF struct {
+	w, h *Dense
+
+	iterations int
+	residual   float64
+}
+
+// succFact returns whether the receiver contains a factorization.
+func (nmf *NMF) succFact() bool {
+	return nmf.w != nil && !nmf.w.IsEmpty()
+}
+
+// Factorize computes an approximate non-negative matrix factorization of
+// the m×n non-negative matrix A with inner dimension rank,
+//  A ≈ W * H
+// where W is an m×rank and H is a rank×n non-negative matrix, by minimizing
+// the Frobenius norm ‖A - W*H‖_F. If settings is nil, default settings are
+// used. The factorization is not unique and depends on the random
+// initialization of W and H.
+//
+// Factorize will panic if rank is not positive or is greater than min(m,n),
+// or if A has a negative element.
+//
+// Factorize returns whether the iteration converged within the iteration
+// limit. The factorization from the final iteration is held by the receiver
+// in either case.
+func (nmf *NMF) Factorize(a Matrix, rank int, settings *NMFSettings) (converged bool) {
+	m, n := a.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic(badNMF)
+	}
+	ad := DenseCopyOf(a)
+	var mean float64
+	for i := 0; i < m; i++ {
+		for _, v := range ad.RawRowView(i) {
+			if v < 0 {
+				panic(badNMFNegative)
+			}
+			mean += v
+		}
+	}
+	mean /= float64(m * n)
+
+	if settings == nil {
+		settings = &NMFSettings{}
+	}
+	maxIter := settings.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 200
+	}
+	tol := settings.Tolerance
+	if tol <= 0 {
+		tol = 1e-4
+	}
+	uniform := rand.Float64
+	if settings.Src != nil {
+		uniform = rand.New(settings.Src).Float64
+	}
+
+	// Initialize the factors with uniform random values scaled so
+	// that the elements of W*H have the same mean as those of A.
+	scale := 2 * math.Sqrt(mean/float64(rank))
+	w := NewDense(m, rank, nil)
+	h := NewDense(rank, n, nil)
+	for i := range w.mat.Data {
+		w.mat.Data[i] = scale * uniform()
+	}
+	for i := range h.mat.Data {
+		h.mat.Data[i] = scale * uniform()
+	}
+
+	var update func(a, w, h *Dense)
+	switch settings.Kind {
+	default:
+		panic("nmf: bad input kind")
+	case NMFHALS:
+		update = nmfHALS
+	case NMFMultiplicative:
+		update = nmfMultiplicative
+	}
+
+	var r Dense
+	residual := func() float64 {
+		r.Mul(w, h)
+		r.Sub(ad, &r)
+		return Norm(&r, 2)
+	}
+	prev := residual()
+	var iter int
+	for iter = 1; iter <= maxIter; iter++ {
+		update(ad, w, h)
+		res := residual()
+		if settings.Monitor != nil {
+			settings.Monitor(iter, res)
+		}
+		if prev-res <= tol*prev {
+			converged = true
+			prev = res
+			break
+		}
+		prev = res
+	}
+
+	nmf.w = w
+	nmf.h = h
+	nmf.iterations = min(iter, maxIter)
+	nmf.residual = prev
+	return converged
+}
+
+// nmfEpsilon is the lower bound for the elements of the factors, which
+// prevents the updates from stalling at zero.
+const nmfEpsilon = 1e-16
+
+// nmfMultiplicative performs one iteration of the multiplicative
+// update rules
+//  H ← H ∘ (Wᵀ A) / (Wᵀ W H)
+//  W ← W ∘ (A Hᵀ) / (W H Hᵀ)
+func nmfMultiplicative(a, w, h *Dense) {
+	var num, gram, den Dense
+	num.Mul(w.T(), a)
+	gram.Mul(w.T(), w)
+	den.Mul(&gram, h)
+	for i, v := range h.mat.Data {
+		h.mat.Data[i] = math.Max(nmfEpsilon, v*num.mat.Data[i]/(den.mat.Data[i]+nmfEpsilon))
+	}
+
+	num.Reset()
+	gram.Reset()
+	den.Reset()
+	num.Mul(a, h.T())
+	gram.Mul(h, h.T())
+	den.Mul(w, &gram)
+	for i, v := range w.mat.Data {
+		w.mat.Data[i] = math.Max(nmfEpsilon, v*num.mat.Data[i]/(den.mat.Data[i]+nmfEpsilon))
+	}
+}
+
+// nmfHALS performs one iteration of hierarchical alternating least
+// squares, updating each row of H and then each column of W in turn.
+func nmfHALS(a, w, h *Dense) {
+	k, n := h.Dims()
+	m, _ := w.Dims()
+
+	var wta, wtw Dense
+	wta.Mul(w.T(), a)
+	wtw.Mul(w.T(), w)
+	for l := 0; l < k; l++ {
+		d := wtw.at(l, l)
+		if d == 0 {
+			continue
+		}
+		hl := h.RawRowView(l)
+		for j := 0; j < n; j++ {
+			var s float64
+			for p := 0; p < k; p++ {
+				s += wtw.at(l, p) * h.at(p, j)
+			}
+			hl[j] = math.Max(nmfEpsilon, hl[j]+(wta.at(l, j)-s)/d)
+		}
+	}
+
+	var aht, hht Dense
+	aht.Mul(a, h.T())
+	hht.Mul(h, h.T())
+	for l := 0; l < k; l++ {
+		d := hht.at(l, l)
+		if d == 0 {
+			continue
+		}
+		for i := 0; i < m; i++ {
+			wi := w.RawRowView(i)
+			var s float64
+			for p := 0; p < k; p++ {
+				s += wi[p] * hht.at(p, l)
+			}
+			wi[l] = math.Max(nmfEpsilon, wi[l]+(aht.at(i, l)-s)/d)
+		}
+	}
+}
+
+// FactorizeRank computes non-negative matrix factorizations of A with
+// increasing rank, starting at one, until the relative residual
+// ‖A - W*H‖_F / ‖A‖_F is at most tol or maxRank is reached. The receiver
+// holds the factorization with the selected rank, which is returned.
+// See Factorize for a description of the remaining parameters.
+//
+// FactorizeRank will panic if maxRank is not positive or is greater than
+// min(m,n), or if A has a negative element.
+func (nmf *NMF) FactorizeRank(a Matrix, maxRank int, tol float64, settings *NMFSettings) (rank int, converged bool) {
+	m, n := a.Dims()
+	if maxRank < 1 || min(m, n) < maxRank {
+		panic(badNMF)
+	}
+	norm := Norm(a, 2)
+	for rank = 1; rank <= maxRank; rank++ {
+		converged = nmf.Factorize(a, rank, settings)
+		if nmf.residual <= tol*norm {
+			break
+		}
+	}
+	return min(rank, maxRank), converged
+}
+
+// Rank returns the inner dimension of the factorization. Rank will panic
+// if the receiver does not contain a factorization.
+func (nmf *NMF) Rank() int {
+	if !nmf.succFact() {
+		panic(badNMF)
+	}
+	_, k := nmf.w.Dims()
+	return k
+}
+
+// Iterations returns the number of iterations performed to compute the
+// factorization. Iterations will panic if the receiver does not contain a
+// factorization.
+func (nmf *NMF) Iterations() int {
+	if !nmf.succFact() {
+		panic(badNMF)
+	}
+	return nmf.iterations
+}
+
+// Residual returns the Frobenius norm of the residual of the factorization,
+// ‖A - W*H‖_F. Residual will panic if the receiver does not contain a
+// factorization.
+func (nmf *NMF) Residual() float64 {
+	if !nmf.succFact() {
+		panic(badNMF)
+	}
+	return nmf.residual
+}
+
+// WTo extracts the m×rank non-negative factor W, placing the result into
+// dst. If dst is empty, WTo will resize dst to be m×rank. When dst is
+// non-empty, WTo will panic if dst is not m×rank. WTo will also panic if
+// the receiver does not contain a factorization.
+func (nmf *NMF) WTo(dst *Dense) {
+	if !nmf.succFact() {
+		panic(badNMF)
+	}
+	r, c := nmf.w.Dims()
+	dst.reuseAsNonZeroed(r, c)
+	dst.Copy(nmf.w)
+}
+
+// HTo extracts the rank×n non-negative factor H, placing the result into
+// dst. If dst is empty, HTo will resize dst to be rank×n. When dst is
+// non-empty, HTo will panic if dst is not rank×n. HTo will also panic if
+// the receiver does not contain a factorization.
+func (nmf *NMF) HTo(dst *Dense) {
+	if !nmf.succFact() {
+		panic(badNMF)
+	}
+	r, c := nmf.h.Dims()
+	dst.reuseAsNonZeroed(r, c)
+	dst.Copy(nmf.h)
+}
@@ -0,0 +1,93 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNMF(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, k = 20, 15, 3
+
+	// Construct an exactly rank-k non-negative matrix.
+	w := NewDense(m, k, nil)
+	h := NewDense(k, n, nil)
+	for i := range w.mat.Data {
+		w.mat.Data[i] = rnd.Float64()
+	}
+	for i := range h.mat.Data {
+		h.mat.Data[i] = rnd.Float64()
+	}
+	var a Dense
+	a.Mul(w, h)
+	norm := Norm(&a, 2)
+
+	for _, test := range []struct {
+		kind    NMFKind
+		maxIter int
+		tol     float64
+	}{
+		{kind: NMFHALS, maxIter: 2000, tol: 1e-3},
+		{kind: NMFMultiplicative, maxIter: 5000, tol: 2e-2},
+	} {
+		var calls int
+		last := norm
+		settings := &NMFSettings{
+			Kind:          test.kind,
+			MaxIterations: test.maxIter,
+			Tolerance:     1e-10,
+			Src:           rand.NewSource(2),
+			Monitor: func(iter int, res float64) {
+				calls++
+				if res > last+1e-12*norm {
+					t.Errorf("kind %d: residual increased at iteration %d", test.kind, iter)
+				}
+				last = res
+			},
+		}
+		var nmf NMF
+		nmf.Factorize(&a, k, settings)
+		if calls != nmf.Iterations() {
+			t.Errorf("kind %d: unexpected number of monitor calls: got %d, want %d", test.kind, calls, nmf.Iterations())
+		}
+
+		var gw, gh, got Dense
+		nmf.WTo(&gw)
+		nmf.HTo(&gh)
+		for _, v := range append(gw.RawMatrix().Data, gh.RawMatrix().Data...) {
+			if v < 0 {
+				t.Fatalf("kind %d: negative element in factor", test.kind)
+			}
+		}
+		got.Mul(&gw, &gh)
+		got.Sub(&a, &got)
+		res := Norm(&got, 2)
+		if math.Abs(res-nmf.Residual()) > 1e-12*res {
+			t.Errorf("kind %d: residual mismatch: got %v, want %v", test.kind, nmf.Residual(), res)
+		}
+		if res > test.tol*norm {
+			t.Errorf("kind %d: relative residual too large: %v", test.kind, res/norm)
+		}
+	}
+
+	var nmf NMF
+	rank, _ := nmf.FactorizeRank(&a, 5, 1e-2, &NMFSettings{MaxIterations: 2000, Src: rand.NewSource(2)})
+	if rank != k {
+		t.Errorf("unexpected selected rank: got %d, want %d", rank, k)
+	}
+	if nmf.Rank() != rank {
+		t.Errorf("unexpected factorization rank: got %d, want %d", nmf.Rank(), rank)
+	}
+
+	a.Set(0, 0, -1)
+	if p, _ := panics(func() { nmf.Factorize(&a, k, nil) }); !p {
+		t.Errorf("expected panic for negative input")
+	}
+}
@@ -38,3 +38,18 @@ func offsetComplex(a, b []complex128) int {
 	// move. See https://golang.org/issue/12445.
 	return int(vb0.UnsafeAddr()-va0.UnsafeAddr()) / sizeOfComplex128
 }
+
+var sizeOfFloat32 = int(reflect.TypeOf(float32(0)).Size())
+
+// offset32 returns the number of float32 values b[0] is after a[0].
+func offset32(a, b []float32) int {
+	va0 := reflect.ValueOf(a).Index(0)
+	vb0 := reflect.ValueOf(b).Index(0)
+	if va0.Addr() == vb0.Addr() {
+		return 0
+	}
+	// This expression must be atomic with respect to GC moves.
+	// At this stage this is true, because the GC does not
+	// move. See https://golang.org/issue/12445.
+	return int(vb0.UnsafeAddr()-va0.UnsafeAddr()) / sizeOfFloat32
+}
@@ -0,0 +1,158 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Parallelism holds the package-wide settings for the operations in mat that
+// are computed concurrently, such as Dense.ApplyParallel and, when
+// ParallelReductions is set, Sum of large dense matrices. Matrix products are
+// computed by the registered BLAS implementation, which manages its own
+// parallelism.
+type Parallelism struct {
+	// Workers is the maximum number of goroutines used by a parallel
+	// operation. If Workers is zero, runtime.GOMAXPROCS(0) goroutines
+	// are used. Setting Workers to one disables parallel execution.
+	Workers int
+
+	// MinSize is the number of matrix elements below which operations
+	// are performed serially. If MinSize is zero, each operation uses
+	// its own default threshold.
+	MinSize int
+
+	// ParallelReductions specifies that reductions over the elements
+	// of large dense matrices, such as Sum, may be computed
+	// concurrently. Reductions are computed serially by default.
+	ParallelReductions bool
+
+	// Deterministic specifies that reductions must partition their
+	// work and combine partial results in an order that depends only
+	// on the size of the problem, so that floating point results are
+	// reproducible independent of Workers, MinSize, GOMAXPROCS and
+	// goroutine scheduling. The partition is used even when the
+	// reduction is computed serially. When Deterministic is false, the
+	// partition of a parallel reduction depends on the number of
+	// workers.
+	Deterministic bool
+}
+
+var (
+	parallelMu sync.RWMutex
+	parallel   Parallelism
+)
+
+// SetParallelism sets the package-wide parallelism settings and returns the
+// previous settings. SetParallelism is safe to call concurrently with
+// operations in the package; operations that have already started use the
+// settings in effect when they started. SetParallelism will panic if
+// p.Workers or p.MinSize is negative.
+func SetParallelism(p Parallelism) (prev Parallelism) {
+	if p.Workers < 0 || p.MinSize < 0 {
+		panic("mat: negative parallelism setting")
+	}
+	parallelMu.Lock()
+	prev = parallel
+	parallel = p
+	parallelMu.Unlock()
+	return prev
+}
+
+// CurrentParallelism returns the package-wide parallelism settings.
+func CurrentParallelism() Parallelism {
+	parallelMu.RLock()
+	defer parallelMu.RUnlock()
+	return parallel
+}
+
+// parallelWorkers returns the number of goroutines to use for an operation on
+// size elements that can be split into at most parts independent pieces.
+// If workers is positive it overrides the package-wide Workers setting, and
+// defaultMin is used when no MinSize has been set.
+func parallelWorkers(p Parallelism, workers, parts, size, defaultMin int) int {
+	if workers <= 0 {
+		workers = p.Workers
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	minSize := p.MinSize
+	if minSize == 0 {
+		minSize = defaultMin
+	}
+	if size < minSize {
+		return 1
+	}
+	return max(1, min(workers, parts))
+}
+
+// parallelRanges partitions [0, n) into chunks contiguous ranges of nearly
+// equal length and calls fn for each of them using workers goroutines. The
+// chunk index passed to fn identifies the range in increasing order.
+// parallelRanges returns when all calls to fn have returned.
+func parallelRanges(n, chunks, workers int, fn func(chunk, start, end int)) {
+	chunks = min(chunks, n)
+	workers = min(workers, chunks)
+	size := n / chunks
+	rem := n % chunks
+	bounds := func(c int) (start, end int) {
+		start = c*size + min(c, rem)
+		end = start + size
+		if c < rem {
+			end++
+		}
+		return start, end
+	}
+	if workers <= 1 {
+		for c := 0; c < chunks; c++ {
+			start, end := bounds(c)
+			fn(c, start, end)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for c := w; c < chunks; c += workers {
+				start, end := bounds(c)
+				fn(c, start, end)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// reductionBlock is the approximate number of elements combined into each
+// partial result of a deterministic parallel reduction.
+const reductionBlock = 1 << 14
+
+// parallelRowSum returns the sum over the rows of an r×c matrix, where
+// rowsSum returns the sum of the rows in [start, end). The rows are split
+// into blocks whose sums are computed using the given number of workers
+// and then combined in row order. If p.Deterministic is set the blocks
+// depend only on r and c, otherwise there is one block per worker.
+func parallelRowSum(p Parallelism, r, c, workers int, rowsSum func(start, end int) float64) float64 {
+	if workers <= 1 && !p.Deterministic {
+		return rowsSum(0, r)
+	}
+	chunks := workers
+	if p.Deterministic {
+		rows := max(1, reductionBlock/c)
+		chunks = (r + rows - 1) / rows
+	}
+	partial := make([]float64, min(chunks, r))
+	parallelRanges(r, chunks, workers, func(chunk, start, end int) {
+		partial[chunk] = rowsSum(start, end)
+	})
+	var sum float64
+	for _, v := range partial {
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,117 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestParallelism(t *testing.T) {
+	// This test changes package-wide state and so is not run in parallel.
+	orig := SetParallelism(Parallelism{Workers: 3, MinSize: 10, Deterministic: true})
+	defer SetParallelism(orig)
+
+	if got := CurrentParallelism(); got != (Parallelism{Workers: 3, MinSize: 10, Deterministic: true}) {
+		t.Errorf("unexpected parallelism settings: %+v", got)
+	}
+	if p, _ := panics(func() { SetParallelism(Parallelism{Workers: -1}) }); !p {
+		t.Error("expected panic for negative workers")
+	}
+
+	for _, test := range []struct {
+		p                       Parallelism
+		workers, parts, size, d int
+		want                    int
+	}{
+		{p: Parallelism{Workers: 4}, parts: 100, size: 100, d: 10, want: 4},
+		{p: Parallelism{Workers: 4}, parts: 2, size: 100, d: 10, want: 2},
+		{p: Parallelism{Workers: 4}, parts: 100, size: 5, d: 10, want: 1},
+		{p: Parallelism{Workers: 4, MinSize: 200}, parts: 100, size: 100, d: 10, want: 1},
+		{p: Parallelism{Workers: 1}, parts: 100, size: 100, d: 10, want: 1},
+		{p: Parallelism{Workers: 1}, workers: 6, parts: 100, size: 100, d: 10, want: 6},
+	} {
+		got := parallelWorkers(test.p, test.workers, test.parts, test.size, test.d)
+		if got != test.want {
+			t.Errorf("unexpected worker count for %+v, workers=%d parts=%d size=%d: got %d, want %d",
+				test.p, test.workers, test.parts, test.size, got, test.want)
+		}
+	}
+
+	for _, test := range []struct{ n, chunks, workers int }{
+		{1, 1, 1}, {10, 3, 2}, {10, 20, 4}, {100, 7, 7}, {5, 5, 1},
+	} {
+		// Each index and each chunk is written by a single call to fn.
+		seen := make([]int, test.n)
+		starts := make([]int, min(test.n, test.chunks))
+		parallelRanges(test.n, test.chunks, test.workers, func(chunk, start, end int) {
+			for i := start; i < end; i++ {
+				seen[i]++
+			}
+			starts[chunk] = start
+		})
+		for i, v := range seen {
+			if v != 1 {
+				t.Errorf("n=%d chunks=%d: index %d visited %d times", test.n, test.chunks, i, v)
+			}
+		}
+		for c := 1; c < len(starts); c++ {
+			if starts[c] <= starts[c-1] {
+				t.Errorf("n=%d chunks=%d: chunks not in increasing order", test.n, test.chunks)
+			}
+		}
+	}
+}
+
+func TestSumParallel(t *testing.T) {
+	// This test changes package-wide state and so is not run in parallel.
+	orig := CurrentParallelism()
+	defer SetParallelism(orig)
+
+	rnd := rand.New(rand.NewSource(1))
+	a := NewDense(1000, 300, nil)
+	var want float64
+	for i := range a.mat.Data {
+		v := rnd.NormFloat64() * math.Pow(10, float64(rnd.Intn(10)))
+		a.mat.Data[i] = v
+	}
+	SetParallelism(Parallelism{})
+	want = Sum(a)
+
+	// Sum is serial unless parallel reductions are enabled,
+	// so the default settings give the plain row order sum.
+	var serial float64
+	for _, v := range a.mat.Data {
+		serial += v
+	}
+	if want != serial {
+		t.Errorf("unexpected default sum: got %v, want %v", want, serial)
+	}
+
+	var first float64
+	for i, workers := range []int{1, 2, 3, 5, 8} {
+		SetParallelism(Parallelism{Workers: workers, ParallelReductions: true, Deterministic: true})
+		got := Sum(a)
+		if math.Abs(got-want) > 1e-10*math.Abs(want) {
+			t.Errorf("unexpected parallel sum with %d workers: got %v, want %v", workers, got, want)
+		}
+		if i == 0 {
+			first = got
+		} else if got != first {
+			t.Errorf("deterministic sum depends on worker count: %v != %v", got, first)
+		}
+		if Sum(a.T()) != got {
+			t.Errorf("sum of transpose differs from sum with %d workers", workers)
+		}
+	}
+
+	// A serial deterministic sum uses the same partition.
+	SetParallelism(Parallelism{Deterministic: true})
+	if got := Sum(a); got != first {
+		t.Errorf("serial deterministic sum differs from parallel sum: %v != %v", got, first)
+	}
+}
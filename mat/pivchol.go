@@ -0,0 +1,135 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "gonum.org/v1/gonum/lapack/lapack64"
+
+const badPivotedCholesky = "mat: invalid pivoted Cholesky factorization"
+
+// PivotedCholesky is a type for creating and using the Cholesky factorization
+// with complete pivoting of a symmetric positive semidefinite matrix,
+//  Pᵀ * A * P = Uᵀ * U,
+// where P is a permutation matrix and U is upper trapezoidal with r rows, r
+// being the numerical rank of A. Unlike Cholesky, PivotedCholesky can
+// factorize matrices that are only semidefinite, such as kernel and sample
+// covariance matrices, and yields low-rank factors of them.
+type PivotedCholesky struct {
+	// u holds the upper triangular factor in
+	// its leading rank rows.
+	u    *TriDense
+	piv  []int
+	rank int
+}
+
+// Factorize computes the pivoted Cholesky factorization of the symmetric
+// positive semidefinite matrix a. The factorization stops when the largest
+// remaining diagonal element of the Schur complement is less than or equal to
+// tol, and the number of steps taken is the numerical rank of A. If tol is
+// negative, n * ε * max(A[k,k]) is used, where ε is the machine epsilon.
+//
+// Factorize returns whether A is positive definite to the tolerance, having
+// full rank. A factorization of a rank-deficient matrix is still valid and
+// can be used by the other methods of the receiver. Factorize does not
+// verify that A is positive semidefinite; if it is not, the factorization
+// is not meaningful.
+func (c *PivotedCholesky) Factorize(a Symmetric, tol float64) (ok bool) {
+	n := a.SymmetricDim()
+	if c.u == nil {
+		c.u = NewTriDense(n, Upper, nil)
+	} else {
+		c.u.Reset()
+		c.u.reuseAsNonZeroed(n, Upper)
+	}
+	copySymIntoTriangle(c.u, a)
+	c.piv = make([]int, n)
+	work := getFloat64s(2*n, false)
+	defer putFloat64s(work)
+	_, c.rank, ok = lapack64.Pstrf(c.u.asSymBlas(), c.piv, tol, work)
+
+	// Zero the trailing Schur complement so that U holds
+	// the rank rows of the factor.
+	for i := c.rank; i < n; i++ {
+		zero(c.u.mat.Data[i*c.u.mat.Stride+i : i*c.u.mat.Stride+n])
+	}
+	return ok
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (c *PivotedCholesky) isValid() bool {
+	return c.u != nil && !c.u.IsEmpty()
+}
+
+// Rank returns the numerical rank of the factorized matrix.
+// Rank will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) Rank() int {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	return c.rank
+}
+
+// Pivot returns the permutation of the factorization. The jth column of A * P
+// is the pivot[j] column of A. If pivot is nil, a new slice is allocated and
+// returned, otherwise its length must equal the order of the factorized
+// matrix, and Pivot will panic with ErrSliceLengthMismatch otherwise.
+//
+// Pivot will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) Pivot(pivot []int) []int {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	if pivot == nil {
+		pivot = make([]int, len(c.piv))
+	}
+	if len(pivot) != len(c.piv) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(pivot, c.piv)
+	return pivot
+}
+
+// UTo stores the r×n upper trapezoidal factor U of the factorization into
+// dst, where r is the rank, so that Pᵀ * A * P = Uᵀ * U within the tolerance
+// of the factorization.
+//
+// If dst is empty, UTo will resize dst to be r×n. When dst is non-empty, UTo
+// will panic if dst is not r×n. UTo will also panic if the receiver does not
+// contain a factorization or if the rank is zero.
+func (c *PivotedCholesky) UTo(dst *Dense) {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := len(c.piv)
+	dst.reuseAsNonZeroed(c.rank, n)
+	for i := 0; i < c.rank; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		zero(row[:i])
+		copy(row[i:], c.u.mat.Data[i*c.u.mat.Stride+i:i*c.u.mat.Stride+n])
+	}
+}
+
+// LowRankTo stores the n×r low-rank factor F = P * Uᵀ into dst, where r is
+// the rank, so that
+//  A = F * Fᵀ
+// within the tolerance of the factorization.
+//
+// If dst is empty, LowRankTo will resize dst to be n×r. When dst is
+// non-empty, LowRankTo will panic if dst is not n×r. LowRankTo will also
+// panic if the receiver does not contain a factorization or if the rank is
+// zero.
+func (c *PivotedCholesky) LowRankTo(dst *Dense) {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := len(c.piv)
+	dst.reuseAsZeroed(n, c.rank)
+	// Row piv[j] of F is column j of U.
+	for j, p := range c.piv {
+		row := dst.mat.Data[p*dst.mat.Stride : p*dst.mat.Stride+c.rank]
+		for i := 0; i < min(j+1, c.rank); i++ {
+			row[i] = c.u.mat.Data[i*c.u.mat.Stride+j]
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPivotedCholesky(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ n, rank int }{
+		{1, 1}, {3, 3}, {5, 2}, {10, 10}, {20, 7}, {30, 1},
+	} {
+		// Construct a positive semidefinite matrix with the
+		// given rank.
+		f := randomDense(test.n, test.rank, rnd)
+		a := NewSymDense(test.n, nil)
+		a.SymOuterK(1, f)
+
+		var chol PivotedCholesky
+		ok := chol.Factorize(a, -1)
+		if ok != (test.rank == test.n) {
+			t.Errorf("n=%d rank=%d: unexpected ok: %t", test.n, test.rank, ok)
+		}
+		if got := chol.Rank(); got != test.rank {
+			t.Fatalf("n=%d rank=%d: unexpected rank: %d", test.n, test.rank, got)
+		}
+
+		// Check Pᵀ A P = Uᵀ U.
+		var u Dense
+		chol.UTo(&u)
+		for i := 0; i < test.rank; i++ {
+			for j := 0; j < i; j++ {
+				if u.At(i, j) != 0 {
+					t.Fatalf("n=%d rank=%d: U not upper trapezoidal", test.n, test.rank)
+				}
+			}
+		}
+		piv := chol.Pivot(nil)
+		pap := NewDense(test.n, test.n, nil)
+		for i, pi := range piv {
+			for j, pj := range piv {
+				pap.Set(i, j, a.At(pi, pj))
+			}
+		}
+		var utu Dense
+		utu.Mul(u.T(), &u)
+		if !EqualApprox(&utu, pap, 1e-12) {
+			t.Errorf("n=%d rank=%d: Pᵀ A P != Uᵀ U", test.n, test.rank)
+		}
+
+		// Check A = F Fᵀ.
+		var lr, ffT Dense
+		chol.LowRankTo(&lr)
+		if r, c := lr.Dims(); r != test.n || c != test.rank {
+			t.Fatalf("n=%d rank=%d: unexpected low-rank factor dimensions %d×%d", test.n, test.rank, r, c)
+		}
+		ffT.Mul(&lr, lr.T())
+		if !EqualApprox(&ffT, a, 1e-12) {
+			t.Errorf("n=%d rank=%d: A != F Fᵀ", test.n, test.rank)
+		}
+	}
+
+	// A large tolerance truncates the factorization.
+	a := NewSymDense(3, []float64{
+		4, 0, 0,
+		0, 1, 0,
+		0, 0, 1e-3,
+	})
+	var chol PivotedCholesky
+	if chol.Factorize(a, 1e-2) {
+		t.Error("unexpected ok for truncated factorization")
+	}
+	if got := chol.Rank(); got != 2 {
+		t.Errorf("unexpected rank with tolerance: got %d, want 2", got)
+	}
+	if piv := chol.Pivot(nil); piv[0] != 0 || piv[1] != 1 {
+		t.Errorf("unexpected pivots: %v", piv)
+	}
+}
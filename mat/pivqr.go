@@ -0,0 +1,233 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badPivotedQR = "mat: invalid pivoted QR factorization"
+
+// PivotedQR is a type for creating and using the QR factorization with column
+// pivoting of a matrix,
+//  A * P = Q * R,
+// where P is a permutation matrix chosen so that the magnitudes of the
+// diagonal elements of R are non-increasing. Unlike the plain QR
+// factorization, the pivoted factorization reveals the numerical rank of A,
+// and can be used to solve rank-deficient least-squares problems at a lower
+// cost than the singular value decomposition.
+type PivotedQR struct {
+	qr  *Dense
+	tau []float64
+	piv []int
+}
+
+// Factorize computes the QR factorization with column pivoting of the m×n
+// matrix a. The factorization always exists even if A is rank-deficient.
+func (qr *PivotedQR) Factorize(a Matrix) {
+	m, n := a.Dims()
+	if qr.qr == nil {
+		qr.qr = &Dense{}
+	}
+	qr.qr.CloneFrom(a)
+	qr.tau = make([]float64, min(m, n))
+	qr.piv = make([]int, n)
+	for i := range qr.piv {
+		qr.piv[i] = -1
+	}
+	work := []float64{0}
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, -1)
+	work = getFloat64s(int(work[0]), false)
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, len(work))
+	putFloat64s(work)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *PivotedQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsEmpty()
+}
+
+// RankEstimate returns the numerical rank of the factorized matrix estimated
+// as the number of diagonal elements of R with magnitude greater than rcond
+// scaled by the largest diagonal magnitude. If rcond is negative, the default
+// tolerance returned by DefaultRcond is used. The estimate is reliable for
+// most matrices, although there exist matrices for which column pivoting
+// fails to reveal the rank; the singular value decomposition should be used
+// when the estimate must be certain.
+//
+// RankEstimate will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) RankEstimate(rcond float64) int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	k := min(m, n)
+	if rcond < 0 {
+		rcond = DefaultRcond(m, n)
+	}
+	tol := rcond * math.Abs(qr.qr.at(0, 0))
+	for i := 0; i < k; i++ {
+		if math.Abs(qr.qr.at(i, i)) <= tol {
+			return i
+		}
+	}
+	return k
+}
+
+// Pivot returns the column permutation of the factorization. The jth column
+// of A * P is the pivot[j] column of A. If pivot is nil, a new slice is
+// allocated and returned, otherwise its length must equal the number of
+// columns of the factorized matrix, and Pivot will panic with
+// ErrSliceLengthMismatch otherwise.
+//
+// Pivot will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) Pivot(pivot []int) []int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	if pivot == nil {
+		pivot = make([]int, len(qr.piv))
+	}
+	if len(pivot) != len(qr.piv) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(pivot, qr.piv)
+	return pivot
+}
+
+// RTo extracts the m×n upper trapezoidal matrix R from the factorization.
+//
+// If dst is empty, RTo will resize dst to be m×n. When dst is non-empty,
+// RTo will panic if dst is not m×n. RTo will also panic if the receiver
+// does not contain a factorization.
+func (qr *PivotedQR) RTo(dst *Dense) {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	dst.reuseAsNonZeroed(m, n)
+	for i := 0; i < m; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		if i >= n {
+			zero(row)
+			continue
+		}
+		zero(row[:i])
+		copy(row[i:], qr.qr.mat.Data[i*qr.qr.mat.Stride+i:i*qr.qr.mat.Stride+n])
+	}
+}
+
+// QTo extracts the m×m orthonormal matrix Q from the factorization.
+//
+// If dst is empty, QTo will resize dst to be m×m. When dst is non-empty,
+// QTo will panic if dst is not m×m. QTo will also panic if the receiver
+// does not contain a factorization.
+func (qr *PivotedQR) QTo(dst *Dense) {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, _ := qr.qr.Dims()
+	dst.reuseAsZeroed(m, m)
+	for i := 0; i < m; i++ {
+		dst.mat.Data[i*dst.mat.Stride+i] = 1
+	}
+	qr.applyQ(blas.NoTrans, dst)
+}
+
+// applyQ computes Q * b or Qᵀ * b, storing the result in place into b.
+func (qr *PivotedQR) applyQ(trans blas.Transpose, b *Dense) {
+	work := []float64{0}
+	a := qr.qr.mat
+	a.Cols = len(qr.tau)
+	lapack64.Ormqr(blas.Left, trans, a, qr.tau, b.mat, work, -1)
+	work = getFloat64s(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, trans, a, qr.tau, b.mat, work, len(work))
+	putFloat64s(work)
+}
+
+// SolveTo finds the basic solution X of the least-squares problem
+//  minimize over X ‖A * X - B‖_2
+// that has at most rank non-zero elements in each column, where A is the
+// factorized m×n matrix and rank is its effective rank, with
+//  1 ≤ rank ≤ min(m,n).
+// The rank can be computed using PivotedQR.RankEstimate. The solution uses
+// the leading rank columns of A * P, so that the columns of A that are
+// numerically dependent on them are not used. For a matrix of full column
+// rank the solution is the unique least-squares solution.
+//
+// Vectors b are stored in the columns of the m×k matrix B and the resulting
+// vectors x will be stored in the columns of dst. dst must be either empty or
+// have the size equal to n×k.
+//
+// SolveTo returns the residual norms ‖A * x - b‖_2 of the columns of the
+// solution. SolveTo will panic if the receiver does not contain a
+// factorization or if rank is out of range.
+func (qr *PivotedQR) SolveTo(dst *Dense, b Matrix, rank int) []float64 {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic("pivotedqr: rank out of range")
+	}
+	br, bc := b.Dims()
+	if br != m {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(n, bc)
+
+	// Compute Qᵀ * B and solve R11 * Z = (Qᵀ * B)[:rank].
+	w := getDenseWorkspace(m, bc, false)
+	defer putDenseWorkspace(w)
+	w.Copy(b)
+	qr.applyQ(blas.Trans, w)
+	res := make([]float64, bc)
+	for j := range res {
+		res[j] = blas64.Nrm2(blas64.Vector{
+			N:    m - rank,
+			Inc:  w.mat.Stride,
+			Data: w.mat.Data[rank*w.mat.Stride+j:],
+		})
+	}
+	r11 := blas64.Triangular{
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+		N:      rank,
+		Stride: qr.qr.mat.Stride,
+		Data:   qr.qr.mat.Data,
+	}
+	z := w.slice(0, rank, 0, bc)
+	blas64.Trsm(blas.Left, blas.NoTrans, 1, r11, z.mat)
+
+	// Undo the column permutation, X[P[i]] = Z[i].
+	dst.Zero()
+	for i := 0; i < rank; i++ {
+		copy(dst.rawRowView(qr.piv[i]), z.rawRowView(i))
+	}
+	return res
+}
+
+// SolveVecTo finds the basic solution x of the least-squares problem
+//  minimize over x ‖A * x - b‖_2
+// where A is the factorized m×n matrix with effective rank rank. See
+// PivotedQR.SolveTo for the full documentation.
+//
+// The resulting vector x will be stored in dst. dst must be either empty or
+// have length equal to n. SolveVecTo returns the residual norm ‖A * x - b‖_2.
+func (qr *PivotedQR) SolveVecTo(dst *VecDense, b Vector, rank int) float64 {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	_, n := qr.qr.Dims()
+	var x Dense
+	res := qr.SolveTo(&x, b, rank)
+	dst.reuseAsNonZeroed(n)
+	dst.CopyVec(x.ColView(0))
+	return res[0]
+}
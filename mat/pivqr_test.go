@@ -0,0 +1,139 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPivotedQR(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		m, n int
+		sv   []float64
+	}{
+		{m: 5, n: 5, sv: []float64{5, 4, 3, 2, 1}},
+		{m: 8, n: 5, sv: []float64{3, 2, 1}},
+		{m: 4, n: 7, sv: []float64{1, 1}},
+		{m: 10, n: 10, sv: []float64{100, 10, 1, 0.1}},
+		{m: 1, n: 3, sv: []float64{2}},
+	} {
+		a := RandomLowRank(test.m, test.n, test.sv, 0, src)
+		var qr PivotedQR
+		qr.Factorize(a)
+
+		var q, r Dense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		if !isOrthonormal(&q, 1e-14) {
+			t.Errorf("m=%d n=%d: Q not orthonormal", test.m, test.n)
+		}
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < min(i, test.n); j++ {
+				if r.At(i, j) != 0 {
+					t.Fatalf("m=%d n=%d: R not upper triangular", test.m, test.n)
+				}
+			}
+		}
+		for i := 1; i < min(test.m, test.n); i++ {
+			if math.Abs(r.At(i, i)) > math.Abs(r.At(i-1, i-1))*(1+1e-14) {
+				t.Errorf("m=%d n=%d: diagonal of R not non-increasing", test.m, test.n)
+			}
+		}
+
+		// Check that A * P = Q * R.
+		piv := qr.Pivot(nil)
+		ap := NewDense(test.m, test.n, nil)
+		for j, p := range piv {
+			for i := 0; i < test.m; i++ {
+				ap.Set(i, j, a.At(i, p))
+			}
+		}
+		var qrProd Dense
+		qrProd.Mul(&q, &r)
+		if !EqualApprox(ap, &qrProd, 1e-13) {
+			t.Errorf("m=%d n=%d: A*P != Q*R", test.m, test.n)
+		}
+
+		if got := qr.RankEstimate(-1); got != len(test.sv) {
+			t.Errorf("m=%d n=%d: unexpected rank estimate: got %d, want %d", test.m, test.n, got, len(test.sv))
+		}
+	}
+}
+
+func TestPivotedQRSolveTo(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	rnd := rand.New(src)
+
+	// Full rank overdetermined systems agree with the QR solution.
+	for _, test := range []struct{ m, n, bc int }{
+		{5, 5, 1}, {8, 5, 2}, {20, 3, 3},
+	} {
+		a := randomDense(test.m, test.n, rnd)
+		b := randomDense(test.m, test.bc, rnd)
+		var pqr PivotedQR
+		pqr.Factorize(a)
+		var got Dense
+		res := pqr.SolveTo(&got, b, pqr.RankEstimate(-1))
+
+		var qr QR
+		qr.Factorize(a)
+		var want Dense
+		if err := qr.SolveTo(&want, false, b); err != nil {
+			t.Fatalf("m=%d n=%d: unexpected QR error: %v", test.m, test.n, err)
+		}
+		if !EqualApprox(&got, &want, 1e-12) {
+			t.Errorf("m=%d n=%d: unexpected solution:\ngot:\n%v\nwant:\n%v", test.m, test.n, Formatted(&got), Formatted(&want))
+		}
+		var resid Dense
+		resid.Mul(a, &got)
+		resid.Sub(&resid, b)
+		for j := 0; j < test.bc; j++ {
+			if want := Norm(resid.ColView(j), 2); math.Abs(res[j]-want) > 1e-12 {
+				t.Errorf("m=%d n=%d: unexpected residual: got %v, want %v", test.m, test.n, res[j], want)
+			}
+		}
+	}
+
+	// A rank-deficient consistent system is solved exactly by
+	// a basic solution.
+	const m, n, rank = 10, 6, 3
+	a := RandomLowRank(m, n, []float64{3, 2, 1}, 0, src)
+	xTrue := NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		xTrue.SetVec(i, rnd.NormFloat64())
+	}
+	var b VecDense
+	b.MulVec(a, xTrue)
+	var qr PivotedQR
+	qr.Factorize(a)
+	if got := qr.RankEstimate(-1); got != rank {
+		t.Fatalf("unexpected rank estimate: got %d, want %d", got, rank)
+	}
+	var x VecDense
+	res := qr.SolveVecTo(&x, &b, rank)
+	if res > 1e-13 {
+		t.Errorf("unexpected residual for consistent system: %v", res)
+	}
+	var ax VecDense
+	ax.MulVec(a, &x)
+	if !EqualApprox(&ax, &b, 1e-12) {
+		t.Error("basic solution does not solve consistent system")
+	}
+	var nonZero int
+	for i := 0; i < n; i++ {
+		if x.AtVec(i) != 0 {
+			nonZero++
+		}
+	}
+	if nonZero != rank {
+		t.Errorf("unexpected number of non-zero elements in basic solution: got %d, want %d", nonZero, rank)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+const badPolar = "mat: invalid polar factorization"
+
+// eps is the machine epsilon, the difference between 1
+// and the next larger representable float64.
+const eps = 0x1p-52
+
+// PolarKind specifies the algorithm used to compute a polar decomposition.
+type PolarKind int
+
+const (
+	// PolarSVD specifies that the polar decomposition is computed from the
+	// singular value decomposition of the matrix.
+	PolarSVD PolarKind = iota
+
+	// PolarNewton specifies that the polar decomposition is computed using
+	// the scaled Newton iteration. PolarNewton requires a square non-singular
+	// matrix, and is typically faster than PolarSVD for such matrices.
+	PolarNewton
+)
+
+// PolarDecomp is a type for creating and using the polar decomposition of a
+// matrix.
+type PolarDecomp struct {
+	u *Dense
+	p *SymDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (pd *PolarDecomp) succFact() bool {
+	return pd.u != nil && !pd.u.IsEmpty()
+}
+
+// Factorize computes the polar decomposition of the m×n matrix A, where m >= n,
+//  A = U * P
+// where U is an m×n matrix with orthonormal columns and P is an n×n symmetric
+// positive semi-definite matrix. If A has full column rank, P is positive
+// definite and the decomposition is unique. U is the orthonormal matrix nearest
+// to A in the Frobenius norm.
+//
+// The kind parameter specifies the algorithm used. Factorize will panic if
+// m < n, or if kind is PolarNewton and A is not square.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+func (pd *PolarDecomp) Factorize(a Matrix, kind PolarKind) (ok bool) {
+	// Kill the previous factorization.
+	if pd.u != nil {
+		pd.u.Reset()
+	}
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	var u Dense
+	switch kind {
+	default:
+		panic("polar: bad input kind")
+	case PolarSVD:
+		var svd SVD
+		if !svd.Factorize(a, SVDThin) {
+			return false
+		}
+		var w, v Dense
+		svd.UTo(&w)
+		svd.VTo(&v)
+		u.Mul(&w, v.T())
+	case PolarNewton:
+		if m != n {
+			panic(ErrSquare)
+		}
+		if !polarNewton(&u, a) {
+			return false
+		}
+	}
+
+	// P = Uᵀ * A, which is symmetric in exact arithmetic.
+	var p Dense
+	p.Mul(u.T(), a)
+	if pd.p == nil {
+		pd.p = &SymDense{}
+	} else {
+		pd.p.Reset()
+	}
+	pd.p.ReuseAsSym(n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			pd.p.SetSym(i, j, 0.5*(p.At(i, j)+p.At(j, i)))
+		}
+	}
+	pd.u = &u
+	return true
+}
+
+// polarNewton computes the orthogonal polar factor of the square matrix a
+// using the scaled Newton iteration
+//  X_{k+1} = (γ_k X_k + X_k^{-T} / γ_k) / 2,
+// storing the result into dst. It returns false if an iterate is singular
+// or the iteration does not converge.
+func polarNewton(dst *Dense, a Matrix) bool {
+	const maxIter = 100
+	n, _ := a.Dims()
+	tol := math.Sqrt(float64(n) * eps)
+
+	x := DenseCopyOf(a)
+	var inv, next Dense
+	scale := true
+	for k := 0; k < maxIter; k++ {
+		err := inv.Inverse(x)
+		if c, ok := err.(Condition); err != nil && (!ok || math.IsInf(float64(c), 1)) {
+			return false
+		}
+		gamma := 1.0
+		if scale {
+			// Frobenius norm scaling accelerates the initial phase.
+			gamma = math.Sqrt(Norm(&inv, 2) / Norm(x, 2))
+		}
+		next.Scale(gamma/2, x)
+		next.addScaled(&next, 0.5/gamma, inv.T())
+
+		x.Sub(&next, x)
+		delta := Norm(x, 2) / Norm(&next, 2)
+		x.Copy(&next)
+		if delta <= tol {
+			dst.CloneFrom(x)
+			return true
+		}
+		if delta < 1e-2 {
+			scale = false
+		}
+	}
+	return false
+}
+
+// UTo extracts the matrix U with orthonormal columns from a polar
+// decomposition, placing the result into dst. If dst is empty, UTo will
+// resize dst to be m×n. When dst is non-empty, UTo will panic if dst is not
+// m×n. UTo will also panic if the receiver does not contain a successful
+// factorization.
+func (pd *PolarDecomp) UTo(dst *Dense) {
+	if !pd.succFact() {
+		panic(badPolar)
+	}
+	m, n := pd.u.Dims()
+	dst.reuseAsNonZeroed(m, n)
+	dst.Copy(pd.u)
+}
+
+// PTo extracts the symmetric positive semi-definite matrix P from a polar
+// decomposition, placing the result into dst. If dst is empty, PTo will
+// resize dst to be n×n. When dst is non-empty, PTo will panic if dst is not
+// n×n. PTo will also panic if the receiver does not contain a successful
+// factorization.
+func (pd *PolarDecomp) PTo(dst *SymDense) {
+	if !pd.succFact() {
+		panic(badPolar)
+	}
+	n := pd.p.SymmetricDim()
+	dst.reuseAsNonZeroed(n)
+	dst.CopySym(pd.p)
+}
@@ -0,0 +1,77 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPolarDecomp(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{4, 4},
+		{10, 10},
+		{8, 3},
+		{20, 7},
+	} {
+		m, n := test.m, test.n
+		a := NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		kinds := []PolarKind{PolarSVD}
+		if m == n {
+			kinds = append(kinds, PolarNewton)
+		}
+		var want Dense
+		for _, kind := range kinds {
+			var pd PolarDecomp
+			if !pd.Factorize(a, kind) {
+				t.Errorf("unexpected failure: m=%d n=%d kind=%d", m, n, kind)
+				continue
+			}
+			var u Dense
+			var p SymDense
+			pd.UTo(&u)
+			pd.PTo(&p)
+
+			var utu Dense
+			utu.Mul(u.T(), &u)
+			if !EqualApprox(&utu, eye(n), 1e-12) {
+				t.Errorf("U does not have orthonormal columns: m=%d n=%d kind=%d", m, n, kind)
+			}
+			var eig EigenSym
+			if !eig.Factorize(&p, false) {
+				t.Fatalf("unexpected eigendecomposition failure")
+			}
+			if v := eig.Values(nil); v[0] < -1e-12 {
+				t.Errorf("P is not positive semi-definite: m=%d n=%d kind=%d", m, n, kind)
+			}
+			var got Dense
+			got.Mul(&u, &p)
+			if !EqualApprox(&got, a, 1e-12) {
+				t.Errorf("U*P does not equal A: m=%d n=%d kind=%d", m, n, kind)
+			}
+			if kind == PolarSVD {
+				want.CloneFrom(&u)
+			} else if !EqualApprox(&u, &want, 1e-10) {
+				t.Errorf("Newton and SVD polar factors differ: m=%d n=%d", m, n)
+			}
+		}
+	}
+
+	var pd PolarDecomp
+	if pd.Factorize(NewDense(3, 3, nil), PolarNewton) {
+		t.Errorf("expected failure for singular matrix with PolarNewton")
+	}
+}
@@ -17,8 +17,15 @@ const badQR = "mat: invalid QR factorization"
 
 // QR is a type for creating and using the QR factorization of a matrix.
 type QR struct {
-	qr   *Dense
-	tau  []float64
+	qr  *Dense
+	tau []float64
+
+	// q holds the orthonormal factor explicitly once the
+	// factorization has been updated by RankOne. When q is
+	// not nil, tau is unused and the upper triangle of qr
+	// holds R with zeros below the diagonal.
+	q *Dense
+
 	cond float64
 }
 
@@ -60,6 +67,7 @@ func (qr *QR) factorize(a Matrix, norm lapack.MatrixNorm) {
 		qr.qr = &Dense{}
 	}
 	qr.qr.CloneFrom(a)
+	qr.q = nil
 	work := []float64{0}
 	qr.tau = make([]float64, k)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, -1)
@@ -146,16 +154,33 @@ func (qr *QR) QTo(dst *Dense) {
 		dst.Zero()
 	}
 
+	if qr.q != nil {
+		dst.Copy(qr.q)
+		return
+	}
+
 	// Set Q = I.
 	for i := 0; i < r*r; i += r + 1 {
 		dst.mat.Data[i] = 1
 	}
 
 	// Construct Q from the elementary reflectors.
+	qr.applyQ(blas.NoTrans, dst)
+}
+
+// applyQ computes Q * b or Qᵀ * b, storing the result in place into b.
+func (qr *QR) applyQ(trans blas.Transpose, b *Dense) {
+	if qr.q != nil {
+		w := getDenseWorkspace(b.mat.Rows, b.mat.Cols, false)
+		w.Copy(b)
+		blas64.Gemm(trans, blas.NoTrans, 1, qr.q.mat, w.mat, 0, b.mat)
+		putDenseWorkspace(w)
+		return
+	}
 	work := []float64{0}
-	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, -1)
+	lapack64.Ormqr(blas.Left, trans, qr.qr.mat, qr.tau, b.mat, work, -1)
 	work = getFloat64s(int(work[0]), false)
-	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, len(work))
+	lapack64.Ormqr(blas.Left, trans, qr.qr.mat, qr.tau, b.mat, work, len(work))
 	putFloat64s(work)
 }
 
@@ -205,17 +230,9 @@ func (qr *QR) SolveTo(dst *Dense, trans bool, b Matrix) error {
 		for i := c; i < r; i++ {
 			zero(w.mat.Data[i*w.mat.Stride : i*w.mat.Stride+bc])
 		}
-		work := []float64{0}
-		lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, w.mat, work, -1)
-		work = getFloat64s(int(work[0]), false)
-		lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, w.mat, work, len(work))
-		putFloat64s(work)
+		qr.applyQ(blas.NoTrans, w)
 	} else {
-		work := []float64{0}
-		lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, -1)
-		work = getFloat64s(int(work[0]), false)
-		lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, len(work))
-		putFloat64s(work)
+		qr.applyQ(blas.Trans, w)
 
 		ok := lapack64.Trtrs(blas.NoTrans, t, w.mat)
 		if !ok {
@@ -263,3 +280,94 @@ func (qr *QR) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
 	}
 	return qr.SolveTo(dst.asDense(), trans, bm)
 }
+
+// RankOne updates a QR factorization as if a rank-one update had been applied
+// to the original matrix A, storing the result into the receiver. That is, if
+// in the original QR decomposition Q * R = A, in the updated decomposition
+//  Q' * R' = A + alpha * x * yᵀ.
+// A negative alpha downdates the factorization. RankOne will panic if orig
+// does not contain a factorization or if x and y do not have lengths matching
+// the rows and columns of A.
+//
+// The update is computed with Givens rotations in O(m² + m*n) time, where A is
+// m×n, rather than the O(m*n²) required to refactorize. The first update of a
+// factorization computed by Factorize additionally forms Q explicitly, which
+// costs O(m²n); the updated factorization then holds Q explicitly.
+func (qr *QR) RankOne(orig *QR, alpha float64, x, y Vector) {
+	if !orig.isValid() {
+		panic(badQR)
+	}
+	m, n := orig.qr.Dims()
+	if r, c := x.Dims(); r != m || c != 1 {
+		panic(ErrShape)
+	}
+	if r, c := y.Dims(); r != n || c != 1 {
+		panic(ErrShape)
+	}
+
+	if orig != qr {
+		qr.qr = DenseCopyOf(orig.qr)
+		qr.tau = append(qr.tau[:0], orig.tau...)
+		qr.q = nil
+		if orig.q != nil {
+			qr.q = DenseCopyOf(orig.q)
+		}
+	}
+	if qr.q == nil {
+		var q Dense
+		qr.QTo(&q)
+		qr.q = &q
+		for i := 1; i < m; i++ {
+			zero(qr.qr.mat.Data[i*qr.qr.mat.Stride : i*qr.qr.mat.Stride+min(i, n)])
+		}
+		qr.tau = qr.tau[:0]
+	}
+	q := qr.q.mat
+	r := qr.qr.mat
+
+	// rotate applies the Givens rotation G in the (k, k+1) plane to
+	// rows k and k+1 of R from column j, and Gᵀ to columns k and k+1
+	// of Q, so that the product Q * R is unchanged.
+	rotate := func(k, j int, c, s float64) {
+		if j < n {
+			blas64.Rot(
+				blas64.Vector{N: n - j, Inc: 1, Data: r.Data[k*r.Stride+j:]},
+				blas64.Vector{N: n - j, Inc: 1, Data: r.Data[(k+1)*r.Stride+j:]},
+				c, s,
+			)
+		}
+		blas64.Rot(
+			blas64.Vector{N: m, Inc: q.Stride, Data: q.Data[k:]},
+			blas64.Vector{N: m, Inc: q.Stride, Data: q.Data[k+1:]},
+			c, s,
+		)
+	}
+
+	// Compute w = alpha * Qᵀ * x and reduce it to a multiple of e₁,
+	// which makes R upper Hessenberg.
+	w := getFloat64s(m, false)
+	defer putFloat64s(w)
+	wv := blas64.Vector{N: m, Inc: 1, Data: w}
+	xv := NewVecDense(m, nil)
+	xv.CopyVec(x)
+	blas64.Gemv(blas.Trans, alpha, q, xv.mat, 0, wv)
+	for k := m - 2; k >= 0; k-- {
+		c, s, rr, _ := blas64.Rotg(w[k], w[k+1])
+		w[k], w[k+1] = rr, 0
+		rotate(k, k, c, s)
+	}
+
+	// Apply the rank-one update to the first row of R.
+	for j := 0; j < n; j++ {
+		r.Data[j] += w[0] * y.AtVec(j)
+	}
+
+	// Restore R to upper triangular form.
+	for k := 0; k < min(n, m-1); k++ {
+		c, s, rr, _ := blas64.Rotg(r.Data[k*r.Stride+k], r.Data[(k+1)*r.Stride+k])
+		r.Data[k*r.Stride+k] = rr
+		r.Data[(k+1)*r.Stride+k] = 0
+		rotate(k, k+1, c, s)
+	}
+	qr.updateCond(CondNorm)
+}
@@ -216,3 +216,87 @@ func TestQRSolveCondTo(t *testing.T) {
 		}
 	}
 }
+
+func TestQRRankOne(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{5, 5},
+		{10, 5},
+		{30, 12},
+	} {
+		m := test.m
+		n := test.n
+		a := NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var orig QR
+		orig.Factorize(a)
+
+		var qr QR
+		for _, alpha := range []float64{1.5, -0.5, 2} {
+			x := NewVecDense(m, nil)
+			y := NewVecDense(n, nil)
+			for i := 0; i < m; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			for i := 0; i < n; i++ {
+				y.SetVec(i, rnd.NormFloat64())
+			}
+			a.RankOne(a, alpha, x, y)
+
+			src := &qr
+			if qr.qr == nil {
+				src = &orig
+			}
+			qr.RankOne(src, alpha, x, y)
+
+			var q, r, got Dense
+			qr.QTo(&q)
+			if !isOrthonormal(&q, 1e-10) {
+				t.Errorf("Q is not orthonormal: m = %v, n = %v", m, n)
+			}
+			qr.RTo(&r)
+			for i := 0; i < n; i++ {
+				for j := 0; j < i; j++ {
+					if r.At(i, j) != 0 {
+						t.Errorf("R is not upper triangular: m = %v, n = %v", m, n)
+					}
+				}
+			}
+			got.Mul(&q, &r)
+			if !EqualApprox(&got, a, 1e-10) {
+				t.Errorf("updated QR does not equal updated matrix: m = %v, n = %v, alpha = %v", m, n, alpha)
+			}
+
+			var want QR
+			want.Factorize(a)
+			b := NewDense(m, 2, nil)
+			for i := 0; i < m; i++ {
+				b.Set(i, 0, rnd.NormFloat64())
+				b.Set(i, 1, rnd.NormFloat64())
+			}
+			var xGot, xWant Dense
+			if err := qr.SolveTo(&xGot, false, b); err != nil {
+				t.Fatalf("unexpected error from SolveTo: %v", err)
+			}
+			if err := want.SolveTo(&xWant, false, b); err != nil {
+				t.Fatalf("unexpected error from SolveTo: %v", err)
+			}
+			if !EqualApprox(&xGot, &xWant, 1e-8) {
+				t.Errorf("solution mismatch after update: m = %v, n = %v", m, n)
+			}
+		}
+
+		// The original factorization must be unchanged.
+		if orig.q != nil || len(orig.tau) == 0 {
+			t.Errorf("original factorization modified: m = %v, n = %v", m, n)
+		}
+	}
+}
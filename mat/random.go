@@ -0,0 +1,134 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// RandomOrthogonal returns a random n×n orthogonal matrix distributed
+// according to the Haar measure, the uniform distribution on the orthogonal
+// group. If src is nil, the global random source is used.
+//
+// RandomOrthogonal will panic if n is not positive.
+func RandomOrthogonal(n int, src rand.Source) *Dense {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	return randomOrthonormal(n, n, normalSource(src))
+}
+
+// RandomSPD returns a random n×n symmetric positive definite matrix with
+// 2-norm condition number cond. The eigenvalues of the returned matrix are
+// geometrically spaced between 1 and 1/cond, and its eigenvectors are the
+// columns of a random orthogonal matrix distributed according to the Haar
+// measure. If src is nil, the global random source is used.
+//
+// RandomSPD will panic if n is not positive or if cond is less than 1.
+func RandomSPD(n int, cond float64, src rand.Source) *SymDense {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if !(cond >= 1) {
+		panic("mat: condition number less than one")
+	}
+	q := randomOrthonormal(n, n, normalSource(src))
+	vals := make([]float64, n)
+	for i := range vals {
+		if n == 1 {
+			vals[i] = 1
+			break
+		}
+		vals[i] = math.Pow(cond, -float64(i)/float64(n-1))
+	}
+	// Form Q diag(λ) Qᵀ as (Q diag(√λ)) (Q diag(√λ))ᵀ.
+	var a SymDense
+	a.SymOuterK(1, scaledColumns(q, vals, true))
+	return &a
+}
+
+// RandomLowRank returns a random m×n matrix
+//  A = U diag(sv) Vᵀ + noise * G,
+// where U and V have len(sv) orthonormal columns distributed according to the
+// Haar measure and G has independent standard normal elements. The rank of
+// the noise-free part of A is the number of non-zero elements of sv. If src
+// is nil, the global random source is used.
+//
+// RandomLowRank will panic if m or n is not positive, if len(sv) is greater
+// than min(m, n) or if noise is negative.
+func RandomLowRank(m, n int, sv []float64, noise float64, src rand.Source) *Dense {
+	if m <= 0 || n <= 0 {
+		if m == 0 || n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	k := len(sv)
+	if k > min(m, n) {
+		panic(ErrShape)
+	}
+	if noise < 0 {
+		panic("mat: negative noise level")
+	}
+	normFloat64 := normalSource(src)
+	a := NewDense(m, n, nil)
+	if k > 0 {
+		u := randomOrthonormal(m, k, normFloat64)
+		v := randomOrthonormal(n, k, normFloat64)
+		a.Mul(scaledColumns(u, sv, false), v.T())
+	}
+	if noise > 0 {
+		for i := range a.mat.Data {
+			a.mat.Data[i] += noise * normFloat64()
+		}
+	}
+	return a
+}
+
+// normalSource returns a function returning standard normal random numbers
+// drawn from src, or from the global random source if src is nil.
+func normalSource(src rand.Source) func() float64 {
+	if src == nil {
+		return rand.NormFloat64
+	}
+	return rand.New(src).NormFloat64
+}
+
+// scaledColumns scales the columns of a in place by s, or by the square
+// roots of s if sqrt is true, and returns a.
+func scaledColumns(a *Dense, s []float64, sqrt bool) *Dense {
+	r, _ := a.Dims()
+	for i := 0; i < r; i++ {
+		row := a.RawRowView(i)
+		for j, v := range s {
+			if sqrt {
+				v = math.Sqrt(v)
+			}
+			row[j] *= v
+		}
+	}
+	return a
+}
+
+// randomOrthonormal returns an m×k matrix, k ≤ m, with orthonormal columns
+// distributed according to the Haar measure, formed from the QR
+// decomposition of a matrix with standard normal elements with the signs
+// of the columns of Q chosen so that R has a positive diagonal.
+func randomOrthonormal(m, k int, normFloat64 func() float64) *Dense {
+	a := NewDense(m, k, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = normFloat64()
+	}
+	orthonormalize(a, true)
+	return a
+}
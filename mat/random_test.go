@@ -0,0 +1,122 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestRandomOrthogonal(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, n := range []int{1, 2, 5, 20} {
+		q := RandomOrthogonal(n, src)
+		if !isOrthonormal(q, 1e-14) {
+			t.Errorf("n=%d: matrix not orthogonal", n)
+		}
+	}
+
+	// The elements of a Haar distributed orthogonal matrix have
+	// mean zero and mean square 1/n.
+	const (
+		n       = 4
+		samples = 2000
+	)
+	var sum, sumSq float64
+	for i := 0; i < samples; i++ {
+		q := RandomOrthogonal(n, src)
+		sum += q.At(0, 0)
+		sumSq += q.At(0, 0) * q.At(0, 0)
+	}
+	if mean := sum / samples; math.Abs(mean) > 0.05 {
+		t.Errorf("unexpected mean of element: %v", mean)
+	}
+	if msq := sumSq / samples; math.Abs(msq-1.0/n) > 0.02 {
+		t.Errorf("unexpected mean square of element: got %v, want %v", msq, 1.0/n)
+	}
+
+	if p, _ := panics(func() { RandomOrthogonal(0, src) }); !p {
+		t.Error("expected panic for zero size")
+	}
+}
+
+func TestRandomSPD(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		n    int
+		cond float64
+	}{
+		{1, 1}, {2, 10}, {5, 1}, {10, 1e3}, {20, 1e8},
+	} {
+		a := RandomSPD(test.n, test.cond, src)
+		var ed EigenSym
+		if !ed.Factorize(a, false) {
+			t.Fatalf("n=%d cond=%v: eigendecomposition failed", test.n, test.cond)
+		}
+		vals := ed.Values(nil)
+		sort.Float64s(vals)
+		if vals[0] <= 0 {
+			t.Errorf("n=%d cond=%v: matrix not positive definite", test.n, test.cond)
+		}
+		if !scalar.EqualWithinAbs(vals[test.n-1], 1, 1e-12) {
+			t.Errorf("n=%d cond=%v: unexpected largest eigenvalue: %v", test.n, test.cond, vals[test.n-1])
+		}
+		if got := vals[test.n-1] / vals[0]; !scalar.EqualWithinRel(got, test.cond, 1e-6) {
+			t.Errorf("n=%d cond=%v: unexpected condition number: %v", test.n, test.cond, got)
+		}
+	}
+
+	if p, _ := panics(func() { RandomSPD(3, 0.5, src) }); !p {
+		t.Error("expected panic for condition number less than one")
+	}
+}
+
+func TestRandomLowRank(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		m, n int
+		sv   []float64
+	}{
+		{5, 5, nil},
+		{5, 8, []float64{3}},
+		{10, 4, []float64{4, 2, 1}},
+		{12, 12, []float64{10, 5, 5, 1e-3}},
+	} {
+		a := RandomLowRank(test.m, test.n, test.sv, 0, src)
+		var svd SVD
+		if !svd.Factorize(a, SVDNone) {
+			t.Fatalf("m=%d n=%d: SVD failed", test.m, test.n)
+		}
+		vals := svd.Values(nil)
+		for i, v := range vals {
+			var want float64
+			if i < len(test.sv) {
+				want = test.sv[i]
+			}
+			if !scalar.EqualWithinAbs(v, want, 1e-12) {
+				t.Errorf("m=%d n=%d: unexpected singular value %d: got %v, want %v", test.m, test.n, i, v, want)
+			}
+		}
+	}
+
+	// The noise is added to each element with the requested scale.
+	const noise = 0.1
+	a := RandomLowRank(100, 100, nil, noise, src)
+	if got := a.Norm(2) / 100; math.Abs(got-noise) > 0.01 {
+		t.Errorf("unexpected noise level: got %v, want %v", got, noise)
+	}
+
+	if p, _ := panics(func() { RandomLowRank(3, 2, []float64{1, 1, 1}, 0, src) }); !p {
+		t.Error("expected panic for rank greater than dimensions")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// DefaultRcond returns the default relative tolerance used by Rank, NullSpace
+// and PivotedQR.RankEstimate for an m×n matrix,
+//  max(m, n) * ε,
+// where ε is the machine epsilon. Singular values below this tolerance scaled
+// by the largest singular value are indistinguishable from zero in the
+// presence of the rounding errors of the singular value decomposition.
+func DefaultRcond(m, n int) float64 {
+	return float64(max(m, n)) * eps
+}
+
+// Rank returns the numerical rank of a, the number of singular values of a
+// greater than rcond scaled by the largest singular value, as for SVD.Rank.
+// If rcond is negative, the default tolerance returned by DefaultRcond is
+// used.
+//
+// Rank will panic if the singular value decomposition of a fails.
+func Rank(a Matrix, rcond float64) int {
+	var svd SVD
+	if !svd.Factorize(a, SVDNone) {
+		panic(ErrFailedConvergence)
+	}
+	return countAbove(svd.s, rankTol(a, svd.s, rcond))
+}
+
+// NullSpace computes an orthonormal basis for the numerical null space of the
+// m×n matrix a, the span of the right singular vectors of a whose singular
+// values are less than or equal to rcond scaled by the largest singular
+// value, and stores it in the columns of dst. If rcond is negative, the
+// default tolerance returned by DefaultRcond is used. NullSpace returns the
+// numerical rank r of a, and the basis is n×(n-r).
+//
+// If a has full column rank, its null space is trivial, NullSpace returns n
+// and dst is not modified. Otherwise, if dst is empty it is resized to be
+// n×(n-r), and NullSpace will panic if dst is not empty and does not have
+// these dimensions. NullSpace will panic if the singular value decomposition
+// of a fails.
+func NullSpace(dst *Dense, a Matrix, rcond float64) (rank int) {
+	_, n := a.Dims()
+	var svd SVD
+	if !svd.Factorize(a, SVDFullV) {
+		panic(ErrFailedConvergence)
+	}
+	rank = countAbove(svd.s, rankTol(a, svd.s, rcond))
+	if rank == n {
+		return rank
+	}
+	var v Dense
+	svd.VTo(&v)
+	dst.reuseAsNonZeroed(n, n-rank)
+	dst.Copy(v.slice(0, n, rank, n))
+	return rank
+}
+
+// rankTol returns the absolute tolerance for a with descending singular
+// values s corresponding to rcond, using the default if rcond is negative.
+func rankTol(a Matrix, s []float64, rcond float64) float64 {
+	if rcond < 0 {
+		m, n := a.Dims()
+		rcond = DefaultRcond(m, n)
+	}
+	return rcond * s[0]
+}
+
+// countAbove returns the number of leading elements of the descending
+// slice s that are greater than tol.
+func countAbove(s []float64, tol float64) int {
+	for i, v := range s {
+		if v <= tol {
+			return i
+		}
+	}
+	return len(s)
+}
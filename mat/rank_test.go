@@ -0,0 +1,69 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestRankNullSpace(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		m, n  int
+		sv    []float64
+		rcond float64
+		want  int
+	}{
+		{m: 4, n: 4, sv: []float64{4, 3, 2, 1}, rcond: -1, want: 4},
+		{m: 5, n: 3, sv: []float64{1, 1}, rcond: -1, want: 2},
+		{m: 3, n: 6, sv: []float64{2, 1, 1}, rcond: -1, want: 3},
+		{m: 6, n: 6, sv: []float64{1, 1e-3}, rcond: -1, want: 2},
+		{m: 6, n: 6, sv: []float64{1, 1e-3}, rcond: 1e-2, want: 1},
+		{m: 4, n: 7, sv: nil, rcond: -1, want: 0},
+	} {
+		a := RandomLowRank(test.m, test.n, test.sv, 0, src)
+		if got := Rank(a, test.rcond); got != test.want {
+			t.Errorf("m=%d n=%d sv=%v: unexpected rank: got %d, want %d", test.m, test.n, test.sv, got, test.want)
+		}
+
+		var ns Dense
+		rank := NullSpace(&ns, a, test.rcond)
+		if rank != test.want {
+			t.Errorf("m=%d n=%d sv=%v: unexpected null space rank: got %d, want %d", test.m, test.n, test.sv, rank, test.want)
+		}
+		if rank == test.n {
+			if !ns.IsEmpty() {
+				t.Errorf("m=%d n=%d sv=%v: unexpected modification of empty dst", test.m, test.n, test.sv)
+			}
+			dst := NewDense(2, 2, []float64{1, 2, 3, 4})
+			NullSpace(dst, a, test.rcond)
+			if !Equal(dst, NewDense(2, 2, []float64{1, 2, 3, 4})) {
+				t.Errorf("m=%d n=%d sv=%v: unexpected modification of dst", test.m, test.n, test.sv)
+			}
+			continue
+		}
+		r, c := ns.Dims()
+		if r != test.n || c != test.n-rank {
+			t.Fatalf("m=%d n=%d sv=%v: unexpected null space dimensions: %d×%d", test.m, test.n, test.sv, r, c)
+		}
+		var vtv Dense
+		vtv.Mul(ns.T(), &ns)
+		if !EqualApprox(&vtv, eye(c), 1e-14) {
+			t.Errorf("m=%d n=%d sv=%v: null space basis not orthonormal", test.m, test.n, test.sv)
+		}
+		var av Dense
+		av.Mul(a, &ns)
+		tol := test.rcond
+		if tol < 0 {
+			tol = 1e-14
+		}
+		if norm := av.Norm(2); norm > tol*float64(c) {
+			t.Errorf("m=%d n=%d sv=%v: null space vectors not annihilated: ‖A N‖ = %v", test.m, test.n, test.sv, norm)
+		}
+	}
+}
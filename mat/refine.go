@@ -0,0 +1,237 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+// maxRefineIter is the maximum number of iterative refinement steps
+// performed for each column of the solution.
+const maxRefineIter = 5
+
+// ErrorBounds holds the error bounds of a solution of a system of linear
+// equations A * X = B computed with iterative refinement.
+type ErrorBounds struct {
+	// Iterations holds the number of refinement steps
+	// performed for each column of X.
+	Iterations []int
+
+	// Backward holds the componentwise relative backward
+	// error of each column of X, the smallest relative change
+	// in any element of A or B that makes the column an exact
+	// solution.
+	Backward []float64
+
+	// Forward holds an estimated bound on the relative
+	// forward error of each column x of X,
+	//  ‖x - x_true‖_∞ / ‖x‖_∞,
+	// where x_true is the exact solution.
+	Forward []float64
+}
+
+// SolveRefinedTo solves a system of linear equations using the LU decomposition
+// of the matrix a and improves the solution by iterative refinement. It computes
+//  A * X = B if trans == false
+//  Aᵀ * X = B if trans == true
+// storing the matrix X into dst. The matrix a must be the matrix that was
+// factorized by the receiver.
+//
+// Each refinement step computes the residual R = B - A * X in doubled working
+// precision and corrects X by the solution of A * D = R, improving the accuracy
+// of the solution of ill-conditioned systems. The refinement of a column stops
+// when the correction is below the working precision or stops decreasing, or
+// after five steps. SolveRefinedTo returns the backward error and an estimated
+// forward error bound for each column of X.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveRefinedTo will panic if the receiver does not contain a factorization
+// or if a does not have the dimensions of the factorized matrix.
+func (lu *LU) SolveRefinedTo(dst *Dense, trans bool, a, b Matrix) (ErrorBounds, error) {
+	if !lu.isValid() {
+		panic(badLU)
+	}
+	_, n := lu.lu.Dims()
+	if r, c := a.Dims(); r != n || c != n {
+		panic(ErrShape)
+	}
+	err := lu.SolveTo(dst, trans, b)
+	if _, ok := err.(Condition); err != nil && !ok {
+		return ErrorBounds{}, err
+	}
+	op := a
+	if trans {
+		op = a.T()
+	}
+	solve := func(dst *VecDense, t bool, r Vector) {
+		lu.SolveVecTo(dst, trans != t, r)
+	}
+	return refineSolution(dst, op, b, solve), err
+}
+
+// SolveRefinedTo finds the matrix X that solves A * X = B where A is represented
+// by the Cholesky decomposition and improves the solution by iterative
+// refinement. The result is stored into dst. The matrix a must be the matrix
+// that was factorized by the receiver. See LU.SolveRefinedTo for a description
+// of the refinement and the returned error bounds.
+//
+// If the Cholesky decomposition is singular or near-singular a Condition error
+// is returned. See the documentation for Condition for more information.
+// SolveRefinedTo will panic if the receiver does not contain a factorization
+// or if a does not have the dimensions of the factorized matrix.
+func (c *Cholesky) SolveRefinedTo(dst *Dense, a Symmetric, b Matrix) (ErrorBounds, error) {
+	if !c.valid() {
+		panic(badCholesky)
+	}
+	if a.SymmetricDim() != c.chol.mat.N {
+		panic(ErrShape)
+	}
+	err := c.SolveTo(dst, b)
+	if _, ok := err.(Condition); err != nil && !ok {
+		return ErrorBounds{}, err
+	}
+	solve := func(dst *VecDense, _ bool, r Vector) {
+		c.SolveVecTo(dst, r)
+	}
+	return refineSolution(dst, a, b, solve), err
+}
+
+// refineSolution performs iterative refinement of the solution x of
+// a * x = b, where solve solves systems with a, or aᵀ if trans is true,
+// using a factorization of a.
+func refineSolution(x *Dense, a, b Matrix, solve func(dst *VecDense, trans bool, r Vector)) ErrorBounds {
+	n, nrhs := x.Dims()
+	bounds := ErrorBounds{
+		Iterations: make([]int, nrhs),
+		Backward:   make([]float64, nrhs),
+		Forward:    make([]float64, nrhs),
+	}
+
+	const (
+		eps   = 0x1p-53   // The relative machine precision.
+		safe1 = 0x1p-1021 // Twice the smallest normal number.
+	)
+	// nz is the maximum number of non-zero elements in a row of a
+	// plus one, which bounds the rounding error of the residual.
+	nz := float64(n + 1)
+	safe2 := nz * safe1
+
+	arows := make([][]float64, n)
+	for i := range arows {
+		arows[i] = make([]float64, n)
+		for j := range arows[i] {
+			arows[i][j] = a.At(i, j)
+		}
+	}
+	xj := make([]float64, n)
+	r := NewVecDense(n, nil)
+	w := make([]float64, n)
+	d := NewVecDense(n, nil)
+	for j := 0; j < nrhs; j++ {
+		var (
+			lastCorr float64
+			done     bool
+		)
+		for iter := 0; ; iter++ {
+			for i := range xj {
+				xj[i] = x.at(i, j)
+			}
+
+			// Compute the residual r = b - A x in doubled precision
+			// and the componentwise scale |A| |x| + |b|.
+			for i, row := range arows {
+				bi := b.At(i, j)
+				r.setVec(i, residualDot2(bi, row, xj))
+				s := math.Abs(bi)
+				for k, v := range row {
+					s += math.Abs(v) * math.Abs(xj[k])
+				}
+				w[i] = s
+			}
+
+			var berr float64
+			for i, s := range w {
+				ri := math.Abs(r.at(i))
+				if s > safe2 {
+					berr = math.Max(berr, ri/s)
+				} else {
+					berr = math.Max(berr, (ri+safe1)/(s+safe1))
+				}
+			}
+			bounds.Backward[j] = berr
+			bounds.Iterations[j] = iter
+			if done || iter == maxRefineIter || berr == 0 {
+				break
+			}
+
+			// Stop when the correction no longer decreases
+			// substantially, or is below the working precision.
+			solve(d, false, r)
+			corr := Norm(d, math.Inf(1))
+			if iter > 0 && corr > lastCorr/2 {
+				break
+			}
+			var xnorm float64
+			for i := 0; i < n; i++ {
+				v := x.at(i, j) + d.at(i)
+				x.set(i, j, v)
+				xnorm = math.Max(xnorm, math.Abs(v))
+			}
+			lastCorr = corr
+			done = corr <= eps*xnorm
+		}
+
+		// Bound the forward error by
+		//  ‖ |A⁻¹| (|r| + nz ε (|A| |x| + |b|)) ‖_∞ / ‖x‖_∞
+		// estimating the norm of the scaled inverse by Hager's method.
+		for i, s := range w {
+			ri := math.Abs(r.at(i))
+			if s > safe2 {
+				w[i] = ri + nz*eps*s
+			} else {
+				w[i] = ri + nz*eps*s + safe1
+			}
+		}
+		// ‖ |A⁻¹| diag(w) ‖_∞ = ‖ diag(w) A⁻ᵀ ‖_1.
+		est := normEst1(n, func(dst *VecDense, trans bool, v Vector) {
+			if trans {
+				// (diag(w) A⁻ᵀ)ᵀ v = A⁻¹ diag(w) v
+				for i, wi := range w {
+					d.setVec(i, wi*v.AtVec(i))
+				}
+				solve(dst, false, d)
+				return
+			}
+			solve(dst, true, v)
+			for i, wi := range w {
+				dst.setVec(i, wi*dst.at(i))
+			}
+		})
+		var xnorm float64
+		for i := 0; i < n; i++ {
+			xnorm = math.Max(xnorm, math.Abs(x.at(i, j)))
+		}
+		if xnorm != 0 {
+			est /= xnorm
+		}
+		bounds.Forward[j] = est
+	}
+	return bounds
+}
+
+// residualDot2 returns b - a·x computed in doubled working precision
+// using error-free transformations.
+func residualDot2(b float64, a, x []float64) float64 {
+	s, c := b, 0.0
+	for i, v := range a {
+		p := -v * x[i]
+		perr := math.FMA(-v, x[i], -p)
+		t := s + p
+		z := t - s
+		serr := (s - (t - z)) + (p - z)
+		s = t
+		c += perr + serr
+	}
+	return s + c
+}
@@ -0,0 +1,159 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// hilbert returns the n×n Hilbert matrix, which is symmetric positive
+// definite and increasingly ill-conditioned with n.
+func hilbert(n int) *SymDense {
+	a := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a.SetSym(i, j, 1/float64(i+j+1))
+		}
+	}
+	return a
+}
+
+func TestLUSolveRefinedTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 8, 10} {
+		for _, trans := range []bool{false, true} {
+			a := DenseCopyOf(hilbert(n))
+			// Break the symmetry so that transposition matters.
+			for i := 0; i < n; i++ {
+				a.Set(i, 0, a.At(i, 0)*2)
+			}
+			want := randomDense(n, 2, rnd)
+			var b Dense
+			if trans {
+				b.Mul(a.T(), want)
+			} else {
+				b.Mul(a, want)
+			}
+
+			var lu LU
+			lu.Factorize(a)
+			var xr Dense
+			bounds, err := lu.SolveRefinedTo(&xr, trans, a, &b)
+			if _, ok := err.(Condition); err != nil && !ok {
+				t.Fatalf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			op := Matrix(a)
+			if trans {
+				op = a.T()
+			}
+			checkRefined(t, n, op, &b, &xr, bounds)
+		}
+	}
+}
+
+func TestCholeskySolveRefinedTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 8, 10} {
+		a := hilbert(n)
+		want := randomDense(n, 3, rnd)
+		var b Dense
+		b.Mul(a, want)
+
+		var chol Cholesky
+		if !chol.Factorize(a) {
+			t.Fatalf("n=%d: unexpected Cholesky failure", n)
+		}
+		var xr Dense
+		bounds, err := chol.SolveRefinedTo(&xr, a, &b)
+		if _, ok := err.(Condition); err != nil && !ok {
+			t.Fatalf("n=%d: unexpected error: %v", n, err)
+		}
+		checkRefined(t, n, a, &b, &xr, bounds)
+	}
+}
+
+// checkRefined checks that the refined solution xr has a small residual
+// and that its error bounds are consistent. The exact solution of the
+// rounded system is not known, so the forward error is not checked.
+func checkRefined(t *testing.T, n int, a, b, xr Matrix, bounds ErrorBounds) {
+	t.Helper()
+	_, c := b.Dims()
+	if len(bounds.Backward) != c || len(bounds.Forward) != c || len(bounds.Iterations) != c {
+		t.Fatalf("n=%d: unexpected length of error bounds", n)
+	}
+	var r Dense
+	r.Mul(a, xr)
+	r.Sub(b, &r)
+	for j := 0; j < c; j++ {
+		if bounds.Backward[j] > 1e-15 {
+			t.Errorf("n=%d column %d: unexpectedly large backward error: %v", n, j, bounds.Backward[j])
+		}
+		if bounds.Forward[j] <= 0 || bounds.Forward[j] >= 1 {
+			t.Errorf("n=%d column %d: unexpected forward error bound: %v", n, j, bounds.Forward[j])
+		}
+		for i := 0; i < n; i++ {
+			if math.Abs(r.At(i, j)) > 1e-14 {
+				t.Errorf("n=%d column %d: unexpectedly large residual: %v", n, j, r.At(i, j))
+				break
+			}
+		}
+	}
+}
+
+func TestLUSolveRefinedToIllConditioned(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 8
+
+	// Construct a nearly singular integer system with an exactly
+	// representable right-hand side, so that the exact solution is
+	// known.
+	a := NewDense(n, n, nil)
+	for i := 0; i < n-1; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, float64(rnd.Intn(9)-4))
+		}
+	}
+	for j := 0; j < n; j++ {
+		var s float64
+		for i := 0; i < n-1; i++ {
+			s += a.At(i, j)
+		}
+		a.Set(n-1, j, s)
+	}
+	a.Set(n-1, n-1, a.At(n-1, n-1)+0x1p-30)
+	want := NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		want.Set(i, 0, float64(rnd.Intn(9)-4))
+	}
+	var b Dense
+	b.Mul(a, want)
+
+	var lu LU
+	lu.Factorize(a)
+	var x, xr Dense
+	lu.SolveTo(&x, false, &b)
+	bounds, err := lu.SolveRefinedTo(&xr, false, a, &b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if EqualApprox(&x, want, 1e-8) {
+		t.Error("unrefined solution unexpectedly accurate")
+	}
+	if !EqualApprox(&xr, want, 1e-14) {
+		t.Errorf("refined solution not accurate:\ngot: %v\nwant:%v", Formatted(xr.T()), Formatted(want.T()))
+	}
+	if bounds.Iterations[0] == 0 {
+		t.Error("no refinement steps performed")
+	}
+	if bounds.Backward[0] != 0 {
+		t.Errorf("unexpected backward error for exact solution: %v", bounds.Backward[0])
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// SolveCARE computes the stabilizing solution X of the continuous-time
+// algebraic Riccati equation
+//  Aᵀ * X + X * A - X * B * R⁻¹ * Bᵀ * X + Q = 0,
+// placing the result into dst. A is n×n, B is n×m, Q is n×n and symmetric,
+// and R is m×m and symmetric positive definite. The stabilizing solution is
+// the unique symmetric solution for which A - B * R⁻¹ * Bᵀ * X is stable,
+// having all its eigenvalues in the open left half plane. It is the solution
+// required by the linear-quadratic regulator, for which the optimal feedback
+// gain is K = R⁻¹ * Bᵀ * X, and, with A, B and Q replaced by Aᵀ, Cᵀ and the
+// process noise covariance, by the Kalman–Bucy filter.
+//
+// SolveCARE uses the Schur method, computing X from a basis for the stable
+// invariant subspace of the Hamiltonian matrix
+//  [ A    -B * R⁻¹ * Bᵀ ]
+//  [ -Q   -Aᵀ           ].
+//
+// If R is not positive definite, SolveCARE returns ErrNotPSD. If the
+// equation has no stabilizing solution, for example because (A, B) is not
+// stabilizable, ErrNoStabilizing is returned. If the solution is
+// ill-conditioned a Condition error is returned with dst holding the
+// computed solution. SolveCARE will panic if the dimensions of the inputs
+// do not match.
+func SolveCARE(dst *SymDense, a, b Matrix, q, r Symmetric) error {
+	n, g, err := riccatiInputs(a, b, q, r)
+	if err != nil {
+		return err
+	}
+
+	h := NewDense(2*n, 2*n, nil)
+	h.slice(0, n, 0, n).Copy(a)
+	h.slice(0, n, n, 2*n).Scale(-1, g)
+	h.slice(n, 2*n, 0, n).Scale(-1, q)
+	h.slice(n, 2*n, n, 2*n).Scale(-1, a.T())
+
+	return stableSubspaceSolution(dst, h, n, func(v complex128) bool {
+		return real(v) < 0
+	})
+}
+
+// SolveDARE computes the stabilizing solution X of the discrete-time
+// algebraic Riccati equation
+//  Aᵀ * X * A - X - Aᵀ * X * B * (R + Bᵀ * X * B)⁻¹ * Bᵀ * X * A + Q = 0,
+// placing the result into dst. A is n×n and non-singular, B is n×m, Q is n×n
+// and symmetric, and R is m×m and symmetric positive definite. The
+// stabilizing solution is the unique symmetric solution for which A - B * K,
+// with the optimal feedback gain K = (R + Bᵀ * X * B)⁻¹ * Bᵀ * X * A, is
+// stable, having all its eigenvalues in the open unit disc.
+//
+// SolveDARE uses the Schur method, computing X from a basis for the stable
+// invariant subspace of the symplectic matrix
+//  [ A + G * A⁻ᵀ * Q   -G * A⁻ᵀ ]
+//  [ -A⁻ᵀ * Q           A⁻ᵀ     ],
+// where G = B * R⁻¹ * Bᵀ.
+//
+// If R is not positive definite, SolveDARE returns ErrNotPSD, and if A is
+// singular, ErrSingular is returned. If the equation has no stabilizing
+// solution, ErrNoStabilizing is returned. If the solution is ill-conditioned
+// a Condition error is returned with dst holding the computed solution.
+// SolveDARE will panic if the dimensions of the inputs do not match.
+func SolveDARE(dst *SymDense, a, b Matrix, q, r Symmetric) error {
+	n, g, err := riccatiInputs(a, b, q, r)
+	if err != nil {
+		return err
+	}
+
+	var ainv Dense
+	err = ainv.Inverse(a)
+	if c, ok := err.(Condition); ok && math.IsInf(float64(c), 1) {
+		return ErrSingular
+	}
+	ait := ainv.T()
+
+	var gait, aitq Dense
+	gait.Mul(g, ait)
+	aitq.Mul(ait, q)
+
+	z := NewDense(2*n, 2*n, nil)
+	z11 := z.slice(0, n, 0, n)
+	z11.Mul(&gait, q)
+	z11.Add(z11, a)
+	z.slice(0, n, n, 2*n).Scale(-1, &gait)
+	z.slice(n, 2*n, 0, n).Scale(-1, &aitq)
+	z.slice(n, 2*n, n, 2*n).Copy(ait)
+
+	return stableSubspaceSolution(dst, z, n, func(v complex128) bool {
+		return cmplx.Abs(v) < 1
+	})
+}
+
+// riccatiInputs checks the dimensions of the inputs of an algebraic Riccati
+// equation and returns the order n of the equation and G = B * R⁻¹ * Bᵀ.
+func riccatiInputs(a, b Matrix, q, r Symmetric) (n int, g *SymDense, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	br, m := b.Dims()
+	if br != n || q.SymmetricDim() != n || r.SymmetricDim() != m {
+		panic(ErrShape)
+	}
+
+	var chol Cholesky
+	if !chol.Factorize(r) {
+		return 0, nil, ErrNotPSD
+	}
+	// G = (B * U⁻¹) * (B * U⁻¹)ᵀ where R = Uᵀ * U.
+	bu := DenseCopyOf(b)
+	blas64.Trsm(blas.Right, blas.NoTrans, 1, chol.chol.mat, bu.mat)
+	g = NewSymDense(n, nil)
+	g.SymOuterK(1, bu)
+	return n, g, nil
+}
+
+// stableSubspaceSolution computes X = U₂ * U₁⁻¹ from the 2n×2n matrix m,
+// where the columns of [U₁; U₂] span the invariant subspace of m associated
+// with the n eigenvalues for which stable returns true, and places the
+// symmetric part of X into dst.
+func stableSubspaceSolution(dst *SymDense, m *Dense, n int, stable func(complex128) bool) error {
+	var schur Schur
+	if !schur.Factorize(m) {
+		return ErrFailedEigen
+	}
+	k, ok := schur.Reorder(stable)
+	if !ok {
+		return ErrFailedEigen
+	}
+	if k != n {
+		return ErrNoStabilizing
+	}
+	var z Dense
+	schur.ZTo(&z)
+	u1 := z.slice(0, n, 0, n)
+	u2 := z.slice(n, 2*n, 0, n)
+
+	// Solve X * U₁ = U₂ as U₁ᵀ * Xᵀ = U₂ᵀ.
+	var xt Dense
+	err := xt.Solve(u1.T(), u2.T())
+	if c, ok := err.(Condition); ok && math.IsInf(float64(c), 1) {
+		return ErrNoStabilizing
+	}
+
+	dst.reuseAsNonZeroed(n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, (xt.at(i, j)+xt.at(j, i))/2)
+		}
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSolveCARE(t *testing.T) {
+	t.Parallel()
+
+	// The double integrator with unit weights has a closed form solution.
+	var x SymDense
+	err := SolveCARE(&x,
+		NewDense(2, 2, []float64{0, 1, 0, 0}),
+		NewDense(2, 1, []float64{0, 1}),
+		NewSymDense(2, []float64{1, 0, 0, 1}),
+		NewSymDense(1, []float64{1}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s3 := math.Sqrt(3)
+	want := NewSymDense(2, []float64{s3, 1, 1, s3})
+	if !EqualApprox(&x, want, 1e-12) {
+		t.Errorf("unexpected solution:\ngot:\n%v\nwant:\n%v", Formatted(&x), Formatted(want))
+	}
+
+	src := rand.NewSource(1)
+	rnd := rand.New(src)
+	for _, test := range []struct{ n, m int }{
+		{1, 1}, {3, 1}, {5, 2}, {10, 3},
+	} {
+		a := randomDense(test.n, test.n, rnd)
+		b := randomDense(test.n, test.m, rnd)
+		q := RandomSPD(test.n, 10, src)
+		r := RandomSPD(test.m, 10, src)
+
+		var x SymDense
+		err := SolveCARE(&x, a, b, q, r)
+		if err != nil {
+			t.Fatalf("n=%d m=%d: unexpected error: %v", test.n, test.m, err)
+		}
+
+		// Check the residual Aᵀ X + X A - X G X + Q.
+		g := riccatiG(b, r)
+		var res, tmp Dense
+		res.Mul(a.T(), &x)
+		tmp.Mul(&x, a)
+		res.Add(&res, &tmp)
+		tmp.Product(&x, g, &x)
+		res.Sub(&res, &tmp)
+		res.Add(&res, q)
+		if norm := res.Norm(1); norm > 1e-10*x.Norm(1) {
+			t.Errorf("n=%d m=%d: unexpected residual norm: %v", test.n, test.m, norm)
+		}
+
+		// Check that A - G X is stable.
+		var cl Dense
+		cl.Mul(g, &x)
+		cl.Sub(a, &cl)
+		checkEigenvalues(t, &cl, func(v complex128) bool { return real(v) < 0 },
+			"n=%d m=%d: closed loop matrix not stable", test.n, test.m)
+	}
+
+	// An uncontrollable oscillator with no state weight has no
+	// stabilizing solution.
+	err = SolveCARE(&x,
+		NewDense(2, 2, []float64{0, 1, -1, 0}),
+		NewDense(2, 1, []float64{0, 0}),
+		NewSymDense(2, nil),
+		NewSymDense(1, []float64{1}),
+	)
+	if err != ErrNoStabilizing {
+		t.Errorf("unexpected error for system without stabilizing solution: %v", err)
+	}
+
+	err = SolveCARE(&x, eye(2), NewDense(2, 1, []float64{1, 1}), NewSymDense(2, nil), NewSymDense(1, []float64{-1}))
+	if err != ErrNotPSD {
+		t.Errorf("unexpected error for indefinite R: %v", err)
+	}
+}
+
+func TestSolveDARE(t *testing.T) {
+	t.Parallel()
+
+	// The scalar equation x = 4x - 4x²/(1+x) + 1 has the
+	// stabilizing solution 2+√5.
+	var x SymDense
+	err := SolveDARE(&x,
+		NewDense(1, 1, []float64{2}),
+		NewDense(1, 1, []float64{1}),
+		NewSymDense(1, []float64{1}),
+		NewSymDense(1, []float64{1}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 2 + math.Sqrt(5); math.Abs(x.At(0, 0)-want) > 1e-12 {
+		t.Errorf("unexpected solution: got %v, want %v", x.At(0, 0), want)
+	}
+
+	src := rand.NewSource(1)
+	rnd := rand.New(src)
+	for _, test := range []struct{ n, m int }{
+		{1, 1}, {3, 1}, {5, 2}, {10, 3},
+	} {
+		a := randomDense(test.n, test.n, rnd)
+		b := randomDense(test.n, test.m, rnd)
+		q := RandomSPD(test.n, 10, src)
+		r := RandomSPD(test.m, 10, src)
+
+		var x SymDense
+		err := SolveDARE(&x, a, b, q, r)
+		if err != nil {
+			t.Fatalf("n=%d m=%d: unexpected error: %v", test.n, test.m, err)
+		}
+
+		// Compute the gain K = (R + Bᵀ X B)⁻¹ Bᵀ X A.
+		var rbxb, bxa, k Dense
+		rbxb.Product(b.T(), &x, b)
+		rbxb.Add(&rbxb, r)
+		bxa.Product(b.T(), &x, a)
+		if err := k.Solve(&rbxb, &bxa); err != nil {
+			t.Fatalf("n=%d m=%d: unexpected error computing gain: %v", test.n, test.m, err)
+		}
+
+		// Check the residual Aᵀ X A - X - Aᵀ X B K + Q.
+		var res, tmp Dense
+		res.Product(a.T(), &x, a)
+		res.Sub(&res, &x)
+		tmp.Product(a.T(), &x, b, &k)
+		res.Sub(&res, &tmp)
+		res.Add(&res, q)
+		if norm := res.Norm(1); norm > 1e-9*x.Norm(1) {
+			t.Errorf("n=%d m=%d: unexpected residual norm: %v", test.n, test.m, norm)
+		}
+
+		// Check that A - B K is stable.
+		var cl Dense
+		cl.Mul(b, &k)
+		cl.Sub(a, &cl)
+		checkEigenvalues(t, &cl, func(v complex128) bool { return cmplx.Abs(v) < 1 },
+			"n=%d m=%d: closed loop matrix not stable", test.n, test.m)
+	}
+
+	err = SolveDARE(&x, NewDense(2, 2, nil), NewDense(2, 1, []float64{1, 1}), NewSymDense(2, nil), NewSymDense(1, []float64{1}))
+	if err != ErrSingular {
+		t.Errorf("unexpected error for singular A: %v", err)
+	}
+}
+
+// riccatiG returns B R⁻¹ Bᵀ.
+func riccatiG(b Matrix, r Symmetric) *Dense {
+	var rbt, g Dense
+	err := rbt.Solve(r, b.T())
+	if err != nil {
+		panic(err)
+	}
+	g.Mul(b, &rbt)
+	return &g
+}
+
+// checkEigenvalues checks that all eigenvalues of a satisfy ok.
+func checkEigenvalues(t *testing.T, a Matrix, ok func(complex128) bool, format string, args ...interface{}) {
+	t.Helper()
+	var ed Eigen
+	if !ed.Factorize(a, EigenNone) {
+		t.Fatal("unexpected eigendecomposition failure")
+	}
+	for _, v := range ed.Values(nil) {
+		if !ok(v) {
+			t.Errorf(format, args...)
+			return
+		}
+	}
+}
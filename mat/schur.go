@@ -0,0 +1,210 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/lapack"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+// Schur is a type for creating and using the real Schur decomposition of a
+// square matrix.
+type Schur struct {
+	n int
+	t *Dense
+	z *Dense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (s *Schur) succFact() bool {
+	return s.n != 0
+}
+
+// Factorize computes the real Schur decomposition of the square matrix A,
+//  A = Z * T * Zᵀ
+// where Z is an orthogonal matrix of Schur vectors and T is an upper
+// quasi-triangular matrix, the Schur form. T is block upper triangular with
+// 1×1 and 2×2 diagonal blocks. Each 1×1 block is a real eigenvalue of A and
+// each 2×2 block is in standard form, having equal diagonal elements and
+// off-diagonal elements of opposite sign, and corresponds to a complex
+// conjugate pair of eigenvalues.
+//
+// Factorize panics if the input matrix is not square.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (s *Schur) Factorize(a Matrix) (ok bool) {
+	// Kill the previous factorization.
+	s.n = 0
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+	n := r
+
+	t := DenseCopyOf(a)
+	tau := getFloat64s(n-1, false)
+	defer putFloat64s(tau)
+
+	// Reduce A to upper Hessenberg form, A = Q * H * Qᵀ.
+	work := []float64{0}
+	lapack64.Gehrd(t.mat, 0, n-1, tau, work, -1)
+	work = getFloat64s(int(work[0]), false)
+	lapack64.Gehrd(t.mat, 0, n-1, tau, work, len(work))
+	putFloat64s(work)
+
+	z := DenseCopyOf(t)
+	work = []float64{0}
+	lapack64.Orghr(z.mat, 0, n-1, tau, work, -1)
+	work = getFloat64s(int(work[0]), false)
+	lapack64.Orghr(z.mat, 0, n-1, tau, work, len(work))
+	putFloat64s(work)
+
+	// Clear the reflectors below the subdiagonal of H.
+	for i := 2; i < n; i++ {
+		zero(t.mat.Data[i*t.mat.Stride : i*t.mat.Stride+i-1])
+	}
+
+	wr := getFloat64s(n, false)
+	defer putFloat64s(wr)
+	wi := getFloat64s(n, false)
+	defer putFloat64s(wi)
+	work = []float64{0}
+	lapack64.Hseqr(lapack.EigenvaluesAndSchur, lapack.SchurOrig, t.mat, 0, n-1, wr, wi, z.mat, work, -1)
+	work = getFloat64s(int(work[0]), false)
+	unconverged := lapack64.Hseqr(lapack.EigenvaluesAndSchur, lapack.SchurOrig, t.mat, 0, n-1, wr, wi, z.mat, work, len(work))
+	putFloat64s(work)
+	if unconverged != 0 {
+		return false
+	}
+
+	s.n = n
+	s.t = t
+	s.z = z
+	return true
+}
+
+// TTo extracts the quasi-triangular Schur form T from a Schur decomposition,
+// placing the result into dst. If dst is empty, TTo will resize dst to be n×n.
+// When dst is non-empty, TTo will panic if dst is not n×n. TTo will also panic
+// if the receiver does not contain a successful factorization.
+func (s *Schur) TTo(dst *Dense) {
+	if !s.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(s.n, s.n)
+	dst.Copy(s.t)
+}
+
+// ZTo extracts the orthogonal matrix of Schur vectors Z from a Schur
+// decomposition, placing the result into dst. If dst is empty, ZTo will resize
+// dst to be n×n. When dst is non-empty, ZTo will panic if dst is not n×n. ZTo
+// will also panic if the receiver does not contain a successful factorization.
+func (s *Schur) ZTo(dst *Dense) {
+	if !s.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(s.n, s.n)
+	dst.Copy(s.z)
+}
+
+// Values extracts the eigenvalues of the factorized matrix in the order in
+// which they appear on the diagonal of T. Complex conjugate pairs appear
+// consecutively with the eigenvalue having the positive imaginary part first.
+// If dst is non-nil, the values are stored in-place into dst. In this case
+// dst must have length n, otherwise Values will panic. If dst is nil, then a
+// new slice will be allocated of the proper length and filled with the
+// eigenvalues.
+//
+// Values panics if the Schur decomposition was not successful.
+func (s *Schur) Values(dst []complex128) []complex128 {
+	if !s.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, s.n)
+	}
+	if len(dst) != s.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	t := s.t.mat
+	for i := 0; i < s.n; i++ {
+		re := t.Data[i*t.Stride+i]
+		if i == s.n-1 || t.Data[(i+1)*t.Stride+i] == 0 {
+			dst[i] = complex(re, 0)
+			continue
+		}
+		im := math.Sqrt(math.Abs(t.Data[i*t.Stride+i+1])) * math.Sqrt(math.Abs(t.Data[(i+1)*t.Stride+i]))
+		dst[i] = complex(re, im)
+		dst[i+1] = complex(re, -im)
+		i++
+	}
+	return dst
+}
+
+// Move reorders the Schur decomposition by an orthogonal similarity
+// transformation so that the diagonal block of T with row index from is moved
+// to row index to. The blocks in between are shifted accordingly and the
+// Schur vectors in Z are updated so that A = Z * T * Zᵀ still holds.
+//
+// If from points to the second row of a 2×2 block, the whole block is moved.
+// Move returns the row index of the first row of the moved block in its final
+// position, which may differ from to by one when 2×2 blocks are involved. If
+// ok is false, two adjacent blocks were too close to swap and T may have been
+// partially reordered.
+//
+// Move panics if the receiver does not contain a successful factorization or
+// if from or to are out of range.
+func (s *Schur) Move(from, to int) (pos int, ok bool) {
+	if !s.succFact() {
+		panic(badFact)
+	}
+	if uint(from) >= uint(s.n) || uint(to) >= uint(s.n) {
+		panic(ErrIndexOutOfRange)
+	}
+	work := getFloat64s(s.n, false)
+	defer putFloat64s(work)
+	_, pos, ok = lapack64.Trexc(lapack.UpdateSchur, s.t.mat, s.z.mat, from, to, work)
+	return pos, ok
+}
+
+// Reorder reorders the Schur decomposition so that the eigenvalues for which
+// sel returns true appear in the leading diagonal blocks of T, preserving the
+// relative order of the selected and of the unselected eigenvalues. For a
+// complex conjugate pair of eigenvalues, sel is called with the eigenvalue
+// having positive imaginary part and the pair is moved together.
+//
+// Reorder returns the number k of leading rows of T holding the selected
+// eigenvalues. The first k columns of Z then form an orthonormal basis for
+// the invariant subspace of A corresponding to the selected eigenvalues. If
+// ok is false, two adjacent blocks were too close to swap and T has been
+// partially reordered.
+//
+// Reorder panics if the receiver does not contain a successful factorization.
+func (s *Schur) Reorder(sel func(complex128) bool) (k int, ok bool) {
+	if !s.succFact() {
+		panic(badFact)
+	}
+	values := s.Values(nil)
+	t := s.t.mat
+	for i := 0; i < s.n; i++ {
+		size := 1
+		if i < s.n-1 && t.Data[(i+1)*t.Stride+i] != 0 {
+			size = 2
+		}
+		if sel(values[i]) {
+			if i != k {
+				if _, ok = s.Move(i, k); !ok {
+					return k, false
+				}
+			}
+			k += size
+		}
+		i += size - 1
+	}
+	return k, true
+}
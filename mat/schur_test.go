@@ -0,0 +1,121 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSchur(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 31} {
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+
+		var s Schur
+		if !s.Factorize(a) {
+			t.Fatalf("n=%d: unexpected factorization failure", n)
+		}
+		checkSchur(t, n, a, &s)
+
+		var eig Eigen
+		if !eig.Factorize(a, EigenNone) {
+			t.Fatalf("n=%d: unexpected eigen failure", n)
+		}
+		want := eig.Values(nil)
+		got := s.Values(nil)
+		sortComplex(want)
+		sortComplex(got)
+		for i := range want {
+			if cmplx.Abs(want[i]-got[i]) > 1e-10 {
+				t.Errorf("n=%d: eigenvalue mismatch: got %v, want %v", n, got[i], want[i])
+			}
+		}
+
+		// Move the eigenvalues with negative real part to the front.
+		k, ok := s.Reorder(func(v complex128) bool { return real(v) < 0 })
+		if !ok {
+			t.Errorf("n=%d: unexpected reorder failure", n)
+			continue
+		}
+		checkSchur(t, n, a, &s)
+		values := s.Values(nil)
+		var wantK int
+		for _, v := range values {
+			if real(v) < 0 {
+				wantK++
+			}
+		}
+		if k != wantK {
+			t.Errorf("n=%d: unexpected number of selected eigenvalues: got %d, want %d", n, k, wantK)
+		}
+		for i, v := range values {
+			if (i < k) != (real(v) < 0) {
+				t.Errorf("n=%d: eigenvalue %v at position %d not reordered", n, v, i)
+			}
+		}
+
+		// The leading k Schur vectors span an invariant subspace.
+		if k > 0 && k < n {
+			var z Dense
+			s.ZTo(&z)
+			zk := z.Slice(0, n, 0, k)
+			var az, p, r Dense
+			az.Mul(a, zk)
+			p.Mul(zk.T(), &az)
+			r.Mul(zk, &p)
+			r.Sub(&az, &r)
+			if Norm(&r, 2) > 1e-10 {
+				t.Errorf("n=%d: leading Schur vectors do not span an invariant subspace", n)
+			}
+		}
+	}
+}
+
+func checkSchur(t *testing.T, n int, a Matrix, s *Schur) {
+	t.Helper()
+	var tm, z Dense
+	s.TTo(&tm)
+	s.ZTo(&z)
+	if !isOrthonormal(&z, 1e-12) {
+		t.Errorf("n=%d: Z is not orthonormal", n)
+	}
+	for i := 2; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			if tm.At(i, j) != 0 {
+				t.Errorf("n=%d: T is not quasi-triangular", n)
+			}
+		}
+	}
+	for i := 0; i < n-2; i++ {
+		if tm.At(i+1, i) != 0 && tm.At(i+2, i+1) != 0 {
+			t.Errorf("n=%d: T has overlapping 2×2 blocks", n)
+		}
+	}
+	var got Dense
+	got.Product(&z, &tm, z.T())
+	if !EqualApprox(&got, a, 1e-12) {
+		t.Errorf("n=%d: Z*T*Zᵀ does not equal A", n)
+	}
+}
+
+func sortComplex(v []complex128) {
+	sort.Slice(v, func(i, j int) bool {
+		if math.Abs(real(v[i])-real(v[j])) > 1e-12 {
+			return real(v[i]) < real(v[j])
+		}
+		return imag(v[i]) < imag(v[j])
+	})
+}
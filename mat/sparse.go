@@ -0,0 +1,653 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+var (
+	coo *COO
+
+	_ Matrix      = coo
+	_ NonZeroDoer = coo
+
+	csr *CSR
+
+	_ Matrix         = csr
+	_ NonZeroDoer    = csr
+	_ RowNonZeroDoer = csr
+	_ Reseter        = csr
+
+	csc *CSC
+
+	_ Matrix         = csc
+	_ NonZeroDoer    = csc
+	_ ColNonZeroDoer = csc
+	_ Reseter        = csc
+)
+
+// COO is a sparse matrix in coordinate (triplet) format. It is intended
+// for incremental construction of sparse matrices that are then converted
+// to CSR or CSC format for computation. Duplicate entries are allowed and
+// are summed.
+type COO struct {
+	r, c int
+	rows []int
+	cols []int
+	data []float64
+}
+
+// NewCOO returns a new r×c COO matrix with the given triplets. The
+// slices rows, cols and data must have the same length and are used
+// as the backing storage of the returned matrix. The slices may be nil.
+// NewCOO will panic if any index is out of range.
+func NewCOO(r, c int, rows, cols []int, data []float64) *COO {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if len(rows) != len(data) || len(cols) != len(data) {
+		panic(ErrSliceLengthMismatch)
+	}
+	for k := range data {
+		if uint(rows[k]) >= uint(r) {
+			panic(ErrRowAccess)
+		}
+		if uint(cols[k]) >= uint(c) {
+			panic(ErrColAccess)
+		}
+	}
+	return &COO{r: r, c: c, rows: rows, cols: cols, data: data}
+}
+
+// Append adds the value v at row i, column j of the matrix. If an entry
+// already exists at (i, j), v is added to it.
+func (m *COO) Append(i, j int, v float64) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	m.rows = append(m.rows, i)
+	m.cols = append(m.cols, j)
+	m.data = append(m.data, v)
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *COO) Dims() (r, c int) { return m.r, m.c }
+
+// At returns the element at row i, column j. At performs a linear scan
+// of the stored triplets.
+func (m *COO) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	var v float64
+	for k, r := range m.rows {
+		if r == i && m.cols[k] == j {
+			v += m.data[k]
+		}
+	}
+	return v
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *COO) T() Matrix {
+	return Transpose{m}
+}
+
+// NNZ returns the number of stored triplets, including duplicates
+// and explicit zeros.
+func (m *COO) NNZ() int { return len(m.data) }
+
+// DoNonZero calls the function fn for each of the stored triplets of
+// the receiver. Duplicate entries are visited separately. The function
+// fn takes a row/column index and the element value of the receiver at
+// (i, j).
+func (m *COO) DoNonZero(fn func(i, j int, v float64)) {
+	for k, v := range m.data {
+		if v != 0 {
+			fn(m.rows[k], m.cols[k], v)
+		}
+	}
+}
+
+// ToCSR returns the receiver converted to CSR format with duplicate
+// entries summed.
+func (m *COO) ToCSR() *CSR {
+	indptr := make([]int, m.r+1)
+	for _, i := range m.rows {
+		indptr[i+1]++
+	}
+	for i := 0; i < m.r; i++ {
+		indptr[i+1] += indptr[i]
+	}
+	next := make([]int, m.r)
+	copy(next, indptr)
+	ind := make([]int, len(m.data))
+	data := make([]float64, len(m.data))
+	for k, i := range m.rows {
+		ind[next[i]] = m.cols[k]
+		data[next[i]] = m.data[k]
+		next[i]++
+	}
+	a := &CSR{r: m.r, c: m.c, indptr: indptr, ind: ind, data: data}
+	a.sumDuplicates()
+	return a
+}
+
+// ToCSC returns the receiver converted to CSC format with duplicate
+// entries summed.
+func (m *COO) ToCSC() *CSC {
+	t := COO{r: m.c, c: m.r, rows: m.cols, cols: m.rows, data: m.data}
+	return &CSC{t: *t.ToCSR()}
+}
+
+// CSR is a sparse matrix in compressed sparse row format. The column
+// indices of the non-zero elements of row i are held in ind[indptr[i]:indptr[i+1]]
+// in increasing order, with the corresponding values held in the same
+// positions of data.
+type CSR struct {
+	r, c   int
+	indptr []int
+	ind    []int
+	data   []float64
+}
+
+// NewCSR returns a new r×c CSR matrix using the provided compressed row
+// storage. The slice indptr must have length r+1 with indptr[0] == 0 and
+// be non-decreasing, ind and data must have length indptr[r], and the
+// column indices of each row must be strictly increasing. The slices are
+// used as the backing storage of the returned matrix. NewCSR will panic if
+// these conditions are not met.
+func NewCSR(r, c int, indptr, ind []int, data []float64) *CSR {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	checkCompressed(r, c, indptr, ind, data)
+	return &CSR{r: r, c: c, indptr: indptr, ind: ind, data: data}
+}
+
+// checkCompressed panics if the compressed storage described by n, m,
+// indptr, ind and data is not valid for n compressed rows or columns with
+// indices in [0, m).
+func checkCompressed(n, m int, indptr, ind []int, data []float64) {
+	if len(indptr) != n+1 || indptr[0] != 0 {
+		panic(ErrShape)
+	}
+	if len(ind) != indptr[n] || len(data) != indptr[n] {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := 0; i < n; i++ {
+		if indptr[i+1] < indptr[i] {
+			panic(ErrShape)
+		}
+		last := -1
+		for _, j := range ind[indptr[i]:indptr[i+1]] {
+			if j <= last || j >= m {
+				panic(ErrIndexOutOfRange)
+			}
+			last = j
+		}
+	}
+}
+
+// CSRCopyOf returns a newly allocated CSR copy of the non-zero elements of a.
+func CSRCopyOf(a Matrix) *CSR {
+	r, c := a.Dims()
+	var m COO
+	m.r, m.c = r, c
+	if nz, ok := a.(NonZeroDoer); ok {
+		nz.DoNonZero(m.Append)
+		return m.ToCSR()
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := a.At(i, j); v != 0 {
+				m.Append(i, j, v)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+// sumDuplicates sorts the indices of each row of the receiver and sums
+// duplicate entries.
+func (m *CSR) sumDuplicates() {
+	var n int
+	start := 0
+	for i := 0; i < m.r; i++ {
+		end := m.indptr[i+1]
+		row := compressedRow{ind: m.ind[start:end], data: m.data[start:end]}
+		sort.Sort(row)
+		first := n
+		for k := start; k < end; k++ {
+			if n > first && m.ind[n-1] == m.ind[k] {
+				m.data[n-1] += m.data[k]
+				continue
+			}
+			m.ind[n] = m.ind[k]
+			m.data[n] = m.data[k]
+			n++
+		}
+		start = end
+		m.indptr[i+1] = n
+	}
+	m.ind = m.ind[:n]
+	m.data = m.data[:n]
+}
+
+// compressedRow sorts the indices and values of a compressed row.
+type compressedRow struct {
+	ind  []int
+	data []float64
+}
+
+func (r compressedRow) Len() int           { return len(r.ind) }
+func (r compressedRow) Less(i, j int) bool { return r.ind[i] < r.ind[j] }
+func (r compressedRow) Swap(i, j int) {
+	r.ind[i], r.ind[j] = r.ind[j], r.ind[i]
+	r.data[i], r.data[j] = r.data[j], r.data[i]
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR) Dims() (r, c int) { return m.r, m.c }
+
+// At returns the element at row i, column j.
+func (m *CSR) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	ind := m.ind[m.indptr[i]:m.indptr[i+1]]
+	k := sort.SearchInts(ind, j)
+	if k < len(ind) && ind[k] == j {
+		return m.data[m.indptr[i]+k]
+	}
+	return 0
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *CSR) T() Matrix {
+	return Transpose{m}
+}
+
+// TCSC returns the transpose of the receiver as a CSC matrix sharing
+// the receiver's backing data.
+func (m *CSR) TCSC() *CSC {
+	return &CSC{t: *m}
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSR) NNZ() int { return len(m.data) }
+
+// RawCSR returns the compressed row storage of the receiver. Changes
+// to the values of the returned slices will be reflected in the receiver.
+func (m *CSR) RawCSR() (indptr, ind []int, data []float64) {
+	return m.indptr, m.ind, m.data
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSR) IsEmpty() bool {
+	return m.r == 0
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (m *CSR) Reset() {
+	m.r, m.c = 0, 0
+	m.indptr = m.indptr[:0]
+	m.ind = m.ind[:0]
+	m.data = m.data[:0]
+}
+
+// DoNonZero calls the function fn for each of the stored elements of
+// the receiver. The function fn takes a row/column index and the element
+// value of the receiver at (i, j).
+func (m *CSR) DoNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < m.r; i++ {
+		m.DoRowNonZero(i, fn)
+	}
+}
+
+// DoRowNonZero calls the function fn for each of the stored elements of
+// row i of the receiver. The function fn takes a row/column index and the
+// element value of the receiver at (i, j).
+func (m *CSR) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+		if m.data[k] != 0 {
+			fn(i, m.ind[k], m.data[k])
+		}
+	}
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (m *CSR) ToDense(dst *Dense) {
+	dst.reuseAsZeroed(m.r, m.c)
+	for i := 0; i < m.r; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+m.c]
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			row[m.ind[k]] = m.data[k]
+		}
+	}
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (m *CSR) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	r, c := m.r, m.c
+	if trans {
+		r, c = c, r
+	}
+	if x.Len() != c {
+		panic(ErrShape)
+	}
+	// Take a copy of x so that dst may alias it.
+	xv := getFloat64s(c, false)
+	defer putFloat64s(xv)
+	for j := range xv {
+		xv[j] = x.AtVec(j)
+	}
+
+	if trans {
+		dst.reuseAsZeroed(r)
+		for i := 0; i < m.r; i++ {
+			xi := xv[i]
+			if xi == 0 {
+				continue
+			}
+			for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+				dst.mat.Data[m.ind[k]*dst.mat.Inc] += m.data[k] * xi
+			}
+		}
+		return
+	}
+	dst.reuseAsNonZeroed(r)
+	for i := 0; i < m.r; i++ {
+		var sum float64
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			sum += m.data[k] * xv[m.ind[k]]
+		}
+		dst.mat.Data[i*dst.mat.Inc] = sum
+	}
+}
+
+// MulTo computes A⋅B or Aᵀ⋅B storing the result into dst, where A is the
+// receiver and B is a dense matrix.
+func (m *CSR) MulTo(dst *Dense, trans bool, b Matrix) {
+	r, c := m.r, m.c
+	if trans {
+		r, c = c, r
+	}
+	br, bc := b.Dims()
+	if br != c {
+		panic(ErrShape)
+	}
+	bd, ok := b.(*Dense)
+	if !ok || dst == bd {
+		bd = DenseCopyOf(b)
+	}
+	dst.reuseAsNonZeroed(r, bc)
+	if dst != bd {
+		dst.checkOverlap(bd.mat)
+	}
+	dst.Zero()
+
+	for i := 0; i < m.r; i++ {
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			j, v := m.ind[k], m.data[k]
+			var src, out []float64
+			if trans {
+				src = bd.mat.Data[i*bd.mat.Stride : i*bd.mat.Stride+bc]
+				out = dst.mat.Data[j*dst.mat.Stride : j*dst.mat.Stride+bc]
+			} else {
+				src = bd.mat.Data[j*bd.mat.Stride : j*bd.mat.Stride+bc]
+				out = dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+bc]
+			}
+			for l, s := range src {
+				out[l] += v * s
+			}
+		}
+	}
+}
+
+// Mul takes the sparse matrix product of a and b, placing the result in
+// the receiver. If the number of columns in a does not equal the number of
+// rows in b, Mul will panic.
+func (m *CSR) Mul(a, b *CSR) {
+	if a.c != b.r {
+		panic(ErrShape)
+	}
+	r, c := a.r, b.c
+
+	// Gustavson's algorithm using a dense accumulator.
+	indptr := make([]int, r+1)
+	var ind []int
+	var data []float64
+	acc := make([]float64, c)
+	mark := make([]int, c)
+	for j := range mark {
+		mark[j] = -1
+	}
+	for i := 0; i < r; i++ {
+		start := len(ind)
+		for ka := a.indptr[i]; ka < a.indptr[i+1]; ka++ {
+			l, av := a.ind[ka], a.data[ka]
+			for kb := b.indptr[l]; kb < b.indptr[l+1]; kb++ {
+				j := b.ind[kb]
+				if mark[j] != i {
+					mark[j] = i
+					acc[j] = 0
+					ind = append(ind, j)
+				}
+				acc[j] += av * b.data[kb]
+			}
+		}
+		sort.Ints(ind[start:])
+		for _, j := range ind[start:] {
+			data = append(data, acc[j])
+		}
+		indptr[i+1] = len(ind)
+	}
+	m.setCompressed(r, c, indptr, ind, data)
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add
+// will panic if the two matrices do not have the same shape.
+func (m *CSR) Add(a, b *CSR) {
+	if a.r != b.r || a.c != b.c {
+		panic(ErrShape)
+	}
+	r, c := a.r, a.c
+	indptr := make([]int, r+1)
+	ind := make([]int, 0, len(a.ind)+len(b.ind))
+	data := make([]float64, 0, len(a.ind)+len(b.ind))
+	for i := 0; i < r; i++ {
+		ka, kb := a.indptr[i], b.indptr[i]
+		for ka < a.indptr[i+1] || kb < b.indptr[i+1] {
+			switch {
+			case kb == b.indptr[i+1] || (ka < a.indptr[i+1] && a.ind[ka] < b.ind[kb]):
+				ind = append(ind, a.ind[ka])
+				data = append(data, a.data[ka])
+				ka++
+			case ka == a.indptr[i+1] || b.ind[kb] < a.ind[ka]:
+				ind = append(ind, b.ind[kb])
+				data = append(data, b.data[kb])
+				kb++
+			default:
+				ind = append(ind, a.ind[ka])
+				data = append(data, a.data[ka]+b.data[kb])
+				ka++
+				kb++
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+	m.setCompressed(r, c, indptr, ind, data)
+}
+
+// setCompressed sets the receiver to the r×c matrix with the given
+// compressed row storage. If the receiver is not empty, its dimensions
+// must match r and c.
+func (m *CSR) setCompressed(r, c int, indptr, ind []int, data []float64) {
+	if !m.IsEmpty() && (m.r != r || m.c != c) {
+		panic(ErrShape)
+	}
+	m.r, m.c = r, c
+	m.indptr, m.ind, m.data = indptr, ind, data
+}
+
+// CSC is a sparse matrix in compressed sparse column format. The row
+// indices of the non-zero elements of column j are held in ind[indptr[j]:indptr[j+1]]
+// in increasing order, with the corresponding values held in the same
+// positions of data.
+type CSC struct {
+	// t holds the transpose of the
+	// matrix in compressed row format.
+	t CSR
+}
+
+// NewCSC returns a new r×c CSC matrix using the provided compressed column
+// storage. The slice indptr must have length c+1 with indptr[0] == 0 and
+// be non-decreasing, ind and data must have length indptr[c], and the row
+// indices of each column must be strictly increasing. The slices are used
+// as the backing storage of the returned matrix. NewCSC will panic if
+// these conditions are not met.
+func NewCSC(r, c int, indptr, ind []int, data []float64) *CSC {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	checkCompressed(c, r, indptr, ind, data)
+	return &CSC{t: CSR{r: c, c: r, indptr: indptr, ind: ind, data: data}}
+}
+
+// CSCCopyOf returns a newly allocated CSC copy of the non-zero elements of a.
+func CSCCopyOf(a Matrix) *CSC {
+	return &CSC{t: *CSRCopyOf(a.T())}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSC) Dims() (r, c int) { return m.t.c, m.t.r }
+
+// At returns the element at row i, column j.
+func (m *CSC) At(i, j int) float64 {
+	if uint(i) >= uint(m.t.c) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.t.r) {
+		panic(ErrColAccess)
+	}
+	return m.t.At(j, i)
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *CSC) T() Matrix {
+	return Transpose{m}
+}
+
+// TCSR returns the transpose of the receiver as a CSR matrix sharing
+// the receiver's backing data.
+func (m *CSC) TCSR() *CSR {
+	t := m.t
+	return &t
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSC) NNZ() int { return m.t.NNZ() }
+
+// RawCSC returns the compressed column storage of the receiver. Changes
+// to the values of the returned slices will be reflected in the receiver.
+func (m *CSC) RawCSC() (indptr, ind []int, data []float64) {
+	return m.t.RawCSR()
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSC) IsEmpty() bool { return m.t.IsEmpty() }
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (m *CSC) Reset() { m.t.Reset() }
+
+// DoNonZero calls the function fn for each of the stored elements of
+// the receiver. The function fn takes a row/column index and the element
+// value of the receiver at (i, j).
+func (m *CSC) DoNonZero(fn func(i, j int, v float64)) {
+	for j := 0; j < m.t.r; j++ {
+		m.DoColNonZero(j, fn)
+	}
+}
+
+// DoColNonZero calls the function fn for each of the stored elements of
+// column j of the receiver. The function fn takes a row/column index and
+// the element value of the receiver at (i, j).
+func (m *CSC) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.t.r) {
+		panic(ErrColAccess)
+	}
+	m.t.DoRowNonZero(j, func(j, i int, v float64) { fn(i, j, v) })
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (m *CSC) ToDense(dst *Dense) {
+	r, c := m.Dims()
+	dst.reuseAsZeroed(r, c)
+	for j := 0; j < c; j++ {
+		for k := m.t.indptr[j]; k < m.t.indptr[j+1]; k++ {
+			dst.mat.Data[m.t.ind[k]*dst.mat.Stride+j] = m.t.data[k]
+		}
+	}
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (m *CSC) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	m.t.MulVecTo(dst, !trans, x)
+}
+
+// MulTo computes A⋅B or Aᵀ⋅B storing the result into dst, where A is the
+// receiver and B is a dense matrix.
+func (m *CSC) MulTo(dst *Dense, trans bool, b Matrix) {
+	m.t.MulTo(dst, !trans, b)
+}
+
+// Mul takes the sparse matrix product of a and b, placing the result in
+// the receiver. If the number of columns in a does not equal the number of
+// rows in b, Mul will panic.
+func (m *CSC) Mul(a, b *CSC) {
+	// (A⋅B)ᵀ = Bᵀ⋅Aᵀ
+	m.t.Mul(&b.t, &a.t)
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add
+// will panic if the two matrices do not have the same shape.
+func (m *CSC) Add(a, b *CSC) {
+	m.t.Add(&a.t, &b.t)
+}
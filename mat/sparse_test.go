@@ -0,0 +1,222 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randomSparse returns an r×c Dense with approximately density
+// non-zero elements.
+func randomSparse(r, c int, density float64, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if rnd.Float64() < density {
+				m.Set(i, j, rnd.NormFloat64())
+			}
+		}
+	}
+	return m
+}
+
+func TestCOO(t *testing.T) {
+	t.Parallel()
+	m := NewCOO(3, 4, nil, nil, nil)
+	m.Append(0, 1, 1)
+	m.Append(2, 3, 2)
+	m.Append(0, 1, 3)
+	m.Append(1, 0, -1)
+	want := NewDense(3, 4, []float64{
+		0, 4, 0, 0,
+		-1, 0, 0, 0,
+		0, 0, 0, 2,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected COO value:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	csr := m.ToCSR()
+	if !Equal(csr, want) {
+		t.Errorf("unexpected CSR value:\ngot:\n%v\nwant:\n%v", Formatted(csr), Formatted(want))
+	}
+	if csr.NNZ() != 3 {
+		t.Errorf("unexpected number of non-zeros: got:%d want:3", csr.NNZ())
+	}
+	csc := m.ToCSC()
+	if !Equal(csc, want) {
+		t.Errorf("unexpected CSC value:\ngot:\n%v\nwant:\n%v", Formatted(csc), Formatted(want))
+	}
+	indptr, ind, data := csc.RawCSC()
+	wantPtr, wantInd, wantData := []int{0, 1, 2, 2, 3}, []int{1, 0, 2}, []float64{-1, 4, 2}
+	if !equalInts(indptr, wantPtr) || !equalInts(ind, wantInd) || !Equal(NewVecDense(3, data), NewVecDense(3, wantData)) {
+		t.Errorf("unexpected CSC storage: got:%v %v %v want:%v %v %v", indptr, ind, data, wantPtr, wantInd, wantData)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSparseConversion(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []struct{ r, c int }{{1, 1}, {3, 5}, {8, 4}, {10, 10}} {
+		a := randomSparse(size.r, size.c, 0.3, rnd)
+
+		csr := CSRCopyOf(a)
+		if !Equal(csr, a) {
+			t.Errorf("unexpected CSR for %d×%d", size.r, size.c)
+		}
+		var d Dense
+		csr.ToDense(&d)
+		if !Equal(&d, a) {
+			t.Errorf("unexpected Dense from CSR for %d×%d", size.r, size.c)
+		}
+		if !Equal(csr.TCSC(), a.T()) {
+			t.Errorf("unexpected transpose of CSR for %d×%d", size.r, size.c)
+		}
+		if !Equal(CSRCopyOf(csr), a) {
+			t.Errorf("unexpected CSR copy of CSR for %d×%d", size.r, size.c)
+		}
+
+		csc := CSCCopyOf(a)
+		if !Equal(csc, a) {
+			t.Errorf("unexpected CSC for %d×%d", size.r, size.c)
+		}
+		d.Reset()
+		csc.ToDense(&d)
+		if !Equal(&d, a) {
+			t.Errorf("unexpected Dense from CSC for %d×%d", size.r, size.c)
+		}
+		if !Equal(csc.TCSR(), a.T()) {
+			t.Errorf("unexpected transpose of CSC for %d×%d", size.r, size.c)
+		}
+
+		var n int
+		csr.DoNonZero(func(i, j int, v float64) {
+			n++
+			if a.At(i, j) != v {
+				t.Errorf("unexpected CSR DoNonZero value at (%d,%d): got:%v want:%v", i, j, v, a.At(i, j))
+			}
+		})
+		if n != csr.NNZ() {
+			t.Errorf("unexpected number of CSR DoNonZero calls: got:%d want:%d", n, csr.NNZ())
+		}
+		n = 0
+		csc.DoNonZero(func(i, j int, v float64) {
+			n++
+			if a.At(i, j) != v {
+				t.Errorf("unexpected CSC DoNonZero value at (%d,%d): got:%v want:%v", i, j, v, a.At(i, j))
+			}
+		})
+		if n != csc.NNZ() {
+			t.Errorf("unexpected number of CSC DoNonZero calls: got:%d want:%d", n, csc.NNZ())
+		}
+	}
+}
+
+func TestCSRMulToOverlap(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := CSRCopyOf(randomSparse(3, 3, 0.5, rnd))
+	b := randomDense(4, 4, rnd)
+	orig := DenseCopyOf(b)
+	dst := b.Slice(1, 4, 1, 4).(*Dense)
+	if p, _ := panics(func() { a.MulTo(dst, false, b.Slice(0, 3, 0, 3)) }); !p {
+		t.Error("expected panic for overlapping MulTo")
+	}
+	if !Equal(b, orig) {
+		t.Error("input modified by overlapping MulTo")
+	}
+}
+
+func TestSparseMul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, k, n int }{{1, 1, 1}, {3, 4, 5}, {10, 7, 3}, {12, 12, 12}} {
+		a := randomSparse(test.m, test.k, 0.3, rnd)
+		b := randomSparse(test.k, test.n, 0.3, rnd)
+		var want Dense
+		want.Mul(a, b)
+
+		var csr CSR
+		csr.Mul(CSRCopyOf(a), CSRCopyOf(b))
+		if !EqualApprox(&csr, &want, 1e-14) {
+			t.Errorf("unexpected CSR Mul result for %d×%d×%d", test.m, test.k, test.n)
+		}
+		var csc CSC
+		csc.Mul(CSCCopyOf(a), CSCCopyOf(b))
+		if !EqualApprox(&csc, &want, 1e-14) {
+			t.Errorf("unexpected CSC Mul result for %d×%d×%d", test.m, test.k, test.n)
+		}
+
+		var got Dense
+		CSRCopyOf(a).MulTo(&got, false, b)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected CSR MulTo result for %d×%d×%d", test.m, test.k, test.n)
+		}
+		got.Reset()
+		CSRCopyOf(a.T()).MulTo(&got, true, b)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected transposed CSR MulTo result for %d×%d×%d", test.m, test.k, test.n)
+		}
+		got.Reset()
+		CSCCopyOf(a).MulTo(&got, false, b)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected CSC MulTo result for %d×%d×%d", test.m, test.k, test.n)
+		}
+
+		x := randomDense(test.k, 1, rnd).ColView(0)
+		var wantVec VecDense
+		wantVec.MulVec(a, x)
+		var gotVec VecDense
+		CSRCopyOf(a).MulVecTo(&gotVec, false, x)
+		if !EqualApprox(&gotVec, &wantVec, 1e-14) {
+			t.Errorf("unexpected CSR MulVecTo result for %d×%d", test.m, test.k)
+		}
+		gotVec.Reset()
+		CSRCopyOf(a.T()).MulVecTo(&gotVec, true, x)
+		if !EqualApprox(&gotVec, &wantVec, 1e-14) {
+			t.Errorf("unexpected transposed CSR MulVecTo result for %d×%d", test.m, test.k)
+		}
+		gotVec.Reset()
+		CSCCopyOf(a).MulVecTo(&gotVec, false, x)
+		if !EqualApprox(&gotVec, &wantVec, 1e-14) {
+			t.Errorf("unexpected CSC MulVecTo result for %d×%d", test.m, test.k)
+		}
+	}
+}
+
+func TestSparseAdd(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []struct{ r, c int }{{1, 1}, {3, 5}, {8, 4}, {10, 10}} {
+		a := randomSparse(size.r, size.c, 0.3, rnd)
+		b := randomSparse(size.r, size.c, 0.3, rnd)
+		var want Dense
+		want.Add(a, b)
+
+		var csr CSR
+		csr.Add(CSRCopyOf(a), CSRCopyOf(b))
+		if !EqualApprox(&csr, &want, 1e-14) {
+			t.Errorf("unexpected CSR Add result for %d×%d", size.r, size.c)
+		}
+		var csc CSC
+		csc.Add(CSCCopyOf(a), CSCCopyOf(b))
+		if !EqualApprox(&csc, &want, 1e-14) {
+			t.Errorf("unexpected CSC Add result for %d×%d", size.r, size.c)
+		}
+	}
+}
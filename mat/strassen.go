@@ -0,0 +1,198 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/internal/asm/f64"
+)
+
+// defaultStrassenCutoff is the dimension at or below which MulStrassen
+// uses conventional multiplication when no cutoff is specified.
+const defaultStrassenCutoff = 256
+
+// MulStrassen takes the matrix product of a and b, placing the result in the
+// receiver, using Strassen's recursive algorithm. If the number of columns in
+// a does not equal the number of rows in b, MulStrassen will panic.
+//
+// The recursion stops and the product is computed by blas64.Gemm once any
+// dimension of a sub-product is at most cutoff. If cutoff is less than one,
+// a default value is used. Strassen's algorithm performs O(n^2.81) rather
+// than O(n^3) floating point operations, but has weaker error bounds than
+// conventional multiplication and is only faster for very large matrices.
+func (m *Dense) MulStrassen(a, b Matrix, cutoff int) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if cutoff < 1 {
+		cutoff = defaultStrassenCutoff
+	}
+
+	aU, _ := untransposeExtract(a)
+	bU, _ := untransposeExtract(b)
+	m.reuseAsNonZeroed(ar, bc)
+
+	amat, aRestore := strassenOperand(a)
+	defer aRestore()
+	bmat, bRestore := strassenOperand(b)
+	defer bRestore()
+
+	if m == aU || m == bU {
+		w := getDenseWorkspace(ar, bc, false)
+		strassen(w.mat, amat, bmat, cutoff)
+		m.Copy(w)
+		putDenseWorkspace(w)
+		return
+	}
+	m.checkOverlap(amat)
+	m.checkOverlap(bmat)
+	strassen(m.mat, amat, bmat, cutoff)
+}
+
+// strassenOperand returns a non-transposed general matrix holding the
+// values of a and a function that releases any workspace allocated for it.
+func strassenOperand(a Matrix) (blas64.General, func()) {
+	aU, trans := untransposeExtract(a)
+	if rm, ok := aU.(*Dense); ok && !trans {
+		return rm.mat, func() {}
+	}
+	r, c := a.Dims()
+	w := getDenseWorkspace(r, c, false)
+	w.Copy(a)
+	return w.mat, func() { putDenseWorkspace(w) }
+}
+
+// strassen computes c = a * b using Strassen's algorithm for
+// sub-products with all dimensions greater than cutoff. Odd
+// dimensions are handled by peeling off the last row or column.
+func strassen(c, a, b blas64.General, cutoff int) {
+	m, k, n := a.Rows, a.Cols, b.Cols
+	if m <= cutoff || k <= cutoff || n <= cutoff {
+		blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, a, b, 0, c)
+		return
+	}
+
+	// Peel odd dimensions.
+	me, ke, ne := m&^1, k&^1, n&^1
+	strassenEven(subGeneral(c, 0, 0, me, ne), subGeneral(a, 0, 0, me, ke), subGeneral(b, 0, 0, ke, ne), cutoff)
+	if ke != k {
+		// C[:me, :ne] += A[:me, ke] * B[ke, :ne]
+		blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, subGeneral(a, 0, ke, me, 1), subGeneral(b, ke, 0, 1, ne), 1, subGeneral(c, 0, 0, me, ne))
+	}
+	if ne != n {
+		// C[:, ne] = A * B[:, ne]
+		blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, a, subGeneral(b, 0, ne, k, 1), 0, subGeneral(c, 0, ne, m, 1))
+	}
+	if me != m {
+		// C[me, :ne] = A[me, :] * B[:, :ne]
+		blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, subGeneral(a, me, 0, 1, k), subGeneral(b, 0, 0, k, ne), 0, subGeneral(c, me, 0, 1, ne))
+	}
+}
+
+// strassenEven computes c = a * b for matrices with even dimensions
+// using a single level of Strassen's recursion, calling strassen for
+// the seven half-size products.
+func strassenEven(c, a, b blas64.General, cutoff int) {
+	m, k, n := a.Rows/2, a.Cols/2, b.Cols/2
+
+	a11, a12 := subGeneral(a, 0, 0, m, k), subGeneral(a, 0, k, m, k)
+	a21, a22 := subGeneral(a, m, 0, m, k), subGeneral(a, m, k, m, k)
+	b11, b12 := subGeneral(b, 0, 0, k, n), subGeneral(b, 0, n, k, n)
+	b21, b22 := subGeneral(b, k, 0, k, n), subGeneral(b, k, n, k, n)
+	c11, c12 := subGeneral(c, 0, 0, m, n), subGeneral(c, 0, n, m, n)
+	c21, c22 := subGeneral(c, m, 0, m, n), subGeneral(c, m, n, m, n)
+
+	ta := getDenseWorkspace(m, k, false)
+	defer putDenseWorkspace(ta)
+	tb := getDenseWorkspace(k, n, false)
+	defer putDenseWorkspace(tb)
+	p := getDenseWorkspace(m, n, false)
+	defer putDenseWorkspace(p)
+
+	// M1 = (A11 + A22) * (B11 + B22)
+	// C11 = M1, C22 = M1
+	addGeneral(ta.mat, a11, 1, a22)
+	addGeneral(tb.mat, b11, 1, b22)
+	strassen(p.mat, ta.mat, tb.mat, cutoff)
+	copyGeneral(c11, p.mat)
+	copyGeneral(c22, p.mat)
+
+	// M2 = (A21 + A22) * B11
+	// C21 = M2, C22 -= M2
+	addGeneral(ta.mat, a21, 1, a22)
+	strassen(p.mat, ta.mat, b11, cutoff)
+	copyGeneral(c21, p.mat)
+	axpyGeneral(c22, -1, p.mat)
+
+	// M3 = A11 * (B12 - B22)
+	// C12 = M3, C22 += M3
+	addGeneral(tb.mat, b12, -1, b22)
+	strassen(p.mat, a11, tb.mat, cutoff)
+	copyGeneral(c12, p.mat)
+	axpyGeneral(c22, 1, p.mat)
+
+	// M4 = A22 * (B21 - B11)
+	// C11 += M4, C21 += M4
+	addGeneral(tb.mat, b21, -1, b11)
+	strassen(p.mat, a22, tb.mat, cutoff)
+	axpyGeneral(c11, 1, p.mat)
+	axpyGeneral(c21, 1, p.mat)
+
+	// M5 = (A11 + A12) * B22
+	// C11 -= M5, C12 += M5
+	addGeneral(ta.mat, a11, 1, a12)
+	strassen(p.mat, ta.mat, b22, cutoff)
+	axpyGeneral(c11, -1, p.mat)
+	axpyGeneral(c12, 1, p.mat)
+
+	// M6 = (A21 - A11) * (B11 + B12)
+	// C22 += M6
+	addGeneral(ta.mat, a21, -1, a11)
+	addGeneral(tb.mat, b11, 1, b12)
+	strassen(p.mat, ta.mat, tb.mat, cutoff)
+	axpyGeneral(c22, 1, p.mat)
+
+	// M7 = (A12 - A22) * (B21 + B22)
+	// C11 += M7
+	addGeneral(ta.mat, a12, -1, a22)
+	addGeneral(tb.mat, b21, 1, b22)
+	strassen(p.mat, ta.mat, tb.mat, cutoff)
+	axpyGeneral(c11, 1, p.mat)
+}
+
+// subGeneral returns the r×c sub-matrix of a starting at row i and column j.
+func subGeneral(a blas64.General, i, j, r, c int) blas64.General {
+	return blas64.General{
+		Rows:   r,
+		Cols:   c,
+		Stride: a.Stride,
+		Data:   a.Data[i*a.Stride+j : (i+r-1)*a.Stride+j+c],
+	}
+}
+
+// addGeneral computes dst = x + alpha * y.
+func addGeneral(dst, x blas64.General, alpha float64, y blas64.General) {
+	for i := 0; i < dst.Rows; i++ {
+		d := dst.Data[i*dst.Stride : i*dst.Stride+dst.Cols]
+		f64.AxpyUnitaryTo(d, alpha, y.Data[i*y.Stride:i*y.Stride+dst.Cols], x.Data[i*x.Stride:i*x.Stride+dst.Cols])
+	}
+}
+
+// axpyGeneral computes dst += alpha * x.
+func axpyGeneral(dst blas64.General, alpha float64, x blas64.General) {
+	for i := 0; i < dst.Rows; i++ {
+		f64.AxpyUnitary(alpha, x.Data[i*x.Stride:i*x.Stride+dst.Cols], dst.Data[i*dst.Stride:i*dst.Stride+dst.Cols])
+	}
+}
+
+// copyGeneral copies the elements of src into dst.
+func copyGeneral(dst, src blas64.General) {
+	for i := 0; i < dst.Rows; i++ {
+		copy(dst.Data[i*dst.Stride:i*dst.Stride+dst.Cols], src.Data[i*src.Stride:i*src.Stride+dst.Cols])
+	}
+}
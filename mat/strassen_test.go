@@ -0,0 +1,75 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestDenseMulStrassen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, k, n int
+		cutoff  int
+	}{
+		{1, 1, 1, 1},
+		{2, 2, 2, 1},
+		{4, 4, 4, 1},
+		{7, 5, 3, 1},
+		{16, 16, 16, 2},
+		{33, 17, 21, 4},
+		{64, 65, 63, 8},
+		{100, 100, 100, 0},
+	} {
+		for _, aTrans := range []bool{false, true} {
+			for _, bTrans := range []bool{false, true} {
+				var a, b Matrix
+				if aTrans {
+					a = randomDense(test.k, test.m, rnd).T()
+				} else {
+					a = randomDense(test.m, test.k, rnd)
+				}
+				if bTrans {
+					b = randomDense(test.n, test.k, rnd).T()
+				} else {
+					b = randomDense(test.k, test.n, rnd)
+				}
+
+				var want Dense
+				want.Mul(a, b)
+				var got Dense
+				got.MulStrassen(a, b, test.cutoff)
+				if !EqualApprox(&got, &want, 1e-12) {
+					t.Errorf("unexpected result for %d×%d×%d cutoff=%d aTrans=%t bTrans=%t",
+						test.m, test.k, test.n, test.cutoff, aTrans, bTrans)
+				}
+			}
+		}
+	}
+}
+
+func TestDenseMulStrassenAliased(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := randomDense(9, 9, rnd)
+	var want Dense
+	want.Mul(a, a.T())
+	a.MulStrassen(a, a.T(), 1)
+	if !EqualApprox(a, &want, 1e-12) {
+		t.Errorf("unexpected result for aliased MulStrassen:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(&want))
+	}
+}
+
+// randomDense returns an r×c Dense with normally distributed elements.
+func randomDense(r, c int, rnd *rand.Rand) *Dense {
+	d := make([]float64, r*c)
+	for i := range d {
+		d[i] = rnd.NormFloat64()
+	}
+	return NewDense(r, c, d)
+}
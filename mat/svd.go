@@ -5,6 +5,9 @@
 package mat
 
 import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/lapack"
 	"gonum.org/v1/gonum/lapack/lapack64"
@@ -411,3 +414,174 @@ func (svd *SVD) SolveVecTo(dst *VecDense, b Vector, rank int) float64 {
 	}
 	return res
 }
+
+// PseudoInverseTo computes the Moore–Penrose pseudo-inverse of the factorized
+// m×n matrix A, storing the n×m result into dst. Singular values less than or
+// equal to rcond scaled by the largest singular value are treated as zero.
+// A common choice for rcond is max(m,n) times machine epsilon.
+//
+// If dst is empty, PseudoInverseTo will resize dst to be n×m. When dst is
+// non-empty, PseudoInverseTo will panic if dst is not n×m. PseudoInverseTo
+// will panic if the receiver does not contain a successful factorization, if
+// either U or V was not computed during factorization, or if rcond is negative.
+func (svd *SVD) PseudoInverseTo(dst *Dense, rcond float64) {
+	rank := svd.Rank(rcond)
+	kind := svd.kind
+	if kind&SVDThinU == 0 && kind&SVDFullU == 0 {
+		panic("svd: u not computed during factorization")
+	}
+	if kind&SVDThinV == 0 && kind&SVDFullV == 0 {
+		panic("svd: v not computed during factorization")
+	}
+
+	m, n := svd.u.Rows, svd.vt.Cols
+	dst.reuseAsNonZeroed(n, m)
+	if rank == 0 {
+		dst.Zero()
+		return
+	}
+
+	u := Dense{
+		mat:     svd.u,
+		capRows: svd.u.Rows,
+		capCols: svd.u.Cols,
+	}
+	vt := getDenseWorkspace(rank, n, false)
+	defer putDenseWorkspace(vt)
+	vt.Copy(&Dense{
+		mat:     svd.vt,
+		capRows: svd.vt.Rows,
+		capCols: svd.vt.Cols,
+	})
+	// Scale the rows of Vᵀ by the reciprocal singular values
+	// so that A⁺ = V * Σ⁺ * Uᵀ.
+	for i, s := range svd.s[:rank] {
+		blas64.Scal(1/s, blas64.Vector{N: n, Inc: 1, Data: vt.mat.Data[i*vt.mat.Stride : i*vt.mat.Stride+n]})
+	}
+	dst.Mul(vt.T(), u.slice(0, m, 0, rank).T())
+}
+
+// FactorizeRandomized computes an approximate thin singular value decomposition
+// of the m×n matrix A holding the largest rank singular values and vectors,
+// using the randomized range finder of Halko, Martinsson and Tropp.
+// https://doi.org/10.1137/090771806
+//
+// A random Gaussian sketch of A with rank+oversample columns is computed and
+// refined by powerIter subspace iterations. Oversampling of 5 to 10 and one
+// or two power iterations are typical choices; power iterations improve the
+// accuracy when the singular values of A decay slowly. If src is nil, the
+// global random source is used.
+//
+// After a successful factorization the receiver holds a thin decomposition
+// with U of size m×rank and V of size n×rank, and its methods report values
+// for the rank-k approximation of A. FactorizeRandomized will panic if rank
+// is not positive or is greater than min(m,n), or if oversample or powerIter
+// is negative.
+//
+// FactorizeRandomized returns whether the decomposition succeeded.
+func (svd *SVD) FactorizeRandomized(a Matrix, rank, oversample, powerIter int, src rand.Source) (ok bool) {
+	m, n := a.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic("svd: rank out of range")
+	}
+	if oversample < 0 || powerIter < 0 {
+		panic("svd: negative randomized parameter")
+	}
+	// kill previous factorization
+	svd.s = svd.s[:0]
+	svd.kind = 0
+
+	l := min(rank+oversample, min(m, n))
+
+	normFloat64 := rand.NormFloat64
+	if src != nil {
+		normFloat64 = rand.New(src).NormFloat64
+	}
+	omega := getDenseWorkspace(n, l, false)
+	defer putDenseWorkspace(omega)
+	for i := range omega.mat.Data {
+		omega.mat.Data[i] = normFloat64()
+	}
+
+	// Find an orthonormal basis Q for the range of A.
+	q := getDenseWorkspace(m, l, false)
+	defer putDenseWorkspace(q)
+	q.Mul(a, omega)
+	orthonormalize(q, false)
+	for i := 0; i < powerIter; i++ {
+		omega.Mul(a.T(), q)
+		orthonormalize(omega, false)
+		q.Mul(a, omega)
+		orthonormalize(q, false)
+	}
+
+	// Compute the SVD of the small matrix B = Qᵀ * A.
+	b := getDenseWorkspace(l, n, false)
+	defer putDenseWorkspace(b)
+	b.Mul(q.T(), a)
+	var small SVD
+	ok = small.Factorize(b, SVDThin)
+	if !ok {
+		return false
+	}
+
+	svd.s = use(svd.s, rank)
+	copy(svd.s, small.s[:rank])
+	svd.u = blas64.General{
+		Rows:   m,
+		Cols:   rank,
+		Stride: rank,
+		Data:   use(svd.u.Data, m*rank),
+	}
+	u := Dense{mat: svd.u, capRows: m, capCols: rank}
+	su := Dense{mat: small.u, capRows: small.u.Rows, capCols: small.u.Cols}
+	u.Mul(q, su.slice(0, l, 0, rank))
+	svd.vt = blas64.General{
+		Rows:   rank,
+		Cols:   n,
+		Stride: n,
+		Data:   use(svd.vt.Data, rank*n),
+	}
+	vt := Dense{mat: svd.vt, capRows: rank, capCols: n}
+	vt.Copy(&Dense{mat: small.vt, capRows: small.vt.Rows, capCols: small.vt.Cols})
+	svd.kind = SVDThin
+	return true
+}
+
+// orthonormalize replaces the columns of the m×n matrix a, m ≥ n, with
+// an orthonormal basis for their span computed by a QR decomposition.
+// If positiveR is true, the signs of the columns of the basis are chosen
+// so that R has a non-negative diagonal, which makes the basis unique for
+// a of full rank.
+func orthonormalize(a *Dense, positiveR bool) {
+	m, n := a.Dims()
+	tau := getFloat64s(n, false)
+	defer putFloat64s(tau)
+	work := []float64{0}
+	lapack64.Geqrf(a.mat, tau, work, -1)
+	lwork := int(work[0])
+
+	q := getDenseWorkspace(m, n, true)
+	defer putDenseWorkspace(q)
+	for i := 0; i < n; i++ {
+		q.mat.Data[i*q.mat.Stride+i] = 1
+	}
+	lapack64.Ormqr(blas.Left, blas.NoTrans, a.mat, tau, q.mat, work, -1)
+	lwork = max(lwork, int(work[0]))
+
+	work = getFloat64s(lwork, false)
+	defer putFloat64s(work)
+	lapack64.Geqrf(a.mat, tau, work, lwork)
+	lapack64.Ormqr(blas.Left, blas.NoTrans, a.mat, tau, q.mat, work, lwork)
+	if positiveR {
+		for j := 0; j < n; j++ {
+			if a.at(j, j) >= 0 {
+				continue
+			}
+			for i := 0; i < m; i++ {
+				q.mat.Data[i*q.mat.Stride+j] *= -1
+			}
+		}
+	}
+	a.Copy(q)
+}
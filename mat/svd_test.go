@@ -661,3 +661,74 @@ func TestSVDSolveVecTo(t *testing.T) {
 		}
 	}
 }
+
+func TestSVDFactorizeRandomized(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+		k          int
+		oversample int
+		powerIter  int
+	}{
+		{m: 10, n: 10, rank: 3, k: 3, oversample: 5},
+		{m: 50, n: 20, rank: 5, k: 5, oversample: 5},
+		{m: 20, n: 50, rank: 5, k: 3, oversample: 10, powerIter: 2},
+		{m: 100, n: 30, rank: 8, k: 8, oversample: 0, powerIter: 1},
+		{m: 6, n: 4, rank: 4, k: 4, oversample: 10},
+	} {
+		// Construct a matrix with the specified rank.
+		var a Dense
+		a.Mul(randomDense(test.m, test.rank, rnd), randomDense(test.rank, test.n, rnd))
+
+		var want SVD
+		if !want.Factorize(&a, SVDThin) {
+			t.Fatalf("unexpected SVD failure")
+		}
+		wantValues := want.Values(nil)
+
+		var svd SVD
+		ok := svd.FactorizeRandomized(&a, test.k, test.oversample, test.powerIter, rand.NewSource(1))
+		if !ok {
+			t.Errorf("unexpected randomized SVD failure for %+v", test)
+			continue
+		}
+		values := svd.Values(nil)
+		if len(values) != test.k {
+			t.Errorf("unexpected number of singular values for %+v: got:%d want:%d", test, len(values), test.k)
+			continue
+		}
+		if !floats.EqualApprox(values, wantValues[:test.k], 1e-8) {
+			t.Errorf("unexpected singular values for %+v:\ngot: %v\nwant:%v", test, values, wantValues[:test.k])
+		}
+
+		var u, v Dense
+		svd.UTo(&u)
+		svd.VTo(&v)
+		if r, c := u.Dims(); r != test.m || c != test.k {
+			t.Errorf("unexpected U dimensions for %+v: got %d×%d", test, r, c)
+		}
+		if r, c := v.Dims(); r != test.n || c != test.k {
+			t.Errorf("unexpected V dimensions for %+v: got %d×%d", test, r, c)
+		}
+		var utu, vtv Dense
+		utu.Mul(u.T(), &u)
+		vtv.Mul(v.T(), &v)
+		eye := NewDiagDense(test.k, nil)
+		for i := 0; i < test.k; i++ {
+			eye.SetDiag(i, 1)
+		}
+		if !EqualApprox(&utu, eye, 1e-10) || !EqualApprox(&vtv, eye, 1e-10) {
+			t.Errorf("singular vectors not orthonormal for %+v", test)
+		}
+
+		if test.k == test.rank {
+			// The approximation is exact.
+			var got Dense
+			got.Product(&u, NewDiagDense(test.k, values), v.T())
+			if !EqualApprox(&got, &a, 1e-8) {
+				t.Errorf("unexpected reconstruction for %+v", test)
+			}
+		}
+	}
+}
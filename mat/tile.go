@@ -0,0 +1,188 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "errors"
+
+var (
+	_ TileReader = TileMatrix{}
+	_ TileWriter = TileMatrix{}
+	_ TileReader = (*MappedDense)(nil)
+	_ TileWriter = (*MappedDense)(nil)
+)
+
+var errTileReadOnly = errors.New("mat: tile destination is not mutable")
+
+const badTileSize = "mat: tile size must be positive"
+
+// TileReader is a matrix whose elements are read in rectangular tiles, for
+// example from a file or from a remote store.
+type TileReader interface {
+	// Dims returns the dimensions of the matrix.
+	Dims() (r, c int)
+
+	// ReadTile fills dst with the elements of the matrix
+	// starting at row i, column j. The extent of the tile
+	// is given by the dimensions of dst.
+	ReadTile(dst *Dense, i, j int) error
+}
+
+// TileWriter is a matrix whose elements are written in rectangular tiles.
+type TileWriter interface {
+	// Dims returns the dimensions of the matrix.
+	Dims() (r, c int)
+
+	// WriteTile writes the elements of src into the matrix
+	// starting at row i, column j.
+	WriteTile(i, j int, src Matrix) error
+}
+
+// TileMatrix adapts a Matrix to the TileReader interface and, if the Matrix
+// is Mutable, to the TileWriter interface.
+type TileMatrix struct {
+	Matrix Matrix
+}
+
+// Dims returns the dimensions of the matrix.
+func (t TileMatrix) Dims() (r, c int) {
+	return t.Matrix.Dims()
+}
+
+// ReadTile fills dst with the elements of the matrix starting at row i,
+// column j. ReadTile will panic if the tile is outside the matrix.
+func (t TileMatrix) ReadTile(dst *Dense, i, j int) error {
+	r, c := dst.Dims()
+	checkTile(t.Matrix, i, j, r, c)
+	switch m := t.Matrix.(type) {
+	case *Dense:
+		dst.Copy(m.slice(i, i+r, j, j+c))
+	case RawMatrixer:
+		var d Dense
+		d.SetRawMatrix(m.RawMatrix())
+		dst.Copy(d.slice(i, i+r, j, j+c))
+	default:
+		for ii := 0; ii < r; ii++ {
+			row := dst.RawRowView(ii)
+			for jj := range row {
+				row[jj] = m.At(i+ii, j+jj)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTile writes the elements of src into the matrix starting at row i,
+// column j. WriteTile will panic if the tile is outside the matrix, and
+// returns an error if the matrix is not Mutable.
+func (t TileMatrix) WriteTile(i, j int, src Matrix) error {
+	r, c := src.Dims()
+	checkTile(t.Matrix, i, j, r, c)
+	switch m := t.Matrix.(type) {
+	case *Dense:
+		m.slice(i, i+r, j, j+c).Copy(src)
+	case Mutable:
+		for ii := 0; ii < r; ii++ {
+			for jj := 0; jj < c; jj++ {
+				m.Set(i+ii, j+jj, src.At(ii, jj))
+			}
+		}
+	default:
+		return errTileReadOnly
+	}
+	return nil
+}
+
+// ReadTile fills dst with the elements of the matrix starting at row i,
+// column j. ReadTile will panic if the tile is outside the matrix.
+func (m *MappedDense) ReadTile(dst *Dense, i, j int) error {
+	if m.mat == nil {
+		return errMapClosed
+	}
+	r, c := dst.Dims()
+	checkTile(m, i, j, r, c)
+	dst.Copy(m.mat.slice(i, i+r, j, j+c))
+	return nil
+}
+
+// WriteTile writes the elements of src into the matrix starting at row i,
+// column j. WriteTile will panic if the tile is outside the matrix or if
+// the matrix is read-only.
+func (m *MappedDense) WriteTile(i, j int, src Matrix) error {
+	if m.mat == nil {
+		return errMapClosed
+	}
+	if !m.writable {
+		panic(badMapReadOnly)
+	}
+	r, c := src.Dims()
+	checkTile(m, i, j, r, c)
+	m.mat.slice(i, i+r, j, j+c).Copy(src)
+	return nil
+}
+
+// checkTile panics if the r×c tile at row i, column j is not within m.
+func checkTile(m Matrix, i, j, r, c int) {
+	mr, mc := m.Dims()
+	if i < 0 || j < 0 || r < 0 || c < 0 || mr < i+r || mc < j+c {
+		panic(ErrIndexOutOfRange)
+	}
+}
+
+// MulTiled computes the matrix product of a and b, writing the result into
+// dst one tile at a time, so that the operands and the result need not be
+// held in memory. Only a tile×tile block of the result and one tile of each
+// operand are held in memory at any time, with the product of the tiles
+// computed by Dense.Mul. Tiles at the edges of the matrices are truncated.
+//
+// Each row panel of a is read once for each column panel of b, so tile
+// should be chosen as large as memory allows. MulTiled returns the first
+// error returned by a read or write of a tile.
+//
+// MulTiled will panic if the inner dimensions of a and b do not match, if
+// dst is not the same shape as the product or if tile is not positive.
+func MulTiled(dst TileWriter, a, b TileReader, tile int) error {
+	if tile <= 0 {
+		panic(badTileSize)
+	}
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if dr, dc := dst.Dims(); dr != ar || dc != bc {
+		panic(ErrShape)
+	}
+
+	aTile := NewDense(tile, tile, nil)
+	bTile := NewDense(tile, tile, nil)
+	prod := NewDense(tile, tile, nil)
+	acc := NewDense(tile, tile, nil)
+	for i := 0; i < ar; i += tile {
+		ni := min(tile, ar-i)
+		for j := 0; j < bc; j += tile {
+			nj := min(tile, bc-j)
+			c := acc.slice(0, ni, 0, nj)
+			c.Zero()
+			for k := 0; k < ac; k += tile {
+				nk := min(tile, ac-k)
+				at := aTile.slice(0, ni, 0, nk)
+				if err := a.ReadTile(at, i, k); err != nil {
+					return err
+				}
+				bt := bTile.slice(0, nk, 0, nj)
+				if err := b.ReadTile(bt, k, j); err != nil {
+					return err
+				}
+				p := prod.slice(0, ni, 0, nj)
+				p.Mul(at, bt)
+				c.Add(c, p)
+			}
+			if err := dst.WriteTile(i, j, c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+type failingTileReader struct {
+	TileReader
+	err error
+}
+
+func (f failingTileReader) ReadTile(dst *Dense, i, j int) error { return f.err }
+
+func TestMulTiled(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, k, c int
+	}{
+		{1, 1, 1},
+		{5, 7, 3},
+		{16, 16, 16},
+		{33, 17, 25},
+	} {
+		a := randomDense(test.r, test.k, rnd)
+		b := randomDense(test.k, test.c, rnd)
+		var want Dense
+		want.Mul(a, b)
+		for _, tile := range []int{1, 3, 8, 64} {
+			got := NewDense(test.r, test.c, nil)
+			err := MulTiled(TileMatrix{got}, TileMatrix{a}, TileMatrix{b}, tile)
+			if err != nil {
+				t.Errorf("unexpected error for %d×%d×%d with tile %d: %v", test.r, test.k, test.c, tile, err)
+			}
+			if !EqualApprox(got, &want, 1e-12) {
+				t.Errorf("unexpected result for %d×%d×%d with tile %d", test.r, test.k, test.c, tile)
+			}
+
+			// Operands that are not Dense are read elementwise.
+			got.Zero()
+			err = MulTiled(TileMatrix{got}, TileMatrix{DenseCopyOf(a.T()).T()}, TileMatrix{DenseCopyOf(b.T()).T()}, tile)
+			if err != nil {
+				t.Errorf("unexpected error for Transpose operands: %v", err)
+			}
+			if !EqualApprox(got, &want, 1e-12) {
+				t.Errorf("unexpected result for Transpose operands %d×%d×%d with tile %d", test.r, test.k, test.c, tile)
+			}
+		}
+	}
+
+	a := randomDense(4, 4, rnd)
+	if err := MulTiled(TileMatrix{a.T()}, TileMatrix{a}, TileMatrix{a}, 2); err != errTileReadOnly {
+		t.Errorf("unexpected error for immutable destination: got %v, want %v", err, errTileReadOnly)
+	}
+	errRead := errors.New("read failed")
+	if err := MulTiled(TileMatrix{NewDense(4, 4, nil)}, TileMatrix{a}, failingTileReader{TileMatrix{a}, errRead}, 2); err != errRead {
+		t.Errorf("unexpected error for failing reader: got %v, want %v", err, errRead)
+	}
+	if p, _ := panics(func() { MulTiled(TileMatrix{a}, TileMatrix{a}, TileMatrix{NewDense(3, 4, nil)}, 2) }); !p {
+		t.Error("expected panic for mismatched dimensions")
+	}
+	if p, _ := panics(func() { MulTiled(TileMatrix{NewDense(5, 4, nil)}, TileMatrix{a}, TileMatrix{a}, 2) }); !p {
+		t.Error("expected panic for oversized destination")
+	}
+	if p, _ := panics(func() { MulTiled(TileMatrix{a}, TileMatrix{a}, TileMatrix{a}, 0) }); !p {
+		t.Error("expected panic for zero tile size")
+	}
+}
+
+func TestMulTiledMapped(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	dir := t.TempDir()
+	const r, k, c = 40, 30, 20
+
+	create := func(name string, r, c int) *MappedDense {
+		m, err := CreateMappedDense(filepath.Join(dir, name), r, c)
+		if err == errMapUnsupported {
+			t.Skip(err)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error creating mapped matrix: %v", err)
+		}
+		return m
+	}
+	a := create("a", r, k)
+	defer a.Close()
+	b := create("b", k, c)
+	defer b.Close()
+	dst := create("c", r, c)
+	defer dst.Close()
+
+	ad := randomDense(r, k, rnd)
+	bd := randomDense(k, c, rnd)
+	if err := a.WriteTile(0, 0, ad); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WriteTile(0, 0, bd); err != nil {
+		t.Fatal(err)
+	}
+	if err := MulTiled(dst, a, b, 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var want Dense
+	want.Mul(ad, bd)
+	if !EqualApprox(dst, &want, 1e-12) {
+		t.Error("unexpected result for mapped operands")
+	}
+}
@@ -0,0 +1,552 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/internal/fftpack"
+)
+
+var (
+	_ Matrix     = (*Circulant)(nil)
+	_ MulVecToer = (*Circulant)(nil)
+	_ Matrix     = (*Toeplitz)(nil)
+	_ MulVecToer = (*Toeplitz)(nil)
+	_ Matrix     = (*Hankel)(nil)
+	_ MulVecToer = (*Hankel)(nil)
+)
+
+const (
+	badToeplitz = "mat: Toeplitz column and row have different first elements"
+	badHankel   = "mat: Hankel column and row have different corner elements"
+)
+
+// Circulant represents an n×n circulant matrix, in which each column is the
+// previous column rotated down by one element. A circulant matrix is stored
+// as its first column c, so that
+//  C[i, j] = c[(i-j) mod n].
+// Products with a circulant matrix and solutions of circulant systems are
+// computed in O(n log n) time using the fast Fourier transform.
+type Circulant struct {
+	c []float64
+
+	// fft is the transform of length n and eig holds
+	// the Fourier coefficients of c, which are the
+	// eigenvalues of C.
+	fft *realFFT
+	eig []complex128
+}
+
+// NewCirculant returns a new circulant matrix with first column c. The
+// elements of c are copied. NewCirculant will panic if c is empty.
+func NewCirculant(c []float64) *Circulant {
+	if len(c) == 0 {
+		panic(ErrZeroLength)
+	}
+	fft := newRealFFT(len(c))
+	return &Circulant{
+		c:   append([]float64(nil), c...),
+		fft: fft,
+		eig: fft.coefficients(append([]float64(nil), c...)),
+	}
+}
+
+// Dims returns the dimensions of the matrix.
+func (m *Circulant) Dims() (r, c int) {
+	return len(m.c), len(m.c)
+}
+
+// At returns the element at row i, column j.
+func (m *Circulant) At(i, j int) float64 {
+	n := len(m.c)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	return m.c[(i-j+n)%n]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (m *Circulant) T() Matrix {
+	return Transpose{m}
+}
+
+// MulVecTo computes C⋅x or Cᵀ⋅x storing the result into dst.
+func (m *Circulant) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(m.c)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	coeff := m.fft.coefficients(vecFloats(x))
+	for k, l := range m.eig {
+		if trans {
+			// The transpose of a real circulant matrix has
+			// the conjugate eigenvalues.
+			l = cmplx.Conj(l)
+		}
+		coeff[k] *= l
+	}
+	m.setFromSequence(dst, coeff)
+}
+
+// SolveVecTo solves the circulant system C * x = b, storing the result into
+// dst. If C is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+func (m *Circulant) SolveVecTo(dst *VecDense, b Vector) error {
+	n := len(m.c)
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	// The eigenvalues of C are the Fourier coefficients of c, and
+	// C is normal, so its 2-norm condition number is the ratio of
+	// the largest and smallest eigenvalue magnitudes.
+	lmin, lmax := math.Inf(1), 0.0
+	for _, l := range m.eig {
+		a := cmplx.Abs(l)
+		lmin = math.Min(lmin, a)
+		lmax = math.Max(lmax, a)
+	}
+	if lmin == 0 {
+		return Condition(math.Inf(1))
+	}
+	coeff := m.fft.coefficients(vecFloats(b))
+	for k, l := range m.eig {
+		coeff[k] /= l
+	}
+	m.setFromSequence(dst, coeff)
+	if cond := lmax / lmin; cond > ConditionTolerance {
+		return Condition(cond)
+	}
+	return nil
+}
+
+// setFromSequence stores the normalized inverse transform of coeff
+// into dst.
+func (m *Circulant) setFromSequence(dst *VecDense, coeff []complex128) {
+	n := len(m.c)
+	seq := m.fft.sequence(coeff, n)
+	dst.reuseAsNonZeroed(n)
+	for i, v := range seq {
+		dst.setVec(i, v/float64(n))
+	}
+}
+
+// realFFT is a real fast Fourier transform of a fixed length. It is
+// a minimal counterpart of dsp/fourier.FFT, which mat cannot import.
+// The transform holds only the precomputed twiddle factors, with the
+// scratch space taken from the workspace pool on each call, so it is
+// safe for concurrent use.
+type realFFT struct {
+	work []float64
+	ifac [15]int
+}
+
+// scratch returns a work slice for the transform holding the twiddle
+// factors. The slice should be returned with putFloat64s.
+func (t *realFFT) scratch() []float64 {
+	n := len(t.work) / 2
+	work := getFloat64s(2*n, false)
+	copy(work[n:], t.work[n:])
+	return work
+}
+
+func newRealFFT(n int) *realFFT {
+	t := realFFT{work: make([]float64, 2*n)}
+	fftpack.Rffti(n, t.work, t.ifac[:])
+	return &t
+}
+
+// coefficients returns the n/2+1 unnormalized Fourier coefficients of
+// seq, overwriting seq.
+func (t *realFFT) coefficients(seq []float64) []complex128 {
+	n := len(seq)
+	work := t.scratch()
+	fftpack.Rfftf(n, seq, work, t.ifac[:])
+	putFloat64s(work)
+	coeff := make([]complex128, n/2+1)
+	coeff[0] = complex(seq[0], 0)
+	if n < 2 {
+		return coeff
+	}
+	if n%2 == 1 {
+		coeff[len(coeff)-1] = complex(seq[n-2], seq[n-1])
+	} else {
+		coeff[len(coeff)-1] = complex(seq[n-1], 0)
+	}
+	for i := 1; i < len(coeff)-1; i++ {
+		coeff[i] = complex(seq[2*i-1], seq[2*i])
+	}
+	return coeff
+}
+
+// sequence returns the unnormalized inverse transform of the n/2+1
+// Fourier coefficients in coeff as a sequence of length n.
+func (t *realFFT) sequence(coeff []complex128, n int) []float64 {
+	seq := make([]float64, n)
+	seq[0] = real(coeff[0])
+	if n < 2 {
+		return seq
+	}
+	nf := coeff[len(coeff)-1]
+	if n%2 == 1 {
+		seq[n-2] = real(nf)
+		seq[n-1] = imag(nf)
+	} else {
+		seq[n-1] = real(nf)
+	}
+	for i, cv := range coeff[1 : len(coeff)-1] {
+		seq[2*i+1] = real(cv)
+		seq[2*i+2] = imag(cv)
+	}
+	work := t.scratch()
+	fftpack.Rfftb(n, seq, work, t.ifac[:])
+	putFloat64s(work)
+	return seq
+}
+
+// Toeplitz represents an m×n Toeplitz matrix, in which each descending
+// diagonal is constant. A Toeplitz matrix is stored as its first column c
+// and its first row r, so that
+//  T[i, j] = c[i-j] if i >= j,
+//  T[i, j] = r[j-i] if i < j.
+// Products with a Toeplitz matrix are computed in O((m+n) log(m+n)) time by
+// embedding it in a circulant matrix.
+type Toeplitz struct {
+	c, r []float64
+
+	// circ is the circulant matrix of order m+n-1
+	// with T as its leading m×n block.
+	circ *Circulant
+}
+
+// NewToeplitz returns a new Toeplitz matrix with first column c and first
+// row r. The elements of c and r are copied. NewToeplitz will panic if c or r
+// is empty or if c[0] and r[0] are not equal.
+func NewToeplitz(c, r []float64) *Toeplitz {
+	if len(c) == 0 || len(r) == 0 {
+		panic(ErrZeroLength)
+	}
+	if c[0] != r[0] && !(math.IsNaN(c[0]) && math.IsNaN(r[0])) {
+		panic(badToeplitz)
+	}
+	// The first column of the embedding circulant matrix is c
+	// followed by the reverse of r without its first element.
+	col := make([]float64, len(c)+len(r)-1)
+	copy(col, c)
+	for j := 1; j < len(r); j++ {
+		col[len(col)-j] = r[j]
+	}
+	return &Toeplitz{
+		c:    append([]float64(nil), c...),
+		r:    append([]float64(nil), r...),
+		circ: NewCirculant(col),
+	}
+}
+
+// NewSymToeplitz returns a new symmetric n×n Toeplitz matrix with first
+// column and row c. The elements of c are copied. NewSymToeplitz will panic
+// if c is empty.
+func NewSymToeplitz(c []float64) *Toeplitz {
+	return NewToeplitz(c, c)
+}
+
+// Dims returns the dimensions of the matrix.
+func (t *Toeplitz) Dims() (r, c int) {
+	return len(t.c), len(t.r)
+}
+
+// At returns the element at row i, column j.
+func (t *Toeplitz) At(i, j int) float64 {
+	if uint(i) >= uint(len(t.c)) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(len(t.r)) {
+		panic(ErrColAccess)
+	}
+	if i >= j {
+		return t.c[i-j]
+	}
+	return t.r[j-i]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (t *Toeplitz) T() Matrix {
+	return Transpose{t}
+}
+
+// MulVecTo computes T⋅x or Tᵀ⋅x storing the result into dst.
+func (t *Toeplitz) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	m, n := len(t.c), len(t.r)
+	if trans {
+		m, n = n, m
+	}
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	// Pad x with zeros to the order of the circulant matrix and
+	// keep the leading elements of the product. The transpose of T
+	// is the leading block of the transpose of the circulant.
+	xp := NewVecDense(len(t.c)+len(t.r)-1, nil)
+	for i := 0; i < n; i++ {
+		xp.setVec(i, x.AtVec(i))
+	}
+	var y VecDense
+	t.circ.MulVecTo(&y, trans, xp)
+	dst.reuseAsNonZeroed(m)
+	dst.CopyVec(y.sliceVec(0, m))
+}
+
+// SolveVecTo solves the square Toeplitz system T * x = b using the Levinson
+// recursion in O(n²) time, storing the result into dst.
+//
+// The Levinson recursion solves the systems of the leading principal
+// submatrices of T in turn and is not numerically stable for general
+// matrices. It is stable for symmetric positive definite T. If a leading
+// principal submatrix of T is singular, SolveVecTo returns ErrSingular even
+// if T is non-singular.
+//
+// SolveVecTo will panic if T is not square.
+func (t *Toeplitz) SolveVecTo(dst *VecDense, b Vector) error {
+	n := len(t.c)
+	if len(t.r) != n {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	t0 := t.c[0]
+	if t0 == 0 {
+		return ErrSingular
+	}
+
+	// f and bw are the forward and backward vectors, which
+	// solve T_k f = e_1 and T_k bw = e_k for the leading k×k
+	// submatrix T_k, and x solves T_k x = b[:k].
+	f := make([]float64, n)
+	bw := make([]float64, n)
+	x := make([]float64, n)
+	f[0] = 1 / t0
+	bw[0] = 1 / t0
+	x[0] = b.AtVec(0) / t0
+	fNew := make([]float64, n)
+	for k := 1; k < n; k++ {
+		// The errors in the final row for f and x extended with a
+		// zero, and in the first row for bw extended with a
+		// leading zero.
+		var ef, eb, ex float64
+		for j := 0; j < k; j++ {
+			ef += t.c[k-j] * f[j]
+			ex += t.c[k-j] * x[j]
+			eb += t.r[j+1] * bw[j]
+		}
+		d := 1 - ef*eb
+		if d == 0 {
+			return ErrSingular
+		}
+		// f ← ([f; 0] - ef [0; bw]) / d
+		// bw ← ([0; bw] - eb [f; 0]) / d
+		for j := 0; j <= k; j++ {
+			var fj, bj float64
+			if j < k {
+				fj = f[j]
+			}
+			if j > 0 {
+				bj = bw[j-1]
+			}
+			fNew[j] = (fj - ef*bj) / d
+		}
+		for j := k; j >= 0; j-- {
+			var fj, bj float64
+			if j < k {
+				fj = f[j]
+			}
+			if j > 0 {
+				bj = bw[j-1]
+			}
+			bw[j] = (bj - eb*fj) / d
+		}
+		copy(f[:k+1], fNew[:k+1])
+
+		// x ← [x; 0] + (b_k - ex) bw
+		s := b.AtVec(k) - ex
+		for j := 0; j <= k; j++ {
+			x[j] += s * bw[j]
+		}
+	}
+	dst.reuseAsNonZeroed(n)
+	for i, v := range x {
+		dst.setVec(i, v)
+	}
+	return nil
+}
+
+// vecFloats returns the elements of v as a newly allocated slice.
+func vecFloats(v Vector) []float64 {
+	data := make([]float64, v.Len())
+	for i := range data {
+		data[i] = v.AtVec(i)
+	}
+	return data
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (m *Circulant) ToDense(dst *Dense) {
+	n := len(m.c)
+	dst.reuseAsNonZeroed(n, n)
+	for i := 0; i < n; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		copy(row[:i+1], m.c[:i+1])
+		reverse(row[:i+1])
+		copy(row[i+1:], m.c[i+1:])
+		reverse(row[i+1:])
+	}
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (t *Toeplitz) ToDense(dst *Dense) {
+	m, n := len(t.c), len(t.r)
+	dst.reuseAsNonZeroed(m, n)
+	for i := 0; i < m; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		for j := range row {
+			if i >= j {
+				row[j] = t.c[i-j]
+			} else {
+				row[j] = t.r[j-i]
+			}
+		}
+	}
+}
+
+// Hankel represents an m×n Hankel matrix, in which each ascending
+// anti-diagonal is constant. A Hankel matrix is stored as its first column c
+// and its last row r, so that
+//  H[i, j] = c[i+j] if i+j < m,
+//  H[i, j] = r[i+j-m+1] otherwise.
+// Hankel matrices arise as trajectory matrices in singular spectrum analysis
+// and in subspace system identification. Products with a Hankel matrix are
+// computed in O((m+n) log(m+n)) time using its relation to a Toeplitz matrix
+// with the columns in reverse order.
+type Hankel struct {
+	m, n int
+
+	// h holds the m+n-1 values of the
+	// anti-diagonals, so that H[i, j] = h[i+j].
+	h []float64
+
+	// toep is H with its columns reversed.
+	toep *Toeplitz
+}
+
+// NewHankel returns a new Hankel matrix with first column c and last row r.
+// The elements of c and r are copied. NewHankel will panic if c or r is
+// empty or if the last element of c and the first element of r are not
+// equal.
+func NewHankel(c, r []float64) *Hankel {
+	if len(c) == 0 || len(r) == 0 {
+		panic(ErrZeroLength)
+	}
+	last := c[len(c)-1]
+	if last != r[0] && !(math.IsNaN(last) && math.IsNaN(r[0])) {
+		panic(badHankel)
+	}
+	m, n := len(c), len(r)
+	h := make([]float64, m+n-1)
+	copy(h, c)
+	copy(h[m:], r[1:])
+
+	// Reversing the columns of H gives the Toeplitz matrix
+	// with T[i, k] = h[i+n-1-k].
+	tc := make([]float64, m)
+	copy(tc, h[n-1:])
+	tr := make([]float64, n)
+	copy(tr, h[:n])
+	reverse(tr)
+	return &Hankel{m: m, n: n, h: h, toep: NewToeplitz(tc, tr)}
+}
+
+// NewHankelFrom returns a new m×n Hankel matrix with H[i, j] = h[i+j], the
+// trajectory matrix of the series h with window length m. The elements of h
+// are copied. NewHankelFrom will panic if m is not in [1, len(h)].
+func NewHankelFrom(h []float64, m int) *Hankel {
+	if m <= 0 || m > len(h) {
+		panic(ErrIndexOutOfRange)
+	}
+	return NewHankel(h[:m], h[m-1:])
+}
+
+// Dims returns the dimensions of the matrix.
+func (h *Hankel) Dims() (r, c int) {
+	return h.m, h.n
+}
+
+// At returns the element at row i, column j.
+func (h *Hankel) At(i, j int) float64 {
+	if uint(i) >= uint(h.m) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(h.n) {
+		panic(ErrColAccess)
+	}
+	return h.h[i+j]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (h *Hankel) T() Matrix {
+	return Transpose{h}
+}
+
+// MulVecTo computes H⋅x or Hᵀ⋅x storing the result into dst.
+func (h *Hankel) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := h.n
+	if trans {
+		n = h.m
+	}
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	// H = T J where J reverses the order of the elements of a vector,
+	// so H x = T (J x) and Hᵀ x = J (Tᵀ x).
+	if trans {
+		var y VecDense
+		h.toep.MulVecTo(&y, true, x)
+		dst.reuseAsNonZeroed(h.n)
+		for i := 0; i < h.n; i++ {
+			dst.setVec(i, y.at(h.n-1-i))
+		}
+		return
+	}
+	xr := vecFloats(x)
+	reverse(xr)
+	h.toep.MulVecTo(dst, false, NewVecDense(n, xr))
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (h *Hankel) ToDense(dst *Dense) {
+	dst.reuseAsNonZeroed(h.m, h.n)
+	for i := 0; i < h.m; i++ {
+		copy(dst.mat.Data[i*dst.mat.Stride:i*dst.mat.Stride+h.n], h.h[i:])
+	}
+}
+
+// reverse reverses the order of the elements of s.
+func reverse(s []float64) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
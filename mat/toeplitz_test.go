@@ -0,0 +1,277 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCirculant(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 7, 8, 16} {
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+		// Make the matrix diagonally dominant so it is well-conditioned.
+		c[0] += float64(2 * n)
+		m := NewCirculant(c)
+		a := DenseCopyOf(m)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if a.At(i, j) != c[(i-j+n)%n] {
+					t.Fatalf("n=%d: unexpected element at (%d,%d)", n, i, j)
+				}
+			}
+		}
+
+		x := NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		for _, trans := range []bool{false, true} {
+			var got, want VecDense
+			m.MulVecTo(&got, trans, x)
+			if trans {
+				want.MulVec(a.T(), x)
+			} else {
+				want.MulVec(a, x)
+			}
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("n=%d trans=%t: unexpected product:\ngot: %v\nwant:%v", n, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+
+		var b, got VecDense
+		b.MulVec(a, x)
+		err := m.SolveVecTo(&got, &b)
+		if err != nil {
+			t.Fatalf("n=%d: unexpected error: %v", n, err)
+		}
+		if !EqualApprox(&got, x, 1e-12) {
+			t.Errorf("n=%d: unexpected solution:\ngot: %v\nwant:%v", n, got.RawVector().Data, x.RawVector().Data)
+		}
+	}
+
+	m := NewCirculant([]float64{1, 1, 1, 1})
+	var x VecDense
+	err := m.SolveVecTo(&x, NewVecDense(4, []float64{1, 2, 3, 4}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular circulant, got: %v", err)
+	}
+}
+
+func TestCirculantConcurrent(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 64
+	c := make([]float64, n)
+	for i := range c {
+		c[i] = rnd.NormFloat64()
+	}
+	m := NewCirculant(c)
+	a := DenseCopyOf(m)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make([]bool, workers)
+	for w := 0; w < workers; w++ {
+		x := NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		wg.Add(1)
+		go func(w int, x *VecDense) {
+			defer wg.Done()
+			var want VecDense
+			want.MulVec(a, x)
+			for k := 0; k < 100; k++ {
+				var got VecDense
+				m.MulVecTo(&got, false, x)
+				if !EqualApprox(&got, &want, 1e-12) {
+					errs[w] = true
+					return
+				}
+			}
+		}(w, x)
+	}
+	wg.Wait()
+	for w, bad := range errs {
+		if bad {
+			t.Errorf("unexpected product in concurrent worker %d", w)
+		}
+	}
+}
+
+func TestToeplitz(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {1, 4}, {4, 1}, {3, 5}, {5, 3}, {8, 8}, {13, 6},
+	} {
+		c := make([]float64, test.m)
+		r := make([]float64, test.n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+		for i := range r {
+			r[i] = rnd.NormFloat64()
+		}
+		r[0] = c[0]
+		tm := NewToeplitz(c, r)
+		a := DenseCopyOf(tm)
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < test.n; j++ {
+				var want float64
+				if i >= j {
+					want = c[i-j]
+				} else {
+					want = r[j-i]
+				}
+				if a.At(i, j) != want {
+					t.Fatalf("m=%d n=%d: unexpected element at (%d,%d)", test.m, test.n, i, j)
+				}
+			}
+		}
+
+		for _, trans := range []bool{false, true} {
+			op := Matrix(a)
+			xlen := test.n
+			if trans {
+				op = a.T()
+				xlen = test.m
+			}
+			x := NewVecDense(xlen, nil)
+			for i := 0; i < xlen; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			var got, want VecDense
+			tm.MulVecTo(&got, trans, x)
+			want.MulVec(op, x)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("m=%d n=%d trans=%t: unexpected product:\ngot: %v\nwant:%v", test.m, test.n, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+
+	if p, _ := panics(func() { NewToeplitz([]float64{1, 2}, []float64{2, 1}) }); !p {
+		t.Error("expected panic for mismatched first elements")
+	}
+}
+
+func TestToeplitzSolveVecTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 32} {
+		for _, sym := range []bool{true, false} {
+			c := make([]float64, n)
+			r := make([]float64, n)
+			// An AR(1) autocorrelation sequence, which gives a
+			// symmetric positive definite matrix, perturbed in the
+			// non-symmetric case.
+			for i := range c {
+				c[i] = 1
+				for k := 0; k < i; k++ {
+					c[i] *= 0.6
+				}
+				r[i] = c[i]
+				if !sym && i > 0 {
+					r[i] += 0.1 * rnd.NormFloat64()
+				}
+			}
+			tm := NewToeplitz(c, r)
+			want := NewVecDense(n, nil)
+			for i := 0; i < n; i++ {
+				want.SetVec(i, rnd.NormFloat64())
+			}
+			var b VecDense
+			b.MulVec(tm, want)
+
+			var got VecDense
+			err := tm.SolveVecTo(&got, &b)
+			if err != nil {
+				t.Fatalf("n=%d sym=%t: unexpected error: %v", n, sym, err)
+			}
+			if !EqualApprox(&got, want, 1e-10) {
+				t.Errorf("n=%d sym=%t: unexpected solution:\ngot: %v\nwant:%v", n, sym, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+
+	// The leading 1×1 submatrix is singular.
+	tm := NewSymToeplitz([]float64{0, 1})
+	var x VecDense
+	if err := tm.SolveVecTo(&x, NewVecDense(2, []float64{1, 1})); err != ErrSingular {
+		t.Errorf("unexpected error for singular leading submatrix: %v", err)
+	}
+}
+
+func TestHankel(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {1, 4}, {4, 1}, {3, 5}, {5, 3}, {8, 8}, {13, 6},
+	} {
+		h := make([]float64, test.m+test.n-1)
+		for i := range h {
+			h[i] = rnd.NormFloat64()
+		}
+		hm := NewHankelFrom(h, test.m)
+		if r, c := hm.Dims(); r != test.m || c != test.n {
+			t.Fatalf("m=%d n=%d: unexpected dimensions %d×%d", test.m, test.n, r, c)
+		}
+		var a Dense
+		hm.ToDense(&a)
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < test.n; j++ {
+				if a.At(i, j) != h[i+j] || hm.At(i, j) != h[i+j] {
+					t.Fatalf("m=%d n=%d: unexpected element at (%d,%d)", test.m, test.n, i, j)
+				}
+			}
+		}
+
+		for _, trans := range []bool{false, true} {
+			op := Matrix(&a)
+			xlen := test.n
+			if trans {
+				op = a.T()
+				xlen = test.m
+			}
+			x := NewVecDense(xlen, nil)
+			for i := 0; i < xlen; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			var got, want VecDense
+			hm.MulVecTo(&got, trans, x)
+			want.MulVec(op, x)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("m=%d n=%d trans=%t: unexpected product:\ngot: %v\nwant:%v", test.m, test.n, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+
+	if p, _ := panics(func() { NewHankel([]float64{1, 2}, []float64{1, 2}) }); !p {
+		t.Error("expected panic for mismatched corner elements")
+	}
+}
+
+func TestStructuredToDense(t *testing.T) {
+	t.Parallel()
+	for _, m := range []Matrix{
+		NewCirculant([]float64{1, 2, 3, 4}),
+		NewToeplitz([]float64{1, 2, 3}, []float64{1, 4, 5, 6}),
+		NewHankel([]float64{1, 2, 3}, []float64{3, 4, 5, 6}),
+	} {
+		var got Dense
+		m.(interface{ ToDense(*Dense) }).ToDense(&got)
+		if !Equal(&got, DenseCopyOf(m)) {
+			t.Errorf("unexpected dense copy of %T:\ngot:\n%v\nwant:\n%v", m, Formatted(&got), Formatted(m))
+		}
+	}
+}
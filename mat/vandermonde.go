@@ -0,0 +1,178 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+var (
+	_ Matrix     = (*Vandermonde)(nil)
+	_ MulVecToer = (*Vandermonde)(nil)
+)
+
+// Vandermonde represents an m×n Vandermonde matrix with nodes x, having the
+// elements
+//  V[i, j] = x[i]^j.
+// The product V * c evaluates the polynomial with coefficients c, in order of
+// increasing degree, at the nodes, so that solving square Vandermonde systems
+// interpolates data by polynomials. Only the nodes are stored.
+type Vandermonde struct {
+	x []float64
+	n int
+}
+
+// NewVandermonde returns a new len(x)×n Vandermonde matrix with nodes x. The
+// elements of x are copied. NewVandermonde will panic if x is empty or n is
+// not positive.
+func NewVandermonde(x []float64, n int) *Vandermonde {
+	if len(x) == 0 || n == 0 {
+		panic(ErrZeroLength)
+	}
+	if n < 0 {
+		panic(ErrNegativeDimension)
+	}
+	return &Vandermonde{x: append([]float64(nil), x...), n: n}
+}
+
+// Dims returns the dimensions of the matrix.
+func (v *Vandermonde) Dims() (r, c int) {
+	return len(v.x), v.n
+}
+
+// At returns the element at row i, column j.
+func (v *Vandermonde) At(i, j int) float64 {
+	if uint(i) >= uint(len(v.x)) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(v.n) {
+		panic(ErrColAccess)
+	}
+	p := 1.0
+	for k := 0; k < j; k++ {
+		p *= v.x[i]
+	}
+	return p
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (v *Vandermonde) T() Matrix {
+	return Transpose{v}
+}
+
+// MulVecTo computes V⋅c or Vᵀ⋅c storing the result into dst. The product
+// V⋅c is computed by Horner's method in O(m*n) time.
+func (v *Vandermonde) MulVecTo(dst *VecDense, trans bool, c Vector) {
+	m, n := len(v.x), v.n
+	if trans {
+		m, n = n, m
+	}
+	if c.Len() != n {
+		panic(ErrShape)
+	}
+	cs := vecFloats(c)
+	dst.reuseAsNonZeroed(m)
+	if !trans {
+		for i, x := range v.x {
+			var p float64
+			for j := n - 1; j >= 0; j-- {
+				p = p*x + cs[j]
+			}
+			dst.setVec(i, p)
+		}
+		return
+	}
+	// (Vᵀ c)[j] = Σ_i c[i] x[i]^j
+	pow := make([]float64, len(v.x))
+	for i := range pow {
+		pow[i] = 1
+	}
+	for j := 0; j < m; j++ {
+		var s float64
+		for i, p := range pow {
+			s += cs[i] * p
+			pow[i] = p * v.x[i]
+		}
+		dst.setVec(j, s)
+	}
+}
+
+// ToDense copies the elements of the receiver into dst. If dst is empty
+// it is resized to the dimensions of the receiver, otherwise ToDense will
+// panic if the dimensions do not match.
+func (v *Vandermonde) ToDense(dst *Dense) {
+	dst.reuseAsNonZeroed(len(v.x), v.n)
+	for i, x := range v.x {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+v.n]
+		p := 1.0
+		for j := range row {
+			row[j] = p
+			p *= x
+		}
+	}
+}
+
+// SolveVecTo solves the square Vandermonde system V * c = b, or Vᵀ * c = b if
+// trans is true, storing the result into dst. The solution of V * c = b holds
+// the coefficients, in order of increasing degree, of the polynomial
+// interpolating the values b at the nodes, and the solution of Vᵀ * c = b
+// holds weights of the nodes, for example of a quadrature rule exact for
+// polynomials with moments b.
+//
+// SolveVecTo uses the Björck–Pereyra algorithm, which takes O(n²) time and
+// often computes solutions far more accurately than the condition number of
+// V would suggest, in particular for monotonically ordered non-negative nodes.
+// If two nodes are equal, V is singular and ErrSingular is returned.
+// SolveVecTo will panic if V is not square.
+func (v *Vandermonde) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := v.n
+	if len(v.x) != n {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	x := v.x
+	for i := range x {
+		for j := 0; j < i; j++ {
+			if x[i] == x[j] {
+				return ErrSingular
+			}
+		}
+	}
+	f := vecFloats(b)
+	if !trans {
+		// Compute the Newton divided differences and convert
+		// the Newton form to monomial coefficients.
+		for k := 0; k < n-1; k++ {
+			for i := n - 1; i > k; i-- {
+				f[i] = (f[i] - f[i-1]) / (x[i] - x[i-k-1])
+			}
+		}
+		for k := n - 2; k >= 0; k-- {
+			for i := k; i < n-1; i++ {
+				f[i] -= f[i+1] * x[k]
+			}
+		}
+	} else {
+		// Apply the transposes of the same factors in
+		// reverse order.
+		for k := 0; k < n-1; k++ {
+			for i := n - 1; i > k; i-- {
+				f[i] -= x[k] * f[i-1]
+			}
+		}
+		for k := n - 2; k >= 0; k-- {
+			for i := k + 1; i < n; i++ {
+				f[i] /= x[i] - x[i-k-1]
+			}
+			for i := k; i < n-1; i++ {
+				f[i] -= f[i+1]
+			}
+		}
+	}
+	dst.reuseAsNonZeroed(n)
+	for i, fi := range f {
+		dst.setVec(i, fi)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestVandermonde(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {3, 1}, {1, 3}, {4, 4}, {7, 3}, {3, 7},
+	} {
+		x := make([]float64, test.m)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+		}
+		v := NewVandermonde(x, test.n)
+		var a Dense
+		v.ToDense(&a)
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < test.n; j++ {
+				want := math.Pow(x[i], float64(j))
+				if math.Abs(v.At(i, j)-want) > 1e-14*math.Abs(want) || a.At(i, j) != v.At(i, j) {
+					t.Fatalf("m=%d n=%d: unexpected element at (%d,%d)", test.m, test.n, i, j)
+				}
+			}
+		}
+
+		for _, trans := range []bool{false, true} {
+			op := Matrix(&a)
+			clen := test.n
+			if trans {
+				op = a.T()
+				clen = test.m
+			}
+			c := NewVecDense(clen, nil)
+			for i := 0; i < clen; i++ {
+				c.SetVec(i, rnd.NormFloat64())
+			}
+			var got, want VecDense
+			v.MulVecTo(&got, trans, c)
+			want.MulVec(op, c)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("m=%d n=%d trans=%t: unexpected product", test.m, test.n, trans)
+			}
+		}
+	}
+}
+
+func TestVandermondeSolveVecTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 8} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = float64(i) + 0.5*rnd.Float64()
+		}
+		v := NewVandermonde(x, n)
+		for _, trans := range []bool{false, true} {
+			want := NewVecDense(n, nil)
+			for i := 0; i < n; i++ {
+				want.SetVec(i, rnd.NormFloat64())
+			}
+			var b VecDense
+			v.MulVecTo(&b, trans, want)
+
+			var got VecDense
+			if err := v.SolveVecTo(&got, trans, &b); err != nil {
+				t.Fatalf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			if !EqualApprox(&got, want, 1e-9) {
+				t.Errorf("n=%d trans=%t: unexpected solution:\ngot: %v\nwant:%v", n, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+
+	// Interpolation at ordered equispaced nodes recovers
+	// a polynomial fitting the data to high accuracy even though V is
+	// ill-conditioned.
+	const n = 15
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i) / (n - 1)
+	}
+	v := NewVandermonde(x, n)
+	coef := NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		coef.SetVec(i, float64(i%3-1))
+	}
+	var b, got VecDense
+	v.MulVecTo(&b, false, coef)
+	if err := v.SolveVecTo(&got, false, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fit VecDense
+	v.MulVecTo(&fit, false, &got)
+	if !EqualApprox(&fit, &b, 1e-12) {
+		t.Error("interpolating polynomial does not fit the data")
+	}
+
+	v = NewVandermonde([]float64{1, 2, 1}, 3)
+	if err := v.SolveVecTo(&got, false, NewVecDense(3, []float64{1, 2, 3})); err != ErrSingular {
+		t.Errorf("unexpected error for repeated nodes: %v", err)
+	}
+}
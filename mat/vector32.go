@@ -0,0 +1,276 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+var (
+	vector32 *VecDense32
+
+	_ Matrix        = vector32
+	_ Vector        = vector32
+	_ Reseter       = vector32
+	_ MutableVector = vector32
+)
+
+// VecDense32 represents a column vector with single precision storage.
+//
+// VecDense32 implements the Vector interface so that it can be used with
+// the float64 API of the package. Values returned by AtVec are exactly
+// representable float64 values, and values passed to SetVec are rounded
+// to the nearest float32.
+type VecDense32 struct {
+	mat blas32.Vector
+	// VecDense32 must have positive increment in this package.
+}
+
+// NewVecDense32 creates a new VecDense32 of length n. If data == nil,
+// a new slice is allocated for the backing slice. If len(data) == n, data is
+// used as the backing slice, and changes to the elements of the returned
+// VecDense32 will be reflected in data. If neither of these is true,
+// NewVecDense32 will panic. NewVecDense32 will panic if n is zero.
+func NewVecDense32(n int, data []float32) *VecDense32 {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if len(data) != n && data != nil {
+		panic(ErrShape)
+	}
+	if data == nil {
+		data = make([]float32, n)
+	}
+	return &VecDense32{
+		mat: blas32.Vector{
+			N:    n,
+			Inc:  1,
+			Data: data,
+		},
+	}
+}
+
+// reuseAsNonZeroed resizes an empty vector to a r×1 vector,
+// or checks that a non-empty matrix is r×1. It does not zero
+// the data in the receiver.
+func (v *VecDense32) reuseAsNonZeroed(r int) {
+	if r == 0 {
+		panic(ErrZeroLength)
+	}
+	if v.IsEmpty() {
+		v.mat = blas32.Vector{
+			N:    r,
+			Inc:  1,
+			Data: use32(v.mat.Data, r),
+		}
+		return
+	}
+	if r != v.mat.N {
+		panic(ErrShape)
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix. Columns is always 1
+// for a non-Reset vector.
+func (v *VecDense32) Dims() (r, c int) {
+	if v.IsEmpty() {
+		return 0, 0
+	}
+	return v.mat.N, 1
+}
+
+// Len returns the length of the vector.
+func (v *VecDense32) Len() int {
+	return v.mat.N
+}
+
+// At returns the element at row i. It panics if i is out of bounds or if j
+// is not zero.
+func (v *VecDense32) At(i, j int) float64 {
+	if j != 0 {
+		panic(ErrColAccess)
+	}
+	return v.AtVec(i)
+}
+
+// AtVec returns the element at row i. It panics if i is out of bounds.
+func (v *VecDense32) AtVec(i int) float64 {
+	if uint(i) >= uint(v.mat.N) {
+		panic(ErrRowAccess)
+	}
+	return float64(v.mat.Data[i*v.mat.Inc])
+}
+
+// SetVec sets the element at row i to the value val rounded to the
+// nearest float32. It panics if i is out of bounds.
+func (v *VecDense32) SetVec(i int, val float64) {
+	if uint(i) >= uint(v.mat.N) {
+		panic(ErrVectorAccess)
+	}
+	v.mat.Data[i*v.mat.Inc] = float32(val)
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (v *VecDense32) T() Matrix {
+	return Transpose{v}
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (v *VecDense32) IsEmpty() bool {
+	// It must be the case that v.Dims() returns
+	// zeros in this case. See comment in Reset().
+	return v.mat.Inc == 0
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// Reset should not be used when the matrix shares backing data.
+// See the Reseter interface for more information.
+func (v *VecDense32) Reset() {
+	// No change of Inc or N to 0 may be
+	// made unless both are set to 0.
+	v.mat.Inc = 0
+	v.mat.N = 0
+	v.mat.Data = v.mat.Data[:0]
+}
+
+// Zero sets all of the matrix elements to zero.
+func (v *VecDense32) Zero() {
+	for i := 0; i < v.mat.N; i++ {
+		v.mat.Data[v.mat.Inc*i] = 0
+	}
+}
+
+// RawVector returns the underlying blas32.Vector used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in returned blas32.Vector.
+func (v *VecDense32) RawVector() blas32.Vector {
+	return v.mat
+}
+
+// SetRawVector sets the underlying blas32.Vector used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in the input.
+func (v *VecDense32) SetRawVector(a blas32.Vector) {
+	v.mat = a
+}
+
+// CopyVec makes a copy of elements of a into the receiver. It is similar to the
+// built-in copy; it copies as much as the overlap between the two vectors and
+// returns the number of elements it copied. The values are rounded to the
+// nearest float32.
+func (v *VecDense32) CopyVec(a Vector) int {
+	n := min(v.Len(), a.Len())
+	if v == a {
+		return n
+	}
+	if av, ok := a.(*VecDense32); ok {
+		src := av.mat
+		src.N = n
+		dst := v.mat
+		dst.N = n
+		blas32.Copy(src, dst)
+		return n
+	}
+	for i := 0; i < n; i++ {
+		v.mat.Data[i*v.mat.Inc] = float32(a.AtVec(i))
+	}
+	return n
+}
+
+// ScaleVec scales the vector a by alpha, placing the result in the receiver.
+func (v *VecDense32) ScaleVec(alpha float32, a Vector) {
+	n := a.Len()
+	v.reuseAsNonZeroed(n)
+	if v != a {
+		v.CopyVec(a)
+	}
+	blas32.Scal(alpha, v.mat)
+}
+
+// AddVec adds the vectors a and b, placing the result in the receiver.
+func (v *VecDense32) AddVec(a, b Vector) {
+	ar := a.Len()
+	br := b.Len()
+	if ar != br {
+		panic(ErrShape)
+	}
+	v.reuseAsNonZeroed(ar)
+
+	av, aok := a.(*VecDense32)
+	bv, bok := b.(*VecDense32)
+	if aok && bok {
+		if v != av {
+			v.CopyVec(av)
+		}
+		blas32.Axpy(1, bv.mat, v.mat)
+		return
+	}
+	for i := 0; i < ar; i++ {
+		v.mat.Data[i*v.mat.Inc] = float32(a.AtVec(i) + b.AtVec(i))
+	}
+}
+
+// MulVec computes a * b. The result is stored into the receiver.
+// MulVec panics if the number of columns in a does not equal the number
+// of rows in b or if the number of columns in b does not equal 1.
+//
+// The product is computed using single precision BLAS routines. Inputs that
+// are not backed by a Dense32 or VecDense32 are first rounded to single
+// precision.
+func (v *VecDense32) MulVec(a Matrix, b Vector) {
+	r, c := a.Dims()
+	br, bc := b.Dims()
+	if c != br || bc != 1 {
+		panic(ErrShape)
+	}
+
+	aU, trans := asDense32(a)
+	bv, ok := b.(*VecDense32)
+	if !ok {
+		bv = NewVecDense32(br, nil)
+		bv.CopyVec(b)
+	}
+
+	v.reuseAsNonZeroed(r)
+	dst := v
+	vmat := v.asDense32().mat
+	if offset32(aU.mat.Data[:1], vmat.Data[:1]) == 0 || offset32(bv.mat.Data[:1], vmat.Data[:1]) == 0 {
+		dst = NewVecDense32(r, nil)
+	} else {
+		checkOverlap32(vmat, aU.mat)
+		checkOverlap32(vmat, bv.asDense32().mat)
+	}
+	t := blas.NoTrans
+	if trans {
+		t = blas.Trans
+	}
+	blas32.Gemv(t, 1, aU.mat, bv.mat, 0, dst.mat)
+	if dst != v {
+		v.CopyVec(dst)
+	}
+}
+
+// asDense32 returns a Dense32 representation of the receiver with the
+// same underlying data.
+func (v *VecDense32) asDense32() *Dense32 {
+	return &Dense32{
+		mat: blas32.General{
+			Rows:   v.mat.N,
+			Cols:   1,
+			Stride: v.mat.Inc,
+			Data:   v.mat.Data,
+		},
+		capRows: v.mat.N,
+		capCols: 1,
+	}
+}
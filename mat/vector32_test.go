@@ -0,0 +1,89 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+func TestVecDense32MulVec(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c int
+	}{
+		{1, 1},
+		{3, 4},
+		{10, 7},
+	} {
+		for _, trans := range []bool{false, true} {
+			var (
+				a    Matrix
+				want Matrix
+			)
+			if trans {
+				a32, a64 := randDense32(rnd, test.c, test.r)
+				a, want = a32.T(), a64.T()
+			} else {
+				a32, a64 := randDense32(rnd, test.r, test.c)
+				a, want = a32, a64
+			}
+			b := NewVecDense32(test.c, nil)
+			for i := 0; i < test.c; i++ {
+				b.SetVec(i, float64(float32(rnd.NormFloat64())))
+			}
+
+			var got VecDense32
+			got.MulVec(a, b)
+			var w VecDense
+			w.MulVec(want, b)
+			if !EqualApprox(&got, &w, 1e-4) {
+				t.Errorf("unexpected result for %d×%d trans=%t:\ngot:\n%v\nwant:\n%v",
+					test.r, test.c, trans, Formatted(&got), Formatted(&w))
+			}
+		}
+	}
+}
+
+func TestVecDense32MulVecOverlap(t *testing.T) {
+	t.Parallel()
+	a := NewDense32(3, 4, nil)
+	b := NewVecDense32(5, []float32{1, 2, 3, 4, 5})
+	var v VecDense32
+	v.SetRawVector(blas32.Vector{N: 3, Inc: 1, Data: b.RawVector().Data[1:4]})
+	b.SetRawVector(blas32.Vector{N: 4, Inc: 1, Data: b.RawVector().Data[:4]})
+	_, msg := panics(func() { v.MulVec(a, b) })
+	if msg != regionOverlap {
+		t.Errorf("unexpected panic for overlapping MulVec: got:%v want:%v", msg, regionOverlap)
+	}
+}
+
+func TestVecDense32AddScale(t *testing.T) {
+	t.Parallel()
+	a := NewVecDense32(4, []float32{1, 2, 3, 4})
+	b := NewVecDense(4, []float64{0.5, 1.5, 2.5, 3.5})
+
+	var got VecDense32
+	got.AddVec(a, b)
+	want := NewVecDense(4, []float64{1.5, 3.5, 5.5, 7.5})
+	if !Equal(&got, want) {
+		t.Errorf("unexpected result for AddVec: got:%v want:%v", got.RawVector().Data, want.RawVector().Data)
+	}
+	got.AddVec(&got, a)
+	want = NewVecDense(4, []float64{2.5, 5.5, 8.5, 11.5})
+	if !Equal(&got, want) {
+		t.Errorf("unexpected result for aliased AddVec: got:%v want:%v", got.RawVector().Data, want.RawVector().Data)
+	}
+
+	got.ScaleVec(2, &got)
+	want = NewVecDense(4, []float64{5, 11, 17, 23})
+	if !Equal(&got, want) {
+		t.Errorf("unexpected result for ScaleVec: got:%v want:%v", got.RawVector().Data, want.RawVector().Data)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const defaultBasinHops = 100
+
+// BasinHopping is a global optimizer that performs a random walk on the local
+// minima of the objective function. At each hop the current minimum is
+// perturbed and a local minimization is run from the perturbed location. The
+// new local minimum is accepted as the current minimum with the Metropolis
+// probability
+//  min(1, exp(-(f(new) - f(current)) / Temperature)).
+//  Wales, D. J. and Doye, J. P. K. "Global optimization by basin-hopping and
+//  the lowest energy structures of Lennard-Jones clusters containing up to 110
+//  atoms." The Journal of Physical Chemistry A 101.28 (1997): 5111-5116.
+//
+// Basin hopping is effective for functions with many local minima separated
+// by barriers, where the local minimizations remove the barriers within each
+// basin from the search.
+type BasinHopping struct {
+	// Method is the local method used for the minimizations. If Method
+	// is nil, the default method of Minimize is used.
+	Method Method
+	// Settings are the settings of the local minimizations, and may be nil.
+	Settings *Settings
+
+	// Hops is the number of perturbations. If Hops is 0, a default value
+	// of 100 is used. Hops must not be negative.
+	Hops int
+	// Stall is the number of consecutive hops without improving on the
+	// best minimum after which the search stops. If Stall is 0 the
+	// criterion is not used. Stall must not be negative.
+	Stall int
+	// Temperature is the temperature of the acceptance test, which should
+	// be comparable to the difference in function value between adjacent
+	// local minima. If Temperature is 0, a default value of 1 is used.
+	// Temperature must not be negative.
+	Temperature float64
+	// Proposal is the distribution of the perturbations. Each element of
+	// the perturbed location is the corresponding element of the current
+	// minimum plus an independent draw from Proposal, and the perturbations
+	// should be large enough to leave the current basin. If Proposal is nil,
+	// a uniform distribution on [-1, 1] using Src is used.
+	Proposal distuv.Rander
+	// Src allows a random number generator to be supplied for the
+	// acceptance test. If Src is nil the generator in golang.org/x/exp/rand
+	// is used.
+	Src rand.Source
+}
+
+// Minimize searches for the global minimum of the problem starting from a
+// local minimization from initX. The returned Result holds the best minimum
+// found and the statistics summed over all of the local minimizations. The
+// status is MethodConverge if the search stopped because of the Stall
+// criterion and IterationLimit if all of the hops were made.
+//
+// A local minimization that returns an error along with a location, for
+// example because of a linesearch failure, is treated as having found that
+// location. Minimize returns the error of a local minimization only if no
+// result was returned, and otherwise returns a nil error.
+func (b BasinHopping) Minimize(p Problem, initX []float64) (*Result, error) {
+	startTime := time.Now()
+	hops := b.Hops
+	switch {
+	case hops == 0:
+		hops = defaultBasinHops
+	case hops < 0:
+		panic("basin hopping: negative number of hops")
+	}
+	if b.Stall < 0 {
+		panic("basin hopping: negative stall")
+	}
+	temp := b.Temperature
+	switch {
+	case temp == 0:
+		temp = 1
+	case temp < 0:
+		panic("basin hopping: negative temperature")
+	}
+	proposal := b.Proposal
+	if proposal == nil {
+		proposal = distuv.Uniform{Min: -1, Max: 1, Src: b.Src}
+	}
+	uniform := rand.Float64
+	if b.Src != nil {
+		uniform = rand.New(b.Src).Float64
+	}
+
+	var stats Stats
+	local := func(x []float64) (*Location, error) {
+		res, err := Minimize(p, x, b.Settings, b.Method)
+		if res == nil {
+			return nil, err
+		}
+		stats.MajorIterations += res.MajorIterations
+		stats.FuncEvaluations += res.FuncEvaluations
+		stats.GradEvaluations += res.GradEvaluations
+		stats.HessEvaluations += res.HessEvaluations
+		return &res.Location, nil
+	}
+
+	cur, err := local(initX)
+	if err != nil {
+		return nil, err
+	}
+	best := cur
+	status := IterationLimit
+	x := make([]float64, len(initX))
+	var stall int
+	for i := 0; i < hops; i++ {
+		for j, v := range cur.X {
+			x[j] = v + proposal.Rand()
+		}
+		loc, err := local(x)
+		if err != nil {
+			return nil, err
+		}
+		if loc.F <= cur.F || uniform() < math.Exp(-(loc.F-cur.F)/temp) {
+			cur = loc
+		}
+		if loc.F < best.F {
+			best = loc
+			stall = 0
+			continue
+		}
+		stall++
+		if stall == b.Stall {
+			status = MethodConverge
+			break
+		}
+	}
+	stats.Runtime = time.Since(startTime)
+	return &Result{
+		Location: *best,
+		Stats:    stats,
+		Status:   status,
+	}, nil
+}
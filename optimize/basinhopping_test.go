@@ -0,0 +1,81 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestBasinHopping(t *testing.T) {
+	t.Parallel()
+	rastrigin := Problem{
+		Func: functions.Rastrigin{}.Func,
+		Grad: func(grad, x []float64) {
+			for i, v := range x {
+				grad[i] = 2*v + 20*math.Pi*math.Sin(2*math.Pi*v)
+			}
+		},
+	}
+	for i, test := range []struct {
+		problem Problem
+		method  BasinHopping
+		// proposal is whether to use a normal proposal distribution.
+		proposal bool
+		initX    []float64
+		status   Status
+		want     []float64
+	}{
+		{
+			problem: rastrigin,
+			method:  BasinHopping{Method: &BFGS{}},
+			initX:   []float64{2.2, 2.2, 2.2},
+			status:  IterationLimit,
+			want:    []float64{0, 0, 0},
+		},
+		{
+			problem:  rastrigin,
+			method:   BasinHopping{Hops: 500, Stall: 50},
+			proposal: true,
+			initX:    []float64{-3.1, 2.2},
+			status:   MethodConverge,
+			want:     []float64{0, 0},
+		},
+		{
+			problem: Problem{
+				Func: functions.Rastrigin{}.Func,
+			},
+			method: BasinHopping{Temperature: 2},
+			initX:  []float64{1.2, -0.9},
+			status: IterationLimit,
+			want:   []float64{0, 0},
+		},
+	} {
+		test.method.Src = rand.NewSource(1)
+		if test.proposal {
+			test.method.Proposal = distuv.Normal{Mu: 0, Sigma: 1, Src: test.method.Src}
+		}
+		result, err := test.method.Minimize(test.problem, test.initX)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if result.Status != test.status {
+			t.Errorf("case %d: unexpected status: got:%v want:%v", i, result.Status, test.status)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-4) {
+			t.Errorf("case %d: global minimum not found: got:%v want:%v", i, result.X, test.want)
+		}
+		if result.FuncEvaluations == 0 || result.MajorIterations == 0 {
+			t.Errorf("case %d: statistics not collected: %+v", i, result.Stats)
+		}
+	}
+}
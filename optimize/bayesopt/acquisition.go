@@ -0,0 +1,60 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Acquisition is an acquisition function, which scores a candidate location
+// by the usefulness of evaluating the objective function there. The location
+// with the largest score is evaluated next.
+type Acquisition interface {
+	// Score returns the score of a candidate location where the surrogate
+	// model of the objective has the predictive mean and standard
+	// deviation given, and best is the lowest objective value observed.
+	Score(mean, std, best float64) float64
+}
+
+// ExpectedImprovement is the expected improvement acquisition function, the
+// expected amount by which the objective at the candidate is lower than the
+// best observed value,
+//  EI = E[max(0, best - ξ - f(x))].
+type ExpectedImprovement struct {
+	// Xi is the margin ξ of improvement, in units of the standard
+	// deviation of the observed values. Larger values favor exploration.
+	Xi float64
+}
+
+// Score returns the expected improvement at the candidate.
+func (e ExpectedImprovement) Score(mean, std, best float64) float64 {
+	imp := best - e.Xi - mean
+	if std <= 0 {
+		return math.Max(imp, 0)
+	}
+	z := imp / std
+	return imp*distuv.UnitNormal.CDF(z) + std*distuv.UnitNormal.Prob(z)
+}
+
+// UpperConfidenceBound is the upper confidence bound acquisition function
+// applied to the negated objective, so that the candidate with the lowest
+// optimistic estimate of the objective is preferred,
+//  UCB = -(mean - κ std).
+//  Srinivas, N., Krause, A., Kakade, S. M. and Seeger, M. "Gaussian process
+//  optimization in the bandit setting: No regret and experimental design."
+//  Proceedings of the 27th International Conference on Machine Learning
+//  (2010): 1015-1022.
+type UpperConfidenceBound struct {
+	// Kappa is the weight κ of the standard deviation. Larger values
+	// favor exploration.
+	Kappa float64
+}
+
+// Score returns the upper confidence bound of the negated objective.
+func (u UpperConfidenceBound) Score(mean, std, _ float64) float64 {
+	return -(mean - u.Kappa*std)
+}
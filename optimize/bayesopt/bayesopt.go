@@ -0,0 +1,250 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/gp"
+)
+
+const (
+	defaultEvaluations = 30
+	defaultCandidates  = 1000
+	defaultNoise       = 1e-6
+
+	// localScale is the standard deviation of the candidates drawn near
+	// the best observation, relative to the size of the domain.
+	localScale = 0.05
+)
+
+// ErrSurrogate is returned when the Gaussian process surrogate model cannot be
+// conditioned on the observations.
+var ErrSurrogate = errors.New("bayesopt: covariance of observations not positive definite")
+
+// Settings holds the settings of Minimize.
+type Settings struct {
+	// Lower and Upper are the bounds of the search domain. They must be
+	// finite, have the same length, and Lower must be less than Upper.
+	Lower, Upper []float64
+
+	// Evaluations is the total number of evaluations of the objective.
+	// If Evaluations is 0, a default value of 30 is used.
+	Evaluations int
+	// InitEvaluations is the number of evaluations at random locations
+	// before the surrogate model is used. If InitEvaluations is 0, a
+	// default value of max(5, 2*dim) is used, limited by Evaluations.
+	InitEvaluations int
+
+	// Kernel is the covariance function of the Gaussian process surrogate,
+	// which is modelled on the domain scaled to the unit hypercube. Its
+	// hyperparameters are fitted to the observations at every iteration,
+	// modifying Kernel. If Kernel is nil, a Matérn kernel with ν = 5/2 is
+	// used.
+	Kernel gp.Kernel
+	// Noise is the noise variance of the surrogate relative to the
+	// variance of the observed values. If Noise is 0, a default value of
+	// 1e-6 is used, which is suited to deterministic objectives.
+	Noise float64
+
+	// Acquisition is the acquisition function. If Acquisition is nil,
+	// ExpectedImprovement{} is used.
+	Acquisition Acquisition
+	// Candidates is the number of random candidate locations scored by
+	// the acquisition function at each iteration, half of them uniformly
+	// distributed and half near the best observation, before the best of
+	// them is refined by local optimization. If Candidates is 0, a default value
+	// of 1000 is used.
+	Candidates int
+
+	// Src allows a random number generator to be supplied for generating
+	// locations. If Src is nil the generator in golang.org/x/exp/rand is
+	// used.
+	Src rand.Source
+}
+
+// Result holds the result of Minimize.
+type Result struct {
+	// X is the location of the lowest observed objective value F.
+	X []float64
+	F float64
+
+	// Locations holds the evaluated locations in its rows, in order of
+	// evaluation, and Values holds the corresponding objective values.
+	Locations *mat.Dense
+	Values    []float64
+}
+
+// Minimize minimizes the expensive objective function f over the box given in
+// settings using Bayesian optimization. After evaluating f at random initial
+// locations, a Gaussian process surrogate model of f is fitted to the
+// observations at each iteration, and f is evaluated at the location that
+// maximizes the acquisition function of the model. The returned Result holds
+// the best observation and all of the evaluations.
+//
+// Minimize returns ErrSurrogate if the surrogate model cannot be fitted, along
+// with the evaluations made so far. Minimize panics if settings is nil or the
+// bounds are invalid.
+func Minimize(f func(x []float64) float64, settings *Settings) (*Result, error) {
+	if settings == nil {
+		panic("bayesopt: nil settings")
+	}
+	lower, upper := settings.Lower, settings.Upper
+	dim := len(lower)
+	if dim == 0 || len(upper) != dim {
+		panic("bayesopt: bound length mismatch")
+	}
+	for i, l := range lower {
+		if !(l < upper[i]) || math.IsInf(l, 0) || math.IsInf(upper[i], 0) {
+			panic("bayesopt: invalid bounds")
+		}
+	}
+	evals := settings.Evaluations
+	if evals == 0 {
+		evals = defaultEvaluations
+	}
+	initEvals := settings.InitEvaluations
+	if initEvals == 0 {
+		initEvals = 5
+		if 2*dim > initEvals {
+			initEvals = 2 * dim
+		}
+	}
+	if initEvals > evals {
+		initEvals = evals
+	}
+	kernel := settings.Kernel
+	if kernel == nil {
+		kernel = &gp.Matern{Variance: 1, LengthScale: 0.2, Nu: 2.5}
+	}
+	noise := settings.Noise
+	if noise == 0 {
+		noise = defaultNoise
+	}
+	acq := settings.Acquisition
+	if acq == nil {
+		acq = ExpectedImprovement{}
+	}
+	nCand := settings.Candidates
+	if nCand == 0 {
+		nCand = defaultCandidates
+	}
+	uniform := rand.Float64
+	normal := rand.NormFloat64
+	if settings.Src != nil {
+		rnd := rand.New(settings.Src)
+		uniform = rnd.Float64
+		normal = rnd.NormFloat64
+	}
+
+	// The surrogate is fitted on the unit hypercube.
+	unit := mat.NewDense(evals, dim, nil)
+	res := &Result{
+		F:         math.Inf(1),
+		Locations: mat.NewDense(evals, dim, nil),
+		Values:    make([]float64, 0, evals),
+	}
+	toDomain := func(dst, u []float64) {
+		for i, v := range u {
+			dst[i] = lower[i] + v*(upper[i]-lower[i])
+		}
+	}
+	evaluate := func(u []float64) {
+		n := len(res.Values)
+		unit.SetRow(n, u)
+		x := res.Locations.RawRowView(n)
+		toDomain(x, u)
+		v := f(append([]float64(nil), x...))
+		res.Values = append(res.Values, v)
+		if v < res.F {
+			res.F = v
+			res.X = append(res.X[:0], x...)
+		}
+	}
+	result := func() *Result {
+		n := len(res.Values)
+		res.Locations = res.Locations.Slice(0, n, 0, dim).(*mat.Dense)
+		return res
+	}
+
+	u := make([]float64, dim)
+	for k := 0; k < initEvals; k++ {
+		for i := range u {
+			u[i] = uniform()
+		}
+		evaluate(u)
+	}
+
+	cand := make([]float64, dim)
+	for len(res.Values) < evals {
+		n := len(res.Values)
+
+		// Standardize the observations and fit the surrogate.
+		mean, std := stat.MeanStdDev(res.Values, nil)
+		if !(std > 0) {
+			std = 1
+		}
+		y := make([]float64, n)
+		best := math.Inf(1)
+		for i, v := range res.Values {
+			y[i] = (v - mean) / std
+			best = math.Min(best, y[i])
+		}
+		x := unit.Slice(0, n, 0, dim)
+		model, ok := gp.NewRegression(kernel, noise, x, y)
+		if !ok {
+			return result(), ErrSurrogate
+		}
+		// The model is left unchanged if the fit fails.
+		model.Optimize(true, nil, nil)
+
+		score := func(u []float64) float64 {
+			m, v := model.Predict(u)
+			return acq.Score(m, math.Sqrt(v), best)
+		}
+
+		// Score random candidates and refine the best of them. Half of
+		// the candidates are drawn near the best observation, where the
+		// acquisition function often has a narrow peak.
+		bestScore := math.Inf(-1)
+		bestUnit := unit.RawRowView(floats.MinIdx(res.Values))
+		for k := 0; k < nCand; k++ {
+			for i := range cand {
+				if k%2 == 0 {
+					cand[i] = uniform()
+				} else {
+					cand[i] = math.Min(math.Max(bestUnit[i]+localScale*normal(), 0), 1)
+				}
+			}
+			if s := score(cand); s > bestScore {
+				bestScore = s
+				copy(u, cand)
+			}
+		}
+		problem := optimize.Problem{
+			Func: func(u []float64) float64 {
+				for _, v := range u {
+					if v < 0 || v > 1 {
+						return math.Inf(1)
+					}
+				}
+				return -score(u)
+			},
+		}
+		local, _ := optimize.Minimize(problem, u, &optimize.Settings{FuncEvaluations: 50 * dim}, &optimize.NelderMead{SimplexSize: 0.05})
+		if local != nil && -local.F > bestScore {
+			copy(u, local.X)
+		}
+		evaluate(u)
+	}
+	return result(), nil
+}
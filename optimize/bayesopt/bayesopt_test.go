@@ -0,0 +1,126 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/optimize/functions"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestAcquisition(t *testing.T) {
+	t.Parallel()
+	// Check the expected improvement against numerical integration.
+	for _, test := range []struct {
+		mean, std, best, xi float64
+	}{
+		{mean: 0, std: 1, best: 0},
+		{mean: 1, std: 0.5, best: 0, xi: 0.1},
+		{mean: -1, std: 2, best: 0.5},
+	} {
+		got := ExpectedImprovement{Xi: test.xi}.Score(test.mean, test.std, test.best)
+		n := distuv.Normal{Mu: test.mean, Sigma: test.std}
+		var want float64
+		const steps = 200000
+		lo, hi := test.mean-10*test.std, test.best-test.xi
+		h := (hi - lo) / steps
+		for i := 0; i < steps; i++ {
+			x := lo + (float64(i)+0.5)*h
+			want += (hi - x) * n.Prob(x) * h
+		}
+		if !scalar.EqualWithinAbsOrRel(got, want, 1e-8, 1e-6) {
+			t.Errorf("unexpected expected improvement for %+v: got:%v want:%v", test, got, want)
+		}
+	}
+	if got := (ExpectedImprovement{}).Score(-1, 0, 0); got != 1 {
+		t.Errorf("unexpected expected improvement with zero deviation: got:%v want:1", got)
+	}
+	if got := (UpperConfidenceBound{Kappa: 2}).Score(1, 0.5, 0); got != 0 {
+		t.Errorf("unexpected upper confidence bound: got:%v want:0", got)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		f        func([]float64) float64
+		settings Settings
+		want     float64
+		tol      float64
+	}{
+		{
+			name: "quadratic",
+			f: func(x []float64) float64 {
+				return (x[0]-0.3)*(x[0]-0.3) + 2*(x[1]+0.5)*(x[1]+0.5)
+			},
+			settings: Settings{
+				Lower:       []float64{-2, -2},
+				Upper:       []float64{2, 2},
+				Evaluations: 25,
+			},
+			want: 0,
+			tol:  1e-3,
+		},
+		{
+			name: "branin expected improvement",
+			f:    functions.BraninHoo{}.Func,
+			settings: Settings{
+				Lower:       []float64{-5, 0},
+				Upper:       []float64{10, 15},
+				Evaluations: 40,
+			},
+			want: 0.397887,
+			tol:  0.05,
+		},
+		{
+			name: "branin upper confidence bound",
+			f:    functions.BraninHoo{}.Func,
+			settings: Settings{
+				Lower:       []float64{-5, 0},
+				Upper:       []float64{10, 15},
+				Evaluations: 40,
+				Acquisition: UpperConfidenceBound{Kappa: 2},
+			},
+			want: 0.397887,
+			tol:  0.05,
+		},
+	} {
+		settings := test.settings
+		settings.Src = rand.NewSource(1)
+		var evals int
+		f := func(x []float64) float64 {
+			evals++
+			for i, v := range x {
+				if v < settings.Lower[i] || v > settings.Upper[i] {
+					t.Errorf("%s: evaluation outside bounds: %v", test.name, x)
+				}
+			}
+			return test.f(x)
+		}
+		res, err := Minimize(f, &settings)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if evals != settings.Evaluations || len(res.Values) != evals {
+			t.Errorf("%s: unexpected number of evaluations: got:%d want:%d", test.name, evals, settings.Evaluations)
+		}
+		if r, c := res.Locations.Dims(); r != evals || c != len(settings.Lower) {
+			t.Errorf("%s: unexpected locations shape: %d×%d", test.name, r, c)
+		}
+		if math.Abs(res.F-test.want) > test.tol {
+			t.Errorf("%s: minimum not found: got:%v want:%v", test.name, res.F, test.want)
+		}
+		if res.F != test.f(res.X) {
+			t.Errorf("%s: best value does not match best location", test.name)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bayesopt implements Bayesian optimization for minimizing expensive
+// black-box functions, such as in hyperparameter tuning or the calibration of
+// simulations, where each evaluation of the function may take minutes and the
+// number of evaluations is small.
+//
+// For an introduction to Bayesian optimization, see
+//  Shahriari, B. et al. "Taking the human out of the loop: A review of
+//  Bayesian optimization." Proceedings of the IEEE 104.1 (2016): 148-175.
+package bayesopt // import "gonum.org/v1/gonum/optimize/bayesopt"
@@ -41,7 +41,8 @@ var _ Method = (*CmaEsChol)(nil)
 // it can be that the mean of the distribution is updated in a gradient-descent
 // like fashion, followed by a shrinking covariance.
 // It is recommended that the algorithm be run multiple times (with different
-// InitMean) to have a better chance of finding the global minimum.
+// InitMean) to have a better chance of finding the global minimum, either
+// explicitly or by setting Restarts.
 //
 // The CMA-ES-Chol algorithm differs from the standard CMA-ES algorithm in that
 // it directly updates the Cholesky decomposition of the normal distribution.
@@ -83,10 +84,31 @@ type CmaEsChol struct {
 	// Src allows a random number generator to be supplied for generating samples.
 	// If Src is nil the generator in golang.org/x/math/rand is used.
 	Src rand.Source
+	// Restarts sets the maximum number of times the algorithm is restarted
+	// when the StopLogDet criterion is met, which improves the chance of
+	// escaping local minima of multimodal functions. Each restart begins
+	// at the initial location with the initial covariance and a larger
+	// population, as described by BIPOP. The best location over all runs is
+	// tracked unless ForgetBest is true. If Restarts is 0, the algorithm is
+	// not restarted. Restarts cannot be negative or CmaEsChol will panic.
+	Restarts int
+	// BIPOP specifies the restart strategy. If BIPOP is false, the population
+	// size is doubled at each restart (IPOP-CMA-ES). If BIPOP is true, the
+	// restarts alternate between a regime with doubling population sizes and
+	// a regime with small population sizes and initial step sizes drawn at
+	// random, choosing the regime that has used fewer function evaluations
+	// (BIPOP-CMA-ES). The strategies are described in
+	//  Auger, A. and Hansen, N. "A restart CMA evolution strategy with
+	//  increasing population size." IEEE Congress on Evolutionary
+	//  Computation. 2005.
+	//  Hansen, N. "Benchmarking a BI-population CMA-ES on the BBOB-2009
+	//  function testbed." GECCO. 2009.
+	BIPOP bool
 
 	// Fixed algorithm parameters.
 	dim                 int
 	pop                 int
+	invSigma0           float64
 	weights             []float64
 	muEff               float64
 	cc, cs, c1, cmu, ds float64
@@ -106,6 +128,15 @@ type CmaEsChol struct {
 	bestX []float64
 	bestF float64
 
+	// Restart state.
+	initMean               []float64
+	restarts               int
+	defaultPop, largePop   int
+	runEvals               int
+	largeEvals, smallEvals int
+	smallRegime            bool
+	float64                func() float64
+
 	// Synchronization.
 	sentIdx     int
 	receivedIdx int
@@ -152,16 +183,61 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 		panic(negativeTasks)
 	}
 
-	// Set fixed algorithm parameters.
-	// Parameter values are from https://arxiv.org/pdf/1604.00772.pdf .
 	cma.dim = dim
-	cma.pop = cma.Population
-	n := float64(dim)
-	if cma.pop == 0 {
-		cma.pop = 4 + int(3*math.Log(n)) // Note the implicit floor.
-	} else if cma.pop < 0 {
+	pop := cma.Population
+	if pop == 0 {
+		pop = 4 + int(3*math.Log(float64(dim))) // Note the implicit floor.
+	} else if pop < 0 {
 		panic("cma-es-chol: negative population size")
 	}
+	cma.invSigma0 = 1 / cma.InitStepSize
+	if cma.InitStepSize == 0 {
+		cma.invSigma0 = 10.0 / 3
+	} else if cma.InitStepSize < 0 {
+		panic("cma-es-chol: negative initial step size")
+	}
+	if cma.InitCholesky != nil && cma.InitCholesky.SymmetricDim() != dim {
+		panic("cma-es-chol: incorrect InitCholesky size")
+	}
+	if cma.Restarts < 0 {
+		panic("cma-es-chol: negative number of restarts")
+	}
+	cma.setup(pop, cma.invSigma0, 1)
+
+	cma.bestX = resize(cma.bestX, dim)
+	cma.bestF = math.Inf(1)
+
+	cma.initMean = resize(cma.initMean, dim)
+	cma.restarts = 0
+	cma.defaultPop = pop
+	cma.largePop = pop
+	cma.runEvals = 0
+	cma.largeEvals = 0
+	cma.smallEvals = 0
+	cma.smallRegime = false
+	cma.float64 = rand.Float64
+	if cma.Src != nil {
+		cma.float64 = rand.New(cma.Src).Float64
+	}
+
+	cma.sentIdx = 0
+	cma.receivedIdx = 0
+	cma.operation = nil
+	cma.updateErr = nil
+	t := min(tasks, cma.pop)
+	return t
+}
+
+// setup sets the algorithm parameters for a run with the given population
+// size and inverse step size, and initializes the adaptive parameters with
+// the initial covariance scaled by covScale.
+func (cma *CmaEsChol) setup(pop int, invSigma, covScale float64) {
+	dim := cma.dim
+
+	// Set fixed algorithm parameters.
+	// Parameter values are from https://arxiv.org/pdf/1604.00772.pdf .
+	cma.pop = pop
+	n := float64(dim)
 	mu := cma.pop / 2
 	cma.weights = resize(cma.weights, mu)
 	for i := range cma.weights {
@@ -187,14 +263,12 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 	// Allocate memory for function data.
 	cma.xs = mat.NewDense(cma.pop, dim, nil)
 	cma.fs = resize(cma.fs, cma.pop)
+	for i := range cma.fs {
+		cma.fs[i] = math.NaN()
+	}
 
 	// Allocate and initialize adaptive parameters.
-	cma.invSigma = 1 / cma.InitStepSize
-	if cma.InitStepSize == 0 {
-		cma.invSigma = 10.0 / 3
-	} else if cma.InitStepSize < 0 {
-		panic("cma-es-chol: negative initial step size")
-	}
+	cma.invSigma = invSigma
 	cma.pc = resize(cma.pc, dim)
 	for i := range cma.pc {
 		cma.pc[i] = 0
@@ -206,9 +280,6 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 	cma.mean = resize(cma.mean, dim) // mean location initialized at the start of Run
 
 	if cma.InitCholesky != nil {
-		if cma.InitCholesky.SymmetricDim() != dim {
-			panic("cma-es-chol: incorrect InitCholesky size")
-		}
 		cma.chol.Clone(cma.InitCholesky)
 	} else {
 		// Set the initial Cholesky to I.
@@ -223,16 +294,41 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 		}
 		cma.chol = chol
 	}
+	if covScale != 1 {
+		cma.chol.Scale(covScale, &cma.chol)
+	}
+}
 
-	cma.bestX = resize(cma.bestX, dim)
-	cma.bestF = math.Inf(1)
-
-	cma.sentIdx = 0
-	cma.receivedIdx = 0
-	cma.operation = nil
-	cma.updateErr = nil
-	t := min(tasks, cma.pop)
-	return t
+// restart begins a new run of the algorithm according to the restart
+// strategy, and returns whether a restart was made.
+func (cma *CmaEsChol) restart() bool {
+	if cma.restarts >= cma.Restarts {
+		return false
+	}
+	cma.restarts++
+	if cma.smallRegime {
+		cma.smallEvals += cma.runEvals
+	} else {
+		cma.largeEvals += cma.runEvals
+	}
+	cma.runEvals = 0
+
+	// The first restart is always in the large population regime so
+	// that the small population sizes are at least the default.
+	if !cma.BIPOP || cma.restarts == 1 || cma.largeEvals <= cma.smallEvals {
+		cma.smallRegime = false
+		cma.largePop *= 2
+		cma.setup(cma.largePop, cma.invSigma0, 1)
+	} else {
+		cma.smallRegime = true
+		u := cma.float64()
+		pop := int(float64(cma.defaultPop) * math.Pow(0.5*float64(cma.largePop)/float64(cma.defaultPop), u*u))
+		// The step size is multiplied by 10^(-2u).
+		stepScale := math.Pow(10, -2*u)
+		cma.setup(pop, cma.invSigma0/stepScale, stepScale*stepScale)
+	}
+	copy(cma.mean, cma.initMean)
+	return true
 }
 
 func (cma *CmaEsChol) sendInitTasks(tasks []Task) {
@@ -297,6 +393,7 @@ func (cma *CmaEsChol) findBestAndUpdateTask(task Task) Task {
 
 func (cma *CmaEsChol) Run(operations chan<- Task, results <-chan Task, tasks []Task) {
 	copy(cma.mean, tasks[0].X)
+	copy(cma.initMean, tasks[0].X)
 	cma.operation = operations
 	// Send the initial tasks. We know there are at most as many tasks as elements
 	// of the population.
@@ -336,6 +433,7 @@ Loop:
 
 				task := cma.findBestAndUpdateTask(result)
 				// Update the parameters and send a MajorIteration or a convergence.
+				cma.runEvals += cma.pop
 				err := cma.update()
 				// Kill the existing data.
 				for i := range cma.fs {
@@ -346,7 +444,7 @@ Loop:
 				case err != nil:
 					cma.updateErr = err
 					task.Op = MethodDone
-				case cma.methodConverged() != NotTerminated:
+				case cma.methodConverged() != NotTerminated && !cma.restart():
 					task.Op = MethodDone
 				default:
 					task.Op = MajorIteration
@@ -196,6 +196,55 @@ func cmaTestCases() []cmaTestCase {
 				return nil
 			},
 		},
+		{
+			// Test that IPOP restarts escape the local minimum near the
+			// initial location.
+			dim: 3,
+			problem: Problem{
+				Func: functions.Rastrigin{}.Func,
+			},
+			initX: []float64{2.2, 2.2, 2.2},
+			method: &CmaEsChol{
+				Restarts: 8,
+			},
+			settings: &Settings{
+				Converger: NeverTerminate{},
+			},
+			good: func(result *Result, err error, concurrent int) error {
+				if result.Status != MethodConverge {
+					return errors.New("result not method converge")
+				}
+				if !floats.EqualApprox(result.X, []float64{0, 0, 0}, 1e-6) {
+					return errors.New("global minimum not found")
+				}
+				return nil
+			},
+		},
+		{
+			// Test that BIPOP restarts leave the basin of the initial
+			// location, where the function value is above 10.
+			dim: 3,
+			problem: Problem{
+				Func: functions.Rastrigin{}.Func,
+			},
+			initX: []float64{2.2, 2.2, 2.2},
+			method: &CmaEsChol{
+				Restarts: 8,
+				BIPOP:    true,
+			},
+			settings: &Settings{
+				Converger: NeverTerminate{},
+			},
+			good: func(result *Result, err error, concurrent int) error {
+				if result.Status != MethodConverge {
+					return errors.New("result not method converge")
+				}
+				if result.F > 1 {
+					return errors.New("restarts did not improve on local minimum")
+				}
+				return nil
+			},
+		},
 	}
 }
 
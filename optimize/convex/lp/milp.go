@@ -0,0 +1,496 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	ErrNodeLimit = errors.New("lp: branch and bound node limit reached")
+	ErrTimeLimit = errors.New("lp: branch and bound time limit reached")
+)
+
+const (
+	defaultIntegerTol = 1e-6
+	defaultGapTol     = 1e-9
+	defaultSimplexTol = 1e-10
+)
+
+// VarKind specifies the values a variable of a mixed-integer linear program
+// may take.
+type VarKind int
+
+const (
+	// Continuous variables may take any value within their bounds.
+	Continuous VarKind = iota
+	// Integer variables may take integer values within their bounds.
+	Integer
+	// Binary variables may take the values 0 and 1.
+	Binary
+)
+
+// MILP is a mixed-integer linear program in the general form
+//  minimize cᵀ * x
+//  s.t      G * x <= h
+//           A * x = b
+//           lower <= x <= upper
+//           x_i integer for the integer and binary variables.
+type MILP struct {
+	// C holds the objective coefficients. Its length is the number of
+	// variables.
+	C []float64
+
+	// G and H hold the inequality constraints. If there are no inequality
+	// constraints they may be nil.
+	G mat.Matrix
+	H []float64
+
+	// A and B hold the equality constraints. If there are no equality
+	// constraints they may be nil. The equality constraints must be
+	// linearly independent.
+	A mat.Matrix
+	B []float64
+
+	// Lower and Upper hold the bounds of the variables, and elements may
+	// be infinite. If Lower is nil, the variables are non-negative, and
+	// if Upper is nil, the variables are unbounded above.
+	Lower, Upper []float64
+
+	// Kind holds the kinds of the variables. If Kind is nil, all of the
+	// variables are continuous. The bounds of Binary variables are
+	// intersected with [0, 1].
+	Kind []VarKind
+}
+
+// MILPSettings holds the settings of BranchAndBound.
+type MILPSettings struct {
+	// Gap is the relative optimality gap at which the search stops.
+	// The search stops when the objective of the best integer solution
+	// found, f, and the lower bound on the optimal objective, l, satisfy
+	//  f - l <= Gap * max(1, |f|).
+	// If Gap is 0, a default value of 1e-9 is used.
+	Gap float64
+
+	// TimeLimit and NodeLimit limit the search time and the number of
+	// linear programs solved. If they are 0 the search is not limited.
+	TimeLimit time.Duration
+	NodeLimit int
+
+	// IntegerTol is the distance from an integer within which the value
+	// of an integer variable is considered integer. If IntegerTol is 0, a
+	// default value of 1e-6 is used.
+	IntegerTol float64
+
+	// Tol is the tolerance passed to Simplex for the linear programs.
+	// If Tol is 0, a default value of 1e-10 is used.
+	Tol float64
+}
+
+// MILPResult holds the solution of a mixed-integer linear program.
+type MILPResult struct {
+	// X is the best solution found and F is its objective value.
+	X []float64
+	F float64
+
+	// Bound is a lower bound on the optimal objective value.
+	Bound float64
+
+	// Nodes is the number of linear programs solved.
+	Nodes int
+}
+
+// BranchAndBound solves a mixed-integer linear program using the branch and
+// bound method. The linear relaxation of the problem, where the integer
+// constraints are removed, is solved with Simplex to give a lower bound on the
+// optimal objective. If an integer variable has a fractional value in the
+// solution of the relaxation, the problem is split into two subproblems by
+// rounding the bounds of the variable down and up, and the subproblems are
+// explored with the lowest bound first. Subproblems whose bound is no better
+// than the best integer solution found are discarded.
+//
+// If the search finishes, BranchAndBound returns the optimal solution to within
+// the gap in settings. If the time or node limit is reached, BranchAndBound
+// returns ErrTimeLimit or ErrNodeLimit with the best solution found, if any.
+// ErrInfeasible is returned if the problem has no integer solution and
+// ErrUnbounded if the relaxation is unbounded. Other errors from Simplex are
+// returned unchanged.
+//
+// If settings is nil, the default settings are used. BranchAndBound panics if
+// the dimensions of the inputs do not match.
+func BranchAndBound(p MILP, settings *MILPSettings) (*MILPResult, error) {
+	start := time.Now()
+	n := len(p.C)
+	checkMILP(p)
+	if settings == nil {
+		settings = &MILPSettings{}
+	}
+	intTol := settings.IntegerTol
+	if intTol == 0 {
+		intTol = defaultIntegerTol
+	}
+	gap := settings.Gap
+	if gap == 0 {
+		gap = defaultGapTol
+	}
+	tol := settings.Tol
+	if tol == 0 {
+		tol = defaultSimplexTol
+	}
+
+	root := &milpNode{
+		lower: make([]float64, n),
+		upper: make([]float64, n),
+		bound: math.Inf(-1),
+	}
+	for j := 0; j < n; j++ {
+		root.lower[j] = 0
+		if p.Lower != nil {
+			root.lower[j] = p.Lower[j]
+		}
+		root.upper[j] = math.Inf(1)
+		if p.Upper != nil {
+			root.upper[j] = p.Upper[j]
+		}
+		if p.kind(j) == Binary {
+			root.lower[j] = math.Max(root.lower[j], 0)
+			root.upper[j] = math.Min(root.upper[j], 1)
+		}
+	}
+
+	res := &MILPResult{
+		F:     math.Inf(1),
+		Bound: math.Inf(-1),
+	}
+	queue := milpQueue{root}
+	var err error
+	for len(queue) > 0 {
+		// The lowest bound of the open nodes bounds the optimum.
+		res.Bound = queue[0].bound
+		if res.X != nil && res.F-res.Bound <= gap*math.Max(1, math.Abs(res.F)) {
+			break
+		}
+		if settings.TimeLimit > 0 && time.Since(start) > settings.TimeLimit {
+			err = ErrTimeLimit
+			break
+		}
+		if settings.NodeLimit > 0 && res.Nodes >= settings.NodeLimit {
+			err = ErrNodeLimit
+			break
+		}
+
+		node := heap.Pop(&queue).(*milpNode)
+		if !node.roundBounds(p, intTol) {
+			continue
+		}
+		res.Nodes++
+		f, x, lpErr := node.relax(p, tol)
+		switch lpErr {
+		case nil:
+		case ErrInfeasible:
+			continue
+		default:
+			return nil, lpErr
+		}
+		if res.X != nil && f >= res.F-gap*math.Max(1, math.Abs(res.F)) {
+			continue
+		}
+
+		// Branch on the most fractional integer variable.
+		branch := -1
+		var frac float64
+		for j, v := range x {
+			if p.kind(j) == Continuous {
+				continue
+			}
+			d := math.Abs(v - math.Round(v))
+			if d > intTol && d > frac {
+				branch = j
+				frac = d
+			}
+		}
+		if branch == -1 {
+			for j := range x {
+				if p.kind(j) != Continuous {
+					x[j] = math.Round(x[j])
+				}
+			}
+			res.X = x
+			res.F = floats.Dot(p.C, x)
+			continue
+		}
+		down := node.child(f)
+		down.upper[branch] = math.Floor(x[branch])
+		up := node.child(f)
+		up.lower[branch] = math.Ceil(x[branch])
+		heap.Push(&queue, down)
+		heap.Push(&queue, up)
+	}
+	if len(queue) == 0 {
+		res.Bound = res.F
+	}
+	if res.X == nil {
+		res.F = math.NaN()
+		if err == nil {
+			err = ErrInfeasible
+		}
+		return res, err
+	}
+	res.Bound = math.Min(res.Bound, res.F)
+	return res, err
+}
+
+func checkMILP(p MILP) {
+	n := len(p.C)
+	if p.G == nil {
+		if len(p.H) != 0 {
+			panic(badShape)
+		}
+	} else if r, c := p.G.Dims(); r != len(p.H) || c != n {
+		panic(badShape)
+	}
+	if p.A == nil {
+		if len(p.B) != 0 {
+			panic(badShape)
+		}
+	} else if r, c := p.A.Dims(); r != len(p.B) || c != n {
+		panic(badShape)
+	}
+	if (p.Lower != nil && len(p.Lower) != n) || (p.Upper != nil && len(p.Upper) != n) || (p.Kind != nil && len(p.Kind) != n) {
+		panic(badShape)
+	}
+}
+
+func (p MILP) kind(j int) VarKind {
+	if p.Kind == nil {
+		return Continuous
+	}
+	return p.Kind[j]
+}
+
+// milpNode is a subproblem of a mixed-integer linear program given by bounds
+// on the variables.
+type milpNode struct {
+	lower, upper []float64
+	// bound is a lower bound on the objective of the subproblem.
+	bound float64
+}
+
+func (nd *milpNode) child(bound float64) *milpNode {
+	c := &milpNode{
+		lower: make([]float64, len(nd.lower)),
+		upper: make([]float64, len(nd.upper)),
+		bound: bound,
+	}
+	copy(c.lower, nd.lower)
+	copy(c.upper, nd.upper)
+	return c
+}
+
+// roundBounds rounds the bounds of the integer variables to integers, and
+// returns whether the bounds are consistent.
+func (nd *milpNode) roundBounds(p MILP, tol float64) bool {
+	for j := range nd.lower {
+		if p.kind(j) != Continuous {
+			nd.lower[j] = math.Ceil(nd.lower[j] - tol)
+			nd.upper[j] = math.Floor(nd.upper[j] + tol)
+		}
+		if nd.lower[j] > nd.upper[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// relax solves the linear relaxation of the subproblem, returning the optimal
+// objective and location.
+func (nd *milpNode) relax(p MILP, tol float64) (float64, []float64, error) {
+	n := len(p.C)
+
+	// Write each variable as an offset plus a sign times a non-negative
+	// standard form variable, splitting free variables into two.
+	offset := make([]float64, n)
+	sign := make([]float64, n)
+	col := make([]int, n)
+	var nCols, nUpper int
+	for j := 0; j < n; j++ {
+		col[j] = nCols
+		nCols++
+		switch {
+		case !math.IsInf(nd.lower[j], -1):
+			offset[j] = nd.lower[j]
+			sign[j] = 1
+			if !math.IsInf(nd.upper[j], 1) {
+				nUpper++
+			}
+		case !math.IsInf(nd.upper[j], 1):
+			offset[j] = nd.upper[j]
+			sign[j] = -1
+		default:
+			sign[j] = 1
+			nCols++
+		}
+	}
+	nIneq := len(p.H)
+	nEq := len(p.B)
+	nVarCols := nCols
+	nCols += nIneq + nUpper
+	nRows := nIneq + nEq + nUpper
+
+	c := make([]float64, nCols)
+	a := mat.NewDense(max(nRows, 1), nCols, nil)
+	b := make([]float64, max(nRows, 1))
+	constant := floats.Dot(p.C, offset)
+	setVar := func(row, j int, v float64) {
+		a.Set(row, col[j], sign[j]*v)
+		if math.IsInf(nd.lower[j], -1) && math.IsInf(nd.upper[j], 1) {
+			a.Set(row, col[j]+1, -v)
+		}
+	}
+	for j, v := range p.C {
+		c[col[j]] = sign[j] * v
+		if math.IsInf(nd.lower[j], -1) && math.IsInf(nd.upper[j], 1) {
+			c[col[j]+1] = -v
+		}
+	}
+	addRows := func(m mat.Matrix, rhs []float64, row0 int) {
+		for i, v := range rhs {
+			b[row0+i] = v
+			for j := 0; j < n; j++ {
+				g := m.At(i, j)
+				if g == 0 {
+					continue
+				}
+				setVar(row0+i, j, g)
+				b[row0+i] -= g * offset[j]
+			}
+		}
+	}
+	if nIneq > 0 {
+		addRows(p.G, p.H, 0)
+		for i := 0; i < nIneq; i++ {
+			a.Set(i, nVarCols+i, 1)
+		}
+	}
+	if nEq > 0 {
+		addRows(p.A, p.B, nIneq)
+	}
+	row := nIneq + nEq
+	for j := 0; j < n; j++ {
+		if sign[j] == 1 && !math.IsInf(nd.lower[j], -1) && !math.IsInf(nd.upper[j], 1) {
+			a.Set(row, col[j], 1)
+			a.Set(row, nVarCols+nIneq+(row-nIneq-nEq), 1)
+			b[row] = nd.upper[j] - nd.lower[j]
+			row++
+		}
+	}
+
+	y, err := solveStandard(c, a, b, nRows, tol)
+	if err != nil {
+		return math.NaN(), nil, err
+	}
+	x := make([]float64, n)
+	for j := range x {
+		x[j] = offset[j] + sign[j]*y[col[j]]
+		if math.IsInf(nd.lower[j], -1) && math.IsInf(nd.upper[j], 1) {
+			x[j] -= y[col[j]+1]
+		}
+	}
+	return constant + floats.Dot(c, y), x, nil
+}
+
+// solveStandard solves the standard form linear program with the first m rows
+// of a and b, removing the zero rows and columns that Simplex does not accept.
+func solveStandard(c []float64, a *mat.Dense, b []float64, m int, tol float64) ([]float64, error) {
+	_, n := a.Dims()
+	var cols []int
+	for j := 0; j < n; j++ {
+		var nonzero bool
+		for i := 0; i < m; i++ {
+			if a.At(i, j) != 0 {
+				nonzero = true
+				break
+			}
+		}
+		switch {
+		case nonzero:
+			cols = append(cols, j)
+		case c[j] < 0:
+			return nil, ErrUnbounded
+		}
+	}
+	var rows []int
+	for i := 0; i < m; i++ {
+		var nonzero bool
+		for _, j := range cols {
+			if a.At(i, j) != 0 {
+				nonzero = true
+				break
+			}
+		}
+		switch {
+		case nonzero:
+			rows = append(rows, i)
+		case b[i] != 0:
+			return nil, ErrInfeasible
+		}
+	}
+	y := make([]float64, n)
+	if len(rows) == 0 {
+		// All of the variables are at their lower bound.
+		return y, nil
+	}
+	if len(rows) > len(cols) {
+		return nil, ErrSingular
+	}
+	aSub := mat.NewDense(len(rows), len(cols), nil)
+	bSub := make([]float64, len(rows))
+	cSub := make([]float64, len(cols))
+	for i, r := range rows {
+		bSub[i] = b[r]
+		for k, j := range cols {
+			aSub.Set(i, k, a.At(r, j))
+		}
+	}
+	for k, j := range cols {
+		cSub[k] = c[j]
+	}
+	_, ySub, err := Simplex(cSub, aSub, bSub, tol, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, j := range cols {
+		y[j] = ySub[k]
+	}
+	return y, nil
+}
+
+// milpQueue is a priority queue of subproblems ordered by their bound.
+type milpQueue []*milpNode
+
+func (q milpQueue) Len() int            { return len(q) }
+func (q milpQueue) Less(i, j int) bool  { return q[i].bound < q[j].bound }
+func (q milpQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *milpQueue) Push(x interface{}) { *q = append(*q, x.(*milpNode)) }
+func (q *milpQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,237 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBranchAndBound(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name string
+		p    MILP
+		f    float64
+		x    []float64 // x is nil if the solution is not unique.
+	}{
+		{
+			name: "integer",
+			p: MILP{
+				C:    []float64{-1, -1},
+				G:    mat.NewDense(3, 2, []float64{-1, 1, 3, 2, 2, 3}),
+				H:    []float64{1, 12, 12},
+				Kind: []VarKind{Integer, Integer},
+			},
+			f: -4,
+		},
+		{
+			name: "mixed",
+			p: MILP{
+				C:     []float64{-1, -2},
+				G:     mat.NewDense(1, 2, []float64{1, 1}),
+				H:     []float64{3.5},
+				Upper: []float64{inf, 2.5},
+				Kind:  []VarKind{Continuous, Integer},
+			},
+			f: -5.5,
+			x: []float64{1.5, 2},
+		},
+		{
+			name: "knapsack",
+			p: MILP{
+				C:    []float64{-10, -13, -7, -8, -9, -4},
+				G:    mat.NewDense(1, 6, []float64{5, 7, 4, 5, 6, 3}),
+				H:    []float64{15},
+				Kind: []VarKind{Binary, Binary, Binary, Binary, Binary, Binary},
+			},
+			f: -27,
+			x: []float64{1, 1, 0, 0, 0, 1},
+		},
+		{
+			name: "negative bounds",
+			p: MILP{
+				C:     []float64{1, 1},
+				G:     mat.NewDense(1, 2, []float64{-2, -2}),
+				H:     []float64{5},
+				Lower: []float64{-5, -inf},
+				Upper: []float64{5, 0},
+				Kind:  []VarKind{Integer, Integer},
+			},
+			f: -2,
+		},
+		{
+			// The assignment problem, where the redundant last column
+			// constraint is omitted to keep the equality constraints
+			// linearly independent.
+			name: "assignment",
+			p: MILP{
+				C: []float64{
+					4, 1, 3,
+					2, 0, 5,
+					3, 2, 2,
+				},
+				A: mat.NewDense(5, 9, []float64{
+					1, 1, 1, 0, 0, 0, 0, 0, 0,
+					0, 0, 0, 1, 1, 1, 0, 0, 0,
+					0, 0, 0, 0, 0, 0, 1, 1, 1,
+					1, 0, 0, 1, 0, 0, 1, 0, 0,
+					0, 1, 0, 0, 1, 0, 0, 1, 0,
+				}),
+				B:    []float64{1, 1, 1, 1, 1},
+				Kind: []VarKind{Binary, Binary, Binary, Binary, Binary, Binary, Binary, Binary, Binary},
+			},
+			f: 5,
+			x: []float64{0, 1, 0, 1, 0, 0, 0, 0, 1},
+		},
+	} {
+		res, err := BranchAndBound(test.p, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(res.F, test.f, 1e-10, 1e-10) {
+			t.Errorf("%s: unexpected objective: got:%v want:%v", test.name, res.F, test.f)
+		}
+		if res.Bound != res.F {
+			t.Errorf("%s: bound does not match objective for finished search: %v != %v", test.name, res.Bound, res.F)
+		}
+		if test.x != nil && !floats.EqualApprox(res.X, test.x, 1e-10) {
+			t.Errorf("%s: unexpected solution: got:%v want:%v", test.name, res.X, test.x)
+		}
+		if got := floats.Dot(test.p.C, res.X); !scalar.EqualWithinAbsOrRel(got, res.F, 1e-10, 1e-10) {
+			t.Errorf("%s: objective does not match solution: %v != %v", test.name, got, res.F)
+		}
+	}
+}
+
+func TestBranchAndBoundErrors(t *testing.T) {
+	t.Parallel()
+	_, err := BranchAndBound(MILP{
+		C:     []float64{1},
+		A:     mat.NewDense(1, 1, []float64{2}),
+		B:     []float64{1},
+		Upper: []float64{10},
+		Kind:  []VarKind{Integer},
+	}, nil)
+	if err != ErrInfeasible {
+		t.Errorf("unexpected error for infeasible problem: got:%v want:%v", err, ErrInfeasible)
+	}
+
+	_, err = BranchAndBound(MILP{
+		C:    []float64{-1, 1},
+		G:    mat.NewDense(1, 2, []float64{0, 1}),
+		H:    []float64{3},
+		Kind: []VarKind{Integer, Integer},
+	}, nil)
+	if err != ErrUnbounded {
+		t.Errorf("unexpected error for unbounded problem: got:%v want:%v", err, ErrUnbounded)
+	}
+
+	res, err := BranchAndBound(MILP{
+		C:    []float64{-10, -13, -7, -8, -9, -4},
+		G:    mat.NewDense(1, 6, []float64{5, 7, 4, 5, 6, 3}),
+		H:    []float64{15},
+		Kind: []VarKind{Binary, Binary, Binary, Binary, Binary, Binary},
+	}, &MILPSettings{NodeLimit: 2})
+	if err != ErrNodeLimit {
+		t.Errorf("unexpected error at node limit: got:%v want:%v", err, ErrNodeLimit)
+	}
+	if res.Nodes != 2 {
+		t.Errorf("unexpected number of nodes: got:%d want:2", res.Nodes)
+	}
+	if res.X != nil && res.F < res.Bound {
+		t.Errorf("objective less than bound: %v < %v", res.F, res.Bound)
+	}
+}
+
+func TestBranchAndBoundRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n     = 3
+		m     = 3
+		bound = 3
+	)
+	for trial := 0; trial < 50; trial++ {
+		c := make([]float64, n)
+		for j := range c {
+			c[j] = float64(rnd.Intn(11) - 5)
+		}
+		g := mat.NewDense(m, n, nil)
+		h := make([]float64, m)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, float64(rnd.Intn(11)-5)+rnd.Float64())
+			}
+			h[i] = 10 * rnd.Float64()
+		}
+		lower := []float64{-bound, -bound, -bound}
+		upper := []float64{bound, bound, bound}
+		kind := []VarKind{Integer, Integer, Integer}
+
+		// Find the solution by enumeration.
+		want := math.Inf(1)
+		x := make([]float64, n)
+		r := make([]float64, m)
+		for x[0] = -bound; x[0] <= bound; x[0]++ {
+			for x[1] = -bound; x[1] <= bound; x[1]++ {
+				for x[2] = -bound; x[2] <= bound; x[2]++ {
+					mat.NewVecDense(m, r).MulVec(g, mat.NewVecDense(n, x))
+					feasible := true
+					for i, v := range r {
+						if v > h[i] {
+							feasible = false
+						}
+					}
+					if feasible {
+						want = math.Min(want, floats.Dot(c, x))
+					}
+				}
+			}
+		}
+
+		// Solve the problem with the bounds of the variables, and with
+		// free variables and the bounds as inequality constraints.
+		gBound := mat.NewDense(m+2*n, n, nil)
+		gBound.Slice(0, m, 0, n).(*mat.Dense).Copy(g)
+		hBound := append([]float64(nil), h...)
+		for j := 0; j < n; j++ {
+			gBound.Set(m+2*j, j, 1)
+			gBound.Set(m+2*j+1, j, -1)
+			hBound = append(hBound, bound, bound)
+		}
+		for _, p := range []MILP{
+			{C: c, G: g, H: h, Lower: lower, Upper: upper, Kind: kind},
+			{
+				C: c, G: gBound, H: hBound,
+				Lower: []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)},
+				Kind:  kind,
+			},
+		} {
+			res, err := BranchAndBound(p, nil)
+			if math.IsInf(want, 1) {
+				if err != ErrInfeasible {
+					t.Errorf("trial %d: unexpected error for infeasible problem: %v", trial, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("trial %d: unexpected error: %v", trial, err)
+				continue
+			}
+			if !scalar.EqualWithinAbsOrRel(res.F, want, 1e-9, 1e-9) {
+				t.Errorf("trial %d: unexpected objective: got:%v want:%v", trial, res.F, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+func ExampleBranchAndBound() {
+	// Choose the items of greatest total value that fit in a knapsack
+	// of capacity 15. The values are negated to give a minimization.
+	values := []float64{-10, -13, -7, -8, -9, -4}
+	weights := mat.NewDense(1, 6, []float64{5, 7, 4, 5, 6, 3})
+	kind := make([]lp.VarKind, len(values))
+	for i := range kind {
+		kind[i] = lp.Binary
+	}
+
+	res, err := lp.BranchAndBound(lp.MILP{
+		C:    values,
+		G:    weights,
+		H:    []float64{15},
+		Kind: kind,
+	}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("value: %v\n", -res.F)
+	fmt.Printf("items: %v\n", res.X)
+	// Output:
+	// value: 27
+	// items: [1 1 0 0 0 1]
+}
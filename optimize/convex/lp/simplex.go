@@ -68,6 +68,13 @@ const (
 //
 // The Convert function can be used to transform a general LP into standard form.
 //
+// If A is a *mat.CSR or *mat.CSC, Simplex uses the revised simplex method with
+// a sparse LU factorization of the basis, and prices and ratio tests the
+// columns of A without forming A or the basis densely. This allows the
+// solution of large LPs whose constraint matrices are mostly zero. In this
+// case Dantzig's rule is used to choose the entering variable except after a
+// degenerate step, where Bland's rule is used to prevent cycling.
+//
 // The input matrix A must have at least as many columns as rows, len(c) must
 // equal the number of columns of A, and len(b) must equal the number of rows of
 // A or Simplex will panic. A must also have full row rank and may not contain any
@@ -89,6 +96,12 @@ func Simplex(c []float64, A mat.Matrix, b []float64, tol float64, initialBasic [
 }
 
 func simplex(initialBasic []int, c []float64, A mat.Matrix, b []float64, tol float64) (float64, []float64, []int, error) {
+	if indptr, ind, data, ok := compressedColumns(A); ok {
+		verifyShape(initialBasic, c, A, b)
+		m, n := A.Dims()
+		return simplexSparse(initialBasic, c, m, n, indptr, ind, data, b, tol)
+	}
+
 	err := verifyInputs(initialBasic, c, A, b)
 	if err != nil {
 		if err == ErrUnbounded {
@@ -380,7 +393,8 @@ func replaceBland(A mat.Matrix, ab *mat.Dense, xb []float64, basicIdxs, nonBasic
 	return -1, -1, ErrBland
 }
 
-func verifyInputs(initialBasic []int, c []float64, A mat.Matrix, b []float64) error {
+// verifyShape panics if the dimensions of the inputs are not consistent.
+func verifyShape(initialBasic []int, c []float64, A mat.Matrix, b []float64) {
 	m, n := A.Dims()
 	if m > n {
 		panic("lp: more equality constraints than variables")
@@ -397,6 +411,11 @@ func verifyInputs(initialBasic []int, c []float64, A mat.Matrix, b []float64) er
 	if len(initialBasic) != 0 && len(initialBasic) != m {
 		panic("lp: initialBasic incorrect length")
 	}
+}
+
+func verifyInputs(initialBasic []int, c []float64, A mat.Matrix, b []float64) error {
+	verifyShape(initialBasic, c, A, b)
+	m, n := A.Dims()
 
 	// Do some sanity checks so that ab does not become singular during the
 	// simplex solution. If the ZeroRow checks are removed then the code for
@@ -0,0 +1,714 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// sparsePivotTol is the smallest magnitude of an element of the
+	// entering column accepted as a pivot in the ratio test.
+	sparsePivotTol = 1e-9
+	// sparseFeasTol is the tolerance, relative to the size of b, on the sum
+	// of the artificial variables for the Phase I problem to be feasible.
+	sparseFeasTol = 1e-9
+	// sparseRefactor is the number of basis updates held in product form
+	// before the basis is refactorized.
+	sparseRefactor = 64
+)
+
+// compressedColumns returns the non-zero elements of A in compressed column
+// form if A is a sparse CSR or CSC matrix, and false otherwise.
+func compressedColumns(A mat.Matrix) (indptr, ind []int, data []float64, ok bool) {
+	switch A := A.(type) {
+	case *mat.CSC:
+		_, n := A.Dims()
+		ap, ai, ax := A.RawCSC()
+		indptr = make([]int, n+1)
+		for j := 0; j < n; j++ {
+			for p := ap[j]; p < ap[j+1]; p++ {
+				if ax[p] != 0 {
+					ind = append(ind, ai[p])
+					data = append(data, ax[p])
+				}
+			}
+			indptr[j+1] = len(ind)
+		}
+		return indptr, ind, data, true
+	case *mat.CSR:
+		m, n := A.Dims()
+		ap, aj, ax := A.RawCSR()
+		indptr = make([]int, n+1)
+		for p, j := range aj {
+			if ax[p] != 0 {
+				indptr[j+1]++
+			}
+		}
+		for j := 0; j < n; j++ {
+			indptr[j+1] += indptr[j]
+		}
+		next := make([]int, n)
+		copy(next, indptr)
+		ind = make([]int, indptr[n])
+		data = make([]float64, indptr[n])
+		for i := 0; i < m; i++ {
+			for p := ap[i]; p < ap[i+1]; p++ {
+				if ax[p] == 0 {
+					continue
+				}
+				j := aj[p]
+				ind[next[j]] = i
+				data[next[j]] = ax[p]
+				next[j]++
+			}
+		}
+		return indptr, ind, data, true
+	}
+	return nil, nil, nil, false
+}
+
+// sparseSimplex solves a standard form linear program with a sparse
+// constraint matrix using the revised simplex method. The basis matrix is
+// held as a sparse LU factorization updated in product form, so neither the
+// constraint matrix nor the basis is ever formed densely.
+//
+// The variables are numbered with the n columns of A first, followed by an
+// artificial variable for each of the m rows used to find an initial
+// feasible basis.
+type sparseSimplex struct {
+	m, n int
+
+	// The constraint matrix in compressed column form.
+	indptr, ind []int
+	data        []float64
+
+	// The artificial column for row i is sign[i] times the ith unit vector.
+	rows []int
+	sign []float64
+
+	b, c []float64
+	tol  float64
+
+	phaseI bool
+
+	basis []int     // The variable at each position of the basis.
+	pos   []int     // The basis position of each variable, or -1 if it is not basic.
+	xb    []float64 // The values of the basic variables.
+
+	lu   sparseLU
+	etas []sparseEta
+
+	// Workspace.
+	y, d []float64
+}
+
+// sparseEta is a basis update in product form. The update replaces the basis
+// column at position r, and d holds the representation of the entering column
+// in the previous basis.
+type sparseEta struct {
+	r   int
+	ind []int
+	val []float64
+	piv float64
+}
+
+// simplexSparse solves the standard form linear program with the compressed
+// column constraint matrix given by indptr, ind and data. The inputs have
+// already been checked for consistent dimensions.
+func simplexSparse(initialBasic []int, c []float64, m, n int, indptr, ind []int, data []float64, b []float64, tol float64) (float64, []float64, []int, error) {
+	// Check for empty rows and columns as is done for dense matrices.
+	rowCount := make([]int, m)
+	for _, i := range ind {
+		rowCount[i]++
+	}
+	for i, cnt := range rowCount {
+		if cnt == 0 {
+			if b[i] != 0 {
+				return math.NaN(), nil, nil, ErrInfeasible
+			}
+			return math.NaN(), nil, nil, ErrZeroRow
+		}
+	}
+	for j := 0; j < n; j++ {
+		if indptr[j] == indptr[j+1] {
+			if c[j] < 0 {
+				return math.Inf(-1), nil, nil, ErrUnbounded
+			}
+			return math.NaN(), nil, nil, ErrZeroColumn
+		}
+	}
+
+	s := &sparseSimplex{
+		m:      m,
+		n:      n,
+		indptr: indptr,
+		ind:    ind,
+		data:   data,
+		rows:   make([]int, m),
+		sign:   make([]float64, m),
+		b:      b,
+		c:      c,
+		tol:    math.Max(tol, rRoundTol),
+		basis:  make([]int, m),
+		pos:    make([]int, n+m),
+		xb:     make([]float64, m),
+		y:      make([]float64, m),
+		d:      make([]float64, m),
+	}
+	for i := range s.rows {
+		s.rows[i] = i
+		s.sign[i] = 1
+		if b[i] < 0 {
+			s.sign[i] = -1
+		}
+	}
+	for j := range s.pos {
+		s.pos[j] = -1
+	}
+
+	if initialBasic != nil {
+		copy(s.basis, initialBasic)
+		for k, j := range s.basis {
+			if s.pos[j] != -1 {
+				panic(errors.New("lp: subcolumns of A for supplied initial basic singular"))
+			}
+			s.pos[j] = k
+		}
+		if !s.refactor() {
+			panic(errors.New("lp: subcolumns of A for supplied initial basic singular"))
+		}
+		for _, v := range s.xb {
+			if v < -initPosTol {
+				panic(errors.New("lp: supplied subcolumns not a feasible solution"))
+			}
+		}
+	} else {
+		err := s.findInitialBasic()
+		if err != nil {
+			return math.NaN(), nil, nil, err
+		}
+	}
+
+	// Solve the Phase II problem.
+	err := s.iterate()
+	if err == ErrUnbounded {
+		return math.Inf(-1), nil, nil, ErrUnbounded
+	}
+	xopt := make([]float64, n)
+	for k, j := range s.basis {
+		xopt[j] = s.xb[k]
+	}
+	basicIdxs := make([]int, m)
+	copy(basicIdxs, s.basis)
+	return floats.Dot(c, xopt), xopt, basicIdxs, err
+}
+
+// findInitialBasic finds an initial feasible basis by solving the Phase I
+// problem
+//  minimize  1ᵀ x_a
+//  s.t.      A x + S x_a = b
+//            x, x_a >= 0
+// where S is diagonal with the signs of b. Rows of A with a single-element
+// column whose value can be made feasible start with that column in the
+// basis, and all other rows start with their artificial variable.
+func (s *sparseSimplex) findInitialBasic() error {
+	for i := range s.basis {
+		s.basis[i] = -1
+	}
+	for j := 0; j < s.n; j++ {
+		p := s.indptr[j]
+		if s.indptr[j+1]-p != 1 {
+			continue
+		}
+		i := s.ind[p]
+		if s.basis[i] == -1 && s.data[p]*s.b[i] >= 0 {
+			s.basis[i] = j
+			s.pos[j] = i
+		}
+	}
+	var artificial bool
+	for i, j := range s.basis {
+		if j == -1 {
+			s.basis[i] = s.n + i
+			s.pos[s.n+i] = i
+			artificial = true
+		}
+	}
+	if !s.refactor() {
+		return ErrSingular
+	}
+	if !artificial {
+		return nil
+	}
+
+	s.phaseI = true
+	err := s.iterate()
+	s.phaseI = false
+	if err != nil {
+		return err
+	}
+
+	var infeas float64
+	for k, j := range s.basis {
+		if j >= s.n {
+			infeas += math.Abs(s.xb[k])
+		}
+	}
+	if infeas > sparseFeasTol*(1+floats.Norm(s.b, math.Inf(1))) {
+		return ErrInfeasible
+	}
+
+	// Drive the remaining artificial variables, which are all zero, out of
+	// the basis. If no column of A can replace an artificial variable, the
+	// rows of A are linearly dependent.
+	for k := range s.basis {
+		if s.basis[k] < s.n {
+			continue
+		}
+		for i := range s.y {
+			s.y[i] = 0
+		}
+		s.y[k] = 1
+		s.btran(s.y)
+		enter := -1
+		best := sparsePivotTol
+		for j := 0; j < s.n; j++ {
+			if s.pos[j] != -1 {
+				continue
+			}
+			if v := math.Abs(s.dot(j, s.y)); v > best {
+				enter = j
+				best = v
+			}
+		}
+		if enter == -1 {
+			return ErrSingular
+		}
+		s.column(s.d, enter)
+		s.ftran(s.d)
+		s.pivot(k, enter, s.xb[k]/s.d[k])
+	}
+	return nil
+}
+
+// iterate performs simplex iterations from the current feasible basis until
+// no reduced cost is below the tolerance. Dantzig's rule is used to choose the
+// entering variable, except after degenerate steps where Bland's rule is used
+// to prevent cycling.
+func (s *sparseSimplex) iterate() error {
+	var bland bool
+	for {
+		if len(s.etas) >= sparseRefactor {
+			if !s.refactor() {
+				return ErrSingular
+			}
+		}
+
+		// Compute the simplex multipliers, y = B⁻ᵀ c_B, and find the
+		// entering variable from the reduced costs r_j = c_j - a_jᵀ y.
+		for k, j := range s.basis {
+			s.y[k] = s.cost(j)
+		}
+		s.btran(s.y)
+		enter := -1
+		best := -s.tol
+		for j := 0; j < s.n; j++ {
+			if s.pos[j] != -1 {
+				continue
+			}
+			r := s.cost(j) - s.dot(j, s.y)
+			if r < best {
+				enter = j
+				if bland {
+					break
+				}
+				best = r
+			}
+		}
+		if enter == -1 {
+			return nil
+		}
+
+		// Compute the change in the basic variables, d = B⁻¹ a_e, and find
+		// the leaving variable with the ratio test.
+		s.column(s.d, enter)
+		s.ftran(s.d)
+		leave := -1
+		theta := math.Inf(1)
+		for k, v := range s.d {
+			if v <= sparsePivotTol {
+				continue
+			}
+			t := math.Max(s.xb[k], 0) / v
+			switch {
+			case t < theta:
+			case t > theta || leave == -1:
+				continue
+			case bland:
+				if s.basis[k] > s.basis[leave] {
+					continue
+				}
+			default:
+				if v <= s.d[leave] {
+					continue
+				}
+			}
+			leave = k
+			theta = t
+		}
+		if leave == -1 {
+			return ErrUnbounded
+		}
+		s.pivot(leave, enter, theta)
+		bland = theta == 0
+	}
+}
+
+// pivot replaces the basic variable at position leave by the variable enter
+// taking the value theta, given the representation of the entering column in
+// the current basis held in s.d.
+func (s *sparseSimplex) pivot(leave, enter int, theta float64) {
+	for k, v := range s.d {
+		s.xb[k] -= theta * v
+	}
+	s.xb[leave] = theta
+	s.pos[s.basis[leave]] = -1
+	s.basis[leave] = enter
+	s.pos[enter] = leave
+
+	e := sparseEta{r: leave, piv: s.d[leave]}
+	for k, v := range s.d {
+		if v != 0 && k != leave {
+			e.ind = append(e.ind, k)
+			e.val = append(e.val, v)
+		}
+	}
+	s.etas = append(s.etas, e)
+}
+
+// refactor computes the LU factorization of the current basis, clears the
+// product form updates and recomputes the basic variables. It returns whether
+// the basis is non-singular.
+func (s *sparseSimplex) refactor() bool {
+	ok := s.lu.factorize(s.m, func(k int) ([]int, []float64) {
+		return s.columnView(s.basis[k])
+	})
+	if !ok {
+		return false
+	}
+	s.etas = s.etas[:0]
+	copy(s.xb, s.b)
+	s.ftran(s.xb)
+	return true
+}
+
+// ftran solves B x = b in place, where B is the current basis.
+func (s *sparseSimplex) ftran(x []float64) {
+	s.lu.solve(x)
+	for _, e := range s.etas {
+		xr := x[e.r] / e.piv
+		x[e.r] = xr
+		if xr == 0 {
+			continue
+		}
+		for p, i := range e.ind {
+			x[i] -= e.val[p] * xr
+		}
+	}
+}
+
+// btran solves Bᵀ x = b in place, where B is the current basis.
+func (s *sparseSimplex) btran(x []float64) {
+	for k := len(s.etas) - 1; k >= 0; k-- {
+		e := s.etas[k]
+		xr := x[e.r]
+		for p, i := range e.ind {
+			xr -= e.val[p] * x[i]
+		}
+		x[e.r] = xr / e.piv
+	}
+	s.lu.solveTrans(x)
+}
+
+// cost returns the objective coefficient of variable j in the current phase.
+func (s *sparseSimplex) cost(j int) float64 {
+	switch {
+	case j >= s.n:
+		if s.phaseI {
+			return 1
+		}
+		return 0
+	case s.phaseI:
+		return 0
+	default:
+		return s.c[j]
+	}
+}
+
+// columnView returns the row indices and values of the non-zero elements of
+// the column of variable j.
+func (s *sparseSimplex) columnView(j int) ([]int, []float64) {
+	if j >= s.n {
+		i := j - s.n
+		return s.rows[i : i+1], s.sign[i : i+1]
+	}
+	return s.ind[s.indptr[j]:s.indptr[j+1]], s.data[s.indptr[j]:s.indptr[j+1]]
+}
+
+// column stores the column of variable j into dst.
+func (s *sparseSimplex) column(dst []float64, j int) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	ind, data := s.columnView(j)
+	for p, i := range ind {
+		dst[i] = data[p]
+	}
+}
+
+// dot returns the dot product of the column of variable j with y.
+func (s *sparseSimplex) dot(j int, y []float64) float64 {
+	ind, data := s.columnView(j)
+	var v float64
+	for p, i := range ind {
+		v += data[p] * y[i]
+	}
+	return v
+}
+
+// sparseLU is a sparse LU factorization with partial pivoting,
+//  P B = L U,
+// of a square matrix B computed with the left-looking method of Gilbert and
+// Peierls.
+//  Gilbert, J. R. and Peierls, T. "Sparse partial pivoting in time
+//  proportional to arithmetic operations." SIAM Journal on Scientific and
+//  Statistical Computing 9.5 (1988): 862-874.
+// L is unit lower triangular with its diagonal stored first in each column,
+// and U is upper triangular with its diagonal stored last in each column. Both
+// are held in compressed column form with row indices in pivot order.
+type sparseLU struct {
+	n      int
+	lp, li []int
+	lx     []float64
+	up, ui []int
+	ux     []float64
+
+	// pinv[i] is the pivot order of row i of B.
+	pinv []int
+
+	// Workspace.
+	x                 []float64
+	xi, stack, pstack []int
+	mark              []int
+	stamp             int
+}
+
+// factorize computes the LU factorization of the n×n matrix whose kth column
+// has the non-zero elements returned by col(k). It returns whether the matrix
+// is non-singular.
+func (lu *sparseLU) factorize(n int, col func(k int) ([]int, []float64)) bool {
+	if lu.n != n {
+		lu.n = n
+		lu.lp = make([]int, n+1)
+		lu.up = make([]int, n+1)
+		lu.pinv = make([]int, n)
+		lu.x = make([]float64, n)
+		lu.xi = make([]int, n)
+		lu.stack = make([]int, n)
+		lu.pstack = make([]int, n)
+		lu.mark = make([]int, n)
+		lu.stamp = 0
+	}
+	lu.li = lu.li[:0]
+	lu.lx = lu.lx[:0]
+	lu.ui = lu.ui[:0]
+	lu.ux = lu.ux[:0]
+	for i := range lu.pinv {
+		lu.pinv[i] = -1
+	}
+	x := lu.x
+	for k := 0; k < n; k++ {
+		lu.lp[k] = len(lu.li)
+		lu.up[k] = len(lu.ui)
+
+		// Solve L x = B[:, k] using the non-zero pattern of x given by
+		// the rows reachable from the non-zero elements of B[:, k].
+		bi, bx := col(k)
+		top := lu.reach(bi)
+		for _, i := range lu.xi[top:] {
+			x[i] = 0
+		}
+		var colMax float64
+		for p, i := range bi {
+			x[i] = bx[p]
+			colMax = math.Max(colMax, math.Abs(bx[p]))
+		}
+		for _, j := range lu.xi[top:] {
+			jj := lu.pinv[j]
+			if jj < 0 {
+				continue
+			}
+			xj := x[j]
+			for p := lu.lp[jj] + 1; p < lu.lp[jj+1]; p++ {
+				x[lu.li[p]] -= lu.lx[p] * xj
+			}
+		}
+
+		// Choose the largest element in the rows that are not yet
+		// pivotal as the pivot.
+		ipiv := -1
+		var a float64
+		for _, i := range lu.xi[top:] {
+			if lu.pinv[i] < 0 {
+				if v := math.Abs(x[i]); v > a {
+					ipiv = i
+					a = v
+				}
+			} else {
+				lu.ui = append(lu.ui, lu.pinv[i])
+				lu.ux = append(lu.ux, x[i])
+			}
+		}
+		if ipiv == -1 || a <= 1e-12*colMax {
+			return false
+		}
+		pivot := x[ipiv]
+		lu.ui = append(lu.ui, k)
+		lu.ux = append(lu.ux, pivot)
+		lu.pinv[ipiv] = k
+		lu.li = append(lu.li, ipiv)
+		lu.lx = append(lu.lx, 1)
+		for _, i := range lu.xi[top:] {
+			if lu.pinv[i] < 0 {
+				lu.li = append(lu.li, i)
+				lu.lx = append(lu.lx, x[i]/pivot)
+			}
+			x[i] = 0
+		}
+	}
+	lu.lp[n] = len(lu.li)
+	lu.up[n] = len(lu.ui)
+	for p, i := range lu.li {
+		lu.li[p] = lu.pinv[i]
+	}
+	return true
+}
+
+// reach computes the rows reachable in the graph of the partial L factor
+// from the rows in bi. The rows are stored in topological order in
+// lu.xi[top:], and top is returned.
+func (lu *sparseLU) reach(bi []int) (top int) {
+	lu.stamp++
+	top = lu.n
+	for _, i := range bi {
+		if lu.mark[i] != lu.stamp {
+			top = lu.dfs(i, top)
+		}
+	}
+	return top
+}
+
+// dfs performs a non-recursive depth-first search from row j, storing the
+// rows in lu.xi below top as they are finished, and returns the new top.
+func (lu *sparseLU) dfs(j, top int) int {
+	head := 0
+	lu.stack[0] = j
+	for head >= 0 {
+		j = lu.stack[head]
+		jj := lu.pinv[j]
+		if lu.mark[j] != lu.stamp {
+			lu.mark[j] = lu.stamp
+			if jj >= 0 {
+				lu.pstack[head] = lu.lp[jj] + 1
+			}
+		}
+		done := true
+		if jj >= 0 {
+			end := lu.lp[jj+1]
+			for p := lu.pstack[head]; p < end; p++ {
+				i := lu.li[p]
+				if lu.mark[i] == lu.stamp {
+					continue
+				}
+				lu.pstack[head] = p
+				head++
+				lu.stack[head] = i
+				done = false
+				break
+			}
+		}
+		if done {
+			head--
+			top--
+			lu.xi[top] = j
+		}
+	}
+	return top
+}
+
+// solve solves B x = b in place.
+func (lu *sparseLU) solve(x []float64) {
+	n := lu.n
+	w := lu.x
+	for i, v := range x {
+		w[lu.pinv[i]] = v
+	}
+	for k := 0; k < n; k++ {
+		wk := w[k]
+		if wk == 0 {
+			continue
+		}
+		for p := lu.lp[k] + 1; p < lu.lp[k+1]; p++ {
+			w[lu.li[p]] -= lu.lx[p] * wk
+		}
+	}
+	for k := n - 1; k >= 0; k-- {
+		diag := lu.up[k+1] - 1
+		wk := w[k] / lu.ux[diag]
+		w[k] = wk
+		if wk == 0 {
+			continue
+		}
+		for p := lu.up[k]; p < diag; p++ {
+			w[lu.ui[p]] -= lu.ux[p] * wk
+		}
+	}
+	copy(x, w)
+	for i := range w {
+		w[i] = 0
+	}
+}
+
+// solveTrans solves Bᵀ x = b in place.
+func (lu *sparseLU) solveTrans(x []float64) {
+	n := lu.n
+	w := lu.x
+	for k := 0; k < n; k++ {
+		diag := lu.up[k+1] - 1
+		v := x[k]
+		for p := lu.up[k]; p < diag; p++ {
+			v -= lu.ux[p] * w[lu.ui[p]]
+		}
+		w[k] = v / lu.ux[diag]
+	}
+	for k := n - 1; k >= 0; k-- {
+		v := w[k]
+		for p := lu.lp[k] + 1; p < lu.lp[k+1]; p++ {
+			v -= lu.lx[p] * w[lu.li[p]]
+		}
+		w[k] = v
+	}
+	for i := range x {
+		x[i] = w[lu.pinv[i]]
+	}
+	for i := range w {
+		w[i] = 0
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSimplexSparse(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		nTest int
+		pZero float64
+		maxN  int
+	}{
+		{nTest: 5000, pZero: 0.7, maxN: 10},
+		{nTest: 5000, pZero: 0, maxN: 10},
+		{nTest: 100, pZero: 0.9, maxN: 100},
+		{nTest: 20, pZero: 0, maxN: 100},
+	} {
+		for i := 0; i < test.nTest; i++ {
+			n := rnd.Intn(test.maxN) + 2
+			m := rnd.Intn(n-1) + 1
+			randValue := func() float64 {
+				if rnd.Float64() < test.pZero {
+					return 0
+				}
+				return rnd.NormFloat64()
+			}
+			a := mat.NewDense(m, n, nil)
+			for i := 0; i < m; i++ {
+				for j := 0; j < n; j++ {
+					a.Set(i, j, randValue())
+				}
+			}
+			b := make([]float64, m)
+			for i := range b {
+				b[i] = randValue()
+			}
+			c := make([]float64, n)
+			for i := range c {
+				c[i] = randValue()
+			}
+
+			wantF, _, _, wantErr := simplex(nil, c, a, b, convergenceTol)
+			for _, sparse := range []mat.Matrix{mat.CSRCopyOf(a), mat.CSCCopyOf(a)} {
+				f, x, _, err := simplex(nil, c, sparse, b, convergenceTol)
+				testSimplexSparse(t, a, b, wantF, wantErr, f, x, err)
+			}
+		}
+	}
+}
+
+func testSimplexSparse(t *testing.T, a *mat.Dense, b []float64, wantF float64, wantErr error, f float64, x []float64, err error) {
+	switch wantErr {
+	case nil, ErrInfeasible, ErrUnbounded, ErrZeroRow, ErrZeroColumn:
+	default:
+		// The dense simplex failed, so there is nothing to compare.
+		return
+	}
+	if err != wantErr {
+		if err == ErrSingular {
+			// The problem is numerically rank deficient.
+			return
+		}
+		t.Errorf("unexpected error for %v: got %v, want %v", mat.Formatted(a), err, wantErr)
+		return
+	}
+	if err != nil {
+		return
+	}
+	for _, v := range x {
+		if v < -1e-10 {
+			t.Errorf("solution not non-negative: %v", x)
+			break
+		}
+	}
+	var bCheck mat.VecDense
+	bCheck.MulVec(a, mat.NewVecDense(len(x), x))
+	if !mat.EqualApprox(&bCheck, mat.NewVecDense(len(b), b), 1e-8) {
+		t.Errorf("solution infeasible")
+	}
+	if !scalar.EqualWithinAbsOrRel(f, wantF, 1e-8, 1e-8) {
+		t.Errorf("optimum mismatch: got %v, want %v", f, wantF)
+	}
+}
+
+func TestSimplexSparseInitialBasic(t *testing.T) {
+	t.Parallel()
+	// minimize -x0 - 2 x1
+	// s.t. x0 + x1 + s0 = 4
+	//      x0 + 3 x1 + s1 = 6
+	a := mat.NewDense(2, 4, []float64{
+		1, 1, 1, 0,
+		1, 3, 0, 1,
+	})
+	b := []float64{4, 6}
+	c := []float64{-1, -2, 0, 0}
+	for _, sparse := range []mat.Matrix{mat.CSRCopyOf(a), mat.CSCCopyOf(a)} {
+		for _, initialBasic := range [][]int{nil, {2, 3}} {
+			f, x, err := Simplex(c, sparse, b, 1e-10, initialBasic)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !scalar.EqualWithinAbsOrRel(f, -5, 1e-12, 1e-12) {
+				t.Errorf("unexpected optimum: got %v, want -5", f)
+			}
+			if !floats.EqualApprox(x, []float64{3, 1, 0, 0}, 1e-12) {
+				t.Errorf("unexpected solution: got %v, want [3 1 0 0]", x)
+			}
+		}
+	}
+}
+
+func TestSimplexSparseLarge(t *testing.T) {
+	t.Parallel()
+	// Find the maximum independent set of a path graph with n vertices,
+	//  maximize  1ᵀ x
+	//  s.t.      x_j + x_{j+1} <= 1, j = 0, ..., n-2
+	//            x >= 0.
+	// The graph is bipartite so the LP relaxation has the optimal value
+	// ⌈n/2⌉. The dense constraint matrix would need 400MB of storage.
+	const n = 5001
+	m := n - 1
+	aCOO := mat.NewCOO(m, n+m, nil, nil, nil)
+	for j := 0; j < m; j++ {
+		aCOO.Append(j, j, 1)
+		aCOO.Append(j, j+1, 1)
+		aCOO.Append(j, n+j, 1)
+	}
+	c := make([]float64, n+m)
+	for j := 0; j < n; j++ {
+		c[j] = -1
+	}
+	b := make([]float64, m)
+	for i := range b {
+		b[i] = 1
+	}
+	for _, a := range []mat.Matrix{aCOO.ToCSR(), aCOO.ToCSC()} {
+		f, x, err := Simplex(c, a, b, 1e-10, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := -math.Ceil(n / 2.0)
+		if !scalar.EqualWithinAbsOrRel(f, want, 1e-8, 1e-8) {
+			t.Errorf("unexpected optimum: got %v, want %v", f, want)
+		}
+		for j := 0; j < m; j++ {
+			if math.Abs(x[j]+x[j+1]+x[n+j]-1) > 1e-10 {
+				t.Errorf("constraint %d not satisfied", j)
+				break
+			}
+		}
+	}
+}
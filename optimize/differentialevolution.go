@@ -0,0 +1,397 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Statuser = (*DifferentialEvolution)(nil)
+	_ Method   = (*DifferentialEvolution)(nil)
+)
+
+// DifferentialEvolution implements the differential evolution global
+// optimization method of Storn and Price using the DE/rand/1/bin strategy.
+//  Storn, R. and Price, K. "Differential evolution - a simple and efficient
+//  heuristic for global optimization over continuous spaces." Journal of
+//  Global Optimization 11.4 (1997): 341-359.
+// Differential evolution maintains a population of candidate locations. At
+// each generation a trial location is formed for every member of the
+// population by adding the scaled difference of two random members to a third,
+// and crossing the result over with the member. The trial replaces the member
+// if its function value is no worse. The trial locations of a generation are
+// evaluated concurrently.
+//
+// Differential evolution does not use derivatives and is suited to multimodal
+// objectives where local methods converge to poor minima, at the expense of
+// many more function evaluations.
+type DifferentialEvolution struct {
+	// Population sets the population size. If Population is 0, a default
+	// value of 10*dim, but at least 4, is used. Population must be at least 4
+	// if it is not 0, or DifferentialEvolution will panic.
+	Population int
+	// Mutation is the differential weight scaling the difference of
+	// members added in the mutation step. If Mutation is 0, a default
+	// value of 0.8 is used. Mutation must be in (0, 2] if it is not 0, or
+	// DifferentialEvolution will panic.
+	Mutation float64
+	// Crossover is the probability that each element of a trial location
+	// is taken from the mutated location rather than from the member. If
+	// Crossover is 0, a default value of 0.9 is used. Crossover must be in
+	// [0, 1], or DifferentialEvolution will panic.
+	Crossover float64
+	// Lower and Upper are optional finite bounds on the locations. If both
+	// are non-nil, the initial population is drawn uniformly from the box
+	// they define, with the initial location projected onto the box as
+	// its first member, and trial locations are kept within the bounds.
+	// If both are nil, the first member of the initial population is the
+	// initial location and the others are drawn from a normal
+	// distribution around it with standard deviation InitStepSize.
+	// DifferentialEvolution panics if only one is nil, if their lengths do
+	// not match the dimension, or if any Lower is greater than Upper.
+	Lower, Upper []float64
+	// InitStepSize is the standard deviation of the initial population
+	// when no bounds are given. If InitStepSize is 0, a default value of 1
+	// is used. InitStepSize must not be negative.
+	InitStepSize float64
+	// StopSpread sets the threshold for stopping the optimization when the
+	// difference between the largest and smallest function values in the
+	// population is less than StopSpread. If StopSpread is 0, a default
+	// value of 1e-12 is used, and if it is NaN the criterion is not used.
+	StopSpread float64
+	// Src allows a random number generator to be supplied for generating
+	// samples. If Src is nil the generator in golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	dim, pop  int
+	mutation  float64
+	crossover float64
+
+	intn        func(int) int
+	float64     func() float64
+	normFloat64 func() float64
+
+	// Population and trial data.
+	xs, trials *mat.Dense
+	fs, trialF []float64
+	started    bool
+
+	// Synchronization.
+	sentIdx     int
+	receivedIdx int
+	operation   chan<- Task
+}
+
+func (de *DifferentialEvolution) Status() (Status, error) {
+	if de.converged() {
+		return MethodConverge, nil
+	}
+	return NotTerminated, nil
+}
+
+func (*DifferentialEvolution) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (de *DifferentialEvolution) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	de.dim = dim
+	de.pop = de.Population
+	switch {
+	case de.pop == 0:
+		de.pop = 10 * dim
+		if de.pop < 4 {
+			de.pop = 4
+		}
+	case de.pop < 4:
+		panic("differential evolution: population too small")
+	}
+	de.mutation = de.Mutation
+	switch {
+	case de.mutation == 0:
+		de.mutation = 0.8
+	case de.mutation < 0 || de.mutation > 2:
+		panic("differential evolution: mutation out of range")
+	}
+	de.crossover = de.Crossover
+	switch {
+	case de.crossover == 0:
+		de.crossover = 0.9
+	case de.crossover < 0 || de.crossover > 1:
+		panic("differential evolution: crossover out of range")
+	}
+	if de.InitStepSize < 0 {
+		panic("differential evolution: negative initial step size")
+	}
+	if (de.Lower == nil) != (de.Upper == nil) {
+		panic("differential evolution: only one bound specified")
+	}
+	if de.Lower != nil {
+		if len(de.Lower) != dim || len(de.Upper) != dim {
+			panic("differential evolution: bound length mismatch")
+		}
+		for i, l := range de.Lower {
+			if !(l <= de.Upper[i]) || math.IsInf(l, 0) || math.IsInf(de.Upper[i], 0) {
+				panic("differential evolution: invalid bounds")
+			}
+		}
+	}
+
+	if de.Src == nil {
+		de.intn = rand.Intn
+		de.float64 = rand.Float64
+		de.normFloat64 = rand.NormFloat64
+	} else {
+		rnd := rand.New(de.Src)
+		de.intn = rnd.Intn
+		de.float64 = rnd.Float64
+		de.normFloat64 = rnd.NormFloat64
+	}
+
+	de.xs = mat.NewDense(de.pop, dim, nil)
+	de.trials = mat.NewDense(de.pop, dim, nil)
+	de.fs = resize(de.fs, de.pop)
+	de.trialF = resize(de.trialF, de.pop)
+	de.started = false
+
+	de.sentIdx = 0
+	de.receivedIdx = 0
+	de.operation = nil
+	return min(tasks, de.pop)
+}
+
+// initPopulation fills the trials with the initial population.
+func (de *DifferentialEvolution) initPopulation(x []float64) {
+	step := de.InitStepSize
+	if step == 0 {
+		step = 1
+	}
+	for i := 0; i < de.pop; i++ {
+		row := de.trials.RawRowView(i)
+		for j := range row {
+			switch {
+			case i == 0 && de.Lower != nil:
+				row[j] = math.Min(math.Max(x[j], de.Lower[j]), de.Upper[j])
+			case i == 0:
+				row[j] = x[j]
+			case de.Lower != nil:
+				row[j] = de.Lower[j] + de.float64()*(de.Upper[j]-de.Lower[j])
+			default:
+				row[j] = x[j] + step*de.normFloat64()
+			}
+		}
+		de.fs[i] = math.Inf(1)
+		de.trialF[i] = math.NaN()
+	}
+}
+
+// mutate fills the trials with the mutated and crossed-over locations of the
+// next generation.
+func (de *DifferentialEvolution) mutate() {
+	for i := 0; i < de.pop; i++ {
+		// Choose three distinct members other than i.
+		r1 := de.intn(de.pop - 1)
+		if r1 >= i {
+			r1++
+		}
+		r2, r3 := r1, r1
+		for r2 == i || r2 == r1 {
+			r2 = de.intn(de.pop)
+		}
+		for r3 == i || r3 == r1 || r3 == r2 {
+			r3 = de.intn(de.pop)
+		}
+		x := de.xs.RawRowView(i)
+		a := de.xs.RawRowView(r1)
+		b := de.xs.RawRowView(r2)
+		c := de.xs.RawRowView(r3)
+		trial := de.trials.RawRowView(i)
+		jrand := de.intn(de.dim)
+		for j := range trial {
+			if j != jrand && de.float64() >= de.crossover {
+				trial[j] = x[j]
+				continue
+			}
+			v := a[j] + de.mutation*(b[j]-c[j])
+			if de.Lower != nil {
+				// Move elements outside the bounds to the midpoint
+				// between the member and the bound.
+				switch {
+				case v < de.Lower[j]:
+					v = (x[j] + de.Lower[j]) / 2
+				case v > de.Upper[j]:
+					v = (x[j] + de.Upper[j]) / 2
+				}
+			}
+			trial[j] = v
+		}
+		de.trialF[i] = math.NaN()
+	}
+}
+
+// selectMembers replaces the members of the population by trial locations
+// with lower or equal function values. The first generation replaces the
+// whole population.
+func (de *DifferentialEvolution) selectMembers() {
+	if !de.started {
+		de.xs.Copy(de.trials)
+		for i, f := range de.trialF {
+			if math.IsNaN(f) {
+				f = math.Inf(1)
+			}
+			de.fs[i] = f
+		}
+		return
+	}
+	for i, f := range de.trialF {
+		if f <= de.fs[i] {
+			de.fs[i] = f
+			de.xs.SetRow(i, de.trials.RawRowView(i))
+		}
+	}
+}
+
+// bestIdx returns the index of the member of the population with the lowest
+// function value, or -1 if no member has a finite function value.
+func (de *DifferentialEvolution) bestIdx() int {
+	best := -1
+	bestVal := math.Inf(1)
+	for i, v := range de.fs {
+		if v < bestVal {
+			best = i
+			bestVal = v
+		}
+	}
+	return best
+}
+
+func (de *DifferentialEvolution) converged() bool {
+	if !de.started {
+		return false
+	}
+	stop := de.StopSpread
+	switch {
+	case math.IsNaN(stop):
+		return false
+	case stop == 0:
+		stop = 1e-12
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range de.fs {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return hi-lo < stop
+}
+
+func (de *DifferentialEvolution) sendInitTasks(tasks []Task) {
+	for i, task := range tasks {
+		de.sendTask(i, task)
+	}
+	de.sentIdx = len(tasks)
+}
+
+// sendTask sends the trial location idx for evaluation.
+func (de *DifferentialEvolution) sendTask(idx int, task Task) {
+	task.ID = idx
+	task.Op = FuncEvaluation
+	copy(task.X, de.trials.RawRowView(idx))
+	de.operation <- task
+}
+
+func (de *DifferentialEvolution) Run(operations chan<- Task, results <-chan Task, tasks []Task) {
+	de.initPopulation(tasks[0].X)
+	de.operation = operations
+	de.sendInitTasks(tasks)
+
+Loop:
+	for {
+		result := <-results
+		switch result.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			de.mutate()
+			de.sendInitTasks(tasks)
+		case FuncEvaluation:
+			de.receivedIdx++
+			de.trialF[result.ID] = result.F
+			switch {
+			case de.sentIdx < de.pop:
+				// There are still trials to evaluate. Send the next.
+				de.sendTask(de.sentIdx, result)
+				de.sentIdx++
+			case de.receivedIdx < de.pop:
+				// Wait until all of the trials are evaluated.
+				continue Loop
+			default:
+				de.receivedIdx = 0
+				de.sentIdx = 0
+				de.selectMembers()
+				de.started = true
+
+				task := result
+				task.ID = -1
+				best := de.bestIdx()
+				if best == -1 {
+					task.F = math.Inf(1)
+					copy(task.X, de.xs.RawRowView(0))
+				} else {
+					task.F = de.fs[best]
+					copy(task.X, de.xs.RawRowView(best))
+				}
+				task.Op = MajorIteration
+				if de.converged() {
+					task.Op = MethodDone
+				}
+				operations <- task
+			}
+		}
+	}
+
+	// Been told to stop. Collect the outstanding evaluations and send a
+	// final MajorIteration if one of them improves on the population.
+	for task := range results {
+		switch task.Op {
+		case MajorIteration:
+		case FuncEvaluation:
+			de.trialF[task.ID] = task.F
+		default:
+			panic("unknown operation")
+		}
+	}
+	bestF := math.Inf(1)
+	if best := de.bestIdx(); best != -1 {
+		bestF = de.fs[best]
+	}
+	bestTrial := -1
+	for i, f := range de.trialF {
+		if f < bestF {
+			bestTrial = i
+			bestF = f
+		}
+	}
+	if bestTrial != -1 {
+		task := tasks[0]
+		task.F = bestF
+		copy(task.X, de.trials.RawRowView(bestTrial))
+		task.Op = MajorIteration
+		task.ID = -1
+		operations <- task
+	}
+	close(operations)
+}
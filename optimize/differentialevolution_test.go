@@ -0,0 +1,127 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestDifferentialEvolution(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		dim      int
+		problem  Problem
+		method   *DifferentialEvolution
+		initX    []float64
+		settings *Settings
+		good     func(result *Result, err error) error
+	}{
+		{
+			// Test that the global minimum of a multimodal function
+			// is found from a local minimum.
+			dim:     3,
+			problem: Problem{Func: functions.Rastrigin{}.Func},
+			method: &DifferentialEvolution{
+				Lower: []float64{-5.12, -5.12, -5.12},
+				Upper: []float64{5.12, 5.12, 5.12},
+			},
+			initX:    []float64{2, 2, 2},
+			settings: &Settings{Converger: NeverTerminate{}},
+			good: func(result *Result, err error) error {
+				if result.Status != MethodConverge {
+					return errors.New("result not method converge")
+				}
+				if !floats.EqualApprox(result.X, []float64{0, 0, 0}, 1e-6) {
+					return errors.New("global minimum not found")
+				}
+				return nil
+			},
+		},
+		{
+			// Test an unbounded problem.
+			dim:      2,
+			problem:  Problem{Func: functions.ExtendedRosenbrock{}.Func},
+			method:   &DifferentialEvolution{},
+			initX:    []float64{-1.2, 1},
+			settings: &Settings{Converger: NeverTerminate{}},
+			good: func(result *Result, err error) error {
+				if result.Status != MethodConverge {
+					return errors.New("result not method converge")
+				}
+				if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-4) {
+					return errors.New("minimum not found")
+				}
+				return nil
+			},
+		},
+		{
+			// Test that the locations stay within the bounds when the
+			// unconstrained minimum is outside them.
+			dim: 2,
+			problem: Problem{Func: func(x []float64) float64 {
+				return (x[0]-10)*(x[0]-10) + x[1]*x[1]
+			}},
+			method: &DifferentialEvolution{
+				Lower: []float64{-1, -1},
+				Upper: []float64{1, 1},
+			},
+			initX:    []float64{5, 5},
+			settings: &Settings{Converger: NeverTerminate{}},
+			good: func(result *Result, err error) error {
+				if result.X[0] > 1 || result.X[1] > 1 || result.X[0] < -1 || result.X[1] < -1 {
+					return errors.New("minimum outside bounds")
+				}
+				if !floats.EqualApprox(result.X, []float64{1, 0}, 1e-5) {
+					return errors.New("bounded minimum not found")
+				}
+				return nil
+			},
+		},
+		{
+			// Test that the iteration limit is respected.
+			dim:     4,
+			problem: Problem{Func: functions.ExtendedRosenbrock{}.Func},
+			method:  &DifferentialEvolution{Population: 20},
+			settings: &Settings{
+				MajorIterations: 10,
+				Converger:       NeverTerminate{},
+			},
+			good: func(result *Result, err error) error {
+				if result.Status != IterationLimit {
+					return errors.New("result not iteration limit")
+				}
+				// There may be one more from the final update.
+				if result.MajorIterations < 10 || result.MajorIterations > 11 {
+					return errors.New("wrong number of iterations")
+				}
+				return nil
+			},
+		},
+	} {
+		for _, concurrent := range []int{0, 5} {
+			method := test.method
+			method.Src = rand.NewSource(1)
+			initX := test.initX
+			if initX == nil {
+				initX = make([]float64, test.dim)
+			}
+			test.settings.Concurrent = concurrent
+			result, err := Minimize(test.problem, initX, test.settings, method)
+			if err != nil {
+				t.Errorf("case %d concurrent=%d: unexpected error: %v", i, concurrent, err)
+				continue
+			}
+			if testErr := test.good(result, err); testErr != nil {
+				t.Errorf("case %d concurrent=%d: %v", i, concurrent, testErr)
+			}
+		}
+	}
+}
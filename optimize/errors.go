@@ -32,6 +32,11 @@ var (
 	// lies out of allowed bounds.
 	ErrLinesearcherBound = errors.New("linesearch: step out of bounds")
 
+	// ErrQPFailure signifies that a quadratic programming subproblem of a
+	// constrained optimization method could not be solved. This may occur
+	// if the linearized constraints are inconsistent.
+	ErrQPFailure = errors.New("optimize: quadratic subproblem failed to converge")
+
 	// ErrMissingGrad signifies that a Method requires a Gradient function that
 	// is not supplied by Problem.
 	ErrMissingGrad = errors.New("optimize: problem does not provide needed Grad function")
@@ -0,0 +1,7 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package leastsq implements routines to solve nonlinear least squares
+// problems, such as fitting the parameters of a model to data.
+package leastsq // import "gonum.org/v1/gonum/optimize/leastsq"
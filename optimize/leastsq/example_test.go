@@ -0,0 +1,40 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leastsq_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/optimize/leastsq"
+)
+
+func ExampleLevenbergMarquardt() {
+	// Fit the model y = a*exp(-b*t) to observations.
+	t := []float64{0, 0.5, 1, 1.5, 2, 2.5, 3}
+	y := []float64{5.1, 3.0, 1.9, 1.1, 0.72, 0.41, 0.26}
+
+	p := leastsq.Problem{
+		M: len(t),
+		Func: func(dst, x []float64) {
+			for i, ti := range t {
+				dst[i] = x[0]*math.Exp(-x[1]*ti) - y[i]
+			}
+		},
+		// The Jacobian is approximated by finite differences
+		// since Jac is nil.
+	}
+	result, err := leastsq.LevenbergMarquardt(p, []float64{1, 1}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("a = %.3f, b = %.3f\n", result.X[0], result.X[1])
+	fmt.Printf("cost = %.5f\n", result.F)
+
+	// Output:
+	// a = 5.075, b = 1.006
+	// cost = 0.00484
+}
@@ -0,0 +1,298 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leastsq
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+const (
+	defaultIterations = 100
+	defaultTolerance  = 1e-10
+	defaultDamping    = 1e-3
+)
+
+// ErrNonFinite is returned when the residuals at the initial location are not
+// all finite.
+var ErrNonFinite = errors.New("leastsq: non-finite residuals at initial location")
+
+// Problem describes a nonlinear least squares problem
+//  minimize F(x) = 1/2 * \sum_i r_i(x)^2
+// where r: ℝⁿ → ℝᵐ is the vector of residuals, for example the differences
+// between the predictions of a model with parameters x and the observed data.
+type Problem struct {
+	// M is the number of residuals. M must be positive.
+	M int
+
+	// Func evaluates the residuals at x and stores the result in dst,
+	// which will have length M. Func must not modify x.
+	Func func(dst, x []float64)
+
+	// Jac evaluates the M×n Jacobian of the residuals at x and stores the
+	// result in dst. Jac must not modify x. If Jac is nil, the Jacobian is
+	// approximated by finite differences.
+	Jac func(dst *mat.Dense, x []float64)
+}
+
+// Settings holds the settings of LevenbergMarquardt. The zero value gives the
+// default settings.
+type Settings struct {
+	// Loss is the robust loss applied to the squared residuals. If Loss
+	// is nil, the squared residuals are summed directly.
+	Loss Loss
+
+	// Iterations is the maximum number of iterations. If Iterations is 0,
+	// a default value of 100 is used.
+	Iterations int
+
+	// GradientThreshold, StepTolerance and FunctionTolerance control the
+	// termination of the iterations. The iterations stop when the infinity
+	// norm of the gradient of the cost is less than GradientThreshold, when
+	// the norm of the step is less than StepTolerance relative to the norm
+	// of the location, or when the decrease in the cost of an accepted step
+	// is less than FunctionTolerance relative to the cost. The default value
+	// of each, used if it is 0, is 1e-10.
+	GradientThreshold float64
+	StepTolerance     float64
+	FunctionTolerance float64
+
+	// InitDamping is the initial damping parameter relative to the largest
+	// diagonal element of JᵀJ. If InitDamping is 0, a default value of
+	// 1e-3 is used. Larger values give shorter initial steps, which may
+	// be needed if the initial location is far from the solution.
+	InitDamping float64
+
+	// Jacobian holds the settings of the finite difference approximation of
+	// the Jacobian used when Problem.Jac is nil. It may be nil.
+	Jacobian *fd.JacobianSettings
+}
+
+// Result holds the solution of a nonlinear least squares problem.
+type Result struct {
+	// X is the location of the solution and F is the cost at X.
+	X []float64
+	F float64
+
+	// Residuals holds the residuals at X.
+	Residuals []float64
+
+	// Status indicates the criterion that terminated the iterations.
+	Status optimize.Status
+
+	Iterations      int
+	FuncEvaluations int
+	JacEvaluations  int
+}
+
+// LevenbergMarquardt solves the nonlinear least squares problem p starting from
+// initX using the Levenberg-Marquardt method. At each iteration the step h is
+// the solution of the damped Gauss-Newton equations
+//  (JᵀJ + μI) h = -Jᵀr,
+// and the damping parameter μ is decreased when the step gives a good
+// decrease in the cost relative to the decrease predicted by the linear model
+// of the residuals and increased otherwise, as described in
+//  Madsen, K., Nielsen, H. B. and Tingleff, O. "Methods for non-linear least
+//  squares problems." Technical University of Denmark (2004), section 3.2.
+// The damping acts as an implicit trust region on the Gauss-Newton step.
+//
+// If settings.Loss is not nil, the residuals and the Jacobian are rescaled at
+// each iteration so that the Gauss-Newton model matches the curvature of the
+// robust cost, following
+//  Triggs, B. et al. "Bundle adjustment - a modern synthesis." International
+//  Workshop on Vision Algorithms (1999): 298-372.
+//
+// If settings is nil, the default settings are used. LevenbergMarquardt
+// returns ErrNonFinite if the residuals at initX are not finite. It panics if
+// p.M is not positive, p.Func is nil or initX has zero length.
+func LevenbergMarquardt(p Problem, initX []float64, settings *Settings) (*Result, error) {
+	if p.M <= 0 {
+		panic("leastsq: non-positive number of residuals")
+	}
+	if p.Func == nil {
+		panic("leastsq: nil residual function")
+	}
+	n := len(initX)
+	if n == 0 {
+		panic("leastsq: zero dimensional input")
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	iterations := settings.Iterations
+	if iterations == 0 {
+		iterations = defaultIterations
+	}
+	gradThresh := defaultOr(settings.GradientThreshold, defaultTolerance)
+	stepTol := defaultOr(settings.StepTolerance, defaultTolerance)
+	funcTol := defaultOr(settings.FunctionTolerance, defaultTolerance)
+	damping := defaultOr(settings.InitDamping, defaultDamping)
+
+	m := p.M
+	res := &Result{
+		X:         make([]float64, n),
+		Residuals: make([]float64, m),
+	}
+	copy(res.X, initX)
+	residuals := func(dst, x []float64) float64 {
+		res.FuncEvaluations++
+		p.Func(dst, x)
+		return cost(dst, settings.Loss)
+	}
+	jac := mat.NewDense(m, n, nil)
+	jacobian := func(x, r []float64) {
+		res.JacEvaluations++
+		if p.Jac != nil {
+			p.Jac(jac, x)
+			return
+		}
+		var fdSettings fd.JacobianSettings
+		if settings.Jacobian != nil {
+			fdSettings = *settings.Jacobian
+		}
+		fdSettings.OriginValue = r
+		fd.Jacobian(jac, p.Func, x, &fdSettings)
+	}
+
+	res.F = residuals(res.Residuals, res.X)
+	if math.IsNaN(res.F) || math.IsInf(res.F, 0) {
+		return nil, ErrNonFinite
+	}
+
+	var (
+		a     mat.SymDense
+		chol  mat.Cholesky
+		damp  = mat.NewSymDense(n, nil)
+		g     = mat.NewVecDense(n, nil)
+		h     = mat.NewVecDense(n, nil)
+		sr    = mat.NewVecDense(m, nil)
+		xNew  = make([]float64, n)
+		rNew  = make([]float64, m)
+		mu    float64
+		nu    = 2.0
+		fresh = true
+	)
+	res.Status = optimize.IterationLimit
+	for res.Iterations < iterations {
+		if fresh {
+			// Form the normal equations at the current location.
+			jacobian(res.X, res.Residuals)
+			scaleRobust(jac, sr, res.Residuals, settings.Loss)
+			a.SymOuterK(1, jac.T())
+			g.MulVec(jac.T(), sr)
+			if mat.Norm(g, math.Inf(1)) < gradThresh {
+				res.Status = optimize.GradientThreshold
+				break
+			}
+			if res.Iterations == 0 {
+				var maxDiag float64
+				for i := 0; i < n; i++ {
+					maxDiag = math.Max(maxDiag, a.At(i, i))
+				}
+				mu = damping * maxDiag
+				if mu == 0 {
+					mu = damping
+				}
+			}
+			fresh = false
+		}
+		res.Iterations++
+
+		// Solve the damped normal equations for the step.
+		damp.CopySym(&a)
+		for i := 0; i < n; i++ {
+			damp.SetSym(i, i, damp.At(i, i)+mu)
+		}
+		if !chol.Factorize(damp) {
+			mu *= nu
+			nu *= 2
+			continue
+		}
+		if err := chol.SolveVecTo(h, g); err != nil {
+			mu *= nu
+			nu *= 2
+			continue
+		}
+		h.ScaleVec(-1, h)
+		if mat.Norm(h, 2) <= stepTol*(floats.Norm(res.X, 2)+stepTol) {
+			res.Status = optimize.StepConvergence
+			break
+		}
+
+		// Compare the actual and predicted decreases in the cost.
+		for i, v := range res.X {
+			xNew[i] = v + h.AtVec(i)
+		}
+		fNew := residuals(rNew, xNew)
+		var predicted float64
+		for i := 0; i < n; i++ {
+			predicted += h.AtVec(i) * (mu*h.AtVec(i) - g.AtVec(i))
+		}
+		predicted /= 2
+		gain := (res.F - fNew) / predicted
+		if !(gain > 0) {
+			mu *= nu
+			nu *= 2
+			continue
+		}
+		decrease := res.F - fNew
+		copy(res.X, xNew)
+		copy(res.Residuals, rNew)
+		res.F = fNew
+		mu *= math.Max(1.0/3, 1-math.Pow(2*gain-1, 3))
+		nu = 2
+		fresh = true
+		if decrease <= funcTol*res.F {
+			res.Status = optimize.FunctionConvergence
+			break
+		}
+	}
+	return res, nil
+}
+
+func defaultOr(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// cost returns the cost of the residuals r with the given loss.
+func cost(r []float64, loss Loss) float64 {
+	var f float64
+	for _, v := range r {
+		s := v * v
+		if loss != nil {
+			s, _, _ = loss.Loss(s)
+		}
+		f += s
+	}
+	return f / 2
+}
+
+// scaleRobust stores in dst the residuals r and scales the rows of the
+// Jacobian jac so that the Gauss-Newton model of the cost with the given loss
+// has the gradient and approximate curvature of the robust cost.
+func scaleRobust(jac *mat.Dense, dst *mat.VecDense, r []float64, loss Loss) {
+	for i, v := range r {
+		if loss == nil {
+			dst.SetVec(i, v)
+			continue
+		}
+		s := v * v
+		_, d1, d2 := loss.Loss(s)
+		// Clamp the scale of the Jacobian to keep the model convex where
+		// the loss has negative curvature.
+		js := math.Sqrt(math.Max(d1+2*d2*s, 1e-15))
+		dst.SetVec(i, v*d1/js)
+		row := jac.RawRowView(i)
+		floats.Scale(js, row)
+	}
+}
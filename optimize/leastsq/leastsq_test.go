@@ -0,0 +1,191 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leastsq
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// expModel returns the residuals and Jacobian of fitting y = a*exp(b*t) to
+// the data.
+func expModel(t, y []float64) (func(dst, x []float64), func(dst *mat.Dense, x []float64)) {
+	f := func(dst, x []float64) {
+		for i, ti := range t {
+			dst[i] = x[0]*math.Exp(x[1]*ti) - y[i]
+		}
+	}
+	jac := func(dst *mat.Dense, x []float64) {
+		for i, ti := range t {
+			e := math.Exp(x[1] * ti)
+			dst.Set(i, 0, e)
+			dst.Set(i, 1, x[0]*ti*e)
+		}
+	}
+	return f, jac
+}
+
+// lineModel returns the residuals and Jacobian of fitting y = a + b*t to the
+// data.
+func lineModel(t, y []float64) (func(dst, x []float64), func(dst *mat.Dense, x []float64)) {
+	f := func(dst, x []float64) {
+		for i, ti := range t {
+			dst[i] = x[0] + x[1]*ti - y[i]
+		}
+	}
+	jac := func(dst *mat.Dense, x []float64) {
+		for i, ti := range t {
+			dst.Set(i, 0, 1)
+			dst.Set(i, 1, ti)
+		}
+	}
+	return f, jac
+}
+
+func TestLevenbergMarquardt(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// Data without noise.
+	ts := make([]float64, 20)
+	ys := make([]float64, len(ts))
+	for i := range ts {
+		ts[i] = float64(i) / 4
+		ys[i] = 2.5 * math.Exp(-1.3*ts[i])
+	}
+	expF, expJac := expModel(ts, ys)
+
+	// Data on a line with noise and gross outliers.
+	tl := make([]float64, 50)
+	yl := make([]float64, len(tl))
+	for i := range tl {
+		tl[i] = float64(i) / 10
+		yl[i] = 1 + 2*tl[i] + 0.01*rnd.NormFloat64()
+		if i%10 == 3 {
+			yl[i] += 20
+		}
+	}
+	lineF, lineJac := lineModel(tl, yl)
+
+	rosenbrock := Problem{
+		M: 2,
+		Func: func(dst, x []float64) {
+			dst[0] = 10 * (x[1] - x[0]*x[0])
+			dst[1] = 1 - x[0]
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			dst.Set(0, 0, -20*x[0])
+			dst.Set(0, 1, 10)
+			dst.Set(1, 0, -1)
+			dst.Set(1, 1, 0)
+		},
+	}
+
+	for _, test := range []struct {
+		name     string
+		problem  Problem
+		initX    []float64
+		settings *Settings
+		want     []float64
+		tol      float64
+	}{
+		{
+			name:    "exponential",
+			problem: Problem{M: len(ts), Func: expF, Jac: expJac},
+			initX:   []float64{1, 0},
+			want:    []float64{2.5, -1.3},
+			tol:     1e-8,
+		},
+		{
+			name:    "exponential finite difference",
+			problem: Problem{M: len(ts), Func: expF},
+			initX:   []float64{1, 0},
+			want:    []float64{2.5, -1.3},
+			tol:     1e-6,
+		},
+		{
+			name:    "rosenbrock",
+			problem: rosenbrock,
+			initX:   []float64{-1.2, 1},
+			want:    []float64{1, 1},
+			tol:     1e-8,
+		},
+		{
+			name:     "huber",
+			problem:  Problem{M: len(tl), Func: lineF, Jac: lineJac},
+			initX:    []float64{0, 0},
+			settings: &Settings{Loss: HuberLoss{Scale: 0.1}},
+			want:     []float64{1, 2},
+			tol:      0.05,
+		},
+		{
+			name:     "soft l1",
+			problem:  Problem{M: len(tl), Func: lineF, Jac: lineJac},
+			initX:    []float64{0, 0},
+			settings: &Settings{Loss: SoftL1Loss{Scale: 0.1}},
+			want:     []float64{1, 2},
+			tol:      0.05,
+		},
+		{
+			name:     "cauchy",
+			problem:  Problem{M: len(tl), Func: lineF, Jac: lineJac},
+			initX:    []float64{0, 0},
+			settings: &Settings{Loss: CauchyLoss{Scale: 0.1}},
+			want:     []float64{1, 2},
+			tol:      0.01,
+		},
+	} {
+		result, err := LevenbergMarquardt(test.problem, test.initX, test.settings)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status.Early() {
+			t.Errorf("%s: unexpected early status: %v", test.name, result.Status)
+		}
+		if !floats.EqualApprox(result.X, test.want, test.tol) {
+			t.Errorf("%s: unexpected solution: got:%v want:%v", test.name, result.X, test.want)
+		}
+		r := make([]float64, test.problem.M)
+		test.problem.Func(r, result.X)
+		if !floats.Equal(r, result.Residuals) {
+			t.Errorf("%s: residuals do not match solution", test.name)
+		}
+		if result.JacEvaluations == 0 || result.FuncEvaluations < result.Iterations {
+			t.Errorf("%s: unexpected evaluation counts: %+v", test.name, result)
+		}
+	}
+
+	// Check that the outliers bias the least squares solution, so that
+	// the robust tests are meaningful.
+	result, err := LevenbergMarquardt(Problem{M: len(tl), Func: lineF, Jac: lineJac}, []float64{0, 0}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if floats.EqualApprox(result.X, []float64{1, 2}, 0.5) {
+		t.Errorf("outliers did not bias least squares solution: %v", result.X)
+	}
+
+	// Check the iteration limit.
+	result, err = LevenbergMarquardt(rosenbrock, []float64{-1.2, 1}, &Settings{Iterations: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != optimize.IterationLimit || result.Iterations != 2 {
+		t.Errorf("unexpected iteration limit behavior: status=%v iterations=%d", result.Status, result.Iterations)
+	}
+
+	// Check that non-finite initial residuals are reported.
+	_, err = LevenbergMarquardt(Problem{M: 1, Func: func(dst, x []float64) { dst[0] = math.NaN() }}, []float64{0}, nil)
+	if err != ErrNonFinite {
+		t.Errorf("unexpected error for non-finite residuals: got:%v want:%v", err, ErrNonFinite)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leastsq
+
+import "math"
+
+// Loss is a robust loss function ρ applied to the squared residuals of a least
+// squares problem, so that the minimized cost is
+//  F(x) = 1/2 * \sum_i ρ(r_i(x)^2).
+// Robust losses grow more slowly than the squared residual for large
+// residuals, reducing the influence of outliers on the solution. A loss must
+// satisfy ρ(0) = 0 and ρ'(0) = 1, so that it is close to the squared loss for
+// small residuals.
+type Loss interface {
+	// Loss returns the value and the first and second derivatives of ρ
+	// at the squared residual s.
+	Loss(s float64) (rho, d1, d2 float64)
+}
+
+// scaled returns the value and derivatives at s of the loss c^2 * ρ(s/c^2).
+func scaled(s, c float64, rho func(z float64) (float64, float64, float64)) (float64, float64, float64) {
+	c2 := c * c
+	v, d1, d2 := rho(s / c2)
+	return c2 * v, d1, d2 / c2
+}
+
+// HuberLoss is the Huber loss, which is quadratic for residuals smaller in
+// magnitude than Scale and linear for larger residuals,
+//  ρ(s) = s                       if s <= Scale^2,
+//  ρ(s) = 2*Scale*sqrt(s) - Scale^2 otherwise.
+type HuberLoss struct {
+	// Scale is the magnitude of the residuals at which the loss
+	// becomes linear. Scale must be positive.
+	Scale float64
+}
+
+// Loss returns the value and the first and second derivatives of the loss at s.
+func (l HuberLoss) Loss(s float64) (rho, d1, d2 float64) {
+	return scaled(s, l.Scale, func(z float64) (float64, float64, float64) {
+		if z <= 1 {
+			return z, 1, 0
+		}
+		sqrtz := math.Sqrt(z)
+		return 2*sqrtz - 1, 1 / sqrtz, -0.5 / (z * sqrtz)
+	})
+}
+
+// SoftL1Loss is a smooth approximation of the absolute value loss,
+//  ρ(s) = 2*Scale^2*(sqrt(1 + s/Scale^2) - 1).
+type SoftL1Loss struct {
+	// Scale is the magnitude of the residuals at which the loss
+	// becomes approximately linear. Scale must be positive.
+	Scale float64
+}
+
+// Loss returns the value and the first and second derivatives of the loss at s.
+func (l SoftL1Loss) Loss(s float64) (rho, d1, d2 float64) {
+	return scaled(s, l.Scale, func(z float64) (float64, float64, float64) {
+		t := 1 + z
+		sqrtt := math.Sqrt(t)
+		return 2 * (sqrtt - 1), 1 / sqrtt, -0.5 / (t * sqrtt)
+	})
+}
+
+// CauchyLoss is the Cauchy, or Lorentzian, loss,
+//  ρ(s) = Scale^2*log(1 + s/Scale^2).
+// The influence of a residual on the solution decreases for residuals larger
+// in magnitude than Scale, so CauchyLoss is suited to data with gross
+// outliers, at the expense of introducing local minima.
+type CauchyLoss struct {
+	// Scale is the magnitude of the residuals at which the influence
+	// of a residual is largest. Scale must be positive.
+	Scale float64
+}
+
+// Loss returns the value and the first and second derivatives of the loss at s.
+func (l CauchyLoss) Loss(s float64) (rho, d1, d2 float64) {
+	return scaled(s, l.Scale, func(z float64) (float64, float64, float64) {
+		t := 1 + z
+		return math.Log1p(z), 1 / t, -1 / (t * t)
+	})
+}
@@ -0,0 +1,44 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leastsq
+
+import (
+	"fmt"
+	"testing"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestLossDerivatives(t *testing.T) {
+	t.Parallel()
+	for _, loss := range []Loss{
+		HuberLoss{Scale: 1},
+		HuberLoss{Scale: 0.3},
+		SoftL1Loss{Scale: 1},
+		SoftL1Loss{Scale: 2.5},
+		CauchyLoss{Scale: 1},
+		CauchyLoss{Scale: 0.5},
+	} {
+		name := fmt.Sprintf("%#v", loss)
+		rho, d1, _ := loss.Loss(0)
+		if rho != 0 || d1 != 1 {
+			t.Errorf("%s: unexpected behavior at zero: ρ(0)=%v ρ'(0)=%v", name, rho, d1)
+		}
+		for _, s := range []float64{0.01, 0.5, 2, 7} {
+			_, d1, d2 := loss.Loss(s)
+			f := func(s float64) float64 { v, _, _ := loss.Loss(s); return v }
+			df := func(s float64) float64 { _, v, _ := loss.Loss(s); return v }
+			want1 := fd.Derivative(f, s, &fd.Settings{Formula: fd.Central})
+			want2 := fd.Derivative(df, s, &fd.Settings{Formula: fd.Central})
+			if !scalar.EqualWithinAbsOrRel(d1, want1, 1e-6, 1e-6) {
+				t.Errorf("%s: unexpected first derivative at %v: got:%v want:%v", name, s, d1, want1)
+			}
+			if !scalar.EqualWithinAbsOrRel(d2, want2, 1e-6, 1e-6) {
+				t.Errorf("%s: unexpected second derivative at %v: got:%v want:%v", name, s, d2, want2)
+			}
+		}
+	}
+}
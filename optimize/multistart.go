@@ -0,0 +1,181 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+const (
+	defaultMultiStarts         = 20
+	defaultMultiStartTolerance = 1e-4
+)
+
+// MultiStart is a global optimizer that runs local minimizations from many
+// starting locations concurrently and collects the distinct minima found. It
+// makes use of multiple cores for nonconvex problems where a single local
+// minimization may converge to a poor minimum.
+type MultiStart struct {
+	// Starts is the number of local minimizations. If Starts is 0, a
+	// default value of 20 is used. Starts must not be negative.
+	Starts int
+	// Sampler generates the starting locations after the first, which is
+	// the initial location passed to Minimize. Sampler must not be nil.
+	Sampler distmv.Rander
+	// Concurrent is the number of local minimizations run at the same
+	// time. If Concurrent is 0, runtime.GOMAXPROCS(0) is used.
+	Concurrent int
+
+	// NewMethod returns the method for a local minimization. Methods hold
+	// state, so NewMethod must return a new value at each call. If
+	// NewMethod is nil, the default method of Minimize is used.
+	NewMethod func() Method
+	// NewSettings returns the settings for a local minimization. Settings
+	// may hold state in their Converger and Recorder, so NewSettings must
+	// return a new value at each call. If NewSettings is nil, the default
+	// settings of Minimize are used.
+	NewSettings func() *Settings
+
+	// Tolerance is the distance in the infinity norm within which two
+	// minima are considered the same. If Tolerance is 0, a default value of
+	// 1e-4 is used.
+	Tolerance float64
+}
+
+// MultiStartMinimum is a distinct minimum found by MultiStart.
+type MultiStartMinimum struct {
+	Location
+	// Count is the number of local minimizations that converged to the
+	// minimum.
+	Count int
+}
+
+// MultiStartResult holds the result of MultiStart.
+type MultiStartResult struct {
+	// Minima holds the distinct minima found in order of increasing
+	// function value. The location of each minimum is the location with
+	// the lowest function value of the local minimizations that converged
+	// to it.
+	Minima []MultiStartMinimum
+	// Unconverged is the number of local minimizations that did not
+	// converge, either because they returned an error or because they
+	// terminated early, as indicated by Status.Early. Their locations are
+	// not included in Minima.
+	Unconverged int
+	// Stats holds the sum of the statistics of the local minimizations,
+	// and the total runtime.
+	Stats
+}
+
+// Minimize runs the local minimizations of the problem from initX and from
+// starting locations drawn from the Sampler. If a local minimization returns
+// a nil Result, Minimize returns its error after all of the minimizations
+// have finished. Minimize panics if the Sampler is nil.
+func (ms MultiStart) Minimize(p Problem, initX []float64) (*MultiStartResult, error) {
+	startTime := time.Now()
+	if ms.Sampler == nil {
+		panic("multistart: nil sampler")
+	}
+	starts := ms.Starts
+	switch {
+	case starts == 0:
+		starts = defaultMultiStarts
+	case starts < 0:
+		panic("multistart: negative number of starts")
+	}
+	concurrent := ms.Concurrent
+	if concurrent == 0 {
+		concurrent = runtime.GOMAXPROCS(0)
+	}
+	tol := ms.Tolerance
+	if tol == 0 {
+		tol = defaultMultiStartTolerance
+	}
+
+	// Draw the starting locations before starting the minimizations
+	// since the Sampler may not be safe for concurrent use.
+	xs := make([][]float64, starts)
+	xs[0] = make([]float64, len(initX))
+	copy(xs[0], initX)
+	for i := 1; i < starts; i++ {
+		xs[i] = ms.Sampler.Rand(nil)
+		if len(xs[i]) != len(initX) {
+			panic("multistart: sampler dimension mismatch")
+		}
+	}
+
+	results := make([]*Result, starts)
+	errs := make([]error, starts)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrent, starts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var method Method
+				if ms.NewMethod != nil {
+					method = ms.NewMethod()
+				}
+				var settings *Settings
+				if ms.NewSettings != nil {
+					settings = ms.NewSettings()
+				}
+				results[i], errs[i] = Minimize(p, xs[i], settings, method)
+			}
+		}()
+	}
+	for i := range xs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	res := &MultiStartResult{}
+	var converged []int
+	for i, r := range results {
+		if r == nil {
+			return nil, errs[i]
+		}
+		res.MajorIterations += r.MajorIterations
+		res.FuncEvaluations += r.FuncEvaluations
+		res.GradEvaluations += r.GradEvaluations
+		res.HessEvaluations += r.HessEvaluations
+		if errs[i] != nil || r.Status.Early() || math.IsNaN(r.F) {
+			res.Unconverged++
+			continue
+		}
+		converged = append(converged, i)
+	}
+
+	// Group the minima, starting from the lowest, so that each group is
+	// represented by its best location.
+	sort.SliceStable(converged, func(a, b int) bool {
+		return results[converged[a]].F < results[converged[b]].F
+	})
+	for _, i := range converged {
+		loc := results[i].Location
+		found := false
+		for k := range res.Minima {
+			if floats.Distance(res.Minima[k].X, loc.X, math.Inf(1)) <= tol {
+				res.Minima[k].Count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.Minima = append(res.Minima, MultiStartMinimum{Location: loc, Count: 1})
+		}
+	}
+	res.Runtime = time.Since(startTime)
+	return res, nil
+}
@@ -0,0 +1,111 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/optimize/functions"
+	"gonum.org/v1/gonum/spatial/r1"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestMultiStart(t *testing.T) {
+	t.Parallel()
+	camel := Problem{
+		Func: functions.CamelSix{}.Func,
+		Grad: func(grad, x []float64) {
+			x0, x1 := x[0], x[1]
+			grad[0] = 8*x0 - 8.4*x0*x0*x0 + 2*x0*x0*x0*x0*x0 + x1
+			grad[1] = x0 - 8*x1 + 16*x1*x1*x1
+		},
+	}
+	for _, test := range []struct {
+		name      string
+		problem   Problem
+		bounds    []r1.Interval
+		initX     []float64
+		newMethod func() Method
+		// want holds the global minima.
+		want [][]float64
+		f    float64
+		// maxMinima is the number of minima of the function.
+		maxMinima int
+	}{
+		{
+			name:      "camel six",
+			problem:   camel,
+			bounds:    []r1.Interval{{Min: -3, Max: 3}, {Min: -2, Max: 2}},
+			initX:     []float64{1, 1},
+			newMethod: func() Method { return &BFGS{} },
+			want:      [][]float64{{0.0898, -0.7126}, {-0.0898, 0.7126}},
+			f:         -1.0316,
+			maxMinima: 6,
+		},
+		{
+			name:    "branin",
+			problem: Problem{Func: functions.BraninHoo{}.Func},
+			bounds:  []r1.Interval{{Min: -5, Max: 10}, {Min: 0, Max: 15}},
+			initX:   []float64{0, 0},
+			want:    [][]float64{{-math.Pi, 12.275}, {math.Pi, 2.275}, {9.424778, 2.475}},
+			f:       0.397887,
+			// There is a fourth global minimum at (5π, 12.875) outside
+			// the domain of the starting locations.
+			maxMinima: 4,
+		},
+	} {
+		for _, concurrent := range []int{1, 4} {
+			ms := MultiStart{
+				Starts:     40,
+				Sampler:    distmv.NewUniform(test.bounds, rand.NewSource(1)),
+				Concurrent: concurrent,
+				NewMethod:  test.newMethod,
+				Tolerance:  1e-3,
+			}
+			res, err := ms.Minimize(test.problem, test.initX)
+			if err != nil {
+				t.Errorf("%s concurrent=%d: unexpected error: %v", test.name, concurrent, err)
+				continue
+			}
+			if len(res.Minima) < len(test.want) || len(res.Minima) > test.maxMinima {
+				t.Errorf("%s concurrent=%d: unexpected number of minima: %d", test.name, concurrent, len(res.Minima))
+				continue
+			}
+			var count int
+			for i, m := range res.Minima {
+				count += m.Count
+				if i > 0 && m.F < res.Minima[i-1].F {
+					t.Errorf("%s concurrent=%d: minima not sorted", test.name, concurrent)
+				}
+			}
+			if count+res.Unconverged != ms.Starts {
+				t.Errorf("%s concurrent=%d: unexpected number of runs: got:%d want:%d", test.name, concurrent, count+res.Unconverged, ms.Starts)
+			}
+			if !scalar.EqualWithinAbs(res.Minima[0].F, test.f, 1e-4) {
+				t.Errorf("%s concurrent=%d: unexpected global minimum value: got:%v want:%v", test.name, concurrent, res.Minima[0].F, test.f)
+			}
+			for _, want := range test.want {
+				found := false
+				for _, m := range res.Minima {
+					if floats.EqualApprox(m.X, want, 1e-3) && scalar.EqualWithinAbs(m.F, test.f, 1e-4) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("%s concurrent=%d: global minimum %v not found", test.name, concurrent, want)
+				}
+			}
+			if res.FuncEvaluations == 0 {
+				t.Errorf("%s concurrent=%d: statistics not collected", test.name, concurrent)
+			}
+		}
+	}
+}
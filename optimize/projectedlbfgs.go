@@ -0,0 +1,327 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method      = (*ProjectedLBFGS)(nil)
+	_ localMethod = (*ProjectedLBFGS)(nil)
+)
+
+// ProjectedLBFGS implements a projected limited-memory BFGS method for
+// gradient-based minimization subject to bound constraints
+//  Lower[i] <= x[i] <= Upper[i].
+// It is a simpler alternative to the L-BFGS-B method of Byrd et al. for the
+// same class of problems.
+//
+// At each iteration the variables at a bound whose gradient points out of the
+// feasible box are held fixed, the search direction for the remaining
+// variables is computed with the L-BFGS two-loop recursion, and a backtracking
+// linesearch along the projection of the direction onto the box finds a step
+// satisfying the Armijo condition. If the initial location is outside the
+// bounds, it is first projected onto them. The method terminates when the
+// infinity norm of the projected gradient is below GradStopThreshold, and
+// returns GradientThreshold status in that case.
+//  Kim, D., Sra, S. and Dhillon, I. S. "Tackling box-constrained optimization
+//  via a new projected quasi-Newton approach." SIAM Journal on Scientific
+//  Computing 32.6 (2010): 3548-3563.
+type ProjectedLBFGS struct {
+	// Lower and Upper are the bounds on the variables. If Lower or Upper
+	// is nil, the variables are unbounded below or above, respectively.
+	// Otherwise its length must match the dimension of the problem, and
+	// elements may be infinite to leave individual variables unbounded.
+	// Lower must not be greater than Upper for any variable.
+	Lower, Upper []float64
+	// Store is the size of the limited-memory storage.
+	// If Store is 0, it will be defaulted to 15.
+	Store int
+	// GradStopThreshold sets the threshold for stopping if the projected
+	// gradient norm gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	dim  int
+	x    []float64 // Location at the last major iteration
+	f    float64   // Function value at the last major iteration
+	grad []float64 // Gradient at the last major iteration
+	dir  []float64 // Search direction from x
+	step float64   // Current step size along dir
+
+	projecting bool // Whether the last evaluation was of the projected initial location
+
+	// History of the limited memory, stored circularly.
+	oldest, stored int
+	y, s           [][]float64
+	rho, a         []float64
+}
+
+func (p *ProjectedLBFGS) Status() (Status, error) {
+	return p.status, p.err
+}
+
+func (*ProjectedLBFGS) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (p *ProjectedLBFGS) Init(dim, tasks int) int {
+	p.status = NotTerminated
+	p.err = nil
+	return 1
+}
+
+func (p *ProjectedLBFGS) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	// The gradient convergence test of localOptimizer does not account
+	// for the bounds, so it is disabled and performed in iterateLocal.
+	status, err := localOptimizer{}.run(p, math.NaN(), operation, result, tasks)
+	if p.status == NotTerminated {
+		p.status, p.err = status, err
+	}
+	close(operation)
+}
+
+func (p *ProjectedLBFGS) initLocal(loc *Location) (Operation, error) {
+	dim := len(loc.X)
+	if p.Lower != nil && len(p.Lower) != dim {
+		panic("projectedlbfgs: lower bound length mismatch")
+	}
+	if p.Upper != nil && len(p.Upper) != dim {
+		panic("projectedlbfgs: upper bound length mismatch")
+	}
+	for i := 0; i < dim; i++ {
+		if p.lower(i) > p.upper(i) {
+			panic("projectedlbfgs: lower bound greater than upper bound")
+		}
+	}
+	if p.Store == 0 {
+		p.Store = 15
+	}
+
+	p.dim = dim
+	p.x = resize(p.x, dim)
+	p.grad = resize(p.grad, dim)
+	p.dir = resize(p.dir, dim)
+	p.a = resize(p.a, p.Store)
+	p.rho = resize(p.rho, p.Store)
+	p.y = p.initHistory(p.y)
+	p.s = p.initHistory(p.s)
+	p.oldest = 0
+	p.stored = 0
+	p.step = 0
+
+	copy(p.x, loc.X)
+	p.project(p.x)
+	if !floats.Equal(p.x, loc.X) {
+		// Evaluate the function at the projected initial location
+		// before starting the iterations.
+		copy(loc.X, p.x)
+		p.projecting = true
+		return FuncEvaluation | GradEvaluation, nil
+	}
+	p.projecting = false
+	p.f = loc.F
+	copy(p.grad, loc.Gradient)
+	return p.nextIteration(loc)
+}
+
+func (p *ProjectedLBFGS) initHistory(hist [][]float64) [][]float64 {
+	c := cap(hist)
+	if c < p.Store {
+		n := make([][]float64, p.Store-c)
+		hist = append(hist[:c], n...)
+	}
+	hist = hist[:p.Store]
+	for i := range hist {
+		hist[i] = resize(hist[i], p.dim)
+	}
+	return hist
+}
+
+func (p *ProjectedLBFGS) iterateLocal(loc *Location) (Operation, error) {
+	if p.projecting {
+		p.projecting = false
+		p.f = loc.F
+		copy(p.grad, loc.Gradient)
+		return MajorIteration, nil
+	}
+	if p.step == 0 {
+		// The previous operation was a MajorIteration.
+		return p.nextIteration(loc)
+	}
+
+	// loc holds the evaluation at a trial step. Check the Armijo
+	// condition along the projected path.
+	var decrease float64
+	for i, v := range loc.X {
+		decrease += p.grad[i] * (v - p.x[i])
+	}
+	if loc.F <= p.f+defaultBacktrackingDecrease*decrease && decrease < 0 {
+		p.update(loc)
+		p.step = 0
+		return MajorIteration, nil
+	}
+	p.step *= defaultBacktrackingContraction
+	if p.step < minimumBacktrackingStepSize {
+		return NoOperation, ErrLinesearcherFailure
+	}
+	p.trial(loc.X)
+	if floats.Equal(loc.X, p.x) {
+		return NoOperation, ErrNoProgress
+	}
+	return FuncEvaluation | GradEvaluation, nil
+}
+
+// nextIteration checks the projected gradient for convergence at the current
+// location and otherwise starts the linesearch along a new search direction.
+func (p *ProjectedLBFGS) nextIteration(loc *Location) (Operation, error) {
+	thresh := p.GradStopThreshold
+	if thresh == 0 {
+		thresh = defaultGradientAbsTol
+	}
+	var norm float64
+	for i, g := range p.grad {
+		if !p.bound(i, g) {
+			norm = math.Max(norm, math.Abs(g))
+		}
+	}
+	if norm < thresh {
+		p.status = GradientThreshold
+		return MethodDone, nil
+	}
+
+	p.direction()
+	p.step = 1
+	if p.stored == 0 {
+		p.step = 1 / floats.Norm(p.dir, 2)
+	}
+	p.trial(loc.X)
+	return FuncEvaluation | GradEvaluation, nil
+}
+
+// direction computes the search direction in p.dir. The variables held at
+// their bounds have zero direction, and the direction for the others is given
+// by the two-loop recursion, falling back to steepest descent if it is not a
+// descent direction.
+func (p *ProjectedLBFGS) direction() {
+	for i, g := range p.grad {
+		p.dir[i] = g
+		if p.bound(i, g) {
+			p.dir[i] = 0
+		}
+	}
+	if p.stored > 0 {
+		// Uses two-loop correction as described in
+		// Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006), chapter 7, page 178.
+		newest := (p.oldest + p.stored - 1) % p.Store
+		for i := 0; i < p.stored; i++ {
+			idx := (newest - i + p.Store) % p.Store
+			p.a[idx] = p.rho[idx] * floats.Dot(p.s[idx], p.dir)
+			floats.AddScaled(p.dir, -p.a[idx], p.y[idx])
+		}
+		gamma := 1 / (p.rho[newest] * floats.Dot(p.y[newest], p.y[newest]))
+		floats.Scale(gamma, p.dir)
+		for i := 0; i < p.stored; i++ {
+			idx := (p.oldest + i) % p.Store
+			beta := p.rho[idx] * floats.Dot(p.y[idx], p.dir)
+			floats.AddScaled(p.dir, p.a[idx]-beta, p.s[idx])
+		}
+	}
+	floats.Scale(-1, p.dir)
+
+	// Remove the components that would immediately leave the box, and
+	// check that the remaining direction is a descent direction.
+	var slope float64
+	for i, d := range p.dir {
+		if p.bound(i, p.grad[i]) || (d < 0 && p.x[i] <= p.lower(i)) || (d > 0 && p.x[i] >= p.upper(i)) {
+			p.dir[i] = 0
+			continue
+		}
+		slope += d * p.grad[i]
+	}
+	if slope < 0 {
+		return
+	}
+	for i, g := range p.grad {
+		p.dir[i] = -g
+		if p.bound(i, g) {
+			p.dir[i] = 0
+		}
+	}
+}
+
+// trial stores in x the projection onto the bounds of the current location
+// moved by the current step along the search direction.
+func (p *ProjectedLBFGS) trial(x []float64) {
+	for i, v := range p.x {
+		x[i] = v + p.step*p.dir[i]
+	}
+	p.project(x)
+}
+
+// update accepts the trial location in loc, adding the change in location
+// and gradient to the history if it satisfies the curvature condition.
+func (p *ProjectedLBFGS) update(loc *Location) {
+	idx := (p.oldest + p.stored) % p.Store
+	s := p.s[idx]
+	y := p.y[idx]
+	floats.SubTo(s, loc.X, p.x)
+	floats.SubTo(y, loc.Gradient, p.grad)
+	sDotY := floats.Dot(s, y)
+	if sDotY > 1e-10*floats.Dot(y, y) {
+		p.rho[idx] = 1 / sDotY
+		if p.stored < p.Store {
+			p.stored++
+		} else {
+			p.oldest = (p.oldest + 1) % p.Store
+		}
+	}
+	copy(p.x, loc.X)
+	copy(p.grad, loc.Gradient)
+	p.f = loc.F
+}
+
+// bound returns whether variable i is at a bound with the gradient g pointing
+// out of the feasible box.
+func (p *ProjectedLBFGS) bound(i int, g float64) bool {
+	return (p.x[i] <= p.lower(i) && g > 0) || (p.x[i] >= p.upper(i) && g < 0)
+}
+
+// project projects x onto the bounds.
+func (p *ProjectedLBFGS) project(x []float64) {
+	for i, v := range x {
+		x[i] = math.Min(math.Max(v, p.lower(i)), p.upper(i))
+	}
+}
+
+func (p *ProjectedLBFGS) lower(i int) float64 {
+	if p.Lower == nil {
+		return math.Inf(-1)
+	}
+	return p.Lower[i]
+}
+
+func (p *ProjectedLBFGS) upper(i int) float64 {
+	if p.Upper == nil {
+		return math.Inf(1)
+	}
+	return p.Upper[i]
+}
+
+func (*ProjectedLBFGS) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
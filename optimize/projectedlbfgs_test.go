@@ -0,0 +1,152 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestProjectedLBFGSUnbounded(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name  string
+		p     Problem
+		x     []float64
+		want  []float64
+		store int
+	}{
+		{
+			name: "ExtendedRosenbrock",
+			p:    Problem{Func: functions.ExtendedRosenbrock{}.Func, Grad: functions.ExtendedRosenbrock{}.Grad},
+			x:    []float64{-1.2, 1},
+			want: []float64{1, 1},
+		},
+		{
+			name:  "ExtendedRosenbrock small store",
+			p:     Problem{Func: functions.ExtendedRosenbrock{}.Func, Grad: functions.ExtendedRosenbrock{}.Grad},
+			x:     []float64{-1.2, 1, -1.2, 1, -1.2, 1},
+			want:  []float64{1, 1, 1, 1, 1, 1},
+			store: 3,
+		},
+		{
+			name: "Beale",
+			p:    Problem{Func: functions.Beale{}.Func, Grad: functions.Beale{}.Grad},
+			x:    []float64{1, 1},
+			want: []float64{3, 0.5},
+		},
+	} {
+		method := &ProjectedLBFGS{Store: test.store, GradStopThreshold: 1e-10}
+		result, err := Minimize(test.p, test.x, &Settings{Converger: NeverTerminate{}}, method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-8) {
+			t.Errorf("%s: unexpected minimum: got %v, want %v", test.name, result.X, test.want)
+		}
+	}
+}
+
+func TestProjectedLBFGSBounded(t *testing.T) {
+	t.Parallel()
+	// The minimum of the Rosenbrock function restricted to x₀ ≤ 0.5 lies
+	// on the bound.
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func, Grad: functions.ExtendedRosenbrock{}.Grad}
+	method := &ProjectedLBFGS{
+		Lower: []float64{math.Inf(-1), -2},
+		Upper: []float64{0.5, 2},
+	}
+	result, err := Minimize(p, []float64{-1.2, 1}, &Settings{Converger: NeverTerminate{}}, method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != GradientThreshold {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, GradientThreshold)
+	}
+	want := []float64{0.5, 0.25}
+	if !floats.EqualApprox(result.X, want, 1e-10) {
+		t.Errorf("unexpected minimum: got %v, want %v", result.X, want)
+	}
+
+	// A convex quadratic ½xᵀAx - bᵀx with the unconstrained minimum
+	// outside the box, started from an infeasible location.
+	rnd := rand.New(rand.NewSource(1))
+	const dim = 8
+	var a mat.SymDense
+	m := mat.NewDense(dim, dim, nil)
+	for i := range m.RawMatrix().Data {
+		m.RawMatrix().Data[i] = rnd.NormFloat64()
+	}
+	a.SymOuterK(1, m)
+	for i := 0; i < dim; i++ {
+		a.SetSym(i, i, a.At(i, i)+1)
+	}
+	b := make([]float64, dim)
+	lower := make([]float64, dim)
+	upper := make([]float64, dim)
+	x0 := make([]float64, dim)
+	for i := range b {
+		b[i] = 10 * rnd.NormFloat64()
+		lower[i] = -1
+		upper[i] = 1
+		x0[i] = 3 * rnd.NormFloat64()
+	}
+	quad := Problem{
+		Func: func(x []float64) float64 {
+			xv := mat.NewVecDense(dim, x)
+			return 0.5*mat.Inner(xv, &a, xv) - floats.Dot(b, x)
+		},
+		Grad: func(grad, x []float64) {
+			g := mat.NewVecDense(dim, grad)
+			g.MulVec(&a, mat.NewVecDense(dim, x))
+			floats.Sub(grad, b)
+		},
+	}
+	method = &ProjectedLBFGS{Lower: lower, Upper: upper, Store: 5, GradStopThreshold: 1e-9}
+	result, err = Minimize(quad, x0, &Settings{Converger: NeverTerminate{}}, method)
+	if err != nil {
+		t.Fatalf("unexpected error for quadratic: %v", err)
+	}
+	if result.Status != GradientThreshold {
+		t.Errorf("unexpected status for quadratic: got %v, want %v", result.Status, GradientThreshold)
+	}
+	// Check the optimality conditions for the bound-constrained problem.
+	grad := make([]float64, dim)
+	quad.Grad(grad, result.X)
+	var active int
+	for i, x := range result.X {
+		switch {
+		case x < lower[i] || x > upper[i]:
+			t.Errorf("solution outside bounds at %d: %v", i, x)
+		case x == lower[i]:
+			active++
+			if grad[i] < 0 {
+				t.Errorf("gradient points into box at lower bound %d: %v", i, grad[i])
+			}
+		case x == upper[i]:
+			active++
+			if grad[i] > 0 {
+				t.Errorf("gradient points into box at upper bound %d: %v", i, grad[i])
+			}
+		default:
+			if math.Abs(grad[i]) > 1e-9 {
+				t.Errorf("nonzero gradient at free variable %d: %v", i, grad[i])
+			}
+		}
+	}
+	if active == 0 {
+		t.Errorf("bad test: no active bounds at solution")
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+var _ Method = (*SimulatedAnnealing)(nil)
+
+// AnnealingSchedule gives the temperature of a simulated annealing process.
+type AnnealingSchedule interface {
+	// Temperature returns the temperature at iteration k, where the
+	// first iteration is 0. The returned temperature must be positive.
+	Temperature(k int) float64
+}
+
+// ExponentialSchedule is an AnnealingSchedule where the temperature decreases
+// geometrically,
+//  T(k) = Initial * Rate^k.
+type ExponentialSchedule struct {
+	// Initial is the temperature at the first iteration.
+	Initial float64
+	// Rate is the factor by which the temperature decreases at each
+	// iteration. Rate must be in (0, 1].
+	Rate float64
+}
+
+// Temperature returns the temperature at iteration k.
+func (s ExponentialSchedule) Temperature(k int) float64 {
+	return s.Initial * math.Pow(s.Rate, float64(k))
+}
+
+// LogarithmicSchedule is an AnnealingSchedule where the temperature decreases
+// as the inverse logarithm of the iteration,
+//  T(k) = Initial * log(2) / log(k+2).
+// Logarithmic cooling is slow, but is the schedule for which simulated
+// annealing is guaranteed to converge to a global minimum in probability.
+type LogarithmicSchedule struct {
+	// Initial is the temperature at the first iteration.
+	Initial float64
+}
+
+// Temperature returns the temperature at iteration k.
+func (s LogarithmicSchedule) Temperature(k int) float64 {
+	return s.Initial * math.Ln2 / math.Log(float64(k)+2)
+}
+
+// CauchySchedule is an AnnealingSchedule where the temperature decreases as
+// the inverse of the iteration,
+//  T(k) = Initial / (k+1).
+// It is the schedule of fast annealing, where it is used with a Cauchy
+// distribution of proposals.
+//  Szu, H. and Hartley, R. "Fast simulated annealing." Physics Letters A
+//  122.3-4 (1987): 157-162.
+type CauchySchedule struct {
+	// Initial is the temperature at the first iteration.
+	Initial float64
+}
+
+// Temperature returns the temperature at iteration k.
+func (s CauchySchedule) Temperature(k int) float64 {
+	return s.Initial / (float64(k) + 1)
+}
+
+// SimulatedAnnealing is a global optimizer that performs a random walk on the
+// objective function. At each iteration a location is proposed by perturbing
+// the current location, and the proposal is accepted as the new current
+// location with the Metropolis probability
+//  min(1, exp(-(f(proposal) - f(current)) / T))
+// where T is the temperature at the iteration. Uphill moves, which allow the
+// walk to escape local minima, become less likely as the temperature is
+// lowered. The best location seen is reported at every iteration.
+//  Kirkpatrick, S., Gelatt, C. D. and Vecchi, M. P. "Optimization by simulated
+//  annealing." Science 220.4598 (1983): 671-680.
+//
+// SimulatedAnnealing evaluates the function sequentially, and does not have
+// its own convergence criterion. The optimization is terminated by the
+// Converger or the limits in Settings.
+type SimulatedAnnealing struct {
+	// Schedule gives the temperature at each iteration. If Schedule is
+	// nil, ExponentialSchedule{Initial: 1, Rate: 0.99} is used.
+	Schedule AnnealingSchedule
+	// Proposal is the distribution of the perturbations. Each element of
+	// the proposed location is the corresponding element of the current
+	// location plus an independent draw from Proposal. If Proposal is nil,
+	// a standard normal distribution using Src is used.
+	Proposal distuv.Rander
+	// Src allows a random number generator to be supplied for the
+	// acceptance test. If Src is nil the generator in golang.org/x/exp/rand
+	// is used.
+	Src rand.Source
+
+	schedule AnnealingSchedule
+	proposal distuv.Rander
+	float64  func() float64
+
+	iter     int
+	x, bestX []float64
+	f, bestF float64
+}
+
+func (*SimulatedAnnealing) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (sa *SimulatedAnnealing) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	sa.schedule = sa.Schedule
+	if sa.schedule == nil {
+		sa.schedule = ExponentialSchedule{Initial: 1, Rate: 0.99}
+	}
+	sa.proposal = sa.Proposal
+	if sa.proposal == nil {
+		sa.proposal = distuv.Normal{Mu: 0, Sigma: 1, Src: sa.Src}
+	}
+	if sa.Src == nil {
+		sa.float64 = rand.Float64
+	} else {
+		sa.float64 = rand.New(sa.Src).Float64
+	}
+
+	sa.iter = 0
+	sa.x = resize(sa.x, dim)
+	sa.bestX = resize(sa.bestX, dim)
+	sa.f = math.Inf(1)
+	sa.bestF = math.Inf(1)
+	return 1
+}
+
+// accept performs the Metropolis acceptance test for the evaluated proposal in
+// task, and returns whether the proposal improves on the best location.
+func (sa *SimulatedAnnealing) accept(task Task) bool {
+	if task.F <= sa.f || sa.float64() < math.Exp(-(task.F-sa.f)/sa.schedule.Temperature(sa.iter)) {
+		sa.f = task.F
+		copy(sa.x, task.X)
+	}
+	sa.iter++
+	if task.F < sa.bestF {
+		sa.bestF = task.F
+		copy(sa.bestX, task.X)
+		return true
+	}
+	return false
+}
+
+// propose stores in x a perturbation of the current location.
+func (sa *SimulatedAnnealing) propose(x []float64) {
+	for i, v := range sa.x {
+		x[i] = v + sa.proposal.Rand()
+	}
+}
+
+func (sa *SimulatedAnnealing) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	task := tasks[0]
+	if task.Op&FuncEvaluation != 0 {
+		// The initial function value was supplied in Settings.InitValues.
+		sa.accept(task)
+		task.Op = MajorIteration
+	} else {
+		task.Op = FuncEvaluation
+	}
+	operation <- task
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			sa.propose(task.X)
+			task.Op = FuncEvaluation
+			operation <- task
+		case FuncEvaluation:
+			sa.accept(task)
+			task.F = sa.bestF
+			copy(task.X, sa.bestX)
+			task.Op = MajorIteration
+			operation <- task
+		}
+	}
+
+	// PostIteration was sent. Report the outstanding evaluation if it
+	// improves on the best location.
+	for task := range result {
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			if sa.accept(task) {
+				task.Op = MajorIteration
+				operation <- task
+			}
+		}
+	}
+	close(operation)
+}
@@ -0,0 +1,82 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/optimize/functions"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestAnnealingSchedules(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		schedule AnnealingSchedule
+		k        int
+		want     float64
+	}{
+		{schedule: ExponentialSchedule{Initial: 2, Rate: 0.5}, k: 0, want: 2},
+		{schedule: ExponentialSchedule{Initial: 2, Rate: 0.5}, k: 3, want: 0.25},
+		{schedule: LogarithmicSchedule{Initial: 2}, k: 0, want: 2},
+		{schedule: LogarithmicSchedule{Initial: 2}, k: 2, want: 1},
+		{schedule: CauchySchedule{Initial: 2}, k: 0, want: 2},
+		{schedule: CauchySchedule{Initial: 2}, k: 3, want: 0.5},
+	} {
+		got := test.schedule.Temperature(test.k)
+		if !scalar.EqualWithinAbsOrRel(got, test.want, 1e-14, 1e-14) {
+			t.Errorf("unexpected temperature for %#v at %d: got:%v want:%v", test.schedule, test.k, got, test.want)
+		}
+	}
+}
+
+func TestSimulatedAnnealing(t *testing.T) {
+	t.Parallel()
+	problem := Problem{Func: functions.Rastrigin{}.Func}
+	initX := []float64{2.2, 2.2}
+	// The local minimum nearest to initX has a function value near 8.
+	for _, concurrent := range []int{0, 5} {
+		src := rand.NewSource(1)
+		method := &SimulatedAnnealing{
+			Schedule: ExponentialSchedule{Initial: 10, Rate: 0.999},
+			Proposal: distuv.Normal{Mu: 0, Sigma: 0.5, Src: src},
+			Src:      src,
+		}
+		settings := &Settings{
+			FuncEvaluations: 20000,
+			Converger:       NeverTerminate{},
+			Concurrent:      concurrent,
+		}
+		result, err := Minimize(problem, initX, settings, method)
+		if err != nil {
+			t.Errorf("concurrent=%d: unexpected error: %v", concurrent, err)
+			continue
+		}
+		if result.Status != FunctionEvaluationLimit {
+			t.Errorf("concurrent=%d: unexpected status: got:%v want:%v", concurrent, result.Status, FunctionEvaluationLimit)
+		}
+		if result.F > 0.1 {
+			t.Errorf("concurrent=%d: global minimum not found: f=%v at %v", concurrent, result.F, result.X)
+		}
+	}
+
+	// Check that a supplied initial value is used.
+	settings := &Settings{
+		InitValues:      &Location{F: math.Inf(-1)},
+		MajorIterations: 10,
+		Converger:       NeverTerminate{},
+	}
+	result, err := Minimize(problem, initX, settings, &SimulatedAnnealing{Src: rand.NewSource(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !math.IsInf(result.F, -1) {
+		t.Errorf("initial value not used: got f=%v", result.F)
+	}
+}
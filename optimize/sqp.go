@@ -0,0 +1,517 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultSQPIterations = 100
+	defaultSQPTolerance  = 1e-8
+
+	sqpDecrease        = 1e-4
+	sqpMinimumStepSize = 1e-10
+
+	qpIterations = 200
+	qpTolerance  = 1e-12
+)
+
+// Constraints represents a vector-valued constraint function c: ℝⁿ → ℝᵐ of the
+// variables of an optimization problem.
+type Constraints interface {
+	// Len returns the number of constraint functions m.
+	Len() int
+
+	// Func evaluates the constraint functions at x and stores the result
+	// in dst, which will have length m. Func must not modify x.
+	Func(dst, x []float64)
+
+	// Jac evaluates the m×n Jacobian of the constraint functions at x and
+	// stores the result in dst, which will have dimensions m×n. Jac must
+	// not modify x.
+	Jac(dst *mat.Dense, x []float64)
+}
+
+// ConstrainedProblem describes a nonlinearly constrained optimization problem
+//  minimize   f(x)
+//  subject to cₑ(x) = 0
+//             cᵢ(x) ≥ 0
+// where the objective function f and the constraint functions are smooth.
+type ConstrainedProblem struct {
+	// Func evaluates the objective function at the given location. Func
+	// must not modify x.
+	Func func(x []float64) float64
+
+	// Grad evaluates the gradient at x and stores the result in grad which
+	// will be the same length as x. Grad must not modify x.
+	Grad func(grad, x []float64)
+
+	// Equality holds the equality constraints cₑ(x) = 0. If Equality is
+	// nil, the problem has no equality constraints.
+	Equality Constraints
+
+	// Inequality holds the inequality constraints cᵢ(x) ≥ 0. If Inequality
+	// is nil, the problem has no inequality constraints.
+	Inequality Constraints
+}
+
+// ConstrainedResult represents the answer of a constrained optimization run.
+type ConstrainedResult struct {
+	Location
+	Stats
+	Status Status
+
+	// Equality and Inequality hold the Lagrange multipliers of the
+	// equality and inequality constraints at the solution, so that
+	//  ∇f(x) = Jₑ(x)ᵀ λₑ + Jᵢ(x)ᵀ λᵢ
+	// with the multipliers of the inequality constraints non-negative.
+	Equality, Inequality []float64
+}
+
+// SQP is a line search sequential quadratic programming method for solving
+// nonlinearly constrained optimization problems.
+//
+// At each iteration SQP solves a quadratic programming subproblem formed from
+// the linearized constraints and a quadratic model of the Lagrangian, and
+// searches along the resulting direction using the ℓ₁ exact penalty function
+// as a merit function. The Hessian of the Lagrangian is approximated by damped
+// BFGS updates, and the subproblems are solved by a primal-dual interior-point
+// method. The method is suited to problems of moderate size, and the
+// linearized constraints must be consistent at each iterate, which is the
+// case close to a solution satisfying the linear independence constraint
+// qualification.
+//
+// The method is described in chapter 18 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006).
+type SQP struct {
+	// MaxIterations is the maximum number of major iterations. If
+	// MaxIterations is zero, 100 are used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance. SQP terminates successfully
+	// when the infinity norms of the step and of the constraint violation
+	// are both below Tolerance. If Tolerance is zero, 1e-8 is used.
+	Tolerance float64
+}
+
+// Minimize solves the constrained optimization problem p starting from the
+// location initX, which need not be feasible. Minimize returns Success status
+// when the convergence tolerance is met and IterationLimit status when the
+// maximum number of iterations is reached. An error is returned and the
+// status is Failure if the function values at initX are not finite, if a
+// subproblem cannot be solved or if the line search fails.
+//
+// Minimize panics if p.Func or p.Grad is nil.
+func (s SQP) Minimize(p ConstrainedProblem, initX []float64) (*ConstrainedResult, error) {
+	startTime := time.Now()
+	if p.Func == nil || p.Grad == nil {
+		panic("optimize: constrained problem must provide Func and Grad")
+	}
+	n := len(initX)
+	if n == 0 {
+		return nil, ErrZeroDimensional
+	}
+	maxIter := s.MaxIterations
+	if maxIter == 0 {
+		maxIter = defaultSQPIterations
+	}
+	tol := s.Tolerance
+	if tol == 0 {
+		tol = defaultSQPTolerance
+	}
+
+	cur := newSQPPoint(p, n)
+	copy(cur.x, initX)
+	var stats Stats
+	cur.evaluate(p, &stats)
+	if math.IsInf(cur.f, 0) || math.IsNaN(cur.f) {
+		return &ConstrainedResult{Status: Failure, Stats: stats}, ErrFunc(cur.f)
+	}
+	cur.evaluateDerivatives(p, &stats)
+	next := newSQPPoint(p, n)
+
+	hess := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		hess.SetSym(i, i, 1)
+	}
+	var (
+		mu     float64
+		status Status
+		err    error
+
+		lagGrad = make([]float64, n)
+		step    = make([]float64, n)
+		dy      = make([]float64, n)
+		bs      = mat.NewVecDense(n, nil)
+	)
+	for {
+		var dir []float64
+		dir, cur.multE, cur.multI, err = solveQP(hess, cur.grad, cur.jacE, cur.cE, cur.jacI, cur.cI)
+		if err != nil {
+			status = Failure
+			break
+		}
+		if floats.Norm(dir, math.Inf(1)) <= tol && cur.violation() <= tol {
+			status = Success
+			break
+		}
+		if stats.MajorIterations == maxIter {
+			status = IterationLimit
+			break
+		}
+
+		// Choose the penalty parameter so that the direction is a
+		// descent direction for the merit function.
+		lmax := math.Max(floats.Norm(cur.multE, math.Inf(1)), floats.Norm(cur.multI, math.Inf(1)))
+		if mu < 1.1*lmax {
+			mu = 1.5 * lmax
+		}
+		phi := cur.merit(mu)
+		slope := floats.Dot(cur.grad, dir) - mu*cur.infeasibility()
+
+		alpha := 1.0
+		for {
+			floats.AddScaledTo(next.x, cur.x, alpha, dir)
+			next.evaluate(p, &stats)
+			if next.merit(mu) <= phi+sqpDecrease*alpha*slope {
+				break
+			}
+			alpha /= 2
+			if alpha < sqpMinimumStepSize {
+				err = ErrLinesearcherFailure
+				break
+			}
+		}
+		if err != nil {
+			status = Failure
+			break
+		}
+		next.evaluateDerivatives(p, &stats)
+		stats.MajorIterations++
+
+		// Update the Hessian approximation with the change in the
+		// gradient of the Lagrangian at the new multipliers, damped
+		// to keep the approximation positive definite.
+		cur.lagrangianGrad(lagGrad, cur.multE, cur.multI)
+		next.lagrangianGrad(dy, cur.multE, cur.multI)
+		floats.Sub(dy, lagGrad)
+		floats.SubTo(step, next.x, cur.x)
+		sv := mat.NewVecDense(n, step)
+		bs.MulVec(hess, sv)
+		sBs := mat.Dot(sv, bs)
+		sy := floats.Dot(step, dy)
+		if sBs > 0 {
+			theta := 1.0
+			if sy < 0.2*sBs {
+				theta = 0.8 * sBs / (sBs - sy)
+			}
+			r := mat.NewVecDense(n, nil)
+			r.AddScaledVec(r, theta, mat.NewVecDense(n, dy))
+			r.AddScaledVec(r, 1-theta, bs)
+			if sr := mat.Dot(sv, r); sr > 0 {
+				hess.SymRankOne(hess, -1/sBs, bs)
+				hess.SymRankOne(hess, 1/sr, r)
+			}
+		}
+		next.multE, next.multI = cur.multE, cur.multI
+		cur, next = next, cur
+	}
+	stats.Runtime = time.Since(startTime)
+	return &ConstrainedResult{
+		Location: Location{
+			X:        cur.x,
+			F:        cur.f,
+			Gradient: cur.grad,
+		},
+		Stats:      stats,
+		Status:     status,
+		Equality:   cur.multE,
+		Inequality: cur.multI,
+	}, err
+}
+
+// sqpPoint holds the values of the functions of a constrained problem at a
+// location.
+type sqpPoint struct {
+	x, grad    []float64
+	f          float64
+	cE, cI     []float64
+	jacE, jacI *mat.Dense
+
+	multE, multI []float64
+}
+
+func newSQPPoint(p ConstrainedProblem, n int) *sqpPoint {
+	pt := &sqpPoint{
+		x:    make([]float64, n),
+		grad: make([]float64, n),
+	}
+	if p.Equality != nil {
+		if m := p.Equality.Len(); m > 0 {
+			pt.cE = make([]float64, m)
+			pt.jacE = mat.NewDense(m, n, nil)
+		}
+	}
+	if p.Inequality != nil {
+		if m := p.Inequality.Len(); m > 0 {
+			pt.cI = make([]float64, m)
+			pt.jacI = mat.NewDense(m, n, nil)
+		}
+	}
+	return pt
+}
+
+// evaluate evaluates the objective and constraint functions at pt.x.
+func (pt *sqpPoint) evaluate(p ConstrainedProblem, stats *Stats) {
+	pt.f = p.Func(pt.x)
+	stats.FuncEvaluations++
+	if pt.cE != nil {
+		p.Equality.Func(pt.cE, pt.x)
+	}
+	if pt.cI != nil {
+		p.Inequality.Func(pt.cI, pt.x)
+	}
+}
+
+// evaluateDerivatives evaluates the objective gradient and constraint
+// Jacobians at pt.x.
+func (pt *sqpPoint) evaluateDerivatives(p ConstrainedProblem, stats *Stats) {
+	p.Grad(pt.grad, pt.x)
+	stats.GradEvaluations++
+	if pt.jacE != nil {
+		p.Equality.Jac(pt.jacE, pt.x)
+	}
+	if pt.jacI != nil {
+		p.Inequality.Jac(pt.jacI, pt.x)
+	}
+}
+
+// infeasibility returns the ℓ₁ norm of the constraint violation.
+func (pt *sqpPoint) infeasibility() float64 {
+	var sum float64
+	for _, v := range pt.cE {
+		sum += math.Abs(v)
+	}
+	for _, v := range pt.cI {
+		sum += math.Max(0, -v)
+	}
+	return sum
+}
+
+// violation returns the infinity norm of the constraint violation.
+func (pt *sqpPoint) violation() float64 {
+	var max float64
+	for _, v := range pt.cE {
+		max = math.Max(max, math.Abs(v))
+	}
+	for _, v := range pt.cI {
+		max = math.Max(max, -v)
+	}
+	return max
+}
+
+// merit returns the ℓ₁ exact penalty function with penalty parameter mu.
+func (pt *sqpPoint) merit(mu float64) float64 {
+	return pt.f + mu*pt.infeasibility()
+}
+
+// lagrangianGrad stores in dst the gradient of the Lagrangian with the
+// given multipliers.
+func (pt *sqpPoint) lagrangianGrad(dst, multE, multI []float64) {
+	copy(dst, pt.grad)
+	var t mat.VecDense
+	if pt.jacE != nil {
+		t.MulVec(pt.jacE.T(), mat.NewVecDense(len(multE), multE))
+		floats.Sub(dst, t.RawVector().Data)
+	}
+	if pt.jacI != nil {
+		t.MulVec(pt.jacI.T(), mat.NewVecDense(len(multI), multI))
+		floats.Sub(dst, t.RawVector().Data)
+	}
+}
+
+// solveQP solves the strictly convex quadratic program
+//  minimize   ½ xᵀHx + gᵀx
+//  subject to Ax + a = 0
+//             Cx + c ≥ 0
+// where A and C are held in eq and ineq, and returns the solution and the
+// multipliers of the equality and inequality constraints. eq and ineq may be
+// nil if there are no constraints of that kind. solveQP uses Mehrotra's predictor-corrector primal-dual interior-point
+// method.
+func solveQP(h mat.Symmetric, g []float64, eq *mat.Dense, a []float64, ineq *mat.Dense, c []float64) (x, y, z []float64, err error) {
+	n := len(g)
+	mE := len(a)
+	mI := len(c)
+	x = make([]float64, n)
+	y = make([]float64, mE)
+	z = make([]float64, mI)
+	s := make([]float64, mI)
+	for i, v := range c {
+		s[i] = math.Max(1, v)
+		z[i] = 1
+	}
+	scale := 1 + math.Max(floats.Norm(g, math.Inf(1)), math.Max(floats.Norm(a, math.Inf(1)), floats.Norm(c, math.Inf(1))))
+
+	var (
+		rd = make([]float64, n)
+		rE = make([]float64, mE)
+		rI = make([]float64, mI)
+		rc = make([]float64, mI)
+
+		dx, dy = make([]float64, n), make([]float64, mE)
+		ds, dz = make([]float64, mI), make([]float64, mI)
+
+		k   = mat.NewDense(n+mE, n+mE, nil)
+		rhs = mat.NewVecDense(n+mE, nil)
+		sol mat.VecDense
+		lu  mat.LU
+		tmp = make([]float64, mI)
+	)
+	xv := mat.NewVecDense(n, x)
+	for iter := 0; iter < qpIterations; iter++ {
+		// Compute the residuals of the optimality conditions
+		//  Hx + g - Aᵀy - Cᵀz = 0
+		//  Ax + a = 0
+		//  Cx + c - s = 0
+		//  sᵢzᵢ = 0, s, z ≥ 0.
+		r := mat.NewVecDense(n, rd)
+		r.MulVec(h, xv)
+		floats.Add(rd, g)
+		if mE > 0 {
+			var t mat.VecDense
+			t.MulVec(eq.T(), mat.NewVecDense(mE, y))
+			floats.Sub(rd, t.RawVector().Data)
+			mat.NewVecDense(mE, rE).MulVec(eq, xv)
+			floats.Add(rE, a)
+		}
+		var mu float64
+		if mI > 0 {
+			var t mat.VecDense
+			t.MulVec(ineq.T(), mat.NewVecDense(mI, z))
+			floats.Sub(rd, t.RawVector().Data)
+			mat.NewVecDense(mI, rI).MulVec(ineq, xv)
+			floats.Add(rI, c)
+			floats.Sub(rI, s)
+			mu = floats.Dot(s, z) / float64(mI)
+		}
+		res := math.Max(floats.Norm(rd, math.Inf(1)), math.Max(floats.Norm(rE, math.Inf(1)), floats.Norm(rI, math.Inf(1))))
+		if res <= qpTolerance*scale && mu <= qpTolerance*scale {
+			return x, y, z, nil
+		}
+
+		// Form and factorize the reduced Newton system
+		//  [H + CᵀS⁻¹ZC  -Aᵀ] [Δx]
+		//  [A              0] [Δy].
+		k.Zero()
+		kh := k.Slice(0, n, 0, n).(*mat.Dense)
+		kh.Copy(h)
+		for i := 0; i < mI; i++ {
+			row := ineq.RawRowView(i)
+			f := z[i] / s[i]
+			for p, vp := range row {
+				if vp == 0 {
+					continue
+				}
+				for q, vq := range row {
+					kh.Set(p, q, kh.At(p, q)+f*vp*vq)
+				}
+			}
+		}
+		if mE > 0 {
+			for i := 0; i < mE; i++ {
+				for j := 0; j < n; j++ {
+					v := eq.At(i, j)
+					k.Set(n+i, j, v)
+					k.Set(j, n+i, -v)
+				}
+			}
+		}
+		lu.Factorize(k)
+		if math.IsInf(lu.Cond(), 1) {
+			return nil, nil, nil, ErrQPFailure
+		}
+
+		// solve computes the Newton step for the complementarity
+		// right-hand side in rc.
+		solve := func() {
+			rv := rhs.RawVector().Data
+			for i := range tmp {
+				tmp[i] = (rc[i] - z[i]*rI[i]) / s[i]
+			}
+			for j := 0; j < n; j++ {
+				rv[j] = -rd[j]
+			}
+			if mI > 0 {
+				var t mat.VecDense
+				t.MulVec(ineq.T(), mat.NewVecDense(mI, tmp))
+				floats.Add(rv[:n], t.RawVector().Data)
+			}
+			for i := 0; i < mE; i++ {
+				rv[n+i] = -rE[i]
+			}
+			// The factorization is known to be non-singular,
+			// so any error only reports ill-conditioning.
+			_ = lu.SolveVecTo(&sol, false, rhs)
+			copy(dx, sol.RawVector().Data[:n])
+			copy(dy, sol.RawVector().Data[n:])
+			if mI > 0 {
+				mat.NewVecDense(mI, ds).MulVec(ineq, mat.NewVecDense(n, dx))
+				floats.Add(ds, rI)
+				for i := range dz {
+					dz[i] = (rc[i] - z[i]*ds[i]) / s[i]
+				}
+			}
+		}
+		// Predictor step.
+		for i := range rc {
+			rc[i] = -s[i] * z[i]
+		}
+		solve()
+		if mI > 0 {
+			alpha := stepToBoundary(s, ds, z, dz)
+			var muAff float64
+			for i := range s {
+				muAff += (s[i] + alpha*ds[i]) * (z[i] + alpha*dz[i])
+			}
+			muAff /= float64(mI)
+			sigma := math.Pow(muAff/mu, 3)
+
+			// Corrector step.
+			for i := range rc {
+				rc[i] = -s[i]*z[i] + sigma*mu - ds[i]*dz[i]
+			}
+			solve()
+		}
+		alpha := 1.0
+		if mI > 0 {
+			alpha = math.Min(1, 0.995*stepToBoundary(s, ds, z, dz))
+		}
+		floats.AddScaled(x, alpha, dx)
+		floats.AddScaled(y, alpha, dy)
+		floats.AddScaled(s, alpha, ds)
+		floats.AddScaled(z, alpha, dz)
+	}
+	return nil, nil, nil, ErrQPFailure
+}
+
+// stepToBoundary returns the largest step α, up to a maximum of one, such
+// that s + α ds and z + α dz are non-negative.
+func stepToBoundary(s, ds, z, dz []float64) float64 {
+	alpha := 1.0
+	for i := range s {
+		if ds[i] < 0 {
+			alpha = math.Min(alpha, -s[i]/ds[i])
+		}
+		if dz[i] < 0 {
+			alpha = math.Min(alpha, -z[i]/dz[i])
+		}
+	}
+	return alpha
+}
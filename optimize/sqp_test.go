@@ -0,0 +1,231 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// testConstraints implements Constraints with function values.
+type testConstraints struct {
+	m   int
+	fn  func(dst, x []float64)
+	jac func(dst *mat.Dense, x []float64)
+}
+
+func (c testConstraints) Len() int                        { return c.m }
+func (c testConstraints) Func(dst, x []float64)           { c.fn(dst, x) }
+func (c testConstraints) Jac(dst *mat.Dense, x []float64) { c.jac(dst, x) }
+
+// boxConstraints returns the inequality constraints lower ≤ x ≤ upper.
+func boxConstraints(lower, upper []float64) testConstraints {
+	n := len(lower)
+	return testConstraints{
+		m: 2 * n,
+		fn: func(dst, x []float64) {
+			for i, v := range x {
+				dst[i] = v - lower[i]
+				dst[n+i] = upper[i] - v
+			}
+		},
+		jac: func(dst *mat.Dense, x []float64) {
+			dst.Zero()
+			for i := range x {
+				dst.Set(i, i, 1)
+				dst.Set(n+i, i, -1)
+			}
+		},
+	}
+}
+
+func TestSQP(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		p        ConstrainedProblem
+		x        []float64
+		want     []float64
+		wantF    float64
+		wantEq   []float64
+		wantIneq []float64
+		tol      float64
+	}{
+		{
+			// Minimize x₀ + x₁ on the circle of radius √2.
+			name: "circle",
+			p: ConstrainedProblem{
+				Func: func(x []float64) float64 { return x[0] + x[1] },
+				Grad: func(grad, x []float64) { grad[0], grad[1] = 1, 1 },
+				Equality: testConstraints{
+					m:   1,
+					fn:  func(dst, x []float64) { dst[0] = x[0]*x[0] + x[1]*x[1] - 2 },
+					jac: func(dst *mat.Dense, x []float64) { dst.Set(0, 0, 2*x[0]); dst.Set(0, 1, 2*x[1]) },
+				},
+			},
+			x:      []float64{1, -0.5},
+			want:   []float64{-1, -1},
+			wantF:  -2,
+			wantEq: []float64{-0.5},
+			tol:    1e-7,
+		},
+		{
+			// Hock-Schittkowski problem 71.
+			name: "HS071",
+			p: ConstrainedProblem{
+				Func: func(x []float64) float64 { return x[0]*x[3]*(x[0]+x[1]+x[2]) + x[2] },
+				Grad: func(grad, x []float64) {
+					grad[0] = x[3]*(x[0]+x[1]+x[2]) + x[0]*x[3]
+					grad[1] = x[0] * x[3]
+					grad[2] = x[0]*x[3] + 1
+					grad[3] = x[0] * (x[0] + x[1] + x[2])
+				},
+				Equality: testConstraints{
+					m:  1,
+					fn: func(dst, x []float64) { dst[0] = floats.Dot(x, x) - 40 },
+					jac: func(dst *mat.Dense, x []float64) {
+						for j, v := range x {
+							dst.Set(0, j, 2*v)
+						}
+					},
+				},
+				Inequality: testConstraints{
+					m: 9,
+					fn: func(dst, x []float64) {
+						boxConstraints([]float64{1, 1, 1, 1}, []float64{5, 5, 5, 5}).Func(dst[:8], x)
+						dst[8] = x[0]*x[1]*x[2]*x[3] - 25
+					},
+					jac: func(dst *mat.Dense, x []float64) {
+						boxConstraints([]float64{1, 1, 1, 1}, []float64{5, 5, 5, 5}).Jac(dst.Slice(0, 8, 0, 4).(*mat.Dense), x)
+						dst.Set(8, 0, x[1]*x[2]*x[3])
+						dst.Set(8, 1, x[0]*x[2]*x[3])
+						dst.Set(8, 2, x[0]*x[1]*x[3])
+						dst.Set(8, 3, x[0]*x[1]*x[2])
+					},
+				},
+			},
+			x:     []float64{1, 5, 5, 1},
+			want:  []float64{1, 4.742999643, 3.821149979, 1.379408293},
+			wantF: 17.014017289,
+			tol:   1e-6,
+		},
+		{
+			// The constraint is inactive at the unconstrained minimum of
+			// the Rosenbrock function.
+			name: "inactive",
+			p: ConstrainedProblem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+				Grad: functions.ExtendedRosenbrock{}.Grad,
+				Inequality: testConstraints{
+					m:   1,
+					fn:  func(dst, x []float64) { dst[0] = 4 - x[0]*x[0] - x[1]*x[1] },
+					jac: func(dst *mat.Dense, x []float64) { dst.Set(0, 0, -2*x[0]); dst.Set(0, 1, -2*x[1]) },
+				},
+			},
+			x:        []float64{-1.2, 1},
+			want:     []float64{1, 1},
+			wantF:    0,
+			wantIneq: []float64{0},
+			tol:      1e-6,
+		},
+	} {
+		result, err := SQP{}.Minimize(test.p, test.x)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != Success {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, Success)
+		}
+		if !floats.EqualApprox(result.X, test.want, test.tol) {
+			t.Errorf("%s: unexpected minimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if math.Abs(result.F-test.wantF) > test.tol {
+			t.Errorf("%s: unexpected function value: got %v, want %v", test.name, result.F, test.wantF)
+		}
+		if test.wantEq != nil && !floats.EqualApprox(result.Equality, test.wantEq, test.tol) {
+			t.Errorf("%s: unexpected equality multipliers: got %v, want %v", test.name, result.Equality, test.wantEq)
+		}
+		if test.wantIneq != nil && !floats.EqualApprox(result.Inequality, test.wantIneq, test.tol) {
+			t.Errorf("%s: unexpected inequality multipliers: got %v, want %v", test.name, result.Inequality, test.wantIneq)
+		}
+
+		// Check the first-order optimality conditions.
+		n := len(test.x)
+		stat := make([]float64, n)
+		copy(stat, result.Gradient)
+		for _, c := range []struct {
+			cons Constraints
+			mult []float64
+		}{
+			{test.p.Equality, result.Equality},
+			{test.p.Inequality, result.Inequality},
+		} {
+			if c.cons == nil {
+				continue
+			}
+			m := c.cons.Len()
+			jac := mat.NewDense(m, n, nil)
+			c.cons.Jac(jac, result.X)
+			var jl mat.VecDense
+			jl.MulVec(jac.T(), mat.NewVecDense(m, c.mult))
+			floats.Sub(stat, jl.RawVector().Data)
+		}
+		if norm := floats.Norm(stat, math.Inf(1)); norm > test.tol {
+			t.Errorf("%s: gradient of Lagrangian not zero: %v", test.name, stat)
+		}
+		for i, v := range result.Inequality {
+			if v < -1e-10 {
+				t.Errorf("%s: negative inequality multiplier %d: %v", test.name, i, v)
+			}
+		}
+	}
+}
+
+func TestSolveQP(t *testing.T) {
+	t.Parallel()
+	// Nocedal and Wright example 16.4:
+	//  minimize (x₀-1)² + (x₁-2.5)²
+	// subject to five linear inequality constraints.
+	h := mat.NewSymDense(2, []float64{2, 0, 0, 2})
+	g := []float64{-2, -5}
+	c := mat.NewDense(5, 2, []float64{
+		1, -2,
+		-1, -2,
+		-1, 2,
+		1, 0,
+		0, 1,
+	})
+	cv := []float64{2, 6, 2, 0, 0}
+	x, _, z, err := solveQP(h, g, nil, nil, c, cv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []float64{1.4, 1.7}; !floats.EqualApprox(x, want, 1e-10) {
+		t.Errorf("unexpected solution: got %v, want %v", x, want)
+	}
+	if want := []float64{0.8, 0, 0, 0, 0}; !floats.EqualApprox(z, want, 1e-10) {
+		t.Errorf("unexpected multipliers: got %v, want %v", z, want)
+	}
+
+	// With an equality constraint x₀ = x₁ the solution is the
+	// projection of (1, 2.5) onto the line, which is feasible.
+	a := mat.NewDense(1, 2, []float64{1, -1})
+	x, y, _, err := solveQP(h, g, a, []float64{0}, c, cv)
+	if err != nil {
+		t.Fatalf("unexpected error with equality: %v", err)
+	}
+	if want := []float64{1.75, 1.75}; !floats.EqualApprox(x, want, 1e-10) {
+		t.Errorf("unexpected solution with equality: got %v, want %v", x, want)
+	}
+	// The gradient 2(x - (1, 2.5)) = (1.5, -1.5) equals y (1, -1).
+	if !floats.EqualApprox(y, []float64{1.5}, 1e-10) {
+		t.Errorf("unexpected equality multiplier: got %v, want [1.5]", y)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+const defaultBootstrapReplicates = 1000
+
+// Statistic is a function computing a statistic of the resample of a data set
+// given by the indices of its observations in idx. An index may appear more
+// than once in a bootstrap resample. A Statistic used with Bootstrap must be
+// safe for concurrent use and must not retain or modify idx.
+type Statistic func(idx []int) float64
+
+// Bootstrap holds the settings for bootstrap resampling of a data set. The
+// observations are resampled with replacement, independently within each
+// stratum if Strata is not nil, or in blocks of consecutive observations if
+// BlockLen is positive.
+//  Efron, B. and Tibshirani, R. J. "An Introduction to the Bootstrap."
+//  Chapman & Hall (1993).
+type Bootstrap struct {
+	// Replicates is the number of bootstrap replicates. If
+	// Replicates is zero, 1000 are used.
+	Replicates int
+
+	// Strata, if not nil, holds the stratum label of each
+	// observation. Each replicate then draws as many observations
+	// from each stratum as it holds in the original data, so the
+	// composition of the strata is preserved.
+	Strata []int
+
+	// BlockLen is the length of the blocks of the moving block
+	// bootstrap for dependent data. Each replicate concatenates
+	// randomly chosen blocks of BlockLen consecutive observations,
+	// truncating the last block so that the replicate has the same
+	// length as the data. If BlockLen is zero, observations are
+	// resampled individually.
+	//  Künsch, H. R. "The jackknife and the bootstrap for general
+	//  stationary observations." The Annals of Statistics 17.3 (1989):
+	//  1217-1241.
+	BlockLen int
+
+	// Workers is the number of goroutines used to compute the
+	// replicates. If Workers is zero, runtime.GOMAXPROCS(0) are
+	// used. The replicates do not depend on Workers.
+	Workers int
+
+	// Src is the source of randomness for the resampling. If Src is
+	// nil, the global source of golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// BootstrapEstimate holds the replicates of a bootstrap estimate of a
+// statistic.
+type BootstrapEstimate struct {
+	// Estimate is the value of the statistic on the original data.
+	Estimate float64
+
+	// Replicates holds the value of the statistic on each
+	// bootstrap resample.
+	Replicates []float64
+
+	n         int
+	statistic Statistic
+}
+
+// Resample computes bootstrap replicates of the statistic of a data set with n
+// observations. The replicates are computed concurrently, but each replicate
+// uses its own random source seeded from Src, so the result does not depend on
+// the number of workers.
+//
+// Resample will panic if n is less than one, if Strata is not nil and its
+// length is not n, if both Strata and BlockLen are set, if BlockLen is greater
+// than n, or if the settings are negative.
+func (b Bootstrap) Resample(n int, statistic Statistic) *BootstrapEstimate {
+	reps := b.Replicates
+	if reps == 0 {
+		reps = defaultBootstrapReplicates
+	}
+	workers := b.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	switch {
+	case n < 1:
+		panic("stat: no observations to resample")
+	case reps < 0 || b.BlockLen < 0 || workers < 0:
+		panic("stat: negative bootstrap setting")
+	case b.Strata != nil && len(b.Strata) != n:
+		panic("stat: len(strata) != observations")
+	case b.Strata != nil && b.BlockLen != 0:
+		panic("stat: stratified block bootstrap not supported")
+	case b.BlockLen > n:
+		panic("stat: bootstrap block longer than data")
+	}
+
+	// Group the observations by stratum in order of first
+	// appearance so that the resampling is deterministic.
+	var strata [][]int
+	if b.Strata != nil {
+		index := make(map[int]int)
+		for i, s := range b.Strata {
+			k, ok := index[s]
+			if !ok {
+				k = len(strata)
+				index[s] = k
+				strata = append(strata, nil)
+			}
+			strata[k] = append(strata[k], i)
+		}
+	}
+
+	seeds := make([]uint64, reps)
+	uint64n := rand.Uint64
+	if b.Src != nil {
+		uint64n = rand.New(b.Src).Uint64
+	}
+	for i := range seeds {
+		seeds[i] = uint64n()
+	}
+
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	e := &BootstrapEstimate{
+		Estimate:   statistic(all),
+		Replicates: make([]float64, reps),
+		n:          n,
+		statistic:  statistic,
+	}
+
+	work := make(chan int)
+	go func() {
+		for r := range seeds {
+			work <- r
+		}
+		close(work)
+	}()
+	if workers > reps {
+		workers = reps
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			idx := make([]int, n)
+			for r := range work {
+				b.draw(idx, strata, rand.New(rand.NewSource(seeds[r])))
+				e.Replicates[r] = statistic(idx)
+			}
+		}()
+	}
+	wg.Wait()
+	return e
+}
+
+// draw fills idx with the indices of a bootstrap resample.
+func (b Bootstrap) draw(idx []int, strata [][]int, rnd *rand.Rand) {
+	n := len(idx)
+	switch {
+	case strata != nil:
+		var i int
+		for _, s := range strata {
+			for range s {
+				idx[i] = s[rnd.Intn(len(s))]
+				i++
+			}
+		}
+	case b.BlockLen > 0:
+		for i := 0; i < n; {
+			start := rnd.Intn(n - b.BlockLen + 1)
+			for j := 0; j < b.BlockLen && i < n; j++ {
+				idx[i] = start + j
+				i++
+			}
+		}
+	default:
+		for i := range idx {
+			idx[i] = rnd.Intn(n)
+		}
+	}
+}
+
+// Bias returns the bootstrap estimate of the bias of the statistic, the mean
+// of the replicates less the estimate on the original data.
+func (e *BootstrapEstimate) Bias() float64 {
+	return Mean(e.Replicates, nil) - e.Estimate
+}
+
+// StdErr returns the bootstrap estimate of the standard error of the
+// statistic, the standard deviation of the replicates.
+func (e *BootstrapEstimate) StdErr() float64 {
+	return StdDev(e.Replicates, nil)
+}
+
+// PercentileInterval returns the bootstrap percentile confidence interval for
+// the statistic with the given confidence level, given by the (1-level)/2 and
+// (1+level)/2 quantiles of the replicates. PercentileInterval will panic if
+// level is not in (0, 1).
+func (e *BootstrapEstimate) PercentileInterval(level float64) (lo, hi float64) {
+	if !(0 < level && level < 1) {
+		panic("stat: confidence level out of range")
+	}
+	alpha := (1 - level) / 2
+	sorted := e.sortedReplicates()
+	return Quantile(alpha, LinInterp, sorted, nil), Quantile(1-alpha, LinInterp, sorted, nil)
+}
+
+// BCaInterval returns the bias-corrected and accelerated bootstrap confidence
+// interval for the statistic with the given confidence level. The acceleration
+// is estimated by the jackknife, which evaluates the statistic n more times.
+// BCaInterval will panic if level is not in (0, 1) or if the data set has
+// fewer than two observations.
+//  Efron, B. "Better bootstrap confidence intervals." Journal of the American
+//  Statistical Association 82.397 (1987): 171-185.
+//
+// If all the replicates lie on the same side of the estimate, the bias
+// correction is infinite and BCaInterval returns the range of the replicates.
+func (e *BootstrapEstimate) BCaInterval(level float64) (lo, hi float64) {
+	if !(0 < level && level < 1) {
+		panic("stat: confidence level out of range")
+	}
+	sorted := e.sortedReplicates()
+
+	// The bias correction is the normal quantile of the fraction
+	// of replicates below the estimate, counting ties as half.
+	below := float64(sort.SearchFloat64s(sorted, e.Estimate))
+	ties := float64(sort.Search(len(sorted), func(i int) bool { return sorted[i] > e.Estimate })) - below
+	z0 := mathext.NormalQuantile((below + ties/2) / float64(len(sorted)))
+	if math.IsInf(z0, 0) {
+		return sorted[0], sorted[len(sorted)-1]
+	}
+
+	a := Jackknife(e.n, e.statistic).acceleration()
+
+	adjust := func(p float64) float64 {
+		z := mathext.NormalQuantile(p)
+		return normalCDF(z0 + (z0+z)/(1-a*(z0+z)))
+	}
+	alpha := (1 - level) / 2
+	return Quantile(adjust(alpha), LinInterp, sorted, nil), Quantile(adjust(1-alpha), LinInterp, sorted, nil)
+}
+
+func (e *BootstrapEstimate) sortedReplicates() []float64 {
+	if len(e.Replicates) == 0 {
+		panic("stat: no bootstrap replicates")
+	}
+	sorted := make([]float64, len(e.Replicates))
+	copy(sorted, e.Replicates)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// normalCDF returns the standard normal cumulative distribution function at z.
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// JackknifeEstimate holds the leave-one-out values of a jackknife estimate of
+// a statistic.
+type JackknifeEstimate struct {
+	// Estimate is the value of the statistic on the original data.
+	Estimate float64
+
+	// Values holds the value of the statistic with each
+	// observation left out in turn.
+	Values []float64
+}
+
+// Jackknife computes the jackknife leave-one-out values of the statistic of a
+// data set with n observations. Jackknife will panic if n is less than two.
+//  Quenouille, M. H. "Notes on bias in estimation." Biometrika 43.3/4
+//  (1956): 353-360.
+func Jackknife(n int, statistic Statistic) JackknifeEstimate {
+	if n < 2 {
+		panic("stat: too few observations for jackknife")
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	e := JackknifeEstimate{
+		Estimate: statistic(idx),
+		Values:   make([]float64, n),
+	}
+	loo := make([]int, n-1)
+	for i := 0; i < n; i++ {
+		// Leave out observation i, keeping the order of the
+		// remaining observations.
+		copy(loo, idx[:i])
+		copy(loo[i:], idx[i+1:])
+		e.Values[i] = statistic(loo)
+	}
+	return e
+}
+
+// Bias returns the jackknife estimate of the bias of the statistic,
+//  (n-1) (mean(θ_(i)) - θ)
+func (e JackknifeEstimate) Bias() float64 {
+	n := float64(len(e.Values))
+	return (n - 1) * (Mean(e.Values, nil) - e.Estimate)
+}
+
+// StdErr returns the jackknife estimate of the standard error of the
+// statistic,
+//  sqrt((n-1)/n Σ_i (θ_(i) - mean(θ_(i)))^2)
+func (e JackknifeEstimate) StdErr() float64 {
+	n := float64(len(e.Values))
+	mean := Mean(e.Values, nil)
+	var ss float64
+	for _, v := range e.Values {
+		ss += (v - mean) * (v - mean)
+	}
+	return math.Sqrt((n - 1) / n * ss)
+}
+
+// acceleration returns the BCa acceleration constant estimated from the
+// skewness of the jackknife values.
+func (e JackknifeEstimate) acceleration() float64 {
+	mean := Mean(e.Values, nil)
+	var num, den float64
+	for _, v := range e.Values {
+		d := mean - v
+		num += d * d * d
+		den += d * d
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / (6 * math.Pow(den, 1.5))
+}
@@ -0,0 +1,211 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// meanOf returns a Statistic computing the mean of the resampled values of x.
+func meanOf(x []float64) Statistic {
+	return func(idx []int) float64 {
+		var sum float64
+		for _, i := range idx {
+			sum += x[i]
+		}
+		return sum / float64(len(idx))
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 200
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 3 + 2*rnd.NormFloat64()
+	}
+	mean := meanOf(x)
+
+	e := Bootstrap{Replicates: 4000, Src: rand.NewSource(1)}.Resample(n, mean)
+	if !scalar.EqualWithinAbsOrRel(e.Estimate, Mean(x, nil), 1e-14, 1e-14) {
+		t.Errorf("unexpected estimate: got %v, want %v", e.Estimate, Mean(x, nil))
+	}
+	if len(e.Replicates) != 4000 {
+		t.Fatalf("unexpected number of replicates: %d", len(e.Replicates))
+	}
+	// The standard error of the mean is σ/√n.
+	wantSE := StdDev(x, nil) / math.Sqrt(n)
+	if se := e.StdErr(); math.Abs(se-wantSE) > 0.05*wantSE {
+		t.Errorf("unexpected standard error: got %v, want %v", se, wantSE)
+	}
+	if bias := e.Bias(); math.Abs(bias) > 0.1*wantSE {
+		t.Errorf("unexpected bias of the mean: %v", bias)
+	}
+
+	const level = 0.9
+	lo, hi := e.PercentileInterval(level)
+	z := 1.6448536269514722
+	if math.Abs(lo-(e.Estimate-z*wantSE)) > 0.1*wantSE || math.Abs(hi-(e.Estimate+z*wantSE)) > 0.1*wantSE {
+		t.Errorf("unexpected percentile interval: got [%v, %v], want about [%v, %v]",
+			lo, hi, e.Estimate-z*wantSE, e.Estimate+z*wantSE)
+	}
+	bcaLo, bcaHi := e.BCaInterval(level)
+	if math.Abs(bcaLo-lo) > 0.1*wantSE || math.Abs(bcaHi-hi) > 0.1*wantSE {
+		t.Errorf("BCa interval for symmetric statistic differs from percentile interval: got [%v, %v], want about [%v, %v]",
+			bcaLo, bcaHi, lo, hi)
+	}
+
+	// The replicates do not depend on the number of workers.
+	for _, workers := range []int{1, 3} {
+		got := Bootstrap{Replicates: 4000, Workers: workers, Src: rand.NewSource(1)}.Resample(n, mean)
+		if !floats.Equal(got.Replicates, e.Replicates) {
+			t.Errorf("replicates depend on the number of workers: %d", workers)
+		}
+	}
+}
+
+func TestBootstrapBCa(t *testing.T) {
+	t.Parallel()
+	// The sampling distribution of the variance of skewed data is
+	// skewed, so the BCa interval is shifted to the right of the
+	// percentile interval.
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.ExpFloat64()
+	}
+	variance := func(idx []int) float64 {
+		v := make([]float64, len(idx))
+		for k, i := range idx {
+			v[k] = x[i]
+		}
+		return Variance(v, nil)
+	}
+	e := Bootstrap{Replicates: 2000, Src: rand.NewSource(2)}.Resample(n, variance)
+	lo, hi := e.PercentileInterval(0.95)
+	bcaLo, bcaHi := e.BCaInterval(0.95)
+	if !(bcaLo > lo && bcaHi > hi) {
+		t.Errorf("BCa interval not shifted right of percentile interval: [%v, %v] and [%v, %v]", bcaLo, bcaHi, lo, hi)
+	}
+	if !(bcaLo < e.Estimate && e.Estimate < bcaHi) {
+		t.Errorf("BCa interval [%v, %v] does not contain estimate %v", bcaLo, bcaHi, e.Estimate)
+	}
+
+	// A constant statistic has all replicates tied with the
+	// estimate.
+	c := Bootstrap{Replicates: 10, Src: rand.NewSource(1)}.Resample(n, func([]int) float64 { return 1 })
+	if lo, hi := c.BCaInterval(0.95); lo != 1 || hi != 1 {
+		t.Errorf("unexpected interval for constant statistic: [%v, %v]", lo, hi)
+	}
+}
+
+func TestBootstrapSchemes(t *testing.T) {
+	t.Parallel()
+	const n = 30
+	strata := make([]int, n)
+	for i := range strata {
+		strata[i] = i % 3 * 10
+	}
+	// The stratified resample preserves the stratum sizes.
+	e := Bootstrap{Replicates: 100, Strata: strata, Src: rand.NewSource(1)}.Resample(n, func(idx []int) float64 {
+		var count float64
+		for _, i := range idx {
+			if strata[i] == 10 {
+				count++
+			}
+		}
+		return count
+	})
+	for r, v := range e.Replicates {
+		if v != n/3 {
+			t.Errorf("unexpected stratum size in replicate %d: got %v, want %d", r, v, n/3)
+		}
+	}
+
+	// The block resample is made of consecutive runs of BlockLen
+	// observations.
+	const blockLen = 4
+	e = Bootstrap{Replicates: 100, BlockLen: blockLen, Src: rand.NewSource(1)}.Resample(n, func(idx []int) float64 {
+		if len(idx) != n {
+			return 0
+		}
+		for i := range idx {
+			if i%blockLen != 0 && idx[i] != idx[i-1]+1 {
+				return 0
+			}
+		}
+		return 1
+	})
+	for r, v := range e.Replicates {
+		if v != 1 {
+			t.Errorf("replicate %d is not made of blocks", r)
+		}
+	}
+}
+
+func TestJackknife(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+
+	// The jackknife standard error of the mean is s/√n.
+	e := Jackknife(n, meanOf(x))
+	if want := StdDev(x, nil) / math.Sqrt(n); !scalar.EqualWithinAbsOrRel(e.StdErr(), want, 1e-14, 1e-12) {
+		t.Errorf("unexpected standard error of the mean: got %v, want %v", e.StdErr(), want)
+	}
+	if math.Abs(e.Bias()) > 1e-14 {
+		t.Errorf("unexpected bias of the mean: %v", e.Bias())
+	}
+
+	// Correcting the plug-in variance for the jackknife bias gives
+	// the unbiased variance.
+	plugin := func(idx []int) float64 {
+		v := make([]float64, len(idx))
+		for k, i := range idx {
+			v[k] = x[i]
+		}
+		_, variance := PopMeanVariance(v, nil)
+		return variance
+	}
+	e = Jackknife(n, plugin)
+	if got, want := e.Estimate-e.Bias(), Variance(x, nil); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-12) {
+		t.Errorf("unexpected bias corrected variance: got %v, want %v", got, want)
+	}
+}
+
+func TestBootstrapPanics(t *testing.T) {
+	t.Parallel()
+	stat := func([]int) float64 { return 0 }
+	e := Bootstrap{Replicates: 10, Src: rand.NewSource(1)}.Resample(5, stat)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "no observations", fn: func() { Bootstrap{}.Resample(0, stat) }},
+		{name: "negative replicates", fn: func() { Bootstrap{Replicates: -1}.Resample(5, stat) }},
+		{name: "strata length", fn: func() { Bootstrap{Strata: []int{1}}.Resample(5, stat) }},
+		{name: "stratified blocks", fn: func() { Bootstrap{Strata: make([]int, 5), BlockLen: 2}.Resample(5, stat) }},
+		{name: "long block", fn: func() { Bootstrap{BlockLen: 6}.Resample(5, stat) }},
+		{name: "percentile level", fn: func() { e.PercentileInterval(1) }},
+		{name: "BCa level", fn: func() { e.BCaInterval(0) }},
+		{name: "jackknife observations", fn: func() { Jackknife(1, stat) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
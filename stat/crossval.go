@@ -0,0 +1,190 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// Fold is a split of the observations of a data set into a training set and a
+// test set for cross-validation. Train and Test hold the indices of the
+// observations in each set in increasing order.
+type Fold struct {
+	Train, Test []int
+}
+
+// KFold splits observations into K folds of nearly equal size for k-fold
+// cross-validation. Each observation is in the test set of exactly one fold
+// and in the training set of the others.
+type KFold struct {
+	// K is the number of folds.
+	K int
+
+	// Shuffle specifies whether the observations are randomly
+	// assigned to folds. If Shuffle is false, the test sets are
+	// contiguous ranges of observations.
+	Shuffle bool
+
+	// Src is the source of randomness for shuffling. If Src is nil,
+	// the global source of golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// Split returns the folds for n observations. The first n%K test sets have
+// one more observation than the others. Split panics if K is less than two
+// or greater than n.
+func (k KFold) Split(n int) []Fold {
+	if k.K < 2 || k.K > n {
+		panic("stat: invalid number of folds")
+	}
+	order := identityPerm(n)
+	if k.Shuffle {
+		order = perm(n, k.Src)
+	}
+	fold := make([]int, n)
+	var start int
+	for f := 0; f < k.K; f++ {
+		size := n / k.K
+		if f < n%k.K {
+			size++
+		}
+		for _, i := range order[start : start+size] {
+			fold[i] = f
+		}
+		start += size
+	}
+	return foldsFromAssignment(fold, k.K)
+}
+
+// StratifiedKFold splits observations into K folds for k-fold
+// cross-validation such that the proportion of each class in every test set
+// is as close as possible to its proportion in the whole data set.
+type StratifiedKFold struct {
+	// K is the number of folds.
+	K int
+
+	// Shuffle specifies whether the observations within each class
+	// are randomly assigned to folds. If Shuffle is false, they are
+	// assigned in order.
+	Shuffle bool
+
+	// Src is the source of randomness for shuffling. If Src is nil,
+	// the global source of golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// Split returns the folds for observations with the given class labels. The
+// observations of each class are dealt to the folds in turn, continuing from
+// the fold following the last observation of the previous class so that the
+// test set sizes differ by at most one. Split panics if K is less than two or
+// greater than the number of observations.
+func (k StratifiedKFold) Split(classes []int) []Fold {
+	n := len(classes)
+	if k.K < 2 || k.K > n {
+		panic("stat: invalid number of folds")
+	}
+	order := identityPerm(n)
+	if k.Shuffle {
+		order = perm(n, k.Src)
+	}
+	// Group the observations by class, preserving their order.
+	sort.SliceStable(order, func(a, b int) bool { return classes[order[a]] < classes[order[b]] })
+	fold := make([]int, n)
+	for j, i := range order {
+		fold[i] = j % k.K
+	}
+	return foldsFromAssignment(fold, k.K)
+}
+
+// TimeSeriesSplit splits a time series into K folds for forward-chaining
+// cross-validation. The observations are assumed to be in time order, and the
+// test set of each fold immediately follows its training set, so that models
+// are never evaluated on observations preceding their training data.
+type TimeSeriesSplit struct {
+	// K is the number of folds.
+	K int
+
+	// MaxTrain is the maximum size of the training sets. If MaxTrain
+	// is zero, each training set includes all observations before
+	// its test set.
+	MaxTrain int
+
+	// Gap is the number of observations excluded between the end of
+	// each training set and the start of its test set.
+	Gap int
+}
+
+// Split returns the folds for n observations. The test sets are consecutive
+// blocks of n/(K+1) observations ending at the last observation, and the
+// training set of each fold is the observations preceding its test set less
+// Gap observations. Split panics if K is less than one, if MaxTrain or Gap is
+// negative, or if the first training set would be empty.
+func (s TimeSeriesSplit) Split(n int) []Fold {
+	if s.K < 1 {
+		panic("stat: invalid number of folds")
+	}
+	if s.MaxTrain < 0 || s.Gap < 0 {
+		panic("stat: negative time series split parameter")
+	}
+	size := n / (s.K + 1)
+	first := n - s.K*size
+	if size == 0 || first-s.Gap <= 0 {
+		panic("stat: too few observations for time series split")
+	}
+	folds := make([]Fold, s.K)
+	for f := range folds {
+		start := first + f*size
+		end := start - s.Gap
+		begin := 0
+		if s.MaxTrain > 0 && end-s.MaxTrain > 0 {
+			begin = end - s.MaxTrain
+		}
+		folds[f] = Fold{
+			Train: indexRange(begin, end),
+			Test:  indexRange(start, start+size),
+		}
+	}
+	return folds
+}
+
+// foldsFromAssignment returns the k folds in which the test set of fold f is
+// the observations i with fold[i] == f.
+func foldsFromAssignment(fold []int, k int) []Fold {
+	folds := make([]Fold, k)
+	for i, f := range fold {
+		for g := range folds {
+			if g == f {
+				folds[g].Test = append(folds[g].Test, i)
+			} else {
+				folds[g].Train = append(folds[g].Train, i)
+			}
+		}
+	}
+	return folds
+}
+
+// perm returns a random permutation of [0, n) using src, or the global source
+// if src is nil.
+func perm(n int, src rand.Source) []int {
+	if src == nil {
+		return rand.Perm(n)
+	}
+	return rand.New(src).Perm(n)
+}
+
+func identityPerm(n int) []int {
+	return indexRange(0, n)
+}
+
+// indexRange returns the indices in [lo, hi).
+func indexRange(lo, hi int) []int {
+	idx := make([]int, hi-lo)
+	for i := range idx {
+		idx[i] = lo + i
+	}
+	return idx
+}
@@ -0,0 +1,167 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// checkPartition checks that each fold splits the n observations into
+// disjoint, sorted training and test sets, and returns the number of times
+// each observation appears in a test set.
+func checkPartition(t *testing.T, name string, folds []Fold, n int) []int {
+	t.Helper()
+	inTest := make([]int, n)
+	for f, fold := range folds {
+		if !sort.IntsAreSorted(fold.Train) || !sort.IntsAreSorted(fold.Test) {
+			t.Errorf("%s: fold %d indices not sorted", name, f)
+		}
+		if len(fold.Train)+len(fold.Test) != n {
+			t.Errorf("%s: fold %d does not cover all observations", name, f)
+		}
+		seen := make([]bool, n)
+		for _, i := range append(append([]int(nil), fold.Train...), fold.Test...) {
+			if seen[i] {
+				t.Errorf("%s: fold %d has observation %d in both sets", name, f, i)
+			}
+			seen[i] = true
+		}
+		for _, i := range fold.Test {
+			inTest[i]++
+		}
+	}
+	return inTest
+}
+
+func TestKFold(t *testing.T) {
+	t.Parallel()
+	const n = 23
+	for _, shuffle := range []bool{false, true} {
+		folds := KFold{K: 5, Shuffle: shuffle, Src: rand.NewSource(1)}.Split(n)
+		if len(folds) != 5 {
+			t.Fatalf("shuffle=%t: unexpected number of folds: got %d, want 5", shuffle, len(folds))
+		}
+		for i, c := range checkPartition(t, "kfold", folds, n) {
+			if c != 1 {
+				t.Errorf("shuffle=%t: observation %d in %d test sets", shuffle, i, c)
+			}
+		}
+		for f, fold := range folds {
+			want := 4
+			if f < 3 {
+				want = 5
+			}
+			if len(fold.Test) != want {
+				t.Errorf("shuffle=%t: unexpected size of test set %d: got %d, want %d", shuffle, f, len(fold.Test), want)
+			}
+		}
+		if !shuffle && !reflect.DeepEqual(folds[1].Test, []int{5, 6, 7, 8, 9}) {
+			t.Errorf("unexpected unshuffled test set: got %v", folds[1].Test)
+		}
+	}
+	a := KFold{K: 4, Shuffle: true, Src: rand.NewSource(2)}.Split(20)
+	b := KFold{K: 4, Shuffle: true, Src: rand.NewSource(2)}.Split(20)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("shuffled folds not reproducible with the same source")
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 60
+	classes := make([]int, n)
+	count := make(map[int]int)
+	for i := range classes {
+		// Classes in proportion 3:2:1.
+		switch u := rnd.Intn(6); {
+		case u < 3:
+			classes[i] = 0
+		case u < 5:
+			classes[i] = 1
+		default:
+			classes[i] = 2
+		}
+		count[classes[i]]++
+	}
+	const k = 4
+	for _, shuffle := range []bool{false, true} {
+		folds := StratifiedKFold{K: k, Shuffle: shuffle, Src: rand.NewSource(1)}.Split(classes)
+		for i, c := range checkPartition(t, "stratified", folds, n) {
+			if c != 1 {
+				t.Errorf("shuffle=%t: observation %d in %d test sets", shuffle, i, c)
+			}
+		}
+		for f, fold := range folds {
+			if len(fold.Test) != n/k {
+				t.Errorf("shuffle=%t: unexpected size of test set %d: got %d, want %d", shuffle, f, len(fold.Test), n/k)
+			}
+			got := make(map[int]int)
+			for _, i := range fold.Test {
+				got[classes[i]]++
+			}
+			for c, m := range count {
+				if got[c] < m/k || got[c] > m/k+1 {
+					t.Errorf("shuffle=%t: fold %d has %d of %d observations of class %d", shuffle, f, got[c], m, c)
+				}
+			}
+		}
+	}
+}
+
+func TestTimeSeriesSplit(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		split TimeSeriesSplit
+		n     int
+		want  []Fold
+	}{
+		{
+			split: TimeSeriesSplit{K: 3},
+			n:     9,
+			want: []Fold{
+				{Train: []int{0, 1, 2}, Test: []int{3, 4}},
+				{Train: []int{0, 1, 2, 3, 4}, Test: []int{5, 6}},
+				{Train: []int{0, 1, 2, 3, 4, 5, 6}, Test: []int{7, 8}},
+			},
+		},
+		{
+			split: TimeSeriesSplit{K: 2, MaxTrain: 2, Gap: 1},
+			n:     10,
+			want: []Fold{
+				{Train: []int{1, 2}, Test: []int{4, 5, 6}},
+				{Train: []int{4, 5}, Test: []int{7, 8, 9}},
+			},
+		},
+	} {
+		got := test.split.Split(test.n)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected folds for %+v with n=%d:\ngot:  %v\nwant: %v", test.split, test.n, got, test.want)
+		}
+	}
+}
+
+func TestSplitPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "kfold one fold", fn: func() { KFold{K: 1}.Split(10) }},
+		{name: "kfold too many folds", fn: func() { KFold{K: 11}.Split(10) }},
+		{name: "stratified too many folds", fn: func() { StratifiedKFold{K: 4}.Split([]int{0, 1, 0}) }},
+		{name: "time series no folds", fn: func() { TimeSeriesSplit{}.Split(10) }},
+		{name: "time series too short", fn: func() { TimeSeriesSplit{K: 5}.Split(5) }},
+		{name: "time series gap", fn: func() { TimeSeriesSplit{K: 2, Gap: 4}.Split(9) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
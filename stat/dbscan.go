@@ -0,0 +1,465 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// Noise is the cluster label given to points that do not belong to any
+// cluster in a density-based clustering.
+const Noise = -1
+
+// Neighbor is a point in a Neighborhood and its distance from a query point.
+type Neighbor struct {
+	Index    int
+	Distance float64
+}
+
+// Neighborhood is a set of points that can be queried for the points lying
+// within a radius of each of its members. It is used by the density-based
+// clustering algorithms DBSCAN and OPTICS.
+type Neighborhood interface {
+	// Len returns the number of points in the set.
+	Len() int
+
+	// Neighbors appends to dst the points within distance eps of
+	// the ith point, including the ith point itself, and returns
+	// the extended slice. The neighbors may be in any order.
+	Neighbors(dst []Neighbor, i int, eps float64) []Neighbor
+}
+
+// KDNeighborhood is a Neighborhood of the rows of a matrix under the Euclidean
+// distance. Queries are answered with a k-d tree, so finding the neighbors
+// of a point takes O(log n) time for small radii in low dimensions.
+type KDNeighborhood struct {
+	tree   *kdtree.Tree
+	points []kdPoint
+}
+
+// NewKDNeighborhood returns a KDNeighborhood of the rows of x. The k-d tree is
+// built from a copy of x, so later changes to x are not reflected in the
+// returned neighborhood.
+func NewKDNeighborhood(x mat.Matrix) *KDNeighborhood {
+	n, _ := x.Dims()
+	xd := mat.DenseCopyOf(x)
+	points := make([]kdPoint, n)
+	for i := range points {
+		points[i] = kdPoint{x: xd.RawRowView(i), index: i}
+	}
+	tree := kdtree.New(kdPoints(append([]kdPoint(nil), points...)), false)
+	return &KDNeighborhood{tree: tree, points: points}
+}
+
+// Len returns the number of points in the neighborhood.
+func (nb *KDNeighborhood) Len() int { return len(nb.points) }
+
+// Neighbors appends to dst the points within Euclidean distance eps of the
+// ith point and returns the extended slice.
+func (nb *KDNeighborhood) Neighbors(dst []Neighbor, i int, eps float64) []Neighbor {
+	keep := kdtree.NewDistKeeper(eps * eps)
+	nb.tree.NearestSet(keep, nb.points[i])
+	for _, c := range keep.Heap {
+		dst = append(dst, Neighbor{Index: c.Comparable.(kdPoint).index, Distance: math.Sqrt(c.Dist)})
+	}
+	return dst
+}
+
+// kdPoint is a row of a matrix stored in a k-d tree along with its row index.
+type kdPoint struct {
+	x     []float64
+	index int
+}
+
+func (p kdPoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.x[d] - c.(kdPoint).x[d]
+}
+
+func (p kdPoint) Dims() int { return len(p.x) }
+
+func (p kdPoint) Distance(c kdtree.Comparable) float64 {
+	return sqEuclidean(p.x, c.(kdPoint).x)
+}
+
+// kdPoints is a collection of kdPoint values for construction of a k-d tree.
+// Pivots are found by sorting rather than by the random sampling of
+// kdtree.Points so that building the tree does not use the global source of
+// randomness and the resulting clusterings are reproducible.
+type kdPoints []kdPoint
+
+func (p kdPoints) Index(i int) kdtree.Comparable         { return p[i] }
+func (p kdPoints) Len() int                              { return len(p) }
+func (p kdPoints) Slice(start, end int) kdtree.Interface { return p[start:end] }
+func (p kdPoints) Pivot(d kdtree.Dim) int {
+	sort.Stable(kdPlane{points: p, dim: d})
+	return len(p) / 2
+}
+
+// kdPlane sorts kdPoints along a dimension.
+type kdPlane struct {
+	points kdPoints
+	dim    kdtree.Dim
+}
+
+func (p kdPlane) Len() int           { return len(p.points) }
+func (p kdPlane) Less(i, j int) bool { return p.points[i].x[p.dim] < p.points[j].x[p.dim] }
+func (p kdPlane) Swap(i, j int)      { p.points[i], p.points[j] = p.points[j], p.points[i] }
+
+// MetricNeighborhood is a Neighborhood of the rows of a matrix under an
+// arbitrary distance function. Queries compare the query point with every
+// point, so finding the neighbors of a point takes O(n) time.
+type MetricNeighborhood struct {
+	points   [][]float64
+	distance func(a, b []float64) float64
+}
+
+// NewMetricNeighborhood returns a MetricNeighborhood of the rows of x under
+// the given distance. The distance must be symmetric for the density-based
+// clusterings to be well defined. If distance is nil, the Euclidean distance
+// is used. The rows are copied from x, so later changes to x are not reflected
+// in the returned neighborhood.
+func NewMetricNeighborhood(x mat.Matrix, distance func(a, b []float64) float64) *MetricNeighborhood {
+	if distance == nil {
+		distance = func(a, b []float64) float64 { return math.Sqrt(sqEuclidean(a, b)) }
+	}
+	n, _ := x.Dims()
+	xd := mat.DenseCopyOf(x)
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = xd.RawRowView(i)
+	}
+	return &MetricNeighborhood{points: points, distance: distance}
+}
+
+// Len returns the number of points in the neighborhood.
+func (nb *MetricNeighborhood) Len() int { return len(nb.points) }
+
+// Neighbors appends to dst the points within distance eps of the ith point
+// and returns the extended slice.
+func (nb *MetricNeighborhood) Neighbors(dst []Neighbor, i int, eps float64) []Neighbor {
+	p := nb.points[i]
+	for j, q := range nb.points {
+		if d := nb.distance(p, q); d <= eps {
+			dst = append(dst, Neighbor{Index: j, Distance: d})
+		}
+	}
+	return dst
+}
+
+// DBSCAN performs the density-based clustering of Ester et al. on the points
+// in nb and returns the cluster label of each point and the number of
+// clusters found.
+//
+// A point is a core point if at least minPts points, including itself, lie
+// within distance eps of it. Clusters are the maximal sets of points that are
+// connected through chains of core points within distance eps of each other,
+// together with the non-core points within distance eps of a core point of
+// the cluster. Clusters are labeled from zero in the order of their lowest
+// indexed core point, and points that belong to no cluster are labeled Noise.
+// A non-core point within reach of more than one cluster is assigned to the
+// first cluster found.
+//  Ester, M. et al. "A density-based algorithm for discovering clusters in
+//  large spatial databases with noise." KDD '96 (1996).
+//
+// If dst is not nil the labels are stored in dst, which must have length
+// nb.Len(), and it is returned. DBSCAN will panic if eps is negative, if
+// minPts is less than one or if the length of a non-nil dst does not match
+// the number of points.
+func DBSCAN(dst []int, nb Neighborhood, eps float64, minPts int) (labels []int, clusters int) {
+	n := nb.Len()
+	checkDensityParams(eps, minPts)
+	if dst == nil {
+		dst = make([]int, n)
+	}
+	if len(dst) != n {
+		panic("stat: slice length mismatch")
+	}
+	const unvisited = Noise - 1
+	for i := range dst {
+		dst[i] = unvisited
+	}
+
+	var neighbors []Neighbor
+	var queue []int
+	for i := range dst {
+		if dst[i] != unvisited {
+			continue
+		}
+		neighbors = nb.Neighbors(neighbors[:0], i, eps)
+		if len(neighbors) < minPts {
+			dst[i] = Noise
+			continue
+		}
+		c := clusters
+		clusters++
+		dst[i] = c
+		queue = queue[:0]
+		for _, q := range neighbors {
+			queue = append(queue, q.Index)
+		}
+		for len(queue) != 0 {
+			j := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			switch dst[j] {
+			case Noise:
+				// A point previously found to be noise
+				// is a border point of this cluster.
+				dst[j] = c
+				continue
+			case unvisited:
+				dst[j] = c
+			default:
+				continue
+			}
+			neighbors = nb.Neighbors(neighbors[:0], j, eps)
+			if len(neighbors) < minPts {
+				continue
+			}
+			for _, q := range neighbors {
+				if dst[q.Index] == unvisited || dst[q.Index] == Noise {
+					queue = append(queue, q.Index)
+				}
+			}
+		}
+	}
+	return dst, clusters
+}
+
+// OPTICS is a type for computing the density-based cluster ordering of
+// Ankerst et al. The ordering describes the density-based clustering
+// structure of the points for all radii up to a maximum at once, so that
+// DBSCAN clusterings for any smaller radius can be extracted without further
+// neighborhood queries, and clusters of differing density can be identified
+// from the reachability plot.
+//  Ankerst, M. et al. "OPTICS: Ordering points to identify the clustering
+//  structure." SIGMOD '99 (1999).
+type OPTICS struct {
+	order []int
+	reach []float64
+	core  []float64
+	ok    bool
+}
+
+// Cluster computes the cluster ordering of the points in nb using neighborhoods
+// of radius eps, in which a point is a core point if at least minPts points,
+// including itself, lie within the neighborhood. Cluster will panic if eps is
+// negative or minPts is less than one.
+func (o *OPTICS) Cluster(nb Neighborhood, eps float64, minPts int) {
+	n := nb.Len()
+	checkDensityParams(eps, minPts)
+	o.ok = false
+	o.order = useInts(o.order, n)[:0]
+	o.reach = useFloats(o.reach, n)
+	o.core = useFloats(o.core, n)
+	for i := range o.reach {
+		o.reach[i] = math.Inf(1)
+	}
+
+	processed := make([]bool, n)
+	var neighbors []Neighbor
+	var dists []float64
+	var seeds reachHeap
+	process := func(i int) {
+		processed[i] = true
+		o.order = append(o.order, i)
+		neighbors = nb.Neighbors(neighbors[:0], i, eps)
+		o.core[i] = math.Inf(1)
+		if len(neighbors) < minPts {
+			return
+		}
+		dists = dists[:0]
+		for _, q := range neighbors {
+			dists = append(dists, q.Distance)
+		}
+		sort.Float64s(dists)
+		core := dists[minPts-1]
+		o.core[i] = core
+		for _, q := range neighbors {
+			if processed[q.Index] {
+				continue
+			}
+			r := math.Max(core, q.Distance)
+			if r < o.reach[q.Index] {
+				o.reach[q.Index] = r
+				heap.Push(&seeds, reachItem{index: q.Index, reach: r})
+			}
+		}
+	}
+	for i := range processed {
+		if processed[i] {
+			continue
+		}
+		process(i)
+		for seeds.Len() != 0 {
+			s := heap.Pop(&seeds).(reachItem)
+			// Seeds are not removed when their reachability
+			// decreases, so skip stale entries.
+			if processed[s.index] || s.reach != o.reach[s.index] {
+				continue
+			}
+			process(s.index)
+		}
+	}
+	o.ok = true
+}
+
+// Ordering returns the cluster ordering of the points. If dst is not nil it
+// is used to store the ordering and returned. Ordering will panic if the
+// receiver does not contain an ordering or dst is not nil and the length of
+// dst is not the number of points.
+func (o *OPTICS) Ordering(dst []int) []int {
+	if !o.ok {
+		panic("stat: use of unordered OPTICS")
+	}
+	if dst == nil {
+		dst = make([]int, len(o.order))
+	}
+	if len(dst) != len(o.order) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, o.order)
+	return dst
+}
+
+// Reachability returns the reachability distance of each point, indexed by
+// point rather than by position in the ordering. The reachability distance
+// of a point is +Inf if it is not within the neighborhood of a core point
+// that precedes it in the ordering. If dst is not nil it is used to store the
+// distances and returned. Reachability will panic if the receiver does not
+// contain an ordering or dst is not nil and the length of dst is not the
+// number of points.
+func (o *OPTICS) Reachability(dst []float64) []float64 {
+	if !o.ok {
+		panic("stat: use of unordered OPTICS")
+	}
+	if dst == nil {
+		dst = make([]float64, len(o.reach))
+	}
+	if len(dst) != len(o.reach) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, o.reach)
+	return dst
+}
+
+// CoreDistances returns the core distance of each point, the smallest radius
+// for which the point is a core point. The core distance is +Inf for points
+// that are not core points for the radius used by Cluster. If dst is not nil
+// it is used to store the distances and returned. CoreDistances will panic if
+// the receiver does not contain an ordering or dst is not nil and the length
+// of dst is not the number of points.
+func (o *OPTICS) CoreDistances(dst []float64) []float64 {
+	if !o.ok {
+		panic("stat: use of unordered OPTICS")
+	}
+	if dst == nil {
+		dst = make([]float64, len(o.core))
+	}
+	if len(dst) != len(o.core) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, o.core)
+	return dst
+}
+
+// Labels returns the DBSCAN clustering for the radius eps extracted from the
+// cluster ordering, and the number of clusters found. The core points and
+// their clusters are the same as those found by DBSCAN with the same minPts,
+// but non-core points within reach of more than one cluster may be assigned
+// differently. Clusters are labeled from zero in the order they appear in the
+// ordering, and points that belong to no cluster are labeled Noise.
+//
+// The extracted clustering is only meaningful if eps is no greater than the
+// radius used by Cluster.
+//
+// If dst is not nil the labels are stored in dst and it is returned. Labels
+// will panic if the receiver does not contain an ordering, if eps is negative
+// or if the length of a non-nil dst is not the number of points.
+func (o *OPTICS) Labels(dst []int, eps float64) (labels []int, clusters int) {
+	if !o.ok {
+		panic("stat: use of unordered OPTICS")
+	}
+	if eps < 0 {
+		panic("stat: negative radius")
+	}
+	if dst == nil {
+		dst = make([]int, len(o.order))
+	}
+	if len(dst) != len(o.order) {
+		panic("stat: slice length mismatch")
+	}
+	c := Noise
+	for _, i := range o.order {
+		if o.reach[i] > eps {
+			if o.core[i] > eps {
+				dst[i] = Noise
+				continue
+			}
+			c = clusters
+			clusters++
+		}
+		dst[i] = c
+	}
+	return dst, clusters
+}
+
+func checkDensityParams(eps float64, minPts int) {
+	if !(eps >= 0) {
+		panic("stat: negative radius")
+	}
+	if minPts < 1 {
+		panic("stat: minimum points less than one")
+	}
+}
+
+// reachItem is a seed point of an OPTICS expansion with its reachability.
+type reachItem struct {
+	index int
+	reach float64
+}
+
+// reachHeap is a min-heap of seed points ordered by reachability and then
+// by index.
+type reachHeap []reachItem
+
+func (h reachHeap) Len() int { return len(h) }
+func (h reachHeap) Less(i, j int) bool {
+	if h[i].reach != h[j].reach {
+		return h[i].reach < h[j].reach
+	}
+	return h[i].index < h[j].index
+}
+func (h reachHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *reachHeap) Push(x interface{}) { *h = append(*h, x.(reachItem)) }
+func (h *reachHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// useInts returns an int slice with l elements, using s if it has sufficient
+// capacity.
+func useInts(s []int, l int) []int {
+	if l <= cap(s) {
+		return s[:l]
+	}
+	return make([]int, l)
+}
+
+// useFloats returns a float64 slice with l elements, using s if it has
+// sufficient capacity.
+func useFloats(s []float64, l int) []float64 {
+	if l <= cap(s) {
+		return s[:l]
+	}
+	return make([]float64, l)
+}
@@ -0,0 +1,219 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// densityData returns dense blobs around the given centers and uniformly
+// scattered outliers far from any blob.
+func densityData(rnd *rand.Rand) (x *mat.Dense, labels []int) {
+	centers := [][]float64{{0, 0}, {10, 0}, {5, 10}}
+	x, labels = kmeansBlobs(rnd, centers, 40, 0.4)
+	outliers := [][]float64{{-20, -20}, {30, 30}, {-20, 30}, {30, -20}, {5, 50}}
+	n, d := x.Dims()
+	all := mat.NewDense(n+len(outliers), d, nil)
+	all.Copy(x)
+	for i, p := range outliers {
+		all.SetRow(n+i, p)
+		labels = append(labels, Noise)
+	}
+	return all, labels
+}
+
+func TestKDNeighborhood(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, _ := densityData(rnd)
+	n, _ := x.Dims()
+	kd := NewKDNeighborhood(x)
+	brute := NewMetricNeighborhood(x, nil)
+	if kd.Len() != n || brute.Len() != n {
+		t.Fatalf("unexpected length: got %d and %d, want %d", kd.Len(), brute.Len(), n)
+	}
+	byIndex := func(nb []Neighbor) func(i, j int) bool {
+		return func(i, j int) bool { return nb[i].Index < nb[j].Index }
+	}
+	for _, eps := range []float64{0, 0.5, 2, 100} {
+		for i := 0; i < n; i++ {
+			got := kd.Neighbors(nil, i, eps)
+			want := brute.Neighbors(nil, i, eps)
+			sort.Slice(got, byIndex(got))
+			sort.Slice(want, byIndex(want))
+			if len(got) != len(want) {
+				t.Errorf("unexpected number of neighbors of %d for eps=%v: got %d, want %d", i, eps, len(got), len(want))
+				continue
+			}
+			for k := range got {
+				if got[k].Index != want[k].Index || !scalar.EqualWithinAbsOrRel(got[k].Distance, want[k].Distance, 1e-12, 1e-12) {
+					t.Errorf("unexpected neighbor of %d for eps=%v: got %v, want %v", i, eps, got[k], want[k])
+				}
+			}
+		}
+	}
+}
+
+func TestDBSCAN(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, want := densityData(rnd)
+
+	for _, nb := range []Neighborhood{NewKDNeighborhood(x), NewMetricNeighborhood(x, nil)} {
+		labels, clusters := DBSCAN(nil, nb, 1.5, 5)
+		if clusters != 3 {
+			t.Errorf("%T: unexpected number of clusters: got %d, want 3", nb, clusters)
+		}
+		// The blobs are labeled in order of their first points.
+		for i, l := range labels {
+			if l != want[i] {
+				t.Errorf("%T: unexpected label for point %d: got %d, want %d", nb, i, l, want[i])
+			}
+		}
+	}
+
+	// A radius covering all points gives a single cluster,
+	// and a minimum larger than the sample gives only noise.
+	nb := NewKDNeighborhood(x)
+	n := nb.Len()
+	labels := make([]int, n)
+	if _, clusters := DBSCAN(labels, nb, 1000, 1); clusters != 1 {
+		t.Errorf("unexpected number of clusters for large radius: got %d, want 1", clusters)
+	}
+	if _, clusters := DBSCAN(labels, nb, 1000, n+1); clusters != 0 {
+		t.Errorf("unexpected number of clusters for large minimum: got %d, want 0", clusters)
+	}
+	for i, l := range labels {
+		if l != Noise {
+			t.Errorf("unexpected label for point %d: got %d, want noise", i, l)
+		}
+	}
+
+	// With minPts of one every point is a core point.
+	_, clusters := DBSCAN(labels, nb, 0, 1)
+	if clusters != n {
+		t.Errorf("unexpected number of clusters for zero radius: got %d, want %d", clusters, n)
+	}
+}
+
+func TestDBSCANBorder(t *testing.T) {
+	t.Parallel()
+	// Point 0 is a border point of the cluster of points 1 to 3,
+	// but is visited before any of them.
+	x := mat.NewDense(5, 1, []float64{0, 1, 1.1, 1.2, 10})
+	labels, clusters := DBSCAN(nil, NewMetricNeighborhood(x, nil), 1, 3)
+	want := []int{0, 0, 0, 0, Noise}
+	if clusters != 1 {
+		t.Errorf("unexpected number of clusters: got %d, want 1", clusters)
+	}
+	for i, l := range labels {
+		if l != want[i] {
+			t.Errorf("unexpected label for point %d: got %d, want %d", i, l, want[i])
+		}
+	}
+}
+
+func TestOPTICS(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, _ := densityData(rnd)
+	nb := NewKDNeighborhood(x)
+	n := nb.Len()
+	const minPts = 5
+
+	var o OPTICS
+	o.Cluster(nb, 10, minPts)
+
+	order := o.Ordering(nil)
+	seen := make([]bool, n)
+	for _, i := range order {
+		if seen[i] {
+			t.Fatalf("point %d appears more than once in ordering", i)
+		}
+		seen[i] = true
+	}
+	if len(order) != n {
+		t.Fatalf("unexpected ordering length: got %d, want %d", len(order), n)
+	}
+
+	reach := o.Reachability(nil)
+	core := o.CoreDistances(nil)
+	var buf []Neighbor
+	for i := 0; i < n; i++ {
+		buf = nb.Neighbors(buf[:0], i, math.Inf(1))
+		d := make([]float64, len(buf))
+		for k, v := range buf {
+			d[k] = v.Distance
+		}
+		sort.Float64s(d)
+		want := d[minPts-1]
+		if want > 10 {
+			want = math.Inf(1)
+		}
+		if !scalar.EqualWithinAbsOrRel(core[i], want, 1e-12, 1e-12) && !(math.IsInf(core[i], 1) && math.IsInf(want, 1)) {
+			t.Errorf("unexpected core distance for point %d: got %v, want %v", i, core[i], want)
+		}
+		if reach[i] < 0 {
+			t.Errorf("negative reachability for point %d", i)
+		}
+	}
+	if !math.IsInf(reach[order[0]], 1) {
+		t.Errorf("unexpected reachability of first point: got %v, want +Inf", reach[order[0]])
+	}
+
+	// Extracted clusterings agree with DBSCAN on core points.
+	for _, eps := range []float64{0.5, 1, 1.5, 3, 10} {
+		got, gotClusters := o.Labels(nil, eps)
+		want, wantClusters := DBSCAN(nil, nb, eps, minPts)
+		if gotClusters != wantClusters {
+			t.Errorf("unexpected number of clusters for eps=%v: got %d, want %d", eps, gotClusters, wantClusters)
+		}
+		mapping := make(map[int]int)
+		for i := range got {
+			if core[i] > eps {
+				continue
+			}
+			if got[i] == Noise {
+				t.Errorf("core point %d labeled noise for eps=%v", i, eps)
+				continue
+			}
+			if m, ok := mapping[got[i]]; ok && m != want[i] {
+				t.Errorf("cluster %d does not match a DBSCAN cluster for eps=%v", got[i], eps)
+			}
+			mapping[got[i]] = want[i]
+		}
+	}
+}
+
+func TestDensityPanics(t *testing.T) {
+	t.Parallel()
+	nb := NewMetricNeighborhood(mat.NewDense(3, 1, []float64{1, 2, 3}), nil)
+	var o OPTICS
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "DBSCAN negative radius", fn: func() { DBSCAN(nil, nb, -1, 1) }},
+		{name: "DBSCAN zero minPts", fn: func() { DBSCAN(nil, nb, 1, 0) }},
+		{name: "DBSCAN dst length", fn: func() { DBSCAN(make([]int, 2), nb, 1, 1) }},
+		{name: "OPTICS negative radius", fn: func() { o.Cluster(nb, math.NaN(), 1) }},
+		{name: "OPTICS before Cluster", fn: func() { o.Ordering(nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+	o.Cluster(nb, 1, 2)
+	if !panics(func() { o.Labels(make([]int, 4), 1) }) {
+		t.Error("expected panic for Labels length mismatch")
+	}
+}
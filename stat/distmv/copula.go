@@ -0,0 +1,550 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const badPseudoObservation = "distmv: pseudo-observation not in (0, 1)"
+
+// PseudoObservations stores into dst the pseudo-observations of the rows of
+// x, the ranks of each column of x scaled by 1/(n+1) where n is the number of
+// rows. Tied values are assigned their average rank. The pseudo-observations
+// lie in (0, 1) and approximate a sample from the copula of the distribution
+// of the rows of x.
+//
+// If dst is empty, it is resized to the dimensions of x. PseudoObservations
+// panics if dst is not empty and its dimensions do not match those of x.
+func PseudoObservations(dst *mat.Dense, x mat.Matrix) {
+	n, d := x.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, d)
+	} else if r, c := dst.Dims(); r != n || c != d {
+		panic(badSizeMismatch)
+	}
+	col := make([]float64, n)
+	idx := make([]int, n)
+	for j := 0; j < d; j++ {
+		for i := range col {
+			col[i] = x.At(i, j)
+			idx[i] = i
+		}
+		sort.Slice(idx, func(a, b int) bool { return col[idx[a]] < col[idx[b]] })
+		for lo := 0; lo < n; {
+			hi := lo + 1
+			for hi < n && col[idx[hi]] == col[idx[lo]] {
+				hi++
+			}
+			// Ranks lo+1 through hi are tied.
+			r := float64(lo+hi+1) / 2
+			for _, i := range idx[lo:hi] {
+				dst.Set(i, j, r/float64(n+1))
+			}
+			lo = hi
+		}
+	}
+}
+
+// GaussianCopula is the copula of a multivariate normal distribution with the
+// given correlation matrix R. Its density is
+//  c(u) = |R|^(-1/2) exp(-1/2 zᵀ(R⁻¹ - I)z)
+// where z_i = Φ⁻¹(u_i) and Φ is the standard normal distribution function.
+//
+// For more information, see https://en.wikipedia.org/wiki/Copula_(probability_theory)#Gaussian_copula.
+type GaussianCopula struct {
+	dim        int
+	chol       mat.Cholesky
+	logSqrtDet float64
+	src        rand.Source
+}
+
+// NewGaussianCopula returns a new Gaussian copula with the given correlation
+// matrix. If the correlation matrix is not positive definite, nil is returned
+// and ok is false. NewGaussianCopula panics if the dimension of corr is zero
+// or if the diagonal of corr is not all ones.
+func NewGaussianCopula(corr mat.Symmetric, src rand.Source) (c *GaussianCopula, ok bool) {
+	dim := corr.SymmetricDim()
+	if dim == 0 {
+		panic(badZeroDimension)
+	}
+	for i := 0; i < dim; i++ {
+		if corr.At(i, i) != 1 {
+			panic("distmv: correlation matrix diagonal not one")
+		}
+	}
+	c = &GaussianCopula{dim: dim, src: src}
+	if ok := c.chol.Factorize(corr); !ok {
+		return nil, false
+	}
+	c.logSqrtDet = 0.5 * c.chol.LogDet()
+	return c, true
+}
+
+// FitGaussianCopula returns the Gaussian copula whose correlation matrix is
+// the sample correlation of the normal scores Φ⁻¹(u) of the rows of u. The
+// rows of u are typically the pseudo-observations of a sample as computed by
+// PseudoObservations. If weights is not nil, it specifies the weight of each
+// row. If the estimated correlation matrix is not positive definite, nil is
+// returned and ok is false.
+//
+// FitGaussianCopula panics if u has fewer than two rows, if an element of u
+// is not in (0, 1), or if weights is not nil and its length is not the number
+// of rows of u.
+func FitGaussianCopula(u mat.Matrix, weights []float64, src rand.Source) (c *GaussianCopula, ok bool) {
+	n, d := u.Dims()
+	if n < 2 {
+		panic("distmv: too few observations for copula fitting")
+	}
+	if weights != nil && len(weights) != n {
+		panic(badInputLength)
+	}
+	z := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			v := u.At(i, j)
+			if !(0 < v && v < 1) {
+				panic(badPseudoObservation)
+			}
+			z.Set(i, j, distuv.UnitNormal.Quantile(v))
+		}
+	}
+	var corr mat.SymDense
+	stat.CorrelationMatrix(&corr, z, weights)
+	// Guard against rounding leaving the diagonal away from one.
+	for i := 0; i < d; i++ {
+		corr.SetSym(i, i, 1)
+	}
+	return NewGaussianCopula(&corr, src)
+}
+
+// CorrelationMatrix stores the correlation matrix of the copula in dst.
+// If dst is empty, it is resized to the dimension of the copula, otherwise
+// CorrelationMatrix panics if the dimension of dst does not match that of the
+// copula.
+func (c *GaussianCopula) CorrelationMatrix(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(c.dim)
+	} else if dst.SymmetricDim() != c.dim {
+		panic(badSizeMismatch)
+	}
+	c.chol.ToSym(dst)
+}
+
+// Dim returns the dimension of the copula.
+func (c *GaussianCopula) Dim() int {
+	return c.dim
+}
+
+// LogProb computes the log of the density of the copula at u. LogProb
+// returns -∞ if u is not in the open unit hypercube and panics if len(u)
+// does not equal the dimension of the copula.
+func (c *GaussianCopula) LogProb(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	z := make([]float64, c.dim)
+	for i, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		z[i] = distuv.UnitNormal.Quantile(v)
+	}
+	zVec := mat.NewVecDense(c.dim, z)
+	var tmp mat.VecDense
+	err := c.chol.SolveVecTo(&tmp, zVec)
+	if err != nil {
+		return math.Inf(-1)
+	}
+	return -c.logSqrtDet - 0.5*(mat.Dot(zVec, &tmp)-floats.Dot(z, z))
+}
+
+// Prob computes the density of the copula at u.
+func (c *GaussianCopula) Prob(u []float64) float64 {
+	return math.Exp(c.LogProb(u))
+}
+
+// Rand generates a random sample from the copula. If u is nil, new memory is
+// allocated and returned, otherwise the result is stored in place into u.
+// Rand panics if u is not nil and its length does not equal the dimension of
+// the copula.
+func (c *GaussianCopula) Rand(u []float64) []float64 {
+	u = NormalRand(u, make([]float64, c.dim), &c.chol, c.src)
+	for i, z := range u {
+		u[i] = distuv.UnitNormal.CDF(z)
+	}
+	return u
+}
+
+// CopulaFamily specifies the family of an ArchimedeanCopula.
+type CopulaFamily int
+
+const (
+	// Clayton is the Clayton copula family with generator
+	//  ψ(t) = (1+t)^(-1/θ)
+	// for θ > 0. Clayton copulas have lower tail dependence.
+	Clayton CopulaFamily = iota
+
+	// Gumbel is the Gumbel copula family with generator
+	//  ψ(t) = exp(-t^(1/θ))
+	// for θ ≥ 1. Gumbel copulas have upper tail dependence and
+	// the independence copula is the special case θ = 1.
+	Gumbel
+
+	// Frank is the Frank copula family with generator
+	//  ψ(t) = -1/θ log(1 - (1-e^(-θ)) e^(-t))
+	// for θ > 0. Frank copulas have no tail dependence.
+	Frank
+)
+
+// ArchimedeanCopula is an Archimedean copula, the distribution function of
+// which is
+//  C(u) = ψ(ψ⁻¹(u_1) + ... + ψ⁻¹(u_d))
+// where ψ is the generator of the copula family with parameter θ.
+// The dependence between the variables increases with θ.
+//
+// For more information, see https://en.wikipedia.org/wiki/Copula_(probability_theory)#Archimedean_copulas.
+type ArchimedeanCopula struct {
+	family CopulaFamily
+	dim    int
+	theta  float64
+	src    rand.Source
+}
+
+// NewArchimedeanCopula returns a new Archimedean copula of the given family,
+// dimension and parameter. NewArchimedeanCopula panics if dim is not positive,
+// if the family is unknown or if theta is not in the domain of the family.
+func NewArchimedeanCopula(family CopulaFamily, dim int, theta float64, src rand.Source) *ArchimedeanCopula {
+	if dim <= 0 {
+		panic(nonPosDimension)
+	}
+	switch family {
+	case Clayton, Frank:
+		if !(theta > 0) || math.IsInf(theta, 1) {
+			panic("distmv: copula parameter out of range")
+		}
+	case Gumbel:
+		if !(theta >= 1) || math.IsInf(theta, 1) {
+			panic("distmv: copula parameter out of range")
+		}
+	default:
+		panic("distmv: unknown copula family")
+	}
+	return &ArchimedeanCopula{family: family, dim: dim, theta: theta, src: src}
+}
+
+// FitArchimedeanCopula returns the Archimedean copula of the given family
+// maximizing the pseudo-likelihood of the rows of u, which are typically the
+// pseudo-observations of a sample as computed by PseudoObservations. If
+// weights is not nil, it specifies the weight of each row.
+//
+// The parameter is found by golden section search, with θ in [1e-4, 1e3]
+// for the Clayton and Frank families and θ-1 in the same interval for the
+// Gumbel family.
+//
+// FitArchimedeanCopula panics if the family is unknown, if u has no rows, if
+// an element of u is not in (0, 1), or if weights is not nil and its length
+// is not the number of rows of u.
+func FitArchimedeanCopula(family CopulaFamily, u mat.Matrix, weights []float64, src rand.Source) *ArchimedeanCopula {
+	n, d := u.Dims()
+	if n == 0 {
+		panic("distmv: too few observations for copula fitting")
+	}
+	if weights != nil && len(weights) != n {
+		panic(badInputLength)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			if v := u.At(i, j); !(0 < v && v < 1) {
+				panic(badPseudoObservation)
+			}
+		}
+	}
+	var offset float64
+	if family == Gumbel {
+		offset = 1
+	}
+	c := NewArchimedeanCopula(family, d, offset+1, src)
+	row := make([]float64, d)
+	logLikelihood := func(logTheta float64) float64 {
+		c.theta = offset + math.Exp(logTheta)
+		var ll float64
+		for i := 0; i < n; i++ {
+			mat.Row(row, i, u)
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * c.LogProb(row)
+		}
+		return ll
+	}
+
+	const tol = 1e-8
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := math.Log(1e-4), math.Log(1e3)
+	c1 := b - invPhi*(b-a)
+	c2 := a + invPhi*(b-a)
+	f1, f2 := logLikelihood(c1), logLikelihood(c2)
+	for b-a > tol {
+		if f1 > f2 {
+			b, c2, f2 = c2, c1, f1
+			c1 = b - invPhi*(b-a)
+			f1 = logLikelihood(c1)
+		} else {
+			a, c1, f1 = c1, c2, f2
+			c2 = a + invPhi*(b-a)
+			f2 = logLikelihood(c2)
+		}
+	}
+	c.theta = offset + math.Exp((a+b)/2)
+	return c
+}
+
+// CDF returns the value of the distribution function of the copula at u.
+// CDF panics if len(u) does not equal the dimension of the copula.
+func (c *ArchimedeanCopula) CDF(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	var t float64
+	for _, v := range u {
+		if v <= 0 {
+			return 0
+		}
+		if v < 1 {
+			t += c.psiInv(v)
+		}
+	}
+	return c.psi(t)
+}
+
+// Dim returns the dimension of the copula.
+func (c *ArchimedeanCopula) Dim() int {
+	return c.dim
+}
+
+// Family returns the family of the copula.
+func (c *ArchimedeanCopula) Family() CopulaFamily {
+	return c.family
+}
+
+// LogProb computes the log of the density of the copula at u,
+//  log c(u) = log|ψ⁽ᵈ⁾(Σ_i ψ⁻¹(u_i))| + Σ_i log|(ψ⁻¹)'(u_i)|
+// LogProb returns -∞ if u is not in the open unit hypercube and panics if
+// len(u) does not equal the dimension of the copula.
+func (c *ArchimedeanCopula) LogProb(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	var t, lp float64
+	for _, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		t += c.psiInv(v)
+		lp += c.logPsiInvDeriv(v)
+	}
+	return lp + c.logPsiDeriv(t)
+}
+
+// Prob computes the density of the copula at u.
+func (c *ArchimedeanCopula) Prob(u []float64) float64 {
+	return math.Exp(c.LogProb(u))
+}
+
+// Rand generates a random sample from the copula. If u is nil, new memory is
+// allocated and returned, otherwise the result is stored in place into u.
+// Rand panics if u is not nil and its length does not equal the dimension of
+// the copula.
+//
+// Samples are generated by the Marshall-Olkin algorithm, which draws a
+// frailty V whose Laplace transform is the generator ψ and returns
+// u_i = ψ(E_i/V) for independent standard exponential E_i.
+//  Marshall, A. W., and I. Olkin. "Families of multivariate distributions."
+//  Journal of the American Statistical Association 83.403 (1988): 834-841.
+func (c *ArchimedeanCopula) Rand(u []float64) []float64 {
+	u = reuseAs(u, c.dim)
+	unifrnd := rand.Float64
+	exprnd := rand.ExpFloat64
+	if c.src != nil {
+		rnd := rand.New(c.src)
+		unifrnd = rnd.Float64
+		exprnd = rnd.ExpFloat64
+	}
+
+	var v float64
+	switch c.family {
+	case Clayton:
+		v = distuv.Gamma{Alpha: 1 / c.theta, Beta: 1, Src: c.src}.Rand()
+	case Gumbel:
+		// Positive stable variate with Laplace transform exp(-t^α)
+		// by the method of Kanter.
+		//  Kanter, M. "Stable densities under change of scale and total
+		//  variation inequalities." The Annals of Probability 3.4
+		//  (1975): 697-707.
+		alpha := 1 / c.theta
+		if alpha == 1 {
+			v = 1
+			break
+		}
+		theta := math.Pi * unifrnd()
+		w := exprnd()
+		v = math.Sin(alpha*theta) / math.Pow(math.Sin(theta), 1/alpha) *
+			math.Pow(math.Sin((1-alpha)*theta)/w, (1-alpha)/alpha)
+	case Frank:
+		// Logarithmic series variate with parameter 1-e^(-θ) by the
+		// LK algorithm.
+		//  Kemp, A. W. "Efficient generation of logarithmically
+		//  distributed pseudo-random variables." Journal of the Royal
+		//  Statistical Society: Series C 30.3 (1981): 249-253.
+		p := -math.Expm1(-c.theta)
+		u2 := unifrnd()
+		if u2 > p {
+			v = 1
+			break
+		}
+		q := -math.Expm1(-c.theta * unifrnd())
+		switch {
+		case u2 < q*q:
+			v = math.Floor(1 + math.Log(u2)/math.Log(q))
+		case u2 > q:
+			v = 1
+		default:
+			v = 2
+		}
+	}
+	for i := range u {
+		u[i] = c.psi(exprnd() / v)
+	}
+	return u
+}
+
+// Theta returns the parameter of the copula.
+func (c *ArchimedeanCopula) Theta() float64 {
+	return c.theta
+}
+
+// psi returns the generator of the copula at t.
+func (c *ArchimedeanCopula) psi(t float64) float64 {
+	switch c.family {
+	case Clayton:
+		return math.Exp(-math.Log1p(t) / c.theta)
+	case Gumbel:
+		return math.Exp(-math.Pow(t, 1/c.theta))
+	case Frank:
+		return -math.Log1p(math.Expm1(-c.theta)*math.Exp(-t)) / c.theta
+	}
+	panic("distmv: unknown copula family")
+}
+
+// psiInv returns the inverse of the generator of the copula at u.
+func (c *ArchimedeanCopula) psiInv(u float64) float64 {
+	switch c.family {
+	case Clayton:
+		return math.Expm1(-c.theta * math.Log(u))
+	case Gumbel:
+		return math.Pow(-math.Log(u), c.theta)
+	case Frank:
+		return -math.Log(math.Expm1(-c.theta*u) / math.Expm1(-c.theta))
+	}
+	panic("distmv: unknown copula family")
+}
+
+// logPsiInvDeriv returns the log of the absolute value of the derivative of
+// the inverse generator of the copula at u.
+func (c *ArchimedeanCopula) logPsiInvDeriv(u float64) float64 {
+	switch c.family {
+	case Clayton:
+		return math.Log(c.theta) - (c.theta+1)*math.Log(u)
+	case Gumbel:
+		l := -math.Log(u)
+		return math.Log(c.theta) + (c.theta-1)*math.Log(l) + l
+	case Frank:
+		return math.Log(c.theta) - math.Log(math.Expm1(c.theta*u))
+	}
+	panic("distmv: unknown copula family")
+}
+
+// logPsiDeriv returns the log of the absolute value of the dth derivative of
+// the generator of the copula at t, where d is the dimension of the copula.
+func (c *ArchimedeanCopula) logPsiDeriv(t float64) float64 {
+	d := c.dim
+	switch c.family {
+	case Clayton:
+		//  |ψ⁽ᵈ⁾(t)| = Π_{k=0}^{d-1} (1/θ + k) (1+t)^(-1/θ-d)
+		alpha := 1 / c.theta
+		var lp float64
+		for k := 0; k < d; k++ {
+			lp += math.Log(alpha + float64(k))
+		}
+		return lp - (alpha+float64(d))*math.Log1p(t)
+	case Gumbel:
+		// The dth derivative is
+		//  ψ⁽ᵈ⁾(t) = ψ(t) t^(-d) Σ_{k=1}^d a_k t^(kα)
+		// with α = 1/θ, where the coefficients follow from the
+		// recurrence of differentiating each term.
+		alpha := 1 / c.theta
+		a := make([]float64, d+1)
+		a[0] = 1
+		for n := 0; n < d; n++ {
+			for k := n + 1; k >= 1; k-- {
+				a[k] = (float64(k)*alpha-float64(n))*a[k] - alpha*a[k-1]
+			}
+			a[0] *= -float64(n)
+		}
+		ta := math.Pow(t, alpha)
+		var sum float64
+		for k := d; k >= 1; k-- {
+			sum = sum*ta + a[k]
+		}
+		sum *= ta
+		return -ta + math.Log(math.Abs(sum)) - float64(d)*math.Log(t)
+	case Frank:
+		// The dth derivative is
+		//  |ψ⁽ᵈ⁾(t)| = Li_{1-d}(z)/θ
+		// with z = (1-e^(-θ)) e^(-t), where the polylogarithm of
+		// non-positive order is
+		//  Li_{-n}(z) = Σ_{k=0}^n k! S(n+1, k+1) (z/(1-z))^(k+1)
+		// and S are Stirling numbers of the second kind.
+		z := -math.Expm1(-c.theta) * math.Exp(-t)
+		w := z / (1 - z)
+		s := stirling2Row(d)
+		var sum float64
+		fact := 1.0
+		wk := w
+		for k := 0; k < d; k++ {
+			if k > 0 {
+				fact *= float64(k)
+			}
+			sum += fact * s[k+1] * wk
+			wk *= w
+		}
+		return math.Log(sum) - math.Log(c.theta)
+	}
+	panic("distmv: unknown copula family")
+}
+
+// stirling2Row returns the Stirling numbers of the second kind S(n, k) for
+// k = 0, ..., n.
+func stirling2Row(n int) []float64 {
+	s := make([]float64, n+1)
+	s[0] = 1
+	for m := 1; m <= n; m++ {
+		for k := m; k >= 1; k-- {
+			s[k] = float64(k)*s[k] + s[k-1]
+		}
+		s[0] = 0
+	}
+	return s
+}
@@ -0,0 +1,264 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestPseudoObservations(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{
+		3, 1,
+		1, 5,
+		2, 5,
+		9, 0,
+	})
+	var u mat.Dense
+	PseudoObservations(&u, x)
+	want := mat.NewDense(4, 2, []float64{
+		3, 2,
+		1, 3.5,
+		2, 3.5,
+		4, 1,
+	})
+	want.Scale(1.0/5, want)
+	if !mat.EqualApprox(&u, want, 1e-15) {
+		t.Errorf("unexpected pseudo-observations:\ngot:\n%v\nwant:\n%v", mat.Formatted(&u), mat.Formatted(want))
+	}
+	if !panics(func() { PseudoObservations(mat.NewDense(3, 2, nil), x) }) {
+		t.Errorf("expected panic for dimension mismatch")
+	}
+}
+
+func TestGaussianCopula(t *testing.T) {
+	t.Parallel()
+	corr := mat.NewSymDense(3, []float64{
+		1, 0.6, -0.3,
+		0.6, 1, 0.2,
+		-0.3, 0.2, 1,
+	})
+	c, ok := NewGaussianCopula(corr, rand.NewSource(1))
+	if !ok {
+		t.Fatalf("bad test, correlation matrix not positive definite")
+	}
+	normal, _ := NewNormal(make([]float64, 3), corr, nil)
+
+	// The copula density is the ratio of the joint density to the
+	// product of the marginal densities.
+	for _, u := range [][]float64{{0.5, 0.5, 0.5}, {0.1, 0.8, 0.3}, {0.99, 0.95, 0.02}} {
+		z := make([]float64, len(u))
+		want := 0.0
+		for i, v := range u {
+			z[i] = distuv.UnitNormal.Quantile(v)
+			want -= distuv.UnitNormal.LogProb(z[i])
+		}
+		want += normal.LogProb(z)
+		if got := c.LogProb(u); !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+			t.Errorf("unexpected log density at %v: got %v, want %v", u, got, want)
+		}
+	}
+	if lp := c.LogProb([]float64{0, 0.5, 0.5}); !math.IsInf(lp, -1) {
+		t.Errorf("unexpected log density on boundary: %v", lp)
+	}
+
+	const n = 10000
+	u := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		c.Rand(u.RawRowView(i))
+	}
+	for j := 0; j < 3; j++ {
+		col := mat.Col(nil, j, u)
+		if m := floats.Sum(col) / n; math.Abs(m-0.5) > 0.01 {
+			t.Errorf("non-uniform margin %d: mean %v", j, m)
+		}
+	}
+
+	var pseudo mat.Dense
+	PseudoObservations(&pseudo, u)
+	fit, ok := FitGaussianCopula(&pseudo, nil, nil)
+	if !ok {
+		t.Fatalf("unexpected failure to fit copula")
+	}
+	var got mat.SymDense
+	fit.CorrelationMatrix(&got)
+	if !mat.EqualApprox(&got, corr, 0.03) {
+		t.Errorf("unexpected fitted correlation:\ngot:\n%v\nwant:\n%v", mat.Formatted(&got), mat.Formatted(corr))
+	}
+
+	if !panics(func() { NewGaussianCopula(mat.NewSymDense(2, []float64{2, 0, 0, 1}), nil) }) {
+		t.Errorf("expected panic for non-unit diagonal")
+	}
+	if !panics(func() { FitGaussianCopula(mat.NewDense(2, 2, []float64{0.5, 0.5, 1, 0.5}), nil, nil) }) {
+		t.Errorf("expected panic for observations outside the unit interval")
+	}
+}
+
+func TestArchimedeanCopula(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		family CopulaFamily
+		theta  float64
+		// tau is Kendall's rank correlation of the bivariate copula.
+		tau float64
+	}{
+		{family: Clayton, theta: 2, tau: 0.5},
+		{family: Clayton, theta: 0.5, tau: 0.2},
+		{family: Gumbel, theta: 2, tau: 0.5},
+		{family: Gumbel, theta: 1.25, tau: 0.2},
+		{family: Frank, theta: 5.736276, tau: 0.5},
+		{family: Frank, theta: 1.860884, tau: 0.2},
+	} {
+		for _, dim := range []int{2, 3, 4} {
+			c := NewArchimedeanCopula(test.family, dim, test.theta, rand.NewSource(1))
+
+			// The density is the mixed partial derivative of the
+			// distribution function.
+			h := 1e-4
+			if dim > 2 {
+				h = 1e-3
+			}
+			tol := 1e-5
+			if dim > 2 {
+				tol = 1e-3
+			}
+			for _, u := range [][]float64{
+				{0.5, 0.5, 0.5, 0.5},
+				{0.2, 0.7, 0.4, 0.9},
+				{0.9, 0.85, 0.8, 0.95},
+				{0.1, 0.05, 0.15, 0.1},
+			} {
+				u = u[:dim]
+				want := mixedDerivative(c.CDF, u, h)
+				if got := c.Prob(u); !scalar.EqualWithinAbsOrRel(got, want, tol, tol) {
+					t.Errorf("family %d theta %v dim %d: density mismatch at %v: got %v, want %v",
+						test.family, test.theta, dim, u, got, want)
+				}
+			}
+
+			const n = 5000
+			u := mat.NewDense(n, dim, nil)
+			for i := 0; i < n; i++ {
+				c.Rand(u.RawRowView(i))
+			}
+			for j := 0; j < dim; j++ {
+				col := mat.Col(nil, j, u)
+				if m := floats.Sum(col) / n; math.Abs(m-0.5) > 0.02 {
+					t.Errorf("family %d theta %v dim %d: non-uniform margin %d: mean %v",
+						test.family, test.theta, dim, j, m)
+				}
+			}
+			if got := kendallTau(mat.Col(nil, 0, u), mat.Col(nil, 1, u)); math.Abs(got-test.tau) > 0.03 {
+				t.Errorf("family %d theta %v dim %d: unexpected Kendall's tau: got %v, want %v",
+					test.family, test.theta, dim, got, test.tau)
+			}
+			for _, p := range [][]float64{{0.3, 0.3, 0.3, 0.3}, {0.8, 0.5, 0.9, 0.7}} {
+				p = p[:dim]
+				var count float64
+				for i := 0; i < n; i++ {
+					if allLess(u.RawRowView(i), p) {
+						count++
+					}
+				}
+				if got, want := count/n, c.CDF(p); math.Abs(got-want) > 0.02 {
+					t.Errorf("family %d theta %v dim %d: empirical CDF mismatch at %v: got %v, want %v",
+						test.family, test.theta, dim, p, got, want)
+				}
+			}
+
+			var pseudo mat.Dense
+			PseudoObservations(&pseudo, u)
+			fit := FitArchimedeanCopula(test.family, &pseudo, nil, nil)
+			if fit.Family() != test.family || fit.Dim() != dim {
+				t.Errorf("family %d theta %v dim %d: unexpected fitted copula family or dimension", test.family, test.theta, dim)
+			}
+			if got := fit.Theta(); math.Abs(got-test.theta) > 0.15*test.theta {
+				t.Errorf("family %d theta %v dim %d: unexpected fitted parameter: got %v", test.family, test.theta, dim, got)
+			}
+		}
+	}
+}
+
+func TestArchimedeanCopulaIndependence(t *testing.T) {
+	t.Parallel()
+	c := NewArchimedeanCopula(Gumbel, 3, 1, nil)
+	for _, u := range [][]float64{{0.5, 0.5, 0.5}, {0.1, 0.8, 0.3}} {
+		if lp := c.LogProb(u); math.Abs(lp) > 1e-14 {
+			t.Errorf("unexpected log density of independence copula at %v: %v", u, lp)
+		}
+		if got, want := c.CDF(u), u[0]*u[1]*u[2]; !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+			t.Errorf("unexpected distribution function of independence copula at %v: got %v, want %v", u, got, want)
+		}
+	}
+
+	for _, test := range []struct {
+		family CopulaFamily
+		theta  float64
+	}{
+		{family: Clayton, theta: 0},
+		{family: Gumbel, theta: 0.5},
+		{family: Frank, theta: -1},
+		{family: 10, theta: 1},
+	} {
+		if !panics(func() { NewArchimedeanCopula(test.family, 2, test.theta, nil) }) {
+			t.Errorf("expected panic for family %d with theta %v", test.family, test.theta)
+		}
+	}
+}
+
+// mixedDerivative returns the central difference estimate of the mixed
+// partial derivative of f with respect to every element of x.
+func mixedDerivative(f func([]float64) float64, x []float64, h float64) float64 {
+	d := len(x)
+	y := make([]float64, d)
+	var sum float64
+	for mask := 0; mask < 1<<uint(d); mask++ {
+		sign := 1.0
+		for i := range x {
+			if mask&(1<<uint(i)) != 0 {
+				y[i] = x[i] + h
+			} else {
+				y[i] = x[i] - h
+				sign = -sign
+			}
+		}
+		sum += sign * f(y)
+	}
+	return sum / math.Pow(2*h, float64(d))
+}
+
+// kendallTau returns Kendall's rank correlation of x and y, which must not
+// contain ties.
+func kendallTau(x, y []float64) float64 {
+	var s float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			if (x[i]-x[j])*(y[i]-y[j]) > 0 {
+				s++
+			} else {
+				s--
+			}
+		}
+	}
+	n := float64(len(x))
+	return 2 * s / (n * (n - 1))
+}
+
+func allLess(x, y []float64) bool {
+	for i, v := range x {
+		if v > y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,450 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// GaussianMixture is a finite mixture of multivariate normal distributions.
+// Its pdf is given by
+//  p(x) = Σ_j w_j N(x; μ_j, Σ_j)
+// where the non-negative mixing weights w_j sum to one. A GaussianMixture may
+// be constructed from its components with NewGaussianMixture or fit to data
+// with GaussianMixtureEM.
+type GaussianMixture struct {
+	weights    []float64
+	logWeights []float64
+	components []*Normal
+	dim        int
+
+	src rand.Source
+}
+
+// NewGaussianMixture returns a new mixture of the given normal distributions.
+// The weights are normalized to sum to one. The components are not copied.
+// NewGaussianMixture panics if there are no components, if the lengths of
+// weights and components differ, if the components do not all have the same
+// dimension, or if a weight is negative or all weights are zero.
+func NewGaussianMixture(weights []float64, components []*Normal, src rand.Source) *GaussianMixture {
+	if len(components) == 0 {
+		panic(badZeroDimension)
+	}
+	if len(weights) != len(components) {
+		panic(badSizeMismatch)
+	}
+	dim := components[0].Dim()
+	sum := 0.0
+	for i, c := range components {
+		if c.Dim() != dim {
+			panic(badSizeMismatch)
+		}
+		if !(weights[i] >= 0) {
+			panic("distmv: negative mixture weight")
+		}
+		sum += weights[i]
+	}
+	if sum == 0 {
+		panic("distmv: zero mixture weights")
+	}
+	g := &GaussianMixture{
+		weights:    make([]float64, len(weights)),
+		logWeights: make([]float64, len(weights)),
+		components: make([]*Normal, len(components)),
+		dim:        dim,
+		src:        src,
+	}
+	copy(g.components, components)
+	for i, w := range weights {
+		g.weights[i] = w / sum
+		g.logWeights[i] = math.Log(g.weights[i])
+	}
+	return g
+}
+
+// Len returns the number of components in the mixture.
+func (g *GaussianMixture) Len() int {
+	return len(g.components)
+}
+
+// Dim returns the dimension of the distribution.
+func (g *GaussianMixture) Dim() int {
+	return g.dim
+}
+
+// Component returns the ith component of the mixture. The returned Normal
+// must not be modified.
+func (g *GaussianMixture) Component(i int) *Normal {
+	return g.components[i]
+}
+
+// Weights returns the mixing weights of the components. If dst is not nil,
+// the weights are stored in-place into dst and returned, otherwise a new slice
+// is allocated. Weights panics if dst is not nil and its length is not the
+// number of components.
+func (g *GaussianMixture) Weights(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(g.weights))
+	}
+	if len(dst) != len(g.weights) {
+		panic(badInputLength)
+	}
+	copy(dst, g.weights)
+	return dst
+}
+
+// LogProb computes the log of the pdf of the point x.
+func (g *GaussianMixture) LogProb(x []float64) float64 {
+	lp := make([]float64, len(g.components))
+	return g.logProbs(lp, x)
+}
+
+// logProbs stores the log of the weighted pdf of each component at x in lp
+// and returns the log of the pdf of the mixture.
+func (g *GaussianMixture) logProbs(lp, x []float64) float64 {
+	for j, c := range g.components {
+		if g.weights[j] == 0 {
+			lp[j] = math.Inf(-1)
+			continue
+		}
+		lp[j] = g.logWeights[j] + c.LogProb(x)
+	}
+	return floats.LogSumExp(lp)
+}
+
+// Prob computes the value of the probability density function at x.
+func (g *GaussianMixture) Prob(x []float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Mean returns the mean of the mixture. If x is nil, a new slice will be
+// allocated and returned. If x is non-nil, its length must equal the dimension
+// of the mixture.
+func (g *GaussianMixture) Mean(x []float64) []float64 {
+	x = reuseAs(x, g.dim)
+	for i := range x {
+		x[i] = 0
+	}
+	for j, c := range g.components {
+		floats.AddScaled(x, g.weights[j], c.mu)
+	}
+	return x
+}
+
+// CovarianceMatrix calculates the covariance matrix of the mixture, storing
+// the result in dst. Upon return, the value at element {i, j} of the
+// covariance matrix is equal to the covariance of the i-th and j-th variables.
+//  covariance(i, j) = E[(x_i - E[x_i])(x_j - E[x_j])]
+// If the dst matrix is empty it will be resized to the correct dimensions,
+// otherwise dst must match the dimension of the receiver or CovarianceMatrix
+// will panic.
+func (g *GaussianMixture) CovarianceMatrix(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(g.dim)
+	} else if dst.SymmetricDim() != g.dim {
+		panic("distmv: incorrect matrix size")
+	}
+	// The covariance is Σ_j w_j (Σ_j + μ_j μ_jᵀ) - μ μᵀ.
+	mean := mat.NewVecDense(g.dim, g.Mean(nil))
+	dst.SymOuterK(-1, mean)
+	var sigma mat.SymDense
+	for j, c := range g.components {
+		c.CovarianceMatrix(&sigma)
+		dst.AddSym(dst, scaledSym(&sigma, g.weights[j]))
+		dst.SymRankOne(dst, g.weights[j], mat.NewVecDense(g.dim, c.mu))
+		sigma.Reset()
+	}
+}
+
+// scaledSym returns the matrix a scaled by alpha.
+func scaledSym(a *mat.SymDense, alpha float64) *mat.SymDense {
+	var s mat.SymDense
+	s.ScaleSym(alpha, a)
+	return &s
+}
+
+// Rand generates a random sample from the mixture by choosing a component
+// according to the mixing weights and sampling from it. If the input slice is
+// nil, new memory is allocated, otherwise the result is stored in place.
+func (g *GaussianMixture) Rand(x []float64) []float64 {
+	var u float64
+	if g.src == nil {
+		u = rand.Float64()
+	} else {
+		u = rand.New(g.src).Float64()
+	}
+	j := len(g.weights) - 1
+	for i, w := range g.weights {
+		u -= w
+		if u < 0 && w > 0 {
+			j = i
+			break
+		}
+	}
+	for g.weights[j] == 0 {
+		// Guard against rounding error in the cumulative
+		// weights selecting a component with no weight.
+		j--
+	}
+	c := g.components[j]
+	return NormalRand(x, c.mu, &c.chol, g.src)
+}
+
+// Responsibilities computes the posterior probability that the point x was
+// generated by each component of the mixture. If dst is not nil, the result is
+// stored in-place into dst and returned, otherwise a new slice is allocated.
+// Responsibilities panics if dst is not nil and its length is not the number
+// of components, or if the length of x is not the dimension of the mixture.
+func (g *GaussianMixture) Responsibilities(dst, x []float64) []float64 {
+	if len(x) != g.dim {
+		panic(badSizeMismatch)
+	}
+	if dst == nil {
+		dst = make([]float64, len(g.components))
+	}
+	if len(dst) != len(g.components) {
+		panic(badInputLength)
+	}
+	lse := g.logProbs(dst, x)
+	for j, v := range dst {
+		dst[j] = math.Exp(v - lse)
+	}
+	return dst
+}
+
+// Predict returns the index of the component most likely to have generated
+// the point x. Predict panics if the length of x is not the dimension of the
+// mixture.
+func (g *GaussianMixture) Predict(x []float64) int {
+	if len(x) != g.dim {
+		panic(badSizeMismatch)
+	}
+	lp := make([]float64, len(g.components))
+	g.logProbs(lp, x)
+	return floats.MaxIdx(lp)
+}
+
+// Score returns the weighted mean log-likelihood of the rows of x under the
+// mixture. If weights is nil, all of the weights are 1. Score panics if the
+// number of columns of x is not the dimension of the mixture, or if weights is
+// not nil and its length is not the number of rows of x.
+func (g *GaussianMixture) Score(x mat.Matrix, weights []float64) float64 {
+	n, d := x.Dims()
+	if d != g.dim {
+		panic(badSizeMismatch)
+	}
+	if weights != nil && len(weights) != n {
+		panic(badInputLength)
+	}
+	row := make([]float64, d)
+	lp := make([]float64, len(g.components))
+	var sum, sumWeights float64
+	for i := 0; i < n; i++ {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if w == 0 {
+			continue
+		}
+		sum += w * g.logProbs(lp, mat.Row(row, i, x))
+		sumWeights += w
+	}
+	return sum / sumWeights
+}
+
+// CovarianceType specifies the form of the component covariance matrices
+// of a GaussianMixture fit by GaussianMixtureEM.
+type CovarianceType int
+
+const (
+	// FullCovariance fits a general covariance matrix for each component.
+	FullCovariance CovarianceType = iota
+	// DiagCovariance fits a diagonal covariance matrix for each
+	// component, so that the variables are independent within a
+	// component.
+	DiagCovariance
+)
+
+// GaussianMixtureEM holds the settings for fitting a GaussianMixture to data by
+// the expectation-maximization algorithm.
+type GaussianMixtureEM struct {
+	// Covariance is the form of the component covariance matrices.
+	Covariance CovarianceType
+
+	// MaxIterations is the maximum number of EM iterations. If
+	// MaxIterations is zero, 100 iterations are used.
+	MaxIterations int
+
+	// Tolerance is the change in the mean log-likelihood between
+	// iterations below which the fit is considered converged. If
+	// Tolerance is zero, 1e-6 is used.
+	Tolerance float64
+
+	// Regularization is added to the diagonal of the component
+	// covariance matrices to keep them positive definite when a
+	// component collapses onto few points. If Regularization is
+	// zero, 1e-6 is used.
+	Regularization float64
+
+	// Src is the source of randomness for the initialization of the
+	// fit, which assigns the observations to components by k-means
+	// clustering. Src is also used by the returned mixture for
+	// sampling. If Src is nil, the global source of
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// Fit fits a mixture of k components to the rows of x by expectation-
+// maximization, starting from a k-means clustering of the rows. If weights
+// is nil, all of the weights are 1, otherwise the length of weights must equal
+// the number of rows of x.
+//
+// Fit returns the fitted mixture, the weighted mean log-likelihood of the data
+// at each iteration, which is non-decreasing up to floating point error, and
+// whether the change in log-likelihood fell below Tolerance within
+// MaxIterations. If a component covariance matrix is not positive definite
+// despite regularization, Fit returns a nil mixture.
+//
+// Fit panics if k is less than one or greater than the number of rows of x,
+// or if the length of weights does not match the number of rows of x.
+func (em GaussianMixtureEM) Fit(x mat.Matrix, weights []float64, k int) (g *GaussianMixture, logLikelihood []float64, converged bool) {
+	n, _ := x.Dims()
+	if weights != nil && len(weights) != n {
+		panic(badInputLength)
+	}
+	iters := em.MaxIterations
+	if iters == 0 {
+		iters = 100
+	}
+	tol := em.Tolerance
+	if tol == 0 {
+		tol = 1e-6
+	}
+	reg := em.Regularization
+	if reg == 0 {
+		reg = 1e-6
+	}
+
+	km := stat.KMeans{Src: em.Src}
+	km.Cluster(x, k, weights)
+	assign := km.Assignments(nil)
+	resp := mat.NewDense(n, k, nil)
+	for i, c := range assign {
+		resp.Set(i, c, 1)
+	}
+
+	xd := mat.DenseCopyOf(x)
+	sumWeights := float64(n)
+	if weights != nil {
+		sumWeights = floats.Sum(weights)
+	}
+	var prev *GaussianMixture
+	for it := 0; ; it++ {
+		g = em.maximize(xd, weights, resp, reg, prev)
+		if g == nil {
+			return nil, logLikelihood, false
+		}
+		if it == iters {
+			return g, logLikelihood, false
+		}
+
+		// Expectation step.
+		var ll float64
+		for i := 0; i < n; i++ {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			r := resp.RawRowView(i)
+			lse := g.logProbs(r, xd.RawRowView(i))
+			for j, v := range r {
+				r[j] = math.Exp(v - lse)
+			}
+			if w != 0 {
+				ll += w * lse
+			}
+		}
+		ll /= sumWeights
+		logLikelihood = append(logLikelihood, ll)
+		if it > 0 && math.Abs(ll-logLikelihood[it-1]) < tol {
+			return g, logLikelihood, true
+		}
+		prev = g
+	}
+}
+
+// maximize returns the mixture maximizing the expected log-likelihood for the
+// given responsibilities. Components with no responsibility keep their values
+// from prev with zero weight.
+func (em GaussianMixtureEM) maximize(x *mat.Dense, weights []float64, resp *mat.Dense, reg float64, prev *GaussianMixture) *GaussianMixture {
+	n, d := x.Dims()
+	_, k := resp.Dims()
+	mix := make([]float64, k)
+	components := make([]*Normal, k)
+	w := make([]float64, n)
+	mu := make([]float64, d)
+	centered := mat.NewDense(n, d, nil)
+	sigma := mat.NewSymDense(d, nil)
+	for j := 0; j < k; j++ {
+		var nk float64
+		for i := range w {
+			w[i] = resp.At(i, j)
+			if weights != nil {
+				w[i] *= weights[i]
+			}
+			nk += w[i]
+		}
+		mix[j] = nk
+		if nk == 0 {
+			if prev == nil {
+				// A component without any responsibility can
+				// only occur from a degenerate clustering.
+				return nil
+			}
+			components[j] = prev.components[j]
+			continue
+		}
+
+		for i := range mu {
+			mu[i] = 0
+		}
+		for i := range w {
+			floats.AddScaled(mu, w[i]/nk, x.RawRowView(i))
+		}
+		for i := range w {
+			row := centered.RawRowView(i)
+			floats.SubTo(row, x.RawRowView(i), mu)
+			floats.Scale(math.Sqrt(w[i]/nk), row)
+		}
+		switch em.Covariance {
+		case FullCovariance:
+			sigma.SymOuterK(1, centered.T())
+		case DiagCovariance:
+			sigma.Zero()
+			for c := 0; c < d; c++ {
+				col := mat.Col(nil, c, centered)
+				sigma.SetSym(c, c, floats.Dot(col, col))
+			}
+		default:
+			panic("distmv: invalid covariance type")
+		}
+		for c := 0; c < d; c++ {
+			sigma.SetSym(c, c, sigma.At(c, c)+reg)
+		}
+		norm, ok := NewNormal(mu, sigma, em.Src)
+		if !ok {
+			return nil
+		}
+		components[j] = norm
+	}
+	return NewGaussianMixture(mix, components, em.Src)
+}
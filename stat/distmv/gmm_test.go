@@ -0,0 +1,184 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func testMixture(t *testing.T, src rand.Source) *GaussianMixture {
+	a, ok := NewNormal([]float64{0, 0}, mat.NewSymDense(2, []float64{1, 0.5, 0.5, 1}), src)
+	if !ok {
+		t.Fatal("bad covariance")
+	}
+	b, ok := NewNormal([]float64{8, 2}, mat.NewSymDense(2, []float64{0.5, 0, 0, 2}), src)
+	if !ok {
+		t.Fatal("bad covariance")
+	}
+	c, ok := NewNormal([]float64{-4, 9}, mat.NewSymDense(2, []float64{2, -0.8, -0.8, 1}), src)
+	if !ok {
+		t.Fatal("bad covariance")
+	}
+	return NewGaussianMixture([]float64{2, 1, 1}, []*Normal{a, b, c}, src)
+}
+
+func TestGaussianMixture(t *testing.T) {
+	t.Parallel()
+	g := testMixture(t, rand.NewSource(1))
+	if g.Len() != 3 || g.Dim() != 2 {
+		t.Fatalf("unexpected size: got %d components of dimension %d", g.Len(), g.Dim())
+	}
+	if w := g.Weights(nil); !floats.EqualApprox(w, []float64{0.5, 0.25, 0.25}, 1e-15) {
+		t.Errorf("unexpected weights: %v", w)
+	}
+
+	x := []float64{1, 1}
+	var want float64
+	for j, w := range g.Weights(nil) {
+		want += w * g.Component(j).Prob(x)
+	}
+	if got := g.Prob(x); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected probability: got %v, want %v", got, want)
+	}
+	resp := g.Responsibilities(nil, x)
+	if !scalar.EqualWithinAbsOrRel(floats.Sum(resp), 1, 1e-14, 1e-14) {
+		t.Errorf("responsibilities do not sum to one: %v", resp)
+	}
+	if got := g.Predict(x); got != floats.MaxIdx(resp) || got != 0 {
+		t.Errorf("unexpected prediction: got %d, want 0", got)
+	}
+
+	// The moments of a large sample match the mixture moments.
+	const n = 100000
+	samples := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		g.Rand(samples.RawRowView(i))
+	}
+	mean := g.Mean(nil)
+	for j := range mean {
+		if got := stat.Mean(mat.Col(nil, j, samples), nil); math.Abs(got-mean[j]) > 0.05 {
+			t.Errorf("unexpected sample mean of variable %d: got %v, want %v", j, got, mean[j])
+		}
+	}
+	var cov, sampleCov mat.SymDense
+	g.CovarianceMatrix(&cov)
+	stat.CovarianceMatrix(&sampleCov, samples, nil)
+	if !mat.EqualApprox(&cov, &sampleCov, 0.2) {
+		t.Errorf("unexpected covariance:\ngot  %v\nwant %v", mat.Formatted(&sampleCov), mat.Formatted(&cov))
+	}
+}
+
+func TestGaussianMixtureEM(t *testing.T) {
+	t.Parallel()
+	truth := testMixture(t, rand.NewSource(1))
+	const n = 3000
+	x := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		truth.Rand(x.RawRowView(i))
+	}
+
+	for _, covType := range []CovarianceType{FullCovariance, DiagCovariance} {
+		em := GaussianMixtureEM{Covariance: covType, Src: rand.NewSource(2)}
+		g, ll, converged := em.Fit(x, nil, 3)
+		if g == nil {
+			t.Fatalf("cov=%d: fit failed", covType)
+		}
+		if !converged {
+			t.Errorf("cov=%d: fit did not converge in %d iterations", covType, len(ll))
+		}
+		for i := 1; i < len(ll); i++ {
+			if ll[i] < ll[i-1]-1e-10 {
+				t.Errorf("cov=%d: log-likelihood decreased at iteration %d: %v to %v", covType, i, ll[i-1], ll[i])
+			}
+		}
+		if score := g.Score(x, nil); score < ll[len(ll)-1]-1e-6 {
+			t.Errorf("cov=%d: score less than final log-likelihood: %v < %v", covType, score, ll[len(ll)-1])
+		}
+
+		weights := g.Weights(nil)
+		wantWeights := truth.Weights(nil)
+		for j := 0; j < truth.Len(); j++ {
+			mu := truth.Component(j).Mean(nil)
+			k := g.Predict(mu)
+			got := g.Component(k).Mean(nil)
+			if !floats.EqualApprox(got, mu, 0.2) {
+				t.Errorf("cov=%d: unexpected mean of component %d: got %v, want %v", covType, j, got, mu)
+			}
+			if math.Abs(weights[k]-wantWeights[j]) > 0.05 {
+				t.Errorf("cov=%d: unexpected weight of component %d: got %v, want %v", covType, j, weights[k], wantWeights[j])
+			}
+			var sigma mat.SymDense
+			g.Component(k).CovarianceMatrix(&sigma)
+			if covType == DiagCovariance && sigma.At(0, 1) != 0 {
+				t.Errorf("cov=%d: non-diagonal covariance for component %d", covType, j)
+			}
+			if covType == FullCovariance {
+				var want mat.SymDense
+				truth.Component(j).CovarianceMatrix(&want)
+				if !mat.EqualApprox(&sigma, &want, 0.3) {
+					t.Errorf("cov=%d: unexpected covariance of component %d:\ngot  %v\nwant %v", covType, j, mat.Formatted(&sigma), mat.Formatted(&want))
+				}
+			}
+		}
+	}
+
+	// A weighted fit with integer weights matches the fit to
+	// repeated observations.
+	small := x.Slice(0, 300, 0, 2).(*mat.Dense)
+	weights := make([]float64, 300)
+	var rows []float64
+	for i := range weights {
+		weights[i] = float64(1 + i%2)
+		for r := 0; r < int(weights[i]); r++ {
+			rows = append(rows, small.RawRowView(i)...)
+		}
+	}
+	em := GaussianMixtureEM{Src: rand.NewSource(3), Tolerance: 1e-12}
+	gw, _, _ := em.Fit(small, weights, 3)
+	em.Src = rand.NewSource(3)
+	gr, _, _ := em.Fit(mat.NewDense(len(rows)/2, 2, rows), nil, 3)
+	if sw, sr := gw.Score(small, weights), gr.Score(small, weights); !scalar.EqualWithinAbsOrRel(sw, sr, 1e-6, 1e-6) {
+		t.Errorf("unexpected weighted score: got %v, want %v", sw, sr)
+	}
+}
+
+func TestGaussianMixturePanics(t *testing.T) {
+	t.Parallel()
+	a, _ := NewNormal([]float64{0}, mat.NewSymDense(1, []float64{1}), nil)
+	b, _ := NewNormal([]float64{0, 0}, mat.NewSymDense(2, []float64{1, 0, 0, 1}), nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "no components", fn: func() { NewGaussianMixture(nil, nil, nil) }},
+		{name: "length mismatch", fn: func() { NewGaussianMixture([]float64{1}, []*Normal{a, a}, nil) }},
+		{name: "dimension mismatch", fn: func() { NewGaussianMixture([]float64{1, 1}, []*Normal{a, b}, nil) }},
+		{name: "negative weight", fn: func() { NewGaussianMixture([]float64{1, -1}, []*Normal{a, a}, nil) }},
+		{name: "zero weights", fn: func() { NewGaussianMixture([]float64{0, 0}, []*Normal{a, a}, nil) }},
+		{name: "fit weights length", fn: func() {
+			GaussianMixtureEM{}.Fit(mat.NewDense(3, 1, []float64{1, 2, 3}), []float64{1}, 1)
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,394 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// KDE is a multivariate Gaussian kernel density estimate. Its pdf is given by
+//  p(x) = Σ_i w_i N(x; x_i, H)
+// where x_i are the observations, the non-negative weights w_i sum to one and
+// H is the bandwidth matrix. A product kernel is obtained with a diagonal
+// bandwidth matrix. The bandwidth may be chosen with KDEBandwidth.
+type KDE struct {
+	dim int
+
+	x       *mat.Dense // Observations.
+	z       *mat.Dense // Observations whitened by the bandwidth.
+	logW    []float64
+	cumW    []float64
+	chol    mat.Cholesky
+	linv    mat.TriDense // Inverse of the Cholesky factor of H.
+	logNorm float64
+
+	src rand.Source
+}
+
+// NewKDE returns a kernel density estimate of the rows of x with the given
+// bandwidth matrix. If weights is nil, all observations are weighted equally,
+// otherwise the weights are normalized to sum to one. The observations are
+// copied.
+//
+// NewKDE returns false if the bandwidth matrix is not positive definite.
+// NewKDE panics if x has no rows, if the size of bandwidth does not match the
+// number of columns of x, if weights is not nil and its length differs from
+// the number of rows of x, or if a weight is negative or all weights are zero.
+func NewKDE(x mat.Matrix, weights []float64, bandwidth mat.Symmetric, src rand.Source) (*KDE, bool) {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic(badZeroDimension)
+	}
+	if bandwidth.SymmetricDim() != d {
+		panic(badSizeMismatch)
+	}
+	w := kdeWeights(weights, n)
+
+	k := &KDE{
+		dim:  d,
+		x:    mat.DenseCopyOf(x),
+		logW: make([]float64, n),
+		cumW: make([]float64, n),
+		src:  src,
+	}
+	if ok := k.chol.Factorize(bandwidth); !ok {
+		return nil, false
+	}
+	var l mat.TriDense
+	k.chol.LTo(&l)
+	err := k.linv.InverseTri(&l)
+	if err != nil {
+		return nil, false
+	}
+	k.z = mat.NewDense(n, d, nil)
+	k.z.Mul(k.x, k.linv.T())
+	k.logNorm = -0.5*float64(d)*logTwoPi - 0.5*k.chol.LogDet()
+
+	var cum float64
+	for i, v := range w {
+		k.logW[i] = math.Log(v)
+		cum += v
+		k.cumW[i] = cum
+	}
+	return k, true
+}
+
+// kdeWeights returns the normalized observation weights, panicking if they
+// are not valid.
+func kdeWeights(weights []float64, n int) []float64 {
+	w := make([]float64, n)
+	if weights == nil {
+		for i := range w {
+			w[i] = 1 / float64(n)
+		}
+		return w
+	}
+	if len(weights) != n {
+		panic(badSizeMismatch)
+	}
+	var sum float64
+	for _, v := range weights {
+		if !(v >= 0) {
+			panic("distmv: negative KDE weight")
+		}
+		sum += v
+	}
+	if sum == 0 {
+		panic("distmv: zero KDE weights")
+	}
+	for i, v := range weights {
+		w[i] = v / sum
+	}
+	return w
+}
+
+// Dim returns the dimension of the distribution.
+func (k *KDE) Dim() int {
+	return k.dim
+}
+
+// Len returns the number of observations in the estimate.
+func (k *KDE) Len() int {
+	return len(k.logW)
+}
+
+// Bandwidth stores the bandwidth matrix of the estimate into dst. If dst is
+// empty, it is resized to the dimension of the distribution.
+func (k *KDE) Bandwidth(dst *mat.SymDense) {
+	k.chol.ToSym(dst)
+}
+
+// LogProb computes the log of the pdf of the point x.
+func (k *KDE) LogProb(x []float64) float64 {
+	if len(x) != k.dim {
+		panic(badSizeMismatch)
+	}
+	z := make([]float64, k.dim)
+	k.whiten(z, x)
+	lp := make([]float64, len(k.logW))
+	return k.logProb(lp, z)
+}
+
+// whiten stores L^-1 x in dst where H = L L^T.
+func (k *KDE) whiten(dst, x []float64) {
+	zv := mat.NewVecDense(len(dst), dst)
+	zv.MulVec(&k.linv, mat.NewVecDense(len(x), x))
+}
+
+// logProb returns the log of the pdf at the whitened point z, using lp as
+// working space.
+func (k *KDE) logProb(lp, z []float64) float64 {
+	for i, lw := range k.logW {
+		lp[i] = lw - 0.5*sqEuclidean(z, k.z.RawRowView(i))
+	}
+	return k.logNorm + floats.LogSumExp(lp)
+}
+
+// Prob computes the value of the probability density function at x.
+func (k *KDE) Prob(x []float64) float64 {
+	return math.Exp(k.LogProb(x))
+}
+
+// ProbGrid evaluates the pdf at each point of the regular grid formed by the
+// Cartesian product of axes, where axes[j] holds the coordinates along the
+// j-th dimension. The values are stored in row-major order, so the value at
+// the grid point (axes[0][i_0], ..., axes[d-1][i_{d-1}]) is at index
+//  ((i_0*len(axes[1]) + i_1)*len(axes[2]) + i_2)...
+// If dst is nil, a new slice is allocated and returned, otherwise the result
+// is stored in place into dst. ProbGrid panics if the number of axes is not
+// the dimension of the distribution or if dst is not nil and its length is not
+// the number of grid points.
+func (k *KDE) ProbGrid(dst []float64, axes [][]float64) []float64 {
+	if len(axes) != k.dim {
+		panic(badSizeMismatch)
+	}
+	size := 1
+	for _, a := range axes {
+		size *= len(a)
+	}
+	dst = reuseAs(dst, size)
+	if size == 0 {
+		return dst
+	}
+
+	idx := make([]int, k.dim)
+	x := make([]float64, k.dim)
+	z := make([]float64, k.dim)
+	lp := make([]float64, len(k.logW))
+	for p := range dst {
+		for j, i := range idx {
+			x[j] = axes[j][i]
+		}
+		k.whiten(z, x)
+		dst[p] = math.Exp(k.logProb(lp, z))
+
+		// Advance the multi-index with the last axis fastest.
+		for j := k.dim - 1; j >= 0; j-- {
+			idx[j]++
+			if idx[j] < len(axes[j]) {
+				break
+			}
+			idx[j] = 0
+		}
+	}
+	return dst
+}
+
+// Rand generates a random sample from the estimate by choosing an observation
+// according to the weights and adding kernel noise to it. If the input slice
+// is nil, new memory is allocated, otherwise the result is stored in place.
+func (k *KDE) Rand(x []float64) []float64 {
+	var u float64
+	if k.src == nil {
+		u = rand.Float64()
+	} else {
+		u = rand.New(k.src).Float64()
+	}
+	i := sort.SearchFloat64s(k.cumW, u*k.cumW[len(k.cumW)-1])
+	if i == len(k.cumW) {
+		i--
+	}
+	for math.IsInf(k.logW[i], -1) {
+		// Guard against rounding error in the cumulative
+		// weights selecting an observation with no weight.
+		i--
+	}
+	return NormalRand(x, k.x.RawRowView(i), &k.chol, k.src)
+}
+
+// BandwidthRule specifies how a kernel density estimate bandwidth is selected.
+type BandwidthRule int
+
+const (
+	// ScottsRule scales the sample covariance by n^(-2/(d+4)),
+	// where n is the effective number of observations and d is
+	// the dimension.
+	ScottsRule BandwidthRule = iota
+
+	// SilvermansRule scales the sample covariance by
+	// (n(d+2)/4)^(-2/(d+4)).
+	SilvermansRule
+
+	// LikelihoodCV scales the bandwidth given by ScottsRule to
+	// maximize the leave-one-out log-likelihood of the
+	// observations. Its cost is quadratic in the number of
+	// observations.
+	LikelihoodCV
+)
+
+// KDEBandwidth computes a bandwidth matrix for a Gaussian kernel density
+// estimate of the rows of x using the given rule, storing the result into dst.
+// If product is true, the bandwidth is diagonal, giving a product kernel,
+// otherwise it is proportional to the sample covariance of x. If weights is
+// not nil, the effective number of observations is (Σw)²/Σw².
+//
+// If dst is empty, it is resized to the number of columns of x. KDEBandwidth
+// panics if dst is not empty and its size does not match the number of columns
+// of x, if x has fewer than two rows, or if the weights are not valid.
+func KDEBandwidth(dst *mat.SymDense, x mat.Matrix, weights []float64, rule BandwidthRule, product bool) {
+	n, d := x.Dims()
+	if n < 2 {
+		panic("distmv: too few observations for bandwidth selection")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(d)
+	} else if dst.SymmetricDim() != d {
+		panic(badSizeMismatch)
+	}
+	w := kdeWeights(weights, n)
+
+	var sumSq float64
+	for _, v := range w {
+		sumSq += v * v
+	}
+	nEff := 1 / sumSq
+
+	stat.CovarianceMatrix(dst, x, weights)
+	if product {
+		for i := 0; i < d; i++ {
+			for j := i + 1; j < d; j++ {
+				dst.SetSym(i, j, 0)
+			}
+		}
+	}
+
+	var factor float64
+	switch rule {
+	case ScottsRule, LikelihoodCV:
+		factor = math.Pow(nEff, -2/float64(d+4))
+	case SilvermansRule:
+		factor = math.Pow(nEff*float64(d+2)/4, -2/float64(d+4))
+	default:
+		panic("distmv: unknown bandwidth rule")
+	}
+	dst.ScaleSym(factor, dst)
+
+	if rule == LikelihoodCV {
+		dst.ScaleSym(cvBandwidthScale(mat.DenseCopyOf(x), w, dst), dst)
+	}
+}
+
+// cvBandwidthScale returns the factor c² by which the bandwidth h should be
+// scaled to maximize the leave-one-out log-likelihood of the observations x
+// with normalized weights w. The scale is found by golden section search over
+// log c.
+func cvBandwidthScale(x *mat.Dense, w []float64, h *mat.SymDense) float64 {
+	n, d := x.Dims()
+	var chol mat.Cholesky
+	if ok := chol.Factorize(h); !ok {
+		// A degenerate sample has no better bandwidth.
+		return 1
+	}
+	var l, linv mat.TriDense
+	chol.LTo(&l)
+	if err := linv.InverseTri(&l); err != nil {
+		return 1
+	}
+	var z mat.Dense
+	z.Mul(x, linv.T())
+
+	// Squared Mahalanobis distances between observations
+	// under h are computed once and rescaled for each c.
+	m := make([]float64, n*(n-1)/2)
+	var p int
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			m[p] = sqEuclidean(z.RawRowView(i), z.RawRowView(j))
+			p++
+		}
+	}
+
+	logW := make([]float64, n)
+	for i, v := range w {
+		logW[i] = math.Log(v)
+	}
+	lp := make([]float64, n)
+	looLogLikelihood := func(logC float64) float64 {
+		c2 := math.Exp(2 * logC)
+		var ll float64
+		for i := 0; i < n; i++ {
+			if w[i] == 0 || w[i] == 1 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				switch {
+				case j == i:
+					lp[j] = math.Inf(-1)
+				case j < i:
+					lp[j] = logW[j] - 0.5*m[pairIndex(j, i, n)]/c2
+				default:
+					lp[j] = logW[j] - 0.5*m[pairIndex(i, j, n)]/c2
+				}
+			}
+			ll += w[i] * (floats.LogSumExp(lp) - math.Log1p(-w[i]) - float64(d)*logC)
+		}
+		return ll
+	}
+
+	const (
+		lo, hi = -3.0, 2.0 // Search c in [e^-3, e^2].
+		tol    = 1e-4
+	)
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := lo, hi
+	c1 := b - invPhi*(b-a)
+	c2 := a + invPhi*(b-a)
+	f1, f2 := looLogLikelihood(c1), looLogLikelihood(c2)
+	for b-a > tol {
+		if f1 > f2 {
+			b, c2, f2 = c2, c1, f1
+			c1 = b - invPhi*(b-a)
+			f1 = looLogLikelihood(c1)
+		} else {
+			a, c1, f1 = c1, c2, f2
+			c2 = a + invPhi*(b-a)
+			f2 = looLogLikelihood(c2)
+		}
+	}
+	return math.Exp(a + b)
+}
+
+// pairIndex returns the index of the pair i < j in the packed upper triangle
+// of an n×n matrix without its diagonal.
+func pairIndex(i, j, n int) int {
+	return i*(2*n-i-1)/2 + j - i - 1
+}
+
+// sqEuclidean returns the squared Euclidean distance between a and b.
+func sqEuclidean(a, b []float64) float64 {
+	var sum float64
+	for i, v := range a {
+		d := v - b[i]
+		sum += d * d
+	}
+	return sum
+}
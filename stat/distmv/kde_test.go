@@ -0,0 +1,220 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestKDE(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{
+		0, 0,
+		1, 2,
+		-1, 0.5,
+		3, -1,
+	})
+	weights := []float64{1, 2, 1, 0}
+	h := mat.NewSymDense(2, []float64{0.5, 0.2, 0.2, 0.8})
+	k, ok := NewKDE(x, weights, h, rand.NewSource(1))
+	if !ok {
+		t.Fatal("unexpected failure for positive definite bandwidth")
+	}
+	if k.Dim() != 2 || k.Len() != 4 {
+		t.Fatalf("unexpected size: got %d observations of dimension %d", k.Len(), k.Dim())
+	}
+	var got mat.SymDense
+	k.Bandwidth(&got)
+	if !mat.EqualApprox(&got, h, 1e-14) {
+		t.Errorf("unexpected bandwidth:\ngot  %v\nwant %v", mat.Formatted(&got), mat.Formatted(h))
+	}
+
+	for _, pt := range [][]float64{{0, 0}, {0.5, 1}, {3, -1}, {-2, 4}} {
+		var want float64
+		for i, w := range weights {
+			norm, _ := NewNormal(x.RawRowView(i), h, nil)
+			want += w / 4 * norm.Prob(pt)
+		}
+		if got := k.Prob(pt); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-12) {
+			t.Errorf("unexpected probability at %v: got %v, want %v", pt, got, want)
+		}
+	}
+
+	// The grid values match pointwise evaluation and the density
+	// integrates to one.
+	const m = 161
+	axes := [][]float64{make([]float64, m), make([]float64, m+1)}
+	floats.Span(axes[0], -6, 6)
+	floats.Span(axes[1], -5, 7)
+	grid := k.ProbGrid(nil, axes)
+	if len(grid) != m*(m+1) {
+		t.Fatalf("unexpected grid length: got %d, want %d", len(grid), m*(m+1))
+	}
+	for _, idx := range [][2]int{{0, 0}, {80, 81}, {100, 3}, {m - 1, m}} {
+		want := k.Prob([]float64{axes[0][idx[0]], axes[1][idx[1]]})
+		if got := grid[idx[0]*(m+1)+idx[1]]; !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-12) {
+			t.Errorf("unexpected grid value at %v: got %v, want %v", idx, got, want)
+		}
+	}
+	area := (axes[0][1] - axes[0][0]) * (axes[1][1] - axes[1][0])
+	if sum := floats.Sum(grid) * area; math.Abs(sum-1) > 1e-3 {
+		t.Errorf("density does not integrate to one: %v", sum)
+	}
+
+	// Samples have the mixture moments and never come from an
+	// observation with zero weight.
+	const n = 100000
+	samples := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		k.Rand(samples.RawRowView(i))
+	}
+	wantMean := []float64{0.25, 1.125}
+	for j, mu := range wantMean {
+		if got := stat.Mean(mat.Col(nil, j, samples), nil); math.Abs(got-mu) > 0.02 {
+			t.Errorf("unexpected sample mean of variable %d: got %v, want %v", j, got, mu)
+		}
+	}
+	var sampleCov, wantCov mat.SymDense
+	stat.CovarianceMatrix(&sampleCov, samples, nil)
+	stat.CovarianceMatrix(&wantCov, x, weights)
+	// Convert the unbiased weighted covariance to the
+	// population covariance and add the kernel covariance.
+	wantCov.ScaleSym(3.0/4, &wantCov)
+	wantCov.AddSym(&wantCov, h)
+	if !mat.EqualApprox(&sampleCov, &wantCov, 0.03) {
+		t.Errorf("unexpected sample covariance:\ngot  %v\nwant %v", mat.Formatted(&sampleCov), mat.Formatted(&wantCov))
+	}
+
+	if _, ok := NewKDE(x, nil, mat.NewSymDense(2, []float64{1, 2, 2, 1}), nil); ok {
+		t.Error("expected failure for indefinite bandwidth")
+	}
+}
+
+func TestKDEBandwidth(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 500
+	x := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		a, b := rnd.NormFloat64(), rnd.NormFloat64()
+		x.SetRow(i, []float64{a, 2*b + a, 0.5 * rnd.NormFloat64()})
+	}
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, x, nil)
+
+	for _, test := range []struct {
+		rule   BandwidthRule
+		factor float64
+	}{
+		{rule: ScottsRule, factor: math.Pow(n, -2.0/7)},
+		{rule: SilvermansRule, factor: math.Pow(n*5.0/4, -2.0/7)},
+	} {
+		for _, product := range []bool{false, true} {
+			var got mat.SymDense
+			KDEBandwidth(&got, x, nil, test.rule, product)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					want := test.factor * cov.At(i, j)
+					if product && i != j {
+						want = 0
+					}
+					if !scalar.EqualWithinAbsOrRel(got.At(i, j), want, 1e-14, 1e-12) {
+						t.Errorf("rule=%d product=%t: unexpected bandwidth at (%d,%d): got %v, want %v",
+							test.rule, product, i, j, got.At(i, j), want)
+					}
+				}
+			}
+		}
+	}
+
+	// Uniform weights give the same bandwidth as no weights, up to
+	// the frequency weight correction of the sample covariance.
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 2
+	}
+	var unweighted, weighted mat.SymDense
+	KDEBandwidth(&unweighted, x, nil, SilvermansRule, false)
+	KDEBandwidth(&weighted, x, weights, SilvermansRule, false)
+	if !mat.EqualApprox(&unweighted, &weighted, 1e-3) {
+		t.Errorf("unexpected weighted bandwidth:\ngot  %v\nwant %v", mat.Formatted(&weighted), mat.Formatted(&unweighted))
+	}
+
+	// The cross-validated bandwidth has a leave-one-out likelihood
+	// at least as large as that of the rule-of-thumb bandwidths.
+	small := mat.DenseCopyOf(x.Slice(0, 200, 0, 3))
+	for _, product := range []bool{false, true} {
+		var cv mat.SymDense
+		KDEBandwidth(&cv, small, nil, LikelihoodCV, product)
+		best := looKDE(t, small, &cv)
+		for _, rule := range []BandwidthRule{ScottsRule, SilvermansRule} {
+			var h mat.SymDense
+			KDEBandwidth(&h, small, nil, rule, product)
+			if ll := looKDE(t, small, &h); ll > best+1e-10 {
+				t.Errorf("product=%t: rule %d has larger leave-one-out likelihood than cross-validation: %v > %v",
+					product, rule, ll, best)
+			}
+			if product && cv.At(0, 1) != 0 {
+				t.Errorf("non-diagonal cross-validated product bandwidth")
+			}
+		}
+	}
+}
+
+// looKDE returns the mean leave-one-out log-likelihood of the rows of x under
+// a kernel density estimate with bandwidth h.
+func looKDE(t *testing.T, x *mat.Dense, h mat.Symmetric) float64 {
+	n, d := x.Dims()
+	var ll float64
+	for i := 0; i < n; i++ {
+		rest := mat.NewDense(n-1, d, nil)
+		for j, r := 0, 0; j < n; j++ {
+			if j != i {
+				rest.SetRow(r, x.RawRowView(j))
+				r++
+			}
+		}
+		k, ok := NewKDE(rest, nil, h, nil)
+		if !ok {
+			t.Fatal("bad bandwidth")
+		}
+		ll += k.LogProb(x.RawRowView(i))
+	}
+	return ll / float64(n)
+}
+
+func TestKDEPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 2, []float64{0, 1, 2, 3, 4, 6})
+	h := mat.NewSymDense(2, []float64{1, 0, 0, 1})
+	k, _ := NewKDE(x, nil, h, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "bandwidth size", fn: func() { NewKDE(x, nil, mat.NewSymDense(1, []float64{1}), nil) }},
+		{name: "weights length", fn: func() { NewKDE(x, []float64{1}, h, nil) }},
+		{name: "negative weight", fn: func() { NewKDE(x, []float64{1, -1, 1}, h, nil) }},
+		{name: "zero weights", fn: func() { NewKDE(x, []float64{0, 0, 0}, h, nil) }},
+		{name: "point length", fn: func() { k.LogProb([]float64{1}) }},
+		{name: "grid axes", fn: func() { k.ProbGrid(nil, [][]float64{{1}}) }},
+		{name: "grid destination", fn: func() { k.ProbGrid(make([]float64, 3), [][]float64{{1, 2}, {1, 2}}) }},
+		{name: "one observation", fn: func() { KDEBandwidth(&mat.SymDense{}, x.Slice(0, 1, 0, 2), nil, ScottsRule, false) }},
+		{name: "unknown rule", fn: func() { KDEBandwidth(&mat.SymDense{}, x, nil, BandwidthRule(-1), false) }},
+		{name: "bandwidth destination", fn: func() { KDEBandwidth(mat.NewSymDense(3, nil), x, nil, ScottsRule, false) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
 	"gonum.org/v1/gonum/stat"
 	"gonum.org/v1/gonum/stat/distuv"
 )
@@ -243,6 +244,16 @@ func (s *StudentsT) Dim() int {
 	return s.dim
 }
 
+// Entropy returns the differential entropy of the distribution.
+func (s *StudentsT) Entropy() float64 {
+	nu := s.nu
+	n := float64(s.dim)
+	lg1, _ := math.Lgamma((nu + n) / 2)
+	lg2, _ := math.Lgamma(nu / 2)
+	return lg2 - lg1 + n/2*math.Log(nu*math.Pi) + s.logSqrtDet +
+		(nu+n)/2*(mathext.Digamma((nu+n)/2)-mathext.Digamma(nu/2))
+}
+
 // LogProb computes the log of the pdf of the point x.
 func (s *StudentsT) LogProb(y []float64) float64 {
 	if len(y) != s.dim {
@@ -352,3 +363,230 @@ func (s *StudentsT) Rand(x []float64) []float64 {
 	floats.Add(x, s.mu)
 	return x
 }
+
+// ScoreInput returns the gradient of the log-probability with respect to the
+// input x. That is, ScoreInput computes
+//  ∇_x log(p(x))
+// If score is nil, a new slice will be allocated and returned. If score is of
+// length the dimension of StudentsT, then the result will be put in-place into
+// score. If neither of these is true, ScoreInput will panic.
+func (s *StudentsT) ScoreInput(score, x []float64) []float64 {
+	// The log probability is
+	//  c - (ν+n)/2 log(1 + (x-μ)ᵀ Σ^-1 (x-μ)/ν)
+	// so the derivative is
+	//  -(ν+n)/(ν + (x-μ)ᵀ Σ^-1 (x-μ)) Σ^-1 (x-μ).
+	if len(x) != s.dim {
+		panic(badInputLength)
+	}
+	if score == nil {
+		score = make([]float64, len(x))
+	}
+	if len(score) != len(x) {
+		panic(badSizeMismatch)
+	}
+	tmp := make([]float64, len(x))
+	copy(tmp, x)
+	floats.Sub(tmp, s.mu)
+
+	err := s.chol.SolveVecTo(mat.NewVecDense(len(score), score), mat.NewVecDense(len(tmp), tmp))
+	if err != nil {
+		panic(err)
+	}
+	mahal := floats.Dot(tmp, score)
+	floats.Scale(-(s.nu+float64(s.dim))/(s.nu+mahal), score)
+	return score
+}
+
+// SetMean changes the location of the Student's T distribution. SetMean panics
+// if len(mu) does not equal the dimension of the distribution.
+func (s *StudentsT) SetMean(mu []float64) {
+	if len(mu) != s.dim {
+		panic(badSizeMismatch)
+	}
+	copy(s.mu, mu)
+}
+
+// StudentsTEM holds the settings for fitting a StudentsT to data by maximum
+// likelihood using the expectation-conditional maximization either (ECME)
+// algorithm. The heavy tails of the Student's T distribution downweight
+// outlying observations, so the fitted location and scale matrix are robust
+// estimates of the center and shape of the data.
+//  Liu, C. and Rubin, D. B. "ML estimation of the t distribution using EM
+//  and its extensions, ECM and ECME." Statistica Sinica 5.1 (1995): 19-39.
+type StudentsTEM struct {
+	// Nu is the degrees of freedom of the fitted distribution. If Nu is
+	// zero, the degrees of freedom are estimated along with the location
+	// and scale matrix.
+	Nu float64
+
+	// MaxIterations is the maximum number of iterations. If
+	// MaxIterations is zero, 100 iterations are used.
+	MaxIterations int
+
+	// Tolerance is the change in the mean log-likelihood between
+	// iterations below which the fit is considered converged. If
+	// Tolerance is zero, 1e-8 is used.
+	Tolerance float64
+
+	// Src is the source of randomness used by the returned
+	// distribution for sampling.
+	Src rand.Source
+}
+
+// Fit fits a Student's T distribution to the rows of x. If weights is nil,
+// all of the weights are 1, otherwise the length of weights must equal the
+// number of rows of x.
+//
+// Fit returns the fitted distribution, the weighted mean log-likelihood of the
+// data at each iteration, which is non-decreasing up to floating point error,
+// and whether the change in log-likelihood fell below Tolerance within
+// MaxIterations. If the scale matrix is not positive definite, Fit returns a
+// nil distribution.
+//
+// Fit panics if the length of weights does not match the number of rows of x,
+// or if Nu is negative.
+func (em StudentsTEM) Fit(x mat.Matrix, weights []float64) (dist *StudentsT, logLikelihood []float64, converged bool) {
+	n, d := x.Dims()
+	if weights != nil && len(weights) != n {
+		panic(badInputLength)
+	}
+	if em.Nu < 0 {
+		panic("studentst: negative degrees of freedom")
+	}
+	iters := em.MaxIterations
+	if iters == 0 {
+		iters = 100
+	}
+	tol := em.Tolerance
+	if tol == 0 {
+		tol = 1e-8
+	}
+	nu := em.Nu
+	if nu == 0 {
+		nu = 4
+	}
+
+	// Start from the sample mean and covariance.
+	mu := make([]float64, d)
+	for j := range mu {
+		mu[j] = stat.Mean(mat.Col(nil, j, x), weights)
+	}
+	var sigma mat.SymDense
+	stat.CovarianceMatrix(&sigma, x, weights)
+
+	xd := mat.DenseCopyOf(x)
+	sumWeights := float64(n)
+	if weights != nil {
+		sumWeights = floats.Sum(weights)
+	}
+	maha := make([]float64, n)
+	u := make([]float64, n)
+	diff := make([]float64, d)
+	for it := 0; ; it++ {
+		dist, ok := NewStudentsT(mu, &sigma, nu, em.Src)
+		if !ok {
+			return nil, logLikelihood, false
+		}
+
+		// Expectation step: the conditional expectations of the latent
+		// precision scale of each observation.
+		for i := range maha {
+			m := stat.Mahalanobis(mat.NewVecDense(d, xd.RawRowView(i)), mat.NewVecDense(d, mu), &dist.chol)
+			maha[i] = m * m
+		}
+		ll := studentsTLogLikelihood(maha, weights, nu, float64(d), dist.logSqrtDet) / sumWeights
+		logLikelihood = append(logLikelihood, ll)
+		if it > 0 && math.Abs(ll-logLikelihood[it-1]) < tol {
+			return dist, logLikelihood, true
+		}
+		if it == iters {
+			return dist, logLikelihood, false
+		}
+		for i, m := range maha {
+			u[i] = (nu + float64(d)) / (nu + m)
+		}
+
+		// Conditional maximization of the location and scale matrix.
+		var sumU float64
+		for j := range mu {
+			mu[j] = 0
+		}
+		for i := 0; i < n; i++ {
+			w := u[i]
+			if weights != nil {
+				w *= weights[i]
+			}
+			floats.AddScaled(mu, w, xd.RawRowView(i))
+			sumU += w
+		}
+		floats.Scale(1/sumU, mu)
+		sigma.Zero()
+		for i := 0; i < n; i++ {
+			w := u[i]
+			if weights != nil {
+				w *= weights[i]
+			}
+			floats.SubTo(diff, xd.RawRowView(i), mu)
+			sigma.SymRankOne(&sigma, w/sumWeights, mat.NewVecDense(d, diff))
+		}
+
+		// Maximization of the observed log-likelihood over the degrees
+		// of freedom with the location and scale matrix fixed.
+		if em.Nu == 0 {
+			var chol mat.Cholesky
+			if !chol.Factorize(&sigma) {
+				return nil, logLikelihood, false
+			}
+			for i := range maha {
+				m := stat.Mahalanobis(mat.NewVecDense(d, xd.RawRowView(i)), mat.NewVecDense(d, mu), &chol)
+				maha[i] = m * m
+			}
+			logSqrtDet := 0.5 * chol.LogDet()
+			nu = maximizeDegreesOfFreedom(func(nu float64) float64 {
+				return studentsTLogLikelihood(maha, weights, nu, float64(d), logSqrtDet)
+			})
+		}
+	}
+}
+
+// studentsTLogLikelihood returns the weighted log-likelihood of observations
+// with the given squared Mahalanobis distances under a Student's T
+// distribution with nu degrees of freedom, dimension d and scale matrix with
+// the given log square root determinant.
+func studentsTLogLikelihood(maha, weights []float64, nu, d, logSqrtDet float64) float64 {
+	lg1, _ := math.Lgamma((nu + d) / 2)
+	lg2, _ := math.Lgamma(nu / 2)
+	c := lg1 - lg2 - d/2*math.Log(nu*math.Pi) - logSqrtDet
+	var ll float64
+	for i, m := range maha {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		ll += w * (c - (nu+d)/2*math.Log1p(m/nu))
+	}
+	return ll
+}
+
+// maximizeDegreesOfFreedom returns the degrees of freedom in [0.1, 1e4]
+// maximizing the log-likelihood f, found by golden section search on the
+// logarithm of the degrees of freedom.
+func maximizeDegreesOfFreedom(f func(nu float64) float64) float64 {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := math.Log(0.1), math.Log(1e4)
+	c := b - invPhi*(b-a)
+	e := a + invPhi*(b-a)
+	fc, fe := f(math.Exp(c)), f(math.Exp(e))
+	for b-a > 1e-8 {
+		if fc > fe {
+			b, e, fe = e, c, fc
+			c = b - invPhi*(b-a)
+			fc = f(math.Exp(c))
+		} else {
+			a, c, fc = c, e, fe
+			e = a + invPhi*(b-a)
+			fe = f(math.Exp(e))
+		}
+	}
+	return math.Exp((a + b) / 2)
+}
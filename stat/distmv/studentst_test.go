@@ -10,9 +10,11 @@ import (
 
 	"golang.org/x/exp/rand"
 
+	"gonum.org/v1/gonum/diff/fd"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/floats/scalar"
 	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
 	"gonum.org/v1/gonum/stat"
 )
 
@@ -272,3 +274,154 @@ func TestStudentsTMarginalSingle(t *testing.T) {
 		}
 	}
 }
+
+func TestStudentsTEntropy(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		mu    []float64
+		sigma *mat.SymDense
+		nu    float64
+	}{
+		{
+			mu:    []float64{1},
+			sigma: mat.NewSymDense(1, []float64{4}),
+			nu:    3,
+		},
+		{
+			mu:    []float64{2, 3, 4},
+			sigma: mat.NewSymDense(3, []float64{2, 0.5, 3, 0.5, 1, 0.6, 3, 0.6, 10}),
+			nu:    5,
+		},
+	} {
+		st, ok := NewStudentsT(test.mu, test.sigma, test.nu, src)
+		if !ok {
+			t.Fatalf("Bad test, covariance matrix not positive definite")
+		}
+		if len(test.mu) == 1 {
+			// The univariate entropy is
+			//  (ν+1)/2 (ψ((ν+1)/2) - ψ(ν/2)) + log(√ν B(ν/2, 1/2)) + log σ
+			nu := test.nu
+			lb, _ := math.Lgamma(nu / 2)
+			lab, _ := math.Lgamma((nu + 1) / 2)
+			want := (nu+1)/2*(mathext.Digamma((nu+1)/2)-mathext.Digamma(nu/2)) +
+				0.5*math.Log(nu) + lb + 0.5*math.Log(math.Pi) - lab + 0.5*math.Log(test.sigma.At(0, 0))
+			if got := st.Entropy(); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+				t.Errorf("Entropy mismatch with univariate: got %v, want %v", got, want)
+			}
+		}
+		const n = 100000
+		var sum float64
+		x := make([]float64, len(test.mu))
+		for i := 0; i < n; i++ {
+			st.Rand(x)
+			sum -= st.LogProb(x)
+		}
+		if got, want := st.Entropy(), sum/n; math.Abs(got-want) > 2e-2 {
+			t.Errorf("Entropy mismatch with Monte Carlo estimate: got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestStudentsTScoreInput(t *testing.T) {
+	t.Parallel()
+	mu := []float64{2, 3, 4}
+	sigma := mat.NewSymDense(3, []float64{2, 0.5, 3, 0.5, 1, 0.6, 3, 0.6, 10})
+	st, ok := NewStudentsT(mu, sigma, 4.5, nil)
+	if !ok {
+		t.Fatalf("Bad test, covariance matrix not positive definite")
+	}
+	for _, x := range [][]float64{{2, 3, 4}, {1, -2, 8}, {10, 3, -1}} {
+		got := st.ScoreInput(nil, x)
+		want := fd.Gradient(nil, st.LogProb, x, nil)
+		if !floats.EqualApprox(got, want, 1e-5) {
+			t.Errorf("ScoreInput mismatch at %v: got %v, want %v", x, got, want)
+		}
+	}
+
+	st.SetMean([]float64{0, 0, 0})
+	if got := st.ScoreInput(nil, []float64{0, 0, 0}); floats.Norm(got, 2) != 0 {
+		t.Errorf("nonzero score at mean after SetMean: %v", got)
+	}
+}
+
+func TestStudentsTEM(t *testing.T) {
+	t.Parallel()
+	mu := []float64{1, -2}
+	sigma := mat.NewSymDense(2, []float64{2, 0.6, 0.6, 1})
+	const nu = 5
+	truth, ok := NewStudentsT(mu, sigma, nu, rand.NewSource(1))
+	if !ok {
+		t.Fatalf("Bad test, covariance matrix not positive definite")
+	}
+	const n = 5000
+	x := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		truth.Rand(x.RawRowView(i))
+	}
+
+	for _, em := range []StudentsTEM{{Nu: nu}, {}} {
+		st, ll, ok := em.Fit(x, nil)
+		if !ok {
+			t.Fatalf("Nu=%v: EM did not converge", em.Nu)
+		}
+		for i := 1; i < len(ll); i++ {
+			if ll[i] < ll[i-1]-1e-12 {
+				t.Errorf("Nu=%v: log-likelihood decreased at iteration %d: %v to %v", em.Nu, i, ll[i-1], ll[i])
+			}
+		}
+		if got := st.Mean(nil); !floats.EqualApprox(got, mu, 0.06) {
+			t.Errorf("Nu=%v: unexpected location: got %v, want %v", em.Nu, got, mu)
+		}
+		if !mat.EqualApprox(&st.sigma, sigma, 0.15) {
+			t.Errorf("Nu=%v: unexpected scale matrix: got %v, want %v", em.Nu, mat.Formatted(&st.sigma), mat.Formatted(sigma))
+		}
+		if math.Abs(st.Nu()-nu) > 1 {
+			t.Errorf("Nu=%v: unexpected degrees of freedom: got %v, want %v", em.Nu, st.Nu(), nu)
+		}
+	}
+
+	// Gross outliers displace the sample mean but barely move the
+	// fitted location.
+	y := mat.NewDense(n+50, 2, nil)
+	y.Slice(0, n, 0, 2).(*mat.Dense).Copy(x)
+	for i := n; i < n+50; i++ {
+		y.SetRow(i, []float64{100, 100})
+	}
+	st, _, ok := StudentsTEM{}.Fit(y, nil)
+	if !ok {
+		t.Fatalf("EM did not converge with outliers")
+	}
+	if got := st.Mean(nil); !floats.EqualApprox(got, mu, 0.1) {
+		t.Errorf("location not robust to outliers: got %v, want %v", got, mu)
+	}
+	if m := stat.Mean(mat.Col(nil, 0, y), nil); math.Abs(m-mu[0]) < 0.5 {
+		t.Errorf("bad test: outliers do not displace the sample mean")
+	}
+
+	// Integer weights are equivalent to repeated observations.
+	w := make([]float64, 100)
+	rows := make([]float64, 0, 2*300)
+	for i := range w {
+		w[i] = float64(i%3 + 1)
+		for k := 0; k < int(w[i]); k++ {
+			rows = append(rows, x.RawRowView(i)...)
+		}
+	}
+	weighted, _, _ := StudentsTEM{Nu: 3}.Fit(x.Slice(0, 100, 0, 2), w)
+	repeated, _, _ := StudentsTEM{Nu: 3}.Fit(mat.NewDense(len(rows)/2, 2, rows), nil)
+	if !floats.EqualApprox(weighted.Mean(nil), repeated.Mean(nil), 1e-6) || !mat.EqualApprox(&weighted.sigma, &repeated.sigma, 1e-6) {
+		t.Errorf("weighted fit differs from fit to repeated observations")
+	}
+}
+
+func TestStudentsTEMPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 7})
+	if !panics(func() { StudentsTEM{}.Fit(x, []float64{1, 2}) }) {
+		t.Errorf("expected panic for weights length mismatch")
+	}
+	if !panics(func() { StudentsTEM{Nu: -1}.Fit(x, nil) }) {
+		t.Errorf("expected panic for negative degrees of freedom")
+	}
+}
@@ -0,0 +1,421 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+// GEV represents the generalized extreme value distribution, the limiting
+// distribution of normalized maxima of sequences of independent random
+// variables. It unifies the Gumbel (Xi = 0), Fréchet (Xi > 0) and reversed
+// Weibull (Xi < 0) families.
+//
+// The generalized extreme value distribution has cumulative distribution
+// function
+//  exp(-t(x))
+//  t(x) = (1 + xi z)^(-1/xi)  if xi ≠ 0
+//  t(x) = exp(-z)             if xi = 0
+//  z = (x - mu)/sigma
+// with support where 1 + xi z > 0. Sigma must be greater than 0.
+//
+// For more information, see https://en.wikipedia.org/wiki/Generalized_extreme_value_distribution.
+type GEV struct {
+	Mu    float64 // Location of the distribution
+	Sigma float64 // Scale of the distribution
+	Xi    float64 // Shape of the distribution
+	Src   rand.Source
+}
+
+// logT returns the logarithm of t(x), returning -Inf above the support and
+// +Inf below it.
+func (g GEV) logT(x float64) float64 {
+	z := (x - g.Mu) / g.Sigma
+	if g.Xi == 0 {
+		return -z
+	}
+	u := g.Xi * z
+	if u <= -1 {
+		if g.Xi > 0 {
+			return math.Inf(1)
+		}
+		return math.Inf(-1)
+	}
+	return -math.Log1p(u) / g.Xi
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (g GEV) CDF(x float64) float64 {
+	return math.Exp(-math.Exp(g.logT(x)))
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (g GEV) Entropy() float64 {
+	return math.Log(g.Sigma) + eulerMascheroni*g.Xi + eulerMascheroni + 1
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution. The excess
+// kurtosis is infinite if Xi ≥ 1/4.
+func (g GEV) ExKurtosis() float64 {
+	if g.Xi == 0 {
+		return 12.0 / 5
+	}
+	if g.Xi >= 0.25 {
+		return math.Inf(1)
+	}
+	m := g.moments()
+	v := math.Expm1(m.d2)
+	n4 := m.c4 + expm1m(m.d4) - 4*expm1m(m.d3) + 6*expm1m(m.d2)
+	return n4/(v*v) - 3
+}
+
+// gevMoments holds the log gamma function terms of the moments of the GEV
+// distribution with shape xi. With L(k) = log Γ(1 - k xi),
+//  l1 = L(1)
+//  dk = L(k) - k L(1)
+//  c3 = d3 - 3 d2
+//  c4 = d4 - 4 d3 + 6 d2
+// The combinations c3 and c4 vanish to high order as xi approaches zero, so
+// they are evaluated directly to avoid cancellation.
+type gevMoments struct {
+	l1, d2, d3, d4, c3, c4 float64
+}
+
+// moments returns the log gamma function terms of the moments of g. For
+// small shape parameters each term is summed from the power series
+//  log Γ(1 - z) = γ z + Σ_{k≥2} ζ(k) z^k / k
+// in which the lower order terms of the combinations cancel exactly.
+func (g GEV) moments() gevMoments {
+	xi := g.Xi
+	if math.Abs(xi) >= 0.05 {
+		l1, _ := math.Lgamma(1 - xi)
+		l2, _ := math.Lgamma(1 - 2*xi)
+		l3, _ := math.Lgamma(1 - 3*xi)
+		l4, _ := math.Lgamma(1 - 4*xi)
+		m := gevMoments{l1: l1, d2: l2 - 2*l1, d3: l3 - 3*l1, d4: l4 - 4*l1}
+		m.c3 = m.d3 - 3*m.d2
+		m.c4 = m.d4 - 4*m.d3 + 6*m.d2
+		return m
+	}
+	m := gevMoments{l1: eulerMascheroni * xi}
+	pow := xi
+	for k := 2; k <= 40; k++ {
+		pow *= xi
+		t := mathext.Zeta(float64(k), 1) / float64(k) * pow
+		p2, p3, p4 := math.Pow(2, float64(k)), math.Pow(3, float64(k)), math.Pow(4, float64(k))
+		m.l1 += t
+		m.d2 += t * (p2 - 2)
+		m.d3 += t * (p3 - 3)
+		m.d4 += t * (p4 - 4)
+		m.c3 += t * (p3 - 3*p2 + 3)
+		m.c4 += t * (p4 - 4*p3 + 6*p2 - 4)
+	}
+	return m
+}
+
+// expm1m returns exp(x) - 1 - x.
+func expm1m(x float64) float64 {
+	if math.Abs(x) > 1e-2 {
+		return math.Expm1(x) - x
+	}
+	// Sum the Taylor series to avoid cancellation.
+	var sum float64
+	term := x
+	for k := 2; k < 20; k++ {
+		term *= x / float64(k)
+		sum += term
+	}
+	return sum
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (g GEV) LogProb(x float64) float64 {
+	lt := g.logT(x)
+	if math.IsInf(lt, 0) {
+		return math.Inf(-1)
+	}
+	return -math.Log(g.Sigma) + (g.Xi+1)*lt - math.Exp(lt)
+}
+
+// Mean returns the mean of the probability distribution. The mean is
+// infinite if Xi ≥ 1.
+func (g GEV) Mean() float64 {
+	if g.Xi == 0 {
+		return g.Mu + g.Sigma*eulerMascheroni
+	}
+	if g.Xi >= 1 {
+		return math.Inf(1)
+	}
+	return g.Mu + g.Sigma*math.Expm1(g.moments().l1)/g.Xi
+}
+
+// Median returns the median of the probability distribution.
+func (g GEV) Median() float64 {
+	return g.Quantile(0.5)
+}
+
+// Mode returns the mode of the probability distribution.
+func (g GEV) Mode() float64 {
+	if g.Xi == 0 {
+		return g.Mu
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*math.Log1p(g.Xi))/g.Xi
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (GEV) NumParameters() int {
+	return 3
+}
+
+// Prob computes the value of the probability density function at x.
+func (g GEV) Prob(x float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (g GEV) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	// The quantile is mu + sigma (y^-xi - 1)/xi with y = -log(p).
+	ly := math.Log(-math.Log(p))
+	if g.Xi == 0 {
+		return g.Mu - g.Sigma*ly
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*ly)/g.Xi
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (g GEV) Rand() float64 {
+	var rnd float64
+	if g.Src == nil {
+		rnd = rand.ExpFloat64()
+	} else {
+		rnd = rand.New(g.Src).ExpFloat64()
+	}
+	// -log(U) is a standard exponential variate.
+	ly := math.Log(rnd)
+	if g.Xi == 0 {
+		return g.Mu - g.Sigma*ly
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*ly)/g.Xi
+}
+
+// Skewness returns the skewness of the distribution. The skewness is
+// infinite if Xi ≥ 1/3.
+func (g GEV) Skewness() float64 {
+	if g.Xi == 0 {
+		return 12 * math.Sqrt(6) * apery / (math.Pi * math.Pi * math.Pi)
+	}
+	if g.Xi >= 1.0/3 {
+		return math.Inf(1)
+	}
+	// The third central moment is proportional to
+	//  Γ(1-3xi) - 3Γ(1-xi)Γ(1-2xi) + 2Γ(1-xi)^3
+	// whose sign follows that of -xi.
+	m := g.moments()
+	n3 := m.c3 + expm1m(m.d3) - 3*expm1m(m.d2)
+	s := n3 / math.Pow(math.Expm1(m.d2), 1.5)
+	if g.Xi < 0 {
+		s = -s
+	}
+	return s
+}
+
+// StdDev returns the standard deviation of the probability distribution.
+// The standard deviation is infinite if Xi ≥ 1/2.
+func (g GEV) StdDev() float64 {
+	return math.Sqrt(g.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (g GEV) Survival(x float64) float64 {
+	return -math.Expm1(-math.Exp(g.logT(x)))
+}
+
+// Variance returns the variance of the probability distribution. The
+// variance is infinite if Xi ≥ 1/2.
+func (g GEV) Variance() float64 {
+	if g.Xi == 0 {
+		return math.Pi * math.Pi * g.Sigma * g.Sigma / 6
+	}
+	if g.Xi >= 0.5 {
+		return math.Inf(1)
+	}
+	// Γ(1-2xi) - Γ(1-xi)^2 is computed relative to Γ(1-xi)^2 to avoid
+	// cancellation for small shape parameters.
+	m := g.moments()
+	r := math.Exp(m.l1) / g.Xi
+	return g.Sigma * g.Sigma * r * r * math.Expm1(m.d2)
+}
+
+// GeneralizedPareto represents the generalized Pareto distribution, the
+// limiting distribution of exceedances over a high threshold, used in
+// peaks-over-threshold extreme value analysis.
+//
+// The generalized Pareto distribution has survival function
+//  (1 + xi z)^(-1/xi)  if xi ≠ 0
+//  exp(-z)             if xi = 0
+//  z = (x - mu)/sigma
+// with support z ≥ 0, bounded above by -1/xi when xi < 0. Sigma must be
+// greater than 0.
+//
+// For more information, see https://en.wikipedia.org/wiki/Generalized_Pareto_distribution.
+type GeneralizedPareto struct {
+	Mu    float64 // Location of the distribution
+	Sigma float64 // Scale of the distribution
+	Xi    float64 // Shape of the distribution
+	Src   rand.Source
+}
+
+// logSurvival returns the logarithm of the survival function at x.
+func (g GeneralizedPareto) logSurvival(x float64) float64 {
+	z := (x - g.Mu) / g.Sigma
+	if z <= 0 {
+		return 0
+	}
+	if g.Xi == 0 {
+		return -z
+	}
+	u := g.Xi * z
+	if u <= -1 {
+		return math.Inf(-1)
+	}
+	return -math.Log1p(u) / g.Xi
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (g GeneralizedPareto) CDF(x float64) float64 {
+	return -math.Expm1(g.logSurvival(x))
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (g GeneralizedPareto) Entropy() float64 {
+	return math.Log(g.Sigma) + g.Xi + 1
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution. The excess
+// kurtosis is infinite if Xi ≥ 1/4.
+func (g GeneralizedPareto) ExKurtosis() float64 {
+	if g.Xi >= 0.25 {
+		return math.Inf(1)
+	}
+	xi := g.Xi
+	return 3*(1-2*xi)*(2*xi*xi+xi+3)/((1-3*xi)*(1-4*xi)) - 3
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (g GeneralizedPareto) LogProb(x float64) float64 {
+	z := (x - g.Mu) / g.Sigma
+	if z < 0 {
+		return math.Inf(-1)
+	}
+	if g.Xi == 0 {
+		return -math.Log(g.Sigma) - z
+	}
+	u := g.Xi * z
+	if u <= -1 {
+		return math.Inf(-1)
+	}
+	return -math.Log(g.Sigma) - (1/g.Xi+1)*math.Log1p(u)
+}
+
+// Mean returns the mean of the probability distribution. The mean is
+// infinite if Xi ≥ 1.
+func (g GeneralizedPareto) Mean() float64 {
+	if g.Xi >= 1 {
+		return math.Inf(1)
+	}
+	return g.Mu + g.Sigma/(1-g.Xi)
+}
+
+// Median returns the median of the probability distribution.
+func (g GeneralizedPareto) Median() float64 {
+	return g.Quantile(0.5)
+}
+
+// Mode returns the mode of the probability distribution. The density is
+// maximal at the upper bound of the support when Xi < -1, and is uniform
+// when Xi = -1, in which case Mu is returned.
+func (g GeneralizedPareto) Mode() float64 {
+	if g.Xi < -1 {
+		return g.Mu - g.Sigma/g.Xi
+	}
+	return g.Mu
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (GeneralizedPareto) NumParameters() int {
+	return 3
+}
+
+// Prob computes the value of the probability density function at x.
+func (g GeneralizedPareto) Prob(x float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (g GeneralizedPareto) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	return g.quantileLogSurvival(math.Log1p(-p))
+}
+
+// quantileLogSurvival returns the value at which the logarithm of the
+// survival function is ls.
+func (g GeneralizedPareto) quantileLogSurvival(ls float64) float64 {
+	if g.Xi == 0 {
+		return g.Mu - g.Sigma*ls
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*ls)/g.Xi
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (g GeneralizedPareto) Rand() float64 {
+	var rnd float64
+	if g.Src == nil {
+		rnd = rand.ExpFloat64()
+	} else {
+		rnd = rand.New(g.Src).ExpFloat64()
+	}
+	return g.quantileLogSurvival(-rnd)
+}
+
+// Skewness returns the skewness of the distribution. The skewness is
+// infinite if Xi ≥ 1/3.
+func (g GeneralizedPareto) Skewness() float64 {
+	if g.Xi >= 1.0/3 {
+		return math.Inf(1)
+	}
+	return 2 * (1 + g.Xi) * math.Sqrt(1-2*g.Xi) / (1 - 3*g.Xi)
+}
+
+// StdDev returns the standard deviation of the probability distribution.
+// The standard deviation is infinite if Xi ≥ 1/2.
+func (g GeneralizedPareto) StdDev() float64 {
+	return math.Sqrt(g.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (g GeneralizedPareto) Survival(x float64) float64 {
+	return math.Exp(g.logSurvival(x))
+}
+
+// Variance returns the variance of the probability distribution. The
+// variance is infinite if Xi ≥ 1/2.
+func (g GeneralizedPareto) Variance() float64 {
+	if g.Xi >= 0.5 {
+		return math.Inf(1)
+	}
+	d := 1 - g.Xi
+	return g.Sigma * g.Sigma / (d * d * (1 - 2*g.Xi))
+}
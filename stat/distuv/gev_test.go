@@ -0,0 +1,174 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestGEV(t *testing.T) {
+	t.Parallel()
+	src := rand.New(rand.NewSource(1))
+	for i, g := range []GEV{
+		{Mu: 0, Sigma: 1, Xi: 0, Src: src},
+		{Mu: 1, Sigma: 2, Xi: 0.1, Src: src},
+		{Mu: -2, Sigma: 0.5, Xi: -0.3, Src: src},
+		{Mu: 0, Sigma: 1, Xi: -1.2, Src: src},
+	} {
+		const (
+			tol = 1e-2
+			n   = 5e5
+		)
+		x := make([]float64, n)
+		generateSamples(x, g)
+		sort.Float64s(x)
+
+		lower, upper := math.Inf(-1), math.Inf(1)
+		if g.Xi > 0 {
+			lower = g.Mu - g.Sigma/g.Xi
+		} else if g.Xi < 0 {
+			upper = g.Mu - g.Sigma/g.Xi
+		}
+		if x[0] < lower || x[len(x)-1] > upper {
+			t.Errorf("case %d: sample outside support", i)
+		}
+		if g.Xi > -1 {
+			checkProbContinuous(t, i, x, lower, upper, g, 1e-10)
+		}
+		checkEntropy(t, i, x, g, tol)
+		checkMean(t, i, x, g, tol)
+		checkMedian(t, i, x, g, tol)
+		checkVarAndStd(t, i, x, g, 2*tol)
+		checkSkewness(t, i, x, g, 5e-2)
+		checkExKurtosis(t, i, x, g, 2e-1)
+		checkQuantileCDFSurvival(t, i, x, g, tol)
+
+		// The mode is a stationary point of the density.
+		mode := g.Mode()
+		const h = 1e-6
+		if d := (g.LogProb(mode+h) - g.LogProb(mode-h)) / (2 * h); math.Abs(d) > 1e-6/g.Sigma {
+			t.Errorf("case %d: nonzero derivative at mode %v: %v", i, mode, d)
+		}
+	}
+}
+
+func TestGEVGumbel(t *testing.T) {
+	t.Parallel()
+	g := GEV{Mu: 1, Sigma: 2}
+	gr := GumbelRight{Mu: 1, Beta: 2}
+	for _, x := range []float64{-3, 0, 1, 2.5, 10} {
+		if !scalar.EqualWithinAbsOrRel(g.CDF(x), gr.CDF(x), 1e-14, 1e-14) {
+			t.Errorf("CDF mismatch at %v: got %v, want %v", x, g.CDF(x), gr.CDF(x))
+		}
+		if !scalar.EqualWithinAbsOrRel(g.LogProb(x), gr.LogProb(x), 1e-14, 1e-14) {
+			t.Errorf("LogProb mismatch at %v: got %v, want %v", x, g.LogProb(x), gr.LogProb(x))
+		}
+	}
+	for _, test := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"mean", g.Mean(), gr.Mean()},
+		{"median", g.Median(), gr.Median()},
+		{"variance", g.Variance(), gr.Variance()},
+		{"skewness", g.Skewness(), gr.Skewness()},
+		{"excess kurtosis", g.ExKurtosis(), gr.ExKurtosis()},
+		{"entropy", g.Entropy(), gr.Entropy()},
+	} {
+		if !scalar.EqualWithinAbsOrRel(test.got, test.want, 1e-14, 1e-14) {
+			t.Errorf("%s mismatch: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+	// Small shape parameters are continuous with the Gumbel limit.
+	near := GEV{Mu: 1, Sigma: 2, Xi: 1e-9}
+	for _, test := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"CDF", near.CDF(2), g.CDF(2)},
+		{"quantile", near.Quantile(0.9), g.Quantile(0.9)},
+		{"mean", near.Mean(), g.Mean()},
+		{"variance", near.Variance(), g.Variance()},
+		{"mode", near.Mode(), g.Mode()},
+	} {
+		if !scalar.EqualWithinAbsOrRel(test.got, test.want, 1e-6, 1e-6) {
+			t.Errorf("%s discontinuous at zero shape: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+	for _, xi := range []float64{-2e-4, 2e-4} {
+		small := GEV{Mu: 1, Sigma: 2, Xi: xi}
+		if !scalar.EqualWithinAbsOrRel(small.Skewness(), g.Skewness(), 1e-2, 1e-2) ||
+			!scalar.EqualWithinAbsOrRel(small.ExKurtosis(), g.ExKurtosis(), 1e-2, 1e-2) ||
+			!scalar.EqualWithinAbsOrRel(GEV{Xi: xi * 1e-5}.ExKurtosis(), g.ExKurtosis(), 1e-6, 1e-6) {
+			t.Errorf("shape discontinuous at xi=%v: skewness %v, excess kurtosis %v", xi, small.Skewness(), small.ExKurtosis())
+		}
+	}
+	heavy := GEV{Mu: 0, Sigma: 1, Xi: 1}
+	if !math.IsInf(heavy.Mean(), 1) || !math.IsInf(heavy.Variance(), 1) {
+		t.Errorf("expected infinite moments for heavy tail")
+	}
+}
+
+func TestGeneralizedPareto(t *testing.T) {
+	t.Parallel()
+	src := rand.New(rand.NewSource(1))
+	for i, g := range []GeneralizedPareto{
+		{Mu: 0, Sigma: 1, Xi: 0, Src: src},
+		{Mu: 1, Sigma: 2, Xi: 0.1, Src: src},
+		{Mu: -2, Sigma: 0.5, Xi: -0.4, Src: src},
+		{Mu: 0, Sigma: 1, Xi: -1, Src: src},
+	} {
+		const (
+			tol = 1e-2
+			n   = 5e5
+		)
+		x := make([]float64, n)
+		generateSamples(x, g)
+		sort.Float64s(x)
+
+		lower, upper := g.Mu, math.Inf(1)
+		if g.Xi < 0 {
+			upper = g.Mu - g.Sigma/g.Xi
+		}
+		if x[0] < lower || x[len(x)-1] > upper {
+			t.Errorf("case %d: sample outside support", i)
+		}
+		checkProbContinuous(t, i, x, lower, upper, g, 1e-10)
+		checkEntropy(t, i, x, g, tol)
+		checkMean(t, i, x, g, tol)
+		checkMedian(t, i, x, g, tol)
+		checkVarAndStd(t, i, x, g, 2*tol)
+		checkSkewness(t, i, x, g, 5e-2)
+		checkExKurtosis(t, i, x, g, 2e-1)
+		checkQuantileCDFSurvival(t, i, x, g, tol)
+	}
+
+	// The zero shape is the shifted exponential distribution and the
+	// shape -1 is the uniform distribution.
+	g := GeneralizedPareto{Mu: 1, Sigma: 2}
+	e := Exponential{Rate: 0.5}
+	u := Uniform{Min: 1, Max: 3}
+	gu := GeneralizedPareto{Mu: 1, Sigma: 2, Xi: -1}
+	for _, x := range []float64{1.5, 2, 2.9} {
+		if !scalar.EqualWithinAbsOrRel(g.CDF(x), e.CDF(x-1), 1e-14, 1e-14) {
+			t.Errorf("CDF mismatch with exponential at %v: got %v, want %v", x, g.CDF(x), e.CDF(x-1))
+		}
+		if !scalar.EqualWithinAbsOrRel(gu.CDF(x), u.CDF(x), 1e-14, 1e-14) {
+			t.Errorf("CDF mismatch with uniform at %v: got %v, want %v", x, gu.CDF(x), u.CDF(x))
+		}
+		if !scalar.EqualWithinAbsOrRel(gu.Prob(x), u.Prob(x), 1e-14, 1e-14) {
+			t.Errorf("Prob mismatch with uniform at %v: got %v, want %v", x, gu.Prob(x), u.Prob(x))
+		}
+	}
+	if g.Prob(0.5) != 0 || g.CDF(0.5) != 0 || gu.CDF(4) != 1 || gu.Prob(4) != 0 {
+		t.Errorf("unexpected values outside support")
+	}
+}
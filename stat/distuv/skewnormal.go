@@ -0,0 +1,258 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sync"
+
+	"golang.org/x/exp/rand"
+)
+
+// SkewNormal represents the skew-normal distribution, a continuous
+// distribution with support over the real numbers that extends the normal
+// distribution with a shape parameter controlling the asymmetry.
+//
+// The skew-normal distribution has density function
+//  2/sigma * φ(z) Φ(alpha z)
+//  z = (x - mu)/sigma
+// where φ and Φ are the density and distribution functions of the standard
+// normal distribution. Sigma must be greater than 0. When Alpha is zero the
+// distribution is the normal distribution with mean Mu and standard
+// deviation Sigma.
+//
+// For more information, see https://en.wikipedia.org/wiki/Skew_normal_distribution.
+type SkewNormal struct {
+	Mu    float64 // Location of the distribution
+	Sigma float64 // Scale of the distribution
+	Alpha float64 // Shape of the distribution
+	Src   rand.Source
+}
+
+func (s SkewNormal) z(x float64) float64 {
+	return (x - s.Mu) / s.Sigma
+}
+
+// delta returns alpha/sqrt(1+alpha^2).
+func (s SkewNormal) delta() float64 {
+	return s.Alpha / math.Hypot(1, s.Alpha)
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (s SkewNormal) CDF(x float64) float64 {
+	z := s.z(x)
+	return math.Max(0, math.Min(1, normCDF(z)-2*owensT(z, s.Alpha)))
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution.
+func (s SkewNormal) ExKurtosis() float64 {
+	m := s.delta() * math.Sqrt(2/math.Pi)
+	v := 1 - m*m
+	return 2 * (math.Pi - 3) * m * m * m * m / (v * v)
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (s SkewNormal) LogProb(x float64) float64 {
+	z := s.z(x)
+	return ln2 + negLogRoot2Pi - z*z/2 - math.Log(s.Sigma) + logNormCDF(s.Alpha*z)
+}
+
+// Mean returns the mean of the probability distribution.
+func (s SkewNormal) Mean() float64 {
+	return s.Mu + s.Sigma*s.delta()*math.Sqrt(2/math.Pi)
+}
+
+// Median returns the median of the probability distribution.
+func (s SkewNormal) Median() float64 {
+	return s.Quantile(0.5)
+}
+
+// Mode returns the mode of the probability distribution.
+func (s SkewNormal) Mode() float64 {
+	if s.Alpha == 0 {
+		return s.Mu
+	}
+	// The mode is the root of the derivative of the log density,
+	//  -z + alpha φ(alpha z)/Φ(alpha z)
+	// which lies between zero and the standardized mean.
+	a := math.Abs(s.Alpha)
+	deriv := func(z float64) float64 {
+		return -z + a*math.Exp(negLogRoot2Pi-a*a*z*z/2-logNormCDF(a*z))
+	}
+	lo, hi := 0.0, math.Sqrt(2/math.Pi)
+	for i := 0; i < 100 && hi-lo > 1e-15*hi; i++ {
+		mid := (lo + hi) / 2
+		if deriv(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return s.Mu + s.Sigma*math.Copysign((lo+hi)/2, s.Alpha)
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (SkewNormal) NumParameters() int {
+	return 3
+}
+
+// Prob computes the value of the probability density function at x.
+func (s SkewNormal) Prob(x float64) float64 {
+	return math.Exp(s.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (s SkewNormal) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	if p == 0 {
+		return math.Inf(-1)
+	}
+	if p == 1 {
+		return math.Inf(1)
+	}
+	// Bracket the root and refine it with safeguarded Newton steps.
+	std := SkewNormal{Mu: 0, Sigma: 1, Alpha: s.Alpha}
+	lo, hi := -1.0, 1.0
+	for std.CDF(lo) > p {
+		lo *= 2
+	}
+	for std.CDF(hi) < p {
+		hi *= 2
+	}
+	z := (lo + hi) / 2
+	for i := 0; i < 100; i++ {
+		f := std.CDF(z) - p
+		if f < 0 {
+			lo = z
+		} else {
+			hi = z
+		}
+		next := z - f/std.Prob(z)
+		if !(lo < next && next < hi) {
+			next = (lo + hi) / 2
+		}
+		if math.Abs(next-z) <= 1e-15*math.Max(1, math.Abs(z)) {
+			z = next
+			break
+		}
+		z = next
+	}
+	return s.Mu + s.Sigma*z
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (s SkewNormal) Rand() float64 {
+	normrnd := rand.NormFloat64
+	if s.Src != nil {
+		normrnd = rand.New(s.Src).NormFloat64
+	}
+	// A skew-normal variate is the sum of a half-normal and an
+	// independent normal variate.
+	d := s.delta()
+	z := d*math.Abs(normrnd()) + math.Sqrt(1-d*d)*normrnd()
+	return s.Mu + s.Sigma*z
+}
+
+// Skewness returns the skewness of the distribution.
+func (s SkewNormal) Skewness() float64 {
+	m := s.delta() * math.Sqrt(2/math.Pi)
+	return (4 - math.Pi) / 2 * m * m * m / math.Pow(1-m*m, 1.5)
+}
+
+// StdDev returns the standard deviation of the probability distribution.
+func (s SkewNormal) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (s SkewNormal) Survival(x float64) float64 {
+	z := s.z(x)
+	return math.Max(0, math.Min(1, normCDF(-z)+2*owensT(z, s.Alpha)))
+}
+
+// Variance returns the variance of the probability distribution.
+func (s SkewNormal) Variance() float64 {
+	d := s.delta()
+	return s.Sigma * s.Sigma * (1 - 2*d*d/math.Pi)
+}
+
+// logNormCDF returns the logarithm of the standard normal cumulative
+// distribution function at x.
+func logNormCDF(x float64) float64 {
+	return logNormSurvival(-x)
+}
+
+// owensT returns Owen's T function
+//  T(h, a) = 1/(2π) ∫_0^a exp(-h²(1+x²)/2)/(1+x²) dx
+// evaluated by Gauss-Legendre quadrature, using the identity
+//  T(h, a) + T(ah, 1/a) = ½Φ(h)Q(ah) + ½Φ(ah)Q(h)
+// for h ≥ 0 and a > 1 to keep the integration interval short.
+func owensT(h, a float64) float64 {
+	h = math.Abs(h)
+	if a < 0 {
+		return -owensT(h, -a)
+	}
+	if a == 0 {
+		return 0
+	}
+	if a > 1 {
+		ah := a * h
+		return (normCDF(h)*normCDF(-ah)+normCDF(ah)*normCDF(-h))/2 - owensT(ah, 1/a)
+	}
+	if math.IsInf(h, 1) {
+		return 0
+	}
+	f := func(x float64) float64 {
+		q := 1 + x*x
+		return math.Exp(-h*h*q/2) / q
+	}
+	owensTOnce.Do(func() {
+		owensTX, owensTW = gaussLegendre(owensTNodes)
+	})
+	var sum float64
+	for i, x := range owensTX {
+		sum += owensTW[i] * f(a*(x+1)/2)
+	}
+	return sum * a / 2 / (2 * math.Pi)
+}
+
+// owensTNodes is the number of Gauss-Legendre nodes used by owensT.
+const owensTNodes = 48
+
+var (
+	owensTOnce       sync.Once
+	owensTX, owensTW []float64
+)
+
+// gaussLegendre returns the nodes and weights of the n-point Gauss-Legendre
+// rule on [-1, 1], computed by Newton iteration on the Legendre polynomial.
+// The integrate/quad package is not used since its tests depend on distuv.
+func gaussLegendre(n int) (x, w []float64) {
+	x = make([]float64, n)
+	w = make([]float64, n)
+	for i := 0; i < (n+1)/2; i++ {
+		z := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			p0, p1 := 1.0, z
+			for k := 2; k <= n; k++ {
+				p0, p1 = p1, ((2*float64(k)-1)*z*p1-(float64(k)-1)*p0)/float64(k)
+			}
+			dp = float64(n) * (z*p1 - p0) / (z*z - 1)
+			dz := p1 / dp
+			z -= dz
+			if math.Abs(dz) < 1e-16 {
+				break
+			}
+		}
+		x[i], x[n-1-i] = -z, z
+		w[i] = 2 / ((1 - z*z) * dp * dp)
+		w[n-1-i] = w[i]
+	}
+	return x, w
+}
@@ -0,0 +1,133 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestOwensT(t *testing.T) {
+	t.Parallel()
+	for _, h := range []float64{0, 0.3, 1, 2.5, 6} {
+		for _, a := range []float64{-3, -0.5, 0, 0.2, 1, 4, 30} {
+			got := owensT(h, a)
+			var want float64
+			switch {
+			case h == 0:
+				// T(0, a) = atan(a)/(2π).
+				want = math.Atan(a) / (2 * math.Pi)
+			case a == 1:
+				// T(h, 1) = Φ(h)Q(h)/2.
+				want = normCDF(h) * normCDF(-h) / 2
+			default:
+				// T(h, a) is the integral from h to ∞ of
+				// φ(x)(Φ(ax)-1/2) dx, here by symmetry in a.
+				f := func(x float64) float64 {
+					return UnitNormal.Prob(x) * (normCDF(a*x) - 0.5)
+				}
+				want = integrate(f, h, h+40)
+			}
+			if !scalar.EqualWithinAbsOrRel(got, want, 1e-13, 1e-10) {
+				t.Errorf("unexpected T(%v, %v): got %v, want %v", h, a, got, want)
+			}
+			if owensT(-h, a) != got {
+				t.Errorf("T(%v, %v) not even in h", h, a)
+			}
+		}
+	}
+}
+
+// integrate returns the integral of f from a to b by composite Simpson's rule.
+func integrate(f func(float64) float64, a, b float64) float64 {
+	const n = 100000
+	h := (b - a) / n
+	sum := f(a) + f(b)
+	for i := 1; i < n; i++ {
+		w := 2.0
+		if i%2 == 1 {
+			w = 4
+		}
+		sum += w * f(a+float64(i)*h)
+	}
+	return sum * h / 3
+}
+
+func TestSkewNormal(t *testing.T) {
+	t.Parallel()
+	src := rand.New(rand.NewSource(1))
+	for i, s := range []SkewNormal{
+		{Mu: 0, Sigma: 1, Alpha: 0, Src: src},
+		{Mu: 0, Sigma: 1, Alpha: 4, Src: src},
+		{Mu: 2, Sigma: 0.5, Alpha: -1.5, Src: src},
+		{Mu: -3, Sigma: 2, Alpha: 0.7, Src: src},
+		{Mu: 1, Sigma: 1, Alpha: 20, Src: src},
+	} {
+		const (
+			tol = 1e-2
+			n   = 2e5
+		)
+		x := make([]float64, n)
+		generateSamples(x, s)
+		sort.Float64s(x)
+
+		checkProbContinuous(t, i, x, math.Inf(-1), math.Inf(1), s, 1e-10)
+		checkMean(t, i, x, s, tol)
+		checkMedian(t, i, x, s, tol)
+		checkVarAndStd(t, i, x, s, tol)
+		checkSkewness(t, i, x, s, 5e-2)
+		checkExKurtosis(t, i, x, s, 1e-1)
+		checkQuantileCDFSurvival(t, i, x, s, tol)
+		checkProbQuantContinuous(t, i, x, s, tol)
+		checkMode(t, i, x, s, 1e-1*s.Sigma, 1e-1*s.Sigma)
+
+		// The mode is a stationary point of the density.
+		mode := s.Mode()
+		const h = 1e-6
+		if d := (s.LogProb(mode+h) - s.LogProb(mode-h)) / (2 * h); math.Abs(d) > 1e-6/s.Sigma {
+			t.Errorf("case %d: nonzero derivative at mode %v: %v", i, mode, d)
+		}
+	}
+}
+
+func TestSkewNormalSpecialCases(t *testing.T) {
+	t.Parallel()
+	n := Normal{Mu: 1.5, Sigma: 2}
+	s := SkewNormal{Mu: 1.5, Sigma: 2}
+	// The skew-normal distribution with Alpha = 1 has CDF Φ(z)^2.
+	s1 := SkewNormal{Mu: 0, Sigma: 1, Alpha: 1}
+	for _, x := range []float64{-4, -1, 0, 0.3, 2, 5} {
+		if !scalar.EqualWithinAbsOrRel(s.CDF(x), n.CDF(x), 1e-15, 1e-15) {
+			t.Errorf("CDF mismatch with normal at %v: got %v, want %v", x, s.CDF(x), n.CDF(x))
+		}
+		if !scalar.EqualWithinAbsOrRel(s.LogProb(x), n.LogProb(x), 1e-14, 1e-14) {
+			t.Errorf("LogProb mismatch with normal at %v: got %v, want %v", x, s.LogProb(x), n.LogProb(x))
+		}
+		phi := normCDF(x)
+		if !scalar.EqualWithinAbsOrRel(s1.CDF(x), phi*phi, 1e-14, 1e-14) {
+			t.Errorf("unexpected CDF for alpha=1 at %v: got %v, want %v", x, s1.CDF(x), phi*phi)
+		}
+	}
+	if !scalar.EqualWithinAbsOrRel(s.Quantile(0.3), n.Quantile(0.3), 1e-14, 1e-14) {
+		t.Errorf("Quantile mismatch with normal: got %v, want %v", s.Quantile(0.3), n.Quantile(0.3))
+	}
+	if s.Mode() != n.Mode() || s.Skewness() != 0 || s.ExKurtosis() != 0 {
+		t.Errorf("unexpected shape for zero alpha")
+	}
+
+	// Large shape parameters approach the half-normal distribution.
+	h := SkewNormal{Mu: 0, Sigma: 1, Alpha: 1e6}
+	if !scalar.EqualWithinAbsOrRel(h.Mean(), math.Sqrt(2/math.Pi), 1e-10, 1e-10) {
+		t.Errorf("unexpected mean for large alpha: %v", h.Mean())
+	}
+	if !scalar.EqualWithinAbsOrRel(h.CDF(1), math.Erf(1/math.Sqrt2), 1e-10, 1e-10) {
+		t.Errorf("unexpected CDF for large alpha: %v", h.CDF(1))
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+// TruncatedNormal represents a normal distribution with mean Mu and standard
+// deviation Sigma restricted to the interval [Min, Max]
+// (https://en.wikipedia.org/wiki/Truncated_normal_distribution).
+// Either bound may be infinite. The methods of TruncatedNormal remain accurate
+// when the interval lies far in the tail of the underlying normal
+// distribution.
+type TruncatedNormal struct {
+	Mu    float64 // Mean of the underlying normal distribution
+	Sigma float64 // Standard deviation of the underlying normal distribution
+	Min   float64 // Lower bound of the support
+	Max   float64 // Upper bound of the support
+	Src   rand.Source
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (t TruncatedNormal) CDF(x float64) float64 {
+	if x <= t.Min {
+		return 0
+	}
+	if x >= t.Max {
+		return 1
+	}
+	s := t.std()
+	z := (x - t.Mu) / t.Sigma
+	if s.flip {
+		return s.survival(-z)
+	}
+	return s.cdf(z)
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (t TruncatedNormal) Entropy() float64 {
+	s := t.std()
+	pa, pb := s.densityRatios()
+	return logRoot2Pi + 0.5 + math.Log(t.Sigma) + s.logZ + (mulFinite(s.a, pa)-mulFinite(s.b, pb))/2
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (t TruncatedNormal) LogProb(x float64) float64 {
+	if x < t.Min || x > t.Max {
+		return math.Inf(-1)
+	}
+	s := t.std()
+	z := (x - t.Mu) / t.Sigma
+	return negLogRoot2Pi - z*z/2 - math.Log(t.Sigma) - s.logZ
+}
+
+// Mean returns the mean of the probability distribution.
+func (t TruncatedNormal) Mean() float64 {
+	s := t.std()
+	pa, pb := s.densityRatios()
+	m := pa - pb
+	if s.flip {
+		m = -m
+	}
+	return t.Mu + t.Sigma*m
+}
+
+// Median returns the median of the probability distribution.
+func (t TruncatedNormal) Median() float64 {
+	return t.Quantile(0.5)
+}
+
+// Mode returns the mode of the probability distribution.
+func (t TruncatedNormal) Mode() float64 {
+	return math.Max(t.Min, math.Min(t.Max, t.Mu))
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (TruncatedNormal) NumParameters() int {
+	return 4
+}
+
+// Prob computes the value of the probability density function at x.
+func (t TruncatedNormal) Prob(x float64) float64 {
+	return math.Exp(t.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (t TruncatedNormal) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	if p == 0 {
+		return t.Min
+	}
+	if p == 1 {
+		return t.Max
+	}
+	s := t.std()
+	var z float64
+	if s.flip {
+		z = -s.quantile(1 - p)
+	} else {
+		z = s.quantile(p)
+	}
+	return math.Max(t.Min, math.Min(t.Max, t.Mu+t.Sigma*z))
+}
+
+// Rand returns a random sample drawn from the distribution.
+//
+// Samples are generated by rejection from a normal, uniform or translated
+// exponential proposal, whichever is most efficient for the truncation
+// interval.
+//  Robert, C. P. "Simulation of truncated normal variables." Statistics and
+//  Computing 5.2 (1995): 121-125.
+func (t TruncatedNormal) Rand() float64 {
+	unifrnd := rand.Float64
+	exprnd := rand.ExpFloat64
+	normrnd := rand.NormFloat64
+	if t.Src != nil {
+		rnd := rand.New(t.Src)
+		unifrnd = rnd.Float64
+		exprnd = rnd.ExpFloat64
+		normrnd = rnd.NormFloat64
+	}
+
+	s := t.std()
+	a, b := s.a, s.b
+	var z float64
+	switch {
+	case a < 0 && b-a >= math.Sqrt(2*math.Pi):
+		// The interval contains the mode and at least half the mass
+		// of the normal distribution.
+		for {
+			z = normrnd()
+			if a <= z && z <= b {
+				break
+			}
+		}
+	case a >= 0 && b > a+2*math.Sqrt(math.E)/(a+math.Sqrt(a*a+4))*math.Exp((a*a-a*math.Sqrt(a*a+4))/4):
+		// The interval is a wide tail region, where an exponential
+		// proposal with the optimal rate is used.
+		lambda := (a + math.Sqrt(a*a+4)) / 2
+		for {
+			z = a + exprnd()/lambda
+			d := z - lambda
+			if z <= b && unifrnd() <= math.Exp(-d*d/2) {
+				break
+			}
+		}
+	default:
+		// The interval is narrow, where a uniform proposal is used.
+		var m2 float64
+		if a > 0 {
+			m2 = a * a
+		}
+		for {
+			z = a + (b-a)*unifrnd()
+			if unifrnd() <= math.Exp((m2-z*z)/2) {
+				break
+			}
+		}
+	}
+	if s.flip {
+		z = -z
+	}
+	return math.Max(t.Min, math.Min(t.Max, t.Mu+t.Sigma*z))
+}
+
+// StdDev returns the standard deviation of the probability distribution.
+func (t TruncatedNormal) StdDev() float64 {
+	return math.Sqrt(t.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (t TruncatedNormal) Survival(x float64) float64 {
+	if x <= t.Min {
+		return 1
+	}
+	if x >= t.Max {
+		return 0
+	}
+	s := t.std()
+	z := (x - t.Mu) / t.Sigma
+	if s.flip {
+		return s.cdf(-z)
+	}
+	return s.survival(z)
+}
+
+// Variance returns the variance of the probability distribution.
+func (t TruncatedNormal) Variance() float64 {
+	s := t.std()
+	pa, pb := s.densityRatios()
+	m := pa - pb
+	return t.Sigma * t.Sigma * (1 + mulFinite(s.a, pa) - mulFinite(s.b, pb) - m*m)
+}
+
+// truncStd is a standard normal distribution truncated to [a, b], reflected
+// if necessary so that either a < 0 < b or 0 ≤ a. In the second case the
+// distribution is computed from ratios of the normal survival function
+// relative to its value at a to avoid underflow and cancellation in the tail.
+type truncStd struct {
+	a, b float64
+	flip bool
+	tail bool
+
+	// logQba is the log of the ratio of the normal survival function at
+	// b to that at a, and is only set when tail is true.
+	logQba float64
+	// logZ is the log of the normal probability of [a, b].
+	logZ float64
+}
+
+// std returns the standardized distribution of t, panicking if the
+// parameters of t are invalid.
+func (t TruncatedNormal) std() truncStd {
+	if !(t.Sigma > 0) {
+		panic("truncatednormal: sigma <= 0")
+	}
+	if !(t.Min < t.Max) {
+		panic("truncatednormal: min >= max")
+	}
+	s := truncStd{a: (t.Min - t.Mu) / t.Sigma, b: (t.Max - t.Mu) / t.Sigma}
+	if s.b <= 0 {
+		s.a, s.b = -s.b, -s.a
+		s.flip = true
+	}
+	if s.a >= 0 {
+		s.tail = true
+		s.logQba = logNormSurvivalRatio(s.b, s.a)
+		s.logZ = logNormSurvival(s.a) + math.Log(-math.Expm1(s.logQba))
+	} else {
+		s.logZ = math.Log(normCDF(s.b) - normCDF(s.a))
+	}
+	return s
+}
+
+// cdf returns the cumulative distribution function of s at z in (a, b).
+func (s truncStd) cdf(z float64) float64 {
+	if s.tail {
+		return math.Expm1(logNormSurvivalRatio(z, s.a)) / math.Expm1(s.logQba)
+	}
+	return (normCDF(z) - normCDF(s.a)) / math.Exp(s.logZ)
+}
+
+// survival returns the survival function of s at z in (a, b).
+func (s truncStd) survival(z float64) float64 {
+	if s.tail {
+		return (math.Exp(logNormSurvivalRatio(z, s.a)) - math.Exp(s.logQba)) / -math.Expm1(s.logQba)
+	}
+	return (normCDF(s.b) - normCDF(z)) / math.Exp(s.logZ)
+}
+
+// quantile returns the quantile of s at p in (0, 1).
+func (s truncStd) quantile(p float64) float64 {
+	if !s.tail {
+		return mathext.NormalQuantile(normCDF(s.a) + p*math.Exp(s.logZ))
+	}
+	d := math.Log1p(p * math.Expm1(s.logQba))
+	if s.a < millsThreshold {
+		return -mathext.NormalQuantile(math.Exp(logNormSurvival(s.a) + d))
+	}
+	// Newton's method on the log survival ratio, whose derivative is the
+	// negated hazard function, starting from the exponential
+	// approximation to the tail.
+	z := s.a - d/s.a
+	for i := 0; i < 100; i++ {
+		step := (logNormSurvivalRatio(z, s.a) - d) / math.Exp(logNormHazard(z))
+		z += step
+		if math.Abs(step) <= 1e-15*z {
+			break
+		}
+	}
+	return z
+}
+
+// densityRatios returns the normal density at the bounds of s divided by the
+// normal probability of [a, b].
+func (s truncStd) densityRatios() (pa, pb float64) {
+	if s.tail {
+		scale := -math.Expm1(s.logQba)
+		pa = math.Exp(logNormHazard(s.a)) / scale
+		if !math.IsInf(s.b, 1) {
+			pb = math.Exp(logNormHazard(s.b)+s.logQba) / scale
+		}
+		return pa, pb
+	}
+	return math.Exp(negLogRoot2Pi - s.a*s.a/2 - s.logZ), math.Exp(negLogRoot2Pi - s.b*s.b/2 - s.logZ)
+}
+
+// mulFinite returns x*y, or zero if y is zero.
+func mulFinite(x, y float64) float64 {
+	if y == 0 {
+		return 0
+	}
+	return x * y
+}
+
+// normCDF returns the standard normal cumulative distribution function at x.
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// millsThreshold is the argument above which the standard normal survival
+// function is computed from the asymptotic expansion of the Mills ratio,
+//  Q(x) = φ(x)/x (1 - 1/x² + 3/x⁴ - 15/x⁶ + ...)
+const millsThreshold = 37
+
+// millsSeries returns the truncated series in the asymptotic expansion of
+// the Mills ratio at x.
+func millsSeries(x float64) float64 {
+	r := 1 / (x * x)
+	return 1 - r*(1-3*r*(1-5*r*(1-7*r*(1-9*r))))
+}
+
+// logNormSurvival returns the logarithm of the standard normal survival
+// function at x.
+func logNormSurvival(x float64) float64 {
+	if x < millsThreshold {
+		return math.Log(0.5 * math.Erfc(x/math.Sqrt2))
+	}
+	if math.IsInf(x, 1) {
+		return math.Inf(-1)
+	}
+	return negLogRoot2Pi - x*x/2 - math.Log(x) + math.Log(millsSeries(x))
+}
+
+// logNormSurvivalRatio returns the logarithm of the ratio of the standard
+// normal survival function at x to its value at a, for x ≥ a.
+func logNormSurvivalRatio(x, a float64) float64 {
+	if math.IsInf(x, 1) {
+		return math.Inf(-1)
+	}
+	if a < millsThreshold {
+		return logNormSurvival(x) - logNormSurvival(a)
+	}
+	return -(x-a)*(x+a)/2 - math.Log(x/a) + math.Log(millsSeries(x)/millsSeries(a))
+}
+
+// logNormHazard returns the logarithm of the ratio of the standard normal
+// density to the survival function at x.
+func logNormHazard(x float64) float64 {
+	if x < millsThreshold {
+		return negLogRoot2Pi - x*x/2 - logNormSurvival(x)
+	}
+	return math.Log(x) - math.Log(millsSeries(x))
+}
@@ -0,0 +1,144 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestTruncatedNormal(t *testing.T) {
+	t.Parallel()
+	src := rand.New(rand.NewSource(1))
+	inf := math.Inf(1)
+	for i, test := range []struct {
+		mu, sigma, min, max float64
+	}{
+		{mu: 0, sigma: 1, min: -1, max: 2},
+		{mu: 0, sigma: 1, min: -inf, max: 0.5},
+		{mu: 2, sigma: 0.5, min: -inf, max: inf},
+		{mu: 1, sigma: 2, min: 3, max: inf},
+		{mu: 0, sigma: 1, min: 5, max: 5.2},
+		{mu: 0, sigma: 1, min: 4, max: 7},
+		{mu: 0, sigma: 1, min: -inf, max: -4},
+		{mu: 0, sigma: 1, min: -0.3, max: 0.4},
+		{mu: -1, sigma: 3, min: -20, max: -12},
+	} {
+		f := TruncatedNormal{Mu: test.mu, Sigma: test.sigma, Min: test.min, Max: test.max, Src: src}
+		const (
+			tol = 1e-2
+			n   = 1e5
+		)
+		x := make([]float64, n)
+		generateSamples(x, f)
+		sort.Float64s(x)
+
+		lower := math.Max(test.min, test.mu-20*test.sigma)
+		upper := math.Min(test.max, test.mu+20*test.sigma)
+		scale := f.StdDev()
+		checkMean(t, i, x, f, tol*scale)
+		checkVarAndStd(t, i, x, f, tol)
+		checkEntropy(t, i, x, f, tol)
+		checkMedian(t, i, x, f, tol*scale)
+		checkQuantileCDFSurvival(t, i, x, f, tol)
+		checkProbContinuous(t, i, x, lower, upper, f, 1e-10)
+		checkProbQuantContinuous(t, i, x, f, tol)
+
+		if x[0] < test.min || x[len(x)-1] > test.max {
+			t.Errorf("sample out of bounds case %d: [%v, %v]", i, x[0], x[len(x)-1])
+		}
+		if test.min <= test.mu && test.mu <= test.max && f.Mode() != test.mu {
+			t.Errorf("unexpected mode case %d: got %v, want %v", i, f.Mode(), test.mu)
+		}
+	}
+}
+
+func TestTruncatedNormalHalfNormal(t *testing.T) {
+	t.Parallel()
+	// The standard normal truncated to the positive half line is the
+	// half-normal distribution.
+	f := TruncatedNormal{Mu: 0, Sigma: 1, Min: 0, Max: math.Inf(1)}
+	for _, test := range []struct {
+		name      string
+		got, want float64
+	}{
+		{name: "mean", got: f.Mean(), want: math.Sqrt(2 / math.Pi)},
+		{name: "variance", got: f.Variance(), want: 1 - 2/math.Pi},
+		{name: "entropy", got: f.Entropy(), want: 0.5 * math.Log(math.Pi*math.E/2)},
+		{name: "median", got: f.Median(), want: math.Sqrt2 * math.Erfinv(0.5)},
+		{name: "density", got: f.Prob(1), want: math.Sqrt(2/math.Pi) * math.Exp(-0.5)},
+		{name: "cdf", got: f.CDF(1), want: math.Erf(1 / math.Sqrt2)},
+	} {
+		if !scalar.EqualWithinAbsOrRel(test.got, test.want, 1e-14, 1e-14) {
+			t.Errorf("unexpected %s: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestTruncatedNormalTail(t *testing.T) {
+	t.Parallel()
+	// Far in the tail, the distribution of z - a for the standard normal
+	// truncated to [a, ∞) is approximately exponential with rate a.
+	for _, a := range []float64{50, 1e3, 1e5} {
+		for _, flip := range []bool{false, true} {
+			f := TruncatedNormal{Mu: 0, Sigma: 1, Min: a, Max: math.Inf(1)}
+			sign := 1.0
+			if flip {
+				f.Min, f.Max = math.Inf(-1), -a
+				sign = -1
+			}
+			src := rand.NewSource(1)
+			f.Src = src
+
+			// The mean has the expansion a + 1/a - 2/a³ + 10/a⁵.
+			if want := sign * (a + 1/a - 2/(a*a*a) + 10/(a*a*a*a*a)); !scalar.EqualWithinAbsOrRel(f.Mean(), want, 1e-11, 1e-11) {
+				t.Errorf("a=%v flip=%t: unexpected mean: got %v, want %v", a, flip, f.Mean(), want)
+			}
+			// The spacing of floating point values near a limits the
+			// resolution of the CDF to about a² times machine epsilon.
+			tol := math.Max(1e-12, 1e-15*a*a)
+			for _, p := range []float64{0.01, 0.5, 0.99} {
+				want := sign * (a - math.Log(1-p)/a)
+				if flip {
+					want = sign * (a - math.Log(p)/a)
+				}
+				x := f.Quantile(p)
+				if math.Abs(x-want) > 1e-2/a {
+					t.Errorf("a=%v flip=%t: unexpected quantile at %v: got %v, want %v", a, flip, p, x, want)
+				}
+				if got := f.CDF(x); !scalar.EqualWithinAbsOrRel(got, p, tol, tol) {
+					t.Errorf("a=%v flip=%t: quantile and CDF mismatch: got %v, want %v", a, flip, got, p)
+				}
+			}
+			if lp := f.LogProb(sign * a); !scalar.EqualWithinAbsOrRel(lp, math.Log(a), 1e-3, 1e-3) {
+				t.Errorf("a=%v flip=%t: unexpected log density at bound: got %v, want %v", a, flip, lp, math.Log(a))
+			}
+			for i := 0; i < 1000; i++ {
+				if x := sign * f.Rand(); x < a || math.IsInf(x, 0) {
+					t.Errorf("a=%v flip=%t: sample out of bounds: %v", a, flip, x)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestTruncatedNormalPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []TruncatedNormal{
+		{Mu: 0, Sigma: 0, Min: -1, Max: 1},
+		{Mu: 0, Sigma: 1, Min: 1, Max: 1},
+		{Mu: 0, Sigma: 1, Min: 2, Max: 1},
+	} {
+		if !panics(func() { test.Mean() }) {
+			t.Errorf("expected panic for %+v", test)
+		}
+	}
+}
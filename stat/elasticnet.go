@@ -0,0 +1,314 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultElasticNetIterations = 10000
+	defaultElasticNetTolerance  = 1e-14
+	defaultElasticNetPathLen    = 100
+)
+
+// ElasticNet is a type for fitting linear models with an elastic net penalty
+// by cyclic coordinate descent. The coefficients β and intercept β₀ minimize
+//  1/(2Σw) Σ_i w_i (y_i - β₀ - x_iᵀβ)² + λ(α‖β‖₁ + (1-α)/2 ‖β‖²)
+// where x_i is the ith row of the design matrix, w_i are the weights of the
+// observations, λ ≥ 0 is the strength of the penalty and α in [0, 1] mixes the
+// lasso (α = 1) and ridge (α = 0) penalties. The intercept is not penalized.
+//  Friedman, J., Hastie, T. and Tibshirani, R. "Regularization paths for
+//  generalized linear models via coordinate descent." Journal of Statistical
+//  Software 33.1 (2010): 1-22.
+//
+// The exported fields of ElasticNet configure the fit.
+type ElasticNet struct {
+	// Alpha is the elastic net mixing parameter in [0, 1]. The zero
+	// value gives ridge regression.
+	Alpha float64
+
+	// Standardize specifies whether the columns of the design matrix
+	// are scaled to unit weighted variance before fitting, so that the
+	// penalty treats the variables equally regardless of their units.
+	// The coefficients are always reported on the original scale.
+	Standardize bool
+
+	// Origin specifies that the model has no intercept, forcing the
+	// regression through the origin.
+	Origin bool
+
+	// MaxIterations is the maximum number of passes of coordinate
+	// descent over the variables for each λ. If MaxIterations is zero,
+	// 10000 are used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance. Coordinate descent stops
+	// when no update in a pass changes the weighted residual sum of
+	// squares by more than Tolerance times the weighted variance of
+	// the response. If Tolerance is zero, 1e-14 is used.
+	Tolerance float64
+}
+
+// PenalizedFit is a linear model fitted with a penalty.
+type PenalizedFit struct {
+	// Lambda is the strength of the penalty.
+	Lambda float64
+
+	// Intercept and Coef are the estimated intercept and coefficients
+	// on the scale of the original variables.
+	Intercept float64
+	Coef      []float64
+
+	// Iterations is the number of passes of coordinate descent and
+	// Converged is whether the passes converged within the maximum.
+	Iterations int
+	Converged  bool
+}
+
+// Fit fits the model to the response y with the n×p design matrix x using the
+// penalty strength lambda. If weights is not nil, it holds the weight of each
+// observation.
+//
+// Fit will panic if the lengths of y or weights do not match the number of
+// rows of x, if a weight is negative or all weights are zero, if lambda is
+// negative, or if Alpha is outside [0, 1].
+func (e ElasticNet) Fit(x mat.Matrix, y, weights []float64, lambda float64) PenalizedFit {
+	return e.Path(x, y, weights, []float64{lambda})[0]
+}
+
+// Path fits the model for each of the penalty strengths in lambdas, using the
+// solution for each λ as the starting point for the next. Path is most
+// efficient when lambdas is decreasing. If lambdas is nil, a sequence of 100
+// values decreasing geometrically from LambdaMax to LambdaMax times 1e-4 is
+// used, or times 1e-2 if x has no more rows than columns.
+//
+// Path panics under the same conditions as Fit.
+func (e ElasticNet) Path(x mat.Matrix, y, weights, lambdas []float64) []PenalizedFit {
+	p := e.prepare(x, y, weights)
+	if lambdas == nil {
+		n, d := x.Dims()
+		eps := 1e-4
+		if n <= d {
+			eps = 1e-2
+		}
+		lambdas = make([]float64, defaultElasticNetPathLen)
+		max := p.lambdaMax(e.Alpha)
+		for i := range lambdas {
+			lambdas[i] = max * math.Pow(eps, float64(i)/float64(len(lambdas)-1))
+		}
+	}
+	maxIter := e.MaxIterations
+	if maxIter == 0 {
+		maxIter = defaultElasticNetIterations
+	}
+	tol := e.Tolerance
+	if tol == 0 {
+		tol = defaultElasticNetTolerance
+	}
+
+	fits := make([]PenalizedFit, len(lambdas))
+	beta := make([]float64, p.d)
+	for k, lambda := range lambdas {
+		if !(lambda >= 0) {
+			panic("stat: negative penalty")
+		}
+		iter, conv := p.descend(beta, lambda, e.Alpha, maxIter, tol)
+		fits[k] = p.fit(beta, lambda)
+		fits[k].Iterations = iter
+		fits[k].Converged = conv
+	}
+	return fits
+}
+
+// LambdaMax returns the smallest penalty strength for which all coefficients
+// of the model fitted to x, y and weights are zero. For ridge regression,
+// where no finite penalty gives zero coefficients, the value for α = 0.001 is
+// returned. LambdaMax panics under the same conditions as Fit.
+func (e ElasticNet) LambdaMax(x mat.Matrix, y, weights []float64) float64 {
+	return e.prepare(x, y, weights).lambdaMax(e.Alpha)
+}
+
+// penalizedProblem is a weighted least squares problem in the centered and
+// optionally scaled variables.
+type penalizedProblem struct {
+	n, d int
+
+	// x holds the columns of the transformed design matrix.
+	x [][]float64
+	// w holds the weights normalized to sum to one.
+	w []float64
+	// r holds the residuals of the current fit.
+	r []float64
+	// v holds the weighted sums of squares of the columns.
+	v []float64
+
+	xMean, xScale []float64
+	yMean, yVar   float64
+}
+
+func (e ElasticNet) prepare(x mat.Matrix, y, weights []float64) *penalizedProblem {
+	n, d := x.Dims()
+	if len(y) != n {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != n {
+		panic("stat: slice length mismatch")
+	}
+	if !(0 <= e.Alpha && e.Alpha <= 1) {
+		panic("stat: elastic net mixing parameter out of range")
+	}
+	p := &penalizedProblem{
+		n:      n,
+		d:      d,
+		x:      make([][]float64, d),
+		w:      make([]float64, n),
+		r:      make([]float64, n),
+		v:      make([]float64, d),
+		xMean:  make([]float64, d),
+		xScale: make([]float64, d),
+	}
+	var sum float64
+	for i := range p.w {
+		p.w[i] = 1
+		if weights != nil {
+			if weights[i] < 0 {
+				panic("stat: negative weight")
+			}
+			p.w[i] = weights[i]
+		}
+		sum += p.w[i]
+	}
+	if sum == 0 {
+		panic("stat: zero weights")
+	}
+	for i := range p.w {
+		p.w[i] /= sum
+	}
+
+	if !e.Origin {
+		for i, v := range y {
+			p.yMean += p.w[i] * v
+		}
+	}
+	for i, v := range y {
+		p.r[i] = v - p.yMean
+		p.yVar += p.w[i] * p.r[i] * p.r[i]
+	}
+	for j := 0; j < d; j++ {
+		col := mat.Col(nil, j, x)
+		if !e.Origin {
+			var m float64
+			for i, v := range col {
+				m += p.w[i] * v
+			}
+			p.xMean[j] = m
+			for i := range col {
+				col[i] -= m
+			}
+		}
+		var ss float64
+		for i, v := range col {
+			ss += p.w[i] * v * v
+		}
+		p.xScale[j] = 1
+		if e.Standardize && ss > 0 {
+			s := math.Sqrt(ss)
+			p.xScale[j] = s
+			for i := range col {
+				col[i] /= s
+			}
+			ss = 1
+		}
+		p.x[j] = col
+		p.v[j] = ss
+	}
+	return p
+}
+
+// lambdaMax returns the smallest λ for which the zero coefficients are
+// optimal with mixing parameter alpha. It must be called before descend,
+// while the residuals are the centered response.
+func (p *penalizedProblem) lambdaMax(alpha float64) float64 {
+	if alpha < 1e-3 {
+		alpha = 1e-3
+	}
+	var max float64
+	for _, col := range p.x {
+		var g float64
+		for i, v := range col {
+			g += p.w[i] * v * p.r[i]
+		}
+		max = math.Max(max, math.Abs(g))
+	}
+	return max / alpha
+}
+
+// descend performs coordinate descent on beta for the penalty lambda and
+// mixing parameter alpha, keeping the residuals up to date, and returns the
+// number of passes and whether they converged.
+func (p *penalizedProblem) descend(beta []float64, lambda, alpha float64, maxIter int, tol float64) (iter int, converged bool) {
+	l1 := lambda * alpha
+	l2 := lambda * (1 - alpha)
+	thresh := tol * p.yVar
+	if thresh == 0 {
+		thresh = tol
+	}
+	for iter = 1; iter <= maxIter; iter++ {
+		var maxChange float64
+		for j, col := range p.x {
+			if p.v[j] == 0 {
+				continue
+			}
+			old := beta[j]
+			g := p.v[j] * old
+			for i, v := range col {
+				g += p.w[i] * v * p.r[i]
+			}
+			b := softThreshold(g, l1) / (p.v[j] + l2)
+			if b == old {
+				continue
+			}
+			delta := b - old
+			for i, v := range col {
+				p.r[i] -= delta * v
+			}
+			beta[j] = b
+			maxChange = math.Max(maxChange, p.v[j]*delta*delta)
+		}
+		if maxChange <= thresh {
+			return iter, true
+		}
+	}
+	return maxIter, false
+}
+
+// fit returns the model with the coefficients beta of the transformed
+// problem on the scale of the original variables.
+func (p *penalizedProblem) fit(beta []float64, lambda float64) PenalizedFit {
+	f := PenalizedFit{
+		Lambda:    lambda,
+		Intercept: p.yMean,
+		Coef:      make([]float64, p.d),
+	}
+	for j, b := range beta {
+		f.Coef[j] = b / p.xScale[j]
+		f.Intercept -= f.Coef[j] * p.xMean[j]
+	}
+	return f
+}
+
+// softThreshold returns the soft thresholding operator sign(z)·max(|z|-t, 0).
+func softThreshold(z, t float64) float64 {
+	switch {
+	case z > t:
+		return z - t
+	case z < -t:
+		return z + t
+	}
+	return 0
+}
@@ -0,0 +1,243 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// penalizedTestData returns a design matrix with correlated columns of
+// differing scales, a response depending on the first three columns, and
+// positive weights.
+func penalizedTestData(rnd *rand.Rand, n, d int) (x *mat.Dense, y, w []float64) {
+	x = mat.NewDense(n, d, nil)
+	y = make([]float64, n)
+	w = make([]float64, n)
+	for i := 0; i < n; i++ {
+		z := rnd.NormFloat64()
+		for j := 0; j < d; j++ {
+			x.Set(i, j, float64(j+1)*(rnd.NormFloat64()+0.5*z)+float64(j))
+		}
+		y[i] = 3 + 2*x.At(i, 0) - x.At(i, 1) + 0.5*x.At(i, 2) + rnd.NormFloat64()
+		w[i] = 0.5 + rnd.Float64()
+	}
+	return x, y, w
+}
+
+func TestElasticNetRidge(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 40, 5
+	x, y, w := penalizedTestData(rnd, n, d)
+	const lambda = 0.7
+	for _, standardize := range []bool{false, true} {
+		fit := ElasticNet{Alpha: 0, Standardize: standardize}.Fit(x, y, w, lambda)
+		if !fit.Converged {
+			t.Errorf("standardize=%t: ridge fit did not converge", standardize)
+		}
+
+		// The ridge solution in the centered variables solves
+		//  (XᵀWX + λS²)β = XᵀWy
+		// where W holds the normalized weights and S holds the
+		// scales of the columns.
+		sumW := floats.Sum(w)
+		xm := make([]float64, d)
+		for j := range xm {
+			xm[j] = Mean(mat.Col(nil, j, x), w)
+		}
+		ym := Mean(y, w)
+		xc := mat.NewDense(n, d, nil)
+		yc := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < d; j++ {
+				xc.Set(i, j, math.Sqrt(w[i]/sumW)*(x.At(i, j)-xm[j]))
+			}
+			yc.SetVec(i, math.Sqrt(w[i]/sumW)*(y[i]-ym))
+		}
+		var a mat.Dense
+		a.Mul(xc.T(), xc)
+		for j := 0; j < d; j++ {
+			s2 := 1.0
+			if standardize {
+				s2 = a.At(j, j)
+			}
+			a.Set(j, j, a.At(j, j)+lambda*s2)
+		}
+		var b, beta mat.VecDense
+		b.MulVec(xc.T(), yc)
+		if err := beta.SolveVec(&a, &b); err != nil {
+			t.Fatalf("bad test: %v", err)
+		}
+		want := beta.RawVector().Data
+		if !floats.EqualApprox(fit.Coef, want, 1e-6) {
+			t.Errorf("standardize=%t: unexpected ridge coefficients: got %v, want %v", standardize, fit.Coef, want)
+		}
+		if wantIntercept := ym - floats.Dot(xm, want); !scalar.EqualWithinAbsOrRel(fit.Intercept, wantIntercept, 1e-6, 1e-6) {
+			t.Errorf("standardize=%t: unexpected intercept: got %v, want %v", standardize, fit.Intercept, wantIntercept)
+		}
+	}
+
+	// Without a penalty the fit is weighted least squares.
+	fit := ElasticNet{}.Fit(x.Slice(0, n, 0, 1), y, w, 0)
+	alpha, beta := LinearRegression(mat.Col(nil, 0, x), y, w, false)
+	if !scalar.EqualWithinAbsOrRel(fit.Intercept, alpha, 1e-8, 1e-8) || !scalar.EqualWithinAbsOrRel(fit.Coef[0], beta, 1e-8, 1e-8) {
+		t.Errorf("unpenalized fit differs from LinearRegression: got (%v, %v), want (%v, %v)", fit.Intercept, fit.Coef[0], alpha, beta)
+	}
+	fit = ElasticNet{Origin: true}.Fit(x.Slice(0, n, 0, 1), y, w, 0)
+	_, beta = LinearRegression(mat.Col(nil, 0, x), y, w, true)
+	if fit.Intercept != 0 || !scalar.EqualWithinAbsOrRel(fit.Coef[0], beta, 1e-8, 1e-8) {
+		t.Errorf("unpenalized fit through origin differs from LinearRegression: got (%v, %v), want (0, %v)", fit.Intercept, fit.Coef[0], beta)
+	}
+}
+
+func TestElasticNetOptimality(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 60, 8
+	x, y, w := penalizedTestData(rnd, n, d)
+	for _, alpha := range []float64{1, 0.5} {
+		for _, standardize := range []bool{false, true} {
+			e := ElasticNet{Alpha: alpha, Standardize: standardize, Tolerance: 1e-16}
+			lmax := e.LambdaMax(x, y, w)
+			for _, lambda := range []float64{lmax, 0.3 * lmax, 0.01 * lmax} {
+				fit := e.Fit(x, y, w, lambda)
+				if !fit.Converged {
+					t.Errorf("alpha=%v standardize=%t lambda=%v: fit did not converge", alpha, standardize, lambda)
+				}
+
+				// The subgradient optimality conditions of the
+				// objective in the scaled variables are
+				//  g_j = λα sign(β_j) + λ(1-α)β_j  if β_j ≠ 0
+				//  |g_j| ≤ λα                      if β_j = 0
+				// where g_j is the weighted correlation of the
+				// scaled jth variable with the residuals.
+				sumW := floats.Sum(w)
+				r := make([]float64, n)
+				for i := range r {
+					r[i] = y[i] - fit.Intercept - floats.Dot(x.RawRowView(i), fit.Coef)
+				}
+				if m := Mean(r, w); math.Abs(m) > 1e-10 {
+					t.Errorf("alpha=%v standardize=%t lambda=%v: residuals not centered: %v", alpha, standardize, lambda, m)
+				}
+				var nonzero int
+				for j := 0; j < d; j++ {
+					col := mat.Col(nil, j, x)
+					m := Mean(col, w)
+					scale := 1.0
+					if standardize {
+						scale = math.Sqrt(PopVariance(col, w))
+					}
+					var g float64
+					for i, v := range col {
+						g += w[i] / sumW * (v - m) / scale * r[i]
+					}
+					b := fit.Coef[j] * scale
+					if b == 0 {
+						if math.Abs(g) > lambda*alpha*(1+1e-6) {
+							t.Errorf("alpha=%v standardize=%t lambda=%v: optimality violated at zero coefficient %d: |%v| > %v",
+								alpha, standardize, lambda, j, g, lambda*alpha)
+						}
+						continue
+					}
+					nonzero++
+					want := lambda*alpha*math.Copysign(1, b) + lambda*(1-alpha)*b
+					if math.Abs(g-want) > 1e-5*lambda {
+						t.Errorf("alpha=%v standardize=%t lambda=%v: optimality violated at coefficient %d: %v != %v",
+							alpha, standardize, lambda, j, g, want)
+					}
+				}
+				if lambda == lmax && nonzero != 0 {
+					t.Errorf("alpha=%v standardize=%t: nonzero coefficients at LambdaMax: %v", alpha, standardize, fit.Coef)
+				}
+				if lambda < lmax && nonzero == 0 {
+					t.Errorf("alpha=%v standardize=%t lambda=%v: all coefficients zero below LambdaMax", alpha, standardize, lambda)
+				}
+			}
+		}
+	}
+}
+
+func TestElasticNetPath(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 50, 6
+	x, y, _ := penalizedTestData(rnd, n, d)
+	e := ElasticNet{Alpha: 1, Standardize: true}
+	path := e.Path(x, y, nil, nil)
+	if len(path) != defaultElasticNetPathLen {
+		t.Fatalf("unexpected path length: got %d, want %d", len(path), defaultElasticNetPathLen)
+	}
+	lmax := e.LambdaMax(x, y, nil)
+	if !scalar.EqualWithinAbsOrRel(path[0].Lambda, lmax, 1e-14, 1e-14) ||
+		!scalar.EqualWithinAbsOrRel(path[len(path)-1].Lambda, 1e-4*lmax, 1e-14, 1e-14) {
+		t.Errorf("unexpected penalty range: [%v, %v]", path[len(path)-1].Lambda, path[0].Lambda)
+	}
+	if floats.Norm(path[0].Coef, 1) != 0 {
+		t.Errorf("nonzero coefficients at start of path: %v", path[0].Coef)
+	}
+	var prevNorm float64
+	for k, fit := range path {
+		if !fit.Converged {
+			t.Errorf("fit %d did not converge", k)
+		}
+		if k > 0 && fit.Lambda >= path[k-1].Lambda {
+			t.Errorf("penalties not decreasing at %d", k)
+		}
+		// The fits along the path match independent fits.
+		if k%20 == 0 {
+			single := e.Fit(x, y, nil, fit.Lambda)
+			if !floats.EqualApprox(single.Coef, fit.Coef, 1e-6) {
+				t.Errorf("path fit %d differs from single fit: %v != %v", k, fit.Coef, single.Coef)
+			}
+		}
+		norm := floats.Norm(fit.Coef, 1)
+		if norm < prevNorm*(1-1e-6) && k > 0 && fit.Lambda < path[k-1].Lambda {
+			// The lasso path for these data is monotone in the
+			// ℓ1 norm of the coefficients.
+			t.Errorf("coefficient norm decreased along path at %d: %v to %v", k, prevNorm, norm)
+		}
+		prevNorm = norm
+	}
+
+	// Standardization makes the fit invariant to the scale of the
+	// variables.
+	scaled := mat.DenseCopyOf(x)
+	for i := 0; i < n; i++ {
+		scaled.Set(i, 1, 100*scaled.At(i, 1))
+	}
+	lambda := path[30].Lambda
+	a := e.Fit(x, y, nil, lambda)
+	b := e.Fit(scaled, y, nil, lambda)
+	b.Coef[1] *= 100
+	if !floats.EqualApprox(a.Coef, b.Coef, 1e-6) || !scalar.EqualWithinAbsOrRel(a.Intercept, b.Intercept, 1e-6, 1e-6) {
+		t.Errorf("standardized fit depends on variable scale: %v != %v", a.Coef, b.Coef)
+	}
+}
+
+func TestElasticNetPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 1, []float64{1, 2, 3})
+	y := []float64{1, 2, 4}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "alpha", fn: func() { ElasticNet{Alpha: 2}.Fit(x, y, nil, 1) }},
+		{name: "lambda", fn: func() { ElasticNet{}.Fit(x, y, nil, -1) }},
+		{name: "length", fn: func() { ElasticNet{}.Fit(x, y[:2], nil, 1) }},
+		{name: "weights", fn: func() { ElasticNet{}.Fit(x, y, []float64{0, 0, 0}, 1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
@@ -0,0 +1,531 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultGLMIterations = 25
+	defaultGLMTolerance  = 1e-8
+)
+
+// GLMFamily specifies the distribution of the response and the link function
+// of a generalized linear model.
+type GLMFamily int
+
+const (
+	// GaussianFamily is the normal distribution with the identity link,
+	// giving ordinary weighted least squares regression.
+	GaussianFamily GLMFamily = iota
+
+	// BinomialFamily is the binomial distribution with the logit link,
+	// giving logistic regression. The response is the proportion of
+	// successes in [0, 1], and the weights are the numbers of trials.
+	BinomialFamily
+
+	// PoissonFamily is the Poisson distribution with the log link,
+	// giving Poisson regression of non-negative counts. Offsets are
+	// typically the log of the exposure of each observation.
+	PoissonFamily
+
+	// GammaFamily is the gamma distribution with the log link, for
+	// positive responses with constant coefficient of variation.
+	GammaFamily
+)
+
+// GLM is a type for fitting generalized linear models by iteratively
+// reweighted least squares (IRLS). A generalized linear model relates the
+// mean μ_i of the response y_i to the linear predictor
+//  η_i = x_iᵀβ + o_i
+// through the link function g(μ_i) = η_i, where x_i is the ith row of the
+// design matrix and o_i is a known offset. The variance of the response is
+// φV(μ_i)/w_i for a variance function V determined by the family, the
+// dispersion φ and prior weights w_i.
+//  McCullagh, P. and Nelder, J. A. "Generalized Linear Models." 2nd ed.
+//  Chapman and Hall (1989).
+//
+// The exported fields of GLM configure the fit and are read by Fit. The
+// results are only valid after a successful call to Fit.
+type GLM struct {
+	// Family is the distribution and link function of the model.
+	Family GLMFamily
+
+	// MaxIterations is the maximum number of IRLS iterations. If
+	// MaxIterations is zero, 25 are used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance for the relative change
+	// of the deviance |D - D_prev|/(|D| + 0.1) between iterations. If
+	// Tolerance is zero, 1e-8 is used.
+	Tolerance float64
+
+	coef         []float64
+	cov          mat.SymDense
+	mu           []float64
+	deviance     float64
+	nullDeviance float64
+	dispersion   float64
+	dof          int
+	iterations   int
+	converged    bool
+	ok           bool
+}
+
+// Fit fits the model to the response y with the n×p design matrix x. The
+// design matrix is used as given, so a column of ones must be included for the
+// model to have an intercept. If weights is not nil, it holds the prior weight
+// of each observation, and if offset is not nil, it holds the offset of the
+// linear predictor of each observation.
+//
+// Fit returns false if the weighted design matrix is rank deficient or the
+// iterations fail to produce a finite deviance. Fit will panic if the lengths
+// of y, weights or offset do not match the number of rows of x, if n is not
+// greater than p, if a weight is negative, if a response is not in the support
+// of the family, or if the family is unknown.
+func (g *GLM) Fit(x mat.Matrix, y, weights, offset []float64) (ok bool) {
+	n, p := x.Dims()
+	if len(y) != n {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != n {
+		panic("stat: slice length mismatch")
+	}
+	if offset != nil && len(offset) != n {
+		panic("stat: slice length mismatch")
+	}
+	if n <= p {
+		panic("stat: too few observations for GLM")
+	}
+	w := weights
+	if w == nil {
+		w = make([]float64, n)
+		for i := range w {
+			w[i] = 1
+		}
+	}
+	for i, v := range y {
+		if w[i] < 0 {
+			panic("stat: negative GLM weight")
+		}
+		if !g.Family.validResponse(v) {
+			panic("stat: response out of range for GLM family")
+		}
+	}
+	o := offset
+	if o == nil {
+		o = make([]float64, n)
+	}
+	maxIter := g.MaxIterations
+	if maxIter == 0 {
+		maxIter = defaultGLMIterations
+	}
+	tol := g.Tolerance
+	if tol == 0 {
+		tol = defaultGLMTolerance
+	}
+
+	g.ok = false
+	xd := mat.DenseCopyOf(x)
+	fit := g.Family.irls(xd, y, w, o, maxIter, tol)
+	if fit == nil {
+		return false
+	}
+
+	// The null model has only an intercept.
+	ones := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		ones.Set(i, 0, 1)
+	}
+	null := g.Family.irls(ones, y, w, o, maxIter, tol)
+	if null == nil {
+		return false
+	}
+
+	var nobs int
+	for _, v := range w {
+		if v > 0 {
+			nobs++
+		}
+	}
+	g.dof = nobs - p
+	g.dispersion = 1
+	if g.Family == GaussianFamily || g.Family == GammaFamily {
+		var pearson float64
+		for i, v := range y {
+			r := v - fit.mu[i]
+			pearson += w[i] * r * r / g.Family.variance(fit.mu[i])
+		}
+		g.dispersion = pearson / float64(g.dof)
+	}
+
+	// The covariance of the coefficients is φ(XᵀWX)⁻¹ for the working
+	// weights W at convergence.
+	if err := fit.chol.InverseTo(&g.cov); err != nil {
+		return false
+	}
+	g.cov.ScaleSym(g.dispersion, &g.cov)
+
+	g.coef = fit.beta
+	g.mu = fit.mu
+	g.deviance = fit.deviance
+	g.nullDeviance = null.deviance
+	g.iterations = fit.iterations
+	g.converged = fit.converged
+	g.ok = true
+	return true
+}
+
+// glmFit is the result of IRLS iterations.
+type glmFit struct {
+	beta       []float64
+	mu         []float64
+	chol       mat.Cholesky
+	deviance   float64
+	iterations int
+	converged  bool
+}
+
+// irls fits the model with design matrix x, response y, prior weights w and
+// offset o by iteratively reweighted least squares, returning nil if the
+// weighted design matrix is rank deficient or the deviance is not finite.
+func (f GLMFamily) irls(x *mat.Dense, y, w, o []float64, maxIter int, tol float64) *glmFit {
+	n, p := x.Dims()
+	fit := &glmFit{
+		beta: make([]float64, p),
+		mu:   make([]float64, n),
+	}
+	eta := make([]float64, n)
+	for i, v := range y {
+		fit.mu[i] = f.initialMean(v, w[i])
+		eta[i] = f.link(fit.mu[i])
+	}
+	fit.deviance = math.Inf(1)
+
+	z := make([]float64, n)
+	sw := make([]float64, n)
+	var sx mat.Dense
+	var xtwz mat.VecDense
+	beta := mat.NewVecDense(p, nil)
+	prev := make([]float64, p)
+	for fit.iterations = 1; fit.iterations <= maxIter; fit.iterations++ {
+		// Solve the weighted least squares problem for the working
+		// response at the current linear predictor.
+		for i := range y {
+			z[i] = eta[i] - o[i] + (y[i]-fit.mu[i])*f.linkDeriv(fit.mu[i])
+		}
+		if !f.information(&fit.chol, &sx, sw, x, fit.mu, w) {
+			return nil
+		}
+		for i, v := range sw {
+			z[i] *= v
+		}
+		xtwz.MulVec(sx.T(), mat.NewVecDense(n, z))
+		if err := fit.chol.SolveVecTo(beta, &xtwz); err != nil {
+			return nil
+		}
+
+		// Halve the step while the deviance is not finite.
+		var dev float64
+		for halving := 0; halving < 30; halving++ {
+			f.predict(fit.mu, eta, x, beta.RawVector().Data, o)
+			dev = f.deviance(y, fit.mu, w)
+			if !math.IsInf(dev, 0) && !math.IsNaN(dev) || fit.iterations == 1 {
+				break
+			}
+			for j, v := range prev {
+				beta.SetVec(j, (beta.AtVec(j)+v)/2)
+			}
+		}
+		if math.IsInf(dev, 0) || math.IsNaN(dev) {
+			return nil
+		}
+		copy(prev, beta.RawVector().Data)
+		if math.Abs(dev-fit.deviance) < tol*(math.Abs(dev)+0.1) {
+			fit.deviance = dev
+			fit.converged = true
+			break
+		}
+		fit.deviance = dev
+	}
+	if fit.iterations > maxIter {
+		fit.iterations = maxIter
+	}
+	copy(fit.beta, prev)
+
+	// Refactorize the information matrix at the final estimate for the
+	// covariance of the coefficients.
+	if !f.information(&fit.chol, &sx, sw, x, fit.mu, w) {
+		return nil
+	}
+	return fit
+}
+
+// information computes the Cholesky factorization of the information matrix
+// XᵀWX for the working weights W at the means mu with prior weights w,
+// returning whether it is positive definite. On return sw holds the square
+// roots of the working weights and sx holds the rows of x scaled by them.
+func (f GLMFamily) information(chol *mat.Cholesky, sx *mat.Dense, sw []float64, x *mat.Dense, mu, w []float64) bool {
+	for i, m := range mu {
+		d := f.linkDeriv(m)
+		sw[i] = math.Sqrt(w[i] / (f.variance(m) * d * d))
+	}
+	sx.CloneFrom(x)
+	for i, v := range sw {
+		floats.Scale(v, sx.RawRowView(i))
+	}
+	var xtwx mat.SymDense
+	xtwx.SymOuterK(1, sx.T())
+	return chol.Factorize(&xtwx)
+}
+
+// predict stores the linear predictor and mean of the model with design
+// matrix x, coefficients beta and offset o into eta and mu.
+func (f GLMFamily) predict(mu, eta []float64, x *mat.Dense, beta, o []float64) {
+	for i := range eta {
+		eta[i] = floats.Dot(x.RawRowView(i), beta) + o[i]
+		mu[i] = f.inverseLink(eta[i])
+	}
+}
+
+// validResponse returns whether y is in the support of the family.
+func (f GLMFamily) validResponse(y float64) bool {
+	switch f {
+	case GaussianFamily:
+		return !math.IsNaN(y) && !math.IsInf(y, 0)
+	case BinomialFamily:
+		return 0 <= y && y <= 1
+	case PoissonFamily:
+		return 0 <= y && !math.IsInf(y, 1)
+	case GammaFamily:
+		return 0 < y && !math.IsInf(y, 1)
+	}
+	panic("stat: unknown GLM family")
+}
+
+// initialMean returns the starting value of the mean for the response y with
+// prior weight w.
+func (f GLMFamily) initialMean(y, w float64) float64 {
+	switch f {
+	case BinomialFamily:
+		return (w*y + 0.5) / (w + 1)
+	case PoissonFamily:
+		return y + 0.1
+	}
+	return y
+}
+
+// link returns the link function at mu.
+func (f GLMFamily) link(mu float64) float64 {
+	switch f {
+	case GaussianFamily:
+		return mu
+	case BinomialFamily:
+		return math.Log(mu / (1 - mu))
+	case PoissonFamily, GammaFamily:
+		return math.Log(mu)
+	}
+	panic("stat: unknown GLM family")
+}
+
+// linkDeriv returns the derivative of the link function at mu.
+func (f GLMFamily) linkDeriv(mu float64) float64 {
+	switch f {
+	case GaussianFamily:
+		return 1
+	case BinomialFamily:
+		return 1 / (mu * (1 - mu))
+	case PoissonFamily, GammaFamily:
+		return 1 / mu
+	}
+	panic("stat: unknown GLM family")
+}
+
+// glmMinProb is the smallest distance from the boundary of the fitted mean
+// of the binomial family.
+const glmMinProb = 1e-10
+
+// inverseLink returns the inverse of the link function at eta.
+func (f GLMFamily) inverseLink(eta float64) float64 {
+	switch f {
+	case GaussianFamily:
+		return eta
+	case BinomialFamily:
+		mu := 1 / (1 + math.Exp(-eta))
+		return math.Max(glmMinProb, math.Min(1-glmMinProb, mu))
+	case PoissonFamily, GammaFamily:
+		return math.Max(math.Exp(eta), math.SmallestNonzeroFloat64)
+	}
+	panic("stat: unknown GLM family")
+}
+
+// variance returns the variance function of the family at mu.
+func (f GLMFamily) variance(mu float64) float64 {
+	switch f {
+	case GaussianFamily:
+		return 1
+	case BinomialFamily:
+		return mu * (1 - mu)
+	case PoissonFamily:
+		return mu
+	case GammaFamily:
+		return mu * mu
+	}
+	panic("stat: unknown GLM family")
+}
+
+// deviance returns the deviance of the fitted means mu for the response y
+// with prior weights w.
+func (f GLMFamily) deviance(y, mu, w []float64) float64 {
+	var dev float64
+	for i, v := range y {
+		m := mu[i]
+		var d float64
+		switch f {
+		case GaussianFamily:
+			d = (v - m) * (v - m)
+		case BinomialFamily:
+			d = 2 * (xlogy(v, v/m) + xlogy(1-v, (1-v)/(1-m)))
+		case PoissonFamily:
+			d = 2 * (xlogy(v, v/m) - (v - m))
+		case GammaFamily:
+			d = 2 * (-math.Log(v/m) + (v-m)/m)
+		}
+		dev += w[i] * d
+	}
+	return dev
+}
+
+// xlogy returns x*log(y), or zero if x is zero.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
+
+// CoefficientsTo returns the estimated coefficients of the model. If dst is
+// not nil it is used to store the coefficients and returned. CoefficientsTo
+// will panic if the receiver does not contain a successful fit or dst is not
+// nil and its length is not the number of columns of the design matrix.
+func (g *GLM) CoefficientsTo(dst []float64) []float64 {
+	return g.copyVec(dst, g.coef)
+}
+
+// StdErrTo returns the standard errors of the estimated coefficients, the
+// square roots of the diagonal of the covariance returned by CovarianceTo,
+// with the same semantics as CoefficientsTo.
+func (g *GLM) StdErrTo(dst []float64) []float64 {
+	dst = g.copyVec(dst, g.coef)
+	for i := range dst {
+		dst[i] = math.Sqrt(g.cov.At(i, i))
+	}
+	return dst
+}
+
+// CovarianceTo stores the estimated covariance matrix of the coefficients,
+// φ(XᵀWX)⁻¹ for the working weights W of the final iteration, into dst. If dst
+// is empty, CovarianceTo will resize dst to be p×p. When dst is non-empty,
+// CovarianceTo will panic if dst is not p×p. CovarianceTo will also panic if
+// the receiver does not contain a successful fit.
+func (g *GLM) CovarianceTo(dst *mat.SymDense) {
+	if !g.ok {
+		panic("stat: use of unfitted GLM")
+	}
+	p := len(g.coef)
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(p)
+	} else if dst.SymmetricDim() != p {
+		panic(mat.ErrShape)
+	}
+	dst.CopySym(&g.cov)
+}
+
+// FittedTo returns the fitted mean of each observation with the same
+// semantics as CoefficientsTo, except that the length of dst must be the
+// number of observations.
+func (g *GLM) FittedTo(dst []float64) []float64 {
+	return g.copyVec(dst, g.mu)
+}
+
+// Predict returns the mean response of the fitted model at the row x of a
+// design matrix with the given offset. Predict will panic if the receiver
+// does not contain a successful fit or len(x) is not the number of
+// coefficients.
+func (g *GLM) Predict(x []float64, offset float64) float64 {
+	if !g.ok {
+		panic("stat: use of unfitted GLM")
+	}
+	if len(x) != len(g.coef) {
+		panic("stat: slice length mismatch")
+	}
+	return g.Family.inverseLink(floats.Dot(x, g.coef) + offset)
+}
+
+// Deviance returns the deviance of the fitted model, the dispersion times
+// twice the difference between the log-likelihoods of the saturated and
+// fitted models. For the Gaussian family it is the weighted residual sum of
+// squares.
+func (g *GLM) Deviance() float64 {
+	g.checkFit()
+	return g.deviance
+}
+
+// NullDeviance returns the deviance of the model with only an intercept and
+// the offset.
+func (g *GLM) NullDeviance() float64 {
+	g.checkFit()
+	return g.nullDeviance
+}
+
+// Dispersion returns the dispersion parameter φ. It is one for the binomial
+// and Poisson families, and is estimated by the Pearson χ² statistic divided
+// by the residual degrees of freedom otherwise.
+func (g *GLM) Dispersion() float64 {
+	g.checkFit()
+	return g.dispersion
+}
+
+// ResidualDoF returns the residual degrees of freedom of the fit, the number
+// of observations with positive weight less the number of coefficients.
+func (g *GLM) ResidualDoF() int {
+	g.checkFit()
+	return g.dof
+}
+
+// Iterations returns the number of IRLS iterations of the fit.
+func (g *GLM) Iterations() int {
+	g.checkFit()
+	return g.iterations
+}
+
+// Converged returns whether the IRLS iterations of the fit converged within
+// MaxIterations.
+func (g *GLM) Converged() bool {
+	g.checkFit()
+	return g.converged
+}
+
+func (g *GLM) checkFit() {
+	if !g.ok {
+		panic("stat: use of unfitted GLM")
+	}
+}
+
+func (g *GLM) copyVec(dst, src []float64) []float64 {
+	g.checkFit()
+	if dst == nil {
+		dst = make([]float64, len(src))
+	}
+	if len(dst) != len(src) {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, src)
+	return dst
+}
@@ -0,0 +1,287 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGLMDobson(t *testing.T) {
+	t.Parallel()
+	// Randomized controlled trial from Dobson, A. J. "An Introduction to
+	// Generalized Linear Models." (1990), p. 93, with treatment contrast
+	// coding of the outcome and treatment factors.
+	counts := []float64{18, 17, 15, 20, 10, 20, 25, 13, 12}
+	x := mat.NewDense(9, 5, nil)
+	for i := 0; i < 9; i++ {
+		x.Set(i, 0, 1)
+		if outcome := i % 3; outcome > 0 {
+			x.Set(i, outcome, 1)
+		}
+		if treatment := i / 3; treatment > 0 {
+			x.Set(i, 2+treatment, 1)
+		}
+	}
+	g := GLM{Family: PoissonFamily}
+	if !g.Fit(x, counts, nil, nil) {
+		t.Fatalf("unexpected failure to fit")
+	}
+	if !g.Converged() {
+		t.Errorf("fit did not converge")
+	}
+	coef := g.CoefficientsTo(nil)
+	wantCoef := []float64{3.044522, -0.4542553, -0.2929871, 0, 0}
+	if !floats.EqualApprox(coef, wantCoef, 1e-6) {
+		t.Errorf("unexpected coefficients: got %v, want %v", coef, wantCoef)
+	}
+	se := g.StdErrTo(nil)
+	wantSE := []float64{0.1708987, 0.2021708, 0.1927423, 0.2, 0.2}
+	if !floats.EqualApprox(se, wantSE, 1e-6) {
+		t.Errorf("unexpected standard errors: got %v, want %v", se, wantSE)
+	}
+	if got, want := g.Deviance(), 5.129141; math.Abs(got-want) > 1e-6 {
+		t.Errorf("unexpected deviance: got %v, want %v", got, want)
+	}
+	if got, want := g.NullDeviance(), 10.58145; math.Abs(got-want) > 1e-5 {
+		t.Errorf("unexpected null deviance: got %v, want %v", got, want)
+	}
+	if g.ResidualDoF() != 4 {
+		t.Errorf("unexpected residual degrees of freedom: got %d, want 4", g.ResidualDoF())
+	}
+	if g.Dispersion() != 1 {
+		t.Errorf("unexpected dispersion: got %v, want 1", g.Dispersion())
+	}
+	fitted := g.FittedTo(nil)
+	if got := g.Predict(x.RawRowView(4), 0); !scalar.EqualWithinAbsOrRel(got, fitted[4], 1e-12, 1e-12) {
+		t.Errorf("prediction does not match fitted value: got %v, want %v", got, fitted[4])
+	}
+}
+
+func TestGLMGaussian(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+	x := mat.NewDense(n, 3, nil)
+	y := make([]float64, n)
+	w := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x.Set(i, 0, 1)
+		x.Set(i, 1, rnd.NormFloat64())
+		x.Set(i, 2, rnd.NormFloat64())
+		y[i] = 1 + 2*x.At(i, 1) - x.At(i, 2) + rnd.NormFloat64()
+		w[i] = 1 + rnd.Float64()
+	}
+	g := GLM{Family: GaussianFamily}
+	if !g.Fit(x, y, w, nil) {
+		t.Fatalf("unexpected failure to fit")
+	}
+
+	// The Gaussian family is weighted least squares.
+	sw := mat.NewDiagDense(n, nil)
+	for i, v := range w {
+		sw.SetDiag(i, v)
+	}
+	var xtw, xtwx mat.Dense
+	xtw.Mul(x.T(), sw)
+	xtwx.Mul(&xtw, x)
+	var xtwy, beta mat.VecDense
+	xtwy.MulVec(&xtw, mat.NewVecDense(n, y))
+	if err := beta.SolveVec(&xtwx, &xtwy); err != nil {
+		t.Fatalf("bad test: %v", err)
+	}
+	if got := g.CoefficientsTo(nil); !floats.EqualApprox(got, beta.RawVector().Data, 1e-10) {
+		t.Errorf("unexpected coefficients: got %v, want %v", got, beta.RawVector().Data)
+	}
+	var rss float64
+	for i, v := range y {
+		r := v - floats.Dot(x.RawRowView(i), beta.RawVector().Data)
+		rss += w[i] * r * r
+	}
+	if got := g.Deviance(); !scalar.EqualWithinAbsOrRel(got, rss, 1e-10, 1e-10) {
+		t.Errorf("unexpected deviance: got %v, want %v", got, rss)
+	}
+	sigma2 := rss / (n - 3)
+	if got := g.Dispersion(); !scalar.EqualWithinAbsOrRel(got, sigma2, 1e-10, 1e-10) {
+		t.Errorf("unexpected dispersion: got %v, want %v", got, sigma2)
+	}
+	var inv mat.Dense
+	if err := inv.Inverse(&xtwx); err != nil {
+		t.Fatalf("bad test: %v", err)
+	}
+	inv.Scale(sigma2, &inv)
+	var cov mat.SymDense
+	g.CovarianceTo(&cov)
+	if !mat.EqualApprox(&cov, &inv, 1e-10) {
+		t.Errorf("unexpected covariance:\ngot:\n%v\nwant:\n%v", mat.Formatted(&cov), mat.Formatted(&inv))
+	}
+}
+
+func TestGLMScoreEquations(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		family GLMFamily
+		beta   []float64
+		draw   func(rnd *rand.Rand, mu float64) float64
+	}{
+		{
+			name:   "logistic",
+			family: BinomialFamily,
+			beta:   []float64{-0.5, 1.5, -1},
+			draw: func(rnd *rand.Rand, mu float64) float64 {
+				if rnd.Float64() < mu {
+					return 1
+				}
+				return 0
+			},
+		},
+		{
+			name:   "poisson",
+			family: PoissonFamily,
+			beta:   []float64{0.5, 0.3, -0.4},
+			draw: func(rnd *rand.Rand, mu float64) float64 {
+				// Knuth's multiplication method.
+				l := math.Exp(-mu)
+				k := 0.0
+				for p := rnd.Float64(); p > l; p *= rnd.Float64() {
+					k++
+				}
+				return k
+			},
+		},
+		{
+			name:   "gamma",
+			family: GammaFamily,
+			beta:   []float64{1, 0.5, 0.2},
+			draw: func(rnd *rand.Rand, mu float64) float64 {
+				// Gamma with shape 2 is a sum of two exponentials.
+				return mu * (rnd.ExpFloat64() + rnd.ExpFloat64()) / 2
+			},
+		},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		const n = 2000
+		x := mat.NewDense(n, 3, nil)
+		y := make([]float64, n)
+		offset := make([]float64, n)
+		for i := 0; i < n; i++ {
+			x.Set(i, 0, 1)
+			x.Set(i, 1, rnd.NormFloat64())
+			x.Set(i, 2, rnd.Float64())
+			if test.family == PoissonFamily {
+				offset[i] = math.Log(1 + rnd.Float64())
+			}
+			mu := test.family.inverseLink(floats.Dot(x.RawRowView(i), test.beta) + offset[i])
+			y[i] = test.draw(rnd, mu)
+		}
+		g := GLM{Family: test.family, Tolerance: 1e-14}
+		if !g.Fit(x, y, nil, offset) {
+			t.Fatalf("%s: unexpected failure to fit", test.name)
+		}
+
+		// The score equations Σ (y-μ)/(V(μ)g'(μ)) x = 0 hold at the
+		// maximum likelihood estimate.
+		mu := g.FittedTo(nil)
+		score := make([]float64, 3)
+		for i, v := range y {
+			floats.AddScaled(score, (v-mu[i])/(test.family.variance(mu[i])*test.family.linkDeriv(mu[i])), x.RawRowView(i))
+		}
+		if floats.Norm(score, math.Inf(1)) > 1e-6 {
+			t.Errorf("%s: score equations not satisfied: %v", test.name, score)
+		}
+
+		coef := g.CoefficientsTo(nil)
+		se := g.StdErrTo(nil)
+		for j, b := range test.beta {
+			if math.Abs(coef[j]-b) > 4*se[j] {
+				t.Errorf("%s: coefficient %d too far from truth: got %v±%v, want %v", test.name, j, coef[j], se[j], b)
+			}
+		}
+		if g.Deviance() >= g.NullDeviance() {
+			t.Errorf("%s: deviance not less than null deviance: %v >= %v", test.name, g.Deviance(), g.NullDeviance())
+		}
+		if test.family == GammaFamily && math.Abs(g.Dispersion()-0.5) > 0.05 {
+			t.Errorf("%s: unexpected dispersion: got %v, want 0.5", test.name, g.Dispersion())
+		}
+	}
+}
+
+func TestGLMWeightsOffset(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(6, 2, []float64{
+		1, 0.5,
+		1, 1.5,
+		1, 2.5,
+		1, 3,
+		1, 4,
+		1, 5.5,
+	})
+	y := []float64{0, 0, 1, 0, 1, 1}
+	w := []float64{1, 3, 2, 1, 2, 1}
+
+	// Integer weights are equivalent to repeated observations.
+	var rows, ys []float64
+	for i, v := range w {
+		for k := 0; k < int(v); k++ {
+			rows = append(rows, x.RawRowView(i)...)
+			ys = append(ys, y[i])
+		}
+	}
+	var weighted, repeated GLM
+	weighted.Family = BinomialFamily
+	repeated.Family = BinomialFamily
+	if !weighted.Fit(x, y, w, nil) || !repeated.Fit(mat.NewDense(len(ys), 2, rows), ys, nil, nil) {
+		t.Fatalf("unexpected failure to fit")
+	}
+	if !floats.EqualApprox(weighted.CoefficientsTo(nil), repeated.CoefficientsTo(nil), 1e-8) {
+		t.Errorf("weighted fit differs from repeated observations: %v != %v",
+			weighted.CoefficientsTo(nil), repeated.CoefficientsTo(nil))
+	}
+	if !floats.EqualApprox(weighted.StdErrTo(nil), repeated.StdErrTo(nil), 1e-8) {
+		t.Errorf("weighted standard errors differ from repeated observations")
+	}
+
+	// A constant offset shifts the intercept.
+	offset := []float64{2, 2, 2, 2, 2, 2}
+	var shifted GLM
+	shifted.Family = BinomialFamily
+	if !shifted.Fit(x, y, w, offset) {
+		t.Fatalf("unexpected failure to fit with offset")
+	}
+	want := weighted.CoefficientsTo(nil)
+	want[0] -= 2
+	if got := shifted.CoefficientsTo(nil); !floats.EqualApprox(got, want, 1e-8) {
+		t.Errorf("unexpected coefficients with offset: got %v, want %v", got, want)
+	}
+	if !scalar.EqualWithinAbsOrRel(shifted.NullDeviance(), weighted.NullDeviance(), 1e-8, 1e-8) {
+		t.Errorf("null deviance changed by constant offset: %v != %v", shifted.NullDeviance(), weighted.NullDeviance())
+	}
+}
+
+func TestGLMPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{1, 0, 1, 1, 1, 2, 1, 3})
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "binomial response", fn: func() { (&GLM{Family: BinomialFamily}).Fit(x, []float64{0, 1, 2, 1}, nil, nil) }},
+		{name: "poisson response", fn: func() { (&GLM{Family: PoissonFamily}).Fit(x, []float64{0, 1, -1, 1}, nil, nil) }},
+		{name: "gamma response", fn: func() { (&GLM{Family: GammaFamily}).Fit(x, []float64{0, 1, 1, 1}, nil, nil) }},
+		{name: "weights length", fn: func() { (&GLM{}).Fit(x, []float64{0, 1, 1, 1}, []float64{1}, nil) }},
+		{name: "unfitted", fn: func() { (&GLM{}).Deviance() }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gp provides Gaussian process regression with composable covariance
+// kernels and maximum marginal likelihood estimation of hyperparameters.
+//
+// For an introduction to Gaussian processes, see
+//  Rasmussen, C. E., and C. K. I. Williams. "Gaussian Processes for Machine
+//  Learning." MIT Press (2006).
+package gp // import "gonum.org/v1/gonum/stat/gp"
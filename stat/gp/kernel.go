@@ -0,0 +1,384 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// Kernel is a covariance function of a Gaussian process. The hyperparameters
+// of a Kernel are exposed on a logarithmic scale so that they can be
+// optimized without constraints.
+type Kernel interface {
+	// Cov returns the covariance between the function values at x and y.
+	Cov(x, y []float64) float64
+
+	// CovGrad stores into grad the derivative of the covariance between
+	// the function values at x and y with respect to each of the log
+	// hyperparameters, and returns the covariance. The length of grad
+	// must be NumHyper.
+	CovGrad(grad, x, y []float64) float64
+
+	// NumHyper returns the number of hyperparameters of the kernel.
+	NumHyper() int
+
+	// Hyper stores the log hyperparameters of the kernel into dst and
+	// returns it. If dst is nil a new slice is allocated, otherwise the
+	// length of dst must be NumHyper.
+	Hyper(dst []float64) []float64
+
+	// SetHyper sets the hyperparameters of the kernel from their
+	// logarithms. The length of theta must be NumHyper.
+	SetHyper(theta []float64)
+}
+
+const badHyperLength = "gp: hyperparameter length mismatch"
+
+// useHyper returns dst, allocating a slice of length n if dst is nil.
+func useHyper(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic(badHyperLength)
+	}
+	return dst
+}
+
+// RBF is the squared exponential kernel
+//  k(x, y) = σ² exp(-|x-y|²/(2ℓ²))
+// with variance σ² and length scale ℓ. Functions drawn from a Gaussian process
+// with an RBF kernel are infinitely differentiable.
+//
+// The hyperparameters are log σ² and log ℓ.
+type RBF struct {
+	Variance    float64
+	LengthScale float64
+}
+
+// Cov returns the covariance between the function values at x and y.
+func (k *RBF) Cov(x, y []float64) float64 {
+	r2 := sqDist(x, y)
+	return k.Variance * math.Exp(-r2/(2*k.LengthScale*k.LengthScale))
+}
+
+// CovGrad stores the derivative of the covariance with respect to the log
+// hyperparameters into grad and returns the covariance.
+func (k *RBF) CovGrad(grad, x, y []float64) float64 {
+	if len(grad) != 2 {
+		panic(badHyperLength)
+	}
+	s2 := sqDist(x, y) / (k.LengthScale * k.LengthScale)
+	c := k.Variance * math.Exp(-s2/2)
+	grad[0] = c
+	grad[1] = c * s2
+	return c
+}
+
+// NumHyper returns 2.
+func (*RBF) NumHyper() int {
+	return 2
+}
+
+// Hyper returns the log variance and log length scale of the kernel.
+func (k *RBF) Hyper(dst []float64) []float64 {
+	dst = useHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+	return dst
+}
+
+// SetHyper sets the variance and length scale of the kernel from their
+// logarithms.
+func (k *RBF) SetHyper(theta []float64) {
+	if len(theta) != 2 {
+		panic(badHyperLength)
+	}
+	k.Variance = math.Exp(theta[0])
+	k.LengthScale = math.Exp(theta[1])
+}
+
+// Matern is the Matérn kernel with smoothness ν of 1/2, 3/2 or 5/2,
+//  ν = 1/2: k(x, y) = σ² exp(-s)
+//  ν = 3/2: k(x, y) = σ² (1 + √3 s) exp(-√3 s)
+//  ν = 5/2: k(x, y) = σ² (1 + √5 s + 5/3 s²) exp(-√5 s)
+// where s = |x-y|/ℓ, with variance σ² and length scale ℓ. Functions drawn
+// from a Gaussian process with a Matérn kernel are ⌈ν⌉-1 times
+// differentiable.
+//
+// The hyperparameters are log σ² and log ℓ. The smoothness is fixed, and
+// methods of Matern panic if Nu is not one of the supported values.
+type Matern struct {
+	Variance    float64
+	LengthScale float64
+	Nu          float64
+}
+
+// Cov returns the covariance between the function values at x and y.
+func (k *Matern) Cov(x, y []float64) float64 {
+	c, _ := k.covAndDeriv(math.Sqrt(sqDist(x, y)) / k.LengthScale)
+	return c
+}
+
+// CovGrad stores the derivative of the covariance with respect to the log
+// hyperparameters into grad and returns the covariance.
+func (k *Matern) CovGrad(grad, x, y []float64) float64 {
+	if len(grad) != 2 {
+		panic(badHyperLength)
+	}
+	c, d := k.covAndDeriv(math.Sqrt(sqDist(x, y)) / k.LengthScale)
+	grad[0] = c
+	grad[1] = d
+	return c
+}
+
+// covAndDeriv returns the covariance at the scaled distance s and its
+// derivative with respect to the log length scale.
+func (k *Matern) covAndDeriv(s float64) (c, d float64) {
+	switch k.Nu {
+	case 0.5:
+		e := k.Variance * math.Exp(-s)
+		return e, e * s
+	case 1.5:
+		e := k.Variance * math.Exp(-math.Sqrt(3)*s)
+		return (1 + math.Sqrt(3)*s) * e, 3 * s * s * e
+	case 2.5:
+		e := k.Variance * math.Exp(-math.Sqrt(5)*s)
+		return (1 + math.Sqrt(5)*s + 5*s*s/3) * e, 5 * s * s / 3 * (1 + math.Sqrt(5)*s) * e
+	}
+	panic("gp: unsupported Matérn smoothness")
+}
+
+// NumHyper returns 2.
+func (*Matern) NumHyper() int {
+	return 2
+}
+
+// Hyper returns the log variance and log length scale of the kernel.
+func (k *Matern) Hyper(dst []float64) []float64 {
+	dst = useHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+	return dst
+}
+
+// SetHyper sets the variance and length scale of the kernel from their
+// logarithms.
+func (k *Matern) SetHyper(theta []float64) {
+	if len(theta) != 2 {
+		panic(badHyperLength)
+	}
+	k.Variance = math.Exp(theta[0])
+	k.LengthScale = math.Exp(theta[1])
+}
+
+// Periodic is the periodic kernel
+//  k(x, y) = σ² exp(-2 sin²(π|x-y|/p)/ℓ²)
+// with variance σ², length scale ℓ and period p.
+//
+// The hyperparameters are log σ², log ℓ and log p.
+type Periodic struct {
+	Variance    float64
+	LengthScale float64
+	Period      float64
+}
+
+// Cov returns the covariance between the function values at x and y.
+func (k *Periodic) Cov(x, y []float64) float64 {
+	s := math.Sin(math.Pi * math.Sqrt(sqDist(x, y)) / k.Period)
+	return k.Variance * math.Exp(-2*s*s/(k.LengthScale*k.LengthScale))
+}
+
+// CovGrad stores the derivative of the covariance with respect to the log
+// hyperparameters into grad and returns the covariance.
+func (k *Periodic) CovGrad(grad, x, y []float64) float64 {
+	if len(grad) != 3 {
+		panic(badHyperLength)
+	}
+	a := math.Pi * math.Sqrt(sqDist(x, y)) / k.Period
+	s := math.Sin(a)
+	l2 := k.LengthScale * k.LengthScale
+	c := k.Variance * math.Exp(-2*s*s/l2)
+	grad[0] = c
+	grad[1] = c * 4 * s * s / l2
+	grad[2] = c * 2 * a * math.Sin(2*a) / l2
+	return c
+}
+
+// NumHyper returns 3.
+func (*Periodic) NumHyper() int {
+	return 3
+}
+
+// Hyper returns the log variance, log length scale and log period of the
+// kernel.
+func (k *Periodic) Hyper(dst []float64) []float64 {
+	dst = useHyper(dst, 3)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+	dst[2] = math.Log(k.Period)
+	return dst
+}
+
+// SetHyper sets the variance, length scale and period of the kernel from
+// their logarithms.
+func (k *Periodic) SetHyper(theta []float64) {
+	if len(theta) != 3 {
+		panic(badHyperLength)
+	}
+	k.Variance = math.Exp(theta[0])
+	k.LengthScale = math.Exp(theta[1])
+	k.Period = math.Exp(theta[2])
+}
+
+// Sum is the sum of kernels. Its hyperparameters are the concatenation of
+// the hyperparameters of its terms.
+type Sum []Kernel
+
+// Cov returns the covariance between the function values at x and y.
+func (s Sum) Cov(x, y []float64) float64 {
+	var c float64
+	for _, k := range s {
+		c += k.Cov(x, y)
+	}
+	return c
+}
+
+// CovGrad stores the derivative of the covariance with respect to the log
+// hyperparameters into grad and returns the covariance.
+func (s Sum) CovGrad(grad, x, y []float64) float64 {
+	if len(grad) != s.NumHyper() {
+		panic(badHyperLength)
+	}
+	var c float64
+	for _, k := range s {
+		n := k.NumHyper()
+		c += k.CovGrad(grad[:n], x, y)
+		grad = grad[n:]
+	}
+	return c
+}
+
+// NumHyper returns the total number of hyperparameters of the terms.
+func (s Sum) NumHyper() int {
+	return numHyper(s)
+}
+
+// Hyper returns the log hyperparameters of the terms.
+func (s Sum) Hyper(dst []float64) []float64 {
+	return hyper(dst, s)
+}
+
+// SetHyper sets the hyperparameters of the terms from their logarithms.
+func (s Sum) SetHyper(theta []float64) {
+	setHyper(s, theta)
+}
+
+// Product is the product of kernels. Its hyperparameters are the
+// concatenation of the hyperparameters of its factors.
+type Product []Kernel
+
+// Cov returns the covariance between the function values at x and y.
+func (p Product) Cov(x, y []float64) float64 {
+	c := 1.0
+	for _, k := range p {
+		c *= k.Cov(x, y)
+	}
+	return c
+}
+
+// CovGrad stores the derivative of the covariance with respect to the log
+// hyperparameters into grad and returns the covariance.
+func (p Product) CovGrad(grad, x, y []float64) float64 {
+	if len(grad) != p.NumHyper() {
+		panic(badHyperLength)
+	}
+	covs := make([]float64, len(p))
+	g := grad
+	for i, k := range p {
+		n := k.NumHyper()
+		covs[i] = k.CovGrad(g[:n], x, y)
+		g = g[n:]
+	}
+	g = grad
+	for i, k := range p {
+		n := k.NumHyper()
+		// The product of the covariances of the other factors is
+		// computed directly to allow zero covariances.
+		other := 1.0
+		for j, c := range covs {
+			if j != i {
+				other *= c
+			}
+		}
+		floats.Scale(other, g[:n])
+		g = g[n:]
+	}
+	c := 1.0
+	for _, v := range covs {
+		c *= v
+	}
+	return c
+}
+
+// NumHyper returns the total number of hyperparameters of the factors.
+func (p Product) NumHyper() int {
+	return numHyper(p)
+}
+
+// Hyper returns the log hyperparameters of the factors.
+func (p Product) Hyper(dst []float64) []float64 {
+	return hyper(dst, p)
+}
+
+// SetHyper sets the hyperparameters of the factors from their logarithms.
+func (p Product) SetHyper(theta []float64) {
+	setHyper(p, theta)
+}
+
+func numHyper(kernels []Kernel) int {
+	var n int
+	for _, k := range kernels {
+		n += k.NumHyper()
+	}
+	return n
+}
+
+func hyper(dst []float64, kernels []Kernel) []float64 {
+	dst = useHyper(dst, numHyper(kernels))
+	d := dst
+	for _, k := range kernels {
+		n := k.NumHyper()
+		k.Hyper(d[:n])
+		d = d[n:]
+	}
+	return dst
+}
+
+func setHyper(kernels []Kernel, theta []float64) {
+	if len(theta) != numHyper(kernels) {
+		panic(badHyperLength)
+	}
+	for _, k := range kernels {
+		n := k.NumHyper()
+		k.SetHyper(theta[:n])
+		theta = theta[n:]
+	}
+}
+
+// sqDist returns the squared Euclidean distance between x and y.
+func sqDist(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("gp: dimension mismatch")
+	}
+	var d float64
+	for i, v := range x {
+		e := v - y[i]
+		d += e * e
+	}
+	return d
+}
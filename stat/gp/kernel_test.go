@@ -0,0 +1,109 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestKernelCov(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2}
+	y := []float64{4, 6}
+	const r = 5.0
+	for _, test := range []struct {
+		name   string
+		kernel Kernel
+		want   float64
+	}{
+		{name: "rbf", kernel: &RBF{Variance: 2, LengthScale: 5}, want: 2 * math.Exp(-0.5)},
+		{name: "matern12", kernel: &Matern{Variance: 2, LengthScale: 5, Nu: 0.5}, want: 2 * math.Exp(-1)},
+		{name: "matern32", kernel: &Matern{Variance: 2, LengthScale: 5, Nu: 1.5}, want: 2 * (1 + math.Sqrt(3)) * math.Exp(-math.Sqrt(3))},
+		{name: "matern52", kernel: &Matern{Variance: 2, LengthScale: 5, Nu: 2.5}, want: 2 * (1 + math.Sqrt(5) + 5.0/3) * math.Exp(-math.Sqrt(5))},
+		{name: "periodic", kernel: &Periodic{Variance: 2, LengthScale: 1, Period: 4 * r}, want: 2 * math.Exp(-1)},
+		{
+			name:   "sum",
+			kernel: Sum{&RBF{Variance: 2, LengthScale: 5}, &Matern{Variance: 1, LengthScale: 5, Nu: 0.5}},
+			want:   2*math.Exp(-0.5) + math.Exp(-1),
+		},
+		{
+			name:   "product",
+			kernel: Product{&RBF{Variance: 2, LengthScale: 5}, &Matern{Variance: 3, LengthScale: 5, Nu: 0.5}},
+			want:   6 * math.Exp(-1.5),
+		},
+	} {
+		if got := test.kernel.Cov(x, y); !scalar.EqualWithinAbsOrRel(got, test.want, 1e-14, 1e-14) {
+			t.Errorf("%s: unexpected covariance: got %v, want %v", test.name, got, test.want)
+		}
+		if got := test.kernel.Cov(y, x); !scalar.EqualWithinAbsOrRel(got, test.want, 1e-14, 1e-14) {
+			t.Errorf("%s: covariance not symmetric: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestKernelCovGrad(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		kernel Kernel
+	}{
+		{name: "rbf", kernel: &RBF{Variance: 2, LengthScale: 1.5}},
+		{name: "matern12", kernel: &Matern{Variance: 2, LengthScale: 1.5, Nu: 0.5}},
+		{name: "matern32", kernel: &Matern{Variance: 2, LengthScale: 1.5, Nu: 1.5}},
+		{name: "matern52", kernel: &Matern{Variance: 2, LengthScale: 1.5, Nu: 2.5}},
+		{name: "periodic", kernel: &Periodic{Variance: 2, LengthScale: 1.5, Period: 3}},
+		{
+			name: "sum",
+			kernel: Sum{
+				&RBF{Variance: 2, LengthScale: 1.5},
+				&Periodic{Variance: 0.5, LengthScale: 0.8, Period: 2},
+			},
+		},
+		{
+			name: "product",
+			kernel: Product{
+				&RBF{Variance: 2, LengthScale: 1.5},
+				Sum{
+					&Periodic{Variance: 0.5, LengthScale: 0.8, Period: 2},
+					&Matern{Variance: 1, LengthScale: 3, Nu: 1.5},
+				},
+			},
+		},
+	} {
+		k := test.kernel
+		theta := k.Hyper(nil)
+		if len(theta) != k.NumHyper() {
+			t.Fatalf("%s: unexpected hyperparameter length", test.name)
+		}
+		for _, pair := range [][2][]float64{
+			{{0, 0}, {0, 0}},
+			{{0.3, -0.2}, {1, 0.5}},
+			{{-1, 2}, {1.5, -0.5}},
+		} {
+			x, y := pair[0], pair[1]
+			grad := make([]float64, k.NumHyper())
+			c := k.CovGrad(grad, x, y)
+			if want := k.Cov(x, y); !scalar.EqualWithinAbsOrRel(c, want, 1e-14, 1e-14) {
+				t.Errorf("%s: CovGrad covariance mismatch: got %v, want %v", test.name, c, want)
+			}
+			want := fd.Gradient(nil, func(h []float64) float64 {
+				k.SetHyper(h)
+				return k.Cov(x, y)
+			}, theta, nil)
+			k.SetHyper(theta)
+			if !floats.EqualApprox(grad, want, 1e-6) {
+				t.Errorf("%s: gradient mismatch at %v, %v: got %v, want %v", test.name, x, y, grad, want)
+			}
+		}
+		if got := k.Hyper(nil); !floats.EqualApprox(got, theta, 1e-14) {
+			t.Errorf("%s: hyperparameter round trip mismatch: got %v, want %v", test.name, got, theta)
+		}
+	}
+}
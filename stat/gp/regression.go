@@ -0,0 +1,273 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Regression is a Gaussian process regression model conditioned on a set of
+// training observations. The latent function has zero prior mean and
+// covariance given by a Kernel, and observations of it are corrupted by
+// independent Gaussian noise. Inference is exact, using the Cholesky
+// factorization of the covariance of the observations.
+type Regression struct {
+	kernel Kernel
+	noise  float64
+
+	x *mat.Dense
+	y *mat.VecDense
+
+	chol  mat.Cholesky
+	alpha mat.VecDense
+}
+
+// NewRegression returns a Gaussian process regression model with the given
+// kernel and observation noise variance, conditioned on the observations y at
+// the locations given by the rows of x. Since the prior mean is zero, y should
+// usually be centered. The kernel is retained and modified by Optimize.
+//
+// If the covariance matrix of the observations is not positive definite,
+// NewRegression returns nil and false; a small positive noise variance
+// ensures that it is. NewRegression panics if the number of rows of x does not
+// equal len(y), or if noise is negative.
+func NewRegression(kernel Kernel, noise float64, x mat.Matrix, y []float64) (r *Regression, ok bool) {
+	n, _ := x.Dims()
+	if n != len(y) {
+		panic("gp: observation length mismatch")
+	}
+	if !(noise >= 0) {
+		panic("gp: negative noise variance")
+	}
+	r = &Regression{
+		kernel: kernel,
+		noise:  noise,
+		x:      mat.DenseCopyOf(x),
+		y:      mat.NewVecDense(n, append([]float64(nil), y...)),
+	}
+	if !r.factorize() {
+		return nil, false
+	}
+	return r, true
+}
+
+// Kernel returns the kernel of the model.
+func (r *Regression) Kernel() Kernel {
+	return r.kernel
+}
+
+// Noise returns the observation noise variance of the model.
+func (r *Regression) Noise() float64 {
+	return r.noise
+}
+
+// factorize computes the Cholesky factorization of the covariance of the
+// observations and the weights α = K⁻¹y, returning whether the covariance is
+// positive definite.
+func (r *Regression) factorize() bool {
+	n := r.y.Len()
+	k := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		xi := r.x.RawRowView(i)
+		for j := i; j < n; j++ {
+			k.SetSym(i, j, r.kernel.Cov(xi, r.x.RawRowView(j)))
+		}
+		k.SetSym(i, i, k.At(i, i)+r.noise)
+	}
+	if !r.chol.Factorize(k) {
+		return false
+	}
+	return r.chol.SolveVecTo(&r.alpha, r.y) == nil
+}
+
+// LogMarginalLikelihood returns the log of the marginal likelihood of the
+// observations,
+//  log p(y) = -1/2 yᵀK⁻¹y - 1/2 log|K| - n/2 log 2π
+// where K is the covariance of the observations.
+func (r *Regression) LogMarginalLikelihood() float64 {
+	n := float64(r.y.Len())
+	return -0.5*mat.Dot(r.y, &r.alpha) - 0.5*r.chol.LogDet() - 0.5*n*math.Log(2*math.Pi)
+}
+
+// logMarginalLikelihoodGrad stores into grad the derivative of the log
+// marginal likelihood with respect to the log hyperparameters of the kernel
+// followed, if len(grad) is one more than their number, by the derivative
+// with respect to the log noise variance. The derivative is
+//  ∂log p(y)/∂θ = 1/2 tr((ααᵀ - K⁻¹) ∂K/∂θ)
+func (r *Regression) logMarginalLikelihoodGrad(grad []float64) {
+	n := r.y.Len()
+	var w mat.SymDense
+	if err := r.chol.InverseTo(&w); err != nil {
+		panic(err)
+	}
+	w.SymRankOne(&w, -1, &r.alpha)
+
+	nk := r.kernel.NumHyper()
+	for i := range grad {
+		grad[i] = 0
+	}
+	dk := make([]float64, nk)
+	for i := 0; i < n; i++ {
+		xi := r.x.RawRowView(i)
+		for j := i; j < n; j++ {
+			r.kernel.CovGrad(dk, xi, r.x.RawRowView(j))
+			f := -w.At(i, j)
+			if i != j {
+				f *= 2
+			}
+			floats.AddScaled(grad[:nk], 0.5*f, dk)
+		}
+	}
+	if len(grad) > nk {
+		grad[nk] = -0.5 * r.noise * mat.Trace(&w)
+	}
+}
+
+// Predict returns the predictive mean and variance of the latent function at
+// x. The predictive variance of a new observation at x is the returned
+// variance plus the noise variance.
+func (r *Regression) Predict(x []float64) (mean, variance float64) {
+	n := r.y.Len()
+	ks := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		ks.SetVec(i, r.kernel.Cov(r.x.RawRowView(i), x))
+	}
+	mean = mat.Dot(ks, &r.alpha)
+
+	var l mat.TriDense
+	r.chol.LTo(&l)
+	var v mat.VecDense
+	if err := v.SolveVec(&l, ks); err != nil {
+		panic(err)
+	}
+	variance = math.Max(0, r.kernel.Cov(x, x)-mat.Dot(&v, &v))
+	return mean, variance
+}
+
+// PredictTo returns the predictive mean of the latent function at the rows of
+// x, stored into mean. If mean is nil a new slice is allocated, otherwise its
+// length must equal the number of rows of x. If cov is not nil, the predictive
+// covariance of the latent function is stored into it. If cov is empty it is
+// resized to the number of rows of x, otherwise PredictTo panics if its
+// dimension does not match the number of rows of x.
+func (r *Regression) PredictTo(mean []float64, cov *mat.SymDense, x mat.Matrix) []float64 {
+	m, _ := x.Dims()
+	if mean == nil {
+		mean = make([]float64, m)
+	}
+	if len(mean) != m {
+		panic("gp: prediction length mismatch")
+	}
+	xs := mat.DenseCopyOf(x)
+	n := r.y.Len()
+	ks := mat.NewDense(n, m, nil)
+	for i := 0; i < n; i++ {
+		xi := r.x.RawRowView(i)
+		for j := 0; j < m; j++ {
+			ks.Set(i, j, r.kernel.Cov(xi, xs.RawRowView(j)))
+		}
+	}
+	mat.NewVecDense(m, mean).MulVec(ks.T(), &r.alpha)
+	if cov == nil {
+		return mean
+	}
+
+	if cov.IsEmpty() {
+		cov.ReuseAsSym(m)
+	} else if cov.SymmetricDim() != m {
+		panic(mat.ErrShape)
+	}
+	var l mat.TriDense
+	r.chol.LTo(&l)
+	var v mat.Dense
+	if err := v.Solve(&l, ks); err != nil {
+		panic(err)
+	}
+	// The predictive covariance is K(x, x) - vᵀv with v = L⁻¹K(X, x).
+	cov.SymOuterK(1, v.T())
+	for i := 0; i < m; i++ {
+		xi := xs.RawRowView(i)
+		for j := i; j < m; j++ {
+			cov.SetSym(i, j, r.kernel.Cov(xi, xs.RawRowView(j))-cov.At(i, j))
+		}
+	}
+	return mean
+}
+
+// Optimize sets the hyperparameters of the kernel and, unless fixNoise is
+// true, the noise variance to maximize the log marginal likelihood of the
+// observations, and conditions the model on the observations with the new
+// parameters. The optimization is over the logarithms of the parameters
+// starting from their current values, using the analytic gradient of the log
+// marginal likelihood. The settings and method are passed to
+// optimize.Minimize. If settings is nil, the optimization stops when the norm
+// of the gradient of the negative log marginal likelihood per observation is
+// below 1e-8, and a nil method uses the default of optimize.Minimize.
+//
+// The noise variance must be positive unless fixNoise is true.
+//
+// Optimize returns the result of the minimization of the negative log marginal
+// likelihood. If the optimization fails to find a parameter with positive
+// definite covariance, the model is left unchanged and an error is returned.
+func (r *Regression) Optimize(fixNoise bool, settings *optimize.Settings, method optimize.Method) (*optimize.Result, error) {
+	nk := r.kernel.NumHyper()
+	np := nk
+	if !fixNoise {
+		np++
+	}
+	if !fixNoise && !(r.noise > 0) {
+		panic("gp: noise variance not positive")
+	}
+	x0 := make([]float64, np)
+	r.kernel.Hyper(x0[:nk])
+	if !fixNoise {
+		x0[nk] = math.Log(r.noise)
+	}
+	orig := append([]float64(nil), x0...)
+
+	n := float64(r.y.Len())
+	set := func(x []float64) bool {
+		r.kernel.SetHyper(x[:nk])
+		if !fixNoise {
+			r.noise = math.Exp(x[nk])
+		}
+		return r.factorize()
+	}
+	problem := optimize.Problem{
+		Func: func(x []float64) float64 {
+			if !set(x) {
+				return math.Inf(1)
+			}
+			return -r.LogMarginalLikelihood() / n
+		},
+		Grad: func(grad, x []float64) {
+			if !set(x) {
+				for i := range grad {
+					grad[i] = math.NaN()
+				}
+				return
+			}
+			r.logMarginalLikelihoodGrad(grad)
+			floats.Scale(-1/n, grad)
+		},
+	}
+	if settings == nil {
+		settings = &optimize.Settings{GradientThreshold: 1e-8}
+	}
+	res, err := optimize.Minimize(problem, x0, settings, method)
+	if res == nil || !set(res.X) {
+		set(orig)
+		if err == nil {
+			err = errors.New("gp: covariance not positive definite")
+		}
+		return res, err
+	}
+	return res, err
+}
@@ -0,0 +1,187 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestRegressionPredict(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 1, []float64{-2, -0.5, 1, 2.5})
+	y := []float64{0.5, -0.3, 0.8, 0.1}
+	k := &RBF{Variance: 1.5, LengthScale: 0.9}
+	const noise = 0.1
+	r, ok := NewRegression(k, noise, x, y)
+	if !ok {
+		t.Fatalf("unexpected failure to condition model")
+	}
+
+	// The predictions are the conditional distribution of the latent
+	// function values given the observations under the joint normal
+	// prior.
+	xs := mat.NewDense(3, 1, []float64{-1, 0.2, 4})
+	all := mat.NewDense(7, 1, nil)
+	all.Slice(0, 4, 0, 1).(*mat.Dense).Copy(x)
+	all.Slice(4, 7, 0, 1).(*mat.Dense).Copy(xs)
+	joint := mat.NewSymDense(7, nil)
+	for i := 0; i < 7; i++ {
+		for j := i; j < 7; j++ {
+			joint.SetSym(i, j, k.Cov(all.RawRowView(i), all.RawRowView(j)))
+		}
+		if i < 4 {
+			joint.SetSym(i, i, joint.At(i, i)+noise)
+		}
+	}
+	normal, ok := distmv.NewNormal(make([]float64, 7), joint, nil)
+	if !ok {
+		t.Fatalf("bad test, joint covariance not positive definite")
+	}
+	cond, ok := normal.ConditionNormal([]int{0, 1, 2, 3}, y, nil)
+	if !ok {
+		t.Fatalf("bad test, unable to condition")
+	}
+	wantMean := cond.Mean(nil)
+	var wantCov mat.SymDense
+	cond.CovarianceMatrix(&wantCov)
+
+	var cov mat.SymDense
+	mean := r.PredictTo(nil, &cov, xs)
+	if !floats.EqualApprox(mean, wantMean, 1e-12) {
+		t.Errorf("unexpected predictive mean: got %v, want %v", mean, wantMean)
+	}
+	if !mat.EqualApprox(&cov, &wantCov, 1e-12) {
+		t.Errorf("unexpected predictive covariance:\ngot:\n%v\nwant:\n%v", mat.Formatted(&cov), mat.Formatted(&wantCov))
+	}
+	for i := 0; i < 3; i++ {
+		m, v := r.Predict(xs.RawRowView(i))
+		if !scalar.EqualWithinAbsOrRel(m, wantMean[i], 1e-12, 1e-12) || !scalar.EqualWithinAbsOrRel(v, wantCov.At(i, i), 1e-12, 1e-12) {
+			t.Errorf("unexpected prediction at %v: got (%v, %v), want (%v, %v)", xs.At(i, 0), m, v, wantMean[i], wantCov.At(i, i))
+		}
+	}
+
+	wantLL := distmv.NormalLogProb(y, make([]float64, 4), choleskyOf(t, joint.SliceSym(0, 4).(*mat.SymDense)))
+	if got := r.LogMarginalLikelihood(); !scalar.EqualWithinAbsOrRel(got, wantLL, 1e-12, 1e-12) {
+		t.Errorf("unexpected log marginal likelihood: got %v, want %v", got, wantLL)
+	}
+
+	// Without noise the posterior mean interpolates the observations.
+	r, ok = NewRegression(&Matern{Variance: 1, LengthScale: 1, Nu: 2.5}, 0, x, y)
+	if !ok {
+		t.Fatalf("unexpected failure to condition noise-free model")
+	}
+	for i, v := range y {
+		m, s := r.Predict(x.RawRowView(i))
+		if math.Abs(m-v) > 1e-10 || s > 1e-10 {
+			t.Errorf("noise-free model does not interpolate at %v: got (%v, %v), want (%v, 0)", x.At(i, 0), m, s, v)
+		}
+	}
+}
+
+func choleskyOf(t *testing.T, a mat.Symmetric) *mat.Cholesky {
+	var chol mat.Cholesky
+	if !chol.Factorize(a) {
+		t.Fatalf("bad test, matrix not positive definite")
+	}
+	return &chol
+}
+
+func TestRegressionLogMarginalLikelihoodGrad(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := mat.NewDense(20, 2, nil)
+	y := make([]float64, 20)
+	for i := range y {
+		x.Set(i, 0, 4*rnd.Float64())
+		x.Set(i, 1, 4*rnd.Float64())
+		y[i] = math.Sin(x.At(i, 0)) + 0.5*math.Cos(2*x.At(i, 1)) + 0.1*rnd.NormFloat64()
+	}
+	k := Sum{&RBF{Variance: 1, LengthScale: 1}, &Periodic{Variance: 0.3, LengthScale: 1, Period: 3}}
+	r, ok := NewRegression(k, 0.05, x, y)
+	if !ok {
+		t.Fatalf("unexpected failure to condition model")
+	}
+	theta := append(k.Hyper(nil), math.Log(r.Noise()))
+	grad := make([]float64, len(theta))
+	r.logMarginalLikelihoodGrad(grad)
+
+	want := fd.Gradient(nil, func(h []float64) float64 {
+		k.SetHyper(h[:len(h)-1])
+		r.noise = math.Exp(h[len(h)-1])
+		if !r.factorize() {
+			panic("bad test")
+		}
+		return r.LogMarginalLikelihood()
+	}, theta, &fd.Settings{Formula: fd.Central})
+	if !floats.EqualApprox(grad, want, 1e-5) {
+		t.Errorf("gradient mismatch: got %v, want %v", grad, want)
+	}
+}
+
+func TestRegressionOptimize(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// Draw observations from a Gaussian process with known parameters.
+	const (
+		n           = 100
+		lengthScale = 0.7
+		noise       = 0.04
+	)
+	truth := &RBF{Variance: 1, LengthScale: lengthScale}
+	x := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		x.Set(i, 0, 10*rnd.Float64())
+	}
+	cov := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			cov.SetSym(i, j, truth.Cov(x.RawRowView(i), x.RawRowView(j)))
+		}
+		cov.SetSym(i, i, cov.At(i, i)+noise)
+	}
+	normal, ok := distmv.NewNormal(make([]float64, n), cov, rand.NewSource(2))
+	if !ok {
+		t.Fatalf("bad test, covariance not positive definite")
+	}
+	y := normal.Rand(nil)
+
+	k := &RBF{Variance: 0.5, LengthScale: 2}
+	r, ok := NewRegression(k, 0.5, x, y)
+	if !ok {
+		t.Fatalf("unexpected failure to condition model")
+	}
+	before := r.LogMarginalLikelihood()
+	if _, err := r.Optimize(false, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := r.LogMarginalLikelihood(); after <= before {
+		t.Errorf("log marginal likelihood did not increase: %v to %v", before, after)
+	}
+	if math.Abs(k.LengthScale-lengthScale) > 0.15 {
+		t.Errorf("unexpected length scale: got %v, want %v", k.LengthScale, lengthScale)
+	}
+	if math.Abs(r.Noise()-noise) > 0.02 {
+		t.Errorf("unexpected noise variance: got %v, want %v", r.Noise(), noise)
+	}
+
+	// With the noise fixed, only the kernel is optimized.
+	r, _ = NewRegression(&RBF{Variance: 0.5, LengthScale: 2}, 0.3, x, y)
+	if _, err := r.Optimize(true, nil, nil); err != nil {
+		t.Fatalf("unexpected error with fixed noise: %v", err)
+	}
+	if r.Noise() != 0.3 {
+		t.Errorf("fixed noise variance changed: got %v", r.Noise())
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Linkage specifies how the distance between two clusters is computed from the
+// distances between their members in agglomerative clustering.
+type Linkage int
+
+const (
+	// SingleLinkage is the minimum distance between members
+	// of the two clusters.
+	SingleLinkage Linkage = iota
+	// CompleteLinkage is the maximum distance between members
+	// of the two clusters.
+	CompleteLinkage
+	// AverageLinkage is the mean distance between members of
+	// the two clusters, also known as UPGMA.
+	AverageLinkage
+	// WardLinkage merges the pair of clusters giving the least
+	// increase in the total within-cluster sum of squares. The
+	// distance between clusters A and B is
+	//  sqrt(2 |A| |B| / (|A| + |B|)) ‖mean(A) - mean(B)‖,
+	// which is only meaningful when the distances between the
+	// observations are Euclidean.
+	WardLinkage
+)
+
+// Merge is a step of agglomerative clustering in which two clusters are joined.
+// Clusters are identified as in the SciPy linkage matrix: the clusters 0 to n-1
+// are the n individual observations, and the cluster formed by the ith merge
+// is identified as n+i.
+type Merge struct {
+	// A and B are the clusters joined by the merge, with A < B.
+	A, B int
+
+	// Height is the linkage distance between A and B.
+	Height float64
+
+	// Size is the number of observations in the joined cluster.
+	Size int
+}
+
+// Dendrogram is the hierarchy of clusters constructed by agglomerative
+// clustering of a set of observations.
+type Dendrogram struct {
+	n      int
+	merges []Merge
+}
+
+// Agglomerate performs agglomerative hierarchical clustering of n observations
+// given the n×n matrix of distances between them, and returns the resulting
+// dendrogram. Starting from the individual observations, the pair of clusters
+// with the least linkage distance is repeatedly merged until one cluster
+// remains. The diagonal of dist is not used.
+//
+// The clustering is computed in O(n²) time using the nearest-neighbor chain
+// algorithm with Lance-Williams distance updates.
+//  Müllner, D. "Modern hierarchical, agglomerative clustering algorithms."
+//  arXiv:1109.2378 (2011).
+//
+// Agglomerate will panic if dist is empty or link is not a valid Linkage.
+func Agglomerate(dist mat.Symmetric, link Linkage) *Dendrogram {
+	n := dist.SymmetricDim()
+	if n == 0 {
+		panic(mat.ErrZeroLength)
+	}
+	if link < SingleLinkage || link > WardLinkage {
+		panic("stat: invalid linkage")
+	}
+	d := mat.NewSymDense(n, nil)
+	d.CopySym(dist)
+	size := make([]int, n)
+	active := make([]bool, n)
+	for i := range size {
+		size[i] = 1
+		active[i] = true
+	}
+
+	type step struct {
+		a, b   int
+		height float64
+	}
+	steps := make([]step, 0, n-1)
+	chain := make([]int, 0, n)
+	for len(steps) < n-1 {
+		if len(chain) == 0 {
+			for i, ok := range active {
+				if ok {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+		var a, b int
+		var height float64
+		for {
+			a = chain[len(chain)-1]
+			// Prefer the previous element of the chain on ties
+			// so that the chain terminates.
+			b = -1
+			height = math.Inf(1)
+			if len(chain) > 1 {
+				b = chain[len(chain)-2]
+				height = d.At(a, b)
+			}
+			for k, ok := range active {
+				if !ok || k == a {
+					continue
+				}
+				if v := d.At(a, k); v < height || b < 0 {
+					b, height = k, v
+				}
+			}
+			if len(chain) > 1 && b == chain[len(chain)-2] {
+				break
+			}
+			chain = append(chain, b)
+		}
+		chain = chain[:len(chain)-2]
+		if a > b {
+			a, b = b, a
+		}
+		steps = append(steps, step{a: a, b: b, height: height})
+
+		// The merged cluster is stored in b.
+		na, nb := float64(size[a]), float64(size[b])
+		active[a] = false
+		for k, ok := range active {
+			if !ok || k == b {
+				continue
+			}
+			dka, dkb := d.At(k, a), d.At(k, b)
+			var v float64
+			switch link {
+			case SingleLinkage:
+				v = math.Min(dka, dkb)
+			case CompleteLinkage:
+				v = math.Max(dka, dkb)
+			case AverageLinkage:
+				v = (na*dka + nb*dkb) / (na + nb)
+			case WardLinkage:
+				nk := float64(size[k])
+				v = math.Sqrt(((nk+na)*dka*dka + (nk+nb)*dkb*dkb - nk*height*height) / (nk + na + nb))
+			}
+			d.SetSym(k, b, v)
+		}
+		size[b] += size[a]
+	}
+
+	// The nearest-neighbor chain finds the merges out of order,
+	// so sort them by height and label the clusters they form.
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].height < steps[j].height })
+	uf := newUnionFind(n)
+	id := make([]int, n)
+	for i := range id {
+		id[i] = i
+	}
+	merges := make([]Merge, len(steps))
+	for i, s := range steps {
+		ra, rb := uf.find(s.a), uf.find(s.b)
+		ca, cb := id[ra], id[rb]
+		if ca > cb {
+			ca, cb = cb, ca
+		}
+		r := uf.union(ra, rb)
+		id[r] = n + i
+		merges[i] = Merge{A: ca, B: cb, Height: s.height, Size: uf.size[r]}
+	}
+	return &Dendrogram{n: n, merges: merges}
+}
+
+// Len returns the number of observations in the dendrogram.
+func (d *Dendrogram) Len() int {
+	return d.n
+}
+
+// Merges returns the n-1 merges of the dendrogram in order of increasing
+// height. If dst is not nil it is used to store the merges and returned.
+// Merges will panic if dst is not nil and its length is not n-1.
+func (d *Dendrogram) Merges(dst []Merge) []Merge {
+	if dst == nil {
+		dst = make([]Merge, len(d.merges))
+	}
+	if len(dst) != len(d.merges) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, d.merges)
+	return dst
+}
+
+// CutK returns the cluster labels of the observations when the dendrogram is
+// cut to give k clusters. Clusters are labeled from zero in the order of their
+// lowest indexed observation. If dst is not nil it is used to store the labels
+// and returned. CutK will panic if k is not between 1 and the number of
+// observations, or if dst is not nil and its length is not the number of
+// observations.
+func (d *Dendrogram) CutK(dst []int, k int) []int {
+	if k < 1 || k > d.n {
+		panic("stat: invalid number of clusters")
+	}
+	return d.cut(dst, d.n-k)
+}
+
+// CutHeight returns the cluster labels of the observations when the
+// dendrogram is cut at the given height, so that merges with a height no
+// greater than height are performed, and the number of clusters. Clusters are
+// labeled from zero in the order of their lowest indexed observation. If dst
+// is not nil it is used to store the labels and returned. CutHeight will panic
+// if dst is not nil and its length is not the number of observations.
+func (d *Dendrogram) CutHeight(dst []int, height float64) (labels []int, clusters int) {
+	m := sort.Search(len(d.merges), func(i int) bool { return d.merges[i].Height > height })
+	return d.cut(dst, m), d.n - m
+}
+
+// cut labels the clusters formed by the first m merges.
+func (d *Dendrogram) cut(dst []int, m int) []int {
+	if dst == nil {
+		dst = make([]int, d.n)
+	}
+	if len(dst) != d.n {
+		panic("stat: slice length mismatch")
+	}
+	// Map each cluster identifier to an observation
+	// it contains to join clusters by observation.
+	rep := make([]int, d.n+m)
+	for i := 0; i < d.n; i++ {
+		rep[i] = i
+	}
+	uf := newUnionFind(d.n)
+	for i, s := range d.merges[:m] {
+		uf.union(rep[s.A], rep[s.B])
+		rep[d.n+i] = rep[s.A]
+	}
+	label := make(map[int]int)
+	for i := range dst {
+		r := uf.find(i)
+		l, ok := label[r]
+		if !ok {
+			l = len(label)
+			label[r] = l
+		}
+		dst[i] = l
+	}
+	return dst
+}
+
+// PairwiseDistances computes the distances between the rows of x, placing the
+// result in dst. If distance is nil, the Euclidean distance is used. If dst is
+// empty, PairwiseDistances will resize dst to be n×n, where n is the number of
+// rows of x. When dst is non-empty, PairwiseDistances will panic if dst is not
+// n×n. The diagonal of dst is set to zero.
+func PairwiseDistances(dst *mat.SymDense, x mat.Matrix, distance func(a, b []float64) float64) {
+	if distance == nil {
+		distance = func(a, b []float64) float64 { return math.Sqrt(sqEuclidean(a, b)) }
+	}
+	n, _ := x.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(n)
+	} else if dst.SymmetricDim() != n {
+		panic(mat.ErrShape)
+	}
+	xd := mat.DenseCopyOf(x)
+	for i := 0; i < n; i++ {
+		dst.SetSym(i, i, 0)
+		for j := i + 1; j < n; j++ {
+			dst.SetSym(i, j, distance(xd.RawRowView(i), xd.RawRowView(j)))
+		}
+	}
+}
+
+// unionFind is a disjoint set forest over the integers [0, n).
+type unionFind struct {
+	parent []int
+	size   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), size: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.size[i] = 1
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+// union joins the sets containing i and j and returns the root of the
+// joined set.
+func (uf *unionFind) union(i, j int) int {
+	i, j = uf.find(i), uf.find(j)
+	if i == j {
+		return i
+	}
+	if uf.size[i] < uf.size[j] {
+		i, j = j, i
+	}
+	uf.parent[j] = i
+	uf.size[i] += uf.size[j]
+	return i
+}
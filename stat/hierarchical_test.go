@@ -0,0 +1,200 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// naiveAgglomerate returns the merge heights and the clusters after each merge
+// of agglomerative clustering of the rows of x, computing linkage distances
+// directly from the cluster members.
+func naiveAgglomerate(x *mat.Dense, link Linkage) (heights []float64, partitions [][]int) {
+	n, dim := x.Dims()
+	clusters := make([][]int, n)
+	for i := range clusters {
+		clusters[i] = []int{i}
+	}
+	dist := func(i, j int) float64 { return math.Sqrt(sqEuclidean(x.RawRowView(i), x.RawRowView(j))) }
+	mean := func(c []int) []float64 {
+		m := make([]float64, dim)
+		for _, i := range c {
+			floats.Add(m, x.RawRowView(i))
+		}
+		floats.Scale(1/float64(len(c)), m)
+		return m
+	}
+	linkage := func(a, b []int) float64 {
+		switch link {
+		case SingleLinkage:
+			v := math.Inf(1)
+			for _, i := range a {
+				for _, j := range b {
+					v = math.Min(v, dist(i, j))
+				}
+			}
+			return v
+		case CompleteLinkage:
+			v := math.Inf(-1)
+			for _, i := range a {
+				for _, j := range b {
+					v = math.Max(v, dist(i, j))
+				}
+			}
+			return v
+		case AverageLinkage:
+			var v float64
+			for _, i := range a {
+				for _, j := range b {
+					v += dist(i, j)
+				}
+			}
+			return v / float64(len(a)*len(b))
+		case WardLinkage:
+			na, nb := float64(len(a)), float64(len(b))
+			return math.Sqrt(2 * na * nb / (na + nb) * sqEuclidean(mean(a), mean(b)))
+		}
+		panic("bad linkage")
+	}
+	for len(clusters) > 1 {
+		bi, bj := -1, -1
+		best := math.Inf(1)
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if v := linkage(clusters[i], clusters[j]); v < best {
+					bi, bj, best = i, j, v
+				}
+			}
+		}
+		heights = append(heights, best)
+		clusters[bi] = append(clusters[bi], clusters[bj]...)
+		clusters = append(clusters[:bj], clusters[bj+1:]...)
+		labels := make([]int, n)
+		for c, members := range clusters {
+			for _, i := range members {
+				labels[i] = c
+			}
+		}
+		partitions = append(partitions, labels)
+	}
+	return heights, partitions
+}
+
+// samePartition returns whether the labelings a and b define the same
+// partition.
+func samePartition(a, b []int) bool {
+	ab := make(map[int]int)
+	ba := make(map[int]int)
+	for i := range a {
+		if v, ok := ab[a[i]]; ok && v != b[i] {
+			return false
+		}
+		if v, ok := ba[b[i]]; ok && v != a[i] {
+			return false
+		}
+		ab[a[i]] = b[i]
+		ba[b[i]] = a[i]
+	}
+	return true
+}
+
+func TestAgglomerate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10, 30} {
+		x := mat.NewDense(n, 3, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < 3; j++ {
+				x.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var dist mat.SymDense
+		PairwiseDistances(&dist, x, nil)
+		for _, link := range []Linkage{SingleLinkage, CompleteLinkage, AverageLinkage, WardLinkage} {
+			d := Agglomerate(&dist, link)
+			if d.Len() != n {
+				t.Errorf("n=%d link=%d: unexpected length: got %d, want %d", n, link, d.Len(), n)
+			}
+			merges := d.Merges(nil)
+			heights, partitions := naiveAgglomerate(x, link)
+			if len(merges) != len(heights) {
+				t.Fatalf("n=%d link=%d: unexpected number of merges: got %d, want %d", n, link, len(merges), len(heights))
+			}
+			for i, m := range merges {
+				if !scalar.EqualWithinAbsOrRel(m.Height, heights[i], 1e-12, 1e-12) {
+					t.Errorf("n=%d link=%d: unexpected height of merge %d: got %v, want %v", n, link, i, m.Height, heights[i])
+				}
+				if m.A >= m.B || m.B >= n+i {
+					t.Errorf("n=%d link=%d: invalid clusters in merge %d: %d and %d", n, link, i, m.A, m.B)
+				}
+				k := n - i - 1
+				labels := d.CutK(nil, k)
+				if !samePartition(labels, partitions[i]) {
+					t.Errorf("n=%d link=%d: unexpected partition for k=%d: got %v, want %v", n, link, k, labels, partitions[i])
+				}
+				if floats.Max(intsToFloats(labels)) != float64(k-1) {
+					t.Errorf("n=%d link=%d: unexpected labels for k=%d: %v", n, link, k, labels)
+				}
+				hl, clusters := d.CutHeight(nil, m.Height)
+				if clusters != k || !samePartition(hl, labels) {
+					t.Errorf("n=%d link=%d: unexpected cut at height %v: got %d clusters, want %d", n, link, m.Height, clusters, k)
+				}
+			}
+			if n > 1 && merges[n-2].Size != n {
+				t.Errorf("n=%d link=%d: unexpected size of final merge: got %d, want %d", n, link, merges[n-2].Size, n)
+			}
+			labels := d.CutK(nil, n)
+			for i, l := range labels {
+				if l != i {
+					t.Errorf("n=%d link=%d: unexpected label for k=n: got %d, want %d", n, link, l, i)
+				}
+			}
+			if _, clusters := d.CutHeight(nil, -1); clusters != n {
+				t.Errorf("n=%d link=%d: unexpected number of clusters below all merges: got %d, want %d", n, link, clusters, n)
+			}
+		}
+	}
+}
+
+func intsToFloats(s []int) []float64 {
+	f := make([]float64, len(s))
+	for i, v := range s {
+		f[i] = float64(v)
+	}
+	return f
+}
+
+func TestDendrogramSizes(t *testing.T) {
+	t.Parallel()
+	// Points on a line with increasing gaps merge in order.
+	x := mat.NewDense(4, 1, []float64{0, 1, 3, 6})
+	var dist mat.SymDense
+	PairwiseDistances(&dist, x, nil)
+	got := Agglomerate(&dist, SingleLinkage).Merges(nil)
+	want := []Merge{
+		{A: 0, B: 1, Height: 1, Size: 2},
+		{A: 2, B: 4, Height: 2, Size: 3},
+		{A: 3, B: 5, Height: 3, Size: 4},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected merge %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if !panics(func() { Agglomerate(&dist, Linkage(-1)) }) {
+		t.Error("expected panic for invalid linkage")
+	}
+	if !panics(func() { Agglomerate(&dist, SingleLinkage).CutK(nil, 0) }) {
+		t.Error("expected panic for invalid k")
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultKMeansIterations    = 300
+	defaultMiniBatchIterations = 100
+)
+
+// KMeans is a type for computing a k-means clustering of the rows of a
+// matrix. The exported fields of KMeans configure the clustering and are read
+// by Cluster. The results of the clustering are only valid after a call to
+// Cluster.
+type KMeans struct {
+	// Distance returns the dissimilarity between the points a and b.
+	// Points are assigned to the cluster with the nearest center under
+	// Distance, and Inertia is the weighted sum of the distances of the
+	// points to their centers. Cluster centers are always updated to the
+	// weighted mean of their points, which minimizes the inertia only for
+	// the squared Euclidean distance. If Distance is nil, the squared
+	// Euclidean distance is used.
+	Distance func(a, b []float64) float64
+
+	// MaxIterations is the maximum number of Lloyd iterations, or the
+	// number of mini-batch updates if BatchSize is positive. If
+	// MaxIterations is zero, 300 Lloyd iterations or 100 mini-batch
+	// updates are used.
+	MaxIterations int
+
+	// BatchSize is the number of points sampled for each update of the
+	// mini-batch k-means algorithm of Sculley. If BatchSize is zero, the
+	// full-batch Lloyd algorithm is used.
+	//  Sculley, D. "Web-scale k-means clustering." WWW '10 (2010).
+	BatchSize int
+
+	// Src is the source of randomness for the k-means++ initialization
+	// and the mini-batch sampling. If Src is nil, the global source of
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	k, d    int
+	centers *mat.Dense
+	assign  []int
+	inertia float64
+	ok      bool
+}
+
+// Cluster partitions the rows of the n×d matrix x into k clusters. The
+// initial cluster centers are chosen by k-means++ seeding and refined by
+// Lloyd iterations or mini-batch updates, depending on BatchSize.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or Cluster will panic.
+// Cluster will also panic if k is less than one or greater than n.
+//
+// Cluster returns whether the Lloyd iterations converged to a fixed
+// assignment within MaxIterations. Mini-batch updates do not test
+// convergence and Cluster returns true when BatchSize is positive. The
+// results are valid in either case.
+func (km *KMeans) Cluster(x mat.Matrix, k int, weights []float64) (converged bool) {
+	n, d := x.Dims()
+	if k < 1 || k > n {
+		panic("stat: invalid number of clusters")
+	}
+	if weights != nil && len(weights) != n {
+		panic("stat: len(weights) != observations")
+	}
+	if km.BatchSize < 0 || km.MaxIterations < 0 {
+		panic("stat: negative k-means parameter")
+	}
+	km.ok = false
+	km.k, km.d = k, d
+
+	dist := km.Distance
+	if dist == nil {
+		dist = sqEuclidean
+	}
+	float64n := rand.Float64
+	intn := rand.Intn
+	if km.Src != nil {
+		rnd := rand.New(km.Src)
+		float64n = rnd.Float64
+		intn = rnd.Intn
+	}
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+
+	xd := mat.DenseCopyOf(x)
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = xd.RawRowView(i)
+	}
+	centers := mat.NewDense(k, d, nil)
+	km.seed(centers, points, weight, dist, float64n)
+
+	if cap(km.assign) < n {
+		km.assign = make([]int, n)
+	}
+	assign := km.assign[:n]
+	for i := range assign {
+		assign[i] = -1
+	}
+	dists := make([]float64, n)
+
+	if km.BatchSize > 0 {
+		km.miniBatch(centers, points, weight, dist, intn)
+		converged = true
+	} else {
+		iters := km.MaxIterations
+		if iters == 0 {
+			iters = defaultKMeansIterations
+		}
+		sums := mat.NewDense(k, d, nil)
+		counts := make([]float64, k)
+		for it := 0; it < iters; it++ {
+			if !assignNearest(assign, dists, centers, points, dist) && it > 0 {
+				converged = true
+				break
+			}
+			sums.Zero()
+			for i := range counts {
+				counts[i] = 0
+			}
+			for i, p := range points {
+				w := weight(i)
+				floats.AddScaled(sums.RawRowView(assign[i]), w, p)
+				counts[assign[i]] += w
+			}
+			for c, cnt := range counts {
+				if cnt == 0 {
+					// Move the center of an empty cluster to the
+					// point that contributes most to the inertia.
+					far := 0
+					for i := range dists {
+						if weight(i)*dists[i] > weight(far)*dists[far] {
+							far = i
+						}
+					}
+					copy(centers.RawRowView(c), points[far])
+					dists[far] = 0
+					continue
+				}
+				floats.ScaleTo(centers.RawRowView(c), 1/cnt, sums.RawRowView(c))
+			}
+		}
+	}
+
+	assignNearest(assign, dists, centers, points, dist)
+	var inertia float64
+	for i, v := range dists {
+		inertia += weight(i) * v
+	}
+	km.centers = centers
+	km.assign = assign
+	km.inertia = inertia
+	km.ok = true
+	return converged
+}
+
+// seed chooses the initial cluster centers with k-means++ seeding, sampling
+// each new center with probability proportional to its weighted distance
+// from the nearest center already chosen.
+func (km *KMeans) seed(centers *mat.Dense, points [][]float64, weight func(int) float64, dist func(a, b []float64) float64, float64n func() float64) {
+	n := len(points)
+	prob := make([]float64, n)
+	for i := range prob {
+		prob[i] = weight(i)
+	}
+	nearest := make([]float64, n)
+	for i := range nearest {
+		nearest[i] = math.Inf(1)
+	}
+	for c := 0; c < km.k; c++ {
+		idx := sampleIndex(prob, float64n)
+		center := centers.RawRowView(c)
+		copy(center, points[idx])
+		for i, p := range points {
+			nearest[i] = math.Min(nearest[i], dist(p, center))
+			prob[i] = weight(i) * nearest[i]
+		}
+	}
+}
+
+// miniBatch refines the centers with mini-batch updates, in which each center
+// moves toward the sampled points assigned to it with a learning rate given by
+// the inverse of the total weight it has been assigned.
+func (km *KMeans) miniBatch(centers *mat.Dense, points [][]float64, weight func(int) float64, dist func(a, b []float64) float64, intn func(int) int) {
+	iters := km.MaxIterations
+	if iters == 0 {
+		iters = defaultMiniBatchIterations
+	}
+	n := len(points)
+	counts := make([]float64, km.k)
+	batch := make([]int, km.BatchSize)
+	nearest := make([]int, km.BatchSize)
+	for it := 0; it < iters; it++ {
+		for b := range batch {
+			batch[b] = intn(n)
+			nearest[b] = nearestCenter(centers, points[batch[b]], dist)
+		}
+		for b, i := range batch {
+			w := weight(i)
+			if w == 0 {
+				continue
+			}
+			c := nearest[b]
+			counts[c] += w
+			eta := w / counts[c]
+			center := centers.RawRowView(c)
+			floats.Scale(1-eta, center)
+			floats.AddScaled(center, eta, points[i])
+		}
+	}
+}
+
+// assignNearest assigns each point to its nearest center, storing the
+// distances to the centers in dists, and returns whether any assignment
+// changed.
+func assignNearest(assign []int, dists []float64, centers *mat.Dense, points [][]float64, dist func(a, b []float64) float64) (changed bool) {
+	for i, p := range points {
+		c := nearestCenter(centers, p, dist)
+		if c != assign[i] {
+			assign[i] = c
+			changed = true
+		}
+		dists[i] = dist(p, centers.RawRowView(c))
+	}
+	return changed
+}
+
+// nearestCenter returns the index of the row of centers nearest to p.
+func nearestCenter(centers *mat.Dense, p []float64, dist func(a, b []float64) float64) int {
+	k, _ := centers.Dims()
+	best := 0
+	bestDist := math.Inf(1)
+	for c := 0; c < k; c++ {
+		if v := dist(p, centers.RawRowView(c)); v < bestDist {
+			best, bestDist = c, v
+		}
+	}
+	return best
+}
+
+// sampleIndex returns an index sampled with probability proportional to the
+// non-negative weights in prob. If all weights are zero, the index is sampled
+// uniformly.
+func sampleIndex(prob []float64, float64n func() float64) int {
+	sum := floats.Sum(prob)
+	if !(sum > 0) {
+		return int(float64n() * float64(len(prob)))
+	}
+	u := float64n() * sum
+	for i, p := range prob {
+		u -= p
+		if u < 0 {
+			return i
+		}
+	}
+	// Guard against rounding error in the cumulative sum.
+	for i := len(prob) - 1; i >= 0; i-- {
+		if prob[i] > 0 {
+			return i
+		}
+	}
+	panic("unreachable")
+}
+
+// sqEuclidean returns the squared Euclidean distance between a and b.
+func sqEuclidean(a, b []float64) float64 {
+	var d float64
+	for i, v := range a {
+		v -= b[i]
+		d += v * v
+	}
+	return d
+}
+
+// CentersTo stores the cluster centers into the rows of dst.
+//
+// If dst is empty, CentersTo will resize dst to be k×d. When dst is non-empty,
+// CentersTo will panic if dst is not k×d. CentersTo will also panic if the
+// receiver does not contain a clustering.
+func (km *KMeans) CentersTo(dst *mat.Dense) {
+	if !km.ok {
+		panic("stat: use of unclustered k-means")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(km.k, km.d)
+	} else if r, c := dst.Dims(); r != km.k || c != km.d {
+		panic(mat.ErrShape)
+	}
+	dst.Copy(km.centers)
+}
+
+// Assignments returns the index of the cluster of each observation. If dst is
+// not nil it is used to store the assignments and returned. Assignments will
+// panic if the receiver does not contain a clustering or dst is not nil and
+// the length of dst is not the number of observations.
+func (km *KMeans) Assignments(dst []int) []int {
+	if !km.ok {
+		panic("stat: use of unclustered k-means")
+	}
+	if dst == nil {
+		dst = make([]int, len(km.assign))
+	}
+	if len(dst) != len(km.assign) {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, km.assign)
+	return dst
+}
+
+// Inertia returns the weighted sum of the distances of the observations to
+// the centers of their clusters. Inertia will panic if the receiver does not
+// contain a clustering.
+func (km *KMeans) Inertia() float64 {
+	if !km.ok {
+		panic("stat: use of unclustered k-means")
+	}
+	return km.inertia
+}
+
+// Nearest returns the index of the cluster whose center is nearest to the
+// point x. Nearest will panic if the receiver does not contain a clustering or
+// the length of x does not match the number of variables.
+func (km *KMeans) Nearest(x []float64) int {
+	if !km.ok {
+		panic("stat: use of unclustered k-means")
+	}
+	if len(x) != km.d {
+		panic("stat: slice length mismatch")
+	}
+	dist := km.Distance
+	if dist == nil {
+		dist = sqEuclidean
+	}
+	return nearestCenter(km.centers, x, dist)
+}
@@ -0,0 +1,196 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// kmeansBlobs returns n points around each of the given centers with the
+// given standard deviation, and the index of the center of each point.
+func kmeansBlobs(rnd *rand.Rand, centers [][]float64, n int, std float64) (*mat.Dense, []int) {
+	d := len(centers[0])
+	x := mat.NewDense(n*len(centers), d, nil)
+	labels := make([]int, n*len(centers))
+	for c, center := range centers {
+		for i := 0; i < n; i++ {
+			row := c*n + i
+			labels[row] = c
+			for j, v := range center {
+				x.Set(row, j, v+std*rnd.NormFloat64())
+			}
+		}
+	}
+	return x, labels
+}
+
+func TestKMeans(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	centers := [][]float64{{0, 0, 0}, {10, 0, 0}, {0, 10, 5}, {-8, -8, 8}}
+	x, labels := kmeansBlobs(rnd, centers, 50, 0.5)
+	n, _ := x.Dims()
+
+	for _, test := range []struct {
+		name      string
+		batchSize int
+		tol       float64
+	}{
+		{name: "lloyd", tol: 0.3},
+		{name: "minibatch", batchSize: 20, tol: 0.5},
+	} {
+		km := KMeans{BatchSize: test.batchSize, Src: rand.NewSource(2)}
+		if !km.Cluster(x, len(centers), nil) {
+			t.Errorf("%s: clustering did not converge", test.name)
+		}
+
+		// Each true cluster must map to a distinct cluster.
+		assign := km.Assignments(nil)
+		mapping := make(map[int]int)
+		for i, l := range labels {
+			c, ok := mapping[l]
+			if !ok {
+				mapping[l] = assign[i]
+				continue
+			}
+			if c != assign[i] {
+				t.Errorf("%s: point %d assigned to cluster %d, want %d", test.name, i, assign[i], c)
+			}
+		}
+		if len(mapping) != len(centers) {
+			t.Errorf("%s: found %d distinct clusters, want %d", test.name, len(mapping), len(centers))
+		}
+
+		var got mat.Dense
+		km.CentersTo(&got)
+		for l, c := range mapping {
+			if !floats.EqualApprox(got.RawRowView(c), centers[l], test.tol) {
+				t.Errorf("%s: unexpected center: got %v, want %v", test.name, got.RawRowView(c), centers[l])
+			}
+		}
+
+		var inertia float64
+		for i := 0; i < n; i++ {
+			inertia += sqEuclidean(x.RawRowView(i), got.RawRowView(assign[i]))
+			if c := km.Nearest(x.RawRowView(i)); c != assign[i] {
+				t.Errorf("%s: unexpected nearest cluster for point %d: got %d, want %d", test.name, i, c, assign[i])
+			}
+		}
+		if !scalar.EqualWithinAbsOrRel(km.Inertia(), inertia, 1e-10, 1e-10) {
+			t.Errorf("%s: unexpected inertia: got %v, want %v", test.name, km.Inertia(), inertia)
+		}
+	}
+}
+
+func TestKMeansWeights(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, _ := kmeansBlobs(rnd, [][]float64{{0, 0}, {20, 20}}, 30, 1)
+	n, _ := x.Dims()
+
+	// Integer weights must give the same clustering as repeated points.
+	weights := make([]float64, n)
+	var rows []float64
+	for i := range weights {
+		weights[i] = float64(1 + i%3)
+		for k := 0; k < int(weights[i]); k++ {
+			rows = append(rows, x.RawRowView(i)...)
+		}
+	}
+	repeated := mat.NewDense(len(rows)/2, 2, rows)
+
+	weighted := KMeans{Src: rand.NewSource(1)}
+	unweighted := KMeans{Src: rand.NewSource(1)}
+	weighted.Cluster(x, 2, weights)
+	unweighted.Cluster(repeated, 2, nil)
+	if !scalar.EqualWithinAbsOrRel(weighted.Inertia(), unweighted.Inertia(), 1e-10, 1e-10) {
+		t.Errorf("unexpected weighted inertia: got %v, want %v", weighted.Inertia(), unweighted.Inertia())
+	}
+	var cw, cu mat.Dense
+	weighted.CentersTo(&cw)
+	unweighted.CentersTo(&cu)
+	for i := 0; i < 2; i++ {
+		j := unweighted.Nearest(cw.RawRowView(i))
+		if !floats.EqualApprox(cw.RawRowView(i), cu.RawRowView(j), 1e-10) {
+			t.Errorf("unexpected weighted center: got %v, want %v", cw.RawRowView(i), cu.RawRowView(j))
+		}
+	}
+
+	// Points with zero weight do not move the centers.
+	weights = make([]float64, n)
+	for i := 0; i < n/2; i++ {
+		weights[i] = 1
+	}
+	km := KMeans{Src: rand.NewSource(1)}
+	km.Cluster(x, 1, weights)
+	var c mat.Dense
+	km.CentersTo(&c)
+	want := make([]float64, 2)
+	for j := range want {
+		want[j] = Mean(mat.Col(nil, j, x), weights)
+	}
+	if !floats.EqualApprox(c.RawRowView(0), want, 1e-12) {
+		t.Errorf("unexpected center with zero weights: got %v, want %v", c.RawRowView(0), want)
+	}
+}
+
+func TestKMeansDistance(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(6, 1, []float64{0, 1, 2, 100, 101, 102})
+	km := KMeans{
+		Distance: func(a, b []float64) float64 { return math.Abs(a[0] - b[0]) },
+		Src:      rand.NewSource(1),
+	}
+	km.Cluster(x, 2, nil)
+	assign := km.Assignments(make([]int, 6))
+	if assign[0] != assign[1] || assign[1] != assign[2] || assign[3] != assign[4] || assign[4] != assign[5] || assign[0] == assign[3] {
+		t.Errorf("unexpected assignments: %v", assign)
+	}
+	if got := km.Inertia(); got != 4 {
+		t.Errorf("unexpected inertia: got %v, want 4", got)
+	}
+
+	// Every point as its own cluster has zero inertia.
+	km.Cluster(x, 6, nil)
+	if got := km.Inertia(); got != 0 {
+		t.Errorf("unexpected inertia with k=n: got %v, want 0", got)
+	}
+}
+
+func TestKMeansPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6})
+	km := KMeans{Src: rand.NewSource(1)}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "Inertia before Cluster", fn: func() { km.Inertia() }},
+		{name: "zero k", fn: func() { km.Cluster(x, 0, nil) }},
+		{name: "k too large", fn: func() { km.Cluster(x, 4, nil) }},
+		{name: "weights length", fn: func() { km.Cluster(x, 2, []float64{1, 1}) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+	km.Cluster(x, 2, nil)
+	if !panics(func() { km.Nearest([]float64{1}) }) {
+		t.Error("expected panic for Nearest length mismatch")
+	}
+	if !panics(func() { km.Assignments(make([]int, 2)) }) {
+		t.Error("expected panic for Assignments length mismatch")
+	}
+	if !panics(func() { km.CentersTo(mat.NewDense(3, 2, nil)) }) {
+		t.Error("expected panic for CentersTo shape mismatch")
+	}
+}
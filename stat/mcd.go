@@ -0,0 +1,418 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+)
+
+const (
+	defaultMCDStarts   = 500
+	defaultMCDQuantile = 0.975
+
+	// mcdCandidates is the number of initial subsets refined to
+	// convergence after two concentration steps.
+	mcdCandidates = 10
+)
+
+// MinCovDet is a type for computing the Minimum Covariance Determinant (MCD)
+// robust estimate of location and scatter of the rows of a matrix. The MCD
+// estimate is the mean and covariance of the subset of h observations whose
+// covariance matrix has the smallest determinant, so a fraction of up to
+// 1-h/n arbitrarily contaminated observations does not break down the
+// estimate. The subset is found with the FAST-MCD algorithm.
+//  Rousseeuw, P. J. and Van Driessen, K. "A fast algorithm for the minimum
+//  covariance determinant estimator." Technometrics 41.3 (1999): 212-223.
+//
+// The exported fields of MinCovDet configure the estimate and are read by
+// Fit. The results are only valid after a successful call to Fit.
+type MinCovDet struct {
+	// SupportFraction is the fraction h/n of the observations used
+	// to compute the raw MCD estimate. It must be in [0.5, 1]. If
+	// SupportFraction is zero, h is (n+d+1)/2, giving the maximal
+	// breakdown point.
+	SupportFraction float64
+
+	// Starts is the number of random initial subsets. If Starts
+	// is zero, 500 are used.
+	Starts int
+
+	// Quantile is the quantile of the χ² distribution with d degrees
+	// of freedom above which the squared Mahalanobis distance of an
+	// observation flags it as an outlier, both for reweighting the
+	// raw estimate and in the final result. If Quantile is zero,
+	// 0.975 is used.
+	Quantile float64
+
+	// Src is the source of randomness for the initial subsets. If
+	// Src is nil, the global source of golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	d           int
+	rawLocation []float64
+	rawCov      mat.SymDense
+	location    []float64
+	cov         mat.SymDense
+	support     []bool
+	dist        []float64
+	outliers    []bool
+	ok          bool
+}
+
+// Fit computes the reweighted MCD estimate of the location and covariance of
+// the rows of the n×d matrix x.
+//
+// The raw MCD covariance is scaled to be consistent at the normal
+// distribution. Observations whose squared Mahalanobis distance under the raw
+// estimate exceeds the Quantile of the χ²_d distribution are then given zero
+// weight and the location and covariance are recomputed from the remaining
+// observations, improving the efficiency of the estimate. The reweighted
+// covariance is also scaled for consistency at the normal distribution.
+//
+// Fit returns false if the covariance of the best subset is singular, which
+// happens when more than h observations lie on a hyperplane. Fit will panic if
+// n is not greater than d, or if the settings are invalid.
+func (m *MinCovDet) Fit(x mat.Matrix) (ok bool) {
+	n, d := x.Dims()
+	if n <= d {
+		panic("stat: too few observations for MCD")
+	}
+	h := (n + d + 1) / 2
+	if m.SupportFraction != 0 {
+		if m.SupportFraction < 0.5 || m.SupportFraction > 1 {
+			panic("stat: MCD support fraction out of range")
+		}
+		h = int(math.Ceil(m.SupportFraction * float64(n)))
+		if h <= d {
+			h = d + 1
+		}
+	}
+	starts := m.Starts
+	if starts == 0 {
+		starts = defaultMCDStarts
+	}
+	quantile := m.Quantile
+	if quantile == 0 {
+		quantile = defaultMCDQuantile
+	}
+	if starts < 0 || !(0 < quantile && quantile < 1) {
+		panic("stat: invalid MCD setting")
+	}
+	m.ok = false
+	m.d = d
+
+	xd := mat.DenseCopyOf(x)
+	var best *mcdSubset
+	if h == n {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		best = newMCDSubset(xd, all)
+	} else {
+		best = m.search(xd, h, starts)
+	}
+	if best.logDet == math.Inf(-1) {
+		return false
+	}
+
+	// Scale the raw covariance to be consistent at the normal
+	// distribution, correcting for the covariance of the best
+	// subset being that of the h observations nearest the center.
+	//  Croux, C. and Haesbroeck, G. "Influence function and efficiency of
+	//  the minimum covariance determinant scatter matrix estimator."
+	//  Journal of Multivariate Analysis 71.2 (1999): 161-190.
+	m.rawLocation = best.mean
+	m.rawCov.ReuseAsSym(d)
+	m.rawCov.ScaleSym(truncationCorrection(float64(h)/float64(n), d), best.cov)
+	m.support = make([]bool, n)
+	for _, i := range best.idx {
+		m.support[i] = true
+	}
+
+	// Reweight the observations.
+	cutoff := chiSquaredQuantile(quantile, d)
+	var rawChol mat.Cholesky
+	if !rawChol.Factorize(&m.rawCov) {
+		return false
+	}
+	dist := make([]float64, n)
+	mahalanobisSqTo(dist, xd, m.rawLocation, &rawChol)
+	var keep []int
+	for i, v := range dist {
+		if v <= cutoff {
+			keep = append(keep, i)
+		}
+	}
+	final := newMCDSubset(xd, keep)
+	if final.logDet == math.Inf(-1) {
+		return false
+	}
+	m.location = final.mean
+	m.cov.ReuseAsSym(d)
+	m.cov.ScaleSym(truncationCorrection(quantile, d), final.cov)
+
+	m.dist = dist
+	final.distances(m.dist, xd)
+	m.outliers = make([]bool, n)
+	for i, v := range m.dist {
+		m.outliers[i] = v > cutoff
+	}
+	m.ok = true
+	return true
+}
+
+// search returns the h-subset of the rows of x with the smallest covariance
+// determinant found from the given number of random starts.
+func (m *MinCovDet) search(x *mat.Dense, h, starts int) *mcdSubset {
+	n, d := x.Dims()
+	perm := rand.Perm
+	if m.Src != nil {
+		perm = rand.New(m.Src).Perm
+	}
+	dist := make([]float64, n)
+	order := make([]int, n)
+
+	var candidates []*mcdSubset
+	for s := 0; s < starts; s++ {
+		// Draw a random (d+1)-subset, extending it while its
+		// covariance is singular.
+		p := perm(n)
+		size := d + 1
+		sub := newMCDSubset(x, p[:size])
+		for sub.logDet == math.Inf(-1) && size < n {
+			size++
+			sub = newMCDSubset(x, p[:size])
+		}
+		if sub.logDet == math.Inf(-1) {
+			// All observations lie on a hyperplane.
+			sub.idx = sub.idx[:h]
+			return sub
+		}
+		for step := 0; step < 2 && sub.logDet != math.Inf(-1); step++ {
+			sub = sub.concentrate(x, h, dist, order)
+		}
+		if sub.logDet == math.Inf(-1) {
+			return sub
+		}
+		candidates = append(candidates, sub)
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].logDet < candidates[j].logDet })
+		if len(candidates) > mcdCandidates {
+			candidates = candidates[:mcdCandidates]
+		}
+	}
+
+	var best *mcdSubset
+	for _, sub := range candidates {
+		for {
+			next := sub.concentrate(x, h, dist, order)
+			if next.logDet >= sub.logDet {
+				break
+			}
+			sub = next
+		}
+		if best == nil || sub.logDet < best.logDet {
+			best = sub
+		}
+		if best.logDet == math.Inf(-1) {
+			break
+		}
+	}
+	return best
+}
+
+// mcdSubset holds the mean and covariance of a subset of observations.
+type mcdSubset struct {
+	idx    []int
+	mean   []float64
+	cov    *mat.SymDense
+	chol   mat.Cholesky
+	logDet float64
+}
+
+// newMCDSubset returns the mean and maximum likelihood covariance of the rows
+// of x indexed by idx. If the covariance is singular, the log determinant of
+// the returned subset is -Inf.
+func newMCDSubset(x *mat.Dense, idx []int) *mcdSubset {
+	_, d := x.Dims()
+	s := &mcdSubset{idx: append([]int(nil), idx...)}
+	s.mean = make([]float64, d)
+	for _, i := range idx {
+		floats.Add(s.mean, x.RawRowView(i))
+	}
+	floats.Scale(1/float64(len(idx)), s.mean)
+
+	s.cov = mat.NewSymDense(d, nil)
+	diff := make([]float64, d)
+	for _, i := range idx {
+		floats.SubTo(diff, x.RawRowView(i), s.mean)
+		s.cov.SymRankOne(s.cov, 1, mat.NewVecDense(d, diff))
+	}
+	s.cov.ScaleSym(1/float64(len(idx)), s.cov)
+
+	if s.chol.Factorize(s.cov) {
+		s.logDet = s.chol.LogDet()
+	} else {
+		s.logDet = math.Inf(-1)
+	}
+	return s
+}
+
+// distances stores the squared Mahalanobis distances of the rows of x from
+// the subset mean under the subset covariance in dst.
+func (s *mcdSubset) distances(dst []float64, x *mat.Dense) {
+	mahalanobisSqTo(dst, x, s.mean, &s.chol)
+}
+
+// mahalanobisSqTo stores the squared Mahalanobis distances of the rows of x
+// from mean under the covariance with the Cholesky decomposition chol in dst.
+func mahalanobisSqTo(dst []float64, x *mat.Dense, mean []float64, chol *mat.Cholesky) {
+	_, d := x.Dims()
+	diff := mat.NewVecDense(d, nil)
+	var z mat.VecDense
+	for i := range dst {
+		floats.SubTo(diff.RawVector().Data, x.RawRowView(i), mean)
+		err := chol.SolveVecTo(&z, diff)
+		if err != nil {
+			dst[i] = math.Inf(1)
+			continue
+		}
+		dst[i] = mat.Dot(diff, &z)
+	}
+}
+
+// concentrate performs a concentration step, returning the subset of the h
+// observations nearest to s in Mahalanobis distance. The dist and order
+// slices are used as working space.
+func (s *mcdSubset) concentrate(x *mat.Dense, h int, dist []float64, order []int) *mcdSubset {
+	s.distances(dist, x)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return dist[order[i]] < dist[order[j]] })
+	idx := append([]int(nil), order[:h]...)
+	sort.Ints(idx)
+	return newMCDSubset(x, idx)
+}
+
+// chiSquaredQuantile returns the p quantile of the χ² distribution with k
+// degrees of freedom.
+func chiSquaredQuantile(p float64, k int) float64 {
+	return 2 * mathext.GammaIncRegInv(float64(k)/2, p)
+}
+
+// truncationCorrection returns the factor that makes the covariance of the
+// fraction p of observations of a d-dimensional normal distribution nearest
+// its mean in Mahalanobis distance consistent for the covariance of the
+// distribution.
+func truncationCorrection(p float64, d int) float64 {
+	if p == 1 {
+		return 1
+	}
+	return p / mathext.GammaIncReg(float64(d+2)/2, chiSquaredQuantile(p, d)/2)
+}
+
+// LocationTo returns the reweighted MCD estimate of the location. If dst is
+// not nil it is used to store the location and returned. LocationTo will panic
+// if the receiver does not contain a successful fit or dst is not nil and its
+// length is not the number of variables.
+func (m *MinCovDet) LocationTo(dst []float64) []float64 {
+	return m.copyVec(dst, m.location)
+}
+
+// CovarianceTo stores the reweighted MCD estimate of the covariance matrix
+// into dst. If dst is empty, CovarianceTo will resize dst to be d×d. When dst
+// is non-empty, CovarianceTo will panic if dst is not d×d. CovarianceTo will
+// also panic if the receiver does not contain a successful fit.
+func (m *MinCovDet) CovarianceTo(dst *mat.SymDense) {
+	m.copySym(dst, &m.cov)
+}
+
+// RawLocationTo returns the raw MCD estimate of the location, the mean of the
+// best h-subset, with the same semantics as LocationTo.
+func (m *MinCovDet) RawLocationTo(dst []float64) []float64 {
+	return m.copyVec(dst, m.rawLocation)
+}
+
+// RawCovarianceTo stores the raw MCD estimate of the covariance, the scaled
+// covariance of the best h-subset, into dst with the same semantics as
+// CovarianceTo.
+func (m *MinCovDet) RawCovarianceTo(dst *mat.SymDense) {
+	m.copySym(dst, &m.rawCov)
+}
+
+// Support returns whether each observation is in the best h-subset. If dst is
+// not nil it is used to store the result and returned. Support will panic if
+// the receiver does not contain a successful fit or dst is not nil and its
+// length is not the number of observations.
+func (m *MinCovDet) Support(dst []bool) []bool {
+	return m.copyBool(dst, m.support)
+}
+
+// Distances returns the squared Mahalanobis distance of each observation
+// under the reweighted estimate. If dst is not nil it is used to store the
+// distances and returned. Distances will panic if the receiver does not
+// contain a successful fit or dst is not nil and its length is not the number
+// of observations.
+func (m *MinCovDet) Distances(dst []float64) []float64 {
+	return m.copyVec(dst, m.dist)
+}
+
+// Outliers returns whether each observation is an outlier, that is, whether
+// its squared Mahalanobis distance under the reweighted estimate exceeds the
+// Quantile of the χ²_d distribution. If dst is not nil it is used to store the
+// result and returned. Outliers will panic if the receiver does not contain a
+// successful fit or dst is not nil and its length is not the number of
+// observations.
+func (m *MinCovDet) Outliers(dst []bool) []bool {
+	return m.copyBool(dst, m.outliers)
+}
+
+func (m *MinCovDet) copyVec(dst, src []float64) []float64 {
+	if !m.ok {
+		panic("stat: use of unfitted MCD estimate")
+	}
+	if dst == nil {
+		dst = make([]float64, len(src))
+	}
+	if len(dst) != len(src) {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, src)
+	return dst
+}
+
+func (m *MinCovDet) copyBool(dst, src []bool) []bool {
+	if !m.ok {
+		panic("stat: use of unfitted MCD estimate")
+	}
+	if dst == nil {
+		dst = make([]bool, len(src))
+	}
+	if len(dst) != len(src) {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, src)
+	return dst
+}
+
+func (m *MinCovDet) copySym(dst, src *mat.SymDense) {
+	if !m.ok {
+		panic("stat: use of unfitted MCD estimate")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(m.d)
+	} else if dst.SymmetricDim() != m.d {
+		panic(mat.ErrShape)
+	}
+	dst.CopySym(src)
+}
@@ -0,0 +1,196 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMinCovDet(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		clean = 300
+		bad   = 60
+		n     = clean + bad
+	)
+	trueCov := mat.NewSymDense(3, []float64{
+		4, 1, 0,
+		1, 2, -0.5,
+		0, -0.5, 1,
+	})
+	var chol mat.Cholesky
+	if !chol.Factorize(trueCov) {
+		t.Fatal("bad covariance")
+	}
+	var l mat.TriDense
+	chol.LTo(&l)
+	trueMean := []float64{1, -2, 3}
+
+	x := mat.NewDense(n, 3, nil)
+	z := mat.NewVecDense(3, nil)
+	var v mat.VecDense
+	for i := 0; i < clean; i++ {
+		for j := 0; j < 3; j++ {
+			z.SetVec(j, rnd.NormFloat64())
+		}
+		v.MulVec(&l, z)
+		for j := 0; j < 3; j++ {
+			x.Set(i, j, trueMean[j]+v.AtVec(j))
+		}
+	}
+	// The contaminating observations form a tight cluster far from
+	// the clean data.
+	for i := clean; i < n; i++ {
+		x.SetRow(i, []float64{20 + 0.1*rnd.NormFloat64(), 20 + 0.1*rnd.NormFloat64(), -10 + 0.1*rnd.NormFloat64()})
+	}
+
+	var classic, cleanCov mat.SymDense
+	CovarianceMatrix(&classic, x, nil)
+	if math.Abs(classic.At(0, 0)-trueCov.At(0, 0)) < 10 {
+		t.Fatal("contamination does not affect the classical covariance")
+	}
+	CovarianceMatrix(&cleanCov, x.Slice(0, clean, 0, 3), nil)
+
+	for k, test := range []MinCovDet{
+		{Src: rand.NewSource(1)},
+		{SupportFraction: 0.75, Starts: 50, Src: rand.NewSource(2)},
+	} {
+		m := test
+		if !m.Fit(x) {
+			t.Fatalf("unexpected failure for test %d", k)
+		}
+		loc := m.LocationTo(nil)
+		if !floats.EqualApprox(loc, trueMean, 0.3) {
+			t.Errorf("unexpected location for test %d: got %v, want %v", k, loc, trueMean)
+		}
+		// The raw estimate is inefficient and its consistency
+		// correction assumes uncontaminated data, so it is only
+		// checked loosely.
+		for _, c := range []struct {
+			name string
+			get  func(*mat.SymDense)
+			tol  float64
+		}{
+			{name: "raw", get: m.RawCovarianceTo, tol: 2},
+			{name: "reweighted", get: m.CovarianceTo, tol: 0.5},
+		} {
+			var cov mat.SymDense
+			c.get(&cov)
+			var diff mat.Dense
+			diff.Sub(&cov, &cleanCov)
+			if mat.Norm(&diff, math.Inf(1)) > c.tol {
+				t.Errorf("unexpected %s covariance for test %d:\ngot  %v\nwant %v",
+					c.name, k, mat.Formatted(&cov), mat.Formatted(&cleanCov))
+			}
+		}
+
+		outliers := m.Outliers(nil)
+		support := m.Support(nil)
+		dist := m.Distances(nil)
+		var falsePositive, inSupport int
+		for i := 0; i < n; i++ {
+			if support[i] {
+				inSupport++
+			}
+			if i >= clean {
+				if !outliers[i] {
+					t.Errorf("contaminated observation %d not flagged for test %d", i, k)
+				}
+				if support[i] {
+					t.Errorf("contaminated observation %d in support for test %d", i, k)
+				}
+				continue
+			}
+			if outliers[i] {
+				falsePositive++
+			}
+			if outliers[i] != (dist[i] > chiSquaredQuantile(defaultMCDQuantile, 3)) {
+				t.Errorf("outlier flag for %d does not match distance %v", i, dist[i])
+			}
+		}
+		if falsePositive > clean/20 {
+			t.Errorf("too many clean observations flagged for test %d: %d of %d", k, falsePositive, clean)
+		}
+		h := (n + 3 + 1) / 2
+		if test.SupportFraction != 0 {
+			h = int(math.Ceil(test.SupportFraction * n))
+		}
+		if inSupport != h {
+			t.Errorf("unexpected support size for test %d: got %d, want %d", k, inSupport, h)
+		}
+	}
+}
+
+func TestMinCovDetClassical(t *testing.T) {
+	t.Parallel()
+	// With a support fraction of one and a quantile that keeps all
+	// the observations, the estimate is the maximum likelihood
+	// estimate.
+	rnd := rand.New(rand.NewSource(1))
+	x := mat.NewDense(50, 2, nil)
+	for i := 0; i < 50; i++ {
+		x.SetRow(i, []float64{rnd.NormFloat64(), rnd.NormFloat64()})
+	}
+	m := MinCovDet{SupportFraction: 1, Quantile: 1 - 1e-12}
+	if !m.Fit(x) {
+		t.Fatal("unexpected failure")
+	}
+	var got, want mat.SymDense
+	m.CovarianceTo(&got)
+	CovarianceMatrix(&want, x, nil)
+	want.ScaleSym(49.0/50, &want)
+	if !mat.EqualApprox(&got, &want, 1e-9) {
+		t.Errorf("unexpected covariance:\ngot  %v\nwant %v", mat.Formatted(&got), mat.Formatted(&want))
+	}
+	for j, v := range m.LocationTo(nil) {
+		if mean := Mean(mat.Col(nil, j, x), nil); math.Abs(v-mean) > 1e-14 {
+			t.Errorf("unexpected location of variable %d: got %v, want %v", j, v, mean)
+		}
+	}
+}
+
+func TestMinCovDetExactFit(t *testing.T) {
+	t.Parallel()
+	// More than h observations lie on a line, so the minimum
+	// covariance determinant is zero.
+	x := mat.NewDense(20, 2, nil)
+	for i := 0; i < 20; i++ {
+		x.SetRow(i, []float64{float64(i), 2 * float64(i)})
+	}
+	x.SetRow(0, []float64{5, -3})
+	m := MinCovDet{Src: rand.NewSource(1)}
+	if m.Fit(x) {
+		t.Error("expected failure for exact fit")
+	}
+	if !panics(func() { m.LocationTo(nil) }) {
+		t.Error("expected panic for use of failed fit")
+	}
+}
+
+func TestMinCovDetPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(10, 2, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "too few observations", fn: func() { (&MinCovDet{}).Fit(mat.NewDense(2, 2, nil)) }},
+		{name: "support fraction", fn: func() { (&MinCovDet{SupportFraction: 0.2}).Fit(x) }},
+		{name: "quantile", fn: func() { (&MinCovDet{Quantile: 1}).Fit(x) }},
+		{name: "negative starts", fn: func() { (&MinCovDet{Starts: -1}).Fit(x) }},
+		{name: "unfitted", fn: func() { (&MinCovDet{}).CovarianceTo(&mat.SymDense{}) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
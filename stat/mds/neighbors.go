@@ -0,0 +1,122 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/vptree"
+)
+
+// neighbor is an observation and its Euclidean distance from another
+// observation.
+type neighbor struct {
+	j    int
+	dist float64
+}
+
+// nearestNeighbors returns the k nearest neighbors of each row of x, excluding
+// the row itself, in order of increasing distance. If k is less than the
+// number of rows of x less one, the neighbors are found with a vp-tree built
+// using src.
+func nearestNeighbors(x *mat.Dense, k int, src rand.Source) [][]neighbor {
+	n, _ := x.Dims()
+	neighbors := make([][]neighbor, n)
+	if k >= n-1 {
+		for i := range neighbors {
+			neighbors[i] = make([]neighbor, 0, n-1)
+			for j := 0; j < n; j++ {
+				if j != i {
+					neighbors[i] = append(neighbors[i], neighbor{j: j, dist: math.Sqrt(sqDist(x.RawRowView(i), x.RawRowView(j)))})
+				}
+			}
+			sort.SliceStable(neighbors[i], func(a, b int) bool { return neighbors[i][a].dist < neighbors[i][b].dist })
+		}
+		return neighbors
+	}
+
+	points := make([]vptree.Comparable, n)
+	for i := range points {
+		points[i] = observation{x: x.RawRowView(i), index: i}
+	}
+	tree, err := vptree.New(points, 3, src)
+	if err != nil {
+		panic(err)
+	}
+	// The vp-tree construction reorders points, so
+	// the query index is taken from the observation.
+	for _, q := range points {
+		i := q.(observation).index
+		keep := vptree.NewNKeeper(k + 1)
+		tree.NearestSet(keep, q)
+		for _, c := range keep.Heap {
+			j := c.Comparable.(observation).index
+			if j != i {
+				neighbors[i] = append(neighbors[i], neighbor{j: j, dist: c.Dist})
+			}
+		}
+		// Keep at most k neighbors if the query point was
+		// not returned due to duplicate observations.
+		neighbors[i] = neighbors[i][:min(k, len(neighbors[i]))]
+	}
+	return neighbors
+}
+
+// observation is a row of an input matrix stored in a vp-tree along with its
+// row index.
+type observation struct {
+	x     []float64
+	index int
+}
+
+func (o observation) Distance(c vptree.Comparable) float64 {
+	return math.Sqrt(sqDist(o.x, c.(observation).x))
+}
+
+// affinity is an entry of a sparse row of a matrix of affinities between
+// observations.
+type affinity struct {
+	j int
+	p float64
+}
+
+// symmetrize returns the sparse symmetric matrix with elements combine(a_ij, a_ji)
+// for the non-zero pattern of the union of a and its transpose, where a missing
+// element is zero. The rows of the result are sorted by column.
+func symmetrize(a [][]affinity, combine func(aij, aji float64) float64) [][]affinity {
+	n := len(a)
+	type pair struct{ ij, ji float64 }
+	sym := make([]map[int]*pair, n)
+	for i := range sym {
+		sym[i] = make(map[int]*pair)
+	}
+	get := func(i, j int) *pair {
+		e, ok := sym[i][j]
+		if !ok {
+			e = &pair{}
+			sym[i][j] = e
+		}
+		return e
+	}
+	for i, row := range a {
+		for _, e := range row {
+			get(i, e.j).ij += e.p
+			get(e.j, i).ji += e.p
+		}
+	}
+	s := make([][]affinity, n)
+	for i, m := range sym {
+		s[i] = make([]affinity, 0, len(m))
+		for j, e := range m {
+			s[i] = append(s[i], affinity{j: j, p: combine(e.ij, e.ji)})
+		}
+		sort.Slice(s[i], func(a, b int) bool { return s[i][a].j < s[i][b].j })
+	}
+	return s
+}
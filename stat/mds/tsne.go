@@ -0,0 +1,410 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/barneshut"
+	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// TSNE holds the settings for t-distributed stochastic neighbor embedding,
+// which maps high-dimensional observations to a low-dimensional space for
+// visualization while preserving the local neighborhood structure of the
+// data.
+//  van der Maaten, L. and Hinton, G. "Visualizing data using t-SNE."
+//  Journal of Machine Learning Research 9 (2008).
+//
+// Unless Exact is true, the input affinities are restricted to the nearest
+// neighbors of each observation and the gradient is approximated with the
+// Barnes-Hut algorithm, so that each iteration costs O(n log n) time.
+//  van der Maaten, L. "Accelerating t-SNE using tree-based algorithms."
+//  Journal of Machine Learning Research 15 (2014).
+type TSNE struct {
+	// Dims is the dimension of the embedding. If Dims is
+	// zero, a two-dimensional embedding is computed. The
+	// Barnes-Hut approximation supports two and three
+	// dimensions.
+	Dims int
+
+	// Perplexity is the effective number of neighbors of each
+	// observation used to calibrate the input affinities. If
+	// Perplexity is zero, 30 is used.
+	Perplexity float64
+
+	// LearningRate is the gradient descent step size. If
+	// LearningRate is zero, 200 is used.
+	LearningRate float64
+
+	// Iterations is the number of gradient descent iterations.
+	// If Iterations is zero, 1000 are used. The first 250
+	// iterations use early exaggeration of the input affinities.
+	Iterations int
+
+	// Exact specifies that the exact O(n²) affinities and
+	// gradients are used instead of the Barnes-Hut
+	// approximation.
+	Exact bool
+
+	// Theta is the Barnes-Hut approximation parameter. Larger
+	// values give faster and less accurate gradients. If Theta
+	// is zero, 0.5 is used.
+	Theta float64
+
+	// Src is the source of randomness for the initial
+	// embedding and the construction of the neighbor search
+	// tree. If Src is nil, the global source of
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+const (
+	exaggeration           = 12.0
+	exaggerationIterations = 250
+)
+
+// Embed computes the embedding of the rows of x, storing the coordinates of
+// each observation in the corresponding row of dst, and returns the
+// Kullback-Leibler divergence between the input affinities and the affinities
+// of the embedding. When the Barnes-Hut approximation is used, the divergence
+// is computed from the approximate normalization of the embedding affinities.
+//
+// If dst is empty, Embed will resize dst to be n×Dims. When dst is non-empty,
+// Embed will panic if dst is not n×Dims. Embed will also panic if x has fewer
+// than two rows, if Perplexity is not less than one fewer than the number of
+// rows of x, if the settings are negative or if the Barnes-Hut approximation is used with
+// Dims other than two or three.
+func (t TSNE) Embed(dst *mat.Dense, x mat.Matrix) (kl float64) {
+	n, _ := x.Dims()
+	dims := t.Dims
+	if dims == 0 {
+		dims = 2
+	}
+	perp := t.Perplexity
+	if perp == 0 {
+		perp = 30
+	}
+	rate := t.LearningRate
+	if rate == 0 {
+		rate = 200
+	}
+	iters := t.Iterations
+	if iters == 0 {
+		iters = 1000
+	}
+	theta := t.Theta
+	if theta == 0 {
+		theta = 0.5
+	}
+	switch {
+	case n < 2:
+		panic("mds: too few observations")
+	case dims < 0 || perp < 0 || rate < 0 || iters < 0 || theta < 0:
+		panic("mds: negative t-SNE setting")
+	case perp >= float64(n-1):
+		panic("mds: perplexity too large for number of observations")
+	case !t.Exact && dims != 2 && dims != 3:
+		panic("mds: Barnes-Hut t-SNE requires two or three dimensions")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, dims)
+	} else if r, c := dst.Dims(); r != n || c != dims {
+		panic(mat.ErrShape)
+	}
+
+	var rnd *rand.Rand
+	if t.Src != nil {
+		rnd = rand.New(t.Src)
+	}
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+
+	p := t.affinities(x, perp)
+
+	y := make([][]float64, n)
+	for i := range y {
+		y[i] = dst.RawRowView(i)
+		for j := range y[i] {
+			y[i][j] = 1e-4 * normFloat64()
+		}
+	}
+
+	grad := mat.NewDense(n, dims, nil)
+	update := mat.NewDense(n, dims, nil)
+	gains := mat.NewDense(n, dims, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < dims; j++ {
+			gains.Set(i, j, 1)
+		}
+	}
+	rep := newRepulsion(y, t.Exact, theta)
+	var z float64
+	for it := 0; it < iters; it++ {
+		exag, momentum := 1.0, 0.8
+		if it < exaggerationIterations {
+			exag, momentum = exaggeration, 0.5
+		}
+		z = rep.forces(grad)
+		for i, row := range p {
+			g := grad.RawRowView(i)
+			for k := range g {
+				g[k] /= -z
+			}
+			for _, e := range row {
+				w := exag * e.p / (1 + sqDist(y[i], y[e.j]))
+				for k := range g {
+					g[k] += w * (y[i][k] - y[e.j][k])
+				}
+			}
+			u := update.RawRowView(i)
+			gain := gains.RawRowView(i)
+			for k, gk := range g {
+				gk *= 4
+				// Adapt the per-parameter gains as in the
+				// delta-bar-delta rule.
+				if (gk > 0) != (u[k] > 0) {
+					gain[k] += 0.2
+				} else {
+					gain[k] = math.Max(0.8*gain[k], 0.01)
+				}
+				u[k] = momentum*u[k] - rate*gain[k]*gk
+			}
+		}
+		for i := range y {
+			u := update.RawRowView(i)
+			for k := range y[i] {
+				y[i][k] += u[k]
+			}
+		}
+		center(y)
+	}
+
+	z = rep.forces(grad)
+	for i, row := range p {
+		for _, e := range row {
+			if e.p == 0 {
+				continue
+			}
+			q := 1 / (1 + sqDist(y[i], y[e.j])) / z
+			kl += e.p * math.Log(e.p/q)
+		}
+	}
+	return kl
+}
+
+// affinities returns the symmetrized input affinities of the rows of x as
+// sparse rows. Each observation has affinities with its 3×perp nearest
+// neighbors, or with all other observations if t.Exact is true.
+func (t TSNE) affinities(x mat.Matrix, perp float64) [][]affinity {
+	n, _ := x.Dims()
+	xd := mat.DenseCopyOf(x)
+
+	k := n - 1
+	if !t.Exact {
+		k = min(n-1, int(3*perp))
+	}
+	neighbors := nearestNeighbors(xd, k, t.Src)
+
+	// Calibrate the conditional affinities to the perplexity.
+	rows := make([][]affinity, n)
+	for i, row := range neighbors {
+		rows[i] = make([]affinity, len(row))
+		for k, e := range row {
+			rows[i][k] = affinity{j: e.j, p: e.dist * e.dist}
+		}
+		conditionalAffinities(rows[i], math.Log(perp))
+	}
+
+	return symmetrize(rows, func(a, b float64) float64 { return (a + b) / float64(2*n) })
+}
+
+// conditionalAffinities replaces the squared distances in row with the
+// conditional affinities of a Gaussian kernel whose precision is found by
+// bisection so that the entropy of the affinities is logPerp.
+func conditionalAffinities(row []affinity, logPerp float64) {
+	dist := make([]float64, len(row))
+	minDist := math.Inf(1)
+	for k, e := range row {
+		dist[k] = e.p
+		minDist = math.Min(minDist, e.p)
+	}
+	beta := 1.0
+	lo, hi := 0.0, math.Inf(1)
+	for iter := 0; iter < 200; iter++ {
+		var sum, sumDP float64
+		for k, d := range dist {
+			// Shift distances by the minimum for stability.
+			v := math.Exp(-beta * (d - minDist))
+			row[k].p = v
+			sum += v
+			sumDP += (d - minDist) * v
+		}
+		entropy := math.Log(sum) + beta*sumDP/sum
+		for k := range row {
+			row[k].p /= sum
+		}
+		diff := entropy - logPerp
+		if math.Abs(diff) < 1e-5 {
+			break
+		}
+		if diff > 0 {
+			lo = beta
+			if math.IsInf(hi, 1) {
+				beta *= 2
+			} else {
+				beta = (beta + hi) / 2
+			}
+		} else {
+			hi = beta
+			beta = (beta + lo) / 2
+		}
+	}
+}
+
+// repulsion computes the repulsive forces between the points of an embedding.
+type repulsion struct {
+	y     [][]float64
+	exact bool
+	theta float64
+
+	particles2 []barneshut.Particle2
+	particles3 []barneshut.Particle3
+}
+
+func newRepulsion(y [][]float64, exact bool, theta float64) *repulsion {
+	r := &repulsion{y: y, exact: exact, theta: theta}
+	if exact {
+		return r
+	}
+	switch len(y[0]) {
+	case 2:
+		r.particles2 = make([]barneshut.Particle2, len(y))
+		for i, v := range y {
+			r.particles2[i] = embedded(v)
+		}
+	case 3:
+		r.particles3 = make([]barneshut.Particle3, len(y))
+		for i, v := range y {
+			r.particles3[i] = embedded(v)
+		}
+	}
+	return r
+}
+
+// forces stores in the rows of dst the unnormalized repulsive forces
+//  Σ_j w_ij² (y_i - y_j)
+// acting on each point, where w_ij = 1/(1+‖y_i - y_j‖²), and returns the
+// normalization Z = Σ_{i≠j} w_ij.
+func (r *repulsion) forces(dst *mat.Dense) (z float64) {
+	switch {
+	case r.particles2 != nil:
+		plane, err := barneshut.NewPlane(r.particles2)
+		if err != nil {
+			break
+		}
+		for i, p := range r.particles2 {
+			f := plane.ForceOn(p, r.theta, func(p1, p2 barneshut.Particle2, _, m2 float64, v r2.Vec) r2.Vec {
+				if p2 != nil && p1.(*embeddedPoint) == p2.(*embeddedPoint) {
+					return r2.Vec{}
+				}
+				w := 1 / (1 + r2.Dot(v, v))
+				z += m2 * w
+				return r2.Scale(-m2*w*w, v)
+			})
+			dst.SetRow(i, []float64{f.X, f.Y})
+		}
+		return z
+	case r.particles3 != nil:
+		volume, err := barneshut.NewVolume(r.particles3)
+		if err != nil {
+			break
+		}
+		for i, p := range r.particles3 {
+			f := volume.ForceOn(p, r.theta, func(p1, p2 barneshut.Particle3, _, m2 float64, v r3.Vec) r3.Vec {
+				if p2 != nil && p1.(*embeddedPoint) == p2.(*embeddedPoint) {
+					return r3.Vec{}
+				}
+				w := 1 / (1 + r3.Dot(v, v))
+				z += m2 * w
+				return r3.Scale(-m2*w*w, v)
+			})
+			dst.SetRow(i, []float64{f.X, f.Y, f.Z})
+		}
+		return z
+	}
+
+	// Compute the forces exactly, either by request or because the
+	// embedding is too spread out for the Barnes-Hut tree.
+	dst.Zero()
+	for i, yi := range r.y {
+		fi := dst.RawRowView(i)
+		for j := i + 1; j < len(r.y); j++ {
+			yj := r.y[j]
+			w := 1 / (1 + sqDist(yi, yj))
+			z += 2 * w
+			fj := dst.RawRowView(j)
+			for k := range fi {
+				v := w * w * (yi[k] - yj[k])
+				fi[k] += v
+				fj[k] -= v
+			}
+		}
+	}
+	return z
+}
+
+// embeddedPoint is a point of an embedding used as a unit mass particle
+// by the Barnes-Hut approximation.
+type embeddedPoint struct {
+	y []float64
+}
+
+func embedded(y []float64) *embeddedPoint { return &embeddedPoint{y: y} }
+
+func (p *embeddedPoint) Coord2() r2.Vec { return r2.Vec{X: p.y[0], Y: p.y[1]} }
+func (p *embeddedPoint) Coord3() r3.Vec { return r3.Vec{X: p.y[0], Y: p.y[1], Z: p.y[2]} }
+func (p *embeddedPoint) Mass() float64  { return 1 }
+
+// center translates the points so that their mean is at the origin.
+func center(y [][]float64) {
+	mean := make([]float64, len(y[0]))
+	for _, v := range y {
+		for k, c := range v {
+			mean[k] += c
+		}
+	}
+	for k := range mean {
+		mean[k] /= float64(len(y))
+	}
+	for _, v := range y {
+		for k := range v {
+			v[k] -= mean[k]
+		}
+	}
+}
+
+// sqDist returns the squared Euclidean distance between a and b.
+func sqDist(a, b []float64) float64 {
+	var d float64
+	for i, v := range a {
+		v -= b[i]
+		d += v * v
+	}
+	return d
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,134 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// clusteredData returns points drawn around well separated centers in dim
+// dimensions and the index of the center of each point.
+func clusteredData(rnd *rand.Rand, clusters, perCluster, dim int) (*mat.Dense, []int) {
+	x := mat.NewDense(clusters*perCluster, dim, nil)
+	labels := make([]int, clusters*perCluster)
+	for c := 0; c < clusters; c++ {
+		center := make([]float64, dim)
+		for k := range center {
+			center[k] = 10 * rnd.NormFloat64()
+		}
+		for i := 0; i < perCluster; i++ {
+			row := c*perCluster + i
+			labels[row] = c
+			for k, v := range center {
+				x.Set(row, k, v+rnd.NormFloat64())
+			}
+		}
+	}
+	return x, labels
+}
+
+func TestTSNE(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, labels := clusteredData(rnd, 4, 40, 10)
+	n, _ := x.Dims()
+
+	for _, test := range []TSNE{
+		{Exact: true, Perplexity: 10, Iterations: 500, Src: rand.NewSource(1)},
+		{Perplexity: 10, Iterations: 500, Src: rand.NewSource(1)},
+		{Dims: 3, Perplexity: 10, Iterations: 500, Src: rand.NewSource(1)},
+	} {
+		var y mat.Dense
+		kl := test.Embed(&y, x)
+		dims := test.Dims
+		if dims == 0 {
+			dims = 2
+		}
+		if r, c := y.Dims(); r != n || c != dims {
+			t.Fatalf("unexpected embedding shape for %+v: got %d×%d, want %d×%d", test, r, c, n, dims)
+		}
+		if !(kl > 0) || math.IsInf(kl, 0) || kl > 2 {
+			t.Errorf("unexpected KL divergence for %+v: %v", test, kl)
+		}
+
+		// The nearest neighbor of each point in the embedding
+		// should belong to the same cluster.
+		var wrong int
+		for i := 0; i < n; i++ {
+			best := -1
+			bestDist := math.Inf(1)
+			for j := 0; j < n; j++ {
+				if j == i {
+					continue
+				}
+				if d := sqDist(y.RawRowView(i), y.RawRowView(j)); d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			if labels[best] != labels[i] {
+				wrong++
+			}
+		}
+		if wrong > n/50 {
+			t.Errorf("too many points with nearest neighbor in another cluster for %+v: %d of %d", test, wrong, n)
+		}
+	}
+}
+
+func TestConditionalAffinities(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, perp := range []float64{2, 5, 20} {
+		row := make([]affinity, 60)
+		for k := range row {
+			row[k] = affinity{j: k, p: 100 * rnd.Float64()}
+		}
+		conditionalAffinities(row, math.Log(perp))
+		var sum, entropy float64
+		for _, e := range row {
+			sum += e.p
+			if e.p > 0 {
+				entropy -= e.p * math.Log(e.p)
+			}
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("affinities for perplexity %v do not sum to one: %v", perp, sum)
+		}
+		if got := math.Exp(entropy); math.Abs(got-perp) > 1e-3 {
+			t.Errorf("unexpected perplexity: got %v, want %v", got, perp)
+		}
+	}
+}
+
+func TestTSNEPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(10, 2, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "one observation", fn: func() { TSNE{}.Embed(&mat.Dense{}, mat.NewDense(1, 2, nil)) }},
+		{name: "perplexity too large", fn: func() { TSNE{Perplexity: 9}.Embed(&mat.Dense{}, x) }},
+		{name: "Barnes-Hut in four dimensions", fn: func() { TSNE{Dims: 4, Perplexity: 2}.Embed(&mat.Dense{}, x) }},
+		{name: "wrong destination shape", fn: func() { TSNE{Perplexity: 2}.Embed(mat.NewDense(10, 3, nil), x) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,362 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// UMAP holds the settings for uniform manifold approximation and projection,
+// which embeds observations in a low-dimensional space by constructing a fuzzy
+// topological representation of the k-nearest neighbor graph of the data and
+// optimizing a layout with a similar fuzzy topological structure using
+// stochastic gradient descent.
+//  McInnes, L., Healy, J. and Melville, J. "UMAP: Uniform manifold
+//  approximation and projection for dimension reduction." arXiv:1802.03426
+//  (2018).
+//
+// The layout is initialized randomly.
+type UMAP struct {
+	// Dims is the dimension of the embedding. If Dims is
+	// zero, a two-dimensional embedding is computed.
+	Dims int
+
+	// Neighbors is the number of nearest neighbors used to
+	// construct the fuzzy graph of the data. Larger values
+	// preserve more of the global structure of the data. If
+	// Neighbors is zero, 15 are used.
+	Neighbors int
+
+	// MinDist is the minimum distance between points in the
+	// embedding, controlling how tightly points are packed.
+	// If MinDist is zero, 0.1 is used.
+	MinDist float64
+
+	// Spread is the scale of the embedded points. If Spread
+	// is zero, 1 is used.
+	Spread float64
+
+	// Epochs is the number of optimization epochs. If Epochs
+	// is zero, 500 are used for up to 10000 observations and
+	// 200 for larger inputs.
+	Epochs int
+
+	// LearningRate is the initial step size of the stochastic
+	// gradient descent, which decays linearly to zero. If
+	// LearningRate is zero, 1 is used.
+	LearningRate float64
+
+	// NegativeSamples is the number of negative samples drawn
+	// for each positive sample. If NegativeSamples is zero, 5
+	// are used.
+	NegativeSamples int
+
+	// Src is the source of randomness for the neighbor search,
+	// the initial layout and the optimization. If Src is nil,
+	// the global source of golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// Embed computes the embedding of the rows of x, storing the coordinates of
+// each observation in the corresponding row of dst.
+//
+// If dst is empty, Embed will resize dst to be n×Dims. When dst is non-empty,
+// Embed will panic if dst is not n×Dims. Embed will also panic if x has fewer
+// than two rows, if Neighbors is not less than the number of rows of x or if
+// the settings are negative.
+func (u UMAP) Embed(dst *mat.Dense, x mat.Matrix) {
+	n, _ := x.Dims()
+	dims := u.Dims
+	if dims == 0 {
+		dims = 2
+	}
+	k := u.Neighbors
+	if k == 0 {
+		k = 15
+	}
+	minDist := u.MinDist
+	if minDist == 0 {
+		minDist = 0.1
+	}
+	spread := u.Spread
+	if spread == 0 {
+		spread = 1
+	}
+	epochs := u.Epochs
+	if epochs == 0 {
+		epochs = 500
+		if n > 10000 {
+			epochs = 200
+		}
+	}
+	rate := u.LearningRate
+	if rate == 0 {
+		rate = 1
+	}
+	negRate := u.NegativeSamples
+	if negRate == 0 {
+		negRate = 5
+	}
+	switch {
+	case n < 2:
+		panic("mds: too few observations")
+	case dims < 0 || k < 0 || minDist < 0 || spread < 0 || epochs < 0 || rate < 0 || negRate < 0:
+		panic("mds: negative UMAP setting")
+	case k >= n:
+		panic("mds: too many neighbors for number of observations")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, dims)
+	} else if r, c := dst.Dims(); r != n || c != dims {
+		panic(mat.ErrShape)
+	}
+
+	float64n := rand.Float64
+	intn := rand.Intn
+	if u.Src != nil {
+		rnd := rand.New(u.Src)
+		float64n = rnd.Float64
+		intn = rnd.Intn
+	}
+
+	graph := fuzzySimplicialSet(mat.DenseCopyOf(x), k, u.Src)
+	a, b := fitCurve(spread, minDist)
+
+	y := make([][]float64, n)
+	for i := range y {
+		y[i] = dst.RawRowView(i)
+		for j := range y[i] {
+			y[i][j] = 20*float64n() - 10
+		}
+	}
+
+	// Sample each edge of the graph with a frequency proportional
+	// to its weight, dropping edges too weak to be sampled.
+	type edge struct {
+		i, j int
+
+		epochsPerSample, nextSample       float64
+		epochsPerNegSample, nextNegSample float64
+	}
+	var maxW float64
+	for _, row := range graph {
+		for _, e := range row {
+			maxW = math.Max(maxW, e.p)
+		}
+	}
+	var edges []edge
+	for i, row := range graph {
+		for _, e := range row {
+			if e.p < maxW/float64(epochs) {
+				continue
+			}
+			per := maxW / e.p
+			edges = append(edges, edge{
+				i: i, j: e.j,
+				epochsPerSample:    per,
+				nextSample:         per,
+				epochsPerNegSample: per / float64(negRate),
+				nextNegSample:      per / float64(negRate),
+			})
+		}
+	}
+
+	const clip = 4.0
+	for epoch := 0; epoch < epochs; epoch++ {
+		alpha := rate * (1 - float64(epoch)/float64(epochs))
+		for ei := range edges {
+			e := &edges[ei]
+			if e.nextSample > float64(epoch) {
+				continue
+			}
+			yi, yj := y[e.i], y[e.j]
+			d2 := sqDist(yi, yj)
+			if d2 > 0 {
+				coef := -2 * a * b * math.Pow(d2, b-1) / (1 + a*math.Pow(d2, b))
+				for c := range yi {
+					g := clamp(coef*(yi[c]-yj[c]), clip) * alpha
+					yi[c] += g
+					yj[c] -= g
+				}
+			}
+			e.nextSample += e.epochsPerSample
+
+			negs := int((float64(epoch) - e.nextNegSample) / e.epochsPerNegSample)
+			for s := 0; s < negs; s++ {
+				kk := intn(n)
+				if kk == e.i {
+					continue
+				}
+				yk := y[kk]
+				d2 := sqDist(yi, yk)
+				for c := range yi {
+					g := clip
+					if d2 > 0 {
+						coef := 2 * b / ((0.001 + d2) * (1 + a*math.Pow(d2, b)))
+						g = clamp(coef*(yi[c]-yk[c]), clip)
+					}
+					yi[c] += g * alpha
+				}
+			}
+			e.nextNegSample += float64(negs) * e.epochsPerNegSample
+		}
+	}
+}
+
+// fuzzySimplicialSet returns the symmetric fuzzy membership strengths of the
+// edges of the k-nearest neighbor graph of the rows of x.
+func fuzzySimplicialSet(x *mat.Dense, k int, src rand.Source) [][]affinity {
+	neighbors := nearestNeighbors(x, k, src)
+	target := math.Log2(float64(k))
+
+	var meanAll float64
+	var count int
+	for _, row := range neighbors {
+		for _, e := range row {
+			meanAll += e.dist
+			count++
+		}
+	}
+	meanAll /= float64(count)
+
+	rows := make([][]affinity, len(neighbors))
+	for i, row := range neighbors {
+		// The distance to the nearest neighbor is subtracted so that
+		// each point is connected to at least one other point with
+		// full strength.
+		var rho, mean float64
+		for _, e := range row {
+			if rho == 0 && e.dist > 0 {
+				rho = e.dist
+			}
+			mean += e.dist
+		}
+		mean /= float64(len(row))
+
+		// Find the kernel width by bisection so that the total
+		// membership strength is log2(k).
+		lo, hi := 0.0, math.Inf(1)
+		sigma := 1.0
+		for iter := 0; iter < 64; iter++ {
+			var sum float64
+			for _, e := range row {
+				sum += math.Exp(-math.Max(0, e.dist-rho) / sigma)
+			}
+			if math.Abs(sum-target) < 1e-5 {
+				break
+			}
+			if sum > target {
+				hi = sigma
+				sigma = (lo + hi) / 2
+			} else {
+				lo = sigma
+				if math.IsInf(hi, 1) {
+					sigma *= 2
+				} else {
+					sigma = (lo + hi) / 2
+				}
+			}
+		}
+		const minScale = 1e-3
+		if rho > 0 {
+			sigma = math.Max(sigma, minScale*mean)
+		} else {
+			sigma = math.Max(sigma, minScale*meanAll)
+		}
+
+		rows[i] = make([]affinity, len(row))
+		for c, e := range row {
+			rows[i][c] = affinity{j: e.j, p: math.Exp(-math.Max(0, e.dist-rho) / sigma)}
+		}
+	}
+
+	// Combine the directed memberships by fuzzy set union.
+	return symmetrize(rows, func(a, b float64) float64 { return a + b - a*b })
+}
+
+// fitCurve returns the parameters a and b of the curve 1/(1+a d^(2b)) that
+// best fits, in the least squares sense, the membership strength of points at
+// distance d in the embedding given by
+//  1                          if d < minDist,
+//  exp(-(d-minDist)/spread)   otherwise,
+// using the Levenberg-Marquardt algorithm.
+func fitCurve(spread, minDist float64) (a, b float64) {
+	const samples = 300
+	xs := make([]float64, samples)
+	ys := make([]float64, samples)
+	for i := range xs {
+		x := 3 * spread * float64(i) / (samples - 1)
+		xs[i] = x
+		ys[i] = 1
+		if x >= minDist {
+			ys[i] = math.Exp(-(x - minDist) / spread)
+		}
+	}
+	residual := func(a, b float64) float64 {
+		var sum float64
+		for i, x := range xs {
+			r := 1/(1+a*math.Pow(x, 2*b)) - ys[i]
+			sum += r * r
+		}
+		return sum
+	}
+
+	a, b = 1, 1
+	lambda := 1e-3
+	cost := residual(a, b)
+	for iter := 0; iter < 500; iter++ {
+		// Form the normal equations of the linearized problem.
+		var jaa, jab, jbb, ga, gb float64
+		for i, x := range xs {
+			if x == 0 {
+				continue
+			}
+			u := math.Pow(x, 2*b)
+			g := 1 / (1 + a*u)
+			r := g - ys[i]
+			da := -u * g * g
+			db := -2 * a * u * math.Log(x) * g * g
+			jaa += da * da
+			jab += da * db
+			jbb += db * db
+			ga += da * r
+			gb += db * r
+		}
+		improved := false
+		for !improved && lambda < 1e10 {
+			maa, mbb := jaa*(1+lambda), jbb*(1+lambda)
+			det := maa*mbb - jab*jab
+			stepA := -(mbb*ga - jab*gb) / det
+			stepB := -(maa*gb - jab*ga) / det
+			na, nb := a+stepA, b+stepB
+			if na > 0 && nb > 0 {
+				if c := residual(na, nb); c < cost {
+					converged := cost-c < 1e-15*cost
+					a, b, cost = na, nb, c
+					lambda /= 10
+					improved = true
+					if converged {
+						return a, b
+					}
+					continue
+				}
+			}
+			lambda *= 10
+		}
+		if !improved {
+			break
+		}
+	}
+	return a, b
+}
+
+// clamp returns v limited to the interval [-limit, limit].
+func clamp(v, limit float64) float64 {
+	return math.Max(-limit, math.Min(limit, v))
+}
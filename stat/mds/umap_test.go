@@ -0,0 +1,131 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestUMAP(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, labels := clusteredData(rnd, 4, 50, 10)
+	n, _ := x.Dims()
+
+	for _, test := range []UMAP{
+		{Src: rand.NewSource(1)},
+		{Dims: 3, Neighbors: 10, Epochs: 200, Src: rand.NewSource(2)},
+	} {
+		var y mat.Dense
+		test.Embed(&y, x)
+		dims := test.Dims
+		if dims == 0 {
+			dims = 2
+		}
+		if r, c := y.Dims(); r != n || c != dims {
+			t.Fatalf("unexpected embedding shape for %+v: got %d×%d, want %d×%d", test, r, c, n, dims)
+		}
+
+		var wrong int
+		for i := 0; i < n; i++ {
+			best := -1
+			bestDist := math.Inf(1)
+			for j := 0; j < n; j++ {
+				if j == i {
+					continue
+				}
+				if d := sqDist(y.RawRowView(i), y.RawRowView(j)); d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			if labels[best] != labels[i] {
+				wrong++
+			}
+		}
+		if wrong > n/50 {
+			t.Errorf("too many points with nearest neighbor in another cluster for %+v: %d of %d", test, wrong, n)
+		}
+	}
+}
+
+func TestFuzzySimplicialSet(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, _ := clusteredData(rnd, 3, 30, 5)
+	const k = 8
+	graph := fuzzySimplicialSet(x, k, rand.NewSource(1))
+	weight := func(i, j int) float64 {
+		for _, e := range graph[i] {
+			if e.j == j {
+				return e.p
+			}
+		}
+		return 0
+	}
+	for i, row := range graph {
+		if len(row) < k {
+			t.Errorf("row %d has %d edges, want at least %d", i, len(row), k)
+		}
+		var max float64
+		for _, e := range row {
+			if e.j == i {
+				t.Errorf("self edge for point %d", i)
+			}
+			if !(e.p > 0 && e.p <= 1) {
+				t.Errorf("membership strength out of range for edge %d-%d: %v", i, e.j, e.p)
+			}
+			if w := weight(e.j, i); w != e.p {
+				t.Errorf("asymmetric membership strength for edge %d-%d: %v != %v", i, e.j, e.p, w)
+			}
+			max = math.Max(max, e.p)
+		}
+		// Each point is connected to its nearest neighbor with
+		// full strength.
+		if math.Abs(max-1) > 1e-14 {
+			t.Errorf("unexpected maximum membership strength for point %d: %v", i, max)
+		}
+	}
+}
+
+func TestFitCurve(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		spread, minDist float64
+		a, b            float64
+	}{
+		// Values from the reference implementation.
+		{spread: 1, minDist: 0.1, a: 1.5769434603, b: 0.8950608781},
+		{spread: 1, minDist: 0.5, a: 0.5830300238, b: 1.3341669931},
+	} {
+		a, b := fitCurve(test.spread, test.minDist)
+		if math.Abs(a-test.a) > 1e-3 || math.Abs(b-test.b) > 1e-3 {
+			t.Errorf("unexpected curve parameters for spread=%v minDist=%v: got (%v, %v), want (%v, %v)",
+				test.spread, test.minDist, a, b, test.a, test.b)
+		}
+	}
+}
+
+func TestUMAPPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(10, 2, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "one observation", fn: func() { UMAP{}.Embed(&mat.Dense{}, mat.NewDense(1, 2, nil)) }},
+		{name: "too many neighbors", fn: func() { UMAP{Neighbors: 10}.Embed(&mat.Dense{}, x) }},
+		{name: "negative setting", fn: func() { UMAP{Neighbors: 3, MinDist: -1}.Embed(&mat.Dense{}, x) }},
+		{name: "wrong destination shape", fn: func() { UMAP{Neighbors: 3}.Embed(mat.NewDense(10, 3, nil), x) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
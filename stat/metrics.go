@@ -0,0 +1,198 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RMSE returns the weighted root mean squared error of the predictions pred
+// of the observations y
+//  sqrt(Σ_i w_i (y_i - pred_i)² / Σ_i w_i).
+// If weights is nil then all of the weights are 1. RMSE panics if the lengths
+// of y, pred and weights do not match.
+func RMSE(y, pred, weights []float64) float64 {
+	checkMetricLengths(y, pred, weights)
+	var sum, sumWeights float64
+	for i, v := range y {
+		d := v - pred[i]
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w * d * d
+		sumWeights += w
+	}
+	return math.Sqrt(sum / sumWeights)
+}
+
+// MAE returns the weighted mean absolute error of the predictions pred of the
+// observations y
+//  Σ_i w_i |y_i - pred_i| / Σ_i w_i.
+// If weights is nil then all of the weights are 1. MAE panics if the lengths
+// of y, pred and weights do not match.
+func MAE(y, pred, weights []float64) float64 {
+	checkMetricLengths(y, pred, weights)
+	var sum, sumWeights float64
+	for i, v := range y {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w * math.Abs(v-pred[i])
+		sumWeights += w
+	}
+	return sum / sumWeights
+}
+
+// AUC returns the area under the receiver operator characteristic curve of
+// the scores y for the true classes, which is the probability that a randomly
+// chosen positive observation has a higher score than a randomly chosen
+// negative observation, counting ties as one half. The observations are
+// weighted by weights, and if weights is nil then all of the weights are 1.
+// Unlike ROC, AUC does not require y to be sorted.
+//
+// AUC returns NaN if there are no positive or no negative observations. AUC
+// panics if the lengths of y, classes and weights do not match.
+func AUC(y []float64, classes []bool, weights []float64) float64 {
+	if len(y) != len(classes) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(y) {
+		panic("stat: slice length mismatch")
+	}
+	idx := identityPerm(len(y))
+	sort.Slice(idx, func(a, b int) bool { return y[idx[a]] < y[idx[b]] })
+
+	// Sum over groups of tied scores the positive weight of the group
+	// times the negative weight below it, plus half the negative weight
+	// tied with it.
+	var area, negBelow, posTotal float64
+	for i := 0; i < len(idx); {
+		var pos, neg float64
+		j := i
+		for ; j < len(idx) && y[idx[j]] == y[idx[i]]; j++ {
+			w := 1.0
+			if weights != nil {
+				w = weights[idx[j]]
+			}
+			if classes[idx[j]] {
+				pos += w
+			} else {
+				neg += w
+			}
+		}
+		area += pos * (negBelow + neg/2)
+		negBelow += neg
+		posTotal += pos
+		i = j
+	}
+	if posTotal == 0 || negBelow == 0 {
+		return math.NaN()
+	}
+	return area / (posTotal * negBelow)
+}
+
+// LogLoss returns the weighted mean negative log-likelihood of the true
+// classes given the predicted probabilities p of the positive class
+//  -Σ_i w_i (c_i log(p_i) + (1-c_i) log(1-p_i)) / Σ_i w_i
+// where c_i is 1 for positive and 0 for negative observations. The loss is
+// +Inf if a probability of zero is predicted for an observed class. If
+// weights is nil then all of the weights are 1.
+//
+// LogLoss panics if the lengths of p, classes and weights do not match, or if
+// any element of p is outside [0, 1].
+func LogLoss(p []float64, classes []bool, weights []float64) float64 {
+	if len(p) != len(classes) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(p) {
+		panic("stat: slice length mismatch")
+	}
+	var sum, sumWeights float64
+	for i, v := range p {
+		if !(0 <= v && v <= 1) {
+			panic("stat: probability out of range")
+		}
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if classes[i] {
+			sum -= w * math.Log(v)
+		} else {
+			sum -= w * math.Log1p(-v)
+		}
+		sumWeights += w
+	}
+	return sum / sumWeights
+}
+
+// ConfusionMatrix stores in dst the weighted confusion matrix of the
+// predicted class labels pred against the true class labels truth, so that
+// element i, j of dst is the total weight of the observations of class i that
+// were predicted to be class j. Class labels are integers in [0, k). If
+// weights is nil then all of the weights are 1.
+//
+// If dst is empty, it is resized to k×k where k is one more than the largest
+// label in truth and pred, and is left empty if truth and pred are empty.
+// Otherwise dst must be square and the labels must be
+// less than its dimension, and ConfusionMatrix will panic otherwise.
+// ConfusionMatrix also panics if the lengths of truth, pred and weights do not
+// match, or if a label is negative.
+func ConfusionMatrix(dst *mat.Dense, truth, pred []int, weights []float64) {
+	if len(truth) != len(pred) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(truth) {
+		panic("stat: slice length mismatch")
+	}
+	k := 0
+	for i, c := range truth {
+		if c < 0 || pred[i] < 0 {
+			panic("stat: negative class label")
+		}
+		if c >= k {
+			k = c + 1
+		}
+		if pred[i] >= k {
+			k = pred[i] + 1
+		}
+	}
+	if dst.IsEmpty() {
+		if k == 0 {
+			return
+		}
+		dst.ReuseAs(k, k)
+	} else {
+		r, c := dst.Dims()
+		if r != c {
+			panic(mat.ErrShape)
+		}
+		if k > r {
+			panic("stat: class label out of range")
+		}
+		dst.Zero()
+	}
+	for i, c := range truth {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		dst.Set(c, pred[i], dst.At(c, pred[i])+w)
+	}
+}
+
+func checkMetricLengths(y, pred, weights []float64) {
+	if len(y) != len(pred) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(y) {
+		panic("stat: slice length mismatch")
+	}
+}
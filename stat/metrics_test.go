@@ -0,0 +1,173 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRMSEMAE(t *testing.T) {
+	t.Parallel()
+	y := []float64{1, 2, 3, 4}
+	pred := []float64{1.5, 2, 1, 5}
+	if got, want := RMSE(y, pred, nil), math.Sqrt((0.25+0+4+1)/4); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected RMSE: got %v, want %v", got, want)
+	}
+	if got, want := MAE(y, pred, nil), (0.5+0+2+1)/4; !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected MAE: got %v, want %v", got, want)
+	}
+
+	// Integer weights are equivalent to repeated observations.
+	w := []float64{2, 1, 3, 1}
+	ry := []float64{1, 1, 2, 3, 3, 3, 4}
+	rpred := []float64{1.5, 1.5, 2, 1, 1, 1, 5}
+	if got, want := RMSE(y, pred, w), RMSE(ry, rpred, nil); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected weighted RMSE: got %v, want %v", got, want)
+	}
+	if got, want := MAE(y, pred, w), MAE(ry, rpred, nil); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected weighted MAE: got %v, want %v", got, want)
+	}
+}
+
+func TestAUC(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		n := 5 + rnd.Intn(30)
+		y := make([]float64, n)
+		classes := make([]bool, n)
+		w := make([]float64, n)
+		for i := range y {
+			// Round the scores to produce ties.
+			y[i] = math.Round(4 * rnd.NormFloat64())
+			classes[i] = rnd.Float64() < 0.5+y[i]/20
+			w[i] = rnd.Float64()
+		}
+		classes[0], classes[1] = true, false
+		for _, weights := range [][]float64{nil, w} {
+			// Compare with the definition over all pairs.
+			var num, den float64
+			for i := range y {
+				for j := range y {
+					if !classes[i] || classes[j] {
+						continue
+					}
+					wij := 1.0
+					if weights != nil {
+						wij = weights[i] * weights[j]
+					}
+					switch {
+					case y[i] > y[j]:
+						num += wij
+					case y[i] == y[j]:
+						num += wij / 2
+					}
+					den += wij
+				}
+			}
+			want := num / den
+			if got := AUC(y, classes, weights); !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("trial %d weighted=%t: unexpected AUC: got %v, want %v", trial, weights != nil, got, want)
+			}
+		}
+	}
+
+	if got := AUC([]float64{1, 2, 3}, []bool{false, false, true}, nil); got != 1 {
+		t.Errorf("unexpected AUC for perfect separation: got %v, want 1", got)
+	}
+	if got := AUC([]float64{1, 2}, []bool{true, true}, nil); !math.IsNaN(got) {
+		t.Errorf("unexpected AUC with a single class: got %v, want NaN", got)
+	}
+}
+
+func TestLogLoss(t *testing.T) {
+	t.Parallel()
+	p := []float64{0.9, 0.2, 0.6, 0.3}
+	classes := []bool{true, false, false, true}
+	want := -(math.Log(0.9) + math.Log(0.8) + math.Log(0.4) + math.Log(0.3)) / 4
+	if got := LogLoss(p, classes, nil); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected log loss: got %v, want %v", got, want)
+	}
+	w := []float64{1, 2, 0, 1}
+	want = -(math.Log(0.9) + 2*math.Log(0.8) + math.Log(0.3)) / 4
+	if got := LogLoss(p, classes, w); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected weighted log loss: got %v, want %v", got, want)
+	}
+	if got := LogLoss([]float64{0, 1}, []bool{false, true}, nil); got != 0 {
+		t.Errorf("unexpected log loss for perfect prediction: got %v, want 0", got)
+	}
+	if got := LogLoss([]float64{0}, []bool{true}, nil); !math.IsInf(got, 1) {
+		t.Errorf("unexpected log loss for impossible prediction: got %v, want +Inf", got)
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	t.Parallel()
+	truth := []int{0, 0, 1, 1, 2, 2, 2}
+	pred := []int{0, 1, 1, 1, 2, 0, 2}
+	var got mat.Dense
+	ConfusionMatrix(&got, truth, pred, nil)
+	want := mat.NewDense(3, 3, []float64{
+		1, 1, 0,
+		0, 2, 0,
+		1, 0, 2,
+	})
+	if !mat.Equal(&got, want) {
+		t.Errorf("unexpected confusion matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(&got), mat.Formatted(want))
+	}
+
+	// A non-empty destination is overwritten and may have room
+	// for classes that do not occur.
+	dst := mat.NewDense(4, 4, nil)
+	dst.Set(3, 3, 10)
+	ConfusionMatrix(dst, truth, pred, []float64{1, 2, 1, 1, 0.5, 3, 1})
+	want = mat.NewDense(4, 4, []float64{
+		1, 2, 0, 0,
+		0, 2, 0, 0,
+		3, 0, 1.5, 0,
+		0, 0, 0, 0,
+	})
+	if !mat.Equal(dst, want) {
+		t.Errorf("unexpected weighted confusion matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(dst), mat.Formatted(want))
+	}
+
+	// Empty inputs leave an empty destination empty and
+	// zero a non-empty destination.
+	var empty mat.Dense
+	ConfusionMatrix(&empty, nil, nil, nil)
+	if !empty.IsEmpty() {
+		t.Errorf("unexpected confusion matrix for empty input:\n%v", mat.Formatted(&empty))
+	}
+	ConfusionMatrix(dst, nil, nil, nil)
+	if !mat.Equal(dst, mat.NewDense(4, 4, nil)) {
+		t.Errorf("unexpected confusion matrix for empty input:\n%v", mat.Formatted(dst))
+	}
+}
+
+func TestMetricPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "RMSE length", fn: func() { RMSE([]float64{1, 2}, []float64{1}, nil) }},
+		{name: "MAE weights length", fn: func() { MAE([]float64{1, 2}, []float64{1, 2}, []float64{1}) }},
+		{name: "AUC length", fn: func() { AUC([]float64{1, 2}, []bool{true}, nil) }},
+		{name: "LogLoss probability", fn: func() { LogLoss([]float64{1.5}, []bool{true}, nil) }},
+		{name: "confusion negative label", fn: func() { ConfusionMatrix(&mat.Dense{}, []int{-1}, []int{0}, nil) }},
+		{name: "confusion label range", fn: func() { ConfusionMatrix(mat.NewDense(2, 2, nil), []int{2}, []int{0}, nil) }},
+		{name: "confusion shape", fn: func() { ConfusionMatrix(mat.NewDense(2, 3, nil), []int{1}, []int{0}, nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
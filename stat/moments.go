@@ -0,0 +1,194 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import "math"
+
+// Moments is an online accumulator of the weighted moments of a sample. Values
+// are added one at a time with Add or in batches with AddAll, so the sample
+// does not need to be held in memory, and accumulators of separate shards of
+// a sample may be combined with Merge.
+//
+// The statistics returned by Moments match those returned by the
+// corresponding functions in this package, for example Mean, Variance, Skew
+// and ExKurtosis, computed over the complete sample, up to floating point
+// error. The moments are updated with the numerically stable pairwise
+// formulae of Pébay.
+//  Pébay, P. "Formulas for robust, one-pass parallel computation of
+//  covariances and arbitrary-order statistical moments." Sandia Report
+//  SAND2008-6212 (2008).
+//
+// The zero value of Moments is an empty accumulator ready for use.
+type Moments struct {
+	count      int
+	sumWeights float64
+
+	mean float64
+	// m2, m3 and m4 are the weighted sums of the
+	// 2nd, 3rd and 4th powers of deviations from
+	// the mean.
+	m2, m3, m4 float64
+
+	min, max float64
+}
+
+// Add adds the value x with the given weight to the sample. Values with a
+// weight of zero are ignored.
+func (m *Moments) Add(x, weight float64) {
+	if weight == 0 {
+		return
+	}
+	m.merge(1, weight, x, 0, 0, 0, x, x)
+}
+
+// AddAll adds the values in x to the sample. If weights is nil then all of the
+// weights are 1. If weights is not nil, then len(x) must equal len(weights).
+func (m *Moments) AddAll(x, weights []float64) {
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	for i, v := range x {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		m.Add(v, w)
+	}
+}
+
+// Merge adds the sample accumulated in o to the receiver, so that the receiver
+// holds the moments of the union of the two samples. o is not modified.
+func (m *Moments) Merge(o *Moments) {
+	if o.count == 0 {
+		return
+	}
+	m.merge(o.count, o.sumWeights, o.mean, o.m2, o.m3, o.m4, o.min, o.max)
+}
+
+// merge combines the receiver's moments with those of another sample.
+func (m *Moments) merge(count int, nb, meanb, m2b, m3b, m4b, minb, maxb float64) {
+	if m.count == 0 {
+		*m = Moments{
+			count:      count,
+			sumWeights: nb,
+			mean:       meanb,
+			m2:         m2b,
+			m3:         m3b,
+			m4:         m4b,
+			min:        minb,
+			max:        maxb,
+		}
+		return
+	}
+	na := m.sumWeights
+	n := na + nb
+	d := meanb - m.mean
+	dn := d / n
+	dn2 := dn * dn
+
+	m.m4 += m4b + d*dn*dn2*na*nb*(na*na-na*nb+nb*nb) +
+		6*dn2*(na*na*m2b+nb*nb*m.m2) +
+		4*dn*(na*m3b-nb*m.m3)
+	m.m3 += m3b + d*dn2*na*nb*(na-nb) +
+		3*dn*(na*m2b-nb*m.m2)
+	m.m2 += m2b + d*dn*na*nb
+	m.mean += dn * nb
+	m.sumWeights = n
+	m.count += count
+	m.min = math.Min(m.min, minb)
+	m.max = math.Max(m.max, maxb)
+}
+
+// Reset clears the accumulated sample.
+func (m *Moments) Reset() {
+	*m = Moments{}
+}
+
+// Count returns the number of values with non-zero weight that have been
+// added to the sample.
+func (m *Moments) Count() int {
+	return m.count
+}
+
+// SumWeights returns the sum of the weights of the sample.
+func (m *Moments) SumWeights() float64 {
+	return m.sumWeights
+}
+
+// Mean returns the weighted mean of the sample. Mean returns NaN if the sample
+// is empty.
+func (m *Moments) Mean() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.mean
+}
+
+// Variance returns the unbiased weighted variance of the sample, as computed
+// by Variance.
+func (m *Moments) Variance() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.m2 / (m.sumWeights - 1)
+}
+
+// StdDev returns the sample standard deviation, as computed by StdDev.
+func (m *Moments) StdDev() float64 {
+	return math.Sqrt(m.Variance())
+}
+
+// PopVariance returns the biased weighted variance of the sample, as computed
+// by PopVariance.
+func (m *Moments) PopVariance() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.m2 / m.sumWeights
+}
+
+// PopStdDev returns the population standard deviation of the sample, as
+// computed by PopStdDev.
+func (m *Moments) PopStdDev() float64 {
+	return math.Sqrt(m.PopVariance())
+}
+
+// Skew returns the skewness of the sample, as computed by Skew.
+func (m *Moments) Skew() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	std := m.StdDev()
+	return m.m3 / (std * std * std) * skewCorrection(m.sumWeights)
+}
+
+// ExKurtosis returns the population excess kurtosis of the sample, as
+// computed by ExKurtosis.
+func (m *Moments) ExKurtosis() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	v := m.Variance()
+	mul, offset := kurtosisCorrection(m.sumWeights)
+	return m.m4/(v*v)*mul - offset
+}
+
+// Min returns the minimum value in the sample. Min returns NaN if the sample
+// is empty.
+func (m *Moments) Min() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.min
+}
+
+// Max returns the maximum value in the sample. Max returns NaN if the sample
+// is empty.
+func (m *Moments) Max() float64 {
+	if m.count == 0 {
+		return math.NaN()
+	}
+	return m.max
+}
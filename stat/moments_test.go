@@ -0,0 +1,110 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestMoments(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{5, 10, 100, 1000} {
+		for _, weighted := range []bool{false, true} {
+			x := make([]float64, n)
+			for i := range x {
+				// Shift the data away from zero to exercise the
+				// stability of the update.
+				x[i] = 1e6 + rnd.ExpFloat64()
+			}
+			var weights []float64
+			if weighted {
+				weights = make([]float64, n)
+				for i := range weights {
+					weights[i] = 0.5 + 2*rnd.Float64()
+				}
+			}
+
+			var m Moments
+			m.AddAll(x, weights)
+
+			// Accumulate the same sample in shards and merge.
+			var merged Moments
+			for start := 0; start < n; {
+				end := min(n, start+1+rnd.Intn(n/2+1))
+				var shard Moments
+				if weighted {
+					shard.AddAll(x[start:end], weights[start:end])
+				} else {
+					shard.AddAll(x[start:end], nil)
+				}
+				merged.Merge(&shard)
+				start = end
+			}
+
+			for _, acc := range []struct {
+				name string
+				m    *Moments
+			}{{"sequential", &m}, {"merged", &merged}} {
+				if acc.m.Count() != n {
+					t.Errorf("n=%d weighted=%t %s: unexpected count: %d", n, weighted, acc.name, acc.m.Count())
+				}
+				sumWeights := float64(n)
+				if weighted {
+					sumWeights = floats.Sum(weights)
+				}
+				for _, test := range []struct {
+					stat      string
+					got, want float64
+					tol       float64
+				}{
+					{"SumWeights", acc.m.SumWeights(), sumWeights, 1e-12},
+					{"Mean", acc.m.Mean(), Mean(x, weights), 1e-14},
+					{"Variance", acc.m.Variance(), Variance(x, weights), 1e-8},
+					{"StdDev", acc.m.StdDev(), StdDev(x, weights), 1e-8},
+					{"PopVariance", acc.m.PopVariance(), PopVariance(x, weights), 1e-8},
+					{"PopStdDev", acc.m.PopStdDev(), PopStdDev(x, weights), 1e-8},
+					{"Skew", acc.m.Skew(), Skew(x, weights), 1e-6},
+					{"ExKurtosis", acc.m.ExKurtosis(), ExKurtosis(x, weights), 1e-6},
+					{"Min", acc.m.Min(), floats.Min(x), 0},
+					{"Max", acc.m.Max(), floats.Max(x), 0},
+				} {
+					if !scalar.EqualWithinAbsOrRel(test.got, test.want, test.tol, test.tol) {
+						t.Errorf("n=%d weighted=%t %s: unexpected %s: got %v, want %v",
+							n, weighted, acc.name, test.stat, test.got, test.want)
+					}
+				}
+			}
+		}
+	}
+
+	var m Moments
+	if !math.IsNaN(m.Mean()) || !math.IsNaN(m.Variance()) || !math.IsNaN(m.Min()) {
+		t.Error("expected NaN statistics for empty accumulator")
+	}
+	m.Add(3, 0)
+	if m.Count() != 0 {
+		t.Error("zero weight value was counted")
+	}
+	m.Add(2, 1)
+	m.Add(4, 1)
+	m.Merge(&Moments{})
+	if m.Count() != 2 || m.Mean() != 3 || m.Variance() != 2 {
+		t.Errorf("unexpected moments of {2, 4}: count=%d mean=%v variance=%v", m.Count(), m.Mean(), m.Variance())
+	}
+	m.Reset()
+	if m.Count() != 0 || m.SumWeights() != 0 {
+		t.Error("Reset did not clear the accumulator")
+	}
+	if !panics(func() { m.AddAll([]float64{1, 2}, []float64{1}) }) {
+		t.Error("expected panic for mismatched slice lengths")
+	}
+}
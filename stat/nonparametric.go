@@ -0,0 +1,349 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+const (
+	// exactRankLimit is the sample size below which the rank tests
+	// compute exact p-values for data without ties.
+	exactRankLimit = 50
+
+	// exactKSLimit is the product of the sample sizes below which the
+	// Kolmogorov-Smirnov test computes exact p-values for data
+	// without ties.
+	exactKSLimit = 10000
+)
+
+// MannWhitneyU performs the Mann-Whitney U test, also known as the Wilcoxon
+// rank-sum test, of the null hypothesis that the distributions of the samples
+// x and y are equal against the two-sided alternative that values from one
+// are stochastically larger than values from the other. It returns the U
+// statistic of x, the number of pairs (x_i, y_j) with x_i > y_j with ties
+// counted as one half, and the p-value of the test.
+//
+// If both samples have fewer than 50 observations and there are no ties, the
+// p-value is computed from the exact distribution of U. Otherwise, the normal
+// approximation with tie and continuity corrections is used.
+//
+// MannWhitneyU will panic if x or y is empty.
+func MannWhitneyU(x, y []float64) (u, p float64) {
+	m, n := len(x), len(y)
+	if m == 0 || n == 0 {
+		panic("stat: empty sample")
+	}
+	all := make([]float64, 0, m+n)
+	all = append(all, x...)
+	all = append(all, y...)
+	ranks, ties := averageRanks(all)
+	var rx float64
+	for _, r := range ranks[:m] {
+		rx += r
+	}
+	mf, nf := float64(m), float64(n)
+	u = rx - mf*(mf+1)/2
+
+	if ties == 0 && m < exactRankLimit && n < exactRankLimit {
+		// The distribution of the rank sum of x is that of the
+		// sum of a random m-subset of {1, ..., m+n}.
+		counts := subsetSumCounts(m+n, m)
+		return u, exactTwoSided(counts[m*(m+1)/2:], int(u))
+	}
+
+	mean := mf * nf / 2
+	N := mf + nf
+	variance := mf * nf / 12 * ((N + 1) - ties/(N*(N-1)))
+	return u, normalTwoSided(u-mean, variance)
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test of the null
+// hypothesis that the distribution of the paired differences x_i - y_i is
+// symmetric about zero against the two-sided alternative. If y is nil, the
+// values of x are tested. It returns the sum W of the ranks of the positive
+// differences and the p-value of the test. Zero differences are discarded.
+//
+// If there are fewer than 50 non-zero differences and their absolute values
+// have no ties, the p-value is computed from the exact distribution of W.
+// Otherwise, the normal approximation with tie and continuity corrections is
+// used.
+//
+// WilcoxonSignedRank will panic if y is not nil and its length differs from
+// the length of x, or if there are no non-zero differences.
+func WilcoxonSignedRank(x, y []float64) (w, p float64) {
+	if y != nil && len(x) != len(y) {
+		panic("stat: slice length mismatch")
+	}
+	var d []float64
+	for i, v := range x {
+		if y != nil {
+			v -= y[i]
+		}
+		if v != 0 {
+			d = append(d, v)
+		}
+	}
+	n := len(d)
+	if n == 0 {
+		panic("stat: no non-zero differences")
+	}
+	abs := make([]float64, n)
+	for i, v := range d {
+		abs[i] = math.Abs(v)
+	}
+	ranks, ties := averageRanks(abs)
+	for i, r := range ranks {
+		if d[i] > 0 {
+			w += r
+		}
+	}
+
+	if ties == 0 && n < exactRankLimit {
+		// Under the null hypothesis each rank is positive with
+		// probability one half independently.
+		return w, exactTwoSided(subsetSumCounts(n, -1), int(w))
+	}
+
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - ties/48
+	return w, normalTwoSided(w-mean, variance)
+}
+
+// KruskalWallis performs the Kruskal-Wallis H test of the null hypothesis that
+// the distributions of all the groups are equal against the alternative that
+// values from at least one group are stochastically larger than values from
+// another. It returns the tie corrected H statistic and its p-value from the
+// asymptotic χ² distribution with one fewer degrees of freedom than the number
+// of groups.
+//
+// KruskalWallis will panic if there are fewer than two groups, if a group is
+// empty, or if all values are equal.
+func KruskalWallis(groups ...[]float64) (h, p float64) {
+	if len(groups) < 2 {
+		panic("stat: too few groups")
+	}
+	var all []float64
+	for _, g := range groups {
+		if len(g) == 0 {
+			panic("stat: empty sample")
+		}
+		all = append(all, g...)
+	}
+	ranks, ties := averageRanks(all)
+	N := float64(len(all))
+	if ties == N*N*N-N {
+		panic("stat: all values equal")
+	}
+	var start int
+	for _, g := range groups {
+		var r float64
+		for _, v := range ranks[start : start+len(g)] {
+			r += v
+		}
+		start += len(g)
+		h += r * r / float64(len(g))
+	}
+	h = 12/(N*(N+1))*h - 3*(N+1)
+	h /= 1 - ties/(N*N*N-N)
+	return h, mathext.GammaIncRegComp(float64(len(groups)-1)/2, h/2)
+}
+
+// KolmogorovSmirnovTest performs the two-sample Kolmogorov-Smirnov test of the
+// null hypothesis that the samples x and y are drawn from the same continuous
+// distribution against the two-sided alternative. It returns the largest
+// distance D between the empirical distribution functions of the samples and
+// the p-value of the test. The samples need not be sorted.
+//
+// If the product of the sample sizes is less than 10000 and there are no ties,
+// the p-value is computed from the exact distribution of D. Otherwise, the
+// asymptotic Kolmogorov distribution is used.
+//
+// KolmogorovSmirnovTest will panic if x or y is empty.
+func KolmogorovSmirnovTest(x, y []float64) (d, p float64) {
+	m, n := len(x), len(y)
+	if m == 0 || n == 0 {
+		panic("stat: empty sample")
+	}
+	xs := append([]float64(nil), x...)
+	ys := append([]float64(nil), y...)
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+	d = KolmogorovSmirnov(xs, nil, ys, nil)
+
+	if m*n < exactKSLimit && !hasTies(xs, ys) {
+		return d, ksExact(d, m, n)
+	}
+	en := math.Sqrt(float64(m) * float64(n) / float64(m+n))
+	return d, kolmogorovSurvival((en + 0.12 + 0.11/en) * d)
+}
+
+// averageRanks returns the ranks of the values in x, assigning tied values the
+// mean of their ranks, and the tie correction Σ(t³-t) over groups of t tied
+// values.
+func averageRanks(x []float64) (ranks []float64, ties float64) {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return x[idx[a]] < x[idx[b]] })
+	ranks = make([]float64, len(x))
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && x[idx[j]] == x[idx[i]] {
+			j++
+		}
+		r := float64(i+j+1) / 2
+		for _, k := range idx[i:j] {
+			ranks[k] = r
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+	return ranks, ties
+}
+
+// hasTies returns whether any value appears more than once in a and b
+// combined.
+func hasTies(a, b []float64) bool {
+	all := append(append([]float64(nil), a...), b...)
+	sort.Float64s(all)
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] {
+			return true
+		}
+	}
+	return false
+}
+
+// subsetSumCounts returns the number of subsets of {1, ..., n} with each
+// possible sum, indexed by the sum. If k is not negative, only subsets with k
+// elements are counted.
+func subsetSumCounts(n, k int) []float64 {
+	max := n * (n + 1) / 2
+	if k < 0 {
+		c := make([]float64, max+1)
+		c[0] = 1
+		for v := 1; v <= n; v++ {
+			for s := max; s >= v; s-- {
+				c[s] += c[s-v]
+			}
+		}
+		return c
+	}
+	// c[j][s] is the number of j-subsets with sum s.
+	c := make([][]float64, k+1)
+	for j := range c {
+		c[j] = make([]float64, max+1)
+	}
+	c[0][0] = 1
+	for v := 1; v <= n; v++ {
+		for j := min(k, v); j >= 1; j-- {
+			for s := max; s >= v; s-- {
+				c[j][s] += c[j-1][s-v]
+			}
+		}
+	}
+	return c[k]
+}
+
+// exactTwoSided returns the two-sided p-value of the observed statistic s
+// under the discrete distribution with the given relative frequencies of the
+// values 0, 1, ....
+func exactTwoSided(counts []float64, s int) float64 {
+	var total, lower, upper float64
+	for v, c := range counts {
+		total += c
+		if v <= s {
+			lower += c
+		}
+		if v >= s {
+			upper += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// normalTwoSided returns the two-sided p-value of a statistic with the given
+// deviation from its mean under the normal approximation with the given
+// variance, using a continuity correction of one half.
+func normalTwoSided(dev, variance float64) float64 {
+	z := math.Max(0, math.Abs(dev)-0.5) / math.Sqrt(variance)
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// ksExact returns the exact probability that the two-sample Kolmogorov-Smirnov
+// statistic of samples of sizes m and n without ties is at least d, by
+// counting the lattice paths that stay within d of the diagonal.
+//  Hodges, J. L. "The significance probability of the Smirnov two-sample
+//  test." Arkiv för Matematik 3.5 (1958): 469-486.
+func ksExact(d float64, m, n int) float64 {
+	if m > n {
+		m, n = n, m
+	}
+	mf, nf := float64(m), float64(n)
+	// Round the statistic down to the lattice of attainable values
+	// to make the comparisons robust to rounding.
+	q := (0.5 + math.Floor(d*mf*nf-1e-7)) / (mf * nf)
+	u := make([]float64, n+1)
+	for j := range u {
+		if float64(j)/nf > q {
+			u[j] = 0
+		} else {
+			u[j] = 1
+		}
+	}
+	for i := 1; i <= m; i++ {
+		w := float64(i) / float64(i+n)
+		if float64(i)/mf > q {
+			u[0] = 0
+		} else {
+			u[0] *= w
+		}
+		for j := 1; j <= n; j++ {
+			if math.Abs(float64(i)/mf-float64(j)/nf) > q {
+				u[j] = 0
+			} else {
+				u[j] = w*u[j] + u[j-1]
+			}
+		}
+	}
+	return math.Max(0, math.Min(1, 1-u[n]))
+}
+
+// kolmogorovSurvival returns the probability that the Kolmogorov distribution
+// exceeds x.
+func kolmogorovSurvival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if x < 1.18 {
+		// Use the series for the distribution function, which
+		// converges quickly for small x.
+		var sum float64
+		for k := 1; k <= 10; k++ {
+			a := float64(2*k-1) * math.Pi / x
+			sum += math.Exp(-a * a / 8)
+		}
+		return 1 - math.Sqrt(2*math.Pi)/x*sum
+	}
+	var sum float64
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		term := math.Exp(-2 * float64(k*k) * x * x)
+		sum += sign * term
+		if term < 1e-16*sum {
+			break
+		}
+		sign = -sign
+	}
+	return math.Max(0, math.Min(1, 2*sum))
+}
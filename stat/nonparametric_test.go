@@ -0,0 +1,237 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	t.Parallel()
+	// Example from the documentation of wilcox.test in R, which
+	// reports W = 35 and a one-sided p-value of 0.1272.
+	x := []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	y := []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+	u, p := MannWhitneyU(x, y)
+	if u != 35 {
+		t.Errorf("unexpected U statistic: got %v, want 35", u)
+	}
+	if math.Abs(p-2*0.1272) > 1e-4 {
+		t.Errorf("unexpected p-value: got %v, want %v", p, 2*0.1272)
+	}
+
+	// The exact p-value matches enumeration of all assignments of
+	// the ranks to the samples.
+	all := append(append([]float64(nil), x...), y...)
+	var extreme, total float64
+	for _, c := range combin.Combinations(len(all), len(x)) {
+		var rx float64
+		for _, i := range c {
+			rx += float64(i + 1)
+		}
+		ui := rx - float64(len(x)*(len(x)+1))/2
+		if ui >= u {
+			extreme++
+		}
+		total++
+	}
+	if want := 2 * extreme / total; !scalar.EqualWithinAbsOrRel(p, want, 1e-12, 1e-12) {
+		t.Errorf("unexpected exact p-value: got %v, want %v", p, want)
+	}
+
+	// The normal approximation is close to the exact distribution
+	// for moderately large samples.
+	rnd := rand.New(rand.NewSource(1))
+	a := make([]float64, 40)
+	b := make([]float64, 45)
+	for i := range a {
+		a[i] = rnd.NormFloat64()
+	}
+	for i := range b {
+		b[i] = rnd.NormFloat64() + 0.4
+	}
+	_, exact := MannWhitneyU(a, b)
+	mf, nf := 40.0, 45.0
+	ua, _ := MannWhitneyU(a, b)
+	approx := normalTwoSided(ua-mf*nf/2, mf*nf*(mf+nf+1)/12)
+	if math.Abs(exact-approx) > 0.005 {
+		t.Errorf("exact p-value %v differs from normal approximation %v", exact, approx)
+	}
+
+	// Ties are handled by the normal approximation.
+	u, p = MannWhitneyU([]float64{1, 2, 2, 3, 4}, []float64{2, 3, 5, 6, 6, 7})
+	if u != 4.5 || !(p > 0.05 && p < 0.1) {
+		t.Errorf("unexpected result with ties: U=%v p=%v", u, p)
+	}
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	t.Parallel()
+	// Example from the documentation of wilcox.test in R, which
+	// reports V = 40 and a one-sided p-value of 0.01953.
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	w, p := WilcoxonSignedRank(x, y)
+	if w != 40 {
+		t.Errorf("unexpected W statistic: got %v, want 40", w)
+	}
+	// With n = 9 there are 2^9 sign assignments, of which 10 have
+	// W >= 40.
+	if want := 2 * 10.0 / 512; !scalar.EqualWithinAbsOrRel(p, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected p-value: got %v, want %v", p, want)
+	}
+
+	// Testing the differences directly gives the same result, and
+	// zero differences are discarded.
+	d := make([]float64, len(x)+2)
+	for i := range x {
+		d[i] = x[i] - y[i]
+	}
+	wd, pd := WilcoxonSignedRank(d, nil)
+	if wd != w || pd != p {
+		t.Errorf("unexpected result for differences: got W=%v p=%v, want W=%v p=%v", wd, pd, w, p)
+	}
+
+	// Symmetric data gives a p-value of one.
+	w, p = WilcoxonSignedRank([]float64{-3, -1, 1, 3, -2, 2}, nil)
+	if w != 10.5 || p != 1 {
+		t.Errorf("unexpected result for symmetric data: W=%v p=%v", w, p)
+	}
+}
+
+func TestKruskalWallis(t *testing.T) {
+	t.Parallel()
+	// Example from the documentation of kruskal.test in R, which
+	// reports a statistic of 0.77143 with a p-value of 0.68.
+	h, p := KruskalWallis(
+		[]float64{2.9, 3.0, 2.5, 2.6, 3.2},
+		[]float64{3.8, 2.7, 4.0, 2.4},
+		[]float64{2.8, 3.4, 3.7, 2.2, 2.0},
+	)
+	if math.Abs(h-0.771428571428571) > 1e-12 {
+		t.Errorf("unexpected H statistic: got %v, want 0.771428571428571", h)
+	}
+	if want := math.Exp(-h / 2); !scalar.EqualWithinAbsOrRel(p, want, 1e-12, 1e-12) {
+		t.Errorf("unexpected p-value: got %v, want %v", p, want)
+	}
+
+	// With two groups and no ties the statistic is the square of
+	// the normal score of the Mann-Whitney test.
+	a := []float64{1.1, 3.4, 2.2, 5.0, 0.3}
+	b := []float64{4.1, 6.2, 2.9, 7.7, 5.5, 8.1}
+	h, _ = KruskalWallis(a, b)
+	u, _ := MannWhitneyU(a, b)
+	m, n := 5.0, 6.0
+	z := (u - m*n/2) / math.Sqrt(m*n*(m+n+1)/12)
+	if !scalar.EqualWithinAbsOrRel(h, z*z, 1e-12, 1e-12) {
+		t.Errorf("unexpected two group statistic: got %v, want %v", h, z*z)
+	}
+}
+
+func TestKolmogorovSmirnovTest(t *testing.T) {
+	t.Parallel()
+	// The exact p-value matches enumeration of all assignments of
+	// the observations to the samples.
+	x := []float64{0.61, 0.29, 0.06, 0.59, -1.73}
+	y := []float64{-0.74, 0.51, -0.56, 0.39, 1.64, 0.05, -0.06}
+	d, p := KolmogorovSmirnovTest(x, y)
+	all := append(append([]float64(nil), x...), y...)
+	var extreme, total float64
+	for _, c := range combin.Combinations(len(all), len(x)) {
+		in := make([]bool, len(all))
+		for _, i := range c {
+			in[i] = true
+		}
+		var xs, ys []float64
+		for i, v := range all {
+			if in[i] {
+				xs = append(xs, v)
+			} else {
+				ys = append(ys, v)
+			}
+		}
+		di, _ := KolmogorovSmirnovTest(xs, ys)
+		if di >= d-1e-12 {
+			extreme++
+		}
+		total++
+	}
+	if want := extreme / total; !scalar.EqualWithinAbsOrRel(p, want, 1e-10, 1e-10) {
+		t.Errorf("unexpected exact p-value: got %v, want %v", p, want)
+	}
+
+	// Large samples from different distributions are detected and
+	// large samples from the same distribution are not.
+	rnd := rand.New(rand.NewSource(1))
+	a := make([]float64, 200)
+	b := make([]float64, 300)
+	c := make([]float64, 300)
+	for i := range a {
+		a[i] = rnd.NormFloat64()
+	}
+	for i := range b {
+		b[i] = rnd.NormFloat64() + 0.5
+		c[i] = rnd.NormFloat64()
+	}
+	if _, p := KolmogorovSmirnovTest(a, b); p > 1e-4 {
+		t.Errorf("shifted samples not detected: p=%v", p)
+	}
+	if _, p := KolmogorovSmirnovTest(a, c); p < 0.05 {
+		t.Errorf("samples from the same distribution rejected: p=%v", p)
+	}
+}
+
+func TestKolmogorovSurvival(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		x, want float64
+	}{
+		// Critical values of the Kolmogorov distribution.
+		{x: 1.2238478702170823, want: 0.1},
+		{x: 1.3580986393225507, want: 0.05},
+		{x: 1.6276236115189408, want: 0.01},
+		{x: 0.5, want: 0.9639452436648751},
+	} {
+		if got := kolmogorovSurvival(test.x); math.Abs(got-test.want) > 1e-10 {
+			t.Errorf("unexpected survival at %v: got %v, want %v", test.x, got, test.want)
+		}
+	}
+	// The two series agree where they are switched.
+	const x = 1.18
+	var lo, hi float64
+	for k := 1; k <= 100; k++ {
+		hi += 2 * math.Pow(-1, float64(k-1)) * math.Exp(-2*float64(k*k)*x*x)
+	}
+	lo = kolmogorovSurvival(x)
+	if math.Abs(lo-hi) > 1e-14 {
+		t.Errorf("series disagree at %v: %v and %v", x, lo, hi)
+	}
+}
+
+func TestNonparametricPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "empty Mann-Whitney sample", fn: func() { MannWhitneyU(nil, []float64{1}) }},
+		{name: "Wilcoxon length mismatch", fn: func() { WilcoxonSignedRank([]float64{1, 2}, []float64{1}) }},
+		{name: "Wilcoxon zero differences", fn: func() { WilcoxonSignedRank([]float64{1, 2}, []float64{1, 2}) }},
+		{name: "Kruskal-Wallis one group", fn: func() { KruskalWallis([]float64{1, 2}) }},
+		{name: "Kruskal-Wallis empty group", fn: func() { KruskalWallis([]float64{1, 2}, nil) }},
+		{name: "Kruskal-Wallis equal values", fn: func() { KruskalWallis([]float64{1, 1}, []float64{1}) }},
+		{name: "empty Kolmogorov-Smirnov sample", fn: func() { KolmogorovSmirnovTest([]float64{1}, nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+)
+
+// PAdjustment specifies a procedure for adjusting p-values for multiple
+// comparisons.
+type PAdjustment int
+
+// List of supported PAdjustment values for the AdjustPValues function.
+const (
+	// Bonferroni multiplies each p-value by the number of tests,
+	// controlling the family-wise error rate.
+	Bonferroni PAdjustment = iota + 1
+
+	// Holm is the step-down procedure of Holm, which controls
+	// the family-wise error rate and is uniformly more powerful
+	// than Bonferroni.
+	//  Holm, S. "A simple sequentially rejective multiple test
+	//  procedure." Scandinavian Journal of Statistics 6.2 (1979): 65-70.
+	Holm
+
+	// BenjaminiHochberg is the step-up procedure of Benjamini and
+	// Hochberg, which controls the false discovery rate for
+	// independent or positively dependent tests.
+	//  Benjamini, Y. and Hochberg, Y. "Controlling the false discovery
+	//  rate: a practical and powerful approach to multiple testing."
+	//  Journal of the Royal Statistical Society B 57.1 (1995): 289-300.
+	BenjaminiHochberg
+
+	// BenjaminiYekutieli is the step-up procedure of Benjamini and
+	// Yekutieli, which controls the false discovery rate under
+	// arbitrary dependence between the tests.
+	//  Benjamini, Y. and Yekutieli, D. "The control of the false
+	//  discovery rate in multiple testing under dependency." The Annals
+	//  of Statistics 29.4 (2001): 1165-1188.
+	BenjaminiYekutieli
+)
+
+// AdjustPValues adjusts the p-values in p for multiple comparisons using the
+// given procedure, storing the adjusted p-values in dst. A hypothesis is
+// rejected at level α under the procedure when its adjusted p-value is at most
+// α. The values adjusted by the false discovery rate procedures are the
+// q-values of the tests, the smallest false discovery rate at which each test
+// is rejected. Adjusted p-values are capped at one.
+//
+// NaN values in p are treated as missing: they are not counted as tests and
+// the corresponding values of dst are NaN. If dst is nil, a new slice is
+// allocated and returned, otherwise the result is stored in place into dst
+// and returned. dst may be p.
+//
+// AdjustPValues will panic if dst is not nil and its length is not the length
+// of p, if a p-value is outside [0, 1], or if the procedure is unknown.
+func AdjustPValues(dst, p []float64, method PAdjustment) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(p))
+	}
+	if len(dst) != len(p) {
+		panic("stat: slice length mismatch")
+	}
+	switch method {
+	case Bonferroni, Holm, BenjaminiHochberg, BenjaminiYekutieli:
+	default:
+		panic("stat: unknown p-value adjustment")
+	}
+
+	// Order the tests by increasing p-value, copying the values so
+	// that dst may alias p.
+	var order []int
+	for i, v := range p {
+		if math.IsNaN(v) {
+			continue
+		}
+		if v < 0 || v > 1 {
+			panic("stat: p-value out of range")
+		}
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(a, b int) bool { return p[order[a]] < p[order[b]] })
+	sorted := make([]float64, len(order))
+	for k, i := range order {
+		sorted[k] = p[i]
+	}
+	for i, v := range p {
+		if math.IsNaN(v) {
+			dst[i] = v
+		}
+	}
+
+	m := float64(len(sorted))
+	switch method {
+	case Bonferroni:
+		for k, i := range order {
+			dst[i] = math.Min(1, m*sorted[k])
+		}
+	case Holm:
+		// The adjusted p-values are the running maximum of
+		// (m-k) p_(k) in increasing order of p-value.
+		var max float64
+		for k, i := range order {
+			max = math.Max(max, math.Min(1, (m-float64(k))*sorted[k]))
+			dst[i] = max
+		}
+	case BenjaminiHochberg, BenjaminiYekutieli:
+		// The adjusted p-values are the running minimum of
+		// c m/(k+1) p_(k) in decreasing order of p-value, where
+		// c is one for BenjaminiHochberg and the harmonic number
+		// H_m for BenjaminiYekutieli.
+		c := 1.0
+		if method == BenjaminiYekutieli {
+			c = 0
+			for k := 1; k <= len(sorted); k++ {
+				c += 1 / float64(k)
+			}
+		}
+		min := 1.0
+		for k := len(order) - 1; k >= 0; k-- {
+			min = math.Min(min, c*m/float64(k+1)*sorted[k])
+			dst[order[k]] = min
+		}
+	}
+	return dst
+}
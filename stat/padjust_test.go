@@ -0,0 +1,116 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestAdjustPValues(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	const hm = 1 + 1.0/2 + 1.0/3 + 1.0/4 + 1.0/5 + 1.0/6
+	for i, test := range []struct {
+		p      []float64
+		method PAdjustment
+		want   []float64
+	}{
+		// Values agree with p.adjust in R.
+		{
+			p:      []float64{0.01, 0.02, 0.03, 0.04, 0.05, 0.5},
+			method: Bonferroni,
+			want:   []float64{0.06, 0.12, 0.18, 0.24, 0.3, 1},
+		},
+		{
+			p:      []float64{0.01, 0.02, 0.03, 0.04, 0.05, 0.5},
+			method: Holm,
+			want:   []float64{0.06, 0.1, 0.12, 0.12, 0.12, 0.5},
+		},
+		{
+			p:      []float64{0.01, 0.02, 0.03, 0.04, 0.05, 0.5},
+			method: BenjaminiHochberg,
+			want:   []float64{0.06, 0.06, 0.06, 0.06, 0.06, 0.5},
+		},
+		{
+			p:      []float64{0.01, 0.02, 0.03, 0.04, 0.05, 0.5},
+			method: BenjaminiYekutieli,
+			want:   []float64{0.06 * hm, 0.06 * hm, 0.06 * hm, 0.06 * hm, 0.06 * hm, 1},
+		},
+		{
+			p:      []float64{0.04, 0.001, 0.3, 0.012, 0.02},
+			method: Holm,
+			want:   []float64{0.08, 0.005, 0.3, 0.048, 0.06},
+		},
+		{
+			p:      []float64{0.04, 0.001, 0.3, 0.012, 0.02},
+			method: BenjaminiHochberg,
+			want:   []float64{0.05, 0.005, 0.3, 0.03, 0.03333333333333333},
+		},
+		{
+			// Missing values are not counted as tests.
+			p:      []float64{0.02, nan, 0.01, 0.04},
+			method: Bonferroni,
+			want:   []float64{0.06, nan, 0.03, 0.12},
+		},
+		{
+			p:      []float64{0.02, nan, 0.01, 0.04},
+			method: BenjaminiHochberg,
+			want:   []float64{0.03, nan, 0.03, 0.04},
+		},
+		{
+			p:      nil,
+			method: Holm,
+			want:   []float64{},
+		},
+	} {
+		got := AdjustPValues(nil, test.p, test.method)
+		if !equalApproxNaN(got, test.want, 1e-14) {
+			t.Errorf("unexpected result for test %d: got %v, want %v", i, got, test.want)
+		}
+
+		// The adjustment may be done in place.
+		inPlace := append([]float64(nil), test.p...)
+		AdjustPValues(inPlace, inPlace, test.method)
+		if !equalApproxNaN(inPlace, test.want, 1e-14) {
+			t.Errorf("unexpected in place result for test %d: got %v, want %v", i, inPlace, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "length mismatch", fn: func() { AdjustPValues(make([]float64, 1), []float64{0.1, 0.2}, Holm) }},
+		{name: "out of range", fn: func() { AdjustPValues(nil, []float64{0.1, 1.2}, Holm) }},
+		{name: "unknown method", fn: func() { AdjustPValues(nil, []float64{0.1}, 0) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+// equalApproxNaN returns whether a and b are approximately equal, treating
+// NaN values as equal.
+func equalApproxNaN(a, b []float64, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if math.IsNaN(v) || math.IsNaN(b[i]) {
+			if math.IsNaN(v) != math.IsNaN(b[i]) {
+				return false
+			}
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(v, b[i], tol, tol) {
+			return false
+		}
+	}
+	return true
+}
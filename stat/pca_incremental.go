@@ -0,0 +1,180 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// IncrementalPC is a type for computing the principal components of data that
+// are presented in batches of observations, for example because the full data
+// set does not fit in memory or arrives over time. Each call to Update revises
+// the analysis with a new batch using the sequential Karhunen-Loeve method of
+// Ross et al., keeping only the leading components between updates.
+//  Ross, D. A., Lim, J., Lin, R.-S. and Yang, M.-H. "Incremental learning for
+//  robust visual tracking." International Journal of Computer Vision 77.1-3
+//  (2008): 125-141.
+//
+// When all components are retained, the result is the same as that of PC
+// applied to all of the observations without weights. The results are only
+// valid if the last call to Update was successful.
+type IncrementalPC struct {
+	// Components is the maximum number of principal components retained
+	// between updates. If Components is zero, all components are kept.
+	// Retaining fewer components reduces the cost of each update at the
+	// expense of approximating the analysis.
+	Components int
+
+	n, d int
+	mean []float64
+	// vecs holds the retained component directions in its columns
+	// and values holds the corresponding singular values of the
+	// centered data.
+	vecs   *mat.Dense
+	values []float64
+	svd    mat.SVD
+	ok     bool
+}
+
+// Update adds the observations in the rows of a to the analysis. The number
+// of columns of a must match the number of variables of previous updates, or
+// Update will panic. Update will also panic if Components is negative.
+//
+// Update returns whether the analysis was successful. After an unsuccessful
+// update the receiver must not be used.
+func (c *IncrementalPC) Update(a mat.Matrix) (ok bool) {
+	if c.Components < 0 {
+		panic("stat: negative number of components")
+	}
+	m, d := a.Dims()
+	if c.n == 0 {
+		c.d = d
+		c.mean = make([]float64, d)
+	} else if d != c.d {
+		panic(mat.ErrShape)
+	}
+	if c.n != 0 && !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+
+	// Stack the scaled components of the current analysis, the
+	// batch centered on its own mean, and a row correcting for the
+	// shift between the current mean and the batch mean.
+	k := len(c.values)
+	rows := k + m
+	if c.n > 0 {
+		rows++
+	}
+	stack := mat.NewDense(rows, d, nil)
+	for i, s := range c.values {
+		row := stack.RawRowView(i)
+		for j := range row {
+			row[j] = s * c.vecs.At(j, i)
+		}
+	}
+	batchMean := make([]float64, d)
+	col := make([]float64, m)
+	for j := 0; j < d; j++ {
+		mat.Col(col, j, a)
+		batchMean[j] = Mean(col, nil)
+		for i, v := range col {
+			stack.Set(k+i, j, v-batchMean[j])
+		}
+	}
+	n := float64(c.n)
+	total := n + float64(m)
+	if c.n > 0 {
+		f := math.Sqrt(n * float64(m) / total)
+		row := stack.RawRowView(rows - 1)
+		for j := range row {
+			row[j] = f * (c.mean[j] - batchMean[j])
+		}
+	}
+	for j, v := range batchMean {
+		c.mean[j] += float64(m) / total * (v - c.mean[j])
+	}
+	c.n += m
+
+	c.ok = c.svd.Factorize(stack, mat.SVDThinV)
+	if !c.ok {
+		return false
+	}
+	keep := min(c.n, min(rows, d))
+	if c.Components > 0 && c.Components < keep {
+		keep = c.Components
+	}
+	c.values = c.svd.Values(nil)[:keep]
+	var v mat.Dense
+	c.svd.VTo(&v)
+	c.vecs = mat.DenseCopyOf(v.Slice(0, d, 0, keep))
+	return true
+}
+
+// VectorsTo returns the component direction vectors of the analysis. The
+// vectors are returned in the columns of a d×k matrix, where k is the number
+// of components retained.
+//
+// If dst is empty, VectorsTo will resize dst to be d×k. When dst is non-empty,
+// VectorsTo will panic if dst is not d×k. VectorsTo will also panic if the
+// receiver does not contain a successful analysis.
+func (c *IncrementalPC) VectorsTo(dst *mat.Dense) {
+	if !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+	k := len(c.values)
+	if dst.IsEmpty() {
+		dst.ReuseAs(c.d, k)
+	} else {
+		if d, n := dst.Dims(); d != c.d || n != k {
+			panic(mat.ErrShape)
+		}
+	}
+	dst.Copy(c.vecs)
+}
+
+// VarsTo returns the variances of the retained principal component scores in
+// descending order. If dst is not nil it is used to store the variances and
+// returned. VarsTo will panic if the receiver does not contain a successful
+// analysis or dst is not nil and its length does not match the number of
+// retained components.
+func (c *IncrementalPC) VarsTo(dst []float64) []float64 {
+	if !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+	if dst == nil {
+		dst = make([]float64, len(c.values))
+	} else if len(dst) != len(c.values) {
+		panic("stat: length of slice does not match analysis")
+	}
+	f := 1 / float64(c.n-1)
+	for i, v := range c.values {
+		dst[i] = f * v * v
+	}
+	return dst
+}
+
+// MeanTo returns the means of the variables over all observations in the
+// analysis. If dst is not nil it is used to store the means and returned.
+// MeanTo will panic if the receiver does not contain a successful analysis or
+// dst is not nil and its length does not match the number of variables.
+func (c *IncrementalPC) MeanTo(dst []float64) []float64 {
+	if !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+	if dst == nil {
+		dst = make([]float64, c.d)
+	} else if len(dst) != c.d {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, c.mean)
+	return dst
+}
+
+// Observations returns the number of observations in the analysis.
+func (c *IncrementalPC) Observations() int {
+	return c.n
+}
@@ -0,0 +1,127 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// equalUpToSign returns whether the columns of a and b are equal within tol
+// up to a change of sign of each column.
+func equalUpToSign(a, b mat.Matrix, tol float64) bool {
+	r, c := a.Dims()
+	if br, bc := b.Dims(); br != r || bc != c {
+		return false
+	}
+	for j := 0; j < c; j++ {
+		ca := mat.Col(nil, j, a)
+		cb := mat.Col(nil, j, b)
+		if floats.Dot(ca, cb) < 0 {
+			floats.Scale(-1, cb)
+		}
+		if !floats.EqualApprox(ca, cb, tol) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIncrementalPC(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 40, 5
+	for _, test := range []struct {
+		name       string
+		rank       int
+		components int
+	}{
+		{name: "all components", rank: d, components: 0},
+		// Data with exactly three principal directions are
+		// analysed without approximation when three
+		// components are retained.
+		{name: "low rank", rank: 3, components: 3},
+	} {
+		basis := mat.NewDense(test.rank, d, nil)
+		for i := range basis.RawMatrix().Data {
+			basis.RawMatrix().Data[i] = rnd.NormFloat64()
+		}
+		scores := mat.NewDense(n, test.rank, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < test.rank; j++ {
+				scores.Set(i, j, float64(test.rank-j)*rnd.NormFloat64())
+			}
+		}
+		var x mat.Dense
+		x.Mul(scores, basis)
+		for i := 0; i < n; i++ {
+			floats.Add(x.RawRowView(i), []float64{1, -2, 3, 0.5, 10})
+		}
+
+		var pc PC
+		if !pc.PrincipalComponents(&x, nil) {
+			t.Fatalf("%s: unexpected failure of batch analysis", test.name)
+		}
+		var wantVecs mat.Dense
+		pc.VectorsTo(&wantVecs)
+		wantVars := pc.VarsTo(nil)
+
+		ipc := IncrementalPC{Components: test.components}
+		for _, b := range [][2]int{{0, 7}, {7, 8}, {8, 25}, {25, n}} {
+			if !ipc.Update(x.Slice(b[0], b[1], 0, d)) {
+				t.Fatalf("%s: unexpected failure of update", test.name)
+			}
+		}
+		if ipc.Observations() != n {
+			t.Errorf("%s: unexpected number of observations: got %d, want %d", test.name, ipc.Observations(), n)
+		}
+		k := test.rank
+		if test.components == 0 {
+			k = d
+		}
+		gotVars := ipc.VarsTo(nil)
+		if len(gotVars) != k {
+			t.Fatalf("%s: unexpected number of components: got %d, want %d", test.name, len(gotVars), k)
+		}
+		if !floats.EqualApprox(gotVars, wantVars[:k], 1e-10) {
+			t.Errorf("%s: unexpected variances: got %v, want %v", test.name, gotVars, wantVars[:k])
+		}
+		var gotVecs mat.Dense
+		ipc.VectorsTo(&gotVecs)
+		if !equalUpToSign(&gotVecs, wantVecs.Slice(0, d, 0, k), 1e-8) {
+			t.Errorf("%s: unexpected vectors:\ngot:\n%v\nwant:\n%v", test.name,
+				mat.Formatted(&gotVecs), mat.Formatted(wantVecs.Slice(0, d, 0, k)))
+		}
+		mean := ipc.MeanTo(nil)
+		for j, m := range mean {
+			if want := Mean(mat.Col(nil, j, &x), nil); math.Abs(m-want) > 1e-12 {
+				t.Errorf("%s: unexpected mean of variable %d: got %v, want %v", test.name, j, m, want)
+			}
+		}
+	}
+}
+
+func TestIncrementalPCPanics(t *testing.T) {
+	t.Parallel()
+	var ipc IncrementalPC
+	if !panics(func() { ipc.VarsTo(nil) }) {
+		t.Errorf("expected panic for use before update")
+	}
+	ipc.Update(mat.NewDense(3, 2, []float64{1, 2, 3, 5, 4, 4}))
+	if !panics(func() { ipc.Update(mat.NewDense(2, 3, nil)) }) {
+		t.Errorf("expected panic for variable mismatch")
+	}
+	if !panics(func() { ipc.VectorsTo(mat.NewDense(2, 1, nil)) }) {
+		t.Errorf("expected panic for destination shape mismatch")
+	}
+	if !panics(func() { (&IncrementalPC{Components: -1}).Update(mat.NewDense(2, 2, nil)) }) {
+		t.Errorf("expected panic for negative components")
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package statespace provides Kalman filtering, Rauch-Tung-Striebel smoothing
+// and expectation-maximization parameter estimation for linear Gaussian
+// state-space models.
+package statespace // import "gonum.org/v1/gonum/stat/statespace"
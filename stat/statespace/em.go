@@ -0,0 +1,205 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Params is a set of Model parameters.
+type Params int
+
+const (
+	// Transition is the state transition matrix F.
+	Transition Params = 1 << iota
+	// Observation is the observation matrix H.
+	Observation
+	// ProcessNoise is the process noise covariance Q.
+	ProcessNoise
+	// ObservationNoise is the observation noise covariance R.
+	ObservationNoise
+	// InitialState is the initial state mean μ_0 and covariance P_0.
+	InitialState
+)
+
+// EM estimates the parameters of a Model by maximum likelihood using the
+// expectation-maximization algorithm. Each iteration runs the Kalman filter
+// and smoother under the current parameters and then maximizes the expected
+// complete data log-likelihood in closed form.
+//  Shumway, R. H. and Stoffer, D. S. "An approach to time series smoothing
+//  and forecasting using the EM algorithm." Journal of Time Series Analysis
+//  3.4 (1982): 253-264.
+//
+// The log-likelihood does not decrease between iterations. The parameters
+// of a state-space model are only identified up to a change of basis of the
+// state, so structural models usually hold the transition and observation
+// matrices fixed.
+type EM struct {
+	// Iterations is the maximum number of iterations. If Iterations is
+	// zero, 1000 is used.
+	Iterations int
+
+	// Tolerance is the relative change in log-likelihood between
+	// iterations below which the algorithm has converged. If Tolerance
+	// is zero, 1e-8 is used.
+	Tolerance float64
+
+	// Fixed is the set of parameters that are held at their current
+	// values. If Fixed is zero, all parameters are estimated.
+	Fixed Params
+}
+
+// Fit estimates the parameters of the model m from the observations in y, a
+// T×k matrix with the observation at time t in row t, starting from the
+// current parameters of m, which are updated in place. NaN elements of y are
+// treated as missing. Fit returns the log-likelihood of the observations
+// under the final parameters and whether the algorithm converged.
+//
+// Fit will panic if the model matrices have inconsistent shapes, y does not
+// have k columns or has fewer than two rows, or if y contains missing values
+// and the observation matrix or observation noise covariance is estimated.
+func (e EM) Fit(m *Model, y mat.Matrix) (logLikelihood float64, converged bool) {
+	n, k := m.dims()
+	steps, c := y.Dims()
+	if c != k {
+		panic("statespace: observation dimension mismatch")
+	}
+	if steps < 2 {
+		panic("statespace: too few observations")
+	}
+	if e.Fixed&(Observation|ObservationNoise) != Observation|ObservationNoise {
+		for t := 0; t < steps; t++ {
+			for j := 0; j < k; j++ {
+				if math.IsNaN(y.At(t, j)) {
+					panic("statespace: missing observation with estimated observation parameters")
+				}
+			}
+		}
+	}
+	iterations := e.Iterations
+	if iterations == 0 {
+		iterations = 1000
+	}
+	tol := e.Tolerance
+	if tol == 0 {
+		tol = 1e-8
+	}
+
+	prev := math.Inf(-1)
+	for i := 0; i < iterations; i++ {
+		f := m.Filter(y)
+		logLikelihood = f.LogLikelihood
+		if math.Abs(logLikelihood-prev) <= tol*math.Abs(logLikelihood) {
+			return logLikelihood, true
+		}
+		prev = logLikelihood
+		e.maximize(m, y, m.Smooth(f), n, k)
+	}
+	logLikelihood = m.Filter(y).LogLikelihood
+	return logLikelihood, math.Abs(logLikelihood-prev) <= tol*math.Abs(logLikelihood)
+}
+
+// maximize updates the parameters of m that are not fixed to maximize the
+// expected complete data log-likelihood given the smoothed states s.
+func (e EM) maximize(m *Model, y mat.Matrix, s *Smoothed, n, k int) {
+	steps := len(s.Mean)
+
+	// Second moments of the smoothed states,
+	//  S11 = Σ_{t=1}^{T-1} E[x_t x_tᵀ]
+	//  S10 = Σ_{t=1}^{T-1} E[x_t x_{t-1}ᵀ]
+	//  S00 = Σ_{t=1}^{T-1} E[x_{t-1} x_{t-1}ᵀ]
+	s11 := mat.NewDense(n, n, nil)
+	s10 := mat.NewDense(n, n, nil)
+	s00 := mat.NewDense(n, n, nil)
+	moment := func(t int) *mat.Dense {
+		var mom mat.Dense
+		mom.Outer(1, s.Mean[t], s.Mean[t])
+		mom.Add(&mom, s.Cov[t])
+		return &mom
+	}
+	for t := 1; t < steps; t++ {
+		s11.Add(s11, moment(t))
+		s00.Add(s00, moment(t-1))
+		var cross mat.Dense
+		cross.Outer(1, s.Mean[t], s.Mean[t-1])
+		cross.Add(&cross, s.LagCov[t-1])
+		s10.Add(s10, &cross)
+	}
+
+	if e.Fixed&Transition == 0 {
+		// F = S10 S00⁻¹, the solution of S00 Fᵀ = S10ᵀ.
+		var ft mat.Dense
+		err := ft.Solve(s00, s10.T())
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				panic("statespace: singular state second moment")
+			}
+		}
+		m.Transition.Copy(ft.T())
+	}
+	if e.Fixed&ProcessNoise == 0 {
+		// Q = (S11 - F S10ᵀ - S10 Fᵀ + F S00 Fᵀ) / (T-1).
+		f := m.Transition
+		var fs, fsf, q mat.Dense
+		fs.Mul(f, s10.T())
+		q.Sub(s11, &fs)
+		q.Sub(&q, fs.T())
+		fsf.Mul(f, s00)
+		fsf.Mul(&fsf, f.T())
+		q.Add(&q, &fsf)
+		q.Scale(1/float64(steps-1), &q)
+		symmetrize(m.ProcessNoise, &q)
+	}
+
+	if e.Fixed&(Observation|ObservationNoise) != Observation|ObservationNoise {
+		obs := mat.NewVecDense(k, nil)
+		if e.Fixed&Observation == 0 {
+			// H = (Σ_t y_t E[x_t]ᵀ) (Σ_t E[x_t x_tᵀ])⁻¹.
+			syx := mat.NewDense(k, n, nil)
+			sxx := mat.NewDense(n, n, nil)
+			for t := 0; t < steps; t++ {
+				mat.Row(obs.RawVector().Data, t, y)
+				var yx mat.Dense
+				yx.Outer(1, obs, s.Mean[t])
+				syx.Add(syx, &yx)
+				sxx.Add(sxx, moment(t))
+			}
+			var ht mat.Dense
+			err := ht.Solve(sxx, syx.T())
+			if err != nil {
+				if _, ok := err.(mat.Condition); !ok {
+					panic("statespace: singular state second moment")
+				}
+			}
+			m.Observation.Copy(ht.T())
+		}
+		if e.Fixed&ObservationNoise == 0 {
+			// R = 1/T Σ_t (y_t - H E[x_t]) (y_t - H E[x_t])ᵀ + H P_{t|T} Hᵀ.
+			h := m.Observation
+			r := mat.NewDense(k, k, nil)
+			for t := 0; t < steps; t++ {
+				mat.Row(obs.RawVector().Data, t, y)
+				var res mat.VecDense
+				res.MulVec(h, s.Mean[t])
+				res.SubVec(obs, &res)
+				var rr, hp, hph mat.Dense
+				rr.Outer(1, &res, &res)
+				hp.Mul(h, s.Cov[t])
+				hph.Mul(&hp, h.T())
+				r.Add(r, &rr)
+				r.Add(r, &hph)
+			}
+			r.Scale(1/float64(steps), r)
+			symmetrize(m.ObservationNoise, r)
+		}
+	}
+
+	if e.Fixed&InitialState == 0 {
+		m.InitialMean.CopyVec(s.Mean[0])
+		m.InitialCov.CopySym(s.Cov[0])
+	}
+}
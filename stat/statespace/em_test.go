@@ -0,0 +1,125 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// simulate returns steps observations of the model.
+func simulate(rnd *rand.Rand, m *Model, steps int) *mat.Dense {
+	n, k := m.dims()
+	var cq, cr mat.Cholesky
+	if !cq.Factorize(m.ProcessNoise) || !cr.Factorize(m.ObservationNoise) {
+		panic("bad noise covariance")
+	}
+	var lq, lr mat.TriDense
+	cq.LTo(&lq)
+	cr.LTo(&lr)
+	noise := func(l *mat.TriDense, d int) *mat.VecDense {
+		z := mat.NewVecDense(d, nil)
+		for i := 0; i < d; i++ {
+			z.SetVec(i, rnd.NormFloat64())
+		}
+		z.MulVec(l, z)
+		return z
+	}
+
+	y := mat.NewDense(steps, k, nil)
+	x := mat.VecDenseCopyOf(m.InitialMean)
+	for t := 0; t < steps; t++ {
+		if t > 0 {
+			x.MulVec(m.Transition, x)
+			x.AddVec(x, noise(&lq, n))
+		}
+		var obs mat.VecDense
+		obs.MulVec(m.Observation, x)
+		obs.AddVec(&obs, noise(&lr, k))
+		y.SetRow(t, obs.RawVector().Data)
+	}
+	return y
+}
+
+func TestEM(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// An AR(1) process observed with noise.
+	truth := &Model{
+		Transition:       mat.NewDense(1, 1, []float64{0.8}),
+		Observation:      mat.NewDense(1, 1, []float64{1}),
+		ProcessNoise:     mat.NewSymDense(1, []float64{1}),
+		ObservationNoise: mat.NewSymDense(1, []float64{0.5}),
+		InitialMean:      mat.NewVecDense(1, []float64{0}),
+		InitialCov:       mat.NewSymDense(1, []float64{1 / (1 - 0.8*0.8)}),
+	}
+	y := simulate(rnd, truth, 1000)
+
+	m := &Model{
+		Transition:       mat.NewDense(1, 1, []float64{0.3}),
+		Observation:      mat.NewDense(1, 1, []float64{1}),
+		ProcessNoise:     mat.NewSymDense(1, []float64{0.2}),
+		ObservationNoise: mat.NewSymDense(1, []float64{2}),
+		InitialMean:      mat.NewVecDense(1, []float64{0}),
+		InitialCov:       mat.NewSymDense(1, []float64{1}),
+	}
+
+	// The log-likelihood does not decrease between iterations.
+	prev := math.Inf(-1)
+	for i := 0; i < 20; i++ {
+		ll, _ := EM{Iterations: 1, Fixed: Observation}.Fit(m, y)
+		if ll < prev-1e-8 {
+			t.Errorf("log-likelihood decreased at iteration %d: %v to %v", i, prev, ll)
+		}
+		prev = ll
+	}
+
+	ll, ok := EM{Tolerance: 1e-6, Fixed: Observation}.Fit(m, y)
+	if !ok {
+		t.Errorf("EM did not converge")
+	}
+	if ll < prev {
+		t.Errorf("log-likelihood decreased after convergence: %v to %v", prev, ll)
+	}
+	for _, test := range []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{name: "transition", got: m.Transition.At(0, 0), want: 0.8, tol: 0.03},
+		{name: "process noise", got: m.ProcessNoise.At(0, 0), want: 1, tol: 0.15},
+		{name: "observation noise", got: m.ObservationNoise.At(0, 0), want: 0.5, tol: 0.15},
+	} {
+		if math.Abs(test.got-test.want) > test.tol {
+			t.Errorf("unexpected %s estimate: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+	if m.Observation.At(0, 0) != 1 {
+		t.Errorf("fixed observation matrix changed: %v", m.Observation.At(0, 0))
+	}
+
+	// Estimation of the transition and noise is possible with missing
+	// observations when the observation parameters are fixed.
+	miss := mat.DenseCopyOf(y)
+	for i := 0; i < 1000; i += 3 {
+		miss.Set(i, 0, math.NaN())
+	}
+	m.ObservationNoise.SetSym(0, 0, 0.5)
+	m.Transition.Set(0, 0, 0.5)
+	if _, ok := (EM{Tolerance: 1e-6, Fixed: Observation | ObservationNoise}).Fit(m, miss); !ok {
+		t.Errorf("EM did not converge with missing observations")
+	}
+	if got := m.Transition.At(0, 0); math.Abs(got-0.8) > 0.03 {
+		t.Errorf("unexpected transition estimate with missing observations: got %v, want 0.8", got)
+	}
+	if !panics(func() { EM{Fixed: Observation}.Fit(m, miss) }) {
+		t.Errorf("expected panic for missing observations with estimated observation noise")
+	}
+}
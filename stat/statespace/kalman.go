@@ -0,0 +1,350 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Model is a linear Gaussian state-space model with n-dimensional state x_t
+// and k-dimensional observation y_t,
+//  x_{t+1} = F x_t + w_t,  w_t ~ N(0, Q)
+//  y_t     = H x_t + v_t,  v_t ~ N(0, R)
+// with the state at the first observation distributed as x_0 ~ N(μ_0, P_0).
+type Model struct {
+	// Transition is the n×n state transition matrix F.
+	Transition *mat.Dense
+	// Observation is the k×n observation matrix H.
+	Observation *mat.Dense
+	// ProcessNoise is the n×n process noise covariance Q.
+	ProcessNoise *mat.SymDense
+	// ObservationNoise is the k×k observation noise covariance R.
+	ObservationNoise *mat.SymDense
+	// InitialMean is the mean μ_0 of the initial state.
+	InitialMean *mat.VecDense
+	// InitialCov is the covariance P_0 of the initial state.
+	InitialCov *mat.SymDense
+}
+
+// dims returns the state and observation dimensions of the model, and
+// panics if the model matrices have inconsistent shapes.
+func (m *Model) dims() (n, k int) {
+	n, c := m.Transition.Dims()
+	if c != n {
+		panic("statespace: transition matrix not square")
+	}
+	k, c = m.Observation.Dims()
+	if c != n {
+		panic("statespace: observation matrix dimension mismatch")
+	}
+	if m.ProcessNoise.SymmetricDim() != n || m.InitialCov.SymmetricDim() != n || m.InitialMean.Len() != n {
+		panic("statespace: state dimension mismatch")
+	}
+	if m.ObservationNoise.SymmetricDim() != k {
+		panic("statespace: observation dimension mismatch")
+	}
+	return n, k
+}
+
+// KalmanFilter is a Kalman filter that processes observations of a Model one
+// at a time, suitable for online tracking. The filter holds the mean and
+// covariance of the current state conditional on the observations seen so far.
+type KalmanFilter struct {
+	model *Model
+	n, k  int
+
+	mean *mat.VecDense
+	cov  *mat.SymDense
+}
+
+// NewKalmanFilter returns a Kalman filter for the model m, initialized to the
+// initial state distribution of m. The model is referenced, not copied, so
+// changes to its matrices are reflected in subsequent steps of the filter.
+// NewKalmanFilter will panic if the model matrices have inconsistent shapes.
+func NewKalmanFilter(m *Model) *KalmanFilter {
+	n, k := m.dims()
+	kf := &KalmanFilter{
+		model: m,
+		n:     n,
+		k:     k,
+		mean:  mat.NewVecDense(n, nil),
+		cov:   mat.NewSymDense(n, nil),
+	}
+	kf.Reset()
+	return kf
+}
+
+// Reset resets the state distribution to the initial state distribution of
+// the model.
+func (kf *KalmanFilter) Reset() {
+	kf.mean.CopyVec(kf.model.InitialMean)
+	kf.cov.CopySym(kf.model.InitialCov)
+}
+
+// MeanTo stores the current state mean into dst. If dst is empty, MeanTo will
+// resize dst to have length n. When dst is non-empty, MeanTo will panic if dst
+// does not have length n.
+func (kf *KalmanFilter) MeanTo(dst *mat.VecDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsVec(kf.n)
+	} else if dst.Len() != kf.n {
+		panic(mat.ErrShape)
+	}
+	dst.CopyVec(kf.mean)
+}
+
+// CovarianceTo stores the current state covariance into dst. If dst is empty,
+// CovarianceTo will resize dst to be n×n. When dst is non-empty, CovarianceTo
+// will panic if dst is not n×n.
+func (kf *KalmanFilter) CovarianceTo(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(kf.n)
+	} else if dst.SymmetricDim() != kf.n {
+		panic(mat.ErrShape)
+	}
+	dst.CopySym(kf.cov)
+}
+
+// Predict advances the state distribution one time step through the
+// transition model,
+//  μ ← F μ
+//  P ← F P Fᵀ + Q
+func (kf *KalmanFilter) Predict() {
+	f := kf.model.Transition
+	var mean mat.VecDense
+	mean.MulVec(f, kf.mean)
+	kf.mean.CopyVec(&mean)
+
+	var fp, fpf mat.Dense
+	fp.Mul(f, kf.cov)
+	fpf.Mul(&fp, f.T())
+	fpf.Add(&fpf, kf.model.ProcessNoise)
+	symmetrize(kf.cov, &fpf)
+}
+
+// Update conditions the state distribution on the observation y and returns
+// the log-likelihood of y under the predictive distribution of the
+// observation. Elements of y that are NaN are treated as missing, so that
+// sensors reporting at different rates can be fused by stacking their
+// observations. If all elements of y are missing, the state distribution is
+// unchanged and the returned log-likelihood is zero.
+//
+// Update will panic if the length of y is not k, or if the covariance of the
+// predicted observation is not positive definite.
+func (kf *KalmanFilter) Update(y []float64) float64 {
+	if len(y) != kf.k {
+		panic("statespace: observation dimension mismatch")
+	}
+	h, r, v := kf.model.observed(y)
+	if v == nil {
+		return 0
+	}
+	k := v.Len()
+
+	// Innovation v = y - H μ with covariance S = H P Hᵀ + R.
+	var hm mat.VecDense
+	hm.MulVec(h, kf.mean)
+	v.SubVec(v, &hm)
+	var hp, hph mat.Dense
+	hp.Mul(h, kf.cov)
+	hph.Mul(&hp, h.T())
+	hph.Add(&hph, r)
+	s := mat.NewSymDense(k, nil)
+	symmetrize(s, &hph)
+	var chol mat.Cholesky
+	if ok := chol.Factorize(s); !ok {
+		panic("statespace: innovation covariance not positive definite")
+	}
+
+	// With the gain K = P Hᵀ S⁻¹,
+	//  μ ← μ + K v
+	//  P ← P - K S Kᵀ = P - (H P)ᵀ S⁻¹ (H P)
+	var shp mat.Dense
+	if err := chol.SolveTo(&shp, &hp); err != nil {
+		panic("statespace: innovation covariance not positive definite")
+	}
+	var dm mat.VecDense
+	dm.MulVec(shp.T(), v)
+	kf.mean.AddVec(kf.mean, &dm)
+	var dp mat.Dense
+	dp.Mul(hp.T(), &shp)
+	dp.Sub(kf.cov, &dp)
+	symmetrize(kf.cov, &dp)
+
+	var sv mat.VecDense
+	if err := chol.SolveVecTo(&sv, v); err != nil {
+		panic("statespace: innovation covariance not positive definite")
+	}
+	return -0.5 * (float64(k)*math.Log(2*math.Pi) + chol.LogDet() + mat.Dot(v, &sv))
+}
+
+// observed returns the rows of the observation matrix, the observation noise
+// covariance and the observation vector corresponding to the non-NaN elements
+// of y. If all elements of y are NaN, observed returns nil values.
+func (m *Model) observed(y []float64) (h *mat.Dense, r *mat.SymDense, v *mat.VecDense) {
+	idx := make([]int, 0, len(y))
+	for i, yi := range y {
+		if !math.IsNaN(yi) {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		return nil, nil, nil
+	}
+	if len(idx) == len(y) {
+		return m.Observation, m.ObservationNoise, mat.NewVecDense(len(y), append([]float64(nil), y...))
+	}
+	_, n := m.Observation.Dims()
+	h = mat.NewDense(len(idx), n, nil)
+	r = mat.NewSymDense(len(idx), nil)
+	v = mat.NewVecDense(len(idx), nil)
+	for i, oi := range idx {
+		h.SetRow(i, m.Observation.RawRowView(oi))
+		v.SetVec(i, y[oi])
+		for j := i; j < len(idx); j++ {
+			r.SetSym(i, j, m.ObservationNoise.At(oi, idx[j]))
+		}
+	}
+	return h, r, v
+}
+
+// Filtered holds the output of the Kalman filter over a sequence of T
+// observations. Element t of each slice corresponds to time step t.
+type Filtered struct {
+	// PredictedMean and PredictedCov are the mean and covariance of the
+	// state at time t given the observations before time t.
+	PredictedMean []*mat.VecDense
+	PredictedCov  []*mat.SymDense
+
+	// Mean and Cov are the mean and covariance of the state at time t
+	// given the observations up to and including time t.
+	Mean []*mat.VecDense
+	Cov  []*mat.SymDense
+
+	// LogLikelihood is the log-likelihood of the observations under the
+	// model, computed by the prediction error decomposition.
+	LogLikelihood float64
+}
+
+// Filter runs the Kalman filter over the observations in y, a T×k matrix with
+// the observation at time t in row t. NaN elements of y are treated as
+// missing. Filter will panic if the model matrices have inconsistent shapes
+// or y does not have k columns.
+func (m *Model) Filter(y mat.Matrix) *Filtered {
+	kf := NewKalmanFilter(m)
+	steps, c := y.Dims()
+	if c != kf.k {
+		panic("statespace: observation dimension mismatch")
+	}
+	f := &Filtered{
+		PredictedMean: make([]*mat.VecDense, steps),
+		PredictedCov:  make([]*mat.SymDense, steps),
+		Mean:          make([]*mat.VecDense, steps),
+		Cov:           make([]*mat.SymDense, steps),
+	}
+	obs := make([]float64, kf.k)
+	for t := 0; t < steps; t++ {
+		if t > 0 {
+			kf.Predict()
+		}
+		f.PredictedMean[t] = mat.VecDenseCopyOf(kf.mean)
+		f.PredictedCov[t] = mat.NewSymDense(kf.n, nil)
+		f.PredictedCov[t].CopySym(kf.cov)
+
+		f.LogLikelihood += kf.Update(mat.Row(obs, t, y))
+		f.Mean[t] = mat.VecDenseCopyOf(kf.mean)
+		f.Cov[t] = mat.NewSymDense(kf.n, nil)
+		f.Cov[t].CopySym(kf.cov)
+	}
+	return f
+}
+
+// Smoothed holds the output of the Rauch-Tung-Striebel smoother over a
+// sequence of T observations. Element t of each slice corresponds to time
+// step t.
+type Smoothed struct {
+	// Mean and Cov are the mean and covariance of the state at time t
+	// given all the observations.
+	Mean []*mat.VecDense
+	Cov  []*mat.SymDense
+
+	// LagCov holds the cross-covariances Cov(x_{t+1}, x_t) given all the
+	// observations, for t from 0 to T-2.
+	LagCov []*mat.Dense
+}
+
+// Smooth runs the Rauch-Tung-Striebel fixed-interval smoother backwards over
+// the output of the Kalman filter of the model on a sequence of observations.
+//  Rauch, H. E., Tung, F. and Striebel, C. T. "Maximum likelihood estimates
+//  of linear dynamic systems." AIAA Journal 3.8 (1965): 1445-1450.
+//
+// Smooth will panic if the predicted state covariance at any time is
+// singular.
+func (m *Model) Smooth(f *Filtered) *Smoothed {
+	n, _ := m.dims()
+	steps := len(f.Mean)
+	s := &Smoothed{
+		Mean:   make([]*mat.VecDense, steps),
+		Cov:    make([]*mat.SymDense, steps),
+		LagCov: make([]*mat.Dense, max(steps-1, 0)),
+	}
+	if steps == 0 {
+		return s
+	}
+	s.Mean[steps-1] = mat.VecDenseCopyOf(f.Mean[steps-1])
+	s.Cov[steps-1] = mat.NewSymDense(n, nil)
+	s.Cov[steps-1].CopySym(f.Cov[steps-1])
+	for t := steps - 2; t >= 0; t-- {
+		// The smoother gain J = P_{t|t} Fᵀ P_{t+1|t}⁻¹ is found from its
+		// transpose, the solution of P_{t+1|t} Jᵀ = F P_{t|t}.
+		var fp, jt mat.Dense
+		fp.Mul(m.Transition, f.Cov[t])
+		err := jt.Solve(f.PredictedCov[t+1], &fp)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				panic("statespace: singular predicted covariance")
+			}
+		}
+
+		var dm, mean mat.VecDense
+		dm.SubVec(s.Mean[t+1], f.PredictedMean[t+1])
+		mean.MulVec(jt.T(), &dm)
+		mean.AddVec(&mean, f.Mean[t])
+		s.Mean[t] = &mean
+
+		var dp, jdp, cov mat.Dense
+		dp.Sub(s.Cov[t+1], f.PredictedCov[t+1])
+		jdp.Mul(jt.T(), &dp)
+		cov.Mul(&jdp, &jt)
+		cov.Add(&cov, f.Cov[t])
+		s.Cov[t] = mat.NewSymDense(n, nil)
+		symmetrize(s.Cov[t], &cov)
+
+		// Cov(x_{t+1}, x_t) = P_{t+1|T} Jᵀ.
+		var lag mat.Dense
+		lag.Mul(s.Cov[t+1], &jt)
+		s.LagCov[t] = &lag
+	}
+	return s
+}
+
+// symmetrize stores the symmetric part of the square matrix a into dst.
+func symmetrize(dst *mat.SymDense, a mat.Matrix) {
+	n := dst.SymmetricDim()
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, (a.At(i, j)+a.At(j, i))/2)
+		}
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
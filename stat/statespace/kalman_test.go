@@ -0,0 +1,310 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func testModel() *Model {
+	return &Model{
+		Transition:       mat.NewDense(2, 2, []float64{0.9, 0.2, -0.1, 0.7}),
+		Observation:      mat.NewDense(2, 2, []float64{1, 0, 0.5, 1}),
+		ProcessNoise:     mat.NewSymDense(2, []float64{0.5, 0.1, 0.1, 0.3}),
+		ObservationNoise: mat.NewSymDense(2, []float64{0.4, -0.1, -0.1, 0.6}),
+		InitialMean:      mat.NewVecDense(2, []float64{1, -1}),
+		InitialCov:       mat.NewSymDense(2, []float64{2, 0.5, 0.5, 1}),
+	}
+}
+
+// joint returns the mean and covariance of the stacked vector of all states
+// followed by all observations of the model over the given number of steps.
+func joint(m *Model, steps int) (*mat.VecDense, *mat.SymDense) {
+	n, _ := m.Transition.Dims()
+	k, _ := m.Observation.Dims()
+	size := steps * (n + k)
+	mean := mat.NewVecDense(size, nil)
+	cov := mat.NewSymDense(size, nil)
+
+	// Means and covariances of the states.
+	means := make([]*mat.VecDense, steps)
+	vars := make([]*mat.Dense, steps)
+	means[0] = mat.VecDenseCopyOf(m.InitialMean)
+	vars[0] = mat.DenseCopyOf(m.InitialCov)
+	for t := 1; t < steps; t++ {
+		var mu mat.VecDense
+		mu.MulVec(m.Transition, means[t-1])
+		means[t] = &mu
+		var v mat.Dense
+		v.Mul(m.Transition, vars[t-1])
+		v.Mul(&v, m.Transition.T())
+		v.Add(&v, m.ProcessNoise)
+		vars[t] = &v
+	}
+	// cross[t][s] is Cov(x_t, x_s) for t >= s.
+	cross := make([][]*mat.Dense, steps)
+	for t := range cross {
+		cross[t] = make([]*mat.Dense, t+1)
+		cross[t][t] = vars[t]
+		for s := t - 1; s >= 0; s-- {
+			var c mat.Dense
+			c.Mul(m.Transition, cross[t-1][s])
+			cross[t][s] = &c
+		}
+	}
+	xcov := func(t, s int) mat.Matrix {
+		if t >= s {
+			return cross[t][s]
+		}
+		return cross[s][t].T()
+	}
+
+	yOff := steps * n
+	for t := 0; t < steps; t++ {
+		var ym mat.VecDense
+		ym.MulVec(m.Observation, means[t])
+		for i := 0; i < n; i++ {
+			mean.SetVec(t*n+i, means[t].AtVec(i))
+		}
+		for i := 0; i < k; i++ {
+			mean.SetVec(yOff+t*k+i, ym.AtVec(i))
+		}
+		for s := 0; s < steps; s++ {
+			c := xcov(t, s)
+			var yx, yy mat.Dense
+			yx.Mul(m.Observation, c)
+			yy.Mul(&yx, m.Observation.T())
+			if s == t {
+				yy.Add(&yy, m.ObservationNoise)
+			}
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if t*n+i <= s*n+j {
+						cov.SetSym(t*n+i, s*n+j, c.At(i, j))
+					}
+				}
+			}
+			for i := 0; i < k; i++ {
+				for j := 0; j < n; j++ {
+					cov.SetSym(s*n+j, yOff+t*k+i, yx.At(i, j))
+				}
+				for j := 0; j < k; j++ {
+					if t*k+i <= s*k+j {
+						cov.SetSym(yOff+t*k+i, yOff+s*k+j, yy.At(i, j))
+					}
+				}
+			}
+		}
+	}
+	return mean, cov
+}
+
+// condition returns the mean and covariance of the elements a of a Gaussian
+// vector given the values of the elements b, and the log-density of the
+// values.
+func condition(mean *mat.VecDense, cov *mat.SymDense, a, b []int, vals []float64) (*mat.VecDense, *mat.Dense, float64) {
+	sab := mat.NewDense(len(a), len(b), nil)
+	saa := mat.NewDense(len(a), len(a), nil)
+	sbb := mat.NewSymDense(len(b), nil)
+	res := mat.NewVecDense(len(b), nil)
+	for i, bi := range b {
+		res.SetVec(i, vals[i]-mean.AtVec(bi))
+		for j, bj := range b {
+			sbb.SetSym(i, j, cov.At(bi, bj))
+		}
+		for j, aj := range a {
+			sab.Set(j, i, cov.At(aj, bi))
+		}
+	}
+	for i, ai := range a {
+		for j, aj := range a {
+			saa.Set(i, j, cov.At(ai, aj))
+		}
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(sbb) {
+		panic("bad covariance")
+	}
+	var w mat.VecDense
+	chol.SolveVecTo(&w, res)
+	var g mat.Dense
+	chol.SolveTo(&g, sab.T())
+
+	mu := mat.NewVecDense(len(a), nil)
+	mu.MulVec(sab, &w)
+	for i, ai := range a {
+		mu.SetVec(i, mu.AtVec(i)+mean.AtVec(ai))
+	}
+	var c mat.Dense
+	c.Mul(sab, &g)
+	c.Sub(saa, &c)
+	ll := -0.5 * (float64(len(b))*math.Log(2*math.Pi) + chol.LogDet() + mat.Dot(res, &w))
+	return mu, &c, ll
+}
+
+func TestFilterSmooth(t *testing.T) {
+	t.Parallel()
+	m := testModel()
+	nan := math.NaN()
+	y := mat.NewDense(5, 2, []float64{
+		1.2, 0.3,
+		0.8, nan,
+		nan, nan,
+		-0.5, 1.1,
+		0.1, -0.7,
+	})
+	const n, k = 2, 2
+	steps, _ := y.Dims()
+	mean, cov := joint(m, steps)
+
+	// observedUpTo returns the stacked indices and values of the
+	// non-missing observations up to and including time t.
+	observedUpTo := func(t int) ([]int, []float64) {
+		var idx []int
+		var vals []float64
+		for s := 0; s <= t; s++ {
+			for i := 0; i < k; i++ {
+				if v := y.At(s, i); !math.IsNaN(v) {
+					idx = append(idx, steps*n+s*k+i)
+					vals = append(vals, v)
+				}
+			}
+		}
+		return idx, vals
+	}
+	states := make([]int, steps*n)
+	for i := range states {
+		states[i] = i
+	}
+
+	const tol = 1e-10
+	f := m.Filter(y)
+	for ti := 0; ti < steps; ti++ {
+		idx, vals := observedUpTo(ti)
+		mu, c, _ := condition(mean, cov, states[ti*n:(ti+1)*n], idx, vals)
+		if !mat.EqualApprox(f.Mean[ti], mu, tol) || !mat.EqualApprox(f.Cov[ti], c, tol) {
+			t.Errorf("unexpected filtered state at time %d:\ngot  %v %v\nwant %v %v",
+				ti, mat.Formatted(f.Mean[ti].T()), mat.Formatted(f.Cov[ti]), mat.Formatted(mu.T()), mat.Formatted(c))
+		}
+	}
+
+	idx, vals := observedUpTo(steps - 1)
+	mu, c, ll := condition(mean, cov, states, idx, vals)
+	if !scalar.EqualWithinAbsOrRel(f.LogLikelihood, ll, tol, tol) {
+		t.Errorf("unexpected log-likelihood: got %v, want %v", f.LogLikelihood, ll)
+	}
+
+	s := m.Smooth(f)
+	for ti := 0; ti < steps; ti++ {
+		if !mat.EqualApprox(s.Mean[ti], mu.SliceVec(ti*n, (ti+1)*n), tol) {
+			t.Errorf("unexpected smoothed mean at time %d: got %v, want %v",
+				ti, mat.Formatted(s.Mean[ti].T()), mat.Formatted(mu.SliceVec(ti*n, (ti+1)*n).T()))
+		}
+		if !mat.EqualApprox(s.Cov[ti], c.Slice(ti*n, (ti+1)*n, ti*n, (ti+1)*n), tol) {
+			t.Errorf("unexpected smoothed covariance at time %d", ti)
+		}
+		if ti < steps-1 {
+			if !mat.EqualApprox(s.LagCov[ti], c.Slice((ti+1)*n, (ti+2)*n, ti*n, (ti+1)*n), tol) {
+				t.Errorf("unexpected smoothed lag covariance at time %d", ti)
+			}
+		}
+	}
+}
+
+func TestKalmanFilterScalar(t *testing.T) {
+	t.Parallel()
+	// A local level model has the scalar filter recursion
+	//  p ← p + q
+	//  g = p / (p + r)
+	//  μ ← μ + g (y - μ)
+	//  p ← (1 - g) p
+	const q, r = 0.5, 2.0
+	m := &Model{
+		Transition:       mat.NewDense(1, 1, []float64{1}),
+		Observation:      mat.NewDense(1, 1, []float64{1}),
+		ProcessNoise:     mat.NewSymDense(1, []float64{q}),
+		ObservationNoise: mat.NewSymDense(1, []float64{r}),
+		InitialMean:      mat.NewVecDense(1, []float64{0}),
+		InitialCov:       mat.NewSymDense(1, []float64{10}),
+	}
+	kf := NewKalmanFilter(m)
+	mu, p := 0.0, 10.0
+	var mean mat.VecDense
+	var cov mat.SymDense
+	for i, y := range []float64{1.5, 2.1, 1.7, 3.2, 2.8} {
+		if i > 0 {
+			kf.Predict()
+			p += q
+		}
+		ll := kf.Update([]float64{y})
+		wantLL := -0.5 * (math.Log(2*math.Pi*(p+r)) + (y-mu)*(y-mu)/(p+r))
+		g := p / (p + r)
+		mu += g * (y - mu)
+		p *= 1 - g
+
+		kf.MeanTo(&mean)
+		kf.CovarianceTo(&cov)
+		if !scalar.EqualWithinAbsOrRel(mean.AtVec(0), mu, 1e-14, 1e-14) {
+			t.Errorf("step %d: unexpected mean: got %v, want %v", i, mean.AtVec(0), mu)
+		}
+		if !scalar.EqualWithinAbsOrRel(cov.At(0, 0), p, 1e-14, 1e-14) {
+			t.Errorf("step %d: unexpected variance: got %v, want %v", i, cov.At(0, 0), p)
+		}
+		if !scalar.EqualWithinAbsOrRel(ll, wantLL, 1e-14, 1e-14) {
+			t.Errorf("step %d: unexpected log-likelihood: got %v, want %v", i, ll, wantLL)
+		}
+	}
+
+	kf.Reset()
+	kf.MeanTo(&mean)
+	kf.CovarianceTo(&cov)
+	if mean.AtVec(0) != 0 || cov.At(0, 0) != 10 {
+		t.Errorf("unexpected state after reset: %v %v", mean.AtVec(0), cov.At(0, 0))
+	}
+}
+
+func TestKalmanPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "transition not square", fn: func() {
+			m := testModel()
+			m.Transition = mat.NewDense(2, 3, nil)
+			NewKalmanFilter(m)
+		}},
+		{name: "observation noise dimension", fn: func() {
+			m := testModel()
+			m.ObservationNoise = mat.NewSymDense(3, nil)
+			NewKalmanFilter(m)
+		}},
+		{name: "observation length", fn: func() {
+			NewKalmanFilter(testModel()).Update([]float64{1})
+		}},
+		{name: "observation columns", fn: func() {
+			testModel().Filter(mat.NewDense(3, 3, nil))
+		}},
+		{name: "mean destination", fn: func() {
+			NewKalmanFilter(testModel()).MeanTo(mat.NewVecDense(3, nil))
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,253 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+)
+
+// TDigest is a streaming sketch of a weighted sample that estimates quantiles
+// and the empirical CDF using memory bounded by its compression parameter,
+// independent of the number of values added.
+//
+// TDigest implements the merging t-digest of Dunning and Ertl with the
+// arcsine scale function, which concentrates accuracy in the tails of the
+// distribution so that extreme quantiles such as p99.9 are estimated with
+// small relative rank error.
+//  Dunning, T. and Ertl, O. "Computing extremely accurate quantiles using
+//  t-digests." arXiv:1902.04023 (2019).
+//
+// Sketches of separate shards of a sample may be combined with Merge.
+type TDigest struct {
+	compression float64
+
+	// centroids holds the compressed clusters sorted by mean, and
+	// buffer holds clusters that have not yet been merged into them.
+	centroids []centroid
+	buffer    []centroid
+	scratch   []centroid
+
+	sumWeights float64
+	min, max   float64
+}
+
+// centroid is a cluster of values with the given mean and total weight.
+type centroid struct {
+	mean, weight float64
+}
+
+// NewTDigest returns a new empty t-digest with the given compression. Larger
+// values of compression give more accurate estimates at the cost of more
+// memory; the number of centroids retained is at most about compression.
+// A compression of 100 gives quantile estimates accurate to well under 1% in
+// rank for most distributions. NewTDigest will panic if compression is less
+// than 10.
+func NewTDigest(compression float64) *TDigest {
+	if !(compression >= 10) {
+		panic("stat: compression too small")
+	}
+	return &TDigest{compression: compression}
+}
+
+// Add adds the value x with the given weight to the sketch. Values with a
+// weight of zero are ignored. Add will panic if weight is negative.
+func (t *TDigest) Add(x, weight float64) {
+	if weight < 0 {
+		panic("stat: negative weight")
+	}
+	if weight == 0 {
+		return
+	}
+	t.add(centroid{mean: x, weight: weight}, x, x)
+}
+
+func (t *TDigest) add(c centroid, min, max float64) {
+	if t.sumWeights == 0 {
+		t.min, t.max = min, max
+	} else {
+		t.min = math.Min(t.min, min)
+		t.max = math.Max(t.max, max)
+	}
+	t.sumWeights += c.weight
+	t.buffer = append(t.buffer, c)
+	if float64(len(t.buffer)) >= 5*t.compression {
+		t.compress()
+	}
+}
+
+// Merge adds the sample summarized by o to the receiver. o is not modified.
+func (t *TDigest) Merge(o *TDigest) {
+	if o.sumWeights == 0 {
+		return
+	}
+	// The extremes of o are added with the first cluster so that
+	// the combined range is correct.
+	first := true
+	for _, clusters := range [][]centroid{o.centroids, o.buffer} {
+		for _, c := range clusters {
+			if first {
+				t.add(c, o.min, o.max)
+				first = false
+				continue
+			}
+			t.add(c, c.mean, c.mean)
+		}
+	}
+}
+
+// Reset clears the sketch, retaining its compression.
+func (t *TDigest) Reset() {
+	t.centroids = t.centroids[:0]
+	t.buffer = t.buffer[:0]
+	t.sumWeights = 0
+	t.min, t.max = 0, 0
+}
+
+// SumWeights returns the sum of the weights of the values added to the sketch.
+func (t *TDigest) SumWeights() float64 {
+	return t.sumWeights
+}
+
+// Min returns the minimum value added to the sketch, or NaN if the sketch
+// is empty.
+func (t *TDigest) Min() float64 {
+	if t.sumWeights == 0 {
+		return math.NaN()
+	}
+	return t.min
+}
+
+// Max returns the maximum value added to the sketch, or NaN if the sketch
+// is empty.
+func (t *TDigest) Max() float64 {
+	if t.sumWeights == 0 {
+		return math.NaN()
+	}
+	return t.max
+}
+
+// Centroids returns the number of clusters currently used to summarize the
+// sample.
+func (t *TDigest) Centroids() int {
+	t.compress()
+	return len(t.centroids)
+}
+
+// Quantile returns an estimate of the value x such that the fraction p of the
+// weight of the sample is less than or equal to x. p must be between 0 and 1,
+// and Quantile will panic otherwise. Quantile returns NaN if the sketch is
+// empty.
+//
+// The estimate interpolates linearly between the means of adjacent clusters,
+// each of which is taken to be centered at the middle of its cumulative
+// weight, and between the extreme clusters and the minimum and maximum.
+func (t *TDigest) Quantile(p float64) float64 {
+	if !(p >= 0 && p <= 1) {
+		panic("stat: percentile out of bounds")
+	}
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+
+	target := p * t.sumWeights
+	cs := t.centroids
+	first := cs[0]
+	if target < first.weight/2 {
+		return t.min + (first.mean-t.min)*target/(first.weight/2)
+	}
+	cum := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		next := cum + (cs[i].weight+cs[i+1].weight)/2
+		if target <= next {
+			frac := (target - cum) / (next - cum)
+			return cs[i].mean + frac*(cs[i+1].mean-cs[i].mean)
+		}
+		cum = next
+	}
+	last := cs[len(cs)-1]
+	frac := math.Min(1, (target-cum)/(last.weight/2))
+	return last.mean + frac*(t.max-last.mean)
+}
+
+// CDF returns an estimate of the fraction of the weight of the sample that is
+// less than or equal to q. CDF returns NaN if the sketch is empty.
+//
+// The estimate is the inverse of the interpolation used by Quantile.
+func (t *TDigest) CDF(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if q < t.min {
+		return 0
+	}
+	if q >= t.max {
+		return 1
+	}
+
+	cs := t.centroids
+	first := cs[0]
+	if q < first.mean {
+		return (q - t.min) / (first.mean - t.min) * first.weight / 2 / t.sumWeights
+	}
+	cum := first.weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		w := (cs[i].weight + cs[i+1].weight) / 2
+		if q < cs[i+1].mean {
+			return (cum + (q-cs[i].mean)/(cs[i+1].mean-cs[i].mean)*w) / t.sumWeights
+		}
+		cum += w
+	}
+	last := cs[len(cs)-1]
+	return (cum + (q-last.mean)/(t.max-last.mean)*last.weight/2) / t.sumWeights
+}
+
+// compress merges the buffered clusters into the sorted centroids, combining
+// adjacent clusters while their combined weight remains within the limit
+// given by the scale function.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.scratch[:0], t.centroids...)
+	all = append(all, t.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	out := t.centroids[:0]
+	cur := all[0]
+	var sofar float64
+	limit := t.sumWeights * t.kInv(t.k(0)+1)
+	for _, c := range all[1:] {
+		if sofar+cur.weight+c.weight <= limit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		sofar += cur.weight
+		out = append(out, cur)
+		limit = t.sumWeights * t.kInv(t.k(sofar/t.sumWeights)+1)
+		cur = c
+	}
+	t.centroids = append(out, cur)
+	t.scratch = all
+	t.buffer = t.buffer[:0]
+}
+
+// k is the arcsine scale function mapping a quantile to the scale on which
+// clusters have unit size.
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// kInv is the inverse of k.
+func (t *TDigest) kInv(k float64) float64 {
+	x := k * 2 * math.Pi / t.compression
+	if x >= math.Pi/2 {
+		return 1
+	}
+	return (math.Sin(x) + 1) / 2
+}
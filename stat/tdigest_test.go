@@ -0,0 +1,121 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestTDigest(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100000
+	for _, dist := range []struct {
+		name string
+		rand func() float64
+	}{
+		{"normal", rnd.NormFloat64},
+		{"exponential", rnd.ExpFloat64},
+		{"uniform", rnd.Float64},
+		{"lognormal", func() float64 { return math.Exp(2 * rnd.NormFloat64()) }},
+	} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = dist.rand()
+		}
+
+		td := NewTDigest(100)
+		for _, v := range x {
+			td.Add(v, 1)
+		}
+		// Sketch the same sample in shards and merge them.
+		merged := NewTDigest(100)
+		for i := 0; i < 10; i++ {
+			shard := NewTDigest(100)
+			for _, v := range x[i*n/10 : (i+1)*n/10] {
+				shard.Add(v, 1)
+			}
+			merged.Merge(shard)
+		}
+		sort.Float64s(x)
+
+		for _, sketch := range []struct {
+			name string
+			td   *TDigest
+		}{{"sequential", td}, {"merged", merged}} {
+			if got := sketch.td.SumWeights(); got != n {
+				t.Errorf("%s %s: unexpected sum of weights: %v", dist.name, sketch.name, got)
+			}
+			if sketch.td.Min() != x[0] || sketch.td.Max() != x[n-1] {
+				t.Errorf("%s %s: unexpected extremes", dist.name, sketch.name)
+			}
+			if c := sketch.td.Centroids(); c > 100 {
+				t.Errorf("%s %s: too many centroids: %d", dist.name, sketch.name, c)
+			}
+			if sketch.td.Quantile(0) != x[0] || sketch.td.Quantile(1) != x[n-1] {
+				t.Errorf("%s %s: unexpected extreme quantiles", dist.name, sketch.name)
+			}
+			for _, p := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+				// The rank error allowed shrinks in the tails.
+				tol := 0.005 * math.Sqrt(p*(1-p)) * 4
+				q := sketch.td.Quantile(p)
+				rank := float64(sort.SearchFloat64s(x, q)) / n
+				if math.Abs(rank-p) > tol {
+					t.Errorf("%s %s: quantile %v has rank %v", dist.name, sketch.name, p, rank)
+				}
+				cdf := sketch.td.CDF(x[int(p*n)])
+				if math.Abs(cdf-p) > tol {
+					t.Errorf("%s %s: CDF at true %v quantile is %v", dist.name, sketch.name, p, cdf)
+				}
+			}
+		}
+	}
+}
+
+func TestTDigestSmall(t *testing.T) {
+	t.Parallel()
+	td := NewTDigest(100)
+	if !math.IsNaN(td.Quantile(0.5)) || !math.IsNaN(td.CDF(0)) {
+		t.Error("expected NaN for empty sketch")
+	}
+
+	// Small samples are held exactly, one cluster per value.
+	for _, v := range []float64{5, 1, 4, 2, 3} {
+		td.Add(v, 1)
+	}
+	td.Add(100, 0)
+	if c := td.Centroids(); c != 5 {
+		t.Errorf("unexpected number of centroids: %d", c)
+	}
+	for _, test := range []struct{ p, want float64 }{
+		{0, 1}, {0.1, 1}, {0.3, 2}, {0.5, 3}, {0.6, 3.5}, {0.9, 5}, {1, 5},
+	} {
+		if got := td.Quantile(test.p); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected quantile %v: got %v, want %v", test.p, got, test.want)
+		}
+	}
+	for _, test := range []struct{ q, want float64 }{
+		{0, 0}, {1, 0.1}, {3, 0.5}, {3.5, 0.6}, {5, 1}, {6, 1},
+	} {
+		if got := td.CDF(test.q); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected CDF at %v: got %v, want %v", test.q, got, test.want)
+		}
+	}
+
+	td.Reset()
+	if td.SumWeights() != 0 || !math.IsNaN(td.Min()) {
+		t.Error("Reset did not clear the sketch")
+	}
+	if !panics(func() { td.Add(1, -1) }) {
+		t.Error("expected panic for negative weight")
+	}
+	if !panics(func() { td.Quantile(1.5) }) {
+		t.Error("expected panic for out of bounds percentile")
+	}
+}
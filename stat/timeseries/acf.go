@@ -0,0 +1,115 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
+)
+
+// ACovF computes the sample autocovariance of x at lags 0 through maxLag,
+//  γ(k) = 1/n Σ_{t=k}^{n-1} (x_t - mean(x)) (x_{t-k} - mean(x))
+// The divisor n makes the sequence of autocovariances positive semi-definite.
+// If dst is nil, a new slice is allocated and returned, otherwise the result
+// is stored in place into dst. ACovF will panic if maxLag is negative or not
+// less than len(x), or if dst is not nil and its length is not maxLag+1.
+func ACovF(dst, x []float64, maxLag int) []float64 {
+	n := len(x)
+	if maxLag < 0 || maxLag >= n {
+		panic("timeseries: lag out of range")
+	}
+	if dst == nil {
+		dst = make([]float64, maxLag+1)
+	}
+	if len(dst) != maxLag+1 {
+		panic("timeseries: slice length mismatch")
+	}
+	mean := stat.Mean(x, nil)
+	for k := range dst {
+		var sum float64
+		for t := k; t < n; t++ {
+			sum += (x[t] - mean) * (x[t-k] - mean)
+		}
+		dst[k] = sum / float64(n)
+	}
+	return dst
+}
+
+// ACF computes the sample autocorrelation of x at lags 0 through maxLag, the
+// autocovariances given by ACovF divided by the variance γ(0). If dst is nil,
+// a new slice is allocated and returned, otherwise the result is stored in
+// place into dst. ACF will panic if maxLag is negative or not less than len(x),
+// or if dst is not nil and its length is not maxLag+1.
+func ACF(dst, x []float64, maxLag int) []float64 {
+	dst = ACovF(dst, x, maxLag)
+	v := dst[0]
+	for k := range dst {
+		dst[k] /= v
+	}
+	return dst
+}
+
+// PACF computes the sample partial autocorrelation of x at lags 1 through
+// maxLag using the Durbin-Levinson recursion on the sample autocorrelations.
+// The partial autocorrelation at lag k is stored in dst[k-1]. If dst is nil,
+// a new slice is allocated and returned, otherwise the result is stored in
+// place into dst. PACF will panic if maxLag is not positive or not less than
+// len(x), or if dst is not nil and its length is not maxLag.
+func PACF(dst, x []float64, maxLag int) []float64 {
+	if maxLag < 1 {
+		panic("timeseries: lag out of range")
+	}
+	if dst == nil {
+		dst = make([]float64, maxLag)
+	}
+	if len(dst) != maxLag {
+		panic("timeseries: slice length mismatch")
+	}
+	r := ACF(nil, x, maxLag)
+	phi := make([]float64, maxLag)
+	prev := make([]float64, maxLag)
+	for k := 1; k <= maxLag; k++ {
+		num, den := r[k], 1.0
+		for j := 1; j < k; j++ {
+			num -= prev[j-1] * r[k-j]
+			den -= prev[j-1] * r[j]
+		}
+		phi[k-1] = num / den
+		for j := 1; j < k; j++ {
+			phi[j-1] = prev[j-1] - phi[k-1]*prev[k-j-1]
+		}
+		copy(prev, phi[:k])
+		dst[k-1] = phi[k-1]
+	}
+	return dst
+}
+
+// LjungBox performs the Ljung-Box portmanteau test of the null hypothesis
+// that the series x, usually the residuals of a fitted model, has no
+// autocorrelation up to the given number of lags. It returns the statistic
+//  Q = n (n+2) Σ_{k=1}^{lags} r_k^2 / (n-k)
+// and its p-value under the asymptotic χ² distribution with lags-fitDF
+// degrees of freedom, where fitDF is the number of fitted model parameters,
+// p+q for an ARIMA(p, d, q) model.
+//  Ljung, G. M. and Box, G. E. P. "On a measure of lack of fit in time series
+//  models." Biometrika 65.2 (1978): 297-303.
+//
+// LjungBox will panic if lags is not positive or not less than len(x), or if
+// fitDF is negative or not less than lags.
+func LjungBox(x []float64, lags, fitDF int) (q, p float64) {
+	if lags < 1 || lags >= len(x) {
+		panic("timeseries: lag out of range")
+	}
+	if fitDF < 0 || fitDF >= lags {
+		panic("timeseries: invalid degrees of freedom")
+	}
+	n := float64(len(x))
+	r := ACF(nil, x, lags)
+	for k := 1; k <= lags; k++ {
+		q += r[k] * r[k] / (n - float64(k))
+	}
+	q *= n * (n + 2)
+	return q, mathext.GammaIncRegComp(float64(lags-fitDF)/2, q/2)
+}
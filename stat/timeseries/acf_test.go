@@ -0,0 +1,162 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// simulateARMA returns n values of an ARMA process with the given
+// coefficients, mean and unit innovation variance, after a burn-in period.
+func simulateARMA(rnd *rand.Rand, n int, ar, ma []float64, mean float64) []float64 {
+	const burn = 500
+	x := make([]float64, n+burn)
+	e := make([]float64, n+burn)
+	for t := range x {
+		e[t] = rnd.NormFloat64()
+		v := e[t]
+		for i, phi := range ar {
+			if t-i-1 >= 0 {
+				v += phi * x[t-i-1]
+			}
+		}
+		for j, theta := range ma {
+			if t-j-1 >= 0 {
+				v += theta * e[t-j-1]
+			}
+		}
+		x[t] = v
+	}
+	x = x[burn:]
+	for t := range x {
+		x[t] += mean
+	}
+	return x
+}
+
+func TestACF(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 5, 4, 6, 8, 7}
+	const maxLag = 3
+	mean := floats.Sum(x) / float64(len(x))
+	want := make([]float64, maxLag+1)
+	for k := range want {
+		for i := k; i < len(x); i++ {
+			want[k] += (x[i] - mean) * (x[i-k] - mean)
+		}
+		want[k] /= float64(len(x))
+	}
+	if got := ACovF(nil, x, maxLag); !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected autocovariance: got %v, want %v", got, want)
+	}
+	floats.Scale(1/want[0], want)
+	if got := ACF(nil, x, maxLag); !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected autocorrelation: got %v, want %v", got, want)
+	}
+
+	// The partial autocorrelation at lag k is the last coefficient
+	// of the solution of the order k Yule-Walker equations.
+	rnd := rand.New(rand.NewSource(1))
+	y := simulateARMA(rnd, 300, []float64{0.6, -0.2}, []float64{0.4}, 0)
+	const lags = 6
+	r := ACF(nil, y, lags)
+	pacf := PACF(nil, y, lags)
+	for k := 1; k <= lags; k++ {
+		toeplitz := mat.NewDense(k, k, nil)
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				toeplitz.Set(i, j, r[abs(i-j)])
+			}
+		}
+		var phi mat.VecDense
+		if err := phi.SolveVec(toeplitz, mat.NewVecDense(k, append([]float64(nil), r[1:k+1]...))); err != nil {
+			t.Fatalf("unexpected error solving Yule-Walker equations: %v", err)
+		}
+		if want := phi.AtVec(k - 1); !scalar.EqualWithinAbsOrRel(pacf[k-1], want, 1e-12, 1e-12) {
+			t.Errorf("unexpected partial autocorrelation at lag %d: got %v, want %v", k, pacf[k-1], want)
+		}
+	}
+
+	// The partial autocorrelations of an AR(1) process vanish
+	// beyond the first lag.
+	z := simulateARMA(rnd, 5000, []float64{0.7}, nil, 0)
+	pacf = PACF(nil, z, 5)
+	if math.Abs(pacf[0]-0.7) > 0.03 {
+		t.Errorf("unexpected first partial autocorrelation: got %v, want 0.7", pacf[0])
+	}
+	for k, v := range pacf[1:] {
+		if math.Abs(v) > 3/math.Sqrt(5000) {
+			t.Errorf("unexpected partial autocorrelation at lag %d: %v", k+2, v)
+		}
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func TestLjungBox(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 5, 4, 6, 8, 7}
+	r := ACF(nil, x, 2)
+	n := 8.0
+	wantQ := n * (n + 2) * (r[1]*r[1]/(n-1) + r[2]*r[2]/(n-2))
+	q, p := LjungBox(x, 2, 0)
+	if !scalar.EqualWithinAbsOrRel(q, wantQ, 1e-14, 1e-14) {
+		t.Errorf("unexpected statistic: got %v, want %v", q, wantQ)
+	}
+	// The χ² distribution with two degrees of freedom has survival
+	// function exp(-q/2).
+	if want := math.Exp(-q / 2); !scalar.EqualWithinAbsOrRel(p, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected p-value: got %v, want %v", p, want)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	noise := simulateARMA(rnd, 500, nil, nil, 0)
+	if _, p := LjungBox(noise, 10, 0); p < 0.05 {
+		t.Errorf("white noise rejected: p=%v", p)
+	}
+	ar := simulateARMA(rnd, 500, []float64{0.5}, nil, 0)
+	if _, p := LjungBox(ar, 10, 0); p > 1e-6 {
+		t.Errorf("autocorrelated series not detected: p=%v", p)
+	}
+}
+
+func TestACFPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3, 4}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "negative lag", fn: func() { ACF(nil, x, -1) }},
+		{name: "lag too large", fn: func() { ACovF(nil, x, 4) }},
+		{name: "destination length", fn: func() { ACF(make([]float64, 2), x, 2) }},
+		{name: "zero PACF lag", fn: func() { PACF(nil, x, 0) }},
+		{name: "Ljung-Box degrees of freedom", fn: func() { LjungBox(x, 2, 2) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,457 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Method specifies the criterion used to estimate the parameters of an ARIMA
+// model.
+type Method int
+
+const (
+	// MaximumLikelihood estimates the parameters by maximizing the
+	// exact Gaussian likelihood of the differenced series, computed
+	// with a Kalman filter. The optimization is started from the
+	// ConditionalSumOfSquares estimate.
+	MaximumLikelihood Method = iota
+
+	// ConditionalSumOfSquares estimates the parameters by minimizing
+	// the sum of squared residuals conditional on the first p values
+	// of the differenced series, with the residuals before the start
+	// of the series taken to be zero.
+	ConditionalSumOfSquares
+)
+
+// ARIMA specifies an autoregressive integrated moving average model of order
+// (P, D, Q). The model states that the series differenced D times, w_t,
+// satisfies
+//  w_t - μ = Σ_{i=1}^P φ_i (w_{t-i} - μ) + e_t + Σ_{j=1}^Q θ_j e_{t-j}
+// where e_t is Gaussian white noise with variance σ².
+//  Box, G. E. P., Jenkins, G. M., Reinsel, G. C. and Ljung, G. M. "Time
+//  Series Analysis: Forecasting and Control." 5th ed. Wiley (2015).
+type ARIMA struct {
+	// P, D and Q are the autoregressive order, the number of
+	// differences and the moving average order of the model.
+	P, D, Q int
+
+	// IncludeMean specifies whether the mean μ of the differenced
+	// series is estimated. If IncludeMean is false, μ is zero.
+	IncludeMean bool
+
+	// Method is the estimation criterion.
+	Method Method
+}
+
+// ARIMAModel is an ARIMA model fit to a series.
+type ARIMAModel struct {
+	// Order is the specification of the model.
+	Order ARIMA
+
+	// AR and MA hold the autoregressive coefficients φ and the
+	// moving average coefficients θ of the model.
+	AR, MA []float64
+
+	// Mean is the mean μ of the differenced series.
+	Mean float64
+
+	// Variance is the estimated innovation variance σ².
+	Variance float64
+
+	// LogLikelihood is the Gaussian log-likelihood of the
+	// differenced series under the fitted model, conditional on the
+	// first P values when the model was fit by
+	// ConditionalSumOfSquares.
+	LogLikelihood float64
+
+	x []float64 // Observed series.
+	w []float64 // Differenced series.
+}
+
+// Fit estimates the parameters of the model for the series x. The estimated
+// model is stationary and invertible.
+//
+// Fit will panic if an order is negative or if the differenced series has no
+// more than P+k observations, where k is the number of estimated coefficients
+// including the mean. Fit returns an error if the
+// optimization of the estimation criterion fails.
+func (a ARIMA) Fit(x []float64) (*ARIMAModel, error) {
+	if a.P < 0 || a.D < 0 || a.Q < 0 {
+		panic("timeseries: negative ARIMA order")
+	}
+	if a.Method != MaximumLikelihood && a.Method != ConditionalSumOfSquares {
+		panic("timeseries: unknown estimation method")
+	}
+	w := difference(x, a.D)
+	nPar := a.P + a.Q
+	if a.IncludeMean {
+		nPar++
+	}
+	if len(w) <= a.P+nPar {
+		panic("timeseries: series too short for ARIMA order")
+	}
+
+	m := &ARIMAModel{
+		Order: a,
+		AR:    make([]float64, a.P),
+		MA:    make([]float64, a.Q),
+		x:     append([]float64(nil), x...),
+		w:     w,
+	}
+
+	// The parameters are optimized in an unconstrained space that
+	// maps onto the stationary and invertible region.
+	u := make([]float64, nPar)
+	if a.IncludeMean {
+		u[nPar-1] = stat.Mean(w, nil)
+	}
+	if nPar == 0 {
+		m.finish()
+		return m, nil
+	}
+
+	css := func(u []float64) float64 {
+		m.setParams(u)
+		ss, n := m.css()
+		return 0.5 * math.Log(ss/float64(n))
+	}
+	u, err := minimize(css, u)
+	if err != nil {
+		return nil, err
+	}
+	if a.Method == MaximumLikelihood {
+		ml := func(u []float64) float64 {
+			m.setParams(u)
+			ll, _ := m.exactLogLikelihood()
+			return -ll / float64(len(w))
+		}
+		u, err = minimize(ml, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.setParams(u)
+	m.finish()
+	return m, nil
+}
+
+// minimize returns the minimizer of f starting from x0, using quasi-Newton
+// steps with a finite difference gradient.
+func minimize(f func([]float64) float64, x0 []float64) ([]float64, error) {
+	problem := optimize.Problem{
+		Func: f,
+		Grad: func(grad, x []float64) {
+			fd.Gradient(grad, f, x, &fd.Settings{Formula: fd.Central})
+		},
+	}
+	settings := &optimize.Settings{
+		GradientThreshold: 1e-8,
+		Converger: &optimize.FunctionConverge{
+			Absolute:   1e-12,
+			Iterations: 50,
+		},
+	}
+	res, err := optimize.Minimize(problem, x0, settings, &optimize.BFGS{})
+	if res == nil {
+		return nil, err
+	}
+	if err != nil && !(res.F <= f(x0)) {
+		return nil, err
+	}
+	return res.X, nil
+}
+
+// setParams sets the model parameters from the unconstrained parameters u.
+func (m *ARIMAModel) setParams(u []float64) {
+	p, q := m.Order.P, m.Order.Q
+	pacfToAR(m.AR, u[:p])
+	pacfToAR(m.MA, u[p:p+q])
+	for j := range m.MA {
+		m.MA[j] = -m.MA[j]
+	}
+	if m.Order.IncludeMean {
+		m.Mean = u[p+q]
+	}
+}
+
+// finish sets the variance and log-likelihood of the model for the
+// estimation method.
+func (m *ARIMAModel) finish() {
+	if m.Order.Method == ConditionalSumOfSquares {
+		ss, n := m.css()
+		m.Variance = ss / float64(n)
+		m.LogLikelihood = -0.5 * float64(n) * (math.Log(2*math.Pi*m.Variance) + 1)
+	} else {
+		m.LogLikelihood, m.Variance = m.exactLogLikelihood()
+	}
+}
+
+// pacfToAR stores in dst the coefficients of the stationary autoregressive
+// polynomial whose partial autocorrelations are tanh(u), using the
+// Durbin-Levinson recursion.
+//  Jones, R. H. "Maximum likelihood fitting of ARMA models to time series
+//  with missing observations." Technometrics 22.3 (1980): 389-395.
+func pacfToAR(dst, u []float64) {
+	prev := make([]float64, len(u))
+	for k := range u {
+		r := math.Tanh(u[k])
+		for j := 0; j < k; j++ {
+			dst[j] = prev[j] - r*prev[k-1-j]
+		}
+		dst[k] = r
+		copy(prev, dst[:k+1])
+	}
+}
+
+// difference returns the series x differenced d times.
+func difference(x []float64, d int) []float64 {
+	w := append([]float64(nil), x...)
+	for i := 0; i < d && len(w) > 0; i++ {
+		for t := 0; t < len(w)-1; t++ {
+			w[t] = w[t+1] - w[t]
+		}
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+// residuals stores in dst the conditional residuals of the differenced series,
+// with the residuals before time P taken to be zero, and returns their sum of
+// squares and the number of residuals after time P.
+func (m *ARIMAModel) residuals(dst []float64) (ss float64, n int) {
+	p := len(m.AR)
+	for t, v := range m.w {
+		if t < p {
+			dst[t] = 0
+			continue
+		}
+		e := v - m.Mean
+		for i, phi := range m.AR {
+			e -= phi * (m.w[t-i-1] - m.Mean)
+		}
+		for j, theta := range m.MA {
+			if t-j-1 >= 0 {
+				e -= theta * dst[t-j-1]
+			}
+		}
+		dst[t] = e
+		ss += e * e
+	}
+	return ss, len(m.w) - p
+}
+
+func (m *ARIMAModel) css() (ss float64, n int) {
+	return m.residuals(make([]float64, len(m.w)))
+}
+
+// exactLogLikelihood returns the exact Gaussian log-likelihood of the
+// differenced series and the maximum likelihood estimate of the innovation
+// variance, computed with a Kalman filter on the state space form of the ARMA
+// model with the variance concentrated out.
+//  Gardner, G., Harvey, A. C. and Phillips, G. D. A. "Algorithm AS 154: An
+//  algorithm for exact maximum likelihood estimation of autoregressive-moving
+//  average models by means of Kalman filtering." Journal of the Royal
+//  Statistical Society C 29.3 (1980): 311-322.
+func (m *ARIMAModel) exactLogLikelihood() (ll, variance float64) {
+	p, q := len(m.AR), len(m.MA)
+	r := p
+	if q+1 > r {
+		r = q + 1
+	}
+	trans := mat.NewDense(r, r, nil)
+	for i, phi := range m.AR {
+		trans.Set(i, 0, phi)
+	}
+	for i := 0; i < r-1; i++ {
+		trans.Set(i, i+1, 1)
+	}
+	g := make([]float64, r)
+	g[0] = 1
+	copy(g[1:], m.MA)
+	noise := mat.NewSymDense(r, nil)
+	noise.SymOuterK(1, mat.NewDense(r, 1, g))
+
+	// The initial state covariance is the stationary covariance,
+	// the solution of P = T P T^T + g g^T.
+	var kron mat.Dense
+	kron.Kronecker(trans, trans)
+	sys := mat.NewDense(r*r, r*r, nil)
+	for i := 0; i < r*r; i++ {
+		sys.Set(i, i, 1)
+	}
+	sys.Sub(sys, &kron)
+	rhs := mat.NewVecDense(r*r, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			rhs.SetVec(i*r+j, noise.At(i, j))
+		}
+	}
+	var vecP mat.VecDense
+	if err := vecP.SolveVec(sys, rhs); err != nil {
+		return math.Inf(-1), math.NaN()
+	}
+	cov := mat.NewDense(r, r, vecP.RawVector().Data)
+
+	state := make([]float64, r)
+	next := make([]float64, r)
+	gain := make([]float64, r)
+	var tmp, pred mat.Dense
+	var sumLog, ssq float64
+	n := float64(len(m.w))
+	for _, v := range m.w {
+		// Update the state with the observation.
+		innov := v - m.Mean - state[0]
+		f := cov.At(0, 0)
+		if !(f > 0) {
+			return math.Inf(-1), math.NaN()
+		}
+		sumLog += math.Log(f)
+		ssq += innov * innov / f
+		for i := range gain {
+			gain[i] = cov.At(i, 0) / f
+			state[i] += gain[i] * innov
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				cov.Set(i, j, cov.At(i, j)-gain[i]*f*gain[j])
+			}
+		}
+
+		// Predict the next state.
+		for i := range next {
+			next[i] = 0
+			for j, s := range state {
+				next[i] += trans.At(i, j) * s
+			}
+		}
+		state, next = next, state
+		tmp.Mul(trans, cov)
+		pred.Mul(&tmp, trans.T())
+		pred.Add(&pred, noise)
+		cov.Copy(&pred)
+	}
+	variance = ssq / n
+	ll = -0.5 * (n*math.Log(2*math.Pi*variance) + sumLog + n)
+	return ll, variance
+}
+
+// AIC returns the Akaike information criterion of the fitted model,
+//  -2 log L + 2 k
+// where k is the number of estimated parameters including the variance.
+func (m *ARIMAModel) AIC() float64 {
+	k := len(m.AR) + len(m.MA) + 1
+	if m.Order.IncludeMean {
+		k++
+	}
+	return -2*m.LogLikelihood + 2*float64(k)
+}
+
+// Residuals returns the residuals of the model for the differenced series,
+// computed by the conditional recursion with the residuals before time P taken
+// to be zero. The first P residuals are zero. If dst is nil, a new slice is
+// allocated and returned, otherwise the result is stored in place into dst.
+// Residuals will panic if dst is not nil and its length is not the length of
+// the differenced series.
+func (m *ARIMAModel) Residuals(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(m.w))
+	}
+	if len(dst) != len(m.w) {
+		panic("timeseries: slice length mismatch")
+	}
+	m.residuals(dst)
+	return dst
+}
+
+// Forecast returns the h-step ahead forecasts of the series following the
+// observed values and the bounds of the prediction intervals with the given
+// coverage level. The forecast errors are computed from the ψ-weights of the
+// model, assuming that the innovations are Gaussian and the parameters are
+// known. Forecast will panic if h is negative or level is not in (0, 1).
+func (m *ARIMAModel) Forecast(h int, level float64) (mean, lower, upper []float64) {
+	if h < 0 {
+		panic("timeseries: negative forecast horizon")
+	}
+	if !(0 < level && level < 1) {
+		panic("timeseries: level out of range")
+	}
+
+	// Combine the autoregressive polynomial with the differences,
+	// φ*(B) = φ(B) (1-B)^D, so the forecasts can be made on the
+	// scale of the observed series.
+	poly := []float64{1}
+	for _, phi := range m.AR {
+		poly = append(poly, -phi)
+	}
+	for i := 0; i < m.Order.D; i++ {
+		next := make([]float64, len(poly)+1)
+		for k, c := range poly {
+			next[k] += c
+			next[k+1] -= c
+		}
+		poly = next
+	}
+	ar := make([]float64, len(poly)-1)
+	for i := range ar {
+		ar[i] = -poly[i+1]
+	}
+
+	// The mean of the differenced series enters the recursion on the
+	// observed scale as the constant μ φ(1).
+	c := m.Mean
+	for _, phi := range m.AR {
+		c -= phi * m.Mean
+	}
+	n := len(m.x)
+	y := make([]float64, n+h)
+	copy(y, m.x)
+	// Residuals are indexed on the observed series; those within
+	// the first D observations are zero.
+	e := make([]float64, n+h)
+	m.residuals(e[m.Order.D:n])
+	for t := n; t < n+h; t++ {
+		v := c
+		for i, a := range ar {
+			v += a * y[t-i-1]
+		}
+		for j, theta := range m.MA {
+			v += theta * e[t-j-1]
+		}
+		y[t] = v
+	}
+
+	psi := make([]float64, h)
+	if h > 0 {
+		psi[0] = 1
+	}
+	for j := 1; j < h; j++ {
+		if j <= len(m.MA) {
+			psi[j] = m.MA[j-1]
+		}
+		for i := 1; i <= j && i <= len(ar); i++ {
+			psi[j] += ar[i-1] * psi[j-i]
+		}
+	}
+	z := mathext.NormalQuantile((1 + level) / 2)
+	mean = make([]float64, h)
+	lower = make([]float64, h)
+	upper = make([]float64, h)
+	var sumPsi2 float64
+	for k := 0; k < h; k++ {
+		sumPsi2 += psi[k] * psi[k]
+		se := math.Sqrt(m.Variance * sumPsi2)
+		mean[k] = y[n+k]
+		lower[k] = mean[k] - z*se
+		upper[k] = mean[k] + z*se
+	}
+	return mean, lower, upper
+}
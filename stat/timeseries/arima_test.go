@@ -0,0 +1,195 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestARIMAFit(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name  string
+		ar    []float64
+		ma    []float64
+		mean  float64
+		order ARIMA
+	}{
+		{name: "AR(2)", ar: []float64{0.5, -0.3}, mean: 2, order: ARIMA{P: 2, IncludeMean: true}},
+		{name: "MA(1)", ma: []float64{0.6}, order: ARIMA{Q: 1}},
+		{name: "ARMA(1,1)", ar: []float64{0.7}, ma: []float64{-0.4}, mean: -1, order: ARIMA{P: 1, Q: 1, IncludeMean: true}},
+	} {
+		x := simulateARMA(rnd, 2000, test.ar, test.ma, test.mean)
+		for _, method := range []Method{MaximumLikelihood, ConditionalSumOfSquares} {
+			order := test.order
+			order.Method = method
+			m, err := order.Fit(x)
+			if err != nil {
+				t.Fatalf("%s method %d: unexpected error: %v", test.name, method, err)
+			}
+			if !floats.EqualApprox(m.AR, test.ar, 0.06) {
+				t.Errorf("%s method %d: unexpected AR coefficients: got %v, want %v", test.name, method, m.AR, test.ar)
+			}
+			if !floats.EqualApprox(m.MA, test.ma, 0.06) {
+				t.Errorf("%s method %d: unexpected MA coefficients: got %v, want %v", test.name, method, m.MA, test.ma)
+			}
+			if math.Abs(m.Mean-test.mean) > 0.15 {
+				t.Errorf("%s method %d: unexpected mean: got %v, want %v", test.name, method, m.Mean, test.mean)
+			}
+			if math.Abs(m.Variance-1) > 0.1 {
+				t.Errorf("%s method %d: unexpected variance: got %v, want 1", test.name, method, m.Variance)
+			}
+
+			// The residuals of a correct model are white noise.
+			res := m.Residuals(nil)
+			if _, p := LjungBox(res[order.P:], 20, order.P+order.Q); p < 0.01 {
+				t.Errorf("%s method %d: residuals are autocorrelated: p=%v", test.name, method, p)
+			}
+		}
+	}
+
+	// An integrated series is fit on its differences.
+	w := simulateARMA(rnd, 2000, []float64{0.5}, nil, 0)
+	x := make([]float64, len(w)+1)
+	for i, v := range w {
+		x[i+1] = x[i] + v
+	}
+	m, err := ARIMA{P: 1, D: 1}.Fit(x)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	direct, err := ARIMA{P: 1}.Fit(w)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(m.AR, direct.AR, 1e-6) || !scalar.EqualWithinAbsOrRel(m.LogLikelihood, direct.LogLikelihood, 1e-8, 1e-8) {
+		t.Errorf("integrated fit differs from fit to differences: got %v (%v), want %v (%v)",
+			m.AR, m.LogLikelihood, direct.AR, direct.LogLikelihood)
+	}
+}
+
+func TestExactLogLikelihood(t *testing.T) {
+	t.Parallel()
+	// The exact likelihood of an AR(1) process has a closed form.
+	rnd := rand.New(rand.NewSource(1))
+	y := simulateARMA(rnd, 50, []float64{0.6}, nil, 0)
+	for _, phi := range []float64{-0.5, 0.2, 0.6, 0.9} {
+		m := &ARIMAModel{AR: []float64{phi}, w: y}
+		ll, variance := m.exactLogLikelihood()
+
+		ss := (1 - phi*phi) * y[0] * y[0]
+		for t := 1; t < len(y); t++ {
+			e := y[t] - phi*y[t-1]
+			ss += e * e
+		}
+		n := float64(len(y))
+		wantVar := ss / n
+		wantLL := -0.5*n*math.Log(2*math.Pi*wantVar) + 0.5*math.Log(1-phi*phi) - 0.5*n
+		if !scalar.EqualWithinAbsOrRel(variance, wantVar, 1e-12, 1e-12) {
+			t.Errorf("phi=%v: unexpected variance: got %v, want %v", phi, variance, wantVar)
+		}
+		if !scalar.EqualWithinAbsOrRel(ll, wantLL, 1e-12, 1e-12) {
+			t.Errorf("phi=%v: unexpected log-likelihood: got %v, want %v", phi, ll, wantLL)
+		}
+	}
+}
+
+func TestARIMAForecast(t *testing.T) {
+	t.Parallel()
+	const z95 = 1.959963984540054
+
+	// The forecasts of an AR(1) process decay geometrically to the
+	// mean.
+	m := &ARIMAModel{
+		Order:    ARIMA{P: 1, IncludeMean: true},
+		AR:       []float64{0.8},
+		Mean:     10,
+		Variance: 4,
+		x:        []float64{9, 11, 12, 14},
+		w:        []float64{9, 11, 12, 14},
+	}
+	mean, lower, upper := m.Forecast(5, 0.95)
+	var sumPsi2 float64
+	for k := 0; k < 5; k++ {
+		want := 10 + math.Pow(0.8, float64(k+1))*4
+		if !scalar.EqualWithinAbsOrRel(mean[k], want, 1e-12, 1e-12) {
+			t.Errorf("AR(1): unexpected forecast at step %d: got %v, want %v", k+1, mean[k], want)
+		}
+		sumPsi2 += math.Pow(0.8, float64(2*k))
+		se := 2 * math.Sqrt(sumPsi2)
+		if !scalar.EqualWithinAbsOrRel(upper[k]-mean[k], z95*se, 1e-12, 1e-12) ||
+			!scalar.EqualWithinAbsOrRel(mean[k]-lower[k], z95*se, 1e-12, 1e-12) {
+			t.Errorf("AR(1): unexpected interval at step %d: got [%v, %v], want half-width %v", k+1, lower[k], upper[k], z95*se)
+		}
+	}
+
+	// The forecasts of a random walk with drift follow the drift
+	// with error growing as the square root of the horizon.
+	x := []float64{0, 1.5, 2, 4, 5}
+	m = &ARIMAModel{
+		Order:    ARIMA{D: 1, IncludeMean: true},
+		Mean:     0.5,
+		Variance: 1,
+		x:        x,
+		w:        difference(x, 1),
+	}
+	mean, lower, upper = m.Forecast(4, 0.95)
+	for k := 0; k < 4; k++ {
+		if want := 5 + 0.5*float64(k+1); !scalar.EqualWithinAbsOrRel(mean[k], want, 1e-12, 1e-12) {
+			t.Errorf("random walk: unexpected forecast at step %d: got %v, want %v", k+1, mean[k], want)
+		}
+		if want := z95 * math.Sqrt(float64(k+1)); !scalar.EqualWithinAbsOrRel(upper[k]-mean[k], want, 1e-12, 1e-12) {
+			t.Errorf("random walk: unexpected interval at step %d: got [%v, %v], want half-width %v", k+1, lower[k], upper[k], want)
+		}
+	}
+
+	// The one step forecast of an MA(1) process uses the last
+	// residual.
+	x = []float64{1, -0.5, 2}
+	m = &ARIMAModel{
+		Order:    ARIMA{Q: 1},
+		MA:       []float64{0.5},
+		Variance: 1,
+		x:        x,
+		w:        x,
+	}
+	res := m.Residuals(nil)
+	mean, _, _ = m.Forecast(2, 0.9)
+	if want := 0.5 * res[2]; !scalar.EqualWithinAbsOrRel(mean[0], want, 1e-14, 1e-14) || mean[1] != 0 {
+		t.Errorf("MA(1): unexpected forecasts: got %v, want [%v 0]", mean, want)
+	}
+}
+
+func TestARIMAPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3, 4, 5}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "negative order", fn: func() { ARIMA{P: -1}.Fit(x) }},
+		{name: "unknown method", fn: func() { ARIMA{P: 1, Method: -1}.Fit(x) }},
+		{name: "short series", fn: func() { ARIMA{P: 2, Q: 1, D: 1}.Fit(x) }},
+		{name: "negative horizon", fn: func() {
+			m, _ := ARIMA{}.Fit(x)
+			m.Forecast(-1, 0.9)
+		}},
+		{name: "forecast level", fn: func() {
+			m, _ := ARIMA{}.Fit(x)
+			m.Forecast(1, 1)
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
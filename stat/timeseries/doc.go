@@ -0,0 +1,7 @@
+// Copyright ©2022 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timeseries provides autocorrelation analysis, residual diagnostics
+// and ARIMA modeling and forecasting of univariate time series.
+package timeseries // import "gonum.org/v1/gonum/stat/timeseries"